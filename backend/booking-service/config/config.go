@@ -1,12 +1,19 @@
 package config
 
 import (
+	"errors"
+
 	sharedConfig "bus-booking/shared/config"
+
+	"github.com/rs/zerolog/log"
 )
 
 type Config struct {
 	*sharedConfig.BaseConfig
-	External ExternalConfig `envPrefix:"EXTERNAL_"`
+	External     ExternalConfig     `envPrefix:"EXTERNAL_"`
+	Cancellation CancellationConfig `envPrefix:"CANCELLATION_"`
+	ETicket      ETicketConfig      `envPrefix:"ETICKET_"`
+	Itinerary    ItineraryConfig    `envPrefix:"ITINERARY_"`
 }
 
 type ExternalConfig struct {
@@ -16,10 +23,52 @@ type ExternalConfig struct {
 	NotificationServiceURL string `env:"NOTIFICATION_SERVICE_URL" envDefault:"http://localhost:8085"`
 }
 
+// CancellationConfig holds the refund policy applied when passengers cancel confirmed bookings.
+// RefundTiers is a comma separated list of hours:percent pairs, e.g. "24:70,12:50,6:30".
+type CancellationConfig struct {
+	RefundTiers string `env:"REFUND_TIERS" envDefault:"24:70,12:50,6:30"`
+}
+
+// ETicketConfig holds the key used to sign and verify e-ticket QR codes. It has no default:
+// whoever knows the key can forge tickets that pass check-in.
+type ETicketConfig struct {
+	QRSecret string `env:"QR_SECRET"`
+}
+
+// ItineraryConfig holds the discounts given to round trips booked together.
+// RoundTripDiscounts is a comma separated list of days:percent pairs, e.g. "7:10,30:5":
+// 10% off when the return departs within 7 days of the outbound, 5% within 30 days.
+// Empty gives no discount.
+type ItineraryConfig struct {
+	RoundTripDiscounts string `env:"ROUND_TRIP_DISCOUNTS" envDefault:""`
+}
+
 func LoadConfig(envFilePath ...string) (*Config, error) {
-	return sharedConfig.LoadConfig[Config](envFilePath...)
+	cfg, err := sharedConfig.LoadConfig[Config](envFilePath...)
+	if err != nil {
+		return nil, err
+	}
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
 }
 
 func MustLoadConfig(envFilePath ...string) *Config {
-	return sharedConfig.MustLoadConfig[Config](envFilePath...)
+	cfg, err := LoadConfig(envFilePath...)
+	if err != nil {
+		panic(err)
+	}
+	return cfg
+}
+
+// validate refuses to start outside development without the secrets the service signs with
+func (c *Config) validate() error {
+	if c.ETicket.QRSecret == "" {
+		if c.Server.Environment != "development" {
+			return errors.New("ETICKET_QR_SECRET must be set outside development")
+		}
+		log.Warn().Msg("ETICKET_QR_SECRET is not set, e-ticket QR codes are signed with an empty key")
+	}
+	return nil
 }
@@ -77,6 +77,34 @@ func (mr *MockNotificationClientMockRecorder) SendBookingPending(ctx, req interf
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendBookingPending", reflect.TypeOf((*MockNotificationClient)(nil).SendBookingPending), ctx, req)
 }
 
+// SendPaymentUnderpaid mocks base method.
+func (m *MockNotificationClient) SendPaymentUnderpaid(ctx context.Context, req *client.PaymentUnderpaidRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendPaymentUnderpaid", ctx, req)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendPaymentUnderpaid indicates an expected call of SendPaymentUnderpaid.
+func (mr *MockNotificationClientMockRecorder) SendPaymentUnderpaid(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendPaymentUnderpaid", reflect.TypeOf((*MockNotificationClient)(nil).SendPaymentUnderpaid), ctx, req)
+}
+
+// SendTripCancelled mocks base method.
+func (m *MockNotificationClient) SendTripCancelled(ctx context.Context, req *client.TripCancelledRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendTripCancelled", ctx, req)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendTripCancelled indicates an expected call of SendTripCancelled.
+func (mr *MockNotificationClientMockRecorder) SendTripCancelled(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendTripCancelled", reflect.TypeOf((*MockNotificationClient)(nil).SendTripCancelled), ctx, req)
+}
+
 // SendTripReminder mocks base method.
 func (m *MockNotificationClient) SendTripReminder(ctx context.Context, req *client.TripReminderRequest) error {
 	m.ctrl.T.Helper()
@@ -90,3 +118,17 @@ func (mr *MockNotificationClientMockRecorder) SendTripReminder(ctx, req interfac
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendTripReminder", reflect.TypeOf((*MockNotificationClient)(nil).SendTripReminder), ctx, req)
 }
+
+// SendWaitlistOffer mocks base method.
+func (m *MockNotificationClient) SendWaitlistOffer(ctx context.Context, req *client.WaitlistOfferRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendWaitlistOffer", ctx, req)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendWaitlistOffer indicates an expected call of SendWaitlistOffer.
+func (mr *MockNotificationClientMockRecorder) SendWaitlistOffer(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendWaitlistOffer", reflect.TypeOf((*MockNotificationClient)(nil).SendWaitlistOffer), ctx, req)
+}
@@ -51,6 +51,36 @@ func (mr *MockPaymentClientMockRecorder) CancelTransaction(ctx, transactionID in
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelTransaction", reflect.TypeOf((*MockPaymentClient)(nil).CancelTransaction), ctx, transactionID)
 }
 
+// CreateOperatorRefund mocks base method.
+func (m *MockPaymentClient) CreateOperatorRefund(ctx context.Context, req *payment.RefundRequest) (*payment.RefundResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateOperatorRefund", ctx, req)
+	ret0, _ := ret[0].(*payment.RefundResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateOperatorRefund indicates an expected call of CreateOperatorRefund.
+func (mr *MockPaymentClientMockRecorder) CreateOperatorRefund(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOperatorRefund", reflect.TypeOf((*MockPaymentClient)(nil).CreateOperatorRefund), ctx, req)
+}
+
+// CreateRefund mocks base method.
+func (m *MockPaymentClient) CreateRefund(ctx context.Context, req *payment.RefundRequest) (*payment.RefundResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateRefund", ctx, req)
+	ret0, _ := ret[0].(*payment.RefundResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateRefund indicates an expected call of CreateRefund.
+func (mr *MockPaymentClientMockRecorder) CreateRefund(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRefund", reflect.TypeOf((*MockPaymentClient)(nil).CreateRefund), ctx, req)
+}
+
 // CreateTransaction mocks base method.
 func (m *MockPaymentClient) CreateTransaction(ctx context.Context, req *payment.CreateTransactionRequest) (*payment.TransactionResponse, error) {
 	m.ctrl.T.Helper()
@@ -51,6 +51,21 @@ func (mr *MockTripClientMockRecorder) GetTripByID(ctx, req, ripID interface{}) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTripByID", reflect.TypeOf((*MockTripClient)(nil).GetTripByID), ctx, req, ripID)
 }
 
+// GetTripFare mocks base method.
+func (m *MockTripClient) GetTripFare(ctx context.Context, tripID uuid.UUID) (*trip.TripFare, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTripFare", ctx, tripID)
+	ret0, _ := ret[0].(*trip.TripFare)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTripFare indicates an expected call of GetTripFare.
+func (mr *MockTripClientMockRecorder) GetTripFare(ctx, tripID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTripFare", reflect.TypeOf((*MockTripClient)(nil).GetTripFare), ctx, tripID)
+}
+
 // GetTripsByIDs mocks base method.
 func (m *MockTripClient) GetTripsByIDs(ctx context.Context, req trip.GetTripByIDRequest, tripIDs []uuid.UUID) ([]trip.Trip, error) {
 	m.ctrl.T.Helper()
@@ -19,6 +19,9 @@ type NotificationClient interface {
 	SendBookingConfirmation(ctx context.Context, req *BookingConfirmationRequest) error
 	SendBookingFailure(ctx context.Context, req *BookingFailureRequest) error
 	SendBookingPending(ctx context.Context, req *BookingPendingRequest) error
+	SendTripCancelled(ctx context.Context, req *TripCancelledRequest) error
+	SendPaymentUnderpaid(ctx context.Context, req *PaymentUnderpaidRequest) error
+	SendWaitlistOffer(ctx context.Context, req *WaitlistOfferRequest) error
 }
 
 type TripReminderRequest struct {
@@ -68,6 +71,43 @@ type BookingPendingRequest struct {
 	PaymentLink      string `json:"payment_link"`
 }
 
+type TripCancelledRequest struct {
+	Email            string `json:"email"`
+	Name             string `json:"name"`
+	BookingReference string `json:"booking_reference"`
+	Reason           string `json:"reason"`
+	From             string `json:"from"`
+	To               string `json:"to"`
+	DepartureTime    string `json:"departure_time"`
+	RefundAmount     int    `json:"refund_amount"`
+	BookingLink      string `json:"booking_link"`
+}
+
+type PaymentUnderpaidRequest struct {
+	Email            string `json:"email"`
+	Name             string `json:"name"`
+	BookingReference string `json:"booking_reference"`
+	From             string `json:"from"`
+	To               string `json:"to"`
+	DepartureTime    string `json:"departure_time"`
+	TotalAmount      int    `json:"total_amount"`
+	AmountPaid       int    `json:"amount_paid"`
+	AmountRemaining  int    `json:"amount_remaining"`
+	PaymentLink      string `json:"payment_link"`
+	ExpiresAt        string `json:"expires_at"`
+}
+
+type WaitlistOfferRequest struct {
+	Email         string `json:"email"`
+	Name          string `json:"name"`
+	From          string `json:"from"`
+	To            string `json:"to"`
+	DepartureTime string `json:"departure_time"`
+	SeatNumbers   string `json:"seat_numbers"`
+	ClaimLink     string `json:"claim_link"`
+	ExpiresAt     string `json:"expires_at"`
+}
+
 type notificationClientImpl struct {
 	baseURL     string
 	serviceName string
@@ -173,3 +213,27 @@ func (c *notificationClientImpl) SendBookingPending(ctx context.Context, req *Bo
 	}
 	return c.sendRequest(ctx, "/api/v1/notifications", genReq)
 }
+
+func (c *notificationClientImpl) SendTripCancelled(ctx context.Context, req *TripCancelledRequest) error {
+	genReq := GenericNotificationRequest{
+		Type:    "TRIP_CANCELLED",
+		Payload: c.toPayload(req),
+	}
+	return c.sendRequest(ctx, "/api/v1/notifications", genReq)
+}
+
+func (c *notificationClientImpl) SendPaymentUnderpaid(ctx context.Context, req *PaymentUnderpaidRequest) error {
+	genReq := GenericNotificationRequest{
+		Type:    "PAYMENT_UNDERPAID",
+		Payload: c.toPayload(req),
+	}
+	return c.sendRequest(ctx, "/api/v1/notifications", genReq)
+}
+
+func (c *notificationClientImpl) SendWaitlistOffer(ctx context.Context, req *WaitlistOfferRequest) error {
+	genReq := GenericNotificationRequest{
+		Type:    "WAITLIST_OFFER",
+		Payload: c.toPayload(req),
+	}
+	return c.sendRequest(ctx, "/api/v1/notifications", genReq)
+}
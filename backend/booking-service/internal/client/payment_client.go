@@ -4,15 +4,23 @@ import (
 	"bus-booking/booking-service/internal/model/payment"
 	"bus-booking/shared/client"
 	"context"
+	"errors"
 	"fmt"
+	"net/http"
 
 	"github.com/google/uuid"
 )
 
+// ErrRefundAlreadyExists is returned when payment service already holds a refund for the booking
+var ErrRefundAlreadyExists = errors.New("refund already exists for this booking")
+
 type PaymentClient interface {
 	CreateTransaction(ctx context.Context, req *payment.CreateTransactionRequest) (*payment.TransactionResponse, error)
 	GetTransactionByID(ctx context.Context, id uuid.UUID) (*payment.TransactionResponse, error)
 	CancelTransaction(ctx context.Context, transactionID uuid.UUID) (*payment.TransactionResponse, error)
+	CreateRefund(ctx context.Context, req *payment.RefundRequest) (*payment.RefundResponse, error)
+	// CreateOperatorRefund refunds a booking without a passenger request, keyed by req.ID
+	CreateOperatorRefund(ctx context.Context, req *payment.RefundRequest) (*payment.RefundResponse, error)
 }
 
 type PaymentClientImpl struct {
@@ -71,3 +79,37 @@ func (c *PaymentClientImpl) CancelTransaction(ctx context.Context, transactionID
 
 	return transactionResp, nil
 }
+
+func (c *PaymentClientImpl) CreateRefund(ctx context.Context, req *payment.RefundRequest) (*payment.RefundResponse, error) {
+	resp, err := c.http.Post(ctx, "/api/v1/refunds", req, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create refund: %w", err)
+	}
+	if resp.StatusCode == http.StatusConflict {
+		return nil, ErrRefundAlreadyExists
+	}
+
+	refundResp, err := client.ParseData[payment.RefundResponse](resp)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse refund response: %w", err)
+	}
+
+	return refundResp, nil
+}
+
+func (c *PaymentClientImpl) CreateOperatorRefund(ctx context.Context, req *payment.RefundRequest) (*payment.RefundResponse, error) {
+	resp, err := c.http.Post(ctx, "/api/v1/refunds/operator", req, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create operator refund: %w", err)
+	}
+	if resp.StatusCode == http.StatusConflict {
+		return nil, ErrRefundAlreadyExists
+	}
+
+	refundResp, err := client.ParseData[payment.RefundResponse](resp)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse refund response: %w", err)
+	}
+
+	return refundResp, nil
+}
@@ -14,6 +14,7 @@ type TripClient interface {
 	GetTripByID(ctx context.Context, req trip.GetTripByIDRequest, ripID uuid.UUID) (*trip.Trip, error)
 	GetTripsByIDs(ctx context.Context, req trip.GetTripByIDRequest, tripIDs []uuid.UUID) ([]trip.Trip, error)
 	ListSeatsByIDs(ctx context.Context, seatIDs []uuid.UUID) ([]trip.Seat, error)
+	GetTripFare(ctx context.Context, tripID uuid.UUID) (*trip.TripFare, error)
 }
 
 type TripClientImpl struct {
@@ -119,3 +120,20 @@ func (c *TripClientImpl) GetTripsByIDs(ctx context.Context, req trip.GetTripByID
 
 	return trips, nil
 }
+
+// GetTripFare quotes the current dynamic fare of a trip
+func (c *TripClientImpl) GetTripFare(ctx context.Context, tripID uuid.UUID) (*trip.TripFare, error) {
+	endpoint := fmt.Sprintf("/api/v1/trips/%s/fare", tripID.String())
+
+	res, err := c.http.Get(ctx, endpoint, nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get trip fare: %w", err)
+	}
+
+	fare, err := client.ParseData[trip.TripFare](res)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse trip fare response: %w", err)
+	}
+
+	return fare, nil
+}
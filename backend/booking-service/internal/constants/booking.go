@@ -17,12 +17,33 @@ const (
 	BookingExpirationGracePeriod = 1 * time.Minute
 )
 
+// Seat holds
+const (
+	// SeatLockDuration is how long seats stay held for a session while the passenger checks out (5 minutes)
+	SeatLockDuration = 5 * time.Minute
+)
+
+// Waitlist
+const (
+	// WaitlistOfferDuration is how long seats offered to a waitlisted user stay held for them (15 minutes)
+	WaitlistOfferDuration = 15 * time.Minute
+)
+
 // Notification and background task timeouts
 const (
 	// BackgroundTaskTimeout is the default timeout for background tasks like sending emails (1 minute)
 	BackgroundTaskTimeout = 1 * time.Minute
 )
 
+// Ticket exchange rules
+const (
+	// ExchangeFreeBeforeDeparture is how long before departure a ticket can be exchanged without surcharge (12 hours)
+	ExchangeFreeBeforeDeparture = 12 * time.Hour
+
+	// ExchangeSurchargePercent is the surcharge (percent of the new fare) for exchanges made after the free window
+	ExchangeSurchargePercent = 10
+)
+
 // Trip reminder scheduling
 const (
 	// TripReminderBeforeDeparture is how long before departure to send reminder (2 hours)
@@ -34,6 +55,9 @@ const (
 	// BookingReferencePrefix is the prefix for booking reference numbers
 	BookingReferencePrefix = "BK"
 
+	// ItineraryReferencePrefix is the prefix for itinerary reference numbers
+	ItineraryReferencePrefix = "IT"
+
 	// BookingReferenceRandomLength is the length of random characters in booking reference
 	BookingReferenceRandomLength = 4
 
@@ -48,6 +72,9 @@ const (
 
 	// QueueNameTripReminder is the queue name for trip reminder jobs
 	QueueNameTripReminder = "trip_reminder"
+
+	// QueueNameWaitlistOffer is the queue name for offering released seats of a trip to its waitlist
+	QueueNameWaitlistOffer = "waitlist_offer"
 )
 
 // Date/Time formats
@@ -64,3 +91,15 @@ const (
 	// DefaultFrontendURL is the default frontend URL
 	DefaultFrontendURL = "http://localhost:3000"
 )
+
+// Promotions
+const (
+	// PromotionMinPayableAmount is the least a discounted booking still costs, payment links cannot be free (1.000 VND)
+	PromotionMinPayableAmount = 1000
+
+	// VoucherCodePrefix is the prefix of generated voucher codes
+	VoucherCodePrefix = "VC"
+
+	// VoucherCodeRandomLength is the length of random characters in generated voucher codes
+	VoucherCodeRandomLength = 8
+)
@@ -3,6 +3,7 @@ package handler
 import (
 	"bus-booking/booking-service/internal/model"
 	"bus-booking/booking-service/internal/service"
+	"bus-booking/shared/constants"
 	"bus-booking/shared/ginext"
 
 	sharedcontext "bus-booking/shared/context"
@@ -19,28 +20,37 @@ type BookingHandler interface {
 	GetByReference(r *ginext.Request) (*ginext.Response, error)
 	GetUserBookings(r *ginext.Request) (*ginext.Response, error)
 	GetTripBookings(r *ginext.Request) (*ginext.Response, error)
+	GetActiveTripBookings(r *ginext.Request) (*ginext.Response, error)
 	ListBookings(r *ginext.Request) (*ginext.Response, error)
 
 	CancelBooking(r *ginext.Request) (*ginext.Response, error)
+	CancelForTrip(r *ginext.Request) (*ginext.Response, error)
+	NotifyTripCancelled(r *ginext.Request) (*ginext.Response, error)
+	GetRefundQuote(r *ginext.Request) (*ginext.Response, error)
 	RetryPayment(r *ginext.Request) (*ginext.Response, error)
+	ExchangeBooking(r *ginext.Request) (*ginext.Response, error)
 
 	UpdateBookingStatus(r *ginext.Request) (*ginext.Response, error)
 	GetSeatStatus(r *ginext.Request) (*ginext.Response, error)
 	GetTripPassengers(r *ginext.Request) (*ginext.Response, error)
+	GetTripManifest(r *ginext.Request) (*ginext.Response, error)
 
 	DownloadETicket(r *ginext.Request) error
 	CheckInPassenger(r *ginext.Request) (*ginext.Response, error)
+	CheckInTicket(r *ginext.Request) (*ginext.Response, error)
 }
 
 type BookingHandlerImpl struct {
-	bookingService service.BookingService
-	eTicketService service.ETicketService
+	bookingService  service.BookingService
+	eTicketService  service.ETicketService
+	exchangeService service.BookingExchangeService
 }
 
-func NewBookingHandler(bookingService service.BookingService, eTicketService service.ETicketService) BookingHandler {
+func NewBookingHandler(bookingService service.BookingService, eTicketService service.ETicketService, exchangeService service.BookingExchangeService) BookingHandler {
 	return &BookingHandlerImpl{
-		bookingService: bookingService,
-		eTicketService: eTicketService,
+		bookingService:  bookingService,
+		eTicketService:  eTicketService,
+		exchangeService: exchangeService,
 	}
 }
 
@@ -230,6 +240,33 @@ func (h *BookingHandlerImpl) GetTripBookings(r *ginext.Request) (*ginext.Respons
 	return ginext.NewPaginatedResponse(bookings, req.Page, req.PageSize, total), nil
 }
 
+// GetActiveTripBookings godoc
+// @Summary Get active trip bookings
+// @Description Get every pending or confirmed booking of a trip (internal use by trip service)
+// @Tags bookings
+// @Produce json
+// @Param trip_id path string true "Trip ID" format(uuid)
+// @Success 200 {object} ginext.Response{data=[]model.BookingResponse}
+// @Failure 400 {object} ginext.Response
+// @Failure 500 {object} ginext.Response
+// @Router /api/v1/bookings/trips/{trip_id}/active [get]
+func (h *BookingHandlerImpl) GetActiveTripBookings(r *ginext.Request) (*ginext.Response, error) {
+	tripIDStr := r.GinCtx.Param("trip_id")
+	tripID, err := uuid.Parse(tripIDStr)
+	if err != nil {
+		log.Error().Err(err).Str("trip_id", tripIDStr).Msg("invalid trip id")
+		return nil, ginext.NewBadRequestError("invalid trip id")
+	}
+
+	bookings, err := h.bookingService.GetActiveTripBookings(r.Context(), tripID)
+	if err != nil {
+		log.Error().Err(err).Str("trip_id", tripIDStr).Msg("failed to get active trip bookings")
+		return nil, err
+	}
+
+	return ginext.NewSuccessResponse(bookings), nil
+}
+
 // CancelBooking godoc
 // @Summary Cancel a booking
 // @Description Cancel a booking and release seats
@@ -264,6 +301,102 @@ func (h *BookingHandlerImpl) CancelBooking(r *ginext.Request) (*ginext.Response,
 	return ginext.NewSuccessResponse("booking cancelled successfully"), nil
 }
 
+// CancelForTrip godoc
+// @Summary Cancel booking of a cancelled trip
+// @Description Cancel a booking because the operator cancelled its trip, without a policy refund (internal use by trip service)
+// @Tags bookings
+// @Accept json
+// @Produce json
+// @Param id path string true "Booking ID" format(uuid)
+// @Param request body model.CancelBookingRequest true "Cancellation reason"
+// @Success 200 {object} ginext.Response
+// @Failure 400 {object} ginext.Response
+// @Failure 404 {object} ginext.Response
+// @Router /api/v1/bookings/{id}/trip-cancelled [post]
+func (h *BookingHandlerImpl) CancelForTrip(r *ginext.Request) (*ginext.Response, error) {
+	idStr := r.GinCtx.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		log.Error().Err(err).Str("id", idStr).Msg("invalid booking id")
+		return nil, ginext.NewBadRequestError("invalid booking id")
+	}
+
+	var req model.CancelBookingRequest
+	if err := r.GinCtx.ShouldBindJSON(&req); err != nil {
+		log.Error().Err(err).Msg("failed to bind request body")
+		return nil, ginext.NewBadRequestError(err.Error())
+	}
+
+	if err := h.bookingService.CancelForTrip(r.Context(), id, req.Reason); err != nil {
+		log.Error().Err(err).Str("booking_id", idStr).Msg("failed to cancel booking of cancelled trip")
+		return nil, err
+	}
+
+	return ginext.NewSuccessResponse("booking cancelled successfully"), nil
+}
+
+// NotifyTripCancelled godoc
+// @Summary Notify passenger of trip cancellation
+// @Description Email the passenger that the operator cancelled their trip (internal use by trip service)
+// @Tags bookings
+// @Accept json
+// @Produce json
+// @Param id path string true "Booking ID" format(uuid)
+// @Param request body model.NotifyTripCancelledRequest true "Cancellation details"
+// @Success 200 {object} ginext.Response
+// @Failure 400 {object} ginext.Response
+// @Failure 404 {object} ginext.Response
+// @Failure 500 {object} ginext.Response
+// @Router /api/v1/bookings/{id}/notify-trip-cancelled [post]
+func (h *BookingHandlerImpl) NotifyTripCancelled(r *ginext.Request) (*ginext.Response, error) {
+	idStr := r.GinCtx.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		log.Error().Err(err).Str("id", idStr).Msg("invalid booking id")
+		return nil, ginext.NewBadRequestError("invalid booking id")
+	}
+
+	var req model.NotifyTripCancelledRequest
+	if err := r.GinCtx.ShouldBindJSON(&req); err != nil {
+		log.Error().Err(err).Msg("failed to bind request body")
+		return nil, ginext.NewBadRequestError(err.Error())
+	}
+
+	if err := h.bookingService.NotifyTripCancelled(r.Context(), id, &req); err != nil {
+		log.Error().Err(err).Str("booking_id", idStr).Msg("failed to notify passenger of trip cancellation")
+		return nil, err
+	}
+
+	return ginext.NewSuccessResponse("passenger notified"), nil
+}
+
+// GetRefundQuote godoc
+// @Summary Get refund quote for a booking
+// @Description Get the refund amount the passenger would receive if the confirmed booking were cancelled now
+// @Tags bookings
+// @Produce json
+// @Param id path string true "Booking ID" format(uuid)
+// @Success 200 {object} ginext.Response{data=model.RefundQuoteResponse}
+// @Failure 400 {object} ginext.Response
+// @Failure 404 {object} ginext.Response
+// @Router /api/v1/bookings/{id}/refund-quote [get]
+func (h *BookingHandlerImpl) GetRefundQuote(r *ginext.Request) (*ginext.Response, error) {
+	idStr := r.GinCtx.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		log.Error().Err(err).Str("id", idStr).Msg("invalid booking id")
+		return nil, ginext.NewBadRequestError("invalid booking id")
+	}
+
+	quote, err := h.bookingService.GetRefundQuote(r.Context(), id)
+	if err != nil {
+		log.Error().Err(err).Str("booking_id", idStr).Msg("failed to get refund quote")
+		return nil, err
+	}
+
+	return ginext.NewSuccessResponse(quote), nil
+}
+
 // RetryPayment godoc
 // @Summary Retry payment for a booking
 // @Description Create a new payment link for a failed or expired booking
@@ -293,6 +426,46 @@ func (h *BookingHandlerImpl) RetryPayment(r *ginext.Request) (*ginext.Response,
 	return ginext.NewSuccessResponse(booking), nil
 }
 
+// ExchangeBooking godoc
+// @Summary Exchange a booking to another trip
+// @Description Move a confirmed booking to new seats on another trip of the same route. Free until 12h before departure, 10% surcharge afterwards. A positive fare difference returns a payment link, a negative one is refunded.
+// @Tags bookings
+// @Accept json
+// @Produce json
+// @Param id path string true "Booking ID" format(uuid)
+// @Param request body model.ExchangeBookingRequest true "Exchange request"
+// @Success 200 {object} ginext.Response{data=model.BookingExchangeResponse}
+// @Failure 400 {object} ginext.Response
+// @Failure 403 {object} ginext.Response
+// @Failure 404 {object} ginext.Response
+// @Failure 409 {object} ginext.Response
+// @Failure 500 {object} ginext.Response
+// @Router /api/v1/bookings/{id}/exchange [post]
+func (h *BookingHandlerImpl) ExchangeBooking(r *ginext.Request) (*ginext.Response, error) {
+	userID := sharedcontext.GetUserID(r.GinCtx)
+
+	idStr := r.GinCtx.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		log.Error().Err(err).Str("id", idStr).Msg("invalid booking id")
+		return nil, ginext.NewBadRequestError("invalid booking id")
+	}
+
+	var req model.ExchangeBookingRequest
+	if err := r.GinCtx.ShouldBindJSON(&req); err != nil {
+		log.Error().Err(err).Msg("failed to bind request body")
+		return nil, ginext.NewBadRequestError(err.Error())
+	}
+
+	exchange, err := h.exchangeService.ExchangeBooking(r.Context(), id, &req, userID)
+	if err != nil {
+		log.Error().Err(err).Str("booking_id", idStr).Msg("failed to exchange booking")
+		return nil, err
+	}
+
+	return ginext.NewSuccessResponse(exchange), nil
+}
+
 // UpdateBookingStatus godoc
 // @Summary Update booking payment status
 // @Description Update booking payment status (internal use by payment service)
@@ -301,6 +474,7 @@ func (h *BookingHandlerImpl) RetryPayment(r *ginext.Request) (*ginext.Response,
 // @Produce json
 // @Param id path string true "Booking ID" format(uuid)
 // @Param request body model.UpdateBookingStatusRequest true "Payment status update"
+// @Param X-Event-ID header string false "Outbox event ID, redeliveries of the same event are ignored" format(uuid)
 // @Success 200 {object} ginext.Response
 // @Failure 400 {object} ginext.Response
 // @Failure 404 {object} ginext.Response
@@ -319,6 +493,12 @@ func (h *BookingHandlerImpl) UpdateBookingStatus(r *ginext.Request) (*ginext.Res
 		return nil, ginext.NewBadRequestError(err.Error())
 	}
 
+	if eventID := r.GinCtx.GetHeader(constants.XEventID); eventID != "" {
+		if req.EventID, err = uuid.Parse(eventID); err != nil {
+			return nil, ginext.NewBadRequestError("invalid event id")
+		}
+	}
+
 	if err := h.bookingService.UpdateBookingStatus(r.Context(), &req, id); err != nil {
 		log.Error().Err(err).Str("booking_id", idStr).Msg("failed to update payment status")
 		return nil, err
@@ -335,6 +515,8 @@ func (h *BookingHandlerImpl) UpdateBookingStatus(r *ginext.Request) (*ginext.Res
 // @Produce json
 // @Param trip_id path string true "Trip ID" format(uuid)
 // @Param seat_ids query []string true "Seat IDs" collectionFormat(multi)
+// @Param from_stop_order query int false "Pickup stop order of the segment"
+// @Param to_stop_order query int false "Dropoff stop order of the segment"
 // @Success 200 {object} ginext.Response{data=[]model.SeatStatusItem}
 // @Failure 400 {object} ginext.Response
 // @Failure 500 {object} ginext.Response
@@ -363,7 +545,18 @@ func (h *BookingHandlerImpl) GetSeatStatus(r *ginext.Request) (*ginext.Response,
 		seatIDs = append(seatIDs, seatID)
 	}
 
-	seatStatuses, err := h.bookingService.GetSeatStatus(r.Context(), tripID, seatIDs)
+	segment := model.FullTripSegment()
+	if req.FromStopOrder != nil || req.ToStopOrder != nil {
+		if req.FromStopOrder == nil || req.ToStopOrder == nil || *req.FromStopOrder >= *req.ToStopOrder {
+			return nil, ginext.NewBadRequestError("invalid segment: from_stop_order must be less than to_stop_order")
+		}
+		segment = model.TripSegment{
+			FromStopOrder: *req.FromStopOrder,
+			ToStopOrder:   *req.ToStopOrder,
+		}
+	}
+
+	seatStatuses, err := h.bookingService.GetSeatStatus(r.Context(), tripID, seatIDs, segment)
 	if err != nil {
 		log.Error().Err(err).Str("trip_id", tripIDStr).Msg("failed to get seat status")
 		return nil, err
@@ -399,6 +592,33 @@ func (h *BookingHandlerImpl) GetTripPassengers(r *ginext.Request) (*ginext.Respo
 	return ginext.NewSuccessResponse(passengers), nil
 }
 
+// GetTripManifest godoc
+// @Summary Get trip manifest
+// @Description Get the per-seat passenger manifest of a trip, sorted by seat number (Admin)
+// @Tags bookings
+// @Produce json
+// @Param trip_id path string true "Trip ID" format(uuid)
+// @Success 200 {object} ginext.Response{data=model.TripManifestResponse}
+// @Failure 400 {object} ginext.Response
+// @Failure 500 {object} ginext.Response
+// @Router /api/v1/bookings/trip/{trip_id}/manifest [get]
+func (h *BookingHandlerImpl) GetTripManifest(r *ginext.Request) (*ginext.Response, error) {
+	tripIDStr := r.GinCtx.Param("trip_id")
+	tripID, err := uuid.Parse(tripIDStr)
+	if err != nil {
+		log.Error().Err(err).Str("trip_id", tripIDStr).Msg("invalid trip id")
+		return nil, ginext.NewBadRequestError("invalid trip id")
+	}
+
+	manifest, err := h.bookingService.GetTripManifest(r.Context(), tripID)
+	if err != nil {
+		log.Error().Err(err).Str("trip_id", tripIDStr).Msg("failed to get trip manifest")
+		return nil, err
+	}
+
+	return ginext.NewSuccessResponse(manifest), nil
+}
+
 // DownloadETicket godoc
 // @Summary Download e-ticket PDF
 // @Description Download e-ticket PDF for a confirmed booking
@@ -493,3 +713,40 @@ func (h *BookingHandlerImpl) CheckInPassenger(r *ginext.Request) (*ginext.Respon
 
 	return ginext.NewSuccessResponse("Passenger checked in successfully"), nil
 }
+
+// CheckInTicket godoc
+// @Summary Check in seats by e-ticket QR code
+// @Description Verify the signed QR code of an e-ticket and board its seats on the given trip. Seats can be boarded individually so a group can board partially; omit seat_ids to board every remaining seat on the ticket (Admin only)
+// @Tags bookings
+// @Accept json
+// @Produce json
+// @Param trip_id path string true "Trip ID" format(uuid)
+// @Param request body model.TicketCheckInRequest true "Scanned QR code and seats to board"
+// @Success 200 {object} ginext.Response{data=model.TicketCheckInResponse}
+// @Failure 400 {object} ginext.Response "Invalid QR code, wrong trip or cancelled booking"
+// @Failure 404 {object} ginext.Response
+// @Failure 409 {object} ginext.Response "Seat already boarded"
+// @Failure 500 {object} ginext.Response
+// @Router /api/v1/trips/{trip_id}/check-in [post]
+func (h *BookingHandlerImpl) CheckInTicket(r *ginext.Request) (*ginext.Response, error) {
+	tripIDStr := r.GinCtx.Param("trip_id")
+	tripID, err := uuid.Parse(tripIDStr)
+	if err != nil {
+		log.Error().Err(err).Str("trip_id", tripIDStr).Msg("invalid trip id")
+		return nil, ginext.NewBadRequestError("invalid trip id")
+	}
+
+	var req model.TicketCheckInRequest
+	if err := r.GinCtx.ShouldBindJSON(&req); err != nil {
+		log.Error().Err(err).Msg("invalid check-in request")
+		return nil, ginext.NewBadRequestError(err.Error())
+	}
+
+	resp, err := h.eTicketService.CheckInTicket(r.Context(), tripID, &req)
+	if err != nil {
+		log.Error().Err(err).Str("trip_id", tripIDStr).Msg("failed to check in ticket")
+		return nil, err
+	}
+
+	return ginext.NewSuccessResponse(resp), nil
+}
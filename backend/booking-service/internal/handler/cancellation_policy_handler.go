@@ -0,0 +1,33 @@
+package handler
+
+import (
+	"bus-booking/booking-service/internal/service"
+	"bus-booking/shared/ginext"
+)
+
+type CancellationPolicyHandler interface {
+	GetPolicy(r *ginext.Request) (*ginext.Response, error)
+}
+
+type CancellationPolicyHandlerImpl struct {
+	service service.CancellationPolicyService
+}
+
+func NewCancellationPolicyHandler(
+	service service.CancellationPolicyService,
+) CancellationPolicyHandler {
+	return &CancellationPolicyHandlerImpl{
+		service: service,
+	}
+}
+
+// GetPolicy godoc
+// @Summary Get cancellation policy
+// @Description Get the time-tiered refund policy applied when cancelling confirmed bookings
+// @Tags bookings
+// @Produce json
+// @Success 200 {object} ginext.Response{data=model.CancellationPolicyResponse}
+// @Router /api/v1/cancellation-policy [get]
+func (h *CancellationPolicyHandlerImpl) GetPolicy(r *ginext.Request) (*ginext.Response, error) {
+	return ginext.NewSuccessResponse(h.service.GetPolicy()), nil
+}
@@ -0,0 +1,75 @@
+package handler
+
+import (
+	"bus-booking/booking-service/internal/model"
+	"bus-booking/booking-service/internal/service"
+	"bus-booking/shared/ginext"
+
+	"github.com/rs/zerolog/log"
+)
+
+type DeadLetterHandler interface {
+	ListDeadLetters(r *ginext.Request) (*ginext.Response, error)
+	ReplayDeadLetter(r *ginext.Request) (*ginext.Response, error)
+}
+
+type DeadLetterHandlerImpl struct {
+	service service.DeadLetterService
+}
+
+func NewDeadLetterHandler(service service.DeadLetterService) DeadLetterHandler {
+	return &DeadLetterHandlerImpl{
+		service: service,
+	}
+}
+
+// ListDeadLetters godoc
+// @Summary List dead letters
+// @Description List background jobs (booking expiry, trip reminder) that exhausted their retries (Admin)
+// @Tags dead-letters
+// @Produce json
+// @Param queue query string false "Queue name (booking_expiry, trip_reminder, waitlist_offer)"
+// @Param limit query int false "Max dead letters to return" default(50)
+// @Success 200 {object} ginext.Response{data=model.DeadLettersResponse}
+// @Failure 400 {object} ginext.Response
+// @Failure 500 {object} ginext.Response
+// @Router /api/v1/dead-letters [get]
+func (h *DeadLetterHandlerImpl) ListDeadLetters(r *ginext.Request) (*ginext.Response, error) {
+	var req model.ListDeadLettersRequest
+	if err := r.GinCtx.ShouldBindQuery(&req); err != nil {
+		log.Error().Err(err).Msg("failed to bind query parameters")
+		return nil, ginext.NewBadRequestError(err.Error())
+	}
+
+	letters, err := h.service.ListDeadLetters(r.Context(), req)
+	if err != nil {
+		log.Error().Err(err).Str("queue", req.Queue).Msg("failed to list dead letters")
+		return nil, err
+	}
+
+	return ginext.NewSuccessResponse(letters), nil
+}
+
+// ReplayDeadLetter godoc
+// @Summary Replay dead letter
+// @Description Put a dead-lettered job back on its queue with its attempts reset (Admin)
+// @Tags dead-letters
+// @Produce json
+// @Param queue path string true "Queue name (booking_expiry, trip_reminder, waitlist_offer)"
+// @Param id path string true "Dead letter ID"
+// @Success 200 {object} ginext.Response
+// @Failure 400 {object} ginext.Response
+// @Failure 404 {object} ginext.Response
+// @Failure 500 {object} ginext.Response
+// @Router /api/v1/dead-letters/{queue}/{id}/replay [post]
+func (h *DeadLetterHandlerImpl) ReplayDeadLetter(r *ginext.Request) (*ginext.Response, error) {
+	queueName := r.GinCtx.Param("queue")
+	id := r.GinCtx.Param("id")
+
+	if err := h.service.ReplayDeadLetter(r.Context(), queueName, id); err != nil {
+		log.Error().Err(err).Str("queue", queueName).Str("id", id).Msg("failed to replay dead letter")
+		return nil, err
+	}
+
+	return ginext.NewSuccessResponse("dead letter replayed"), nil
+}
@@ -0,0 +1,153 @@
+package handler
+
+import (
+	"bus-booking/booking-service/internal/model"
+	"bus-booking/booking-service/internal/service"
+	"bus-booking/shared/ginext"
+
+	sharedcontext "bus-booking/shared/context"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+type ItineraryHandler interface {
+	CreateItinerary(r *ginext.Request) (*ginext.Response, error)
+	ListMyItineraries(r *ginext.Request) (*ginext.Response, error)
+	GetItinerary(r *ginext.Request) (*ginext.Response, error)
+	CancelItinerary(r *ginext.Request) (*ginext.Response, error)
+}
+
+type ItineraryHandlerImpl struct {
+	service service.ItineraryService
+}
+
+func NewItineraryHandler(service service.ItineraryService) ItineraryHandler {
+	return &ItineraryHandlerImpl{
+		service: service,
+	}
+}
+
+// CreateItinerary godoc
+// @Summary Book a round trip or multi-leg journey
+// @Description Book 2 to 4 legs under one reference with a single payment. Legs are given in travel order; round trips may get a discount.
+// @Tags itineraries
+// @Accept json
+// @Produce json
+// @Param request body model.CreateItineraryRequest true "Itinerary request"
+// @Success 201 {object} ginext.Response{data=model.ItineraryResponse}
+// @Failure 400 {object} ginext.Response
+// @Failure 409 {object} ginext.Response
+// @Failure 500 {object} ginext.Response
+// @Router /api/v1/itineraries [post]
+// @Security BearerAuth
+func (h *ItineraryHandlerImpl) CreateItinerary(r *ginext.Request) (*ginext.Response, error) {
+	userID := sharedcontext.GetUserID(r.GinCtx)
+
+	var req model.CreateItineraryRequest
+	if err := r.GinCtx.ShouldBindJSON(&req); err != nil {
+		log.Debug().Err(err).Msg("JSON binding failed")
+		return nil, ginext.NewBadRequestError(err.Error())
+	}
+
+	itinerary, err := h.service.CreateItinerary(r.Context(), &req, userID)
+	if err != nil {
+		log.Error().Err(err).Str("user_id", userID.String()).Msg("Failed to create itinerary")
+		return nil, err
+	}
+
+	return ginext.NewCreatedResponse(itinerary), nil
+}
+
+// ListMyItineraries godoc
+// @Summary List my itineraries
+// @Description List the round trips and multi-leg journeys of the current user, newest first
+// @Tags itineraries
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Items per page" default(20)
+// @Success 200 {object} ginext.Response{data=[]model.ItineraryResponse}
+// @Failure 500 {object} ginext.Response
+// @Router /api/v1/itineraries [get]
+// @Security BearerAuth
+func (h *ItineraryHandlerImpl) ListMyItineraries(r *ginext.Request) (*ginext.Response, error) {
+	userID := sharedcontext.GetUserID(r.GinCtx)
+
+	var req model.PaginationRequest
+	if err := r.GinCtx.ShouldBindQuery(&req); err != nil {
+		return nil, ginext.NewBadRequestError(err.Error())
+	}
+	req.Normalize()
+
+	itineraries, total, err := h.service.ListUserItineraries(r.Context(), req, userID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to list itineraries")
+		return nil, err
+	}
+
+	return ginext.NewPaginatedResponse(itineraries, req.Page, req.PageSize, total), nil
+}
+
+// GetItinerary godoc
+// @Summary Get an itinerary
+// @Description Get an itinerary of the current user with its legs and payment
+// @Tags itineraries
+// @Produce json
+// @Param id path string true "Itinerary ID" format(uuid)
+// @Success 200 {object} ginext.Response{data=model.ItineraryResponse}
+// @Failure 400 {object} ginext.Response
+// @Failure 404 {object} ginext.Response
+// @Router /api/v1/itineraries/{id} [get]
+// @Security BearerAuth
+func (h *ItineraryHandlerImpl) GetItinerary(r *ginext.Request) (*ginext.Response, error) {
+	userID := sharedcontext.GetUserID(r.GinCtx)
+
+	id, err := uuid.Parse(r.GinCtx.Param("id"))
+	if err != nil {
+		return nil, ginext.NewBadRequestError("invalid itinerary id")
+	}
+
+	itinerary, err := h.service.GetItinerary(r.Context(), id, userID)
+	if err != nil {
+		log.Error().Err(err).Str("itinerary_id", id.String()).Msg("Failed to get itinerary")
+		return nil, err
+	}
+
+	return ginext.NewSuccessResponse(itinerary), nil
+}
+
+// CancelItinerary godoc
+// @Summary Cancel an itinerary
+// @Description Cancel every leg that can still be cancelled. Unpaid legs are cancelled with their payment; paid legs are refunded by the cancellation policy.
+// @Tags itineraries
+// @Accept json
+// @Produce json
+// @Param id path string true "Itinerary ID" format(uuid)
+// @Param request body model.CancelBookingRequest true "Cancellation request"
+// @Success 200 {object} ginext.Response
+// @Failure 400 {object} ginext.Response
+// @Failure 404 {object} ginext.Response
+// @Failure 500 {object} ginext.Response
+// @Router /api/v1/itineraries/{id}/cancel [post]
+// @Security BearerAuth
+func (h *ItineraryHandlerImpl) CancelItinerary(r *ginext.Request) (*ginext.Response, error) {
+	userID := sharedcontext.GetUserID(r.GinCtx)
+
+	id, err := uuid.Parse(r.GinCtx.Param("id"))
+	if err != nil {
+		return nil, ginext.NewBadRequestError("invalid itinerary id")
+	}
+
+	var req model.CancelBookingRequest
+	if err := r.GinCtx.ShouldBindJSON(&req); err != nil {
+		log.Debug().Err(err).Msg("JSON binding failed")
+		return nil, ginext.NewBadRequestError(err.Error())
+	}
+
+	if err := h.service.CancelItinerary(r.Context(), id, userID, req.Reason); err != nil {
+		log.Error().Err(err).Str("itinerary_id", id.String()).Msg("Failed to cancel itinerary")
+		return nil, err
+	}
+
+	return ginext.NewSuccessResponse("itinerary cancelled successfully"), nil
+}
@@ -0,0 +1,148 @@
+package handler
+
+import (
+	"bus-booking/booking-service/internal/model"
+	"bus-booking/booking-service/internal/service"
+	"bus-booking/shared/ginext"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+type PromotionHandler interface {
+	CreatePromotion(r *ginext.Request) (*ginext.Response, error)
+	ListPromotions(r *ginext.Request) (*ginext.Response, error)
+	GetPromotion(r *ginext.Request) (*ginext.Response, error)
+	UpdatePromotion(r *ginext.Request) (*ginext.Response, error)
+}
+
+type PromotionHandlerImpl struct {
+	service service.PromotionService
+}
+
+func NewPromotionHandler(service service.PromotionService) PromotionHandler {
+	return &PromotionHandlerImpl{
+		service: service,
+	}
+}
+
+// CreatePromotion godoc
+// @Summary Create promotion (Admin)
+// @Description Create a promo code or a single-use voucher. Vouchers without a code get a generated one.
+// @Tags promotions
+// @Accept json
+// @Produce json
+// @Param request body model.CreatePromotionRequest true "Promotion"
+// @Success 201 {object} ginext.Response{data=model.Promotion}
+// @Failure 400 {object} ginext.Response
+// @Failure 409 {object} ginext.Response
+// @Failure 500 {object} ginext.Response
+// @Router /api/v1/promotions [post]
+// @Security BearerAuth
+func (h *PromotionHandlerImpl) CreatePromotion(r *ginext.Request) (*ginext.Response, error) {
+	var req model.CreatePromotionRequest
+	if err := r.GinCtx.ShouldBindJSON(&req); err != nil {
+		log.Debug().Err(err).Msg("JSON binding failed")
+		return nil, ginext.NewBadRequestError(err.Error())
+	}
+
+	promotion, err := h.service.CreatePromotion(r.Context(), &req)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to create promotion")
+		return nil, err
+	}
+
+	return ginext.NewCreatedResponse(promotion), nil
+}
+
+// ListPromotions godoc
+// @Summary List promotions (Admin)
+// @Description List promo codes and vouchers, newest first
+// @Tags promotions
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(20)
+// @Param kind query string false "Promotion kind" Enums(CODE, VOUCHER)
+// @Param is_active query bool false "Only active or inactive promotions"
+// @Param search query string false "Search by code or description"
+// @Success 200 {object} ginext.Response{data=[]model.Promotion,meta=ginext.MetaData}
+// @Failure 400 {object} ginext.Response
+// @Failure 500 {object} ginext.Response
+// @Router /api/v1/promotions [get]
+// @Security BearerAuth
+func (h *PromotionHandlerImpl) ListPromotions(r *ginext.Request) (*ginext.Response, error) {
+	var req model.ListPromotionsRequest
+	if err := r.GinCtx.ShouldBindQuery(&req); err != nil {
+		log.Debug().Err(err).Msg("Query binding failed")
+		return nil, ginext.NewBadRequestError("Invalid query parameters")
+	}
+	req.Normalize()
+
+	promotions, total, err := h.service.ListPromotions(r.Context(), &req)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to list promotions")
+		return nil, err
+	}
+
+	return ginext.NewPaginatedResponse(promotions, req.Page, req.PageSize, total), nil
+}
+
+// GetPromotion godoc
+// @Summary Get promotion (Admin)
+// @Description Get a promo code or voucher with its usage
+// @Tags promotions
+// @Produce json
+// @Param id path string true "Promotion ID" format(uuid)
+// @Success 200 {object} ginext.Response{data=model.Promotion}
+// @Failure 400 {object} ginext.Response
+// @Failure 404 {object} ginext.Response
+// @Router /api/v1/promotions/{id} [get]
+// @Security BearerAuth
+func (h *PromotionHandlerImpl) GetPromotion(r *ginext.Request) (*ginext.Response, error) {
+	id, err := uuid.Parse(r.GinCtx.Param("id"))
+	if err != nil {
+		return nil, ginext.NewBadRequestError("invalid promotion id")
+	}
+
+	promotion, err := h.service.GetPromotion(r.Context(), id)
+	if err != nil {
+		return nil, err
+	}
+
+	return ginext.NewSuccessResponse(promotion), nil
+}
+
+// UpdatePromotion godoc
+// @Summary Update promotion (Admin)
+// @Description Change the limits or validity of a promotion, or switch it off. Vouchers stay single-use.
+// @Tags promotions
+// @Accept json
+// @Produce json
+// @Param id path string true "Promotion ID" format(uuid)
+// @Param request body model.UpdatePromotionRequest true "Changes"
+// @Success 200 {object} ginext.Response{data=model.Promotion}
+// @Failure 400 {object} ginext.Response
+// @Failure 404 {object} ginext.Response
+// @Failure 500 {object} ginext.Response
+// @Router /api/v1/promotions/{id} [put]
+// @Security BearerAuth
+func (h *PromotionHandlerImpl) UpdatePromotion(r *ginext.Request) (*ginext.Response, error) {
+	id, err := uuid.Parse(r.GinCtx.Param("id"))
+	if err != nil {
+		return nil, ginext.NewBadRequestError("invalid promotion id")
+	}
+
+	var req model.UpdatePromotionRequest
+	if err := r.GinCtx.ShouldBindJSON(&req); err != nil {
+		log.Debug().Err(err).Msg("JSON binding failed")
+		return nil, ginext.NewBadRequestError(err.Error())
+	}
+
+	promotion, err := h.service.UpdatePromotion(r.Context(), id, &req)
+	if err != nil {
+		log.Error().Err(err).Str("promotion_id", id.String()).Msg("Failed to update promotion")
+		return nil, err
+	}
+
+	return ginext.NewSuccessResponse(promotion), nil
+}
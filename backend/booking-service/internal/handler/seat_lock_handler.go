@@ -25,7 +25,7 @@ func NewSeatLockHandler(lockService service.SeatLockService) SeatLockHandler {
 
 // LockSeats godoc
 // @Summary Lock seats temporarily
-// @Description Lock selected seats for 5 minutes during booking process
+// @Description Lock selected seats for 5 minutes during booking process. The current trip fare is locked in with them, so bookings made with the session are charged the fare shown
 // @Tags seat-locks
 // @Accept json
 // @Produce json
@@ -42,7 +42,7 @@ func (h *SeatLockHandlerImpl) LockSeats(r *ginext.Request) (*ginext.Response, er
 		return nil, ginext.NewBadRequestError(err.Error())
 	}
 
-	expiresAt, err := h.lockService.LockSeats(r.Context(), req.TripID, req.SeatIDs, req.SessionID)
+	hold, err := h.lockService.LockSeats(r.Context(), req.TripID, req.SeatIDs, req.SessionID)
 	if err != nil {
 		log.Error().Err(err).Msg("failed to lock seats")
 		return nil, err
@@ -50,7 +50,8 @@ func (h *SeatLockHandlerImpl) LockSeats(r *ginext.Request) (*ginext.Response, er
 
 	response := model.LockSeatsResponse{
 		SessionID: req.SessionID,
-		ExpiresAt: expiresAt,
+		ExpiresAt: hold.ExpiresAt,
+		Fare:      hold.Fare,
 		Message:   "Seats locked successfully for 5 minutes",
 	}
 
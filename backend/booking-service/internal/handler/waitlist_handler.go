@@ -0,0 +1,147 @@
+package handler
+
+import (
+	"bus-booking/booking-service/internal/model"
+	"bus-booking/booking-service/internal/service"
+	"bus-booking/shared/ginext"
+
+	sharedcontext "bus-booking/shared/context"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+type WaitlistHandler interface {
+	JoinWaitlist(r *ginext.Request) (*ginext.Response, error)
+	ListMyEntries(r *ginext.Request) (*ginext.Response, error)
+	LeaveWaitlist(r *ginext.Request) (*ginext.Response, error)
+	ClaimOffer(r *ginext.Request) (*ginext.Response, error)
+}
+
+type WaitlistHandlerImpl struct {
+	service service.WaitlistService
+}
+
+func NewWaitlistHandler(service service.WaitlistService) WaitlistHandler {
+	return &WaitlistHandlerImpl{
+		service: service,
+	}
+}
+
+// JoinWaitlist godoc
+// @Summary Join the waitlist of a trip
+// @Description Wait for seats of a sold-out trip. Released seats are offered in the order users joined and held for 15 minutes.
+// @Tags waitlist
+// @Accept json
+// @Produce json
+// @Param request body model.JoinWaitlistRequest true "Waitlist request"
+// @Success 201 {object} ginext.Response{data=model.WaitlistEntryResponse}
+// @Failure 400 {object} ginext.Response
+// @Failure 409 {object} ginext.Response
+// @Failure 500 {object} ginext.Response
+// @Router /api/v1/waitlist [post]
+// @Security BearerAuth
+func (h *WaitlistHandlerImpl) JoinWaitlist(r *ginext.Request) (*ginext.Response, error) {
+	userID := sharedcontext.GetUserID(r.GinCtx)
+
+	var req model.JoinWaitlistRequest
+	if err := r.GinCtx.ShouldBindJSON(&req); err != nil {
+		log.Debug().Err(err).Msg("JSON binding failed")
+		return nil, ginext.NewBadRequestError(err.Error())
+	}
+
+	entry, err := h.service.JoinWaitlist(r.Context(), &req, userID)
+	if err != nil {
+		log.Error().Err(err).Str("trip_id", req.TripID.String()).Msg("Failed to join waitlist")
+		return nil, err
+	}
+
+	return ginext.NewCreatedResponse(entry), nil
+}
+
+// ListMyEntries godoc
+// @Summary List my waitlist entries
+// @Description List the waitlist entries of the current user, newest first, with their position or offered seats
+// @Tags waitlist
+// @Produce json
+// @Success 200 {object} ginext.Response{data=[]model.WaitlistEntryResponse}
+// @Failure 500 {object} ginext.Response
+// @Router /api/v1/waitlist [get]
+// @Security BearerAuth
+func (h *WaitlistHandlerImpl) ListMyEntries(r *ginext.Request) (*ginext.Response, error) {
+	userID := sharedcontext.GetUserID(r.GinCtx)
+
+	entries, err := h.service.ListUserEntries(r.Context(), userID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to list waitlist entries")
+		return nil, err
+	}
+
+	return ginext.NewSuccessResponse(entries), nil
+}
+
+// LeaveWaitlist godoc
+// @Summary Leave a waitlist
+// @Description Leave the waitlist of a trip. Seats offered to the user go to the next in line.
+// @Tags waitlist
+// @Produce json
+// @Param id path string true "Waitlist entry ID" format(uuid)
+// @Success 200 {object} ginext.Response
+// @Failure 400 {object} ginext.Response
+// @Failure 404 {object} ginext.Response
+// @Failure 500 {object} ginext.Response
+// @Router /api/v1/waitlist/{id} [delete]
+// @Security BearerAuth
+func (h *WaitlistHandlerImpl) LeaveWaitlist(r *ginext.Request) (*ginext.Response, error) {
+	userID := sharedcontext.GetUserID(r.GinCtx)
+
+	id, err := uuid.Parse(r.GinCtx.Param("id"))
+	if err != nil {
+		return nil, ginext.NewBadRequestError("invalid waitlist entry id")
+	}
+
+	if err := h.service.LeaveWaitlist(r.Context(), id, userID); err != nil {
+		log.Error().Err(err).Str("entry_id", id.String()).Msg("Failed to leave waitlist")
+		return nil, err
+	}
+
+	return ginext.NewSuccessResponse("left the waitlist"), nil
+}
+
+// ClaimOffer godoc
+// @Summary Claim a waitlist offer
+// @Description Book the seats held by a waitlist offer before it expires. Returns the booking with its payment link.
+// @Tags waitlist
+// @Accept json
+// @Produce json
+// @Param id path string true "Waitlist entry ID" format(uuid)
+// @Param request body model.ClaimWaitlistOfferRequest true "Booking details"
+// @Success 201 {object} ginext.Response{data=model.BookingResponse}
+// @Failure 400 {object} ginext.Response
+// @Failure 404 {object} ginext.Response
+// @Failure 409 {object} ginext.Response
+// @Failure 500 {object} ginext.Response
+// @Router /api/v1/waitlist/{id}/claim [post]
+// @Security BearerAuth
+func (h *WaitlistHandlerImpl) ClaimOffer(r *ginext.Request) (*ginext.Response, error) {
+	userID := sharedcontext.GetUserID(r.GinCtx)
+
+	id, err := uuid.Parse(r.GinCtx.Param("id"))
+	if err != nil {
+		return nil, ginext.NewBadRequestError("invalid waitlist entry id")
+	}
+
+	var req model.ClaimWaitlistOfferRequest
+	if err := r.GinCtx.ShouldBindJSON(&req); err != nil {
+		log.Debug().Err(err).Msg("JSON binding failed")
+		return nil, ginext.NewBadRequestError(err.Error())
+	}
+
+	booking, err := h.service.ClaimOffer(r.Context(), id, userID, &req)
+	if err != nil {
+		log.Error().Err(err).Str("entry_id", id.String()).Msg("Failed to claim waitlist offer")
+		return nil, err
+	}
+
+	return ginext.NewCreatedResponse(booking), nil
+}
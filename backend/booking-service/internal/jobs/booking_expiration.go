@@ -3,8 +3,10 @@ package jobs
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"time"
 
+	"bus-booking/booking-service/internal/constants"
 	"bus-booking/booking-service/internal/repository"
 	"bus-booking/booking-service/internal/service"
 	"bus-booking/shared/queue"
@@ -74,42 +76,52 @@ func (j *BookingExpirationJob) runQueuePolling(ctx context.Context) {
 }
 
 func (j *BookingExpirationJob) processQueue(ctx context.Context) {
-	items, err := j.delayedQueue.Poll(ctx, "booking_expiry", 10) // Limit 10 items per poll
+	items, err := j.delayedQueue.Poll(ctx, constants.QueueNameBookingExpiry, 10) // Limit 10 items per poll
 	if err != nil {
 		log.Error().Err(err).Msg("Failed to poll booking_expiry queue")
 		return
 	}
 
 	for _, item := range items {
-		j.processItem(ctx, item)
+		if err := j.processItem(ctx, item); err != nil {
+			if nackErr := j.delayedQueue.Nack(ctx, constants.QueueNameBookingExpiry, item, err); nackErr != nil {
+				log.Error().Err(nackErr).Str("item_id", item.ID).Msg("Failed to nack booking expiration")
+			}
+			continue
+		}
+		if err := j.delayedQueue.Ack(ctx, constants.QueueNameBookingExpiry, item); err != nil {
+			log.Error().Err(err).Str("item_id", item.ID).Msg("Failed to ack booking expiration")
+		}
 	}
 }
 
-func (j *BookingExpirationJob) processItem(ctx context.Context, item *queue.DelayedItem) {
-	// Parse booking ID from payload
+func (j *BookingExpirationJob) processItem(ctx context.Context, item *queue.DelayedItem) error {
 	// Payload was saved as booking.ID (uuid.UUID) which marshals to string
-	var bookingID uuid.UUID
-
-	// Handle if payload is string or object. uuid marshals to string.
-	// We need to marshal/unmarshal or type assert carefully.
-	// Since we used json.Marshal in Schedule, item.Payload is interface{}.
-	// If it was unmarshaled from JSON in Poll, it depends on how encoding/json treated it.
-	// UUIDs are strings in JSON.
-
-	payloadBytes, err := json.Marshal(item.Payload)
+	bookingID, err := parseUUIDPayload(item)
 	if err != nil {
-		log.Error().Err(err).Msg("Failed to marshal payload for processing")
-		return
+		log.Error().Err(err).Msg("Failed to parse booking ID from payload")
+		return queue.Permanent(err)
 	}
 
-	if err := json.Unmarshal(payloadBytes, &bookingID); err != nil {
-		log.Error().Err(err).Str("payload", string(payloadBytes)).Msg("Failed to unmarshal booking ID from payload")
-		return
+	if err := j.bookingService.ExpireBooking(ctx, bookingID); err != nil {
+		log.Error().Err(err).Str("booking_id", bookingID.String()).Int("attempt", item.Attempts).Msg("Failed to expire booking")
+		return err
 	}
 
-	if err := j.bookingService.ExpireBooking(ctx, bookingID); err != nil {
-		log.Error().Err(err).Str("booking_id", bookingID.String()).Msg("Failed to expire booking")
-	} else {
-		log.Info().Str("booking_id", bookingID.String()).Msg("Successfully processed booking expiration")
+	log.Info().Str("booking_id", bookingID.String()).Msg("Successfully processed booking expiration")
+	return nil
+}
+
+// parseUUIDPayload reads the booking or trip ID the jobs schedule as their payload.
+// Since Poll unmarshals into interface{}, the UUID arrives as a JSON string.
+func parseUUIDPayload(item *queue.DelayedItem) (uuid.UUID, error) {
+	var id uuid.UUID
+	payloadBytes, err := json.Marshal(item.Payload)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("failed to marshal payload: %w", err)
+	}
+	if err := json.Unmarshal(payloadBytes, &id); err != nil {
+		return uuid.Nil, fmt.Errorf("invalid ID payload %s: %w", payloadBytes, err)
 	}
+	return id, nil
 }
@@ -2,12 +2,12 @@ package jobs
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"strings"
 	"time"
 
 	"bus-booking/booking-service/internal/client"
+	"bus-booking/booking-service/internal/constants"
 	"bus-booking/booking-service/internal/model"
 	"bus-booking/booking-service/internal/model/trip"
 	"bus-booking/booking-service/internal/repository"
@@ -63,37 +63,39 @@ func (j *TripReminderJob) runQueuePolling(ctx context.Context) {
 }
 
 func (j *TripReminderJob) processQueue(ctx context.Context) {
-	items, err := j.delayedQueue.Poll(ctx, "trip_reminder", 10)
+	items, err := j.delayedQueue.Poll(ctx, constants.QueueNameTripReminder, 10)
 	if err != nil {
 		log.Error().Err(err).Msg("Failed to poll trip_reminder queue")
 		return
 	}
 
 	for _, item := range items {
-		j.processItem(ctx, item)
+		if err := j.processItem(ctx, item); err != nil {
+			if nackErr := j.delayedQueue.Nack(ctx, constants.QueueNameTripReminder, item, err); nackErr != nil {
+				log.Error().Err(nackErr).Str("item_id", item.ID).Msg("Failed to nack trip reminder")
+			}
+			continue
+		}
+		if err := j.delayedQueue.Ack(ctx, constants.QueueNameTripReminder, item); err != nil {
+			log.Error().Err(err).Str("item_id", item.ID).Msg("Failed to ack trip reminder")
+		}
 	}
 }
 
-func (j *TripReminderJob) processItem(ctx context.Context, item *queue.DelayedItem) {
-	// Parse booking ID from payload
-	var bookingID uuid.UUID
-
-	payloadBytes, err := json.Marshal(item.Payload)
+func (j *TripReminderJob) processItem(ctx context.Context, item *queue.DelayedItem) error {
+	bookingID, err := parseUUIDPayload(item)
 	if err != nil {
-		log.Error().Err(err).Msg("Failed to marshal payload for processing")
-		return
-	}
-
-	if err := json.Unmarshal(payloadBytes, &bookingID); err != nil {
-		log.Error().Err(err).Str("payload", string(payloadBytes)).Msg("Failed to unmarshal booking ID from payload")
-		return
+		log.Error().Err(err).Msg("Failed to parse booking ID from payload")
+		return queue.Permanent(err)
 	}
 
 	if err := j.sendReminder(ctx, bookingID); err != nil {
-		log.Error().Err(err).Str("booking_id", bookingID.String()).Msg("Failed to send trip reminder")
-	} else {
-		log.Info().Str("booking_id", bookingID.String()).Msg("Successfully sent trip reminder")
+		log.Error().Err(err).Str("booking_id", bookingID.String()).Int("attempt", item.Attempts).Msg("Failed to send trip reminder")
+		return err
 	}
+
+	log.Info().Str("booking_id", bookingID.String()).Msg("Successfully sent trip reminder")
+	return nil
 }
 
 func (j *TripReminderJob) sendReminder(ctx context.Context, bookingID uuid.UUID) error {
@@ -0,0 +1,81 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"bus-booking/booking-service/internal/constants"
+	"bus-booking/booking-service/internal/service"
+	"bus-booking/shared/queue"
+
+	"github.com/rs/zerolog/log"
+)
+
+// WaitlistOfferJob offers released seats to the trip's waitlist and rolls lapsed offers
+// over to the next in line. Each trip has at most one item in the queue, keyed by trip ID.
+type WaitlistOfferJob struct {
+	waitlistService service.WaitlistService
+	delayedQueue    queue.DelayedQueueManager
+	interval        time.Duration
+}
+
+func NewWaitlistOfferJob(waitlistService service.WaitlistService, delayedQueue queue.DelayedQueueManager) *WaitlistOfferJob {
+	return &WaitlistOfferJob{
+		waitlistService: waitlistService,
+		delayedQueue:    delayedQueue,
+		interval:        5 * time.Second,
+	}
+}
+
+func (j *WaitlistOfferJob) Start(ctx context.Context) {
+	log.Info().Msg("Waitlist offer job started")
+	go j.runQueuePolling(ctx)
+}
+
+func (j *WaitlistOfferJob) runQueuePolling(ctx context.Context) {
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			j.processQueue(ctx)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (j *WaitlistOfferJob) processQueue(ctx context.Context) {
+	items, err := j.delayedQueue.Poll(ctx, constants.QueueNameWaitlistOffer, 10)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to poll waitlist_offer queue")
+		return
+	}
+
+	for _, item := range items {
+		if err := j.processItem(ctx, item); err != nil {
+			if nackErr := j.delayedQueue.Nack(ctx, constants.QueueNameWaitlistOffer, item, err); nackErr != nil {
+				log.Error().Err(nackErr).Str("item_id", item.ID).Msg("Failed to nack waitlist offer")
+			}
+			continue
+		}
+		if err := j.delayedQueue.Ack(ctx, constants.QueueNameWaitlistOffer, item); err != nil {
+			log.Error().Err(err).Str("item_id", item.ID).Msg("Failed to ack waitlist offer")
+		}
+	}
+}
+
+func (j *WaitlistOfferJob) processItem(ctx context.Context, item *queue.DelayedItem) error {
+	tripID, err := parseUUIDPayload(item)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to parse trip ID from payload")
+		return queue.Permanent(err)
+	}
+
+	if err := j.waitlistService.OfferSeats(ctx, tripID); err != nil {
+		log.Error().Err(err).Str("trip_id", tripID.String()).Int("attempt", item.Attempts).Msg("Failed to offer seats to waitlist")
+		return err
+	}
+	return nil
+}
@@ -16,6 +16,7 @@ type Booking struct {
 	Status             BookingStatus             `json:"status" gorm:"type:varchar(20);not null;default:'pending';index"`
 	TransactionStatus  payment.TransactionStatus `json:"transaction_status" gorm:"type:varchar(20);not null;default:'pending';index"`
 	TransactionID      uuid.UUID                 `json:"transaction_id,omitempty" gorm:"type:uuid;index"`
+	PaymentMethod      payment.PaymentMethod     `json:"payment_method" gorm:"type:varchar(20);not null;default:'PAYOS'"`
 	ExpiresAt          *time.Time                `json:"expires_at,omitempty" gorm:"type:timestamptz;index"`
 	ConfirmedAt        *time.Time                `json:"confirmed_at,omitempty" gorm:"type:timestamptz"`
 	CancelledAt        *time.Time                `json:"cancelled_at,omitempty" gorm:"type:timestamptz"`
@@ -23,6 +24,23 @@ type Booking struct {
 	Notes              string                    `json:"notes,omitempty" gorm:"type:text"`
 	IsBoarded          bool                      `json:"is_boarded" gorm:"default:false"`
 
+	// Promotion applied at booking time; TotalAmount already has DiscountAmount taken off
+	PromotionID    *uuid.UUID `json:"promotion_id,omitempty" gorm:"type:uuid"`
+	PromoCode      string     `json:"promo_code,omitempty" gorm:"type:varchar(50)"`
+	DiscountAmount int        `json:"discount_amount" gorm:"type:decimal(10,2);not null;default:0"`
+
+	// Segment bookings only; nil means the whole trip
+	PickupStopID     *uuid.UUID `json:"pickup_stop_id,omitempty" gorm:"type:uuid"`
+	DropoffStopID    *uuid.UUID `json:"dropoff_stop_id,omitempty" gorm:"type:uuid"`
+	PickupStopOrder  *int       `json:"pickup_stop_order,omitempty" gorm:"type:int"`
+	DropoffStopOrder *int       `json:"dropoff_stop_order,omitempty" gorm:"type:int"`
+	PickupLocation   string     `json:"pickup_location,omitempty" gorm:"type:varchar(255)"`
+	DropoffLocation  string     `json:"dropoff_location,omitempty" gorm:"type:varchar(255)"`
+
+	// Itinerary legs only; legs share the itinerary's transaction, made out to leg 1
+	ItineraryID *uuid.UUID `json:"itinerary_id,omitempty" gorm:"type:uuid;index"`
+	LegNumber   int        `json:"leg_number,omitempty" gorm:"type:int;not null;default:0"`
+
 	BookingSeats []BookingSeat `json:"booking_seats,omitempty" gorm:"foreignKey:BookingID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
 }
 
@@ -52,11 +70,42 @@ func (Booking) TableName() string {
 	return "bookings"
 }
 
-// BuyerInfo contains buyer information for payment
+// Segment returns the stop range the booking holds its seats for
+func (b *Booking) Segment() TripSegment {
+	segment := FullTripSegment()
+	if b.PickupStopOrder != nil {
+		segment.FromStopOrder = *b.PickupStopOrder
+	}
+	if b.DropoffStopOrder != nil {
+		segment.ToStopOrder = *b.DropoffStopOrder
+	}
+	return segment
+}
+
+// BuyerInfo contains buyer information for payment. Giving a tax code asks for a VAT invoice
+// made out to the buyer's company once the booking is paid.
 type BuyerInfo struct {
 	Name  string `json:"name" binding:"required"`
 	Email string `json:"email" binding:"required,email"`
 	Phone string `json:"phone" binding:"required"`
+
+	CompanyName    string `json:"company_name,omitempty" binding:"required_with=TaxCode,max=255"`
+	TaxCode        string `json:"tax_code,omitempty" binding:"omitempty,min=10,max=14"`
+	CompanyAddress string `json:"company_address,omitempty" binding:"required_with=TaxCode,max=500"`
+}
+
+// InvoiceBuyer returns the company of the VAT invoice asked for, nil when the buyer asked for none
+func (b *BuyerInfo) InvoiceBuyer() *payment.InvoiceBuyer {
+	if b == nil || b.TaxCode == "" {
+		return nil
+	}
+	return &payment.InvoiceBuyer{
+		Name:        b.Name,
+		CompanyName: b.CompanyName,
+		TaxCode:     b.TaxCode,
+		Address:     b.CompanyAddress,
+		Email:       b.Email,
+	}
 }
 
 // CreatePaymentRequest is the request to create payment for a booking
@@ -66,7 +115,13 @@ type CreatePaymentRequest struct {
 
 // UpdateBookingStatusRequest updates booking payment status (internal use)
 type UpdateBookingStatusRequest struct {
+	TransactionID     uuid.UUID                 `json:"transaction_id,omitempty"`
 	TransactionStatus payment.TransactionStatus `json:"transaction_status" validate:"required"`
+	// AmountPaid is what the passenger transferred so far, reported with UNDERPAID payments
+	AmountPaid int `json:"amount_paid,omitempty"`
+
+	// EventID is the payment outbox event being delivered, taken from the X-Event-ID header
+	EventID uuid.UUID `json:"-"`
 }
 
 type GetUserBookingsRequest struct {
@@ -0,0 +1,86 @@
+package model
+
+import (
+	"bus-booking/booking-service/internal/model/payment"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrExchangeNotPending is returned when completing an exchange that was already completed or given up
+var ErrExchangeNotPending = errors.New("booking exchange is no longer pending")
+
+// BookingExchange records a request to move a booking to another trip on the same route
+type BookingExchange struct {
+	BaseModel
+	BookingID     uuid.UUID      `json:"booking_id" gorm:"type:uuid;not null;index"`
+	OldTripID     uuid.UUID      `json:"old_trip_id" gorm:"type:uuid;not null"`
+	NewTripID     uuid.UUID      `json:"new_trip_id" gorm:"type:uuid;not null;index"`
+	OldAmount     int            `json:"old_amount" gorm:"type:decimal(10,2);not null"`
+	NewAmount     int            `json:"new_amount" gorm:"type:decimal(10,2);not null"`
+	Surcharge     int            `json:"surcharge" gorm:"type:decimal(10,2);not null;default:0"`
+	AmountDue     int            `json:"amount_due" gorm:"type:decimal(10,2);not null"` // negative when the difference is refunded
+	Status        ExchangeStatus `json:"status" gorm:"type:varchar(20);not null;default:'PENDING';index"`
+	TransactionID *uuid.UUID     `json:"transaction_id,omitempty" gorm:"type:uuid;index"`
+	RefundID      *uuid.UUID     `json:"refund_id,omitempty" gorm:"type:uuid"`
+	ExpiresAt     *time.Time     `json:"expires_at,omitempty" gorm:"type:timestamptz"`
+	CompletedAt   *time.Time     `json:"completed_at,omitempty" gorm:"type:timestamptz"`
+
+	Seats []BookingExchangeSeat `json:"seats,omitempty" gorm:"foreignKey:ExchangeID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+}
+
+func (BookingExchange) TableName() string {
+	return "booking_exchanges"
+}
+
+// BookingExchangeSeat is a snapshot of a seat on the new trip, copied into booking_seats once the exchange completes
+type BookingExchangeSeat struct {
+	BaseModel
+	ExchangeID      uuid.UUID `json:"exchange_id" gorm:"type:uuid;not null;index"`
+	SeatID          uuid.UUID `json:"seat_id" gorm:"type:uuid;not null"`
+	SeatNumber      string    `json:"seat_number" gorm:"type:varchar(10);not null"`
+	SeatType        string    `json:"seat_type" gorm:"type:varchar(50);not null"`
+	Floor           int       `json:"floor" gorm:"type:int;not null;default:1"`
+	Price           float64   `json:"price" gorm:"type:decimal(10,2);not null"`
+	PriceMultiplier float64   `json:"price_multiplier" gorm:"type:decimal(3,2);not null;default:1.0"`
+}
+
+func (BookingExchangeSeat) TableName() string {
+	return "booking_exchange_seats"
+}
+
+type ExchangeStatus string
+
+const (
+	ExchangeStatusPending   ExchangeStatus = "PENDING"
+	ExchangeStatusCompleted ExchangeStatus = "COMPLETED"
+	ExchangeStatusFailed    ExchangeStatus = "FAILED"
+	ExchangeStatusCancelled ExchangeStatus = "CANCELLED"
+	ExchangeStatusExpired   ExchangeStatus = "EXPIRED"
+)
+
+// ExchangeBookingRequest represents a request to move a booking to another trip on the same route
+type ExchangeBookingRequest struct {
+	TripID  uuid.UUID   `json:"trip_id" binding:"required"`
+	SeatIDs []uuid.UUID `json:"seat_ids" binding:"required,min=1,max=10,dive"`
+}
+
+// BookingExchangeResponse represents the result of an exchange request
+type BookingExchangeResponse struct {
+	ID          uuid.UUID             `json:"id"`
+	BookingID   uuid.UUID             `json:"booking_id"`
+	OldTripID   uuid.UUID             `json:"old_trip_id"`
+	NewTripID   uuid.UUID             `json:"new_trip_id"`
+	OldAmount   int                   `json:"old_amount"`
+	NewAmount   int                   `json:"new_amount"`
+	Surcharge   int                   `json:"surcharge"`
+	AmountDue   int                   `json:"amount_due"`
+	Status      ExchangeStatus        `json:"status"`
+	ExpiresAt   *time.Time            `json:"expires_at,omitempty"`
+	CompletedAt *time.Time            `json:"completed_at,omitempty"`
+	Seats       []BookingSeatResponse `json:"seats"`
+
+	Transaction *payment.TransactionResponse `json:"transaction,omitempty"`
+	Refund      *payment.RefundResponse      `json:"refund,omitempty"`
+}
@@ -1,6 +1,8 @@
 package model
 
 import (
+	"time"
+
 	"github.com/google/uuid"
 )
 
@@ -15,6 +17,15 @@ type BookingSeat struct {
 	Floor           int     `json:"floor" gorm:"type:int;not null;default:1"`
 	Price           float64 `json:"price" gorm:"type:decimal(10,2);not null"`
 	PriceMultiplier float64 `json:"price_multiplier" gorm:"type:decimal(3,2);not null;default:1.0"`
+
+	// named passenger travelling on this seat, empty when the booker did not provide one
+	PassengerName        string               `json:"passenger_name,omitempty" gorm:"column:passenger_name;type:varchar(255)"`
+	PassengerPhone       string               `json:"passenger_phone,omitempty" gorm:"column:passenger_phone;type:varchar(20)"`
+	PassengerIDNumber    string               `json:"passenger_id_number,omitempty" gorm:"column:passenger_id;type:varchar(50)"`
+	PassengerAgeCategory PassengerAgeCategory `json:"passenger_age_category,omitempty" gorm:"column:passenger_age_category;type:varchar(20)"`
+
+	IsBoarded bool       `json:"is_boarded" gorm:"not null;default:false"`
+	BoardedAt *time.Time `json:"boarded_at,omitempty" gorm:"type:timestamptz"`
 }
 
 func (BookingSeat) TableName() string {
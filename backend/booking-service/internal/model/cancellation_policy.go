@@ -0,0 +1,77 @@
+package model
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RefundTier defines the refund percentage granted when a booking is cancelled
+// at least HoursBeforeDeparture hours before the trip departs
+type RefundTier struct {
+	HoursBeforeDeparture int `json:"hours_before_departure"`
+	RefundPercent        int `json:"refund_percent"`
+}
+
+// ParseRefundTiers parses tiers in the form "24:70,12:50,6:30" (hours:percent)
+// and returns them ordered from the longest to the shortest notice period
+func ParseRefundTiers(value string) ([]RefundTier, error) {
+	var tiers []RefundTier
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		hoursStr, percentStr, ok := strings.Cut(part, ":")
+		if !ok {
+			return nil, fmt.Errorf("invalid refund tier %q: expected hours:percent", part)
+		}
+
+		hours, err := strconv.Atoi(strings.TrimSpace(hoursStr))
+		if err != nil || hours < 0 {
+			return nil, fmt.Errorf("invalid refund tier hours %q", hoursStr)
+		}
+
+		percent, err := strconv.Atoi(strings.TrimSpace(percentStr))
+		if err != nil || percent < 0 || percent > 100 {
+			return nil, fmt.Errorf("invalid refund tier percent %q", percentStr)
+		}
+
+		tiers = append(tiers, RefundTier{
+			HoursBeforeDeparture: hours,
+			RefundPercent:        percent,
+		})
+	}
+
+	SortRefundTiers(tiers)
+	return tiers, nil
+}
+
+// SortRefundTiers orders tiers from the longest to the shortest notice period
+func SortRefundTiers(tiers []RefundTier) {
+	sort.SliceStable(tiers, func(i, j int) bool {
+		return tiers[i].HoursBeforeDeparture > tiers[j].HoursBeforeDeparture
+	})
+}
+
+// CancellationPolicyResponse represents the public cancellation policy
+type CancellationPolicyResponse struct {
+	Tiers []RefundTier `json:"tiers"`
+}
+
+// RefundQuoteResponse represents the refund a passenger would receive
+// if the booking were cancelled now
+type RefundQuoteResponse struct {
+	BookingID            uuid.UUID `json:"booking_id"`
+	DepartureTime        time.Time `json:"departure_time"`
+	HoursBeforeDeparture float64   `json:"hours_before_departure"`
+	TotalAmount          int       `json:"total_amount"`
+	RefundPercent        int       `json:"refund_percent"`
+	RefundAmount         int       `json:"refund_amount"`
+	Cancellable          bool      `json:"cancellable"`
+}
@@ -0,0 +1,15 @@
+package model
+
+import "bus-booking/shared/queue"
+
+// ListDeadLettersRequest filters dead letters of the background job queues.
+// An empty Queue lists every queue.
+type ListDeadLettersRequest struct {
+	Queue string `form:"queue"`
+	Limit int    `form:"limit,default=50" binding:"omitempty,min=1,max=500"`
+}
+
+type DeadLettersResponse struct {
+	Total       int                 `json:"total"`
+	DeadLetters []*queue.DeadLetter `json:"dead_letters"`
+}
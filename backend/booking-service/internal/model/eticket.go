@@ -0,0 +1,103 @@
+package model
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"bus-booking/shared/utils"
+
+	"github.com/google/uuid"
+)
+
+// ETicketQRVersion prefixes every e-ticket QR code so the format can evolve
+const ETicketQRVersion = "BBT1"
+
+var (
+	ErrInvalidETicketQR   = errors.New("invalid e-ticket QR code")
+	ErrSeatAlreadyBoarded = errors.New("seat already boarded")
+)
+
+// ETicketQRPayload is the signed content of the QR code printed on an e-ticket.
+// Field names are kept short to keep the QR code small.
+type ETicketQRPayload struct {
+	BookingID        uuid.UUID   `json:"b"`
+	BookingReference string      `json:"r"`
+	TripID           uuid.UUID   `json:"t"`
+	SeatIDs          []uuid.UUID `json:"s"`
+}
+
+func NewETicketQRPayload(booking *Booking) *ETicketQRPayload {
+	seatIDs := make([]uuid.UUID, 0, len(booking.BookingSeats))
+	for _, seat := range booking.BookingSeats {
+		seatIDs = append(seatIDs, seat.SeatID)
+	}
+
+	return &ETicketQRPayload{
+		BookingID:        booking.ID,
+		BookingReference: booking.BookingReference,
+		TripID:           booking.TripID,
+		SeatIDs:          seatIDs,
+	}
+}
+
+// Encode renders the payload as "<version>.<base64url json>.<hmac>"
+func (p *ETicketQRPayload) Encode(secret string) (string, error) {
+	data, err := json.Marshal(p)
+	if err != nil {
+		return "", err
+	}
+
+	signed := ETicketQRVersion + "." + base64.RawURLEncoding.EncodeToString(data)
+	return signed + "." + utils.SignHMAC(secret, signed), nil
+}
+
+// ParseETicketQR verifies the signature of an encoded QR code and returns its payload
+func ParseETicketQR(code, secret string) (*ETicketQRPayload, error) {
+	parts := strings.Split(strings.TrimSpace(code), ".")
+	if len(parts) != 3 || parts[0] != ETicketQRVersion {
+		return nil, ErrInvalidETicketQR
+	}
+
+	signed := parts[0] + "." + parts[1]
+	if !utils.VerifyHMAC(secret, signed, parts[2]) {
+		return nil, ErrInvalidETicketQR
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, ErrInvalidETicketQR
+	}
+
+	var payload ETicketQRPayload
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return nil, ErrInvalidETicketQR
+	}
+
+	return &payload, nil
+}
+
+// TicketCheckInRequest boards seats of the ticket scanned by the driver app.
+// SeatIDs selects the seats to board; when empty every seat on the ticket not yet boarded is boarded.
+type TicketCheckInRequest struct {
+	QRCode  string      `json:"qr_code" binding:"required"`
+	SeatIDs []uuid.UUID `json:"seat_ids,omitempty"`
+}
+
+type TicketCheckInResponse struct {
+	BookingID        uuid.UUID               `json:"booking_id"`
+	BookingReference string                  `json:"booking_reference"`
+	TripID           uuid.UUID               `json:"trip_id"`
+	CheckedIn        []string                `json:"checked_in"` // seat numbers boarded by this request
+	Seats            []TicketSeatCheckInInfo `json:"seats"`
+	AllBoarded       bool                    `json:"all_boarded"`
+}
+
+type TicketSeatCheckInInfo struct {
+	SeatID     uuid.UUID  `json:"seat_id"`
+	SeatNumber string     `json:"seat_number"`
+	IsBoarded  bool       `json:"is_boarded"`
+	BoardedAt  *time.Time `json:"boarded_at,omitempty"`
+}
@@ -0,0 +1,152 @@
+package model
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"bus-booking/booking-service/internal/model/payment"
+
+	"github.com/google/uuid"
+)
+
+type ItineraryKind string
+
+const (
+	// ItineraryKindRoundTrip goes out and back along the same route
+	ItineraryKindRoundTrip ItineraryKind = "ROUND_TRIP"
+	// ItineraryKindMultiLeg chains connecting trips, or any other set of legs
+	ItineraryKindMultiLeg ItineraryKind = "MULTI_LEG"
+)
+
+// Itinerary groups the bookings of one journey, e.g. the outbound and return of a round trip,
+// under a single reference and payment. Each leg is a booking holding seats on its own trip.
+// The combined transaction is made out to leg 1, and every leg follows its status.
+type Itinerary struct {
+	BaseModel
+	ItineraryReference string                `json:"itinerary_reference" gorm:"type:varchar(20);unique;not null"`
+	UserID             uuid.UUID             `json:"user_id" gorm:"type:uuid;not null;index"`
+	Kind               ItineraryKind         `json:"kind" gorm:"type:varchar(20);not null"`
+	TotalAmount        int                   `json:"total_amount" gorm:"type:decimal(10,2);not null"`
+	DiscountAmount     int                   `json:"discount_amount" gorm:"type:decimal(10,2);not null;default:0"`
+	TransactionID      uuid.UUID             `json:"transaction_id" gorm:"type:uuid;not null;index"`
+	PaymentMethod      payment.PaymentMethod `json:"payment_method" gorm:"type:varchar(20);not null;default:'PAYOS'"`
+	ExpiresAt          *time.Time            `json:"expires_at,omitempty" gorm:"type:timestamptz"`
+
+	Bookings []Booking `json:"bookings,omitempty" gorm:"foreignKey:ItineraryID"`
+}
+
+func (Itinerary) TableName() string {
+	return "itineraries"
+}
+
+// Status sums up the legs: pending while the payment is awaited, confirmed once paid even if
+// some legs were cancelled since, and otherwise the way the legs ended
+func (i *Itinerary) Status() BookingStatus {
+	if len(i.Bookings) == 0 {
+		return BookingStatusPending
+	}
+	for _, status := range []BookingStatus{BookingStatusPending, BookingStatusConfirmed} {
+		for _, leg := range i.Bookings {
+			if leg.Status == status {
+				return status
+			}
+		}
+	}
+	return i.Bookings[0].Status
+}
+
+// RoundTripDiscount takes Percent off a round trip whose return departs
+// within WithinDays days of the outbound
+type RoundTripDiscount struct {
+	WithinDays int `json:"within_days"`
+	Percent    int `json:"percent"`
+}
+
+// ParseRoundTripDiscounts parses discounts in the form "7:10,30:5" (days:percent)
+// and returns them ordered from the shortest to the longest stay
+func ParseRoundTripDiscounts(value string) ([]RoundTripDiscount, error) {
+	var discounts []RoundTripDiscount
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		daysStr, percentStr, ok := strings.Cut(part, ":")
+		if !ok {
+			return nil, fmt.Errorf("invalid round-trip discount %q: expected days:percent", part)
+		}
+
+		days, err := strconv.Atoi(strings.TrimSpace(daysStr))
+		if err != nil || days <= 0 {
+			return nil, fmt.Errorf("invalid round-trip discount days %q", daysStr)
+		}
+
+		percent, err := strconv.Atoi(strings.TrimSpace(percentStr))
+		if err != nil || percent <= 0 || percent > 100 {
+			return nil, fmt.Errorf("invalid round-trip discount percent %q", percentStr)
+		}
+
+		discounts = append(discounts, RoundTripDiscount{
+			WithinDays: days,
+			Percent:    percent,
+		})
+	}
+
+	sort.SliceStable(discounts, func(i, j int) bool {
+		return discounts[i].WithinDays < discounts[j].WithinDays
+	})
+	return discounts, nil
+}
+
+// ItineraryLegRequest is one leg of an itinerary, booked like CreateBookingRequest
+type ItineraryLegRequest struct {
+	TripID  uuid.UUID   `json:"trip_id" binding:"required"`
+	SeatIDs []uuid.UUID `json:"seat_ids" binding:"required,min=1,max=10,dive"`
+
+	// Optional segment between intermediate route stops; both must be given together
+	PickupStopID  *uuid.UUID `json:"pickup_stop_id,omitempty"`
+	DropoffStopID *uuid.UUID `json:"dropoff_stop_id,omitempty"`
+
+	// Optional named passenger per seat; each entry must reference one of SeatIDs
+	Passengers []PassengerInfo `json:"passengers,omitempty" binding:"omitempty,max=10,dive"`
+}
+
+// CreateItineraryRequest books several legs under one reference and pays for them at once.
+// Legs are given in travel order; each must depart after the previous one arrives.
+type CreateItineraryRequest struct {
+	Legs  []ItineraryLegRequest `json:"legs" binding:"required,min=2,max=4,dive"`
+	Notes string                `json:"notes,omitempty"`
+
+	// How the itinerary is paid, PAYOS when empty
+	PaymentMethod payment.PaymentMethod `json:"payment_method,omitempty" binding:"omitempty,oneof=PAYOS CASH SANDBOX"`
+
+	// Seat-lock session holding the seats of every leg during checkout
+	SessionID string `json:"session_id,omitempty"`
+
+	// Optional buyer details, with a tax code to get one VAT invoice for the itinerary
+	BuyerInfo *BuyerInfo `json:"buyer_info,omitempty"`
+
+	// Pay from the wallet balance first; the payment method only covers what the wallet cannot
+	UseWallet bool `json:"use_wallet,omitempty"`
+}
+
+type ItineraryResponse struct {
+	ID                 uuid.UUID             `json:"id"`
+	CreatedAt          time.Time             `json:"created_at"`
+	ItineraryReference string                `json:"itinerary_reference"`
+	UserID             uuid.UUID             `json:"user_id"`
+	Kind               ItineraryKind         `json:"kind"`
+	Status             BookingStatus         `json:"status"`
+	TotalAmount        int                   `json:"total_amount"`
+	DiscountAmount     int                   `json:"discount_amount,omitempty"`
+	TransactionID      uuid.UUID             `json:"transaction_id"`
+	PaymentMethod      payment.PaymentMethod `json:"payment_method"`
+	ExpiresAt          *time.Time            `json:"expires_at,omitempty"`
+
+	Legs        []*BookingResponse           `json:"legs"`
+	Transaction *payment.TransactionResponse `json:"transaction,omitempty"`
+}
@@ -0,0 +1,31 @@
+package model
+
+import "github.com/google/uuid"
+
+const (
+	AggregateTypeBooking = "booking"
+
+	// EventTypePaymentCancelDue cancels the payment of a booking that expired before it was paid
+	EventTypePaymentCancelDue = "booking.payment_cancel_due"
+	// EventTypeExchangeRefundDue refunds the fare difference of an exchange to a cheaper trip, or of one
+	// that failed after it was paid
+	EventTypeExchangeRefundDue = "booking.exchange_refund_due"
+)
+
+// PaymentCancelDueEvent is the payload of EventTypePaymentCancelDue
+type PaymentCancelDueEvent struct {
+	BookingID     uuid.UUID `json:"booking_id"`
+	TransactionID uuid.UUID `json:"transaction_id"`
+}
+
+// ExchangeRefundDueEvent is the payload of EventTypeExchangeRefundDue. The exchange ID keys the refund,
+// so a redelivered event refunds once.
+type ExchangeRefundDueEvent struct {
+	ExchangeID       uuid.UUID `json:"exchange_id"`
+	BookingID        uuid.UUID `json:"booking_id"`
+	BookingReference string    `json:"booking_reference"`
+	TransactionID    uuid.UUID `json:"transaction_id"`
+	Amount           int       `json:"amount"`
+	// Completed is set when the exchange went through to a cheaper fare, rather than failing after payment
+	Completed bool `json:"completed,omitempty"`
+}
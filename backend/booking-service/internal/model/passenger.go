@@ -1,18 +1,76 @@
 package model
 
-import "github.com/google/uuid"
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type PassengerAgeCategory string
+
+const (
+	PassengerAgeCategoryAdult  PassengerAgeCategory = "ADULT"
+	PassengerAgeCategoryChild  PassengerAgeCategory = "CHILD"
+	PassengerAgeCategorySenior PassengerAgeCategory = "SENIOR"
+)
+
+// PassengerInfo names the passenger travelling on one seat of a booking
+type PassengerInfo struct {
+	SeatID      uuid.UUID            `json:"seat_id" binding:"required"`
+	FullName    string               `json:"full_name" binding:"required,min=1,max=100"`
+	Phone       string               `json:"phone,omitempty" binding:"omitempty,min=10,max=15"`
+	IDNumber    string               `json:"id_number,omitempty" binding:"omitempty,max=50"`
+	AgeCategory PassengerAgeCategory `json:"age_category,omitempty" binding:"omitempty,oneof=ADULT CHILD SENIOR"`
+}
 
 // PassengerResponse represents a passenger in a trip
 type PassengerResponse struct {
-	UserID           uuid.UUID `json:"user_id"`
-	FullName         string    `json:"full_name"`
-	Email            string    `json:"email"`
-	Phone            string    `json:"phone"`
-	BookingID        uuid.UUID `json:"booking_id"`
-	BookingReference string    `json:"booking_reference"`
-	Status           string    `json:"status"`
-	Seats            []string  `json:"seats"` // e.g. ["A1", "B2"]
-	OriginalPrice    int       `json:"original_price"`
-	PaidPrice        int       `json:"paid_price"`
-	IsBoarded        bool      `json:"is_boarded"`
+	UserID           uuid.UUID               `json:"user_id"`
+	FullName         string                  `json:"full_name"`
+	Email            string                  `json:"email"`
+	Phone            string                  `json:"phone"`
+	BookingID        uuid.UUID               `json:"booking_id"`
+	BookingReference string                  `json:"booking_reference"`
+	Status           string                  `json:"status"`
+	Seats            []string                `json:"seats"` // e.g. ["A1", "B2"]
+	Passengers       []SeatPassengerResponse `json:"passengers"`
+	OriginalPrice    int                     `json:"original_price"`
+	PaidPrice        int                     `json:"paid_price"`
+	IsBoarded        bool                    `json:"is_boarded"`
+}
+
+// SeatPassengerResponse is the passenger travelling on a single seat of a booking
+type SeatPassengerResponse struct {
+	SeatID      uuid.UUID            `json:"seat_id"`
+	SeatNumber  string               `json:"seat_number"`
+	FullName    string               `json:"full_name"`
+	Phone       string               `json:"phone,omitempty"`
+	IDNumber    string               `json:"id_number,omitempty"`
+	AgeCategory PassengerAgeCategory `json:"age_category,omitempty"`
+	IsBoarded   bool                 `json:"is_boarded"`
+}
+
+// TripManifestResponse lists every occupied seat of a trip for the driver and station staff
+type TripManifestResponse struct {
+	TripID          uuid.UUID           `json:"trip_id"`
+	Origin          string              `json:"origin,omitempty"`
+	Destination     string              `json:"destination,omitempty"`
+	DepartureTime   time.Time           `json:"departure_time"`
+	BusPlateNumber  string              `json:"bus_plate_number,omitempty"`
+	TotalPassengers int                 `json:"total_passengers"`
+	BoardedCount    int                 `json:"boarded_count"`
+	Entries         []TripManifestEntry `json:"entries"`
+}
+
+// TripManifestEntry is one seat on the trip manifest. Passenger details fall back to
+// the booker when no named passenger was given for the seat.
+type TripManifestEntry struct {
+	SeatNumber       string               `json:"seat_number"`
+	FullName         string               `json:"full_name"`
+	Phone            string               `json:"phone,omitempty"`
+	IDNumber         string               `json:"id_number,omitempty"`
+	AgeCategory      PassengerAgeCategory `json:"age_category,omitempty"`
+	BookingID        uuid.UUID            `json:"booking_id"`
+	BookingReference string               `json:"booking_reference"`
+	IsBoarded        bool                 `json:"is_boarded"`
 }
@@ -14,4 +14,19 @@ type CreateTransactionRequest struct {
 	PaymentMethod PaymentMethod `json:"payment_method"`
 	Description   string        `json:"description"`
 	ExpiresAt     time.Time     `json:"expires_at"`
+	InvoiceBuyer  *InvoiceBuyer `json:"invoice_buyer,omitempty"`
+	UseWallet     bool          `json:"use_wallet,omitempty"`
+	// Other bookings paid for together with BookingID, e.g. the return leg of a round trip
+	LegBookingIDs []uuid.UUID `json:"leg_booking_ids,omitempty"`
+	// OriginalTransactionID marks the fare difference of an exchange, paid on top of the booking's payment
+	OriginalTransactionID *uuid.UUID `json:"original_transaction_id,omitempty"`
+}
+
+// InvoiceBuyer is the company a VAT invoice of the booking is made out to once it is paid
+type InvoiceBuyer struct {
+	Name        string `json:"name,omitempty"`
+	CompanyName string `json:"company_name"`
+	TaxCode     string `json:"tax_code"`
+	Address     string `json:"address"`
+	Email       string `json:"email,omitempty"`
 }
@@ -0,0 +1,37 @@
+package payment
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type RefundStatus string
+
+const (
+	RefundStatusPending    RefundStatus = "PENDING"
+	RefundStatusProcessing RefundStatus = "PROCESSING"
+	RefundStatusCompleted  RefundStatus = "COMPLETED"
+	RefundStatusRejected   RefundStatus = "REJECTED"
+)
+
+type RefundRequest struct {
+	// ID is the idempotency key of an operator refund, a retried request returns the refund it created
+	ID           uuid.UUID `json:"id,omitempty"`
+	BookingID    uuid.UUID `json:"booking_id"`
+	Reason       string    `json:"reason"`
+	RefundAmount int       `json:"refund_amount"`
+	// BookingSeatIDs are the seats refunded; payment service refunds each seat at most once
+	BookingSeatIDs []uuid.UUID `json:"booking_seat_ids,omitempty"`
+}
+
+type RefundResponse struct {
+	ID           uuid.UUID    `json:"id"`
+	CreatedAt    time.Time    `json:"created_at"`
+	UpdatedAt    time.Time    `json:"updated_at"`
+	BookingID    uuid.UUID    `json:"booking_id"`
+	UserID       uuid.UUID    `json:"user_id"`
+	RefundAmount int          `json:"refund_amount"`
+	RefundStatus RefundStatus `json:"refund_status"`
+	RefundReason string       `json:"refund_reason"`
+}
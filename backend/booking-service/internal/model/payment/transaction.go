@@ -13,7 +13,10 @@ type TransactionStatus string
 const (
 	CurrencyVND Currency = "VND"
 
-	PaymentMethodPayOS PaymentMethod = "PAYOS"
+	PaymentMethodPayOS   PaymentMethod = "PAYOS"
+	PaymentMethodCash    PaymentMethod = "CASH"
+	PaymentMethodSandbox PaymentMethod = "SANDBOX"
+	PaymentMethodWallet  PaymentMethod = "WALLET"
 
 	TransactionStatusPending    TransactionStatus = "PENDING"
 	TransactionStatusCancelled  TransactionStatus = "CANCELLED"
@@ -45,10 +48,13 @@ type TransactionResponse struct {
 	UpdatedAt     time.Time         `json:"updated_at"`
 	BookingID     uuid.UUID         `json:"booking_id"`
 	Amount        int               `json:"amount"`
+	AmountPaid    int               `json:"amount_paid"`
+	WalletAmount  int               `json:"wallet_amount"`
 	Currency      Currency          `json:"currency"`
 	PaymentMethod PaymentMethod     `json:"payment_method"`
 	OrderCode     int64             `json:"order_code,omitempty"`
 	Status        TransactionStatus `json:"status"`
 	CheckoutURL   string            `json:"checkout_url,omitempty"`
 	QRCode        string            `json:"qr_code,omitempty"`
+	InvoiceBuyer  *InvoiceBuyer     `json:"invoice_buyer,omitempty"`
 }
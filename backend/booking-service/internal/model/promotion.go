@@ -0,0 +1,157 @@
+package model
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ErrPromotionUnavailable is returned when a promotion ran out of uses or was switched off before it was redeemed
+var ErrPromotionUnavailable = errors.New("promotion is no longer available")
+
+type PromotionKind string
+
+const (
+	// PromotionKindCode is a shared promo code, limited by its usage caps
+	PromotionKindCode PromotionKind = "CODE"
+	// PromotionKindVoucher is a single-use code, optionally issued to one user
+	PromotionKindVoucher PromotionKind = "VOUCHER"
+)
+
+type PromotionDiscountType string
+
+const (
+	// PromotionDiscountPercent takes DiscountValue percent off the fare, up to MaxDiscount
+	PromotionDiscountPercent PromotionDiscountType = "PERCENT"
+	// PromotionDiscountFixed takes DiscountValue VND off the fare
+	PromotionDiscountFixed PromotionDiscountType = "FIXED"
+)
+
+type PromotionRedemptionStatus string
+
+const (
+	PromotionRedemptionStatusActive   PromotionRedemptionStatus = "ACTIVE"
+	PromotionRedemptionStatusReleased PromotionRedemptionStatus = "RELEASED"
+)
+
+// Promotion is a promo code or voucher that discounts a booking. Nil restrictions and limits do not apply.
+type Promotion struct {
+	BaseModel
+	Code          string                `json:"code" gorm:"type:varchar(50);not null;uniqueIndex"`
+	Kind          PromotionKind         `json:"kind" gorm:"type:varchar(20);not null;default:'CODE'"`
+	Description   string                `json:"description,omitempty" gorm:"type:text"`
+	DiscountType  PromotionDiscountType `json:"discount_type" gorm:"type:varchar(20);not null"`
+	DiscountValue int                   `json:"discount_value" gorm:"not null"`
+	MaxDiscount   *int                  `json:"max_discount,omitempty"`
+	MinSpend      int                   `json:"min_spend" gorm:"not null;default:0"`
+
+	// Where and when the promotion applies
+	RouteID       *uuid.UUID `json:"route_id,omitempty" gorm:"type:uuid"`
+	TripID        *uuid.UUID `json:"trip_id,omitempty" gorm:"type:uuid"`
+	StartsAt      *time.Time `json:"starts_at,omitempty" gorm:"type:timestamptz"`
+	EndsAt        *time.Time `json:"ends_at,omitempty" gorm:"type:timestamptz"`
+	DepartureFrom *time.Time `json:"departure_from,omitempty" gorm:"type:timestamptz"`
+	DepartureTo   *time.Time `json:"departure_to,omitempty" gorm:"type:timestamptz"`
+
+	// Usage caps; UsedCount counts the bookings currently holding the promotion
+	UsageLimit     *int       `json:"usage_limit,omitempty"`
+	PerUserLimit   *int       `json:"per_user_limit,omitempty"`
+	UsedCount      int        `json:"used_count" gorm:"not null;default:0"`
+	AssignedUserID *uuid.UUID `json:"assigned_user_id,omitempty" gorm:"type:uuid"`
+
+	IsActive bool `json:"is_active" gorm:"not null;default:true"`
+}
+
+func (Promotion) TableName() string {
+	return "promotions"
+}
+
+func (p *Promotion) BeforeCreate(tx *gorm.DB) error {
+	if p.ID == uuid.Nil {
+		p.ID = uuid.New()
+	}
+	return nil
+}
+
+// DiscountFor returns the discount the promotion gives on a fare, never more than the fare
+func (p *Promotion) DiscountFor(amount int) int {
+	discount := p.DiscountValue
+	if p.DiscountType == PromotionDiscountPercent {
+		discount = amount * p.DiscountValue / 100
+		if p.MaxDiscount != nil && discount > *p.MaxDiscount {
+			discount = *p.MaxDiscount
+		}
+	}
+	if discount > amount {
+		discount = amount
+	}
+	return discount
+}
+
+// PromotionRedemption records a booking holding one use of a promotion. Releasing it gives the use back.
+type PromotionRedemption struct {
+	ID             uuid.UUID                 `json:"id" gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
+	PromotionID    uuid.UUID                 `json:"promotion_id" gorm:"type:uuid;not null;index"`
+	BookingID      uuid.UUID                 `json:"booking_id" gorm:"type:uuid;not null;index"`
+	UserID         uuid.UUID                 `json:"user_id" gorm:"type:uuid;not null"`
+	DiscountAmount int                       `json:"discount_amount" gorm:"not null"`
+	Status         PromotionRedemptionStatus `json:"status" gorm:"type:varchar(20);not null;default:'ACTIVE'"`
+	ReleasedAt     *time.Time                `json:"released_at,omitempty" gorm:"type:timestamptz"`
+	CreatedAt      time.Time                 `json:"created_at" gorm:"autoCreateTime"`
+}
+
+func (PromotionRedemption) TableName() string {
+	return "promotion_redemptions"
+}
+
+func (r *PromotionRedemption) BeforeCreate(tx *gorm.DB) error {
+	if r.ID == uuid.Nil {
+		r.ID = uuid.New()
+	}
+	return nil
+}
+
+// CreatePromotionRequest represents a new promo code or voucher (admin).
+// Codes are required except for vouchers, which get a generated one and are always single-use.
+type CreatePromotionRequest struct {
+	Code          string                `json:"code" binding:"omitempty,min=3,max=50,alphanum"`
+	Kind          PromotionKind         `json:"kind" binding:"omitempty,oneof=CODE VOUCHER"`
+	Description   string                `json:"description" binding:"omitempty,max=500"`
+	DiscountType  PromotionDiscountType `json:"discount_type" binding:"required,oneof=PERCENT FIXED"`
+	DiscountValue int                   `json:"discount_value" binding:"required,min=1"`
+	MaxDiscount   *int                  `json:"max_discount" binding:"omitempty,min=1"`
+	MinSpend      int                   `json:"min_spend" binding:"omitempty,min=0"`
+
+	RouteID       *uuid.UUID `json:"route_id"`
+	TripID        *uuid.UUID `json:"trip_id"`
+	StartsAt      *time.Time `json:"starts_at"`
+	EndsAt        *time.Time `json:"ends_at"`
+	DepartureFrom *time.Time `json:"departure_from"`
+	DepartureTo   *time.Time `json:"departure_to"`
+
+	UsageLimit     *int       `json:"usage_limit" binding:"omitempty,min=1"`
+	PerUserLimit   *int       `json:"per_user_limit" binding:"omitempty,min=1"`
+	AssignedUserID *uuid.UUID `json:"assigned_user_id"`
+}
+
+// UpdatePromotionRequest changes a promotion (admin); omitted fields are kept
+type UpdatePromotionRequest struct {
+	Description  *string    `json:"description" binding:"omitempty,max=500"`
+	MaxDiscount  *int       `json:"max_discount" binding:"omitempty,min=1"`
+	MinSpend     *int       `json:"min_spend" binding:"omitempty,min=0"`
+	StartsAt     *time.Time `json:"starts_at"`
+	EndsAt       *time.Time `json:"ends_at"`
+	UsageLimit   *int       `json:"usage_limit" binding:"omitempty,min=1"`
+	PerUserLimit *int       `json:"per_user_limit" binding:"omitempty,min=1"`
+	IsActive     *bool      `json:"is_active"`
+}
+
+// ListPromotionsRequest represents query parameters for listing promotions (admin)
+type ListPromotionsRequest struct {
+	PaginationRequest
+	Kind     *PromotionKind `form:"kind" binding:"omitempty,oneof=CODE VOUCHER"`
+	IsActive *bool          `form:"is_active"`
+	Search   string         `form:"search"`
+}
@@ -13,6 +13,29 @@ type CreateBookingRequest struct {
 	TripID  uuid.UUID   `json:"trip_id" binding:"required"`
 	SeatIDs []uuid.UUID `json:"seat_ids" binding:"required,min=1,max=10,dive"`
 	Notes   string      `json:"notes,omitempty"`
+
+	// How the booking is paid, PAYOS when empty. CASH is paid at the counter and confirmed by an admin.
+	PaymentMethod payment.PaymentMethod `json:"payment_method,omitempty" binding:"omitempty,oneof=PAYOS CASH SANDBOX"`
+
+	// Seat-lock session that holds the seats during checkout; the hold becomes the booking.
+	// Without it the seats are held just for the booking and must not be held by anyone else.
+	SessionID string `json:"session_id,omitempty"`
+
+	// Optional segment between intermediate route stops; both must be given together
+	PickupStopID  *uuid.UUID `json:"pickup_stop_id,omitempty"`
+	DropoffStopID *uuid.UUID `json:"dropoff_stop_id,omitempty"`
+
+	// Optional named passenger per seat; each entry must reference one of SeatIDs
+	Passengers []PassengerInfo `json:"passengers,omitempty" binding:"omitempty,max=10,dive"`
+
+	// Optional promo code or voucher taken off the fare
+	PromoCode string `json:"promo_code,omitempty" binding:"omitempty,max=50"`
+
+	// Optional buyer details, with a tax code to get a VAT invoice for the booking
+	BuyerInfo *BuyerInfo `json:"buyer_info,omitempty"`
+
+	// Pay from the wallet balance first; the payment method only covers what the wallet cannot
+	UseWallet bool `json:"use_wallet,omitempty"`
 }
 
 // CreateGuestBookingRequest represents guest booking creation (without authentication)
@@ -28,6 +51,12 @@ type CancelBookingRequest struct {
 	Reason string `json:"reason" binding:"required"`
 }
 
+// NotifyTripCancelledRequest represents the request to tell a passenger their trip was cancelled
+type NotifyTripCancelledRequest struct {
+	Reason       string `json:"reason" binding:"required"`
+	RefundAmount int    `json:"refund_amount" binding:"omitempty,min=0"`
+}
+
 // InitSeatsRequest represents request to initialize seats for a trip
 type InitSeatsRequest struct {
 	Seats []SeatInitData `json:"seats" binding:"required,min=1,dive"`
@@ -62,13 +91,22 @@ type BookingResponse struct {
 	TripID            uuid.UUID                 `json:"trip_id"`
 	UserID            uuid.UUID                 `json:"user_id"`
 	TotalAmount       int                       `json:"total_amount"`
+	PromoCode         string                    `json:"promo_code,omitempty"`
+	DiscountAmount    int                       `json:"discount_amount,omitempty"`
 	Status            BookingStatus             `json:"status"`
 	TransactionStatus payment.TransactionStatus `json:"transaction_status"`
 	TransactionID     uuid.UUID                 `json:"transaction_id,omitempty"`
+	PaymentMethod     payment.PaymentMethod     `json:"payment_method"`
 	Notes             string                    `json:"notes,omitempty"`
+	PickupStopID      *uuid.UUID                `json:"pickup_stop_id,omitempty"`
+	DropoffStopID     *uuid.UUID                `json:"dropoff_stop_id,omitempty"`
+	PickupLocation    string                    `json:"pickup_location,omitempty"`
+	DropoffLocation   string                    `json:"dropoff_location,omitempty"`
 	ExpiresAt         *time.Time                `json:"expires_at,omitempty"`
 	ConfirmedAt       *time.Time                `json:"confirmed_at,omitempty"`
 	CancelledAt       *time.Time                `json:"cancelled_at,omitempty"`
+	ItineraryID       *uuid.UUID                `json:"itinerary_id,omitempty"`
+	LegNumber         int                       `json:"leg_number,omitempty"`
 
 	// Seats info
 	Seats       []BookingSeatResponse        `json:"seats"`
@@ -88,13 +126,20 @@ type TripBasicInfo struct {
 
 // BookingSeatResponse represents booking seat in response
 type BookingSeatResponse struct {
-	ID              uuid.UUID `json:"id"`
-	SeatID          uuid.UUID `json:"seat_id"`
-	SeatNumber      string    `json:"seat_number"`
-	SeatType        string    `json:"seat_type"`
-	Floor           int       `json:"floor"`
-	Price           float64   `json:"price"`
-	PriceMultiplier float64   `json:"price_multiplier"`
+	ID              uuid.UUID  `json:"id"`
+	SeatID          uuid.UUID  `json:"seat_id"`
+	SeatNumber      string     `json:"seat_number"`
+	SeatType        string     `json:"seat_type"`
+	Floor           int        `json:"floor"`
+	Price           float64    `json:"price"`
+	PriceMultiplier float64    `json:"price_multiplier"`
+	IsBoarded       bool       `json:"is_boarded"`
+	BoardedAt       *time.Time `json:"boarded_at,omitempty"`
+
+	PassengerName        string               `json:"passenger_name,omitempty"`
+	PassengerPhone       string               `json:"passenger_phone,omitempty"`
+	PassengerIDNumber    string               `json:"passenger_id_number,omitempty"`
+	PassengerAgeCategory PassengerAgeCategory `json:"passenger_age_category,omitempty"`
 }
 
 // PaymentResponse represents payment response
@@ -157,12 +202,17 @@ type UnlockSeatsRequest struct {
 type LockSeatsResponse struct {
 	SessionID string    `json:"session_id"`
 	ExpiresAt time.Time `json:"expires_at"`
+	Fare      *float64  `json:"fare,omitempty"` // trip fare locked in for bookings made with this session
 	Message   string    `json:"message"`
 }
 
 // GetSeatStatusRequest represents request to check seat status for a trip
 type GetSeatStatusRequest struct {
 	SeatIDs []string `form:"seat_ids"`
+
+	// Optional segment; seats booked only on non-overlapping segments are reported as free
+	FromStopOrder *int `form:"from_stop_order" binding:"omitempty,min=0"`
+	ToStopOrder   *int `form:"to_stop_order" binding:"omitempty,min=0"`
 }
 
 // SeatStatusItem represents booking status of a single seat
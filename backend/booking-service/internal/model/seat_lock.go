@@ -1,11 +1,15 @@
 package model
 
 import (
+	"errors"
 	"time"
 
 	"github.com/google/uuid"
 )
 
+// ErrSeatsUnavailable is returned when a seat hold cannot take every requested seat
+var ErrSeatsUnavailable = errors.New("one or more seats are not available")
+
 type SeatLock struct {
 	BaseModel
 	TripID    uuid.UUID `json:"trip_id" gorm:"type:uuid;not null"`
@@ -13,6 +17,15 @@ type SeatLock struct {
 	SessionID string    `json:"session_id" gorm:"type:varchar(255);not null"`
 	LockedAt  time.Time `json:"locked_at" gorm:"type:timestamptz"`
 	ExpiresAt time.Time `json:"expires_at" gorm:"type:timestamptz;not null"`
+
+	// Trip fare quoted when the seat was held, which a booking from the hold is charged
+	QuotedFare *float64 `json:"quoted_fare,omitempty" gorm:"type:decimal(10,2)"`
 }
 
 func (SeatLock) TableName() string { return "seat_locks" }
+
+// SeatHold is a session's hold on seats: until when it lasts and the trip fare it locked in
+type SeatHold struct {
+	ExpiresAt time.Time
+	Fare      *float64 // nil when no fare could be quoted; bookings are then charged the fare at booking time
+}
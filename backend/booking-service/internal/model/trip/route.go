@@ -1,6 +1,7 @@
 package trip
 
 import (
+	"math"
 	"time"
 
 	"github.com/google/uuid"
@@ -17,3 +18,31 @@ type Route struct {
 	CreatedAt        time.Time   `json:"created_at"`
 	UpdatedAt        time.Time   `json:"updated_at"`
 }
+
+func (r *Route) FindStop(id uuid.UUID) *RouteStop {
+	for i := range r.RouteStops {
+		if r.RouteStops[i].ID == id {
+			return &r.RouteStops[i]
+		}
+	}
+	return nil
+}
+
+// DistanceFraction returns the share of the route distance travelled between two stops.
+// Falls back to the full route when stop distances are not configured.
+func (r *Route) DistanceFraction(pickup, dropoff *RouteStop) float64 {
+	distance := dropoff.DistanceKm - pickup.DistanceKm
+	if r.DistanceKm <= 0 || distance <= 0 {
+		return 1
+	}
+	return math.Min(distance/r.DistanceKm, 1)
+}
+
+// SegmentBasePrice prorates the trip base price by distance, rounded to the nearest 1,000 VND.
+// Must stay in sync with trip service search results.
+func SegmentBasePrice(basePrice, fraction float64) float64 {
+	if fraction >= 1 {
+		return basePrice
+	}
+	return math.Round(basePrice*fraction/1000) * 1000
+}
@@ -12,6 +12,7 @@ type RouteStop struct {
 	Latitude      float64   `json:"latitude"`
 	Longitude     float64   `json:"longitude"`
 	OffsetMinutes int       `json:"offset_minutes"`
+	DistanceKm    float64   `json:"distance_km"`
 	IsActive      bool      `json:"is_active"`
 }
 
@@ -22,3 +23,11 @@ const (
 	StopTypeDropoff StopType = "dropoff"
 	StopTypeBoth    StopType = "both"
 )
+
+func (rs *RouteStop) CanPickup() bool {
+	return rs.IsActive && (rs.StopType == StopTypePickup || rs.StopType == StopTypeBoth)
+}
+
+func (rs *RouteStop) CanDropoff() bool {
+	return rs.IsActive && (rs.StopType == StopTypeDropoff || rs.StopType == StopTypeBoth)
+}
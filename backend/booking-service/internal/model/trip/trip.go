@@ -36,6 +36,15 @@ func (t *Trip) IsBookable() bool {
 	return t.IsActive && t.Status == "scheduled"
 }
 
+// TripFare is the fare trip service currently quotes for a trip. It replaces the static
+// base price; seat multipliers and segment proration apply on top.
+type TripFare struct {
+	TripID    uuid.UUID `json:"trip_id"`
+	BasePrice float64   `json:"base_price"`
+	Fare      float64   `json:"fare"`
+	QuotedAt  time.Time `json:"quoted_at"`
+}
+
 type GetTripByIDRequest struct {
 	SeatBookingStatus bool `form:"seat_booking_status" json:"seat_booking_status"`
 	PreLoadRoute      bool `form:"preload_route" json:"preload_route"`
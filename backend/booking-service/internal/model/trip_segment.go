@@ -0,0 +1,25 @@
+package model
+
+import "math"
+
+// TripSegment is the half-open range of route stop orders [FromStopOrder, ToStopOrder)
+// a booking holds its seats for. The same seat can be sold again on segments that do
+// not overlap it.
+type TripSegment struct {
+	FromStopOrder int `json:"from_stop_order"`
+	ToStopOrder   int `json:"to_stop_order"`
+}
+
+// FullTripSegment covers every stop of the trip
+func FullTripSegment() TripSegment {
+	return TripSegment{
+		FromStopOrder: 0,
+		ToStopOrder:   math.MaxInt32,
+	}
+}
+
+// Overlaps reports whether both segments share part of the journey.
+// Touching segments (one ends where the other starts) do not overlap.
+func (s TripSegment) Overlaps(other TripSegment) bool {
+	return s.FromStopOrder < other.ToStopOrder && other.FromStopOrder < s.ToStopOrder
+}
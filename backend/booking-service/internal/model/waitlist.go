@@ -0,0 +1,79 @@
+package model
+
+import (
+	"errors"
+	"time"
+
+	"bus-booking/booking-service/internal/model/payment"
+
+	"github.com/google/uuid"
+)
+
+// ErrWaitlistEntryChanged is returned when a waitlist entry left the status it was acted on in, e.g. it was cancelled meanwhile
+var ErrWaitlistEntryChanged = errors.New("waitlist entry changed meanwhile")
+
+type WaitlistStatus string
+
+const (
+	// WaitlistStatusWaiting is in line for seats of the trip
+	WaitlistStatusWaiting WaitlistStatus = "WAITING"
+	// WaitlistStatusOffered has seats held for it until OfferExpiresAt
+	WaitlistStatusOffered WaitlistStatus = "OFFERED"
+	// WaitlistStatusClaimed booked the seats it was offered
+	WaitlistStatusClaimed WaitlistStatus = "CLAIMED"
+	// WaitlistStatusExpired let its offer lapse, or the trip left without seats for it
+	WaitlistStatusExpired WaitlistStatus = "EXPIRED"
+	// WaitlistStatusCancelled left the waitlist
+	WaitlistStatusCancelled WaitlistStatus = "CANCELLED"
+)
+
+// WaitlistEntry is a user waiting for seats of a sold-out trip. Released seats are offered to
+// entries in the order they joined; an offer holds the seats under OfferSessionID for a while.
+type WaitlistEntry struct {
+	BaseModel
+	TripID    uuid.UUID      `json:"trip_id" gorm:"type:uuid;not null;index"`
+	UserID    uuid.UUID      `json:"user_id" gorm:"type:uuid;not null;index"`
+	SeatCount int            `json:"seat_count" gorm:"not null"`
+	SeatType  string         `json:"seat_type,omitempty" gorm:"type:varchar(20)"`
+	Status    WaitlistStatus `json:"status" gorm:"type:varchar(20);not null;default:'WAITING'"`
+
+	OfferSessionID string     `json:"-" gorm:"type:varchar(255)"`
+	OfferedAt      *time.Time `json:"offered_at,omitempty" gorm:"type:timestamptz"`
+	OfferExpiresAt *time.Time `json:"offer_expires_at,omitempty" gorm:"type:timestamptz"`
+	BookingID      *uuid.UUID `json:"booking_id,omitempty" gorm:"type:uuid"`
+}
+
+func (WaitlistEntry) TableName() string {
+	return "waitlist_entries"
+}
+
+// OfferLapsed reports whether the seats offered are no longer held for the entry
+func (e *WaitlistEntry) OfferLapsed(now time.Time) bool {
+	return e.Status == WaitlistStatusOffered && e.OfferExpiresAt != nil && !now.Before(*e.OfferExpiresAt)
+}
+
+// JoinWaitlistRequest puts the user in line for seats of a trip
+type JoinWaitlistRequest struct {
+	TripID    uuid.UUID `json:"trip_id" binding:"required"`
+	SeatCount int       `json:"seat_count" binding:"required,min=1,max=10"`
+	// Optional seat type; without it any seat will do
+	SeatType string `json:"seat_type,omitempty" binding:"omitempty,oneof=standard vip sleeper"`
+}
+
+// ClaimWaitlistOfferRequest books the seats held by an offer, like CreateBookingRequest without the seat choice
+type ClaimWaitlistOfferRequest struct {
+	Notes         string                `json:"notes,omitempty"`
+	PaymentMethod payment.PaymentMethod `json:"payment_method,omitempty" binding:"omitempty,oneof=PAYOS CASH SANDBOX"`
+	Passengers    []PassengerInfo       `json:"passengers,omitempty" binding:"omitempty,max=10,dive"`
+	PromoCode     string                `json:"promo_code,omitempty" binding:"omitempty,max=50"`
+	BuyerInfo     *BuyerInfo            `json:"buyer_info,omitempty"`
+	UseWallet     bool                  `json:"use_wallet,omitempty"`
+}
+
+type WaitlistEntryResponse struct {
+	*WaitlistEntry
+	// Position in line, 1 is next; only set while waiting
+	Position int `json:"position,omitempty"`
+	// Seats held for the entry while its offer stands
+	OfferedSeatIDs []uuid.UUID `json:"offered_seat_ids,omitempty"`
+}
@@ -0,0 +1,207 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"bus-booking/booking-service/internal/constants"
+	"bus-booking/booking-service/internal/model"
+	"bus-booking/shared/outbox"
+)
+
+type BookingExchangeRepository interface {
+	CreateExchange(ctx context.Context, exchange *model.BookingExchange) error
+	GetExchangeByID(ctx context.Context, id uuid.UUID) (*model.BookingExchange, error)
+	GetExchangeByTransactionID(ctx context.Context, transactionID uuid.UUID) (*model.BookingExchange, error)
+	GetPendingExchangeByBookingID(ctx context.Context, bookingID uuid.UUID) (*model.BookingExchange, error)
+	UpdateExchangeStatus(ctx context.Context, id uuid.UUID, status model.ExchangeStatus) error
+	UpdateExchangeStatusWithEvents(ctx context.Context, id uuid.UUID, status model.ExchangeStatus, events ...*outbox.Event) error
+	// CompleteExchange moves the booking onto the exchange seats, saving the outbox events with it.
+	// It returns model.ErrSeatsUnavailable when the seats were taken and model.ErrExchangeNotPending
+	// when the exchange was already settled.
+	CompleteExchange(ctx context.Context, exchange *model.BookingExchange, segment model.TripSegment, events ...*outbox.Event) error
+}
+
+type bookingExchangeRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewBookingExchangeRepository(db *gorm.DB) BookingExchangeRepository {
+	return &bookingExchangeRepositoryImpl{db: db}
+}
+
+func (r *bookingExchangeRepositoryImpl) CreateExchange(ctx context.Context, exchange *model.BookingExchange) error {
+	if err := r.db.WithContext(ctx).Create(exchange).Error; err != nil {
+		return fmt.Errorf("failed to create booking exchange: %w", err)
+	}
+	return nil
+}
+
+func (r *bookingExchangeRepositoryImpl) GetExchangeByID(ctx context.Context, id uuid.UUID) (*model.BookingExchange, error) {
+	var exchange model.BookingExchange
+	if err := r.db.WithContext(ctx).
+		Preload("Seats").
+		First(&exchange, "id = ?", id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("booking exchange not found")
+		}
+		return nil, fmt.Errorf("failed to get booking exchange: %w", err)
+	}
+	return &exchange, nil
+}
+
+func (r *bookingExchangeRepositoryImpl) GetExchangeByTransactionID(ctx context.Context, transactionID uuid.UUID) (*model.BookingExchange, error) {
+	var exchange model.BookingExchange
+	if err := r.db.WithContext(ctx).
+		Preload("Seats").
+		First(&exchange, "transaction_id = ?", transactionID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("booking exchange not found")
+		}
+		return nil, fmt.Errorf("failed to get booking exchange: %w", err)
+	}
+	return &exchange, nil
+}
+
+// GetPendingExchangeByBookingID returns the pending exchange of a booking that has not expired yet
+func (r *bookingExchangeRepositoryImpl) GetPendingExchangeByBookingID(ctx context.Context, bookingID uuid.UUID) (*model.BookingExchange, error) {
+	var exchange model.BookingExchange
+	if err := r.db.WithContext(ctx).
+		Preload("Seats").
+		Where("booking_id = ? AND status = ?", bookingID, model.ExchangeStatusPending).
+		Where("expires_at IS NULL OR expires_at > ?", time.Now().UTC()).
+		Order("created_at DESC").
+		First(&exchange).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("booking exchange not found")
+		}
+		return nil, fmt.Errorf("failed to get pending booking exchange: %w", err)
+	}
+	return &exchange, nil
+}
+
+func (r *bookingExchangeRepositoryImpl) UpdateExchangeStatus(ctx context.Context, id uuid.UUID, status model.ExchangeStatus) error {
+	if err := r.db.WithContext(ctx).
+		Model(&model.BookingExchange{}).
+		Where("id = ?", id).
+		Update("status", status).Error; err != nil {
+		return fmt.Errorf("failed to update booking exchange status: %w", err)
+	}
+	return nil
+}
+
+// UpdateExchangeStatusWithEvents saves the status together with the outbox events it triggers
+func (r *bookingExchangeRepositoryImpl) UpdateExchangeStatusWithEvents(ctx context.Context, id uuid.UUID, status model.ExchangeStatus, events ...*outbox.Event) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&model.BookingExchange{}).
+			Where("id = ?", id).
+			Update("status", status).Error; err != nil {
+			return fmt.Errorf("failed to update booking exchange status: %w", err)
+		}
+		return outbox.Add(tx, events...)
+	})
+}
+
+// CompleteExchange swaps the booking seats for the exchange seats and moves the booking
+// to the new trip in a single transaction
+func (r *bookingExchangeRepositoryImpl) CompleteExchange(ctx context.Context, exchange *model.BookingExchange, segment model.TripSegment, events ...*outbox.Event) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Concurrent payment updates of the exchange wait here and then find it settled
+		var current model.BookingExchange
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("status").
+			First(&current, "id = ?", exchange.ID).Error; err != nil {
+			return fmt.Errorf("failed to lock booking exchange: %w", err)
+		}
+		if current.Status != model.ExchangeStatusPending {
+			return model.ErrExchangeNotPending
+		}
+
+		seatIDs := make([]uuid.UUID, len(exchange.Seats))
+		for i, seat := range exchange.Seats {
+			seatIDs[i] = seat.SeatID
+		}
+
+		// Hold the seats like any other booker, then check nobody booked them meanwhile
+		sessionID := exchange.ID.String()
+		if err := holdSeats(tx, exchange.NewTripID, seatIDs, sessionID, time.Now().Add(constants.SeatLockDuration), nil); err != nil {
+			if errors.Is(err, model.ErrSeatsUnavailable) {
+				return err
+			}
+			return fmt.Errorf("failed to hold seats: %w", err)
+		}
+
+		var bookedCount int64
+		if err := bookedSeatsQuery(tx, exchange.NewTripID, segment).
+			Where("booking_seats.seat_id IN ?", seatIDs).
+			Count(&bookedCount).Error; err != nil {
+			return fmt.Errorf("failed to check booked seats: %w", err)
+		}
+		if bookedCount > 0 {
+			return model.ErrSeatsUnavailable
+		}
+
+		if err := tx.Unscoped().
+			Where("booking_id = ?", exchange.BookingID).
+			Delete(&model.BookingSeat{}).Error; err != nil {
+			return fmt.Errorf("failed to release old booking seats: %w", err)
+		}
+
+		seats := make([]model.BookingSeat, len(exchange.Seats))
+		for i, seat := range exchange.Seats {
+			seats[i] = model.BookingSeat{
+				BookingID:       exchange.BookingID,
+				SeatID:          seat.SeatID,
+				SeatNumber:      seat.SeatNumber,
+				SeatType:        seat.SeatType,
+				Floor:           seat.Floor,
+				Price:           seat.Price,
+				PriceMultiplier: seat.PriceMultiplier,
+			}
+		}
+		if err := tx.Create(&seats).Error; err != nil {
+			return fmt.Errorf("failed to create new booking seats: %w", err)
+		}
+
+		now := time.Now().UTC()
+		if err := tx.Model(&model.Booking{}).
+			Where("id = ?", exchange.BookingID).
+			Updates(map[string]interface{}{
+				"trip_id":      exchange.NewTripID,
+				"total_amount": exchange.NewAmount + exchange.Surcharge,
+				"updated_at":   now,
+			}).Error; err != nil {
+			return fmt.Errorf("failed to move booking to new trip: %w", err)
+		}
+
+		if err := tx.Model(&model.BookingExchange{}).
+			Where("id = ?", exchange.ID).
+			Updates(map[string]interface{}{
+				"status":       model.ExchangeStatusCompleted,
+				"completed_at": &now,
+				"refund_id":    exchange.RefundID,
+			}).Error; err != nil {
+			return fmt.Errorf("failed to complete booking exchange: %w", err)
+		}
+
+		if err := tx.Unscoped().
+			Where("trip_id = ? AND seat_id IN ? AND session_id = ?", exchange.NewTripID, seatIDs, sessionID).
+			Delete(&model.SeatLock{}).Error; err != nil {
+			return fmt.Errorf("failed to release seat hold: %w", err)
+		}
+
+		if err := outbox.Add(tx, events...); err != nil {
+			return err
+		}
+
+		exchange.Status = model.ExchangeStatusCompleted
+		exchange.CompletedAt = &now
+		return nil
+	})
+}
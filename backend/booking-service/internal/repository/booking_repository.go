@@ -2,30 +2,45 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 
+	"bus-booking/booking-service/internal/constants"
 	"bus-booking/booking-service/internal/model"
+	"bus-booking/shared/outbox"
 )
 
+// processedEventConsumer names this service in the processed events table
+const processedEventConsumer = "booking-service"
+
+var errEventAlreadyProcessed = errors.New("event already processed")
+
 type BookingRepository interface {
-	CreateBooking(ctx context.Context, booking *model.Booking) error
-	GetBookedSeatIDs(ctx context.Context, tripID uuid.UUID) ([]uuid.UUID, error)
+	CreateBookingFromHold(ctx context.Context, booking *model.Booking, sessionID string, segment model.TripSegment) error
+	GetBookedSeatIDs(ctx context.Context, tripID uuid.UUID, segment model.TripSegment) ([]uuid.UUID, error)
 
 	GetBookingByID(ctx context.Context, id uuid.UUID) (*model.Booking, error)
 	GetBookingByReference(ctx context.Context, reference string) (*model.Booking, error)
 	GetBookingsByUserID(ctx context.Context, userID uuid.UUID, statuses []model.BookingStatus, limit, offset int) ([]*model.Booking, int64, error)
 	GetBookingsByTripID(ctx context.Context, tripID uuid.UUID, limit, offset int) ([]*model.Booking, int64, error)
+	GetItineraryBookings(ctx context.Context, itineraryID uuid.UUID) ([]*model.Booking, error)
 	GetTripBookings(ctx context.Context, tripID uuid.UUID, page, limit int) ([]*model.Booking, int64, error)
 	ListBookings(ctx context.Context, req model.ListBookingsRequest) ([]*model.Booking, int64, error)
 	UpdateBooking(ctx context.Context, booking *model.Booking) error
+	UpdateBookingForEvent(ctx context.Context, booking *model.Booking, eventID uuid.UUID) error
+	UpdateBookings(ctx context.Context, bookings []*model.Booking, eventID uuid.UUID) error
+	UpdateBookingsWithEvents(ctx context.Context, bookings []*model.Booking, events ...*outbox.Event) error
+	IsEventProcessed(ctx context.Context, eventID uuid.UUID) (bool, error)
+	MarkEventProcessed(ctx context.Context, eventID uuid.UUID) error
 	UpdateStatus(ctx context.Context, id uuid.UUID, status model.BookingStatus) error
 	CancelBooking(ctx context.Context, id uuid.UUID, reason string) error
 	GetAllActiveBookingsByTripID(ctx context.Context, tripID uuid.UUID) ([]*model.Booking, error)
 	CheckInPassenger(ctx context.Context, bookingID uuid.UUID) error
+	CheckInSeats(ctx context.Context, bookingID uuid.UUID, seatIDs []uuid.UUID) error
 }
 
 type bookingRepositoryImpl struct {
@@ -64,21 +79,29 @@ func (r *bookingRepositoryImpl) GetBookingByReference(ctx context.Context, refer
 	return &booking, nil
 }
 
-func (r *bookingRepositoryImpl) GetBookedSeatIDs(ctx context.Context, tripID uuid.UUID) ([]uuid.UUID, error) {
+// GetBookedSeatIDs returns seats held by active bookings whose segment overlaps the given one.
+// Whole-trip bookings have NULL stop orders and overlap every segment.
+func (r *bookingRepositoryImpl) GetBookedSeatIDs(ctx context.Context, tripID uuid.UUID, segment model.TripSegment) ([]uuid.UUID, error) {
 	var seatIDs []uuid.UUID
-	if err := r.db.WithContext(ctx).
-		Model(&model.BookingSeat{}).
+	if err := bookedSeatsQuery(r.db.WithContext(ctx), tripID, segment).
 		Select("booking_seats.seat_id").
-		Joins("JOIN bookings ON bookings.id = booking_seats.booking_id").
-		Where("bookings.trip_id = ? AND bookings.status IN ?", tripID, []model.BookingStatus{
-			model.BookingStatusPending,
-			model.BookingStatusConfirmed}).
 		Scan(&seatIDs).Error; err != nil {
 		return nil, fmt.Errorf("failed to get booked seat IDs: %w", err)
 	}
 	return seatIDs, nil
 }
 
+func bookedSeatsQuery(db *gorm.DB, tripID uuid.UUID, segment model.TripSegment) *gorm.DB {
+	full := model.FullTripSegment()
+	return db.Model(&model.BookingSeat{}).
+		Joins("JOIN bookings ON bookings.id = booking_seats.booking_id").
+		Where("bookings.trip_id = ? AND bookings.status IN ?", tripID, []model.BookingStatus{
+			model.BookingStatusPending,
+			model.BookingStatusConfirmed}).
+		Where("COALESCE(bookings.pickup_stop_order, ?) < ? AND COALESCE(bookings.dropoff_stop_order, ?) > ?",
+			full.FromStopOrder, segment.ToStopOrder, full.ToStopOrder, segment.FromStopOrder)
+}
+
 func (r *bookingRepositoryImpl) GetBookingsByUserID(ctx context.Context, userID uuid.UUID, statuses []model.BookingStatus, limit, offset int) ([]*model.Booking, int64, error) {
 	var bookings []*model.Booking
 	var total int64
@@ -140,6 +163,19 @@ func (r *bookingRepositoryImpl) GetBookingsByTripID(ctx context.Context, tripID
 	return bookings, total, nil
 }
 
+// GetItineraryBookings returns the legs of an itinerary in travel order
+func (r *bookingRepositoryImpl) GetItineraryBookings(ctx context.Context, itineraryID uuid.UUID) ([]*model.Booking, error) {
+	var bookings []*model.Booking
+	if err := r.db.WithContext(ctx).
+		Preload("BookingSeats").
+		Where("itinerary_id = ?", itineraryID).
+		Order("leg_number ASC").
+		Find(&bookings).Error; err != nil {
+		return nil, fmt.Errorf("failed to get itinerary bookings: %w", err)
+	}
+	return bookings, nil
+}
+
 func (r *bookingRepositoryImpl) GetTripBookings(ctx context.Context, tripID uuid.UUID, page, limit int) ([]*model.Booking, int64, error) {
 	offset := (page - 1) * limit
 	return r.GetBookingsByTripID(ctx, tripID, limit, offset)
@@ -199,8 +235,132 @@ func (r *bookingRepositoryImpl) UpdateBooking(ctx context.Context, booking *mode
 	return nil
 }
 
-func (r *bookingRepositoryImpl) CreateBooking(ctx context.Context, booking *model.Booking) error {
-	return r.db.WithContext(ctx).Create(booking).Error
+// UpdateBookingForEvent saves the booking and records the event that changed it in one
+// transaction. A concurrent delivery of the same event leaves the booking untouched.
+func (r *bookingRepositoryImpl) UpdateBookingForEvent(ctx context.Context, booking *model.Booking, eventID uuid.UUID) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		first, err := outbox.MarkProcessed(tx, eventID, processedEventConsumer)
+		if err != nil {
+			return err
+		}
+		if !first {
+			return errEventAlreadyProcessed
+		}
+		if err := tx.Save(booking).Error; err != nil {
+			return fmt.Errorf("failed to update booking: %w", err)
+		}
+		return nil
+	})
+	if errors.Is(err, errEventAlreadyProcessed) {
+		return nil
+	}
+	return err
+}
+
+// UpdateBookings saves bookings settled together, such as the legs of an itinerary, in one
+// transaction. A non-nil eventID is recorded with them like in UpdateBookingForEvent.
+func (r *bookingRepositoryImpl) UpdateBookings(ctx context.Context, bookings []*model.Booking, eventID uuid.UUID) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if eventID != uuid.Nil {
+			first, err := outbox.MarkProcessed(tx, eventID, processedEventConsumer)
+			if err != nil {
+				return err
+			}
+			if !first {
+				return errEventAlreadyProcessed
+			}
+		}
+		for _, booking := range bookings {
+			if err := tx.Save(booking).Error; err != nil {
+				return fmt.Errorf("failed to update booking: %w", err)
+			}
+		}
+		return nil
+	})
+	if errors.Is(err, errEventAlreadyProcessed) {
+		return nil
+	}
+	return err
+}
+
+// UpdateBookingsWithEvents saves bookings together with the outbox events announcing the change,
+// so the events reach other services exactly when the bookings are saved
+func (r *bookingRepositoryImpl) UpdateBookingsWithEvents(ctx context.Context, bookings []*model.Booking, events ...*outbox.Event) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, booking := range bookings {
+			if err := tx.Save(booking).Error; err != nil {
+				return fmt.Errorf("failed to update booking: %w", err)
+			}
+		}
+		return outbox.Add(tx, events...)
+	})
+}
+
+func (r *bookingRepositoryImpl) IsEventProcessed(ctx context.Context, eventID uuid.UUID) (bool, error) {
+	return outbox.IsProcessed(ctx, r.db, eventID)
+}
+
+func (r *bookingRepositoryImpl) MarkEventProcessed(ctx context.Context, eventID uuid.UUID) error {
+	_, err := outbox.MarkProcessed(r.db.WithContext(ctx), eventID, processedEventConsumer)
+	return err
+}
+
+// CreateBookingFromHold saves the booking and converts the session's hold on its seats into
+// booking seats in one transaction. The seats are (re)held for the session first, so a seat
+// held by another session or booked for an overlapping segment fails the whole booking
+// with model.ErrSeatsUnavailable. A booking with a promotion takes one use of it in the same
+// transaction, or fails with model.ErrPromotionUnavailable.
+func (r *bookingRepositoryImpl) CreateBookingFromHold(ctx context.Context, booking *model.Booking, sessionID string, segment model.TripSegment) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return createBookingFromHold(tx, booking, sessionID, segment)
+	})
+}
+
+func createBookingFromHold(tx *gorm.DB, booking *model.Booking, sessionID string, segment model.TripSegment) error {
+	seatIDs := make([]uuid.UUID, len(booking.BookingSeats))
+	for i, seat := range booking.BookingSeats {
+		seatIDs[i] = seat.SeatID
+	}
+
+	if err := holdSeats(tx, booking.TripID, seatIDs, sessionID, time.Now().Add(constants.SeatLockDuration), nil); err != nil {
+		if errors.Is(err, model.ErrSeatsUnavailable) {
+			return err
+		}
+		return fmt.Errorf("failed to hold seats: %w", err)
+	}
+
+	// Every booker holds the seats first, so no other booking of them can commit meanwhile
+	var bookedCount int64
+	if err := bookedSeatsQuery(tx, booking.TripID, segment).
+		Where("booking_seats.seat_id IN ?", seatIDs).
+		Count(&bookedCount).Error; err != nil {
+		return fmt.Errorf("failed to check booked seats: %w", err)
+	}
+	if bookedCount > 0 {
+		return model.ErrSeatsUnavailable
+	}
+
+	if err := tx.Create(booking).Error; err != nil {
+		return fmt.Errorf("failed to create booking: %w", err)
+	}
+
+	if booking.PromotionID != nil {
+		if err := redeemPromotion(tx, &model.PromotionRedemption{
+			PromotionID:    *booking.PromotionID,
+			BookingID:      booking.ID,
+			UserID:         booking.UserID,
+			DiscountAmount: booking.DiscountAmount,
+		}); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Unscoped().
+		Where("trip_id = ? AND seat_id IN ? AND session_id = ?", booking.TripID, seatIDs, sessionID).
+		Delete(&model.SeatLock{}).Error; err != nil {
+		return fmt.Errorf("failed to release seat hold: %w", err)
+	}
+	return nil
 }
 
 func (r *bookingRepositoryImpl) UpdateStatus(ctx context.Context, id uuid.UUID, status model.BookingStatus) error {
@@ -237,10 +397,56 @@ func (r *bookingRepositoryImpl) GetAllActiveBookingsByTripID(ctx context.Context
 	return bookings, nil
 }
 
+// CheckInPassenger boards every seat of the booking that has not boarded yet
 func (r *bookingRepositoryImpl) CheckInPassenger(ctx context.Context, bookingID uuid.UUID) error {
-	return r.db.WithContext(ctx).
-		Model(&model.Booking{}).
-		Where("id = ?", bookingID).
-		Update("is_boarded", true).
-		Error
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now().UTC()
+		if err := tx.Model(&model.BookingSeat{}).
+			Where("booking_id = ? AND is_boarded = ?", bookingID, false).
+			Updates(map[string]interface{}{
+				"is_boarded": true,
+				"boarded_at": now,
+			}).Error; err != nil {
+			return fmt.Errorf("failed to board booking seats: %w", err)
+		}
+
+		if err := tx.Model(&model.Booking{}).
+			Where("id = ?", bookingID).
+			Update("is_boarded", true).Error; err != nil {
+			return fmt.Errorf("failed to check in booking: %w", err)
+		}
+		return nil
+	})
+}
+
+// CheckInSeats boards the given seats of a booking. It fails with model.ErrSeatAlreadyBoarded,
+// without boarding anything, if any of the seats boarded before, including concurrently.
+// The booking is marked boarded once its last seat boards.
+func (r *bookingRepositoryImpl) CheckInSeats(ctx context.Context, bookingID uuid.UUID, seatIDs []uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now().UTC()
+		result := tx.Model(&model.BookingSeat{}).
+			Where("booking_id = ? AND seat_id IN ? AND is_boarded = ?", bookingID, seatIDs, false).
+			Updates(map[string]interface{}{
+				"is_boarded": true,
+				"boarded_at": now,
+			})
+		if result.Error != nil {
+			return fmt.Errorf("failed to board seats: %w", result.Error)
+		}
+		if result.RowsAffected != int64(len(seatIDs)) {
+			return model.ErrSeatAlreadyBoarded
+		}
+
+		if err := tx.Model(&model.Booking{}).
+			Where("id = ?", bookingID).
+			Where(`NOT EXISTS (
+				SELECT 1 FROM booking_seats bs
+				WHERE bs.booking_id = bookings.id AND bs.is_boarded = false AND bs.deleted_at IS NULL
+			)`).
+			Update("is_boarded", true).Error; err != nil {
+			return fmt.Errorf("failed to check in booking: %w", err)
+		}
+		return nil
+	})
 }
@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"bus-booking/booking-service/internal/model"
+)
+
+type ItineraryRepository interface {
+	// CreateFromHolds saves the itinerary with its legs, converting the session's hold on the seats
+	// of every leg into booking seats, all or nothing. It fails with model.ErrSeatsUnavailable when
+	// a seat of any leg is held by someone else or already booked.
+	CreateFromHolds(ctx context.Context, itinerary *model.Itinerary, sessionID string) error
+	// GetByID returns the itinerary with its legs in travel order
+	GetByID(ctx context.Context, id uuid.UUID) (*model.Itinerary, error)
+	// ListByUserID returns the user's itineraries with their legs, newest first
+	ListByUserID(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*model.Itinerary, int64, error)
+}
+
+type itineraryRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewItineraryRepository(db *gorm.DB) ItineraryRepository {
+	return &itineraryRepositoryImpl{db: db}
+}
+
+func (r *itineraryRepositoryImpl) CreateFromHolds(ctx context.Context, itinerary *model.Itinerary, sessionID string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit(clause.Associations).Create(itinerary).Error; err != nil {
+			return fmt.Errorf("failed to create itinerary: %w", err)
+		}
+
+		// Legs are created in travel order, so a later leg sees the seats an earlier one took
+		for i := range itinerary.Bookings {
+			leg := &itinerary.Bookings[i]
+			if err := createBookingFromHold(tx, leg, sessionID, leg.Segment()); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (r *itineraryRepositoryImpl) GetByID(ctx context.Context, id uuid.UUID) (*model.Itinerary, error) {
+	var itinerary model.Itinerary
+	if err := r.withLegs(r.db.WithContext(ctx)).First(&itinerary, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("itinerary not found")
+		}
+		return nil, fmt.Errorf("failed to get itinerary: %w", err)
+	}
+	return &itinerary, nil
+}
+
+func (r *itineraryRepositoryImpl) ListByUserID(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*model.Itinerary, int64, error) {
+	var (
+		itineraries []*model.Itinerary
+		total       int64
+	)
+
+	if err := r.db.WithContext(ctx).
+		Model(&model.Itinerary{}).
+		Where("user_id = ?", userID).
+		Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count itineraries: %w", err)
+	}
+
+	if err := r.withLegs(r.db.WithContext(ctx)).
+		Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&itineraries).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to list itineraries: %w", err)
+	}
+
+	return itineraries, total, nil
+}
+
+func (r *itineraryRepositoryImpl) withLegs(db *gorm.DB) *gorm.DB {
+	return db.
+		Preload("Bookings", func(db *gorm.DB) *gorm.DB {
+			return db.Order("leg_number ASC")
+		}).
+		Preload("Bookings.BookingSeats")
+}
@@ -6,6 +6,7 @@ package mocks
 
 import (
 	model "bus-booking/booking-service/internal/model"
+	outbox "bus-booking/shared/outbox"
 	context "context"
 	reflect "reflect"
 
@@ -64,18 +65,32 @@ func (mr *MockBookingRepositoryMockRecorder) CheckInPassenger(ctx, bookingID int
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckInPassenger", reflect.TypeOf((*MockBookingRepository)(nil).CheckInPassenger), ctx, bookingID)
 }
 
-// CreateBooking mocks base method.
-func (m *MockBookingRepository) CreateBooking(ctx context.Context, booking *model.Booking) error {
+// CheckInSeats mocks base method.
+func (m *MockBookingRepository) CheckInSeats(ctx context.Context, bookingID uuid.UUID, seatIDs []uuid.UUID) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateBooking", ctx, booking)
+	ret := m.ctrl.Call(m, "CheckInSeats", ctx, bookingID, seatIDs)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// CreateBooking indicates an expected call of CreateBooking.
-func (mr *MockBookingRepositoryMockRecorder) CreateBooking(ctx, booking interface{}) *gomock.Call {
+// CheckInSeats indicates an expected call of CheckInSeats.
+func (mr *MockBookingRepositoryMockRecorder) CheckInSeats(ctx, bookingID, seatIDs interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateBooking", reflect.TypeOf((*MockBookingRepository)(nil).CreateBooking), ctx, booking)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckInSeats", reflect.TypeOf((*MockBookingRepository)(nil).CheckInSeats), ctx, bookingID, seatIDs)
+}
+
+// CreateBookingFromHold mocks base method.
+func (m *MockBookingRepository) CreateBookingFromHold(ctx context.Context, booking *model.Booking, sessionID string, segment model.TripSegment) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateBookingFromHold", ctx, booking, sessionID, segment)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateBookingFromHold indicates an expected call of CreateBookingFromHold.
+func (mr *MockBookingRepositoryMockRecorder) CreateBookingFromHold(ctx, booking, sessionID, segment interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateBookingFromHold", reflect.TypeOf((*MockBookingRepository)(nil).CreateBookingFromHold), ctx, booking, sessionID, segment)
 }
 
 // GetAllActiveBookingsByTripID mocks base method.
@@ -94,18 +109,18 @@ func (mr *MockBookingRepositoryMockRecorder) GetAllActiveBookingsByTripID(ctx, t
 }
 
 // GetBookedSeatIDs mocks base method.
-func (m *MockBookingRepository) GetBookedSeatIDs(ctx context.Context, tripID uuid.UUID) ([]uuid.UUID, error) {
+func (m *MockBookingRepository) GetBookedSeatIDs(ctx context.Context, tripID uuid.UUID, segment model.TripSegment) ([]uuid.UUID, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetBookedSeatIDs", ctx, tripID)
+	ret := m.ctrl.Call(m, "GetBookedSeatIDs", ctx, tripID, segment)
 	ret0, _ := ret[0].([]uuid.UUID)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetBookedSeatIDs indicates an expected call of GetBookedSeatIDs.
-func (mr *MockBookingRepositoryMockRecorder) GetBookedSeatIDs(ctx, tripID interface{}) *gomock.Call {
+func (mr *MockBookingRepositoryMockRecorder) GetBookedSeatIDs(ctx, tripID, segment interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBookedSeatIDs", reflect.TypeOf((*MockBookingRepository)(nil).GetBookedSeatIDs), ctx, tripID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBookedSeatIDs", reflect.TypeOf((*MockBookingRepository)(nil).GetBookedSeatIDs), ctx, tripID, segment)
 }
 
 // GetBookingByID mocks base method.
@@ -170,6 +185,21 @@ func (mr *MockBookingRepositoryMockRecorder) GetBookingsByUserID(ctx, userID, st
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBookingsByUserID", reflect.TypeOf((*MockBookingRepository)(nil).GetBookingsByUserID), ctx, userID, statuses, limit, offset)
 }
 
+// GetItineraryBookings mocks base method.
+func (m *MockBookingRepository) GetItineraryBookings(ctx context.Context, itineraryID uuid.UUID) ([]*model.Booking, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetItineraryBookings", ctx, itineraryID)
+	ret0, _ := ret[0].([]*model.Booking)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetItineraryBookings indicates an expected call of GetItineraryBookings.
+func (mr *MockBookingRepositoryMockRecorder) GetItineraryBookings(ctx, itineraryID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetItineraryBookings", reflect.TypeOf((*MockBookingRepository)(nil).GetItineraryBookings), ctx, itineraryID)
+}
+
 // GetTripBookings mocks base method.
 func (m *MockBookingRepository) GetTripBookings(ctx context.Context, tripID uuid.UUID, page, limit int) ([]*model.Booking, int64, error) {
 	m.ctrl.T.Helper()
@@ -186,6 +216,21 @@ func (mr *MockBookingRepositoryMockRecorder) GetTripBookings(ctx, tripID, page,
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTripBookings", reflect.TypeOf((*MockBookingRepository)(nil).GetTripBookings), ctx, tripID, page, limit)
 }
 
+// IsEventProcessed mocks base method.
+func (m *MockBookingRepository) IsEventProcessed(ctx context.Context, eventID uuid.UUID) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsEventProcessed", ctx, eventID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsEventProcessed indicates an expected call of IsEventProcessed.
+func (mr *MockBookingRepositoryMockRecorder) IsEventProcessed(ctx, eventID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsEventProcessed", reflect.TypeOf((*MockBookingRepository)(nil).IsEventProcessed), ctx, eventID)
+}
+
 // ListBookings mocks base method.
 func (m *MockBookingRepository) ListBookings(ctx context.Context, req model.ListBookingsRequest) ([]*model.Booking, int64, error) {
 	m.ctrl.T.Helper()
@@ -202,6 +247,20 @@ func (mr *MockBookingRepositoryMockRecorder) ListBookings(ctx, req interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListBookings", reflect.TypeOf((*MockBookingRepository)(nil).ListBookings), ctx, req)
 }
 
+// MarkEventProcessed mocks base method.
+func (m *MockBookingRepository) MarkEventProcessed(ctx context.Context, eventID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkEventProcessed", ctx, eventID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkEventProcessed indicates an expected call of MarkEventProcessed.
+func (mr *MockBookingRepositoryMockRecorder) MarkEventProcessed(ctx, eventID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkEventProcessed", reflect.TypeOf((*MockBookingRepository)(nil).MarkEventProcessed), ctx, eventID)
+}
+
 // UpdateBooking mocks base method.
 func (m *MockBookingRepository) UpdateBooking(ctx context.Context, booking *model.Booking) error {
 	m.ctrl.T.Helper()
@@ -216,6 +275,53 @@ func (mr *MockBookingRepositoryMockRecorder) UpdateBooking(ctx, booking interfac
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateBooking", reflect.TypeOf((*MockBookingRepository)(nil).UpdateBooking), ctx, booking)
 }
 
+// UpdateBookingForEvent mocks base method.
+func (m *MockBookingRepository) UpdateBookingForEvent(ctx context.Context, booking *model.Booking, eventID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateBookingForEvent", ctx, booking, eventID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateBookingForEvent indicates an expected call of UpdateBookingForEvent.
+func (mr *MockBookingRepositoryMockRecorder) UpdateBookingForEvent(ctx, booking, eventID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateBookingForEvent", reflect.TypeOf((*MockBookingRepository)(nil).UpdateBookingForEvent), ctx, booking, eventID)
+}
+
+// UpdateBookings mocks base method.
+func (m *MockBookingRepository) UpdateBookings(ctx context.Context, bookings []*model.Booking, eventID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateBookings", ctx, bookings, eventID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateBookings indicates an expected call of UpdateBookings.
+func (mr *MockBookingRepositoryMockRecorder) UpdateBookings(ctx, bookings, eventID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateBookings", reflect.TypeOf((*MockBookingRepository)(nil).UpdateBookings), ctx, bookings, eventID)
+}
+
+// UpdateBookingsWithEvents mocks base method.
+func (m *MockBookingRepository) UpdateBookingsWithEvents(ctx context.Context, bookings []*model.Booking, events ...*outbox.Event) error {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, bookings}
+	for _, a := range events {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "UpdateBookingsWithEvents", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateBookingsWithEvents indicates an expected call of UpdateBookingsWithEvents.
+func (mr *MockBookingRepositoryMockRecorder) UpdateBookingsWithEvents(ctx, bookings interface{}, events ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, bookings}, events...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateBookingsWithEvents", reflect.TypeOf((*MockBookingRepository)(nil).UpdateBookingsWithEvents), varargs...)
+}
+
 // UpdateStatus mocks base method.
 func (m *MockBookingRepository) UpdateStatus(ctx context.Context, id uuid.UUID, status model.BookingStatus) error {
 	m.ctrl.T.Helper()
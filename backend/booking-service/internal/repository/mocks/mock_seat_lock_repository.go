@@ -65,6 +65,21 @@ func (mr *MockSeatLockRepositoryMockRecorder) GetLockedSeats(ctx, tripID interfa
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLockedSeats", reflect.TypeOf((*MockSeatLockRepository)(nil).GetLockedSeats), ctx, tripID)
 }
 
+// GetSessionFares mocks base method.
+func (m *MockSeatLockRepository) GetSessionFares(ctx context.Context, tripID uuid.UUID, sessionID string) (map[uuid.UUID]float64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSessionFares", ctx, tripID, sessionID)
+	ret0, _ := ret[0].(map[uuid.UUID]float64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSessionFares indicates an expected call of GetSessionFares.
+func (mr *MockSeatLockRepositoryMockRecorder) GetSessionFares(ctx, tripID, sessionID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSessionFares", reflect.TypeOf((*MockSeatLockRepository)(nil).GetSessionFares), ctx, tripID, sessionID)
+}
+
 // IsLocked mocks base method.
 func (m *MockSeatLockRepository) IsLocked(ctx context.Context, tripID, seatID uuid.UUID) (bool, error) {
 	m.ctrl.T.Helper()
@@ -81,17 +96,17 @@ func (mr *MockSeatLockRepositoryMockRecorder) IsLocked(ctx, tripID, seatID inter
 }
 
 // LockSeats mocks base method.
-func (m *MockSeatLockRepository) LockSeats(ctx context.Context, tripID uuid.UUID, seatIDs []uuid.UUID, sessionID string, duration time.Duration) error {
+func (m *MockSeatLockRepository) LockSeats(ctx context.Context, tripID uuid.UUID, seatIDs []uuid.UUID, sessionID string, duration time.Duration, fare *float64) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "LockSeats", ctx, tripID, seatIDs, sessionID, duration)
+	ret := m.ctrl.Call(m, "LockSeats", ctx, tripID, seatIDs, sessionID, duration, fare)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // LockSeats indicates an expected call of LockSeats.
-func (mr *MockSeatLockRepositoryMockRecorder) LockSeats(ctx, tripID, seatIDs, sessionID, duration interface{}) *gomock.Call {
+func (mr *MockSeatLockRepositoryMockRecorder) LockSeats(ctx, tripID, seatIDs, sessionID, duration, fare interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockSeats", reflect.TypeOf((*MockSeatLockRepository)(nil).LockSeats), ctx, tripID, seatIDs, sessionID, duration)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockSeats", reflect.TypeOf((*MockSeatLockRepository)(nil).LockSeats), ctx, tripID, seatIDs, sessionID, duration, fare)
 }
 
 // UnlockSeats mocks base method.
@@ -0,0 +1,203 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"bus-booking/booking-service/internal/model"
+)
+
+type PromotionRepository interface {
+	CreatePromotion(ctx context.Context, promotion *model.Promotion) error
+	GetPromotionByID(ctx context.Context, id uuid.UUID) (*model.Promotion, error)
+	GetPromotionByCode(ctx context.Context, code string) (*model.Promotion, error)
+	ListPromotions(ctx context.Context, req *model.ListPromotionsRequest) ([]*model.Promotion, int64, error)
+	UpdatePromotion(ctx context.Context, promotion *model.Promotion) error
+	// CountActiveRedemptions counts the bookings of the user currently holding a use of the promotion
+	CountActiveRedemptions(ctx context.Context, promotionID, userID uuid.UUID) (int64, error)
+	// Redeem takes one use of the promotion for a booking, or returns model.ErrPromotionUnavailable.
+	// A booking that already holds its use is left as is.
+	Redeem(ctx context.Context, redemption *model.PromotionRedemption) error
+	// Release gives back the use held by a booking; it reports false when the booking held none
+	Release(ctx context.Context, bookingID uuid.UUID) (bool, error)
+}
+
+type promotionRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewPromotionRepository(db *gorm.DB) PromotionRepository {
+	return &promotionRepositoryImpl{db: db}
+}
+
+func (r *promotionRepositoryImpl) CreatePromotion(ctx context.Context, promotion *model.Promotion) error {
+	if err := r.db.WithContext(ctx).Create(promotion).Error; err != nil {
+		return fmt.Errorf("failed to create promotion: %w", err)
+	}
+	return nil
+}
+
+func (r *promotionRepositoryImpl) GetPromotionByID(ctx context.Context, id uuid.UUID) (*model.Promotion, error) {
+	var promotion model.Promotion
+	if err := r.db.WithContext(ctx).First(&promotion, "id = ?", id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("promotion not found")
+		}
+		return nil, fmt.Errorf("failed to get promotion: %w", err)
+	}
+	return &promotion, nil
+}
+
+func (r *promotionRepositoryImpl) GetPromotionByCode(ctx context.Context, code string) (*model.Promotion, error) {
+	var promotion model.Promotion
+	if err := r.db.WithContext(ctx).First(&promotion, "code = ?", code).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("promotion not found")
+		}
+		return nil, fmt.Errorf("failed to get promotion by code: %w", err)
+	}
+	return &promotion, nil
+}
+
+func (r *promotionRepositoryImpl) ListPromotions(ctx context.Context, req *model.ListPromotionsRequest) ([]*model.Promotion, int64, error) {
+	query := r.db.WithContext(ctx).Model(&model.Promotion{})
+	if req.Kind != nil {
+		query = query.Where("kind = ?", *req.Kind)
+	}
+	if req.IsActive != nil {
+		query = query.Where("is_active = ?", *req.IsActive)
+	}
+	if search := strings.TrimSpace(req.Search); search != "" {
+		query = query.Where("code ILIKE ? OR description ILIKE ?", "%"+search+"%", "%"+search+"%")
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count promotions: %w", err)
+	}
+
+	var promotions []*model.Promotion
+	offset := (req.Page - 1) * req.PageSize
+	if err := query.
+		Order("created_at DESC").
+		Offset(offset).
+		Limit(req.PageSize).
+		Find(&promotions).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to list promotions: %w", err)
+	}
+	return promotions, total, nil
+}
+
+// UpdatePromotion saves the editable fields; used_count is only changed by redemptions
+func (r *promotionRepositoryImpl) UpdatePromotion(ctx context.Context, promotion *model.Promotion) error {
+	if err := r.db.WithContext(ctx).
+		Model(promotion).
+		Select("description", "max_discount", "min_spend", "starts_at", "ends_at", "usage_limit", "per_user_limit", "is_active").
+		Updates(promotion).Error; err != nil {
+		return fmt.Errorf("failed to update promotion: %w", err)
+	}
+	return nil
+}
+
+func (r *promotionRepositoryImpl) CountActiveRedemptions(ctx context.Context, promotionID, userID uuid.UUID) (int64, error) {
+	var count int64
+	if err := r.db.WithContext(ctx).
+		Model(&model.PromotionRedemption{}).
+		Where("promotion_id = ? AND user_id = ? AND status = ?", promotionID, userID, model.PromotionRedemptionStatusActive).
+		Count(&count).Error; err != nil {
+		return 0, fmt.Errorf("failed to count promotion redemptions: %w", err)
+	}
+	return count, nil
+}
+
+func (r *promotionRepositoryImpl) Redeem(ctx context.Context, redemption *model.PromotionRedemption) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return redeemPromotion(tx, redemption)
+	})
+}
+
+func (r *promotionRepositoryImpl) Release(ctx context.Context, bookingID uuid.UUID) (bool, error) {
+	released := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var redemption model.PromotionRedemption
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("booking_id = ? AND status = ?", bookingID, model.PromotionRedemptionStatusActive).
+			First(&redemption).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil
+			}
+			return fmt.Errorf("failed to get promotion redemption: %w", err)
+		}
+
+		now := time.Now().UTC()
+		if err := tx.Model(&redemption).Updates(map[string]interface{}{
+			"status":      model.PromotionRedemptionStatusReleased,
+			"released_at": &now,
+		}).Error; err != nil {
+			return fmt.Errorf("failed to release promotion redemption: %w", err)
+		}
+
+		if err := tx.Model(&model.Promotion{}).
+			Where("id = ? AND used_count > 0", redemption.PromotionID).
+			UpdateColumn("used_count", gorm.Expr("used_count - 1")).Error; err != nil {
+			return fmt.Errorf("failed to give back promotion use: %w", err)
+		}
+		released = true
+		return nil
+	})
+	return released, err
+}
+
+// redeemPromotion takes one use of the promotion inside tx. Incrementing used_count locks the
+// promotion row until tx ends, so concurrent redemptions queue up and the global and per-user
+// caps cannot be overrun. A promotion that is off or used up returns model.ErrPromotionUnavailable.
+func redeemPromotion(tx *gorm.DB, redemption *model.PromotionRedemption) error {
+	var held int64
+	if err := tx.Model(&model.PromotionRedemption{}).
+		Where("booking_id = ? AND status = ?", redemption.BookingID, model.PromotionRedemptionStatusActive).
+		Count(&held).Error; err != nil {
+		return fmt.Errorf("failed to check promotion redemption: %w", err)
+	}
+	if held > 0 {
+		return nil
+	}
+
+	result := tx.Model(&model.Promotion{}).
+		Where("id = ? AND is_active", redemption.PromotionID).
+		Where("usage_limit IS NULL OR used_count < usage_limit").
+		UpdateColumn("used_count", gorm.Expr("used_count + 1"))
+	if result.Error != nil {
+		return fmt.Errorf("failed to take promotion use: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return model.ErrPromotionUnavailable
+	}
+
+	var promotion model.Promotion
+	if err := tx.Select("per_user_limit").First(&promotion, "id = ?", redemption.PromotionID).Error; err != nil {
+		return fmt.Errorf("failed to get promotion: %w", err)
+	}
+	if promotion.PerUserLimit != nil {
+		var used int64
+		if err := tx.Model(&model.PromotionRedemption{}).
+			Where("promotion_id = ? AND user_id = ? AND status = ?", redemption.PromotionID, redemption.UserID, model.PromotionRedemptionStatusActive).
+			Count(&used).Error; err != nil {
+			return fmt.Errorf("failed to count promotion redemptions: %w", err)
+		}
+		if used >= int64(*promotion.PerUserLimit) {
+			return model.ErrPromotionUnavailable
+		}
+	}
+
+	if err := tx.Create(redemption).Error; err != nil {
+		return fmt.Errorf("failed to create promotion redemption: %w", err)
+	}
+	return nil
+}
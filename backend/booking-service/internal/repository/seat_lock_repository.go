@@ -8,10 +8,12 @@ import (
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type SeatLockRepository interface {
-	LockSeats(ctx context.Context, tripID uuid.UUID, seatIDs []uuid.UUID, sessionID string, duration time.Duration) error
+	LockSeats(ctx context.Context, tripID uuid.UUID, seatIDs []uuid.UUID, sessionID string, duration time.Duration, fare *float64) error
+	GetSessionFares(ctx context.Context, tripID uuid.UUID, sessionID string) (map[uuid.UUID]float64, error)
 	UnlockSeats(ctx context.Context, sessionID string) error
 	UnlockSpecificSeats(ctx context.Context, tripID uuid.UUID, seatIDs []uuid.UUID) error
 	GetLockedSeats(ctx context.Context, tripID uuid.UUID) ([]uuid.UUID, error)
@@ -27,31 +29,43 @@ func NewSeatLockRepository(db *gorm.DB) SeatLockRepository {
 	return &SeatLockRepositoryImpl{db: db}
 }
 
-func (r *SeatLockRepositoryImpl) LockSeats(ctx context.Context, tripID uuid.UUID, seatIDs []uuid.UUID, sessionID string, duration time.Duration) error {
-	expiresAt := time.Now().Add(duration)
+// LockSeats holds every given seat for the session, or none of them when any seat
+// is held by another session. It fails with model.ErrSeatsUnavailable in that case.
+// Locking seats the session already holds extends the hold at the newly quoted fare.
+func (r *SeatLockRepositoryImpl) LockSeats(ctx context.Context, tripID uuid.UUID, seatIDs []uuid.UUID, sessionID string, duration time.Duration, fare *float64) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return holdSeats(tx, tripID, seatIDs, sessionID, time.Now().Add(duration), fare)
+	})
+}
 
-	locks := make([]model.SeatLock, len(seatIDs))
-	for i, seatID := range seatIDs {
-		locks[i] = model.SeatLock{
-			TripID:    tripID,
-			SeatID:    seatID,
-			SessionID: sessionID,
-			LockedAt:  time.Now(),
-			ExpiresAt: expiresAt,
-		}
+// GetSessionFares returns the fare each seat the session still holds on the trip was quoted at.
+// Seats held without a quoted fare are left out.
+func (r *SeatLockRepositoryImpl) GetSessionFares(ctx context.Context, tripID uuid.UUID, sessionID string) (map[uuid.UUID]float64, error) {
+	var locks []model.SeatLock
+	if err := r.db.WithContext(ctx).
+		Where("trip_id = ? AND session_id = ? AND expires_at > ?", tripID, sessionID, time.Now()).
+		Where("quoted_fare IS NOT NULL").
+		Find(&locks).Error; err != nil {
+		return nil, err
 	}
 
-	return r.db.WithContext(ctx).Create(&locks).Error
+	fares := make(map[uuid.UUID]float64, len(locks))
+	for _, lock := range locks {
+		fares[lock.SeatID] = *lock.QuotedFare
+	}
+	return fares, nil
 }
 
 func (r *SeatLockRepositoryImpl) UnlockSeats(ctx context.Context, sessionID string) error {
 	return r.db.WithContext(ctx).
+		Unscoped().
 		Where("session_id = ?", sessionID).
 		Delete(&model.SeatLock{}).Error
 }
 
 func (r *SeatLockRepositoryImpl) UnlockSpecificSeats(ctx context.Context, tripID uuid.UUID, seatIDs []uuid.UUID) error {
 	return r.db.WithContext(ctx).
+		Unscoped().
 		Where("trip_id = ? AND seat_id IN ?", tripID, seatIDs).
 		Delete(&model.SeatLock{}).Error
 }
@@ -81,6 +95,7 @@ func (r *SeatLockRepositoryImpl) IsLocked(ctx context.Context, tripID, seatID uu
 
 func (r *SeatLockRepositoryImpl) CleanExpiredLocks(ctx context.Context) error {
 	err := r.db.WithContext(ctx).
+		Unscoped().
 		Where("expires_at < ?", time.Now()).
 		Delete(&model.SeatLock{}).Error
 
@@ -91,3 +106,53 @@ func (r *SeatLockRepositoryImpl) CleanExpiredLocks(ctx context.Context) error {
 
 	return err
 }
+
+// holdSeats takes an all-or-nothing hold on the seats inside tx. Expired holds and the
+// session's own holds on the seats are replaced first; a seat still held by another
+// session makes the unique (trip_id, seat_id) index skip its row, which is reported as
+// model.ErrSeatsUnavailable so the caller's transaction rolls back every seat.
+// The fare, when given, is recorded as the fare the seats were quoted at.
+func holdSeats(tx *gorm.DB, tripID uuid.UUID, seatIDs []uuid.UUID, sessionID string, expiresAt time.Time, fare *float64) error {
+	now := time.Now()
+	seatIDs = uniqueSeatIDs(seatIDs)
+
+	if err := tx.Unscoped().
+		Where("trip_id = ? AND seat_id IN ?", tripID, seatIDs).
+		Where("(expires_at <= ? OR session_id = ?)", now, sessionID).
+		Delete(&model.SeatLock{}).Error; err != nil {
+		return err
+	}
+
+	locks := make([]model.SeatLock, len(seatIDs))
+	for i, seatID := range seatIDs {
+		locks[i] = model.SeatLock{
+			TripID:     tripID,
+			SeatID:     seatID,
+			SessionID:  sessionID,
+			LockedAt:   now,
+			ExpiresAt:  expiresAt,
+			QuotedFare: fare,
+		}
+	}
+
+	result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&locks)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected != int64(len(locks)) {
+		return model.ErrSeatsUnavailable
+	}
+	return nil
+}
+
+func uniqueSeatIDs(seatIDs []uuid.UUID) []uuid.UUID {
+	seen := make(map[uuid.UUID]bool, len(seatIDs))
+	unique := make([]uuid.UUID, 0, len(seatIDs))
+	for _, seatID := range seatIDs {
+		if !seen[seatID] {
+			seen[seatID] = true
+			unique = append(unique, seatID)
+		}
+	}
+	return unique
+}
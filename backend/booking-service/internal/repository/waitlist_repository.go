@@ -0,0 +1,191 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"bus-booking/booking-service/internal/model"
+)
+
+// activeWaitlistStatuses are the statuses of entries still in line for seats
+var activeWaitlistStatuses = []model.WaitlistStatus{model.WaitlistStatusWaiting, model.WaitlistStatusOffered}
+
+type WaitlistRepository interface {
+	CreateEntry(ctx context.Context, entry *model.WaitlistEntry) error
+	GetEntryByID(ctx context.Context, id uuid.UUID) (*model.WaitlistEntry, error)
+	// HasActiveEntry reports whether the user is already waiting for, or has an offer on, the trip
+	HasActiveEntry(ctx context.Context, tripID, userID uuid.UUID) (bool, error)
+	ListUserEntries(ctx context.Context, userID uuid.UUID) ([]*model.WaitlistEntry, error)
+	// ListActiveEntries returns the waiting and offered entries of the trip in the order they joined
+	ListActiveEntries(ctx context.Context, tripID uuid.UUID) ([]*model.WaitlistEntry, error)
+	// CountWaitingAhead counts the entries of the trip that joined before the entry and still wait
+	CountWaitingAhead(ctx context.Context, entry *model.WaitlistEntry) (int64, error)
+	// GetOfferedSeatIDs returns the seats still held by the entry's offer
+	GetOfferedSeatIDs(ctx context.Context, entry *model.WaitlistEntry) ([]uuid.UUID, error)
+
+	// Offer holds the seats under the entry's offer session until its offer expires and marks it offered,
+	// both or neither. It fails with model.ErrSeatsUnavailable when a seat is held by someone else and
+	// with model.ErrWaitlistEntryChanged when the entry no longer waits.
+	Offer(ctx context.Context, entry *model.WaitlistEntry, seatIDs []uuid.UUID) error
+	// Close takes a waiting or offered entry out of line with the given status, releasing the seats
+	// of its offer. It reports false when the entry had already left the line.
+	Close(ctx context.Context, entry *model.WaitlistEntry, status model.WaitlistStatus) (bool, error)
+	// MarkClaimed records the booking made from an offer; it reports false when the entry holds no offer
+	MarkClaimed(ctx context.Context, id, bookingID uuid.UUID) (bool, error)
+}
+
+type waitlistRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewWaitlistRepository(db *gorm.DB) WaitlistRepository {
+	return &waitlistRepositoryImpl{db: db}
+}
+
+func (r *waitlistRepositoryImpl) CreateEntry(ctx context.Context, entry *model.WaitlistEntry) error {
+	if err := r.db.WithContext(ctx).Create(entry).Error; err != nil {
+		return fmt.Errorf("failed to create waitlist entry: %w", err)
+	}
+	return nil
+}
+
+func (r *waitlistRepositoryImpl) GetEntryByID(ctx context.Context, id uuid.UUID) (*model.WaitlistEntry, error) {
+	var entry model.WaitlistEntry
+	if err := r.db.WithContext(ctx).First(&entry, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("waitlist entry not found")
+		}
+		return nil, fmt.Errorf("failed to get waitlist entry: %w", err)
+	}
+	return &entry, nil
+}
+
+func (r *waitlistRepositoryImpl) HasActiveEntry(ctx context.Context, tripID, userID uuid.UUID) (bool, error) {
+	var count int64
+	if err := r.db.WithContext(ctx).
+		Model(&model.WaitlistEntry{}).
+		Where("trip_id = ? AND user_id = ? AND status IN ?", tripID, userID, activeWaitlistStatuses).
+		Count(&count).Error; err != nil {
+		return false, fmt.Errorf("failed to check waitlist entry: %w", err)
+	}
+	return count > 0, nil
+}
+
+func (r *waitlistRepositoryImpl) ListUserEntries(ctx context.Context, userID uuid.UUID) ([]*model.WaitlistEntry, error) {
+	var entries []*model.WaitlistEntry
+	if err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Find(&entries).Error; err != nil {
+		return nil, fmt.Errorf("failed to list waitlist entries: %w", err)
+	}
+	return entries, nil
+}
+
+func (r *waitlistRepositoryImpl) ListActiveEntries(ctx context.Context, tripID uuid.UUID) ([]*model.WaitlistEntry, error) {
+	var entries []*model.WaitlistEntry
+	if err := r.db.WithContext(ctx).
+		Where("trip_id = ? AND status IN ?", tripID, activeWaitlistStatuses).
+		Order("created_at ASC").
+		Find(&entries).Error; err != nil {
+		return nil, fmt.Errorf("failed to list waitlist entries of trip: %w", err)
+	}
+	return entries, nil
+}
+
+func (r *waitlistRepositoryImpl) CountWaitingAhead(ctx context.Context, entry *model.WaitlistEntry) (int64, error) {
+	var count int64
+	if err := r.db.WithContext(ctx).
+		Model(&model.WaitlistEntry{}).
+		Where("trip_id = ? AND status = ? AND created_at < ?", entry.TripID, model.WaitlistStatusWaiting, entry.CreatedAt).
+		Count(&count).Error; err != nil {
+		return 0, fmt.Errorf("failed to count waitlist entries ahead: %w", err)
+	}
+	return count, nil
+}
+
+func (r *waitlistRepositoryImpl) GetOfferedSeatIDs(ctx context.Context, entry *model.WaitlistEntry) ([]uuid.UUID, error) {
+	var seatIDs []uuid.UUID
+	if entry.OfferSessionID == "" {
+		return seatIDs, nil
+	}
+	if err := r.db.WithContext(ctx).
+		Model(&model.SeatLock{}).
+		Where("trip_id = ? AND session_id = ? AND expires_at > ?", entry.TripID, entry.OfferSessionID, time.Now()).
+		Pluck("seat_id", &seatIDs).Error; err != nil {
+		return nil, fmt.Errorf("failed to get offered seats: %w", err)
+	}
+	return seatIDs, nil
+}
+
+func (r *waitlistRepositoryImpl) Offer(ctx context.Context, entry *model.WaitlistEntry, seatIDs []uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&model.WaitlistEntry{}).
+			Where("id = ? AND status = ?", entry.ID, model.WaitlistStatusWaiting).
+			Updates(map[string]interface{}{
+				"status":           model.WaitlistStatusOffered,
+				"offer_session_id": entry.OfferSessionID,
+				"offered_at":       entry.OfferedAt,
+				"offer_expires_at": entry.OfferExpiresAt,
+			})
+		if result.Error != nil {
+			return fmt.Errorf("failed to mark waitlist entry offered: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return model.ErrWaitlistEntryChanged
+		}
+
+		if err := holdSeats(tx, entry.TripID, seatIDs, entry.OfferSessionID, *entry.OfferExpiresAt, nil); err != nil {
+			if errors.Is(err, model.ErrSeatsUnavailable) {
+				return err
+			}
+			return fmt.Errorf("failed to hold offered seats: %w", err)
+		}
+		return nil
+	})
+}
+
+func (r *waitlistRepositoryImpl) Close(ctx context.Context, entry *model.WaitlistEntry, status model.WaitlistStatus) (bool, error) {
+	closed := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&model.WaitlistEntry{}).
+			Where("id = ? AND status IN ?", entry.ID, activeWaitlistStatuses).
+			Update("status", status)
+		if result.Error != nil {
+			return fmt.Errorf("failed to close waitlist entry: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+
+		if entry.OfferSessionID != "" {
+			if err := tx.Unscoped().
+				Where("session_id = ?", entry.OfferSessionID).
+				Delete(&model.SeatLock{}).Error; err != nil {
+				return fmt.Errorf("failed to release offered seats: %w", err)
+			}
+		}
+		closed = true
+		return nil
+	})
+	return closed, err
+}
+
+func (r *waitlistRepositoryImpl) MarkClaimed(ctx context.Context, id, bookingID uuid.UUID) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&model.WaitlistEntry{}).
+		Where("id = ? AND status = ?", id, model.WaitlistStatusOffered).
+		Updates(map[string]interface{}{
+			"status":     model.WaitlistStatusClaimed,
+			"booking_id": bookingID,
+		})
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to mark waitlist entry claimed: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
@@ -20,6 +20,12 @@ type Handlers struct {
 	StatisticsHandler handler.StatisticsHandler
 	SeatLockHandler   handler.SeatLockHandler
 	ReviewHandler     handler.ReviewHandler
+	DeadLetterHandler handler.DeadLetterHandler
+	WaitlistHandler   handler.WaitlistHandler
+	ItineraryHandler  handler.ItineraryHandler
+
+	CancellationPolicyHandler handler.CancellationPolicyHandler
+	PromotionHandler          handler.PromotionHandler
 }
 
 func SetupRoutes(router *gin.Engine, cfg *config.Config, h *Handlers) {
@@ -47,6 +53,8 @@ func SetupRoutes(router *gin.Engine, cfg *config.Config, h *Handlers) {
 			})
 		}
 
+		v1.GET("/cancellation-policy", ginext.WrapHandler(h.CancellationPolicyHandler.GetPolicy))
+
 		seatLocks := v1.Group("/seat-locks")
 		{
 			seatLocks.POST("", ginext.WrapHandler(h.SeatLockHandler.LockSeats))
@@ -68,7 +76,9 @@ func SetupRoutes(router *gin.Engine, cfg *config.Config, h *Handlers) {
 		{
 			bookings.POST("", ginext.WrapHandler(h.BookingHandler.CreateBooking))
 			bookings.POST("/:id/cancel", ginext.WrapHandler(h.BookingHandler.CancelBooking))
+			bookings.GET("/:id/refund-quote", ginext.WrapHandler(h.BookingHandler.GetRefundQuote))
 			bookings.POST("/:id/retry-payment", ginext.WrapHandler(h.BookingHandler.RetryPayment))
+			bookings.POST("/:id/exchange", ginext.WrapHandler(h.BookingHandler.ExchangeBooking))
 			bookings.GET("/user/:user_id", ginext.WrapHandler(h.BookingHandler.GetUserBookings))
 			bookings.POST("/:id/review", ginext.WrapHandler(h.ReviewHandler.CreateReview))
 			bookings.GET("/:id/review", ginext.WrapHandler(h.ReviewHandler.GetReviewByBooking))
@@ -80,6 +90,22 @@ func SetupRoutes(router *gin.Engine, cfg *config.Config, h *Handlers) {
 			reviews.DELETE("/:id", ginext.WrapHandler(h.ReviewHandler.DeleteReview))
 		}
 
+		waitlist := userV1.Group("/waitlist")
+		{
+			waitlist.POST("", ginext.WrapHandler(h.WaitlistHandler.JoinWaitlist))
+			waitlist.GET("", ginext.WrapHandler(h.WaitlistHandler.ListMyEntries))
+			waitlist.DELETE("/:id", ginext.WrapHandler(h.WaitlistHandler.LeaveWaitlist))
+			waitlist.POST("/:id/claim", ginext.WrapHandler(h.WaitlistHandler.ClaimOffer))
+		}
+
+		itineraries := userV1.Group("/itineraries")
+		{
+			itineraries.POST("", ginext.WrapHandler(h.ItineraryHandler.CreateItinerary))
+			itineraries.GET("", ginext.WrapHandler(h.ItineraryHandler.ListMyItineraries))
+			itineraries.GET("/:id", ginext.WrapHandler(h.ItineraryHandler.GetItinerary))
+			itineraries.POST("/:id/cancel", ginext.WrapHandler(h.ItineraryHandler.CancelItinerary))
+		}
+
 		users := userV1.Group("/users")
 		{
 			users.GET("/:user_id/reviews", ginext.WrapHandler(h.ReviewHandler.GetUserReviews))
@@ -94,10 +120,16 @@ func SetupRoutes(router *gin.Engine, cfg *config.Config, h *Handlers) {
 		{
 			bookings.GET("/trip/:trip_id", ginext.WrapHandler(h.BookingHandler.GetTripBookings))
 			bookings.GET("/trip/:trip_id/passengers", ginext.WrapHandler(h.BookingHandler.GetTripPassengers))
+			bookings.GET("/trip/:trip_id/manifest", ginext.WrapHandler(h.BookingHandler.GetTripManifest))
 			bookings.GET("", ginext.WrapHandler(h.BookingHandler.ListBookings))
 			bookings.POST("/:id/check-in", ginext.WrapHandler(h.BookingHandler.CheckInPassenger))
 		}
 
+		trips := adminV1.Group("/trips")
+		{
+			trips.POST("/:trip_id/check-in", ginext.WrapHandler(h.BookingHandler.CheckInTicket))
+		}
+
 		statistics := adminV1.Group("/statistics")
 		{
 			statistics.GET("/bookings", ginext.WrapHandler(h.StatisticsHandler.GetBookingStats))
@@ -108,6 +140,20 @@ func SetupRoutes(router *gin.Engine, cfg *config.Config, h *Handlers) {
 		{
 			reviews.PUT("/:id/moderate", ginext.WrapHandler(h.ReviewHandler.ModerateReview))
 		}
+
+		promotions := adminV1.Group("/promotions")
+		{
+			promotions.POST("", ginext.WrapHandler(h.PromotionHandler.CreatePromotion))
+			promotions.GET("", ginext.WrapHandler(h.PromotionHandler.ListPromotions))
+			promotions.GET("/:id", ginext.WrapHandler(h.PromotionHandler.GetPromotion))
+			promotions.PUT("/:id", ginext.WrapHandler(h.PromotionHandler.UpdatePromotion))
+		}
+
+		deadLetters := adminV1.Group("/dead-letters")
+		{
+			deadLetters.GET("", ginext.WrapHandler(h.DeadLetterHandler.ListDeadLetters))
+			deadLetters.POST("/:queue/:id/replay", ginext.WrapHandler(h.DeadLetterHandler.ReplayDeadLetter))
+		}
 	}
 
 	internalV1 := router.Group("/api/v1")
@@ -115,7 +161,10 @@ func SetupRoutes(router *gin.Engine, cfg *config.Config, h *Handlers) {
 		bookings := internalV1.Group("/bookings")
 		{
 			bookings.PUT("/:id/status", ginext.WrapHandler(h.BookingHandler.UpdateBookingStatus))
+			bookings.POST("/:id/trip-cancelled", ginext.WrapHandler(h.BookingHandler.CancelForTrip))
+			bookings.POST("/:id/notify-trip-cancelled", ginext.WrapHandler(h.BookingHandler.NotifyTripCancelled))
 			bookings.GET("/trips/:trip_id/seats/status", ginext.WrapHandler(h.BookingHandler.GetSeatStatus))
+			bookings.GET("/trips/:trip_id/active", ginext.WrapHandler(h.BookingHandler.GetActiveTripBookings))
 		}
 	}
 }
@@ -4,19 +4,26 @@ import (
 	"bus-booking/booking-service/internal/client"
 	"bus-booking/booking-service/internal/handler"
 	"bus-booking/booking-service/internal/jobs"
+	"bus-booking/booking-service/internal/model"
 	"bus-booking/booking-service/internal/repository"
 	"bus-booking/booking-service/internal/router"
 	"bus-booking/booking-service/internal/service"
+	"bus-booking/shared/outbox"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
 )
 
-func (s *Server) buildHandler() (http.Handler, *jobs.BookingExpirationJob, *jobs.TripReminderJob) {
+func (s *Server) buildHandler() (http.Handler, *jobs.BookingExpirationJob, *jobs.TripReminderJob, *jobs.WaitlistOfferJob, *outbox.Relay) {
 	// Initialize repositories
 	bookingRepo := repository.NewBookingRepository(s.db.DB)
 	bookingStatsRepo := repository.NewBookingStatsRepository(s.db.DB)
 	reviewRepo := repository.NewReviewRepository(s.db.DB)
+	exchangeRepo := repository.NewBookingExchangeRepository(s.db.DB)
+	promotionRepo := repository.NewPromotionRepository(s.db.DB)
+	waitlistRepo := repository.NewWaitlistRepository(s.db.DB)
+	itineraryRepo := repository.NewItineraryRepository(s.db.DB)
 
 	// Initialize HTTP clients for other services
 	tripClient := client.NewTripClient(s.cfg.ServiceName, s.cfg.External.TripServiceURL)
@@ -26,22 +33,49 @@ func (s *Server) buildHandler() (http.Handler, *jobs.BookingExpirationJob, *jobs
 
 	// Initialize services
 	seatLockRepo := repository.NewSeatLockRepository(s.db.DB)
-	seatLockService := service.NewSeatLockService(seatLockRepo)
+	seatLockService := service.NewSeatLockService(seatLockRepo, tripClient)
 
-	bookingService := service.NewBookingService(bookingRepo, paymentClient, tripClient, userClient, notificationClient, s.delayedQueue, seatLockService)
+	refundTiers, err := model.ParseRefundTiers(s.cfg.Cancellation.RefundTiers)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Invalid cancellation refund tiers")
+	}
+	cancellationPolicyService := service.NewCancellationPolicyService(refundTiers)
+
+	roundTripDiscounts, err := model.ParseRoundTripDiscounts(s.cfg.Itinerary.RoundTripDiscounts)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Invalid round-trip discounts")
+	}
+
+	exchangeService := service.NewBookingExchangeService(bookingRepo, exchangeRepo, paymentClient, tripClient, seatLockService)
+	promotionService := service.NewPromotionService(promotionRepo)
+	bookingService := service.NewBookingService(bookingRepo, paymentClient, tripClient, userClient, notificationClient, s.delayedQueue, seatLockService, cancellationPolicyService, exchangeService, promotionService)
 	statisticsService := service.NewStatisticsService(bookingStatsRepo)
-	eTicketService := service.NewETicketService(bookingRepo, tripClient)
+	eTicketService := service.NewETicketService(bookingRepo, tripClient, s.cfg.ETicket.QRSecret)
 	reviewService := service.NewReviewService(reviewRepo, bookingRepo)
+	deadLetterService := service.NewDeadLetterService(s.delayedQueue)
+	waitlistService := service.NewWaitlistService(waitlistRepo, bookingRepo, seatLockRepo, bookingService, tripClient, userClient, notificationClient, s.delayedQueue)
+	itineraryService := service.NewItineraryService(itineraryRepo, bookingRepo, bookingService, paymentClient, tripClient, seatLockService, userClient, notificationClient, s.delayedQueue, roundTripDiscounts)
 
 	// Initialize Jobs
 	bookingExpirationJob := jobs.NewBookingExpirationJob(bookingService, seatLockRepo, s.delayedQueue)
 	tripReminderJob := jobs.NewTripReminderJob(bookingRepo, s.delayedQueue, notificationClient, tripClient, userClient)
+	waitlistOfferJob := jobs.NewWaitlistOfferJob(waitlistService, s.delayedQueue)
+
+	// Initialize outbox relay
+	relay := outbox.NewRelay(s.db.DB, outbox.DefaultRelayConfig())
+	relay.Register(model.EventTypePaymentCancelDue, bookingService.CancelExpiredPayment)
+	relay.Register(model.EventTypeExchangeRefundDue, exchangeService.RefundExchangeDifference)
 
 	// Initialize handlers
-	bookingHandler := handler.NewBookingHandler(bookingService, eTicketService)
+	bookingHandler := handler.NewBookingHandler(bookingService, eTicketService, exchangeService)
 	statisticsHandler := handler.NewStatisticsHandler(statisticsService)
 	seatLockHandler := handler.NewSeatLockHandler(seatLockService)
 	reviewHandler := handler.NewReviewHandler(reviewService)
+	deadLetterHandler := handler.NewDeadLetterHandler(deadLetterService)
+	cancellationPolicyHandler := handler.NewCancellationPolicyHandler(cancellationPolicyService)
+	promotionHandler := handler.NewPromotionHandler(promotionService)
+	waitlistHandler := handler.NewWaitlistHandler(waitlistService)
+	itineraryHandler := handler.NewItineraryHandler(itineraryService)
 
 	if s.cfg.Server.IsProduction {
 		gin.SetMode(gin.ReleaseMode)
@@ -55,6 +89,12 @@ func (s *Server) buildHandler() (http.Handler, *jobs.BookingExpirationJob, *jobs
 		StatisticsHandler: statisticsHandler,
 		SeatLockHandler:   seatLockHandler,
 		ReviewHandler:     reviewHandler,
+		DeadLetterHandler: deadLetterHandler,
+		WaitlistHandler:   waitlistHandler,
+		ItineraryHandler:  itineraryHandler,
+
+		CancellationPolicyHandler: cancellationPolicyHandler,
+		PromotionHandler:          promotionHandler,
 	})
-	return engine, bookingExpirationJob, tripReminderJob
+	return engine, bookingExpirationJob, tripReminderJob, waitlistOfferJob, relay
 }
@@ -37,13 +37,15 @@ func NewServer(
 }
 
 func (s *Server) Run() {
-	handler, expirationJob, tripReminderJob := s.buildHandler()
+	handler, expirationJob, tripReminderJob, waitlistOfferJob, relay := s.buildHandler()
 
 	// Start background jobs
 	ctx, cancelJob := context.WithCancel(context.Background())
 	defer cancelJob()
 	go expirationJob.Start(ctx)
 	go tripReminderJob.Start(ctx)
+	go waitlistOfferJob.Start(ctx)
+	go relay.Start(ctx)
 
 	server := &http.Server{
 		Addr:           s.cfg.GetServerAddr(),
@@ -0,0 +1,499 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"bus-booking/booking-service/internal/client"
+	"bus-booking/booking-service/internal/constants"
+	"bus-booking/booking-service/internal/model"
+	"bus-booking/booking-service/internal/model/payment"
+	"bus-booking/booking-service/internal/model/trip"
+	"bus-booking/booking-service/internal/repository"
+	"bus-booking/shared/ginext"
+	"bus-booking/shared/outbox"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+	"golang.org/x/sync/errgroup"
+)
+
+type BookingExchangeService interface {
+	ExchangeBooking(ctx context.Context, bookingID uuid.UUID, req *model.ExchangeBookingRequest, userID uuid.UUID) (*model.BookingExchangeResponse, error)
+	// HandleExchangePayment applies a payment status update to the exchange owning the transaction.
+	// It reports false when the transaction does not belong to any exchange.
+	HandleExchangePayment(ctx context.Context, transactionID uuid.UUID, status payment.TransactionStatus) (bool, error)
+	RefundExchangeDifference(ctx context.Context, event *outbox.Event) error
+}
+
+type bookingExchangeServiceImpl struct {
+	bookingRepo     repository.BookingRepository
+	exchangeRepo    repository.BookingExchangeRepository
+	paymentClient   client.PaymentClient
+	tripClient      client.TripClient
+	seatLockService SeatLockService
+}
+
+func NewBookingExchangeService(
+	bookingRepo repository.BookingRepository,
+	exchangeRepo repository.BookingExchangeRepository,
+	paymentClient client.PaymentClient,
+	tripClient client.TripClient,
+	seatLockService SeatLockService,
+) BookingExchangeService {
+	return &bookingExchangeServiceImpl{
+		bookingRepo:     bookingRepo,
+		exchangeRepo:    exchangeRepo,
+		paymentClient:   paymentClient,
+		tripClient:      tripClient,
+		seatLockService: seatLockService,
+	}
+}
+
+func (s *bookingExchangeServiceImpl) ExchangeBooking(ctx context.Context, bookingID uuid.UUID, req *model.ExchangeBookingRequest, userID uuid.UUID) (*model.BookingExchangeResponse, error) {
+	// 1. Validate booking
+	booking, err := s.bookingRepo.GetBookingByID(ctx, bookingID)
+	if err != nil {
+		return nil, ginext.NewNotFoundError("booking not found")
+	}
+
+	if booking.UserID != userID {
+		return nil, ginext.NewForbiddenError("you don't own this booking")
+	}
+
+	if booking.Status != model.BookingStatusConfirmed {
+		return nil, ginext.NewBadRequestError("only confirmed bookings can be exchanged")
+	}
+
+	if req.TripID == booking.TripID {
+		return nil, ginext.NewBadRequestError("new trip must be different from the current trip")
+	}
+
+	if len(req.SeatIDs) != len(booking.BookingSeats) {
+		return nil, ginext.NewBadRequestError("number of seats must match the original booking")
+	}
+
+	if pending, err := s.exchangeRepo.GetPendingExchangeByBookingID(ctx, bookingID); err == nil && pending != nil {
+		return nil, ginext.NewConflictError("booking already has a pending exchange")
+	}
+
+	// 2. Fetch both trips and the new seats concurrently
+	var (
+		oldTrip *trip.Trip
+		newTrip *trip.Trip
+		fares   map[uuid.UUID]float64
+		seats   []trip.Seat
+	)
+
+	g, gCtx := errgroup.WithContext(ctx)
+
+	g.Go(func() error {
+		var err error
+		oldTrip, err = s.tripClient.GetTripByID(gCtx, trip.GetTripByIDRequest{}, booking.TripID)
+		if err != nil {
+			return fmt.Errorf("failed to get current trip: %w", err)
+		}
+		return nil
+	})
+
+	// Segment bookings keep their stops on the new trip, so its route stops are needed for repricing
+	newTripReq := trip.GetTripByIDRequest{}
+	if booking.PickupStopID != nil {
+		newTripReq.PreLoadRoute = true
+		newTripReq.PreLoadRouteStop = true
+	}
+
+	g.Go(func() error {
+		var err error
+		newTrip, err = s.tripClient.GetTripByID(gCtx, newTripReq, req.TripID)
+		if err != nil {
+			return fmt.Errorf("failed to get new trip: %w", err)
+		}
+		return nil
+	})
+
+	// The new seats are repriced at the current fare of the new trip
+	g.Go(func() error {
+		var err error
+		fares, err = s.seatLockService.QuoteSeatFares(gCtx, req.TripID, "", req.SeatIDs)
+		return err
+	})
+
+	g.Go(func() error {
+		var err error
+		seats, err = s.tripClient.ListSeatsByIDs(gCtx, req.SeatIDs)
+		if err != nil {
+			return fmt.Errorf("failed to list seats: %w", err)
+		}
+		return nil
+	})
+
+	if err := g.Wait(); err != nil {
+		return nil, ginext.NewInternalServerError(err.Error())
+	}
+
+	// 3. Validate the new trip and seats
+	now := time.Now().UTC()
+
+	if newTrip.RouteID != oldTrip.RouteID {
+		return nil, ginext.NewBadRequestError("new trip must be on the same route")
+	}
+
+	if !newTrip.IsBookable() || !newTrip.DepartureTime.After(now) {
+		return nil, ginext.NewBadRequestError("new trip is not available for booking")
+	}
+
+	if !oldTrip.DepartureTime.After(now) {
+		return nil, ginext.NewBadRequestError("cannot exchange booking after departure")
+	}
+
+	if len(seats) != len(req.SeatIDs) {
+		return nil, ginext.NewBadRequestError("one or more selected seats do not exist")
+	}
+	for _, seat := range seats {
+		if seat.BusID != newTrip.BusID {
+			return nil, ginext.NewBadRequestError("selected seats do not belong to the new trip")
+		}
+	}
+
+	fraction := 1.0
+	if booking.PickupStopID != nil && booking.DropoffStopID != nil {
+		var pickup, dropoff *trip.RouteStop
+		if newTrip.Route != nil {
+			pickup = newTrip.Route.FindStop(*booking.PickupStopID)
+			dropoff = newTrip.Route.FindStop(*booking.DropoffStopID)
+		}
+		if pickup == nil || dropoff == nil {
+			return nil, ginext.NewBadRequestError("booking stops are not served by the new trip")
+		}
+		fraction = newTrip.Route.DistanceFraction(pickup, dropoff)
+	}
+
+	available, err := s.checkSeatAvailability(ctx, newTrip.ID, req.SeatIDs, booking.Segment())
+	if err != nil {
+		return nil, ginext.NewInternalServerError(fmt.Sprintf("failed to check seat availability: %v", err))
+	}
+	if !available {
+		return nil, ginext.NewBadRequestError("one or more selected seats are already booked")
+	}
+
+	// 4. Reprice: new fare plus surcharge when inside the free exchange window
+	exchange := &model.BookingExchange{
+		BaseModel: model.BaseModel{
+			ID: uuid.New(),
+		},
+		BookingID: booking.ID,
+		OldTripID: booking.TripID,
+		NewTripID: newTrip.ID,
+		OldAmount: booking.TotalAmount,
+		Status:    model.ExchangeStatusPending,
+	}
+
+	prices, newAmount := priceSeats(seats, fares, fraction)
+	for _, seat := range seats {
+		exchange.Seats = append(exchange.Seats, model.BookingExchangeSeat{
+			SeatID:          seat.ID,
+			SeatNumber:      seat.SeatNumber,
+			SeatType:        seat.SeatType,
+			Floor:           seat.Floor,
+			Price:           prices[seat.ID],
+			PriceMultiplier: seat.PriceMultiplier,
+		})
+	}
+
+	exchange.NewAmount = newAmount
+	if oldTrip.DepartureTime.Sub(now) < constants.ExchangeFreeBeforeDeparture {
+		exchange.Surcharge = exchange.NewAmount * constants.ExchangeSurchargePercent / 100
+	}
+	exchange.AmountDue = exchange.NewAmount + exchange.Surcharge - booking.TotalAmount
+
+	// 5. Collect the difference or refund it
+	if exchange.AmountDue > 0 {
+		return s.collectDifference(ctx, booking, exchange)
+	}
+
+	return s.completeWithRefund(ctx, booking, exchange)
+}
+
+// collectDifference holds the new seats and creates a payment link for the fare difference.
+// The exchange is applied once the payment webhook reports the transaction as paid.
+func (s *bookingExchangeServiceImpl) collectDifference(ctx context.Context, booking *model.Booking, exchange *model.BookingExchange) (*model.BookingExchangeResponse, error) {
+	seatIDs := make([]uuid.UUID, len(exchange.Seats))
+	for i, seat := range exchange.Seats {
+		seatIDs[i] = seat.SeatID
+	}
+
+	if _, err := s.seatLockService.LockSeats(ctx, exchange.NewTripID, seatIDs, exchange.ID.String()); err != nil {
+		return nil, err
+	}
+
+	transactionID := uuid.New()
+	expiresAt := time.Now().UTC().Add(constants.BookingPaymentTimeout)
+	exchange.TransactionID = &transactionID
+	exchange.ExpiresAt = &expiresAt
+
+	if err := s.exchangeRepo.CreateExchange(ctx, exchange); err != nil {
+		s.releaseSeats(ctx, exchange)
+		return nil, ginext.NewInternalServerError(fmt.Sprintf("failed to create exchange: %v", err))
+	}
+
+	transaction, err := s.paymentClient.CreateTransaction(ctx, &payment.CreateTransactionRequest{
+		ID:            transactionID,
+		BookingID:     booking.ID,
+		Amount:        exchange.AmountDue,
+		Currency:      payment.CurrencyVND,
+		PaymentMethod: booking.PaymentMethod,
+		Description:   fmt.Sprintf("Doi ve %s", booking.BookingReference),
+		ExpiresAt:     expiresAt,
+		// Refunds of the booking then keep drawing on its original payment first
+		OriginalTransactionID: &booking.TransactionID,
+	})
+	if err != nil {
+		log.Error().Err(err).
+			Str("booking_id", booking.ID.String()).
+			Str("exchange_id", exchange.ID.String()).
+			Msg("Payment link creation failed for booking exchange")
+
+		if updateErr := s.exchangeRepo.UpdateExchangeStatus(ctx, exchange.ID, model.ExchangeStatusFailed); updateErr != nil {
+			log.Error().Err(updateErr).Str("exchange_id", exchange.ID.String()).Msg("Failed to mark booking exchange as failed")
+		}
+		s.releaseSeats(ctx, exchange)
+		return nil, ginext.NewInternalServerError("failed to create payment link")
+	}
+
+	resp := s.toExchangeResponse(exchange)
+	resp.Transaction = transaction
+	return resp, nil
+}
+
+// completeWithRefund applies an exchange that costs the same or less. The refund of any difference
+// is queued with the completion, so it goes out only once the booking has moved.
+func (s *bookingExchangeServiceImpl) completeWithRefund(ctx context.Context, booking *model.Booking, exchange *model.BookingExchange) (*model.BookingExchangeResponse, error) {
+	var events []*outbox.Event
+	if exchange.AmountDue < 0 {
+		event, err := outbox.NewEvent(model.AggregateTypeBooking, booking.ID, model.EventTypeExchangeRefundDue, &model.ExchangeRefundDueEvent{
+			ExchangeID:       exchange.ID,
+			BookingID:        booking.ID,
+			BookingReference: booking.BookingReference,
+			TransactionID:    booking.TransactionID,
+			Amount:           -exchange.AmountDue,
+			Completed:        true,
+		})
+		if err != nil {
+			return nil, ginext.NewInternalServerError(fmt.Sprintf("failed to queue refund: %v", err))
+		}
+		events = append(events, event)
+		// The exchange ID keys the refund
+		exchange.RefundID = &exchange.ID
+	}
+
+	if err := s.exchangeRepo.CreateExchange(ctx, exchange); err != nil {
+		return nil, ginext.NewInternalServerError(fmt.Sprintf("failed to create exchange: %v", err))
+	}
+
+	if err := s.exchangeRepo.CompleteExchange(ctx, exchange, booking.Segment(), events...); err != nil {
+		if updateErr := s.exchangeRepo.UpdateExchangeStatus(ctx, exchange.ID, model.ExchangeStatusFailed); updateErr != nil {
+			log.Error().Err(updateErr).Str("exchange_id", exchange.ID.String()).Msg("Failed to mark booking exchange as failed")
+		}
+		if errors.Is(err, model.ErrSeatsUnavailable) {
+			return nil, ginext.NewBadRequestError("one or more selected seats are already booked")
+		}
+		return nil, ginext.NewInternalServerError(fmt.Sprintf("failed to complete exchange: %v", err))
+	}
+
+	log.Info().
+		Str("booking_id", booking.ID.String()).
+		Str("exchange_id", exchange.ID.String()).
+		Str("new_trip_id", exchange.NewTripID.String()).
+		Int("amount_due", exchange.AmountDue).
+		Msg("Booking exchanged successfully")
+
+	return s.toExchangeResponse(exchange), nil
+}
+
+func (s *bookingExchangeServiceImpl) HandleExchangePayment(ctx context.Context, transactionID uuid.UUID, status payment.TransactionStatus) (bool, error) {
+	exchange, err := s.exchangeRepo.GetExchangeByTransactionID(ctx, transactionID)
+	if err != nil {
+		return false, nil
+	}
+
+	// Already processed
+	if exchange.Status != model.ExchangeStatusPending {
+		return true, nil
+	}
+
+	switch status {
+	case payment.TransactionStatusPaid:
+		booking, err := s.bookingRepo.GetBookingByID(ctx, exchange.BookingID)
+		if err != nil {
+			return true, fmt.Errorf("failed to get booking: %w", err)
+		}
+
+		err = s.exchangeRepo.CompleteExchange(ctx, exchange, booking.Segment())
+		switch {
+		case errors.Is(err, model.ErrSeatsUnavailable):
+			// Seats may have been taken if the hold expired before the payment arrived
+			return true, s.failPaidExchange(ctx, booking, exchange)
+		case errors.Is(err, model.ErrExchangeNotPending):
+			// Another delivery of the payment update settled it
+			return true, nil
+		case err != nil:
+			return true, err
+		}
+
+	case payment.TransactionStatusCancelled:
+		s.releaseSeats(ctx, exchange)
+		return true, s.exchangeRepo.UpdateExchangeStatus(ctx, exchange.ID, model.ExchangeStatusCancelled)
+
+	case payment.TransactionStatusExpired:
+		s.releaseSeats(ctx, exchange)
+		return true, s.exchangeRepo.UpdateExchangeStatus(ctx, exchange.ID, model.ExchangeStatusExpired)
+
+	case payment.TransactionStatusFailed:
+		s.releaseSeats(ctx, exchange)
+		return true, s.exchangeRepo.UpdateExchangeStatus(ctx, exchange.ID, model.ExchangeStatusFailed)
+	}
+
+	return true, nil
+}
+
+// failPaidExchange fails an exchange whose fare difference was paid after its seats were taken. The
+// refund of the difference is queued with the status, so the money goes back even if payment service
+// cannot be reached now.
+func (s *bookingExchangeServiceImpl) failPaidExchange(ctx context.Context, booking *model.Booking, exchange *model.BookingExchange) error {
+	event, err := outbox.NewEvent(model.AggregateTypeBooking, booking.ID, model.EventTypeExchangeRefundDue, &model.ExchangeRefundDueEvent{
+		ExchangeID:       exchange.ID,
+		BookingID:        booking.ID,
+		BookingReference: booking.BookingReference,
+		TransactionID:    *exchange.TransactionID,
+		Amount:           exchange.AmountDue,
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := s.exchangeRepo.UpdateExchangeStatusWithEvents(ctx, exchange.ID, model.ExchangeStatusFailed, event); err != nil {
+		return err
+	}
+	s.releaseSeats(ctx, exchange)
+
+	log.Warn().
+		Str("exchange_id", exchange.ID.String()).
+		Str("transaction_id", exchange.TransactionID.String()).
+		Int("amount", exchange.AmountDue).
+		Msg("Exchange paid but seats are no longer available, refunding the fare difference")
+	return nil
+}
+
+// RefundExchangeDifference is the outbox handler refunding the fare difference of an exchange: what
+// a cheaper new trip saves, or what was paid for an exchange that failed, leaving the passenger on
+// the original booking.
+func (s *bookingExchangeServiceImpl) RefundExchangeDifference(ctx context.Context, event *outbox.Event) error {
+	var payload model.ExchangeRefundDueEvent
+	if err := event.Decode(&payload); err != nil {
+		return outbox.Permanent(err)
+	}
+
+	reason := fmt.Sprintf("Đổi vé %s không thành công: hoàn chênh lệch giá vé", payload.BookingReference)
+	if payload.Completed {
+		reason = fmt.Sprintf("Đổi vé %s: hoàn chênh lệch giá vé", payload.BookingReference)
+	}
+
+	refund, err := s.paymentClient.CreateOperatorRefund(ctx, &payment.RefundRequest{
+		ID:           payload.ExchangeID,
+		BookingID:    payload.BookingID,
+		Reason:       reason,
+		RefundAmount: payload.Amount,
+	})
+	if errors.Is(err, client.ErrRefundAlreadyExists) {
+		// Nothing left to refund, the booking was refunded in full in the meantime
+		log.Warn().
+			Str("exchange_id", payload.ExchangeID.String()).
+			Str("booking_id", payload.BookingID.String()).
+			Msg("Nothing left to refund for exchange")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	log.Info().
+		Str("exchange_id", payload.ExchangeID.String()).
+		Str("refund_id", refund.ID.String()).
+		Int("refund_amount", refund.RefundAmount).
+		Msg("Refunded fare difference of exchange")
+	return nil
+}
+
+// checkSeatAvailability verifies the seats are neither booked nor held by another session
+func (s *bookingExchangeServiceImpl) checkSeatAvailability(ctx context.Context, tripID uuid.UUID, seatIDs []uuid.UUID, segment model.TripSegment) (bool, error) {
+	available, err := s.checkBookedSeats(ctx, tripID, seatIDs, segment)
+	if err != nil || !available {
+		return available, err
+	}
+
+	if err := s.seatLockService.ValidateSeatAvailability(ctx, tripID, seatIDs); err != nil {
+		return false, nil
+	}
+
+	return true, nil
+}
+
+func (s *bookingExchangeServiceImpl) checkBookedSeats(ctx context.Context, tripID uuid.UUID, seatIDs []uuid.UUID, segment model.TripSegment) (bool, error) {
+	bookedSeatIDs, err := s.bookingRepo.GetBookedSeatIDs(ctx, tripID, segment)
+	if err != nil {
+		return false, err
+	}
+
+	bookedMap := make(map[uuid.UUID]bool)
+	for _, bookedID := range bookedSeatIDs {
+		bookedMap[bookedID] = true
+	}
+
+	for _, seatID := range seatIDs {
+		if bookedMap[seatID] {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+func (s *bookingExchangeServiceImpl) releaseSeats(ctx context.Context, exchange *model.BookingExchange) {
+	if err := s.seatLockService.UnlockSeats(ctx, exchange.ID.String()); err != nil {
+		log.Warn().Err(err).
+			Str("exchange_id", exchange.ID.String()).
+			Msg("Failed to release seat locks held by booking exchange")
+	}
+}
+
+func (s *bookingExchangeServiceImpl) toExchangeResponse(exchange *model.BookingExchange) *model.BookingExchangeResponse {
+	resp := &model.BookingExchangeResponse{
+		ID:          exchange.ID,
+		BookingID:   exchange.BookingID,
+		OldTripID:   exchange.OldTripID,
+		NewTripID:   exchange.NewTripID,
+		OldAmount:   exchange.OldAmount,
+		NewAmount:   exchange.NewAmount,
+		Surcharge:   exchange.Surcharge,
+		AmountDue:   exchange.AmountDue,
+		Status:      exchange.Status,
+		ExpiresAt:   exchange.ExpiresAt,
+		CompletedAt: exchange.CompletedAt,
+	}
+
+	for _, seat := range exchange.Seats {
+		resp.Seats = append(resp.Seats, model.BookingSeatResponse{
+			SeatID:          seat.SeatID,
+			SeatNumber:      seat.SeatNumber,
+			SeatType:        seat.SeatType,
+			Floor:           seat.Floor,
+			Price:           seat.Price,
+			PriceMultiplier: seat.PriceMultiplier,
+		})
+	}
+
+	return resp
+}
@@ -0,0 +1,489 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"bus-booking/booking-service/internal/client"
+	"bus-booking/booking-service/internal/client/mocks"
+	"bus-booking/booking-service/internal/model"
+	"bus-booking/booking-service/internal/model/payment"
+	"bus-booking/booking-service/internal/model/trip"
+	repo_mocks "bus-booking/booking-service/internal/repository/mocks"
+	service_mocks "bus-booking/booking-service/internal/service/mocks"
+	"bus-booking/shared/ginext"
+	"bus-booking/shared/outbox"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+type exchangeTestFixture struct {
+	bookingRepo     *repo_mocks.MockBookingRepository
+	exchangeRepo    *repo_mocks.MockBookingExchangeRepository
+	paymentClient   *mocks.MockPaymentClient
+	tripClient      *mocks.MockTripClient
+	seatLockService *service_mocks.MockSeatLockService
+	service         BookingExchangeService
+
+	userID  uuid.UUID
+	booking *model.Booking
+	oldTrip *trip.Trip
+	newTrip *trip.Trip
+	seat    trip.Seat
+}
+
+func newExchangeTestFixture(ctrl *gomock.Controller, oldDepartureIn time.Duration, newBasePrice float64) *exchangeTestFixture {
+	f := &exchangeTestFixture{
+		bookingRepo:     repo_mocks.NewMockBookingRepository(ctrl),
+		exchangeRepo:    repo_mocks.NewMockBookingExchangeRepository(ctrl),
+		paymentClient:   mocks.NewMockPaymentClient(ctrl),
+		tripClient:      mocks.NewMockTripClient(ctrl),
+		seatLockService: service_mocks.NewMockSeatLockService(ctrl),
+		userID:          uuid.New(),
+	}
+	f.service = NewBookingExchangeService(f.bookingRepo, f.exchangeRepo, f.paymentClient, f.tripClient, f.seatLockService)
+
+	routeID := uuid.New()
+	newBusID := uuid.New()
+	now := time.Now().UTC()
+
+	f.oldTrip = &trip.Trip{
+		ID:            uuid.New(),
+		RouteID:       routeID,
+		BusID:         uuid.New(),
+		DepartureTime: now.Add(oldDepartureIn),
+		BasePrice:     200000,
+		Status:        trip.TripStatusScheduled,
+		IsActive:      true,
+	}
+	f.newTrip = &trip.Trip{
+		ID:            uuid.New(),
+		RouteID:       routeID,
+		BusID:         newBusID,
+		DepartureTime: now.Add(72 * time.Hour),
+		BasePrice:     newBasePrice,
+		Status:        trip.TripStatusScheduled,
+		IsActive:      true,
+	}
+	f.seat = trip.Seat{
+		ID:              uuid.New(),
+		BusID:           newBusID,
+		SeatNumber:      "B2",
+		SeatType:        "standard",
+		PriceMultiplier: 1.0,
+		Floor:           1,
+	}
+	f.booking = &model.Booking{
+		BaseModel:        model.BaseModel{ID: uuid.New()},
+		BookingReference: "BK251208AB12",
+		TripID:           f.oldTrip.ID,
+		UserID:           f.userID,
+		TotalAmount:      200000,
+		TransactionID:    uuid.New(),
+		Status:           model.BookingStatusConfirmed,
+		BookingSeats: []model.BookingSeat{
+			{SeatID: uuid.New(), SeatNumber: "A1", Price: 200000, PriceMultiplier: 1.0},
+		},
+	}
+
+	return f
+}
+
+func (f *exchangeTestFixture) expectLookups(ctx context.Context) {
+	f.bookingRepo.EXPECT().GetBookingByID(ctx, f.booking.ID).Return(f.booking, nil)
+	f.exchangeRepo.EXPECT().GetPendingExchangeByBookingID(ctx, f.booking.ID).Return(nil, assert.AnError)
+	f.tripClient.EXPECT().GetTripByID(gomock.Any(), gomock.Any(), f.oldTrip.ID).Return(f.oldTrip, nil)
+	f.tripClient.EXPECT().GetTripByID(gomock.Any(), gomock.Any(), f.newTrip.ID).Return(f.newTrip, nil)
+	f.tripClient.EXPECT().ListSeatsByIDs(gomock.Any(), []uuid.UUID{f.seat.ID}).Return([]trip.Seat{f.seat}, nil)
+	f.seatLockService.EXPECT().
+		QuoteSeatFares(gomock.Any(), f.newTrip.ID, "", []uuid.UUID{f.seat.ID}).
+		Return(map[uuid.UUID]float64{f.seat.ID: f.newTrip.BasePrice}, nil)
+}
+
+func (f *exchangeTestFixture) request() *model.ExchangeBookingRequest {
+	return &model.ExchangeBookingRequest{
+		TripID:  f.newTrip.ID,
+		SeatIDs: []uuid.UUID{f.seat.ID},
+	}
+}
+
+func TestExchangeBooking_FreeSamePrice(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	f := newExchangeTestFixture(ctrl, 48*time.Hour, 200000)
+	f.expectLookups(ctx)
+
+	f.bookingRepo.EXPECT().GetBookedSeatIDs(ctx, f.newTrip.ID, model.FullTripSegment()).Return([]uuid.UUID{}, nil)
+	f.seatLockService.EXPECT().ValidateSeatAvailability(ctx, f.newTrip.ID, []uuid.UUID{f.seat.ID}).Return(nil)
+	f.exchangeRepo.EXPECT().CreateExchange(ctx, gomock.Any()).Return(nil)
+	f.paymentClient.EXPECT().CreateRefund(gomock.Any(), gomock.Any()).Times(0)
+	f.exchangeRepo.EXPECT().CompleteExchange(ctx, gomock.Any(), model.FullTripSegment()).
+		DoAndReturn(func(_ context.Context, exchange *model.BookingExchange, _ model.TripSegment, events ...*outbox.Event) error {
+			assert.Empty(t, events)
+			exchange.Status = model.ExchangeStatusCompleted
+			return nil
+		})
+
+	resp, err := f.service.ExchangeBooking(ctx, f.booking.ID, f.request(), f.userID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 0, resp.Surcharge)
+	assert.Equal(t, 0, resp.AmountDue)
+	assert.Equal(t, model.ExchangeStatusCompleted, resp.Status)
+	assert.Len(t, resp.Seats, 1)
+	assert.Equal(t, "B2", resp.Seats[0].SeatNumber)
+}
+
+func TestExchangeBooking_CheaperTripRefundsDifference(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	f := newExchangeTestFixture(ctrl, 48*time.Hour, 150000)
+	f.expectLookups(ctx)
+
+	f.bookingRepo.EXPECT().GetBookedSeatIDs(ctx, f.newTrip.ID, model.FullTripSegment()).Return([]uuid.UUID{}, nil)
+	f.seatLockService.EXPECT().ValidateSeatAvailability(ctx, f.newTrip.ID, gomock.Any()).Return(nil)
+	f.exchangeRepo.EXPECT().CreateExchange(ctx, gomock.Any()).Return(nil)
+	// The refund is queued with the completion, not requested before the seats are secured
+	f.paymentClient.EXPECT().CreateRefund(gomock.Any(), gomock.Any()).Times(0)
+	f.exchangeRepo.EXPECT().CompleteExchange(ctx, gomock.Any(), model.FullTripSegment(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, exchange *model.BookingExchange, _ model.TripSegment, events ...*outbox.Event) error {
+			assert.Equal(t, &exchange.ID, exchange.RefundID)
+			if assert.Len(t, events, 1) {
+				assert.Equal(t, model.EventTypeExchangeRefundDue, events[0].EventType)
+				var payload model.ExchangeRefundDueEvent
+				assert.NoError(t, events[0].Decode(&payload))
+				assert.Equal(t, exchange.ID, payload.ExchangeID)
+				assert.Equal(t, f.booking.TransactionID, payload.TransactionID)
+				assert.Equal(t, 50000, payload.Amount)
+				assert.True(t, payload.Completed)
+			}
+			return nil
+		})
+
+	resp, err := f.service.ExchangeBooking(ctx, f.booking.ID, f.request(), f.userID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, -50000, resp.AmountDue)
+}
+
+func TestExchangeBooking_SeatTakenWhileCompleting(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	f := newExchangeTestFixture(ctrl, 48*time.Hour, 150000)
+	f.expectLookups(ctx)
+
+	f.bookingRepo.EXPECT().GetBookedSeatIDs(ctx, f.newTrip.ID, model.FullTripSegment()).Return([]uuid.UUID{}, nil)
+	f.seatLockService.EXPECT().ValidateSeatAvailability(ctx, f.newTrip.ID, gomock.Any()).Return(nil)
+	f.exchangeRepo.EXPECT().CreateExchange(ctx, gomock.Any()).Return(nil)
+	// Another passenger booked the seat after the availability check
+	f.exchangeRepo.EXPECT().CompleteExchange(ctx, gomock.Any(), model.FullTripSegment(), gomock.Any()).Return(model.ErrSeatsUnavailable)
+	f.exchangeRepo.EXPECT().UpdateExchangeStatus(ctx, gomock.Any(), model.ExchangeStatusFailed).Return(nil)
+
+	resp, err := f.service.ExchangeBooking(ctx, f.booking.ID, f.request(), f.userID)
+
+	assert.Nil(t, resp)
+	var apiErr *ginext.Error
+	if assert.ErrorAs(t, err, &apiErr) {
+		assert.Equal(t, http.StatusBadRequest, apiErr.Code)
+	}
+}
+
+func TestExchangeBooking_SurchargeCollectsPayment(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	// Inside the 12h window: 10% surcharge on the new fare
+	f := newExchangeTestFixture(ctrl, 6*time.Hour, 200000)
+	f.expectLookups(ctx)
+
+	f.bookingRepo.EXPECT().GetBookedSeatIDs(ctx, f.newTrip.ID, model.FullTripSegment()).Return([]uuid.UUID{}, nil)
+	f.seatLockService.EXPECT().ValidateSeatAvailability(ctx, f.newTrip.ID, gomock.Any()).Return(nil)
+	f.seatLockService.EXPECT().LockSeats(ctx, f.newTrip.ID, []uuid.UUID{f.seat.ID}, gomock.Any()).Return(&model.SeatHold{ExpiresAt: time.Now()}, nil)
+	f.exchangeRepo.EXPECT().CreateExchange(ctx, gomock.Any()).Return(nil)
+	f.paymentClient.EXPECT().CreateTransaction(ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, req *payment.CreateTransactionRequest) (*payment.TransactionResponse, error) {
+			assert.Equal(t, 20000, req.Amount)
+			assert.Equal(t, f.booking.ID, req.BookingID)
+			// The difference is paid on top of the booking's payment, which refunds keep drawing on first
+			assert.Equal(t, &f.booking.TransactionID, req.OriginalTransactionID)
+			return &payment.TransactionResponse{ID: req.ID, CheckoutURL: "https://pay.example/checkout"}, nil
+		})
+
+	resp, err := f.service.ExchangeBooking(ctx, f.booking.ID, f.request(), f.userID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 20000, resp.Surcharge)
+	assert.Equal(t, 20000, resp.AmountDue)
+	assert.Equal(t, model.ExchangeStatusPending, resp.Status)
+	assert.NotNil(t, resp.Transaction)
+}
+
+func TestExchangeBooking_DifferentRoute(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	f := newExchangeTestFixture(ctrl, 48*time.Hour, 200000)
+	f.newTrip.RouteID = uuid.New()
+	f.expectLookups(ctx)
+
+	resp, err := f.service.ExchangeBooking(ctx, f.booking.ID, f.request(), f.userID)
+
+	assert.Error(t, err)
+	assert.Nil(t, resp)
+	assert.Contains(t, err.Error(), "same route")
+}
+
+func TestExchangeBooking_NotOwner(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	f := newExchangeTestFixture(ctrl, 48*time.Hour, 200000)
+	f.bookingRepo.EXPECT().GetBookingByID(ctx, f.booking.ID).Return(f.booking, nil)
+
+	resp, err := f.service.ExchangeBooking(ctx, f.booking.ID, f.request(), uuid.New())
+
+	assert.Error(t, err)
+	assert.Nil(t, resp)
+}
+
+func TestExchangeBooking_NotConfirmed(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	f := newExchangeTestFixture(ctrl, 48*time.Hour, 200000)
+	f.booking.Status = model.BookingStatusPending
+	f.bookingRepo.EXPECT().GetBookingByID(ctx, f.booking.ID).Return(f.booking, nil)
+
+	resp, err := f.service.ExchangeBooking(ctx, f.booking.ID, f.request(), f.userID)
+
+	assert.Error(t, err)
+	assert.Nil(t, resp)
+	assert.Contains(t, err.Error(), "only confirmed")
+}
+
+func TestExchangeBooking_SeatTaken(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	f := newExchangeTestFixture(ctrl, 48*time.Hour, 200000)
+	f.expectLookups(ctx)
+
+	f.bookingRepo.EXPECT().GetBookedSeatIDs(ctx, f.newTrip.ID, model.FullTripSegment()).Return([]uuid.UUID{f.seat.ID}, nil)
+
+	resp, err := f.service.ExchangeBooking(ctx, f.booking.ID, f.request(), f.userID)
+
+	assert.Error(t, err)
+	assert.Nil(t, resp)
+	assert.Contains(t, err.Error(), "already booked")
+}
+
+func TestHandleExchangePayment_UnknownTransaction(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	f := newExchangeTestFixture(ctrl, 48*time.Hour, 200000)
+	txID := uuid.New()
+
+	f.exchangeRepo.EXPECT().GetExchangeByTransactionID(ctx, txID).Return(nil, assert.AnError)
+
+	handled, err := f.service.HandleExchangePayment(ctx, txID, payment.TransactionStatusPaid)
+
+	assert.NoError(t, err)
+	assert.False(t, handled)
+}
+
+func TestHandleExchangePayment_PaidCompletesExchange(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	f := newExchangeTestFixture(ctrl, 48*time.Hour, 200000)
+	txID := uuid.New()
+	exchange := &model.BookingExchange{
+		BaseModel:     model.BaseModel{ID: uuid.New()},
+		BookingID:     f.booking.ID,
+		NewTripID:     f.newTrip.ID,
+		Status:        model.ExchangeStatusPending,
+		TransactionID: &txID,
+		Seats:         []model.BookingExchangeSeat{{SeatID: f.seat.ID, SeatNumber: "B2"}},
+	}
+
+	f.exchangeRepo.EXPECT().GetExchangeByTransactionID(ctx, txID).Return(exchange, nil)
+	f.bookingRepo.EXPECT().GetBookingByID(ctx, f.booking.ID).Return(f.booking, nil)
+	f.exchangeRepo.EXPECT().CompleteExchange(ctx, exchange, model.FullTripSegment()).Return(nil)
+
+	handled, err := f.service.HandleExchangePayment(ctx, txID, payment.TransactionStatusPaid)
+
+	assert.NoError(t, err)
+	assert.True(t, handled)
+}
+
+func TestHandleExchangePayment_PaidAlreadySettledByRedelivery(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	f := newExchangeTestFixture(ctrl, 48*time.Hour, 260000)
+	txID := uuid.New()
+	exchange := &model.BookingExchange{
+		BaseModel:     model.BaseModel{ID: uuid.New()},
+		BookingID:     f.booking.ID,
+		NewTripID:     f.newTrip.ID,
+		AmountDue:     60000,
+		Status:        model.ExchangeStatusPending,
+		TransactionID: &txID,
+		Seats:         []model.BookingExchangeSeat{{SeatID: f.seat.ID, SeatNumber: "B2"}},
+	}
+
+	f.exchangeRepo.EXPECT().GetExchangeByTransactionID(ctx, txID).Return(exchange, nil)
+	f.bookingRepo.EXPECT().GetBookingByID(ctx, f.booking.ID).Return(f.booking, nil)
+	// A concurrent delivery completed the exchange first: its seats are now booked by this booking
+	f.exchangeRepo.EXPECT().CompleteExchange(ctx, exchange, model.FullTripSegment()).Return(model.ErrExchangeNotPending)
+	f.exchangeRepo.EXPECT().UpdateExchangeStatusWithEvents(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+	handled, err := f.service.HandleExchangePayment(ctx, txID, payment.TransactionStatusPaid)
+
+	assert.NoError(t, err)
+	assert.True(t, handled)
+}
+
+func TestHandleExchangePayment_PaidAfterSeatsTakenRefundsDifference(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	f := newExchangeTestFixture(ctrl, 48*time.Hour, 260000)
+	txID := uuid.New()
+	exchange := &model.BookingExchange{
+		BaseModel:     model.BaseModel{ID: uuid.New()},
+		BookingID:     f.booking.ID,
+		NewTripID:     f.newTrip.ID,
+		AmountDue:     60000,
+		Status:        model.ExchangeStatusPending,
+		TransactionID: &txID,
+		Seats:         []model.BookingExchangeSeat{{SeatID: f.seat.ID, SeatNumber: "B2"}},
+	}
+
+	f.exchangeRepo.EXPECT().GetExchangeByTransactionID(ctx, txID).Return(exchange, nil)
+	f.bookingRepo.EXPECT().GetBookingByID(ctx, f.booking.ID).Return(f.booking, nil)
+	// The hold expired and another passenger booked the seat before the payment arrived
+	f.exchangeRepo.EXPECT().CompleteExchange(ctx, exchange, model.FullTripSegment()).Return(model.ErrSeatsUnavailable)
+	f.exchangeRepo.EXPECT().
+		UpdateExchangeStatusWithEvents(ctx, exchange.ID, model.ExchangeStatusFailed, gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ uuid.UUID, _ model.ExchangeStatus, events ...*outbox.Event) error {
+			if assert.Len(t, events, 1) {
+				assert.Equal(t, model.EventTypeExchangeRefundDue, events[0].EventType)
+				var payload model.ExchangeRefundDueEvent
+				assert.NoError(t, events[0].Decode(&payload))
+				assert.Equal(t, exchange.ID, payload.ExchangeID)
+				assert.Equal(t, txID, payload.TransactionID)
+				assert.Equal(t, 60000, payload.Amount)
+			}
+			return nil
+		})
+	f.seatLockService.EXPECT().UnlockSeats(ctx, exchange.ID.String()).Return(nil)
+
+	handled, err := f.service.HandleExchangePayment(ctx, txID, payment.TransactionStatusPaid)
+
+	assert.NoError(t, err)
+	assert.True(t, handled)
+}
+
+func TestRefundExchangeDifference(t *testing.T) {
+	tests := []struct {
+		name       string
+		completed  bool
+		refundErr  error
+		wantErr    bool
+		wantReason string
+	}{
+		{name: "refunds the difference of a failed exchange", wantReason: "không thành công"},
+		{name: "refunds the difference of a cheaper exchange", completed: true, wantReason: "Đổi vé BK251208AB12: hoàn"},
+		{name: "nothing left to refund", refundErr: client.ErrRefundAlreadyExists},
+		{name: "payment service down is retried", refundErr: assert.AnError, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			ctx := context.Background()
+			f := newExchangeTestFixture(ctrl, 48*time.Hour, 260000)
+			exchangeID := uuid.New()
+
+			event, err := outbox.NewEvent(model.AggregateTypeBooking, f.booking.ID, model.EventTypeExchangeRefundDue, &model.ExchangeRefundDueEvent{
+				ExchangeID:       exchangeID,
+				BookingID:        f.booking.ID,
+				BookingReference: f.booking.BookingReference,
+				TransactionID:    uuid.New(),
+				Amount:           60000,
+				Completed:        tt.completed,
+			})
+			assert.NoError(t, err)
+
+			var refund *payment.RefundResponse
+			if tt.refundErr == nil {
+				refund = &payment.RefundResponse{ID: exchangeID, RefundAmount: 60000}
+			}
+			f.paymentClient.EXPECT().CreateOperatorRefund(ctx, gomock.Any()).
+				DoAndReturn(func(_ context.Context, req *payment.RefundRequest) (*payment.RefundResponse, error) {
+					// Keyed by the exchange, so a redelivered event refunds once
+					assert.Equal(t, exchangeID, req.ID)
+					assert.Equal(t, f.booking.ID, req.BookingID)
+					assert.Equal(t, 60000, req.RefundAmount)
+					if tt.wantReason != "" {
+						assert.Contains(t, req.Reason, tt.wantReason)
+					}
+					return refund, tt.refundErr
+				})
+
+			err = f.service.RefundExchangeDifference(ctx, event)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestHandleExchangePayment_ExpiredReleasesSeats(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	f := newExchangeTestFixture(ctrl, 48*time.Hour, 200000)
+	txID := uuid.New()
+	exchange := &model.BookingExchange{
+		BaseModel:     model.BaseModel{ID: uuid.New()},
+		Status:        model.ExchangeStatusPending,
+		TransactionID: &txID,
+	}
+
+	f.exchangeRepo.EXPECT().GetExchangeByTransactionID(ctx, txID).Return(exchange, nil)
+	f.seatLockService.EXPECT().UnlockSeats(ctx, exchange.ID.String()).Return(nil)
+	f.exchangeRepo.EXPECT().UpdateExchangeStatus(ctx, exchange.ID, model.ExchangeStatusExpired).Return(nil)
+
+	handled, err := f.service.HandleExchangePayment(ctx, txID, payment.TransactionStatusExpired)
+
+	assert.NoError(t, err)
+	assert.True(t, handled)
+}
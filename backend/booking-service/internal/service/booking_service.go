@@ -3,8 +3,11 @@ package service
 import (
 	"context"
 	"crypto/rand"
+	"errors"
 	"fmt"
 	"math/big"
+	"sort"
+	"strings"
 	"time"
 
 	"bus-booking/booking-service/internal/client"
@@ -15,6 +18,7 @@ import (
 	"bus-booking/booking-service/internal/model/user"
 	"bus-booking/booking-service/internal/repository"
 	"bus-booking/shared/ginext"
+	"bus-booking/shared/outbox"
 	"bus-booking/shared/queue"
 
 	"github.com/google/uuid"
@@ -31,14 +35,20 @@ type BookingService interface {
 	GetByReference(ctx context.Context, reference string, email string) (*model.BookingResponse, error)
 	GetUserBookings(ctx context.Context, req model.GetUserBookingsRequest, userID uuid.UUID) ([]*model.BookingResponse, int64, error)
 	GetTripBookings(ctx context.Context, req model.PaginationRequest, tripID uuid.UUID) ([]*model.BookingResponse, int64, error)
+	GetActiveTripBookings(ctx context.Context, tripID uuid.UUID) ([]*model.BookingResponse, error)
 	ListBookings(ctx context.Context, req model.ListBookingsRequest) ([]*model.BookingResponse, int64, error)
 
 	CancelBooking(ctx context.Context, id uuid.UUID, reason string) error
+	CancelForTrip(ctx context.Context, id uuid.UUID, reason string) error
+	NotifyTripCancelled(ctx context.Context, id uuid.UUID, req *model.NotifyTripCancelledRequest) error
+	GetRefundQuote(ctx context.Context, id uuid.UUID) (*model.RefundQuoteResponse, error)
 	RetryPayment(ctx context.Context, bookingID uuid.UUID) (*model.BookingResponse, error)
 
-	GetSeatStatus(ctx context.Context, tripID uuid.UUID, seatIDs []uuid.UUID) ([]model.SeatStatusItem, error)
+	GetSeatStatus(ctx context.Context, tripID uuid.UUID, seatIDs []uuid.UUID, segment model.TripSegment) ([]model.SeatStatusItem, error)
 	GetTripPassengers(ctx context.Context, tripID uuid.UUID) ([]model.PassengerResponse, error)
+	GetTripManifest(ctx context.Context, tripID uuid.UUID) (*model.TripManifestResponse, error)
 	ExpireBooking(ctx context.Context, bookingID uuid.UUID) error
+	CancelExpiredPayment(ctx context.Context, event *outbox.Event) error
 	CheckInPassenger(ctx context.Context, bookingID uuid.UUID) error
 }
 
@@ -50,6 +60,9 @@ type bookingServiceImpl struct {
 	delayedQueue       queue.DelayedQueueManager
 	notificationClient client.NotificationClient
 	seatLockService    SeatLockService
+	cancellationPolicy CancellationPolicyService
+	exchangeService    BookingExchangeService
+	promotionService   PromotionService
 }
 
 func NewBookingService(
@@ -60,6 +73,9 @@ func NewBookingService(
 	notificationClient client.NotificationClient,
 	delayedQueue queue.DelayedQueueManager,
 	seatLockService SeatLockService,
+	cancellationPolicy CancellationPolicyService,
+	exchangeService BookingExchangeService,
+	promotionService PromotionService,
 ) BookingService {
 	return &bookingServiceImpl{
 		bookingRepo:        bookingRepo,
@@ -69,12 +85,40 @@ func NewBookingService(
 		notificationClient: notificationClient,
 		delayedQueue:       delayedQueue,
 		seatLockService:    seatLockService,
+		cancellationPolicy: cancellationPolicy,
+		exchangeService:    exchangeService,
+		promotionService:   promotionService,
 	}
 }
 
 func (s *bookingServiceImpl) CreateBooking(ctx context.Context, req *model.CreateBookingRequest, userID uuid.UUID) (*model.BookingResponse, error) {
-	// 1. Validate seat IDs
-	seatAvailability, err := s.checkSeatAvailability(ctx, req.TripID, req.SeatIDs)
+	var (
+		tripData *trip.Trip
+		seats    []trip.Seat
+		fares    map[uuid.UUID]float64
+	)
+
+	passengers, err := indexPassengersBySeat(req.SeatIDs, req.Passengers)
+	if err != nil {
+		return nil, err
+	}
+
+	// 1. Resolve the booked segment (whole trip unless pickup/dropoff stops are given)
+	segment := model.FullTripSegment()
+	var pickup, dropoff *trip.RouteStop
+	if req.PickupStopID != nil || req.DropoffStopID != nil {
+		tripData, pickup, dropoff, err = s.resolveSegmentStops(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		segment = model.TripSegment{
+			FromStopOrder: pickup.StopOrder,
+			ToStopOrder:   dropoff.StopOrder,
+		}
+	}
+
+	// 2. Validate seat IDs
+	seatAvailability, err := s.checkSeatAvailability(ctx, req.TripID, req.SeatIDs, segment)
 	if err != nil {
 		return nil, ginext.NewInternalServerError(fmt.Sprintf("failed to check seat availability: %v", err))
 	}
@@ -82,15 +126,13 @@ func (s *bookingServiceImpl) CreateBooking(ctx context.Context, req *model.Creat
 		return nil, ginext.NewBadRequestError("one or more selected seats are already booked")
 	}
 
-	// 2. Fetch trip and seat details concurrently
-	var (
-		tripData *trip.Trip
-		seats    []trip.Seat
-	)
-
+	// 3. Fetch trip and seat details concurrently
 	g, gCtx := errgroup.WithContext(ctx)
 
 	g.Go(func() error {
+		if tripData != nil {
+			return nil
+		}
 		var err error
 		tripData, err = s.tripClient.GetTripByID(gCtx, trip.GetTripByIDRequest{}, req.TripID)
 		if err != nil {
@@ -108,14 +150,41 @@ func (s *bookingServiceImpl) CreateBooking(ctx context.Context, req *model.Creat
 		return nil
 	})
 
+	g.Go(func() error {
+		var err error
+		fares, err = s.seatLockService.QuoteSeatFares(gCtx, req.TripID, req.SessionID, req.SeatIDs)
+		return err
+	})
+
 	if err := g.Wait(); err != nil {
 		return nil, err
 	}
 
-	// 4. Calculate total amount
-	totalAmount := s.calculateTotalPrice(tripData.BasePrice, seats)
+	// 4. Calculate total amount at the fares locked in with the seats, prorated by distance for segment bookings
+	fraction := 1.0
+	if pickup != nil {
+		fraction = tripData.Route.DistanceFraction(pickup, dropoff)
+	}
+	seatPrices, totalAmount := priceSeats(seats, fares, fraction)
 
-	// 5. Create booking
+	// 5. Apply the promo code; its use is taken together with the booking
+	var (
+		promotion *model.Promotion
+		discount  int
+	)
+	if req.PromoCode != "" {
+		promotion, discount, err = s.promotionService.ApplyPromotion(ctx, req.PromoCode, userID, tripData, totalAmount)
+		if err != nil {
+			return nil, err
+		}
+		totalAmount -= discount
+	}
+
+	// 6. Create booking
+	paymentMethod := req.PaymentMethod
+	if paymentMethod == "" {
+		paymentMethod = payment.PaymentMethodPayOS
+	}
 	expiresAt := time.Now().UTC().Add(constants.BookingPaymentTimeout)
 	booking := &model.Booking{
 		BaseModel: model.BaseModel{
@@ -128,36 +197,69 @@ func (s *bookingServiceImpl) CreateBooking(ctx context.Context, req *model.Creat
 		Status:            model.BookingStatusPending,
 		TransactionStatus: payment.TransactionStatusPending,
 		TransactionID:     uuid.New(),
+		PaymentMethod:     paymentMethod,
 		Notes:             req.Notes,
 		ExpiresAt:         &expiresAt,
 	}
+	if promotion != nil {
+		booking.PromotionID = &promotion.ID
+		booking.PromoCode = promotion.Code
+		booking.DiscountAmount = discount
+	}
+	if pickup != nil {
+		booking.PickupStopID = &pickup.ID
+		booking.DropoffStopID = &dropoff.ID
+		booking.PickupStopOrder = &pickup.StopOrder
+		booking.DropoffStopOrder = &dropoff.StopOrder
+		booking.PickupLocation = pickup.Location
+		booking.DropoffLocation = dropoff.Location
+	}
 
-	// 6. Create booking seats
+	// 7. Create booking seats with their named passengers
 	for _, seat := range seats {
-		booking.BookingSeats = append(booking.BookingSeats, model.BookingSeat{
+		bookingSeat := model.BookingSeat{
 			SeatID:          seat.ID,
 			SeatNumber:      seat.SeatNumber,
 			SeatType:        seat.SeatType,
 			Floor:           seat.Floor,
-			Price:           seat.CalculateSeatPrice(tripData.BasePrice),
+			Price:           seatPrices[seat.ID],
 			PriceMultiplier: seat.PriceMultiplier,
-		})
+		}
+		if passenger, ok := passengers[seat.ID]; ok {
+			bookingSeat.PassengerName = strings.TrimSpace(passenger.FullName)
+			bookingSeat.PassengerPhone = passenger.Phone
+			bookingSeat.PassengerIDNumber = strings.TrimSpace(passenger.IDNumber)
+			bookingSeat.PassengerAgeCategory = passenger.AgeCategory
+		}
+		booking.BookingSeats = append(booking.BookingSeats, bookingSeat)
 	}
 
-	// 7. Save to database
-	if err := s.bookingRepo.CreateBooking(ctx, booking); err != nil {
+	// 8. Save to database, converting the seat hold into booking seats atomically
+	sessionID := req.SessionID
+	if sessionID == "" {
+		sessionID = booking.ID.String()
+	}
+	if err := s.bookingRepo.CreateBookingFromHold(ctx, booking, sessionID, segment); err != nil {
+		if errors.Is(err, model.ErrSeatsUnavailable) {
+			return nil, ginext.NewConflictError("one or more selected seats are no longer available")
+		}
+		if errors.Is(err, model.ErrPromotionUnavailable) {
+			return nil, ginext.NewConflictError("promo code is no longer available")
+		}
 		return nil, ginext.NewInternalServerError(fmt.Sprintf("failed to create booking: %v", err))
 	}
 
-	// 8. Create payment link
+	// 9. Create payment link
 	transaction, err := s.paymentClient.CreateTransaction(ctx, &payment.CreateTransactionRequest{
 		ID:            booking.TransactionID,
 		BookingID:     booking.ID,
 		Amount:        totalAmount,
 		Currency:      payment.CurrencyVND,
-		PaymentMethod: payment.PaymentMethodPayOS,
+		PaymentMethod: booking.PaymentMethod,
 		Description:   fmt.Sprintf("Don hang %s", booking.BookingReference),
 		ExpiresAt:     expiresAt,
+		InvoiceBuyer:  req.BuyerInfo.InvoiceBuyer(),
+		UseWallet:     req.UseWallet,
 	})
 	if err != nil {
 		// Payment creation failed - update booking status to FAILED
@@ -173,10 +275,12 @@ func (s *bookingServiceImpl) CreateBooking(ctx context.Context, req *model.Creat
 			log.Error().Err(updateErr).
 				Str("booking_id", booking.ID.String()).
 				Msg("Failed to update booking status after payment failure")
+		} else {
+			s.releasePromotion(ctx, booking)
 		}
 
 		// Return booking with error info - user can retry payment
-		resp := s.toBookingResponse(booking)
+		resp := toBookingResponse(booking)
 		resp.Transaction = &payment.TransactionResponse{
 			ID:     booking.TransactionID,
 			Status: payment.TransactionStatusFailed,
@@ -184,19 +288,24 @@ func (s *bookingServiceImpl) CreateBooking(ctx context.Context, req *model.Creat
 		return resp, nil
 	}
 
-	// Payment link created successfully - send pending email and schedule expiration
-	// 9. Send pending email and schedule expiration job
-	if transaction != nil && transaction.CheckoutURL != "" {
+	// Payment created successfully - send pending email and schedule expiration
+	// 10. Send pending email and schedule expiration job
+	// Payments at the counter have no checkout link but still expire when nobody pays
+	payAtCounter := booking.PaymentMethod == payment.PaymentMethodCash
+	if transaction != nil && (transaction.CheckoutURL != "" || payAtCounter) {
 		go func() {
 			// Create a detached context with timeout for background task
 			bgCtx, cancel := context.WithTimeout(context.Background(), constants.BackgroundTaskTimeout)
 			defer cancel()
 
 			// Send pending email
-			s.sendBookingPendingEmail(bgCtx, booking, tripData, transaction.CheckoutURL)
+			if transaction.CheckoutURL != "" {
+				s.sendBookingPendingEmail(bgCtx, booking, tripData, transaction.CheckoutURL)
+			}
 
 			// Schedule expiration in delayed queue
 			item := &queue.DelayedItem{
+				ID:      booking.ID.String(), // one pending expiry per booking
 				Payload: booking.ID,
 			}
 			if err := s.delayedQueue.Schedule(bgCtx, constants.QueueNameBookingExpiry, item, *booking.ExpiresAt); err != nil {
@@ -213,7 +322,7 @@ func (s *bookingServiceImpl) CreateBooking(ctx context.Context, req *model.Creat
 		}()
 	}
 
-	resp := s.toBookingResponse(booking)
+	resp := toBookingResponse(booking)
 	resp.Transaction = transaction
 	return resp, nil
 }
@@ -236,14 +345,87 @@ func (s *bookingServiceImpl) CreateGuestBooking(ctx context.Context, req *model.
 
 	// 3. Use existing CreateBooking logic with guest user ID
 	return s.CreateBooking(ctx, &model.CreateBookingRequest{
-		TripID:  req.TripID,
-		SeatIDs: req.SeatIDs,
-		Notes:   req.Notes,
+		TripID:        req.TripID,
+		SeatIDs:       req.SeatIDs,
+		Notes:         req.Notes,
+		PaymentMethod: req.PaymentMethod,
+		SessionID:     req.SessionID,
+		PickupStopID:  req.PickupStopID,
+		DropoffStopID: req.DropoffStopID,
+		Passengers:    req.Passengers,
+		PromoCode:     req.PromoCode,
+		BuyerInfo:     req.BuyerInfo,
 	}, guest.ID)
 }
 
-func (s *bookingServiceImpl) checkSeatAvailability(ctx context.Context, tripID uuid.UUID, seatIDs []uuid.UUID) (bool, error) {
-	bookedSeatIDs, err := s.bookingRepo.GetBookedSeatIDs(ctx, tripID)
+// indexPassengersBySeat checks that every named passenger sits on one of the booked seats
+// and that no seat is given two passengers
+func indexPassengersBySeat(seatIDs []uuid.UUID, passengers []model.PassengerInfo) (map[uuid.UUID]model.PassengerInfo, error) {
+	booked := make(map[uuid.UUID]bool, len(seatIDs))
+	for _, id := range seatIDs {
+		booked[id] = true
+	}
+
+	bySeat := make(map[uuid.UUID]model.PassengerInfo, len(passengers))
+	for _, passenger := range passengers {
+		if !booked[passenger.SeatID] {
+			return nil, ginext.NewBadRequestError(fmt.Sprintf("passenger %s is assigned to seat %s which is not part of this booking", passenger.FullName, passenger.SeatID))
+		}
+		if _, exists := bySeat[passenger.SeatID]; exists {
+			return nil, ginext.NewBadRequestError(fmt.Sprintf("seat %s has more than one passenger", passenger.SeatID))
+		}
+		if strings.TrimSpace(passenger.FullName) == "" {
+			return nil, ginext.NewBadRequestError("passenger full name is required")
+		}
+		bySeat[passenger.SeatID] = passenger
+	}
+
+	return bySeat, nil
+}
+
+// resolveSegmentStops loads the trip with its route stops and validates the requested pickup/dropoff pair
+func (s *bookingServiceImpl) resolveSegmentStops(ctx context.Context, req *model.CreateBookingRequest) (*trip.Trip, *trip.RouteStop, *trip.RouteStop, error) {
+	if req.PickupStopID == nil || req.DropoffStopID == nil {
+		return nil, nil, nil, ginext.NewBadRequestError("pickup_stop_id and dropoff_stop_id must be provided together")
+	}
+
+	tripData, err := s.tripClient.GetTripByID(ctx, trip.GetTripByIDRequest{
+		PreLoadRoute:     true,
+		PreLoadRouteStop: true,
+	}, req.TripID)
+	if err != nil {
+		return nil, nil, nil, ginext.NewInternalServerError(fmt.Sprintf("failed to get trip data: %v", err))
+	}
+	if tripData.Route == nil {
+		return nil, nil, nil, ginext.NewInternalServerError("trip route not available")
+	}
+
+	pickup, dropoff, err := findSegmentStops(tripData.Route, *req.PickupStopID, *req.DropoffStopID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	return tripData, pickup, dropoff, nil
+}
+
+// findSegmentStops looks up a pickup/dropoff pair on the route and checks passengers can travel between them
+func findSegmentStops(route *trip.Route, pickupStopID, dropoffStopID uuid.UUID) (*trip.RouteStop, *trip.RouteStop, error) {
+	pickup := route.FindStop(pickupStopID)
+	if pickup == nil || !pickup.CanPickup() {
+		return nil, nil, ginext.NewBadRequestError("pickup stop is not a boarding point of this trip")
+	}
+	dropoff := route.FindStop(dropoffStopID)
+	if dropoff == nil || !dropoff.CanDropoff() {
+		return nil, nil, ginext.NewBadRequestError("dropoff stop is not an alighting point of this trip")
+	}
+	if pickup.StopOrder >= dropoff.StopOrder {
+		return nil, nil, ginext.NewBadRequestError("dropoff stop must come after pickup stop")
+	}
+	return pickup, dropoff, nil
+}
+
+func (s *bookingServiceImpl) checkSeatAvailability(ctx context.Context, tripID uuid.UUID, seatIDs []uuid.UUID, segment model.TripSegment) (bool, error) {
+	bookedSeatIDs, err := s.bookingRepo.GetBookedSeatIDs(ctx, tripID, segment)
 	if err != nil {
 		return false, err
 	}
@@ -262,16 +444,26 @@ func (s *bookingServiceImpl) checkSeatAvailability(ctx context.Context, tripID u
 	return true, nil
 }
 
-func (s *bookingServiceImpl) calculateTotalPrice(basePrice float64, seats []trip.Seat) int {
+// priceSeats prices each seat from the trip fare it is charged, prorated by the distance fraction
+// travelled for segment bookings, and returns the seat prices with their total
+func priceSeats(seats []trip.Seat, fares map[uuid.UUID]float64, fraction float64) (map[uuid.UUID]float64, int) {
+	prices := make(map[uuid.UUID]float64, len(seats))
 	total := 0.0
 	for _, seat := range seats {
-		total += seat.CalculateSeatPrice(basePrice)
+		price := seat.CalculateSeatPrice(trip.SegmentBasePrice(fares[seat.ID], fraction))
+		prices[seat.ID] = price
+		total += price
 	}
-	return int(total)
+	return prices, int(total)
 }
 
 // example: BK251208AB123
 func (s *bookingServiceImpl) generateBookingReference() string {
+	return generateReference(constants.BookingReferencePrefix)
+}
+
+// generateReference builds a booking or itinerary reference from its prefix, today's date and random characters
+func generateReference(prefix string) string {
 	now := time.Now().UTC()
 	dateStr := now.Format(constants.DateFormatBookingReference)
 
@@ -284,16 +476,116 @@ func (s *bookingServiceImpl) generateBookingReference() string {
 		randomPart[i] = constants.BookingReferenceCharset[n.Int64()]
 	}
 
-	return constants.BookingReferencePrefix + dateStr + string(randomPart)
+	return prefix + dateStr + string(randomPart)
 }
 
 func (s *bookingServiceImpl) UpdateBookingStatus(ctx context.Context, req *model.UpdateBookingStatusRequest, bookingID uuid.UUID) error {
+	// Payment service redelivers its outbox events until they are acknowledged
+	if req.EventID != uuid.Nil {
+		processed, err := s.bookingRepo.IsEventProcessed(ctx, req.EventID)
+		if err != nil {
+			return ginext.NewInternalServerError("failed to check payment event")
+		}
+		if processed {
+			log.Info().Str("event_id", req.EventID.String()).Str("booking_id", bookingID.String()).Msg("Payment event already processed, skipping")
+			return nil
+		}
+	}
+
 	booking, err := s.bookingRepo.GetBookingByID(ctx, bookingID)
 	if err != nil {
 		return ginext.NewNotFoundError("booking not found")
 	}
 
+	// Transactions other than the booking's own may belong to a ticket exchange
+	if req.TransactionID != uuid.Nil && req.TransactionID != booking.TransactionID {
+		handled, err := s.exchangeService.HandleExchangePayment(ctx, req.TransactionID, req.TransactionStatus)
+		if err != nil {
+			return ginext.NewInternalServerError(fmt.Sprintf("failed to update booking exchange: %v", err))
+		}
+		if handled {
+			if req.EventID != uuid.Nil {
+				if err := s.bookingRepo.MarkEventProcessed(ctx, req.EventID); err != nil {
+					log.Error().Err(err).Str("event_id", req.EventID.String()).Msg("Failed to record processed payment event")
+				}
+			}
+			return nil
+		}
+	}
+
+	// A combined payment settles every leg of the itinerary alike
+	legs := []*model.Booking{booking}
+	if booking.ItineraryID != nil {
+		if legs, err = s.paymentLegs(ctx, booking); err != nil {
+			return err
+		}
+	}
+
 	// Update payment status
+	heldSeats := make(map[uuid.UUID]bool, len(legs))
+	amountDue := 0
+	for _, leg := range legs {
+		heldSeats[leg.ID] = leg.Status == model.BookingStatusPending || leg.Status == model.BookingStatusConfirmed
+		amountDue += leg.TotalAmount
+	}
+	for _, leg := range legs {
+		s.applyPaymentStatus(ctx, leg, req, amountDue)
+	}
+
+	switch {
+	case len(legs) > 1:
+		err = s.bookingRepo.UpdateBookings(ctx, legs, req.EventID)
+	case req.EventID != uuid.Nil:
+		err = s.bookingRepo.UpdateBookingForEvent(ctx, booking, req.EventID)
+	default:
+		err = s.bookingRepo.UpdateBooking(ctx, booking)
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, leg := range legs {
+		switch leg.Status {
+		case model.BookingStatusCancelled, model.BookingStatusExpired, model.BookingStatusFailed:
+			s.releasePromotion(ctx, leg)
+			if heldSeats[leg.ID] {
+				s.offerReleasedSeats(ctx, leg)
+			}
+		case model.BookingStatusConfirmed:
+			// A payment arriving after the booking expired confirms it with its discount, so the use is taken back
+			if leg.PromotionID != nil {
+				if err := s.promotionService.RedeemForBooking(ctx, leg); err != nil {
+					log.Warn().Err(err).Str("booking_id", leg.ID.String()).Msg("Failed to redeem promotion of paid booking")
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// paymentLegs returns the legs of the booking's itinerary still paid by the booking's transaction
+func (s *bookingServiceImpl) paymentLegs(ctx context.Context, booking *model.Booking) ([]*model.Booking, error) {
+	legs, err := s.bookingRepo.GetItineraryBookings(ctx, *booking.ItineraryID)
+	if err != nil {
+		return nil, ginext.NewInternalServerError(fmt.Sprintf("failed to get itinerary bookings: %v", err))
+	}
+
+	paid := make([]*model.Booking, 0, len(legs))
+	for _, leg := range legs {
+		if leg.TransactionID == booking.TransactionID {
+			paid = append(paid, leg)
+		}
+	}
+	if len(paid) == 0 {
+		paid = append(paid, booking)
+	}
+	return paid, nil
+}
+
+// applyPaymentStatus moves the booking to the status its payment reached. amountDue is
+// what the payment covers, more than the booking's own total for itinerary legs.
+func (s *bookingServiceImpl) applyPaymentStatus(ctx context.Context, booking *model.Booking, req *model.UpdateBookingStatusRequest, amountDue int) {
+	bookingID := booking.ID
 	booking.TransactionStatus = req.TransactionStatus
 	switch booking.TransactionStatus {
 	case payment.TransactionStatusPending:
@@ -330,6 +622,7 @@ func (s *bookingServiceImpl) UpdateBookingStatus(ctx context.Context, req *model
 			// If now > executeAt, send immediately (schedule for now).
 			if time.Now().Before(trip.DepartureTime) {
 				reminderPayload := &queue.DelayedItem{
+					ID:      booking.ID.String(), // duplicate payment webhooks schedule a single reminder
 					Type:    "trip_reminder",
 					Payload: booking.ID,
 				}
@@ -339,6 +632,27 @@ func (s *bookingServiceImpl) UpdateBookingStatus(ctx context.Context, req *model
 			}
 		}
 
+	case payment.TransactionStatusUnderpaid:
+		// The seats stay held until the booking expires so the passenger can transfer the rest.
+		// Should it not arrive, payment service refunds what was paid when the hold expires.
+		if booking.Status != model.BookingStatusPending {
+			log.Warn().
+				Str("booking_id", booking.ID.String()).
+				Str("booking_status", string(booking.Status)).
+				Msg("Partial payment for a booking no longer awaiting payment")
+			break
+		}
+		// Itinerary legs share one payment link, asked to be topped up once
+		if booking.LegNumber > 1 {
+			break
+		}
+		amountPaid := req.AmountPaid
+		go func() {
+			bgCtx, cancel := context.WithTimeout(context.Background(), constants.BackgroundTaskTimeout)
+			defer cancel()
+			s.sendPaymentUnderpaidEmail(bgCtx, bookingID, amountDue, amountPaid)
+		}()
+
 	case payment.TransactionStatusCancelled:
 		booking.Status = model.BookingStatusCancelled
 		now := time.Now()
@@ -365,8 +679,6 @@ func (s *bookingServiceImpl) UpdateBookingStatus(ctx context.Context, req *model
 			s.sendBookingFailureEmail(bgCtx, bookingID, "Lỗi không xác định")
 		}()
 	}
-
-	return s.bookingRepo.UpdateBooking(ctx, booking)
 }
 
 func (s *bookingServiceImpl) GetByID(ctx context.Context, id uuid.UUID) (*model.BookingResponse, error) {
@@ -381,7 +693,7 @@ func (s *bookingServiceImpl) GetByID(ctx context.Context, id uuid.UUID) (*model.
 		return nil, ginext.NewInternalServerError("không thể lấy thông tin giao dịch")
 	}
 
-	resp := s.toBookingResponse(booking)
+	resp := toBookingResponse(booking)
 	resp.Transaction = transaction
 	return resp, nil
 }
@@ -395,7 +707,7 @@ func (s *bookingServiceImpl) GetByReference(ctx context.Context, reference strin
 
 	// For guest bookings, we trust the reference number is unique enough
 	// In production, you might want to verify email matches the user's email
-	return s.toBookingResponse(booking), nil
+	return toBookingResponse(booking), nil
 }
 
 // GetUserBookings retrieves bookings for a user with pagination
@@ -440,7 +752,7 @@ func (s *bookingServiceImpl) GetUserBookings(ctx context.Context, req model.GetU
 
 	// Build responses with trip data
 	for i, booking := range bookings {
-		resp := s.toBookingResponse(booking)
+		resp := toBookingResponse(booking)
 
 		// Add trip info if available
 		if tripData, ok := tripDataMap[booking.TripID]; ok {
@@ -470,13 +782,25 @@ func (s *bookingServiceImpl) CancelBooking(ctx context.Context, id uuid.UUID, re
 	}
 
 	if booking.Status == model.BookingStatusConfirmed {
-		return ginext.NewBadRequestError("cannot cancel confirmed booking")
+		return s.cancelConfirmedBooking(ctx, booking, reason)
 	}
 
+	return s.cancelPendingBooking(ctx, booking, reason)
+}
+
+// cancelPendingBooking cancels an unpaid booking and then its payment, if the payment can still be cancelled
+func (s *bookingServiceImpl) cancelPendingBooking(ctx context.Context, booking *model.Booking, reason string) error {
+	if booking.ItineraryID != nil {
+		return s.cancelPendingItinerary(ctx, booking, reason)
+	}
+	id := booking.ID
+
 	// Cancel the booking first
 	if err := s.bookingRepo.CancelBooking(ctx, id, reason); err != nil {
 		return err
 	}
+	s.releasePromotion(ctx, booking)
+	s.offerReleasedSeats(ctx, booking)
 
 	// Try to cancel payment if transaction exists
 	transaction, err := s.paymentClient.CancelTransaction(ctx, booking.TransactionID)
@@ -520,6 +844,190 @@ func (s *bookingServiceImpl) CancelBooking(ctx context.Context, id uuid.UUID, re
 	return nil
 }
 
+// cancelPendingItinerary cancels every unpaid leg of the booking's itinerary at once, as the legs
+// share their payment, and then the payment, if it can still be cancelled
+func (s *bookingServiceImpl) cancelPendingItinerary(ctx context.Context, booking *model.Booking, reason string) error {
+	legs, err := s.paymentLegs(ctx, booking)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	cancelled := make([]*model.Booking, 0, len(legs))
+	for _, leg := range legs {
+		if leg.Status == model.BookingStatusCancelled || leg.Status == model.BookingStatusConfirmed {
+			continue
+		}
+		leg.Status = model.BookingStatusCancelled
+		leg.CancellationReason = reason
+		leg.CancelledAt = &now
+		cancelled = append(cancelled, leg)
+	}
+
+	if err := s.bookingRepo.UpdateBookings(ctx, cancelled, uuid.Nil); err != nil {
+		return ginext.NewInternalServerError(fmt.Sprintf("failed to cancel itinerary bookings: %v", err))
+	}
+	for _, leg := range cancelled {
+		s.releasePromotion(ctx, leg)
+		s.offerReleasedSeats(ctx, leg)
+	}
+
+	transaction, err := s.paymentClient.CancelTransaction(ctx, booking.TransactionID)
+	if err != nil {
+		log.Error().Err(err).
+			Str("itinerary_id", booking.ItineraryID.String()).
+			Str("transaction_id", booking.TransactionID.String()).
+			Msg("Failed to cancel payment, but itinerary bookings are cancelled")
+		return nil
+	}
+
+	for _, leg := range cancelled {
+		leg.TransactionStatus = transaction.Status
+	}
+	if err := s.bookingRepo.UpdateBookings(ctx, cancelled, uuid.Nil); err != nil {
+		log.Error().Err(err).
+			Str("itinerary_id", booking.ItineraryID.String()).
+			Msg("Failed to update itinerary bookings transaction status after payment cancellation")
+	}
+
+	return nil
+}
+
+// cancelConfirmedBooking cancels a paid booking and requests a refund according to the cancellation policy.
+// The refund is requested before the booking is cancelled so a failed refund leaves the booking intact.
+func (s *bookingServiceImpl) cancelConfirmedBooking(ctx context.Context, booking *model.Booking, reason string) error {
+	quote, err := s.quoteRefund(ctx, booking)
+	if err != nil {
+		return err
+	}
+
+	if !quote.Cancellable {
+		return ginext.NewBadRequestError("cannot cancel booking after departure")
+	}
+
+	if quote.RefundAmount > 0 {
+		seatIDs := make([]uuid.UUID, len(booking.BookingSeats))
+		for i, seat := range booking.BookingSeats {
+			seatIDs[i] = seat.ID
+		}
+
+		refund, err := s.paymentClient.CreateRefund(ctx, &payment.RefundRequest{
+			BookingID:      booking.ID,
+			Reason:         fmt.Sprintf("Hủy vé %s (hoàn %d%%): %s", booking.BookingReference, quote.RefundPercent, reason),
+			RefundAmount:   quote.RefundAmount,
+			BookingSeatIDs: seatIDs,
+		})
+		switch {
+		case errors.Is(err, client.ErrRefundAlreadyExists):
+			// e.g. a retried cancellation whose seats were already refunded
+			log.Info().Str("booking_id", booking.ID.String()).Msg("Booking already refunded, cancelling without a new refund")
+		case err != nil:
+			log.Error().Err(err).
+				Str("booking_id", booking.ID.String()).
+				Int("refund_amount", quote.RefundAmount).
+				Msg("Failed to create refund for cancelled booking")
+			return ginext.NewInternalServerError(fmt.Sprintf("failed to create refund: %v", err))
+		default:
+			log.Info().
+				Str("booking_id", booking.ID.String()).
+				Str("refund_id", refund.ID.String()).
+				Int("refund_percent", quote.RefundPercent).
+				Int("refund_amount", quote.RefundAmount).
+				Msg("Refund created for cancelled booking")
+		}
+	}
+
+	if err := s.bookingRepo.CancelBooking(ctx, booking.ID, reason); err != nil {
+		return err
+	}
+	s.releasePromotion(ctx, booking)
+	s.offerReleasedSeats(ctx, booking)
+
+	return nil
+}
+
+// CancelForTrip cancels a booking because the operator cancelled its trip. It never refunds:
+// the trip cancellation saga refunds paid bookings in full. Repeated calls are no-ops.
+func (s *bookingServiceImpl) CancelForTrip(ctx context.Context, id uuid.UUID, reason string) error {
+	booking, err := s.bookingRepo.GetBookingByID(ctx, id)
+	if err != nil {
+		return ginext.NewNotFoundError("booking not found")
+	}
+
+	switch booking.Status {
+	case model.BookingStatusCancelled, model.BookingStatusExpired, model.BookingStatusFailed:
+		return nil
+	case model.BookingStatusConfirmed:
+		if err := s.bookingRepo.CancelBooking(ctx, id, reason); err != nil {
+			return ginext.NewInternalServerError("failed to cancel booking")
+		}
+		s.releasePromotion(ctx, booking)
+		return nil
+	}
+
+	return s.cancelPendingBooking(ctx, booking, reason)
+}
+
+// NotifyTripCancelled emails the passenger that the operator cancelled their trip.
+// Unlike the other emails it reports failures, so the caller can retry.
+func (s *bookingServiceImpl) NotifyTripCancelled(ctx context.Context, id uuid.UUID, req *model.NotifyTripCancelledRequest) error {
+	booking, err := s.bookingRepo.GetBookingByID(ctx, id)
+	if err != nil {
+		return ginext.NewNotFoundError("booking not found")
+	}
+
+	user, err := s.userClient.GetUserByID(ctx, booking.UserID)
+	if err != nil {
+		return ginext.NewInternalServerError(fmt.Sprintf("failed to get user: %v", err))
+	}
+
+	tripData, err := s.tripClient.GetTripByID(ctx, trip.GetTripByIDRequest{
+		PreLoadRoute: true,
+	}, booking.TripID)
+	if err != nil {
+		return ginext.NewInternalServerError(fmt.Sprintf("failed to get trip: %v", err))
+	}
+
+	if err := s.notificationClient.SendTripCancelled(ctx, &client.TripCancelledRequest{
+		Email:            user.Email,
+		Name:             user.FullName,
+		BookingReference: booking.BookingReference,
+		Reason:           req.Reason,
+		From:             tripData.Route.Origin,
+		To:               tripData.Route.Destination,
+		DepartureTime:    tripData.DepartureTime.Format(constants.DateTimeFormatDisplay),
+		RefundAmount:     req.RefundAmount,
+		BookingLink:      constants.DefaultFrontendURL,
+	}); err != nil {
+		return ginext.NewInternalServerError(fmt.Sprintf("failed to send trip cancelled email: %v", err))
+	}
+
+	return nil
+}
+
+// GetRefundQuote returns the refund the passenger would receive if the booking were cancelled now
+func (s *bookingServiceImpl) GetRefundQuote(ctx context.Context, id uuid.UUID) (*model.RefundQuoteResponse, error) {
+	booking, err := s.bookingRepo.GetBookingByID(ctx, id)
+	if err != nil {
+		return nil, ginext.NewNotFoundError("booking not found")
+	}
+
+	if booking.Status != model.BookingStatusConfirmed {
+		return nil, ginext.NewBadRequestError("only confirmed bookings are eligible for refund")
+	}
+
+	return s.quoteRefund(ctx, booking)
+}
+
+func (s *bookingServiceImpl) quoteRefund(ctx context.Context, booking *model.Booking) (*model.RefundQuoteResponse, error) {
+	tripData, err := s.tripClient.GetTripByID(ctx, trip.GetTripByIDRequest{}, booking.TripID)
+	if err != nil {
+		return nil, ginext.NewInternalServerError("failed to fetch trip data")
+	}
+
+	return s.cancellationPolicy.QuoteRefund(booking.ID, booking.TotalAmount, tripData.DepartureTime, time.Now().UTC()), nil
+}
+
 // RetryPayment creates a new payment link for a failed or expired booking
 func (s *bookingServiceImpl) RetryPayment(ctx context.Context, bookingID uuid.UUID) (*model.BookingResponse, error) {
 	// 1. Get booking
@@ -528,7 +1036,10 @@ func (s *bookingServiceImpl) RetryPayment(ctx context.Context, bookingID uuid.UU
 		return nil, ginext.NewNotFoundError("booking not found")
 	}
 
-	// 2. Validate booking is in retryable state
+	// 2. Validate booking is in retryable state; itinerary legs are only paid together
+	if booking.ItineraryID != nil {
+		return nil, ginext.NewBadRequestError("bookings of an itinerary cannot retry payment on their own")
+	}
 	if booking.Status != model.BookingStatusFailed && booking.Status != model.BookingStatusExpired {
 		return nil, ginext.NewBadRequestError("booking is not in a retryable state")
 	}
@@ -549,28 +1060,50 @@ func (s *bookingServiceImpl) RetryPayment(ctx context.Context, bookingID uuid.UU
 		return nil, ginext.NewInternalServerError("failed to fetch trip data")
 	}
 
-	// 5. Create new transaction ID and expiration
+	// 5. Take back the promotion use released when the booking failed or expired
+	if booking.PromotionID != nil {
+		if err := s.promotionService.RedeemForBooking(ctx, booking); err != nil {
+			return nil, err
+		}
+	}
+
+	// 6. Create new transaction ID and expiration
 	newTransactionID := uuid.New()
 	expiresAt := time.Now().UTC().Add(constants.BookingPaymentTimeout)
 
-	// 6. Create new payment link
+	// 7. Keep the VAT invoice and wallet use asked for with the failed payment
+	var invoiceBuyer *payment.InvoiceBuyer
+	useWallet := false
+	if previous, err := s.paymentClient.GetTransactionByID(ctx, booking.TransactionID); err != nil {
+		log.Warn().Err(err).
+			Str("booking_id", booking.ID.String()).
+			Msg("Failed to get previous transaction, retrying payment without its invoice request")
+	} else {
+		invoiceBuyer = previous.InvoiceBuyer
+		useWallet = previous.WalletAmount > 0
+	}
+
+	// 8. Create new payment link
 	transaction, err := s.paymentClient.CreateTransaction(ctx, &payment.CreateTransactionRequest{
 		ID:            newTransactionID,
 		BookingID:     booking.ID,
 		Amount:        booking.TotalAmount,
 		Currency:      payment.CurrencyVND,
-		PaymentMethod: payment.PaymentMethodPayOS,
+		PaymentMethod: booking.PaymentMethod,
 		Description:   fmt.Sprintf("Don hang %s (Thu lai)", booking.BookingReference),
 		ExpiresAt:     expiresAt,
+		InvoiceBuyer:  invoiceBuyer,
+		UseWallet:     useWallet,
 	})
 	if err != nil {
 		log.Error().Err(err).
 			Str("booking_id", booking.ID.String()).
 			Msg("Failed to create retry payment link")
+		s.releasePromotion(ctx, booking)
 		return nil, ginext.NewInternalServerError("failed to create payment link")
 	}
 
-	// 7. Update booking with new transaction and expiry
+	// 9. Update booking with new transaction and expiry
 	booking.TransactionID = newTransactionID
 	booking.TransactionStatus = payment.TransactionStatusPending
 	booking.Status = model.BookingStatusPending
@@ -580,15 +1113,17 @@ func (s *bookingServiceImpl) RetryPayment(ctx context.Context, bookingID uuid.UU
 		log.Error().Err(err).
 			Str("booking_id", booking.ID.String()).
 			Msg("Failed to update booking with new transaction")
+		s.releasePromotion(ctx, booking)
 		return nil, ginext.NewInternalServerError("failed to update booking")
 	}
 
-	// 8. Schedule expiration in delayed queue
+	// 10. Schedule expiration in delayed queue
 	go func() {
 		bgCtx, cancel := context.WithTimeout(context.Background(), 1*time.Minute)
 		defer cancel()
 
 		item := &queue.DelayedItem{
+			ID:      booking.ID.String(), // replaces the expiry scheduled at creation
 			Payload: booking.ID,
 		}
 		if err := s.delayedQueue.Schedule(bgCtx, constants.QueueNameBookingExpiry, item, expiresAt); err != nil {
@@ -604,19 +1139,21 @@ func (s *bookingServiceImpl) RetryPayment(ctx context.Context, bookingID uuid.UU
 		}
 	}()
 
-	// 9. Send pending email with new payment link
-	go func() {
-		bgCtx, cancel := context.WithTimeout(context.Background(), constants.BackgroundTaskTimeout)
-		defer cancel()
-		s.sendBookingPendingEmail(bgCtx, booking, tripData, transaction.CheckoutURL)
-	}()
+	// 11. Send pending email with new payment link
+	if transaction.CheckoutURL != "" {
+		go func() {
+			bgCtx, cancel := context.WithTimeout(context.Background(), constants.BackgroundTaskTimeout)
+			defer cancel()
+			s.sendBookingPendingEmail(bgCtx, booking, tripData, transaction.CheckoutURL)
+		}()
+	}
 
 	log.Info().
 		Str("booking_id", booking.ID.String()).
 		Str("new_transaction_id", newTransactionID.String()).
 		Msg("Payment retry successful")
 
-	resp := s.toBookingResponse(booking)
+	resp := toBookingResponse(booking)
 	resp.Transaction = transaction
 	return resp, nil
 }
@@ -630,7 +1167,7 @@ func (s *bookingServiceImpl) GetTripBookings(ctx context.Context, req model.Pagi
 
 	responses := make([]*model.BookingResponse, 0, len(bookings))
 	for _, booking := range bookings {
-		responses = append(responses, s.toBookingResponse(booking))
+		responses = append(responses, toBookingResponse(booking))
 	}
 
 	return responses, total, nil
@@ -638,7 +1175,46 @@ func (s *bookingServiceImpl) GetTripBookings(ctx context.Context, req model.Pagi
 
 // Helper methods
 
-func (s *bookingServiceImpl) toBookingResponse(booking *model.Booking) *model.BookingResponse {
+// releasePromotion gives back the promotion use of a booking that will not be paid.
+// The booking change is already saved, so a failure is only logged.
+func (s *bookingServiceImpl) releasePromotion(ctx context.Context, booking *model.Booking) {
+	if booking.PromotionID == nil {
+		return
+	}
+	if err := s.promotionService.ReleaseForBooking(ctx, booking.ID); err != nil {
+		log.Error().Err(err).
+			Str("booking_id", booking.ID.String()).
+			Str("promotion_id", booking.PromotionID.String()).
+			Msg("Failed to release promotion use of booking")
+	}
+}
+
+// offerReleasedSeats has the seats the booking gave up offered to the trip's waitlist.
+// The booking change is already saved, so a failure is only logged.
+func (s *bookingServiceImpl) offerReleasedSeats(ctx context.Context, booking *model.Booking) {
+	if err := scheduleWaitlistOffers(ctx, s.delayedQueue, booking.TripID, time.Now()); err != nil {
+		log.Error().Err(err).
+			Str("booking_id", booking.ID.String()).
+			Str("trip_id", booking.TripID.String()).
+			Msg("Failed to schedule waitlist offers for released seats")
+	}
+}
+
+// GetActiveTripBookings returns every pending or confirmed booking of a trip, unpaginated
+func (s *bookingServiceImpl) GetActiveTripBookings(ctx context.Context, tripID uuid.UUID) ([]*model.BookingResponse, error) {
+	bookings, err := s.bookingRepo.GetAllActiveBookingsByTripID(ctx, tripID)
+	if err != nil {
+		return nil, ginext.NewInternalServerError("failed to get trip bookings")
+	}
+
+	responses := make([]*model.BookingResponse, len(bookings))
+	for i, booking := range bookings {
+		responses[i] = toBookingResponse(booking)
+	}
+	return responses, nil
+}
+
+func toBookingResponse(booking *model.Booking) *model.BookingResponse {
 	resp := &model.BookingResponse{
 		ID:                booking.ID,
 		CreatedAt:         booking.CreatedAt,
@@ -647,13 +1223,22 @@ func (s *bookingServiceImpl) toBookingResponse(booking *model.Booking) *model.Bo
 		TripID:            booking.TripID,
 		UserID:            booking.UserID,
 		TotalAmount:       booking.TotalAmount,
+		PromoCode:         booking.PromoCode,
+		DiscountAmount:    booking.DiscountAmount,
 		Status:            booking.Status,
 		TransactionStatus: booking.TransactionStatus,
 		TransactionID:     booking.TransactionID,
+		PaymentMethod:     booking.PaymentMethod,
 		Notes:             booking.Notes,
+		PickupStopID:      booking.PickupStopID,
+		DropoffStopID:     booking.DropoffStopID,
+		PickupLocation:    booking.PickupLocation,
+		DropoffLocation:   booking.DropoffLocation,
 		ExpiresAt:         booking.ExpiresAt,
 		ConfirmedAt:       booking.ConfirmedAt,
 		CancelledAt:       booking.CancelledAt,
+		ItineraryID:       booking.ItineraryID,
+		LegNumber:         booking.LegNumber,
 	}
 
 	// Map seats
@@ -666,19 +1251,51 @@ func (s *bookingServiceImpl) toBookingResponse(booking *model.Booking) *model.Bo
 			Floor:           seat.Floor,
 			Price:           seat.Price,
 			PriceMultiplier: seat.PriceMultiplier,
+			IsBoarded:       seat.IsBoarded,
+			BoardedAt:       seat.BoardedAt,
+
+			PassengerName:        seat.PassengerName,
+			PassengerPhone:       seat.PassengerPhone,
+			PassengerIDNumber:    seat.PassengerIDNumber,
+			PassengerAgeCategory: seat.PassengerAgeCategory,
 		})
 	}
 
 	return resp
 }
 
-func (s *bookingServiceImpl) GetSeatStatus(ctx context.Context, tripID uuid.UUID, seatIDs []uuid.UUID) ([]model.SeatStatusItem, error) {
+// toSeatPassengers lists who travels on each seat, falling back to the booker
+// for seats booked without a named passenger
+func toSeatPassengers(seats []model.BookingSeat, booker *user.User) []model.SeatPassengerResponse {
+	passengers := make([]model.SeatPassengerResponse, 0, len(seats))
+	for _, seat := range seats {
+		passenger := model.SeatPassengerResponse{
+			SeatID:      seat.SeatID,
+			SeatNumber:  seat.SeatNumber,
+			FullName:    seat.PassengerName,
+			Phone:       seat.PassengerPhone,
+			IDNumber:    seat.PassengerIDNumber,
+			AgeCategory: seat.PassengerAgeCategory,
+			IsBoarded:   seat.IsBoarded,
+		}
+		if passenger.FullName == "" {
+			passenger.FullName = booker.FullName
+			if passenger.Phone == "" {
+				passenger.Phone = booker.Phone
+			}
+		}
+		passengers = append(passengers, passenger)
+	}
+	return passengers
+}
+
+func (s *bookingServiceImpl) GetSeatStatus(ctx context.Context, tripID uuid.UUID, seatIDs []uuid.UUID, segment model.TripSegment) ([]model.SeatStatusItem, error) {
 	if len(seatIDs) == 0 {
 		return []model.SeatStatusItem{}, nil
 	}
 
-	// Get booked seats
-	bookedSeatIDs, err := s.bookingRepo.GetBookedSeatIDs(ctx, tripID)
+	// Get seats booked on overlapping segments
+	bookedSeatIDs, err := s.bookingRepo.GetBookedSeatIDs(ctx, tripID, segment)
 	if err != nil {
 		return nil, ginext.NewInternalServerError(fmt.Sprintf("failed to get booked seats: %v", err))
 	}
@@ -741,6 +1358,7 @@ func (s *bookingServiceImpl) ExpireBooking(ctx context.Context, bookingID uuid.U
 
 			// Schedule to execute right after grace period
 			item := &queue.DelayedItem{
+				ID:      booking.ID.String(), // one pending expiry per booking
 				Payload: booking.ID,
 			}
 			if err := s.delayedQueue.Schedule(ctx, constants.QueueNameBookingExpiry, item, graceDeadline); err != nil {
@@ -755,32 +1373,29 @@ func (s *bookingServiceImpl) ExpireBooking(ctx context.Context, bookingID uuid.U
 		}
 	}
 
+	if booking.ItineraryID != nil {
+		return s.expireItinerary(ctx, booking)
+	}
+
 	// 3. Grace period has passed - Update status to Expired
+	reason := expiryReason(booking)
 	booking.Status = model.BookingStatusExpired
 	booking.TransactionStatus = payment.TransactionStatusExpired
 	now := time.Now().UTC()
 	booking.UpdatedAt = now
 
-	// 4. Try to cancel payment transaction
-	if booking.TransactionID != uuid.Nil {
-		_, err := s.paymentClient.CancelTransaction(ctx, booking.TransactionID)
-		if err != nil {
-			// Log error but don't fail expiration - transaction will expire on PayOS side
-			log.Warn().Err(err).
-				Str("booking_id", booking.ID.String()).
-				Str("transaction_id", booking.TransactionID.String()).
-				Msg("Failed to cancel transaction, but booking will still expire")
-		} else {
-			log.Info().
-				Str("booking_id", booking.ID.String()).
-				Str("transaction_id", booking.TransactionID.String()).
-				Msg("Successfully cancelled transaction")
-		}
+	// 4. Cancel the payment transaction through the outbox, so it is cancelled, and a partial
+	// payment refunded, even when payment service is down right now
+	events, err := newPaymentCancelDueEvents(booking)
+	if err != nil {
+		return err
 	}
 
-	if err := s.bookingRepo.UpdateBooking(ctx, booking); err != nil {
+	if err := s.bookingRepo.UpdateBookingsWithEvents(ctx, []*model.Booking{booking}, events...); err != nil {
 		return fmt.Errorf("failed to expire booking: %w", err)
 	}
+	s.releasePromotion(ctx, booking)
+	s.offerReleasedSeats(ctx, booking)
 
 	log.Info().
 		Str("booking_id", booking.ID.String()).
@@ -791,12 +1406,120 @@ func (s *bookingServiceImpl) ExpireBooking(ctx context.Context, bookingID uuid.U
 	go func() {
 		bgCtx, cancel := context.WithTimeout(context.Background(), constants.BackgroundTaskTimeout)
 		defer cancel()
-		s.sendBookingFailureEmail(bgCtx, bookingID, "Hết hạn thanh toán")
+		s.sendBookingFailureEmail(bgCtx, bookingID, reason)
 	}()
 
 	return nil
 }
 
+// expireItinerary expires the unpaid legs of an itinerary together and cancels their combined payment
+func (s *bookingServiceImpl) expireItinerary(ctx context.Context, lead *model.Booking) error {
+	legs, err := s.paymentLegs(ctx, lead)
+	if err != nil {
+		return err
+	}
+
+	reason := expiryReason(lead)
+	expired := make([]*model.Booking, 0, len(legs))
+	for _, leg := range legs {
+		if leg.Status != model.BookingStatusPending {
+			continue
+		}
+		leg.Status = model.BookingStatusExpired
+		leg.TransactionStatus = payment.TransactionStatusExpired
+		expired = append(expired, leg)
+	}
+
+	events, err := newPaymentCancelDueEvents(lead)
+	if err != nil {
+		return err
+	}
+
+	if err := s.bookingRepo.UpdateBookingsWithEvents(ctx, expired, events...); err != nil {
+		return fmt.Errorf("failed to expire itinerary bookings: %w", err)
+	}
+
+	for _, leg := range expired {
+		s.releasePromotion(ctx, leg)
+		s.offerReleasedSeats(ctx, leg)
+
+		legID := leg.ID
+		go func() {
+			bgCtx, cancel := context.WithTimeout(context.Background(), constants.BackgroundTaskTimeout)
+			defer cancel()
+			s.sendBookingFailureEmail(bgCtx, legID, reason)
+		}()
+	}
+
+	log.Info().
+		Str("itinerary_id", lead.ItineraryID.String()).
+		Int("expired_legs", len(expired)).
+		Msg("Successfully expired itinerary after grace period")
+	return nil
+}
+
+// newPaymentCancelDueEvents queues the cancellation of the payment of an expired booking, shared by
+// every leg of an itinerary. A booking without a payment queues nothing.
+func newPaymentCancelDueEvents(booking *model.Booking) ([]*outbox.Event, error) {
+	if booking.TransactionID == uuid.Nil {
+		return nil, nil
+	}
+
+	event, err := outbox.NewEvent(model.AggregateTypeBooking, booking.ID, model.EventTypePaymentCancelDue, &model.PaymentCancelDueEvent{
+		BookingID:     booking.ID,
+		TransactionID: booking.TransactionID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return []*outbox.Event{event}, nil
+}
+
+// CancelExpiredPayment is the outbox handler cancelling the payment of an expired booking. A payment
+// that was already cancelled, expired at its provider or paid in the meantime is left as it is.
+func (s *bookingServiceImpl) CancelExpiredPayment(ctx context.Context, event *outbox.Event) error {
+	var payload model.PaymentCancelDueEvent
+	if err := event.Decode(&payload); err != nil {
+		return outbox.Permanent(err)
+	}
+
+	transaction, err := s.paymentClient.GetTransactionByID(ctx, payload.TransactionID)
+	if err != nil {
+		return err
+	}
+
+	switch transaction.Status {
+	case payment.TransactionStatusCancelled, payment.TransactionStatusExpired, payment.TransactionStatusFailed:
+		return nil
+	case payment.TransactionStatusPaid:
+		// Paid during the grace period, the payment event settles the booking
+		log.Warn().
+			Str("booking_id", payload.BookingID.String()).
+			Str("transaction_id", payload.TransactionID.String()).
+			Msg("Payment of expired booking was paid, not cancelling it")
+		return nil
+	}
+
+	if _, err := s.paymentClient.CancelTransaction(ctx, payload.TransactionID); err != nil {
+		return err
+	}
+
+	log.Info().
+		Str("booking_id", payload.BookingID.String()).
+		Str("transaction_id", payload.TransactionID.String()).
+		Msg("Successfully cancelled payment of expired booking")
+	return nil
+}
+
+// expiryReason tells the passenger why the booking expired.
+// Cancelling an underpaid transaction has payment service refund the partial payment.
+func expiryReason(booking *model.Booking) string {
+	if booking.TransactionStatus == payment.TransactionStatusUnderpaid {
+		return "Hết hạn thanh toán, số tiền bạn đã chuyển sẽ được hoàn lại"
+	}
+	return "Hết hạn thanh toán"
+}
+
 func (s *bookingServiceImpl) GetTripPassengers(ctx context.Context, tripID uuid.UUID) ([]model.PassengerResponse, error) {
 	// 1. Get all active bookings for the trip
 	bookings, err := s.bookingRepo.GetAllActiveBookingsByTripID(ctx, tripID)
@@ -852,6 +1575,7 @@ func (s *bookingServiceImpl) GetTripPassengers(ctx context.Context, tripID uuid.
 				BookingReference: booking.BookingReference,
 				Status:           string(booking.Status),
 				Seats:            seats,
+				Passengers:       toSeatPassengers(booking.BookingSeats, userData),
 				OriginalPrice:    booking.TotalAmount,
 				PaidPrice:        int(paidPrice),
 				IsBoarded:        booking.IsBoarded,
@@ -867,6 +1591,63 @@ func (s *bookingServiceImpl) GetTripPassengers(ctx context.Context, tripID uuid.
 	return passengerResps, nil
 }
 
+// GetTripManifest lists every occupied seat of a trip with the passenger travelling on it
+func (s *bookingServiceImpl) GetTripManifest(ctx context.Context, tripID uuid.UUID) (*model.TripManifestResponse, error) {
+	tripData, err := s.tripClient.GetTripByID(ctx, trip.GetTripByIDRequest{
+		PreLoadRoute: true,
+		PreloadBus:   true,
+	}, tripID)
+	if err != nil {
+		return nil, ginext.NewInternalServerError(fmt.Sprintf("failed to get trip data: %v", err))
+	}
+	if tripData == nil {
+		return nil, ginext.NewNotFoundError("trip not found")
+	}
+
+	passengers, err := s.GetTripPassengers(ctx, tripID)
+	if err != nil {
+		return nil, err
+	}
+
+	manifest := &model.TripManifestResponse{
+		TripID:        tripData.ID,
+		DepartureTime: tripData.DepartureTime,
+		Entries:       []model.TripManifestEntry{},
+	}
+	if tripData.Route != nil {
+		manifest.Origin = tripData.Route.Origin
+		manifest.Destination = tripData.Route.Destination
+	}
+	if tripData.Bus != nil {
+		manifest.BusPlateNumber = tripData.Bus.PlateNumber
+	}
+
+	for _, booking := range passengers {
+		for _, passenger := range booking.Passengers {
+			manifest.Entries = append(manifest.Entries, model.TripManifestEntry{
+				SeatNumber:       passenger.SeatNumber,
+				FullName:         passenger.FullName,
+				Phone:            passenger.Phone,
+				IDNumber:         passenger.IDNumber,
+				AgeCategory:      passenger.AgeCategory,
+				BookingID:        booking.BookingID,
+				BookingReference: booking.BookingReference,
+				IsBoarded:        passenger.IsBoarded,
+			})
+			if passenger.IsBoarded {
+				manifest.BoardedCount++
+			}
+		}
+	}
+
+	sort.Slice(manifest.Entries, func(i, j int) bool {
+		return manifest.Entries[i].SeatNumber < manifest.Entries[j].SeatNumber
+	})
+	manifest.TotalPassengers = len(manifest.Entries)
+
+	return manifest, nil
+}
+
 func (s *bookingServiceImpl) CheckInPassenger(ctx context.Context, bookingID uuid.UUID) error {
 	booking, err := s.bookingRepo.GetBookingByID(ctx, bookingID)
 	if err != nil {
@@ -928,7 +1709,7 @@ func (s *bookingServiceImpl) ListBookings(ctx context.Context, req model.ListBoo
 
 	// Build responses with trip data
 	for i, booking := range bookings {
-		resp := s.toBookingResponse(booking)
+		resp := toBookingResponse(booking)
 
 		// Add trip info if available
 		if tripData, ok := tripDataMap[booking.TripID]; ok {
@@ -1065,6 +1846,60 @@ func (s *bookingServiceImpl) sendBookingFailureEmail(ctx context.Context, bookin
 	}
 }
 
+// sendPaymentUnderpaidEmail asks the passenger to transfer the rest of a short payment through its payment link.
+// amountDue is what the payment covers, the whole itinerary for its legs.
+func (s *bookingServiceImpl) sendPaymentUnderpaidEmail(ctx context.Context, bookingID uuid.UUID, amountDue, amountPaid int) {
+	booking, err := s.bookingRepo.GetBookingByID(ctx, bookingID)
+	if err != nil {
+		log.Error().Err(err).Str("booking_id", bookingID.String()).Msg("Failed to get booking for payment underpaid email")
+		return
+	}
+
+	user, err := s.userClient.GetUserByID(ctx, booking.UserID)
+	if err != nil {
+		log.Error().Err(err).Str("user_id", booking.UserID.String()).Msg("Failed to get user for payment underpaid email")
+		return
+	}
+
+	tripData, err := s.tripClient.GetTripByID(ctx, trip.GetTripByIDRequest{
+		PreLoadRoute: true,
+	}, booking.TripID)
+	if err != nil || tripData.Route == nil {
+		log.Error().Err(err).Str("booking_id", bookingID.String()).Msg("Failed to get trip for payment underpaid email")
+		return
+	}
+
+	// The payment link stays open for the remaining amount
+	transaction, err := s.paymentClient.GetTransactionByID(ctx, booking.TransactionID)
+	if err != nil || transaction.CheckoutURL == "" {
+		log.Error().Err(err).Str("booking_id", bookingID.String()).Msg("Failed to get payment link for payment underpaid email")
+		return
+	}
+	if transaction.AmountPaid > amountPaid {
+		amountPaid = transaction.AmountPaid
+	}
+
+	req := &client.PaymentUnderpaidRequest{
+		Email:            user.Email,
+		Name:             user.FullName,
+		BookingReference: booking.BookingReference,
+		From:             tripData.Route.Origin,
+		To:               tripData.Route.Destination,
+		DepartureTime:    tripData.DepartureTime.Format(constants.DateTimeFormatDisplay),
+		TotalAmount:      amountDue,
+		AmountPaid:       amountPaid,
+		AmountRemaining:  amountDue - amountPaid,
+		PaymentLink:      transaction.CheckoutURL,
+	}
+	if booking.ExpiresAt != nil {
+		req.ExpiresAt = booking.ExpiresAt.Format(constants.DateTimeFormatDisplay)
+	}
+
+	if err := s.notificationClient.SendPaymentUnderpaid(ctx, req); err != nil {
+		log.Error().Err(err).Str("booking_id", bookingID.String()).Msg("Failed to send payment underpaid email")
+	}
+}
+
 func (s *bookingServiceImpl) getSeatNumbersResults(seats []model.BookingSeat) string {
 	var numbers string
 	for i, seat := range seats {
@@ -2,16 +2,23 @@ package service
 
 import (
 	"context"
+	"errors"
+	"net/http"
 	"testing"
 	"time"
 
+	"bus-booking/booking-service/internal/client"
 	"bus-booking/booking-service/internal/client/mocks"
+	"bus-booking/booking-service/internal/constants"
 	"bus-booking/booking-service/internal/model"
 	"bus-booking/booking-service/internal/model/payment"
 	"bus-booking/booking-service/internal/model/trip"
 	"bus-booking/booking-service/internal/model/user"
 	repo_mocks "bus-booking/booking-service/internal/repository/mocks"
 	service_mocks "bus-booking/booking-service/internal/service/mocks"
+	"bus-booking/shared/ginext"
+	"bus-booking/shared/outbox"
+	"bus-booking/shared/queue"
 	queue_mocks "bus-booking/shared/queue/mocks"
 
 	"github.com/golang/mock/gomock"
@@ -19,6 +26,12 @@ import (
 	"github.com/stretchr/testify/assert"
 )
 
+var testRefundTiers = []model.RefundTier{
+	{HoursBeforeDeparture: 24, RefundPercent: 70},
+	{HoursBeforeDeparture: 12, RefundPercent: 50},
+	{HoursBeforeDeparture: 6, RefundPercent: 30},
+}
+
 func TestNewBookingService(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -39,6 +52,9 @@ func TestNewBookingService(t *testing.T) {
 		mockNotificationClient,
 		mockDelayedQueue,
 		mockSeatLockService,
+		NewCancellationPolicyService(testRefundTiers),
+		service_mocks.NewMockBookingExchangeService(ctrl),
+		service_mocks.NewMockPromotionService(ctrl),
 	)
 
 	assert.NotNil(t, service)
@@ -65,6 +81,9 @@ func TestGetByID_NotFound(t *testing.T) {
 		mockNotificationClient,
 		mockDelayedQueue,
 		mockSeatLockService,
+		NewCancellationPolicyService(testRefundTiers),
+		service_mocks.NewMockBookingExchangeService(ctrl),
+		service_mocks.NewMockPromotionService(ctrl),
 	)
 
 	ctx := context.Background()
@@ -101,6 +120,9 @@ func TestGetByID_Success(t *testing.T) {
 		mockNotificationClient,
 		mockDelayedQueue,
 		mockSeatLockService,
+		NewCancellationPolicyService(testRefundTiers),
+		service_mocks.NewMockBookingExchangeService(ctrl),
+		service_mocks.NewMockPromotionService(ctrl),
 	)
 
 	ctx := context.Background()
@@ -159,6 +181,9 @@ func TestGetByID_TransactionError(t *testing.T) {
 		mockNotificationClient,
 		mockDelayedQueue,
 		mockSeatLockService,
+		NewCancellationPolicyService(testRefundTiers),
+		service_mocks.NewMockBookingExchangeService(ctrl),
+		service_mocks.NewMockPromotionService(ctrl),
 	)
 
 	ctx := context.Background()
@@ -208,6 +233,9 @@ func TestGetByReference_Success(t *testing.T) {
 		mockNotificationClient,
 		mockDelayedQueue,
 		mockSeatLockService,
+		NewCancellationPolicyService(testRefundTiers),
+		service_mocks.NewMockBookingExchangeService(ctrl),
+		service_mocks.NewMockPromotionService(ctrl),
 	)
 
 	ctx := context.Background()
@@ -251,6 +279,9 @@ func TestGetByReference_NotFound(t *testing.T) {
 		mockNotificationClient,
 		mockDelayedQueue,
 		mockSeatLockService,
+		NewCancellationPolicyService(testRefundTiers),
+		service_mocks.NewMockBookingExchangeService(ctrl),
+		service_mocks.NewMockPromotionService(ctrl),
 	)
 
 	ctx := context.Background()
@@ -288,6 +319,9 @@ func TestGetTripBookings_Success(t *testing.T) {
 		mockNotificationClient,
 		mockDelayedQueue,
 		mockSeatLockService,
+		NewCancellationPolicyService(testRefundTiers),
+		service_mocks.NewMockBookingExchangeService(ctrl),
+		service_mocks.NewMockPromotionService(ctrl),
 	)
 
 	ctx := context.Background()
@@ -310,36 +344,29 @@ func TestGetTripBookings_Success(t *testing.T) {
 	assert.Equal(t, int64(1), total)
 }
 
-func TestCalculateTotalPrice(t *testing.T) {
-	ctrl := gomock.NewController(t)
-	defer ctrl.Finish()
+func TestPriceSeats(t *testing.T) {
+	regularID, vipID := uuid.New(), uuid.New()
+	seats := []trip.Seat{
+		{ID: regularID, PriceMultiplier: 1.0},
+		{ID: vipID, PriceMultiplier: 1.5}, // VIP seat
+	}
+	fares := map[uuid.UUID]float64{regularID: 100000, vipID: 120000} // VIP held at a later, higher fare
 
-	mockBookingRepo := repo_mocks.NewMockBookingRepository(ctrl)
-	mockPaymentClient := mocks.NewMockPaymentClient(ctrl)
-	mockTripClient := mocks.NewMockTripClient(ctrl)
-	mockUserClient := mocks.NewMockUserClient(ctrl)
-	mockNotificationClient := mocks.NewMockNotificationClient(ctrl)
-	mockDelayedQueue := queue_mocks.NewMockDelayedQueueManager(ctrl)
-	mockSeatLockService := service_mocks.NewMockSeatLockService(ctrl)
+	prices, total := priceSeats(seats, fares, 1.0)
 
-	service := NewBookingService(
-		mockBookingRepo,
-		mockPaymentClient,
-		mockTripClient,
-		mockUserClient,
-		mockNotificationClient,
-		mockDelayedQueue,
-		mockSeatLockService,
-	).(*bookingServiceImpl)
+	assert.Equal(t, 100000.0, prices[regularID])
+	assert.Equal(t, 180000.0, prices[vipID])
+	assert.Equal(t, 280000, total)
+}
 
-	seats := []trip.Seat{
-		{PriceMultiplier: 1.0},
-		{PriceMultiplier: 1.5}, // VIP seat
-	}
+func TestPriceSeats_ProratesSegment(t *testing.T) {
+	seatID := uuid.New()
+	seats := []trip.Seat{{ID: seatID, PriceMultiplier: 1.0}}
 
-	total := service.calculateTotalPrice(100000, seats)
+	prices, total := priceSeats(seats, map[uuid.UUID]float64{seatID: 200000}, 0.5)
 
-	assert.Equal(t, 250000, total) // 100k + 150k
+	assert.Equal(t, prices[seatID], float64(total))
+	assert.Less(t, total, 200000)
 }
 
 func TestGenerateBookingReference(t *testing.T) {
@@ -362,6 +389,9 @@ func TestGenerateBookingReference(t *testing.T) {
 		mockNotificationClient,
 		mockDelayedQueue,
 		mockSeatLockService,
+		NewCancellationPolicyService(testRefundTiers),
+		service_mocks.NewMockBookingExchangeService(ctrl),
+		service_mocks.NewMockPromotionService(ctrl),
 	).(*bookingServiceImpl)
 
 	ref := service.generateBookingReference()
@@ -391,6 +421,9 @@ func TestGetSeatNumbersResults(t *testing.T) {
 		mockNotificationClient,
 		mockDelayedQueue,
 		mockSeatLockService,
+		NewCancellationPolicyService(testRefundTiers),
+		service_mocks.NewMockBookingExchangeService(ctrl),
+		service_mocks.NewMockPromotionService(ctrl),
 	).(*bookingServiceImpl)
 
 	seats := []model.BookingSeat{
@@ -407,27 +440,6 @@ func TestGetSeatNumbersResults(t *testing.T) {
 }
 
 func TestToBookingResponse(t *testing.T) {
-	ctrl := gomock.NewController(t)
-	defer ctrl.Finish()
-
-	mockBookingRepo := repo_mocks.NewMockBookingRepository(ctrl)
-	mockPaymentClient := mocks.NewMockPaymentClient(ctrl)
-	mockTripClient := mocks.NewMockTripClient(ctrl)
-	mockUserClient := mocks.NewMockUserClient(ctrl)
-	mockNotificationClient := mocks.NewMockNotificationClient(ctrl)
-	mockDelayedQueue := queue_mocks.NewMockDelayedQueueManager(ctrl)
-	mockSeatLockService := service_mocks.NewMockSeatLockService(ctrl)
-
-	service := NewBookingService(
-		mockBookingRepo,
-		mockPaymentClient,
-		mockTripClient,
-		mockUserClient,
-		mockNotificationClient,
-		mockDelayedQueue,
-		mockSeatLockService,
-	).(*bookingServiceImpl)
-
 	bookingID := uuid.New()
 	userID := uuid.New()
 	tripID := uuid.New()
@@ -446,7 +458,7 @@ func TestToBookingResponse(t *testing.T) {
 		},
 	}
 
-	result := service.toBookingResponse(booking)
+	result := toBookingResponse(booking)
 
 	assert.NotNil(t, result)
 	assert.Equal(t, bookingID, result.ID)
@@ -478,6 +490,9 @@ func TestCheckSeatAvailability_AllAvailable(t *testing.T) {
 		mockNotificationClient,
 		mockDelayedQueue,
 		mockSeatLockService,
+		NewCancellationPolicyService(testRefundTiers),
+		service_mocks.NewMockBookingExchangeService(ctrl),
+		service_mocks.NewMockPromotionService(ctrl),
 	).(*bookingServiceImpl)
 
 	ctx := context.Background()
@@ -486,11 +501,11 @@ func TestCheckSeatAvailability_AllAvailable(t *testing.T) {
 
 	// No booked seats
 	mockBookingRepo.EXPECT().
-		GetBookedSeatIDs(ctx, tripID).
+		GetBookedSeatIDs(ctx, tripID, model.FullTripSegment()).
 		Return([]uuid.UUID{}, nil).
 		Times(1)
 
-	available, err := service.checkSeatAvailability(ctx, tripID, seatIDs)
+	available, err := service.checkSeatAvailability(ctx, tripID, seatIDs, model.FullTripSegment())
 
 	assert.NoError(t, err)
 	assert.True(t, available)
@@ -516,6 +531,9 @@ func TestCheckSeatAvailability_SeatTaken(t *testing.T) {
 		mockNotificationClient,
 		mockDelayedQueue,
 		mockSeatLockService,
+		NewCancellationPolicyService(testRefundTiers),
+		service_mocks.NewMockBookingExchangeService(ctrl),
+		service_mocks.NewMockPromotionService(ctrl),
 	).(*bookingServiceImpl)
 
 	ctx := context.Background()
@@ -525,11 +543,11 @@ func TestCheckSeatAvailability_SeatTaken(t *testing.T) {
 
 	// Seat already booked
 	mockBookingRepo.EXPECT().
-		GetBookedSeatIDs(ctx, tripID).
+		GetBookedSeatIDs(ctx, tripID, model.FullTripSegment()).
 		Return([]uuid.UUID{seatID}, nil).
 		Times(1)
 
-	available, err := service.checkSeatAvailability(ctx, tripID, seatIDs)
+	available, err := service.checkSeatAvailability(ctx, tripID, seatIDs, model.FullTripSegment())
 
 	assert.NoError(t, err)
 	assert.False(t, available)
@@ -555,6 +573,9 @@ func TestCheckSeatAvailability_RepositoryError(t *testing.T) {
 		mockNotificationClient,
 		mockDelayedQueue,
 		mockSeatLockService,
+		NewCancellationPolicyService(testRefundTiers),
+		service_mocks.NewMockBookingExchangeService(ctrl),
+		service_mocks.NewMockPromotionService(ctrl),
 	).(*bookingServiceImpl)
 
 	ctx := context.Background()
@@ -562,11 +583,11 @@ func TestCheckSeatAvailability_RepositoryError(t *testing.T) {
 	seatIDs := []uuid.UUID{uuid.New()}
 
 	mockBookingRepo.EXPECT().
-		GetBookedSeatIDs(ctx, tripID).
+		GetBookedSeatIDs(ctx, tripID, model.FullTripSegment()).
 		Return(nil, assert.AnError).
 		Times(1)
 
-	available, err := service.checkSeatAvailability(ctx, tripID, seatIDs)
+	available, err := service.checkSeatAvailability(ctx, tripID, seatIDs, model.FullTripSegment())
 
 	assert.Error(t, err)
 	assert.False(t, available)
@@ -592,6 +613,9 @@ func TestGetSeatStatus_Success(t *testing.T) {
 		mockNotificationClient,
 		mockDelayedQueue,
 		mockSeatLockService,
+		NewCancellationPolicyService(testRefundTiers),
+		service_mocks.NewMockBookingExchangeService(ctrl),
+		service_mocks.NewMockPromotionService(ctrl),
 	)
 
 	ctx := context.Background()
@@ -603,7 +627,7 @@ func TestGetSeatStatus_Success(t *testing.T) {
 
 	// Mock booked seats
 	mockBookingRepo.EXPECT().
-		GetBookedSeatIDs(ctx, tripID).
+		GetBookedSeatIDs(ctx, tripID, model.FullTripSegment()).
 		Return([]uuid.UUID{seat1}, nil).
 		Times(1)
 
@@ -613,7 +637,7 @@ func TestGetSeatStatus_Success(t *testing.T) {
 		Return([]uuid.UUID{seat2}, nil).
 		Times(1)
 
-	result, err := service.GetSeatStatus(ctx, tripID, seatIDs)
+	result, err := service.GetSeatStatus(ctx, tripID, seatIDs, model.FullTripSegment())
 
 	assert.NoError(t, err)
 	assert.Len(t, result, 3)
@@ -645,12 +669,15 @@ func TestGetSeatStatus_EmptySeats(t *testing.T) {
 		mockNotificationClient,
 		mockDelayedQueue,
 		mockSeatLockService,
+		NewCancellationPolicyService(testRefundTiers),
+		service_mocks.NewMockBookingExchangeService(ctrl),
+		service_mocks.NewMockPromotionService(ctrl),
 	)
 
 	ctx := context.Background()
 	tripID := uuid.New()
 
-	result, err := service.GetSeatStatus(ctx, tripID, []uuid.UUID{})
+	result, err := service.GetSeatStatus(ctx, tripID, []uuid.UUID{}, model.FullTripSegment())
 
 	assert.NoError(t, err)
 	assert.Empty(t, result)
@@ -676,6 +703,9 @@ func TestGetSeatStatus_RepositoryError(t *testing.T) {
 		mockNotificationClient,
 		mockDelayedQueue,
 		mockSeatLockService,
+		NewCancellationPolicyService(testRefundTiers),
+		service_mocks.NewMockBookingExchangeService(ctrl),
+		service_mocks.NewMockPromotionService(ctrl),
 	)
 
 	ctx := context.Background()
@@ -683,11 +713,11 @@ func TestGetSeatStatus_RepositoryError(t *testing.T) {
 	seatIDs := []uuid.UUID{uuid.New()}
 
 	mockBookingRepo.EXPECT().
-		GetBookedSeatIDs(ctx, tripID).
+		GetBookedSeatIDs(ctx, tripID, model.FullTripSegment()).
 		Return(nil, assert.AnError).
 		Times(1)
 
-	result, err := service.GetSeatStatus(ctx, tripID, seatIDs)
+	result, err := service.GetSeatStatus(ctx, tripID, seatIDs, model.FullTripSegment())
 
 	assert.Error(t, err)
 	assert.Nil(t, result)
@@ -713,6 +743,9 @@ func TestGetUserBookings_WithTrips(t *testing.T) {
 		mockNotificationClient,
 		mockDelayedQueue,
 		mockSeatLockService,
+		NewCancellationPolicyService(testRefundTiers),
+		service_mocks.NewMockBookingExchangeService(ctrl),
+		service_mocks.NewMockPromotionService(ctrl),
 	)
 
 	ctx := context.Background()
@@ -808,6 +841,9 @@ func TestCreateGuestBooking_ValidationError(t *testing.T) {
 		mockNotificationClient,
 		mockDelayedQueue,
 		mockSeatLockService,
+		NewCancellationPolicyService(testRefundTiers),
+		service_mocks.NewMockBookingExchangeService(ctrl),
+		service_mocks.NewMockPromotionService(ctrl),
 	)
 
 	ctx := context.Background()
@@ -843,6 +879,9 @@ func TestCreateGuestBooking_UserClientError(t *testing.T) {
 		mockNotificationClient,
 		mockDelayedQueue,
 		mockSeatLockService,
+		NewCancellationPolicyService(testRefundTiers),
+		service_mocks.NewMockBookingExchangeService(ctrl),
+		service_mocks.NewMockPromotionService(ctrl),
 	)
 
 	ctx := context.Background()
@@ -863,7 +902,7 @@ func TestCreateGuestBooking_UserClientError(t *testing.T) {
 	assert.Contains(t, err.Error(), "không thể tạo tài khoản khách")
 }
 
-func TestCancelBooking_AlreadyCancelled(t *testing.T) {
+func TestCreateGuestBooking_ConvertsHeldSeats(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
@@ -871,8 +910,6 @@ func TestCancelBooking_AlreadyCancelled(t *testing.T) {
 	mockPaymentClient := mocks.NewMockPaymentClient(ctrl)
 	mockTripClient := mocks.NewMockTripClient(ctrl)
 	mockUserClient := mocks.NewMockUserClient(ctrl)
-	mockNotificationClient := mocks.NewMockNotificationClient(ctrl)
-	mockDelayedQueue := queue_mocks.NewMockDelayedQueueManager(ctrl)
 	mockSeatLockService := service_mocks.NewMockSeatLockService(ctrl)
 
 	service := NewBookingService(
@@ -880,31 +917,126 @@ func TestCancelBooking_AlreadyCancelled(t *testing.T) {
 		mockPaymentClient,
 		mockTripClient,
 		mockUserClient,
-		mockNotificationClient,
-		mockDelayedQueue,
+		mocks.NewMockNotificationClient(ctrl),
+		queue_mocks.NewMockDelayedQueueManager(ctrl),
 		mockSeatLockService,
+		NewCancellationPolicyService(testRefundTiers),
+		service_mocks.NewMockBookingExchangeService(ctrl),
+		service_mocks.NewMockPromotionService(ctrl),
 	)
 
 	ctx := context.Background()
-	bookingID := uuid.New()
+	tripID := uuid.New()
+	seatID := uuid.New()
+	guestID := uuid.New()
 
-	booking := &model.Booking{
-		BaseModel: model.BaseModel{ID: bookingID},
-		Status:    model.BookingStatusCancelled,
+	req := &model.CreateGuestBookingRequest{
+		CreateBookingRequest: model.CreateBookingRequest{
+			TripID:    tripID,
+			SeatIDs:   []uuid.UUID{seatID},
+			SessionID: "guest-session",
+		},
+		FullName: "Guest User",
+		Email:    "guest@example.com",
+	}
+
+	mockUserClient.EXPECT().CreateGuest(ctx, gomock.Any()).Return(&user.GuestResponse{ID: guestID}, nil)
+	mockBookingRepo.EXPECT().GetBookedSeatIDs(ctx, tripID, model.FullTripSegment()).Return([]uuid.UUID{}, nil)
+	mockTripClient.EXPECT().GetTripByID(gomock.Any(), gomock.Any(), tripID).Return(&trip.Trip{ID: tripID, BasePrice: 100000}, nil)
+	mockTripClient.EXPECT().ListSeatsByIDs(gomock.Any(), gomock.Any()).Return([]trip.Seat{
+		{ID: seatID, SeatNumber: "A1", PriceMultiplier: 1.0},
+	}, nil)
+	// The guest is charged the fare held by the session
+	mockSeatLockService.EXPECT().
+		QuoteSeatFares(gomock.Any(), tripID, "guest-session", req.SeatIDs).
+		Return(map[uuid.UUID]float64{seatID: 120000}, nil)
+
+	// The seats held while choosing them become the booking's seats
+	mockBookingRepo.EXPECT().
+		CreateBookingFromHold(ctx, gomock.Any(), "guest-session", model.FullTripSegment()).
+		DoAndReturn(func(_ context.Context, booking *model.Booking, _ string, _ model.TripSegment) error {
+			assert.Equal(t, guestID, booking.UserID)
+			assert.Equal(t, 120000, booking.TotalAmount)
+			return nil
+		})
+	mockPaymentClient.EXPECT().CreateTransaction(ctx, gomock.Any()).Return(nil, assert.AnError)
+	mockBookingRepo.EXPECT().UpdateBooking(ctx, gomock.Any()).Return(nil)
+
+	result, err := service.CreateGuestBooking(ctx, req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 120000, result.TotalAmount)
+}
+
+func TestCreateGuestBooking_PaysAtCounter(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockBookingRepo := repo_mocks.NewMockBookingRepository(ctrl)
+	mockPaymentClient := mocks.NewMockPaymentClient(ctrl)
+	mockTripClient := mocks.NewMockTripClient(ctrl)
+	mockUserClient := mocks.NewMockUserClient(ctrl)
+	mockSeatLockService := service_mocks.NewMockSeatLockService(ctrl)
+
+	service := NewBookingService(
+		mockBookingRepo,
+		mockPaymentClient,
+		mockTripClient,
+		mockUserClient,
+		mocks.NewMockNotificationClient(ctrl),
+		queue_mocks.NewMockDelayedQueueManager(ctrl),
+		mockSeatLockService,
+		NewCancellationPolicyService(testRefundTiers),
+		service_mocks.NewMockBookingExchangeService(ctrl),
+		service_mocks.NewMockPromotionService(ctrl),
+	)
+
+	ctx := context.Background()
+	tripID := uuid.New()
+	seatID := uuid.New()
+	guestID := uuid.New()
+
+	req := &model.CreateGuestBookingRequest{
+		CreateBookingRequest: model.CreateBookingRequest{
+			TripID:        tripID,
+			SeatIDs:       []uuid.UUID{seatID},
+			SessionID:     "guest-session",
+			PaymentMethod: payment.PaymentMethodCash,
+		},
+		FullName: "Guest User",
+		Phone:    "0901234567",
 	}
 
+	mockUserClient.EXPECT().CreateGuest(ctx, gomock.Any()).Return(&user.GuestResponse{ID: guestID}, nil)
+	mockBookingRepo.EXPECT().GetBookedSeatIDs(ctx, tripID, model.FullTripSegment()).Return([]uuid.UUID{}, nil)
+	mockTripClient.EXPECT().GetTripByID(gomock.Any(), gomock.Any(), tripID).Return(&trip.Trip{ID: tripID, BasePrice: 100000}, nil)
+	mockTripClient.EXPECT().ListSeatsByIDs(gomock.Any(), gomock.Any()).Return([]trip.Seat{
+		{ID: seatID, SeatNumber: "A1", PriceMultiplier: 1.0},
+	}, nil)
+	mockSeatLockService.EXPECT().
+		QuoteSeatFares(gomock.Any(), tripID, "guest-session", req.SeatIDs).
+		Return(map[uuid.UUID]float64{seatID: 100000}, nil)
 	mockBookingRepo.EXPECT().
-		GetBookingByID(ctx, bookingID).
-		Return(booking, nil).
-		Times(1)
+		CreateBookingFromHold(ctx, gomock.Any(), "guest-session", model.FullTripSegment()).
+		DoAndReturn(func(_ context.Context, booking *model.Booking, _ string, _ model.TripSegment) error {
+			assert.Equal(t, payment.PaymentMethodCash, booking.PaymentMethod)
+			return nil
+		})
+	// The guest's choice reaches payment service instead of falling back to PAYOS
+	mockPaymentClient.EXPECT().CreateTransaction(ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, req *payment.CreateTransactionRequest) (*payment.TransactionResponse, error) {
+			assert.Equal(t, payment.PaymentMethodCash, req.PaymentMethod)
+			return nil, assert.AnError
+		})
+	mockBookingRepo.EXPECT().UpdateBooking(ctx, gomock.Any()).Return(nil)
 
-	err := service.CancelBooking(ctx, bookingID, "test")
+	result, err := service.CreateGuestBooking(ctx, req)
 
-	assert.Error(t, err)
-	assert.Contains(t, err.Error(), "already cancelled")
+	assert.NoError(t, err)
+	assert.Equal(t, payment.PaymentMethodCash, result.PaymentMethod)
 }
 
-func TestCancelBooking_CannotCancelConfirmed(t *testing.T) {
+func TestCancelBooking_AlreadyCancelled(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
@@ -924,6 +1056,9 @@ func TestCancelBooking_CannotCancelConfirmed(t *testing.T) {
 		mockNotificationClient,
 		mockDelayedQueue,
 		mockSeatLockService,
+		NewCancellationPolicyService(testRefundTiers),
+		service_mocks.NewMockBookingExchangeService(ctrl),
+		service_mocks.NewMockPromotionService(ctrl),
 	)
 
 	ctx := context.Background()
@@ -931,7 +1066,7 @@ func TestCancelBooking_CannotCancelConfirmed(t *testing.T) {
 
 	booking := &model.Booking{
 		BaseModel: model.BaseModel{ID: bookingID},
-		Status:    model.BookingStatusConfirmed,
+		Status:    model.BookingStatusCancelled,
 	}
 
 	mockBookingRepo.EXPECT().
@@ -942,10 +1077,10 @@ func TestCancelBooking_CannotCancelConfirmed(t *testing.T) {
 	err := service.CancelBooking(ctx, bookingID, "test")
 
 	assert.Error(t, err)
-	assert.Contains(t, err.Error(), "cannot cancel confirmed")
+	assert.Contains(t, err.Error(), "already cancelled")
 }
 
-func TestCancelBooking_Success(t *testing.T) {
+func TestCancelBooking_Confirmed_RefundsByPolicy(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
@@ -965,62 +1100,68 @@ func TestCancelBooking_Success(t *testing.T) {
 		mockNotificationClient,
 		mockDelayedQueue,
 		mockSeatLockService,
+		NewCancellationPolicyService(testRefundTiers),
+		service_mocks.NewMockBookingExchangeService(ctrl),
+		service_mocks.NewMockPromotionService(ctrl),
 	)
 
 	ctx := context.Background()
 	bookingID := uuid.New()
-	txID := uuid.New()
+	tripID := uuid.New()
+	seatID := uuid.New()
 
 	booking := &model.Booking{
-		BaseModel:     model.BaseModel{ID: bookingID},
-		Status:        model.BookingStatusPending,
-		TransactionID: txID,
-	}
-
-	cancelledTx := &payment.TransactionResponse{
-		ID:     txID,
-		Status: payment.TransactionStatusCancelled,
+		BaseModel:        model.BaseModel{ID: bookingID},
+		BookingReference: "BK251208AB12",
+		TripID:           tripID,
+		TotalAmount:      500000,
+		Status:           model.BookingStatusConfirmed,
+		BookingSeats: []model.BookingSeat{
+			{BaseModel: model.BaseModel{ID: seatID}, BookingID: bookingID},
+		},
 	}
 
-	updatedBooking := &model.Booking{
-		BaseModel:         model.BaseModel{ID: bookingID},
-		Status:            model.BookingStatusCancelled,
-		TransactionID:     txID,
-		TransactionStatus: payment.TransactionStatusPending,
+	tripData := &trip.Trip{
+		ID:            tripID,
+		DepartureTime: time.Now().UTC().Add(18 * time.Hour),
 	}
 
-	// Mock sequence
 	mockBookingRepo.EXPECT().
 		GetBookingByID(ctx, bookingID).
 		Return(booking, nil).
 		Times(1)
 
-	mockBookingRepo.EXPECT().
-		CancelBooking(ctx, bookingID, "test reason").
-		Return(nil).
+	mockTripClient.EXPECT().
+		GetTripByID(ctx, gomock.Any(), tripID).
+		Return(tripData, nil).
 		Times(1)
 
 	mockPaymentClient.EXPECT().
-		CancelTransaction(ctx, txID).
-		Return(cancelledTx, nil).
-		Times(1)
-
-	mockBookingRepo.EXPECT().
-		GetBookingByID(ctx, bookingID).
-		Return(updatedBooking, nil).
+		CreateRefund(ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, req *payment.RefundRequest) (*payment.RefundResponse, error) {
+			assert.Equal(t, bookingID, req.BookingID)
+			assert.Equal(t, 250000, req.RefundAmount) // 12h tier: 50%
+			assert.Equal(t, []uuid.UUID{seatID}, req.BookingSeatIDs)
+			return &payment.RefundResponse{ID: uuid.New(), RefundAmount: req.RefundAmount}, nil
+		}).
 		Times(1)
 
 	mockBookingRepo.EXPECT().
-		UpdateBooking(ctx, gomock.Any()).
+		CancelBooking(ctx, bookingID, "change of plans").
 		Return(nil).
 		Times(1)
 
-	err := service.CancelBooking(ctx, bookingID, "test reason")
+	// The released seats go to the trip's waitlist
+	mockDelayedQueue.EXPECT().
+		ScheduleEarliest(ctx, constants.QueueNameWaitlistOffer, gomock.Any(), gomock.Any()).
+		Return(nil)
+
+	err := service.CancelBooking(ctx, bookingID, "change of plans")
 
 	assert.NoError(t, err)
 }
 
-func TestCancelBooking_PaymentCancelFails(t *testing.T) {
+func TestCancelBooking_Confirmed_NoRefundInsideLastTier(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
@@ -1040,42 +1181,57 @@ func TestCancelBooking_PaymentCancelFails(t *testing.T) {
 		mockNotificationClient,
 		mockDelayedQueue,
 		mockSeatLockService,
+		NewCancellationPolicyService(testRefundTiers),
+		service_mocks.NewMockBookingExchangeService(ctrl),
+		service_mocks.NewMockPromotionService(ctrl),
 	)
 
 	ctx := context.Background()
 	bookingID := uuid.New()
-	txID := uuid.New()
+	tripID := uuid.New()
 
 	booking := &model.Booking{
-		BaseModel:     model.BaseModel{ID: bookingID},
-		Status:        model.BookingStatusPending,
-		TransactionID: txID,
+		BaseModel:   model.BaseModel{ID: bookingID},
+		TripID:      tripID,
+		TotalAmount: 500000,
+		Status:      model.BookingStatusConfirmed,
+	}
+
+	tripData := &trip.Trip{
+		ID:            tripID,
+		DepartureTime: time.Now().UTC().Add(2 * time.Hour),
 	}
 
-	// Mock sequence
 	mockBookingRepo.EXPECT().
 		GetBookingByID(ctx, bookingID).
 		Return(booking, nil).
 		Times(1)
 
+	mockTripClient.EXPECT().
+		GetTripByID(ctx, gomock.Any(), tripID).
+		Return(tripData, nil).
+		Times(1)
+
+	mockPaymentClient.EXPECT().
+		CreateRefund(gomock.Any(), gomock.Any()).
+		Times(0)
+
 	mockBookingRepo.EXPECT().
 		CancelBooking(ctx, bookingID, "test").
 		Return(nil).
 		Times(1)
 
-	// Payment cancellation fails
-	mockPaymentClient.EXPECT().
-		CancelTransaction(ctx, txID).
-		Return(nil, assert.AnError).
-		Times(1)
+	// The released seats go to the trip's waitlist
+	mockDelayedQueue.EXPECT().
+		ScheduleEarliest(ctx, constants.QueueNameWaitlistOffer, gomock.Any(), gomock.Any()).
+		Return(nil)
 
-	// Should still return nil (booking is cancelled despite payment error)
 	err := service.CancelBooking(ctx, bookingID, "test")
 
-	assert.NoError(t, err) // Function logs error but doesn't fail
+	assert.NoError(t, err)
 }
 
-func TestCancelBooking_GetBookingAfterCancelFails(t *testing.T) {
+func TestCancelBooking_Confirmed_AfterDeparture(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
@@ -1095,52 +1251,44 @@ func TestCancelBooking_GetBookingAfterCancelFails(t *testing.T) {
 		mockNotificationClient,
 		mockDelayedQueue,
 		mockSeatLockService,
+		NewCancellationPolicyService(testRefundTiers),
+		service_mocks.NewMockBookingExchangeService(ctrl),
+		service_mocks.NewMockPromotionService(ctrl),
 	)
 
 	ctx := context.Background()
 	bookingID := uuid.New()
-	txID := uuid.New()
+	tripID := uuid.New()
 
 	booking := &model.Booking{
-		BaseModel:     model.BaseModel{ID: bookingID},
-		Status:        model.BookingStatusPending,
-		TransactionID: txID,
+		BaseModel:   model.BaseModel{ID: bookingID},
+		TripID:      tripID,
+		TotalAmount: 500000,
+		Status:      model.BookingStatusConfirmed,
 	}
 
-	cancelledTx := &payment.TransactionResponse{
-		ID:     txID,
-		Status: payment.TransactionStatusCancelled,
+	tripData := &trip.Trip{
+		ID:            tripID,
+		DepartureTime: time.Now().UTC().Add(-1 * time.Hour),
 	}
 
-	// Mock sequence
 	mockBookingRepo.EXPECT().
 		GetBookingByID(ctx, bookingID).
 		Return(booking, nil).
 		Times(1)
 
-	mockBookingRepo.EXPECT().
-		CancelBooking(ctx, bookingID, "test").
-		Return(nil).
-		Times(1)
-
-	mockPaymentClient.EXPECT().
-		CancelTransaction(ctx, txID).
-		Return(cancelledTx, nil).
-		Times(1)
-
-	// Second GetBookingByID fails
-	mockBookingRepo.EXPECT().
-		GetBookingByID(ctx, bookingID).
-		Return(nil, assert.AnError).
+	mockTripClient.EXPECT().
+		GetTripByID(ctx, gomock.Any(), tripID).
+		Return(tripData, nil).
 		Times(1)
 
-	// Should log error but still return nil (booking is already cancelled)
 	err := service.CancelBooking(ctx, bookingID, "test")
 
-	assert.NoError(t, err) // Logs error but succeeds
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "after departure")
 }
 
-func TestGetUserBookings_TripEnrichmentFails(t *testing.T) {
+func TestCancelBooking_Confirmed_RefundFails(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
@@ -1160,44 +1308,53 @@ func TestGetUserBookings_TripEnrichmentFails(t *testing.T) {
 		mockNotificationClient,
 		mockDelayedQueue,
 		mockSeatLockService,
+		NewCancellationPolicyService(testRefundTiers),
+		service_mocks.NewMockBookingExchangeService(ctrl),
+		service_mocks.NewMockPromotionService(ctrl),
 	)
 
 	ctx := context.Background()
-	userID := uuid.New()
+	bookingID := uuid.New()
 	tripID := uuid.New()
 
-	req := model.GetUserBookingsRequest{
-		PaginationRequest: model.PaginationRequest{
-			Page:     1,
-			PageSize: 10,
-		},
+	booking := &model.Booking{
+		BaseModel:   model.BaseModel{ID: bookingID},
+		TripID:      tripID,
+		TotalAmount: 500000,
+		Status:      model.BookingStatusConfirmed,
 	}
 
-	bookings := []*model.Booking{
-		{BaseModel: model.BaseModel{ID: uuid.New()}, TripID: tripID, UserID: userID},
+	tripData := &trip.Trip{
+		ID:            tripID,
+		DepartureTime: time.Now().UTC().Add(48 * time.Hour),
 	}
 
 	mockBookingRepo.EXPECT().
-		GetBookingsByUserID(ctx, userID, req.Status, 10, 0).
-		Return(bookings, int64(1), nil).
+		GetBookingByID(ctx, bookingID).
+		Return(booking, nil).
 		Times(1)
 
-	// Trip enrichment fails
 	mockTripClient.EXPECT().
-		GetTripsByIDs(ctx, gomock.Any(), gomock.Any()).
+		GetTripByID(ctx, gomock.Any(), tripID).
+		Return(tripData, nil).
+		Times(1)
+
+	mockPaymentClient.EXPECT().
+		CreateRefund(ctx, gomock.Any()).
 		Return(nil, assert.AnError).
 		Times(1)
 
-	// Should still return bookings (just without trip data)
-	result, total, err := service.GetUserBookings(ctx, req, userID)
+	// Booking must stay confirmed when the refund could not be created
+	mockBookingRepo.EXPECT().
+		CancelBooking(gomock.Any(), gomock.Any(), gomock.Any()).
+		Times(0)
 
-	assert.NoError(t, err)
-	assert.Len(t, result, 1)
-	assert.Equal(t, int64(1), total)
-	assert.Nil(t, result[0].Trip) // No trip data
+	err := service.CancelBooking(ctx, bookingID, "test")
+
+	assert.Error(t, err)
 }
 
-func TestGetTripBookings_RepositoryError(t *testing.T) {
+func TestGetRefundQuote_Success(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
@@ -1217,25 +1374,46 @@ func TestGetTripBookings_RepositoryError(t *testing.T) {
 		mockNotificationClient,
 		mockDelayedQueue,
 		mockSeatLockService,
+		NewCancellationPolicyService(testRefundTiers),
+		service_mocks.NewMockBookingExchangeService(ctrl),
+		service_mocks.NewMockPromotionService(ctrl),
 	)
 
 	ctx := context.Background()
+	bookingID := uuid.New()
 	tripID := uuid.New()
-	req := model.PaginationRequest{Page: 1, PageSize: 10}
 
-	mockBookingRepo.EXPECT().
-		GetTripBookings(ctx, tripID, 10, 0).
-		Return(nil, int64(0), assert.AnError).
-		Times(1)
-
-	result, total, err := service.GetTripBookings(ctx, req, tripID)
+	booking := &model.Booking{
+		BaseModel:   model.BaseModel{ID: bookingID},
+		TripID:      tripID,
+		TotalAmount: 400000,
+		Status:      model.BookingStatusConfirmed,
+	}
 
-	assert.Error(t, err)
-	assert.Nil(t, result)
-	assert.Equal(t, int64(0), total)
+	tripData := &trip.Trip{
+		ID:            tripID,
+		DepartureTime: time.Now().UTC().Add(30 * time.Hour),
+	}
+
+	mockBookingRepo.EXPECT().
+		GetBookingByID(ctx, bookingID).
+		Return(booking, nil).
+		Times(1)
+
+	mockTripClient.EXPECT().
+		GetTripByID(ctx, gomock.Any(), tripID).
+		Return(tripData, nil).
+		Times(1)
+
+	quote, err := service.GetRefundQuote(ctx, bookingID)
+
+	assert.NoError(t, err)
+	assert.True(t, quote.Cancellable)
+	assert.Equal(t, 70, quote.RefundPercent)
+	assert.Equal(t, 280000, quote.RefundAmount)
 }
 
-func TestCreateBooking_SeatUnavailable(t *testing.T) {
+func TestGetRefundQuote_NotConfirmed(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
@@ -1255,32 +1433,31 @@ func TestCreateBooking_SeatUnavailable(t *testing.T) {
 		mockNotificationClient,
 		mockDelayedQueue,
 		mockSeatLockService,
+		NewCancellationPolicyService(testRefundTiers),
+		service_mocks.NewMockBookingExchangeService(ctrl),
+		service_mocks.NewMockPromotionService(ctrl),
 	)
 
 	ctx := context.Background()
-	userID := uuid.New()
-	tripID := uuid.New()
-	seatID := uuid.New()
+	bookingID := uuid.New()
 
-	req := &model.CreateBookingRequest{
-		TripID:  tripID,
-		SeatIDs: []uuid.UUID{seatID},
+	booking := &model.Booking{
+		BaseModel: model.BaseModel{ID: bookingID},
+		Status:    model.BookingStatusPending,
 	}
 
-	// Seat is already booked
 	mockBookingRepo.EXPECT().
-		GetBookedSeatIDs(ctx, tripID).
-		Return([]uuid.UUID{seatID}, nil).
+		GetBookingByID(ctx, bookingID).
+		Return(booking, nil).
 		Times(1)
 
-	result, err := service.CreateBooking(ctx, req, userID)
+	quote, err := service.GetRefundQuote(ctx, bookingID)
 
 	assert.Error(t, err)
-	assert.Nil(t, result)
-	assert.Contains(t, err.Error(), "already booked")
+	assert.Nil(t, quote)
 }
 
-func TestCreateBooking_PaymentCreationFails(t *testing.T) {
+func TestCancelBooking_Success(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
@@ -1300,70 +1477,70 @@ func TestCreateBooking_PaymentCreationFails(t *testing.T) {
 		mockNotificationClient,
 		mockDelayedQueue,
 		mockSeatLockService,
+		NewCancellationPolicyService(testRefundTiers),
+		service_mocks.NewMockBookingExchangeService(ctrl),
+		service_mocks.NewMockPromotionService(ctrl),
 	)
 
 	ctx := context.Background()
-	userID := uuid.New()
-	tripID := uuid.New()
-	seatID := uuid.New()
+	bookingID := uuid.New()
+	txID := uuid.New()
 
-	req := &model.CreateBookingRequest{
-		TripID:  tripID,
-		SeatIDs: []uuid.UUID{seatID},
+	booking := &model.Booking{
+		BaseModel:     model.BaseModel{ID: bookingID},
+		Status:        model.BookingStatusPending,
+		TransactionID: txID,
 	}
 
-	tripData := &trip.Trip{
-		ID:        tripID,
-		BasePrice: 100000,
+	cancelledTx := &payment.TransactionResponse{
+		ID:     txID,
+		Status: payment.TransactionStatusCancelled,
 	}
 
-	seatData := trip.Seat{
-		ID:              seatID,
-		SeatNumber:      "A1",
-		PriceMultiplier: 1.0,
+	updatedBooking := &model.Booking{
+		BaseModel:         model.BaseModel{ID: bookingID},
+		Status:            model.BookingStatusCancelled,
+		TransactionID:     txID,
+		TransactionStatus: payment.TransactionStatusPending,
 	}
 
 	// Mock sequence
 	mockBookingRepo.EXPECT().
-		GetBookedSeatIDs(ctx, tripID).
-		Return([]uuid.UUID{}, nil).
-		Times(1)
-
-	mockTripClient.EXPECT().
-		GetTripByID(gomock.Any(), gomock.Any(), tripID).
-		Return(tripData, nil).
-		Times(1)
-
-	mockTripClient.EXPECT().
-		ListSeatsByIDs(gomock.Any(), gomock.Any()).
-		Return([]trip.Seat{seatData}, nil).
+		GetBookingByID(ctx, bookingID).
+		Return(booking, nil).
 		Times(1)
 
 	mockBookingRepo.EXPECT().
-		CreateBooking(ctx, gomock.Any()).
+		CancelBooking(ctx, bookingID, "test reason").
 		Return(nil).
 		Times(1)
 
-	// Payment fails
 	mockPaymentClient.EXPECT().
-		CreateTransaction(ctx, gomock.Any()).
-		Return(nil, assert.AnError).
+		CancelTransaction(ctx, txID).
+		Return(cancelledTx, nil).
+		Times(1)
+
+	mockBookingRepo.EXPECT().
+		GetBookingByID(ctx, bookingID).
+		Return(updatedBooking, nil).
 		Times(1)
 
-	// Should update to FAILED
 	mockBookingRepo.EXPECT().
 		UpdateBooking(ctx, gomock.Any()).
 		Return(nil).
 		Times(1)
 
-	result, err := service.CreateBooking(ctx, req, userID)
+	// The released seats go to the trip's waitlist
+	mockDelayedQueue.EXPECT().
+		ScheduleEarliest(ctx, constants.QueueNameWaitlistOffer, gomock.Any(), gomock.Any()).
+		Return(nil)
 
-	assert.NoError(t, err) // Returns booking with failed status
-	assert.NotNil(t, result)
-	assert.Equal(t, payment.TransactionStatusFailed, result.Transaction.Status)
+	err := service.CancelBooking(ctx, bookingID, "test reason")
+
+	assert.NoError(t, err)
 }
 
-func TestUpdateBookingStatus_Cancelled(t *testing.T) {
+func TestCancelBooking_PaymentCancelFails(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
@@ -1383,40 +1560,50 @@ func TestUpdateBookingStatus_Cancelled(t *testing.T) {
 		mockNotificationClient,
 		mockDelayedQueue,
 		mockSeatLockService,
+		NewCancellationPolicyService(testRefundTiers),
+		service_mocks.NewMockBookingExchangeService(ctrl),
+		service_mocks.NewMockPromotionService(ctrl),
 	)
 
 	ctx := context.Background()
 	bookingID := uuid.New()
+	txID := uuid.New()
 
 	booking := &model.Booking{
-		BaseModel: model.BaseModel{ID: bookingID},
-		Status:    model.BookingStatusPending,
-	}
-
-	req := &model.UpdateBookingStatusRequest{
-		TransactionStatus: payment.TransactionStatusCancelled,
+		BaseModel:     model.BaseModel{ID: bookingID},
+		Status:        model.BookingStatusPending,
+		TransactionID: txID,
 	}
 
+	// Mock sequence
 	mockBookingRepo.EXPECT().
 		GetBookingByID(ctx, bookingID).
 		Return(booking, nil).
 		Times(1)
 
 	mockBookingRepo.EXPECT().
-		UpdateBooking(ctx, gomock.Any()).
-		Do(func(_ context.Context, b *model.Booking) {
-			assert.Equal(t, model.BookingStatusCancelled, b.Status)
-			assert.NotNil(t, b.CancelledAt)
-		}).
+		CancelBooking(ctx, bookingID, "test").
 		Return(nil).
 		Times(1)
 
-	err := service.UpdateBookingStatus(ctx, req, bookingID)
+	// Payment cancellation fails
+	mockPaymentClient.EXPECT().
+		CancelTransaction(ctx, txID).
+		Return(nil, assert.AnError).
+		Times(1)
 
-	assert.NoError(t, err)
+	// The released seats go to the trip's waitlist
+	mockDelayedQueue.EXPECT().
+		ScheduleEarliest(ctx, constants.QueueNameWaitlistOffer, gomock.Any(), gomock.Any()).
+		Return(nil)
+
+	// Should still return nil (booking is cancelled despite payment error)
+	err := service.CancelBooking(ctx, bookingID, "test")
+
+	assert.NoError(t, err) // Function logs error but doesn't fail
 }
 
-func TestUpdateBookingStatus_Expired(t *testing.T) {
+func TestCancelBooking_GetBookingAfterCancelFails(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
@@ -1436,39 +1623,60 @@ func TestUpdateBookingStatus_Expired(t *testing.T) {
 		mockNotificationClient,
 		mockDelayedQueue,
 		mockSeatLockService,
+		NewCancellationPolicyService(testRefundTiers),
+		service_mocks.NewMockBookingExchangeService(ctrl),
+		service_mocks.NewMockPromotionService(ctrl),
 	)
 
 	ctx := context.Background()
 	bookingID := uuid.New()
+	txID := uuid.New()
 
 	booking := &model.Booking{
-		BaseModel: model.BaseModel{ID: bookingID},
-		Status:    model.BookingStatusPending,
+		BaseModel:     model.BaseModel{ID: bookingID},
+		Status:        model.BookingStatusPending,
+		TransactionID: txID,
 	}
 
-	req := &model.UpdateBookingStatusRequest{
-		TransactionStatus: payment.TransactionStatusExpired,
+	cancelledTx := &payment.TransactionResponse{
+		ID:     txID,
+		Status: payment.TransactionStatusCancelled,
 	}
 
+	// Mock sequence
 	mockBookingRepo.EXPECT().
 		GetBookingByID(ctx, bookingID).
 		Return(booking, nil).
 		Times(1)
 
 	mockBookingRepo.EXPECT().
-		UpdateBooking(ctx, gomock.Any()).
-		Do(func(_ context.Context, b *model.Booking) {
-			assert.Equal(t, model.BookingStatusExpired, b.Status)
-		}).
+		CancelBooking(ctx, bookingID, "test").
 		Return(nil).
 		Times(1)
 
-	err := service.UpdateBookingStatus(ctx, req, bookingID)
+	mockPaymentClient.EXPECT().
+		CancelTransaction(ctx, txID).
+		Return(cancelledTx, nil).
+		Times(1)
 
-	assert.NoError(t, err)
+	// Second GetBookingByID fails
+	mockBookingRepo.EXPECT().
+		GetBookingByID(ctx, bookingID).
+		Return(nil, assert.AnError).
+		Times(1)
+
+	// The released seats go to the trip's waitlist
+	mockDelayedQueue.EXPECT().
+		ScheduleEarliest(ctx, constants.QueueNameWaitlistOffer, gomock.Any(), gomock.Any()).
+		Return(nil)
+
+	// Should log error but still return nil (booking is already cancelled)
+	err := service.CancelBooking(ctx, bookingID, "test")
+
+	assert.NoError(t, err) // Logs error but succeeds
 }
 
-func TestRetryPayment_BookingNotFound(t *testing.T) {
+func TestGetUserBookings_TripEnrichmentFails(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
@@ -1488,24 +1696,47 @@ func TestRetryPayment_BookingNotFound(t *testing.T) {
 		mockNotificationClient,
 		mockDelayedQueue,
 		mockSeatLockService,
+		NewCancellationPolicyService(testRefundTiers),
+		service_mocks.NewMockBookingExchangeService(ctrl),
+		service_mocks.NewMockPromotionService(ctrl),
 	)
 
 	ctx := context.Background()
-	bookingID := uuid.New()
+	userID := uuid.New()
+	tripID := uuid.New()
+
+	req := model.GetUserBookingsRequest{
+		PaginationRequest: model.PaginationRequest{
+			Page:     1,
+			PageSize: 10,
+		},
+	}
+
+	bookings := []*model.Booking{
+		{BaseModel: model.BaseModel{ID: uuid.New()}, TripID: tripID, UserID: userID},
+	}
 
 	mockBookingRepo.EXPECT().
-		GetBookingByID(ctx, bookingID).
+		GetBookingsByUserID(ctx, userID, req.Status, 10, 0).
+		Return(bookings, int64(1), nil).
+		Times(1)
+
+	// Trip enrichment fails
+	mockTripClient.EXPECT().
+		GetTripsByIDs(ctx, gomock.Any(), gomock.Any()).
 		Return(nil, assert.AnError).
 		Times(1)
 
-	result, err := service.RetryPayment(ctx, bookingID)
+	// Should still return bookings (just without trip data)
+	result, total, err := service.GetUserBookings(ctx, req, userID)
 
-	assert.Error(t, err)
-	assert.Nil(t, result)
-	assert.Contains(t, err.Error(), "not found")
+	assert.NoError(t, err)
+	assert.Len(t, result, 1)
+	assert.Equal(t, int64(1), total)
+	assert.Nil(t, result[0].Trip) // No trip data
 }
 
-func TestRetryPayment_NotRetryableState(t *testing.T) {
+func TestGetTripBookings_RepositoryError(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
@@ -1525,30 +1756,28 @@ func TestRetryPayment_NotRetryableState(t *testing.T) {
 		mockNotificationClient,
 		mockDelayedQueue,
 		mockSeatLockService,
+		NewCancellationPolicyService(testRefundTiers),
+		service_mocks.NewMockBookingExchangeService(ctrl),
+		service_mocks.NewMockPromotionService(ctrl),
 	)
 
 	ctx := context.Background()
-	bookingID := uuid.New()
-
-	// Booking is confirmed - not retryable
-	booking := &model.Booking{
-		BaseModel: model.BaseModel{ID: bookingID},
-		Status:    model.BookingStatusConfirmed,
-	}
+	tripID := uuid.New()
+	req := model.PaginationRequest{Page: 1, PageSize: 10}
 
 	mockBookingRepo.EXPECT().
-		GetBookingByID(ctx, bookingID).
-		Return(booking, nil).
+		GetTripBookings(ctx, tripID, 10, 0).
+		Return(nil, int64(0), assert.AnError).
 		Times(1)
 
-	result, err := service.RetryPayment(ctx, bookingID)
+	result, total, err := service.GetTripBookings(ctx, req, tripID)
 
 	assert.Error(t, err)
 	assert.Nil(t, result)
-	assert.Contains(t, err.Error(), "not in a retryable state")
+	assert.Equal(t, int64(0), total)
 }
 
-func TestRetryPayment_ExpiredBeyondGracePeriod(t *testing.T) {
+func TestCreateBooking_SeatUnavailable(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
@@ -1568,34 +1797,35 @@ func TestRetryPayment_ExpiredBeyondGracePeriod(t *testing.T) {
 		mockNotificationClient,
 		mockDelayedQueue,
 		mockSeatLockService,
+		NewCancellationPolicyService(testRefundTiers),
+		service_mocks.NewMockBookingExchangeService(ctrl),
+		service_mocks.NewMockPromotionService(ctrl),
 	)
 
 	ctx := context.Background()
-	bookingID := uuid.New()
+	userID := uuid.New()
+	tripID := uuid.New()
+	seatID := uuid.New()
 
-	// Expired 2 hours ago
-	expiredTime := time.Now().UTC().Add(-2 * time.Hour)
-	booking := &model.Booking{
-		BaseModel: model.BaseModel{ID: bookingID},
-		Status:    model.BookingStatusExpired,
-		ExpiresAt: &expiredTime,
+	req := &model.CreateBookingRequest{
+		TripID:  tripID,
+		SeatIDs: []uuid.UUID{seatID},
 	}
 
+	// Seat is already booked
 	mockBookingRepo.EXPECT().
-		GetBookingByID(ctx, bookingID).
-		Return(booking, nil).
+		GetBookedSeatIDs(ctx, tripID, model.FullTripSegment()).
+		Return([]uuid.UUID{seatID}, nil).
 		Times(1)
 
-	result, err := service.RetryPayment(ctx, bookingID)
+	result, err := service.CreateBooking(ctx, req, userID)
 
 	assert.Error(t, err)
 	assert.Nil(t, result)
-	assert.Contains(t, err.Error(), "expired beyond retry period")
+	assert.Contains(t, err.Error(), "already booked")
 }
 
-// ========== ExpireBooking - Validation paths ==========
-
-func TestExpireBooking_BookingNotFound(t *testing.T) {
+func TestCreateBooking_PaymentCreationFails(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
@@ -1615,63 +1845,175 @@ func TestExpireBooking_BookingNotFound(t *testing.T) {
 		mockNotificationClient,
 		mockDelayedQueue,
 		mockSeatLockService,
+		NewCancellationPolicyService(testRefundTiers),
+		service_mocks.NewMockBookingExchangeService(ctrl),
+		service_mocks.NewMockPromotionService(ctrl),
 	)
 
 	ctx := context.Background()
-	bookingID := uuid.New()
+	userID := uuid.New()
+	tripID := uuid.New()
+	seatID := uuid.New()
 
-	mockBookingRepo.EXPECT().
-		GetBookingByID(ctx, bookingID).
-		Return(nil, assert.AnError).
-		Times(1)
+	req := &model.CreateBookingRequest{
+		TripID:  tripID,
+		SeatIDs: []uuid.UUID{seatID},
+	}
 
-	err := service.ExpireBooking(ctx, bookingID)
+	tripData := &trip.Trip{
+		ID:        tripID,
+		BasePrice: 100000,
+	}
 
-	assert.Error(t, err)
+	seatData := trip.Seat{
+		ID:              seatID,
+		SeatNumber:      "A1",
+		PriceMultiplier: 1.0,
+	}
+
+	// Mock sequence
+	mockBookingRepo.EXPECT().
+		GetBookedSeatIDs(ctx, tripID, model.FullTripSegment()).
+		Return([]uuid.UUID{}, nil).
+		Times(1)
+
+	mockTripClient.EXPECT().
+		GetTripByID(gomock.Any(), gomock.Any(), tripID).
+		Return(tripData, nil).
+		Times(1)
+
+	mockTripClient.EXPECT().
+		ListSeatsByIDs(gomock.Any(), gomock.Any()).
+		Return([]trip.Seat{seatData}, nil).
+		Times(1)
+
+	mockSeatLockService.EXPECT().
+		QuoteSeatFares(gomock.Any(), tripID, req.SessionID, req.SeatIDs).
+		DoAndReturn(quoteSeatsAt(tripData.BasePrice)).
+		Times(1)
+
+	mockBookingRepo.EXPECT().
+		CreateBookingFromHold(ctx, gomock.Any(), gomock.Any(), model.FullTripSegment()).
+		Return(nil).
+		Times(1)
+
+	// Payment fails
+	mockPaymentClient.EXPECT().
+		CreateTransaction(ctx, gomock.Any()).
+		Return(nil, assert.AnError).
+		Times(1)
+
+	// Should update to FAILED
+	mockBookingRepo.EXPECT().
+		UpdateBooking(ctx, gomock.Any()).
+		Return(nil).
+		Times(1)
+
+	result, err := service.CreateBooking(ctx, req, userID)
+
+	assert.NoError(t, err) // Returns booking with failed status
+	assert.NotNil(t, result)
+	assert.Equal(t, payment.TransactionStatusFailed, result.Transaction.Status)
 }
 
-func TestExpireBooking_AlreadyProcessed(t *testing.T) {
+func TestCreateBooking_ChargesFareHeldBySession(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	mockBookingRepo := repo_mocks.NewMockBookingRepository(ctrl)
 	mockPaymentClient := mocks.NewMockPaymentClient(ctrl)
 	mockTripClient := mocks.NewMockTripClient(ctrl)
-	mockUserClient := mocks.NewMockUserClient(ctrl)
-	mockNotificationClient := mocks.NewMockNotificationClient(ctrl)
-	mockDelayedQueue := queue_mocks.NewMockDelayedQueueManager(ctrl)
 	mockSeatLockService := service_mocks.NewMockSeatLockService(ctrl)
 
 	service := NewBookingService(
 		mockBookingRepo,
 		mockPaymentClient,
 		mockTripClient,
-		mockUserClient,
-		mockNotificationClient,
-		mockDelayedQueue,
+		mocks.NewMockUserClient(ctrl),
+		mocks.NewMockNotificationClient(ctrl),
+		queue_mocks.NewMockDelayedQueueManager(ctrl),
 		mockSeatLockService,
+		NewCancellationPolicyService(testRefundTiers),
+		service_mocks.NewMockBookingExchangeService(ctrl),
+		service_mocks.NewMockPromotionService(ctrl),
 	)
 
 	ctx := context.Background()
-	bookingID := uuid.New()
+	tripID := uuid.New()
+	standardID, vipID := uuid.New(), uuid.New()
 
-	// Already confirmed - skip expiration
-	booking := &model.Booking{
-		BaseModel: model.BaseModel{ID: bookingID},
-		Status:    model.BookingStatusConfirmed,
+	req := &model.CreateBookingRequest{
+		TripID:    tripID,
+		SeatIDs:   []uuid.UUID{standardID, vipID},
+		SessionID: "checkout-session",
 	}
 
+	// Both seats were held at a 120k fare, above the 100k base price
+	mockBookingRepo.EXPECT().GetBookedSeatIDs(ctx, tripID, model.FullTripSegment()).Return([]uuid.UUID{}, nil)
+	mockTripClient.EXPECT().GetTripByID(gomock.Any(), gomock.Any(), tripID).Return(&trip.Trip{ID: tripID, BasePrice: 100000}, nil)
+	mockTripClient.EXPECT().ListSeatsByIDs(gomock.Any(), gomock.Any()).Return([]trip.Seat{
+		{ID: standardID, SeatNumber: "A1", PriceMultiplier: 1.0},
+		{ID: vipID, SeatNumber: "V1", PriceMultiplier: 1.5},
+	}, nil)
+	mockSeatLockService.EXPECT().
+		QuoteSeatFares(gomock.Any(), tripID, "checkout-session", req.SeatIDs).
+		Return(map[uuid.UUID]float64{standardID: 120000, vipID: 120000}, nil)
+
 	mockBookingRepo.EXPECT().
-		GetBookingByID(ctx, bookingID).
-		Return(booking, nil).
-		Times(1)
+		CreateBookingFromHold(ctx, gomock.Any(), "checkout-session", model.FullTripSegment()).
+		DoAndReturn(func(_ context.Context, booking *model.Booking, _ string, _ model.TripSegment) error {
+			assert.Equal(t, 300000, booking.TotalAmount)
+			if assert.Len(t, booking.BookingSeats, 2) {
+				assert.Equal(t, 120000.0, booking.BookingSeats[0].Price)
+				assert.Equal(t, 180000.0, booking.BookingSeats[1].Price)
+			}
+			return nil
+		})
+	mockPaymentClient.EXPECT().CreateTransaction(ctx, gomock.Any()).Return(nil, assert.AnError)
+	mockBookingRepo.EXPECT().UpdateBooking(ctx, gomock.Any()).Return(nil)
+
+	result, err := service.CreateBooking(ctx, req, uuid.New())
 
-	err := service.ExpireBooking(ctx, bookingID)
+	assert.NoError(t, err)
+	assert.Equal(t, 300000, result.TotalAmount)
+}
 
-	assert.NoError(t, err) // Returns nil - already processed
+// quoteSeatsAt charges every seat the same trip fare, as if held at it
+func quoteSeatsAt(fare float64) func(context.Context, uuid.UUID, string, []uuid.UUID) (map[uuid.UUID]float64, error) {
+	return func(_ context.Context, _ uuid.UUID, _ string, seatIDs []uuid.UUID) (map[uuid.UUID]float64, error) {
+		fares := make(map[uuid.UUID]float64, len(seatIDs))
+		for _, id := range seatIDs {
+			fares[id] = fare
+		}
+		return fares, nil
+	}
 }
 
-func TestListBookings_Success(t *testing.T) {
+// quotingSeatLockService quotes the seats of one booking at the given fare
+func quotingSeatLockService(ctrl *gomock.Controller, fare float64) *service_mocks.MockSeatLockService {
+	seatLockService := service_mocks.NewMockSeatLockService(ctrl)
+	seatLockService.EXPECT().
+		QuoteSeatFares(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(quoteSeatsAt(fare))
+	return seatLockService
+}
+
+func segmentTestTrip(tripID uuid.UUID) *trip.Trip {
+	return &trip.Trip{
+		ID:        tripID,
+		BasePrice: 300000,
+		Route: &trip.Route{
+			DistanceKm: 300,
+			RouteStops: []trip.RouteStop{
+				{ID: uuid.New(), StopOrder: 100, StopType: trip.StopTypePickup, DistanceKm: 0, IsActive: true, Location: "Bến xe Mỹ Đình"},
+				{ID: uuid.New(), StopOrder: 200, StopType: trip.StopTypeBoth, DistanceKm: 100, IsActive: true, Location: "Bến xe Ninh Bình"},
+				{ID: uuid.New(), StopOrder: 300, StopType: trip.StopTypeDropoff, DistanceKm: 300, IsActive: true, Location: "Bến xe Vinh"},
+			},
+		},
+	}
+}
+
+func TestCreateBooking_SegmentProratesFare(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
@@ -1691,89 +2033,78 @@ func TestListBookings_Success(t *testing.T) {
 		mockNotificationClient,
 		mockDelayedQueue,
 		mockSeatLockService,
+		NewCancellationPolicyService(testRefundTiers),
+		service_mocks.NewMockBookingExchangeService(ctrl),
+		service_mocks.NewMockPromotionService(ctrl),
 	)
 
 	ctx := context.Background()
-	tripID1 := uuid.New()
-	tripID2 := uuid.New()
-
-	req := model.ListBookingsRequest{
-		Page:     1,
-		PageSize: 10,
-		SortBy:   "created_at",
-		Order:    "desc",
-	}
+	userID := uuid.New()
+	tripID := uuid.New()
+	seatID := uuid.New()
+	tripData := segmentTestTrip(tripID)
+	pickup := tripData.Route.RouteStops[1]
+	dropoff := tripData.Route.RouteStops[2]
 
-	bookings := []*model.Booking{
-		{
-			BaseModel:        model.BaseModel{ID: uuid.New()},
-			BookingReference: "BK001",
-			TripID:           tripID1,
-			UserID:           uuid.New(),
-			TotalAmount:      100000,
-			Status:           model.BookingStatusConfirmed,
-		},
-		{
-			BaseModel:        model.BaseModel{ID: uuid.New()},
-			BookingReference: "BK002",
-			TripID:           tripID2,
-			UserID:           uuid.New(),
-			TotalAmount:      150000,
-			Status:           model.BookingStatusPending,
-		},
+	req := &model.CreateBookingRequest{
+		TripID:        tripID,
+		SeatIDs:       []uuid.UUID{seatID},
+		PickupStopID:  &pickup.ID,
+		DropoffStopID: &dropoff.ID,
 	}
 
-	trips := []trip.Trip{
-		{
-			ID:            tripID1,
-			BasePrice:     100000,
-			DepartureTime: time.Now(),
-			Route: &trip.Route{
-				Origin:      "Ha Noi",
-				Destination: "Da Nang",
-			},
-			Bus: &trip.Bus{
-				Model: "Hyundai Universe",
-			},
-		},
-		{
-			ID:            tripID2,
-			BasePrice:     150000,
-			DepartureTime: time.Now(),
-			Route: &trip.Route{
-				Origin:      "Ho Chi Minh",
-				Destination: "Vung Tau",
-			},
-			Bus: &trip.Bus{
-				Model: "Mercedes Benz",
-			},
-		},
-	}
+	mockTripClient.EXPECT().
+		GetTripByID(ctx, trip.GetTripByIDRequest{PreLoadRoute: true, PreLoadRouteStop: true}, tripID).
+		Return(tripData, nil).
+		Times(1)
 
+	// Only bookings overlapping stops 200-300 block the seat
 	mockBookingRepo.EXPECT().
-		ListBookings(ctx, req).
-		Return(bookings, int64(2), nil).
+		GetBookedSeatIDs(ctx, tripID, model.TripSegment{FromStopOrder: 200, ToStopOrder: 300}).
+		Return([]uuid.UUID{}, nil).
 		Times(1)
 
 	mockTripClient.EXPECT().
-		GetTripsByIDs(ctx, gomock.Any(), gomock.Any()).
-		Return(trips, nil).
+		ListSeatsByIDs(gomock.Any(), gomock.Any()).
+		Return([]trip.Seat{{ID: seatID, SeatNumber: "A1", PriceMultiplier: 1.0}}, nil).
 		Times(1)
 
-	result, total, err := service.ListBookings(ctx, req)
+	mockSeatLockService.EXPECT().
+		QuoteSeatFares(gomock.Any(), tripID, req.SessionID, req.SeatIDs).
+		DoAndReturn(quoteSeatsAt(tripData.BasePrice)).
+		Times(1)
+
+	mockBookingRepo.EXPECT().
+		CreateBookingFromHold(ctx, gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, booking *model.Booking, _ string, _ model.TripSegment) error {
+			// 200km of a 300km route
+			assert.Equal(t, 200000, booking.TotalAmount)
+			assert.Equal(t, model.TripSegment{FromStopOrder: 200, ToStopOrder: 300}, booking.Segment())
+			assert.Equal(t, "Bến xe Ninh Bình", booking.PickupLocation)
+			assert.Equal(t, "Bến xe Vinh", booking.DropoffLocation)
+			return nil
+		}).
+		Times(1)
+
+	mockPaymentClient.EXPECT().
+		CreateTransaction(ctx, gomock.Any()).
+		Return(nil, assert.AnError).
+		Times(1)
+
+	mockBookingRepo.EXPECT().
+		UpdateBooking(ctx, gomock.Any()).
+		Return(nil).
+		Times(1)
+
+	result, err := service.CreateBooking(ctx, req, userID)
 
 	assert.NoError(t, err)
-	assert.Len(t, result, 2)
-	assert.Equal(t, int64(2), total)
-	assert.Equal(t, "BK001", result[0].BookingReference)
-	assert.Equal(t, "BK002", result[1].BookingReference)
-	assert.NotNil(t, result[0].Trip)
-	assert.Equal(t, "Ha Noi", result[0].Trip.Origin)
-	assert.NotNil(t, result[1].Trip)
-	assert.Equal(t, "Ho Chi Minh", result[1].Trip.Origin)
+	assert.Equal(t, 200000, result.TotalAmount)
+	assert.Equal(t, &pickup.ID, result.PickupStopID)
+	assert.Equal(t, &dropoff.ID, result.DropoffStopID)
 }
 
-func TestListBookings_EmptyResults(t *testing.T) {
+func TestCreateBooking_SegmentDropoffBeforePickup(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
@@ -1793,27 +2124,76 @@ func TestListBookings_EmptyResults(t *testing.T) {
 		mockNotificationClient,
 		mockDelayedQueue,
 		mockSeatLockService,
+		NewCancellationPolicyService(testRefundTiers),
+		service_mocks.NewMockBookingExchangeService(ctrl),
+		service_mocks.NewMockPromotionService(ctrl),
 	)
 
 	ctx := context.Background()
-	req := model.ListBookingsRequest{
-		Page:     1,
-		PageSize: 10,
+	tripID := uuid.New()
+	tripData := segmentTestTrip(tripID)
+
+	req := &model.CreateBookingRequest{
+		TripID:        tripID,
+		SeatIDs:       []uuid.UUID{uuid.New()},
+		PickupStopID:  &tripData.Route.RouteStops[1].ID,
+		DropoffStopID: &tripData.Route.RouteStops[1].ID,
 	}
 
-	mockBookingRepo.EXPECT().
-		ListBookings(ctx, req).
-		Return([]*model.Booking{}, int64(0), nil).
+	mockTripClient.EXPECT().
+		GetTripByID(ctx, gomock.Any(), tripID).
+		Return(tripData, nil).
 		Times(1)
 
-	result, total, err := service.ListBookings(ctx, req)
+	result, err := service.CreateBooking(ctx, req, uuid.New())
 
-	assert.NoError(t, err)
-	assert.Empty(t, result)
-	assert.Equal(t, int64(0), total)
+	assert.Error(t, err)
+	assert.Nil(t, result)
+	assert.Contains(t, err.Error(), "after pickup")
 }
 
-func TestListBookings_RepositoryError(t *testing.T) {
+func TestCreateBooking_SegmentMissingDropoff(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service := NewBookingService(
+		repo_mocks.NewMockBookingRepository(ctrl),
+		mocks.NewMockPaymentClient(ctrl),
+		mocks.NewMockTripClient(ctrl),
+		mocks.NewMockUserClient(ctrl),
+		mocks.NewMockNotificationClient(ctrl),
+		queue_mocks.NewMockDelayedQueueManager(ctrl),
+		service_mocks.NewMockSeatLockService(ctrl),
+		NewCancellationPolicyService(testRefundTiers),
+		service_mocks.NewMockBookingExchangeService(ctrl),
+		service_mocks.NewMockPromotionService(ctrl),
+	)
+
+	pickupID := uuid.New()
+	req := &model.CreateBookingRequest{
+		TripID:       uuid.New(),
+		SeatIDs:      []uuid.UUID{uuid.New()},
+		PickupStopID: &pickupID,
+	}
+
+	result, err := service.CreateBooking(context.Background(), req, uuid.New())
+
+	assert.Error(t, err)
+	assert.Nil(t, result)
+}
+
+func TestTripSegment_Overlaps(t *testing.T) {
+	first := model.TripSegment{FromStopOrder: 100, ToStopOrder: 200}
+	second := model.TripSegment{FromStopOrder: 200, ToStopOrder: 300}
+	spanning := model.TripSegment{FromStopOrder: 150, ToStopOrder: 250}
+
+	assert.False(t, first.Overlaps(second))
+	assert.True(t, first.Overlaps(spanning))
+	assert.True(t, second.Overlaps(spanning))
+	assert.True(t, model.FullTripSegment().Overlaps(first))
+}
+
+func TestUpdateBookingStatus_Cancelled(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
@@ -1833,27 +2213,48 @@ func TestListBookings_RepositoryError(t *testing.T) {
 		mockNotificationClient,
 		mockDelayedQueue,
 		mockSeatLockService,
+		NewCancellationPolicyService(testRefundTiers),
+		service_mocks.NewMockBookingExchangeService(ctrl),
+		service_mocks.NewMockPromotionService(ctrl),
 	)
 
 	ctx := context.Background()
-	req := model.ListBookingsRequest{
-		Page:     1,
-		PageSize: 10,
+	bookingID := uuid.New()
+
+	booking := &model.Booking{
+		BaseModel: model.BaseModel{ID: bookingID},
+		Status:    model.BookingStatusPending,
+	}
+
+	req := &model.UpdateBookingStatusRequest{
+		TransactionStatus: payment.TransactionStatusCancelled,
 	}
 
 	mockBookingRepo.EXPECT().
-		ListBookings(ctx, req).
-		Return(nil, int64(0), assert.AnError).
+		GetBookingByID(ctx, bookingID).
+		Return(booking, nil).
 		Times(1)
 
-	result, total, err := service.ListBookings(ctx, req)
+	mockBookingRepo.EXPECT().
+		UpdateBooking(ctx, gomock.Any()).
+		Do(func(_ context.Context, b *model.Booking) {
+			assert.Equal(t, model.BookingStatusCancelled, b.Status)
+			assert.NotNil(t, b.CancelledAt)
+		}).
+		Return(nil).
+		Times(1)
 
-	assert.Error(t, err)
-	assert.Nil(t, result)
-	assert.Equal(t, int64(0), total)
+	// The released seats go to the trip's waitlist
+	mockDelayedQueue.EXPECT().
+		ScheduleEarliest(ctx, constants.QueueNameWaitlistOffer, gomock.Any(), gomock.Any()).
+		Return(nil)
+
+	err := service.UpdateBookingStatus(ctx, req, bookingID)
+
+	assert.NoError(t, err)
 }
 
-func TestListBookings_TripFetchError(t *testing.T) {
+func TestUpdateBookingStatus_Expired(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
@@ -1873,55 +2274,54 @@ func TestListBookings_TripFetchError(t *testing.T) {
 		mockNotificationClient,
 		mockDelayedQueue,
 		mockSeatLockService,
+		NewCancellationPolicyService(testRefundTiers),
+		service_mocks.NewMockBookingExchangeService(ctrl),
+		service_mocks.NewMockPromotionService(ctrl),
 	)
 
 	ctx := context.Background()
-	tripID := uuid.New()
+	bookingID := uuid.New()
 
-	req := model.ListBookingsRequest{
-		Page:     1,
-		PageSize: 10,
+	booking := &model.Booking{
+		BaseModel: model.BaseModel{ID: bookingID},
+		Status:    model.BookingStatusPending,
 	}
 
-	bookings := []*model.Booking{
-		{
-			BaseModel:        model.BaseModel{ID: uuid.New()},
-			BookingReference: "BK001",
-			TripID:           tripID,
-			UserID:           uuid.New(),
-			TotalAmount:      100000,
-			Status:           model.BookingStatusConfirmed,
-		},
+	req := &model.UpdateBookingStatusRequest{
+		TransactionStatus: payment.TransactionStatusExpired,
 	}
 
 	mockBookingRepo.EXPECT().
-		ListBookings(ctx, req).
-		Return(bookings, int64(1), nil).
+		GetBookingByID(ctx, bookingID).
+		Return(booking, nil).
 		Times(1)
 
-	// Trip fetch fails but should not cause entire operation to fail
-	mockTripClient.EXPECT().
-		GetTripsByIDs(ctx, gomock.Any(), gomock.Any()).
-		Return(nil, assert.AnError).
+	mockBookingRepo.EXPECT().
+		UpdateBooking(ctx, gomock.Any()).
+		Do(func(_ context.Context, b *model.Booking) {
+			assert.Equal(t, model.BookingStatusExpired, b.Status)
+		}).
+		Return(nil).
 		Times(1)
 
-	result, total, err := service.ListBookings(ctx, req)
+	// The released seats go to the trip's waitlist
+	mockDelayedQueue.EXPECT().
+		ScheduleEarliest(ctx, constants.QueueNameWaitlistOffer, gomock.Any(), gomock.Any()).
+		Return(nil)
+
+	err := service.UpdateBookingStatus(ctx, req, bookingID)
 
 	assert.NoError(t, err)
-	assert.Len(t, result, 1)
-	assert.Equal(t, int64(1), total)
-	assert.Equal(t, "BK001", result[0].BookingReference)
-	assert.Nil(t, result[0].Trip) // Trip should be nil when fetch fails
 }
 
-func TestGetTripPassengers_Success(t *testing.T) {
+func TestRetryPayment_BookingNotFound(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	mockBookingRepo := repo_mocks.NewMockBookingRepository(ctrl)
-	mockUserClient := mocks.NewMockUserClient(ctrl)
 	mockPaymentClient := mocks.NewMockPaymentClient(ctrl)
 	mockTripClient := mocks.NewMockTripClient(ctrl)
+	mockUserClient := mocks.NewMockUserClient(ctrl)
 	mockNotificationClient := mocks.NewMockNotificationClient(ctrl)
 	mockDelayedQueue := queue_mocks.NewMockDelayedQueueManager(ctrl)
 	mockSeatLockService := service_mocks.NewMockSeatLockService(ctrl)
@@ -1934,53 +2334,27 @@ func TestGetTripPassengers_Success(t *testing.T) {
 		mockNotificationClient,
 		mockDelayedQueue,
 		mockSeatLockService,
+		NewCancellationPolicyService(testRefundTiers),
+		service_mocks.NewMockBookingExchangeService(ctrl),
+		service_mocks.NewMockPromotionService(ctrl),
 	)
 
 	ctx := context.Background()
-	tripID := uuid.New()
-	userID := uuid.New()
 	bookingID := uuid.New()
 
-	bookings := []*model.Booking{
-		{
-			BaseModel:   model.BaseModel{ID: bookingID},
-			TripID:      tripID,
-			UserID:      userID,
-			Status:      model.BookingStatusConfirmed,
-			TotalAmount: 100000,
-			BookingSeats: []model.BookingSeat{
-				{SeatNumber: "A1", Price: 100000},
-			},
-			BookingReference: "BKREF123",
-		},
-	}
-
-	userResp := &user.User{
-		ID:       userID,
-		FullName: "Test Passenger",
-		Email:    "passenger@example.com",
-		Phone:    "0987654321",
-	}
-
 	mockBookingRepo.EXPECT().
-		GetAllActiveBookingsByTripID(ctx, tripID).
-		Return(bookings, nil).
-		Times(1)
-
-	mockUserClient.EXPECT().
-		GetUserByID(gomock.Any(), userID).
-		Return(userResp, nil).
+		GetBookingByID(ctx, bookingID).
+		Return(nil, assert.AnError).
 		Times(1)
 
-	result, err := service.GetTripPassengers(ctx, tripID)
+	result, err := service.RetryPayment(ctx, bookingID)
 
-	assert.NoError(t, err)
-	assert.Len(t, result, 1)
-	assert.Equal(t, "Test Passenger", result[0].FullName)
-	assert.Equal(t, "A1", result[0].Seats[0])
+	assert.Error(t, err)
+	assert.Nil(t, result)
+	assert.Contains(t, err.Error(), "not found")
 }
 
-func TestCheckInPassenger_Success(t *testing.T) {
+func TestRetryPayment_NotRetryableState(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
@@ -2000,15 +2374,18 @@ func TestCheckInPassenger_Success(t *testing.T) {
 		mockNotificationClient,
 		mockDelayedQueue,
 		mockSeatLockService,
+		NewCancellationPolicyService(testRefundTiers),
+		service_mocks.NewMockBookingExchangeService(ctrl),
+		service_mocks.NewMockPromotionService(ctrl),
 	)
 
 	ctx := context.Background()
 	bookingID := uuid.New()
 
+	// Booking is confirmed - not retryable
 	booking := &model.Booking{
 		BaseModel: model.BaseModel{ID: bookingID},
 		Status:    model.BookingStatusConfirmed,
-		IsBoarded: false,
 	}
 
 	mockBookingRepo.EXPECT().
@@ -2016,17 +2393,14 @@ func TestCheckInPassenger_Success(t *testing.T) {
 		Return(booking, nil).
 		Times(1)
 
-	mockBookingRepo.EXPECT().
-		CheckInPassenger(ctx, bookingID).
-		Return(nil).
-		Times(1)
-
-	err := service.CheckInPassenger(ctx, bookingID)
+	result, err := service.RetryPayment(ctx, bookingID)
 
-	assert.NoError(t, err)
+	assert.Error(t, err)
+	assert.Nil(t, result)
+	assert.Contains(t, err.Error(), "not in a retryable state")
 }
 
-func TestUpdateBookingStatus_Paid_Success(t *testing.T) {
+func TestRetryPayment_ExpiredBeyondGracePeriod(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
@@ -2046,100 +2420,75 @@ func TestUpdateBookingStatus_Paid_Success(t *testing.T) {
 		mockNotificationClient,
 		mockDelayedQueue,
 		mockSeatLockService,
+		NewCancellationPolicyService(testRefundTiers),
+		service_mocks.NewMockBookingExchangeService(ctrl),
+		service_mocks.NewMockPromotionService(ctrl),
 	)
 
 	ctx := context.Background()
 	bookingID := uuid.New()
-	tripID := uuid.New()
-	userID := uuid.New()
 
+	// Expired 2 hours ago
+	expiredTime := time.Now().UTC().Add(-2 * time.Hour)
 	booking := &model.Booking{
-		BaseModel:        model.BaseModel{ID: bookingID},
-		TripID:           tripID,
-		UserID:           userID,
-		Status:           model.BookingStatusPending,
-		BookingSeats:     []model.BookingSeat{{SeatNumber: "A1"}},
-		TotalAmount:      100000,
-		BookingReference: "BKREF",
-	}
-
-	req := &model.UpdateBookingStatusRequest{
-		TransactionStatus: payment.TransactionStatusPaid,
-	}
-
-	tripData := &trip.Trip{
-		ID:            tripID,
-		DepartureTime: time.Now().Add(24 * time.Hour), // Future trip
-		Route:         &trip.Route{Origin: "A", Destination: "B"},
-	}
-
-	userData := &user.User{
-		ID:       userID,
-		Email:    "test@example.com",
-		FullName: "Test User",
+		BaseModel: model.BaseModel{ID: bookingID},
+		Status:    model.BookingStatusExpired,
+		ExpiresAt: &expiredTime,
 	}
 
-	// 1. Get Booking
 	mockBookingRepo.EXPECT().
 		GetBookingByID(ctx, bookingID).
 		Return(booking, nil).
 		Times(1)
 
-	// 2. Update Booking Status
-	mockBookingRepo.EXPECT().
-		UpdateBooking(ctx, gomock.Any()).
-		Do(func(_ context.Context, b *model.Booking) {
-			assert.Equal(t, model.BookingStatusConfirmed, b.Status)
-		}).
-		Return(nil).
-		Times(1)
+	result, err := service.RetryPayment(ctx, bookingID)
 
-	// 3. Async: Send Confirmation Email
-	// Need to expect these to be called asynchronously.
-	// Since we can't easily wait, we mock them and hope the scheduler runs them.
-	// We'll add a short sleep at the end.
+	assert.Error(t, err)
+	assert.Nil(t, result)
+	assert.Contains(t, err.Error(), "expired beyond retry period")
+}
 
-	// Re-fetch booking (inside sendBookingConfirmationEmail)
-	mockBookingRepo.EXPECT().
-		GetBookingByID(gomock.Any(), bookingID).
-		Return(booking, nil).
-		MaxTimes(1)
+// ========== ExpireBooking - Validation paths ==========
 
-	// Fetch User (inside sendBookingConfirmationEmail)
-	mockUserClient.EXPECT().
-		GetUserByID(gomock.Any(), userID).
-		Return(userData, nil).
-		MaxTimes(1)
+func TestExpireBooking_BookingNotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
 
-	// Fetch Trip (inside sendBookingConfirmationEmail AND UpdateBookingStatus for reminder)
-	// UpdateBookingStatus calls GetTripByID for reminder scheduling
-	// sendBookingConfirmationEmail calls GetTripByID for email details
-	mockTripClient.EXPECT().
-		GetTripByID(gomock.Any(), gomock.Any(), tripID).
-		Return(tripData, nil).
-		MinTimes(1)
+	mockBookingRepo := repo_mocks.NewMockBookingRepository(ctrl)
+	mockPaymentClient := mocks.NewMockPaymentClient(ctrl)
+	mockTripClient := mocks.NewMockTripClient(ctrl)
+	mockUserClient := mocks.NewMockUserClient(ctrl)
+	mockNotificationClient := mocks.NewMockNotificationClient(ctrl)
+	mockDelayedQueue := queue_mocks.NewMockDelayedQueueManager(ctrl)
+	mockSeatLockService := service_mocks.NewMockSeatLockService(ctrl)
 
-	// Send Email
-	mockNotificationClient.EXPECT().
-		SendBookingConfirmation(gomock.Any(), gomock.Any()).
-		Return(nil).
-		MaxTimes(1)
+	service := NewBookingService(
+		mockBookingRepo,
+		mockPaymentClient,
+		mockTripClient,
+		mockUserClient,
+		mockNotificationClient,
+		mockDelayedQueue,
+		mockSeatLockService,
+		NewCancellationPolicyService(testRefundTiers),
+		service_mocks.NewMockBookingExchangeService(ctrl),
+		service_mocks.NewMockPromotionService(ctrl),
+	)
 
-	// Schedule Reminder
-	mockDelayedQueue.EXPECT().
-		Schedule(gomock.Any(), "trip_reminder", gomock.Any(), gomock.Any()).
-		Return(nil).
-		MaxTimes(1)
+	ctx := context.Background()
+	bookingID := uuid.New()
 
-	err := service.UpdateBookingStatus(ctx, req, bookingID)
+	mockBookingRepo.EXPECT().
+		GetBookingByID(ctx, bookingID).
+		Return(nil, assert.AnError).
+		Times(1)
 
-	assert.NoError(t, err)
+	err := service.ExpireBooking(ctx, bookingID)
 
-	// Wait for async goroutines
-	time.Sleep(50 * time.Millisecond)
+	assert.Error(t, err)
 }
 
-func TestRetryPayment_Success(t *testing.T) {
+func TestExpireBooking_AlreadyProcessed(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
@@ -2159,90 +2508,1612 @@ func TestRetryPayment_Success(t *testing.T) {
 		mockNotificationClient,
 		mockDelayedQueue,
 		mockSeatLockService,
+		NewCancellationPolicyService(testRefundTiers),
+		service_mocks.NewMockBookingExchangeService(ctrl),
+		service_mocks.NewMockPromotionService(ctrl),
 	)
 
 	ctx := context.Background()
 	bookingID := uuid.New()
-	tripID := uuid.New()
-	userID := uuid.New()
 
+	// Already confirmed - skip expiration
 	booking := &model.Booking{
-		BaseModel:        model.BaseModel{ID: bookingID},
-		TripID:           tripID,
-		UserID:           userID,
-		Status:           model.BookingStatusFailed,
-		BookingReference: "BKREF",
-		TotalAmount:      100000,
-	}
-
-	tripData := &trip.Trip{
-		ID:        tripID,
-		BasePrice: 100000,
-		Route:     &trip.Route{Origin: "A", Destination: "B"},
-	}
-
-	userData := &user.User{
-		ID:       userID,
-		Email:    "test@example.com",
-		FullName: "Test User",
+		BaseModel: model.BaseModel{ID: bookingID},
+		Status:    model.BookingStatusConfirmed,
 	}
 
-	// 1. Get booking
 	mockBookingRepo.EXPECT().
 		GetBookingByID(ctx, bookingID).
 		Return(booking, nil).
 		Times(1)
 
-	// 2. Get Trip
-	mockTripClient.EXPECT().
-		GetTripByID(ctx, gomock.Any(), tripID).
-		Return(tripData, nil).
-		Times(1)
-
-	// 3. Create Transaction
-	transaction := &payment.TransactionResponse{
-		ID:          uuid.New(),
-		CheckoutURL: "http://checkout.url",
-	}
-	mockPaymentClient.EXPECT().
-		CreateTransaction(ctx, gomock.Any()).
-		Return(transaction, nil).
-		Times(1)
+	err := service.ExpireBooking(ctx, bookingID)
 
-	// 4. Update Booking
-	mockBookingRepo.EXPECT().
-		UpdateBooking(ctx, gomock.Any()).
-		Return(nil).
-		Times(1)
+	assert.NoError(t, err) // Returns nil - already processed
+}
 
-	// 5. Async: Schedule Expiry
+func TestListBookings_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockBookingRepo := repo_mocks.NewMockBookingRepository(ctrl)
+	mockPaymentClient := mocks.NewMockPaymentClient(ctrl)
+	mockTripClient := mocks.NewMockTripClient(ctrl)
+	mockUserClient := mocks.NewMockUserClient(ctrl)
+	mockNotificationClient := mocks.NewMockNotificationClient(ctrl)
+	mockDelayedQueue := queue_mocks.NewMockDelayedQueueManager(ctrl)
+	mockSeatLockService := service_mocks.NewMockSeatLockService(ctrl)
+
+	service := NewBookingService(
+		mockBookingRepo,
+		mockPaymentClient,
+		mockTripClient,
+		mockUserClient,
+		mockNotificationClient,
+		mockDelayedQueue,
+		mockSeatLockService,
+		NewCancellationPolicyService(testRefundTiers),
+		service_mocks.NewMockBookingExchangeService(ctrl),
+		service_mocks.NewMockPromotionService(ctrl),
+	)
+
+	ctx := context.Background()
+	tripID1 := uuid.New()
+	tripID2 := uuid.New()
+
+	req := model.ListBookingsRequest{
+		Page:     1,
+		PageSize: 10,
+		SortBy:   "created_at",
+		Order:    "desc",
+	}
+
+	bookings := []*model.Booking{
+		{
+			BaseModel:        model.BaseModel{ID: uuid.New()},
+			BookingReference: "BK001",
+			TripID:           tripID1,
+			UserID:           uuid.New(),
+			TotalAmount:      100000,
+			Status:           model.BookingStatusConfirmed,
+		},
+		{
+			BaseModel:        model.BaseModel{ID: uuid.New()},
+			BookingReference: "BK002",
+			TripID:           tripID2,
+			UserID:           uuid.New(),
+			TotalAmount:      150000,
+			Status:           model.BookingStatusPending,
+		},
+	}
+
+	trips := []trip.Trip{
+		{
+			ID:            tripID1,
+			BasePrice:     100000,
+			DepartureTime: time.Now(),
+			Route: &trip.Route{
+				Origin:      "Ha Noi",
+				Destination: "Da Nang",
+			},
+			Bus: &trip.Bus{
+				Model: "Hyundai Universe",
+			},
+		},
+		{
+			ID:            tripID2,
+			BasePrice:     150000,
+			DepartureTime: time.Now(),
+			Route: &trip.Route{
+				Origin:      "Ho Chi Minh",
+				Destination: "Vung Tau",
+			},
+			Bus: &trip.Bus{
+				Model: "Mercedes Benz",
+			},
+		},
+	}
+
+	mockBookingRepo.EXPECT().
+		ListBookings(ctx, req).
+		Return(bookings, int64(2), nil).
+		Times(1)
+
+	mockTripClient.EXPECT().
+		GetTripsByIDs(ctx, gomock.Any(), gomock.Any()).
+		Return(trips, nil).
+		Times(1)
+
+	result, total, err := service.ListBookings(ctx, req)
+
+	assert.NoError(t, err)
+	assert.Len(t, result, 2)
+	assert.Equal(t, int64(2), total)
+	assert.Equal(t, "BK001", result[0].BookingReference)
+	assert.Equal(t, "BK002", result[1].BookingReference)
+	assert.NotNil(t, result[0].Trip)
+	assert.Equal(t, "Ha Noi", result[0].Trip.Origin)
+	assert.NotNil(t, result[1].Trip)
+	assert.Equal(t, "Ho Chi Minh", result[1].Trip.Origin)
+}
+
+func TestListBookings_EmptyResults(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockBookingRepo := repo_mocks.NewMockBookingRepository(ctrl)
+	mockPaymentClient := mocks.NewMockPaymentClient(ctrl)
+	mockTripClient := mocks.NewMockTripClient(ctrl)
+	mockUserClient := mocks.NewMockUserClient(ctrl)
+	mockNotificationClient := mocks.NewMockNotificationClient(ctrl)
+	mockDelayedQueue := queue_mocks.NewMockDelayedQueueManager(ctrl)
+	mockSeatLockService := service_mocks.NewMockSeatLockService(ctrl)
+
+	service := NewBookingService(
+		mockBookingRepo,
+		mockPaymentClient,
+		mockTripClient,
+		mockUserClient,
+		mockNotificationClient,
+		mockDelayedQueue,
+		mockSeatLockService,
+		NewCancellationPolicyService(testRefundTiers),
+		service_mocks.NewMockBookingExchangeService(ctrl),
+		service_mocks.NewMockPromotionService(ctrl),
+	)
+
+	ctx := context.Background()
+	req := model.ListBookingsRequest{
+		Page:     1,
+		PageSize: 10,
+	}
+
+	mockBookingRepo.EXPECT().
+		ListBookings(ctx, req).
+		Return([]*model.Booking{}, int64(0), nil).
+		Times(1)
+
+	result, total, err := service.ListBookings(ctx, req)
+
+	assert.NoError(t, err)
+	assert.Empty(t, result)
+	assert.Equal(t, int64(0), total)
+}
+
+func TestListBookings_RepositoryError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockBookingRepo := repo_mocks.NewMockBookingRepository(ctrl)
+	mockPaymentClient := mocks.NewMockPaymentClient(ctrl)
+	mockTripClient := mocks.NewMockTripClient(ctrl)
+	mockUserClient := mocks.NewMockUserClient(ctrl)
+	mockNotificationClient := mocks.NewMockNotificationClient(ctrl)
+	mockDelayedQueue := queue_mocks.NewMockDelayedQueueManager(ctrl)
+	mockSeatLockService := service_mocks.NewMockSeatLockService(ctrl)
+
+	service := NewBookingService(
+		mockBookingRepo,
+		mockPaymentClient,
+		mockTripClient,
+		mockUserClient,
+		mockNotificationClient,
+		mockDelayedQueue,
+		mockSeatLockService,
+		NewCancellationPolicyService(testRefundTiers),
+		service_mocks.NewMockBookingExchangeService(ctrl),
+		service_mocks.NewMockPromotionService(ctrl),
+	)
+
+	ctx := context.Background()
+	req := model.ListBookingsRequest{
+		Page:     1,
+		PageSize: 10,
+	}
+
+	mockBookingRepo.EXPECT().
+		ListBookings(ctx, req).
+		Return(nil, int64(0), assert.AnError).
+		Times(1)
+
+	result, total, err := service.ListBookings(ctx, req)
+
+	assert.Error(t, err)
+	assert.Nil(t, result)
+	assert.Equal(t, int64(0), total)
+}
+
+func TestListBookings_TripFetchError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockBookingRepo := repo_mocks.NewMockBookingRepository(ctrl)
+	mockPaymentClient := mocks.NewMockPaymentClient(ctrl)
+	mockTripClient := mocks.NewMockTripClient(ctrl)
+	mockUserClient := mocks.NewMockUserClient(ctrl)
+	mockNotificationClient := mocks.NewMockNotificationClient(ctrl)
+	mockDelayedQueue := queue_mocks.NewMockDelayedQueueManager(ctrl)
+	mockSeatLockService := service_mocks.NewMockSeatLockService(ctrl)
+
+	service := NewBookingService(
+		mockBookingRepo,
+		mockPaymentClient,
+		mockTripClient,
+		mockUserClient,
+		mockNotificationClient,
+		mockDelayedQueue,
+		mockSeatLockService,
+		NewCancellationPolicyService(testRefundTiers),
+		service_mocks.NewMockBookingExchangeService(ctrl),
+		service_mocks.NewMockPromotionService(ctrl),
+	)
+
+	ctx := context.Background()
+	tripID := uuid.New()
+
+	req := model.ListBookingsRequest{
+		Page:     1,
+		PageSize: 10,
+	}
+
+	bookings := []*model.Booking{
+		{
+			BaseModel:        model.BaseModel{ID: uuid.New()},
+			BookingReference: "BK001",
+			TripID:           tripID,
+			UserID:           uuid.New(),
+			TotalAmount:      100000,
+			Status:           model.BookingStatusConfirmed,
+		},
+	}
+
+	mockBookingRepo.EXPECT().
+		ListBookings(ctx, req).
+		Return(bookings, int64(1), nil).
+		Times(1)
+
+	// Trip fetch fails but should not cause entire operation to fail
+	mockTripClient.EXPECT().
+		GetTripsByIDs(ctx, gomock.Any(), gomock.Any()).
+		Return(nil, assert.AnError).
+		Times(1)
+
+	result, total, err := service.ListBookings(ctx, req)
+
+	assert.NoError(t, err)
+	assert.Len(t, result, 1)
+	assert.Equal(t, int64(1), total)
+	assert.Equal(t, "BK001", result[0].BookingReference)
+	assert.Nil(t, result[0].Trip) // Trip should be nil when fetch fails
+}
+
+func TestGetTripPassengers_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockBookingRepo := repo_mocks.NewMockBookingRepository(ctrl)
+	mockUserClient := mocks.NewMockUserClient(ctrl)
+	mockPaymentClient := mocks.NewMockPaymentClient(ctrl)
+	mockTripClient := mocks.NewMockTripClient(ctrl)
+	mockNotificationClient := mocks.NewMockNotificationClient(ctrl)
+	mockDelayedQueue := queue_mocks.NewMockDelayedQueueManager(ctrl)
+	mockSeatLockService := service_mocks.NewMockSeatLockService(ctrl)
+
+	service := NewBookingService(
+		mockBookingRepo,
+		mockPaymentClient,
+		mockTripClient,
+		mockUserClient,
+		mockNotificationClient,
+		mockDelayedQueue,
+		mockSeatLockService,
+		NewCancellationPolicyService(testRefundTiers),
+		service_mocks.NewMockBookingExchangeService(ctrl),
+		service_mocks.NewMockPromotionService(ctrl),
+	)
+
+	ctx := context.Background()
+	tripID := uuid.New()
+	userID := uuid.New()
+	bookingID := uuid.New()
+
+	bookings := []*model.Booking{
+		{
+			BaseModel:   model.BaseModel{ID: bookingID},
+			TripID:      tripID,
+			UserID:      userID,
+			Status:      model.BookingStatusConfirmed,
+			TotalAmount: 100000,
+			BookingSeats: []model.BookingSeat{
+				{SeatNumber: "A1", Price: 100000},
+			},
+			BookingReference: "BKREF123",
+		},
+	}
+
+	userResp := &user.User{
+		ID:       userID,
+		FullName: "Test Passenger",
+		Email:    "passenger@example.com",
+		Phone:    "0987654321",
+	}
+
+	mockBookingRepo.EXPECT().
+		GetAllActiveBookingsByTripID(ctx, tripID).
+		Return(bookings, nil).
+		Times(1)
+
+	mockUserClient.EXPECT().
+		GetUserByID(gomock.Any(), userID).
+		Return(userResp, nil).
+		Times(1)
+
+	result, err := service.GetTripPassengers(ctx, tripID)
+
+	assert.NoError(t, err)
+	assert.Len(t, result, 1)
+	assert.Equal(t, "Test Passenger", result[0].FullName)
+	assert.Equal(t, "A1", result[0].Seats[0])
+}
+
+func TestCheckInPassenger_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockBookingRepo := repo_mocks.NewMockBookingRepository(ctrl)
+	mockPaymentClient := mocks.NewMockPaymentClient(ctrl)
+	mockTripClient := mocks.NewMockTripClient(ctrl)
+	mockUserClient := mocks.NewMockUserClient(ctrl)
+	mockNotificationClient := mocks.NewMockNotificationClient(ctrl)
+	mockDelayedQueue := queue_mocks.NewMockDelayedQueueManager(ctrl)
+	mockSeatLockService := service_mocks.NewMockSeatLockService(ctrl)
+
+	service := NewBookingService(
+		mockBookingRepo,
+		mockPaymentClient,
+		mockTripClient,
+		mockUserClient,
+		mockNotificationClient,
+		mockDelayedQueue,
+		mockSeatLockService,
+		NewCancellationPolicyService(testRefundTiers),
+		service_mocks.NewMockBookingExchangeService(ctrl),
+		service_mocks.NewMockPromotionService(ctrl),
+	)
+
+	ctx := context.Background()
+	bookingID := uuid.New()
+
+	booking := &model.Booking{
+		BaseModel: model.BaseModel{ID: bookingID},
+		Status:    model.BookingStatusConfirmed,
+		IsBoarded: false,
+	}
+
+	mockBookingRepo.EXPECT().
+		GetBookingByID(ctx, bookingID).
+		Return(booking, nil).
+		Times(1)
+
+	mockBookingRepo.EXPECT().
+		CheckInPassenger(ctx, bookingID).
+		Return(nil).
+		Times(1)
+
+	err := service.CheckInPassenger(ctx, bookingID)
+
+	assert.NoError(t, err)
+}
+
+func TestUpdateBookingStatus_Paid_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockBookingRepo := repo_mocks.NewMockBookingRepository(ctrl)
+	mockPaymentClient := mocks.NewMockPaymentClient(ctrl)
+	mockTripClient := mocks.NewMockTripClient(ctrl)
+	mockUserClient := mocks.NewMockUserClient(ctrl)
+	mockNotificationClient := mocks.NewMockNotificationClient(ctrl)
+	mockDelayedQueue := queue_mocks.NewMockDelayedQueueManager(ctrl)
+	mockSeatLockService := service_mocks.NewMockSeatLockService(ctrl)
+
+	service := NewBookingService(
+		mockBookingRepo,
+		mockPaymentClient,
+		mockTripClient,
+		mockUserClient,
+		mockNotificationClient,
+		mockDelayedQueue,
+		mockSeatLockService,
+		NewCancellationPolicyService(testRefundTiers),
+		service_mocks.NewMockBookingExchangeService(ctrl),
+		service_mocks.NewMockPromotionService(ctrl),
+	)
+
+	ctx := context.Background()
+	bookingID := uuid.New()
+	tripID := uuid.New()
+	userID := uuid.New()
+
+	booking := &model.Booking{
+		BaseModel:        model.BaseModel{ID: bookingID},
+		TripID:           tripID,
+		UserID:           userID,
+		Status:           model.BookingStatusPending,
+		BookingSeats:     []model.BookingSeat{{SeatNumber: "A1"}},
+		TotalAmount:      100000,
+		BookingReference: "BKREF",
+	}
+
+	req := &model.UpdateBookingStatusRequest{
+		TransactionStatus: payment.TransactionStatusPaid,
+	}
+
+	tripData := &trip.Trip{
+		ID:            tripID,
+		DepartureTime: time.Now().Add(24 * time.Hour), // Future trip
+		Route:         &trip.Route{Origin: "A", Destination: "B"},
+	}
+
+	userData := &user.User{
+		ID:       userID,
+		Email:    "test@example.com",
+		FullName: "Test User",
+	}
+
+	// 1. Get Booking
+	mockBookingRepo.EXPECT().
+		GetBookingByID(ctx, bookingID).
+		Return(booking, nil).
+		Times(1)
+
+	// 2. Update Booking Status
+	mockBookingRepo.EXPECT().
+		UpdateBooking(ctx, gomock.Any()).
+		Do(func(_ context.Context, b *model.Booking) {
+			assert.Equal(t, model.BookingStatusConfirmed, b.Status)
+		}).
+		Return(nil).
+		Times(1)
+
+	// 3. Async: Send Confirmation Email
+	// Need to expect these to be called asynchronously.
+	// Since we can't easily wait, we mock them and hope the scheduler runs them.
+	// We'll add a short sleep at the end.
+
+	// Re-fetch booking (inside sendBookingConfirmationEmail)
+	mockBookingRepo.EXPECT().
+		GetBookingByID(gomock.Any(), bookingID).
+		Return(booking, nil).
+		MaxTimes(1)
+
+	// Fetch User (inside sendBookingConfirmationEmail)
+	mockUserClient.EXPECT().
+		GetUserByID(gomock.Any(), userID).
+		Return(userData, nil).
+		MaxTimes(1)
+
+	// Fetch Trip (inside sendBookingConfirmationEmail AND UpdateBookingStatus for reminder)
+	// UpdateBookingStatus calls GetTripByID for reminder scheduling
+	// sendBookingConfirmationEmail calls GetTripByID for email details
+	mockTripClient.EXPECT().
+		GetTripByID(gomock.Any(), gomock.Any(), tripID).
+		Return(tripData, nil).
+		MinTimes(1)
+
+	// Send Email
+	mockNotificationClient.EXPECT().
+		SendBookingConfirmation(gomock.Any(), gomock.Any()).
+		Return(nil).
+		MaxTimes(1)
+
+	// Schedule Reminder
+	mockDelayedQueue.EXPECT().
+		Schedule(gomock.Any(), "trip_reminder", gomock.Any(), gomock.Any()).
+		Return(nil).
+		MaxTimes(1)
+
+	err := service.UpdateBookingStatus(ctx, req, bookingID)
+
+	assert.NoError(t, err)
+
+	// Wait for async goroutines
+	time.Sleep(50 * time.Millisecond)
+}
+
+func TestRetryPayment_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockBookingRepo := repo_mocks.NewMockBookingRepository(ctrl)
+	mockPaymentClient := mocks.NewMockPaymentClient(ctrl)
+	mockTripClient := mocks.NewMockTripClient(ctrl)
+	mockUserClient := mocks.NewMockUserClient(ctrl)
+	mockNotificationClient := mocks.NewMockNotificationClient(ctrl)
+	mockDelayedQueue := queue_mocks.NewMockDelayedQueueManager(ctrl)
+	mockSeatLockService := service_mocks.NewMockSeatLockService(ctrl)
+
+	service := NewBookingService(
+		mockBookingRepo,
+		mockPaymentClient,
+		mockTripClient,
+		mockUserClient,
+		mockNotificationClient,
+		mockDelayedQueue,
+		mockSeatLockService,
+		NewCancellationPolicyService(testRefundTiers),
+		service_mocks.NewMockBookingExchangeService(ctrl),
+		service_mocks.NewMockPromotionService(ctrl),
+	)
+
+	ctx := context.Background()
+	bookingID := uuid.New()
+	tripID := uuid.New()
+	userID := uuid.New()
+
+	booking := &model.Booking{
+		BaseModel:        model.BaseModel{ID: bookingID},
+		TripID:           tripID,
+		UserID:           userID,
+		Status:           model.BookingStatusFailed,
+		BookingReference: "BKREF",
+		TotalAmount:      100000,
+		TransactionID:    uuid.New(),
+	}
+
+	tripData := &trip.Trip{
+		ID:        tripID,
+		BasePrice: 100000,
+		Route:     &trip.Route{Origin: "A", Destination: "B"},
+	}
+
+	userData := &user.User{
+		ID:       userID,
+		Email:    "test@example.com",
+		FullName: "Test User",
+	}
+
+	// 1. Get booking
+	mockBookingRepo.EXPECT().
+		GetBookingByID(ctx, bookingID).
+		Return(booking, nil).
+		Times(1)
+
+	// 2. Get Trip
+	mockTripClient.EXPECT().
+		GetTripByID(ctx, gomock.Any(), tripID).
+		Return(tripData, nil).
+		Times(1)
+
+	// 3. Get the failed transaction, whose invoice request and wallet use carry over
+	invoiceBuyer := &payment.InvoiceBuyer{CompanyName: "Cong ty ABC", TaxCode: "0312345678", Address: "1 Le Loi, Q1"}
+	mockPaymentClient.EXPECT().
+		GetTransactionByID(ctx, booking.TransactionID).
+		Return(&payment.TransactionResponse{ID: booking.TransactionID, InvoiceBuyer: invoiceBuyer, WalletAmount: 30000}, nil).
+		Times(1)
+
+	// 4. Create Transaction
+	transaction := &payment.TransactionResponse{
+		ID:          uuid.New(),
+		CheckoutURL: "http://checkout.url",
+	}
+	mockPaymentClient.EXPECT().
+		CreateTransaction(ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, req *payment.CreateTransactionRequest) (*payment.TransactionResponse, error) {
+			assert.Equal(t, invoiceBuyer, req.InvoiceBuyer)
+			assert.True(t, req.UseWallet)
+			return transaction, nil
+		}).
+		Times(1)
+
+	// 5. Update Booking
+	mockBookingRepo.EXPECT().
+		UpdateBooking(ctx, gomock.Any()).
+		Return(nil).
+		Times(1)
+
+	// 6. Async: Schedule Expiry
+	mockDelayedQueue.EXPECT().
+		Schedule(gomock.Any(), "booking_expiry", gomock.Any(), gomock.Any()).
+		Return(nil).
+		MaxTimes(1)
+
+	// 7. Async: Send Pending Email
+	// Re-fetch user
+	mockUserClient.EXPECT().
+		GetUserByID(gomock.Any(), userID).
+		Return(userData, nil).
+		MaxTimes(1)
+
+	// Send Email
+	mockNotificationClient.EXPECT().
+		SendBookingPending(gomock.Any(), gomock.Any()).
+		Return(nil).
+		MaxTimes(1)
+
+	result, err := service.RetryPayment(ctx, bookingID)
+
+	assert.NoError(t, err)
+	assert.NotNil(t, result)
+
+	time.Sleep(50 * time.Millisecond)
+}
+
+func TestExpireBooking_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockBookingRepo := repo_mocks.NewMockBookingRepository(ctrl)
+	mockPaymentClient := mocks.NewMockPaymentClient(ctrl)
+	mockTripClient := mocks.NewMockTripClient(ctrl)
+	mockUserClient := mocks.NewMockUserClient(ctrl)
+	mockNotificationClient := mocks.NewMockNotificationClient(ctrl)
+	mockDelayedQueue := queue_mocks.NewMockDelayedQueueManager(ctrl)
+	mockSeatLockService := service_mocks.NewMockSeatLockService(ctrl)
+
+	service := NewBookingService(
+		mockBookingRepo,
+		mockPaymentClient,
+		mockTripClient,
+		mockUserClient,
+		mockNotificationClient,
+		mockDelayedQueue,
+		mockSeatLockService,
+		NewCancellationPolicyService(testRefundTiers),
+		service_mocks.NewMockBookingExchangeService(ctrl),
+		service_mocks.NewMockPromotionService(ctrl),
+	)
+
+	ctx := context.Background()
+	bookingID := uuid.New()
+	userID := uuid.New()
+	tripID := uuid.New()
+
+	// Past grace period
+	expiredAt := time.Now().Add(-1 * time.Hour)
+
+	booking := &model.Booking{
+		BaseModel:     model.BaseModel{ID: bookingID},
+		Status:        model.BookingStatusPending,
+		ExpiresAt:     &expiredAt,
+		TransactionID: uuid.New(),
+		UserID:        userID,
+		TripID:        tripID,
+	}
+
+	userData := &user.User{ID: userID, Email: "test@example.com", FullName: "Test"}
+	tripData := &trip.Trip{ID: tripID, Route: &trip.Route{Origin: "A", Destination: "B"}}
+
+	// 1. Get Booking
+	mockBookingRepo.EXPECT().
+		GetBookingByID(ctx, bookingID).
+		Return(booking, nil).
+		Times(1)
+
+	// 2. Update Booking, the payment is cancelled through the outbox
+	mockPaymentClient.EXPECT().CancelTransaction(gomock.Any(), gomock.Any()).Times(0)
+	mockBookingRepo.EXPECT().
+		UpdateBookingsWithEvents(ctx, gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, bookings []*model.Booking, events ...*outbox.Event) error {
+			assert.Len(t, bookings, 1)
+			assert.Equal(t, model.BookingStatusExpired, bookings[0].Status)
+			if assert.Len(t, events, 1) {
+				assert.Equal(t, model.EventTypePaymentCancelDue, events[0].EventType)
+				var payload model.PaymentCancelDueEvent
+				assert.NoError(t, events[0].Decode(&payload))
+				assert.Equal(t, booking.TransactionID, payload.TransactionID)
+			}
+			return nil
+		}).
+		Times(1)
+
+	// 4. Async: Send Failure Email
+	// Fetch booking
+	mockBookingRepo.EXPECT().
+		GetBookingByID(gomock.Any(), bookingID).
+		Return(booking, nil).
+		MaxTimes(1)
+
+	// Fetch User
+	mockUserClient.EXPECT().
+		GetUserByID(gomock.Any(), userID).
+		Return(userData, nil).
+		MaxTimes(1)
+
+	// Fetch Trip
+	mockTripClient.EXPECT().
+		GetTripByID(gomock.Any(), gomock.Any(), tripID).
+		Return(tripData, nil).
+		MaxTimes(1)
+
+	// Send Email
+	mockNotificationClient.EXPECT().
+		SendBookingFailure(gomock.Any(), gomock.Any()).
+		Return(nil).
+		MaxTimes(1)
+
+	// The released seats go to the trip's waitlist
+	mockDelayedQueue.EXPECT().
+		ScheduleEarliest(ctx, constants.QueueNameWaitlistOffer, gomock.Any(), gomock.Any()).
+		Return(nil)
+
+	err := service.ExpireBooking(ctx, bookingID)
+
+	assert.NoError(t, err)
+
+	time.Sleep(50 * time.Millisecond)
+}
+
+func TestCreateBooking_StoresPassengerDetails(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockBookingRepo := repo_mocks.NewMockBookingRepository(ctrl)
+	mockPaymentClient := mocks.NewMockPaymentClient(ctrl)
+	mockTripClient := mocks.NewMockTripClient(ctrl)
+
+	service := NewBookingService(
+		mockBookingRepo,
+		mockPaymentClient,
+		mockTripClient,
+		mocks.NewMockUserClient(ctrl),
+		mocks.NewMockNotificationClient(ctrl),
+		queue_mocks.NewMockDelayedQueueManager(ctrl),
+		quotingSeatLockService(ctrl, 100000),
+		NewCancellationPolicyService(testRefundTiers),
+		service_mocks.NewMockBookingExchangeService(ctrl),
+		service_mocks.NewMockPromotionService(ctrl),
+	)
+
+	ctx := context.Background()
+	tripID := uuid.New()
+	seatA1 := uuid.New()
+	seatA2 := uuid.New()
+
+	req := &model.CreateBookingRequest{
+		TripID:  tripID,
+		SeatIDs: []uuid.UUID{seatA1, seatA2},
+		Passengers: []model.PassengerInfo{
+			{SeatID: seatA2, FullName: " Tran Thi B ", IDNumber: "079123456789", AgeCategory: model.PassengerAgeCategoryChild},
+		},
+	}
+
+	mockBookingRepo.EXPECT().GetBookedSeatIDs(ctx, tripID, model.FullTripSegment()).Return([]uuid.UUID{}, nil)
+	mockTripClient.EXPECT().GetTripByID(gomock.Any(), gomock.Any(), tripID).Return(&trip.Trip{ID: tripID, BasePrice: 100000}, nil)
+	mockTripClient.EXPECT().ListSeatsByIDs(gomock.Any(), gomock.Any()).Return([]trip.Seat{
+		{ID: seatA1, SeatNumber: "A1", PriceMultiplier: 1.0},
+		{ID: seatA2, SeatNumber: "A2", PriceMultiplier: 1.0},
+	}, nil)
+
+	var saved *model.Booking
+	mockBookingRepo.EXPECT().CreateBookingFromHold(ctx, gomock.Any(), gomock.Any(), model.FullTripSegment()).DoAndReturn(func(_ context.Context, booking *model.Booking, _ string, _ model.TripSegment) error {
+		saved = booking
+		return nil
+	})
+	mockPaymentClient.EXPECT().CreateTransaction(ctx, gomock.Any()).Return(&payment.TransactionResponse{}, nil)
+
+	result, err := service.CreateBooking(ctx, req, uuid.New())
+
+	assert.NoError(t, err)
+	assert.Len(t, saved.BookingSeats, 2)
+	assert.Empty(t, saved.BookingSeats[0].PassengerName)
+	assert.Equal(t, "Tran Thi B", saved.BookingSeats[1].PassengerName)
+	assert.Equal(t, "079123456789", saved.BookingSeats[1].PassengerIDNumber)
+	assert.Equal(t, model.PassengerAgeCategoryChild, saved.BookingSeats[1].PassengerAgeCategory)
+	assert.Equal(t, "Tran Thi B", result.Seats[1].PassengerName)
+}
+
+func TestCreateBooking_PayAtCounter(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockBookingRepo := repo_mocks.NewMockBookingRepository(ctrl)
+	mockPaymentClient := mocks.NewMockPaymentClient(ctrl)
+	mockTripClient := mocks.NewMockTripClient(ctrl)
+	mockDelayedQueue := queue_mocks.NewMockDelayedQueueManager(ctrl)
+
+	service := NewBookingService(
+		mockBookingRepo,
+		mockPaymentClient,
+		mockTripClient,
+		mocks.NewMockUserClient(ctrl),
+		mocks.NewMockNotificationClient(ctrl),
+		mockDelayedQueue,
+		quotingSeatLockService(ctrl, 100000),
+		NewCancellationPolicyService(testRefundTiers),
+		service_mocks.NewMockBookingExchangeService(ctrl),
+		service_mocks.NewMockPromotionService(ctrl),
+	)
+
+	ctx := context.Background()
+	tripID := uuid.New()
+	seatID := uuid.New()
+
+	req := &model.CreateBookingRequest{
+		TripID:        tripID,
+		SeatIDs:       []uuid.UUID{seatID},
+		PaymentMethod: payment.PaymentMethodCash,
+	}
+
+	mockBookingRepo.EXPECT().GetBookedSeatIDs(ctx, tripID, model.FullTripSegment()).Return([]uuid.UUID{}, nil)
+	mockTripClient.EXPECT().GetTripByID(gomock.Any(), gomock.Any(), tripID).Return(&trip.Trip{ID: tripID, BasePrice: 100000}, nil)
+	mockTripClient.EXPECT().ListSeatsByIDs(gomock.Any(), gomock.Any()).Return([]trip.Seat{
+		{ID: seatID, SeatNumber: "A1", PriceMultiplier: 1.0},
+	}, nil)
+	mockBookingRepo.EXPECT().CreateBookingFromHold(ctx, gomock.Any(), gomock.Any(), model.FullTripSegment()).Return(nil)
+	mockPaymentClient.EXPECT().
+		CreateTransaction(ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, req *payment.CreateTransactionRequest) (*payment.TransactionResponse, error) {
+			assert.Equal(t, payment.PaymentMethodCash, req.PaymentMethod)
+			return &payment.TransactionResponse{ID: req.ID, PaymentMethod: req.PaymentMethod, Status: payment.TransactionStatusPending}, nil
+		})
+
+	// No checkout link to email, but the booking still expires when nobody pays at the counter
+	scheduled := make(chan struct{})
+	mockDelayedQueue.EXPECT().
+		Schedule(gomock.Any(), constants.QueueNameBookingExpiry, gomock.Any(), gomock.Any()).
+		DoAndReturn(func(context.Context, string, *queue.DelayedItem, time.Time) error {
+			close(scheduled)
+			return nil
+		})
+
+	result, err := service.CreateBooking(ctx, req, uuid.New())
+
+	assert.NoError(t, err)
+	assert.Equal(t, payment.PaymentMethodCash, result.PaymentMethod)
+	assert.Empty(t, result.Transaction.CheckoutURL)
+
+	select {
+	case <-scheduled:
+	case <-time.After(time.Second):
+		t.Fatal("booking expiration was not scheduled")
+	}
+}
+
+func TestCreateBooking_PassengerSeatNotBooked(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service := NewBookingService(
+		repo_mocks.NewMockBookingRepository(ctrl),
+		mocks.NewMockPaymentClient(ctrl),
+		mocks.NewMockTripClient(ctrl),
+		mocks.NewMockUserClient(ctrl),
+		mocks.NewMockNotificationClient(ctrl),
+		queue_mocks.NewMockDelayedQueueManager(ctrl),
+		service_mocks.NewMockSeatLockService(ctrl),
+		NewCancellationPolicyService(testRefundTiers),
+		service_mocks.NewMockBookingExchangeService(ctrl),
+		service_mocks.NewMockPromotionService(ctrl),
+	)
+
+	req := &model.CreateBookingRequest{
+		TripID:  uuid.New(),
+		SeatIDs: []uuid.UUID{uuid.New()},
+		Passengers: []model.PassengerInfo{
+			{SeatID: uuid.New(), FullName: "Nguyen Van A"},
+		},
+	}
+
+	result, err := service.CreateBooking(context.Background(), req, uuid.New())
+
+	assert.Error(t, err)
+	assert.Nil(t, result)
+	assert.Contains(t, err.Error(), "not part of this booking")
+}
+
+func TestCreateBooking_DuplicatePassengerSeat(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service := NewBookingService(
+		repo_mocks.NewMockBookingRepository(ctrl),
+		mocks.NewMockPaymentClient(ctrl),
+		mocks.NewMockTripClient(ctrl),
+		mocks.NewMockUserClient(ctrl),
+		mocks.NewMockNotificationClient(ctrl),
+		queue_mocks.NewMockDelayedQueueManager(ctrl),
+		service_mocks.NewMockSeatLockService(ctrl),
+		NewCancellationPolicyService(testRefundTiers),
+		service_mocks.NewMockBookingExchangeService(ctrl),
+		service_mocks.NewMockPromotionService(ctrl),
+	)
+
+	seatID := uuid.New()
+	req := &model.CreateBookingRequest{
+		TripID:  uuid.New(),
+		SeatIDs: []uuid.UUID{seatID},
+		Passengers: []model.PassengerInfo{
+			{SeatID: seatID, FullName: "Nguyen Van A"},
+			{SeatID: seatID, FullName: "Nguyen Van B"},
+		},
+	}
+
+	result, err := service.CreateBooking(context.Background(), req, uuid.New())
+
+	assert.Error(t, err)
+	assert.Nil(t, result)
+	assert.Contains(t, err.Error(), "more than one passenger")
+}
+
+func TestGetTripManifest_SortedWithBookerFallback(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockBookingRepo := repo_mocks.NewMockBookingRepository(ctrl)
+	mockTripClient := mocks.NewMockTripClient(ctrl)
+	mockUserClient := mocks.NewMockUserClient(ctrl)
+
+	service := NewBookingService(
+		mockBookingRepo,
+		mocks.NewMockPaymentClient(ctrl),
+		mockTripClient,
+		mockUserClient,
+		mocks.NewMockNotificationClient(ctrl),
+		queue_mocks.NewMockDelayedQueueManager(ctrl),
+		service_mocks.NewMockSeatLockService(ctrl),
+		NewCancellationPolicyService(testRefundTiers),
+		service_mocks.NewMockBookingExchangeService(ctrl),
+		service_mocks.NewMockPromotionService(ctrl),
+	)
+
+	ctx := context.Background()
+	tripID := uuid.New()
+	bookerID := uuid.New()
+	departure := time.Date(2025, 6, 2, 8, 0, 0, 0, time.UTC)
+
+	bookings := []*model.Booking{
+		{
+			BaseModel:        model.BaseModel{ID: uuid.New()},
+			TripID:           tripID,
+			UserID:           bookerID,
+			BookingReference: "BKGROUP",
+			Status:           model.BookingStatusConfirmed,
+			BookingSeats: []model.BookingSeat{
+				{SeatNumber: "B2", PassengerName: "Tran Thi B", PassengerIDNumber: "079123456789", IsBoarded: true},
+				{SeatNumber: "A1"},
+			},
+		},
+	}
+
+	mockTripClient.EXPECT().GetTripByID(ctx, gomock.Any(), tripID).Return(&trip.Trip{
+		ID:            tripID,
+		DepartureTime: departure,
+		Route:         &trip.Route{Origin: "Ha Noi", Destination: "Vinh"},
+		Bus:           &trip.Bus{PlateNumber: "29B-12345"},
+	}, nil)
+	mockBookingRepo.EXPECT().GetAllActiveBookingsByTripID(ctx, tripID).Return(bookings, nil)
+	mockUserClient.EXPECT().GetUserByID(gomock.Any(), bookerID).Return(&user.User{
+		ID:       bookerID,
+		FullName: "Nguyen Van A",
+		Phone:    "0987654321",
+	}, nil)
+
+	manifest, err := service.GetTripManifest(ctx, tripID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Ha Noi", manifest.Origin)
+	assert.Equal(t, "29B-12345", manifest.BusPlateNumber)
+	assert.Equal(t, 2, manifest.TotalPassengers)
+	assert.Equal(t, 1, manifest.BoardedCount)
+	assert.Equal(t, "A1", manifest.Entries[0].SeatNumber)
+	assert.Equal(t, "Nguyen Van A", manifest.Entries[0].FullName)
+	assert.Equal(t, "0987654321", manifest.Entries[0].Phone)
+	assert.Equal(t, "B2", manifest.Entries[1].SeatNumber)
+	assert.Equal(t, "Tran Thi B", manifest.Entries[1].FullName)
+	assert.Equal(t, "079123456789", manifest.Entries[1].IDNumber)
+	assert.Equal(t, "BKGROUP", manifest.Entries[1].BookingReference)
+}
+
+func TestCreateBooking_SeatHeldByAnotherSession(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockBookingRepo := repo_mocks.NewMockBookingRepository(ctrl)
+	mockTripClient := mocks.NewMockTripClient(ctrl)
+
+	service := NewBookingService(
+		mockBookingRepo,
+		mocks.NewMockPaymentClient(ctrl),
+		mockTripClient,
+		mocks.NewMockUserClient(ctrl),
+		mocks.NewMockNotificationClient(ctrl),
+		queue_mocks.NewMockDelayedQueueManager(ctrl),
+		quotingSeatLockService(ctrl, 100000),
+		NewCancellationPolicyService(testRefundTiers),
+		service_mocks.NewMockBookingExchangeService(ctrl),
+		service_mocks.NewMockPromotionService(ctrl),
+	)
+
+	ctx := context.Background()
+	tripID := uuid.New()
+	seatID := uuid.New()
+
+	req := &model.CreateBookingRequest{
+		TripID:    tripID,
+		SeatIDs:   []uuid.UUID{seatID},
+		SessionID: "checkout-session",
+	}
+
+	mockBookingRepo.EXPECT().GetBookedSeatIDs(ctx, tripID, model.FullTripSegment()).Return([]uuid.UUID{}, nil)
+	mockTripClient.EXPECT().GetTripByID(gomock.Any(), gomock.Any(), tripID).Return(&trip.Trip{ID: tripID, BasePrice: 100000}, nil)
+	mockTripClient.EXPECT().ListSeatsByIDs(gomock.Any(), gomock.Any()).Return([]trip.Seat{
+		{ID: seatID, SeatNumber: "A1", PriceMultiplier: 1.0},
+	}, nil)
+
+	// Another session grabbed the seat between the availability check and the save
+	mockBookingRepo.EXPECT().
+		CreateBookingFromHold(ctx, gomock.Any(), "checkout-session", model.FullTripSegment()).
+		Return(model.ErrSeatsUnavailable)
+
+	result, err := service.CreateBooking(ctx, req, uuid.New())
+
+	assert.Nil(t, result)
+	var apiErr *ginext.Error
+	assert.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusConflict, apiErr.Code)
+}
+
+func TestUpdateBookingStatus_DuplicateEventSkipped(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockBookingRepo := repo_mocks.NewMockBookingRepository(ctrl)
+
+	service := NewBookingService(
+		mockBookingRepo,
+		mocks.NewMockPaymentClient(ctrl),
+		mocks.NewMockTripClient(ctrl),
+		mocks.NewMockUserClient(ctrl),
+		mocks.NewMockNotificationClient(ctrl),
+		queue_mocks.NewMockDelayedQueueManager(ctrl),
+		service_mocks.NewMockSeatLockService(ctrl),
+		NewCancellationPolicyService(testRefundTiers),
+		service_mocks.NewMockBookingExchangeService(ctrl),
+		service_mocks.NewMockPromotionService(ctrl),
+	)
+
+	ctx := context.Background()
+	bookingID := uuid.New()
+
+	req := &model.UpdateBookingStatusRequest{
+		TransactionStatus: payment.TransactionStatusCancelled,
+		EventID:           uuid.New(),
+	}
+
+	mockBookingRepo.EXPECT().
+		IsEventProcessed(ctx, req.EventID).
+		Return(true, nil).
+		Times(1)
+
+	// A redelivered event must not touch the booking again
+	mockBookingRepo.EXPECT().GetBookingByID(gomock.Any(), gomock.Any()).Times(0)
+	mockBookingRepo.EXPECT().UpdateBookingForEvent(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+	err := service.UpdateBookingStatus(ctx, req, bookingID)
+
+	assert.NoError(t, err)
+}
+
+func TestUpdateBookingStatus_RecordsEvent(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockBookingRepo := repo_mocks.NewMockBookingRepository(ctrl)
+
+	mockDelayedQueue := queue_mocks.NewMockDelayedQueueManager(ctrl)
+
+	service := NewBookingService(
+		mockBookingRepo,
+		mocks.NewMockPaymentClient(ctrl),
+		mocks.NewMockTripClient(ctrl),
+		mocks.NewMockUserClient(ctrl),
+		mocks.NewMockNotificationClient(ctrl),
+		mockDelayedQueue,
+		service_mocks.NewMockSeatLockService(ctrl),
+		NewCancellationPolicyService(testRefundTiers),
+		service_mocks.NewMockBookingExchangeService(ctrl),
+		service_mocks.NewMockPromotionService(ctrl),
+	)
+
+	ctx := context.Background()
+	bookingID := uuid.New()
+
+	booking := &model.Booking{
+		BaseModel: model.BaseModel{ID: bookingID},
+		Status:    model.BookingStatusPending,
+	}
+
+	req := &model.UpdateBookingStatusRequest{
+		TransactionStatus: payment.TransactionStatusExpired,
+		EventID:           uuid.New(),
+	}
+
+	mockBookingRepo.EXPECT().
+		IsEventProcessed(ctx, req.EventID).
+		Return(false, nil).
+		Times(1)
+
+	mockBookingRepo.EXPECT().
+		GetBookingByID(ctx, bookingID).
+		Return(booking, nil).
+		Times(1)
+
+	mockBookingRepo.EXPECT().
+		UpdateBookingForEvent(ctx, gomock.Any(), req.EventID).
+		Do(func(_ context.Context, b *model.Booking, _ uuid.UUID) {
+			assert.Equal(t, model.BookingStatusExpired, b.Status)
+		}).
+		Return(nil).
+		Times(1)
+
+	// The released seats go to the trip's waitlist
 	mockDelayedQueue.EXPECT().
-		Schedule(gomock.Any(), "booking_expiry", gomock.Any(), gomock.Any()).
+		ScheduleEarliest(ctx, constants.QueueNameWaitlistOffer, gomock.Any(), gomock.Any()).
+		Return(nil)
+
+	err := service.UpdateBookingStatus(ctx, req, bookingID)
+
+	assert.NoError(t, err)
+}
+
+func TestCancelBooking_Confirmed_AlreadyRefunded(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockBookingRepo := repo_mocks.NewMockBookingRepository(ctrl)
+	mockPaymentClient := mocks.NewMockPaymentClient(ctrl)
+	mockTripClient := mocks.NewMockTripClient(ctrl)
+
+	mockDelayedQueue := queue_mocks.NewMockDelayedQueueManager(ctrl)
+
+	service := NewBookingService(
+		mockBookingRepo,
+		mockPaymentClient,
+		mockTripClient,
+		mocks.NewMockUserClient(ctrl),
+		mocks.NewMockNotificationClient(ctrl),
+		mockDelayedQueue,
+		service_mocks.NewMockSeatLockService(ctrl),
+		NewCancellationPolicyService(testRefundTiers),
+		service_mocks.NewMockBookingExchangeService(ctrl),
+		service_mocks.NewMockPromotionService(ctrl),
+	)
+
+	ctx := context.Background()
+	bookingID := uuid.New()
+	tripID := uuid.New()
+
+	booking := &model.Booking{
+		BaseModel:   model.BaseModel{ID: bookingID},
+		TripID:      tripID,
+		TotalAmount: 500000,
+		Status:      model.BookingStatusConfirmed,
+	}
+
+	mockBookingRepo.EXPECT().
+		GetBookingByID(ctx, bookingID).
+		Return(booking, nil).
+		Times(1)
+
+	mockTripClient.EXPECT().
+		GetTripByID(ctx, gomock.Any(), tripID).
+		Return(&trip.Trip{ID: tripID, DepartureTime: time.Now().UTC().Add(48 * time.Hour)}, nil).
+		Times(1)
+
+	mockPaymentClient.EXPECT().
+		CreateRefund(ctx, gomock.Any()).
+		Return(nil, client.ErrRefundAlreadyExists).
+		Times(1)
+
+	// A trip cancellation already refunded the booking, so it is still cancelled
+	mockBookingRepo.EXPECT().
+		CancelBooking(ctx, bookingID, "Trip Cancelled by Operator").
 		Return(nil).
-		MaxTimes(1)
+		Times(1)
+
+	// The released seats go to the trip's waitlist
+	mockDelayedQueue.EXPECT().
+		ScheduleEarliest(ctx, constants.QueueNameWaitlistOffer, gomock.Any(), gomock.Any()).
+		Return(nil)
+
+	err := service.CancelBooking(ctx, bookingID, "Trip Cancelled by Operator")
+
+	assert.NoError(t, err)
+}
+
+func TestCancelForTrip_Confirmed_NoPolicyRefund(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockBookingRepo := repo_mocks.NewMockBookingRepository(ctrl)
+
+	// No trip lookup and no refund: the trip cancellation refunds the booking in full itself
+	service := NewBookingService(
+		mockBookingRepo,
+		mocks.NewMockPaymentClient(ctrl),
+		mocks.NewMockTripClient(ctrl),
+		mocks.NewMockUserClient(ctrl),
+		mocks.NewMockNotificationClient(ctrl),
+		queue_mocks.NewMockDelayedQueueManager(ctrl),
+		service_mocks.NewMockSeatLockService(ctrl),
+		NewCancellationPolicyService(testRefundTiers),
+		service_mocks.NewMockBookingExchangeService(ctrl),
+		service_mocks.NewMockPromotionService(ctrl),
+	)
+
+	ctx := context.Background()
+	bookingID := uuid.New()
+
+	mockBookingRepo.EXPECT().
+		GetBookingByID(ctx, bookingID).
+		Return(&model.Booking{
+			BaseModel: model.BaseModel{ID: bookingID},
+			Status:    model.BookingStatusConfirmed,
+		}, nil).
+		Times(1)
+
+	mockBookingRepo.EXPECT().
+		CancelBooking(ctx, bookingID, "Trip Cancelled by Operator").
+		Return(nil).
+		Times(1)
+
+	err := service.CancelForTrip(ctx, bookingID, "Trip Cancelled by Operator")
+
+	assert.NoError(t, err)
+}
+
+func TestCancelForTrip_AlreadyCancelled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockBookingRepo := repo_mocks.NewMockBookingRepository(ctrl)
+
+	service := NewBookingService(
+		mockBookingRepo,
+		mocks.NewMockPaymentClient(ctrl),
+		mocks.NewMockTripClient(ctrl),
+		mocks.NewMockUserClient(ctrl),
+		mocks.NewMockNotificationClient(ctrl),
+		queue_mocks.NewMockDelayedQueueManager(ctrl),
+		service_mocks.NewMockSeatLockService(ctrl),
+		NewCancellationPolicyService(testRefundTiers),
+		service_mocks.NewMockBookingExchangeService(ctrl),
+		service_mocks.NewMockPromotionService(ctrl),
+	)
+
+	ctx := context.Background()
+	bookingID := uuid.New()
+
+	mockBookingRepo.EXPECT().
+		GetBookingByID(ctx, bookingID).
+		Return(&model.Booking{
+			BaseModel: model.BaseModel{ID: bookingID},
+			Status:    model.BookingStatusCancelled,
+		}, nil).
+		Times(1)
+
+	// A retried saga step must not fail on a booking it already cancelled
+	err := service.CancelForTrip(ctx, bookingID, "Trip Cancelled by Operator")
+
+	assert.NoError(t, err)
+}
+
+func TestNotifyTripCancelled_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockBookingRepo := repo_mocks.NewMockBookingRepository(ctrl)
+	mockTripClient := mocks.NewMockTripClient(ctrl)
+	mockUserClient := mocks.NewMockUserClient(ctrl)
+	mockNotificationClient := mocks.NewMockNotificationClient(ctrl)
+
+	service := NewBookingService(
+		mockBookingRepo,
+		mocks.NewMockPaymentClient(ctrl),
+		mockTripClient,
+		mockUserClient,
+		mockNotificationClient,
+		queue_mocks.NewMockDelayedQueueManager(ctrl),
+		service_mocks.NewMockSeatLockService(ctrl),
+		NewCancellationPolicyService(testRefundTiers),
+		service_mocks.NewMockBookingExchangeService(ctrl),
+		service_mocks.NewMockPromotionService(ctrl),
+	)
+
+	ctx := context.Background()
+	bookingID := uuid.New()
+	tripID := uuid.New()
+	userID := uuid.New()
+
+	mockBookingRepo.EXPECT().
+		GetBookingByID(ctx, bookingID).
+		Return(&model.Booking{
+			BaseModel:        model.BaseModel{ID: bookingID},
+			BookingReference: "BK123",
+			TripID:           tripID,
+			UserID:           userID,
+		}, nil).
+		Times(1)
+
+	mockUserClient.EXPECT().
+		GetUserByID(ctx, userID).
+		Return(&user.User{ID: userID, Email: "passenger@example.com", FullName: "Nguyen Van A"}, nil).
+		Times(1)
+
+	mockTripClient.EXPECT().
+		GetTripByID(ctx, gomock.Any(), tripID).
+		Return(&trip.Trip{
+			ID:            tripID,
+			DepartureTime: time.Now().Add(48 * time.Hour),
+			Route:         &trip.Route{Origin: "Hà Nội", Destination: "Hải Phòng"},
+		}, nil).
+		Times(1)
+
+	mockNotificationClient.EXPECT().
+		SendTripCancelled(ctx, gomock.Any()).
+		DoAndReturn(func(ctx context.Context, req *client.TripCancelledRequest) error {
+			assert.Equal(t, "passenger@example.com", req.Email)
+			assert.Equal(t, "BK123", req.BookingReference)
+			assert.Equal(t, 300000, req.RefundAmount)
+			return nil
+		}).
+		Times(1)
+
+	err := service.NotifyTripCancelled(ctx, bookingID, &model.NotifyTripCancelledRequest{
+		Reason:       "Trip Cancelled by Operator",
+		RefundAmount: 300000,
+	})
+
+	assert.NoError(t, err)
+}
+
+func TestCreateBooking_AppliesPromoCode(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockBookingRepo := repo_mocks.NewMockBookingRepository(ctrl)
+	mockPaymentClient := mocks.NewMockPaymentClient(ctrl)
+	mockTripClient := mocks.NewMockTripClient(ctrl)
+	mockPromotionService := service_mocks.NewMockPromotionService(ctrl)
+
+	service := NewBookingService(
+		mockBookingRepo,
+		mockPaymentClient,
+		mockTripClient,
+		mocks.NewMockUserClient(ctrl),
+		mocks.NewMockNotificationClient(ctrl),
+		queue_mocks.NewMockDelayedQueueManager(ctrl),
+		quotingSeatLockService(ctrl, 200000),
+		NewCancellationPolicyService(testRefundTiers),
+		service_mocks.NewMockBookingExchangeService(ctrl),
+		mockPromotionService,
+	)
+
+	ctx := context.Background()
+	userID := uuid.New()
+	tripID := uuid.New()
+	seatID := uuid.New()
+	tripData := &trip.Trip{ID: tripID, BasePrice: 200000}
+	promotion := &model.Promotion{BaseModel: model.BaseModel{ID: uuid.New()}, Code: "TET2025"}
+
+	req := &model.CreateBookingRequest{
+		TripID:    tripID,
+		SeatIDs:   []uuid.UUID{seatID},
+		PromoCode: "tet2025",
+	}
+
+	mockBookingRepo.EXPECT().GetBookedSeatIDs(ctx, tripID, model.FullTripSegment()).Return([]uuid.UUID{}, nil)
+	mockTripClient.EXPECT().GetTripByID(gomock.Any(), gomock.Any(), tripID).Return(tripData, nil)
+	mockTripClient.EXPECT().ListSeatsByIDs(gomock.Any(), gomock.Any()).Return([]trip.Seat{
+		{ID: seatID, SeatNumber: "A1", PriceMultiplier: 1.0},
+	}, nil)
+	mockPromotionService.EXPECT().ApplyPromotion(ctx, "tet2025", userID, tripData, 200000).Return(promotion, 30000, nil)
+
+	var saved *model.Booking
+	mockBookingRepo.EXPECT().CreateBookingFromHold(ctx, gomock.Any(), gomock.Any(), model.FullTripSegment()).DoAndReturn(func(_ context.Context, booking *model.Booking, _ string, _ model.TripSegment) error {
+		saved = booking
+		return nil
+	})
+	mockPaymentClient.EXPECT().CreateTransaction(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, req *payment.CreateTransactionRequest) (*payment.TransactionResponse, error) {
+		assert.Equal(t, 170000, req.Amount)
+		return &payment.TransactionResponse{}, nil
+	})
+
+	result, err := service.CreateBooking(ctx, req, userID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, &promotion.ID, saved.PromotionID)
+	assert.Equal(t, "TET2025", saved.PromoCode)
+	assert.Equal(t, 30000, saved.DiscountAmount)
+	assert.Equal(t, 170000, saved.TotalAmount)
+	assert.Equal(t, 200000, int(saved.BookingSeats[0].Price))
+	assert.Equal(t, 30000, result.DiscountAmount)
+	assert.Equal(t, 170000, result.TotalAmount)
+}
+
+func TestCreateBooking_InvalidPromoCode(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockBookingRepo := repo_mocks.NewMockBookingRepository(ctrl)
+	mockTripClient := mocks.NewMockTripClient(ctrl)
+	mockPromotionService := service_mocks.NewMockPromotionService(ctrl)
+
+	service := NewBookingService(
+		mockBookingRepo,
+		mocks.NewMockPaymentClient(ctrl),
+		mockTripClient,
+		mocks.NewMockUserClient(ctrl),
+		mocks.NewMockNotificationClient(ctrl),
+		queue_mocks.NewMockDelayedQueueManager(ctrl),
+		quotingSeatLockService(ctrl, 100000),
+		NewCancellationPolicyService(testRefundTiers),
+		service_mocks.NewMockBookingExchangeService(ctrl),
+		mockPromotionService,
+	)
+
+	ctx := context.Background()
+	tripID := uuid.New()
+	seatID := uuid.New()
+
+	req := &model.CreateBookingRequest{
+		TripID:    tripID,
+		SeatIDs:   []uuid.UUID{seatID},
+		PromoCode: "NOPE",
+	}
+
+	mockBookingRepo.EXPECT().GetBookedSeatIDs(ctx, tripID, model.FullTripSegment()).Return([]uuid.UUID{}, nil)
+	mockTripClient.EXPECT().GetTripByID(gomock.Any(), gomock.Any(), tripID).Return(&trip.Trip{ID: tripID, BasePrice: 100000}, nil)
+	mockTripClient.EXPECT().ListSeatsByIDs(gomock.Any(), gomock.Any()).Return([]trip.Seat{{ID: seatID, PriceMultiplier: 1.0}}, nil)
+	mockPromotionService.EXPECT().
+		ApplyPromotion(ctx, "NOPE", gomock.Any(), gomock.Any(), 100000).
+		Return(nil, 0, ginext.NewBadRequestError("invalid promo code"))
+
+	result, err := service.CreateBooking(ctx, req, uuid.New())
 
-	// 6. Async: Send Pending Email
-	// Re-fetch user
-	mockUserClient.EXPECT().
-		GetUserByID(gomock.Any(), userID).
-		Return(userData, nil).
-		MaxTimes(1)
+	assert.Nil(t, result)
+	var apiErr *ginext.Error
+	assert.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusBadRequest, apiErr.Code)
+}
 
-	// Send Email
-	mockNotificationClient.EXPECT().
-		SendBookingPending(gomock.Any(), gomock.Any()).
-		Return(nil).
-		MaxTimes(1)
+func TestCreateBooking_PromotionUsedUpMeanwhile(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
 
-	result, err := service.RetryPayment(ctx, bookingID)
+	mockBookingRepo := repo_mocks.NewMockBookingRepository(ctrl)
+	mockTripClient := mocks.NewMockTripClient(ctrl)
+	mockPromotionService := service_mocks.NewMockPromotionService(ctrl)
+
+	service := NewBookingService(
+		mockBookingRepo,
+		mocks.NewMockPaymentClient(ctrl),
+		mockTripClient,
+		mocks.NewMockUserClient(ctrl),
+		mocks.NewMockNotificationClient(ctrl),
+		queue_mocks.NewMockDelayedQueueManager(ctrl),
+		quotingSeatLockService(ctrl, 100000),
+		NewCancellationPolicyService(testRefundTiers),
+		service_mocks.NewMockBookingExchangeService(ctrl),
+		mockPromotionService,
+	)
+
+	ctx := context.Background()
+	tripID := uuid.New()
+	seatID := uuid.New()
+
+	req := &model.CreateBookingRequest{
+		TripID:    tripID,
+		SeatIDs:   []uuid.UUID{seatID},
+		PromoCode: "LAST1",
+	}
+
+	mockBookingRepo.EXPECT().GetBookedSeatIDs(ctx, tripID, model.FullTripSegment()).Return([]uuid.UUID{}, nil)
+	mockTripClient.EXPECT().GetTripByID(gomock.Any(), gomock.Any(), tripID).Return(&trip.Trip{ID: tripID, BasePrice: 100000}, nil)
+	mockTripClient.EXPECT().ListSeatsByIDs(gomock.Any(), gomock.Any()).Return([]trip.Seat{{ID: seatID, PriceMultiplier: 1.0}}, nil)
+	mockPromotionService.EXPECT().
+		ApplyPromotion(ctx, "LAST1", gomock.Any(), gomock.Any(), 100000).
+		Return(&model.Promotion{BaseModel: model.BaseModel{ID: uuid.New()}, Code: "LAST1"}, 10000, nil)
+	mockBookingRepo.EXPECT().
+		CreateBookingFromHold(ctx, gomock.Any(), gomock.Any(), model.FullTripSegment()).
+		Return(model.ErrPromotionUnavailable)
+
+	result, err := service.CreateBooking(ctx, req, uuid.New())
+
+	assert.Nil(t, result)
+	var apiErr *ginext.Error
+	assert.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusConflict, apiErr.Code)
+}
+
+func TestUpdateBookingStatus_Expired_ReleasesPromotion(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockBookingRepo := repo_mocks.NewMockBookingRepository(ctrl)
+	mockPromotionService := service_mocks.NewMockPromotionService(ctrl)
+
+	mockDelayedQueue := queue_mocks.NewMockDelayedQueueManager(ctrl)
+
+	service := NewBookingService(
+		mockBookingRepo,
+		mocks.NewMockPaymentClient(ctrl),
+		mocks.NewMockTripClient(ctrl),
+		mocks.NewMockUserClient(ctrl),
+		mocks.NewMockNotificationClient(ctrl),
+		mockDelayedQueue,
+		service_mocks.NewMockSeatLockService(ctrl),
+		NewCancellationPolicyService(testRefundTiers),
+		service_mocks.NewMockBookingExchangeService(ctrl),
+		mockPromotionService,
+	)
+
+	ctx := context.Background()
+	bookingID := uuid.New()
+	promotionID := uuid.New()
+
+	booking := &model.Booking{
+		BaseModel:   model.BaseModel{ID: bookingID},
+		Status:      model.BookingStatusPending,
+		PromotionID: &promotionID,
+	}
+
+	mockBookingRepo.EXPECT().GetBookingByID(ctx, bookingID).Return(booking, nil)
+	mockBookingRepo.EXPECT().UpdateBooking(ctx, booking).Return(nil)
+	mockPromotionService.EXPECT().ReleaseForBooking(ctx, bookingID).Return(nil)
+
+	// The released seats go to the trip's waitlist
+	mockDelayedQueue.EXPECT().
+		ScheduleEarliest(ctx, constants.QueueNameWaitlistOffer, gomock.Any(), gomock.Any()).
+		Return(nil)
+
+	err := service.UpdateBookingStatus(ctx, &model.UpdateBookingStatusRequest{
+		TransactionStatus: payment.TransactionStatusExpired,
+	}, bookingID)
 
 	assert.NoError(t, err)
-	assert.NotNil(t, result)
+	assert.Equal(t, model.BookingStatusExpired, booking.Status)
+}
 
-	time.Sleep(50 * time.Millisecond)
+func TestCancelForTrip_Confirmed_ReleasesPromotion(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockBookingRepo := repo_mocks.NewMockBookingRepository(ctrl)
+	mockPromotionService := service_mocks.NewMockPromotionService(ctrl)
+
+	service := NewBookingService(
+		mockBookingRepo,
+		mocks.NewMockPaymentClient(ctrl),
+		mocks.NewMockTripClient(ctrl),
+		mocks.NewMockUserClient(ctrl),
+		mocks.NewMockNotificationClient(ctrl),
+		queue_mocks.NewMockDelayedQueueManager(ctrl),
+		service_mocks.NewMockSeatLockService(ctrl),
+		NewCancellationPolicyService(testRefundTiers),
+		service_mocks.NewMockBookingExchangeService(ctrl),
+		mockPromotionService,
+	)
+
+	ctx := context.Background()
+	bookingID := uuid.New()
+	promotionID := uuid.New()
+
+	mockBookingRepo.EXPECT().GetBookingByID(ctx, bookingID).Return(&model.Booking{
+		BaseModel:   model.BaseModel{ID: bookingID},
+		Status:      model.BookingStatusConfirmed,
+		PromotionID: &promotionID,
+	}, nil)
+	mockBookingRepo.EXPECT().CancelBooking(ctx, bookingID, "Trip cancelled").Return(nil)
+	mockPromotionService.EXPECT().ReleaseForBooking(ctx, bookingID).Return(nil)
+
+	err := service.CancelForTrip(ctx, bookingID, "Trip cancelled")
+
+	assert.NoError(t, err)
 }
 
-func TestExpireBooking_Success(t *testing.T) {
+func TestUpdateBookingStatus_Underpaid_KeepsHoldAndAsksForRemainder(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
@@ -2251,8 +4122,6 @@ func TestExpireBooking_Success(t *testing.T) {
 	mockTripClient := mocks.NewMockTripClient(ctrl)
 	mockUserClient := mocks.NewMockUserClient(ctrl)
 	mockNotificationClient := mocks.NewMockNotificationClient(ctrl)
-	mockDelayedQueue := queue_mocks.NewMockDelayedQueueManager(ctrl)
-	mockSeatLockService := service_mocks.NewMockSeatLockService(ctrl)
 
 	service := NewBookingService(
 		mockBookingRepo,
@@ -2260,79 +4129,406 @@ func TestExpireBooking_Success(t *testing.T) {
 		mockTripClient,
 		mockUserClient,
 		mockNotificationClient,
-		mockDelayedQueue,
-		mockSeatLockService,
+		queue_mocks.NewMockDelayedQueueManager(ctrl),
+		service_mocks.NewMockSeatLockService(ctrl),
+		NewCancellationPolicyService(testRefundTiers),
+		service_mocks.NewMockBookingExchangeService(ctrl),
+		service_mocks.NewMockPromotionService(ctrl),
 	)
 
 	ctx := context.Background()
-	bookingID := uuid.New()
-	userID := uuid.New()
-	tripID := uuid.New()
-
-	// Past grace period
-	expiredAt := time.Now().Add(-1 * time.Hour)
-
+	expiresAt := time.Now().Add(10 * time.Minute)
 	booking := &model.Booking{
-		BaseModel:     model.BaseModel{ID: bookingID},
-		Status:        model.BookingStatusPending,
-		ExpiresAt:     &expiredAt,
-		TransactionID: uuid.New(),
-		UserID:        userID,
-		TripID:        tripID,
+		BaseModel:        model.BaseModel{ID: uuid.New()},
+		BookingReference: "BKREF",
+		TripID:           uuid.New(),
+		UserID:           uuid.New(),
+		TransactionID:    uuid.New(),
+		TotalAmount:      300000,
+		Status:           model.BookingStatusPending,
+		ExpiresAt:        &expiresAt,
 	}
 
-	userData := &user.User{ID: userID, Email: "test@example.com", FullName: "Test"}
-	tripData := &trip.Trip{ID: tripID, Route: &trip.Route{Origin: "A", Destination: "B"}}
-
-	// 1. Get Booking
+	mockBookingRepo.EXPECT().GetBookingByID(gomock.Any(), booking.ID).Return(booking, nil).MinTimes(1)
 	mockBookingRepo.EXPECT().
-		GetBookingByID(ctx, bookingID).
-		Return(booking, nil).
-		Times(1)
+		UpdateBooking(ctx, gomock.Any()).
+		Do(func(_ context.Context, b *model.Booking) {
+			assert.Equal(t, model.BookingStatusPending, b.Status)
+			assert.Equal(t, payment.TransactionStatusUnderpaid, b.TransactionStatus)
+		}).
+		Return(nil)
 
-	// 2. Cancel Transaction
+	sent := make(chan *client.PaymentUnderpaidRequest, 1)
+	mockUserClient.EXPECT().GetUserByID(gomock.Any(), booking.UserID).Return(&user.User{Email: "test@example.com", FullName: "Test"}, nil)
+	mockTripClient.EXPECT().
+		GetTripByID(gomock.Any(), gomock.Any(), booking.TripID).
+		Return(&trip.Trip{Route: &trip.Route{Origin: "Sài Gòn", Destination: "Đà Lạt"}}, nil)
 	mockPaymentClient.EXPECT().
-		CancelTransaction(ctx, booking.TransactionID).
-		Return(nil, nil).
-		Times(1)
+		GetTransactionByID(gomock.Any(), booking.TransactionID).
+		Return(&payment.TransactionResponse{AmountPaid: 200000, CheckoutURL: "https://pay.payos.vn/web/abc"}, nil)
+	mockNotificationClient.EXPECT().
+		SendPaymentUnderpaid(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, req *client.PaymentUnderpaidRequest) error {
+			sent <- req
+			return nil
+		})
+
+	err := service.UpdateBookingStatus(ctx, &model.UpdateBookingStatusRequest{
+		TransactionID:     booking.TransactionID,
+		TransactionStatus: payment.TransactionStatusUnderpaid,
+		AmountPaid:        200000,
+	}, booking.ID)
+	assert.NoError(t, err)
+
+	select {
+	case req := <-sent:
+		assert.Equal(t, 200000, req.AmountPaid)
+		assert.Equal(t, 100000, req.AmountRemaining)
+		assert.Equal(t, "https://pay.payos.vn/web/abc", req.PaymentLink)
+	case <-time.After(time.Second):
+		t.Fatal("payment underpaid email was not sent")
+	}
+}
+
+func TestUpdateBookingStatus_Underpaid_ExpiredBookingNotReopened(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockBookingRepo := repo_mocks.NewMockBookingRepository(ctrl)
+	mockNotificationClient := mocks.NewMockNotificationClient(ctrl)
+
+	service := NewBookingService(
+		mockBookingRepo,
+		mocks.NewMockPaymentClient(ctrl),
+		mocks.NewMockTripClient(ctrl),
+		mocks.NewMockUserClient(ctrl),
+		mockNotificationClient,
+		queue_mocks.NewMockDelayedQueueManager(ctrl),
+		service_mocks.NewMockSeatLockService(ctrl),
+		NewCancellationPolicyService(testRefundTiers),
+		service_mocks.NewMockBookingExchangeService(ctrl),
+		service_mocks.NewMockPromotionService(ctrl),
+	)
+
+	ctx := context.Background()
+	booking := &model.Booking{
+		BaseModel:     model.BaseModel{ID: uuid.New()},
+		TransactionID: uuid.New(),
+		Status:        model.BookingStatusExpired,
+	}
 
-	// 3. Update Booking
+	mockBookingRepo.EXPECT().GetBookingByID(ctx, booking.ID).Return(booking, nil)
 	mockBookingRepo.EXPECT().
 		UpdateBooking(ctx, gomock.Any()).
 		Do(func(_ context.Context, b *model.Booking) {
 			assert.Equal(t, model.BookingStatusExpired, b.Status)
 		}).
-		Return(nil).
-		Times(1)
+		Return(nil)
+	mockNotificationClient.EXPECT().SendPaymentUnderpaid(gomock.Any(), gomock.Any()).Times(0)
 
-	// 4. Async: Send Failure Email
-	// Fetch booking
-	mockBookingRepo.EXPECT().
-		GetBookingByID(gomock.Any(), bookingID).
-		Return(booking, nil).
-		MaxTimes(1)
+	err := service.UpdateBookingStatus(ctx, &model.UpdateBookingStatusRequest{
+		TransactionID:     booking.TransactionID,
+		TransactionStatus: payment.TransactionStatusUnderpaid,
+		AmountPaid:        50000,
+	}, booking.ID)
 
-	// Fetch User
-	mockUserClient.EXPECT().
-		GetUserByID(gomock.Any(), userID).
-		Return(userData, nil).
-		MaxTimes(1)
+	assert.NoError(t, err)
+}
 
-	// Fetch Trip
-	mockTripClient.EXPECT().
-		GetTripByID(gomock.Any(), gomock.Any(), tripID).
-		Return(tripData, nil).
-		MaxTimes(1)
+func TestExpireBooking_Underpaid_TellsPassengerAboutRefund(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
 
-	// Send Email
+	mockBookingRepo := repo_mocks.NewMockBookingRepository(ctrl)
+	mockPaymentClient := mocks.NewMockPaymentClient(ctrl)
+	mockTripClient := mocks.NewMockTripClient(ctrl)
+	mockUserClient := mocks.NewMockUserClient(ctrl)
+	mockNotificationClient := mocks.NewMockNotificationClient(ctrl)
+
+	mockDelayedQueue := queue_mocks.NewMockDelayedQueueManager(ctrl)
+
+	service := NewBookingService(
+		mockBookingRepo,
+		mockPaymentClient,
+		mockTripClient,
+		mockUserClient,
+		mockNotificationClient,
+		mockDelayedQueue,
+		service_mocks.NewMockSeatLockService(ctrl),
+		NewCancellationPolicyService(testRefundTiers),
+		service_mocks.NewMockBookingExchangeService(ctrl),
+		service_mocks.NewMockPromotionService(ctrl),
+	)
+
+	ctx := context.Background()
+	expiredAt := time.Now().Add(-1 * time.Hour)
+	booking := &model.Booking{
+		BaseModel:         model.BaseModel{ID: uuid.New()},
+		TripID:            uuid.New(),
+		UserID:            uuid.New(),
+		TransactionID:     uuid.New(),
+		Status:            model.BookingStatusPending,
+		TransactionStatus: payment.TransactionStatusUnderpaid,
+		ExpiresAt:         &expiredAt,
+	}
+
+	mockBookingRepo.EXPECT().GetBookingByID(gomock.Any(), booking.ID).Return(booking, nil).MinTimes(1)
+	// Payment service refunds the partial payment of the transaction it cancels
+	mockBookingRepo.EXPECT().UpdateBookingsWithEvents(ctx, gomock.Any(), gomock.Any()).Return(nil)
+
+	sent := make(chan *client.BookingFailureRequest, 1)
+	mockUserClient.EXPECT().GetUserByID(gomock.Any(), booking.UserID).Return(&user.User{Email: "test@example.com"}, nil)
+	mockTripClient.EXPECT().
+		GetTripByID(gomock.Any(), gomock.Any(), booking.TripID).
+		Return(&trip.Trip{Route: &trip.Route{Origin: "A", Destination: "B"}}, nil)
 	mockNotificationClient.EXPECT().
 		SendBookingFailure(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, req *client.BookingFailureRequest) error {
+			sent <- req
+			return nil
+		})
+
+	// The released seats go to the trip's waitlist
+	mockDelayedQueue.EXPECT().
+		ScheduleEarliest(ctx, constants.QueueNameWaitlistOffer, gomock.Any(), gomock.Any()).
+		Return(nil)
+
+	assert.NoError(t, service.ExpireBooking(ctx, booking.ID))
+
+	select {
+	case req := <-sent:
+		assert.Contains(t, req.Reason, "hoàn lại")
+	case <-time.After(time.Second):
+		t.Fatal("booking failure email was not sent")
+	}
+}
+
+func newItineraryLegs(statuses ...model.BookingStatus) []*model.Booking {
+	itineraryID := uuid.New()
+	transactionID := uuid.New()
+	expiresAt := time.Now().Add(-1 * time.Hour)
+
+	legs := make([]*model.Booking, len(statuses))
+	for i, status := range statuses {
+		legs[i] = &model.Booking{
+			BaseModel:         model.BaseModel{ID: uuid.New()},
+			TripID:            uuid.New(),
+			UserID:            uuid.New(),
+			TotalAmount:       540000,
+			Status:            status,
+			TransactionStatus: payment.TransactionStatusPending,
+			TransactionID:     transactionID,
+			ExpiresAt:         &expiresAt,
+			ItineraryID:       &itineraryID,
+			LegNumber:         i + 1,
+		}
+	}
+	return legs
+}
+
+func TestUpdateBookingStatus_ItineraryPaymentSettlesEveryLeg(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockBookingRepo := repo_mocks.NewMockBookingRepository(ctrl)
+	mockDelayedQueue := queue_mocks.NewMockDelayedQueueManager(ctrl)
+
+	service := NewBookingService(
+		mockBookingRepo,
+		mocks.NewMockPaymentClient(ctrl),
+		mocks.NewMockTripClient(ctrl),
+		mocks.NewMockUserClient(ctrl),
+		mocks.NewMockNotificationClient(ctrl),
+		mockDelayedQueue,
+		service_mocks.NewMockSeatLockService(ctrl),
+		NewCancellationPolicyService(testRefundTiers),
+		service_mocks.NewMockBookingExchangeService(ctrl),
+		service_mocks.NewMockPromotionService(ctrl),
+	)
+
+	ctx := context.Background()
+	legs := newItineraryLegs(model.BookingStatusPending, model.BookingStatusPending)
+	eventID := uuid.New()
+
+	mockBookingRepo.EXPECT().IsEventProcessed(ctx, eventID).Return(false, nil)
+	mockBookingRepo.EXPECT().GetBookingByID(ctx, legs[0].ID).Return(legs[0], nil)
+	mockBookingRepo.EXPECT().GetItineraryBookings(ctx, *legs[0].ItineraryID).Return(legs, nil)
+	mockBookingRepo.EXPECT().
+		UpdateBookings(ctx, gomock.Any(), eventID).
+		DoAndReturn(func(_ context.Context, bookings []*model.Booking, _ uuid.UUID) error {
+			assert.Len(t, bookings, 2)
+			for _, booking := range bookings {
+				assert.Equal(t, model.BookingStatusExpired, booking.Status)
+				assert.Equal(t, payment.TransactionStatusExpired, booking.TransactionStatus)
+			}
+			return nil
+		})
+
+	// Seats of both legs go to their trips' waitlists
+	for _, leg := range legs {
+		mockDelayedQueue.EXPECT().
+			ScheduleEarliest(ctx, constants.QueueNameWaitlistOffer, gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, _ string, item *queue.DelayedItem, _ time.Time) error {
+				assert.Equal(t, leg.TripID.String(), item.ID)
+				return nil
+			})
+	}
+
+	err := service.UpdateBookingStatus(ctx, &model.UpdateBookingStatusRequest{
+		TransactionID:     legs[0].TransactionID,
+		TransactionStatus: payment.TransactionStatusExpired,
+		EventID:           eventID,
+	}, legs[0].ID)
+
+	assert.NoError(t, err)
+}
+
+func TestExpireBooking_ItineraryExpiresEveryLeg(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockBookingRepo := repo_mocks.NewMockBookingRepository(ctrl)
+	mockPaymentClient := mocks.NewMockPaymentClient(ctrl)
+	mockTripClient := mocks.NewMockTripClient(ctrl)
+	mockUserClient := mocks.NewMockUserClient(ctrl)
+	mockNotificationClient := mocks.NewMockNotificationClient(ctrl)
+	mockDelayedQueue := queue_mocks.NewMockDelayedQueueManager(ctrl)
+
+	service := NewBookingService(
+		mockBookingRepo,
+		mockPaymentClient,
+		mockTripClient,
+		mockUserClient,
+		mockNotificationClient,
+		mockDelayedQueue,
+		service_mocks.NewMockSeatLockService(ctrl),
+		NewCancellationPolicyService(testRefundTiers),
+		service_mocks.NewMockBookingExchangeService(ctrl),
+		service_mocks.NewMockPromotionService(ctrl),
+	)
+
+	ctx := context.Background()
+	legs := newItineraryLegs(model.BookingStatusPending, model.BookingStatusPending)
+
+	mockBookingRepo.EXPECT().GetBookingByID(ctx, legs[0].ID).Return(legs[0], nil)
+	mockBookingRepo.EXPECT().GetItineraryBookings(ctx, *legs[0].ItineraryID).Return(legs, nil)
+	// The shared payment is cancelled once
+	mockBookingRepo.EXPECT().
+		UpdateBookingsWithEvents(ctx, gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, bookings []*model.Booking, events ...*outbox.Event) error {
+			assert.Len(t, bookings, 2)
+			for _, booking := range bookings {
+				assert.Equal(t, model.BookingStatusExpired, booking.Status)
+			}
+			assert.Len(t, events, 1)
+			return nil
+		})
+	mockDelayedQueue.EXPECT().
+		ScheduleEarliest(ctx, constants.QueueNameWaitlistOffer, gomock.Any(), gomock.Any()).
 		Return(nil).
-		MaxTimes(1)
+		Times(2)
 
-	err := service.ExpireBooking(ctx, bookingID)
+	// Async failure emails, one per leg
+	mockBookingRepo.EXPECT().GetBookingByID(gomock.Any(), gomock.Any()).Return(nil, errors.New("not needed")).AnyTimes()
+
+	err := service.ExpireBooking(ctx, legs[0].ID)
 
 	assert.NoError(t, err)
 
 	time.Sleep(50 * time.Millisecond)
 }
+
+func TestCancelExpiredPayment(t *testing.T) {
+	tests := []struct {
+		name      string
+		status    payment.TransactionStatus
+		cancelErr error
+		cancelled bool
+		wantErr   bool
+	}{
+		{name: "pending payment is cancelled", status: payment.TransactionStatusPending, cancelled: true},
+		{name: "underpaid payment is cancelled", status: payment.TransactionStatusUnderpaid, cancelled: true},
+		{name: "cancel failure is retried", status: payment.TransactionStatusPending, cancelErr: assert.AnError, cancelled: true, wantErr: true},
+		{name: "already cancelled", status: payment.TransactionStatusCancelled},
+		{name: "expired at the provider", status: payment.TransactionStatusExpired},
+		{name: "paid in the meantime", status: payment.TransactionStatusPaid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockPaymentClient := mocks.NewMockPaymentClient(ctrl)
+
+			service := NewBookingService(
+				repo_mocks.NewMockBookingRepository(ctrl),
+				mockPaymentClient,
+				mocks.NewMockTripClient(ctrl),
+				mocks.NewMockUserClient(ctrl),
+				mocks.NewMockNotificationClient(ctrl),
+				queue_mocks.NewMockDelayedQueueManager(ctrl),
+				service_mocks.NewMockSeatLockService(ctrl),
+				NewCancellationPolicyService(testRefundTiers),
+				service_mocks.NewMockBookingExchangeService(ctrl),
+				service_mocks.NewMockPromotionService(ctrl),
+			)
+
+			ctx := context.Background()
+			transactionID := uuid.New()
+			event, err := outbox.NewEvent(model.AggregateTypeBooking, uuid.New(), model.EventTypePaymentCancelDue, &model.PaymentCancelDueEvent{
+				BookingID:     uuid.New(),
+				TransactionID: transactionID,
+			})
+			assert.NoError(t, err)
+
+			mockPaymentClient.EXPECT().GetTransactionByID(ctx, transactionID).
+				Return(&payment.TransactionResponse{ID: transactionID, Status: tt.status}, nil)
+			if tt.cancelled {
+				mockPaymentClient.EXPECT().CancelTransaction(ctx, transactionID).Return(nil, tt.cancelErr)
+			} else {
+				mockPaymentClient.EXPECT().CancelTransaction(gomock.Any(), gomock.Any()).Times(0)
+			}
+
+			err = service.CancelExpiredPayment(ctx, event)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestRetryPayment_ItineraryLegRejected(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockBookingRepo := repo_mocks.NewMockBookingRepository(ctrl)
+
+	service := NewBookingService(
+		mockBookingRepo,
+		mocks.NewMockPaymentClient(ctrl),
+		mocks.NewMockTripClient(ctrl),
+		mocks.NewMockUserClient(ctrl),
+		mocks.NewMockNotificationClient(ctrl),
+		queue_mocks.NewMockDelayedQueueManager(ctrl),
+		service_mocks.NewMockSeatLockService(ctrl),
+		NewCancellationPolicyService(testRefundTiers),
+		service_mocks.NewMockBookingExchangeService(ctrl),
+		service_mocks.NewMockPromotionService(ctrl),
+	)
+
+	ctx := context.Background()
+	legs := newItineraryLegs(model.BookingStatusFailed, model.BookingStatusFailed)
+
+	mockBookingRepo.EXPECT().GetBookingByID(ctx, legs[1].ID).Return(legs[1], nil)
+
+	result, err := service.RetryPayment(ctx, legs[1].ID)
+
+	assert.Nil(t, result)
+	var apiErr *ginext.Error
+	assert.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusBadRequest, apiErr.Code)
+}
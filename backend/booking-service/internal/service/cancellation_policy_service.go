@@ -0,0 +1,63 @@
+package service
+
+import (
+	"time"
+
+	"bus-booking/booking-service/internal/model"
+
+	"github.com/google/uuid"
+)
+
+type CancellationPolicyService interface {
+	GetPolicy() *model.CancellationPolicyResponse
+	QuoteRefund(bookingID uuid.UUID, totalAmount int, departureTime, cancelAt time.Time) *model.RefundQuoteResponse
+}
+
+type CancellationPolicyServiceImpl struct {
+	tiers []model.RefundTier
+}
+
+func NewCancellationPolicyService(tiers []model.RefundTier) CancellationPolicyService {
+	sorted := make([]model.RefundTier, len(tiers))
+	copy(sorted, tiers)
+	model.SortRefundTiers(sorted)
+
+	return &CancellationPolicyServiceImpl{
+		tiers: sorted,
+	}
+}
+
+func (s *CancellationPolicyServiceImpl) GetPolicy() *model.CancellationPolicyResponse {
+	tiers := make([]model.RefundTier, len(s.tiers))
+	copy(tiers, s.tiers)
+	return &model.CancellationPolicyResponse{Tiers: tiers}
+}
+
+// QuoteRefund picks the first tier whose notice period is satisfied.
+// Bookings cancelled inside the shortest tier are cancellable without refund;
+// bookings whose trip has already departed are not cancellable at all.
+func (s *CancellationPolicyServiceImpl) QuoteRefund(bookingID uuid.UUID, totalAmount int, departureTime, cancelAt time.Time) *model.RefundQuoteResponse {
+	timeLeft := departureTime.Sub(cancelAt)
+
+	quote := &model.RefundQuoteResponse{
+		BookingID:            bookingID,
+		DepartureTime:        departureTime,
+		HoursBeforeDeparture: timeLeft.Hours(),
+		TotalAmount:          totalAmount,
+		Cancellable:          timeLeft > 0,
+	}
+	if !quote.Cancellable {
+		quote.HoursBeforeDeparture = 0
+		return quote
+	}
+
+	for _, tier := range s.tiers {
+		if timeLeft >= time.Duration(tier.HoursBeforeDeparture)*time.Hour {
+			quote.RefundPercent = tier.RefundPercent
+			quote.RefundAmount = totalAmount * tier.RefundPercent / 100
+			break
+		}
+	}
+
+	return quote
+}
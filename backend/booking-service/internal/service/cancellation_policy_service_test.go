@@ -0,0 +1,72 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"bus-booking/booking-service/internal/model"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRefundTiers(t *testing.T) {
+	tiers, err := model.ParseRefundTiers("6:30, 24:70,12:50")
+
+	assert.NoError(t, err)
+	assert.Equal(t, []model.RefundTier{
+		{HoursBeforeDeparture: 24, RefundPercent: 70},
+		{HoursBeforeDeparture: 12, RefundPercent: 50},
+		{HoursBeforeDeparture: 6, RefundPercent: 30},
+	}, tiers)
+}
+
+func TestParseRefundTiers_Invalid(t *testing.T) {
+	for _, value := range []string{"24", "abc:70", "24:150", "-1:10"} {
+		_, err := model.ParseRefundTiers(value)
+		assert.Error(t, err, value)
+	}
+}
+
+func TestCancellationPolicyService_GetPolicy(t *testing.T) {
+	service := NewCancellationPolicyService([]model.RefundTier{
+		{HoursBeforeDeparture: 6, RefundPercent: 30},
+		{HoursBeforeDeparture: 24, RefundPercent: 70},
+	})
+
+	policy := service.GetPolicy()
+
+	assert.Len(t, policy.Tiers, 2)
+	assert.Equal(t, 24, policy.Tiers[0].HoursBeforeDeparture)
+	assert.Equal(t, 6, policy.Tiers[1].HoursBeforeDeparture)
+}
+
+func TestCancellationPolicyService_QuoteRefund(t *testing.T) {
+	service := NewCancellationPolicyService(testRefundTiers)
+	departure := time.Date(2025, 12, 20, 8, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name            string
+		timeLeft        time.Duration
+		expectedPercent int
+		expectedAmount  int
+		cancellable     bool
+	}{
+		{"more than 24h", 48 * time.Hour, 70, 350000, true},
+		{"exactly 24h", 24 * time.Hour, 70, 350000, true},
+		{"between 12h and 24h", 20 * time.Hour, 50, 250000, true},
+		{"between 6h and 12h", 7 * time.Hour, 30, 150000, true},
+		{"within 6h", 3 * time.Hour, 0, 0, true},
+		{"after departure", -1 * time.Hour, 0, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			quote := service.QuoteRefund(uuid.New(), 500000, departure, departure.Add(-tt.timeLeft))
+
+			assert.Equal(t, tt.expectedPercent, quote.RefundPercent)
+			assert.Equal(t, tt.expectedAmount, quote.RefundAmount)
+			assert.Equal(t, tt.cancellable, quote.Cancellable)
+		})
+	}
+}
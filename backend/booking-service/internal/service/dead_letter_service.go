@@ -0,0 +1,90 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+
+	"bus-booking/booking-service/internal/constants"
+	"bus-booking/booking-service/internal/model"
+	"bus-booking/shared/ginext"
+	"bus-booking/shared/queue"
+)
+
+// jobQueues are the delayed queues consumed by the booking background jobs
+var jobQueues = []string{
+	constants.QueueNameBookingExpiry,
+	constants.QueueNameTripReminder,
+	constants.QueueNameWaitlistOffer,
+}
+
+type DeadLetterService interface {
+	ListDeadLetters(ctx context.Context, req model.ListDeadLettersRequest) (*model.DeadLettersResponse, error)
+	ReplayDeadLetter(ctx context.Context, queueName, id string) error
+}
+
+type DeadLetterServiceImpl struct {
+	delayedQueue queue.DelayedQueueManager
+}
+
+func NewDeadLetterService(delayedQueue queue.DelayedQueueManager) DeadLetterService {
+	return &DeadLetterServiceImpl{
+		delayedQueue: delayedQueue,
+	}
+}
+
+// ListDeadLetters lists dead letters of one job queue, or of all of them oldest first
+func (s *DeadLetterServiceImpl) ListDeadLetters(ctx context.Context, req model.ListDeadLettersRequest) (*model.DeadLettersResponse, error) {
+	queues := jobQueues
+	if req.Queue != "" {
+		if err := validateJobQueue(req.Queue); err != nil {
+			return nil, err
+		}
+		queues = []string{req.Queue}
+	}
+
+	letters := make([]*queue.DeadLetter, 0)
+	for _, queueName := range queues {
+		queueLetters, err := s.delayedQueue.ListDeadLetters(ctx, queueName, req.Limit)
+		if err != nil {
+			return nil, ginext.NewInternalServerError(fmt.Sprintf("failed to list dead letters of %s: %v", queueName, err))
+		}
+		letters = append(letters, queueLetters...)
+	}
+
+	sort.SliceStable(letters, func(i, j int) bool {
+		return letters[i].DeadAt.Before(letters[j].DeadAt)
+	})
+	if len(letters) > req.Limit {
+		letters = letters[:req.Limit]
+	}
+
+	return &model.DeadLettersResponse{
+		Total:       len(letters),
+		DeadLetters: letters,
+	}, nil
+}
+
+// ReplayDeadLetter puts a dead letter back on its queue to be processed on the next poll
+func (s *DeadLetterServiceImpl) ReplayDeadLetter(ctx context.Context, queueName, id string) error {
+	if err := validateJobQueue(queueName); err != nil {
+		return err
+	}
+
+	if err := s.delayedQueue.ReplayDeadLetter(ctx, queueName, id); err != nil {
+		if errors.Is(err, queue.ErrDeadLetterNotFound) {
+			return ginext.NewNotFoundError("dead letter not found")
+		}
+		return ginext.NewInternalServerError(fmt.Sprintf("failed to replay dead letter: %v", err))
+	}
+	return nil
+}
+
+func validateJobQueue(queueName string) error {
+	if !slices.Contains(jobQueues, queueName) {
+		return ginext.NewBadRequestError(fmt.Sprintf("unknown queue %q", queueName))
+	}
+	return nil
+}
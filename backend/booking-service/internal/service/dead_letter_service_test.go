@@ -0,0 +1,131 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"bus-booking/booking-service/internal/constants"
+	"bus-booking/booking-service/internal/model"
+	"bus-booking/shared/ginext"
+	"bus-booking/shared/queue"
+	queue_mocks "bus-booking/shared/queue/mocks"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func deadLetter(queueName, id string, deadAt time.Time) *queue.DeadLetter {
+	return &queue.DeadLetter{
+		Queue:  queueName,
+		Item:   &queue.DelayedItem{ID: id, Type: "test", Attempts: queue.DefaultMaxAttempts, LastError: "boom"},
+		DeadAt: deadAt,
+	}
+}
+
+func TestListDeadLetters_AllQueuesOldestFirst(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockQueue := queue_mocks.NewMockDelayedQueueManager(ctrl)
+	service := NewDeadLetterService(mockQueue)
+
+	ctx := context.Background()
+	now := time.Now()
+
+	mockQueue.EXPECT().ListDeadLetters(ctx, constants.QueueNameBookingExpiry, 2).
+		Return([]*queue.DeadLetter{deadLetter(constants.QueueNameBookingExpiry, "expiry-1", now.Add(-time.Minute))}, nil)
+	mockQueue.EXPECT().ListDeadLetters(ctx, constants.QueueNameTripReminder, 2).
+		Return([]*queue.DeadLetter{
+			deadLetter(constants.QueueNameTripReminder, "reminder-1", now.Add(-time.Hour)),
+			deadLetter(constants.QueueNameTripReminder, "reminder-2", now),
+		}, nil)
+	mockQueue.EXPECT().ListDeadLetters(ctx, constants.QueueNameWaitlistOffer, 2).
+		Return([]*queue.DeadLetter{deadLetter(constants.QueueNameWaitlistOffer, "trip-1", now.Add(-30*time.Minute))}, nil)
+
+	result, err := service.ListDeadLetters(ctx, model.ListDeadLettersRequest{Limit: 2})
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.Total)
+	assert.Equal(t, "reminder-1", result.DeadLetters[0].Item.ID)
+	assert.Equal(t, "trip-1", result.DeadLetters[1].Item.ID)
+}
+
+func TestListDeadLetters_SingleQueue(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockQueue := queue_mocks.NewMockDelayedQueueManager(ctrl)
+	service := NewDeadLetterService(mockQueue)
+
+	ctx := context.Background()
+
+	mockQueue.EXPECT().ListDeadLetters(ctx, constants.QueueNameTripReminder, 50).
+		Return([]*queue.DeadLetter{}, nil)
+
+	result, err := service.ListDeadLetters(ctx, model.ListDeadLettersRequest{Queue: constants.QueueNameTripReminder, Limit: 50})
+
+	require.NoError(t, err)
+	assert.Equal(t, 0, result.Total)
+	assert.NotNil(t, result.DeadLetters)
+}
+
+func TestListDeadLetters_UnknownQueue(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service := NewDeadLetterService(queue_mocks.NewMockDelayedQueueManager(ctrl))
+
+	_, err := service.ListDeadLetters(context.Background(), model.ListDeadLettersRequest{Queue: "payments", Limit: 50})
+
+	var apiErr *ginext.Error
+	require.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, http.StatusBadRequest, apiErr.Code)
+}
+
+func TestReplayDeadLetter_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockQueue := queue_mocks.NewMockDelayedQueueManager(ctrl)
+	service := NewDeadLetterService(mockQueue)
+
+	ctx := context.Background()
+	mockQueue.EXPECT().ReplayDeadLetter(ctx, constants.QueueNameBookingExpiry, "expiry-1").Return(nil)
+
+	assert.NoError(t, service.ReplayDeadLetter(ctx, constants.QueueNameBookingExpiry, "expiry-1"))
+}
+
+func TestReplayDeadLetter_WaitlistOffer(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockQueue := queue_mocks.NewMockDelayedQueueManager(ctrl)
+	service := NewDeadLetterService(mockQueue)
+
+	ctx := context.Background()
+	mockQueue.EXPECT().ReplayDeadLetter(ctx, constants.QueueNameWaitlistOffer, "trip-1").Return(nil)
+
+	assert.NoError(t, service.ReplayDeadLetter(ctx, constants.QueueNameWaitlistOffer, "trip-1"))
+}
+
+func TestReplayDeadLetter_NotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockQueue := queue_mocks.NewMockDelayedQueueManager(ctrl)
+	service := NewDeadLetterService(mockQueue)
+
+	ctx := context.Background()
+	mockQueue.EXPECT().ReplayDeadLetter(ctx, constants.QueueNameBookingExpiry, "missing").
+		Return(queue.ErrDeadLetterNotFound)
+
+	err := service.ReplayDeadLetter(ctx, constants.QueueNameBookingExpiry, "missing")
+
+	var apiErr *ginext.Error
+	require.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, http.StatusNotFound, apiErr.Code)
+}
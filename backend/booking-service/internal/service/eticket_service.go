@@ -3,6 +3,7 @@ package service
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -22,21 +23,25 @@ import (
 // ETicketService định nghĩa interface cho e-ticket service
 type ETicketService interface {
 	GenerateETicket(ctx context.Context, bookingID uuid.UUID) (*bytes.Buffer, error)
+	CheckInTicket(ctx context.Context, tripID uuid.UUID, req *model.TicketCheckInRequest) (*model.TicketCheckInResponse, error)
 }
 
 type eTicketServiceImpl struct {
 	bookingRepo repository.BookingRepository
 	tripClient  client.TripClient
+	qrSecret    string
 }
 
 // NewETicketService tạo mới e-ticket service
 func NewETicketService(
 	bookingRepo repository.BookingRepository,
 	tripClient client.TripClient,
+	qrSecret string,
 ) ETicketService {
 	return &eTicketServiceImpl{
 		bookingRepo: bookingRepo,
 		tripClient:  tripClient,
+		qrSecret:    qrSecret,
 	}
 }
 
@@ -107,6 +112,15 @@ func (s *eTicketServiceImpl) GenerateETicket(ctx context.Context, bookingID uuid
 	s.addInfoRow(pdf, "Ghe da chon:", fmt.Sprintf("%d ghe: %v", len(seatNumbers), seatNumbers))
 	pdf.Ln(5)
 
+	// Hành khách theo từng ghế (chỉ in khi người đặt đã khai báo)
+	if hasNamedPassengers(booking.BookingSeats) {
+		s.addSection(pdf, "HANH KHACH")
+		for _, seat := range booking.BookingSeats {
+			s.addInfoRow(pdf, fmt.Sprintf("Ghe %s:", seat.SeatNumber), formatSeatPassenger(seat))
+		}
+		pdf.Ln(5)
+	}
+
 	// Thông tin thanh toán
 	s.addSection(pdf, "THONG TIN THANH TOAN")
 	s.addInfoRow(pdf, "Tong tien:", fmt.Sprintf("%d VND", booking.TotalAmount))
@@ -116,12 +130,12 @@ func (s *eTicketServiceImpl) GenerateETicket(ctx context.Context, bookingID uuid
 	}
 	pdf.Ln(5)
 
-	// QR Code
-	qrData := fmt.Sprintf("BOOKING:%s|REF:%s|AMOUNT:%d",
-		booking.ID.String(),
-		booking.BookingReference,
-		booking.TotalAmount,
-	)
+	// QR Code - signed payload được quét khi lên xe
+	qrData, err := model.NewETicketQRPayload(booking).Encode(s.qrSecret)
+	if err != nil {
+		log.Error().Err(err).Str("booking_id", bookingID.String()).Msg("Failed to sign e-ticket QR payload")
+		return nil, ginext.NewInternalServerError("Failed to generate e-ticket")
+	}
 	qrCode, err := qrcode.Encode(qrData, qrcode.Medium, 200)
 	if err == nil {
 		// Tạo temporary file cho QR code
@@ -154,6 +168,167 @@ func (s *eTicketServiceImpl) GenerateETicket(ctx context.Context, bookingID uuid
 	return &buf, nil
 }
 
+// CheckInTicket xác thực QR code trên e-ticket và cho từng ghế lên xe
+func (s *eTicketServiceImpl) CheckInTicket(ctx context.Context, tripID uuid.UUID, req *model.TicketCheckInRequest) (*model.TicketCheckInResponse, error) {
+	payload, err := model.ParseETicketQR(req.QRCode, s.qrSecret)
+	if err != nil {
+		return nil, ginext.NewBadRequestError("Invalid ticket QR code")
+	}
+
+	if payload.TripID != tripID {
+		return nil, ginext.NewBadRequestError("Ticket is for a different trip")
+	}
+
+	booking, err := s.bookingRepo.GetBookingByID(ctx, payload.BookingID)
+	if err != nil {
+		log.Error().Err(err).Str("booking_id", payload.BookingID.String()).Msg("Failed to get booking")
+		return nil, ginext.NewNotFoundError("Booking not found")
+	}
+
+	if booking.BookingReference != payload.BookingReference {
+		return nil, ginext.NewBadRequestError("Invalid ticket QR code")
+	}
+
+	// Vé cũ của booking đã đổi sang chuyến khác không còn giá trị
+	if booking.TripID != tripID {
+		return nil, ginext.NewBadRequestError("Ticket is for a different trip")
+	}
+
+	switch booking.Status {
+	case model.BookingStatusConfirmed:
+	case model.BookingStatusCancelled:
+		return nil, ginext.NewBadRequestError("Booking has been cancelled")
+	default:
+		return nil, ginext.NewBadRequestError("Booking is not confirmed")
+	}
+
+	seatIDs, err := s.selectSeatsToBoard(booking, payload, req.SeatIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.bookingRepo.CheckInSeats(ctx, booking.ID, seatIDs); err != nil {
+		if errors.Is(err, model.ErrSeatAlreadyBoarded) {
+			return nil, ginext.NewConflictError("Seat has already boarded")
+		}
+		log.Error().Err(err).Str("booking_id", booking.ID.String()).Msg("Failed to check in seats")
+		return nil, ginext.NewInternalServerError("Failed to check in seats")
+	}
+
+	return s.toCheckInResponse(booking, seatIDs), nil
+}
+
+// selectSeatsToBoard trả về các ghế cần check-in: ghế được yêu cầu, hoặc mọi ghế chưa lên xe trên vé
+func (s *eTicketServiceImpl) selectSeatsToBoard(booking *model.Booking, payload *model.ETicketQRPayload, requested []uuid.UUID) ([]uuid.UUID, error) {
+	onTicket := make(map[uuid.UUID]bool, len(payload.SeatIDs))
+	for _, seatID := range payload.SeatIDs {
+		onTicket[seatID] = true
+	}
+
+	seats := make(map[uuid.UUID]*model.BookingSeat, len(booking.BookingSeats))
+	for i := range booking.BookingSeats {
+		seat := &booking.BookingSeats[i]
+		if onTicket[seat.SeatID] {
+			seats[seat.SeatID] = seat
+		}
+	}
+
+	if len(requested) == 0 {
+		var seatIDs []uuid.UUID
+		for i := range booking.BookingSeats {
+			seat := &booking.BookingSeats[i]
+			if seats[seat.SeatID] != nil && !seat.IsBoarded {
+				seatIDs = append(seatIDs, seat.SeatID)
+			}
+		}
+		if len(seatIDs) == 0 {
+			return nil, ginext.NewConflictError("All passengers on this ticket have already boarded")
+		}
+		return seatIDs, nil
+	}
+
+	seatIDs := make([]uuid.UUID, 0, len(requested))
+	selected := make(map[uuid.UUID]bool, len(requested))
+	for _, seatID := range requested {
+		if selected[seatID] {
+			continue
+		}
+		seat := seats[seatID]
+		if seat == nil {
+			return nil, ginext.NewBadRequestError(fmt.Sprintf("Seat %s is not on this ticket", seatID))
+		}
+		if seat.IsBoarded {
+			return nil, ginext.NewConflictError(fmt.Sprintf("Seat %s has already boarded", seat.SeatNumber))
+		}
+		selected[seatID] = true
+		seatIDs = append(seatIDs, seatID)
+	}
+	return seatIDs, nil
+}
+
+func (s *eTicketServiceImpl) toCheckInResponse(booking *model.Booking, boarded []uuid.UUID) *model.TicketCheckInResponse {
+	boardedNow := make(map[uuid.UUID]bool, len(boarded))
+	for _, seatID := range boarded {
+		boardedNow[seatID] = true
+	}
+
+	now := time.Now().UTC()
+	resp := &model.TicketCheckInResponse{
+		BookingID:        booking.ID,
+		BookingReference: booking.BookingReference,
+		TripID:           booking.TripID,
+		CheckedIn:        make([]string, 0, len(boarded)),
+		Seats:            make([]model.TicketSeatCheckInInfo, 0, len(booking.BookingSeats)),
+		AllBoarded:       true,
+	}
+
+	for _, seat := range booking.BookingSeats {
+		info := model.TicketSeatCheckInInfo{
+			SeatID:     seat.SeatID,
+			SeatNumber: seat.SeatNumber,
+			IsBoarded:  seat.IsBoarded,
+			BoardedAt:  seat.BoardedAt,
+		}
+		if boardedNow[seat.SeatID] {
+			info.IsBoarded = true
+			info.BoardedAt = &now
+			resp.CheckedIn = append(resp.CheckedIn, seat.SeatNumber)
+		}
+		if !info.IsBoarded {
+			resp.AllBoarded = false
+		}
+		resp.Seats = append(resp.Seats, info)
+	}
+
+	return resp
+}
+
+// hasNamedPassengers kiểm tra booking có ghế nào được khai báo tên hành khách
+func hasNamedPassengers(seats []model.BookingSeat) bool {
+	for _, seat := range seats {
+		if seat.PassengerName != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// formatSeatPassenger hiển thị hành khách của ghế: tên, loại vé và số giấy tờ
+func formatSeatPassenger(seat model.BookingSeat) string {
+	if seat.PassengerName == "" {
+		return "-"
+	}
+
+	text := seat.PassengerName
+	if seat.PassengerAgeCategory != "" {
+		text += fmt.Sprintf(" (%s)", seat.PassengerAgeCategory)
+	}
+	if seat.PassengerIDNumber != "" {
+		text += fmt.Sprintf(" - CCCD: %s", seat.PassengerIDNumber)
+	}
+	return text
+}
+
 // addSection thêm tiêu đề section
 func (s *eTicketServiceImpl) addSection(pdf *gofpdf.Fpdf, title string) {
 	pdf.SetFont("Arial", "B", 14)
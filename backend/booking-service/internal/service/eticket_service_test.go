@@ -23,7 +23,7 @@ func TestNewETicketService(t *testing.T) {
 	mockBookingRepo := repo_mocks.NewMockBookingRepository(ctrl)
 	mockTripClient := mocks.NewMockTripClient(ctrl)
 
-	service := NewETicketService(mockBookingRepo, mockTripClient)
+	service := NewETicketService(mockBookingRepo, mockTripClient, testQRSecret)
 
 	assert.NotNil(t, service)
 	assert.IsType(t, &eTicketServiceImpl{}, service)
@@ -35,7 +35,7 @@ func TestGenerateETicket_Success(t *testing.T) {
 
 	mockBookingRepo := repo_mocks.NewMockBookingRepository(ctrl)
 	mockTripClient := mocks.NewMockTripClient(ctrl)
-	service := NewETicketService(mockBookingRepo, mockTripClient)
+	service := NewETicketService(mockBookingRepo, mockTripClient, testQRSecret)
 
 	ctx := context.Background()
 	bookingID := uuid.New()
@@ -84,7 +84,7 @@ func TestGenerateETicket_BookingNotFound(t *testing.T) {
 
 	mockBookingRepo := repo_mocks.NewMockBookingRepository(ctrl)
 	mockTripClient := mocks.NewMockTripClient(ctrl)
-	service := NewETicketService(mockBookingRepo, mockTripClient)
+	service := NewETicketService(mockBookingRepo, mockTripClient, testQRSecret)
 
 	ctx := context.Background()
 	bookingID := uuid.New()
@@ -107,7 +107,7 @@ func TestGenerateETicket_BookingNotConfirmed(t *testing.T) {
 
 	mockBookingRepo := repo_mocks.NewMockBookingRepository(ctrl)
 	mockTripClient := mocks.NewMockTripClient(ctrl)
-	service := NewETicketService(mockBookingRepo, mockTripClient)
+	service := NewETicketService(mockBookingRepo, mockTripClient, testQRSecret)
 
 	ctx := context.Background()
 	bookingID := uuid.New()
@@ -135,7 +135,7 @@ func TestGenerateETicket_SuccessWithoutTripData(t *testing.T) {
 
 	mockBookingRepo := repo_mocks.NewMockBookingRepository(ctrl)
 	mockTripClient := mocks.NewMockTripClient(ctrl)
-	service := NewETicketService(mockBookingRepo, mockTripClient)
+	service := NewETicketService(mockBookingRepo, mockTripClient, testQRSecret)
 
 	ctx := context.Background()
 	bookingID := uuid.New()
@@ -176,7 +176,7 @@ func TestGetPaymentStatusText(t *testing.T) {
 
 	mockBookingRepo := repo_mocks.NewMockBookingRepository(ctrl)
 	mockTripClient := mocks.NewMockTripClient(ctrl)
-	service := NewETicketService(mockBookingRepo, mockTripClient).(*eTicketServiceImpl)
+	service := NewETicketService(mockBookingRepo, mockTripClient, testQRSecret).(*eTicketServiceImpl)
 
 	tests := []struct {
 		status   payment.TransactionStatus
@@ -193,3 +193,236 @@ func TestGetPaymentStatusText(t *testing.T) {
 		assert.Equal(t, tt.expected, result)
 	}
 }
+
+const testQRSecret = "test-eticket-secret"
+
+type checkInTestFixture struct {
+	service     ETicketService
+	bookingRepo *repo_mocks.MockBookingRepository
+	booking     *model.Booking
+	qrCode      string
+}
+
+// newCheckInTestFixture builds a confirmed two-seat booking and its signed QR code
+func newCheckInTestFixture(t *testing.T, ctrl *gomock.Controller) *checkInTestFixture {
+	mockBookingRepo := repo_mocks.NewMockBookingRepository(ctrl)
+	booking := &model.Booking{
+		BaseModel:        model.BaseModel{ID: uuid.New()},
+		BookingReference: "BK123456",
+		TripID:           uuid.New(),
+		Status:           model.BookingStatusConfirmed,
+		BookingSeats: []model.BookingSeat{
+			{SeatID: uuid.New(), SeatNumber: "A1"},
+			{SeatID: uuid.New(), SeatNumber: "A2"},
+		},
+	}
+
+	qrCode, err := model.NewETicketQRPayload(booking).Encode(testQRSecret)
+	assert.NoError(t, err)
+
+	return &checkInTestFixture{
+		service:     NewETicketService(mockBookingRepo, mocks.NewMockTripClient(ctrl), testQRSecret),
+		bookingRepo: mockBookingRepo,
+		booking:     booking,
+		qrCode:      qrCode,
+	}
+}
+
+func TestETicketQR_RoundTrip(t *testing.T) {
+	booking := &model.Booking{
+		BaseModel:        model.BaseModel{ID: uuid.New()},
+		BookingReference: "BK123456",
+		TripID:           uuid.New(),
+		BookingSeats:     []model.BookingSeat{{SeatID: uuid.New()}},
+	}
+
+	code, err := model.NewETicketQRPayload(booking).Encode(testQRSecret)
+	assert.NoError(t, err)
+
+	payload, err := model.ParseETicketQR(code, testQRSecret)
+	assert.NoError(t, err)
+	assert.Equal(t, booking.ID, payload.BookingID)
+	assert.Equal(t, booking.TripID, payload.TripID)
+	assert.Equal(t, []uuid.UUID{booking.BookingSeats[0].SeatID}, payload.SeatIDs)
+
+	_, err = model.ParseETicketQR(code, "another-secret")
+	assert.ErrorIs(t, err, model.ErrInvalidETicketQR)
+
+	tampered := code[:len(code)-2] + "xx"
+	_, err = model.ParseETicketQR(tampered, testQRSecret)
+	assert.ErrorIs(t, err, model.ErrInvalidETicketQR)
+}
+
+func TestCheckInTicket_BoardsRemainingSeats(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	f := newCheckInTestFixture(t, ctrl)
+	ctx := context.Background()
+	f.booking.BookingSeats[0].IsBoarded = true
+
+	f.bookingRepo.EXPECT().GetBookingByID(ctx, f.booking.ID).Return(f.booking, nil)
+	f.bookingRepo.EXPECT().
+		CheckInSeats(ctx, f.booking.ID, []uuid.UUID{f.booking.BookingSeats[1].SeatID}).
+		Return(nil)
+
+	resp, err := f.service.CheckInTicket(ctx, f.booking.TripID, &model.TicketCheckInRequest{QRCode: f.qrCode})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"A2"}, resp.CheckedIn)
+	assert.True(t, resp.AllBoarded)
+}
+
+func TestCheckInTicket_PartialGroup(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	f := newCheckInTestFixture(t, ctrl)
+	ctx := context.Background()
+	seatID := f.booking.BookingSeats[0].SeatID
+
+	f.bookingRepo.EXPECT().GetBookingByID(ctx, f.booking.ID).Return(f.booking, nil)
+	f.bookingRepo.EXPECT().CheckInSeats(ctx, f.booking.ID, []uuid.UUID{seatID}).Return(nil)
+
+	resp, err := f.service.CheckInTicket(ctx, f.booking.TripID, &model.TicketCheckInRequest{
+		QRCode:  f.qrCode,
+		SeatIDs: []uuid.UUID{seatID, seatID},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"A1"}, resp.CheckedIn)
+	assert.False(t, resp.AllBoarded)
+	assert.True(t, resp.Seats[0].IsBoarded)
+	assert.False(t, resp.Seats[1].IsBoarded)
+}
+
+func TestCheckInTicket_InvalidSignature(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	f := newCheckInTestFixture(t, ctrl)
+	forged, err := model.NewETicketQRPayload(f.booking).Encode("forged-secret")
+	assert.NoError(t, err)
+
+	_, err = f.service.CheckInTicket(context.Background(), f.booking.TripID, &model.TicketCheckInRequest{QRCode: forged})
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "Invalid ticket QR code")
+}
+
+func TestCheckInTicket_WrongTrip(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	f := newCheckInTestFixture(t, ctrl)
+
+	_, err := f.service.CheckInTicket(context.Background(), uuid.New(), &model.TicketCheckInRequest{QRCode: f.qrCode})
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "different trip")
+}
+
+func TestCheckInTicket_ExchangedToAnotherTrip(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	f := newCheckInTestFixture(t, ctrl)
+	ctx := context.Background()
+	tripID := f.booking.TripID
+
+	exchanged := *f.booking
+	exchanged.TripID = uuid.New()
+	f.bookingRepo.EXPECT().GetBookingByID(ctx, f.booking.ID).Return(&exchanged, nil)
+
+	_, err := f.service.CheckInTicket(ctx, tripID, &model.TicketCheckInRequest{QRCode: f.qrCode})
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "different trip")
+}
+
+func TestCheckInTicket_CancelledBooking(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	f := newCheckInTestFixture(t, ctrl)
+	ctx := context.Background()
+	f.booking.Status = model.BookingStatusCancelled
+
+	f.bookingRepo.EXPECT().GetBookingByID(ctx, f.booking.ID).Return(f.booking, nil)
+
+	_, err := f.service.CheckInTicket(ctx, f.booking.TripID, &model.TicketCheckInRequest{QRCode: f.qrCode})
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "cancelled")
+}
+
+func TestCheckInTicket_SeatAlreadyBoarded(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	f := newCheckInTestFixture(t, ctrl)
+	ctx := context.Background()
+	f.booking.BookingSeats[0].IsBoarded = true
+
+	f.bookingRepo.EXPECT().GetBookingByID(ctx, f.booking.ID).Return(f.booking, nil)
+
+	_, err := f.service.CheckInTicket(ctx, f.booking.TripID, &model.TicketCheckInRequest{
+		QRCode:  f.qrCode,
+		SeatIDs: []uuid.UUID{f.booking.BookingSeats[0].SeatID},
+	})
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "Seat A1 has already boarded")
+}
+
+func TestCheckInTicket_AllSeatsAlreadyBoarded(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	f := newCheckInTestFixture(t, ctrl)
+	ctx := context.Background()
+	for i := range f.booking.BookingSeats {
+		f.booking.BookingSeats[i].IsBoarded = true
+	}
+
+	f.bookingRepo.EXPECT().GetBookingByID(ctx, f.booking.ID).Return(f.booking, nil)
+
+	_, err := f.service.CheckInTicket(ctx, f.booking.TripID, &model.TicketCheckInRequest{QRCode: f.qrCode})
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "already boarded")
+}
+
+func TestCheckInTicket_ConcurrentScan(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	f := newCheckInTestFixture(t, ctrl)
+	ctx := context.Background()
+
+	f.bookingRepo.EXPECT().GetBookingByID(ctx, f.booking.ID).Return(f.booking, nil)
+	f.bookingRepo.EXPECT().CheckInSeats(ctx, f.booking.ID, gomock.Any()).Return(model.ErrSeatAlreadyBoarded)
+
+	_, err := f.service.CheckInTicket(ctx, f.booking.TripID, &model.TicketCheckInRequest{QRCode: f.qrCode})
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "already boarded")
+}
+
+func TestCheckInTicket_SeatNotOnTicket(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	f := newCheckInTestFixture(t, ctrl)
+	ctx := context.Background()
+
+	f.bookingRepo.EXPECT().GetBookingByID(ctx, f.booking.ID).Return(f.booking, nil)
+
+	_, err := f.service.CheckInTicket(ctx, f.booking.TripID, &model.TicketCheckInRequest{
+		QRCode:  f.qrCode,
+		SeatIDs: []uuid.UUID{uuid.New()},
+	})
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "is not on this ticket")
+}
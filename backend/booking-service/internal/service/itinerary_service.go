@@ -0,0 +1,506 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"bus-booking/booking-service/internal/client"
+	"bus-booking/booking-service/internal/constants"
+	"bus-booking/booking-service/internal/model"
+	"bus-booking/booking-service/internal/model/payment"
+	"bus-booking/booking-service/internal/model/trip"
+	"bus-booking/booking-service/internal/repository"
+	"bus-booking/shared/ginext"
+	"bus-booking/shared/queue"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+	"golang.org/x/sync/errgroup"
+)
+
+type ItineraryService interface {
+	// CreateItinerary books every leg under one reference and opens a single payment for all of them.
+	// Either every leg is booked or none is, and a payment that cannot be created fails them all.
+	CreateItinerary(ctx context.Context, req *model.CreateItineraryRequest, userID uuid.UUID) (*model.ItineraryResponse, error)
+	GetItinerary(ctx context.Context, id uuid.UUID, userID uuid.UUID) (*model.ItineraryResponse, error)
+	ListUserItineraries(ctx context.Context, req model.PaginationRequest, userID uuid.UUID) ([]*model.ItineraryResponse, int64, error)
+	// CancelItinerary cancels the legs that can still be cancelled. Unpaid legs go together with their
+	// payment; paid legs are each refunded by the cancellation policy.
+	CancelItinerary(ctx context.Context, id uuid.UUID, userID uuid.UUID, reason string) error
+}
+
+type itineraryServiceImpl struct {
+	itineraryRepo      repository.ItineraryRepository
+	bookingRepo        repository.BookingRepository
+	bookingService     BookingService
+	paymentClient      client.PaymentClient
+	tripClient         client.TripClient
+	seatLockService    SeatLockService
+	userClient         client.UserClient
+	notificationClient client.NotificationClient
+	delayedQueue       queue.DelayedQueueManager
+	roundTripDiscounts []model.RoundTripDiscount
+}
+
+func NewItineraryService(
+	itineraryRepo repository.ItineraryRepository,
+	bookingRepo repository.BookingRepository,
+	bookingService BookingService,
+	paymentClient client.PaymentClient,
+	tripClient client.TripClient,
+	seatLockService SeatLockService,
+	userClient client.UserClient,
+	notificationClient client.NotificationClient,
+	delayedQueue queue.DelayedQueueManager,
+	roundTripDiscounts []model.RoundTripDiscount,
+) ItineraryService {
+	return &itineraryServiceImpl{
+		itineraryRepo:      itineraryRepo,
+		bookingRepo:        bookingRepo,
+		bookingService:     bookingService,
+		paymentClient:      paymentClient,
+		tripClient:         tripClient,
+		seatLockService:    seatLockService,
+		userClient:         userClient,
+		notificationClient: notificationClient,
+		delayedQueue:       delayedQueue,
+		roundTripDiscounts: roundTripDiscounts,
+	}
+}
+
+// itineraryLeg is a leg being booked with where and when it travels
+type itineraryLeg struct {
+	booking     *model.Booking
+	origin      string
+	destination string
+	departsAt   time.Time
+	arrivesAt   time.Time
+}
+
+func (s *itineraryServiceImpl) CreateItinerary(ctx context.Context, req *model.CreateItineraryRequest, userID uuid.UUID) (*model.ItineraryResponse, error) {
+	seenTrips := make(map[uuid.UUID]bool, len(req.Legs))
+	for _, legReq := range req.Legs {
+		if seenTrips[legReq.TripID] {
+			return nil, ginext.NewBadRequestError("each leg of an itinerary must be on a different trip")
+		}
+		seenTrips[legReq.TripID] = true
+	}
+
+	// 1. Price every leg on its own trip
+	legs := make([]*itineraryLeg, len(req.Legs))
+	g, gCtx := errgroup.WithContext(ctx)
+	for i := range req.Legs {
+		i := i
+		g.Go(func() error {
+			leg, err := s.prepareLeg(gCtx, &req.Legs[i], req.SessionID)
+			legs[i] = leg
+			return err
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	// 2. Each leg must leave after the previous one arrives
+	for i := 1; i < len(legs); i++ {
+		if !legs[i].departsAt.After(legs[i-1].arrivesAt) {
+			return nil, ginext.NewBadRequestError(fmt.Sprintf("leg %d departs before leg %d arrives", i+1, i))
+		}
+	}
+
+	// 3. Round trips get the configured discount, taken off every leg alike
+	kind := model.ItineraryKindMultiLeg
+	discountPercent := 0
+	if isRoundTrip(legs) {
+		kind = model.ItineraryKindRoundTrip
+		discountPercent = roundTripDiscountPercent(s.roundTripDiscounts, legs[1].departsAt.Sub(legs[0].departsAt))
+	}
+
+	// 4. Build the itinerary; its legs share one transaction and one expiry
+	paymentMethod := req.PaymentMethod
+	if paymentMethod == "" {
+		paymentMethod = payment.PaymentMethodPayOS
+	}
+	expiresAt := time.Now().UTC().Add(constants.BookingPaymentTimeout)
+	itinerary := &model.Itinerary{
+		BaseModel: model.BaseModel{
+			ID: uuid.New(),
+		},
+		ItineraryReference: generateReference(constants.ItineraryReferencePrefix),
+		UserID:             userID,
+		Kind:               kind,
+		TransactionID:      uuid.New(),
+		PaymentMethod:      paymentMethod,
+		ExpiresAt:          &expiresAt,
+	}
+	for i, leg := range legs {
+		booking := leg.booking
+		booking.ID = uuid.New()
+		booking.BookingReference = generateReference(constants.BookingReferencePrefix)
+		booking.UserID = userID
+		booking.ItineraryID = &itinerary.ID
+		booking.LegNumber = i + 1
+		booking.TransactionID = itinerary.TransactionID
+		booking.PaymentMethod = paymentMethod
+		booking.Notes = req.Notes
+		booking.ExpiresAt = &expiresAt
+		booking.DiscountAmount = booking.TotalAmount * discountPercent / 100
+		booking.TotalAmount -= booking.DiscountAmount
+
+		itinerary.TotalAmount += booking.TotalAmount
+		itinerary.DiscountAmount += booking.DiscountAmount
+		itinerary.Bookings = append(itinerary.Bookings, *booking)
+	}
+	lead := &itinerary.Bookings[0]
+
+	// 5. Save every leg, converting the seat holds into booking seats atomically
+	sessionID := req.SessionID
+	if sessionID == "" {
+		sessionID = itinerary.ID.String()
+	}
+	if err := s.itineraryRepo.CreateFromHolds(ctx, itinerary, sessionID); err != nil {
+		if errors.Is(err, model.ErrSeatsUnavailable) {
+			return nil, ginext.NewConflictError("one or more selected seats are no longer available")
+		}
+		return nil, ginext.NewInternalServerError(fmt.Sprintf("failed to create itinerary: %v", err))
+	}
+
+	// 6. One payment for every leg, made out to the lead leg
+	legBookingIDs := make([]uuid.UUID, 0, len(itinerary.Bookings)-1)
+	for _, booking := range itinerary.Bookings[1:] {
+		legBookingIDs = append(legBookingIDs, booking.ID)
+	}
+	transaction, err := s.paymentClient.CreateTransaction(ctx, &payment.CreateTransactionRequest{
+		ID:            itinerary.TransactionID,
+		BookingID:     lead.ID,
+		LegBookingIDs: legBookingIDs,
+		Amount:        itinerary.TotalAmount,
+		Currency:      payment.CurrencyVND,
+		PaymentMethod: paymentMethod,
+		Description:   fmt.Sprintf("Don hang %s", itinerary.ItineraryReference),
+		ExpiresAt:     expiresAt,
+		InvoiceBuyer:  req.BuyerInfo.InvoiceBuyer(),
+		UseWallet:     req.UseWallet,
+	})
+	if err != nil {
+		log.Error().Err(err).
+			Str("itinerary_id", itinerary.ID.String()).
+			Str("transaction_id", itinerary.TransactionID.String()).
+			Msg("Payment link creation failed for itinerary")
+
+		// Fail every leg together, releasing all their seats
+		failed := make([]*model.Booking, len(itinerary.Bookings))
+		for i := range itinerary.Bookings {
+			itinerary.Bookings[i].Status = model.BookingStatusFailed
+			itinerary.Bookings[i].TransactionStatus = payment.TransactionStatusFailed
+			failed[i] = &itinerary.Bookings[i]
+		}
+		if updateErr := s.bookingRepo.UpdateBookings(ctx, failed, uuid.Nil); updateErr != nil {
+			log.Error().Err(updateErr).
+				Str("itinerary_id", itinerary.ID.String()).
+				Msg("Failed to update itinerary bookings after payment failure")
+		}
+
+		resp := toItineraryResponse(itinerary)
+		resp.Transaction = &payment.TransactionResponse{
+			ID:     itinerary.TransactionID,
+			Status: payment.TransactionStatusFailed,
+		}
+		return resp, nil
+	}
+
+	// 7. Send the pending email and schedule one expiry for every leg, on the lead leg
+	payAtCounter := paymentMethod == payment.PaymentMethodCash
+	if transaction != nil && (transaction.CheckoutURL != "" || payAtCounter) {
+		pending := &client.BookingPendingRequest{
+			BookingReference: itinerary.ItineraryReference,
+			From:             legs[0].origin,
+			To:               legs[len(legs)-1].destination,
+			DepartureTime:    legs[0].departsAt.Format(constants.DateTimeFormatDisplay),
+			TotalAmount:      itinerary.TotalAmount,
+			PaymentLink:      transaction.CheckoutURL,
+		}
+		if kind == model.ItineraryKindRoundTrip {
+			pending.To = legs[0].destination
+		}
+
+		go func() {
+			bgCtx, cancel := context.WithTimeout(context.Background(), constants.BackgroundTaskTimeout)
+			defer cancel()
+
+			if pending.PaymentLink != "" {
+				s.sendPendingEmail(bgCtx, userID, pending)
+			}
+
+			item := &queue.DelayedItem{
+				ID:      lead.ID.String(), // one pending expiry per itinerary
+				Payload: lead.ID,
+			}
+			if err := s.delayedQueue.Schedule(bgCtx, constants.QueueNameBookingExpiry, item, expiresAt); err != nil {
+				log.Error().Err(err).
+					Str("itinerary_id", itinerary.ID.String()).
+					Time("expires_at", expiresAt).
+					Msg("Failed to schedule itinerary expiration")
+			}
+		}()
+	}
+
+	resp := toItineraryResponse(itinerary)
+	resp.Transaction = transaction
+	return resp, nil
+}
+
+// prepareLeg prices a leg on its trip like CreateBooking does, leaving the booking's identity to the caller
+func (s *itineraryServiceImpl) prepareLeg(ctx context.Context, req *model.ItineraryLegRequest, sessionID string) (*itineraryLeg, error) {
+	passengers, err := indexPassengersBySeat(req.SeatIDs, req.Passengers)
+	if err != nil {
+		return nil, err
+	}
+
+	tripData, err := s.tripClient.GetTripByID(ctx, trip.GetTripByIDRequest{
+		PreLoadRoute:     true,
+		PreLoadRouteStop: true,
+	}, req.TripID)
+	if err != nil {
+		return nil, ginext.NewInternalServerError(fmt.Sprintf("failed to get trip data: %v", err))
+	}
+	if tripData.Route == nil {
+		return nil, ginext.NewInternalServerError("trip route not available")
+	}
+	if !isOpenForBooking(tripData, time.Now().UTC()) {
+		return nil, ginext.NewBadRequestError(fmt.Sprintf("trip %s is not open for booking", req.TripID))
+	}
+
+	booking := &model.Booking{
+		TripID:            req.TripID,
+		Status:            model.BookingStatusPending,
+		TransactionStatus: payment.TransactionStatusPending,
+	}
+	leg := &itineraryLeg{
+		booking:     booking,
+		origin:      tripData.Route.Origin,
+		destination: tripData.Route.Destination,
+		departsAt:   tripData.DepartureTime,
+		arrivesAt:   tripData.ArrivalTime,
+	}
+
+	// Resolve the booked segment, prorating the fare by distance
+	segment := model.FullTripSegment()
+	fraction := 1.0
+	if req.PickupStopID != nil || req.DropoffStopID != nil {
+		if req.PickupStopID == nil || req.DropoffStopID == nil {
+			return nil, ginext.NewBadRequestError("pickup_stop_id and dropoff_stop_id must be provided together")
+		}
+		pickup, dropoff, err := findSegmentStops(tripData.Route, *req.PickupStopID, *req.DropoffStopID)
+		if err != nil {
+			return nil, err
+		}
+		segment = model.TripSegment{
+			FromStopOrder: pickup.StopOrder,
+			ToStopOrder:   dropoff.StopOrder,
+		}
+		fraction = tripData.Route.DistanceFraction(pickup, dropoff)
+
+		booking.PickupStopID = &pickup.ID
+		booking.DropoffStopID = &dropoff.ID
+		booking.PickupStopOrder = &pickup.StopOrder
+		booking.DropoffStopOrder = &dropoff.StopOrder
+		booking.PickupLocation = pickup.Location
+		booking.DropoffLocation = dropoff.Location
+
+		leg.origin = pickup.Location
+		leg.destination = dropoff.Location
+		leg.departsAt = tripData.DepartureTime.Add(time.Duration(pickup.OffsetMinutes) * time.Minute)
+		if dropoff.OffsetMinutes > 0 {
+			leg.arrivesAt = tripData.DepartureTime.Add(time.Duration(dropoff.OffsetMinutes) * time.Minute)
+		}
+	}
+
+	bookedSeatIDs, err := s.bookingRepo.GetBookedSeatIDs(ctx, req.TripID, segment)
+	if err != nil {
+		return nil, ginext.NewInternalServerError(fmt.Sprintf("failed to check seat availability: %v", err))
+	}
+	booked := make(map[uuid.UUID]bool, len(bookedSeatIDs))
+	for _, id := range bookedSeatIDs {
+		booked[id] = true
+	}
+	for _, id := range req.SeatIDs {
+		if booked[id] {
+			return nil, ginext.NewBadRequestError("one or more selected seats are already booked")
+		}
+	}
+
+	seats, err := s.tripClient.ListSeatsByIDs(ctx, req.SeatIDs)
+	if err != nil {
+		return nil, ginext.NewInternalServerError(fmt.Sprintf("failed to list seats: %v", err))
+	}
+
+	// Seats are charged the fare locked in when the session held them
+	fares, err := s.seatLockService.QuoteSeatFares(ctx, req.TripID, sessionID, req.SeatIDs)
+	if err != nil {
+		return nil, err
+	}
+	seatPrices, total := priceSeats(seats, fares, fraction)
+
+	for _, seat := range seats {
+		bookingSeat := model.BookingSeat{
+			SeatID:          seat.ID,
+			SeatNumber:      seat.SeatNumber,
+			SeatType:        seat.SeatType,
+			Floor:           seat.Floor,
+			Price:           seatPrices[seat.ID],
+			PriceMultiplier: seat.PriceMultiplier,
+		}
+		if passenger, ok := passengers[seat.ID]; ok {
+			bookingSeat.PassengerName = strings.TrimSpace(passenger.FullName)
+			bookingSeat.PassengerPhone = passenger.Phone
+			bookingSeat.PassengerIDNumber = strings.TrimSpace(passenger.IDNumber)
+			bookingSeat.PassengerAgeCategory = passenger.AgeCategory
+		}
+		booking.BookingSeats = append(booking.BookingSeats, bookingSeat)
+	}
+	booking.TotalAmount = total
+
+	return leg, nil
+}
+
+// isRoundTrip reports whether the legs go out and come back between the same two places
+func isRoundTrip(legs []*itineraryLeg) bool {
+	return len(legs) == 2 &&
+		strings.EqualFold(strings.TrimSpace(legs[0].origin), strings.TrimSpace(legs[1].destination)) &&
+		strings.EqualFold(strings.TrimSpace(legs[0].destination), strings.TrimSpace(legs[1].origin))
+}
+
+// roundTripDiscountPercent returns the best discount whose stay covers the time between the
+// outbound and return departures, 0 when none does
+func roundTripDiscountPercent(discounts []model.RoundTripDiscount, stay time.Duration) int {
+	best := 0
+	for _, discount := range discounts {
+		if stay <= time.Duration(discount.WithinDays)*24*time.Hour && discount.Percent > best {
+			best = discount.Percent
+		}
+	}
+	return best
+}
+
+func (s *itineraryServiceImpl) GetItinerary(ctx context.Context, id uuid.UUID, userID uuid.UUID) (*model.ItineraryResponse, error) {
+	itinerary, err := s.getOwnItinerary(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := toItineraryResponse(itinerary)
+	transaction, err := s.paymentClient.GetTransactionByID(ctx, itinerary.TransactionID)
+	if err != nil {
+		log.Warn().Err(err).
+			Str("itinerary_id", itinerary.ID.String()).
+			Msg("Failed to get itinerary transaction")
+	} else {
+		resp.Transaction = transaction
+	}
+	return resp, nil
+}
+
+func (s *itineraryServiceImpl) ListUserItineraries(ctx context.Context, req model.PaginationRequest, userID uuid.UUID) ([]*model.ItineraryResponse, int64, error) {
+	itineraries, total, err := s.itineraryRepo.ListByUserID(ctx, userID, req.PageSize, (req.Page-1)*req.PageSize)
+	if err != nil {
+		return nil, 0, ginext.NewInternalServerError("failed to list itineraries")
+	}
+
+	responses := make([]*model.ItineraryResponse, len(itineraries))
+	for i, itinerary := range itineraries {
+		responses[i] = toItineraryResponse(itinerary)
+	}
+	return responses, total, nil
+}
+
+func (s *itineraryServiceImpl) CancelItinerary(ctx context.Context, id uuid.UUID, userID uuid.UUID, reason string) error {
+	itinerary, err := s.getOwnItinerary(ctx, id, userID)
+	if err != nil {
+		return err
+	}
+
+	cancelled := 0
+	unpaidCancelled := false
+	for i := range itinerary.Bookings {
+		leg := &itinerary.Bookings[i]
+		switch leg.Status {
+		case model.BookingStatusConfirmed:
+			// Legs already travelled, or too close to departure, stay as they are
+			quote, err := s.bookingService.GetRefundQuote(ctx, leg.ID)
+			if err != nil {
+				return err
+			}
+			if !quote.Cancellable {
+				continue
+			}
+		case model.BookingStatusPending, model.BookingStatusFailed, model.BookingStatusExpired:
+			// Cancelling one unpaid leg cancels every leg sharing its payment
+			if unpaidCancelled {
+				continue
+			}
+			unpaidCancelled = true
+		default:
+			continue
+		}
+
+		if err := s.bookingService.CancelBooking(ctx, leg.ID, reason); err != nil {
+			return err
+		}
+		cancelled++
+	}
+
+	if cancelled == 0 {
+		return ginext.NewBadRequestError("no leg of the itinerary can be cancelled")
+	}
+	return nil
+}
+
+// getOwnItinerary loads an itinerary of the user; other users' itineraries are not found
+func (s *itineraryServiceImpl) getOwnItinerary(ctx context.Context, id uuid.UUID, userID uuid.UUID) (*model.Itinerary, error) {
+	itinerary, err := s.itineraryRepo.GetByID(ctx, id)
+	if err != nil || itinerary.UserID != userID {
+		return nil, ginext.NewNotFoundError("itinerary not found")
+	}
+	return itinerary, nil
+}
+
+func (s *itineraryServiceImpl) sendPendingEmail(ctx context.Context, userID uuid.UUID, req *client.BookingPendingRequest) {
+	userData, err := s.userClient.GetUserByID(ctx, userID)
+	if err != nil {
+		log.Error().Err(err).Str("user_id", userID.String()).Msg("Failed to fetch user for itinerary pending email")
+		return
+	}
+
+	req.Email = userData.Email
+	req.Name = userData.FullName
+	if err := s.notificationClient.SendBookingPending(ctx, req); err != nil {
+		log.Error().Err(err).
+			Str("itinerary_reference", req.BookingReference).
+			Msg("Failed to send itinerary pending email")
+	}
+}
+
+func toItineraryResponse(itinerary *model.Itinerary) *model.ItineraryResponse {
+	resp := &model.ItineraryResponse{
+		ID:                 itinerary.ID,
+		CreatedAt:          itinerary.CreatedAt,
+		ItineraryReference: itinerary.ItineraryReference,
+		UserID:             itinerary.UserID,
+		Kind:               itinerary.Kind,
+		Status:             itinerary.Status(),
+		TotalAmount:        itinerary.TotalAmount,
+		DiscountAmount:     itinerary.DiscountAmount,
+		TransactionID:      itinerary.TransactionID,
+		PaymentMethod:      itinerary.PaymentMethod,
+		ExpiresAt:          itinerary.ExpiresAt,
+		Legs:               make([]*model.BookingResponse, 0, len(itinerary.Bookings)),
+	}
+	for i := range itinerary.Bookings {
+		resp.Legs = append(resp.Legs, toBookingResponse(&itinerary.Bookings[i]))
+	}
+	return resp
+}
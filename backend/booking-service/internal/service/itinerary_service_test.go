@@ -0,0 +1,402 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"bus-booking/booking-service/internal/client"
+	"bus-booking/booking-service/internal/client/mocks"
+	"bus-booking/booking-service/internal/constants"
+	"bus-booking/booking-service/internal/model"
+	"bus-booking/booking-service/internal/model/payment"
+	"bus-booking/booking-service/internal/model/trip"
+	"bus-booking/booking-service/internal/model/user"
+	repo_mocks "bus-booking/booking-service/internal/repository/mocks"
+	service_mocks "bus-booking/booking-service/internal/service/mocks"
+	"bus-booking/shared/ginext"
+	"bus-booking/shared/queue"
+	queue_mocks "bus-booking/shared/queue/mocks"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+type itineraryTestFixture struct {
+	itineraryRepo      *repo_mocks.MockItineraryRepository
+	bookingRepo        *repo_mocks.MockBookingRepository
+	bookingService     *service_mocks.MockBookingService
+	paymentClient      *mocks.MockPaymentClient
+	tripClient         *mocks.MockTripClient
+	seatLockService    *service_mocks.MockSeatLockService
+	userClient         *mocks.MockUserClient
+	notificationClient *mocks.MockNotificationClient
+	delayedQueue       *queue_mocks.MockDelayedQueueManager
+	service            ItineraryService
+
+	// Sài Gòn to Đà Lạt in two days, and back three days later
+	outbound *trip.Trip
+	inbound  *trip.Trip
+	seats    []trip.Seat
+}
+
+// newItineraryTestFixture gives 10% off round trips returning within 7 days and 5% within 30
+func newItineraryTestFixture(ctrl *gomock.Controller) *itineraryTestFixture {
+	f := &itineraryTestFixture{
+		itineraryRepo:      repo_mocks.NewMockItineraryRepository(ctrl),
+		bookingRepo:        repo_mocks.NewMockBookingRepository(ctrl),
+		bookingService:     service_mocks.NewMockBookingService(ctrl),
+		paymentClient:      mocks.NewMockPaymentClient(ctrl),
+		tripClient:         mocks.NewMockTripClient(ctrl),
+		seatLockService:    service_mocks.NewMockSeatLockService(ctrl),
+		userClient:         mocks.NewMockUserClient(ctrl),
+		notificationClient: mocks.NewMockNotificationClient(ctrl),
+		delayedQueue:       queue_mocks.NewMockDelayedQueueManager(ctrl),
+	}
+	discounts := []model.RoundTripDiscount{{WithinDays: 7, Percent: 10}, {WithinDays: 30, Percent: 5}}
+	f.service = NewItineraryService(f.itineraryRepo, f.bookingRepo, f.bookingService, f.paymentClient, f.tripClient, f.seatLockService, f.userClient, f.notificationClient, f.delayedQueue, discounts)
+
+	for _, number := range []string{"A1", "A2"} {
+		f.seats = append(f.seats, trip.Seat{ID: uuid.New(), SeatNumber: number, SeatType: "standard", PriceMultiplier: 1.0, Floor: 1})
+	}
+	departure := time.Now().UTC().Add(48 * time.Hour)
+	f.outbound = f.newTrip("Sài Gòn", "Đà Lạt", departure)
+	f.inbound = f.newTrip("Đà Lạt", "Sài Gòn", departure.Add(72*time.Hour))
+	return f
+}
+
+func (f *itineraryTestFixture) newTrip(origin, destination string, departure time.Time) *trip.Trip {
+	return &trip.Trip{
+		ID:            uuid.New(),
+		DepartureTime: departure,
+		ArrivalTime:   departure.Add(7 * time.Hour),
+		BasePrice:     300000,
+		Status:        trip.TripStatusScheduled,
+		IsActive:      true,
+		Route:         &trip.Route{Origin: origin, Destination: destination},
+	}
+}
+
+func (f *itineraryTestFixture) legRequest(tripData *trip.Trip) model.ItineraryLegRequest {
+	return model.ItineraryLegRequest{
+		TripID:  tripData.ID,
+		SeatIDs: []uuid.UUID{f.seats[0].ID, f.seats[1].ID},
+	}
+}
+
+// expectLeg has both seats of the trip free, held at the trip's base price
+func (f *itineraryTestFixture) expectLeg(tripData *trip.Trip) {
+	f.tripClient.EXPECT().
+		GetTripByID(gomock.Any(), gomock.Any(), tripData.ID).
+		Return(tripData, nil)
+	f.bookingRepo.EXPECT().
+		GetBookedSeatIDs(gomock.Any(), tripData.ID, model.FullTripSegment()).
+		Return(nil, nil)
+	f.tripClient.EXPECT().
+		ListSeatsByIDs(gomock.Any(), []uuid.UUID{f.seats[0].ID, f.seats[1].ID}).
+		Return(f.seats, nil)
+	f.seatLockService.EXPECT().
+		QuoteSeatFares(gomock.Any(), tripData.ID, gomock.Any(), []uuid.UUID{f.seats[0].ID, f.seats[1].ID}).
+		Return(map[uuid.UUID]float64{f.seats[0].ID: tripData.BasePrice, f.seats[1].ID: tripData.BasePrice}, nil)
+}
+
+func (f *itineraryTestFixture) newItinerary(userID uuid.UUID, statuses ...model.BookingStatus) *model.Itinerary {
+	itinerary := &model.Itinerary{
+		BaseModel:     model.BaseModel{ID: uuid.New()},
+		UserID:        userID,
+		Kind:          model.ItineraryKindRoundTrip,
+		TransactionID: uuid.New(),
+	}
+	for i, status := range statuses {
+		itinerary.Bookings = append(itinerary.Bookings, model.Booking{
+			BaseModel:     model.BaseModel{ID: uuid.New()},
+			UserID:        userID,
+			ItineraryID:   &itinerary.ID,
+			LegNumber:     i + 1,
+			Status:        status,
+			TransactionID: itinerary.TransactionID,
+		})
+	}
+	return itinerary
+}
+
+func TestCreateItinerary_RoundTripPaidAtOnceWithDiscount(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	f := newItineraryTestFixture(ctrl)
+	ctx := context.Background()
+	userID := uuid.New()
+
+	f.expectLeg(f.outbound)
+	f.expectLeg(f.inbound)
+
+	var saved *model.Itinerary
+	f.itineraryRepo.EXPECT().
+		CreateFromHolds(ctx, gomock.Any(), "checkout-session").
+		DoAndReturn(func(_ context.Context, itinerary *model.Itinerary, _ string) error {
+			saved = itinerary
+			return nil
+		})
+	f.paymentClient.EXPECT().
+		CreateTransaction(ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, req *payment.CreateTransactionRequest) (*payment.TransactionResponse, error) {
+			// One payment for both legs, made out to the outbound
+			assert.Equal(t, saved.TransactionID, req.ID)
+			assert.Equal(t, saved.Bookings[0].ID, req.BookingID)
+			assert.Equal(t, []uuid.UUID{saved.Bookings[1].ID}, req.LegBookingIDs)
+			assert.Equal(t, 1080000, req.Amount)
+			return &payment.TransactionResponse{ID: req.ID, Status: payment.TransactionStatusPending, CheckoutURL: "https://pay.example.com/it"}, nil
+		})
+
+	f.userClient.EXPECT().
+		GetUserByID(gomock.Any(), userID).
+		Return(&user.User{ID: userID, Email: "traveller@example.com", FullName: "Tran Thi B"}, nil)
+	f.notificationClient.EXPECT().
+		SendBookingPending(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, req *client.BookingPendingRequest) error {
+			assert.Equal(t, saved.ItineraryReference, req.BookingReference)
+			assert.Equal(t, "Sài Gòn", req.From)
+			assert.Equal(t, "Đà Lạt", req.To)
+			assert.Equal(t, 1080000, req.TotalAmount)
+			return nil
+		})
+	scheduled := make(chan *queue.DelayedItem, 1)
+	f.delayedQueue.EXPECT().
+		Schedule(gomock.Any(), constants.QueueNameBookingExpiry, gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ string, item *queue.DelayedItem, _ time.Time) error {
+			scheduled <- item
+			return nil
+		})
+
+	result, err := f.service.CreateItinerary(ctx, &model.CreateItineraryRequest{
+		Legs:      []model.ItineraryLegRequest{f.legRequest(f.outbound), f.legRequest(f.inbound)},
+		SessionID: "checkout-session",
+	}, userID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, model.ItineraryKindRoundTrip, result.Kind)
+	assert.Equal(t, model.BookingStatusPending, result.Status)
+	assert.Equal(t, 1080000, result.TotalAmount)
+	assert.Equal(t, 120000, result.DiscountAmount)
+	assert.Regexp(t, `^IT\d{6}`, result.ItineraryReference)
+	if assert.Len(t, result.Legs, 2) {
+		for i, leg := range result.Legs {
+			// Each leg keeps its seats on its own trip and shares the payment and expiry
+			assert.Equal(t, i+1, leg.LegNumber)
+			assert.Equal(t, &saved.ID, leg.ItineraryID)
+			assert.Equal(t, saved.TransactionID, leg.TransactionID)
+			assert.Equal(t, saved.ExpiresAt, leg.ExpiresAt)
+			assert.Equal(t, 540000, leg.TotalAmount)
+			assert.Equal(t, 60000, leg.DiscountAmount)
+			assert.Len(t, leg.Seats, 2)
+		}
+		assert.Equal(t, f.outbound.ID, result.Legs[0].TripID)
+		assert.Equal(t, f.inbound.ID, result.Legs[1].TripID)
+	}
+
+	// The itinerary expires as a whole, through its lead leg
+	select {
+	case item := <-scheduled:
+		assert.Equal(t, saved.Bookings[0].ID.String(), item.ID)
+	case <-time.After(time.Second):
+		t.Fatal("itinerary expiry was not scheduled")
+	}
+}
+
+func TestCreateItinerary_ConnectingTripsGetNoDiscount(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	f := newItineraryTestFixture(ctrl)
+	ctx := context.Background()
+
+	onward := f.newTrip("Đà Lạt", "Nha Trang", f.outbound.ArrivalTime.Add(2*time.Hour))
+	f.expectLeg(f.outbound)
+	f.expectLeg(onward)
+	f.itineraryRepo.EXPECT().CreateFromHolds(ctx, gomock.Any(), gomock.Any()).Return(nil)
+	f.paymentClient.EXPECT().
+		CreateTransaction(ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, req *payment.CreateTransactionRequest) (*payment.TransactionResponse, error) {
+			assert.Equal(t, 1200000, req.Amount)
+			return &payment.TransactionResponse{ID: req.ID, Status: payment.TransactionStatusPending}, nil
+		})
+
+	result, err := f.service.CreateItinerary(ctx, &model.CreateItineraryRequest{
+		Legs: []model.ItineraryLegRequest{f.legRequest(f.outbound), f.legRequest(onward)},
+	}, uuid.New())
+
+	assert.NoError(t, err)
+	assert.Equal(t, model.ItineraryKindMultiLeg, result.Kind)
+	assert.Equal(t, 1200000, result.TotalAmount)
+	assert.Zero(t, result.DiscountAmount)
+}
+
+func TestCreateItinerary_LegDepartsBeforePreviousArrives(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	f := newItineraryTestFixture(ctrl)
+	ctx := context.Background()
+
+	// Leaves Đà Lạt while the outbound is still on the road
+	early := f.newTrip("Đà Lạt", "Sài Gòn", f.outbound.DepartureTime.Add(3*time.Hour))
+	f.expectLeg(f.outbound)
+	f.expectLeg(early)
+
+	result, err := f.service.CreateItinerary(ctx, &model.CreateItineraryRequest{
+		Legs: []model.ItineraryLegRequest{f.legRequest(f.outbound), f.legRequest(early)},
+	}, uuid.New())
+
+	assert.Nil(t, result)
+	var apiErr *ginext.Error
+	assert.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusBadRequest, apiErr.Code)
+}
+
+func TestCreateItinerary_SeatTakenOnOneLeg(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	f := newItineraryTestFixture(ctrl)
+	ctx := context.Background()
+
+	f.expectLeg(f.outbound)
+	f.expectLeg(f.inbound)
+	f.itineraryRepo.EXPECT().
+		CreateFromHolds(ctx, gomock.Any(), gomock.Any()).
+		Return(model.ErrSeatsUnavailable)
+
+	result, err := f.service.CreateItinerary(ctx, &model.CreateItineraryRequest{
+		Legs: []model.ItineraryLegRequest{f.legRequest(f.outbound), f.legRequest(f.inbound)},
+	}, uuid.New())
+
+	assert.Nil(t, result)
+	var apiErr *ginext.Error
+	assert.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusConflict, apiErr.Code)
+}
+
+func TestCreateItinerary_PaymentFailureFailsEveryLeg(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	f := newItineraryTestFixture(ctrl)
+	ctx := context.Background()
+
+	f.expectLeg(f.outbound)
+	f.expectLeg(f.inbound)
+	f.itineraryRepo.EXPECT().CreateFromHolds(ctx, gomock.Any(), gomock.Any()).Return(nil)
+	f.paymentClient.EXPECT().
+		CreateTransaction(ctx, gomock.Any()).
+		Return(nil, errors.New("payment service unavailable"))
+	f.bookingRepo.EXPECT().
+		UpdateBookings(ctx, gomock.Any(), uuid.Nil).
+		DoAndReturn(func(_ context.Context, bookings []*model.Booking, _ uuid.UUID) error {
+			// Both legs fail in one go, releasing their seats
+			assert.Len(t, bookings, 2)
+			for _, booking := range bookings {
+				assert.Equal(t, model.BookingStatusFailed, booking.Status)
+				assert.Equal(t, payment.TransactionStatusFailed, booking.TransactionStatus)
+			}
+			return nil
+		})
+
+	result, err := f.service.CreateItinerary(ctx, &model.CreateItineraryRequest{
+		Legs: []model.ItineraryLegRequest{f.legRequest(f.outbound), f.legRequest(f.inbound)},
+	}, uuid.New())
+
+	assert.NoError(t, err)
+	assert.Equal(t, model.BookingStatusFailed, result.Status)
+	assert.Equal(t, payment.TransactionStatusFailed, result.Transaction.Status)
+}
+
+func TestCancelItinerary_UnpaidLegsCancelledTogether(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	f := newItineraryTestFixture(ctrl)
+	ctx := context.Background()
+	userID := uuid.New()
+	itinerary := f.newItinerary(userID, model.BookingStatusPending, model.BookingStatusPending)
+
+	f.itineraryRepo.EXPECT().GetByID(ctx, itinerary.ID).Return(itinerary, nil)
+	// Cancelling the lead leg cancels the other one and their shared payment
+	f.bookingService.EXPECT().
+		CancelBooking(ctx, itinerary.Bookings[0].ID, "Đổi kế hoạch").
+		Return(nil)
+
+	err := f.service.CancelItinerary(ctx, itinerary.ID, userID, "Đổi kế hoạch")
+
+	assert.NoError(t, err)
+}
+
+func TestCancelItinerary_SkipsLegsNoLongerCancellable(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	f := newItineraryTestFixture(ctrl)
+	ctx := context.Background()
+	userID := uuid.New()
+	itinerary := f.newItinerary(userID, model.BookingStatusConfirmed, model.BookingStatusConfirmed)
+	outbound, inbound := itinerary.Bookings[0], itinerary.Bookings[1]
+
+	f.itineraryRepo.EXPECT().GetByID(ctx, itinerary.ID).Return(itinerary, nil)
+	f.bookingService.EXPECT().
+		GetRefundQuote(ctx, outbound.ID).
+		Return(&model.RefundQuoteResponse{BookingID: outbound.ID, Cancellable: false}, nil)
+	f.bookingService.EXPECT().
+		GetRefundQuote(ctx, inbound.ID).
+		Return(&model.RefundQuoteResponse{BookingID: inbound.ID, Cancellable: true, RefundPercent: 70}, nil)
+	// Only the return is refunded; the outbound has already left
+	f.bookingService.EXPECT().
+		CancelBooking(ctx, inbound.ID, "Ở lại thêm").
+		Return(nil)
+
+	err := f.service.CancelItinerary(ctx, itinerary.ID, userID, "Ở lại thêm")
+
+	assert.NoError(t, err)
+}
+
+func TestGetItinerary_OtherUsersItinerary(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	f := newItineraryTestFixture(ctrl)
+	ctx := context.Background()
+	itinerary := f.newItinerary(uuid.New(), model.BookingStatusConfirmed, model.BookingStatusConfirmed)
+
+	f.itineraryRepo.EXPECT().GetByID(ctx, itinerary.ID).Return(itinerary, nil)
+
+	result, err := f.service.GetItinerary(ctx, itinerary.ID, uuid.New())
+
+	assert.Nil(t, result)
+	var apiErr *ginext.Error
+	assert.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusNotFound, apiErr.Code)
+}
+
+func TestRoundTripDiscountPercent(t *testing.T) {
+	discounts := []model.RoundTripDiscount{{WithinDays: 7, Percent: 10}, {WithinDays: 30, Percent: 5}}
+
+	tests := []struct {
+		name string
+		stay time.Duration
+		want int
+	}{
+		{name: "same week", stay: 3 * 24 * time.Hour, want: 10},
+		{name: "exactly a week", stay: 7 * 24 * time.Hour, want: 10},
+		{name: "within the month", stay: 8 * 24 * time.Hour, want: 5},
+		{name: "longer stay", stay: 45 * 24 * time.Hour, want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, roundTripDiscountPercent(discounts, tt.stay))
+		})
+	}
+
+	assert.Zero(t, roundTripDiscountPercent(nil, time.Hour))
+}
@@ -5,9 +5,9 @@
 package mocks
 
 import (
+	model "bus-booking/booking-service/internal/model"
 	context "context"
 	reflect "reflect"
-	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 	uuid "github.com/google/uuid"
@@ -66,10 +66,10 @@ func (mr *MockSeatLockServiceMockRecorder) GetLockedSeats(ctx, tripID interface{
 }
 
 // LockSeats mocks base method.
-func (m *MockSeatLockService) LockSeats(ctx context.Context, tripID uuid.UUID, seatIDs []uuid.UUID, sessionID string) (time.Time, error) {
+func (m *MockSeatLockService) LockSeats(ctx context.Context, tripID uuid.UUID, seatIDs []uuid.UUID, sessionID string) (*model.SeatHold, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "LockSeats", ctx, tripID, seatIDs, sessionID)
-	ret0, _ := ret[0].(time.Time)
+	ret0, _ := ret[0].(*model.SeatHold)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -80,6 +80,21 @@ func (mr *MockSeatLockServiceMockRecorder) LockSeats(ctx, tripID, seatIDs, sessi
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockSeats", reflect.TypeOf((*MockSeatLockService)(nil).LockSeats), ctx, tripID, seatIDs, sessionID)
 }
 
+// QuoteSeatFares mocks base method.
+func (m *MockSeatLockService) QuoteSeatFares(ctx context.Context, tripID uuid.UUID, sessionID string, seatIDs []uuid.UUID) (map[uuid.UUID]float64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QuoteSeatFares", ctx, tripID, sessionID, seatIDs)
+	ret0, _ := ret[0].(map[uuid.UUID]float64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// QuoteSeatFares indicates an expected call of QuoteSeatFares.
+func (mr *MockSeatLockServiceMockRecorder) QuoteSeatFares(ctx, tripID, sessionID, seatIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QuoteSeatFares", reflect.TypeOf((*MockSeatLockService)(nil).QuoteSeatFares), ctx, tripID, sessionID, seatIDs)
+}
+
 // UnlockSeats mocks base method.
 func (m *MockSeatLockService) UnlockSeats(ctx context.Context, sessionID string) error {
 	m.ctrl.T.Helper()
@@ -0,0 +1,262 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"bus-booking/booking-service/internal/constants"
+	"bus-booking/booking-service/internal/model"
+	"bus-booking/booking-service/internal/model/trip"
+	"bus-booking/booking-service/internal/repository"
+	"bus-booking/shared/ginext"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+type PromotionService interface {
+	CreatePromotion(ctx context.Context, req *model.CreatePromotionRequest) (*model.Promotion, error)
+	UpdatePromotion(ctx context.Context, id uuid.UUID, req *model.UpdatePromotionRequest) (*model.Promotion, error)
+	GetPromotion(ctx context.Context, id uuid.UUID) (*model.Promotion, error)
+	ListPromotions(ctx context.Context, req *model.ListPromotionsRequest) ([]*model.Promotion, int64, error)
+
+	// ApplyPromotion checks that the code can be used by the user on the trip and returns the
+	// promotion with the discount it gives on the subtotal. The use is only taken when the booking is saved.
+	ApplyPromotion(ctx context.Context, code string, userID uuid.UUID, tripData *trip.Trip, subtotal int) (*model.Promotion, int, error)
+	// RedeemForBooking takes the promotion use of a booking again, e.g. when its payment is retried
+	RedeemForBooking(ctx context.Context, booking *model.Booking) error
+	// ReleaseForBooking gives back the promotion use of a booking that will not be paid. Repeated calls are no-ops.
+	ReleaseForBooking(ctx context.Context, bookingID uuid.UUID) error
+}
+
+type promotionServiceImpl struct {
+	promotionRepo repository.PromotionRepository
+}
+
+func NewPromotionService(promotionRepo repository.PromotionRepository) PromotionService {
+	return &promotionServiceImpl{
+		promotionRepo: promotionRepo,
+	}
+}
+
+func (s *promotionServiceImpl) CreatePromotion(ctx context.Context, req *model.CreatePromotionRequest) (*model.Promotion, error) {
+	kind := req.Kind
+	if kind == "" {
+		kind = model.PromotionKindCode
+	}
+
+	code := normalizePromoCode(req.Code)
+	if code == "" {
+		if kind != model.PromotionKindVoucher {
+			return nil, ginext.NewBadRequestError("code is required")
+		}
+		code = generateVoucherCode()
+	}
+
+	if req.DiscountType == model.PromotionDiscountPercent && req.DiscountValue > 100 {
+		return nil, ginext.NewBadRequestError("percentage discount cannot exceed 100")
+	}
+	if req.StartsAt != nil && req.EndsAt != nil && !req.EndsAt.After(*req.StartsAt) {
+		return nil, ginext.NewBadRequestError("ends_at must be after starts_at")
+	}
+	if req.DepartureFrom != nil && req.DepartureTo != nil && !req.DepartureTo.After(*req.DepartureFrom) {
+		return nil, ginext.NewBadRequestError("departure_to must be after departure_from")
+	}
+
+	if _, err := s.promotionRepo.GetPromotionByCode(ctx, code); err == nil {
+		return nil, ginext.NewConflictError("promo code already exists")
+	}
+
+	promotion := &model.Promotion{
+		Code:           code,
+		Kind:           kind,
+		Description:    req.Description,
+		DiscountType:   req.DiscountType,
+		DiscountValue:  req.DiscountValue,
+		MaxDiscount:    req.MaxDiscount,
+		MinSpend:       req.MinSpend,
+		RouteID:        req.RouteID,
+		TripID:         req.TripID,
+		StartsAt:       req.StartsAt,
+		EndsAt:         req.EndsAt,
+		DepartureFrom:  req.DepartureFrom,
+		DepartureTo:    req.DepartureTo,
+		UsageLimit:     req.UsageLimit,
+		PerUserLimit:   req.PerUserLimit,
+		AssignedUserID: req.AssignedUserID,
+		IsActive:       true,
+	}
+	if kind == model.PromotionKindVoucher {
+		single := 1
+		promotion.UsageLimit = &single
+		promotion.PerUserLimit = &single
+	}
+
+	if err := s.promotionRepo.CreatePromotion(ctx, promotion); err != nil {
+		log.Error().Err(err).Str("code", code).Msg("Failed to create promotion")
+		return nil, ginext.NewInternalServerError("failed to create promotion")
+	}
+
+	return promotion, nil
+}
+
+func (s *promotionServiceImpl) UpdatePromotion(ctx context.Context, id uuid.UUID, req *model.UpdatePromotionRequest) (*model.Promotion, error) {
+	promotion, err := s.promotionRepo.GetPromotionByID(ctx, id)
+	if err != nil {
+		return nil, ginext.NewNotFoundError("promotion not found")
+	}
+
+	if req.Description != nil {
+		promotion.Description = *req.Description
+	}
+	if req.MaxDiscount != nil {
+		promotion.MaxDiscount = req.MaxDiscount
+	}
+	if req.MinSpend != nil {
+		promotion.MinSpend = *req.MinSpend
+	}
+	if req.StartsAt != nil {
+		promotion.StartsAt = req.StartsAt
+	}
+	if req.EndsAt != nil {
+		promotion.EndsAt = req.EndsAt
+	}
+	if req.IsActive != nil {
+		promotion.IsActive = *req.IsActive
+	}
+	// Vouchers stay single-use
+	if promotion.Kind != model.PromotionKindVoucher {
+		if req.UsageLimit != nil {
+			promotion.UsageLimit = req.UsageLimit
+		}
+		if req.PerUserLimit != nil {
+			promotion.PerUserLimit = req.PerUserLimit
+		}
+	}
+
+	if promotion.StartsAt != nil && promotion.EndsAt != nil && !promotion.EndsAt.After(*promotion.StartsAt) {
+		return nil, ginext.NewBadRequestError("ends_at must be after starts_at")
+	}
+
+	if err := s.promotionRepo.UpdatePromotion(ctx, promotion); err != nil {
+		log.Error().Err(err).Str("promotion_id", id.String()).Msg("Failed to update promotion")
+		return nil, ginext.NewInternalServerError("failed to update promotion")
+	}
+
+	return promotion, nil
+}
+
+func (s *promotionServiceImpl) GetPromotion(ctx context.Context, id uuid.UUID) (*model.Promotion, error) {
+	promotion, err := s.promotionRepo.GetPromotionByID(ctx, id)
+	if err != nil {
+		return nil, ginext.NewNotFoundError("promotion not found")
+	}
+	return promotion, nil
+}
+
+func (s *promotionServiceImpl) ListPromotions(ctx context.Context, req *model.ListPromotionsRequest) ([]*model.Promotion, int64, error) {
+	promotions, total, err := s.promotionRepo.ListPromotions(ctx, req)
+	if err != nil {
+		return nil, 0, ginext.NewInternalServerError("failed to list promotions")
+	}
+	return promotions, total, nil
+}
+
+func (s *promotionServiceImpl) ApplyPromotion(ctx context.Context, code string, userID uuid.UUID, tripData *trip.Trip, subtotal int) (*model.Promotion, int, error) {
+	promotion, err := s.promotionRepo.GetPromotionByCode(ctx, normalizePromoCode(code))
+	if err != nil {
+		return nil, 0, ginext.NewBadRequestError("invalid promo code")
+	}
+
+	now := time.Now().UTC()
+	switch {
+	case !promotion.IsActive:
+		return nil, 0, ginext.NewBadRequestError("promo code is no longer active")
+	case promotion.StartsAt != nil && now.Before(*promotion.StartsAt):
+		return nil, 0, ginext.NewBadRequestError("promo code is not valid yet")
+	case promotion.EndsAt != nil && now.After(*promotion.EndsAt):
+		return nil, 0, ginext.NewBadRequestError("promo code has expired")
+	case promotion.AssignedUserID != nil && *promotion.AssignedUserID != userID:
+		return nil, 0, ginext.NewBadRequestError("promo code is not valid for this account")
+	case promotion.TripID != nil && *promotion.TripID != tripData.ID,
+		promotion.RouteID != nil && *promotion.RouteID != tripData.RouteID:
+		return nil, 0, ginext.NewBadRequestError("promo code does not apply to this trip")
+	case promotion.DepartureFrom != nil && tripData.DepartureTime.Before(*promotion.DepartureFrom),
+		promotion.DepartureTo != nil && tripData.DepartureTime.After(*promotion.DepartureTo):
+		return nil, 0, ginext.NewBadRequestError("promo code does not apply to this departure date")
+	case subtotal < promotion.MinSpend:
+		return nil, 0, ginext.NewBadRequestError(fmt.Sprintf("promo code requires a minimum spend of %d VND", promotion.MinSpend))
+	case promotion.UsageLimit != nil && promotion.UsedCount >= *promotion.UsageLimit:
+		return nil, 0, ginext.NewBadRequestError("promo code has been fully redeemed")
+	}
+
+	if promotion.PerUserLimit != nil {
+		used, err := s.promotionRepo.CountActiveRedemptions(ctx, promotion.ID, userID)
+		if err != nil {
+			return nil, 0, ginext.NewInternalServerError("failed to check promo code usage")
+		}
+		if used >= int64(*promotion.PerUserLimit) {
+			return nil, 0, ginext.NewBadRequestError("you have already used this promo code")
+		}
+	}
+
+	discount := promotion.DiscountFor(subtotal)
+	if maxDiscount := subtotal - constants.PromotionMinPayableAmount; discount > maxDiscount {
+		discount = max(maxDiscount, 0)
+	}
+
+	return promotion, discount, nil
+}
+
+func (s *promotionServiceImpl) RedeemForBooking(ctx context.Context, booking *model.Booking) error {
+	if booking.PromotionID == nil {
+		return nil
+	}
+
+	err := s.promotionRepo.Redeem(ctx, &model.PromotionRedemption{
+		PromotionID:    *booking.PromotionID,
+		BookingID:      booking.ID,
+		UserID:         booking.UserID,
+		DiscountAmount: booking.DiscountAmount,
+	})
+	if errors.Is(err, model.ErrPromotionUnavailable) {
+		return ginext.NewBadRequestError("promo code of this booking is no longer available")
+	}
+	if err != nil {
+		log.Error().Err(err).Str("booking_id", booking.ID.String()).Msg("Failed to redeem promotion")
+		return ginext.NewInternalServerError("failed to redeem promo code")
+	}
+	return nil
+}
+
+func (s *promotionServiceImpl) ReleaseForBooking(ctx context.Context, bookingID uuid.UUID) error {
+	released, err := s.promotionRepo.Release(ctx, bookingID)
+	if err != nil {
+		return err
+	}
+	if released {
+		log.Info().Str("booking_id", bookingID.String()).Msg("Released promotion use of booking")
+	}
+	return nil
+}
+
+func normalizePromoCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+func generateVoucherCode() string {
+	randomPart := make([]byte, constants.VoucherCodeRandomLength)
+	for i := range randomPart {
+		n, err := rand.Int(rand.Reader, big.NewInt(int64(len(constants.BookingReferenceCharset))))
+		if err != nil {
+			n = big.NewInt(0)
+		}
+		randomPart[i] = constants.BookingReferenceCharset[n.Int64()]
+	}
+	return constants.VoucherCodePrefix + string(randomPart)
+}
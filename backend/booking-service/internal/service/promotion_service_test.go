@@ -0,0 +1,329 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"bus-booking/booking-service/internal/model"
+	"bus-booking/booking-service/internal/model/trip"
+	repo_mocks "bus-booking/booking-service/internal/repository/mocks"
+	"bus-booking/shared/ginext"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func intPtr(v int) *int {
+	return &v
+}
+
+func TestPromotion_DiscountFor(t *testing.T) {
+	tests := []struct {
+		name      string
+		promotion model.Promotion
+		amount    int
+		expected  int
+	}{
+		{"percent", model.Promotion{DiscountType: model.PromotionDiscountPercent, DiscountValue: 10}, 250000, 25000},
+		{"percent capped", model.Promotion{DiscountType: model.PromotionDiscountPercent, DiscountValue: 50, MaxDiscount: intPtr(40000)}, 250000, 40000},
+		{"fixed", model.Promotion{DiscountType: model.PromotionDiscountFixed, DiscountValue: 30000}, 250000, 30000},
+		{"fixed above fare", model.Promotion{DiscountType: model.PromotionDiscountFixed, DiscountValue: 300000}, 250000, 250000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.promotion.DiscountFor(tt.amount))
+		})
+	}
+}
+
+func TestPromotionService_ApplyPromotion(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := repo_mocks.NewMockPromotionRepository(ctrl)
+	service := NewPromotionService(mockRepo)
+
+	ctx := context.Background()
+	userID := uuid.New()
+	routeID := uuid.New()
+	tripData := &trip.Trip{ID: uuid.New(), RouteID: routeID, DepartureTime: time.Now().Add(48 * time.Hour)}
+	promotion := &model.Promotion{
+		BaseModel:     model.BaseModel{ID: uuid.New()},
+		Code:          "SUMMER",
+		DiscountType:  model.PromotionDiscountPercent,
+		DiscountValue: 20,
+		MinSpend:      100000,
+		RouteID:       &routeID,
+		PerUserLimit:  intPtr(2),
+		IsActive:      true,
+	}
+
+	mockRepo.EXPECT().GetPromotionByCode(ctx, "SUMMER").Return(promotion, nil)
+	mockRepo.EXPECT().CountActiveRedemptions(ctx, promotion.ID, userID).Return(int64(1), nil)
+
+	applied, discount, err := service.ApplyPromotion(ctx, " summer ", userID, tripData, 300000)
+
+	assert.NoError(t, err)
+	assert.Equal(t, promotion, applied)
+	assert.Equal(t, 60000, discount)
+}
+
+func TestPromotionService_ApplyPromotion_KeepsMinimumPayable(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := repo_mocks.NewMockPromotionRepository(ctrl)
+	service := NewPromotionService(mockRepo)
+
+	ctx := context.Background()
+	mockRepo.EXPECT().GetPromotionByCode(ctx, "FREE").Return(&model.Promotion{
+		Code:          "FREE",
+		DiscountType:  model.PromotionDiscountPercent,
+		DiscountValue: 100,
+		IsActive:      true,
+	}, nil)
+
+	_, discount, err := service.ApplyPromotion(ctx, "FREE", uuid.New(), &trip.Trip{}, 150000)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 149000, discount)
+}
+
+func TestPromotionService_ApplyPromotion_Rejected(t *testing.T) {
+	userID := uuid.New()
+	tripData := &trip.Trip{ID: uuid.New(), RouteID: uuid.New(), DepartureTime: time.Now().Add(48 * time.Hour)}
+	past := time.Now().Add(-time.Hour)
+	future := time.Now().Add(time.Hour)
+	otherID := uuid.New()
+
+	tests := []struct {
+		name      string
+		promotion model.Promotion
+		message   string
+	}{
+		{"inactive", model.Promotion{}, "no longer active"},
+		{"not started", model.Promotion{IsActive: true, StartsAt: &future}, "not valid yet"},
+		{"ended", model.Promotion{IsActive: true, EndsAt: &past}, "expired"},
+		{"other user's voucher", model.Promotion{IsActive: true, AssignedUserID: &otherID}, "not valid for this account"},
+		{"other trip", model.Promotion{IsActive: true, TripID: &otherID}, "does not apply to this trip"},
+		{"other route", model.Promotion{IsActive: true, RouteID: &otherID}, "does not apply to this trip"},
+		{"departure outside window", model.Promotion{IsActive: true, DepartureTo: &future}, "departure date"},
+		{"below min spend", model.Promotion{IsActive: true, MinSpend: 500000}, "minimum spend"},
+		{"used up", model.Promotion{IsActive: true, UsageLimit: intPtr(100), UsedCount: 100}, "fully redeemed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := repo_mocks.NewMockPromotionRepository(ctrl)
+			service := NewPromotionService(mockRepo)
+
+			promotion := tt.promotion
+			promotion.DiscountType = model.PromotionDiscountFixed
+			promotion.DiscountValue = 10000
+			mockRepo.EXPECT().GetPromotionByCode(gomock.Any(), "CODE").Return(&promotion, nil)
+
+			_, _, err := service.ApplyPromotion(context.Background(), "CODE", userID, tripData, 300000)
+
+			var apiErr *ginext.Error
+			assert.ErrorAs(t, err, &apiErr)
+			assert.Equal(t, http.StatusBadRequest, apiErr.Code)
+			assert.Contains(t, apiErr.Message, tt.message)
+		})
+	}
+}
+
+func TestPromotionService_ApplyPromotion_PerUserLimitReached(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := repo_mocks.NewMockPromotionRepository(ctrl)
+	service := NewPromotionService(mockRepo)
+
+	ctx := context.Background()
+	userID := uuid.New()
+	promotion := &model.Promotion{
+		BaseModel:     model.BaseModel{ID: uuid.New()},
+		DiscountType:  model.PromotionDiscountFixed,
+		DiscountValue: 10000,
+		PerUserLimit:  intPtr(1),
+		IsActive:      true,
+	}
+
+	mockRepo.EXPECT().GetPromotionByCode(ctx, "ONCE").Return(promotion, nil)
+	mockRepo.EXPECT().CountActiveRedemptions(ctx, promotion.ID, userID).Return(int64(1), nil)
+
+	_, _, err := service.ApplyPromotion(ctx, "ONCE", userID, &trip.Trip{}, 300000)
+
+	var apiErr *ginext.Error
+	assert.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusBadRequest, apiErr.Code)
+}
+
+func TestPromotionService_ApplyPromotion_UnknownCode(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := repo_mocks.NewMockPromotionRepository(ctrl)
+	service := NewPromotionService(mockRepo)
+
+	mockRepo.EXPECT().GetPromotionByCode(gomock.Any(), "NOPE").Return(nil, errors.New("promotion not found"))
+
+	_, _, err := service.ApplyPromotion(context.Background(), "nope", uuid.New(), &trip.Trip{}, 300000)
+
+	var apiErr *ginext.Error
+	assert.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusBadRequest, apiErr.Code)
+}
+
+func TestPromotionService_CreatePromotion_Voucher(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := repo_mocks.NewMockPromotionRepository(ctrl)
+	service := NewPromotionService(mockRepo)
+
+	ctx := context.Background()
+	userID := uuid.New()
+
+	mockRepo.EXPECT().GetPromotionByCode(ctx, gomock.Any()).Return(nil, errors.New("promotion not found"))
+	mockRepo.EXPECT().CreatePromotion(ctx, gomock.Any()).Return(nil)
+
+	promotion, err := service.CreatePromotion(ctx, &model.CreatePromotionRequest{
+		Kind:           model.PromotionKindVoucher,
+		DiscountType:   model.PromotionDiscountFixed,
+		DiscountValue:  50000,
+		UsageLimit:     intPtr(10),
+		AssignedUserID: &userID,
+	})
+
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(promotion.Code, "VC"))
+	assert.Len(t, promotion.Code, 10)
+	assert.Equal(t, 1, *promotion.UsageLimit)
+	assert.Equal(t, 1, *promotion.PerUserLimit)
+	assert.Equal(t, &userID, promotion.AssignedUserID)
+	assert.True(t, promotion.IsActive)
+}
+
+func TestPromotionService_CreatePromotion_Invalid(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service := NewPromotionService(repo_mocks.NewMockPromotionRepository(ctrl))
+	ends := time.Now()
+	starts := ends.Add(time.Hour)
+
+	requests := []*model.CreatePromotionRequest{
+		{DiscountType: model.PromotionDiscountFixed, DiscountValue: 10000},
+		{Code: "HALF", DiscountType: model.PromotionDiscountPercent, DiscountValue: 150},
+		{Code: "LATE", DiscountType: model.PromotionDiscountFixed, DiscountValue: 10000, StartsAt: &starts, EndsAt: &ends},
+	}
+
+	for _, req := range requests {
+		_, err := service.CreatePromotion(context.Background(), req)
+
+		var apiErr *ginext.Error
+		assert.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, http.StatusBadRequest, apiErr.Code)
+	}
+}
+
+func TestPromotionService_CreatePromotion_DuplicateCode(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := repo_mocks.NewMockPromotionRepository(ctrl)
+	service := NewPromotionService(mockRepo)
+
+	mockRepo.EXPECT().GetPromotionByCode(gomock.Any(), "SUMMER").Return(&model.Promotion{Code: "SUMMER"}, nil)
+
+	_, err := service.CreatePromotion(context.Background(), &model.CreatePromotionRequest{
+		Code:          "summer",
+		DiscountType:  model.PromotionDiscountFixed,
+		DiscountValue: 10000,
+	})
+
+	var apiErr *ginext.Error
+	assert.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusConflict, apiErr.Code)
+}
+
+func TestPromotionService_UpdatePromotion_VoucherStaysSingleUse(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := repo_mocks.NewMockPromotionRepository(ctrl)
+	service := NewPromotionService(mockRepo)
+
+	ctx := context.Background()
+	id := uuid.New()
+	inactive := false
+
+	mockRepo.EXPECT().GetPromotionByID(ctx, id).Return(&model.Promotion{
+		BaseModel:  model.BaseModel{ID: id},
+		Kind:       model.PromotionKindVoucher,
+		UsageLimit: intPtr(1),
+		IsActive:   true,
+	}, nil)
+	mockRepo.EXPECT().UpdatePromotion(ctx, gomock.Any()).Return(nil)
+
+	promotion, err := service.UpdatePromotion(ctx, id, &model.UpdatePromotionRequest{
+		UsageLimit: intPtr(5),
+		IsActive:   &inactive,
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, *promotion.UsageLimit)
+	assert.False(t, promotion.IsActive)
+}
+
+func TestPromotionService_RedeemForBooking_Unavailable(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := repo_mocks.NewMockPromotionRepository(ctrl)
+	service := NewPromotionService(mockRepo)
+
+	promotionID := uuid.New()
+	booking := &model.Booking{
+		BaseModel:      model.BaseModel{ID: uuid.New()},
+		UserID:         uuid.New(),
+		PromotionID:    &promotionID,
+		DiscountAmount: 20000,
+	}
+
+	mockRepo.EXPECT().Redeem(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, redemption *model.PromotionRedemption) error {
+		assert.Equal(t, promotionID, redemption.PromotionID)
+		assert.Equal(t, booking.ID, redemption.BookingID)
+		assert.Equal(t, 20000, redemption.DiscountAmount)
+		return model.ErrPromotionUnavailable
+	})
+
+	err := service.RedeemForBooking(context.Background(), booking)
+
+	var apiErr *ginext.Error
+	assert.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusBadRequest, apiErr.Code)
+}
+
+func TestPromotionService_ReleaseForBooking(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := repo_mocks.NewMockPromotionRepository(ctrl)
+	service := NewPromotionService(mockRepo)
+
+	bookingID := uuid.New()
+	mockRepo.EXPECT().Release(gomock.Any(), bookingID).Return(false, nil)
+
+	assert.NoError(t, service.ReleaseForBooking(context.Background(), bookingID))
+}
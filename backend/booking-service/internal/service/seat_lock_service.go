@@ -2,10 +2,15 @@ package service
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"bus-booking/shared/ginext"
 
+	"bus-booking/booking-service/internal/client"
+	"bus-booking/booking-service/internal/constants"
+	"bus-booking/booking-service/internal/model"
+	"bus-booking/booking-service/internal/model/trip"
 	"bus-booking/booking-service/internal/repository"
 
 	"github.com/google/uuid"
@@ -13,52 +18,90 @@ import (
 )
 
 type SeatLockService interface {
-	LockSeats(ctx context.Context, tripID uuid.UUID, seatIDs []uuid.UUID, sessionID string) (time.Time, error)
+	LockSeats(ctx context.Context, tripID uuid.UUID, seatIDs []uuid.UUID, sessionID string) (*model.SeatHold, error)
 	UnlockSeats(ctx context.Context, sessionID string) error
 	GetLockedSeats(ctx context.Context, tripID uuid.UUID) ([]uuid.UUID, error)
 	ValidateSeatAvailability(ctx context.Context, tripID uuid.UUID, seatIDs []uuid.UUID) error
 	CleanExpiredLocks(ctx context.Context) error
+
+	// QuoteSeatFares returns the trip fare each seat is charged at: the fare locked in when the
+	// session held the seat, or the current fare for seats it holds without one
+	QuoteSeatFares(ctx context.Context, tripID uuid.UUID, sessionID string, seatIDs []uuid.UUID) (map[uuid.UUID]float64, error)
 }
 
 type SeatLockServiceImpl struct {
 	lockRepo     repository.SeatLockRepository
+	tripClient   client.TripClient
 	lockDuration time.Duration
 }
 
-func NewSeatLockService(lockRepo repository.SeatLockRepository) SeatLockService {
+func NewSeatLockService(lockRepo repository.SeatLockRepository, tripClient client.TripClient) SeatLockService {
 	return &SeatLockServiceImpl{
 		lockRepo:     lockRepo,
-		lockDuration: 5 * time.Minute, // 5 minutes lock duration
+		tripClient:   tripClient,
+		lockDuration: constants.SeatLockDuration,
 	}
 }
 
-func (s *SeatLockServiceImpl) LockSeats(ctx context.Context, tripID uuid.UUID, seatIDs []uuid.UUID, sessionID string) (time.Time, error) {
-	// Check if any seats are already locked
-	for _, seatID := range seatIDs {
-		locked, err := s.lockRepo.IsLocked(ctx, tripID, seatID)
-		if err != nil {
-			log.Error().Err(err).Msg("Failed to check seat lock status")
-			return time.Time{}, err
-		}
-		if locked {
-			return time.Time{}, ginext.NewConflictError("one or more seats are already locked")
-		}
-	}
-
+// LockSeats holds all requested seats for the session or none of them, locking in the current
+// trip fare. When trip service cannot quote a fare the seats are still held, without one.
+func (s *SeatLockServiceImpl) LockSeats(ctx context.Context, tripID uuid.UUID, seatIDs []uuid.UUID, sessionID string) (*model.SeatHold, error) {
 	log.Info().
 		Str("trip_id", tripID.String()).
 		Str("session_id", sessionID).
 		Int("seat_count", len(seatIDs)).
 		Msg("Locking seats")
 
-	err := s.lockRepo.LockSeats(ctx, tripID, seatIDs, sessionID, s.lockDuration)
+	hold := &model.SeatHold{}
+	quote, err := s.tripClient.GetTripFare(ctx, tripID)
 	if err != nil {
-		return time.Time{}, err
+		log.Warn().Err(err).Str("trip_id", tripID.String()).Msg("Failed to quote trip fare, holding seats without a fare")
+	} else {
+		hold.Fare = &quote.Fare
+	}
+
+	err = s.lockRepo.LockSeats(ctx, tripID, seatIDs, sessionID, s.lockDuration, hold.Fare)
+	if errors.Is(err, model.ErrSeatsUnavailable) {
+		return nil, ginext.NewConflictError("one or more seats are already locked")
+	}
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to lock seats")
+		return nil, err
 	}
 
 	// Return expiration time for frontend countdown
-	expiresAt := time.Now().UTC().Add(s.lockDuration)
-	return expiresAt, nil
+	hold.ExpiresAt = time.Now().UTC().Add(s.lockDuration)
+	return hold, nil
+}
+
+func (s *SeatLockServiceImpl) QuoteSeatFares(ctx context.Context, tripID uuid.UUID, sessionID string, seatIDs []uuid.UUID) (map[uuid.UUID]float64, error) {
+	var locked map[uuid.UUID]float64
+	if sessionID != "" {
+		var err error
+		if locked, err = s.lockRepo.GetSessionFares(ctx, tripID, sessionID); err != nil {
+			log.Error().Err(err).Str("session_id", sessionID).Msg("Failed to get held seat fares")
+			return nil, ginext.NewInternalServerError("failed to get seat fares")
+		}
+	}
+
+	fares := make(map[uuid.UUID]float64, len(seatIDs))
+	var current *trip.TripFare
+	for _, seatID := range seatIDs {
+		if fare, ok := locked[seatID]; ok {
+			fares[seatID] = fare
+			continue
+		}
+
+		if current == nil {
+			var err error
+			if current, err = s.tripClient.GetTripFare(ctx, tripID); err != nil {
+				log.Error().Err(err).Str("trip_id", tripID.String()).Msg("Failed to quote trip fare")
+				return nil, ginext.NewInternalServerError("failed to get trip fare")
+			}
+		}
+		fares[seatID] = current.Fare
+	}
+	return fares, nil
 }
 
 func (s *SeatLockServiceImpl) UnlockSeats(ctx context.Context, sessionID string) error {
@@ -2,9 +2,13 @@ package service
 
 import (
 	"context"
+	"net/http"
 	"testing"
 	"time"
 
+	client_mocks "bus-booking/booking-service/internal/client/mocks"
+	"bus-booking/booking-service/internal/model"
+	"bus-booking/booking-service/internal/model/trip"
 	"bus-booking/booking-service/internal/repository/mocks"
 	"bus-booking/shared/ginext"
 
@@ -18,7 +22,7 @@ func TestNewSeatLockService(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockRepo := mocks.NewMockSeatLockRepository(ctrl)
-	service := NewSeatLockService(mockRepo)
+	service := NewSeatLockService(mockRepo, client_mocks.NewMockTripClient(ctrl))
 
 	assert.NotNil(t, service)
 	impl, ok := service.(*SeatLockServiceImpl)
@@ -31,7 +35,8 @@ func TestLockSeats_Success(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockRepo := mocks.NewMockSeatLockRepository(ctrl)
-	service := NewSeatLockService(mockRepo)
+	mockTripClient := client_mocks.NewMockTripClient(ctrl)
+	service := NewSeatLockService(mockRepo, mockTripClient)
 
 	ctx := context.Background()
 	tripID := uuid.New()
@@ -39,29 +44,51 @@ func TestLockSeats_Success(t *testing.T) {
 	seatID2 := uuid.New()
 	seatIDs := []uuid.UUID{seatID1, seatID2}
 	sessionID := "test-session-123"
+	fare := 280000.0
 
-	// Expect IsLocked calls for each seat
+	mockTripClient.EXPECT().
+		GetTripFare(ctx, tripID).
+		Return(&trip.TripFare{TripID: tripID, BasePrice: 250000, Fare: fare}, nil)
+	// All seats are held in a single atomic repository call, at the quoted fare
 	mockRepo.EXPECT().
-		IsLocked(ctx, tripID, seatID1).
-		Return(false, nil).
+		LockSeats(ctx, tripID, seatIDs, sessionID, 5*time.Minute, &fare).
+		Return(nil).
 		Times(1)
 
-	mockRepo.EXPECT().
-		IsLocked(ctx, tripID, seatID2).
-		Return(false, nil).
-		Times(1)
+	hold, err := service.LockSeats(ctx, tripID, seatIDs, sessionID)
+
+	assert.NoError(t, err)
+	assert.True(t, hold.ExpiresAt.After(time.Now()))
+	if assert.NotNil(t, hold.Fare) {
+		assert.Equal(t, fare, *hold.Fare)
+	}
+}
+
+func TestLockSeats_HoldsWithoutFareWhenQuoteFails(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockSeatLockRepository(ctrl)
+	mockTripClient := client_mocks.NewMockTripClient(ctrl)
+	service := NewSeatLockService(mockRepo, mockTripClient)
+
+	ctx := context.Background()
+	tripID := uuid.New()
+	seatIDs := []uuid.UUID{uuid.New()}
+	sessionID := "test-session-123"
 
-	// Expect LockSeats call
+	mockTripClient.EXPECT().
+		GetTripFare(ctx, tripID).
+		Return(nil, assert.AnError)
 	mockRepo.EXPECT().
-		LockSeats(ctx, tripID, seatIDs, sessionID, 5*time.Minute).
-		Return(nil).
-		Times(1)
+		LockSeats(ctx, tripID, seatIDs, sessionID, 5*time.Minute, nil).
+		Return(nil)
 
-	expiresAt, err := service.LockSeats(ctx, tripID, seatIDs, sessionID)
+	hold, err := service.LockSeats(ctx, tripID, seatIDs, sessionID)
 
 	assert.NoError(t, err)
-	assert.False(t, expiresAt.IsZero())
-	assert.True(t, expiresAt.After(time.Now()))
+	assert.False(t, hold.ExpiresAt.IsZero())
+	assert.Nil(t, hold.Fare)
 }
 
 func TestLockSeats_SeatAlreadyLocked(t *testing.T) {
@@ -69,7 +96,8 @@ func TestLockSeats_SeatAlreadyLocked(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockRepo := mocks.NewMockSeatLockRepository(ctrl)
-	service := NewSeatLockService(mockRepo)
+	mockTripClient := client_mocks.NewMockTripClient(ctrl)
+	service := NewSeatLockService(mockRepo, mockTripClient)
 
 	ctx := context.Background()
 	tripID := uuid.New()
@@ -77,25 +105,32 @@ func TestLockSeats_SeatAlreadyLocked(t *testing.T) {
 	seatIDs := []uuid.UUID{seatID}
 	sessionID := "test-session-123"
 
-	// First seat is already locked
+	mockTripClient.EXPECT().
+		GetTripFare(ctx, tripID).
+		Return(&trip.TripFare{TripID: tripID, Fare: 250000}, nil)
+	// Another session holds the seat
 	mockRepo.EXPECT().
-		IsLocked(ctx, tripID, seatID).
-		Return(true, nil).
+		LockSeats(ctx, tripID, seatIDs, sessionID, 5*time.Minute, gomock.Any()).
+		Return(model.ErrSeatsUnavailable).
 		Times(1)
 
-	expiresAt, err := service.LockSeats(ctx, tripID, seatIDs, sessionID)
+	hold, err := service.LockSeats(ctx, tripID, seatIDs, sessionID)
 
 	assert.Error(t, err)
-	assert.True(t, expiresAt.IsZero())
+	assert.Nil(t, hold)
 	assert.Contains(t, err.Error(), "already locked")
+	var apiErr *ginext.Error
+	assert.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusConflict, apiErr.Code)
 }
 
-func TestLockSeats_IsLockedError(t *testing.T) {
+func TestLockSeats_LockSeatsError(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	mockRepo := mocks.NewMockSeatLockRepository(ctrl)
-	service := NewSeatLockService(mockRepo)
+	mockTripClient := client_mocks.NewMockTripClient(ctrl)
+	service := NewSeatLockService(mockRepo, mockTripClient)
 
 	ctx := context.Background()
 	tripID := uuid.New()
@@ -105,48 +140,92 @@ func TestLockSeats_IsLockedError(t *testing.T) {
 
 	expectedErr := assert.AnError
 
+	mockTripClient.EXPECT().
+		GetTripFare(ctx, tripID).
+		Return(&trip.TripFare{TripID: tripID, Fare: 250000}, nil)
 	mockRepo.EXPECT().
-		IsLocked(ctx, tripID, seatID).
-		Return(false, expectedErr).
+		LockSeats(ctx, tripID, seatIDs, sessionID, 5*time.Minute, gomock.Any()).
+		Return(expectedErr).
 		Times(1)
 
-	expiresAt, err := service.LockSeats(ctx, tripID, seatIDs, sessionID)
+	hold, err := service.LockSeats(ctx, tripID, seatIDs, sessionID)
 
 	assert.Error(t, err)
-	assert.True(t, expiresAt.IsZero())
+	assert.Nil(t, hold)
 	assert.Equal(t, expectedErr, err)
 }
 
-func TestLockSeats_LockSeatsError(t *testing.T) {
+func TestQuoteSeatFares_LockedFaresFirst(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	mockRepo := mocks.NewMockSeatLockRepository(ctrl)
-	service := NewSeatLockService(mockRepo)
+	mockTripClient := client_mocks.NewMockTripClient(ctrl)
+	service := NewSeatLockService(mockRepo, mockTripClient)
 
 	ctx := context.Background()
 	tripID := uuid.New()
-	seatID := uuid.New()
-	seatIDs := []uuid.UUID{seatID}
+	heldID, unheldID := uuid.New(), uuid.New()
 	sessionID := "test-session-123"
 
-	expectedErr := assert.AnError
-
+	// The held seat keeps the fare seen when it was held; the other is charged today's fare
 	mockRepo.EXPECT().
-		IsLocked(ctx, tripID, seatID).
-		Return(false, nil).
+		GetSessionFares(ctx, tripID, sessionID).
+		Return(map[uuid.UUID]float64{heldID: 250000}, nil)
+	mockTripClient.EXPECT().
+		GetTripFare(ctx, tripID).
+		Return(&trip.TripFare{TripID: tripID, Fare: 310000}, nil).
 		Times(1)
 
+	fares, err := service.QuoteSeatFares(ctx, tripID, sessionID, []uuid.UUID{heldID, unheldID})
+
+	assert.NoError(t, err)
+	assert.Equal(t, map[uuid.UUID]float64{heldID: 250000, unheldID: 310000}, fares)
+}
+
+func TestQuoteSeatFares_AllHeldSkipsQuote(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockSeatLockRepository(ctrl)
+	service := NewSeatLockService(mockRepo, client_mocks.NewMockTripClient(ctrl))
+
+	ctx := context.Background()
+	tripID := uuid.New()
+	seatID := uuid.New()
+
 	mockRepo.EXPECT().
-		LockSeats(ctx, tripID, seatIDs, sessionID, 5*time.Minute).
-		Return(expectedErr).
-		Times(1)
+		GetSessionFares(ctx, tripID, "test-session-123").
+		Return(map[uuid.UUID]float64{seatID: 250000}, nil)
 
-	expiresAt, err := service.LockSeats(ctx, tripID, seatIDs, sessionID)
+	fares, err := service.QuoteSeatFares(ctx, tripID, "test-session-123", []uuid.UUID{seatID})
 
-	assert.Error(t, err)
-	assert.True(t, expiresAt.IsZero())
-	assert.Equal(t, expectedErr, err)
+	assert.NoError(t, err)
+	assert.Equal(t, 250000.0, fares[seatID])
+}
+
+func TestQuoteSeatFares_QuoteError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockSeatLockRepository(ctrl)
+	mockTripClient := client_mocks.NewMockTripClient(ctrl)
+	service := NewSeatLockService(mockRepo, mockTripClient)
+
+	ctx := context.Background()
+	tripID := uuid.New()
+
+	// Without a session there are no held fares to look up
+	mockTripClient.EXPECT().
+		GetTripFare(ctx, tripID).
+		Return(nil, assert.AnError)
+
+	fares, err := service.QuoteSeatFares(ctx, tripID, "", []uuid.UUID{uuid.New()})
+
+	assert.Nil(t, fares)
+	var apiErr *ginext.Error
+	assert.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusInternalServerError, apiErr.Code)
 }
 
 func TestUnlockSeats_Success(t *testing.T) {
@@ -154,7 +233,7 @@ func TestUnlockSeats_Success(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockRepo := mocks.NewMockSeatLockRepository(ctrl)
-	service := NewSeatLockService(mockRepo)
+	service := NewSeatLockService(mockRepo, client_mocks.NewMockTripClient(ctrl))
 
 	ctx := context.Background()
 	sessionID := "test-session-123"
@@ -174,7 +253,7 @@ func TestUnlockSeats_Error(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockRepo := mocks.NewMockSeatLockRepository(ctrl)
-	service := NewSeatLockService(mockRepo)
+	service := NewSeatLockService(mockRepo, client_mocks.NewMockTripClient(ctrl))
 
 	ctx := context.Background()
 	sessionID := "test-session-123"
@@ -197,7 +276,7 @@ func TestGetLockedSeats_Success(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockRepo := mocks.NewMockSeatLockRepository(ctrl)
-	service := NewSeatLockService(mockRepo)
+	service := NewSeatLockService(mockRepo, client_mocks.NewMockTripClient(ctrl))
 
 	ctx := context.Background()
 	tripID := uuid.New()
@@ -222,7 +301,7 @@ func TestGetLockedSeats_Error(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockRepo := mocks.NewMockSeatLockRepository(ctrl)
-	service := NewSeatLockService(mockRepo)
+	service := NewSeatLockService(mockRepo, client_mocks.NewMockTripClient(ctrl))
 
 	ctx := context.Background()
 	tripID := uuid.New()
@@ -246,7 +325,7 @@ func TestValidateSeatAvailability_AllAvailable(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockRepo := mocks.NewMockSeatLockRepository(ctrl)
-	service := NewSeatLockService(mockRepo)
+	service := NewSeatLockService(mockRepo, client_mocks.NewMockTripClient(ctrl))
 
 	ctx := context.Background()
 	tripID := uuid.New()
@@ -274,7 +353,7 @@ func TestValidateSeatAvailability_SeatLocked(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockRepo := mocks.NewMockSeatLockRepository(ctrl)
-	service := NewSeatLockService(mockRepo)
+	service := NewSeatLockService(mockRepo, client_mocks.NewMockTripClient(ctrl))
 
 	ctx := context.Background()
 	tripID := uuid.New()
@@ -298,7 +377,7 @@ func TestValidateSeatAvailability_RepositoryError(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockRepo := mocks.NewMockSeatLockRepository(ctrl)
-	service := NewSeatLockService(mockRepo)
+	service := NewSeatLockService(mockRepo, client_mocks.NewMockTripClient(ctrl))
 
 	ctx := context.Background()
 	tripID := uuid.New()
@@ -323,7 +402,7 @@ func TestCleanExpiredLocks_Success(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockRepo := mocks.NewMockSeatLockRepository(ctrl)
-	service := NewSeatLockService(mockRepo)
+	service := NewSeatLockService(mockRepo, client_mocks.NewMockTripClient(ctrl))
 
 	ctx := context.Background()
 
@@ -342,7 +421,7 @@ func TestCleanExpiredLocks_Error(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockRepo := mocks.NewMockSeatLockRepository(ctrl)
-	service := NewSeatLockService(mockRepo)
+	service := NewSeatLockService(mockRepo, client_mocks.NewMockTripClient(ctrl))
 
 	ctx := context.Background()
 
@@ -0,0 +1,465 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"bus-booking/booking-service/internal/client"
+	"bus-booking/booking-service/internal/constants"
+	"bus-booking/booking-service/internal/model"
+	"bus-booking/booking-service/internal/model/trip"
+	"bus-booking/booking-service/internal/repository"
+	"bus-booking/shared/ginext"
+	"bus-booking/shared/queue"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+type WaitlistService interface {
+	// JoinWaitlist puts the user in line for seats of a trip that cannot seat them now
+	JoinWaitlist(ctx context.Context, req *model.JoinWaitlistRequest, userID uuid.UUID) (*model.WaitlistEntryResponse, error)
+	// LeaveWaitlist takes the user out of line, handing seats offered to them on to the next in line
+	LeaveWaitlist(ctx context.Context, id uuid.UUID, userID uuid.UUID) error
+	ListUserEntries(ctx context.Context, userID uuid.UUID) ([]*model.WaitlistEntryResponse, error)
+	// ClaimOffer books the seats held by the user's offer and returns the booking with its payment link
+	ClaimOffer(ctx context.Context, id uuid.UUID, userID uuid.UUID, req *model.ClaimWaitlistOfferRequest) (*model.BookingResponse, error)
+
+	// OfferSeats ends the lapsed offers of the trip and offers its free seats to the waiting entries
+	// in the order they joined. It schedules itself again for when the next open offer lapses.
+	OfferSeats(ctx context.Context, tripID uuid.UUID) error
+}
+
+type waitlistServiceImpl struct {
+	waitlistRepo       repository.WaitlistRepository
+	bookingRepo        repository.BookingRepository
+	lockRepo           repository.SeatLockRepository
+	bookingService     BookingService
+	tripClient         client.TripClient
+	userClient         client.UserClient
+	notificationClient client.NotificationClient
+	delayedQueue       queue.DelayedQueueManager
+	offerDuration      time.Duration
+}
+
+func NewWaitlistService(
+	waitlistRepo repository.WaitlistRepository,
+	bookingRepo repository.BookingRepository,
+	lockRepo repository.SeatLockRepository,
+	bookingService BookingService,
+	tripClient client.TripClient,
+	userClient client.UserClient,
+	notificationClient client.NotificationClient,
+	delayedQueue queue.DelayedQueueManager,
+) WaitlistService {
+	return &waitlistServiceImpl{
+		waitlistRepo:       waitlistRepo,
+		bookingRepo:        bookingRepo,
+		lockRepo:           lockRepo,
+		bookingService:     bookingService,
+		tripClient:         tripClient,
+		userClient:         userClient,
+		notificationClient: notificationClient,
+		delayedQueue:       delayedQueue,
+		offerDuration:      constants.WaitlistOfferDuration,
+	}
+}
+
+func (s *waitlistServiceImpl) JoinWaitlist(ctx context.Context, req *model.JoinWaitlistRequest, userID uuid.UUID) (*model.WaitlistEntryResponse, error) {
+	tripData, err := s.tripClient.GetTripByID(ctx, trip.GetTripByIDRequest{
+		PreloadBus:  true,
+		PreloadSeat: true,
+	}, req.TripID)
+	if err != nil {
+		return nil, ginext.NewInternalServerError("failed to fetch trip data")
+	}
+	if !isOpenForBooking(tripData, time.Now().UTC()) {
+		return nil, ginext.NewBadRequestError("trip is not open for booking")
+	}
+
+	waiting, err := s.waitlistRepo.HasActiveEntry(ctx, req.TripID, userID)
+	if err != nil {
+		return nil, ginext.NewInternalServerError(err.Error())
+	}
+	if waiting {
+		return nil, ginext.NewConflictError("already on the waitlist for this trip")
+	}
+
+	entry := &model.WaitlistEntry{
+		TripID:    req.TripID,
+		UserID:    userID,
+		SeatCount: req.SeatCount,
+		SeatType:  req.SeatType,
+		Status:    model.WaitlistStatusWaiting,
+	}
+
+	free, err := s.freeSeats(ctx, tripData)
+	if err != nil {
+		return nil, ginext.NewInternalServerError(err.Error())
+	}
+	if pickSeats(free, entry) != nil {
+		return nil, ginext.NewBadRequestError("enough seats are available, book them directly")
+	}
+
+	if err := s.waitlistRepo.CreateEntry(ctx, entry); err != nil {
+		return nil, ginext.NewInternalServerError(err.Error())
+	}
+
+	log.Info().
+		Str("trip_id", entry.TripID.String()).
+		Str("user_id", userID.String()).
+		Int("seat_count", entry.SeatCount).
+		Msg("User joined trip waitlist")
+
+	return s.toEntryResponse(ctx, entry)
+}
+
+func (s *waitlistServiceImpl) LeaveWaitlist(ctx context.Context, id uuid.UUID, userID uuid.UUID) error {
+	entry, err := s.getUserEntry(ctx, id, userID)
+	if err != nil {
+		return err
+	}
+
+	closed, err := s.waitlistRepo.Close(ctx, entry, model.WaitlistStatusCancelled)
+	if err != nil {
+		return ginext.NewInternalServerError(err.Error())
+	}
+	if !closed {
+		return ginext.NewBadRequestError("waitlist entry is no longer active")
+	}
+
+	// The seats held for the user go to the next in line
+	if entry.Status == model.WaitlistStatusOffered {
+		if err := s.scheduleOffers(ctx, entry.TripID, time.Now()); err != nil {
+			log.Error().Err(err).Str("trip_id", entry.TripID.String()).Msg("Failed to schedule waitlist offers for declined seats")
+		}
+	}
+	return nil
+}
+
+func (s *waitlistServiceImpl) ListUserEntries(ctx context.Context, userID uuid.UUID) ([]*model.WaitlistEntryResponse, error) {
+	entries, err := s.waitlistRepo.ListUserEntries(ctx, userID)
+	if err != nil {
+		return nil, ginext.NewInternalServerError(err.Error())
+	}
+
+	responses := make([]*model.WaitlistEntryResponse, len(entries))
+	for i, entry := range entries {
+		if responses[i], err = s.toEntryResponse(ctx, entry); err != nil {
+			return nil, err
+		}
+	}
+	return responses, nil
+}
+
+func (s *waitlistServiceImpl) ClaimOffer(ctx context.Context, id uuid.UUID, userID uuid.UUID, req *model.ClaimWaitlistOfferRequest) (*model.BookingResponse, error) {
+	entry, err := s.getUserEntry(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+	if entry.Status != model.WaitlistStatusOffered {
+		return nil, ginext.NewBadRequestError("no seat offer to claim")
+	}
+	if entry.OfferLapsed(time.Now().UTC()) {
+		return nil, ginext.NewBadRequestError("seat offer has expired")
+	}
+
+	seatIDs, err := s.waitlistRepo.GetOfferedSeatIDs(ctx, entry)
+	if err != nil {
+		return nil, ginext.NewInternalServerError(err.Error())
+	}
+	if len(seatIDs) != entry.SeatCount {
+		return nil, ginext.NewConflictError("seat offer is no longer held")
+	}
+
+	// The offer session holds the seats, so the booking takes them over like any checkout hold
+	booking, err := s.bookingService.CreateBooking(ctx, &model.CreateBookingRequest{
+		TripID:        entry.TripID,
+		SeatIDs:       seatIDs,
+		Notes:         req.Notes,
+		PaymentMethod: req.PaymentMethod,
+		SessionID:     entry.OfferSessionID,
+		Passengers:    req.Passengers,
+		PromoCode:     req.PromoCode,
+		BuyerInfo:     req.BuyerInfo,
+		UseWallet:     req.UseWallet,
+	}, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	claimed, err := s.waitlistRepo.MarkClaimed(ctx, entry.ID, booking.ID)
+	if err != nil || !claimed {
+		log.Warn().Err(err).
+			Str("waitlist_entry_id", entry.ID.String()).
+			Str("booking_id", booking.ID.String()).
+			Msg("Booked waitlist offer but could not mark it claimed")
+	}
+	return booking, nil
+}
+
+func (s *waitlistServiceImpl) OfferSeats(ctx context.Context, tripID uuid.UUID) error {
+	entries, err := s.waitlistRepo.ListActiveEntries(ctx, tripID)
+	if err != nil {
+		return err
+	}
+	if len(entries) == 0 {
+		return nil
+	}
+
+	// 1. Offers nobody claimed in time give their seats back, they roll over to the next in line
+	now := time.Now().UTC()
+	var (
+		waiting   []*model.WaitlistEntry
+		nextLapse *time.Time
+	)
+	for _, entry := range entries {
+		switch {
+		case entry.OfferLapsed(now):
+			if _, err := s.waitlistRepo.Close(ctx, entry, model.WaitlistStatusExpired); err != nil {
+				return err
+			}
+			log.Info().Str("waitlist_entry_id", entry.ID.String()).Msg("Waitlist offer lapsed")
+		case entry.Status == model.WaitlistStatusWaiting:
+			waiting = append(waiting, entry)
+		default:
+			nextLapse = earliest(nextLapse, entry.OfferExpiresAt)
+		}
+	}
+	if len(waiting) == 0 {
+		return s.scheduleNextLapse(ctx, tripID, nextLapse)
+	}
+
+	tripData, err := s.tripClient.GetTripByID(ctx, trip.GetTripByIDRequest{
+		PreLoadRoute: true,
+		PreloadBus:   true,
+		PreloadSeat:  true,
+	}, tripID)
+	if err != nil {
+		return fmt.Errorf("failed to get trip data: %w", err)
+	}
+
+	// 2. A trip that left or was cancelled has nothing left to offer
+	if !isOpenForBooking(tripData, now) {
+		for _, entry := range waiting {
+			if _, err := s.waitlistRepo.Close(ctx, entry, model.WaitlistStatusExpired); err != nil {
+				return err
+			}
+		}
+		return s.scheduleNextLapse(ctx, tripID, nextLapse)
+	}
+
+	// 3. Hold free seats for the waiting entries in order; an entry asking for more seats
+	// than are free keeps its place and lets the entries behind it take what there is
+	free, err := s.freeSeats(ctx, tripData)
+	if err != nil {
+		return err
+	}
+	for _, entry := range waiting {
+		seats := pickSeats(free, entry)
+		if seats == nil {
+			continue
+		}
+
+		offeredAt := now
+		expiresAt := now.Add(s.offerDuration)
+		entry.OfferSessionID = uuid.NewString()
+		entry.OfferedAt = &offeredAt
+		entry.OfferExpiresAt = &expiresAt
+
+		err := s.waitlistRepo.Offer(ctx, entry, seatIDsOf(seats))
+		if errors.Is(err, model.ErrSeatsUnavailable) || errors.Is(err, model.ErrWaitlistEntryChanged) {
+			// Someone took a seat or the user left meanwhile; the next run sees the change
+			log.Info().Err(err).Str("waitlist_entry_id", entry.ID.String()).Msg("Skipped waitlist offer")
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		free = withoutSeats(free, seats)
+		nextLapse = earliest(nextLapse, entry.OfferExpiresAt)
+		log.Info().
+			Str("waitlist_entry_id", entry.ID.String()).
+			Str("trip_id", tripID.String()).
+			Int("seat_count", len(seats)).
+			Time("expires_at", expiresAt).
+			Msg("Offered released seats to waitlisted user")
+
+		s.sendOfferEmail(ctx, entry, tripData, seats)
+	}
+
+	return s.scheduleNextLapse(ctx, tripID, nextLapse)
+}
+
+func (s *waitlistServiceImpl) getUserEntry(ctx context.Context, id uuid.UUID, userID uuid.UUID) (*model.WaitlistEntry, error) {
+	entry, err := s.waitlistRepo.GetEntryByID(ctx, id)
+	if err != nil || entry.UserID != userID {
+		return nil, ginext.NewNotFoundError("waitlist entry not found")
+	}
+	return entry, nil
+}
+
+func (s *waitlistServiceImpl) toEntryResponse(ctx context.Context, entry *model.WaitlistEntry) (*model.WaitlistEntryResponse, error) {
+	resp := &model.WaitlistEntryResponse{WaitlistEntry: entry}
+
+	switch {
+	case entry.Status == model.WaitlistStatusWaiting:
+		ahead, err := s.waitlistRepo.CountWaitingAhead(ctx, entry)
+		if err != nil {
+			return nil, ginext.NewInternalServerError(err.Error())
+		}
+		resp.Position = int(ahead) + 1
+	case entry.Status == model.WaitlistStatusOffered && !entry.OfferLapsed(time.Now().UTC()):
+		seatIDs, err := s.waitlistRepo.GetOfferedSeatIDs(ctx, entry)
+		if err != nil {
+			return nil, ginext.NewInternalServerError(err.Error())
+		}
+		resp.OfferedSeatIDs = seatIDs
+	}
+	return resp, nil
+}
+
+// freeSeats returns the seats of the trip that are neither booked nor held, in the bus layout order
+func (s *waitlistServiceImpl) freeSeats(ctx context.Context, tripData *trip.Trip) ([]trip.Seat, error) {
+	if tripData.Bus == nil {
+		return nil, nil
+	}
+
+	bookedSeatIDs, err := s.bookingRepo.GetBookedSeatIDs(ctx, tripData.ID, model.FullTripSegment())
+	if err != nil {
+		return nil, err
+	}
+	lockedSeatIDs, err := s.lockRepo.GetLockedSeats(ctx, tripData.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get locked seats: %w", err)
+	}
+
+	taken := make(map[uuid.UUID]bool, len(bookedSeatIDs)+len(lockedSeatIDs))
+	for _, id := range bookedSeatIDs {
+		taken[id] = true
+	}
+	for _, id := range lockedSeatIDs {
+		taken[id] = true
+	}
+
+	free := make([]trip.Seat, 0, len(tripData.Bus.Seats))
+	for _, seat := range tripData.Bus.Seats {
+		if !taken[seat.ID] {
+			free = append(free, seat)
+		}
+	}
+	return free, nil
+}
+
+// scheduleNextLapse has OfferSeats run again once the earliest open offer lapses
+func (s *waitlistServiceImpl) scheduleNextLapse(ctx context.Context, tripID uuid.UUID, lapse *time.Time) error {
+	if lapse == nil {
+		return nil
+	}
+	// The queue works in whole seconds, so an offer is sure to have lapsed a second later
+	return s.scheduleOffers(ctx, tripID, lapse.Add(time.Second))
+}
+
+func (s *waitlistServiceImpl) scheduleOffers(ctx context.Context, tripID uuid.UUID, at time.Time) error {
+	return scheduleWaitlistOffers(ctx, s.delayedQueue, tripID, at)
+}
+
+func (s *waitlistServiceImpl) sendOfferEmail(ctx context.Context, entry *model.WaitlistEntry, tripData *trip.Trip, seats []trip.Seat) {
+	userData, err := s.userClient.GetUserByID(ctx, entry.UserID)
+	if err != nil {
+		log.Error().Err(err).
+			Str("user_id", entry.UserID.String()).
+			Msg("Failed to fetch user for waitlist offer email")
+		return
+	}
+	if tripData.Route == nil {
+		log.Error().
+			Str("trip_id", tripData.ID.String()).
+			Msg("Route is nil, cannot send waitlist offer email")
+		return
+	}
+
+	seatNumbers := make([]string, len(seats))
+	for i, seat := range seats {
+		seatNumbers[i] = seat.SeatNumber
+	}
+
+	if err := s.notificationClient.SendWaitlistOffer(ctx, &client.WaitlistOfferRequest{
+		Email:         userData.Email,
+		Name:          userData.FullName,
+		From:          tripData.Route.Origin,
+		To:            tripData.Route.Destination,
+		DepartureTime: tripData.DepartureTime.Format(constants.DateTimeFormatDisplay),
+		SeatNumbers:   strings.Join(seatNumbers, ", "),
+		ClaimLink:     fmt.Sprintf("%s/waitlist/%s", constants.DefaultFrontendURL, entry.ID),
+		ExpiresAt:     entry.OfferExpiresAt.Format(constants.DateTimeFormatDisplay),
+	}); err != nil {
+		log.Error().Err(err).
+			Str("waitlist_entry_id", entry.ID.String()).
+			Msg("Failed to send waitlist offer email")
+	}
+}
+
+// scheduleWaitlistOffers queues an OfferSeats run for the trip. Runs are keyed by trip, so
+// scheduling again only ever moves the pending run earlier: a seat released now must not wait
+// for an offer lapsing later, and the earlier run schedules the next lapse itself.
+func scheduleWaitlistOffers(ctx context.Context, delayedQueue queue.DelayedQueueManager, tripID uuid.UUID, at time.Time) error {
+	item := &queue.DelayedItem{
+		ID:      tripID.String(),
+		Payload: tripID,
+	}
+	return delayedQueue.ScheduleEarliest(ctx, constants.QueueNameWaitlistOffer, item, at)
+}
+
+// isOpenForBooking reports whether seats of the trip can still be booked
+func isOpenForBooking(tripData *trip.Trip, now time.Time) bool {
+	return tripData.IsBookable() && now.Before(tripData.DepartureTime)
+}
+
+// pickSeats returns the first seats matching the entry, nil when too few of them are free
+func pickSeats(free []trip.Seat, entry *model.WaitlistEntry) []trip.Seat {
+	var picked []trip.Seat
+	for _, seat := range free {
+		if entry.SeatType != "" && seat.SeatType != entry.SeatType {
+			continue
+		}
+		picked = append(picked, seat)
+		if len(picked) == entry.SeatCount {
+			return picked
+		}
+	}
+	return nil
+}
+
+func withoutSeats(seats []trip.Seat, remove []trip.Seat) []trip.Seat {
+	removed := make(map[uuid.UUID]bool, len(remove))
+	for _, seat := range remove {
+		removed[seat.ID] = true
+	}
+	kept := make([]trip.Seat, 0, len(seats))
+	for _, seat := range seats {
+		if !removed[seat.ID] {
+			kept = append(kept, seat)
+		}
+	}
+	return kept
+}
+
+func seatIDsOf(seats []trip.Seat) []uuid.UUID {
+	ids := make([]uuid.UUID, len(seats))
+	for i, seat := range seats {
+		ids[i] = seat.ID
+	}
+	return ids
+}
+
+func earliest(current *time.Time, candidate *time.Time) *time.Time {
+	if candidate == nil || (current != nil && !candidate.Before(*current)) {
+		return current
+	}
+	return candidate
+}
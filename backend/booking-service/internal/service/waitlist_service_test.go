@@ -0,0 +1,352 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"bus-booking/booking-service/internal/client"
+	"bus-booking/booking-service/internal/client/mocks"
+	"bus-booking/booking-service/internal/constants"
+	"bus-booking/booking-service/internal/model"
+	"bus-booking/booking-service/internal/model/trip"
+	"bus-booking/booking-service/internal/model/user"
+	repo_mocks "bus-booking/booking-service/internal/repository/mocks"
+	service_mocks "bus-booking/booking-service/internal/service/mocks"
+	"bus-booking/shared/ginext"
+	"bus-booking/shared/queue"
+	queue_mocks "bus-booking/shared/queue/mocks"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+type waitlistTestFixture struct {
+	waitlistRepo       *repo_mocks.MockWaitlistRepository
+	bookingRepo        *repo_mocks.MockBookingRepository
+	lockRepo           *repo_mocks.MockSeatLockRepository
+	bookingService     *service_mocks.MockBookingService
+	tripClient         *mocks.MockTripClient
+	userClient         *mocks.MockUserClient
+	notificationClient *mocks.MockNotificationClient
+	delayedQueue       *queue_mocks.MockDelayedQueueManager
+	service            WaitlistService
+
+	trip  *trip.Trip
+	seats []trip.Seat
+}
+
+// newWaitlistTestFixture builds a bookable trip whose bus has three standard seats
+func newWaitlistTestFixture(ctrl *gomock.Controller) *waitlistTestFixture {
+	f := &waitlistTestFixture{
+		waitlistRepo:       repo_mocks.NewMockWaitlistRepository(ctrl),
+		bookingRepo:        repo_mocks.NewMockBookingRepository(ctrl),
+		lockRepo:           repo_mocks.NewMockSeatLockRepository(ctrl),
+		bookingService:     service_mocks.NewMockBookingService(ctrl),
+		tripClient:         mocks.NewMockTripClient(ctrl),
+		userClient:         mocks.NewMockUserClient(ctrl),
+		notificationClient: mocks.NewMockNotificationClient(ctrl),
+		delayedQueue:       queue_mocks.NewMockDelayedQueueManager(ctrl),
+	}
+	f.service = NewWaitlistService(f.waitlistRepo, f.bookingRepo, f.lockRepo, f.bookingService, f.tripClient, f.userClient, f.notificationClient, f.delayedQueue)
+
+	busID := uuid.New()
+	for _, number := range []string{"A1", "A2", "A3"} {
+		f.seats = append(f.seats, trip.Seat{ID: uuid.New(), BusID: busID, SeatNumber: number, SeatType: "standard", PriceMultiplier: 1.0, Floor: 1})
+	}
+	f.trip = &trip.Trip{
+		ID:            uuid.New(),
+		BusID:         busID,
+		DepartureTime: time.Now().UTC().Add(48 * time.Hour),
+		BasePrice:     200000,
+		Status:        trip.TripStatusScheduled,
+		IsActive:      true,
+		Route:         &trip.Route{Origin: "Hà Nội", Destination: "Hải Phòng"},
+		Bus:           &trip.Bus{ID: busID, Seats: f.seats},
+	}
+	return f
+}
+
+func (f *waitlistTestFixture) newEntry(seatCount int, joinedAgo time.Duration) *model.WaitlistEntry {
+	return &model.WaitlistEntry{
+		BaseModel: model.BaseModel{ID: uuid.New(), CreatedAt: time.Now().UTC().Add(-joinedAgo)},
+		TripID:    f.trip.ID,
+		UserID:    uuid.New(),
+		SeatCount: seatCount,
+		Status:    model.WaitlistStatusWaiting,
+	}
+}
+
+func (f *waitlistTestFixture) expectTrip() {
+	f.tripClient.EXPECT().
+		GetTripByID(gomock.Any(), gomock.Any(), f.trip.ID).
+		Return(f.trip, nil)
+}
+
+func (f *waitlistTestFixture) expectTakenSeats(booked, locked []uuid.UUID) {
+	f.bookingRepo.EXPECT().
+		GetBookedSeatIDs(gomock.Any(), f.trip.ID, model.FullTripSegment()).
+		Return(booked, nil)
+	f.lockRepo.EXPECT().
+		GetLockedSeats(gomock.Any(), f.trip.ID).
+		Return(locked, nil)
+}
+
+func (f *waitlistTestFixture) expectOfferEmail(t *testing.T, entry *model.WaitlistEntry, seatNumbers string) {
+	f.userClient.EXPECT().
+		GetUserByID(gomock.Any(), entry.UserID).
+		Return(&user.User{ID: entry.UserID, Email: "waiting@example.com", FullName: "Nguyen Van A"}, nil)
+	f.notificationClient.EXPECT().
+		SendWaitlistOffer(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, req *client.WaitlistOfferRequest) error {
+			assert.Equal(t, "waiting@example.com", req.Email)
+			assert.Equal(t, seatNumbers, req.SeatNumbers)
+			assert.Contains(t, req.ClaimLink, entry.ID.String())
+			return nil
+		})
+}
+
+func TestOfferSeats_OffersInJoinOrder(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	f := newWaitlistTestFixture(ctrl)
+	ctx := context.Background()
+
+	// Two seats are free; the first in line wants three and keeps its place for later
+	first := f.newEntry(3, 2*time.Hour)
+	second := f.newEntry(2, time.Hour)
+
+	f.waitlistRepo.EXPECT().
+		ListActiveEntries(ctx, f.trip.ID).
+		Return([]*model.WaitlistEntry{first, second}, nil)
+	f.expectTrip()
+	f.expectTakenSeats([]uuid.UUID{f.seats[0].ID}, nil)
+	f.waitlistRepo.EXPECT().
+		Offer(ctx, second, []uuid.UUID{f.seats[1].ID, f.seats[2].ID}).
+		DoAndReturn(func(ctx context.Context, entry *model.WaitlistEntry, seatIDs []uuid.UUID) error {
+			assert.NotEmpty(t, entry.OfferSessionID)
+			assert.WithinDuration(t, time.Now().Add(constants.WaitlistOfferDuration), *entry.OfferExpiresAt, time.Minute)
+			return nil
+		})
+	f.expectOfferEmail(t, second, "A2, A3")
+	// The run comes back once the offer lapses
+	f.delayedQueue.EXPECT().
+		ScheduleEarliest(ctx, constants.QueueNameWaitlistOffer, gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, queueName string, item *queue.DelayedItem, at time.Time) error {
+			assert.Equal(t, f.trip.ID.String(), item.ID)
+			assert.Equal(t, second.OfferExpiresAt.Add(time.Second), at)
+			return nil
+		})
+
+	err := f.service.OfferSeats(ctx, f.trip.ID)
+
+	assert.NoError(t, err)
+	assert.Empty(t, first.OfferSessionID)
+}
+
+func TestOfferSeats_LapsedOfferRollsOver(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	f := newWaitlistTestFixture(ctrl)
+	ctx := context.Background()
+
+	lapsedAt := time.Now().UTC().Add(-time.Minute)
+	lapsed := f.newEntry(1, 2*time.Hour)
+	lapsed.Status = model.WaitlistStatusOffered
+	lapsed.OfferSessionID = uuid.NewString()
+	lapsed.OfferExpiresAt = &lapsedAt
+	next := f.newEntry(1, time.Hour)
+
+	f.waitlistRepo.EXPECT().
+		ListActiveEntries(ctx, f.trip.ID).
+		Return([]*model.WaitlistEntry{lapsed, next}, nil)
+	f.waitlistRepo.EXPECT().
+		Close(ctx, lapsed, model.WaitlistStatusExpired).
+		Return(true, nil)
+	f.expectTrip()
+	// Only the seat released by the lapsed offer is free
+	f.expectTakenSeats([]uuid.UUID{f.seats[0].ID, f.seats[1].ID}, nil)
+	f.waitlistRepo.EXPECT().
+		Offer(ctx, next, []uuid.UUID{f.seats[2].ID}).
+		Return(nil)
+	f.expectOfferEmail(t, next, "A3")
+	f.delayedQueue.EXPECT().
+		ScheduleEarliest(ctx, constants.QueueNameWaitlistOffer, gomock.Any(), gomock.Any()).
+		Return(nil)
+
+	err := f.service.OfferSeats(ctx, f.trip.ID)
+
+	assert.NoError(t, err)
+}
+
+func TestOfferSeats_SeatTakenMeanwhile(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	f := newWaitlistTestFixture(ctrl)
+	ctx := context.Background()
+	entry := f.newEntry(1, time.Hour)
+
+	f.waitlistRepo.EXPECT().
+		ListActiveEntries(ctx, f.trip.ID).
+		Return([]*model.WaitlistEntry{entry}, nil)
+	f.expectTrip()
+	f.expectTakenSeats([]uuid.UUID{f.seats[0].ID, f.seats[1].ID}, nil)
+	// A checkout locked the seat between the read and the hold; the entry keeps waiting
+	f.waitlistRepo.EXPECT().
+		Offer(ctx, entry, []uuid.UUID{f.seats[2].ID}).
+		Return(model.ErrSeatsUnavailable)
+
+	err := f.service.OfferSeats(ctx, f.trip.ID)
+
+	assert.NoError(t, err)
+}
+
+func TestOfferSeats_TripDepartedExpiresEntries(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	f := newWaitlistTestFixture(ctrl)
+	ctx := context.Background()
+	f.trip.Status = trip.TripStatusCancelled
+	entry := f.newEntry(1, time.Hour)
+
+	f.waitlistRepo.EXPECT().
+		ListActiveEntries(ctx, f.trip.ID).
+		Return([]*model.WaitlistEntry{entry}, nil)
+	f.expectTrip()
+	f.waitlistRepo.EXPECT().
+		Close(ctx, entry, model.WaitlistStatusExpired).
+		Return(true, nil)
+
+	err := f.service.OfferSeats(ctx, f.trip.ID)
+
+	assert.NoError(t, err)
+}
+
+func TestJoinWaitlist_SeatsAvailable(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	f := newWaitlistTestFixture(ctrl)
+	ctx := context.Background()
+	userID := uuid.New()
+
+	f.expectTrip()
+	f.waitlistRepo.EXPECT().
+		HasActiveEntry(ctx, f.trip.ID, userID).
+		Return(false, nil)
+	f.expectTakenSeats([]uuid.UUID{f.seats[0].ID}, []uuid.UUID{f.seats[1].ID})
+
+	entry, err := f.service.JoinWaitlist(ctx, &model.JoinWaitlistRequest{TripID: f.trip.ID, SeatCount: 1}, userID)
+
+	assert.Nil(t, entry)
+	var apiErr *ginext.Error
+	assert.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusBadRequest, apiErr.Code)
+}
+
+func TestJoinWaitlist_SoldOut(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	f := newWaitlistTestFixture(ctrl)
+	ctx := context.Background()
+	userID := uuid.New()
+
+	f.expectTrip()
+	f.waitlistRepo.EXPECT().
+		HasActiveEntry(ctx, f.trip.ID, userID).
+		Return(false, nil)
+	f.expectTakenSeats([]uuid.UUID{f.seats[0].ID, f.seats[1].ID}, []uuid.UUID{f.seats[2].ID})
+	f.waitlistRepo.EXPECT().
+		CreateEntry(ctx, gomock.Any()).
+		Return(nil)
+	f.waitlistRepo.EXPECT().
+		CountWaitingAhead(ctx, gomock.Any()).
+		Return(int64(4), nil)
+
+	entry, err := f.service.JoinWaitlist(ctx, &model.JoinWaitlistRequest{TripID: f.trip.ID, SeatCount: 2}, userID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, model.WaitlistStatusWaiting, entry.Status)
+	assert.Equal(t, 5, entry.Position)
+}
+
+func TestClaimOffer_BooksHeldSeats(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	f := newWaitlistTestFixture(ctrl)
+	ctx := context.Background()
+
+	expiresAt := time.Now().UTC().Add(10 * time.Minute)
+	entry := f.newEntry(2, time.Hour)
+	entry.Status = model.WaitlistStatusOffered
+	entry.OfferSessionID = uuid.NewString()
+	entry.OfferExpiresAt = &expiresAt
+	seatIDs := []uuid.UUID{f.seats[1].ID, f.seats[2].ID}
+	bookingID := uuid.New()
+
+	f.waitlistRepo.EXPECT().GetEntryByID(ctx, entry.ID).Return(entry, nil)
+	f.waitlistRepo.EXPECT().GetOfferedSeatIDs(ctx, entry).Return(seatIDs, nil)
+	f.bookingService.EXPECT().
+		CreateBooking(ctx, gomock.Any(), entry.UserID).
+		DoAndReturn(func(ctx context.Context, req *model.CreateBookingRequest, userID uuid.UUID) (*model.BookingResponse, error) {
+			// The booking takes over the seats held by the offer session
+			assert.Equal(t, entry.OfferSessionID, req.SessionID)
+			assert.Equal(t, seatIDs, req.SeatIDs)
+			assert.True(t, req.UseWallet)
+			return &model.BookingResponse{ID: bookingID}, nil
+		})
+	f.waitlistRepo.EXPECT().MarkClaimed(ctx, entry.ID, bookingID).Return(true, nil)
+
+	booking, err := f.service.ClaimOffer(ctx, entry.ID, entry.UserID, &model.ClaimWaitlistOfferRequest{UseWallet: true})
+
+	assert.NoError(t, err)
+	assert.Equal(t, bookingID, booking.ID)
+}
+
+func TestClaimOffer_OfferLapsed(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	f := newWaitlistTestFixture(ctrl)
+	ctx := context.Background()
+
+	expiresAt := time.Now().UTC().Add(-time.Minute)
+	entry := f.newEntry(1, time.Hour)
+	entry.Status = model.WaitlistStatusOffered
+	entry.OfferSessionID = uuid.NewString()
+	entry.OfferExpiresAt = &expiresAt
+
+	f.waitlistRepo.EXPECT().GetEntryByID(ctx, entry.ID).Return(entry, nil)
+
+	booking, err := f.service.ClaimOffer(ctx, entry.ID, entry.UserID, &model.ClaimWaitlistOfferRequest{})
+
+	assert.Nil(t, booking)
+	var apiErr *ginext.Error
+	assert.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusBadRequest, apiErr.Code)
+}
+
+func TestLeaveWaitlist_OtherUsersEntry(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	f := newWaitlistTestFixture(ctrl)
+	ctx := context.Background()
+	entry := f.newEntry(1, time.Hour)
+
+	f.waitlistRepo.EXPECT().GetEntryByID(ctx, entry.ID).Return(entry, nil)
+
+	err := f.service.LeaveWaitlist(ctx, entry.ID, uuid.New())
+
+	var apiErr *ginext.Error
+	assert.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusNotFound, apiErr.Code)
+}
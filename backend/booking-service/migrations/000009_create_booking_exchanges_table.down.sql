@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS booking_exchange_seats;
+DROP TABLE IF EXISTS booking_exchanges;
@@ -0,0 +1,63 @@
+-- Create booking_exchanges table for moving bookings to another trip on the same route
+CREATE TABLE IF NOT EXISTS booking_exchanges (
+    -- Standard fields
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMPTZ,
+
+    -- Business fields
+    booking_id UUID NOT NULL,
+    old_trip_id UUID NOT NULL,
+    new_trip_id UUID NOT NULL,
+    old_amount DECIMAL(10,2) NOT NULL,
+    new_amount DECIMAL(10,2) NOT NULL,
+    surcharge DECIMAL(10,2) NOT NULL DEFAULT 0,
+    amount_due DECIMAL(10,2) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'PENDING',
+    transaction_id UUID,
+    refund_id UUID,
+    expires_at TIMESTAMPTZ,
+    completed_at TIMESTAMPTZ,
+
+    -- Foreign keys
+    CONSTRAINT fk_booking_exchanges_booking FOREIGN KEY (booking_id)
+        REFERENCES bookings(id) ON UPDATE CASCADE ON DELETE CASCADE
+);
+
+CREATE INDEX idx_booking_exchanges_booking_id ON booking_exchanges(booking_id);
+CREATE INDEX idx_booking_exchanges_new_trip_id ON booking_exchanges(new_trip_id);
+CREATE INDEX idx_booking_exchanges_status ON booking_exchanges(status);
+CREATE INDEX idx_booking_exchanges_transaction_id ON booking_exchanges(transaction_id);
+CREATE INDEX idx_booking_exchanges_deleted_at ON booking_exchanges(deleted_at);
+
+CREATE TRIGGER update_booking_exchanges_updated_at BEFORE UPDATE ON booking_exchanges
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+-- Create booking_exchange_seats table holding the seats requested on the new trip
+CREATE TABLE IF NOT EXISTS booking_exchange_seats (
+    -- Standard fields
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMPTZ,
+
+    -- Business fields
+    exchange_id UUID NOT NULL,
+    seat_id UUID NOT NULL,
+    seat_number VARCHAR(10) NOT NULL,
+    seat_type VARCHAR(50) NOT NULL,
+    floor INT NOT NULL DEFAULT 1,
+    price DECIMAL(10,2) NOT NULL,
+    price_multiplier DECIMAL(3,2) NOT NULL DEFAULT 1.0,
+
+    -- Foreign keys
+    CONSTRAINT fk_booking_exchange_seats_exchange FOREIGN KEY (exchange_id)
+        REFERENCES booking_exchanges(id) ON UPDATE CASCADE ON DELETE CASCADE
+);
+
+CREATE INDEX idx_booking_exchange_seats_exchange_id ON booking_exchange_seats(exchange_id);
+CREATE INDEX idx_booking_exchange_seats_deleted_at ON booking_exchange_seats(deleted_at);
+
+CREATE TRIGGER update_booking_exchange_seats_updated_at BEFORE UPDATE ON booking_exchange_seats
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
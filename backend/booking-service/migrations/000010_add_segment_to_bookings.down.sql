@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS idx_bookings_trip_segment;
+ALTER TABLE bookings DROP CONSTRAINT IF EXISTS chk_bookings_segment_order;
+ALTER TABLE bookings DROP COLUMN IF EXISTS dropoff_location;
+ALTER TABLE bookings DROP COLUMN IF EXISTS pickup_location;
+ALTER TABLE bookings DROP COLUMN IF EXISTS dropoff_stop_order;
+ALTER TABLE bookings DROP COLUMN IF EXISTS pickup_stop_order;
+ALTER TABLE bookings DROP COLUMN IF EXISTS dropoff_stop_id;
+ALTER TABLE bookings DROP COLUMN IF EXISTS pickup_stop_id;
@@ -0,0 +1,13 @@
+-- Segment bookings hold seats only between a pickup and a dropoff stop.
+-- NULL stop orders mean the booking covers the whole trip.
+ALTER TABLE bookings ADD COLUMN IF NOT EXISTS pickup_stop_id UUID;
+ALTER TABLE bookings ADD COLUMN IF NOT EXISTS dropoff_stop_id UUID;
+ALTER TABLE bookings ADD COLUMN IF NOT EXISTS pickup_stop_order INT;
+ALTER TABLE bookings ADD COLUMN IF NOT EXISTS dropoff_stop_order INT;
+ALTER TABLE bookings ADD COLUMN IF NOT EXISTS pickup_location VARCHAR(255);
+ALTER TABLE bookings ADD COLUMN IF NOT EXISTS dropoff_location VARCHAR(255);
+
+ALTER TABLE bookings ADD CONSTRAINT chk_bookings_segment_order
+    CHECK (pickup_stop_order IS NULL OR dropoff_stop_order IS NULL OR pickup_stop_order < dropoff_stop_order);
+
+CREATE INDEX IF NOT EXISTS idx_bookings_trip_segment ON bookings(trip_id, pickup_stop_order, dropoff_stop_order);
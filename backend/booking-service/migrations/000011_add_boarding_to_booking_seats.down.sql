@@ -0,0 +1,2 @@
+ALTER TABLE booking_seats DROP COLUMN IF EXISTS boarded_at;
+ALTER TABLE booking_seats DROP COLUMN IF EXISTS is_boarded;
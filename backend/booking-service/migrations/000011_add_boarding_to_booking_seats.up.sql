@@ -0,0 +1,12 @@
+-- Passengers board seat by seat so a group can be checked in partially
+ALTER TABLE booking_seats ADD COLUMN IF NOT EXISTS is_boarded BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE booking_seats ADD COLUMN IF NOT EXISTS boarded_at TIMESTAMPTZ;
+
+-- Bookings checked in before per-seat boarding existed boarded every seat
+UPDATE booking_seats bs
+SET is_boarded = TRUE, boarded_at = b.updated_at
+FROM bookings b
+WHERE b.id = bs.booking_id AND b.is_boarded = TRUE;
+
+COMMENT ON COLUMN booking_seats.is_boarded IS 'Passenger on this seat has checked in';
+COMMENT ON COLUMN bookings.is_boarded IS 'Every seat of the booking has checked in';
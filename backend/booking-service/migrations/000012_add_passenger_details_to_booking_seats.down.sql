@@ -0,0 +1 @@
+ALTER TABLE booking_seats DROP COLUMN IF EXISTS passenger_age_category;
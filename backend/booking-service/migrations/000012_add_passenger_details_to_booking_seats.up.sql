@@ -0,0 +1,6 @@
+-- Each seat of a group booking carries its own named passenger
+ALTER TABLE booking_seats ADD COLUMN IF NOT EXISTS passenger_age_category VARCHAR(20);
+
+COMMENT ON COLUMN booking_seats.passenger_name IS 'Full name of the passenger travelling on this seat';
+COMMENT ON COLUMN booking_seats.passenger_id IS 'ID document number (CCCD/passport) of the passenger';
+COMMENT ON COLUMN booking_seats.passenger_age_category IS 'ADULT, CHILD or SENIOR';
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS uq_seat_locks_trip_seat;
+CREATE INDEX IF NOT EXISTS idx_seat_locks_trip_seat ON seat_locks(trip_id, seat_id) WHERE deleted_at IS NULL;
@@ -0,0 +1,15 @@
+-- Seat holds are taken with INSERT ... ON CONFLICT DO NOTHING against this index, so two
+-- sessions can never hold the same seat of a trip at the same time
+
+-- Released and expired holds are deleted outright from now on
+DELETE FROM seat_locks WHERE deleted_at IS NOT NULL OR expires_at <= NOW();
+
+-- Keep only the earliest hold when concurrent lockers already double-held a seat
+DELETE FROM seat_locks a
+USING seat_locks b
+WHERE a.trip_id = b.trip_id
+  AND a.seat_id = b.seat_id
+  AND (a.locked_at, a.id) > (b.locked_at, b.id);
+
+DROP INDEX IF EXISTS idx_seat_locks_trip_seat;
+CREATE UNIQUE INDEX IF NOT EXISTS uq_seat_locks_trip_seat ON seat_locks(trip_id, seat_id) WHERE deleted_at IS NULL;
@@ -0,0 +1,8 @@
+-- Events from other services' outboxes already applied here; redeliveries are ignored
+CREATE TABLE IF NOT EXISTS processed_events (
+    event_id UUID PRIMARY KEY,
+    consumer VARCHAR(100) NOT NULL,
+    processed_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_processed_events_processed_at ON processed_events(processed_at);
@@ -0,0 +1,2 @@
+-- How the booking is paid; existing bookings were all paid through PayOS
+ALTER TABLE bookings ADD COLUMN IF NOT EXISTS payment_method VARCHAR(20) NOT NULL DEFAULT 'PAYOS';
@@ -0,0 +1,66 @@
+-- Create promotions table for promo codes and single-use vouchers applied at booking time
+CREATE TABLE IF NOT EXISTS promotions (
+    -- Standard fields
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMPTZ,
+
+    -- Business fields
+    code VARCHAR(50) NOT NULL,
+    kind VARCHAR(20) NOT NULL DEFAULT 'CODE' CHECK (kind IN ('CODE', 'VOUCHER')),
+    description TEXT,
+    discount_type VARCHAR(20) NOT NULL CHECK (discount_type IN ('PERCENT', 'FIXED')),
+    discount_value INT NOT NULL CHECK (discount_value > 0),
+    max_discount INT,
+    min_spend INT NOT NULL DEFAULT 0,
+
+    -- Restrictions; NULL applies everywhere
+    route_id UUID,
+    trip_id UUID,
+    starts_at TIMESTAMPTZ,
+    ends_at TIMESTAMPTZ,
+    departure_from TIMESTAMPTZ,
+    departure_to TIMESTAMPTZ,
+
+    -- Usage caps; NULL is unlimited
+    usage_limit INT,
+    per_user_limit INT,
+    used_count INT NOT NULL DEFAULT 0 CHECK (used_count >= 0),
+    assigned_user_id UUID,
+
+    is_active BOOLEAN NOT NULL DEFAULT TRUE
+);
+
+CREATE UNIQUE INDEX idx_promotions_code ON promotions(code);
+CREATE INDEX idx_promotions_deleted_at ON promotions(deleted_at);
+
+CREATE TRIGGER update_promotions_updated_at BEFORE UPDATE ON promotions
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+-- Create promotion_redemptions table; an ACTIVE redemption holds one use of the promotion
+CREATE TABLE IF NOT EXISTS promotion_redemptions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    promotion_id UUID NOT NULL,
+    booking_id UUID NOT NULL,
+    user_id UUID NOT NULL,
+    discount_amount INT NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'ACTIVE' CHECK (status IN ('ACTIVE', 'RELEASED')),
+    released_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    -- Foreign keys
+    CONSTRAINT fk_promotion_redemptions_promotion FOREIGN KEY (promotion_id)
+        REFERENCES promotions(id) ON UPDATE CASCADE,
+    CONSTRAINT fk_promotion_redemptions_booking FOREIGN KEY (booking_id)
+        REFERENCES bookings(id) ON UPDATE CASCADE ON DELETE CASCADE
+);
+
+CREATE INDEX idx_promotion_redemptions_promotion_user ON promotion_redemptions(promotion_id, user_id) WHERE status = 'ACTIVE';
+-- A booking holds at most one use at a time
+CREATE UNIQUE INDEX idx_promotion_redemptions_active_booking ON promotion_redemptions(booking_id) WHERE status = 'ACTIVE';
+
+-- Snapshot of the discount on the booking; total_amount is what is left to pay
+ALTER TABLE bookings ADD COLUMN IF NOT EXISTS promotion_id UUID;
+ALTER TABLE bookings ADD COLUMN IF NOT EXISTS promo_code VARCHAR(50);
+ALTER TABLE bookings ADD COLUMN IF NOT EXISTS discount_amount DECIMAL(10,2) NOT NULL DEFAULT 0;
@@ -0,0 +1,30 @@
+-- Create waitlist_entries table; users wait for seats of a sold-out trip in the order they joined
+CREATE TABLE IF NOT EXISTS waitlist_entries (
+    -- Standard fields
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMPTZ,
+
+    -- Business fields
+    trip_id UUID NOT NULL,
+    user_id UUID NOT NULL,
+    seat_count INT NOT NULL CHECK (seat_count > 0),
+    seat_type VARCHAR(20),
+    status VARCHAR(20) NOT NULL DEFAULT 'WAITING' CHECK (status IN ('WAITING', 'OFFERED', 'CLAIMED', 'EXPIRED', 'CANCELLED')),
+
+    -- Seats offered are held under the offer session until the offer expires
+    offer_session_id VARCHAR(255),
+    offered_at TIMESTAMPTZ,
+    offer_expires_at TIMESTAMPTZ,
+    booking_id UUID
+);
+
+CREATE INDEX idx_waitlist_entries_trip_queue ON waitlist_entries(trip_id, created_at) WHERE status IN ('WAITING', 'OFFERED');
+CREATE INDEX idx_waitlist_entries_user_id ON waitlist_entries(user_id);
+CREATE INDEX idx_waitlist_entries_deleted_at ON waitlist_entries(deleted_at);
+-- A user waits at most once per trip
+CREATE UNIQUE INDEX idx_waitlist_entries_active_user_trip ON waitlist_entries(trip_id, user_id) WHERE status IN ('WAITING', 'OFFERED');
+
+CREATE TRIGGER update_waitlist_entries_updated_at BEFORE UPDATE ON waitlist_entries
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
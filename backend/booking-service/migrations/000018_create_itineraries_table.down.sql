@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_bookings_itinerary_id;
+ALTER TABLE bookings DROP COLUMN IF EXISTS leg_number;
+ALTER TABLE bookings DROP COLUMN IF EXISTS itinerary_id;
+
+DROP TABLE IF EXISTS itineraries;
@@ -0,0 +1,32 @@
+-- Create itineraries table; an itinerary groups the bookings of a round trip or
+-- multi-leg journey under one reference and one payment
+CREATE TABLE IF NOT EXISTS itineraries (
+    -- Standard fields
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMPTZ,
+
+    -- Business fields
+    itinerary_reference VARCHAR(20) NOT NULL UNIQUE,
+    user_id UUID NOT NULL,
+    kind VARCHAR(20) NOT NULL CHECK (kind IN ('ROUND_TRIP', 'MULTI_LEG')),
+    total_amount DECIMAL(10,2) NOT NULL,
+    discount_amount DECIMAL(10,2) NOT NULL DEFAULT 0,
+    transaction_id UUID NOT NULL,
+    payment_method VARCHAR(20) NOT NULL DEFAULT 'PAYOS',
+    expires_at TIMESTAMPTZ
+);
+
+CREATE INDEX idx_itineraries_user_id ON itineraries(user_id);
+CREATE INDEX idx_itineraries_transaction_id ON itineraries(transaction_id);
+CREATE INDEX idx_itineraries_deleted_at ON itineraries(deleted_at);
+
+CREATE TRIGGER update_itineraries_updated_at BEFORE UPDATE ON itineraries
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+-- Legs of an itinerary; plain bookings have no itinerary and leg number 0
+ALTER TABLE bookings ADD COLUMN IF NOT EXISTS itinerary_id UUID REFERENCES itineraries(id);
+ALTER TABLE bookings ADD COLUMN IF NOT EXISTS leg_number INT NOT NULL DEFAULT 0;
+
+CREATE INDEX IF NOT EXISTS idx_bookings_itinerary_id ON bookings(itinerary_id, leg_number);
@@ -0,0 +1 @@
+ALTER TABLE seat_locks DROP COLUMN quoted_fare;
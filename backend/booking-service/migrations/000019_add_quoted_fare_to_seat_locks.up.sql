@@ -0,0 +1,3 @@
+-- Fare quoted by trip service when the seats were held; the booking made from the hold is
+-- charged this fare even if dynamic pricing has changed it since
+ALTER TABLE seat_locks ADD COLUMN quoted_fare DECIMAL(10,2);
@@ -18,6 +18,16 @@ type APIError struct {
 	Code    string `json:"code,omitempty"`
 }
 
+// CancellationPolicy represents the refund tiers published by booking-service
+type CancellationPolicy struct {
+	Tiers []RefundTier `json:"tiers"`
+}
+
+type RefundTier struct {
+	HoursBeforeDeparture int `json:"hours_before_departure"`
+	RefundPercent        int `json:"refund_percent"`
+}
+
 // TripDetailResponse represents a detailed trip from trip-service
 type TripDetailResponse struct {
 	ID             uuid.UUID    `json:"id"`
@@ -129,14 +139,14 @@ type CreateGuestBookingRequest struct {
 	FullName   string          `json:"full_name"`
 	Email      string          `json:"email"`
 	Phone      string          `json:"phone"`
-	Passengers []PassengerData `json:"passengers"`
+	Passengers []PassengerData `json:"passengers,omitempty"`
 }
 
+// PassengerData names the passenger travelling on one booked seat
 type PassengerData struct {
-	Name   string    `json:"name"`
-	Phone  string    `json:"phone"`
-	Email  string    `json:"email"`
-	SeatID uuid.UUID `json:"seat_id"`
+	SeatID   uuid.UUID `json:"seat_id"`
+	FullName string    `json:"full_name"`
+	Phone    string    `json:"phone,omitempty"`
 }
 
 // TransactionResponse from payment-service
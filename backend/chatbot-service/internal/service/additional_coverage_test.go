@@ -9,7 +9,9 @@ import (
 	"time"
 
 	"bus-booking/chatbot-service/internal/model"
+	"bus-booking/chatbot-service/internal/service/mocks"
 
+	"github.com/golang/mock/gomock"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -301,6 +303,52 @@ func TestGetFAQAnswer_DirectKeywordMatch(t *testing.T) {
 	}
 }
 
+func TestGetFAQAnswer_CancellationPolicyFromBookingService(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockBookingService := mocks.NewMockBookingServiceClient(ctrl)
+	service := &ChatbotServiceImpl{
+		faqKnowledge:   loadFAQs(),
+		bookingService: mockBookingService,
+	}
+
+	t.Run("Uses live policy", func(t *testing.T) {
+		mockBookingService.EXPECT().
+			GetCancellationPolicy(gomock.Any()).
+			Return(&model.CancellationPolicy{
+				Tiers: []model.RefundTier{
+					{HoursBeforeDeparture: 48, RefundPercent: 90},
+					{HoursBeforeDeparture: 24, RefundPercent: 60},
+				},
+			}, nil)
+
+		answer, err := service.GetFAQAnswer(context.Background(), "Tôi muốn hủy vé")
+		require.NoError(t, err)
+		assert.Equal(t, "Bạn có thể hủy vé trước 48 giờ và được hoàn 90% giá vé, và trước 24 giờ hoàn 60%. Hủy vé trong vòng 24 giờ không được hoàn tiền.", answer)
+	})
+
+	t.Run("Falls back to static answer", func(t *testing.T) {
+		mockBookingService.EXPECT().
+			GetCancellationPolicy(gomock.Any()).
+			Return(nil, assert.AnError)
+
+		answer, err := service.GetFAQAnswer(context.Background(), "Tôi muốn hủy vé")
+		require.NoError(t, err)
+		assert.Equal(t, loadFAQs()[0].Answer, answer)
+	})
+}
+
+func TestFormatCancellationPolicy_MatchesStaticFAQ(t *testing.T) {
+	answer := formatCancellationPolicy([]model.RefundTier{
+		{HoursBeforeDeparture: 24, RefundPercent: 70},
+		{HoursBeforeDeparture: 12, RefundPercent: 50},
+		{HoursBeforeDeparture: 6, RefundPercent: 30},
+	})
+
+	assert.Equal(t, loadFAQs()[0].Answer, answer)
+}
+
 // Tests for normalizeCityName with actual cityAliases map
 func TestNormalizeCityName_ActualMappings(t *testing.T) {
 	tests := []struct {
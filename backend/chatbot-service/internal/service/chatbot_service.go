@@ -492,6 +492,9 @@ func (s *ChatbotServiceImpl) GetFAQAnswer(ctx context.Context, question string)
 	for _, faq := range s.faqKnowledge {
 		for _, keyword := range faq.Keywords {
 			if strings.Contains(questionLower, strings.ToLower(keyword)) {
+				if faq.Question == cancellationPolicyQuestion {
+					return s.getCancellationPolicyAnswer(ctx, faq.Answer), nil
+				}
 				return faq.Answer, nil
 			}
 		}
@@ -585,6 +588,42 @@ func (s *ChatbotServiceImpl) determineAction(intent string, context *model.ChatC
 	}
 }
 
+// getCancellationPolicyAnswer quotes the refund tiers currently enforced by booking-service,
+// falling back to the static FAQ answer when the policy cannot be fetched
+func (s *ChatbotServiceImpl) getCancellationPolicyAnswer(ctx context.Context, fallback string) string {
+	if s.bookingService == nil {
+		return fallback
+	}
+
+	policy, err := s.bookingService.GetCancellationPolicy(ctx)
+	if err != nil || len(policy.Tiers) == 0 {
+		log.Warn().Err(err).Msg("Failed to get cancellation policy, using static FAQ answer")
+		return fallback
+	}
+
+	return formatCancellationPolicy(policy.Tiers)
+}
+
+// formatCancellationPolicy renders tiers (ordered from longest notice) as a Vietnamese FAQ answer
+func formatCancellationPolicy(tiers []model.RefundTier) string {
+	parts := make([]string, 0, len(tiers))
+	for i, tier := range tiers {
+		if i == 0 {
+			parts = append(parts, fmt.Sprintf("trước %d giờ và được hoàn %d%% giá vé", tier.HoursBeforeDeparture, tier.RefundPercent))
+			continue
+		}
+		if i == len(tiers)-1 {
+			parts = append(parts, fmt.Sprintf("và trước %d giờ hoàn %d%%", tier.HoursBeforeDeparture, tier.RefundPercent))
+			continue
+		}
+		parts = append(parts, fmt.Sprintf("trước %d giờ hoàn %d%%", tier.HoursBeforeDeparture, tier.RefundPercent))
+	}
+
+	last := tiers[len(tiers)-1]
+	return fmt.Sprintf("Bạn có thể hủy vé %s. Hủy vé trong vòng %d giờ không được hoàn tiền.",
+		strings.Join(parts, ", "), last.HoursBeforeDeparture)
+}
+
 func (s *ChatbotServiceImpl) formatFAQs() string {
 	var sb strings.Builder
 	for _, faq := range s.faqKnowledge {
@@ -593,11 +632,14 @@ func (s *ChatbotServiceImpl) formatFAQs() string {
 	return sb.String()
 }
 
+// cancellationPolicyQuestion identifies the FAQ whose answer is served from booking-service's live policy
+const cancellationPolicyQuestion = "Chính sách hủy vé như thế nào?"
+
 // loadFAQs loads FAQ knowledge base
 func loadFAQs() []model.FAQ {
 	return []model.FAQ{
 		{
-			Question: "Chính sách hủy vé như thế nào?",
+			Question: cancellationPolicyQuestion,
 			Answer:   "Bạn có thể hủy vé trước 24 giờ và được hoàn 70% giá vé, trước 12 giờ hoàn 50%, và trước 6 giờ hoàn 30%. Hủy vé trong vòng 6 giờ không được hoàn tiền.",
 			Keywords: []string{"hủy", "cancel", "hoàn tiền", "refund"},
 		},
@@ -197,6 +197,7 @@ type BookingServiceClient interface {
 	CreateGuestBooking(ctx context.Context, req *model.CreateGuestBookingRequest) (*model.BookingResponse, error)
 	GetBookingByReference(ctx context.Context, reference string, email string) (*model.BookingResponse, error)
 	GetBookingByID(ctx context.Context, bookingID string) (*model.BookingResponse, error)
+	GetCancellationPolicy(ctx context.Context) (*model.CancellationPolicy, error)
 }
 
 type bookingServiceClientImpl struct {
@@ -328,6 +329,38 @@ func (c *bookingServiceClientImpl) GetBookingByID(ctx context.Context, bookingID
 	return &apiResp.Data, nil
 }
 
+func (c *bookingServiceClientImpl) GetCancellationPolicy(ctx context.Context) (*model.CancellationPolicy, error) {
+	reqURL := fmt.Sprintf("%s/api/v1/cancellation-policy", c.baseURL)
+
+	req, err := http.NewRequestWithContext(ctx, "GET", reqURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to call booking service")
+		return nil, fmt.Errorf("failed to call booking service: %w", err)
+	}
+	defer func() {
+		if closeErr := resp.Body.Close(); closeErr != nil {
+			log.Error().Err(closeErr).Msg("Failed to close response body")
+		}
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		log.Error().Int("status_code", resp.StatusCode).Msg("Booking service returned non-200 status")
+		return nil, fmt.Errorf("failed to get cancellation policy: status %d", resp.StatusCode)
+	}
+
+	var apiResp model.APIResponse[model.CancellationPolicy]
+	if err := json.NewDecoder(resp.Body).Decode(&apiResp); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return &apiResp.Data, nil
+}
+
 // PaymentServiceClient interfaces with payment-service
 type PaymentServiceClient interface {
 	CreateTransaction(ctx context.Context, req *model.CreateTransactionRequest) (*model.TransactionResponse, error)
@@ -250,10 +250,9 @@ func TestBookingServiceClient_CreateGuestBooking(t *testing.T) {
 			Phone:    "0901234567",
 			Passengers: []model.PassengerData{
 				{
-					Name:   "Test User",
-					Email:  "test@example.com",
-					Phone:  "0901234567",
-					SeatID: seatID,
+					SeatID:   seatID,
+					FullName: "Test User",
+					Phone:    "0901234567",
 				},
 			},
 		}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strings"
 	"time"
 
 	"bus-booking/chatbot-service/internal/model"
@@ -122,12 +123,12 @@ func (s *ChatbotServiceImpl) handleCreateGuestBooking(ctx context.Context, args
 	}
 
 	type BookingArgs struct {
-		TripID      string                `json:"trip_id"`
-		SeatNumbers []string              `json:"seat_numbers"`
-		FullName    string                `json:"full_name"`
-		Email       string                `json:"email"`
-		Phone       string                `json:"phone"`
-		Passengers  []model.PassengerData `json:"passengers"`
+		TripID      string                  `json:"trip_id"`
+		SeatNumbers []string                `json:"seat_numbers"`
+		FullName    string                  `json:"full_name"`
+		Email       string                  `json:"email"`
+		Phone       string                  `json:"phone"`
+		Passengers  []model.PassengerDetail `json:"passengers"`
 	}
 
 	var bookingArgs BookingArgs
@@ -166,31 +167,26 @@ func (s *ChatbotServiceImpl) handleCreateGuestBooking(ctx context.Context, args
 		}
 	}
 
-	// Step 3: Map seat numbers to seat IDs in passengers
-	passengers := make([]model.PassengerData, len(bookingArgs.Passengers))
+	// Step 3: Map passengers to their seat IDs, by seat number or by position
+	passengers := make([]model.PassengerData, 0, len(bookingArgs.Passengers))
 	for i, p := range bookingArgs.Passengers {
-		seatID, exists := seatMap[p.SeatID.String()] // Try direct UUID first
+		if strings.TrimSpace(p.Name) == "" {
+			continue
+		}
+
+		seatID, exists := seatMap[p.SeatNumber]
 		if !exists {
-			// If not UUID, try as seat number
-			if sid, ok := seatMap[p.SeatID.String()]; ok {
-				seatID = sid
-			} else {
-				// Last resort: find by matching in args
-				for j, seatNum := range bookingArgs.SeatNumbers {
-					if i < len(bookingArgs.SeatNumbers) && seatNum == bookingArgs.SeatNumbers[j] {
-						seatID = seatIDs[j]
-						break
-					}
-				}
+			if i >= len(seatIDs) {
+				continue
 			}
+			seatID = seatIDs[i]
 		}
 
-		passengers[i] = model.PassengerData{
-			Name:   p.Name,
-			Phone:  p.Phone,
-			Email:  p.Email,
-			SeatID: seatID,
-		}
+		passengers = append(passengers, model.PassengerData{
+			SeatID:   seatID,
+			FullName: p.Name,
+			Phone:    p.Phone,
+		})
 	}
 
 	// Step 4: Parse trip ID
@@ -133,6 +133,21 @@ func (mr *MockBookingServiceClientMockRecorder) GetBookingByReference(ctx, refer
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBookingByReference", reflect.TypeOf((*MockBookingServiceClient)(nil).GetBookingByReference), ctx, reference, email)
 }
 
+// GetCancellationPolicy mocks base method.
+func (m *MockBookingServiceClient) GetCancellationPolicy(ctx context.Context) (*model.CancellationPolicy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCancellationPolicy", ctx)
+	ret0, _ := ret[0].(*model.CancellationPolicy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCancellationPolicy indicates an expected call of GetCancellationPolicy.
+func (mr *MockBookingServiceClientMockRecorder) GetCancellationPolicy(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCancellationPolicy", reflect.TypeOf((*MockBookingServiceClient)(nil).GetCancellationPolicy), ctx)
+}
+
 // MockPaymentServiceClient is a mock of PaymentServiceClient interface.
 type MockPaymentServiceClient struct {
 	ctrl     *gomock.Controller
@@ -65,6 +65,56 @@ type BookingPendingRequest struct {
 	PaymentLink      string `json:"payment_link" binding:"required"`
 }
 
+// TripCancelledRequest represents the request to notify a passenger that the operator cancelled their trip
+type TripCancelledRequest struct {
+	Email            string `json:"email" binding:"required,email"`
+	Name             string `json:"name" binding:"required"`
+	BookingReference string `json:"booking_reference" binding:"required"`
+	Reason           string `json:"reason" binding:"required"`
+	From             string `json:"from" binding:"required"`
+	To               string `json:"to" binding:"required"`
+	DepartureTime    string `json:"departure_time" binding:"required"`
+	RefundAmount     int    `json:"refund_amount"`
+	BookingLink      string `json:"booking_link" binding:"required"`
+}
+
+// RefundSentRequest represents the request to notify a passenger that their refund was transferred
+type RefundSentRequest struct {
+	Email           string `json:"email" binding:"required,email"`
+	Name            string `json:"name" binding:"required"`
+	RefundAmount    int    `json:"refund_amount" binding:"required"`
+	BankName        string `json:"bank_name" binding:"required"`
+	AccountNumber   string `json:"account_number" binding:"required"`
+	TransferContent string `json:"transfer_content" binding:"required"`
+}
+
+// PaymentUnderpaidRequest represents the request to ask a passenger to transfer the rest of a short payment
+type PaymentUnderpaidRequest struct {
+	Email            string `json:"email" binding:"required,email"`
+	Name             string `json:"name" binding:"required"`
+	BookingReference string `json:"booking_reference" binding:"required"`
+	From             string `json:"from" binding:"required"`
+	To               string `json:"to" binding:"required"`
+	DepartureTime    string `json:"departure_time" binding:"required"`
+	TotalAmount      int    `json:"total_amount" binding:"required"`
+	AmountPaid       int    `json:"amount_paid" binding:"required"`
+	AmountRemaining  int    `json:"amount_remaining" binding:"required"`
+	PaymentLink      string `json:"payment_link" binding:"required"`
+	ExpiresAt        string `json:"expires_at" binding:"required"`
+}
+
+// WaitlistOfferRequest represents the request to offer seats held for a waitlisted passenger
+type WaitlistOfferRequest struct {
+	Email         string `json:"email" binding:"required,email"`
+	Name          string `json:"name" binding:"required"`
+	From          string `json:"from" binding:"required"`
+	To            string `json:"to" binding:"required"`
+	DepartureTime string `json:"departure_time" binding:"required"`
+	SeatNumbers   string `json:"seat_numbers" binding:"required"`
+	ClaimLink     string `json:"claim_link" binding:"required"`
+	ExpiresAt     string `json:"expires_at" binding:"required"`
+}
+
 type NotificationType string
 
 const (
@@ -73,6 +123,10 @@ const (
 	NotificationTypeBookingConfirmation NotificationType = "BOOKING_CONFIRMATION"
 	NotificationTypeBookingFailure      NotificationType = "BOOKING_FAILURE"
 	NotificationTypeBookingPending      NotificationType = "BOOKING_PENDING"
+	NotificationTypeTripCancelled       NotificationType = "TRIP_CANCELLED"
+	NotificationTypeRefundSent          NotificationType = "REFUND_SENT"
+	NotificationTypePaymentUnderpaid    NotificationType = "PAYMENT_UNDERPAID"
+	NotificationTypeWaitlistOffer       NotificationType = "WAITLIST_OFFER"
 )
 
 // GenericNotificationRequest represents a unified request for all notifications
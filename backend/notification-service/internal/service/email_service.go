@@ -20,6 +20,10 @@ type EmailService interface {
 	SendBookingConfirmationEmail(to string, data map[string]interface{}) error
 	SendBookingFailureEmail(to string, data map[string]interface{}) error
 	SendBookingPendingEmail(to string, data map[string]interface{}) error
+	SendTripCancelledEmail(to string, data map[string]interface{}) error
+	SendRefundSentEmail(to string, data map[string]interface{}) error
+	SendPaymentUnderpaidEmail(to string, data map[string]interface{}) error
+	SendWaitlistOfferEmail(to string, data map[string]interface{}) error
 	SendTemplateEmail(to []string, subject, templateName string, data map[string]interface{}) error
 }
 
@@ -147,6 +151,58 @@ func (s *EmailServiceImpl) SendBookingPendingEmail(to string, data map[string]in
 	return s.SendTemplateEmail([]string{to}, subject, "booking_pending.html", data)
 }
 
+// SendTripCancelledEmail sends a trip cancelled email
+func (s *EmailServiceImpl) SendTripCancelledEmail(to string, data map[string]interface{}) error {
+	subject := "Chuyến đi đã bị hủy - Bus Booking System"
+	data["LogoHTML"] = s.getLogoHTML()
+
+	log.Info().
+		Str("to", to).
+		Str("subject", subject).
+		Msg("Sending trip cancelled email")
+
+	return s.SendTemplateEmail([]string{to}, subject, "trip_cancelled.html", data)
+}
+
+// SendRefundSentEmail sends a refund sent email
+func (s *EmailServiceImpl) SendRefundSentEmail(to string, data map[string]interface{}) error {
+	subject := "Hoàn tiền đã được chuyển - Bus Booking System"
+	data["LogoHTML"] = s.getLogoHTML()
+
+	log.Info().
+		Str("to", to).
+		Str("subject", subject).
+		Msg("Sending refund sent email")
+
+	return s.SendTemplateEmail([]string{to}, subject, "refund_sent.html", data)
+}
+
+// SendPaymentUnderpaidEmail sends a payment underpaid email
+func (s *EmailServiceImpl) SendPaymentUnderpaidEmail(to string, data map[string]interface{}) error {
+	subject := "Thanh toán chưa đủ - Bus Booking System"
+	data["LogoHTML"] = s.getLogoHTML()
+
+	log.Info().
+		Str("to", to).
+		Str("subject", subject).
+		Msg("Sending payment underpaid email")
+
+	return s.SendTemplateEmail([]string{to}, subject, "payment_underpaid.html", data)
+}
+
+// SendWaitlistOfferEmail sends a waitlist offer email
+func (s *EmailServiceImpl) SendWaitlistOfferEmail(to string, data map[string]interface{}) error {
+	subject := "Có chỗ trống cho bạn - Bus Booking System"
+	data["LogoHTML"] = s.getLogoHTML()
+
+	log.Info().
+		Str("to", to).
+		Str("subject", subject).
+		Msg("Sending waitlist offer email")
+
+	return s.SendTemplateEmail([]string{to}, subject, "waitlist_offer.html", data)
+}
+
 // SendTemplateEmail sends an email using a template via Brevo API
 func (s *EmailServiceImpl) SendTemplateEmail(to []string, subject, templateName string, data map[string]interface{}) error {
 	htmlBody, err := s.getMailTemplate(templateName, data)
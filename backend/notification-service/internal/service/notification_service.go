@@ -17,6 +17,10 @@ type NotificationService interface {
 	SendBookingConfirmationEmail(ctx context.Context, req *model.BookingConfirmationRequest) error
 	SendBookingFailureEmail(ctx context.Context, req *model.BookingFailureRequest) error
 	SendBookingPendingEmail(ctx context.Context, req *model.BookingPendingRequest) error
+	SendTripCancelledEmail(ctx context.Context, req *model.TripCancelledRequest) error
+	SendRefundSentEmail(ctx context.Context, req *model.RefundSentRequest) error
+	SendPaymentUnderpaidEmail(ctx context.Context, req *model.PaymentUnderpaidRequest) error
+	SendWaitlistOfferEmail(ctx context.Context, req *model.WaitlistOfferRequest) error
 }
 
 type NotificationServiceImpl struct {
@@ -71,6 +75,34 @@ func (n *NotificationServiceImpl) SendNotification(ctx context.Context, req *mod
 		}
 		return n.SendBookingPendingEmail(ctx, &pendingReq)
 
+	case model.NotificationTypeTripCancelled:
+		var cancelledReq model.TripCancelledRequest
+		if err := json.Unmarshal(payloadBytes, &cancelledReq); err != nil {
+			return fmt.Errorf("invalid payload for trip cancelled: %w", err)
+		}
+		return n.SendTripCancelledEmail(ctx, &cancelledReq)
+
+	case model.NotificationTypeRefundSent:
+		var refundReq model.RefundSentRequest
+		if err := json.Unmarshal(payloadBytes, &refundReq); err != nil {
+			return fmt.Errorf("invalid payload for refund sent: %w", err)
+		}
+		return n.SendRefundSentEmail(ctx, &refundReq)
+
+	case model.NotificationTypePaymentUnderpaid:
+		var underpaidReq model.PaymentUnderpaidRequest
+		if err := json.Unmarshal(payloadBytes, &underpaidReq); err != nil {
+			return fmt.Errorf("invalid payload for payment underpaid: %w", err)
+		}
+		return n.SendPaymentUnderpaidEmail(ctx, &underpaidReq)
+
+	case model.NotificationTypeWaitlistOffer:
+		var offerReq model.WaitlistOfferRequest
+		if err := json.Unmarshal(payloadBytes, &offerReq); err != nil {
+			return fmt.Errorf("invalid payload for waitlist offer: %w", err)
+		}
+		return n.SendWaitlistOfferEmail(ctx, &offerReq)
+
 	default:
 		return fmt.Errorf("unsupported notification type: %s", req.Type)
 	}
@@ -180,3 +212,85 @@ func (n *NotificationServiceImpl) SendBookingPendingEmail(ctx context.Context, r
 	}
 	return nil
 }
+
+func (n *NotificationServiceImpl) SendTripCancelledEmail(ctx context.Context, req *model.TripCancelledRequest) error {
+	log.Info().Str("email", req.Email).Msg("Sending trip cancelled email")
+
+	data := map[string]interface{}{
+		"Name":             req.Name,
+		"BookingReference": req.BookingReference,
+		"Reason":           req.Reason,
+		"From":             req.From,
+		"To":               req.To,
+		"DepartureTime":    req.DepartureTime,
+		"RefundAmount":     req.RefundAmount,
+		"BookingLink":      req.BookingLink,
+	}
+
+	if err := n.emailService.SendTripCancelledEmail(req.Email, data); err != nil {
+		log.Error().Err(err).Msg("Failed to send trip cancelled email")
+		return fmt.Errorf("failed to send trip cancelled email: %w", err)
+	}
+	return nil
+}
+
+func (n *NotificationServiceImpl) SendRefundSentEmail(ctx context.Context, req *model.RefundSentRequest) error {
+	log.Info().Str("email", req.Email).Msg("Sending refund sent email")
+
+	data := map[string]interface{}{
+		"Name":            req.Name,
+		"RefundAmount":    req.RefundAmount,
+		"BankName":        req.BankName,
+		"AccountNumber":   req.AccountNumber,
+		"TransferContent": req.TransferContent,
+	}
+
+	if err := n.emailService.SendRefundSentEmail(req.Email, data); err != nil {
+		log.Error().Err(err).Msg("Failed to send refund sent email")
+		return fmt.Errorf("failed to send refund sent email: %w", err)
+	}
+	return nil
+}
+
+func (n *NotificationServiceImpl) SendPaymentUnderpaidEmail(ctx context.Context, req *model.PaymentUnderpaidRequest) error {
+	log.Info().Str("email", req.Email).Msg("Sending payment underpaid email")
+
+	data := map[string]interface{}{
+		"Name":             req.Name,
+		"BookingReference": req.BookingReference,
+		"From":             req.From,
+		"To":               req.To,
+		"DepartureTime":    req.DepartureTime,
+		"TotalAmount":      req.TotalAmount,
+		"AmountPaid":       req.AmountPaid,
+		"AmountRemaining":  req.AmountRemaining,
+		"PaymentLink":      req.PaymentLink,
+		"ExpiresAt":        req.ExpiresAt,
+	}
+
+	if err := n.emailService.SendPaymentUnderpaidEmail(req.Email, data); err != nil {
+		log.Error().Err(err).Msg("Failed to send payment underpaid email")
+		return fmt.Errorf("failed to send payment underpaid email: %w", err)
+	}
+	return nil
+}
+
+func (n *NotificationServiceImpl) SendWaitlistOfferEmail(ctx context.Context, req *model.WaitlistOfferRequest) error {
+	log.Info().Str("email", req.Email).Msg("Sending waitlist offer email")
+
+	data := map[string]interface{}{
+		"Name":          req.Name,
+		"From":          req.From,
+		"To":            req.To,
+		"DepartureTime": req.DepartureTime,
+		"SeatNumbers":   req.SeatNumbers,
+		"ClaimLink":     req.ClaimLink,
+		"ExpiresAt":     req.ExpiresAt,
+	}
+
+	if err := n.emailService.SendWaitlistOfferEmail(req.Email, data); err != nil {
+		log.Error().Err(err).Msg("Failed to send waitlist offer email")
+		return fmt.Errorf("failed to send waitlist offer email: %w", err)
+	}
+	return nil
+}
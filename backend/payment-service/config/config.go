@@ -1,6 +1,8 @@
 package config
 
 import (
+	"time"
+
 	sharedConfig "bus-booking/shared/config"
 )
 
@@ -8,10 +10,19 @@ type Config struct {
 	*sharedConfig.BaseConfig
 	External ExternalConfig `envPrefix:"EXTERNAL_"`
 	PayOS    PayOSConfig    `envPrefix:"PAYOS_"`
+
+	Reconciliation ReconciliationConfig `envPrefix:"RECONCILIATION_"`
+	Sandbox        SandboxConfig        `envPrefix:"SANDBOX_"`
+	Payout         PayoutConfig         `envPrefix:"PAYOUT_"`
+	Ledger         LedgerConfig         `envPrefix:"LEDGER_"`
+	Invoice        InvoiceConfig        `envPrefix:"INVOICE_"`
+	Wallet         WalletConfig         `envPrefix:"WALLET_"`
 }
 
 type ExternalConfig struct {
-	BookingServiceURL string `env:"BOOKING_SERVICE_URL" envDefault:"http://localhost:8082"`
+	BookingServiceURL      string `env:"BOOKING_SERVICE_URL" envDefault:"http://localhost:8082"`
+	UserServiceURL         string `env:"USER_SERVICE_URL" envDefault:"http://localhost:8083"`
+	NotificationServiceURL string `env:"NOTIFICATION_SERVICE_URL" envDefault:"http://localhost:8085"`
 }
 
 type PayOSConfig struct {
@@ -22,6 +33,48 @@ type PayOSConfig struct {
 	CancelURL   string `env:"CANCEL_URL" envDefault:"http://localhost:3000/payment/cancel"`
 }
 
+// ReconciliationConfig controls the worker polling PayOS for payments whose webhook never arrived
+type ReconciliationConfig struct {
+	Interval  time.Duration `env:"INTERVAL" envDefault:"1m"`
+	MinAge    time.Duration `env:"MIN_AGE" envDefault:"2m"`
+	BatchSize int           `env:"BATCH_SIZE" envDefault:"100"`
+}
+
+// SandboxConfig enables the offline payment provider, never turn it on in production
+type SandboxConfig struct {
+	Enabled     bool   `env:"ENABLED" envDefault:"false"`
+	CheckoutURL string `env:"CHECKOUT_URL" envDefault:"http://localhost:3000/payment/sandbox"`
+}
+
+// PayoutConfig controls the refund transfer batches
+type PayoutConfig struct {
+	MaxBatchSize int `env:"MAX_BATCH_SIZE" envDefault:"200"`
+}
+
+// LedgerConfig holds the PayOS fee posted to the ledger with each payment, in basis points plus a fixed VND amount
+type LedgerConfig struct {
+	PayOSFeeBasisPoints int `env:"PAYOS_FEE_BASIS_POINTS" envDefault:"0"`
+	PayOSFixedFee       int `env:"PAYOS_FIXED_FEE" envDefault:"0"`
+}
+
+// InvoiceConfig holds the seller printed on VAT invoices and how they are taxed and numbered
+type InvoiceConfig struct {
+	SellerName    string `env:"SELLER_NAME" envDefault:"Bus Booking System"`
+	SellerTaxCode string `env:"SELLER_TAX_CODE" envDefault:""`
+	SellerAddress string `env:"SELLER_ADDRESS" envDefault:""`
+	// VATRate is the VAT percent included in ticket prices
+	VATRate int `env:"VAT_RATE" envDefault:"10"`
+	// SeriesSuffix ends the invoice symbol, which starts with 1C and the two digits of the year
+	SeriesSuffix string `env:"SERIES_SUFFIX" envDefault:"TBB"`
+}
+
+// WalletConfig controls how long promotional credit lasts and the worker taking it off wallets once expired
+type WalletConfig struct {
+	PromotionalCreditValidity time.Duration `env:"PROMOTIONAL_CREDIT_VALIDITY" envDefault:"2160h"`
+	ExpiryInterval            time.Duration `env:"EXPIRY_INTERVAL" envDefault:"1h"`
+	ExpiryBatchSize           int           `env:"EXPIRY_BATCH_SIZE" envDefault:"100"`
+}
+
 func LoadConfig(envFilePath ...string) (*Config, error) {
 	return sharedConfig.LoadConfig[Config](envFilePath...)
 }
@@ -5,7 +5,7 @@ package docs
 import "github.com/swaggo/swag/v2"
 
 const docTemplate = `{
-    "schemes": {{ marshal .Schemes }},"swagger":"2.0","info":{"description":"{{escape .Description}}","title":"{{.Title}}","contact":{"name":"API Support","email":"support@busbooking.com"},"license":{"name":"MIT","url":"https://opensource.org/licenses/MIT"},"version":"{{.Version}}"},"host":"{{.Host}}","basePath":"{{.BasePath}}","paths":{"/api/v1/bank-accounts":{"get":{"description":"Get all bank accounts of the authenticated user","consumes":["application/json"],"produces":["application/json"],"tags":["bank-accounts"],"summary":"Get my bank accounts","responses":{"200":{"description":"OK","schema":{"allOf":[{"$ref":"#/definitions/ginext.Response"},{"type":"object","properties":{"data":{"type":"array","items":{"$ref":"#/definitions/bus-booking_payment-service_internal_model.BankAccountResponse"}}}}]}},"401":{"description":"Unauthorized","schema":{"$ref":"#/definitions/ginext.Response"}},"500":{"description":"Internal Server Error","schema":{"$ref":"#/definitions/ginext.Response"}}}},"post":{"description":"Create a new bank account for the authenticated user","consumes":["application/json"],"produces":["application/json"],"tags":["bank-accounts"],"summary":"Create a bank account","parameters":[{"description":"Bank account details","name":"account","in":"body","required":true,"schema":{"$ref":"#/definitions/bus-booking_payment-service_internal_model.BankAccountRequest"}}],"responses":{"201":{"description":"Created","schema":{"allOf":[{"$ref":"#/definitions/ginext.Response"},{"type":"object","properties":{"data":{"$ref":"#/definitions/bus-booking_payment-service_internal_model.BankAccountResponse"}}}]}},"400":{"description":"Bad Request","schema":{"$ref":"#/definitions/ginext.Response"}},"401":{"description":"Unauthorized","schema":{"$ref":"#/definitions/ginext.Response"}},"500":{"description":"Internal Server Error","schema":{"$ref":"#/definitions/ginext.Response"}}}}},"/api/v1/bank-accounts/{id}":{"put":{"description":"Update a bank account of the authenticated user","consumes":["application/json"],"produces":["application/json"],"tags":["bank-accounts"],"summary":"Update a bank account","parameters":[{"type":"string","description":"Bank Account ID","name":"id","in":"path","required":true},{"description":"Bank account details","name":"account","in":"body","required":true,"schema":{"$ref":"#/definitions/bus-booking_payment-service_internal_model.BankAccountRequest"}}],"responses":{"200":{"description":"OK","schema":{"allOf":[{"$ref":"#/definitions/ginext.Response"},{"type":"object","properties":{"data":{"$ref":"#/definitions/bus-booking_payment-service_internal_model.BankAccountResponse"}}}]}},"400":{"description":"Bad Request","schema":{"$ref":"#/definitions/ginext.Response"}},"401":{"description":"Unauthorized","schema":{"$ref":"#/definitions/ginext.Response"}},"404":{"description":"Not Found","schema":{"$ref":"#/definitions/ginext.Response"}},"500":{"description":"Internal Server Error","schema":{"$ref":"#/definitions/ginext.Response"}}}},"delete":{"description":"Delete a bank account of the authenticated user","consumes":["application/json"],"produces":["application/json"],"tags":["bank-accounts"],"summary":"Delete a bank account","parameters":[{"type":"string","description":"Bank Account ID","name":"id","in":"path","required":true}],"responses":{"200":{"description":"OK","schema":{"$ref":"#/definitions/ginext.Response"}},"400":{"description":"Bad Request","schema":{"$ref":"#/definitions/ginext.Response"}},"401":{"description":"Unauthorized","schema":{"$ref":"#/definitions/ginext.Response"}},"404":{"description":"Not Found","schema":{"$ref":"#/definitions/ginext.Response"}},"500":{"description":"Internal Server Error","schema":{"$ref":"#/definitions/ginext.Response"}}}}},"/api/v1/bank-accounts/{id}/set-primary":{"post":{"description":"Set a bank account as the primary account for refunds","consumes":["application/json"],"produces":["application/json"],"tags":["bank-accounts"],"summary":"Set primary bank account","parameters":[{"type":"string","description":"Bank Account ID","name":"id","in":"path","required":true}],"responses":{"200":{"description":"OK","schema":{"$ref":"#/definitions/ginext.Response"}},"400":{"description":"Bad Request","schema":{"$ref":"#/definitions/ginext.Response"}},"401":{"description":"Unauthorized","schema":{"$ref":"#/definitions/ginext.Response"}},"404":{"description":"Not Found","schema":{"$ref":"#/definitions/ginext.Response"}},"500":{"description":"Internal Server Error","schema":{"$ref":"#/definitions/ginext.Response"}}}}},"/api/v1/constants":{"get":{"description":"Get constants by type (banks). Returns all types if type parameter is not specified.","consumes":["application/json"],"produces":["application/json"],"tags":["constants"],"summary":"Get constants","parameters":[{"enum":["banks"],"type":"string","description":"Constant type","name":"type","in":"query"}],"responses":{"200":{"description":"Constants","schema":{"$ref":"#/definitions/ginext.Response"}},"400":{"description":"Invalid type parameter","schema":{"$ref":"#/definitions/ginext.Response"}},"500":{"description":"Internal server error","schema":{"$ref":"#/definitions/ginext.Response"}}}}},"/api/v1/refunds":{"get":{"description":"List refund transactions with filters","consumes":["application/json"],"produces":["application/json"],"tags":["admin"],"summary":"List refunds (Admin)","parameters":[{"enum":["PENDING","PROCESSING","COMPLETED","REJECTED"],"type":"string","description":"Refund status","name":"status","in":"query"},{"type":"string","description":"Start date (RFC3339)","name":"start_date","in":"query"},{"type":"string","description":"End date (RFC3339)","name":"end_date","in":"query"},{"type":"integer","default":20,"description":"Limit","name":"limit","in":"query"},{"type":"integer","default":0,"description":"Offset","name":"offset","in":"query"}],"responses":{"200":{"description":"OK","schema":{"$ref":"#/definitions/ginext.Response"}},"400":{"description":"Bad Request","schema":{"$ref":"#/definitions/ginext.Response"}},"401":{"description":"Unauthorized","schema":{"$ref":"#/definitions/ginext.Response"}},"403":{"description":"Forbidden","schema":{"$ref":"#/definitions/ginext.Response"}},"500":{"description":"Internal Server Error","schema":{"$ref":"#/definitions/ginext.Response"}}}},"post":{"description":"Create a refund request for a cancelled booking","consumes":["application/json"],"produces":["application/json"],"tags":["refunds"],"summary":"Create a refund request","parameters":[{"description":"Refund request","name":"refund","in":"body","required":true,"schema":{"$ref":"#/definitions/bus-booking_payment-service_internal_model.RefundRequest"}}],"responses":{"201":{"description":"Created","schema":{"allOf":[{"$ref":"#/definitions/ginext.Response"},{"type":"object","properties":{"data":{"$ref":"#/definitions/bus-booking_payment-service_internal_model.RefundResponse"}}}]}},"400":{"description":"Bad Request","schema":{"$ref":"#/definitions/ginext.Response"}},"401":{"description":"Unauthorized","schema":{"$ref":"#/definitions/ginext.Response"}},"500":{"description":"Internal Server Error","schema":{"$ref":"#/definitions/ginext.Response"}}}}},"/api/v1/refunds/booking/{booking_id}":{"get":{"description":"Get refund information for a specific booking","consumes":["application/json"],"produces":["application/json"],"tags":["refunds"],"summary":"Get refund by booking ID","parameters":[{"type":"string","description":"Booking ID","name":"booking_id","in":"path","required":true}],"responses":{"200":{"description":"OK","schema":{"allOf":[{"$ref":"#/definitions/ginext.Response"},{"type":"object","properties":{"data":{"$ref":"#/definitions/bus-booking_payment-service_internal_model.RefundResponse"}}}]}},"400":{"description":"Bad Request","schema":{"$ref":"#/definitions/ginext.Response"}},"401":{"description":"Unauthorized","schema":{"$ref":"#/definitions/ginext.Response"}},"404":{"description":"Not Found","schema":{"$ref":"#/definitions/ginext.Response"}},"500":{"description":"Internal Server Error","schema":{"$ref":"#/definitions/ginext.Response"}}}}},"/api/v1/refunds/export":{"post":{"description":"Export selected refund transactions to an Excel file","consumes":["application/json"],"produces":["application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"],"tags":["admin"],"summary":"Export refunds to Excel (Admin)","parameters":[{"description":"Export request","name":"request","in":"body","required":true,"schema":{"$ref":"#/definitions/bus-booking_payment-service_internal_model.ExportRefundsRequest"}}],"responses":{"200":{"description":"Excel file","schema":{"type":"file"}},"400":{"description":"Bad Request","schema":{"$ref":"#/definitions/ginext.Response"}},"401":{"description":"Unauthorized","schema":{"$ref":"#/definitions/ginext.Response"}},"403":{"description":"Forbidden","schema":{"$ref":"#/definitions/ginext.Response"}},"404":{"description":"Not Found","schema":{"$ref":"#/definitions/ginext.Response"}},"500":{"description":"Internal Server Error","schema":{"$ref":"#/definitions/ginext.Response"}}}}},"/api/v1/refunds/{id}":{"put":{"description":"Update the status of a refund transaction","consumes":["application/json"],"produces":["application/json"],"tags":["admin"],"summary":"Update refund status (Admin)","parameters":[{"type":"string","description":"Refund Transaction ID","name":"id","in":"path","required":true},{"description":"Status update request","name":"request","in":"body","required":true,"schema":{"$ref":"#/definitions/bus-booking_payment-service_internal_model.UpdateRefundStatusRequest"}}],"responses":{"200":{"description":"OK","schema":{"$ref":"#/definitions/ginext.Response"}},"400":{"description":"Bad Request","schema":{"$ref":"#/definitions/ginext.Response"}},"401":{"description":"Unauthorized","schema":{"$ref":"#/definitions/ginext.Response"}},"403":{"description":"Forbidden","schema":{"$ref":"#/definitions/ginext.Response"}},"404":{"description":"Not Found","schema":{"$ref":"#/definitions/ginext.Response"}},"500":{"description":"Internal Server Error","schema":{"$ref":"#/definitions/ginext.Response"}}}}},"/api/v1/transactions":{"get":{"description":"List all transactions with filters","consumes":["application/json"],"produces":["application/json"],"tags":["admin"],"summary":"List all transactions (Admin)","parameters":[{"enum":["IN","OUT"],"type":"string","description":"Transaction type","name":"transaction_type","in":"query"},{"type":"string","description":"Transaction status","name":"status","in":"query"},{"type":"string","description":"Refund status","name":"refund_status","in":"query"},{"type":"string","description":"Start date (RFC3339)","name":"start_date","in":"query"},{"type":"string","description":"End date (RFC3339)","name":"end_date","in":"query"},{"type":"integer","default":1,"description":"Page number","name":"page","in":"query"},{"type":"integer","default":20,"description":"Page size","name":"page_size","in":"query"}],"responses":{"200":{"description":"OK","schema":{"$ref":"#/definitions/ginext.Response"}},"400":{"description":"Bad Request","schema":{"$ref":"#/definitions/ginext.Response"}},"401":{"description":"Unauthorized","schema":{"$ref":"#/definitions/ginext.Response"}},"403":{"description":"Forbidden","schema":{"$ref":"#/definitions/ginext.Response"}},"500":{"description":"Internal Server Error","schema":{"$ref":"#/definitions/ginext.Response"}}}},"post":{"description":"Create a payment link via PayOS for a booking","consumes":["application/json"],"produces":["application/json"],"tags":["transactions"],"summary":"Create a payment link","parameters":[{"description":"Payment creation request","name":"transaction","in":"body","required":true,"schema":{"$ref":"#/definitions/bus-booking_payment-service_internal_model.CreateTransactionRequest"}}],"responses":{"201":{"description":"Created","schema":{"allOf":[{"$ref":"#/definitions/ginext.Response"},{"type":"object","properties":{"data":{"$ref":"#/definitions/bus-booking_payment-service_internal_model.TransactionResponse"}}}]}},"400":{"description":"Bad Request","schema":{"$ref":"#/definitions/ginext.Response"}},"500":{"description":"Internal Server Error","schema":{"$ref":"#/definitions/ginext.Response"}}}}},"/api/v1/transactions/stats":{"get":{"description":"Get transaction stats with filters","consumes":["application/json"],"produces":["application/json"],"tags":["admin"],"summary":"Get transaction stats (Admin)","parameters":[{"enum":["IN","OUT"],"type":"string","description":"Transaction type","name":"transaction_type","in":"query"},{"type":"string","description":"Transaction status","name":"status","in":"query"},{"type":"string","description":"Refund status","name":"refund_status","in":"query"},{"type":"string","description":"Start date (RFC3339)","name":"start_date","in":"query"},{"type":"string","description":"End date (RFC3339)","name":"end_date","in":"query"}],"responses":{"200":{"description":"OK","schema":{"$ref":"#/definitions/ginext.Response"}},"400":{"description":"Bad Request","schema":{"$ref":"#/definitions/ginext.Response"}},"401":{"description":"Unauthorized","schema":{"$ref":"#/definitions/ginext.Response"}},"403":{"description":"Forbidden","schema":{"$ref":"#/definitions/ginext.Response"}},"500":{"description":"Internal Server Error","schema":{"$ref":"#/definitions/ginext.Response"}}}}},"/api/v1/transactions/webhook":{"post":{"description":"Handle payment webhook notification from PayOS","consumes":["application/json"],"produces":["application/json"],"tags":["transactions"],"summary":"Handle PayOS webhook","parameters":[{"description":"Webhook payload","name":"webhook","in":"body","required":true,"schema":{"$ref":"#/definitions/bus-booking_payment-service_internal_model.PaymentWebhookData"}}],"responses":{"200":{"description":"OK","schema":{"$ref":"#/definitions/ginext.Response"}},"400":{"description":"Bad Request","schema":{"$ref":"#/definitions/ginext.Response"}},"500":{"description":"Internal Server Error","schema":{"$ref":"#/definitions/ginext.Response"}}}}},"/api/v1/transactions/{id}":{"get":{"description":"Retrieve transaction details by transaction ID","consumes":["application/json"],"produces":["application/json"],"tags":["transactions"],"summary":"Get transaction by ID","parameters":[{"type":"string","description":"Transaction ID","name":"id","in":"path","required":true}],"responses":{"200":{"description":"OK","schema":{"allOf":[{"$ref":"#/definitions/ginext.Response"},{"type":"object","properties":{"data":{"$ref":"#/definitions/bus-booking_payment-service_internal_model.TransactionResponse"}}}]}},"400":{"description":"Bad Request","schema":{"$ref":"#/definitions/ginext.Response"}},"404":{"description":"Not Found","schema":{"$ref":"#/definitions/ginext.Response"}},"500":{"description":"Internal Server Error","schema":{"$ref":"#/definitions/ginext.Response"}}}}},"/api/v1/transactions/{id}/cancel":{"post":{"description":"Cancel a payment transaction and PayOS payment link","consumes":["application/json"],"produces":["application/json"],"tags":["transactions"],"summary":"Cancel a payment","parameters":[{"type":"string","description":"Transaction ID","name":"id","in":"path","required":true}],"responses":{"200":{"description":"OK","schema":{"$ref":"#/definitions/ginext.Response"}},"400":{"description":"Bad Request","schema":{"$ref":"#/definitions/ginext.Response"}},"404":{"description":"Not Found","schema":{"$ref":"#/definitions/ginext.Response"}},"500":{"description":"Internal Server Error","schema":{"$ref":"#/definitions/ginext.Response"}}}}}},"definitions":{"bus-booking_payment-service_internal_model.BankAccountRequest":{"type":"object","required":["account_holder","account_number","bank_code"],"properties":{"account_holder":{"type":"string","maxLength":100,"minLength":1},"account_number":{"type":"string"},"bank_code":{"type":"string"}}},"bus-booking_payment-service_internal_model.BankAccountResponse":{"type":"object","properties":{"account_holder":{"type":"string"},"account_number":{"type":"string"},"bank_code":{"type":"string"},"bank_name":{"description":"Resolved from BankCode","type":"string"},"created_at":{"type":"string"},"id":{"type":"string"},"is_primary":{"type":"boolean"},"updated_at":{"type":"string"},"user_id":{"type":"string"}}},"bus-booking_payment-service_internal_model.CreateTransactionRequest":{"type":"object","required":["amount","booking_id","currency","payment_method"],"properties":{"amount":{"type":"integer"},"booking_id":{"type":"string"},"currency":{"$ref":"#/definitions/bus-booking_payment-service_internal_model.Currency"},"description":{"type":"string"},"expires_at":{"type":"string"},"id":{"type":"string"},"payment_method":{"$ref":"#/definitions/bus-booking_payment-service_internal_model.PaymentMethod"}}},"bus-booking_payment-service_internal_model.Currency":{"type":"string","enum":["VND"],"x-enum-varnames":["CurrencyVND"]},"bus-booking_payment-service_internal_model.ExportRefundsRequest":{"type":"object","required":["refund_ids"],"properties":{"refund_ids":{"type":"array","minItems":1,"items":{"type":"string"}}}},"bus-booking_payment-service_internal_model.PaymentMethod":{"type":"string","enum":["PAYOS"],"x-enum-varnames":["PaymentMethodPayOS"]},"bus-booking_payment-service_internal_model.PaymentWebhookData":{"type":"object","properties":{"code":{"type":"string"},"data":{"$ref":"#/definitions/bus-booking_payment-service_internal_model.PaymentWebhookDetails"},"desc":{"type":"string"},"signature":{"type":"string"},"success":{"type":"boolean"}}},"bus-booking_payment-service_internal_model.PaymentWebhookDetails":{"type":"object","properties":{"accountNumber":{"type":"string"},"amount":{"type":"integer"},"code":{"type":"string"},"counterAccountBankId":{"type":"string"},"counterAccountBankName":{"type":"string"},"counterAccountName":{"type":"string"},"counterAccountNumber":{"type":"string"},"currency":{"type":"string"},"desc":{"type":"string"},"description":{"type":"string"},"orderCode":{"type":"integer"},"paymentLinkId":{"type":"string"},"reference":{"type":"string"},"transactionDateTime":{"type":"string"},"virtualAccountName":{"type":"string"},"virtualAccountNumber":{"type":"string"}}},"bus-booking_payment-service_internal_model.RefundRequest":{"type":"object","required":["booking_id","reason","refund_amount"],"properties":{"booking_id":{"type":"string"},"reason":{"type":"string","maxLength":500,"minLength":10},"refund_amount":{"type":"integer"}}},"bus-booking_payment-service_internal_model.RefundResponse":{"type":"object","properties":{"account_holder":{"type":"string"},"account_number":{"type":"string"},"bank_code":{"description":"User bank account info (for export)","type":"string"},"bank_name":{"type":"string"},"booking_id":{"type":"string"},"created_at":{"type":"string"},"id":{"type":"string"},"original_transaction_id":{"description":"For compatibility","type":"string"},"processed_at":{"type":"string"},"processed_by":{"type":"string"},"refund_amount":{"type":"integer"},"refund_reason":{"type":"string"},"refund_status":{"$ref":"#/definitions/bus-booking_payment-service_internal_model.RefundStatus"},"updated_at":{"type":"string"},"user_id":{"type":"string"}}},"bus-booking_payment-service_internal_model.RefundStatus":{"type":"string","enum":["PENDING","PROCESSING","COMPLETED","REJECTED"],"x-enum-varnames":["RefundStatusPending","RefundStatusProcessing","RefundStatusCompleted","RefundStatusRejected"]},"bus-booking_payment-service_internal_model.TransactionResponse":{"type":"object","properties":{"amount":{"type":"integer"},"booking_id":{"type":"string"},"checkout_url":{"type":"string"},"created_at":{"type":"string"},"currency":{"$ref":"#/definitions/bus-booking_payment-service_internal_model.Currency"},"id":{"type":"string"},"order_code":{"type":"integer"},"payment_method":{"$ref":"#/definitions/bus-booking_payment-service_internal_model.PaymentMethod"},"qr_code":{"type":"string"},"status":{"$ref":"#/definitions/bus-booking_payment-service_internal_model.TransactionStatus"},"updated_at":{"type":"string"},"user_id":{"type":"string"}}},"bus-booking_payment-service_internal_model.TransactionStatus":{"type":"string","enum":["PENDING","CANCELLED","UNDERPAID","PAID","EXPIRED","PROCESSING","FAILED"],"x-enum-varnames":["TransactionStatusPending","TransactionStatusCancelled","TransactionStatusUnderpaid","TransactionStatusPaid","TransactionStatusExpired","TransactionStatusProcessing","TransactionStatusFailed"]},"bus-booking_payment-service_internal_model.UpdateRefundStatusRequest":{"type":"object","required":["status"],"properties":{"status":{"enum":["PROCESSING","COMPLETED","REJECTED"],"allOf":[{"$ref":"#/definitions/bus-booking_payment-service_internal_model.RefundStatus"}]}}},"ginext.ErrorBody":{"type":"object","properties":{"message":{"type":"string"}}},"ginext.MetaData":{"type":"object","properties":{"page":{"type":"integer"},"page_size":{"type":"integer"},"total":{"type":"integer"},"total_pages":{"type":"integer"}}},"ginext.Response":{"type":"object","properties":{"code":{"type":"integer"},"data":{},"error":{"$ref":"#/definitions/ginext.ErrorBody"},"header":{"$ref":"#/definitions/http.Header"},"message":{"type":"string"},"meta":{"$ref":"#/definitions/ginext.MetaData"}}},"http.Header":{"type":"object","additionalProperties":{"type":"array","items":{"type":"string"}}}},"securityDefinitions":{"BearerAuth":{"description":"Type \"Bearer\" followed by a space and JWT token.","type":"apiKey","name":"Authorization","in":"header"}}}`
+    "schemes": {{ marshal .Schemes }},"swagger":"2.0","info":{"description":"{{escape .Description}}","title":"{{.Title}}","contact":{"name":"API Support","email":"support@busbooking.com"},"license":{"name":"MIT","url":"https://opensource.org/licenses/MIT"},"version":"{{.Version}}"},"host":"{{.Host}}","basePath":"{{.BasePath}}","paths":{"/api/v1/bank-accounts":{"get":{"description":"Get all bank accounts of the authenticated user","consumes":["application/json"],"produces":["application/json"],"tags":["bank-accounts"],"summary":"Get my bank accounts","responses":{"200":{"description":"OK","schema":{"allOf":[{"$ref":"#/definitions/ginext.Response"},{"type":"object","properties":{"data":{"type":"array","items":{"$ref":"#/definitions/bus-booking_payment-service_internal_model.BankAccountResponse"}}}}]}},"401":{"description":"Unauthorized","schema":{"$ref":"#/definitions/ginext.Response"}},"500":{"description":"Internal Server Error","schema":{"$ref":"#/definitions/ginext.Response"}}}},"post":{"description":"Create a new bank account for the authenticated user","consumes":["application/json"],"produces":["application/json"],"tags":["bank-accounts"],"summary":"Create a bank account","parameters":[{"description":"Bank account details","name":"account","in":"body","required":true,"schema":{"$ref":"#/definitions/bus-booking_payment-service_internal_model.BankAccountRequest"}}],"responses":{"201":{"description":"Created","schema":{"allOf":[{"$ref":"#/definitions/ginext.Response"},{"type":"object","properties":{"data":{"$ref":"#/definitions/bus-booking_payment-service_internal_model.BankAccountResponse"}}}]}},"400":{"description":"Bad Request","schema":{"$ref":"#/definitions/ginext.Response"}},"401":{"description":"Unauthorized","schema":{"$ref":"#/definitions/ginext.Response"}},"500":{"description":"Internal Server Error","schema":{"$ref":"#/definitions/ginext.Response"}}}}},"/api/v1/bank-accounts/{id}":{"put":{"description":"Update a bank account of the authenticated user","consumes":["application/json"],"produces":["application/json"],"tags":["bank-accounts"],"summary":"Update a bank account","parameters":[{"type":"string","description":"Bank Account ID","name":"id","in":"path","required":true},{"description":"Bank account details","name":"account","in":"body","required":true,"schema":{"$ref":"#/definitions/bus-booking_payment-service_internal_model.BankAccountRequest"}}],"responses":{"200":{"description":"OK","schema":{"allOf":[{"$ref":"#/definitions/ginext.Response"},{"type":"object","properties":{"data":{"$ref":"#/definitions/bus-booking_payment-service_internal_model.BankAccountResponse"}}}]}},"400":{"description":"Bad Request","schema":{"$ref":"#/definitions/ginext.Response"}},"401":{"description":"Unauthorized","schema":{"$ref":"#/definitions/ginext.Response"}},"404":{"description":"Not Found","schema":{"$ref":"#/definitions/ginext.Response"}},"500":{"description":"Internal Server Error","schema":{"$ref":"#/definitions/ginext.Response"}}}},"delete":{"description":"Delete a bank account of the authenticated user","consumes":["application/json"],"produces":["application/json"],"tags":["bank-accounts"],"summary":"Delete a bank account","parameters":[{"type":"string","description":"Bank Account ID","name":"id","in":"path","required":true}],"responses":{"200":{"description":"OK","schema":{"$ref":"#/definitions/ginext.Response"}},"400":{"description":"Bad Request","schema":{"$ref":"#/definitions/ginext.Response"}},"401":{"description":"Unauthorized","schema":{"$ref":"#/definitions/ginext.Response"}},"404":{"description":"Not Found","schema":{"$ref":"#/definitions/ginext.Response"}},"500":{"description":"Internal Server Error","schema":{"$ref":"#/definitions/ginext.Response"}}}}},"/api/v1/bank-accounts/{id}/set-primary":{"post":{"description":"Set a bank account as the primary account for refunds","consumes":["application/json"],"produces":["application/json"],"tags":["bank-accounts"],"summary":"Set primary bank account","parameters":[{"type":"string","description":"Bank Account ID","name":"id","in":"path","required":true}],"responses":{"200":{"description":"OK","schema":{"$ref":"#/definitions/ginext.Response"}},"400":{"description":"Bad Request","schema":{"$ref":"#/definitions/ginext.Response"}},"401":{"description":"Unauthorized","schema":{"$ref":"#/definitions/ginext.Response"}},"404":{"description":"Not Found","schema":{"$ref":"#/definitions/ginext.Response"}},"500":{"description":"Internal Server Error","schema":{"$ref":"#/definitions/ginext.Response"}}}}},"/api/v1/constants":{"get":{"description":"Get constants by type (banks). Returns all types if type parameter is not specified.","consumes":["application/json"],"produces":["application/json"],"tags":["constants"],"summary":"Get constants","parameters":[{"enum":["banks"],"type":"string","description":"Constant type","name":"type","in":"query"}],"responses":{"200":{"description":"Constants","schema":{"$ref":"#/definitions/ginext.Response"}},"400":{"description":"Invalid type parameter","schema":{"$ref":"#/definitions/ginext.Response"}},"500":{"description":"Internal server error","schema":{"$ref":"#/definitions/ginext.Response"}}}}},"/api/v1/refunds":{"get":{"description":"List refund transactions with filters","consumes":["application/json"],"produces":["application/json"],"tags":["admin"],"summary":"List refunds (Admin)","parameters":[{"enum":["PENDING","PROCESSING","COMPLETED","REJECTED"],"type":"string","description":"Refund status","name":"status","in":"query"},{"type":"string","description":"Start date (RFC3339)","name":"start_date","in":"query"},{"type":"string","description":"End date (RFC3339)","name":"end_date","in":"query"},{"type":"integer","default":20,"description":"Limit","name":"limit","in":"query"},{"type":"integer","default":0,"description":"Offset","name":"offset","in":"query"}],"responses":{"200":{"description":"OK","schema":{"$ref":"#/definitions/ginext.Response"}},"400":{"description":"Bad Request","schema":{"$ref":"#/definitions/ginext.Response"}},"401":{"description":"Unauthorized","schema":{"$ref":"#/definitions/ginext.Response"}},"403":{"description":"Forbidden","schema":{"$ref":"#/definitions/ginext.Response"}},"500":{"description":"Internal Server Error","schema":{"$ref":"#/definitions/ginext.Response"}}}},"post":{"description":"Create a refund request for a cancelled booking","consumes":["application/json"],"produces":["application/json"],"tags":["refunds"],"summary":"Create a refund request","parameters":[{"description":"Refund request","name":"refund","in":"body","required":true,"schema":{"$ref":"#/definitions/bus-booking_payment-service_internal_model.RefundRequest"}}],"responses":{"201":{"description":"Created","schema":{"allOf":[{"$ref":"#/definitions/ginext.Response"},{"type":"object","properties":{"data":{"$ref":"#/definitions/bus-booking_payment-service_internal_model.RefundResponse"}}}]}},"400":{"description":"Bad Request","schema":{"$ref":"#/definitions/ginext.Response"}},"401":{"description":"Unauthorized","schema":{"$ref":"#/definitions/ginext.Response"}},"500":{"description":"Internal Server Error","schema":{"$ref":"#/definitions/ginext.Response"}}}}},"/api/v1/refunds/booking/{booking_id}":{"get":{"description":"Get every refund of a specific booking, newest first","consumes":["application/json"],"produces":["application/json"],"tags":["refunds"],"summary":"Get refunds by booking ID","parameters":[{"type":"string","description":"Booking ID","name":"booking_id","in":"path","required":true}],"responses":{"200":{"description":"OK","schema":{"allOf":[{"$ref":"#/definitions/ginext.Response"},{"type":"object","properties":{"data":{"type":"array","items":{"$ref":"#/definitions/bus-booking_payment-service_internal_model.RefundResponse"}}}}]}},"400":{"description":"Bad Request","schema":{"$ref":"#/definitions/ginext.Response"}},"401":{"description":"Unauthorized","schema":{"$ref":"#/definitions/ginext.Response"}},"403":{"description":"Forbidden","schema":{"$ref":"#/definitions/ginext.Response"}},"500":{"description":"Internal Server Error","schema":{"$ref":"#/definitions/ginext.Response"}}}}},"/api/v1/refunds/export":{"post":{"description":"Export selected refund transactions to an Excel file","consumes":["application/json"],"produces":["application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"],"tags":["admin"],"summary":"Export refunds to Excel (Admin)","parameters":[{"description":"Export request","name":"request","in":"body","required":true,"schema":{"$ref":"#/definitions/bus-booking_payment-service_internal_model.ExportRefundsRequest"}}],"responses":{"200":{"description":"Excel file","schema":{"type":"file"}},"400":{"description":"Bad Request","schema":{"$ref":"#/definitions/ginext.Response"}},"401":{"description":"Unauthorized","schema":{"$ref":"#/definitions/ginext.Response"}},"403":{"description":"Forbidden","schema":{"$ref":"#/definitions/ginext.Response"}},"404":{"description":"Not Found","schema":{"$ref":"#/definitions/ginext.Response"}},"500":{"description":"Internal Server Error","schema":{"$ref":"#/definitions/ginext.Response"}}}}},"/api/v1/refunds/{id}":{"put":{"description":"Update the status of a refund transaction","consumes":["application/json"],"produces":["application/json"],"tags":["admin"],"summary":"Update refund status (Admin)","parameters":[{"type":"string","description":"Refund Transaction ID","name":"id","in":"path","required":true},{"description":"Status update request","name":"request","in":"body","required":true,"schema":{"$ref":"#/definitions/bus-booking_payment-service_internal_model.UpdateRefundStatusRequest"}}],"responses":{"200":{"description":"OK","schema":{"$ref":"#/definitions/ginext.Response"}},"400":{"description":"Bad Request","schema":{"$ref":"#/definitions/ginext.Response"}},"401":{"description":"Unauthorized","schema":{"$ref":"#/definitions/ginext.Response"}},"403":{"description":"Forbidden","schema":{"$ref":"#/definitions/ginext.Response"}},"404":{"description":"Not Found","schema":{"$ref":"#/definitions/ginext.Response"}},"500":{"description":"Internal Server Error","schema":{"$ref":"#/definitions/ginext.Response"}}}}},"/api/v1/transactions":{"get":{"description":"List all transactions with filters","consumes":["application/json"],"produces":["application/json"],"tags":["admin"],"summary":"List all transactions (Admin)","parameters":[{"enum":["IN","OUT"],"type":"string","description":"Transaction type","name":"transaction_type","in":"query"},{"type":"string","description":"Transaction status","name":"status","in":"query"},{"type":"string","description":"Refund status","name":"refund_status","in":"query"},{"type":"string","description":"Start date (RFC3339)","name":"start_date","in":"query"},{"type":"string","description":"End date (RFC3339)","name":"end_date","in":"query"},{"type":"integer","default":1,"description":"Page number","name":"page","in":"query"},{"type":"integer","default":20,"description":"Page size","name":"page_size","in":"query"}],"responses":{"200":{"description":"OK","schema":{"$ref":"#/definitions/ginext.Response"}},"400":{"description":"Bad Request","schema":{"$ref":"#/definitions/ginext.Response"}},"401":{"description":"Unauthorized","schema":{"$ref":"#/definitions/ginext.Response"}},"403":{"description":"Forbidden","schema":{"$ref":"#/definitions/ginext.Response"}},"500":{"description":"Internal Server Error","schema":{"$ref":"#/definitions/ginext.Response"}}}},"post":{"description":"Create a payment link via PayOS for a booking","consumes":["application/json"],"produces":["application/json"],"tags":["transactions"],"summary":"Create a payment link","parameters":[{"description":"Payment creation request","name":"transaction","in":"body","required":true,"schema":{"$ref":"#/definitions/bus-booking_payment-service_internal_model.CreateTransactionRequest"}}],"responses":{"201":{"description":"Created","schema":{"allOf":[{"$ref":"#/definitions/ginext.Response"},{"type":"object","properties":{"data":{"$ref":"#/definitions/bus-booking_payment-service_internal_model.TransactionResponse"}}}]}},"400":{"description":"Bad Request","schema":{"$ref":"#/definitions/ginext.Response"}},"500":{"description":"Internal Server Error","schema":{"$ref":"#/definitions/ginext.Response"}}}}},"/api/v1/transactions/stats":{"get":{"description":"Get transaction stats with filters","consumes":["application/json"],"produces":["application/json"],"tags":["admin"],"summary":"Get transaction stats (Admin)","parameters":[{"enum":["IN","OUT"],"type":"string","description":"Transaction type","name":"transaction_type","in":"query"},{"type":"string","description":"Transaction status","name":"status","in":"query"},{"type":"string","description":"Refund status","name":"refund_status","in":"query"},{"type":"string","description":"Start date (RFC3339)","name":"start_date","in":"query"},{"type":"string","description":"End date (RFC3339)","name":"end_date","in":"query"}],"responses":{"200":{"description":"OK","schema":{"$ref":"#/definitions/ginext.Response"}},"400":{"description":"Bad Request","schema":{"$ref":"#/definitions/ginext.Response"}},"401":{"description":"Unauthorized","schema":{"$ref":"#/definitions/ginext.Response"}},"403":{"description":"Forbidden","schema":{"$ref":"#/definitions/ginext.Response"}},"500":{"description":"Internal Server Error","schema":{"$ref":"#/definitions/ginext.Response"}}}}},"/api/v1/transactions/webhook":{"post":{"description":"Handle payment webhook notification from PayOS","consumes":["application/json"],"produces":["application/json"],"tags":["transactions"],"summary":"Handle PayOS webhook","parameters":[{"description":"Webhook payload","name":"webhook","in":"body","required":true,"schema":{"$ref":"#/definitions/bus-booking_payment-service_internal_model.PaymentWebhookData"}}],"responses":{"200":{"description":"OK","schema":{"$ref":"#/definitions/ginext.Response"}},"400":{"description":"Bad Request","schema":{"$ref":"#/definitions/ginext.Response"}},"500":{"description":"Internal Server Error","schema":{"$ref":"#/definitions/ginext.Response"}}}}},"/api/v1/transactions/{id}":{"get":{"description":"Retrieve transaction details by transaction ID","consumes":["application/json"],"produces":["application/json"],"tags":["transactions"],"summary":"Get transaction by ID","parameters":[{"type":"string","description":"Transaction ID","name":"id","in":"path","required":true}],"responses":{"200":{"description":"OK","schema":{"allOf":[{"$ref":"#/definitions/ginext.Response"},{"type":"object","properties":{"data":{"$ref":"#/definitions/bus-booking_payment-service_internal_model.TransactionResponse"}}}]}},"400":{"description":"Bad Request","schema":{"$ref":"#/definitions/ginext.Response"}},"404":{"description":"Not Found","schema":{"$ref":"#/definitions/ginext.Response"}},"500":{"description":"Internal Server Error","schema":{"$ref":"#/definitions/ginext.Response"}}}}},"/api/v1/transactions/{id}/cancel":{"post":{"description":"Cancel a payment transaction and PayOS payment link","consumes":["application/json"],"produces":["application/json"],"tags":["transactions"],"summary":"Cancel a payment","parameters":[{"type":"string","description":"Transaction ID","name":"id","in":"path","required":true}],"responses":{"200":{"description":"OK","schema":{"$ref":"#/definitions/ginext.Response"}},"400":{"description":"Bad Request","schema":{"$ref":"#/definitions/ginext.Response"}},"404":{"description":"Not Found","schema":{"$ref":"#/definitions/ginext.Response"}},"500":{"description":"Internal Server Error","schema":{"$ref":"#/definitions/ginext.Response"}}}}}},"definitions":{"bus-booking_payment-service_internal_model.BankAccountRequest":{"type":"object","required":["account_holder","account_number","bank_code"],"properties":{"account_holder":{"type":"string","maxLength":100,"minLength":1},"account_number":{"type":"string"},"bank_code":{"type":"string"}}},"bus-booking_payment-service_internal_model.BankAccountResponse":{"type":"object","properties":{"account_holder":{"type":"string"},"account_number":{"type":"string"},"bank_code":{"type":"string"},"bank_name":{"description":"Resolved from BankCode","type":"string"},"created_at":{"type":"string"},"id":{"type":"string"},"is_primary":{"type":"boolean"},"updated_at":{"type":"string"},"user_id":{"type":"string"}}},"bus-booking_payment-service_internal_model.CreateTransactionRequest":{"type":"object","required":["amount","booking_id","currency","payment_method"],"properties":{"amount":{"type":"integer"},"booking_id":{"type":"string"},"currency":{"$ref":"#/definitions/bus-booking_payment-service_internal_model.Currency"},"description":{"type":"string"},"expires_at":{"type":"string"},"id":{"type":"string"},"payment_method":{"$ref":"#/definitions/bus-booking_payment-service_internal_model.PaymentMethod"}}},"bus-booking_payment-service_internal_model.Currency":{"type":"string","enum":["VND"],"x-enum-varnames":["CurrencyVND"]},"bus-booking_payment-service_internal_model.ExportRefundsRequest":{"type":"object","required":["refund_ids"],"properties":{"refund_ids":{"type":"array","minItems":1,"items":{"type":"string"}}}},"bus-booking_payment-service_internal_model.PaymentMethod":{"type":"string","enum":["PAYOS"],"x-enum-varnames":["PaymentMethodPayOS"]},"bus-booking_payment-service_internal_model.PaymentWebhookData":{"type":"object","properties":{"code":{"type":"string"},"data":{"$ref":"#/definitions/bus-booking_payment-service_internal_model.PaymentWebhookDetails"},"desc":{"type":"string"},"signature":{"type":"string"},"success":{"type":"boolean"}}},"bus-booking_payment-service_internal_model.PaymentWebhookDetails":{"type":"object","properties":{"accountNumber":{"type":"string"},"amount":{"type":"integer"},"code":{"type":"string"},"counterAccountBankId":{"type":"string"},"counterAccountBankName":{"type":"string"},"counterAccountName":{"type":"string"},"counterAccountNumber":{"type":"string"},"currency":{"type":"string"},"desc":{"type":"string"},"description":{"type":"string"},"orderCode":{"type":"integer"},"paymentLinkId":{"type":"string"},"reference":{"type":"string"},"transactionDateTime":{"type":"string"},"virtualAccountName":{"type":"string"},"virtualAccountNumber":{"type":"string"}}},"bus-booking_payment-service_internal_model.RefundRequest":{"type":"object","required":["booking_id","reason","refund_amount"],"properties":{"booking_id":{"type":"string"},"reason":{"type":"string","maxLength":500,"minLength":10},"refund_amount":{"type":"integer"}}},"bus-booking_payment-service_internal_model.RefundResponse":{"type":"object","properties":{"account_holder":{"type":"string"},"account_number":{"type":"string"},"bank_code":{"description":"User bank account info (for export)","type":"string"},"bank_name":{"type":"string"},"booking_id":{"type":"string"},"created_at":{"type":"string"},"id":{"type":"string"},"original_transaction_id":{"description":"For compatibility","type":"string"},"processed_at":{"type":"string"},"processed_by":{"type":"string"},"refund_amount":{"type":"integer"},"refund_reason":{"type":"string"},"refund_status":{"$ref":"#/definitions/bus-booking_payment-service_internal_model.RefundStatus"},"updated_at":{"type":"string"},"user_id":{"type":"string"}}},"bus-booking_payment-service_internal_model.RefundStatus":{"type":"string","enum":["PENDING","PROCESSING","COMPLETED","REJECTED"],"x-enum-varnames":["RefundStatusPending","RefundStatusProcessing","RefundStatusCompleted","RefundStatusRejected"]},"bus-booking_payment-service_internal_model.TransactionResponse":{"type":"object","properties":{"amount":{"type":"integer"},"booking_id":{"type":"string"},"checkout_url":{"type":"string"},"created_at":{"type":"string"},"currency":{"$ref":"#/definitions/bus-booking_payment-service_internal_model.Currency"},"id":{"type":"string"},"order_code":{"type":"integer"},"payment_method":{"$ref":"#/definitions/bus-booking_payment-service_internal_model.PaymentMethod"},"qr_code":{"type":"string"},"status":{"$ref":"#/definitions/bus-booking_payment-service_internal_model.TransactionStatus"},"updated_at":{"type":"string"},"user_id":{"type":"string"}}},"bus-booking_payment-service_internal_model.TransactionStatus":{"type":"string","enum":["PENDING","CANCELLED","UNDERPAID","PAID","EXPIRED","PROCESSING","FAILED"],"x-enum-varnames":["TransactionStatusPending","TransactionStatusCancelled","TransactionStatusUnderpaid","TransactionStatusPaid","TransactionStatusExpired","TransactionStatusProcessing","TransactionStatusFailed"]},"bus-booking_payment-service_internal_model.UpdateRefundStatusRequest":{"type":"object","required":["status"],"properties":{"status":{"enum":["PROCESSING","COMPLETED","REJECTED"],"allOf":[{"$ref":"#/definitions/bus-booking_payment-service_internal_model.RefundStatus"}]}}},"ginext.ErrorBody":{"type":"object","properties":{"message":{"type":"string"}}},"ginext.MetaData":{"type":"object","properties":{"page":{"type":"integer"},"page_size":{"type":"integer"},"total":{"type":"integer"},"total_pages":{"type":"integer"}}},"ginext.Response":{"type":"object","properties":{"code":{"type":"integer"},"data":{},"error":{"$ref":"#/definitions/ginext.ErrorBody"},"header":{"$ref":"#/definitions/http.Header"},"message":{"type":"string"},"meta":{"$ref":"#/definitions/ginext.MetaData"}}},"http.Header":{"type":"object","additionalProperties":{"type":"array","items":{"type":"string"}}}},"securityDefinitions":{"BearerAuth":{"description":"Type \"Bearer\" followed by a space and JWT token.","type":"apiKey","name":"Authorization","in":"header"}}}`
 
 // SwaggerInfo holds exported Swagger Info so clients can modify it
 var SwaggerInfo = &swag.Spec{
@@ -8,6 +8,7 @@ require (
 	bus-booking/shared v0.0.0-00010101000000-000000000000
 	github.com/gin-gonic/gin v1.11.0
 	github.com/google/uuid v1.6.0
+	github.com/jung-kurt/gofpdf v1.16.2
 	github.com/payOSHQ/payos-lib-golang/v2 v2.0.1
 	github.com/rs/zerolog v1.34.0
 	github.com/swaggo/files v1.0.1
@@ -15,6 +16,7 @@ require (
 	github.com/swaggo/swag/v2 v2.0.0-rc4
 	github.com/xuri/excelize/v2 v2.10.0
 	golang.org/x/sync v0.18.0
+	golang.org/x/text v0.30.0
 	gorm.io/gorm v1.31.1
 )
 
@@ -78,7 +80,6 @@ require (
 	golang.org/x/mod v0.28.0 // indirect
 	golang.org/x/net v0.46.0 // indirect
 	golang.org/x/sys v0.37.0 // indirect
-	golang.org/x/text v0.30.0 // indirect
 	golang.org/x/tools v0.37.0 // indirect
 	google.golang.org/protobuf v1.36.10 // indirect
 	gopkg.in/natefinch/lumberjack.v2 v2.2.1 // indirect
@@ -30,9 +30,12 @@ func NewBookingClient(serviceName, baseURL string) BookingClient {
 
 func (c *BookingClientImpl) UpdateBookingStatus(ctx context.Context, req *booking.UpdateBookingStatusRequest, bookingID uuid.UUID) error {
 	endpoint := fmt.Sprintf("/api/v1/bookings/%s/status", bookingID.String())
-	_, err := c.http.Put(ctx, endpoint, req, nil)
+	resp, err := c.http.Put(ctx, endpoint, req, nil)
 	if err != nil {
 		return fmt.Errorf("failed to update booking payment status: %w", err)
 	}
+	if !resp.IsSuccess() {
+		return fmt.Errorf("failed to update booking payment status: booking service responded %d", resp.StatusCode)
+	}
 	return nil
 }
@@ -0,0 +1,45 @@
+package client
+
+import (
+	"bus-booking/payment-service/internal/model/notification"
+	"bus-booking/shared/client"
+	"context"
+	"fmt"
+)
+
+type NotificationClient interface {
+	SendRefundSent(ctx context.Context, req *notification.RefundSentRequest) error
+}
+
+type NotificationClientImpl struct {
+	http client.HTTPClient
+}
+
+func NewNotificationClient(serviceName, baseURL string) NotificationClient {
+	httpClient := client.NewHTTPClient(&client.Config{
+		ServiceName: serviceName,
+		BaseURL:     baseURL,
+	})
+
+	return &NotificationClientImpl{
+		http: httpClient,
+	}
+}
+
+func (c *NotificationClientImpl) SendRefundSent(ctx context.Context, req *notification.RefundSentRequest) error {
+	return c.send(ctx, notification.NotificationTypeRefundSent, req)
+}
+
+func (c *NotificationClientImpl) send(ctx context.Context, notificationType notification.NotificationType, payload interface{}) error {
+	resp, err := c.http.Post(ctx, "/api/v1/notifications", &notification.GenericNotificationRequest{
+		Type:    notificationType,
+		Payload: payload,
+	}, nil)
+	if err != nil {
+		return fmt.Errorf("failed to send %s notification: %w", notificationType, err)
+	}
+	if !resp.IsSuccess() {
+		return fmt.Errorf("failed to send %s notification: notification service responded %d", notificationType, resp.StatusCode)
+	}
+	return nil
+}
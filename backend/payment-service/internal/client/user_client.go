@@ -0,0 +1,46 @@
+package client
+
+import (
+	"bus-booking/payment-service/internal/model/user"
+	"bus-booking/shared/client"
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+type UserClient interface {
+	GetUserByID(ctx context.Context, userID uuid.UUID) (*user.User, error)
+}
+
+type UserClientImpl struct {
+	http client.HTTPClient
+}
+
+func NewUserClient(serviceName, baseURL string) UserClient {
+	httpClient := client.NewHTTPClient(&client.Config{
+		ServiceName: serviceName,
+		BaseURL:     baseURL,
+	})
+
+	return &UserClientImpl{
+		http: httpClient,
+	}
+}
+
+func (c *UserClientImpl) GetUserByID(ctx context.Context, userID uuid.UUID) (*user.User, error) {
+	// Use internal endpoint for service-to-service calls
+	endpoint := fmt.Sprintf("/api/v1/internal/users/%s", userID.String())
+
+	resp, err := c.http.Get(ctx, endpoint, nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	userData, err := client.ParseData[user.User](resp)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse user response: %w", err)
+	}
+
+	return userData, nil
+}
@@ -0,0 +1,72 @@
+package cronjob
+
+import (
+	"context"
+	"time"
+
+	"bus-booking/payment-service/config"
+	"bus-booking/payment-service/internal/service"
+
+	"github.com/rs/zerolog/log"
+)
+
+// ReconciliationCronJob settles payments whose PayOS webhook never arrived
+type ReconciliationCronJob struct {
+	transactionSvc service.TransactionService
+	cfg            config.ReconciliationConfig
+	stopChan       chan struct{}
+}
+
+func NewReconciliationCronJob(transactionSvc service.TransactionService, cfg config.ReconciliationConfig) *ReconciliationCronJob {
+	return &ReconciliationCronJob{
+		transactionSvc: transactionSvc,
+		cfg:            cfg,
+		stopChan:       make(chan struct{}),
+	}
+}
+
+// Start begins the cronjob worker - runs every configured interval
+func (c *ReconciliationCronJob) Start(ctx context.Context) {
+	log.Info().Dur("interval", c.cfg.Interval).Msg("Starting payment reconciliation cronjob worker")
+
+	ticker := time.NewTicker(c.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Info().Msg("Payment reconciliation cronjob context cancelled, stopping...")
+			return
+		case <-c.stopChan:
+			log.Info().Msg("Payment reconciliation cronjob stopped")
+			return
+		case <-ticker.C:
+			c.reconcile(ctx)
+		}
+	}
+}
+
+// Stop stops the cronjob worker
+func (c *ReconciliationCronJob) Stop() {
+	close(c.stopChan)
+}
+
+func (c *ReconciliationCronJob) reconcile(ctx context.Context) {
+	// Young payments are left to their webhook
+	createdBefore := time.Now().Add(-c.cfg.MinAge)
+
+	summary, err := c.transactionSvc.Reconcile(ctx, createdBefore, c.cfg.BatchSize)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to reconcile payments")
+		return
+	}
+
+	if summary.Checked > 0 {
+		log.Info().
+			Int("checked", summary.Checked).
+			Int("updated", summary.Updated).
+			Int("flagged", summary.Flagged).
+			Int("failed", summary.Failed).
+			Msg("Payment reconciliation completed")
+	}
+}
@@ -0,0 +1,68 @@
+package cronjob
+
+import (
+	"context"
+	"time"
+
+	"bus-booking/payment-service/config"
+	"bus-booking/payment-service/internal/service"
+
+	"github.com/rs/zerolog/log"
+)
+
+// WalletExpiryCronJob takes expired promotional credit off the wallets
+type WalletExpiryCronJob struct {
+	walletSvc service.WalletService
+	cfg       config.WalletConfig
+	stopChan  chan struct{}
+}
+
+func NewWalletExpiryCronJob(walletSvc service.WalletService, cfg config.WalletConfig) *WalletExpiryCronJob {
+	return &WalletExpiryCronJob{
+		walletSvc: walletSvc,
+		cfg:       cfg,
+		stopChan:  make(chan struct{}),
+	}
+}
+
+// Start begins the cronjob worker - runs every configured interval
+func (c *WalletExpiryCronJob) Start(ctx context.Context) {
+	log.Info().Dur("interval", c.cfg.ExpiryInterval).Msg("Starting wallet credit expiry cronjob worker")
+
+	ticker := time.NewTicker(c.cfg.ExpiryInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Info().Msg("Wallet credit expiry cronjob context cancelled, stopping...")
+			return
+		case <-c.stopChan:
+			log.Info().Msg("Wallet credit expiry cronjob stopped")
+			return
+		case <-ticker.C:
+			c.expire(ctx)
+		}
+	}
+}
+
+// Stop stops the cronjob worker
+func (c *WalletExpiryCronJob) Stop() {
+	close(c.stopChan)
+}
+
+func (c *WalletExpiryCronJob) expire(ctx context.Context) {
+	summary, err := c.walletSvc.ExpireCredits(ctx, c.cfg.ExpiryBatchSize)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to expire wallet credits")
+		return
+	}
+
+	if summary.Expired > 0 || summary.Failed > 0 {
+		log.Info().
+			Int("expired", summary.Expired).
+			Int("amount", summary.Amount).
+			Int("failed", summary.Failed).
+			Msg("Wallet credit expiry completed")
+	}
+}
@@ -0,0 +1,167 @@
+package handler
+
+import (
+	"bus-booking/payment-service/internal/model"
+	"bus-booking/payment-service/internal/service"
+	sharedcontext "bus-booking/shared/context"
+	"bus-booking/shared/ginext"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+type InvoiceHandler interface {
+	IssueInvoice(r *ginext.Request) (*ginext.Response, error)
+	ListByBookingID(r *ginext.Request) (*ginext.Response, error)
+	DownloadPDF(r *ginext.Request) error
+	DownloadXML(r *ginext.Request) error
+}
+
+type InvoiceHandlerImpl struct {
+	service service.InvoiceService
+}
+
+func NewInvoiceHandler(service service.InvoiceService) InvoiceHandler {
+	return &InvoiceHandlerImpl{
+		service: service,
+	}
+}
+
+// IssueInvoice godoc
+// @Summary Issue a VAT invoice
+// @Description Issue the VAT invoice of a paid booking to the buyer's company
+// @Tags invoices
+// @Accept json
+// @Produce json
+// @Param id path string true "Transaction ID"
+// @Param invoice body model.IssueInvoiceRequest true "Buyer company"
+// @Success 201 {object} ginext.Response{data=model.Invoice}
+// @Failure 400 {object} ginext.Response
+// @Failure 401 {object} ginext.Response
+// @Failure 404 {object} ginext.Response
+// @Failure 409 {object} ginext.Response
+// @Failure 500 {object} ginext.Response
+// @Router /api/v1/transactions/{id}/invoice [post]
+func (h *InvoiceHandlerImpl) IssueInvoice(r *ginext.Request) (*ginext.Response, error) {
+	userID := sharedcontext.GetUserID(r.GinCtx)
+
+	transactionID, err := uuid.Parse(r.GinCtx.Param("id"))
+	if err != nil {
+		return nil, ginext.NewBadRequestError("Invalid transaction ID")
+	}
+
+	var req model.IssueInvoiceRequest
+	if err := r.GinCtx.ShouldBindJSON(&req); err != nil {
+		log.Debug().Err(err).Msg("JSON binding failed")
+		return nil, ginext.NewBadRequestError("Invalid request data")
+	}
+
+	invoice, err := h.service.IssueInvoice(r.Context(), transactionID, userID, &req)
+	if err != nil {
+		return nil, err
+	}
+
+	return ginext.NewCreatedResponse(invoice), nil
+}
+
+// ListByBookingID godoc
+// @Summary List the invoices of a booking
+// @Description List the VAT invoices of a booking, including those cancelled or replaced after refunds
+// @Tags invoices
+// @Accept json
+// @Produce json
+// @Param booking_id path string true "Booking ID"
+// @Success 200 {object} ginext.Response{data=[]model.Invoice}
+// @Failure 400 {object} ginext.Response
+// @Failure 401 {object} ginext.Response
+// @Failure 404 {object} ginext.Response
+// @Failure 500 {object} ginext.Response
+// @Router /api/v1/invoices/booking/{booking_id} [get]
+func (h *InvoiceHandlerImpl) ListByBookingID(r *ginext.Request) (*ginext.Response, error) {
+	userID := sharedcontext.GetUserID(r.GinCtx)
+
+	bookingID, err := uuid.Parse(r.GinCtx.Param("booking_id"))
+	if err != nil {
+		return nil, ginext.NewBadRequestError("Invalid booking ID")
+	}
+
+	invoices, err := h.service.ListByBookingID(r.Context(), bookingID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return ginext.NewSuccessResponse(invoices), nil
+}
+
+// DownloadPDF godoc
+// @Summary Download an invoice as PDF
+// @Tags invoices
+// @Produce application/pdf
+// @Param id path string true "Invoice ID"
+// @Success 200 {file} binary "Invoice PDF"
+// @Failure 400 {object} ginext.Response
+// @Failure 401 {object} ginext.Response
+// @Failure 404 {object} ginext.Response
+// @Failure 500 {object} ginext.Response
+// @Router /api/v1/invoices/{id}/pdf [get]
+func (h *InvoiceHandlerImpl) DownloadPDF(r *ginext.Request) error {
+	return h.download(r, "application/pdf", "pdf", h.service.GeneratePDF)
+}
+
+// DownloadXML godoc
+// @Summary Download an invoice as XML
+// @Description Download the invoice in the XML layout of e-invoices
+// @Tags invoices
+// @Produce application/xml
+// @Param id path string true "Invoice ID"
+// @Success 200 {file} binary "Invoice XML"
+// @Failure 400 {object} ginext.Response
+// @Failure 401 {object} ginext.Response
+// @Failure 404 {object} ginext.Response
+// @Failure 500 {object} ginext.Response
+// @Router /api/v1/invoices/{id}/xml [get]
+func (h *InvoiceHandlerImpl) DownloadXML(r *ginext.Request) error {
+	return h.download(r, "application/xml", "xml", h.service.GenerateXML)
+}
+
+func (h *InvoiceHandlerImpl) download(
+	r *ginext.Request,
+	contentType, extension string,
+	generate func(ctx context.Context, id uuid.UUID, userID uuid.UUID) (*model.Invoice, []byte, error),
+) error {
+	userID := sharedcontext.GetUserID(r.GinCtx)
+
+	id, err := uuid.Parse(r.GinCtx.Param("id"))
+	if err != nil {
+		r.GinCtx.JSON(http.StatusBadRequest, map[string]interface{}{
+			"success": false,
+			"message": "Invalid invoice ID",
+		})
+		return nil
+	}
+
+	invoice, data, err := generate(r.Context(), id, userID)
+	if err != nil {
+		log.Error().Err(err).Str("invoice_id", id.String()).Msg("Failed to generate invoice file")
+		status := http.StatusInternalServerError
+		var apiErr *ginext.Error
+		if errors.As(err, &apiErr) {
+			status = apiErr.Code
+		}
+		r.GinCtx.JSON(status, map[string]interface{}{
+			"success": false,
+			"message": err.Error(),
+		})
+		return nil
+	}
+
+	filename := fmt.Sprintf("invoice_%s_%s.%s", invoice.Series, invoice.InvoiceNumber(), extension)
+	r.GinCtx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%s", filename))
+	r.GinCtx.Data(http.StatusOK, contentType, data)
+
+	return nil
+}
@@ -0,0 +1,204 @@
+package handler
+
+import (
+	"bus-booking/payment-service/internal/model"
+	"bus-booking/payment-service/internal/service"
+	sharedcontext "bus-booking/shared/context"
+	"bus-booking/shared/ginext"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/rs/zerolog/log"
+)
+
+type LedgerHandler interface {
+	ListJournals(r *ginext.Request) (*ginext.Response, error)
+	CreateAdjustment(r *ginext.Request) (*ginext.Response, error)
+	GetSettlementReport(r *ginext.Request) (*ginext.Response, error)
+	ExportSettlementReport(r *ginext.Request) error
+}
+
+type LedgerHandlerImpl struct {
+	service service.LedgerService
+}
+
+func NewLedgerHandler(service service.LedgerService) LedgerHandler {
+	return &LedgerHandlerImpl{
+		service: service,
+	}
+}
+
+// ListJournals godoc
+// @Summary List ledger journals (Admin)
+// @Description List the double-entry postings of charges, provider fees, refunds and adjustments, newest first
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(20)
+// @Param kind query string false "Journal kind" Enums(CHARGE, FEE, REFUND, ADJUSTMENT)
+// @Param operator_id query string false "Operator ID" format(uuid)
+// @Param payment_method query string false "Payment method" Enums(PAYOS, CASH, SANDBOX)
+// @Param transaction_id query string false "Transaction ID" format(uuid)
+// @Param start_date query string false "Start date (RFC3339)"
+// @Param end_date query string false "End date (RFC3339)"
+// @Success 200 {object} ginext.Response
+// @Failure 400 {object} ginext.Response
+// @Failure 401 {object} ginext.Response
+// @Failure 403 {object} ginext.Response
+// @Failure 500 {object} ginext.Response
+// @Router /api/v1/ledger/journals [get]
+func (h *LedgerHandlerImpl) ListJournals(r *ginext.Request) (*ginext.Response, error) {
+	var query model.LedgerJournalListQuery
+	if err := r.GinCtx.ShouldBindQuery(&query); err != nil {
+		log.Debug().Err(err).Msg("Query binding failed")
+		return nil, ginext.NewBadRequestError("Invalid query parameters")
+	}
+
+	// Normalize defaults
+	query.Normalize()
+
+	journals, total, err := h.service.ListJournals(r.Context(), &query)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to list ledger journals")
+		return nil, err
+	}
+
+	return ginext.NewPaginatedResponse(journals, query.Page, query.PageSize, total), nil
+}
+
+// CreateAdjustment godoc
+// @Summary Post a ledger adjustment (Admin)
+// @Description Correct the money held by a payment method, e.g. after a bank statement check. A positive amount adds to the balance, a negative one takes from it.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param request body model.CreateLedgerAdjustmentRequest true "Adjustment"
+// @Success 201 {object} ginext.Response
+// @Failure 400 {object} ginext.Response
+// @Failure 401 {object} ginext.Response
+// @Failure 403 {object} ginext.Response
+// @Failure 500 {object} ginext.Response
+// @Router /api/v1/ledger/adjustments [post]
+func (h *LedgerHandlerImpl) CreateAdjustment(r *ginext.Request) (*ginext.Response, error) {
+	adminID := sharedcontext.GetUserID(r.GinCtx)
+
+	var req model.CreateLedgerAdjustmentRequest
+	if err := r.GinCtx.ShouldBindJSON(&req); err != nil {
+		log.Debug().Err(err).Msg("JSON binding failed")
+		return nil, ginext.NewBadRequestError("Invalid request data")
+	}
+
+	journal, err := h.service.CreateAdjustment(r.Context(), &req, adminID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to post ledger adjustment")
+		return nil, err
+	}
+
+	return ginext.NewCreatedResponse(journal), nil
+}
+
+// GetSettlementReport godoc
+// @Summary Get the daily settlement report (Admin)
+// @Description Opening balance, charges, refunds, fees, adjustments and closing balance of the money held by each payment method, per day and operator
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param from query string true "First day (YYYY-MM-DD)"
+// @Param to query string true "Last day (YYYY-MM-DD)"
+// @Param operator_id query string false "Operator ID" format(uuid)
+// @Param payment_method query string false "Payment method" Enums(PAYOS, CASH, SANDBOX)
+// @Success 200 {object} ginext.Response{data=model.SettlementReport}
+// @Failure 400 {object} ginext.Response
+// @Failure 401 {object} ginext.Response
+// @Failure 403 {object} ginext.Response
+// @Failure 500 {object} ginext.Response
+// @Router /api/v1/ledger/settlements [get]
+func (h *LedgerHandlerImpl) GetSettlementReport(r *ginext.Request) (*ginext.Response, error) {
+	var query model.SettlementReportQuery
+	if err := r.GinCtx.ShouldBindQuery(&query); err != nil {
+		log.Debug().Err(err).Msg("Query binding failed")
+		return nil, ginext.NewBadRequestError("Invalid query parameters")
+	}
+
+	report, err := h.service.GetSettlementReport(r.Context(), &query)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get settlement report")
+		return nil, err
+	}
+
+	return ginext.NewSuccessResponse(report), nil
+}
+
+// ExportSettlementReport godoc
+// @Summary Export the daily settlement report (Admin)
+// @Description Download the daily settlement report as CSV or as an Excel file
+// @Tags admin
+// @Accept json
+// @Produce text/csv
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param from query string true "First day (YYYY-MM-DD)"
+// @Param to query string true "Last day (YYYY-MM-DD)"
+// @Param operator_id query string false "Operator ID" format(uuid)
+// @Param payment_method query string false "Payment method" Enums(PAYOS, CASH, SANDBOX)
+// @Param format query string false "File format" Enums(csv, xlsx) default(csv)
+// @Success 200 {file} binary "Settlement report"
+// @Failure 400 {object} ginext.Response
+// @Failure 401 {object} ginext.Response
+// @Failure 403 {object} ginext.Response
+// @Failure 500 {object} ginext.Response
+// @Router /api/v1/ledger/settlements/export [get]
+func (h *LedgerHandlerImpl) ExportSettlementReport(r *ginext.Request) error {
+	var query model.SettlementReportQuery
+	if err := r.GinCtx.ShouldBindQuery(&query); err != nil {
+		log.Debug().Err(err).Msg("Query binding failed")
+		r.GinCtx.JSON(http.StatusBadRequest, map[string]interface{}{
+			"success": false,
+			"message": "Invalid query parameters",
+		})
+		return nil
+	}
+
+	var (
+		data        []byte
+		contentType string
+		extension   string
+		err         error
+	)
+	if query.Format == "xlsx" {
+		data, err = h.service.ExportSettlementReportExcel(r.Context(), &query)
+		contentType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+		extension = "xlsx"
+	} else {
+		data, err = h.service.ExportSettlementReportCSV(r.Context(), &query)
+		contentType = "text/csv; charset=utf-8"
+		extension = "csv"
+	}
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to export settlement report")
+		status := http.StatusInternalServerError
+		var apiErr *ginext.Error
+		if errors.As(err, &apiErr) {
+			status = apiErr.Code
+		}
+		r.GinCtx.JSON(status, map[string]interface{}{
+			"success": false,
+			"message": err.Error(),
+		})
+		return nil
+	}
+
+	// Set headers for file download
+	filename := fmt.Sprintf("settlement_%s_%s.%s", query.From.Format("20060102"), query.To.Format("20060102"), extension)
+	r.GinCtx.Header("Content-Description", "File Transfer")
+	r.GinCtx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%s", filename))
+	r.GinCtx.Header("Content-Type", contentType)
+	r.GinCtx.Header("Expires", "0")
+	r.GinCtx.Header("Cache-Control", "must-revalidate")
+	r.GinCtx.Header("Pragma", "public")
+
+	r.GinCtx.Data(http.StatusOK, contentType, data)
+
+	return nil
+}
@@ -0,0 +1,101 @@
+package handler
+
+import (
+	"bus-booking/payment-service/internal/model"
+	"bus-booking/payment-service/internal/service"
+	sharedcontext "bus-booking/shared/context"
+	"bus-booking/shared/ginext"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+type ReconciliationHandler interface {
+	ListIssues(r *ginext.Request) (*ginext.Response, error)
+	ResolveIssue(r *ginext.Request) (*ginext.Response, error)
+}
+
+type ReconciliationHandlerImpl struct {
+	service service.ReconciliationService
+}
+
+func NewReconciliationHandler(service service.ReconciliationService) ReconciliationHandler {
+	return &ReconciliationHandlerImpl{
+		service: service,
+	}
+}
+
+// ListIssues godoc
+// @Summary List payment reconciliation issues (Admin)
+// @Description List provider payments that were flagged for review instead of being applied to their booking
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param status query string false "Issue status" Enums(OPEN, RESOLVED)
+// @Param issue_type query string false "Issue type" Enums(PAID_AFTER_EXPIRED, PAID_AFTER_CANCELLED)
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(20)
+// @Success 200 {object} ginext.Response
+// @Failure 400 {object} ginext.Response
+// @Failure 401 {object} ginext.Response
+// @Failure 403 {object} ginext.Response
+// @Failure 500 {object} ginext.Response
+// @Router /api/v1/reconciliation/issues [get]
+func (h *ReconciliationHandlerImpl) ListIssues(r *ginext.Request) (*ginext.Response, error) {
+	var query model.ReconciliationIssueListQuery
+	if err := r.GinCtx.ShouldBindQuery(&query); err != nil {
+		log.Debug().Err(err).Msg("Query binding failed")
+		return nil, ginext.NewBadRequestError("Invalid query parameters")
+	}
+
+	// Normalize defaults
+	query.Normalize()
+
+	issues, total, err := h.service.ListIssues(r.Context(), &query)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to list reconciliation issues")
+		return nil, err
+	}
+
+	return ginext.NewPaginatedResponse(issues, query.Page, query.PageSize, total), nil
+}
+
+// ResolveIssue godoc
+// @Summary Resolve a payment reconciliation issue (Admin)
+// @Description Record the provider status on the transaction. APPLY also updates the booking, IGNORE leaves it as is.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path string true "Issue ID"
+// @Param request body model.ResolveReconciliationIssueRequest true "Resolution"
+// @Success 200 {object} ginext.Response
+// @Failure 400 {object} ginext.Response
+// @Failure 401 {object} ginext.Response
+// @Failure 403 {object} ginext.Response
+// @Failure 404 {object} ginext.Response
+// @Failure 409 {object} ginext.Response
+// @Failure 500 {object} ginext.Response
+// @Router /api/v1/reconciliation/issues/{id}/resolve [post]
+func (h *ReconciliationHandlerImpl) ResolveIssue(r *ginext.Request) (*ginext.Response, error) {
+	adminID := sharedcontext.GetUserID(r.GinCtx)
+
+	idStr := r.GinCtx.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		return nil, ginext.NewBadRequestError("Invalid issue ID")
+	}
+
+	var req model.ResolveReconciliationIssueRequest
+	if err := r.GinCtx.ShouldBindJSON(&req); err != nil {
+		log.Debug().Err(err).Msg("JSON binding failed")
+		return nil, ginext.NewBadRequestError("Invalid request data")
+	}
+
+	issue, err := h.service.ResolveIssue(r.Context(), id, &req, adminID)
+	if err != nil {
+		log.Error().Err(err).Str("id", idStr).Msg("Failed to resolve reconciliation issue")
+		return nil, err
+	}
+
+	return ginext.NewSuccessResponse(issue), nil
+}
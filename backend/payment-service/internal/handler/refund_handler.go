@@ -15,6 +15,9 @@ import (
 type RefundHandler interface {
 	Create(r *ginext.Request) (*ginext.Response, error)
 	GetByBookingID(r *ginext.Request) (*ginext.Response, error)
+	CreateOperatorRefund(r *ginext.Request) (*ginext.Response, error)
+	GetBookingRefund(r *ginext.Request) (*ginext.Response, error)
+	GetRefundStatus(r *ginext.Request) (*ginext.Response, error)
 	ListRefunds(r *ginext.Request) (*ginext.Response, error)
 	UpdateRefundStatus(r *ginext.Request) (*ginext.Response, error)
 	ExportRefunds(r *ginext.Request) error
@@ -61,16 +64,16 @@ func (h *RefundHandlerImpl) Create(r *ginext.Request) (*ginext.Response, error)
 }
 
 // GetByBookingID godoc
-// @Summary Get refund by booking ID
-// @Description Get refund information for a specific booking
+// @Summary Get refunds by booking ID
+// @Description Get every refund of a specific booking, newest first
 // @Tags refunds
 // @Accept json
 // @Produce json
 // @Param booking_id path string true "Booking ID"
-// @Success 200 {object} ginext.Response{data=model.RefundResponse}
+// @Success 200 {object} ginext.Response{data=[]model.RefundResponse}
 // @Failure 400 {object} ginext.Response
 // @Failure 401 {object} ginext.Response
-// @Failure 404 {object} ginext.Response
+// @Failure 403 {object} ginext.Response
 // @Failure 500 {object} ginext.Response
 // @Router /api/v1/refunds/booking/{booking_id} [get]
 func (h *RefundHandlerImpl) GetByBookingID(r *ginext.Request) (*ginext.Response, error) {
@@ -82,7 +85,64 @@ func (h *RefundHandlerImpl) GetByBookingID(r *ginext.Request) (*ginext.Response,
 		return nil, ginext.NewBadRequestError("Invalid booking ID")
 	}
 
-	refund, err := h.service.GetRefundByBookingID(r.Context(), bookingID, userID)
+	refunds, err := h.service.ListRefundsByBookingID(r.Context(), bookingID, userID)
+	if err != nil {
+		log.Error().Err(err).Str("booking_id", bookingIDStr).Msg("Failed to get refunds")
+		return nil, err
+	}
+
+	return ginext.NewSuccessResponse(refunds), nil
+}
+
+// CreateOperatorRefund godoc
+// @Summary Create an operator refund (Internal)
+// @Description Refund a booking cancelled by the operator on behalf of the passenger who paid
+// @Tags internal
+// @Accept json
+// @Produce json
+// @Param refund body model.OperatorRefundRequest true "Refund request"
+// @Success 201 {object} ginext.Response{data=model.RefundResponse}
+// @Failure 400 {object} ginext.Response
+// @Failure 404 {object} ginext.Response
+// @Failure 409 {object} ginext.Response
+// @Failure 500 {object} ginext.Response
+// @Router /api/v1/refunds/operator [post]
+func (h *RefundHandlerImpl) CreateOperatorRefund(r *ginext.Request) (*ginext.Response, error) {
+	var req model.OperatorRefundRequest
+	if err := r.GinCtx.ShouldBindJSON(&req); err != nil {
+		log.Debug().Err(err).Msg("JSON binding failed")
+		return nil, ginext.NewBadRequestError("Invalid request data")
+	}
+	req.RefundRequest.ID = req.ID
+
+	refund, err := h.service.CreateOperatorRefund(r.Context(), &req.RefundRequest)
+	if err != nil {
+		log.Error().Err(err).Str("booking_id", req.BookingID.String()).Msg("Failed to create operator refund")
+		return nil, err
+	}
+
+	return ginext.NewCreatedResponse(refund), nil
+}
+
+// GetBookingRefund godoc
+// @Summary Get refund status of a booking (Internal)
+// @Description Get the latest refund of a booking without an ownership check
+// @Tags internal
+// @Accept json
+// @Produce json
+// @Param booking_id path string true "Booking ID"
+// @Success 200 {object} ginext.Response{data=model.RefundResponse}
+// @Failure 400 {object} ginext.Response
+// @Failure 404 {object} ginext.Response
+// @Router /api/v1/refunds/booking/{booking_id}/status [get]
+func (h *RefundHandlerImpl) GetBookingRefund(r *ginext.Request) (*ginext.Response, error) {
+	bookingIDStr := r.GinCtx.Param("booking_id")
+	bookingID, err := uuid.Parse(bookingIDStr)
+	if err != nil {
+		return nil, ginext.NewBadRequestError("Invalid booking ID")
+	}
+
+	refund, err := h.service.GetBookingRefund(r.Context(), bookingID)
 	if err != nil {
 		log.Error().Err(err).Str("booking_id", bookingIDStr).Msg("Failed to get refund")
 		return nil, err
@@ -91,6 +151,33 @@ func (h *RefundHandlerImpl) GetByBookingID(r *ginext.Request) (*ginext.Response,
 	return ginext.NewSuccessResponse(refund), nil
 }
 
+// GetRefundStatus godoc
+// @Summary Get status of a refund (Internal)
+// @Description Get a refund together with the parts split off it, completed once every part is paid back
+// @Tags internal
+// @Accept json
+// @Produce json
+// @Param id path string true "Refund ID"
+// @Success 200 {object} ginext.Response{data=model.RefundResponse}
+// @Failure 400 {object} ginext.Response
+// @Failure 404 {object} ginext.Response
+// @Router /api/v1/refunds/{id}/status [get]
+func (h *RefundHandlerImpl) GetRefundStatus(r *ginext.Request) (*ginext.Response, error) {
+	refundIDStr := r.GinCtx.Param("id")
+	refundID, err := uuid.Parse(refundIDStr)
+	if err != nil {
+		return nil, ginext.NewBadRequestError("Invalid refund ID")
+	}
+
+	refund, err := h.service.GetRefundStatus(r.Context(), refundID)
+	if err != nil {
+		log.Error().Err(err).Str("refund_id", refundIDStr).Msg("Failed to get refund status")
+		return nil, err
+	}
+
+	return ginext.NewSuccessResponse(refund), nil
+}
+
 // ListRefunds godoc
 // @Summary List refunds (Admin)
 // @Description List refund transactions with filters
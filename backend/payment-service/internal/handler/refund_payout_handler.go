@@ -0,0 +1,232 @@
+package handler
+
+import (
+	"bus-booking/payment-service/internal/model"
+	"bus-booking/payment-service/internal/service"
+	sharedcontext "bus-booking/shared/context"
+	"bus-booking/shared/ginext"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+type RefundPayoutHandler interface {
+	CreateBatch(r *ginext.Request) (*ginext.Response, error)
+	ListBatches(r *ginext.Request) (*ginext.Response, error)
+	GetBatch(r *ginext.Request) (*ginext.Response, error)
+	SendBatch(r *ginext.Request) (*ginext.Response, error)
+	ConfirmPayout(r *ginext.Request) (*ginext.Response, error)
+	FailPayout(r *ginext.Request) (*ginext.Response, error)
+}
+
+type RefundPayoutHandlerImpl struct {
+	service service.RefundPayoutService
+}
+
+func NewRefundPayoutHandler(service service.RefundPayoutService) RefundPayoutHandler {
+	return &RefundPayoutHandlerImpl{
+		service: service,
+	}
+}
+
+// CreateBatch godoc
+// @Summary Create a refund payout batch (Admin)
+// @Description Queue a VietQR transfer to the primary bank account of each approved refund. Without refund_ids all approved refunds are taken, oldest first.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param request body model.CreateRefundPayoutBatchRequest false "Refunds to pay out"
+// @Success 201 {object} ginext.Response
+// @Failure 400 {object} ginext.Response
+// @Failure 401 {object} ginext.Response
+// @Failure 403 {object} ginext.Response
+// @Failure 500 {object} ginext.Response
+// @Router /api/v1/refund-payouts/batches [post]
+func (h *RefundPayoutHandlerImpl) CreateBatch(r *ginext.Request) (*ginext.Response, error) {
+	adminID := sharedcontext.GetUserID(r.GinCtx)
+
+	var req model.CreateRefundPayoutBatchRequest
+	if r.GinCtx.Request.ContentLength > 0 {
+		if err := r.GinCtx.ShouldBindJSON(&req); err != nil {
+			log.Debug().Err(err).Msg("JSON binding failed")
+			return nil, ginext.NewBadRequestError("Invalid request data")
+		}
+	}
+
+	batch, err := h.service.CreateBatch(r.Context(), &req, adminID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to create refund payout batch")
+		return nil, err
+	}
+
+	return ginext.NewCreatedResponse(batch), nil
+}
+
+// ListBatches godoc
+// @Summary List refund payout batches (Admin)
+// @Description List refund payout batches with their transfers, newest first
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(20)
+// @Success 200 {object} ginext.Response
+// @Failure 400 {object} ginext.Response
+// @Failure 401 {object} ginext.Response
+// @Failure 403 {object} ginext.Response
+// @Failure 500 {object} ginext.Response
+// @Router /api/v1/refund-payouts/batches [get]
+func (h *RefundPayoutHandlerImpl) ListBatches(r *ginext.Request) (*ginext.Response, error) {
+	var query model.RefundPayoutBatchListQuery
+	if err := r.GinCtx.ShouldBindQuery(&query); err != nil {
+		log.Debug().Err(err).Msg("Query binding failed")
+		return nil, ginext.NewBadRequestError("Invalid query parameters")
+	}
+
+	// Normalize defaults
+	query.Normalize()
+
+	batches, total, err := h.service.ListBatches(r.Context(), &query)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to list refund payout batches")
+		return nil, err
+	}
+
+	return ginext.NewPaginatedResponse(batches, query.Page, query.PageSize, total), nil
+}
+
+// GetBatch godoc
+// @Summary Get a refund payout batch (Admin)
+// @Description Get a refund payout batch with the VietQR payload of each transfer
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path string true "Batch ID"
+// @Success 200 {object} ginext.Response
+// @Failure 400 {object} ginext.Response
+// @Failure 401 {object} ginext.Response
+// @Failure 403 {object} ginext.Response
+// @Failure 404 {object} ginext.Response
+// @Router /api/v1/refund-payouts/batches/{id} [get]
+func (h *RefundPayoutHandlerImpl) GetBatch(r *ginext.Request) (*ginext.Response, error) {
+	id, err := uuid.Parse(r.GinCtx.Param("id"))
+	if err != nil {
+		return nil, ginext.NewBadRequestError("Invalid batch ID")
+	}
+
+	batch, err := h.service.GetBatch(r.Context(), id)
+	if err != nil {
+		return nil, err
+	}
+
+	return ginext.NewSuccessResponse(batch), nil
+}
+
+// SendBatch godoc
+// @Summary Mark a refund payout batch as sent (Admin)
+// @Description Record that the queued transfers of the batch were submitted to the bank and email the passengers
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path string true "Batch ID"
+// @Success 200 {object} ginext.Response
+// @Failure 400 {object} ginext.Response
+// @Failure 401 {object} ginext.Response
+// @Failure 403 {object} ginext.Response
+// @Failure 404 {object} ginext.Response
+// @Failure 409 {object} ginext.Response
+// @Failure 500 {object} ginext.Response
+// @Router /api/v1/refund-payouts/batches/{id}/send [post]
+func (h *RefundPayoutHandlerImpl) SendBatch(r *ginext.Request) (*ginext.Response, error) {
+	idStr := r.GinCtx.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		return nil, ginext.NewBadRequestError("Invalid batch ID")
+	}
+
+	batch, err := h.service.SendBatch(r.Context(), id)
+	if err != nil {
+		log.Error().Err(err).Str("id", idStr).Msg("Failed to send refund payout batch")
+		return nil, err
+	}
+
+	return ginext.NewSuccessResponse(batch), nil
+}
+
+// ConfirmPayout godoc
+// @Summary Confirm a refund payout (Admin)
+// @Description Confirm a sent transfer against the bank statement, completing its refund
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path string true "Payout ID"
+// @Param request body model.ConfirmRefundPayoutRequest true "Bank reference"
+// @Success 200 {object} ginext.Response
+// @Failure 400 {object} ginext.Response
+// @Failure 401 {object} ginext.Response
+// @Failure 403 {object} ginext.Response
+// @Failure 404 {object} ginext.Response
+// @Failure 409 {object} ginext.Response
+// @Failure 500 {object} ginext.Response
+// @Router /api/v1/refund-payouts/{id}/confirm [post]
+func (h *RefundPayoutHandlerImpl) ConfirmPayout(r *ginext.Request) (*ginext.Response, error) {
+	adminID := sharedcontext.GetUserID(r.GinCtx)
+
+	idStr := r.GinCtx.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		return nil, ginext.NewBadRequestError("Invalid payout ID")
+	}
+
+	var req model.ConfirmRefundPayoutRequest
+	if err := r.GinCtx.ShouldBindJSON(&req); err != nil {
+		log.Debug().Err(err).Msg("JSON binding failed")
+		return nil, ginext.NewBadRequestError("Invalid request data")
+	}
+
+	payout, err := h.service.ConfirmPayout(r.Context(), id, &req, adminID)
+	if err != nil {
+		log.Error().Err(err).Str("id", idStr).Msg("Failed to confirm refund payout")
+		return nil, err
+	}
+
+	return ginext.NewSuccessResponse(payout), nil
+}
+
+// FailPayout godoc
+// @Summary Fail a refund payout (Admin)
+// @Description Record a rejected transfer. The refund stays approved and is picked up by the next batch.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path string true "Payout ID"
+// @Param request body model.FailRefundPayoutRequest true "Failure reason"
+// @Success 200 {object} ginext.Response
+// @Failure 400 {object} ginext.Response
+// @Failure 401 {object} ginext.Response
+// @Failure 403 {object} ginext.Response
+// @Failure 404 {object} ginext.Response
+// @Failure 409 {object} ginext.Response
+// @Failure 500 {object} ginext.Response
+// @Router /api/v1/refund-payouts/{id}/fail [post]
+func (h *RefundPayoutHandlerImpl) FailPayout(r *ginext.Request) (*ginext.Response, error) {
+	idStr := r.GinCtx.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		return nil, ginext.NewBadRequestError("Invalid payout ID")
+	}
+
+	var req model.FailRefundPayoutRequest
+	if err := r.GinCtx.ShouldBindJSON(&req); err != nil {
+		log.Debug().Err(err).Msg("JSON binding failed")
+		return nil, ginext.NewBadRequestError("Invalid request data")
+	}
+
+	payout, err := h.service.FailPayout(r.Context(), id, &req)
+	if err != nil {
+		log.Error().Err(err).Str("id", idStr).Msg("Failed to fail refund payout")
+		return nil, err
+	}
+
+	return ginext.NewSuccessResponse(payout), nil
+}
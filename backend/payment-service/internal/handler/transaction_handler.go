@@ -1,9 +1,6 @@
 package handler
 
 import (
-	"encoding/json"
-	"io"
-
 	"github.com/google/uuid"
 	"github.com/rs/zerolog/log"
 
@@ -20,7 +17,7 @@ type TransactionHandler interface {
 
 	Create(r *ginext.Request) (*ginext.Response, error)
 	Cancel(r *ginext.Request) (*ginext.Response, error)
-	HandleWebhook(r *ginext.Request) (*ginext.Response, error)
+	ConfirmCashPayment(r *ginext.Request) (*ginext.Response, error)
 }
 
 type TransactionHandlerImpl struct {
@@ -183,54 +180,47 @@ func (h *TransactionHandlerImpl) Cancel(r *ginext.Request) (*ginext.Response, er
 	return ginext.NewSuccessResponse(transaction), nil
 }
 
-// HandleWebhook godoc
-// @Summary Handle PayOS webhook
-// @Description Handle payment webhook notification from PayOS
-// @Tags transactions
+// ConfirmCashPayment godoc
+// @Summary Confirm a cash payment (Admin)
+// @Description Mark a pay-at-counter transaction as paid once the money has been received
+// @Tags admin
 // @Accept json
 // @Produce json
-// @Param webhook body model.PaymentWebhookData true "Webhook payload"
-// @Success 200 {object} ginext.Response
+// @Param id path string true "Transaction ID"
+// @Param request body model.ConfirmCashPaymentRequest true "Counter receipt"
+// @Success 200 {object} ginext.Response{data=model.TransactionResponse}
 // @Failure 400 {object} ginext.Response
+// @Failure 401 {object} ginext.Response
+// @Failure 403 {object} ginext.Response
+// @Failure 404 {object} ginext.Response
+// @Failure 409 {object} ginext.Response
 // @Failure 500 {object} ginext.Response
-// @Router /api/v1/transactions/webhook [post]
-func (h *TransactionHandlerImpl) HandleWebhook(r *ginext.Request) (*ginext.Response, error) {
-	log.Info().Msg("Webhook handler started")
+// @Router /api/v1/transactions/{id}/confirm-cash [post]
+func (h *TransactionHandlerImpl) ConfirmCashPayment(r *ginext.Request) (*ginext.Response, error) {
+	adminID := sharedcontext.GetUserID(r.GinCtx)
 
-	// Read the raw body first (needed for signature verification)
-	bodyBytes, err := io.ReadAll(r.GinCtx.Request.Body)
+	idStr := r.GinCtx.Param("id")
+	id, err := uuid.Parse(idStr)
 	if err != nil {
-		log.Error().Err(err).Msg("Failed to read request body")
-		return nil, ginext.NewBadRequestError("Invalid request body")
-	}
-	defer func() {
-		if err := r.GinCtx.Request.Body.Close(); err != nil {
-			log.Error().Err(err).Msg("Failed to close request body")
-		}
-	}()
-
-	// Parse to map for verification
-	var webhookMap map[string]interface{}
-	if err := json.Unmarshal(bodyBytes, &webhookMap); err != nil {
-		log.Error().Err(err).Msg("JSON parsing failed - invalid JSON format")
-		return nil, ginext.NewBadRequestError("Invalid webhook data")
+		return nil, ginext.NewBadRequestError("Invalid transaction ID")
 	}
 
-	// Parse to struct for processing
-	var webhookData model.PaymentWebhookData
-	if err := json.Unmarshal(bodyBytes, &webhookData); err != nil {
-		log.Error().Err(err).Msg("JSON parsing failed - invalid webhook structure")
-		return nil, ginext.NewBadRequestError("Invalid webhook data")
+	var req model.ConfirmCashPaymentRequest
+	if err := r.GinCtx.ShouldBindJSON(&req); err != nil {
+		log.Debug().Err(err).Msg("JSON binding failed")
+		return nil, ginext.NewBadRequestError("Invalid request data")
 	}
 
-	log.Info().Interface("webhookData", webhookData).Msg("Successfully parsed webhook data")
-
-	err = h.service.HandleWebhook(r.GinCtx.Request.Context(), webhookMap, webhookData)
+	transaction, err := h.service.ConfirmCashPayment(r.Context(), id, &req)
 	if err != nil {
-		log.Error().Err(err).Msg("Failed to process webhook in service layer")
+		log.Error().Err(err).Str("id", idStr).Msg("Failed to confirm cash payment")
 		return nil, err
 	}
 
-	log.Info().Msg("Webhook processed successfully")
-	return ginext.NewSuccessResponse("Webhook processed successfully"), nil
+	log.Info().
+		Str("transaction_id", idStr).
+		Str("admin_id", adminID.String()).
+		Str("receipt_number", req.ReceiptNumber).
+		Msg("Cash payment confirmed")
+	return ginext.NewSuccessResponse(transaction), nil
 }
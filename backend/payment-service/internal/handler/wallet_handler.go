@@ -0,0 +1,144 @@
+package handler
+
+import (
+	"bus-booking/payment-service/internal/model"
+	"bus-booking/payment-service/internal/service"
+	sharedcontext "bus-booking/shared/context"
+	"bus-booking/shared/ginext"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+type WalletHandler interface {
+	GetWallet(r *ginext.Request) (*ginext.Response, error)
+	ListEntries(r *ginext.Request) (*ginext.Response, error)
+	ListUserEntries(r *ginext.Request) (*ginext.Response, error)
+	GrantPromotionalCredit(r *ginext.Request) (*ginext.Response, error)
+}
+
+type WalletHandlerImpl struct {
+	service service.WalletService
+}
+
+func NewWalletHandler(service service.WalletService) WalletHandler {
+	return &WalletHandlerImpl{
+		service: service,
+	}
+}
+
+// GetWallet godoc
+// @Summary Get my wallet
+// @Description Get the balance the user can spend, with the promotional credit that expires
+// @Tags wallet
+// @Accept json
+// @Produce json
+// @Success 200 {object} ginext.Response{data=model.WalletResponse}
+// @Failure 401 {object} ginext.Response
+// @Failure 500 {object} ginext.Response
+// @Router /api/v1/wallet [get]
+func (h *WalletHandlerImpl) GetWallet(r *ginext.Request) (*ginext.Response, error) {
+	userID := sharedcontext.GetUserID(r.GinCtx)
+
+	wallet, err := h.service.GetWallet(r.Context(), userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return ginext.NewSuccessResponse(wallet), nil
+}
+
+// ListEntries godoc
+// @Summary List my wallet history
+// @Description List the refunds, promotional credit, payments and expiries of the user's wallet
+// @Tags wallet
+// @Accept json
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(20)
+// @Param type query string false "Entry type" Enums(REFUND, PROMOTION, PAYMENT, PAYMENT_RELEASED, EXPIRED)
+// @Success 200 {object} ginext.Response{data=[]model.WalletEntry}
+// @Failure 400 {object} ginext.Response
+// @Failure 401 {object} ginext.Response
+// @Failure 500 {object} ginext.Response
+// @Router /api/v1/wallet/entries [get]
+func (h *WalletHandlerImpl) ListEntries(r *ginext.Request) (*ginext.Response, error) {
+	return h.listEntries(r, sharedcontext.GetUserID(r.GinCtx))
+}
+
+// ListUserEntries godoc
+// @Summary List the wallet history of a user
+// @Tags wallet
+// @Accept json
+// @Produce json
+// @Param user_id path string true "User ID"
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(20)
+// @Param type query string false "Entry type" Enums(REFUND, PROMOTION, PAYMENT, PAYMENT_RELEASED, EXPIRED)
+// @Success 200 {object} ginext.Response{data=[]model.WalletEntry}
+// @Failure 400 {object} ginext.Response
+// @Failure 401 {object} ginext.Response
+// @Failure 403 {object} ginext.Response
+// @Failure 500 {object} ginext.Response
+// @Router /api/v1/wallets/{user_id}/entries [get]
+func (h *WalletHandlerImpl) ListUserEntries(r *ginext.Request) (*ginext.Response, error) {
+	userID, err := uuid.Parse(r.GinCtx.Param("user_id"))
+	if err != nil {
+		return nil, ginext.NewBadRequestError("Invalid user ID")
+	}
+	return h.listEntries(r, userID)
+}
+
+func (h *WalletHandlerImpl) listEntries(r *ginext.Request, userID uuid.UUID) (*ginext.Response, error) {
+	var query model.WalletEntryListQuery
+	if err := r.GinCtx.ShouldBindQuery(&query); err != nil {
+		log.Debug().Err(err).Msg("Query binding failed")
+		return nil, ginext.NewBadRequestError("Invalid query parameters")
+	}
+
+	// Normalize defaults
+	query.Normalize()
+
+	entries, total, err := h.service.ListEntries(r.Context(), userID, &query)
+	if err != nil {
+		return nil, err
+	}
+
+	return ginext.NewPaginatedResponse(entries, query.Page, query.PageSize, total), nil
+}
+
+// GrantPromotionalCredit godoc
+// @Summary Grant promotional credit
+// @Description Credit a user's wallet with promotional credit that expires unspent
+// @Tags wallet
+// @Accept json
+// @Produce json
+// @Param user_id path string true "User ID"
+// @Param credit body model.GrantPromotionalCreditRequest true "Promotional credit"
+// @Success 201 {object} ginext.Response{data=model.WalletEntry}
+// @Failure 400 {object} ginext.Response
+// @Failure 401 {object} ginext.Response
+// @Failure 403 {object} ginext.Response
+// @Failure 500 {object} ginext.Response
+// @Router /api/v1/wallets/{user_id}/promotional-credits [post]
+func (h *WalletHandlerImpl) GrantPromotionalCredit(r *ginext.Request) (*ginext.Response, error) {
+	adminID := sharedcontext.GetUserID(r.GinCtx)
+
+	userID, err := uuid.Parse(r.GinCtx.Param("user_id"))
+	if err != nil {
+		return nil, ginext.NewBadRequestError("Invalid user ID")
+	}
+
+	var req model.GrantPromotionalCreditRequest
+	if err := r.GinCtx.ShouldBindJSON(&req); err != nil {
+		log.Debug().Err(err).Msg("JSON binding failed")
+		return nil, ginext.NewBadRequestError("Invalid request data")
+	}
+
+	entry, err := h.service.GrantPromotionalCredit(r.Context(), userID, &req, adminID)
+	if err != nil {
+		return nil, err
+	}
+
+	return ginext.NewCreatedResponse(entry), nil
+}
@@ -0,0 +1,174 @@
+package handler
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+
+	"bus-booking/payment-service/internal/model"
+	"bus-booking/payment-service/internal/service"
+	sharedcontext "bus-booking/shared/context"
+	"bus-booking/shared/ginext"
+)
+
+type WebhookHandler interface {
+	HandleWebhook(r *ginext.Request) (*ginext.Response, error)
+	SimulateSandboxPayment(r *ginext.Request) (*ginext.Response, error)
+	ListTransactionEvents(r *ginext.Request) (*ginext.Response, error)
+	ReplayEvent(r *ginext.Request) (*ginext.Response, error)
+}
+
+type WebhookHandlerImpl struct {
+	service service.WebhookService
+}
+
+func NewWebhookHandler(service service.WebhookService) WebhookHandler {
+	return &WebhookHandlerImpl{
+		service: service,
+	}
+}
+
+// HandleWebhook godoc
+// @Summary Handle PayOS webhook
+// @Description Handle payment webhook notification from PayOS
+// @Tags transactions
+// @Accept json
+// @Produce json
+// @Param webhook body model.PaymentWebhookData true "Webhook payload"
+// @Success 200 {object} ginext.Response
+// @Failure 400 {object} ginext.Response
+// @Failure 500 {object} ginext.Response
+// @Router /api/v1/transactions/webhook [post]
+func (h *WebhookHandlerImpl) HandleWebhook(r *ginext.Request) (*ginext.Response, error) {
+	log.Info().Msg("Webhook handler started")
+
+	// Read the raw body first (needed for signature verification and the webhook log)
+	bodyBytes, err := io.ReadAll(r.GinCtx.Request.Body)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to read request body")
+		return nil, ginext.NewBadRequestError("Invalid request body")
+	}
+	defer func() {
+		if err := r.GinCtx.Request.Body.Close(); err != nil {
+			log.Error().Err(err).Msg("Failed to close request body")
+		}
+	}()
+
+	err = h.service.Receive(r.GinCtx.Request.Context(), model.PaymentMethodPayOS, bodyBytes)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to process webhook in service layer")
+		return nil, err
+	}
+
+	log.Info().Msg("Webhook processed successfully")
+	return ginext.NewSuccessResponse("Webhook processed successfully"), nil
+}
+
+// SimulateSandboxPayment godoc
+// @Summary Simulate a sandbox payment
+// @Description Pay, cancel or fail a sandbox payment as the customer would. Paying less than the outstanding amount leaves it underpaid, more overpays it. Only available when the sandbox provider is enabled.
+// @Tags transactions
+// @Accept json
+// @Produce json
+// @Param payment_link_id path string true "Sandbox payment link ID"
+// @Param request body model.SandboxPaymentRequest true "Customer action"
+// @Success 200 {object} ginext.Response
+// @Failure 400 {object} ginext.Response
+// @Failure 500 {object} ginext.Response
+// @Router /api/v1/sandbox/payments/{payment_link_id} [post]
+func (h *WebhookHandlerImpl) SimulateSandboxPayment(r *ginext.Request) (*ginext.Response, error) {
+	paymentLinkID := r.GinCtx.Param("payment_link_id")
+
+	var req model.SandboxPaymentRequest
+	if err := r.GinCtx.ShouldBindJSON(&req); err != nil {
+		log.Debug().Err(err).Msg("JSON binding failed")
+		return nil, ginext.NewBadRequestError("Invalid request data")
+	}
+
+	// Delivered like a provider notification so it shows up in the webhook log
+	payload := map[string]interface{}{
+		"payment_link_id": paymentLinkID,
+		"status":          string(req.Status),
+	}
+	if req.Amount > 0 {
+		payload["amount"] = req.Amount
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, ginext.NewInternalServerError(err.Error())
+	}
+
+	if err := h.service.Receive(r.Context(), model.PaymentMethodSandbox, body); err != nil {
+		log.Error().Err(err).Str("payment_link_id", paymentLinkID).Msg("Failed to simulate sandbox payment")
+		return nil, err
+	}
+
+	return ginext.NewSuccessResponse("Sandbox payment processed successfully"), nil
+}
+
+// ListTransactionEvents godoc
+// @Summary List webhook events of a transaction (Admin)
+// @Description List every provider notification received for a transaction with its signature check and processing result, newest first
+// @Tags admin
+// @Produce json
+// @Param id path string true "Transaction ID"
+// @Success 200 {object} ginext.Response{data=[]model.WebhookEvent}
+// @Failure 400 {object} ginext.Response
+// @Failure 401 {object} ginext.Response
+// @Failure 403 {object} ginext.Response
+// @Failure 404 {object} ginext.Response
+// @Failure 500 {object} ginext.Response
+// @Router /api/v1/transactions/{id}/webhook-events [get]
+func (h *WebhookHandlerImpl) ListTransactionEvents(r *ginext.Request) (*ginext.Response, error) {
+	idStr := r.GinCtx.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		return nil, ginext.NewBadRequestError("Invalid transaction ID")
+	}
+
+	events, err := h.service.ListForTransaction(r.Context(), id)
+	if err != nil {
+		log.Error().Err(err).Str("id", idStr).Msg("Failed to list webhook events")
+		return nil, err
+	}
+
+	return ginext.NewSuccessResponse(events), nil
+}
+
+// ReplayEvent godoc
+// @Summary Replay a webhook event (Admin)
+// @Description Process a verified webhook event again. The payment status is fetched from the provider anew, so an applied payment is not applied twice.
+// @Tags admin
+// @Produce json
+// @Param id path string true "Webhook event ID"
+// @Success 200 {object} ginext.Response{data=model.WebhookEvent}
+// @Failure 400 {object} ginext.Response
+// @Failure 401 {object} ginext.Response
+// @Failure 403 {object} ginext.Response
+// @Failure 404 {object} ginext.Response
+// @Failure 409 {object} ginext.Response
+// @Failure 500 {object} ginext.Response
+// @Router /api/v1/webhook-events/{id}/replay [post]
+func (h *WebhookHandlerImpl) ReplayEvent(r *ginext.Request) (*ginext.Response, error) {
+	adminID := sharedcontext.GetUserID(r.GinCtx)
+
+	idStr := r.GinCtx.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		return nil, ginext.NewBadRequestError("Invalid webhook event ID")
+	}
+
+	event, err := h.service.Replay(r.Context(), id)
+	if err != nil {
+		log.Error().Err(err).Str("id", idStr).Msg("Failed to replay webhook event")
+		return nil, err
+	}
+
+	log.Info().
+		Str("webhook_event_id", idStr).
+		Str("admin_id", adminID.String()).
+		Msg("Webhook event replayed")
+	return ginext.NewSuccessResponse(event), nil
+}
@@ -32,6 +32,7 @@ func (b *BankAccount) BeforeCreate(tx *gorm.DB) error {
 type BankConstant struct {
 	Code      string `json:"code"`
 	ShortName string `json:"short_name"`
+	BIN       string `json:"bin"`
 	Name      string `json:"name"`
 	Logo      string `json:"logo,omitempty"`
 }
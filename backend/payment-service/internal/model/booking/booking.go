@@ -2,10 +2,14 @@ package booking
 
 import (
 	"bus-booking/payment-service/internal/model"
+
+	"github.com/google/uuid"
 )
 
 type UpdateBookingStatusRequest struct {
+	TransactionID     uuid.UUID               `json:"transaction_id"`
 	TransactionStatus model.TransactionStatus `json:"transaction_status"`
+	AmountPaid        int                     `json:"amount_paid,omitempty"`
 }
 
 type BookingStatus string
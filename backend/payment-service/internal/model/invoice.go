@@ -0,0 +1,98 @@
+package model
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type InvoiceStatus string
+
+const (
+	InvoiceStatusIssued InvoiceStatus = "ISSUED"
+	// InvoiceStatusCancelled means the payment was refunded in full and nothing replaced the invoice
+	InvoiceStatusCancelled InvoiceStatus = "CANCELLED"
+	// InvoiceStatusReplaced means a partial refund cancelled the invoice and another one replaced it
+	InvoiceStatusReplaced InvoiceStatus = "REPLACED"
+)
+
+// InvoiceBuyer is the company a VAT invoice is made out to
+type InvoiceBuyer struct {
+	Name        string `json:"name,omitempty" binding:"omitempty,max=255"`
+	CompanyName string `json:"company_name" binding:"required,max=255"`
+	TaxCode     string `json:"tax_code" binding:"required,min=10,max=14"`
+	Address     string `json:"address" binding:"required,max=500"`
+	Email       string `json:"email,omitempty" binding:"omitempty,email"`
+}
+
+// Invoice is a VAT invoice for a paid booking. Invoices are numbered in sequence per operator
+// and series; a refund cancels the invoice and, unless everything was refunded, replaces it.
+type Invoice struct {
+	BaseModel
+	TransactionID uuid.UUID     `gorm:"type:uuid;not null;index" json:"transaction_id"`
+	BookingID     uuid.UUID     `gorm:"type:uuid;not null;index" json:"booking_id"`
+	UserID        uuid.UUID     `gorm:"type:uuid;not null" json:"user_id"`
+	OperatorID    *uuid.UUID    `gorm:"type:uuid" json:"operator_id,omitempty"`
+	Series        string        `gorm:"type:varchar(10);not null" json:"series"`
+	Number        int           `gorm:"not null" json:"number"`
+	Status        InvoiceStatus `gorm:"type:varchar(20);not null;default:'ISSUED'" json:"status"`
+
+	// Seller details are kept as printed when the invoice was issued
+	SellerName    string `gorm:"type:varchar(255);not null" json:"seller_name"`
+	SellerTaxCode string `gorm:"type:varchar(14);not null" json:"seller_tax_code"`
+	SellerAddress string `gorm:"type:text;not null" json:"seller_address"`
+
+	BuyerName        string `gorm:"type:varchar(255);not null;default:''" json:"buyer_name,omitempty"`
+	BuyerCompanyName string `gorm:"type:varchar(255);not null" json:"buyer_company_name"`
+	BuyerTaxCode     string `gorm:"type:varchar(14);not null" json:"buyer_tax_code"`
+	BuyerAddress     string `gorm:"type:text;not null" json:"buyer_address"`
+	BuyerEmail       string `gorm:"type:varchar(255);not null;default:''" json:"buyer_email,omitempty"`
+
+	Description     string `gorm:"type:text;not null" json:"description"`
+	AmountBeforeTax int    `gorm:"not null" json:"amount_before_tax"`
+	VATRate         int    `gorm:"not null" json:"vat_rate"` // percent
+	VATAmount       int    `gorm:"not null" json:"vat_amount"`
+	TotalAmount     int    `gorm:"not null" json:"total_amount"`
+
+	IssuedAt     time.Time  `gorm:"not null" json:"issued_at"`
+	CancelledAt  *time.Time `json:"cancelled_at,omitempty"`
+	CancelReason *string    `gorm:"type:text" json:"cancel_reason,omitempty"`
+	ReplacesID   *uuid.UUID `gorm:"type:uuid" json:"replaces_id,omitempty"`
+	ReplacedByID *uuid.UUID `gorm:"type:uuid" json:"replaced_by_id,omitempty"`
+}
+
+func (Invoice) TableName() string {
+	return "invoices"
+}
+
+func (i *Invoice) BeforeCreate(tx *gorm.DB) error {
+	if i.ID == uuid.Nil {
+		i.ID = uuid.New()
+	}
+	return nil
+}
+
+// InvoiceNumber is the number printed on the invoice, zero padded to the 8 digits of e-invoices
+func (i *Invoice) InvoiceNumber() string {
+	return fmt.Sprintf("%08d", i.Number)
+}
+
+// InvoiceSequence holds the last number used for the invoices of an operator in a series.
+// Payments settled to the platform are numbered under the nil operator ID.
+type InvoiceSequence struct {
+	OperatorID uuid.UUID `gorm:"type:uuid;primaryKey"`
+	Series     string    `gorm:"type:varchar(10);primaryKey"`
+	LastNumber int       `gorm:"not null;default:0"`
+	UpdatedAt  time.Time `gorm:"autoUpdateTime"`
+}
+
+func (InvoiceSequence) TableName() string {
+	return "invoice_sequences"
+}
+
+// IssueInvoiceRequest asks for the VAT invoice of a paid booking
+type IssueInvoiceRequest struct {
+	InvoiceBuyer
+}
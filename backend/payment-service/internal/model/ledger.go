@@ -0,0 +1,188 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type LedgerJournalKind string
+type LedgerAccount string
+type LedgerDirection string
+
+const (
+	// LedgerJournalKindCharge records a payment taken by a provider
+	LedgerJournalKindCharge LedgerJournalKind = "CHARGE"
+	// LedgerJournalKindFee records what the provider kept of a payment
+	LedgerJournalKindFee LedgerJournalKind = "FEE"
+	// LedgerJournalKindRefund records a refund paid back to the passenger
+	LedgerJournalKindRefund LedgerJournalKind = "REFUND"
+	// LedgerJournalKindAdjustment records a manual correction by finance
+	LedgerJournalKindAdjustment LedgerJournalKind = "ADJUSTMENT"
+	// LedgerJournalKindWalletCharge records the part of a payment drawn from the wallet
+	LedgerJournalKindWalletCharge LedgerJournalKind = "WALLET_CHARGE"
+	// LedgerJournalKindPromotion records promotional credit handed out to a wallet
+	LedgerJournalKindPromotion LedgerJournalKind = "PROMOTION"
+	// LedgerJournalKindCreditExpiry records promotional credit that expired unspent
+	LedgerJournalKindCreditExpiry LedgerJournalKind = "CREDIT_EXPIRY"
+
+	// LedgerAccountProviderClearing is the money held for us by a payment method, settlement reports balance it
+	LedgerAccountProviderClearing LedgerAccount = "PROVIDER_CLEARING"
+	// LedgerAccountOperatorPayable is what we owe the operators for the tickets sold
+	LedgerAccountOperatorPayable LedgerAccount = "OPERATOR_PAYABLE"
+	// LedgerAccountProviderFees is what the payment providers charged us
+	LedgerAccountProviderFees LedgerAccount = "PROVIDER_FEES"
+	// LedgerAccountAdjustments is the counterpart of manual corrections
+	LedgerAccountAdjustments LedgerAccount = "ADJUSTMENTS"
+	// LedgerAccountCustomerWallets is what we owe the passengers in their wallets
+	LedgerAccountCustomerWallets LedgerAccount = "CUSTOMER_WALLETS"
+	// LedgerAccountPromotions is the promotional credit we handed out and that was not left to expire
+	LedgerAccountPromotions LedgerAccount = "PROMOTIONS"
+
+	LedgerDirectionDebit  LedgerDirection = "DEBIT"
+	LedgerDirectionCredit LedgerDirection = "CREDIT"
+)
+
+// LedgerJournal is one balanced posting of the double-entry ledger
+type LedgerJournal struct {
+	ID            uuid.UUID         `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	Kind          LedgerJournalKind `gorm:"type:varchar(20);not null" json:"kind"`
+	SourceID      uuid.UUID         `gorm:"type:uuid;not null" json:"source_id"`
+	TransactionID *uuid.UUID        `gorm:"type:uuid" json:"transaction_id,omitempty"`
+	OperatorID    *uuid.UUID        `gorm:"type:uuid" json:"operator_id,omitempty"`
+	PaymentMethod PaymentMethod     `gorm:"type:varchar(50);not null" json:"payment_method"`
+	Description   string            `gorm:"type:text;not null;default:''" json:"description"`
+	CreatedBy     *uuid.UUID        `gorm:"type:uuid" json:"created_by,omitempty"`
+	OccurredAt    time.Time         `gorm:"not null" json:"occurred_at"`
+	CreatedAt     time.Time         `gorm:"autoCreateTime" json:"created_at"`
+
+	Entries []*LedgerEntry `gorm:"foreignKey:JournalID" json:"entries"`
+}
+
+func (LedgerJournal) TableName() string {
+	return "ledger_journals"
+}
+
+func (j *LedgerJournal) BeforeCreate(tx *gorm.DB) error {
+	if j.ID == uuid.Nil {
+		j.ID = uuid.New()
+	}
+	return nil
+}
+
+// Balanced reports whether the debits of the journal equal its credits
+func (j *LedgerJournal) Balanced() bool {
+	if len(j.Entries) < 2 {
+		return false
+	}
+	balance := 0
+	for _, entry := range j.Entries {
+		if entry.Amount <= 0 {
+			return false
+		}
+		balance += entry.SignedAmount()
+	}
+	return balance == 0
+}
+
+// LedgerEntry debits or credits one account. The operator, payment method and time are copied
+// from the journal so reports only read entries.
+type LedgerEntry struct {
+	ID            uuid.UUID       `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	JournalID     uuid.UUID       `gorm:"type:uuid;not null;index" json:"journal_id"`
+	Account       LedgerAccount   `gorm:"type:varchar(30);not null" json:"account"`
+	Direction     LedgerDirection `gorm:"type:varchar(10);not null" json:"direction"`
+	Amount        int             `gorm:"not null" json:"amount"`
+	OperatorID    *uuid.UUID      `gorm:"type:uuid" json:"operator_id,omitempty"`
+	PaymentMethod PaymentMethod   `gorm:"type:varchar(50);not null" json:"payment_method"`
+	OccurredAt    time.Time       `gorm:"not null" json:"occurred_at"`
+	CreatedAt     time.Time       `gorm:"autoCreateTime" json:"created_at"`
+}
+
+func (LedgerEntry) TableName() string {
+	return "ledger_entries"
+}
+
+func (e *LedgerEntry) BeforeCreate(tx *gorm.DB) error {
+	if e.ID == uuid.Nil {
+		e.ID = uuid.New()
+	}
+	return nil
+}
+
+// SignedAmount is positive for a debit and negative for a credit
+func (e *LedgerEntry) SignedAmount() int {
+	if e.Direction == LedgerDirectionCredit {
+		return -e.Amount
+	}
+	return e.Amount
+}
+
+// LedgerMovement is the net change of the clearing account on one day, for one operator,
+// payment method and journal kind. Amount is signed: debits add to the balance.
+type LedgerMovement struct {
+	Day           time.Time
+	OperatorID    *uuid.UUID
+	PaymentMethod PaymentMethod
+	Kind          LedgerJournalKind
+	Amount        int
+}
+
+// LedgerBalance is the balance of the clearing account for one operator and payment method
+type LedgerBalance struct {
+	OperatorID    *uuid.UUID
+	PaymentMethod PaymentMethod
+	Balance       int
+}
+
+// LedgerJournalListQuery represents query parameters for listing ledger journals
+type LedgerJournalListQuery struct {
+	PaginationRequest
+	Kind          *LedgerJournalKind `form:"kind"`
+	OperatorID    *uuid.UUID         `form:"operator_id"`
+	PaymentMethod *PaymentMethod     `form:"payment_method"`
+	TransactionID *uuid.UUID         `form:"transaction_id"`
+	StartDate     *time.Time         `form:"start_date"`
+	EndDate       *time.Time         `form:"end_date"`
+}
+
+// CreateLedgerAdjustmentRequest represents a manual correction of the money held by a payment method.
+// A positive amount adds to the balance, a negative one takes from it.
+type CreateLedgerAdjustmentRequest struct {
+	OperatorID    *uuid.UUID    `json:"operator_id"`
+	PaymentMethod PaymentMethod `json:"payment_method" binding:"required,oneof=PAYOS CASH SANDBOX"`
+	Amount        int           `json:"amount" binding:"required,ne=0"`
+	Reason        string        `json:"reason" binding:"required,max=500"`
+	OccurredAt    *time.Time    `json:"occurred_at"`
+}
+
+// SettlementReportQuery selects the days of a settlement report, both ends included
+type SettlementReportQuery struct {
+	From          time.Time      `form:"from" binding:"required" time_format:"2006-01-02"`
+	To            time.Time      `form:"to" binding:"required" time_format:"2006-01-02"`
+	OperatorID    *uuid.UUID     `form:"operator_id"`
+	PaymentMethod *PaymentMethod `form:"payment_method"`
+	Format        string         `form:"format" binding:"omitempty,oneof=csv xlsx"`
+}
+
+// SettlementReportRow is the day of one operator and payment method.
+// ClosingBalance = OpeningBalance + Charges - Refunds - Fees + Adjustments.
+type SettlementReportRow struct {
+	Date           string        `json:"date"`
+	OperatorID     *uuid.UUID    `json:"operator_id"`
+	PaymentMethod  PaymentMethod `json:"payment_method"`
+	OpeningBalance int           `json:"opening_balance"`
+	Charges        int           `json:"charges"`
+	Refunds        int           `json:"refunds"`
+	Fees           int           `json:"fees"`
+	Adjustments    int           `json:"adjustments"`
+	ClosingBalance int           `json:"closing_balance"`
+}
+
+// SettlementReport is the daily settlement of the money held by each payment method
+type SettlementReport struct {
+	From string                 `json:"from"`
+	To   string                 `json:"to"`
+	Rows []*SettlementReportRow `json:"rows"`
+}
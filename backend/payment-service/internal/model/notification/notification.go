@@ -0,0 +1,23 @@
+package notification
+
+type NotificationType string
+
+const (
+	NotificationTypeRefundSent NotificationType = "REFUND_SENT"
+)
+
+// GenericNotificationRequest is the request accepted by notification service for every notification
+type GenericNotificationRequest struct {
+	Type    NotificationType `json:"type"`
+	Payload interface{}      `json:"payload"`
+}
+
+// RefundSentRequest tells a passenger that their refund was transferred
+type RefundSentRequest struct {
+	Email           string `json:"email"`
+	Name            string `json:"name"`
+	RefundAmount    int    `json:"refund_amount"`
+	BankName        string `json:"bank_name"`
+	AccountNumber   string `json:"account_number"`
+	TransferContent string `json:"transfer_content"`
+}
@@ -0,0 +1,100 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	AggregateTypeTransaction  = "transaction"
+	AggregateTypeRefundPayout = "refund_payout"
+	AggregateTypeRefund       = "refund"
+	AggregateTypeWallet       = "wallet"
+
+	// EventTypePaymentStatusChanged tells booking service that a booking's payment changed status
+	EventTypePaymentStatusChanged = "payment.status_changed"
+	// EventTypeRefundPayoutSent tells the passenger that their refund was transferred
+	EventTypeRefundPayoutSent = "refund_payout.sent"
+	// EventTypePaymentCaptured posts a payment and its provider fee to the ledger
+	EventTypePaymentCaptured = "payment.captured"
+	// EventTypeRefundCompleted posts a refund paid back to the passenger to the ledger
+	EventTypeRefundCompleted = "refund.completed"
+	// EventTypeSurplusRefundDue refunds money received that no booking keeps
+	EventTypeSurplusRefundDue = "payment.surplus_refund_due"
+	// EventTypeInvoiceDue issues the VAT invoice asked for at booking time once the payment is captured
+	EventTypeInvoiceDue = "invoice.due"
+	// EventTypeInvoiceAdjustmentDue cancels or replaces the invoice of a payment after a refund was paid back
+	EventTypeInvoiceAdjustmentDue = "invoice.adjustment_due"
+	// EventTypeWalletReleaseDue gives back the wallet credit a payment drew when it was never completed
+	EventTypeWalletReleaseDue = "wallet.release_due"
+	// EventTypePromotionalCreditChanged posts promotional credit handed out or expired to the ledger
+	EventTypePromotionalCreditChanged = "wallet.promotional_credit_changed"
+)
+
+// PaymentStatusChangedEvent is the payload of EventTypePaymentStatusChanged
+type PaymentStatusChangedEvent struct {
+	BookingID         uuid.UUID         `json:"booking_id"`
+	TransactionID     uuid.UUID         `json:"transaction_id"`
+	TransactionStatus TransactionStatus `json:"transaction_status"`
+	AmountPaid        int               `json:"amount_paid"`
+}
+
+// RefundPayoutSentEvent is the payload of EventTypeRefundPayoutSent
+type RefundPayoutSentEvent struct {
+	PayoutID        uuid.UUID `json:"payout_id"`
+	RefundID        uuid.UUID `json:"refund_id"`
+	BookingID       uuid.UUID `json:"booking_id"`
+	UserID          uuid.UUID `json:"user_id"`
+	Amount          int       `json:"amount"`
+	BankCode        string    `json:"bank_code"`
+	AccountNumber   string    `json:"account_number"`
+	TransferContent string    `json:"transfer_content"`
+}
+
+// PaymentCapturedEvent is the payload of EventTypePaymentCaptured
+type PaymentCapturedEvent struct {
+	TransactionID uuid.UUID `json:"transaction_id"`
+	CapturedAt    time.Time `json:"captured_at"`
+}
+
+// RefundCompletedEvent is the payload of EventTypeRefundCompleted
+type RefundCompletedEvent struct {
+	RefundID    uuid.UUID `json:"refund_id"`
+	CompletedAt time.Time `json:"completed_at"`
+}
+
+// SurplusRefundDueEvent is the payload of EventTypeSurplusRefundDue. The refund ID is fixed
+// when the event is written, so a redelivered event creates the refund once.
+type SurplusRefundDueEvent struct {
+	RefundID      uuid.UUID `json:"refund_id"`
+	TransactionID uuid.UUID `json:"transaction_id"`
+	Amount        int       `json:"amount"`
+	Reason        string    `json:"reason"`
+}
+
+// InvoiceDueEvent is the payload of EventTypeInvoiceDue
+type InvoiceDueEvent struct {
+	TransactionID uuid.UUID `json:"transaction_id"`
+}
+
+// InvoiceAdjustmentDueEvent is the payload of EventTypeInvoiceAdjustmentDue
+type InvoiceAdjustmentDueEvent struct {
+	TransactionID uuid.UUID `json:"transaction_id"`
+	RefundID      uuid.UUID `json:"refund_id"`
+}
+
+// WalletReleaseDueEvent is the payload of EventTypeWalletReleaseDue
+type WalletReleaseDueEvent struct {
+	TransactionID uuid.UUID `json:"transaction_id"`
+}
+
+// PromotionalCreditChangedEvent is the payload of EventTypePromotionalCreditChanged. Amount is
+// positive for credit handed out and negative for credit that expired.
+type PromotionalCreditChangedEvent struct {
+	EntryID    uuid.UUID       `json:"entry_id"`
+	UserID     uuid.UUID       `json:"user_id"`
+	Type       WalletEntryType `json:"type"`
+	Amount     int             `json:"amount"`
+	OccurredAt time.Time       `json:"occurred_at"`
+}
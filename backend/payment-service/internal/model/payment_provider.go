@@ -0,0 +1,56 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ProviderPayment is a payment as its provider reports it
+type ProviderPayment struct {
+	OrderCode     int64
+	PaymentLinkID string // empty when the provider has nothing to poll, e.g. cash
+	Status        TransactionStatus
+	Amount        int
+	AmountPaid    int
+	CheckoutURL   string
+	QRCode        string
+	Reference     string // reference of the latest payment received
+	PaidAt        *time.Time
+}
+
+// ProviderWebhook identifies the payment a verified provider notification is about
+type ProviderWebhook struct {
+	EventID       string // the same for every delivery of one notification
+	OrderCode     int64
+	PaymentLinkID string
+	Reference     string
+	PaidAt        *time.Time
+}
+
+// ProviderRefundRequest asks a provider to return money of a paid transaction
+type ProviderRefundRequest struct {
+	RefundID      uuid.UUID
+	TransactionID uuid.UUID
+	PaymentLinkID string
+	Amount        int
+	Reason        string
+}
+
+// ProviderRefund is a refund the provider has paid out
+type ProviderRefund struct {
+	Reference  string
+	RefundedAt time.Time
+}
+
+// ConfirmCashPaymentRequest represents an admin confirming money received at the counter
+type ConfirmCashPaymentRequest struct {
+	ReceiptNumber string `json:"receipt_number" binding:"required,max=100"`
+}
+
+// SandboxPaymentRequest represents a simulated customer action on a sandbox payment
+type SandboxPaymentRequest struct {
+	Status TransactionStatus `json:"status" binding:"required,oneof=PAID CANCELLED FAILED EXPIRED"`
+	// Amount transferred when paying, the outstanding amount when empty. Less leaves the payment UNDERPAID.
+	Amount int `json:"amount" binding:"omitempty,gt=0"`
+}
@@ -0,0 +1,72 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ReconciliationIssueType string
+type ReconciliationIssueStatus string
+type ReconciliationResolution string
+
+const (
+	// ReconciliationIssuePaidAfterExpired means the provider took the payment after the booking could have expired
+	ReconciliationIssuePaidAfterExpired ReconciliationIssueType = "PAID_AFTER_EXPIRED"
+	// ReconciliationIssuePaidAfterCancelled means the provider took the payment of a transaction we had cancelled
+	ReconciliationIssuePaidAfterCancelled ReconciliationIssueType = "PAID_AFTER_CANCELLED"
+
+	ReconciliationIssueStatusOpen     ReconciliationIssueStatus = "OPEN"
+	ReconciliationIssueStatusResolved ReconciliationIssueStatus = "RESOLVED"
+
+	// ReconciliationResolutionApply records the provider status and notifies booking service
+	ReconciliationResolutionApply ReconciliationResolution = "APPLY"
+	// ReconciliationResolutionIgnore records the provider status without touching the booking
+	ReconciliationResolutionIgnore ReconciliationResolution = "IGNORE"
+)
+
+// ReconciliationIssue is a provider payment that was not applied to its booking and waits for an admin
+type ReconciliationIssue struct {
+	ID                uuid.UUID                 `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	TransactionID     uuid.UUID                 `gorm:"type:uuid;not null" json:"transaction_id"`
+	BookingID         uuid.UUID                 `gorm:"type:uuid;not null" json:"booking_id"`
+	IssueType         ReconciliationIssueType   `gorm:"type:varchar(50);not null" json:"issue_type"`
+	LocalStatus       TransactionStatus         `gorm:"type:varchar(50);not null" json:"local_status"`
+	ProviderStatus    TransactionStatus         `gorm:"type:varchar(50);not null" json:"provider_status"`
+	Amount            int                       `gorm:"not null" json:"amount"`
+	ProviderAmount    int                       `gorm:"not null" json:"provider_amount"`
+	ProviderReference string                    `gorm:"type:varchar(255)" json:"provider_reference,omitempty"`
+	ProviderPaidAt    *time.Time                `json:"provider_paid_at,omitempty"`
+	Status            ReconciliationIssueStatus `gorm:"type:varchar(20);not null;default:'OPEN'" json:"status"`
+	Resolution        *ReconciliationResolution `gorm:"type:varchar(20)" json:"resolution,omitempty"`
+	ResolutionNote    *string                   `gorm:"type:text" json:"resolution_note,omitempty"`
+	ResolvedBy        *uuid.UUID                `gorm:"type:uuid" json:"resolved_by,omitempty"`
+	ResolvedAt        *time.Time                `json:"resolved_at,omitempty"`
+	CreatedAt         time.Time                 `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt         time.Time                 `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+func (ReconciliationIssue) TableName() string {
+	return "payment_reconciliation_issues"
+}
+
+// ReconciliationIssueListQuery represents query parameters for listing reconciliation issues
+type ReconciliationIssueListQuery struct {
+	PaginationRequest
+	Status    *ReconciliationIssueStatus `form:"status"`
+	IssueType *ReconciliationIssueType   `form:"issue_type"`
+}
+
+// ResolveReconciliationIssueRequest represents an admin decision on a reconciliation issue
+type ResolveReconciliationIssueRequest struct {
+	Resolution ReconciliationResolution `json:"resolution" binding:"required,oneof=APPLY IGNORE"`
+	Note       string                   `json:"note" binding:"max=500"`
+}
+
+// ReconciliationSummary counts what one reconciliation run did
+type ReconciliationSummary struct {
+	Checked int `json:"checked"`
+	Updated int `json:"updated"`
+	Flagged int `json:"flagged"`
+	Failed  int `json:"failed"`
+}
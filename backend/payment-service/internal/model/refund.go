@@ -19,18 +19,40 @@ type Refund struct {
 	RejectedReason *string      `gorm:"type:text" json:"rejected_reason,omitempty"`
 	ProcessedBy    *uuid.UUID   `gorm:"type:uuid" json:"processed_by,omitempty"`
 	ProcessedAt    *time.Time   `json:"processed_at,omitempty"`
+	// Destination is where the refund is paid back, wallet refunds complete as they are created
+	Destination RefundDestination `gorm:"type:varchar(10);not null;default:'BANK'" json:"destination"`
+	// ParentRefundID is the refund this one was split off, when a refund is drawn on several payments
+	// of the booking or paid back partly to the wallet
+	ParentRefundID *uuid.UUID `gorm:"type:uuid;index" json:"parent_refund_id,omitempty"`
 
 	// Relations (not stored in DB, loaded via preload)
 	Transaction *Transaction `gorm:"foreignKey:TransactionID" json:"transaction,omitempty"`
+	Seats       []RefundSeat `gorm:"foreignKey:RefundID" json:"seats,omitempty"`
+}
+
+// RefundSeat links a refund to a booked seat it pays back. A seat is refunded at most once
+// unless its refund is rejected.
+type RefundSeat struct {
+	RefundID      uuid.UUID `gorm:"type:uuid;primaryKey" json:"refund_id"`
+	BookingSeatID uuid.UUID `gorm:"type:uuid;primaryKey" json:"booking_seat_id"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+func (RefundSeat) TableName() string {
+	return "refund_seats"
 }
 
 type RefundStatus string
+type RefundDestination string
 
 const (
 	RefundStatusPending    RefundStatus = "PENDING"
 	RefundStatusProcessing RefundStatus = "PROCESSING"
 	RefundStatusCompleted  RefundStatus = "COMPLETED"
 	RefundStatusRejected   RefundStatus = "REJECTED"
+
+	RefundDestinationBank   RefundDestination = "BANK"
+	RefundDestinationWallet RefundDestination = "WALLET"
 )
 
 func (Refund) TableName() string {
@@ -44,26 +66,77 @@ func (r *Refund) BeforeCreate(tx *gorm.DB) error {
 	return nil
 }
 
+// IsFinal tells whether the refund can no longer change status
+func (r *Refund) IsFinal() bool {
+	return r.RefundStatus == RefundStatusCompleted || r.RefundStatus == RefundStatusRejected
+}
+
+// BookingSeatIDs lists the seats the refund pays back
+func (r *Refund) BookingSeatIDs() []uuid.UUID {
+	ids := make([]uuid.UUID, len(r.Seats))
+	for i, seat := range r.Seats {
+		ids[i] = seat.BookingSeatID
+	}
+	return ids
+}
+
 // RefundRequest represents a request to create a refund
 type RefundRequest struct {
+	// ID fixes the ID of the refund created, so a redelivered request creates it once
+	ID           uuid.UUID `json:"-"`
 	BookingID    uuid.UUID `json:"booking_id" binding:"required"`
 	Reason       string    `json:"reason" binding:"required,min=10,max=500"`
 	RefundAmount int       `json:"refund_amount" binding:"required,gt=0"`
+	// BookingSeatIDs are the seats refunded, empty when the refund is not for specific seats
+	BookingSeatIDs []uuid.UUID `json:"booking_seat_ids" binding:"omitempty,max=50,unique"`
+	// Destination is where the refund goes: the primary bank account when the passenger has one and
+	// the refund can be paid out, the wallet otherwise
+	Destination RefundDestination `json:"destination" binding:"omitempty,oneof=BANK WALLET"`
+	// ParentRefundID is set on the parts a refund is split into
+	ParentRefundID *uuid.UUID `json:"-"`
+}
+
+// OperatorRefundRequest is a refund requested by another service. The caller picks the ID, so a
+// request retried after a lost response creates the refund once.
+type OperatorRefundRequest struct {
+	RefundRequest
+	ID uuid.UUID `json:"id" binding:"required"`
+}
+
+// SummarizeRefunds folds a refund and the parts split off it into one status: pending or processing
+// while any part is, rejected when a part was rejected, completed once every part is paid back
+func SummarizeRefunds(refunds []*Refund) RefundStatus {
+	status := RefundStatusCompleted
+	for _, refund := range refunds {
+		switch refund.RefundStatus {
+		case RefundStatusPending:
+			return RefundStatusPending
+		case RefundStatusProcessing:
+			status = RefundStatusProcessing
+		case RefundStatusRejected:
+			if status == RefundStatusCompleted {
+				status = RefundStatusRejected
+			}
+		}
+	}
+	return status
 }
 
 // RefundResponse represents a refund transaction with user info
 type RefundResponse struct {
-	ID                    uuid.UUID    `json:"id"`
-	CreatedAt             time.Time    `json:"created_at"`
-	UpdatedAt             time.Time    `json:"updated_at"`
-	BookingID             uuid.UUID    `json:"booking_id"`
-	UserID                uuid.UUID    `json:"user_id"`
-	RefundAmount          int          `json:"refund_amount"`
-	RefundStatus          RefundStatus `json:"refund_status"`
-	RefundReason          string       `json:"refund_reason"`
-	OriginalTransactionID uuid.UUID    `json:"original_transaction_id"` // For compatibility
-	ProcessedBy           *uuid.UUID   `json:"processed_by,omitempty"`
-	ProcessedAt           *time.Time   `json:"processed_at,omitempty"`
+	ID                    uuid.UUID         `json:"id"`
+	CreatedAt             time.Time         `json:"created_at"`
+	UpdatedAt             time.Time         `json:"updated_at"`
+	BookingID             uuid.UUID         `json:"booking_id"`
+	UserID                uuid.UUID         `json:"user_id"`
+	RefundAmount          int               `json:"refund_amount"`
+	RefundStatus          RefundStatus      `json:"refund_status"`
+	RefundReason          string            `json:"refund_reason"`
+	Destination           RefundDestination `json:"destination"`
+	OriginalTransactionID uuid.UUID         `json:"original_transaction_id"` // For compatibility
+	BookingSeatIDs        []uuid.UUID       `json:"booking_seat_ids,omitempty"`
+	ProcessedBy           *uuid.UUID        `json:"processed_by,omitempty"`
+	ProcessedAt           *time.Time        `json:"processed_at,omitempty"`
 
 	// User bank account info (for export)
 	BankCode      string `json:"bank_code,omitempty"`
@@ -0,0 +1,93 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type RefundPayoutStatus string
+
+const (
+	// RefundPayoutStatusQueued means the transfer is in a batch but has not been sent to the bank yet
+	RefundPayoutStatusQueued RefundPayoutStatus = "QUEUED"
+	// RefundPayoutStatusSent means the transfer was submitted to the bank, the passenger is notified
+	RefundPayoutStatusSent RefundPayoutStatus = "SENT"
+	// RefundPayoutStatusConfirmed means the bank statement shows the transfer, the refund is completed
+	RefundPayoutStatusConfirmed RefundPayoutStatus = "CONFIRMED"
+	// RefundPayoutStatusFailed means the transfer was rejected, the refund can go into another batch
+	RefundPayoutStatusFailed RefundPayoutStatus = "FAILED"
+)
+
+// RefundPayoutBatch groups approved refunds that are transferred together
+type RefundPayoutBatch struct {
+	BaseModel
+	CreatedBy   uuid.UUID `gorm:"type:uuid;not null" json:"created_by"`
+	TotalAmount int       `gorm:"not null" json:"total_amount"`
+	PayoutCount int       `gorm:"not null" json:"payout_count"`
+
+	Payouts []*RefundPayout `gorm:"foreignKey:BatchID" json:"payouts,omitempty"`
+}
+
+func (RefundPayoutBatch) TableName() string {
+	return "refund_payout_batches"
+}
+
+// RefundPayout is the bank transfer paying out one refund
+type RefundPayout struct {
+	BaseModel
+	BatchID         uuid.UUID          `gorm:"type:uuid;not null;index" json:"batch_id"`
+	RefundID        uuid.UUID          `gorm:"type:uuid;not null;index" json:"refund_id"`
+	BookingID       uuid.UUID          `gorm:"type:uuid;not null" json:"booking_id"`
+	UserID          uuid.UUID          `gorm:"type:uuid;not null" json:"user_id"`
+	Amount          int                `gorm:"not null" json:"amount"`
+	BankCode        string             `gorm:"type:varchar(20);not null" json:"bank_code"`
+	BankBIN         string             `gorm:"column:bank_bin;type:varchar(10);not null" json:"bank_bin"`
+	AccountNumber   string             `gorm:"type:varchar(50);not null" json:"account_number"`
+	AccountHolder   string             `gorm:"type:varchar(100);not null" json:"account_holder"`
+	TransferContent string             `gorm:"type:varchar(50);not null" json:"transfer_content"`
+	QRPayload       string             `gorm:"column:qr_payload;type:text;not null" json:"qr_payload"`
+	Status          RefundPayoutStatus `gorm:"type:varchar(20);not null;default:'QUEUED'" json:"status"`
+	BankReference   *string            `gorm:"type:varchar(100)" json:"bank_reference,omitempty"`
+	FailureReason   *string            `gorm:"type:text" json:"failure_reason,omitempty"`
+	SentAt          *time.Time         `json:"sent_at,omitempty"`
+	ConfirmedAt     *time.Time         `json:"confirmed_at,omitempty"`
+	FailedAt        *time.Time         `json:"failed_at,omitempty"`
+}
+
+func (RefundPayout) TableName() string {
+	return "refund_payouts"
+}
+
+// CreateRefundPayoutBatchRequest selects the approved refunds to pay out, all of them when empty
+type CreateRefundPayoutBatchRequest struct {
+	RefundIDs []uuid.UUID `json:"refund_ids" binding:"omitempty,max=500"`
+}
+
+// ConfirmRefundPayoutRequest represents an admin matching a payout against the bank statement
+type ConfirmRefundPayoutRequest struct {
+	BankReference string `json:"bank_reference" binding:"required,max=100"`
+}
+
+// FailRefundPayoutRequest represents an admin recording a rejected transfer
+type FailRefundPayoutRequest struct {
+	Reason string `json:"reason" binding:"required,max=500"`
+}
+
+// RefundPayoutBatchListQuery represents query parameters for listing payout batches
+type RefundPayoutBatchListQuery struct {
+	PaginationRequest
+}
+
+// SkippedRefund is an approved refund left out of a batch
+type SkippedRefund struct {
+	RefundID uuid.UUID `json:"refund_id"`
+	Reason   string    `json:"reason"`
+}
+
+// RefundPayoutBatchResponse is a batch with its payouts and the refunds that could not be included
+type RefundPayoutBatchResponse struct {
+	*RefundPayoutBatch
+	StatusCounts map[RefundPayoutStatus]int `json:"status_counts"`
+	Skipped      []SkippedRefund            `json:"skipped,omitempty"`
+}
@@ -12,6 +12,8 @@ type Transaction struct {
 	BookingID       uuid.UUID         `gorm:"type:uuid;not null;index;" json:"booking_id"`
 	UserID          uuid.UUID         `gorm:"type:uuid;not null;index;" json:"user_id"`
 	Amount          int               `gorm:"not null" json:"amount"`
+	AmountPaid      int               `gorm:"not null;default:0" json:"amount_paid"`   // less than Amount while UNDERPAID, more when overpaid
+	WalletAmount    int               `gorm:"not null;default:0" json:"wallet_amount"` // drawn from the wallet, included in AmountPaid
 	Currency        Currency          `gorm:"type:varchar(10);not null" json:"currency"`
 	PaymentMethod   PaymentMethod     `gorm:"type:varchar(50);not null" json:"payment_method"`
 	OrderCode       int64             `gorm:"index;unique" json:"order_code,omitempty"`
@@ -21,11 +23,26 @@ type Transaction struct {
 	QRCode          string            `gorm:"type:text" json:"qr_code,omitempty"`
 	Reference       string            `gorm:"type:varchar(255)" json:"reference,omitempty"`
 	TransactionTime *int64            `json:"transaction_time,omitempty"`
+	ExpiresAt       *time.Time        `json:"expires_at,omitempty"`
 
 	// Transaction type and refund fields
 	TransactionType TransactionType `gorm:"type:varchar(10);not null;default:'IN';index" json:"transaction_type"`
 	RefundStatus    *RefundStatus   `gorm:"type:varchar(20);index" json:"refund_status,omitempty"`
 	RefundAmount    *int            `json:"refund_amount,omitempty"`
+	// RefundedAmount is the running total of the refunds of an IN payment that were not rejected
+	RefundedAmount int `gorm:"not null;default:0" json:"refunded_amount"`
+	// RefundID links an OUT ledger entry to the refund it pays
+	RefundID *uuid.UUID `gorm:"type:uuid;uniqueIndex" json:"refund_id,omitempty"`
+	// OperatorID is the operator the payment is settled to, when the caller knows it
+	OperatorID *uuid.UUID `gorm:"type:uuid" json:"operator_id,omitempty"`
+	// InvoiceBuyer is set when the buyer asked for a VAT invoice at booking time
+	InvoiceBuyer *InvoiceBuyer `gorm:"type:jsonb;serializer:json" json:"invoice_buyer,omitempty"`
+	// LegBookingIDs are the other bookings a combined payment pays for, e.g. the return leg of a
+	// round trip. Booking service hears about the payment through BookingID; refunds of the legs draw on it.
+	LegBookingIDs []uuid.UUID `gorm:"type:jsonb;serializer:json" json:"leg_booking_ids,omitempty"`
+	// OriginalTransactionID is the payment of the booking a fare difference is paid on top of, when the
+	// booking is exchanged for a dearer trip. Refunds of the booking draw on the original payment first.
+	OriginalTransactionID *uuid.UUID `gorm:"type:uuid;index" json:"original_transaction_id,omitempty"`
 }
 
 type Currency string
@@ -36,7 +53,10 @@ type TransactionType string
 const (
 	CurrencyVND Currency = "VND"
 
-	PaymentMethodPayOS PaymentMethod = "PAYOS"
+	PaymentMethodPayOS   PaymentMethod = "PAYOS"
+	PaymentMethodCash    PaymentMethod = "CASH"    // paid at the counter and confirmed by an admin
+	PaymentMethodSandbox PaymentMethod = "SANDBOX" // local provider for development and integration tests
+	PaymentMethodWallet  PaymentMethod = "WALLET"  // paid in full from the wallet, no provider involved
 
 	TransactionStatusPending    TransactionStatus = "PENDING"
 	TransactionStatusCancelled  TransactionStatus = "CANCELLED"
@@ -54,6 +74,99 @@ func (Transaction) TableName() string {
 	return "transactions"
 }
 
+// ReceivedAmount is the money received for a payment. Paid rows recorded before partial
+// payments were tracked have no AmountPaid and received their Amount.
+func (t *Transaction) ReceivedAmount() int {
+	if t.AmountPaid == 0 && t.Status == TransactionStatusPaid {
+		return t.Amount
+	}
+	return t.AmountPaid
+}
+
+// RefundableAmount is what can still be refunded of a payment
+func (t *Transaction) RefundableAmount() int {
+	return t.ReceivedAmount() - t.RefundedAmount
+}
+
+// ProviderAmount is the part of the price charged through the payment provider
+func (t *Transaction) ProviderAmount() int {
+	return t.Amount - t.WalletAmount
+}
+
+// CashRefundableAmount is what can still be refunded to a bank account. What was drawn from the
+// wallet is refunded last, and only to the wallet.
+func (t *Transaction) CashRefundableAmount() int {
+	return max(t.RefundableAmount()-t.WalletAmount, 0)
+}
+
+// BookingPayments are the payment of a booking followed by the fare differences paid on top of it
+// when the booking was exchanged. Refunds of the booking draw on them in that order.
+type BookingPayments []*Transaction
+
+// RefundableAmount is what can still be refunded of the booking
+func (p BookingPayments) RefundableAmount() int {
+	total := 0
+	for _, t := range p {
+		total += t.RefundableAmount()
+	}
+	return total
+}
+
+// CashRefundableAmount is what can still be refunded of the booking to a bank account
+func (p BookingPayments) CashRefundableAmount() int {
+	total := 0
+	for _, t := range p {
+		total += t.CashRefundableAmount()
+	}
+	return total
+}
+
+// RefundPart is the share of a refund drawn on one payment
+type RefundPart struct {
+	Payment     *Transaction
+	Amount      int
+	Destination RefundDestination
+}
+
+// SplitRefund draws a refund on the payments in order: the cash amount goes to a bank account and
+// the wallet amount to the wallet. The amounts must not exceed what the payments can refund.
+func (p BookingPayments) SplitRefund(cash, wallet int) []RefundPart {
+	drawn := make([]int, len(p))
+	var parts []RefundPart
+	draw := func(amount int, destination RefundDestination, refundable func(*Transaction) int) {
+		for i, t := range p {
+			if amount <= 0 {
+				return
+			}
+			part := min(amount, refundable(t)-drawn[i])
+			if part <= 0 {
+				continue
+			}
+			drawn[i] += part
+			amount -= part
+			parts = append(parts, RefundPart{Payment: t, Amount: part, Destination: destination})
+		}
+	}
+
+	draw(cash, RefundDestinationBank, (*Transaction).CashRefundableAmount)
+	draw(wallet, RefundDestinationWallet, (*Transaction).RefundableAmount)
+	return parts
+}
+
+// InvoiceableAmount is what the invoice of a paid booking is made out for: the price, less the
+// refunds paid back beyond what was transferred in excess
+func (t *Transaction) InvoiceableAmount(completedRefunds int) int {
+	return max(min(t.Amount, t.ReceivedAmount()-completedRefunds), 0)
+}
+
+// ExcessAmount is what the customer transferred beyond the price of a paid booking
+func (t *Transaction) ExcessAmount() int {
+	if t.Status != TransactionStatusPaid {
+		return 0
+	}
+	return max(t.ReceivedAmount()-t.Amount, 0)
+}
+
 func (t *Transaction) BeforeCreate(tx *gorm.DB) error {
 	if t.ID == uuid.Nil {
 		t.ID = uuid.New()
@@ -64,29 +177,46 @@ func (t *Transaction) BeforeCreate(tx *gorm.DB) error {
 type CreateTransactionRequest struct {
 	ID            uuid.UUID     `json:"id"`
 	BookingID     uuid.UUID     `json:"booking_id" binding:"required"`
+	OperatorID    *uuid.UUID    `json:"operator_id"`
 	Amount        int           `json:"amount" binding:"required,gt=0"`
 	Currency      Currency      `json:"currency" binding:"required"`
-	PaymentMethod PaymentMethod `json:"payment_method" binding:"required"`
+	PaymentMethod PaymentMethod `json:"payment_method" binding:"required,oneof=PAYOS CASH SANDBOX"`
 	Description   string        `json:"description"`
 	ExpiresAt     time.Time     `json:"expires_at"`
+	// InvoiceBuyer asks for a VAT invoice issued once the payment is captured
+	InvoiceBuyer *InvoiceBuyer `json:"invoice_buyer,omitempty"`
+	// UseWallet draws as much of the amount as the wallet balance allows, the rest goes through PaymentMethod
+	UseWallet bool `json:"use_wallet,omitempty"`
+	// LegBookingIDs are further bookings paid for together with BookingID, e.g. the legs of an itinerary
+	LegBookingIDs []uuid.UUID `json:"leg_booking_ids,omitempty" binding:"omitempty,max=5,unique"`
+	// OriginalTransactionID marks the fare difference of an exchanged booking, paid on top of that payment
+	OriginalTransactionID *uuid.UUID `json:"original_transaction_id,omitempty"`
 }
 
 type TransactionResponse struct {
-	ID              uuid.UUID         `json:"id"`
-	CreatedAt       time.Time         `json:"created_at"`
-	UpdatedAt       time.Time         `json:"updated_at"`
-	BookingID       uuid.UUID         `json:"booking_id"`
-	UserID          uuid.UUID         `json:"user_id"`
-	Amount          int               `json:"amount"`
-	Currency        Currency          `json:"currency"`
-	PaymentMethod   PaymentMethod     `json:"payment_method"`
-	OrderCode       int64             `json:"order_code,omitempty"`
-	Status          TransactionStatus `json:"status"`
-	CheckoutURL     string            `json:"checkout_url,omitempty"`
-	QRCode          string            `json:"qr_code,omitempty"`
-	TransactionType TransactionType   `json:"transaction_type"`
-	RefundStatus    *RefundStatus     `json:"refund_status,omitempty"`
-	RefundAmount    *int              `json:"refund_amount,omitempty"`
+	ID                    uuid.UUID         `json:"id"`
+	CreatedAt             time.Time         `json:"created_at"`
+	UpdatedAt             time.Time         `json:"updated_at"`
+	BookingID             uuid.UUID         `json:"booking_id"`
+	UserID                uuid.UUID         `json:"user_id"`
+	Amount                int               `json:"amount"`
+	AmountPaid            int               `json:"amount_paid"`
+	WalletAmount          int               `json:"wallet_amount"`
+	Currency              Currency          `json:"currency"`
+	PaymentMethod         PaymentMethod     `json:"payment_method"`
+	OrderCode             int64             `json:"order_code,omitempty"`
+	Status                TransactionStatus `json:"status"`
+	CheckoutURL           string            `json:"checkout_url,omitempty"`
+	QRCode                string            `json:"qr_code,omitempty"`
+	TransactionType       TransactionType   `json:"transaction_type"`
+	RefundStatus          *RefundStatus     `json:"refund_status,omitempty"`
+	RefundAmount          *int              `json:"refund_amount,omitempty"`
+	RefundedAmount        int               `json:"refunded_amount"`
+	RefundID              *uuid.UUID        `json:"refund_id,omitempty"`
+	OperatorID            *uuid.UUID        `json:"operator_id,omitempty"`
+	InvoiceBuyer          *InvoiceBuyer     `json:"invoice_buyer,omitempty"`
+	LegBookingIDs         []uuid.UUID       `json:"leg_booking_ids,omitempty"`
+	OriginalTransactionID *uuid.UUID        `json:"original_transaction_id,omitempty"`
 }
 
 // TransactionListQuery represents query parameters for listing transactions
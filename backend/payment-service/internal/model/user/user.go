@@ -0,0 +1,10 @@
+package user
+
+import "github.com/google/uuid"
+
+type User struct {
+	ID       uuid.UUID `json:"id"`
+	Email    string    `json:"email,omitempty"`
+	Phone    string    `json:"phone,omitempty"`
+	FullName string    `json:"full_name"`
+}
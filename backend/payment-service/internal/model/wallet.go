@@ -0,0 +1,114 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type WalletEntryType string
+
+const (
+	// WalletEntryTypeRefund credits a refund paid back to the wallet, it does not expire
+	WalletEntryTypeRefund WalletEntryType = "REFUND"
+	// WalletEntryTypePromotion credits promotional credit handed out by an admin, valid until it expires
+	WalletEntryTypePromotion WalletEntryType = "PROMOTION"
+	// WalletEntryTypePayment draws a booking payment from the wallet
+	WalletEntryTypePayment WalletEntryType = "PAYMENT"
+	// WalletEntryTypePaymentReleased gives back what a payment drew when it was never completed
+	WalletEntryTypePaymentReleased WalletEntryType = "PAYMENT_RELEASED"
+	// WalletEntryTypeExpired takes what is left of promotional credit off the wallet once it expired
+	WalletEntryTypeExpired WalletEntryType = "EXPIRED"
+)
+
+// Wallet holds the balance of a user's wallet, the sum of what is left of their credits.
+// Credit past its expiry stays in the balance until the expiry job takes it off, but is never spent.
+type Wallet struct {
+	UserID    uuid.UUID `gorm:"type:uuid;primaryKey" json:"user_id"`
+	Balance   int       `gorm:"not null;default:0" json:"balance"`
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+func (Wallet) TableName() string {
+	return "wallets"
+}
+
+// WalletEntry is one line of the wallet history. Credits keep what is left of them in Remaining,
+// payments draw from them soonest expiring first.
+type WalletEntry struct {
+	ID           uuid.UUID       `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	UserID       uuid.UUID       `gorm:"type:uuid;not null;index" json:"user_id"`
+	Type         WalletEntryType `gorm:"type:varchar(20);not null" json:"type"`
+	Amount       int             `gorm:"not null" json:"amount"` // negative for payments and expiries
+	BalanceAfter int             `gorm:"not null" json:"balance_after"`
+	Remaining    int             `gorm:"not null;default:0" json:"remaining,omitempty"`
+	ExpiresAt    *time.Time      `json:"expires_at,omitempty"`
+
+	TransactionID *uuid.UUID         `gorm:"type:uuid" json:"transaction_id,omitempty"`
+	BookingID     *uuid.UUID         `gorm:"type:uuid" json:"booking_id,omitempty"`
+	RefundID      *uuid.UUID         `gorm:"type:uuid" json:"refund_id,omitempty"`
+	Allocations   []WalletAllocation `gorm:"type:jsonb;serializer:json" json:"-"`
+	Description   string             `gorm:"type:text;not null;default:''" json:"description"`
+	CreatedBy     *uuid.UUID         `gorm:"type:uuid" json:"created_by,omitempty"`
+	CreatedAt     time.Time          `gorm:"autoCreateTime" json:"created_at"`
+}
+
+func (WalletEntry) TableName() string {
+	return "wallet_entries"
+}
+
+func (e *WalletEntry) BeforeCreate(tx *gorm.DB) error {
+	if e.ID == uuid.Nil {
+		e.ID = uuid.New()
+	}
+	return nil
+}
+
+// IsExpiredAt tells whether the credit can no longer be spent at the given time
+func (e *WalletEntry) IsExpiredAt(at time.Time) bool {
+	return e.ExpiresAt != nil && !e.ExpiresAt.After(at)
+}
+
+// WalletAllocation is the part of a payment drawn from one credit
+type WalletAllocation struct {
+	EntryID uuid.UUID `json:"entry_id"`
+	Amount  int       `json:"amount"`
+}
+
+// WalletCredit is credit left to spend
+type WalletCredit struct {
+	Type      WalletEntryType `json:"type"`
+	Remaining int             `json:"remaining"`
+	ExpiresAt *time.Time      `json:"expires_at,omitempty"`
+}
+
+// WalletResponse is the balance a user can spend, with the promotional credit that expires
+type WalletResponse struct {
+	UserID             uuid.UUID       `json:"user_id"`
+	Balance            int             `json:"balance"`
+	PromotionalBalance int             `json:"promotional_balance"`
+	ExpiringCredits    []*WalletCredit `json:"expiring_credits"`
+}
+
+// WalletEntryListQuery represents query parameters for listing the wallet history
+type WalletEntryListQuery struct {
+	PaginationRequest
+	Type *WalletEntryType `form:"type"`
+}
+
+// GrantPromotionalCreditRequest hands out promotional credit. Without an expiry it is valid for the
+// configured validity period.
+type GrantPromotionalCreditRequest struct {
+	Amount      int        `json:"amount" binding:"required,gt=0"`
+	Description string     `json:"description" binding:"required,max=500"`
+	ExpiresAt   *time.Time `json:"expires_at"`
+}
+
+// WalletExpirySummary reports what one run of the expiry job took off wallets
+type WalletExpirySummary struct {
+	Expired int
+	Amount  int
+	Failed  int
+}
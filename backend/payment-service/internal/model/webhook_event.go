@@ -0,0 +1,60 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type WebhookEventStatus string
+
+const (
+	WebhookEventStatusReceived   WebhookEventStatus = "RECEIVED"
+	WebhookEventStatusProcessing WebhookEventStatus = "PROCESSING"
+	WebhookEventStatusProcessed  WebhookEventStatus = "PROCESSED"
+	// WebhookEventStatusDuplicate means another delivery of the same provider event was already applied
+	WebhookEventStatusDuplicate WebhookEventStatus = "DUPLICATE"
+	WebhookEventStatusFailed    WebhookEventStatus = "FAILED"
+	// WebhookEventStatusRejected means the body could not be parsed or its signature was invalid
+	WebhookEventStatusRejected WebhookEventStatus = "REJECTED"
+)
+
+// WebhookEvent is one delivery of a provider notification and what processing it did
+type WebhookEvent struct {
+	ID             uuid.UUID          `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	PaymentMethod  PaymentMethod      `gorm:"type:varchar(50);not null" json:"payment_method"`
+	EventKey       *string            `gorm:"type:varchar(255)" json:"event_key,omitempty"`
+	RawBody        string             `gorm:"type:text;not null" json:"raw_body"`
+	SignatureValid bool               `gorm:"not null;default:false" json:"signature_valid"`
+	OrderCode      *int64             `json:"order_code,omitempty"`
+	PaymentLinkID  string             `gorm:"type:varchar(255)" json:"payment_link_id,omitempty"`
+	Reference      string             `gorm:"type:varchar(255)" json:"reference,omitempty"`
+	PaidAt         *time.Time         `json:"paid_at,omitempty"`
+	TransactionID  *uuid.UUID         `gorm:"type:uuid" json:"transaction_id,omitempty"`
+	Status         WebhookEventStatus `gorm:"type:varchar(20);not null;default:'RECEIVED'" json:"status"`
+	Error          *string            `gorm:"type:text" json:"error,omitempty"`
+	Attempts       int                `gorm:"not null;default:0" json:"attempts"`
+	ProcessedAt    *time.Time         `json:"processed_at,omitempty"`
+	CreatedAt      time.Time          `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt      time.Time          `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+func (WebhookEvent) TableName() string {
+	return "webhook_events"
+}
+
+// ProviderWebhook rebuilds the verified notification saved on the event
+func (e *WebhookEvent) ProviderWebhook() *ProviderWebhook {
+	webhook := &ProviderWebhook{
+		PaymentLinkID: e.PaymentLinkID,
+		Reference:     e.Reference,
+		PaidAt:        e.PaidAt,
+	}
+	if e.OrderCode != nil {
+		webhook.OrderCode = *e.OrderCode
+	}
+	if e.EventKey != nil {
+		webhook.EventID = *e.EventKey
+	}
+	return webhook
+}
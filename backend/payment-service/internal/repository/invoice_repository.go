@@ -0,0 +1,159 @@
+package repository
+
+import (
+	"bus-booking/payment-service/internal/model"
+	"bus-booking/shared/utils/dbutils"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrInvoiceAlreadyIssued means the payment already has a valid invoice
+var ErrInvoiceAlreadyIssued = errors.New("invoice already issued for the payment")
+
+type InvoiceRepository interface {
+	// Issue numbers the invoice in its operator's series and saves it. Numbering and saving share a
+	// database transaction, so a failed issue leaves no gap in the series.
+	Issue(ctx context.Context, invoice *model.Invoice) error
+	// Replace moves an issued invoice to its new status and issues its replacement, if any, atomically.
+	// It reports false when the invoice was no longer issued.
+	Replace(ctx context.Context, invoice *model.Invoice, replacement *model.Invoice) (bool, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*model.Invoice, error)
+	// GetIssuedByTransactionID returns the valid invoice of a payment, nil when it has none
+	GetIssuedByTransactionID(ctx context.Context, transactionID uuid.UUID) (*model.Invoice, error)
+	// ListByBookingID returns every invoice of a booking, the latest first
+	ListByBookingID(ctx context.Context, bookingID uuid.UUID) ([]*model.Invoice, error)
+}
+
+type InvoiceRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewInvoiceRepository(db *gorm.DB) InvoiceRepository {
+	return &InvoiceRepositoryImpl{db: db}
+}
+
+func (r *InvoiceRepositoryImpl) Issue(ctx context.Context, invoice *model.Invoice) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return issueInvoice(tx, invoice)
+	})
+}
+
+func (r *InvoiceRepositoryImpl) Replace(ctx context.Context, invoice *model.Invoice, replacement *model.Invoice) (bool, error) {
+	replaced := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// The replacement takes the place of the invoice in idx_invoices_transaction_issued, so the
+		// invoice leaves ISSUED first
+		result := tx.Model(&model.Invoice{}).
+			Where("id = ? AND status = ?", invoice.ID, model.InvoiceStatusIssued).
+			Updates(map[string]interface{}{
+				"status":        invoice.Status,
+				"cancelled_at":  invoice.CancelledAt,
+				"cancel_reason": invoice.CancelReason,
+			})
+		if result.Error != nil {
+			return fmt.Errorf("failed to cancel invoice: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+		replaced = true
+
+		if replacement == nil {
+			return nil
+		}
+		replacement.ReplacesID = &invoice.ID
+		if err := issueInvoice(tx, replacement); err != nil {
+			return err
+		}
+		invoice.ReplacedByID = &replacement.ID
+		if err := tx.Model(&model.Invoice{}).
+			Where("id = ?", invoice.ID).
+			Update("replaced_by_id", replacement.ID).Error; err != nil {
+			return fmt.Errorf("failed to link replacement invoice: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+	return replaced, nil
+}
+
+// issueInvoice takes the next number of the invoice's series, locking the payment and the sequence
+// row until the database transaction ends
+func issueInvoice(tx *gorm.DB, invoice *model.Invoice) error {
+	// Locking the payment checks concurrent issues of its invoice one after the other
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ?", invoice.TransactionID).
+		First(&model.Transaction{}).Error; err != nil {
+		return fmt.Errorf("failed to lock transaction: %w", err)
+	}
+	var issued int64
+	if err := tx.Model(&model.Invoice{}).
+		Where("transaction_id = ? AND status = ?", invoice.TransactionID, model.InvoiceStatusIssued).
+		Count(&issued).Error; err != nil {
+		return fmt.Errorf("failed to check issued invoices: %w", err)
+	}
+	if issued > 0 {
+		return ErrInvoiceAlreadyIssued
+	}
+
+	sequence := model.InvoiceSequence{Series: invoice.Series, LastNumber: 1}
+	if invoice.OperatorID != nil {
+		sequence.OperatorID = *invoice.OperatorID
+	}
+	if err := tx.Clauses(
+		clause.OnConflict{
+			Columns: []clause.Column{{Name: "operator_id"}, {Name: "series"}},
+			DoUpdates: clause.Assignments(map[string]interface{}{
+				"last_number": gorm.Expr("invoice_sequences.last_number + 1"),
+				"updated_at":  gorm.Expr("CURRENT_TIMESTAMP"),
+			}),
+		},
+		clause.Returning{Columns: []clause.Column{{Name: "last_number"}}},
+	).Create(&sequence).Error; err != nil {
+		return fmt.Errorf("failed to number invoice: %w", err)
+	}
+
+	invoice.Number = sequence.LastNumber
+	if err := tx.Create(invoice).Error; err != nil {
+		return fmt.Errorf("failed to create invoice: %w", err)
+	}
+	return nil
+}
+
+func (r *InvoiceRepositoryImpl) GetByID(ctx context.Context, id uuid.UUID) (*model.Invoice, error) {
+	var invoice model.Invoice
+	if err := r.db.WithContext(ctx).
+		Where("id = ?", id).
+		First(&invoice).Error; err != nil {
+		return nil, fmt.Errorf("invoice not found: %w", err)
+	}
+	return &invoice, nil
+}
+
+func (r *InvoiceRepositoryImpl) GetIssuedByTransactionID(ctx context.Context, transactionID uuid.UUID) (*model.Invoice, error) {
+	var invoice model.Invoice
+	if err := r.db.WithContext(ctx).
+		Where("transaction_id = ? AND status = ?", transactionID, model.InvoiceStatusIssued).
+		First(&invoice).Error; err != nil {
+		return nil, dbutils.WrapIfNotFound(err, "failed to get invoice")
+	}
+	return &invoice, nil
+}
+
+func (r *InvoiceRepositoryImpl) ListByBookingID(ctx context.Context, bookingID uuid.UUID) ([]*model.Invoice, error) {
+	var invoices []*model.Invoice
+	if err := r.db.WithContext(ctx).
+		Where("booking_id = ?", bookingID).
+		Order("issued_at DESC").
+		Find(&invoices).Error; err != nil {
+		return nil, fmt.Errorf("failed to list invoices: %w", err)
+	}
+	return invoices, nil
+}
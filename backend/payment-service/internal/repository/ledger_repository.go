@@ -0,0 +1,151 @@
+package repository
+
+import (
+	"bus-booking/payment-service/internal/model"
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type LedgerRepository interface {
+	// Post records the journals with their entries atomically. A journal whose kind and source
+	// were already posted is skipped, so redelivered events never post twice.
+	Post(ctx context.Context, journals ...*model.LedgerJournal) error
+	ListJournals(ctx context.Context, query *model.LedgerJournalListQuery) ([]*model.LedgerJournal, int64, error)
+	// GetDailyMovements sums the clearing account per day, operator, payment method and journal kind
+	// over [from, to)
+	GetDailyMovements(ctx context.Context, query *model.SettlementReportQuery, from, to time.Time) ([]*model.LedgerMovement, error)
+	// GetBalances returns the clearing account balance per operator and payment method before the given time
+	GetBalances(ctx context.Context, query *model.SettlementReportQuery, before time.Time) ([]*model.LedgerBalance, error)
+}
+
+type LedgerRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewLedgerRepository(db *gorm.DB) LedgerRepository {
+	return &LedgerRepositoryImpl{db: db}
+}
+
+func (r *LedgerRepositoryImpl) Post(ctx context.Context, journals ...*model.LedgerJournal) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, journal := range journals {
+			if !journal.Balanced() {
+				return fmt.Errorf("ledger journal %s for %s is not balanced", journal.Kind, journal.SourceID)
+			}
+
+			result := tx.Omit(clause.Associations).
+				Clauses(clause.OnConflict{DoNothing: true}).
+				Create(journal)
+			if result.Error != nil {
+				return fmt.Errorf("failed to create ledger journal: %w", result.Error)
+			}
+			if result.RowsAffected == 0 {
+				continue
+			}
+
+			for _, entry := range journal.Entries {
+				entry.JournalID = journal.ID
+				entry.OperatorID = journal.OperatorID
+				entry.PaymentMethod = journal.PaymentMethod
+				entry.OccurredAt = journal.OccurredAt
+			}
+			if err := tx.Create(journal.Entries).Error; err != nil {
+				return fmt.Errorf("failed to create ledger entries: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+func (r *LedgerRepositoryImpl) ListJournals(ctx context.Context, query *model.LedgerJournalListQuery) ([]*model.LedgerJournal, int64, error) {
+	db := r.db.WithContext(ctx).Model(&model.LedgerJournal{})
+
+	// Apply filters
+	if query.Kind != nil {
+		db = db.Where("kind = ?", *query.Kind)
+	}
+	if query.OperatorID != nil {
+		db = db.Where("operator_id = ?", *query.OperatorID)
+	}
+	if query.PaymentMethod != nil {
+		db = db.Where("payment_method = ?", *query.PaymentMethod)
+	}
+	if query.TransactionID != nil {
+		db = db.Where("transaction_id = ?", *query.TransactionID)
+	}
+	if query.StartDate != nil {
+		db = db.Where("occurred_at >= ?", *query.StartDate)
+	}
+	if query.EndDate != nil {
+		db = db.Where("occurred_at <= ?", *query.EndDate)
+	}
+
+	// Get total count
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count ledger journals: %w", err)
+	}
+
+	// Normalize pagination
+	query.Normalize()
+
+	// Calculate offset
+	offset := (query.Page - 1) * query.PageSize
+
+	var journals []*model.LedgerJournal
+	if err := db.
+		Preload("Entries").
+		Offset(offset).
+		Limit(query.PageSize).
+		Order("occurred_at DESC, created_at DESC").
+		Find(&journals).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to list ledger journals: %w", err)
+	}
+
+	return journals, total, nil
+}
+
+func (r *LedgerRepositoryImpl) GetDailyMovements(ctx context.Context, query *model.SettlementReportQuery, from, to time.Time) ([]*model.LedgerMovement, error) {
+	var movements []*model.LedgerMovement
+	if err := r.clearingEntries(ctx, query).
+		Joins("JOIN ledger_journals j ON j.id = ledger_entries.journal_id").
+		Where("ledger_entries.occurred_at >= ? AND ledger_entries.occurred_at < ?", from, to).
+		Select("DATE(ledger_entries.occurred_at) AS day, ledger_entries.operator_id, ledger_entries.payment_method, j.kind, " +
+			"SUM(CASE WHEN ledger_entries.direction = 'DEBIT' THEN ledger_entries.amount ELSE -ledger_entries.amount END) AS amount").
+		Group("day, ledger_entries.operator_id, ledger_entries.payment_method, j.kind").
+		Order("day").
+		Scan(&movements).Error; err != nil {
+		return nil, fmt.Errorf("failed to sum ledger movements: %w", err)
+	}
+	return movements, nil
+}
+
+func (r *LedgerRepositoryImpl) GetBalances(ctx context.Context, query *model.SettlementReportQuery, before time.Time) ([]*model.LedgerBalance, error) {
+	var balances []*model.LedgerBalance
+	if err := r.clearingEntries(ctx, query).
+		Where("ledger_entries.occurred_at < ?", before).
+		Select("ledger_entries.operator_id, ledger_entries.payment_method, " +
+			"SUM(CASE WHEN ledger_entries.direction = 'DEBIT' THEN ledger_entries.amount ELSE -ledger_entries.amount END) AS balance").
+		Group("ledger_entries.operator_id, ledger_entries.payment_method").
+		Scan(&balances).Error; err != nil {
+		return nil, fmt.Errorf("failed to sum ledger balances: %w", err)
+	}
+	return balances, nil
+}
+
+// clearingEntries selects the clearing account entries of the operator and payment method of the report
+func (r *LedgerRepositoryImpl) clearingEntries(ctx context.Context, query *model.SettlementReportQuery) *gorm.DB {
+	db := r.db.WithContext(ctx).Model(&model.LedgerEntry{}).
+		Where("ledger_entries.account = ?", model.LedgerAccountProviderClearing)
+	if query.OperatorID != nil {
+		db = db.Where("ledger_entries.operator_id = ?", *query.OperatorID)
+	}
+	if query.PaymentMethod != nil {
+		db = db.Where("ledger_entries.payment_method = ?", *query.PaymentMethod)
+	}
+	return db
+}
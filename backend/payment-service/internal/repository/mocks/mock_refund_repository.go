@@ -6,6 +6,7 @@ package mocks
 
 import (
 	model "bus-booking/payment-service/internal/model"
+	outbox "bus-booking/shared/outbox"
 	context "context"
 	reflect "reflect"
 
@@ -37,17 +38,22 @@ func (m *MockRefundRepository) EXPECT() *MockRefundRepositoryMockRecorder {
 }
 
 // Create mocks base method.
-func (m *MockRefundRepository) Create(ctx context.Context, refund *model.Refund) error {
+func (m *MockRefundRepository) Create(ctx context.Context, refund *model.Refund, entry *model.Transaction, events ...*outbox.Event) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Create", ctx, refund)
+	varargs := []interface{}{ctx, refund, entry}
+	for _, a := range events {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Create", varargs...)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Create indicates an expected call of Create.
-func (mr *MockRefundRepositoryMockRecorder) Create(ctx, refund interface{}) *gomock.Call {
+func (mr *MockRefundRepositoryMockRecorder) Create(ctx, refund, entry interface{}, events ...interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockRefundRepository)(nil).Create), ctx, refund)
+	varargs := append([]interface{}{ctx, refund, entry}, events...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockRefundRepository)(nil).Create), varargs...)
 }
 
 // GetByBookingID mocks base method.
@@ -95,6 +101,21 @@ func (mr *MockRefundRepositoryMockRecorder) GetCompletedRefundsTotal(ctx interfa
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCompletedRefundsTotal", reflect.TypeOf((*MockRefundRepository)(nil).GetCompletedRefundsTotal), ctx)
 }
 
+// GetCompletedTotalByTransactionID mocks base method.
+func (m *MockRefundRepository) GetCompletedTotalByTransactionID(ctx context.Context, transactionID uuid.UUID) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCompletedTotalByTransactionID", ctx, transactionID)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCompletedTotalByTransactionID indicates an expected call of GetCompletedTotalByTransactionID.
+func (mr *MockRefundRepositoryMockRecorder) GetCompletedTotalByTransactionID(ctx, transactionID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCompletedTotalByTransactionID", reflect.TypeOf((*MockRefundRepository)(nil).GetCompletedTotalByTransactionID), ctx, transactionID)
+}
+
 // GetPendingRefundsStats mocks base method.
 func (m *MockRefundRepository) GetPendingRefundsStats(ctx context.Context) (int, int, error) {
 	m.ctrl.T.Helper()
@@ -127,6 +148,21 @@ func (mr *MockRefundRepositoryMockRecorder) List(ctx, query interface{}) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockRefundRepository)(nil).List), ctx, query)
 }
 
+// ListByBookingID mocks base method.
+func (m *MockRefundRepository) ListByBookingID(ctx context.Context, bookingID uuid.UUID) ([]*model.Refund, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByBookingID", ctx, bookingID)
+	ret0, _ := ret[0].([]*model.Refund)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByBookingID indicates an expected call of ListByBookingID.
+func (mr *MockRefundRepositoryMockRecorder) ListByBookingID(ctx, bookingID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByBookingID", reflect.TypeOf((*MockRefundRepository)(nil).ListByBookingID), ctx, bookingID)
+}
+
 // ListByIDs mocks base method.
 func (m *MockRefundRepository) ListByIDs(ctx context.Context, ids []uuid.UUID) ([]*model.Refund, error) {
 	m.ctrl.T.Helper()
@@ -142,16 +178,37 @@ func (mr *MockRefundRepositoryMockRecorder) ListByIDs(ctx, ids interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByIDs", reflect.TypeOf((*MockRefundRepository)(nil).ListByIDs), ctx, ids)
 }
 
-// Update mocks base method.
-func (m *MockRefundRepository) Update(ctx context.Context, refund *model.Refund) error {
+// ListByParentID mocks base method.
+func (m *MockRefundRepository) ListByParentID(ctx context.Context, parentID uuid.UUID) ([]*model.Refund, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Update", ctx, refund)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "ListByParentID", ctx, parentID)
+	ret0, _ := ret[0].([]*model.Refund)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByParentID indicates an expected call of ListByParentID.
+func (mr *MockRefundRepositoryMockRecorder) ListByParentID(ctx, parentID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByParentID", reflect.TypeOf((*MockRefundRepository)(nil).ListByParentID), ctx, parentID)
+}
+
+// UpdateStatus mocks base method.
+func (m *MockRefundRepository) UpdateStatus(ctx context.Context, refund *model.Refund, events ...*outbox.Event) (bool, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, refund}
+	for _, a := range events {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "UpdateStatus", varargs...)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// Update indicates an expected call of Update.
-func (mr *MockRefundRepositoryMockRecorder) Update(ctx, refund interface{}) *gomock.Call {
+// UpdateStatus indicates an expected call of UpdateStatus.
+func (mr *MockRefundRepositoryMockRecorder) UpdateStatus(ctx, refund interface{}, events ...interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockRefundRepository)(nil).Update), ctx, refund)
+	varargs := append([]interface{}{ctx, refund}, events...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateStatus", reflect.TypeOf((*MockRefundRepository)(nil).UpdateStatus), varargs...)
 }
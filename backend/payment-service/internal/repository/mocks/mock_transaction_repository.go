@@ -6,8 +6,10 @@ package mocks
 
 import (
 	model "bus-booking/payment-service/internal/model"
+	outbox "bus-booking/shared/outbox"
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 	uuid "github.com/google/uuid"
@@ -50,6 +52,20 @@ func (mr *MockTransactionRepositoryMockRecorder) CreateTransaction(ctx, transact
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTransaction", reflect.TypeOf((*MockTransactionRepository)(nil).CreateTransaction), ctx, transaction)
 }
 
+// FlagTransaction mocks base method.
+func (m *MockTransactionRepository) FlagTransaction(ctx context.Context, issue *model.ReconciliationIssue) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FlagTransaction", ctx, issue)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FlagTransaction indicates an expected call of FlagTransaction.
+func (mr *MockTransactionRepositoryMockRecorder) FlagTransaction(ctx, issue interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FlagTransaction", reflect.TypeOf((*MockTransactionRepository)(nil).FlagTransaction), ctx, issue)
+}
+
 // GetByBookingID mocks base method.
 func (m *MockTransactionRepository) GetByBookingID(ctx context.Context, bookingID uuid.UUID) (*model.Transaction, error) {
 	m.ctrl.T.Helper()
@@ -126,6 +142,36 @@ func (mr *MockTransactionRepositoryMockRecorder) GetStats(ctx interface{}) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStats", reflect.TypeOf((*MockTransactionRepository)(nil).GetStats), ctx)
 }
 
+// ListExchangeDifferences mocks base method.
+func (m *MockTransactionRepository) ListExchangeDifferences(ctx context.Context, transactionID uuid.UUID) ([]*model.Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListExchangeDifferences", ctx, transactionID)
+	ret0, _ := ret[0].([]*model.Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListExchangeDifferences indicates an expected call of ListExchangeDifferences.
+func (mr *MockTransactionRepositoryMockRecorder) ListExchangeDifferences(ctx, transactionID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListExchangeDifferences", reflect.TypeOf((*MockTransactionRepository)(nil).ListExchangeDifferences), ctx, transactionID)
+}
+
+// ListUnsettled mocks base method.
+func (m *MockTransactionRepository) ListUnsettled(ctx context.Context, createdBefore time.Time, limit int) ([]*model.Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUnsettled", ctx, createdBefore, limit)
+	ret0, _ := ret[0].([]*model.Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUnsettled indicates an expected call of ListUnsettled.
+func (mr *MockTransactionRepositoryMockRecorder) ListUnsettled(ctx, createdBefore, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUnsettled", reflect.TypeOf((*MockTransactionRepository)(nil).ListUnsettled), ctx, createdBefore, limit)
+}
+
 // UpdateTransaction mocks base method.
 func (m *MockTransactionRepository) UpdateTransaction(ctx context.Context, transaction *model.Transaction) error {
 	m.ctrl.T.Helper()
@@ -139,3 +185,22 @@ func (mr *MockTransactionRepositoryMockRecorder) UpdateTransaction(ctx, transact
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTransaction", reflect.TypeOf((*MockTransactionRepository)(nil).UpdateTransaction), ctx, transaction)
 }
+
+// UpdateTransactionWithEvents mocks base method.
+func (m *MockTransactionRepository) UpdateTransactionWithEvents(ctx context.Context, transaction *model.Transaction, previousStatus model.TransactionStatus, events ...*outbox.Event) error {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, transaction, previousStatus}
+	for _, a := range events {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "UpdateTransactionWithEvents", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateTransactionWithEvents indicates an expected call of UpdateTransactionWithEvents.
+func (mr *MockTransactionRepositoryMockRecorder) UpdateTransactionWithEvents(ctx, transaction, previousStatus interface{}, events ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, transaction, previousStatus}, events...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTransactionWithEvents", reflect.TypeOf((*MockTransactionRepository)(nil).UpdateTransactionWithEvents), varargs...)
+}
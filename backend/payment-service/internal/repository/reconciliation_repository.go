@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"bus-booking/payment-service/internal/model"
+	"bus-booking/shared/outbox"
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type ReconciliationRepository interface {
+	GetByID(ctx context.Context, id uuid.UUID) (*model.ReconciliationIssue, error)
+	List(ctx context.Context, query *model.ReconciliationIssueListQuery) ([]*model.ReconciliationIssue, int64, error)
+	// Resolve closes an open issue and saves its transaction and outbox events atomically.
+	// It reports false when the issue was already resolved.
+	Resolve(ctx context.Context, issue *model.ReconciliationIssue, transaction *model.Transaction, events ...*outbox.Event) (bool, error)
+}
+
+type ReconciliationRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewReconciliationRepository(db *gorm.DB) ReconciliationRepository {
+	return &ReconciliationRepositoryImpl{db: db}
+}
+
+// GetByID retrieves a reconciliation issue by ID
+func (r *ReconciliationRepositoryImpl) GetByID(ctx context.Context, id uuid.UUID) (*model.ReconciliationIssue, error) {
+	var issue model.ReconciliationIssue
+	if err := r.db.WithContext(ctx).
+		Where("id = ?", id).
+		First(&issue).Error; err != nil {
+		return nil, fmt.Errorf("reconciliation issue not found: %w", err)
+	}
+	return &issue, nil
+}
+
+// List retrieves reconciliation issues with filters and pagination
+func (r *ReconciliationRepositoryImpl) List(ctx context.Context, query *model.ReconciliationIssueListQuery) ([]*model.ReconciliationIssue, int64, error) {
+	db := r.db.WithContext(ctx).Model(&model.ReconciliationIssue{})
+
+	if query.Status != nil {
+		db = db.Where("status = ?", *query.Status)
+	}
+	if query.IssueType != nil {
+		db = db.Where("issue_type = ?", *query.IssueType)
+	}
+
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count reconciliation issues: %w", err)
+	}
+
+	query.Normalize()
+	offset := (query.Page - 1) * query.PageSize
+
+	var issues []*model.ReconciliationIssue
+	if err := db.
+		Offset(offset).
+		Limit(query.PageSize).
+		Order("created_at DESC").
+		Find(&issues).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to list reconciliation issues: %w", err)
+	}
+
+	return issues, total, nil
+}
+
+func (r *ReconciliationRepositoryImpl) Resolve(ctx context.Context, issue *model.ReconciliationIssue, transaction *model.Transaction, events ...*outbox.Event) (bool, error) {
+	resolved := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(issue).
+			Where("status = ?", model.ReconciliationIssueStatusOpen).
+			Select("status", "resolution", "resolution_note", "resolved_by", "resolved_at").
+			Updates(issue)
+		if result.Error != nil {
+			return fmt.Errorf("failed to resolve reconciliation issue: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+		resolved = true
+
+		if err := tx.Save(transaction).Error; err != nil {
+			return fmt.Errorf("failed to update transaction: %w", err)
+		}
+		return outbox.Add(tx, events...)
+	})
+	return resolved, err
+}
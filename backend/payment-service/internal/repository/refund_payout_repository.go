@@ -0,0 +1,148 @@
+package repository
+
+import (
+	"bus-booking/payment-service/internal/model"
+	"bus-booking/shared/outbox"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type RefundPayoutRepository interface {
+	// ListPayableRefunds returns approved refunds that have no queued, sent or confirmed payout,
+	// restricted to refundIDs when given
+	ListPayableRefunds(ctx context.Context, refundIDs []uuid.UUID, limit int) ([]*model.Refund, error)
+	// CreateBatch saves a batch together with its payouts
+	CreateBatch(ctx context.Context, batch *model.RefundPayoutBatch) error
+	GetBatch(ctx context.Context, id uuid.UUID) (*model.RefundPayoutBatch, error)
+	ListBatches(ctx context.Context, query *model.RefundPayoutBatchListQuery) ([]*model.RefundPayoutBatch, int64, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*model.RefundPayout, error)
+	// Transition moves payouts out of status from, saving their refunds and outbox events atomically.
+	// It reports false, changing nothing, when any payout is no longer in status from or any refund is already final.
+	Transition(ctx context.Context, payouts []*model.RefundPayout, from model.RefundPayoutStatus, refunds []*model.Refund, events ...*outbox.Event) (bool, error)
+}
+
+type RefundPayoutRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewRefundPayoutRepository(db *gorm.DB) RefundPayoutRepository {
+	return &RefundPayoutRepositoryImpl{db: db}
+}
+
+func (r *RefundPayoutRepositoryImpl) ListPayableRefunds(ctx context.Context, refundIDs []uuid.UUID, limit int) ([]*model.Refund, error) {
+	db := r.db.WithContext(ctx).
+		Where("refund_status = ?", model.RefundStatusProcessing).
+		Where("NOT EXISTS (SELECT 1 FROM refund_payouts p WHERE p.refund_id = refunds.id AND p.status <> ? AND p.deleted_at IS NULL)",
+			model.RefundPayoutStatusFailed)
+	if len(refundIDs) > 0 {
+		db = db.Where("id IN ?", refundIDs)
+	}
+
+	var refunds []*model.Refund
+	if err := db.
+		Order("created_at ASC").
+		Limit(limit).
+		Find(&refunds).Error; err != nil {
+		return nil, fmt.Errorf("failed to list payable refunds: %w", err)
+	}
+	return refunds, nil
+}
+
+func (r *RefundPayoutRepositoryImpl) CreateBatch(ctx context.Context, batch *model.RefundPayoutBatch) error {
+	if err := r.db.WithContext(ctx).Create(batch).Error; err != nil {
+		return fmt.Errorf("failed to create refund payout batch: %w", err)
+	}
+	return nil
+}
+
+func (r *RefundPayoutRepositoryImpl) GetBatch(ctx context.Context, id uuid.UUID) (*model.RefundPayoutBatch, error) {
+	var batch model.RefundPayoutBatch
+	if err := r.db.WithContext(ctx).
+		Preload("Payouts", func(db *gorm.DB) *gorm.DB {
+			return db.Order("created_at ASC")
+		}).
+		Where("id = ?", id).
+		First(&batch).Error; err != nil {
+		return nil, fmt.Errorf("refund payout batch not found: %w", err)
+	}
+	return &batch, nil
+}
+
+func (r *RefundPayoutRepositoryImpl) ListBatches(ctx context.Context, query *model.RefundPayoutBatchListQuery) ([]*model.RefundPayoutBatch, int64, error) {
+	db := r.db.WithContext(ctx).Model(&model.RefundPayoutBatch{})
+
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count refund payout batches: %w", err)
+	}
+
+	query.Normalize()
+	offset := (query.Page - 1) * query.PageSize
+
+	var batches []*model.RefundPayoutBatch
+	if err := db.
+		Preload("Payouts").
+		Offset(offset).
+		Limit(query.PageSize).
+		Order("created_at DESC").
+		Find(&batches).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to list refund payout batches: %w", err)
+	}
+
+	return batches, total, nil
+}
+
+func (r *RefundPayoutRepositoryImpl) GetByID(ctx context.Context, id uuid.UUID) (*model.RefundPayout, error) {
+	var payout model.RefundPayout
+	if err := r.db.WithContext(ctx).
+		Where("id = ?", id).
+		First(&payout).Error; err != nil {
+		return nil, fmt.Errorf("refund payout not found: %w", err)
+	}
+	return &payout, nil
+}
+
+// errPayoutStatusChanged rolls back a transition that lost a race with another admin
+var errPayoutStatusChanged = errors.New("refund payout status changed")
+
+func (r *RefundPayoutRepositoryImpl) Transition(ctx context.Context, payouts []*model.RefundPayout, from model.RefundPayoutStatus, refunds []*model.Refund, events ...*outbox.Event) (bool, error) {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, payout := range payouts {
+			result := tx.Model(payout).
+				Where("status = ?", from).
+				Select("status", "bank_reference", "failure_reason", "sent_at", "confirmed_at", "failed_at").
+				Updates(payout)
+			if result.Error != nil {
+				return fmt.Errorf("failed to update refund payout: %w", result.Error)
+			}
+			if result.RowsAffected == 0 {
+				return errPayoutStatusChanged
+			}
+		}
+
+		for _, refund := range refunds {
+			result := tx.Model(refund).
+				Where("refund_status NOT IN ?", []model.RefundStatus{model.RefundStatusCompleted, model.RefundStatusRejected}).
+				Select("refund_status", "processed_by", "processed_at", "updated_at").
+				Updates(refund)
+			if result.Error != nil {
+				return fmt.Errorf("failed to update refund: %w", result.Error)
+			}
+			if result.RowsAffected == 0 {
+				return errPayoutStatusChanged
+			}
+			if err := syncRefundLedger(tx, refund); err != nil {
+				return err
+			}
+		}
+		return outbox.Add(tx, events...)
+	})
+	if errors.Is(err, errPayoutStatusChanged) {
+		return false, nil
+	}
+	return err == nil, err
+}
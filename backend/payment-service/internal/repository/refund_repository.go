@@ -2,19 +2,40 @@ package repository
 
 import (
 	"bus-booking/payment-service/internal/model"
+	"bus-booking/shared/outbox"
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+var (
+	// ErrRefundExceedsPayment means the refund would take the refunded total of its payment above the amount paid
+	ErrRefundExceedsPayment = errors.New("refund exceeds the refundable amount of the payment")
+	// ErrSeatAlreadyRefunded means a seat is already paid back by a refund that was not rejected
+	ErrSeatAlreadyRefunded = errors.New("seat already refunded")
+)
+
+var errRefundFinalized = errors.New("refund already completed or rejected")
+
 type RefundRepository interface {
 	// Core CRUD
-	Create(ctx context.Context, refund *model.Refund) error
+	// Create records the refund with its seats and OUT ledger entry, adding it to the refunded total of its payment.
+	// A wallet refund is credited to the wallet of the passenger. The outbox events are queued atomically.
+	Create(ctx context.Context, refund *model.Refund, entry *model.Transaction, events ...*outbox.Event) error
 	GetByID(ctx context.Context, id uuid.UUID) (*model.Refund, error)
+	// GetByBookingID returns the latest refund of the booking, not counting the parts split off refunds
 	GetByBookingID(ctx context.Context, bookingID uuid.UUID) (*model.Refund, error)
-	Update(ctx context.Context, refund *model.Refund) error
+	// ListByBookingID returns every refund of the booking, newest first, not counting the parts split off refunds
+	ListByBookingID(ctx context.Context, bookingID uuid.UUID) ([]*model.Refund, error)
+	// ListByParentID returns the parts split off a refund
+	ListByParentID(ctx context.Context, parentID uuid.UUID) ([]*model.Refund, error)
+	// UpdateStatus saves the status of a refund that is not final yet, reporting false if it already was.
+	// A rejected refund is taken off the refunded total of its payment. The outbox events are queued atomically.
+	UpdateStatus(ctx context.Context, refund *model.Refund, events ...*outbox.Event) (bool, error)
 
 	// List & Filter
 	List(ctx context.Context, query *model.RefundListQuery) ([]*model.Refund, int64, error)
@@ -23,6 +44,8 @@ type RefundRepository interface {
 	// Stats
 	GetPendingRefundsStats(ctx context.Context) (totalAmount int, count int, err error)
 	GetCompletedRefundsTotal(ctx context.Context) (int, error)
+	// GetCompletedTotalByTransactionID sums the refunds of a payment that were paid back
+	GetCompletedTotalByTransactionID(ctx context.Context, transactionID uuid.UUID) (int, error)
 }
 
 type RefundRepositoryImpl struct {
@@ -33,18 +56,76 @@ func NewRefundRepository(db *gorm.DB) RefundRepository {
 	return &RefundRepositoryImpl{db: db}
 }
 
-// Create creates a new refund
-func (r *RefundRepositoryImpl) Create(ctx context.Context, refund *model.Refund) error {
-	if err := r.db.WithContext(ctx).Create(refund).Error; err != nil {
-		return fmt.Errorf("failed to create refund: %w", err)
-	}
-	return nil
+// Create locks the payment so concurrent refunds of the same booking are checked one after the other
+func (r *RefundRepositoryImpl) Create(ctx context.Context, refund *model.Refund, entry *model.Transaction, events ...*outbox.Event) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var payment model.Transaction
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ?", refund.TransactionID).
+			First(&payment).Error; err != nil {
+			return fmt.Errorf("failed to lock transaction: %w", err)
+		}
+		// What the payment drew from the wallet can only go back to it
+		refundable := payment.CashRefundableAmount()
+		if refund.Destination == model.RefundDestinationWallet {
+			refundable = payment.RefundableAmount()
+		}
+		if refund.RefundAmount > refundable {
+			return ErrRefundExceedsPayment
+		}
+
+		if seatIDs := refund.BookingSeatIDs(); len(seatIDs) > 0 {
+			var refunded int64
+			if err := tx.Model(&model.RefundSeat{}).
+				Joins("JOIN refunds ON refunds.id = refund_seats.refund_id").
+				Where("refund_seats.booking_seat_id IN ?", seatIDs).
+				Where("refunds.refund_status <> ? AND refunds.deleted_at IS NULL", model.RefundStatusRejected).
+				Count(&refunded).Error; err != nil {
+				return fmt.Errorf("failed to check refunded seats: %w", err)
+			}
+			if refunded > 0 {
+				return ErrSeatAlreadyRefunded
+			}
+		}
+
+		if err := tx.Create(refund).Error; err != nil {
+			return fmt.Errorf("failed to create refund: %w", err)
+		}
+
+		// Ledger entries have no provider order, so the unique order code stays NULL
+		entry.RefundID = &refund.ID
+		if err := tx.Omit("OrderCode").Create(entry).Error; err != nil {
+			return fmt.Errorf("failed to create refund ledger entry: %w", err)
+		}
+
+		if err := tx.Model(&payment).
+			UpdateColumn("refunded_amount", gorm.Expr("refunded_amount + ?", refund.RefundAmount)).Error; err != nil {
+			return fmt.Errorf("failed to update refunded amount: %w", err)
+		}
+
+		if refund.Destination == model.RefundDestinationWallet {
+			transactionID := payment.ID
+			if err := creditWallet(tx, &model.WalletEntry{
+				UserID:        refund.UserID,
+				Type:          model.WalletEntryTypeRefund,
+				Amount:        refund.RefundAmount,
+				TransactionID: &transactionID,
+				BookingID:     &refund.BookingID,
+				RefundID:      &refund.ID,
+				Description:   refund.RefundReason,
+			}); err != nil {
+				return err
+			}
+		}
+		return outbox.Add(tx, events...)
+	})
 }
 
 // GetByID retrieves a refund by ID
 func (r *RefundRepositoryImpl) GetByID(ctx context.Context, id uuid.UUID) (*model.Refund, error) {
 	var refund model.Refund
 	if err := r.db.WithContext(ctx).
+		Preload("Seats").
 		Where("id = ?", id).
 		First(&refund).Error; err != nil {
 		return nil, fmt.Errorf("refund not found: %w", err)
@@ -52,21 +133,95 @@ func (r *RefundRepositoryImpl) GetByID(ctx context.Context, id uuid.UUID) (*mode
 	return &refund, nil
 }
 
-// GetByBookingID retrieves a refund by booking ID
+// GetByBookingID retrieves the latest refund of a booking. Its parts are created after it, so they
+// are left out for the refund they belong to.
 func (r *RefundRepositoryImpl) GetByBookingID(ctx context.Context, bookingID uuid.UUID) (*model.Refund, error) {
 	var refund model.Refund
 	if err := r.db.WithContext(ctx).
-		Where("booking_id = ?", bookingID).
+		Preload("Seats").
+		Where("booking_id = ? AND parent_refund_id IS NULL", bookingID).
+		Order("created_at DESC").
 		First(&refund).Error; err != nil {
 		return nil, fmt.Errorf("refund not found: %w", err)
 	}
 	return &refund, nil
 }
 
-// Update updates a refund
-func (r *RefundRepositoryImpl) Update(ctx context.Context, refund *model.Refund) error {
-	if err := r.db.WithContext(ctx).Save(refund).Error; err != nil {
-		return fmt.Errorf("failed to update refund: %w", err)
+// ListByBookingID retrieves the refunds of a booking, newest first, leaving out the parts split off them
+func (r *RefundRepositoryImpl) ListByBookingID(ctx context.Context, bookingID uuid.UUID) ([]*model.Refund, error) {
+	var refunds []*model.Refund
+	if err := r.db.WithContext(ctx).
+		Preload("Seats").
+		Where("booking_id = ? AND parent_refund_id IS NULL", bookingID).
+		Order("created_at DESC").
+		Find(&refunds).Error; err != nil {
+		return nil, fmt.Errorf("failed to list refunds of booking: %w", err)
+	}
+	return refunds, nil
+}
+
+// ListByParentID retrieves the parts split off a refund, oldest first
+func (r *RefundRepositoryImpl) ListByParentID(ctx context.Context, parentID uuid.UUID) ([]*model.Refund, error) {
+	var refunds []*model.Refund
+	if err := r.db.WithContext(ctx).
+		Where("parent_refund_id = ?", parentID).
+		Order("created_at ASC").
+		Find(&refunds).Error; err != nil {
+		return nil, fmt.Errorf("failed to list refund parts: %w", err)
+	}
+	return refunds, nil
+}
+
+// UpdateStatus guards on the stored status, so a refund is released from its payment only once
+func (r *RefundRepositoryImpl) UpdateStatus(ctx context.Context, refund *model.Refund, events ...*outbox.Event) (bool, error) {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(refund).
+			Where("refund_status NOT IN ?", []model.RefundStatus{model.RefundStatusCompleted, model.RefundStatusRejected}).
+			Select("refund_status", "rejected_reason", "processed_by", "processed_at", "updated_at").
+			Updates(refund)
+		if result.Error != nil {
+			return fmt.Errorf("failed to update refund: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return errRefundFinalized
+		}
+
+		if refund.RefundStatus == model.RefundStatusRejected {
+			if err := tx.Model(&model.Transaction{}).
+				Where("id = ?", refund.TransactionID).
+				UpdateColumn("refunded_amount", gorm.Expr("refunded_amount - ?", refund.RefundAmount)).Error; err != nil {
+				return fmt.Errorf("failed to release refunded amount: %w", err)
+			}
+		}
+
+		if err := syncRefundLedger(tx, refund); err != nil {
+			return err
+		}
+		return outbox.Add(tx, events...)
+	})
+	if errors.Is(err, errRefundFinalized) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// syncRefundLedger mirrors the status of a refund on its OUT ledger entry
+func syncRefundLedger(tx *gorm.DB, refund *model.Refund) error {
+	status := model.TransactionStatusPending
+	switch refund.RefundStatus {
+	case model.RefundStatusCompleted:
+		status = model.TransactionStatusPaid
+	case model.RefundStatusRejected:
+		status = model.TransactionStatusCancelled
+	}
+
+	if err := tx.Model(&model.Transaction{}).
+		Where("refund_id = ? AND transaction_type = ?", refund.ID, model.TransactionTypeOut).
+		Updates(map[string]interface{}{
+			"status":        status,
+			"refund_status": refund.RefundStatus,
+		}).Error; err != nil {
+		return fmt.Errorf("failed to update refund ledger entry: %w", err)
 	}
 	return nil
 }
@@ -102,6 +257,7 @@ func (r *RefundRepositoryImpl) List(ctx context.Context, query *model.RefundList
 	var refunds []*model.Refund
 	if err := db.
 		Preload("Transaction").
+		Preload("Seats").
 		Offset(offset).
 		Limit(query.PageSize).
 		Order("created_at DESC").
@@ -158,3 +314,14 @@ func (r *RefundRepositoryImpl) GetCompletedRefundsTotal(ctx context.Context) (in
 	}
 	return int(total), nil
 }
+
+func (r *RefundRepositoryImpl) GetCompletedTotalByTransactionID(ctx context.Context, transactionID uuid.UUID) (int, error) {
+	var total int64
+	if err := r.db.WithContext(ctx).Model(&model.Refund{}).
+		Where("transaction_id = ? AND refund_status = ?", transactionID, model.RefundStatusCompleted).
+		Select("COALESCE(SUM(refund_amount), 0)").
+		Scan(&total).Error; err != nil {
+		return 0, fmt.Errorf("failed to sum completed refunds of transaction: %w", err)
+	}
+	return int(total), nil
+}
@@ -2,21 +2,29 @@ package repository
 
 import (
 	"bus-booking/payment-service/internal/model"
+	"bus-booking/shared/outbox"
 	"context"
+	"errors"
 	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type TransactionRepository interface {
 	GetList(ctx context.Context, query *model.TransactionListQuery) ([]*model.Transaction, int64, error)
 	GetByID(ctx context.Context, id uuid.UUID) (*model.Transaction, error)
 	GetByBookingID(ctx context.Context, bookingID uuid.UUID) (*model.Transaction, error)
+	ListExchangeDifferences(ctx context.Context, transactionID uuid.UUID) ([]*model.Transaction, error)
 	GetByWebhookData(ctx context.Context, orderCode int, paymentLinkID string) (*model.Transaction, error)
 	GetStats(ctx context.Context) (*model.TransactionStats, error)
 	CreateTransaction(ctx context.Context, transaction *model.Transaction) error
 	UpdateTransaction(ctx context.Context, transaction *model.Transaction) error
+	UpdateTransactionWithEvents(ctx context.Context, transaction *model.Transaction, previousStatus model.TransactionStatus, events ...*outbox.Event) error
+	ListUnsettled(ctx context.Context, createdBefore time.Time, limit int) ([]*model.Transaction, error)
+	FlagTransaction(ctx context.Context, issue *model.ReconciliationIssue) error
 }
 
 type transactionRepositoryImpl struct {
@@ -41,6 +49,53 @@ func (r *transactionRepositoryImpl) UpdateTransaction(ctx context.Context, trans
 	return nil
 }
 
+// ErrTransactionStatusChanged is returned when another update moved the transaction on first
+var ErrTransactionStatusChanged = errors.New("transaction status changed")
+
+// UpdateTransactionWithEvents saves the transaction and queues its outbox events atomically, provided
+// it is still in the previous status. Otherwise nothing is saved and ErrTransactionStatusChanged is returned,
+// so concurrent deliveries of the same payment update queue their events once.
+func (r *transactionRepositoryImpl) UpdateTransactionWithEvents(ctx context.Context, transaction *model.Transaction, previousStatus model.TransactionStatus, events ...*outbox.Event) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(transaction).
+			Where("status = ?", previousStatus).
+			Select("*").
+			Updates(transaction)
+		if result.Error != nil {
+			return fmt.Errorf("failed to update transaction: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return ErrTransactionStatusChanged
+		}
+		return outbox.Add(tx, events...)
+	})
+}
+
+// ListUnsettled lists pending and underpaid payments created before the given time, skipping those waiting for an admin
+func (r *transactionRepositoryImpl) ListUnsettled(ctx context.Context, createdBefore time.Time, limit int) ([]*model.Transaction, error) {
+	var transactions []*model.Transaction
+	if err := r.db.WithContext(ctx).
+		Where("status IN ?", []model.TransactionStatus{model.TransactionStatusPending, model.TransactionStatusProcessing, model.TransactionStatusUnderpaid}).
+		Where("transaction_type = ? AND payment_link_id <> '' AND created_at < ?", model.TransactionTypeIn, createdBefore).
+		Where("NOT EXISTS (SELECT 1 FROM payment_reconciliation_issues i WHERE i.transaction_id = transactions.id AND i.status = ?)", model.ReconciliationIssueStatusOpen).
+		Order("created_at ASC").
+		Limit(limit).
+		Find(&transactions).Error; err != nil {
+		return nil, fmt.Errorf("failed to list unsettled transactions: %w", err)
+	}
+	return transactions, nil
+}
+
+// FlagTransaction records a reconciliation issue; an open issue of the same type is kept as is
+func (r *transactionRepositoryImpl) FlagTransaction(ctx context.Context, issue *model.ReconciliationIssue) error {
+	if err := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(issue).Error; err != nil {
+		return fmt.Errorf("failed to flag transaction: %w", err)
+	}
+	return nil
+}
+
 func (r *transactionRepositoryImpl) GetByID(ctx context.Context, id uuid.UUID) (*model.Transaction, error) {
 	var transaction model.Transaction
 	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&transaction).Error; err != nil {
@@ -59,17 +114,33 @@ func (r *transactionRepositoryImpl) GetByWebhookData(ctx context.Context, orderC
 	return &transaction, nil
 }
 
+// GetByBookingID returns the payment of the booking, which may be a combined payment made for
+// another booking it is a leg of. Fare differences paid on exchanges are left out, and the paid
+// payment comes before later attempts that were cancelled or expired.
 func (r *transactionRepositoryImpl) GetByBookingID(ctx context.Context, bookingID uuid.UUID) (*model.Transaction, error) {
 	var transaction model.Transaction
 	if err := r.db.WithContext(ctx).
-		Where("booking_id = ?", bookingID).
-		Order("created_at DESC").
+		Where("transaction_type = ? AND original_transaction_id IS NULL", model.TransactionTypeIn).
+		Where("booking_id = ? OR leg_booking_ids @> ?", bookingID, fmt.Sprintf(`["%s"]`, bookingID)).
+		Order(clause.OrderBy{Expression: clause.Expr{SQL: "status = ? DESC, created_at DESC", Vars: []interface{}{model.TransactionStatusPaid}}}).
 		First(&transaction).Error; err != nil {
 		return nil, fmt.Errorf("transaction not found: %w", err)
 	}
 	return &transaction, nil
 }
 
+// ListExchangeDifferences lists the fare differences paid on top of a payment, oldest first
+func (r *transactionRepositoryImpl) ListExchangeDifferences(ctx context.Context, transactionID uuid.UUID) ([]*model.Transaction, error) {
+	var transactions []*model.Transaction
+	if err := r.db.WithContext(ctx).
+		Where("original_transaction_id = ? AND status = ?", transactionID, model.TransactionStatusPaid).
+		Order("created_at ASC").
+		Find(&transactions).Error; err != nil {
+		return nil, fmt.Errorf("failed to list exchange differences: %w", err)
+	}
+	return transactions, nil
+}
+
 // GetList lists all transactions with filters (admin)
 func (r *transactionRepositoryImpl) GetList(ctx context.Context, query *model.TransactionListQuery) ([]*model.Transaction, int64, error) {
 	db := r.db.WithContext(ctx).Model(&model.Transaction{})
@@ -126,7 +197,7 @@ func (r *transactionRepositoryImpl) GetStats(ctx context.Context) (*model.Transa
 	var totalIn int64
 	if err := r.db.WithContext(ctx).Model(&model.Transaction{}).
 		Where("transaction_type = ? AND status = ?", model.TransactionTypeIn, model.TransactionStatusPaid).
-		Select("COALESCE(SUM(amount), 0)").
+		Select("COALESCE(SUM(amount_paid), 0)").
 		Scan(&totalIn).Error; err != nil {
 		return nil, fmt.Errorf("failed to sum IN transactions: %w", err)
 	}
@@ -0,0 +1,279 @@
+package repository
+
+import (
+	"bus-booking/payment-service/internal/model"
+	"bus-booking/shared/outbox"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrInsufficientWalletBalance means the wallet no longer holds the credit a payment draws
+var ErrInsufficientWalletBalance = errors.New("insufficient wallet balance")
+
+type WalletRepository interface {
+	// ListAvailableCredits lists the credits the user can spend at the given time, soonest expiring first
+	ListAvailableCredits(ctx context.Context, userID uuid.UUID, at time.Time) ([]*model.WalletEntry, error)
+	ListEntries(ctx context.Context, userID uuid.UUID, query *model.WalletEntryListQuery) ([]*model.WalletEntry, int64, error)
+	// Credit adds credit to the wallet of its user and queues the outbox events atomically
+	Credit(ctx context.Context, entry *model.WalletEntry, events ...*outbox.Event) error
+	// Pay creates the transaction and draws its wallet amount from the wallet of its user, returning
+	// ErrInsufficientWalletBalance when the credit is no longer there. The outbox events are queued atomically.
+	Pay(ctx context.Context, transaction *model.Transaction, description string, events ...*outbox.Event) error
+	// ReleasePayment gives back what a payment drew to the credits it came from, reporting false when the
+	// payment drew nothing or was already released
+	ReleasePayment(ctx context.Context, transactionID uuid.UUID, description string) (bool, error)
+	// ListExpired lists promotional credits expired before the given time with credit left
+	ListExpired(ctx context.Context, before time.Time, limit int) ([]*model.WalletEntry, error)
+	// Expire takes what is left of an expired credit off its wallet with the expiry entry, reporting false
+	// when the credit changed since it was listed. The outbox events are queued atomically.
+	Expire(ctx context.Context, credit *model.WalletEntry, expiry *model.WalletEntry, events ...*outbox.Event) (bool, error)
+}
+
+type walletRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewWalletRepository(db *gorm.DB) WalletRepository {
+	return &walletRepositoryImpl{db: db}
+}
+
+func (r *walletRepositoryImpl) ListAvailableCredits(ctx context.Context, userID uuid.UUID, at time.Time) ([]*model.WalletEntry, error) {
+	var credits []*model.WalletEntry
+	if err := availableCredits(r.db.WithContext(ctx), userID, at).Find(&credits).Error; err != nil {
+		return nil, fmt.Errorf("failed to list wallet credits: %w", err)
+	}
+	return credits, nil
+}
+
+func (r *walletRepositoryImpl) ListEntries(ctx context.Context, userID uuid.UUID, query *model.WalletEntryListQuery) ([]*model.WalletEntry, int64, error) {
+	db := r.db.WithContext(ctx).Model(&model.WalletEntry{}).Where("user_id = ?", userID)
+
+	// Apply filters
+	if query.Type != nil {
+		db = db.Where("type = ?", *query.Type)
+	}
+
+	// Get total count
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count wallet entries: %w", err)
+	}
+
+	// Normalize pagination
+	query.Normalize()
+
+	// Calculate offset
+	offset := (query.Page - 1) * query.PageSize
+
+	var entries []*model.WalletEntry
+	if err := db.
+		Offset(offset).
+		Limit(query.PageSize).
+		Order("created_at DESC").
+		Find(&entries).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to list wallet entries: %w", err)
+	}
+
+	return entries, total, nil
+}
+
+func (r *walletRepositoryImpl) Credit(ctx context.Context, entry *model.WalletEntry, events ...*outbox.Event) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := creditWallet(tx, entry); err != nil {
+			return err
+		}
+		return outbox.Add(tx, events...)
+	})
+}
+
+// Pay locks the credits it draws, so concurrent payments of one user never spend the same credit
+func (r *walletRepositoryImpl) Pay(ctx context.Context, transaction *model.Transaction, description string, events ...*outbox.Event) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		wallet, err := lockWallet(tx, transaction.UserID)
+		if err != nil {
+			return err
+		}
+
+		var credits []*model.WalletEntry
+		if err := availableCredits(tx, transaction.UserID, time.Now()).
+			Clauses(clause.Locking{Strength: "UPDATE"}).
+			Find(&credits).Error; err != nil {
+			return fmt.Errorf("failed to lock wallet credits: %w", err)
+		}
+
+		var allocations []model.WalletAllocation
+		left := transaction.WalletAmount
+		for _, credit := range credits {
+			if left == 0 {
+				break
+			}
+			drawn := min(credit.Remaining, left)
+			if err := tx.Model(credit).
+				UpdateColumn("remaining", gorm.Expr("remaining - ?", drawn)).Error; err != nil {
+				return fmt.Errorf("failed to draw wallet credit: %w", err)
+			}
+			allocations = append(allocations, model.WalletAllocation{EntryID: credit.ID, Amount: drawn})
+			left -= drawn
+		}
+		if left > 0 {
+			return ErrInsufficientWalletBalance
+		}
+
+		if err := tx.Create(transaction).Error; err != nil {
+			return fmt.Errorf("failed to create transaction: %w", err)
+		}
+
+		transactionID, bookingID := transaction.ID, transaction.BookingID
+		return addWalletEntry(tx, wallet, &model.WalletEntry{
+			UserID:        transaction.UserID,
+			Type:          model.WalletEntryTypePayment,
+			Amount:        -transaction.WalletAmount,
+			TransactionID: &transactionID,
+			BookingID:     &bookingID,
+			Allocations:   allocations,
+			Description:   description,
+		}, events...)
+	})
+}
+
+func (r *walletRepositoryImpl) ReleasePayment(ctx context.Context, transactionID uuid.UUID, description string) (bool, error) {
+	released := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var payment model.WalletEntry
+		err := tx.Where("transaction_id = ? AND type = ?", transactionID, model.WalletEntryTypePayment).
+			First(&payment).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get wallet payment: %w", err)
+		}
+
+		wallet, err := lockWallet(tx, payment.UserID)
+		if err != nil {
+			return err
+		}
+
+		var count int64
+		if err := tx.Model(&model.WalletEntry{}).
+			Where("transaction_id = ? AND type = ?", transactionID, model.WalletEntryTypePaymentReleased).
+			Count(&count).Error; err != nil {
+			return fmt.Errorf("failed to check wallet release: %w", err)
+		}
+		if count > 0 {
+			return nil
+		}
+
+		// Credit that expired in the meantime is taken off by the next expiry run
+		for _, allocation := range payment.Allocations {
+			if err := tx.Model(&model.WalletEntry{}).
+				Where("id = ?", allocation.EntryID).
+				UpdateColumn("remaining", gorm.Expr("remaining + ?", allocation.Amount)).Error; err != nil {
+				return fmt.Errorf("failed to restore wallet credit: %w", err)
+			}
+		}
+
+		released = true
+		return addWalletEntry(tx, wallet, &model.WalletEntry{
+			UserID:        payment.UserID,
+			Type:          model.WalletEntryTypePaymentReleased,
+			Amount:        -payment.Amount,
+			TransactionID: payment.TransactionID,
+			BookingID:     payment.BookingID,
+			Description:   description,
+		})
+	})
+	return released, err
+}
+
+func (r *walletRepositoryImpl) ListExpired(ctx context.Context, before time.Time, limit int) ([]*model.WalletEntry, error) {
+	var credits []*model.WalletEntry
+	if err := r.db.WithContext(ctx).
+		Where("remaining > 0 AND expires_at IS NOT NULL AND expires_at <= ?", before).
+		Order("expires_at ASC").
+		Limit(limit).
+		Find(&credits).Error; err != nil {
+		return nil, fmt.Errorf("failed to list expired wallet credits: %w", err)
+	}
+	return credits, nil
+}
+
+func (r *walletRepositoryImpl) Expire(ctx context.Context, credit *model.WalletEntry, expiry *model.WalletEntry, events ...*outbox.Event) (bool, error) {
+	expired := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		wallet, err := lockWallet(tx, credit.UserID)
+		if err != nil {
+			return err
+		}
+
+		// A payment released since may have given some credit back
+		result := tx.Model(&model.WalletEntry{}).
+			Where("id = ? AND remaining = ?", credit.ID, credit.Remaining).
+			UpdateColumn("remaining", 0)
+		if result.Error != nil {
+			return fmt.Errorf("failed to expire wallet credit: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+
+		expired = true
+		return addWalletEntry(tx, wallet, expiry, events...)
+	})
+	return expired, err
+}
+
+// availableCredits selects the credits of the user that can be spent at the given time, in the order they are drawn
+func availableCredits(db *gorm.DB, userID uuid.UUID, at time.Time) *gorm.DB {
+	return db.Model(&model.WalletEntry{}).
+		Where("user_id = ? AND remaining > 0", userID).
+		Where("expires_at IS NULL OR expires_at > ?", at).
+		Order("expires_at ASC NULLS LAST, created_at ASC")
+}
+
+// lockWallet locks the wallet of the user, creating it on first use
+func lockWallet(tx *gorm.DB, userID uuid.UUID) (*model.Wallet, error) {
+	if err := tx.Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&model.Wallet{UserID: userID}).Error; err != nil {
+		return nil, fmt.Errorf("failed to create wallet: %w", err)
+	}
+
+	var wallet model.Wallet
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("user_id = ?", userID).
+		First(&wallet).Error; err != nil {
+		return nil, fmt.Errorf("failed to lock wallet: %w", err)
+	}
+	return &wallet, nil
+}
+
+// creditWallet adds a credit whole to the wallet of its user, within the caller's transaction
+func creditWallet(tx *gorm.DB, entry *model.WalletEntry) error {
+	wallet, err := lockWallet(tx, entry.UserID)
+	if err != nil {
+		return err
+	}
+	entry.Remaining = entry.Amount
+	return addWalletEntry(tx, wallet, entry)
+}
+
+// addWalletEntry records the entry on the locked wallet, moving its balance by the entry amount
+func addWalletEntry(tx *gorm.DB, wallet *model.Wallet, entry *model.WalletEntry, events ...*outbox.Event) error {
+	wallet.Balance += entry.Amount
+	entry.BalanceAfter = wallet.Balance
+
+	if err := tx.Create(entry).Error; err != nil {
+		return fmt.Errorf("failed to create wallet entry: %w", err)
+	}
+	if err := tx.Model(wallet).
+		Update("balance", wallet.Balance).Error; err != nil {
+		return fmt.Errorf("failed to update wallet balance: %w", err)
+	}
+	return outbox.Add(tx, events...)
+}
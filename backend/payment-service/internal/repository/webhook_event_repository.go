@@ -0,0 +1,124 @@
+package repository
+
+import (
+	"bus-booking/payment-service/internal/model"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type WebhookEventRepository interface {
+	Create(ctx context.Context, event *model.WebhookEvent) error
+	GetByID(ctx context.Context, id uuid.UUID) (*model.WebhookEvent, error)
+	// ListForTransaction returns the events of a transaction, including those that failed before it was resolved
+	ListForTransaction(ctx context.Context, transactionID uuid.UUID, orderCode int64) ([]*model.WebhookEvent, error)
+	// Claim marks a verified event as processing. It reports false when the event is already being processed
+	// or another delivery of the same provider event is being or has been processed. A claim left without a
+	// result for webhookClaimTimeout was abandoned; it is marked failed and the event can be claimed again.
+	Claim(ctx context.Context, event *model.WebhookEvent) (bool, error)
+	// SaveResult saves the outcome of receiving or processing an event
+	SaveResult(ctx context.Context, event *model.WebhookEvent) error
+}
+
+// webhookClaimTimeout is how long processing a claimed event may take before the claim is
+// considered abandoned, such as by a crash between claiming and saving the result
+const webhookClaimTimeout = 5 * time.Minute
+
+type webhookEventRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewWebhookEventRepository(db *gorm.DB) WebhookEventRepository {
+	return &webhookEventRepositoryImpl{db: db}
+}
+
+func (r *webhookEventRepositoryImpl) Create(ctx context.Context, event *model.WebhookEvent) error {
+	if err := r.db.WithContext(ctx).Create(event).Error; err != nil {
+		return fmt.Errorf("failed to create webhook event: %w", err)
+	}
+	return nil
+}
+
+func (r *webhookEventRepositoryImpl) GetByID(ctx context.Context, id uuid.UUID) (*model.WebhookEvent, error) {
+	var event model.WebhookEvent
+	if err := r.db.WithContext(ctx).
+		Where("id = ?", id).
+		First(&event).Error; err != nil {
+		return nil, fmt.Errorf("webhook event not found: %w", err)
+	}
+	return &event, nil
+}
+
+func (r *webhookEventRepositoryImpl) ListForTransaction(ctx context.Context, transactionID uuid.UUID, orderCode int64) ([]*model.WebhookEvent, error) {
+	var events []*model.WebhookEvent
+	if err := r.db.WithContext(ctx).
+		Where("transaction_id = ? OR (transaction_id IS NULL AND order_code = ?)", transactionID, orderCode).
+		Order("created_at DESC").
+		Find(&events).Error; err != nil {
+		return nil, fmt.Errorf("failed to list webhook events: %w", err)
+	}
+	return events, nil
+}
+
+func (r *webhookEventRepositoryImpl) Claim(ctx context.Context, event *model.WebhookEvent) (bool, error) {
+	now := time.Now().UTC()
+	claimed := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Release abandoned claims on this provider event first, so they no longer hold idx_webhook_events_applied
+		if err := tx.Model(&model.WebhookEvent{}).
+			Where("payment_method = ? AND event_key = ? AND status = ? AND updated_at < ?",
+				event.PaymentMethod, event.EventKey, model.WebhookEventStatusProcessing, now.Add(-webhookClaimTimeout)).
+			Updates(map[string]interface{}{
+				"status":       model.WebhookEventStatusFailed,
+				"error":        "processing was abandoned before a result was saved",
+				"processed_at": now,
+				"updated_at":   now,
+			}).Error; err != nil {
+			return err
+		}
+
+		// A concurrent delivery that slips past the check is stopped by idx_webhook_events_applied
+		result := tx.Model(&model.WebhookEvent{}).
+			Where("id = ? AND status <> ?", event.ID, model.WebhookEventStatusProcessing).
+			Where(`NOT EXISTS (
+				SELECT 1 FROM webhook_events other
+				WHERE other.payment_method = ? AND other.event_key = ? AND other.id <> ? AND other.status IN ?
+			)`, event.PaymentMethod, event.EventKey, event.ID,
+				[]model.WebhookEventStatus{model.WebhookEventStatusProcessing, model.WebhookEventStatusProcessed}).
+			Updates(map[string]interface{}{
+				"status":     model.WebhookEventStatusProcessing,
+				"attempts":   gorm.Expr("attempts + 1"),
+				"updated_at": now,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		claimed = result.RowsAffected > 0
+		return nil
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to claim webhook event: %w", err)
+	}
+	if !claimed {
+		return false, nil
+	}
+
+	event.Status = model.WebhookEventStatusProcessing
+	event.Attempts++
+	event.UpdatedAt = now
+	return true, nil
+}
+
+func (r *webhookEventRepositoryImpl) SaveResult(ctx context.Context, event *model.WebhookEvent) error {
+	if err := r.db.WithContext(ctx).
+		Model(event).
+		Select("event_key", "signature_valid", "order_code", "payment_link_id", "reference", "paid_at",
+			"transaction_id", "status", "error", "processed_at").
+		Updates(event).Error; err != nil {
+		return fmt.Errorf("failed to save webhook event result: %w", err)
+	}
+	return nil
+}
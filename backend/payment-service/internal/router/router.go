@@ -16,10 +16,16 @@ import (
 )
 
 type Handlers struct {
-	TransactionHandler handler.TransactionHandler
-	BankAccountHandler handler.BankAccountHandler
-	ConstantsHandler   handler.ConstantsHandler
-	RefundHandler      handler.RefundHandler
+	TransactionHandler    handler.TransactionHandler
+	BankAccountHandler    handler.BankAccountHandler
+	ConstantsHandler      handler.ConstantsHandler
+	RefundHandler         handler.RefundHandler
+	ReconciliationHandler handler.ReconciliationHandler
+	RefundPayoutHandler   handler.RefundPayoutHandler
+	LedgerHandler         handler.LedgerHandler
+	WebhookHandler        handler.WebhookHandler
+	InvoiceHandler        handler.InvoiceHandler
+	WalletHandler         handler.WalletHandler
 }
 
 func SetupRoutes(router *gin.Engine, cfg *config.Config, h *Handlers) {
@@ -38,7 +44,14 @@ func SetupRoutes(router *gin.Engine, cfg *config.Config, h *Handlers) {
 
 		transactions := v1.Group("/transactions")
 		{
-			transactions.POST("/webhook", ginext.WrapHandler(h.TransactionHandler.HandleWebhook))
+			transactions.POST("/webhook", ginext.WrapHandler(h.WebhookHandler.HandleWebhook))
+		}
+
+		if cfg.Sandbox.Enabled {
+			sandbox := v1.Group("/sandbox")
+			{
+				sandbox.POST("/payments/:payment_link_id", ginext.WrapHandler(h.WebhookHandler.SimulateSandboxPayment))
+			}
 		}
 	}
 
@@ -60,6 +73,34 @@ func SetupRoutes(router *gin.Engine, cfg *config.Config, h *Handlers) {
 			refunds.POST("", ginext.WrapHandler(h.RefundHandler.Create))
 			refunds.GET("/booking/:booking_id", ginext.WrapHandler(h.RefundHandler.GetByBookingID))
 		}
+
+		wallet := userV1.Group("/wallet")
+		{
+			wallet.GET("", ginext.WrapHandler(h.WalletHandler.GetWallet))
+			wallet.GET("/entries", ginext.WrapHandler(h.WalletHandler.ListEntries))
+		}
+
+		transactions := userV1.Group("/transactions")
+		{
+			transactions.POST("/:id/invoice", ginext.WrapHandler(h.InvoiceHandler.IssueInvoice))
+		}
+
+		invoices := userV1.Group("/invoices")
+		{
+			invoices.GET("/booking/:booking_id", ginext.WrapHandler(h.InvoiceHandler.ListByBookingID))
+			invoices.GET("/:id/pdf", func(c *gin.Context) {
+				req := &ginext.Request{GinCtx: c}
+				if err := h.InvoiceHandler.DownloadPDF(req); err != nil {
+					log.Error().Err(err).Msg("failed to download invoice PDF")
+				}
+			})
+			invoices.GET("/:id/xml", func(c *gin.Context) {
+				req := &ginext.Request{GinCtx: c}
+				if err := h.InvoiceHandler.DownloadXML(req); err != nil {
+					log.Error().Err(err).Msg("failed to download invoice XML")
+				}
+			})
+		}
 	}
 
 	adminV1 := router.Group("/api/v1")
@@ -70,6 +111,13 @@ func SetupRoutes(router *gin.Engine, cfg *config.Config, h *Handlers) {
 		{
 			transactions.GET("", ginext.WrapHandler(h.TransactionHandler.GetList))
 			transactions.GET("/stats", ginext.WrapHandler(h.TransactionHandler.GetStats))
+			transactions.POST("/:id/confirm-cash", ginext.WrapHandler(h.TransactionHandler.ConfirmCashPayment))
+			transactions.GET("/:id/webhook-events", ginext.WrapHandler(h.WebhookHandler.ListTransactionEvents))
+		}
+
+		webhookEvents := adminV1.Group("/webhook-events")
+		{
+			webhookEvents.POST("/:id/replay", ginext.WrapHandler(h.WebhookHandler.ReplayEvent))
 		}
 
 		refunds := adminV1.Group("/refunds")
@@ -83,6 +131,41 @@ func SetupRoutes(router *gin.Engine, cfg *config.Config, h *Handlers) {
 				}
 			})
 		}
+
+		reconciliation := adminV1.Group("/reconciliation")
+		{
+			reconciliation.GET("/issues", ginext.WrapHandler(h.ReconciliationHandler.ListIssues))
+			reconciliation.POST("/issues/:id/resolve", ginext.WrapHandler(h.ReconciliationHandler.ResolveIssue))
+		}
+
+		refundPayouts := adminV1.Group("/refund-payouts")
+		{
+			refundPayouts.POST("/batches", ginext.WrapHandler(h.RefundPayoutHandler.CreateBatch))
+			refundPayouts.GET("/batches", ginext.WrapHandler(h.RefundPayoutHandler.ListBatches))
+			refundPayouts.GET("/batches/:id", ginext.WrapHandler(h.RefundPayoutHandler.GetBatch))
+			refundPayouts.POST("/batches/:id/send", ginext.WrapHandler(h.RefundPayoutHandler.SendBatch))
+			refundPayouts.POST("/:id/confirm", ginext.WrapHandler(h.RefundPayoutHandler.ConfirmPayout))
+			refundPayouts.POST("/:id/fail", ginext.WrapHandler(h.RefundPayoutHandler.FailPayout))
+		}
+
+		ledger := adminV1.Group("/ledger")
+		{
+			ledger.GET("/journals", ginext.WrapHandler(h.LedgerHandler.ListJournals))
+			ledger.POST("/adjustments", ginext.WrapHandler(h.LedgerHandler.CreateAdjustment))
+			ledger.GET("/settlements", ginext.WrapHandler(h.LedgerHandler.GetSettlementReport))
+			ledger.GET("/settlements/export", func(c *gin.Context) {
+				req := &ginext.Request{GinCtx: c}
+				if err := h.LedgerHandler.ExportSettlementReport(req); err != nil {
+					log.Error().Err(err).Msg("failed to export settlement report")
+				}
+			})
+		}
+
+		wallets := adminV1.Group("/wallets")
+		{
+			wallets.POST("/:user_id/promotional-credits", ginext.WrapHandler(h.WalletHandler.GrantPromotionalCredit))
+			wallets.GET("/:user_id/entries", ginext.WrapHandler(h.WalletHandler.ListUserEntries))
+		}
 	}
 
 	internalV1 := router.Group("/api/v1")
@@ -93,5 +176,12 @@ func SetupRoutes(router *gin.Engine, cfg *config.Config, h *Handlers) {
 			transactions.GET("/:id", ginext.WrapHandler(h.TransactionHandler.GetByID))
 			transactions.POST("/:id/cancel", ginext.WrapHandler(h.TransactionHandler.Cancel))
 		}
+
+		refunds := internalV1.Group("/refunds")
+		{
+			refunds.POST("/operator", ginext.WrapHandler(h.RefundHandler.CreateOperatorRefund))
+			refunds.GET("/booking/:booking_id/status", ginext.WrapHandler(h.RefundHandler.GetBookingRefund))
+			refunds.GET("/:id/status", ginext.WrapHandler(h.RefundHandler.GetRefundStatus))
+		}
 	}
 }
@@ -2,33 +2,58 @@ package server
 
 import (
 	"bus-booking/payment-service/internal/client"
+	"bus-booking/payment-service/internal/cronjob"
 	"bus-booking/payment-service/internal/handler"
+	"bus-booking/payment-service/internal/model"
 	"bus-booking/payment-service/internal/repository"
 	"bus-booking/payment-service/internal/router"
 	"bus-booking/payment-service/internal/service"
+	"bus-booking/shared/outbox"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 )
 
-func (s *Server) buildHandler() http.Handler {
+func (s *Server) buildHandler() (http.Handler, *outbox.Relay, *cronjob.ReconciliationCronJob, *cronjob.WalletExpiryCronJob) {
 	transactionRepo := repository.NewTransactionRepository(s.db.DB)
 	bankAccountRepo := repository.NewBankAccountRepository(s.db.DB)
 	refundRepo := repository.NewRefundRepository(s.db.DB) // NEW
+	reconciliationRepo := repository.NewReconciliationRepository(s.db.DB)
+	refundPayoutRepo := repository.NewRefundPayoutRepository(s.db.DB)
+	ledgerRepo := repository.NewLedgerRepository(s.db.DB)
+	webhookEventRepo := repository.NewWebhookEventRepository(s.db.DB)
+	invoiceRepo := repository.NewInvoiceRepository(s.db.DB)
+	walletRepo := repository.NewWalletRepository(s.db.DB)
 
-	// Initialize PayOS client
-	payosClient := service.NewPayOSService(s.cfg.PayOS)
+	// Initialize payment providers
+	providers := service.PaymentProviders{
+		model.PaymentMethodPayOS: service.NewPayOSService(s.cfg.PayOS),
+		model.PaymentMethodCash:  service.NewCashProvider(),
+	}
+	if s.cfg.Sandbox.Enabled {
+		providers[model.PaymentMethodSandbox] = service.NewSandboxProvider(s.cfg.Sandbox.CheckoutURL)
+	}
 	bookingClient := client.NewBookingClient(s.cfg.ServiceName, s.cfg.External.BookingServiceURL)
+	userClient := client.NewUserClient(s.cfg.ServiceName, s.cfg.External.UserServiceURL)
+	notificationClient := client.NewNotificationClient(s.cfg.ServiceName, s.cfg.External.NotificationServiceURL)
 
 	// Initialize constants and Excel services
 	constantsService := service.NewConstantsService()
 	excelService := service.NewExcelService()
 
-	// Initialize services with PayOS client
+	// Initialize services with payment providers
 	transactionService := service.NewTransactionService(
 		transactionRepo,
+		walletRepo,
 		bookingClient,
-		payosClient,
+		providers,
+	)
+
+	webhookService := service.NewWebhookService(
+		webhookEventRepo,
+		transactionRepo,
+		transactionService,
+		providers,
 	)
 
 	bankAccountService := service.NewBankAccountService(
@@ -44,10 +69,70 @@ func (s *Server) buildHandler() http.Handler {
 		excelService,
 	)
 
+	reconciliationService := service.NewReconciliationService(
+		reconciliationRepo,
+		transactionRepo,
+	)
+
+	refundPayoutService := service.NewRefundPayoutService(
+		refundPayoutRepo,
+		refundRepo,
+		bankAccountRepo,
+		constantsService,
+		userClient,
+		notificationClient,
+		s.cfg.Payout.MaxBatchSize,
+	)
+
+	ledgerService := service.NewLedgerService(
+		ledgerRepo,
+		transactionRepo,
+		refundRepo,
+		excelService,
+		service.LedgerFees{
+			model.PaymentMethodPayOS: {
+				BasisPoints: s.cfg.Ledger.PayOSFeeBasisPoints,
+				Fixed:       s.cfg.Ledger.PayOSFixedFee,
+			},
+		},
+	)
+
+	invoiceService := service.NewInvoiceService(
+		invoiceRepo,
+		transactionRepo,
+		refundRepo,
+		s.cfg.Invoice,
+	)
+
+	walletService := service.NewWalletService(
+		walletRepo,
+		s.cfg.Wallet,
+	)
+
+	relay := outbox.NewRelay(s.db.DB, outbox.DefaultRelayConfig())
+	relay.Register(model.EventTypePaymentStatusChanged, transactionService.DeliverPaymentStatusChanged)
+	relay.Register(model.EventTypeRefundPayoutSent, refundPayoutService.DeliverRefundPayoutSent)
+	relay.Register(model.EventTypePaymentCaptured, ledgerService.PostPaymentCaptured)
+	relay.Register(model.EventTypeRefundCompleted, ledgerService.PostRefundCompleted)
+	relay.Register(model.EventTypeSurplusRefundDue, refundService.RefundSurplus)
+	relay.Register(model.EventTypeInvoiceDue, invoiceService.IssueDue)
+	relay.Register(model.EventTypeInvoiceAdjustmentDue, invoiceService.AdjustForRefund)
+	relay.Register(model.EventTypeWalletReleaseDue, walletService.ReleasePayment)
+	relay.Register(model.EventTypePromotionalCreditChanged, ledgerService.PostPromotionalCreditChanged)
+
 	transactionHandler := handler.NewTransactionHandler(transactionService)
 	bankAccountHandler := handler.NewBankAccountHandler(bankAccountService)
 	constantsHandler := handler.NewConstantsHandler(constantsService)
 	refundHandler := handler.NewRefundHandler(refundService)
+	reconciliationHandler := handler.NewReconciliationHandler(reconciliationService)
+	refundPayoutHandler := handler.NewRefundPayoutHandler(refundPayoutService)
+	ledgerHandler := handler.NewLedgerHandler(ledgerService)
+	webhookHandler := handler.NewWebhookHandler(webhookService)
+	invoiceHandler := handler.NewInvoiceHandler(invoiceService)
+	walletHandler := handler.NewWalletHandler(walletService)
+
+	reconciliationCron := cronjob.NewReconciliationCronJob(transactionService, s.cfg.Reconciliation)
+	walletExpiryCron := cronjob.NewWalletExpiryCronJob(walletService, s.cfg.Wallet)
 
 	if s.cfg.Server.IsProduction {
 		gin.SetMode(gin.ReleaseMode)
@@ -57,10 +142,16 @@ func (s *Server) buildHandler() http.Handler {
 
 	engine := gin.New()
 	router.SetupRoutes(engine, s.cfg, &router.Handlers{
-		TransactionHandler: transactionHandler,
-		BankAccountHandler: bankAccountHandler,
-		ConstantsHandler:   constantsHandler,
-		RefundHandler:      refundHandler,
+		TransactionHandler:    transactionHandler,
+		BankAccountHandler:    bankAccountHandler,
+		ConstantsHandler:      constantsHandler,
+		RefundHandler:         refundHandler,
+		ReconciliationHandler: reconciliationHandler,
+		RefundPayoutHandler:   refundPayoutHandler,
+		LedgerHandler:         ledgerHandler,
+		WebhookHandler:        webhookHandler,
+		InvoiceHandler:        invoiceHandler,
+		WalletHandler:         walletHandler,
 	})
-	return engine
+	return engine, relay, reconciliationCron, walletExpiryCron
 }
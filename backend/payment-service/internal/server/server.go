@@ -27,7 +27,7 @@ func NewServer(
 }
 
 func (s *Server) Run() {
-	handler := s.buildHandler()
+	handler, relay, reconciliationCron, walletExpiryCron := s.buildHandler()
 	server := &http.Server{
 		Addr:           s.cfg.GetServerAddr(),
 		Handler:        handler,
@@ -37,6 +37,18 @@ func (s *Server) Run() {
 		MaxHeaderBytes: s.cfg.Server.MaxHeaderBytes,
 	}
 
+	// Start outbox relay in background
+	relayCtx, cancelRelay := context.WithCancel(context.Background())
+	defer cancelRelay()
+
+	go relay.Start(relayCtx)
+
+	// Start payment reconciliation cronjob
+	go reconciliationCron.Start(relayCtx)
+
+	// Start wallet credit expiry cronjob
+	go walletExpiryCron.Start(relayCtx)
+
 	// Start server
 	go func() {
 		log.Info().
@@ -55,6 +67,9 @@ func (s *Server) Run() {
 	<-quit
 
 	log.Info().Msg("Shutdown signal received, shutting down HTTP server...")
+	reconciliationCron.Stop()
+	walletExpiryCron.Stop()
+	cancelRelay()
 
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
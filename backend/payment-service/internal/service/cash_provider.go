@@ -0,0 +1,39 @@
+package service
+
+import (
+	"bus-booking/payment-service/internal/model"
+	"context"
+)
+
+// CashProvider takes payments at the ticket counter. Nothing is sent to a provider:
+// an admin confirms the payment once the money has been received.
+type CashProvider struct{}
+
+func NewCashProvider() PaymentProvider {
+	return &CashProvider{}
+}
+
+func (p *CashProvider) CreatePayment(ctx context.Context, req *model.CreatePaymentLinkRequest) (*model.ProviderPayment, error) {
+	return &model.ProviderPayment{
+		OrderCode: nextOrderCode(),
+		Status:    model.TransactionStatusPending,
+		Amount:    req.Amount,
+	}, nil
+}
+
+func (p *CashProvider) GetPayment(ctx context.Context, paymentLinkID string) (*model.ProviderPayment, error) {
+	return nil, ErrPaymentNotTracked
+}
+
+func (p *CashProvider) CancelPayment(ctx context.Context, paymentLinkID string, reason string) (*model.ProviderPayment, error) {
+	return &model.ProviderPayment{Status: model.TransactionStatusCancelled}, nil
+}
+
+func (p *CashProvider) VerifyWebhook(ctx context.Context, payload map[string]interface{}) (*model.ProviderWebhook, error) {
+	return nil, ErrWebhookNotSupported
+}
+
+// Refund is handed back at the counter like the payment
+func (p *CashProvider) Refund(ctx context.Context, req *model.ProviderRefundRequest) (*model.ProviderRefund, error) {
+	return nil, ErrRefundNotSupported
+}
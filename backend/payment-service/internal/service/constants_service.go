@@ -15,37 +15,37 @@ func NewConstantsService() ConstantsService {
 	return &ConstantsServiceImpl{}
 }
 
-// Vietnamese banks list
+// Vietnamese banks list, BIN is the NAPAS bank identifier used by VietQR
 var vietnameseBanks = []model.BankConstant{
-	{Code: "VCB", ShortName: "Vietcombank", Name: "Ngân hàng TMCP Ngoại Thương Việt Nam"},
-	{Code: "TCB", ShortName: "Techcombank", Name: "Ngân hàng TMCP Kỹ Thương Việt Nam"},
-	{Code: "MB", ShortName: "MBBank", Name: "Ngân hàng TMCP Quân Đội"},
-	{Code: "VTB", ShortName: "VietinBank", Name: "Ngân hàng TMCP Công Thương Việt Nam"},
-	{Code: "BIDV", ShortName: "BIDV", Name: "Ngân hàng TMCP Đầu Tư và Phát Triển Việt Nam"},
-	{Code: "ACB", ShortName: "ACB", Name: "Ngân hàng TMCP Á Châu"},
-	{Code: "AGR", ShortName: "Agribank", Name: "Ngân hàng Nông Nghiệp và Phát Triển Nông Thôn Việt Nam"},
-	{Code: "SAC", ShortName: "Sacombank", Name: "Ngân hàng TMCP Sài Gòn Thương Tín"},
-	{Code: "VPB", ShortName: "VPBank", Name: "Ngân hàng TMCP Việt Nam Thịnh Vượng"},
-	{Code: "TPB", ShortName: "TPBank", Name: "Ngân hàng TMCP Tiên Phong"},
-	{Code: "SCB", ShortName: "SCB", Name: "Ngân hàng TMCP Sài Gòn"},
-	{Code: "HDB", ShortName: "HDBank", Name: "Ngân hàng TMCP Phát Triển TP.HCM"},
-	{Code: "MSB", ShortName: "MSB", Name: "Ngân hàng TMCP Hàng Hải Việt Nam"},
-	{Code: "SHB", ShortName: "SHB", Name: "Ngân hàng TMCP Sài Gòn - Hà Nội"},
-	{Code: "VIB", ShortName: "VIB", Name: "Ngân hàng TMCP Quốc Tế Việt Nam"},
-	{Code: "OCB", ShortName: "OCB", Name: "Ngân hàng TMCP Phương Đông"},
-	{Code: "EIB", ShortName: "Eximbank", Name: "Ngân hàng TMCP Xuất Nhập Khẩu Việt Nam"},
-	{Code: "LPB", ShortName: "LienVietPostBank", Name: "Ngân hàng TMCP Bưu Điện Liên Việt"},
-	{Code: "SEA", ShortName: "SeABank", Name: "Ngân hàng TMCP Đông Nam Á"},
-	{Code: "VAB", ShortName: "VietABank", Name: "Ngân hàng TMCP Việt Á"},
-	{Code: "NAB", ShortName: "NamABank", Name: "Ngân hàng TMCP Nam Á"},
-	{Code: "PGB", ShortName: "PGBank", Name: "Ngân hàng TMCP Xăng Dầu Petrolimex"},
-	{Code: "VCB", ShortName: "Viet Capital Bank", Name: "Ngân hàng TMCP Bản Việt"},
-	{Code: "BAB", ShortName: "BacABank", Name: "Ngân hàng TMCP Bắc Á"},
-	{Code: "PVC", ShortName: "PVCombank", Name: "Ngân hàng TMCP Đại Chúng Việt Nam"},
-	{Code: "KLB", ShortName: "Kienlongbank", Name: "Ngân hàng TMCP Kiên Long"},
-	{Code: "CAKE", ShortName: "CAKE", Name: "CAKE by VPBank"},
-	{Code: "UBANK", ShortName: "Ubank", Name: "Ubank by VPBank"},
-	{Code: "TIMO", ShortName: "Timo", Name: "Timo by VPBank"},
+	{Code: "VCB", ShortName: "Vietcombank", BIN: "970436", Name: "Ngân hàng TMCP Ngoại Thương Việt Nam"},
+	{Code: "TCB", ShortName: "Techcombank", BIN: "970407", Name: "Ngân hàng TMCP Kỹ Thương Việt Nam"},
+	{Code: "MB", ShortName: "MBBank", BIN: "970422", Name: "Ngân hàng TMCP Quân Đội"},
+	{Code: "VTB", ShortName: "VietinBank", BIN: "970415", Name: "Ngân hàng TMCP Công Thương Việt Nam"},
+	{Code: "BIDV", ShortName: "BIDV", BIN: "970418", Name: "Ngân hàng TMCP Đầu Tư và Phát Triển Việt Nam"},
+	{Code: "ACB", ShortName: "ACB", BIN: "970416", Name: "Ngân hàng TMCP Á Châu"},
+	{Code: "AGR", ShortName: "Agribank", BIN: "970405", Name: "Ngân hàng Nông Nghiệp và Phát Triển Nông Thôn Việt Nam"},
+	{Code: "SAC", ShortName: "Sacombank", BIN: "970403", Name: "Ngân hàng TMCP Sài Gòn Thương Tín"},
+	{Code: "VPB", ShortName: "VPBank", BIN: "970432", Name: "Ngân hàng TMCP Việt Nam Thịnh Vượng"},
+	{Code: "TPB", ShortName: "TPBank", BIN: "970423", Name: "Ngân hàng TMCP Tiên Phong"},
+	{Code: "SCB", ShortName: "SCB", BIN: "970429", Name: "Ngân hàng TMCP Sài Gòn"},
+	{Code: "HDB", ShortName: "HDBank", BIN: "970437", Name: "Ngân hàng TMCP Phát Triển TP.HCM"},
+	{Code: "MSB", ShortName: "MSB", BIN: "970426", Name: "Ngân hàng TMCP Hàng Hải Việt Nam"},
+	{Code: "SHB", ShortName: "SHB", BIN: "970443", Name: "Ngân hàng TMCP Sài Gòn - Hà Nội"},
+	{Code: "VIB", ShortName: "VIB", BIN: "970441", Name: "Ngân hàng TMCP Quốc Tế Việt Nam"},
+	{Code: "OCB", ShortName: "OCB", BIN: "970448", Name: "Ngân hàng TMCP Phương Đông"},
+	{Code: "EIB", ShortName: "Eximbank", BIN: "970431", Name: "Ngân hàng TMCP Xuất Nhập Khẩu Việt Nam"},
+	{Code: "LPB", ShortName: "LienVietPostBank", BIN: "970449", Name: "Ngân hàng TMCP Bưu Điện Liên Việt"},
+	{Code: "SEA", ShortName: "SeABank", BIN: "970440", Name: "Ngân hàng TMCP Đông Nam Á"},
+	{Code: "VAB", ShortName: "VietABank", BIN: "970427", Name: "Ngân hàng TMCP Việt Á"},
+	{Code: "NAB", ShortName: "NamABank", BIN: "970428", Name: "Ngân hàng TMCP Nam Á"},
+	{Code: "PGB", ShortName: "PGBank", BIN: "970430", Name: "Ngân hàng TMCP Xăng Dầu Petrolimex"},
+	{Code: "VCB", ShortName: "Viet Capital Bank", BIN: "970454", Name: "Ngân hàng TMCP Bản Việt"},
+	{Code: "BAB", ShortName: "BacABank", BIN: "970409", Name: "Ngân hàng TMCP Bắc Á"},
+	{Code: "PVC", ShortName: "PVCombank", BIN: "970412", Name: "Ngân hàng TMCP Đại Chúng Việt Nam"},
+	{Code: "KLB", ShortName: "Kienlongbank", BIN: "970452", Name: "Ngân hàng TMCP Kiên Long"},
+	{Code: "CAKE", ShortName: "CAKE", BIN: "546034", Name: "CAKE by VPBank"},
+	{Code: "UBANK", ShortName: "Ubank", BIN: "546035", Name: "Ubank by VPBank"},
+	{Code: "TIMO", ShortName: "Timo", BIN: "963388", Name: "Timo by VPBank"},
 }
 
 func (s *ConstantsServiceImpl) GetBanks(ctx context.Context) ([]model.BankConstant, error) {
@@ -11,6 +11,7 @@ import (
 
 type ExcelService interface {
 	GenerateRefundExcel(refunds []*model.RefundExportItem) ([]byte, error)
+	GenerateSettlementExcel(report *model.SettlementReport) ([]byte, error)
 }
 
 type ExcelServiceImpl struct{}
@@ -282,6 +283,179 @@ func (s *ExcelServiceImpl) GenerateRefundExcel(refunds []*model.RefundExportItem
 	return buffer.Bytes(), nil
 }
 
+func (s *ExcelServiceImpl) GenerateSettlementExcel(report *model.SettlementReport) ([]byte, error) {
+	f := excelize.NewFile()
+	defer func() {
+		if err := f.Close(); err != nil {
+			log.Error().Err(err).Msg("failed to close file")
+		}
+	}()
+
+	sheetName := "Đối Soát"
+	index, err := f.NewSheet(sheetName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create sheet: %w", err)
+	}
+
+	// Set as active sheet
+	f.SetActiveSheet(index)
+	// Delete default sheet
+	if err = f.DeleteSheet("Sheet1"); err != nil {
+		log.Error().Err(err).Msg("failed to delete default sheet")
+	}
+
+	// Define header style
+	headerStyle, err := f.NewStyle(&excelize.Style{
+		Font: &excelize.Font{
+			Bold: true,
+			Size: 12,
+		},
+		Fill: excelize.Fill{
+			Type:    "pattern",
+			Color:   []string{"#4472C4"},
+			Pattern: 1,
+		},
+		Alignment: &excelize.Alignment{
+			Horizontal: "center",
+			Vertical:   "center",
+		},
+		Border: []excelize.Border{
+			{Type: "left", Color: "000000", Style: 1},
+			{Type: "top", Color: "000000", Style: 1},
+			{Type: "bottom", Color: "000000", Style: 1},
+			{Type: "right", Color: "000000", Style: 1},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create header style: %w", err)
+	}
+
+	// Define data style
+	dataStyle, err := f.NewStyle(&excelize.Style{
+		Border: []excelize.Border{
+			{Type: "left", Color: "000000", Style: 1},
+			{Type: "top", Color: "000000", Style: 1},
+			{Type: "bottom", Color: "000000", Style: 1},
+			{Type: "right", Color: "000000", Style: 1},
+		},
+		Alignment: &excelize.Alignment{
+			Vertical: "center",
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create data style: %w", err)
+	}
+
+	// Define currency style for amount columns
+	currencyStyle, err := f.NewStyle(&excelize.Style{
+		Border: []excelize.Border{
+			{Type: "left", Color: "000000", Style: 1},
+			{Type: "top", Color: "000000", Style: 1},
+			{Type: "bottom", Color: "000000", Style: 1},
+			{Type: "right", Color: "000000", Style: 1},
+		},
+		Alignment: &excelize.Alignment{
+			Horizontal: "right",
+			Vertical:   "center",
+		},
+		CustomNumFmt: strPtr("#,##0"),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create currency style: %w", err)
+	}
+
+	// Set column widths
+	columnWidths := map[string]float64{
+		"A": 14, // Ngày
+		"B": 38, // Nhà xe
+		"C": 16, // Phương thức
+		"D": 18, // Số dư đầu ngày
+		"E": 18, // Thu
+		"F": 18, // Hoàn tiền
+		"G": 18, // Phí
+		"H": 18, // Điều chỉnh
+		"I": 18, // Số dư cuối ngày
+	}
+	for col, width := range columnWidths {
+		if err := f.SetColWidth(sheetName, col, col, width); err != nil {
+			return nil, fmt.Errorf("failed to set column width: %w", err)
+		}
+	}
+
+	// Set header
+	headers := []string{
+		"Ngày",
+		"Nhà Xe",
+		"Phương Thức",
+		"Số Dư Đầu Ngày",
+		"Thu",
+		"Hoàn Tiền",
+		"Phí",
+		"Điều Chỉnh",
+		"Số Dư Cuối Ngày",
+	}
+
+	for i, header := range headers {
+		cell := string(rune('A'+i)) + "1"
+		if err := f.SetCellValue(sheetName, cell, header); err != nil {
+			return nil, fmt.Errorf("failed to set header: %w", err)
+		}
+		if err := f.SetCellStyle(sheetName, cell, cell, headerStyle); err != nil {
+			return nil, fmt.Errorf("failed to set header style: %w", err)
+		}
+	}
+
+	// Set row height for header
+	if err := f.SetRowHeight(sheetName, 1, 25); err != nil {
+		return nil, fmt.Errorf("failed to set row height: %w", err)
+	}
+
+	// Fill data
+	for i, row := range report.Rows {
+		rowStr := strconv.Itoa(i + 2)
+
+		operator := "Chưa xác định"
+		if row.OperatorID != nil {
+			operator = row.OperatorID.String()
+		}
+
+		values := []interface{}{
+			row.Date,
+			operator,
+			string(row.PaymentMethod),
+			row.OpeningBalance,
+			row.Charges,
+			row.Refunds,
+			row.Fees,
+			row.Adjustments,
+			row.ClosingBalance,
+		}
+		for j, value := range values {
+			cell := string(rune('A'+j)) + rowStr
+			if err := f.SetCellValue(sheetName, cell, value); err != nil {
+				return nil, fmt.Errorf("failed to set cell value: %w", err)
+			}
+
+			// Amounts from column D on
+			style := dataStyle
+			if j >= 3 {
+				style = currencyStyle
+			}
+			if err := f.SetCellStyle(sheetName, cell, cell, style); err != nil {
+				return nil, fmt.Errorf("failed to set cell style: %w", err)
+			}
+		}
+	}
+
+	// Generate file in memory
+	buffer, err := f.WriteToBuffer()
+	if err != nil {
+		return nil, fmt.Errorf("failed to write to buffer: %w", err)
+	}
+
+	return buffer.Bytes(), nil
+}
+
 func strPtr(s string) *string {
 	return &s
 }
@@ -165,3 +165,29 @@ func TestGenerateRefundExcel_LargeDataset(t *testing.T) {
 	assert.NotNil(t, excelData)
 	assert.Greater(t, len(excelData), 0, "Should handle large datasets")
 }
+
+func TestGenerateSettlementExcel_Success(t *testing.T) {
+	service := NewExcelService()
+
+	report := &model.SettlementReport{
+		From: "2026-03-01",
+		To:   "2026-03-01",
+		Rows: []*model.SettlementReportRow{
+			{
+				Date:           "2026-03-01",
+				PaymentMethod:  model.PaymentMethodPayOS,
+				OpeningBalance: 100000,
+				Charges:        200000,
+				Fees:           3000,
+				ClosingBalance: 297000,
+			},
+		},
+	}
+
+	excelData, err := service.GenerateSettlementExcel(report)
+
+	assert.NoError(t, err)
+	assert.Greater(t, len(excelData), 0, "Excel file should have data")
+	assert.Equal(t, byte(0x50), excelData[0], "Should start with ZIP signature 'PK'")
+	assert.Equal(t, byte(0x4B), excelData[1], "Should start with ZIP signature 'PK'")
+}
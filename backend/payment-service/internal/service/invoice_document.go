@@ -0,0 +1,289 @@
+package service
+
+import (
+	"bus-booking/payment-service/internal/model"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/jung-kurt/gofpdf"
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+const invoiceDateLayout = "2006-01-02"
+
+// The XML follows the layout of the e-invoices sent to the tax authority (Circular 78/2021),
+// keeping the fields of a single line invoice
+type invoiceXML struct {
+	XMLName xml.Name       `xml:"HDon"`
+	Data    invoiceXMLData `xml:"DLHDon"`
+}
+
+type invoiceXMLData struct {
+	ID      string            `xml:"Id,attr"`
+	General invoiceXMLGeneral `xml:"TTChung"`
+	Content invoiceXMLContent `xml:"NDHDon"`
+}
+
+type invoiceXMLGeneral struct {
+	Version       string                `xml:"PBan"`
+	Name          string                `xml:"THDon"`
+	TemplateCode  string                `xml:"KHMSHDon"`
+	Symbol        string                `xml:"KHHDon"`
+	Number        int                   `xml:"SHDon"`
+	IssuedOn      string                `xml:"NLap"`
+	Currency      string                `xml:"DVTTe"`
+	ExchangeRate  int                   `xml:"TGia"`
+	PaymentMethod string                `xml:"HTTToan"`
+	Related       *invoiceXMLRelated    `xml:"TTHDLQuan,omitempty"`
+	Cancelled     *invoiceXMLCancelInfo `xml:"TTHuy,omitempty"`
+}
+
+// invoiceXMLRelated names the invoice a replacement invoice replaces
+type invoiceXMLRelated struct {
+	Kind         int    `xml:"TCHDon"`
+	TemplateCode string `xml:"KHMSHDCLQuan"`
+	Symbol       string `xml:"KHHDCLQuan"`
+	Number       int    `xml:"SHDCLQuan"`
+	IssuedOn     string `xml:"NLHDCLQuan"`
+}
+
+type invoiceXMLCancelInfo struct {
+	CancelledOn string `xml:"NHuy"`
+	Reason      string `xml:"LDo"`
+}
+
+type invoiceXMLContent struct {
+	Seller invoiceXMLParty  `xml:"NBan"`
+	Buyer  invoiceXMLParty  `xml:"NMua"`
+	Items  []invoiceXMLItem `xml:"DSHHDVu>HHDVu"`
+	Totals invoiceXMLTotals `xml:"TToan"`
+}
+
+type invoiceXMLParty struct {
+	Name      string `xml:"Ten"`
+	TaxCode   string `xml:"MST"`
+	Address   string `xml:"DChi"`
+	Purchaser string `xml:"HVTNMHang,omitempty"`
+	Email     string `xml:"DCTDTu,omitempty"`
+}
+
+type invoiceXMLItem struct {
+	Kind      int    `xml:"TChat"`
+	Line      int    `xml:"STT"`
+	Name      string `xml:"THHDVu"`
+	Unit      string `xml:"DVTinh"`
+	Quantity  int    `xml:"SLuong"`
+	UnitPrice int    `xml:"DGia"`
+	Amount    int    `xml:"ThTien"`
+	VATRate   string `xml:"TSuat"`
+}
+
+type invoiceXMLTotals struct {
+	AmountBeforeTax int `xml:"TgTCThue"`
+	VATAmount       int `xml:"TgTThue"`
+	TotalAmount     int `xml:"TgTTTBSo"`
+}
+
+// splitInvoiceSeries splits an invoice series such as 1C26TBB into its template code and symbol
+func splitInvoiceSeries(series string) (string, string) {
+	if len(series) < 2 {
+		return series, ""
+	}
+	return series[:1], series[1:]
+}
+
+// renderInvoiceXML writes the invoice; replaced is the invoice it replaces, if any
+func renderInvoiceXML(invoice *model.Invoice, replaced *model.Invoice) ([]byte, error) {
+	templateCode, symbol := splitInvoiceSeries(invoice.Series)
+	vatRate := fmt.Sprintf("%d%%", invoice.VATRate)
+
+	document := invoiceXML{
+		Data: invoiceXMLData{
+			ID: invoice.ID.String(),
+			General: invoiceXMLGeneral{
+				Version:       "2.0.0",
+				Name:          "Hóa đơn giá trị gia tăng",
+				TemplateCode:  templateCode,
+				Symbol:        symbol,
+				Number:        invoice.Number,
+				IssuedOn:      invoice.IssuedAt.Format(invoiceDateLayout),
+				Currency:      string(model.CurrencyVND),
+				ExchangeRate:  1,
+				PaymentMethod: "TM/CK",
+			},
+			Content: invoiceXMLContent{
+				Seller: invoiceXMLParty{
+					Name:    invoice.SellerName,
+					TaxCode: invoice.SellerTaxCode,
+					Address: invoice.SellerAddress,
+				},
+				Buyer: invoiceXMLParty{
+					Name:      invoice.BuyerCompanyName,
+					TaxCode:   invoice.BuyerTaxCode,
+					Address:   invoice.BuyerAddress,
+					Purchaser: invoice.BuyerName,
+					Email:     invoice.BuyerEmail,
+				},
+				Items: []invoiceXMLItem{{
+					Kind:      1,
+					Line:      1,
+					Name:      invoice.Description,
+					Unit:      "Vé",
+					Quantity:  1,
+					UnitPrice: invoice.AmountBeforeTax,
+					Amount:    invoice.AmountBeforeTax,
+					VATRate:   vatRate,
+				}},
+				Totals: invoiceXMLTotals{
+					AmountBeforeTax: invoice.AmountBeforeTax,
+					VATAmount:       invoice.VATAmount,
+					TotalAmount:     invoice.TotalAmount,
+				},
+			},
+		},
+	}
+	if replaced != nil {
+		replacedTemplateCode, replacedSymbol := splitInvoiceSeries(replaced.Series)
+		document.Data.General.Related = &invoiceXMLRelated{
+			Kind:         1, // replacement
+			TemplateCode: replacedTemplateCode,
+			Symbol:       replacedSymbol,
+			Number:       replaced.Number,
+			IssuedOn:     replaced.IssuedAt.Format(invoiceDateLayout),
+		}
+	}
+	if invoice.CancelledAt != nil {
+		cancelled := &invoiceXMLCancelInfo{CancelledOn: invoice.CancelledAt.Format(invoiceDateLayout)}
+		if invoice.CancelReason != nil {
+			cancelled.Reason = *invoice.CancelReason
+		}
+		document.Data.General.Cancelled = cancelled
+	}
+
+	data, err := xml.MarshalIndent(document, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal invoice XML: %w", err)
+	}
+	return append([]byte(xml.Header), data...), nil
+}
+
+// renderInvoicePDF draws the invoice; replaced is the invoice it replaces, if any
+func renderInvoicePDF(invoice *model.Invoice, replaced *model.Invoice) ([]byte, error) {
+	templateCode, symbol := splitInvoiceSeries(invoice.Series)
+
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.AddPage()
+
+	pdf.SetFont("Arial", "B", 18)
+	pdf.CellFormat(0, 10, "HOA DON GIA TRI GIA TANG", "", 1, "C", false, 0, "")
+	pdf.SetFont("Arial", "I", 11)
+	pdf.CellFormat(0, 6, fmt.Sprintf("Ngay %s", invoice.IssuedAt.Format("02/01/2006")), "", 1, "C", false, 0, "")
+	pdf.SetFont("Arial", "", 11)
+	pdf.CellFormat(0, 6, fmt.Sprintf("Mau so: %s   Ky hieu: %s   So: %s", templateCode, symbol, invoice.InvoiceNumber()), "", 1, "C", false, 0, "")
+
+	if invoice.Status != model.InvoiceStatusIssued {
+		pdf.SetTextColor(220, 38, 38)
+		pdf.SetFont("Arial", "B", 14)
+		pdf.CellFormat(0, 10, "DA HUY", "", 1, "C", false, 0, "")
+		pdf.SetTextColor(0, 0, 0)
+	}
+	if replaced != nil {
+		pdf.SetFont("Arial", "I", 10)
+		pdf.CellFormat(0, 6, fmt.Sprintf("Thay the hoa don ky hieu %s so %s ngay %s",
+			replaced.Series, replaced.InvoiceNumber(), replaced.IssuedAt.Format("02/01/2006")), "", 1, "C", false, 0, "")
+	}
+	pdf.Ln(5)
+
+	addInvoiceSection(pdf, "Don vi ban hang")
+	addInvoiceRow(pdf, "Ten don vi:", invoice.SellerName)
+	addInvoiceRow(pdf, "Ma so thue:", invoice.SellerTaxCode)
+	addInvoiceRow(pdf, "Dia chi:", invoice.SellerAddress)
+	pdf.Ln(3)
+
+	addInvoiceSection(pdf, "Don vi mua hang")
+	if invoice.BuyerName != "" {
+		addInvoiceRow(pdf, "Nguoi mua hang:", invoice.BuyerName)
+	}
+	addInvoiceRow(pdf, "Ten don vi:", invoice.BuyerCompanyName)
+	addInvoiceRow(pdf, "Ma so thue:", invoice.BuyerTaxCode)
+	addInvoiceRow(pdf, "Dia chi:", invoice.BuyerAddress)
+	addInvoiceRow(pdf, "Hinh thuc thanh toan:", "TM/CK")
+	pdf.Ln(5)
+
+	headers := []string{"STT", "Ten hang hoa, dich vu", "DVT", "SL", "Don gia", "Thanh tien"}
+	widths := []float64{12, 70, 18, 12, 34, 34}
+	pdf.SetFont("Arial", "B", 10)
+	pdf.SetFillColor(240, 240, 240)
+	for i, header := range headers {
+		pdf.CellFormat(widths[i], 8, header, "1", 0, "C", true, 0, "")
+	}
+	pdf.Ln(-1)
+	pdf.SetFont("Arial", "", 10)
+	row := []string{"1", pdfText(invoice.Description), "Ve", "1", formatVND(invoice.AmountBeforeTax), formatVND(invoice.AmountBeforeTax)}
+	for i, cell := range row {
+		align := "R"
+		if i == 1 {
+			align = "L"
+		}
+		pdf.CellFormat(widths[i], 8, cell, "1", 0, align, false, 0, "")
+	}
+	pdf.Ln(10)
+
+	addInvoiceRow(pdf, "Cong tien hang:", formatVND(invoice.AmountBeforeTax))
+	addInvoiceRow(pdf, fmt.Sprintf("Thue GTGT (%d%%):", invoice.VATRate), formatVND(invoice.VATAmount))
+	addInvoiceRow(pdf, "Tong tien thanh toan:", formatVND(invoice.TotalAmount))
+
+	if invoice.CancelReason != nil {
+		pdf.Ln(5)
+		addInvoiceRow(pdf, "Ly do huy:", *invoice.CancelReason)
+	}
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, fmt.Errorf("failed to render invoice PDF: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func addInvoiceSection(pdf *gofpdf.Fpdf, title string) {
+	pdf.SetFont("Arial", "B", 12)
+	pdf.CellFormat(0, 8, title, "", 1, "L", false, 0, "")
+}
+
+func addInvoiceRow(pdf *gofpdf.Fpdf, label, value string) {
+	pdf.SetFont("Arial", "", 11)
+	pdf.CellFormat(50, 7, label, "", 0, "L", false, 0, "")
+	pdf.SetFont("Arial", "B", 11)
+	pdf.MultiCell(0, 7, pdfText(value), "", "L", false)
+}
+
+// pdfText drops the Vietnamese diacritics the core PDF fonts cannot draw
+func pdfText(s string) string {
+	s = strings.NewReplacer("đ", "d", "Đ", "D").Replace(s)
+	result, _, err := transform.String(transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC), s)
+	if err != nil {
+		return s
+	}
+	return result
+}
+
+// formatVND writes an amount with dot thousands separators, as printed on Vietnamese invoices
+func formatVND(amount int) string {
+	if amount < 0 {
+		return "-" + formatVND(-amount)
+	}
+	digits := fmt.Sprintf("%d", amount)
+	var b strings.Builder
+	for i, digit := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			b.WriteByte('.')
+		}
+		b.WriteRune(digit)
+	}
+	return b.String()
+}
@@ -0,0 +1,290 @@
+package service
+
+import (
+	"bus-booking/payment-service/config"
+	"bus-booking/payment-service/internal/model"
+	"bus-booking/payment-service/internal/repository"
+	"bus-booking/shared/ginext"
+	"bus-booking/shared/outbox"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+type InvoiceService interface {
+	// IssueInvoice issues the VAT invoice of a paid booking to the passenger who paid it
+	IssueInvoice(ctx context.Context, transactionID uuid.UUID, userID uuid.UUID, req *model.IssueInvoiceRequest) (*model.Invoice, error)
+	ListByBookingID(ctx context.Context, bookingID uuid.UUID, userID uuid.UUID) ([]*model.Invoice, error)
+	GeneratePDF(ctx context.Context, id uuid.UUID, userID uuid.UUID) (*model.Invoice, []byte, error)
+	GenerateXML(ctx context.Context, id uuid.UUID, userID uuid.UUID) (*model.Invoice, []byte, error)
+	// IssueDue is the outbox handler issuing the invoice asked for at booking time
+	IssueDue(ctx context.Context, event *outbox.Event) error
+	// AdjustForRefund is the outbox handler cancelling the invoice of a refunded payment, replacing it
+	// with one for what the buyer still paid unless everything was refunded
+	AdjustForRefund(ctx context.Context, event *outbox.Event) error
+}
+
+type InvoiceServiceImpl struct {
+	invoiceRepo     repository.InvoiceRepository
+	transactionRepo repository.TransactionRepository
+	refundRepo      repository.RefundRepository
+	cfg             config.InvoiceConfig
+}
+
+func NewInvoiceService(
+	invoiceRepo repository.InvoiceRepository,
+	transactionRepo repository.TransactionRepository,
+	refundRepo repository.RefundRepository,
+	cfg config.InvoiceConfig,
+) InvoiceService {
+	return &InvoiceServiceImpl{
+		invoiceRepo:     invoiceRepo,
+		transactionRepo: transactionRepo,
+		refundRepo:      refundRepo,
+		cfg:             cfg,
+	}
+}
+
+// newInvoiceDueEvent queues the invoice of a payment whose buyer asked for one at booking time
+func newInvoiceDueEvent(transaction *model.Transaction) (*outbox.Event, error) {
+	return outbox.NewEvent(model.AggregateTypeTransaction, transaction.ID, model.EventTypeInvoiceDue, &model.InvoiceDueEvent{
+		TransactionID: transaction.ID,
+	})
+}
+
+// newInvoiceAdjustmentEvent queues the adjustment of the invoice of a refunded payment
+func newInvoiceAdjustmentEvent(refund *model.Refund) (*outbox.Event, error) {
+	return outbox.NewEvent(model.AggregateTypeRefund, refund.ID, model.EventTypeInvoiceAdjustmentDue, &model.InvoiceAdjustmentDueEvent{
+		TransactionID: refund.TransactionID,
+		RefundID:      refund.ID,
+	})
+}
+
+func (s *InvoiceServiceImpl) IssueInvoice(ctx context.Context, transactionID uuid.UUID, userID uuid.UUID, req *model.IssueInvoiceRequest) (*model.Invoice, error) {
+	transaction, err := s.transactionRepo.GetByID(ctx, transactionID)
+	if err != nil || transaction.UserID != userID {
+		return nil, ginext.NewNotFoundError("transaction not found")
+	}
+	if transaction.TransactionType != model.TransactionTypeIn || transaction.Status != model.TransactionStatusPaid {
+		return nil, ginext.NewBadRequestError("invoices are only issued for paid bookings")
+	}
+
+	amount, err := s.invoiceableAmount(ctx, transaction)
+	if err != nil {
+		return nil, ginext.NewInternalServerError("failed to get refunds of the payment")
+	}
+	if amount == 0 {
+		return nil, ginext.NewBadRequestError("the payment was refunded in full")
+	}
+
+	invoice := s.newInvoice(transaction, &req.InvoiceBuyer, amount)
+	if err := s.invoiceRepo.Issue(ctx, invoice); err != nil {
+		if errors.Is(err, repository.ErrInvoiceAlreadyIssued) {
+			return nil, ginext.NewConflictError("an invoice was already issued for this booking")
+		}
+		log.Error().Err(err).Str("transaction_id", transactionID.String()).Msg("Failed to issue invoice")
+		return nil, ginext.NewInternalServerError("failed to issue invoice")
+	}
+
+	return invoice, nil
+}
+
+func (s *InvoiceServiceImpl) ListByBookingID(ctx context.Context, bookingID uuid.UUID, userID uuid.UUID) ([]*model.Invoice, error) {
+	invoices, err := s.invoiceRepo.ListByBookingID(ctx, bookingID)
+	if err != nil {
+		log.Error().Err(err).Str("booking_id", bookingID.String()).Msg("Failed to list invoices")
+		return nil, ginext.NewInternalServerError("failed to list invoices")
+	}
+	for _, invoice := range invoices {
+		if invoice.UserID != userID {
+			return nil, ginext.NewNotFoundError("booking not found")
+		}
+	}
+	return invoices, nil
+}
+
+func (s *InvoiceServiceImpl) GeneratePDF(ctx context.Context, id uuid.UUID, userID uuid.UUID) (*model.Invoice, []byte, error) {
+	invoice, err := s.getOwnedInvoice(ctx, id, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	replaced, err := s.getReplacedInvoice(ctx, invoice)
+	if err != nil {
+		return nil, nil, err
+	}
+	data, err := renderInvoicePDF(invoice, replaced)
+	if err != nil {
+		log.Error().Err(err).Str("invoice_id", id.String()).Msg("Failed to render invoice PDF")
+		return nil, nil, ginext.NewInternalServerError("failed to generate invoice PDF")
+	}
+	return invoice, data, nil
+}
+
+func (s *InvoiceServiceImpl) GenerateXML(ctx context.Context, id uuid.UUID, userID uuid.UUID) (*model.Invoice, []byte, error) {
+	invoice, err := s.getOwnedInvoice(ctx, id, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	replaced, err := s.getReplacedInvoice(ctx, invoice)
+	if err != nil {
+		return nil, nil, err
+	}
+	data, err := renderInvoiceXML(invoice, replaced)
+	if err != nil {
+		log.Error().Err(err).Str("invoice_id", id.String()).Msg("Failed to render invoice XML")
+		return nil, nil, ginext.NewInternalServerError("failed to generate invoice XML")
+	}
+	return invoice, data, nil
+}
+
+func (s *InvoiceServiceImpl) getOwnedInvoice(ctx context.Context, id uuid.UUID, userID uuid.UUID) (*model.Invoice, error) {
+	invoice, err := s.invoiceRepo.GetByID(ctx, id)
+	if err != nil || invoice.UserID != userID {
+		return nil, ginext.NewNotFoundError("invoice not found")
+	}
+	return invoice, nil
+}
+
+// getReplacedInvoice returns the invoice a replacement invoice replaces, nil for other invoices
+func (s *InvoiceServiceImpl) getReplacedInvoice(ctx context.Context, invoice *model.Invoice) (*model.Invoice, error) {
+	if invoice.ReplacesID == nil {
+		return nil, nil
+	}
+	replaced, err := s.invoiceRepo.GetByID(ctx, *invoice.ReplacesID)
+	if err != nil {
+		log.Error().Err(err).Str("invoice_id", invoice.ID.String()).Msg("Failed to get replaced invoice")
+		return nil, ginext.NewInternalServerError("failed to get replaced invoice")
+	}
+	return replaced, nil
+}
+
+func (s *InvoiceServiceImpl) IssueDue(ctx context.Context, event *outbox.Event) error {
+	var payload model.InvoiceDueEvent
+	if err := event.Decode(&payload); err != nil {
+		return outbox.Permanent(err)
+	}
+
+	transaction, err := s.transactionRepo.GetByID(ctx, payload.TransactionID)
+	if err != nil {
+		return err
+	}
+	if transaction.InvoiceBuyer == nil || transaction.Status != model.TransactionStatusPaid {
+		return nil
+	}
+
+	amount, err := s.invoiceableAmount(ctx, transaction)
+	if err != nil {
+		return err
+	}
+	// Refunded in full before the invoice went out, there is nothing left to invoice
+	if amount == 0 {
+		return nil
+	}
+
+	err = s.invoiceRepo.Issue(ctx, s.newInvoice(transaction, transaction.InvoiceBuyer, amount))
+	if errors.Is(err, repository.ErrInvoiceAlreadyIssued) {
+		return nil
+	}
+	return err
+}
+
+func (s *InvoiceServiceImpl) AdjustForRefund(ctx context.Context, event *outbox.Event) error {
+	var payload model.InvoiceAdjustmentDueEvent
+	if err := event.Decode(&payload); err != nil {
+		return outbox.Permanent(err)
+	}
+
+	invoice, err := s.invoiceRepo.GetIssuedByTransactionID(ctx, payload.TransactionID)
+	if err != nil {
+		return err
+	}
+	if invoice == nil {
+		return nil
+	}
+
+	transaction, err := s.transactionRepo.GetByID(ctx, payload.TransactionID)
+	if err != nil {
+		return err
+	}
+	amount, err := s.invoiceableAmount(ctx, transaction)
+	if err != nil {
+		return err
+	}
+	// A redelivered event finds the invoice already adjusted
+	if amount == invoice.TotalAmount {
+		return nil
+	}
+
+	now := time.Now()
+	reason := fmt.Sprintf("Hoàn tiền của đơn hàng %d", transaction.OrderCode)
+	invoice.CancelledAt = &now
+	invoice.CancelReason = &reason
+
+	var replacement *model.Invoice
+	if amount == 0 {
+		invoice.Status = model.InvoiceStatusCancelled
+	} else {
+		invoice.Status = model.InvoiceStatusReplaced
+		replacement = s.newInvoice(transaction, &model.InvoiceBuyer{
+			Name:        invoice.BuyerName,
+			CompanyName: invoice.BuyerCompanyName,
+			TaxCode:     invoice.BuyerTaxCode,
+			Address:     invoice.BuyerAddress,
+			Email:       invoice.BuyerEmail,
+		}, amount)
+	}
+
+	replaced, err := s.invoiceRepo.Replace(ctx, invoice, replacement)
+	if err != nil {
+		return err
+	}
+	if !replaced {
+		log.Info().Str("invoice_id", invoice.ID.String()).Msg("Invoice was already cancelled")
+	}
+	return nil
+}
+
+// invoiceableAmount is what the invoice of a paid transaction is made out for after its refunds
+func (s *InvoiceServiceImpl) invoiceableAmount(ctx context.Context, transaction *model.Transaction) (int, error) {
+	completed, err := s.refundRepo.GetCompletedTotalByTransactionID(ctx, transaction.ID)
+	if err != nil {
+		return 0, err
+	}
+	return transaction.InvoiceableAmount(completed), nil
+}
+
+// newInvoice makes out an invoice of the VAT inclusive amount, in the series of the current year
+func (s *InvoiceServiceImpl) newInvoice(transaction *model.Transaction, buyer *model.InvoiceBuyer, amount int) *model.Invoice {
+	issuedAt := time.Now()
+	vatRate := s.cfg.VATRate
+	amountBeforeTax := (amount*100 + (100+vatRate)/2) / (100 + vatRate)
+
+	return &model.Invoice{
+		TransactionID:    transaction.ID,
+		BookingID:        transaction.BookingID,
+		UserID:           transaction.UserID,
+		OperatorID:       transaction.OperatorID,
+		Series:           fmt.Sprintf("1C%02d%s", issuedAt.Year()%100, s.cfg.SeriesSuffix),
+		Status:           model.InvoiceStatusIssued,
+		SellerName:       s.cfg.SellerName,
+		SellerTaxCode:    s.cfg.SellerTaxCode,
+		SellerAddress:    s.cfg.SellerAddress,
+		BuyerName:        buyer.Name,
+		BuyerCompanyName: buyer.CompanyName,
+		BuyerTaxCode:     buyer.TaxCode,
+		BuyerAddress:     buyer.Address,
+		BuyerEmail:       buyer.Email,
+		Description:      fmt.Sprintf("Vé xe khách - đơn hàng %d", transaction.OrderCode),
+		AmountBeforeTax:  amountBeforeTax,
+		VATRate:          vatRate,
+		VATAmount:        amount - amountBeforeTax,
+		TotalAmount:      amount,
+		IssuedAt:         issuedAt,
+	}
+}
@@ -0,0 +1,371 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"bus-booking/payment-service/config"
+	"bus-booking/payment-service/internal/model"
+	"bus-booking/payment-service/internal/repository"
+	repo_mocks "bus-booking/payment-service/internal/repository/mocks"
+	"bus-booking/shared/ginext"
+	"bus-booking/shared/outbox"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+type invoiceMocks struct {
+	invoiceRepo     *repo_mocks.MockInvoiceRepository
+	transactionRepo *repo_mocks.MockTransactionRepository
+	refundRepo      *repo_mocks.MockRefundRepository
+}
+
+func newInvoiceService(ctrl *gomock.Controller) (InvoiceService, *invoiceMocks) {
+	m := &invoiceMocks{
+		invoiceRepo:     repo_mocks.NewMockInvoiceRepository(ctrl),
+		transactionRepo: repo_mocks.NewMockTransactionRepository(ctrl),
+		refundRepo:      repo_mocks.NewMockRefundRepository(ctrl),
+	}
+	service := NewInvoiceService(m.invoiceRepo, m.transactionRepo, m.refundRepo, config.InvoiceConfig{
+		SellerName:    "Bus Booking System",
+		SellerTaxCode: "0312345678",
+		SellerAddress: "227 Nguyễn Văn Cừ, Quận 5, TP. Hồ Chí Minh",
+		VATRate:       10,
+		SeriesSuffix:  "TBB",
+	})
+	return service, m
+}
+
+func testInvoiceBuyer() model.InvoiceBuyer {
+	return model.InvoiceBuyer{
+		Name:        "Nguyễn Văn A",
+		CompanyName: "Công ty TNHH Du Lịch Đà Lạt",
+		TaxCode:     "0301234567",
+		Address:     "12 Lê Lợi, Quận 1, TP. Hồ Chí Minh",
+		Email:       "ketoan@dalat.vn",
+	}
+}
+
+func newIssuedInvoice(transaction *model.Transaction, total int) *model.Invoice {
+	buyer := testInvoiceBuyer()
+	return &model.Invoice{
+		BaseModel:        model.BaseModel{ID: uuid.New()},
+		TransactionID:    transaction.ID,
+		BookingID:        transaction.BookingID,
+		UserID:           transaction.UserID,
+		OperatorID:       transaction.OperatorID,
+		Series:           "1C26TBB",
+		Number:           7,
+		Status:           model.InvoiceStatusIssued,
+		BuyerName:        buyer.Name,
+		BuyerCompanyName: buyer.CompanyName,
+		BuyerTaxCode:     buyer.TaxCode,
+		BuyerAddress:     buyer.Address,
+		BuyerEmail:       buyer.Email,
+		TotalAmount:      total,
+		IssuedAt:         time.Now().Add(-24 * time.Hour),
+	}
+}
+
+func TestIssueInvoice_IssuesForPaidBooking(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, m := newInvoiceService(ctrl)
+	ctx := context.Background()
+
+	transaction := newPaidTransaction(model.PaymentMethodPayOS, 300000)
+	transaction.OrderCode = 123456
+
+	m.transactionRepo.EXPECT().GetByID(ctx, transaction.ID).Return(transaction, nil)
+	m.refundRepo.EXPECT().GetCompletedTotalByTransactionID(ctx, transaction.ID).Return(0, nil)
+	m.invoiceRepo.EXPECT().Issue(ctx, gomock.Any()).Return(nil)
+
+	invoice, err := service.IssueInvoice(ctx, transaction.ID, transaction.UserID, &model.IssueInvoiceRequest{InvoiceBuyer: testInvoiceBuyer()})
+
+	assert.NoError(t, err)
+	assert.Equal(t, transaction.OperatorID, invoice.OperatorID)
+	assert.Equal(t, fmt.Sprintf("1C%02dTBB", time.Now().Year()%100), invoice.Series)
+	assert.Equal(t, "0301234567", invoice.BuyerTaxCode)
+	assert.Equal(t, "0312345678", invoice.SellerTaxCode)
+	// Ticket prices include VAT
+	assert.Equal(t, 300000, invoice.TotalAmount)
+	assert.Equal(t, 272727, invoice.AmountBeforeTax)
+	assert.Equal(t, 27273, invoice.VATAmount)
+	assert.Contains(t, invoice.Description, "123456")
+}
+
+func TestIssueInvoice_PaymentNotPaid(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, m := newInvoiceService(ctrl)
+	ctx := context.Background()
+
+	transaction := newPaidTransaction(model.PaymentMethodPayOS, 300000)
+	transaction.Status = model.TransactionStatusUnderpaid
+
+	m.transactionRepo.EXPECT().GetByID(ctx, transaction.ID).Return(transaction, nil)
+
+	_, err := service.IssueInvoice(ctx, transaction.ID, transaction.UserID, &model.IssueInvoiceRequest{InvoiceBuyer: testInvoiceBuyer()})
+
+	var apiErr *ginext.Error
+	assert.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusBadRequest, apiErr.Code)
+}
+
+func TestIssueInvoice_OtherUsersPayment(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, m := newInvoiceService(ctrl)
+	ctx := context.Background()
+
+	transaction := newPaidTransaction(model.PaymentMethodPayOS, 300000)
+
+	m.transactionRepo.EXPECT().GetByID(ctx, transaction.ID).Return(transaction, nil)
+
+	_, err := service.IssueInvoice(ctx, transaction.ID, uuid.New(), &model.IssueInvoiceRequest{InvoiceBuyer: testInvoiceBuyer()})
+
+	var apiErr *ginext.Error
+	assert.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusNotFound, apiErr.Code)
+}
+
+func TestIssueInvoice_AlreadyIssued(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, m := newInvoiceService(ctrl)
+	ctx := context.Background()
+
+	transaction := newPaidTransaction(model.PaymentMethodPayOS, 300000)
+
+	m.transactionRepo.EXPECT().GetByID(ctx, transaction.ID).Return(transaction, nil)
+	m.refundRepo.EXPECT().GetCompletedTotalByTransactionID(ctx, transaction.ID).Return(0, nil)
+	m.invoiceRepo.EXPECT().Issue(ctx, gomock.Any()).Return(repository.ErrInvoiceAlreadyIssued)
+
+	_, err := service.IssueInvoice(ctx, transaction.ID, transaction.UserID, &model.IssueInvoiceRequest{InvoiceBuyer: testInvoiceBuyer()})
+
+	var apiErr *ginext.Error
+	assert.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusConflict, apiErr.Code)
+}
+
+func TestIssueDue_IssuesInvoiceAskedForAtBooking(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, m := newInvoiceService(ctrl)
+	ctx := context.Background()
+
+	transaction := newPaidTransaction(model.PaymentMethodPayOS, 300000)
+	buyer := testInvoiceBuyer()
+	transaction.InvoiceBuyer = &buyer
+
+	event, err := newInvoiceDueEvent(transaction)
+	assert.NoError(t, err)
+
+	m.transactionRepo.EXPECT().GetByID(ctx, transaction.ID).Return(transaction, nil)
+	m.refundRepo.EXPECT().GetCompletedTotalByTransactionID(ctx, transaction.ID).Return(0, nil)
+	m.invoiceRepo.EXPECT().
+		Issue(ctx, gomock.Any()).
+		DoAndReturn(func(ctx context.Context, invoice *model.Invoice) error {
+			assert.Equal(t, buyer.CompanyName, invoice.BuyerCompanyName)
+			assert.Equal(t, 300000, invoice.TotalAmount)
+			return nil
+		})
+
+	assert.NoError(t, service.IssueDue(ctx, event))
+}
+
+func TestIssueDue_RedeliveredEventSkipped(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, m := newInvoiceService(ctrl)
+	ctx := context.Background()
+
+	transaction := newPaidTransaction(model.PaymentMethodPayOS, 300000)
+	buyer := testInvoiceBuyer()
+	transaction.InvoiceBuyer = &buyer
+
+	event, err := newInvoiceDueEvent(transaction)
+	assert.NoError(t, err)
+
+	m.transactionRepo.EXPECT().GetByID(ctx, transaction.ID).Return(transaction, nil)
+	m.refundRepo.EXPECT().GetCompletedTotalByTransactionID(ctx, transaction.ID).Return(0, nil)
+	m.invoiceRepo.EXPECT().Issue(ctx, gomock.Any()).Return(repository.ErrInvoiceAlreadyIssued)
+
+	assert.NoError(t, service.IssueDue(ctx, event))
+}
+
+func TestAdjustForRefund_PartialRefundReplacesInvoice(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, m := newInvoiceService(ctrl)
+	ctx := context.Background()
+
+	transaction := newPaidTransaction(model.PaymentMethodPayOS, 300000)
+	issued := newIssuedInvoice(transaction, 300000)
+	refund := &model.Refund{BaseModel: model.BaseModel{ID: uuid.New()}, TransactionID: transaction.ID, RefundAmount: 100000}
+
+	event, err := newInvoiceAdjustmentEvent(refund)
+	assert.NoError(t, err)
+
+	m.invoiceRepo.EXPECT().GetIssuedByTransactionID(ctx, transaction.ID).Return(issued, nil)
+	m.transactionRepo.EXPECT().GetByID(ctx, transaction.ID).Return(transaction, nil)
+	m.refundRepo.EXPECT().GetCompletedTotalByTransactionID(ctx, transaction.ID).Return(100000, nil)
+	m.invoiceRepo.EXPECT().
+		Replace(ctx, issued, gomock.Any()).
+		DoAndReturn(func(ctx context.Context, invoice *model.Invoice, replacement *model.Invoice) (bool, error) {
+			assert.Equal(t, model.InvoiceStatusReplaced, invoice.Status)
+			assert.NotNil(t, invoice.CancelledAt)
+			assert.NotNil(t, invoice.CancelReason)
+
+			// The replacement is made out to the same company for what was kept
+			assert.NotNil(t, replacement)
+			assert.Equal(t, model.InvoiceStatusIssued, replacement.Status)
+			assert.Equal(t, issued.BuyerTaxCode, replacement.BuyerTaxCode)
+			assert.Equal(t, 200000, replacement.TotalAmount)
+			return true, nil
+		})
+
+	assert.NoError(t, service.AdjustForRefund(ctx, event))
+}
+
+func TestAdjustForRefund_FullRefundCancelsInvoice(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, m := newInvoiceService(ctrl)
+	ctx := context.Background()
+
+	transaction := newPaidTransaction(model.PaymentMethodPayOS, 300000)
+	issued := newIssuedInvoice(transaction, 300000)
+	refund := &model.Refund{BaseModel: model.BaseModel{ID: uuid.New()}, TransactionID: transaction.ID, RefundAmount: 300000}
+
+	event, err := newInvoiceAdjustmentEvent(refund)
+	assert.NoError(t, err)
+
+	m.invoiceRepo.EXPECT().GetIssuedByTransactionID(ctx, transaction.ID).Return(issued, nil)
+	m.transactionRepo.EXPECT().GetByID(ctx, transaction.ID).Return(transaction, nil)
+	m.refundRepo.EXPECT().GetCompletedTotalByTransactionID(ctx, transaction.ID).Return(300000, nil)
+	m.invoiceRepo.EXPECT().
+		Replace(ctx, issued, gomock.Nil()).
+		DoAndReturn(func(ctx context.Context, invoice *model.Invoice, replacement *model.Invoice) (bool, error) {
+			assert.Equal(t, model.InvoiceStatusCancelled, invoice.Status)
+			return true, nil
+		})
+
+	assert.NoError(t, service.AdjustForRefund(ctx, event))
+}
+
+func TestAdjustForRefund_RefundOfExcessKeepsInvoice(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, m := newInvoiceService(ctrl)
+	ctx := context.Background()
+
+	// The passenger transferred 50000 too much, refunding it leaves the price invoiced as is
+	transaction := newPaidTransaction(model.PaymentMethodPayOS, 300000)
+	transaction.AmountPaid = 350000
+	issued := newIssuedInvoice(transaction, 300000)
+	refund := &model.Refund{BaseModel: model.BaseModel{ID: uuid.New()}, TransactionID: transaction.ID, RefundAmount: 50000}
+
+	event, err := newInvoiceAdjustmentEvent(refund)
+	assert.NoError(t, err)
+
+	m.invoiceRepo.EXPECT().GetIssuedByTransactionID(ctx, transaction.ID).Return(issued, nil)
+	m.transactionRepo.EXPECT().GetByID(ctx, transaction.ID).Return(transaction, nil)
+	m.refundRepo.EXPECT().GetCompletedTotalByTransactionID(ctx, transaction.ID).Return(50000, nil)
+	m.invoiceRepo.EXPECT().Replace(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+	assert.NoError(t, service.AdjustForRefund(ctx, event))
+}
+
+func TestAdjustForRefund_NoInvoice(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, m := newInvoiceService(ctrl)
+	ctx := context.Background()
+
+	refund := &model.Refund{BaseModel: model.BaseModel{ID: uuid.New()}, TransactionID: uuid.New(), RefundAmount: 100000}
+	event, err := newInvoiceAdjustmentEvent(refund)
+	assert.NoError(t, err)
+
+	m.invoiceRepo.EXPECT().GetIssuedByTransactionID(ctx, refund.TransactionID).Return(nil, nil)
+
+	assert.NoError(t, service.AdjustForRefund(ctx, event))
+}
+
+func TestApplyWebhook_PaidQueuesInvoiceAskedForAtBooking(t *testing.T) {
+	service, mockTransactionRepo, mockPayOSService := newReconciliationTestService(t)
+	ctx := context.Background()
+
+	transaction := newPendingTransaction(time.Now().Add(10 * time.Minute))
+	transaction.OrderCode = 555555
+	buyer := testInvoiceBuyer()
+	transaction.InvoiceBuyer = &buyer
+	webhook := &model.ProviderWebhook{OrderCode: transaction.OrderCode, PaymentLinkID: transaction.PaymentLinkID}
+
+	mockPayOSService.EXPECT().
+		GetPayment(gomock.Any(), transaction.PaymentLinkID).
+		Return(paidPayment(transaction.Amount), nil)
+	mockTransactionRepo.EXPECT().
+		GetByWebhookData(gomock.Any(), int(transaction.OrderCode), transaction.PaymentLinkID).
+		Return(transaction, nil)
+	mockTransactionRepo.EXPECT().
+		UpdateTransactionWithEvents(ctx, gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, tx *model.Transaction, _ model.TransactionStatus, events ...*outbox.Event) error {
+			assert.Len(t, events, 3)
+			assert.Equal(t, model.EventTypeInvoiceDue, events[2].EventType)
+			return nil
+		})
+
+	_, err := service.ApplyWebhook(ctx, model.PaymentMethodPayOS, webhook)
+
+	assert.NoError(t, err)
+}
+
+func TestRenderInvoiceXML_ReferencesReplacedInvoice(t *testing.T) {
+	transaction := newPaidTransaction(model.PaymentMethodPayOS, 300000)
+	replaced := newIssuedInvoice(transaction, 300000)
+	replacement := newIssuedInvoice(transaction, 200000)
+	replacement.Number = 8
+	replacement.ReplacesID = &replaced.ID
+
+	data, err := renderInvoiceXML(replacement, replaced)
+
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), "<KHMSHDon>1</KHMSHDon>")
+	assert.Contains(t, string(data), "<KHHDon>C26TBB</KHHDon>")
+	assert.Contains(t, string(data), "<SHDon>8</SHDon>")
+	assert.Contains(t, string(data), "<SHDCLQuan>7</SHDCLQuan>")
+	assert.Contains(t, string(data), "<MST>0301234567</MST>")
+	assert.Contains(t, string(data), "<TgTTTBSo>200000</TgTTTBSo>")
+}
+
+func TestRenderInvoicePDF(t *testing.T) {
+	transaction := newPaidTransaction(model.PaymentMethodPayOS, 300000)
+	invoice := newIssuedInvoice(transaction, 300000)
+
+	data, err := renderInvoicePDF(invoice, nil)
+
+	assert.NoError(t, err)
+	assert.True(t, bytes.HasPrefix(data, []byte("%PDF")))
+}
+
+func TestPDFText_DropsDiacritics(t *testing.T) {
+	assert.Equal(t, "Cong ty TNHH Du Lich Da Lat", pdfText("Công ty TNHH Du Lịch Đà Lạt"))
+	assert.Equal(t, "1.234.567", formatVND(1234567))
+}
@@ -0,0 +1,442 @@
+package service
+
+import (
+	"bus-booking/payment-service/internal/model"
+	"bus-booking/payment-service/internal/repository"
+	"bus-booking/shared/ginext"
+	"bus-booking/shared/outbox"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+// maxSettlementReportDays bounds the days of one settlement report
+const maxSettlementReportDays = 92
+
+const settlementDateLayout = "2006-01-02"
+
+// LedgerFee is what a provider keeps of each payment: a rate in basis points plus a fixed amount
+type LedgerFee struct {
+	BasisPoints int
+	Fixed       int
+}
+
+// For returns the fee of a payment, never more than the payment itself
+func (f LedgerFee) For(amount int) int {
+	return min(amount*f.BasisPoints/10000+f.Fixed, amount)
+}
+
+// LedgerFees maps each payment method to its provider fee, methods without one are free
+type LedgerFees map[model.PaymentMethod]LedgerFee
+
+type LedgerService interface {
+	PostPaymentCaptured(ctx context.Context, event *outbox.Event) error
+	PostRefundCompleted(ctx context.Context, event *outbox.Event) error
+	PostPromotionalCreditChanged(ctx context.Context, event *outbox.Event) error
+	CreateAdjustment(ctx context.Context, req *model.CreateLedgerAdjustmentRequest, adminID uuid.UUID) (*model.LedgerJournal, error)
+	ListJournals(ctx context.Context, query *model.LedgerJournalListQuery) ([]*model.LedgerJournal, int64, error)
+	GetSettlementReport(ctx context.Context, query *model.SettlementReportQuery) (*model.SettlementReport, error)
+	ExportSettlementReportCSV(ctx context.Context, query *model.SettlementReportQuery) ([]byte, error)
+	ExportSettlementReportExcel(ctx context.Context, query *model.SettlementReportQuery) ([]byte, error)
+}
+
+type LedgerServiceImpl struct {
+	ledgerRepo      repository.LedgerRepository
+	transactionRepo repository.TransactionRepository
+	refundRepo      repository.RefundRepository
+	excelService    ExcelService
+	fees            LedgerFees
+}
+
+func NewLedgerService(
+	ledgerRepo repository.LedgerRepository,
+	transactionRepo repository.TransactionRepository,
+	refundRepo repository.RefundRepository,
+	excelService ExcelService,
+	fees LedgerFees,
+) LedgerService {
+	return &LedgerServiceImpl{
+		ledgerRepo:      ledgerRepo,
+		transactionRepo: transactionRepo,
+		refundRepo:      refundRepo,
+		excelService:    excelService,
+		fees:            fees,
+	}
+}
+
+// newPaymentCapturedEvent queues the ledger posting of a payment that became paid
+func newPaymentCapturedEvent(transaction *model.Transaction) (*outbox.Event, error) {
+	capturedAt := time.Now()
+	if transaction.TransactionTime != nil {
+		capturedAt = time.Unix(*transaction.TransactionTime, 0)
+	}
+	return outbox.NewEvent(model.AggregateTypeTransaction, transaction.ID, model.EventTypePaymentCaptured, &model.PaymentCapturedEvent{
+		TransactionID: transaction.ID,
+		CapturedAt:    capturedAt,
+	})
+}
+
+// newRefundCompletedEvent queues the ledger posting of a refund that was paid back
+func newRefundCompletedEvent(refund *model.Refund) (*outbox.Event, error) {
+	completedAt := time.Now()
+	if refund.ProcessedAt != nil {
+		completedAt = *refund.ProcessedAt
+	}
+	return outbox.NewEvent(model.AggregateTypeRefund, refund.ID, model.EventTypeRefundCompleted, &model.RefundCompletedEvent{
+		RefundID:    refund.ID,
+		CompletedAt: completedAt,
+	})
+}
+
+// PostPaymentCaptured is the outbox handler posting a paid transaction and its provider fee
+func (s *LedgerServiceImpl) PostPaymentCaptured(ctx context.Context, event *outbox.Event) error {
+	var payload model.PaymentCapturedEvent
+	if err := event.Decode(&payload); err != nil {
+		return outbox.Permanent(err)
+	}
+
+	transaction, err := s.transactionRepo.GetByID(ctx, payload.TransactionID)
+	if err != nil {
+		return err
+	}
+
+	// Everything received is posted, excess and partial payments leave again as refunds
+	var journals []*model.LedgerJournal
+	if received := transaction.ReceivedAmount() - transaction.WalletAmount; received > 0 {
+		journals = append(journals, newLedgerJournal(model.LedgerJournalKindCharge, transaction, payload.CapturedAt, received,
+			model.LedgerAccountProviderClearing, model.LedgerAccountOperatorPayable,
+			fmt.Sprintf("Payment of booking %s", transaction.BookingID)))
+		if fee := s.fees[transaction.PaymentMethod].For(received); fee > 0 {
+			journals = append(journals, newLedgerJournal(model.LedgerJournalKindFee, transaction, payload.CapturedAt, fee,
+				model.LedgerAccountProviderFees, model.LedgerAccountProviderClearing,
+				fmt.Sprintf("%s fee of booking %s", transaction.PaymentMethod, transaction.BookingID)))
+		}
+	}
+	// The wallet part is only spent once the booking is paid, otherwise it goes back to the wallet
+	if transaction.Status == model.TransactionStatusPaid && transaction.WalletAmount > 0 {
+		journal := newLedgerJournal(model.LedgerJournalKindWalletCharge, transaction, payload.CapturedAt, transaction.WalletAmount,
+			model.LedgerAccountCustomerWallets, model.LedgerAccountOperatorPayable,
+			fmt.Sprintf("Wallet payment of booking %s", transaction.BookingID))
+		journal.PaymentMethod = model.PaymentMethodWallet
+		journals = append(journals, journal)
+	}
+	if len(journals) == 0 {
+		return nil
+	}
+
+	return s.ledgerRepo.Post(ctx, journals...)
+}
+
+// PostRefundCompleted is the outbox handler posting a refund paid back to the passenger
+func (s *LedgerServiceImpl) PostRefundCompleted(ctx context.Context, event *outbox.Event) error {
+	var payload model.RefundCompletedEvent
+	if err := event.Decode(&payload); err != nil {
+		return outbox.Permanent(err)
+	}
+
+	refund, err := s.refundRepo.GetByID(ctx, payload.RefundID)
+	if err != nil {
+		return err
+	}
+	transaction, err := s.transactionRepo.GetByID(ctx, refund.TransactionID)
+	if err != nil {
+		return err
+	}
+
+	journal := newLedgerJournal(model.LedgerJournalKindRefund, transaction, payload.CompletedAt, refund.RefundAmount,
+		model.LedgerAccountOperatorPayable, model.LedgerAccountProviderClearing,
+		fmt.Sprintf("Refund of booking %s", refund.BookingID))
+	journal.SourceID = refund.ID
+	// A wallet refund never leaves the platform, the passenger holds it as credit
+	if refund.Destination == model.RefundDestinationWallet {
+		journal.PaymentMethod = model.PaymentMethodWallet
+		journal.Entries[1].Account = model.LedgerAccountCustomerWallets
+	}
+
+	return s.ledgerRepo.Post(ctx, journal)
+}
+
+// PostPromotionalCreditChanged is the outbox handler posting promotional credit handed out or expired
+func (s *LedgerServiceImpl) PostPromotionalCreditChanged(ctx context.Context, event *outbox.Event) error {
+	var payload model.PromotionalCreditChangedEvent
+	if err := event.Decode(&payload); err != nil {
+		return outbox.Permanent(err)
+	}
+
+	var (
+		kind          model.LedgerJournalKind
+		debit, credit model.LedgerAccount
+		description   string
+	)
+	switch payload.Type {
+	case model.WalletEntryTypePromotion:
+		kind, debit, credit = model.LedgerJournalKindPromotion, model.LedgerAccountPromotions, model.LedgerAccountCustomerWallets
+		description = fmt.Sprintf("Promotional credit of user %s", payload.UserID)
+	case model.WalletEntryTypeExpired:
+		kind, debit, credit = model.LedgerJournalKindCreditExpiry, model.LedgerAccountCustomerWallets, model.LedgerAccountPromotions
+		description = fmt.Sprintf("Expired promotional credit of user %s", payload.UserID)
+	default:
+		return outbox.Permanent(fmt.Errorf("wallet entry type %s is not promotional credit", payload.Type))
+	}
+
+	amount := payload.Amount
+	if amount < 0 {
+		amount = -amount
+	}
+
+	return s.ledgerRepo.Post(ctx, &model.LedgerJournal{
+		Kind:          kind,
+		SourceID:      payload.EntryID,
+		PaymentMethod: model.PaymentMethodWallet,
+		Description:   description,
+		OccurredAt:    payload.OccurredAt,
+		Entries: []*model.LedgerEntry{
+			{Account: debit, Direction: model.LedgerDirectionDebit, Amount: amount},
+			{Account: credit, Direction: model.LedgerDirectionCredit, Amount: amount},
+		},
+	})
+}
+
+// newLedgerJournal moves amount from the credited account to the debited one for a transaction
+func newLedgerJournal(kind model.LedgerJournalKind, transaction *model.Transaction, occurredAt time.Time, amount int, debit, credit model.LedgerAccount, description string) *model.LedgerJournal {
+	transactionID := transaction.ID
+	return &model.LedgerJournal{
+		Kind:          kind,
+		SourceID:      transaction.ID,
+		TransactionID: &transactionID,
+		OperatorID:    transaction.OperatorID,
+		PaymentMethod: transaction.PaymentMethod,
+		Description:   description,
+		OccurredAt:    occurredAt,
+		Entries: []*model.LedgerEntry{
+			{Account: debit, Direction: model.LedgerDirectionDebit, Amount: amount},
+			{Account: credit, Direction: model.LedgerDirectionCredit, Amount: amount},
+		},
+	}
+}
+
+// CreateAdjustment posts a manual correction of the money held by a payment method
+func (s *LedgerServiceImpl) CreateAdjustment(ctx context.Context, req *model.CreateLedgerAdjustmentRequest, adminID uuid.UUID) (*model.LedgerJournal, error) {
+	occurredAt := time.Now()
+	if req.OccurredAt != nil {
+		if req.OccurredAt.After(occurredAt) {
+			return nil, ginext.NewBadRequestError("adjustment cannot occur in the future")
+		}
+		occurredAt = *req.OccurredAt
+	}
+
+	// Adjustments have no source to deduplicate on, each one stands for itself
+	id := uuid.New()
+	debit, credit, amount := model.LedgerAccountProviderClearing, model.LedgerAccountAdjustments, req.Amount
+	if amount < 0 {
+		debit, credit, amount = credit, debit, -amount
+	}
+
+	journal := &model.LedgerJournal{
+		ID:            id,
+		Kind:          model.LedgerJournalKindAdjustment,
+		SourceID:      id,
+		OperatorID:    req.OperatorID,
+		PaymentMethod: req.PaymentMethod,
+		Description:   req.Reason,
+		CreatedBy:     &adminID,
+		OccurredAt:    occurredAt,
+		Entries: []*model.LedgerEntry{
+			{Account: debit, Direction: model.LedgerDirectionDebit, Amount: amount},
+			{Account: credit, Direction: model.LedgerDirectionCredit, Amount: amount},
+		},
+	}
+
+	if err := s.ledgerRepo.Post(ctx, journal); err != nil {
+		log.Error().Err(err).Msg("Failed to post ledger adjustment")
+		return nil, ginext.NewInternalServerError("failed to post ledger adjustment")
+	}
+
+	return journal, nil
+}
+
+func (s *LedgerServiceImpl) ListJournals(ctx context.Context, query *model.LedgerJournalListQuery) ([]*model.LedgerJournal, int64, error) {
+	journals, total, err := s.ledgerRepo.ListJournals(ctx, query)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to list ledger journals")
+		return nil, 0, ginext.NewInternalServerError("failed to list ledger journals")
+	}
+	return journals, total, nil
+}
+
+// GetSettlementReport rolls the clearing balance of each operator and payment method forward day by day.
+// Days without money held or moved are left out.
+func (s *LedgerServiceImpl) GetSettlementReport(ctx context.Context, query *model.SettlementReportQuery) (*model.SettlementReport, error) {
+	from := truncateToDay(query.From)
+	to := truncateToDay(query.To).AddDate(0, 0, 1)
+	if !from.Before(to) {
+		return nil, ginext.NewBadRequestError("from must not be after to")
+	}
+	if to.Sub(from) > maxSettlementReportDays*24*time.Hour {
+		return nil, ginext.NewBadRequestError(fmt.Sprintf("settlement report cannot span more than %d days", maxSettlementReportDays))
+	}
+
+	balances, err := s.ledgerRepo.GetBalances(ctx, query, from)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get ledger balances")
+		return nil, ginext.NewInternalServerError("failed to get settlement report")
+	}
+	movements, err := s.ledgerRepo.GetDailyMovements(ctx, query, from, to)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get ledger movements")
+		return nil, ginext.NewInternalServerError("failed to get settlement report")
+	}
+
+	type settlementKey struct {
+		operatorID    uuid.UUID // uuid.Nil when the payment has no operator
+		paymentMethod model.PaymentMethod
+	}
+	keyOf := func(operatorID *uuid.UUID, method model.PaymentMethod) settlementKey {
+		key := settlementKey{paymentMethod: method}
+		if operatorID != nil {
+			key.operatorID = *operatorID
+		}
+		return key
+	}
+
+	running := make(map[settlementKey]int)
+	operators := make(map[settlementKey]*uuid.UUID)
+	for _, balance := range balances {
+		key := keyOf(balance.OperatorID, balance.PaymentMethod)
+		running[key] += balance.Balance
+		operators[key] = balance.OperatorID
+	}
+
+	daily := make(map[string]map[settlementKey]*model.SettlementReportRow)
+	for _, movement := range movements {
+		key := keyOf(movement.OperatorID, movement.PaymentMethod)
+		operators[key] = movement.OperatorID
+
+		date := movement.Day.Format(settlementDateLayout)
+		if daily[date] == nil {
+			daily[date] = make(map[settlementKey]*model.SettlementReportRow)
+		}
+		row := daily[date][key]
+		if row == nil {
+			row = &model.SettlementReportRow{}
+			daily[date][key] = row
+		}
+
+		// Money leaving the clearing account is reported as a positive refund or fee
+		switch movement.Kind {
+		case model.LedgerJournalKindCharge:
+			row.Charges += movement.Amount
+		case model.LedgerJournalKindRefund:
+			row.Refunds -= movement.Amount
+		case model.LedgerJournalKindFee:
+			row.Fees -= movement.Amount
+		default:
+			row.Adjustments += movement.Amount
+		}
+	}
+
+	keys := make([]settlementKey, 0, len(operators))
+	for key := range operators {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].operatorID != keys[j].operatorID {
+			return keys[i].operatorID.String() < keys[j].operatorID.String()
+		}
+		return keys[i].paymentMethod < keys[j].paymentMethod
+	})
+
+	report := &model.SettlementReport{
+		From: from.Format(settlementDateLayout),
+		To:   to.AddDate(0, 0, -1).Format(settlementDateLayout),
+		Rows: []*model.SettlementReportRow{},
+	}
+	for day := from; day.Before(to); day = day.AddDate(0, 0, 1) {
+		date := day.Format(settlementDateLayout)
+		for _, key := range keys {
+			row := daily[date][key]
+			if row == nil {
+				if running[key] == 0 {
+					continue
+				}
+				row = &model.SettlementReportRow{}
+			}
+
+			row.Date = date
+			row.OperatorID = operators[key]
+			row.PaymentMethod = key.paymentMethod
+			row.OpeningBalance = running[key]
+			row.ClosingBalance = row.OpeningBalance + row.Charges - row.Refunds - row.Fees + row.Adjustments
+			running[key] = row.ClosingBalance
+
+			report.Rows = append(report.Rows, row)
+		}
+	}
+
+	return report, nil
+}
+
+// ExportSettlementReportCSV renders the settlement report as CSV, one line per row
+func (s *LedgerServiceImpl) ExportSettlementReportCSV(ctx context.Context, query *model.SettlementReportQuery) ([]byte, error) {
+	report, err := s.GetSettlementReport(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	records := [][]string{{
+		"date", "operator_id", "payment_method", "opening_balance",
+		"charges", "refunds", "fees", "adjustments", "closing_balance",
+	}}
+	for _, row := range report.Rows {
+		operatorID := ""
+		if row.OperatorID != nil {
+			operatorID = row.OperatorID.String()
+		}
+		records = append(records, []string{
+			row.Date,
+			operatorID,
+			string(row.PaymentMethod),
+			strconv.Itoa(row.OpeningBalance),
+			strconv.Itoa(row.Charges),
+			strconv.Itoa(row.Refunds),
+			strconv.Itoa(row.Fees),
+			strconv.Itoa(row.Adjustments),
+			strconv.Itoa(row.ClosingBalance),
+		})
+	}
+	if err := w.WriteAll(records); err != nil {
+		log.Error().Err(err).Msg("Failed to write settlement report CSV")
+		return nil, ginext.NewInternalServerError("failed to export settlement report")
+	}
+
+	return buf.Bytes(), nil
+}
+
+// ExportSettlementReportExcel renders the settlement report with the Excel generator
+func (s *LedgerServiceImpl) ExportSettlementReportExcel(ctx context.Context, query *model.SettlementReportQuery) ([]byte, error) {
+	report, err := s.GetSettlementReport(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	excelData, err := s.excelService.GenerateSettlementExcel(report)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to generate settlement Excel file")
+		return nil, ginext.NewInternalServerError("failed to export settlement report")
+	}
+
+	return excelData, nil
+}
+
+func truncateToDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
@@ -0,0 +1,459 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"bus-booking/payment-service/internal/model"
+	repo_mocks "bus-booking/payment-service/internal/repository/mocks"
+	service_mocks "bus-booking/payment-service/internal/service/mocks"
+	"bus-booking/shared/ginext"
+	"bus-booking/shared/outbox"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+type ledgerMocks struct {
+	ledgerRepo      *repo_mocks.MockLedgerRepository
+	transactionRepo *repo_mocks.MockTransactionRepository
+	refundRepo      *repo_mocks.MockRefundRepository
+	excelService    *service_mocks.MockExcelService
+}
+
+func newLedgerService(ctrl *gomock.Controller) (LedgerService, *ledgerMocks) {
+	m := &ledgerMocks{
+		ledgerRepo:      repo_mocks.NewMockLedgerRepository(ctrl),
+		transactionRepo: repo_mocks.NewMockTransactionRepository(ctrl),
+		refundRepo:      repo_mocks.NewMockRefundRepository(ctrl),
+		excelService:    service_mocks.NewMockExcelService(ctrl),
+	}
+	fees := LedgerFees{
+		model.PaymentMethodPayOS: {BasisPoints: 100, Fixed: 1000},
+	}
+	service := NewLedgerService(m.ledgerRepo, m.transactionRepo, m.refundRepo, m.excelService, fees)
+	return service, m
+}
+
+func newPaidTransaction(method model.PaymentMethod, amount int) *model.Transaction {
+	operatorID := uuid.New()
+	return &model.Transaction{
+		BaseModel:       model.BaseModel{ID: uuid.New()},
+		BookingID:       uuid.New(),
+		UserID:          uuid.New(),
+		OperatorID:      &operatorID,
+		Amount:          amount,
+		Currency:        model.CurrencyVND,
+		PaymentMethod:   method,
+		Status:          model.TransactionStatusPaid,
+		TransactionType: model.TransactionTypeIn,
+	}
+}
+
+func day(date string) time.Time {
+	t, _ := time.Parse("2006-01-02", date)
+	return t
+}
+
+func TestLedgerFee_For(t *testing.T) {
+	fee := LedgerFee{BasisPoints: 100, Fixed: 1000}
+
+	assert.Equal(t, 3000, fee.For(200000))
+	assert.Equal(t, 500, fee.For(500), "a fee never exceeds the payment")
+	assert.Equal(t, 0, LedgerFee{}.For(200000))
+}
+
+func TestPostPaymentCaptured_PostsChargeAndFee(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, m := newLedgerService(ctrl)
+	ctx := context.Background()
+
+	transaction := newPaidTransaction(model.PaymentMethodPayOS, 200000)
+	capturedAt := time.Date(2026, 3, 1, 9, 30, 0, 0, time.UTC)
+	event, err := outbox.NewEvent(model.AggregateTypeTransaction, transaction.ID, model.EventTypePaymentCaptured, &model.PaymentCapturedEvent{
+		TransactionID: transaction.ID,
+		CapturedAt:    capturedAt,
+	})
+	assert.NoError(t, err)
+
+	m.transactionRepo.EXPECT().GetByID(ctx, transaction.ID).Return(transaction, nil)
+	m.ledgerRepo.EXPECT().
+		Post(ctx, gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, journals ...*model.LedgerJournal) error {
+			assert.Len(t, journals, 2)
+
+			charge := journals[0]
+			assert.Equal(t, model.LedgerJournalKindCharge, charge.Kind)
+			assert.Equal(t, transaction.ID, charge.SourceID)
+			assert.Equal(t, transaction.OperatorID, charge.OperatorID)
+			assert.Equal(t, model.PaymentMethodPayOS, charge.PaymentMethod)
+			assert.True(t, capturedAt.Equal(charge.OccurredAt))
+			assert.True(t, charge.Balanced())
+			assert.Equal(t, model.LedgerAccountProviderClearing, charge.Entries[0].Account)
+			assert.Equal(t, model.LedgerDirectionDebit, charge.Entries[0].Direction)
+			assert.Equal(t, 200000, charge.Entries[0].Amount)
+
+			fee := journals[1]
+			assert.Equal(t, model.LedgerJournalKindFee, fee.Kind)
+			assert.Equal(t, transaction.ID, fee.SourceID)
+			assert.True(t, fee.Balanced())
+			assert.Equal(t, model.LedgerAccountProviderFees, fee.Entries[0].Account)
+			assert.Equal(t, model.LedgerAccountProviderClearing, fee.Entries[1].Account)
+			assert.Equal(t, 3000, fee.Entries[1].Amount)
+			return nil
+		})
+
+	assert.NoError(t, service.PostPaymentCaptured(ctx, event))
+}
+
+func TestPostPaymentCaptured_CashHasNoFee(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, m := newLedgerService(ctrl)
+	ctx := context.Background()
+
+	transaction := newPaidTransaction(model.PaymentMethodCash, 200000)
+	event, err := newPaymentCapturedEvent(transaction)
+	assert.NoError(t, err)
+
+	m.transactionRepo.EXPECT().GetByID(ctx, transaction.ID).Return(transaction, nil)
+	m.ledgerRepo.EXPECT().
+		Post(ctx, gomock.Any()).
+		DoAndReturn(func(ctx context.Context, journals ...*model.LedgerJournal) error {
+			assert.Len(t, journals, 1)
+			assert.Equal(t, model.LedgerJournalKindCharge, journals[0].Kind)
+			return nil
+		})
+
+	assert.NoError(t, service.PostPaymentCaptured(ctx, event))
+}
+
+func TestPostPaymentCaptured_InvalidPayload(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, _ := newLedgerService(ctrl)
+
+	err := service.PostPaymentCaptured(context.Background(), &outbox.Event{Payload: "not json"})
+
+	assert.Error(t, err)
+}
+
+func TestPostRefundCompleted_PostsRefund(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, m := newLedgerService(ctrl)
+	ctx := context.Background()
+
+	transaction := newPaidTransaction(model.PaymentMethodPayOS, 200000)
+	refund := newApprovedRefund(80000)
+	refund.TransactionID = transaction.ID
+	refund.RefundStatus = model.RefundStatusCompleted
+	event, err := newRefundCompletedEvent(refund)
+	assert.NoError(t, err)
+
+	m.refundRepo.EXPECT().GetByID(ctx, refund.ID).Return(refund, nil)
+	m.transactionRepo.EXPECT().GetByID(ctx, transaction.ID).Return(transaction, nil)
+	m.ledgerRepo.EXPECT().
+		Post(ctx, gomock.Any()).
+		DoAndReturn(func(ctx context.Context, journals ...*model.LedgerJournal) error {
+			assert.Len(t, journals, 1)
+			journal := journals[0]
+			assert.Equal(t, model.LedgerJournalKindRefund, journal.Kind)
+			assert.Equal(t, refund.ID, journal.SourceID)
+			assert.Equal(t, transaction.ID, *journal.TransactionID)
+			assert.True(t, journal.Balanced())
+			assert.Equal(t, model.LedgerAccountOperatorPayable, journal.Entries[0].Account)
+			assert.Equal(t, model.LedgerAccountProviderClearing, journal.Entries[1].Account)
+			assert.Equal(t, model.LedgerDirectionCredit, journal.Entries[1].Direction)
+			assert.Equal(t, 80000, journal.Entries[1].Amount)
+			return nil
+		})
+
+	assert.NoError(t, service.PostRefundCompleted(ctx, event))
+}
+
+func TestPostPaymentCaptured_WalletPartPostedToWallets(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, m := newLedgerService(ctrl)
+	ctx := context.Background()
+
+	transaction := newPaidTransaction(model.PaymentMethodPayOS, 200000)
+	transaction.WalletAmount = 50000
+	transaction.AmountPaid = 200000
+	event, err := newPaymentCapturedEvent(transaction)
+	assert.NoError(t, err)
+
+	m.transactionRepo.EXPECT().GetByID(ctx, transaction.ID).Return(transaction, nil)
+	m.ledgerRepo.EXPECT().
+		Post(ctx, gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, journals ...*model.LedgerJournal) error {
+			// Only the transferred part goes through the provider and pays its fee
+			charge := journals[0]
+			assert.Equal(t, model.LedgerJournalKindCharge, charge.Kind)
+			assert.Equal(t, 150000, charge.Entries[0].Amount)
+			assert.Equal(t, 2500, journals[1].Entries[0].Amount)
+
+			wallet := journals[2]
+			assert.Equal(t, model.LedgerJournalKindWalletCharge, wallet.Kind)
+			assert.Equal(t, model.PaymentMethodWallet, wallet.PaymentMethod)
+			assert.True(t, wallet.Balanced())
+			assert.Equal(t, model.LedgerAccountCustomerWallets, wallet.Entries[0].Account)
+			assert.Equal(t, model.LedgerAccountOperatorPayable, wallet.Entries[1].Account)
+			assert.Equal(t, 50000, wallet.Entries[1].Amount)
+			return nil
+		})
+
+	assert.NoError(t, service.PostPaymentCaptured(ctx, event))
+}
+
+func TestPostRefundCompleted_WalletRefundCreditsWallets(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, m := newLedgerService(ctrl)
+	ctx := context.Background()
+
+	transaction := newPaidTransaction(model.PaymentMethodPayOS, 200000)
+	refund := newApprovedRefund(80000)
+	refund.TransactionID = transaction.ID
+	refund.RefundStatus = model.RefundStatusCompleted
+	refund.Destination = model.RefundDestinationWallet
+	event, err := newRefundCompletedEvent(refund)
+	assert.NoError(t, err)
+
+	m.refundRepo.EXPECT().GetByID(ctx, refund.ID).Return(refund, nil)
+	m.transactionRepo.EXPECT().GetByID(ctx, transaction.ID).Return(transaction, nil)
+	m.ledgerRepo.EXPECT().
+		Post(ctx, gomock.Any()).
+		DoAndReturn(func(ctx context.Context, journals ...*model.LedgerJournal) error {
+			journal := journals[0]
+			assert.Equal(t, model.LedgerJournalKindRefund, journal.Kind)
+			assert.Equal(t, model.PaymentMethodWallet, journal.PaymentMethod)
+			assert.True(t, journal.Balanced())
+			assert.Equal(t, model.LedgerAccountOperatorPayable, journal.Entries[0].Account)
+			assert.Equal(t, model.LedgerAccountCustomerWallets, journal.Entries[1].Account)
+			return nil
+		})
+
+	assert.NoError(t, service.PostRefundCompleted(ctx, event))
+}
+
+func TestPostPromotionalCreditChanged_ExpiryReversesPromotion(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, m := newLedgerService(ctrl)
+	ctx := context.Background()
+
+	expiry := &model.WalletEntry{
+		ID:     uuid.New(),
+		UserID: uuid.New(),
+		Type:   model.WalletEntryTypeExpired,
+		Amount: -30000,
+	}
+	occurredAt := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	event, err := newPromotionalCreditChangedEvent(expiry, occurredAt)
+	assert.NoError(t, err)
+
+	m.ledgerRepo.EXPECT().
+		Post(ctx, gomock.Any()).
+		DoAndReturn(func(ctx context.Context, journals ...*model.LedgerJournal) error {
+			journal := journals[0]
+			assert.Equal(t, model.LedgerJournalKindCreditExpiry, journal.Kind)
+			assert.Equal(t, expiry.ID, journal.SourceID)
+			assert.Nil(t, journal.TransactionID)
+			assert.True(t, occurredAt.Equal(journal.OccurredAt))
+			assert.True(t, journal.Balanced())
+			assert.Equal(t, model.LedgerAccountCustomerWallets, journal.Entries[0].Account)
+			assert.Equal(t, model.LedgerAccountPromotions, journal.Entries[1].Account)
+			assert.Equal(t, 30000, journal.Entries[1].Amount)
+			return nil
+		})
+
+	assert.NoError(t, service.PostPromotionalCreditChanged(ctx, event))
+}
+
+func TestCreateAdjustment_NegativeCreditsClearing(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, m := newLedgerService(ctrl)
+	ctx := context.Background()
+	adminID := uuid.New()
+
+	m.ledgerRepo.EXPECT().Post(ctx, gomock.Any()).Return(nil)
+
+	journal, err := service.CreateAdjustment(ctx, &model.CreateLedgerAdjustmentRequest{
+		PaymentMethod: model.PaymentMethodCash,
+		Amount:        -50000,
+		Reason:        "Counter short at closing",
+	}, adminID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, model.LedgerJournalKindAdjustment, journal.Kind)
+	assert.Equal(t, journal.ID, journal.SourceID)
+	assert.Equal(t, adminID, *journal.CreatedBy)
+	assert.True(t, journal.Balanced())
+	assert.Equal(t, model.LedgerAccountProviderClearing, journal.Entries[1].Account)
+	assert.Equal(t, model.LedgerDirectionCredit, journal.Entries[1].Direction)
+	assert.Equal(t, 50000, journal.Entries[1].Amount)
+}
+
+func TestCreateAdjustment_FutureDate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, _ := newLedgerService(ctrl)
+	future := time.Now().Add(time.Hour)
+
+	_, err := service.CreateAdjustment(context.Background(), &model.CreateLedgerAdjustmentRequest{
+		PaymentMethod: model.PaymentMethodCash,
+		Amount:        50000,
+		Reason:        "Late deposit",
+		OccurredAt:    &future,
+	}, uuid.New())
+
+	var apiErr *ginext.Error
+	assert.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusBadRequest, apiErr.Code)
+}
+
+func TestGetSettlementReport_RollsBalancesForward(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, m := newLedgerService(ctrl)
+	ctx := context.Background()
+	operatorID := uuid.New()
+
+	query := &model.SettlementReportQuery{From: day("2026-03-01"), To: day("2026-03-03")}
+
+	m.ledgerRepo.EXPECT().GetBalances(ctx, query, day("2026-03-01")).Return([]*model.LedgerBalance{
+		{OperatorID: &operatorID, PaymentMethod: model.PaymentMethodPayOS, Balance: 100000},
+	}, nil)
+	m.ledgerRepo.EXPECT().GetDailyMovements(ctx, query, day("2026-03-01"), day("2026-03-04")).Return([]*model.LedgerMovement{
+		{Day: day("2026-03-01"), OperatorID: &operatorID, PaymentMethod: model.PaymentMethodPayOS, Kind: model.LedgerJournalKindCharge, Amount: 200000},
+		{Day: day("2026-03-01"), OperatorID: &operatorID, PaymentMethod: model.PaymentMethodPayOS, Kind: model.LedgerJournalKindFee, Amount: -3000},
+		{Day: day("2026-03-03"), OperatorID: &operatorID, PaymentMethod: model.PaymentMethodPayOS, Kind: model.LedgerJournalKindRefund, Amount: -80000},
+		{Day: day("2026-03-03"), PaymentMethod: model.PaymentMethodCash, Kind: model.LedgerJournalKindAdjustment, Amount: -5000},
+	}, nil)
+
+	report, err := service.GetSettlementReport(ctx, query)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "2026-03-01", report.From)
+	assert.Equal(t, "2026-03-03", report.To)
+
+	// The cash adjustment sorts first: payments without an operator come before the others
+	assert.Len(t, report.Rows, 4)
+
+	first := report.Rows[0]
+	assert.Equal(t, "2026-03-01", first.Date)
+	assert.Equal(t, 100000, first.OpeningBalance)
+	assert.Equal(t, 200000, first.Charges)
+	assert.Equal(t, 3000, first.Fees)
+	assert.Equal(t, 297000, first.ClosingBalance)
+
+	// A quiet day still carries the balance
+	quiet := report.Rows[1]
+	assert.Equal(t, "2026-03-02", quiet.Date)
+	assert.Equal(t, 297000, quiet.OpeningBalance)
+	assert.Equal(t, 297000, quiet.ClosingBalance)
+
+	cash := report.Rows[2]
+	assert.Equal(t, "2026-03-03", cash.Date)
+	assert.Nil(t, cash.OperatorID)
+	assert.Equal(t, model.PaymentMethodCash, cash.PaymentMethod)
+	assert.Equal(t, -5000, cash.Adjustments)
+	assert.Equal(t, -5000, cash.ClosingBalance)
+
+	last := report.Rows[3]
+	assert.Equal(t, "2026-03-03", last.Date)
+	assert.Equal(t, &operatorID, last.OperatorID)
+	assert.Equal(t, 80000, last.Refunds)
+	assert.Equal(t, 217000, last.ClosingBalance)
+}
+
+func TestGetSettlementReport_InvalidRange(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, _ := newLedgerService(ctrl)
+
+	tests := []struct {
+		name string
+		from string
+		to   string
+	}{
+		{"from after to", "2026-03-02", "2026-03-01"},
+		{"too long", "2026-01-01", "2026-06-30"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := service.GetSettlementReport(context.Background(), &model.SettlementReportQuery{From: day(tt.from), To: day(tt.to)})
+
+			var apiErr *ginext.Error
+			assert.ErrorAs(t, err, &apiErr)
+			assert.Equal(t, http.StatusBadRequest, apiErr.Code)
+		})
+	}
+}
+
+func TestExportSettlementReportCSV(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, m := newLedgerService(ctrl)
+	ctx := context.Background()
+
+	query := &model.SettlementReportQuery{From: day("2026-03-01"), To: day("2026-03-01")}
+	m.ledgerRepo.EXPECT().GetBalances(ctx, query, gomock.Any()).Return(nil, nil)
+	m.ledgerRepo.EXPECT().GetDailyMovements(ctx, query, gomock.Any(), gomock.Any()).Return([]*model.LedgerMovement{
+		{Day: day("2026-03-01"), PaymentMethod: model.PaymentMethodCash, Kind: model.LedgerJournalKindCharge, Amount: 150000},
+	}, nil)
+
+	data, err := service.ExportSettlementReportCSV(ctx, query)
+
+	assert.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	assert.Equal(t, []string{
+		"date,operator_id,payment_method,opening_balance,charges,refunds,fees,adjustments,closing_balance",
+		"2026-03-01,,CASH,0,150000,0,0,0,150000",
+	}, lines)
+}
+
+func TestExportSettlementReportExcel_UsesExcelGenerator(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, m := newLedgerService(ctrl)
+	ctx := context.Background()
+
+	query := &model.SettlementReportQuery{From: day("2026-03-01"), To: day("2026-03-01")}
+	m.ledgerRepo.EXPECT().GetBalances(ctx, query, gomock.Any()).Return(nil, nil)
+	m.ledgerRepo.EXPECT().GetDailyMovements(ctx, query, gomock.Any(), gomock.Any()).Return(nil, nil)
+	m.excelService.EXPECT().
+		GenerateSettlementExcel(gomock.Any()).
+		DoAndReturn(func(report *model.SettlementReport) ([]byte, error) {
+			assert.Equal(t, "2026-03-01", report.From)
+			assert.Empty(t, report.Rows)
+			return []byte("xlsx"), nil
+		})
+
+	data, err := service.ExportSettlementReportExcel(ctx, query)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("xlsx"), data)
+}
@@ -48,3 +48,18 @@ func (mr *MockExcelServiceMockRecorder) GenerateRefundExcel(refunds interface{})
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GenerateRefundExcel", reflect.TypeOf((*MockExcelService)(nil).GenerateRefundExcel), refunds)
 }
+
+// GenerateSettlementExcel mocks base method.
+func (m *MockExcelService) GenerateSettlementExcel(report *model.SettlementReport) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GenerateSettlementExcel", report)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GenerateSettlementExcel indicates an expected call of GenerateSettlementExcel.
+func (mr *MockExcelServiceMockRecorder) GenerateSettlementExcel(report interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GenerateSettlementExcel", reflect.TypeOf((*MockExcelService)(nil).GenerateSettlementExcel), report)
+}
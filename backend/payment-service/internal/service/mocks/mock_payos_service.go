@@ -1,5 +1,5 @@
 // Code generated by MockGen. DO NOT EDIT.
-// Source: internal/service/payos_service.go
+// Source: bus-booking/payment-service/internal/service (interfaces: PayOSService)
 
 // Package mocks is a generated GoMock package.
 package mocks
@@ -36,75 +36,91 @@ func (m *MockPayOSService) EXPECT() *MockPayOSServiceMockRecorder {
 	return m.recorder
 }
 
-// CancelPaymentLink mocks base method.
-func (m *MockPayOSService) CancelPaymentLink(ctx context.Context, paymentLinkID string, cancellationReason *string) (*payos.PaymentLink, error) {
+// CancelPayment mocks base method.
+func (m *MockPayOSService) CancelPayment(arg0 context.Context, arg1, arg2 string) (*model.ProviderPayment, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CancelPaymentLink", ctx, paymentLinkID, cancellationReason)
-	ret0, _ := ret[0].(*payos.PaymentLink)
+	ret := m.ctrl.Call(m, "CancelPayment", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*model.ProviderPayment)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// CancelPaymentLink indicates an expected call of CancelPaymentLink.
-func (mr *MockPayOSServiceMockRecorder) CancelPaymentLink(ctx, paymentLinkID, cancellationReason interface{}) *gomock.Call {
+// CancelPayment indicates an expected call of CancelPayment.
+func (mr *MockPayOSServiceMockRecorder) CancelPayment(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelPaymentLink", reflect.TypeOf((*MockPayOSService)(nil).CancelPaymentLink), ctx, paymentLinkID, cancellationReason)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelPayment", reflect.TypeOf((*MockPayOSService)(nil).CancelPayment), arg0, arg1, arg2)
 }
 
-// CreatePaymentLink mocks base method.
-func (m *MockPayOSService) CreatePaymentLink(ctx context.Context, req *model.CreatePaymentLinkRequest) (*payos.CreatePaymentLinkResponse, error) {
+// CreatePayment mocks base method.
+func (m *MockPayOSService) CreatePayment(arg0 context.Context, arg1 *model.CreatePaymentLinkRequest) (*model.ProviderPayment, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreatePaymentLink", ctx, req)
-	ret0, _ := ret[0].(*payos.CreatePaymentLinkResponse)
+	ret := m.ctrl.Call(m, "CreatePayment", arg0, arg1)
+	ret0, _ := ret[0].(*model.ProviderPayment)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// CreatePaymentLink indicates an expected call of CreatePaymentLink.
-func (mr *MockPayOSServiceMockRecorder) CreatePaymentLink(ctx, req interface{}) *gomock.Call {
+// CreatePayment indicates an expected call of CreatePayment.
+func (mr *MockPayOSServiceMockRecorder) CreatePayment(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePaymentLink", reflect.TypeOf((*MockPayOSService)(nil).CreatePaymentLink), ctx, req)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePayment", reflect.TypeOf((*MockPayOSService)(nil).CreatePayment), arg0, arg1)
 }
 
-// GetPaymentLink mocks base method.
-func (m *MockPayOSService) GetPaymentLink(ctx context.Context, paymentLinkID string) (*payos.PaymentLink, error) {
+// GetPayment mocks base method.
+func (m *MockPayOSService) GetPayment(arg0 context.Context, arg1 string) (*model.ProviderPayment, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetPaymentLink", ctx, paymentLinkID)
-	ret0, _ := ret[0].(*payos.PaymentLink)
+	ret := m.ctrl.Call(m, "GetPayment", arg0, arg1)
+	ret0, _ := ret[0].(*model.ProviderPayment)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetPaymentLink indicates an expected call of GetPaymentLink.
-func (mr *MockPayOSServiceMockRecorder) GetPaymentLink(ctx, paymentLinkID interface{}) *gomock.Call {
+// GetPayment indicates an expected call of GetPayment.
+func (mr *MockPayOSServiceMockRecorder) GetPayment(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPaymentLink", reflect.TypeOf((*MockPayOSService)(nil).GetPaymentLink), ctx, paymentLinkID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPayment", reflect.TypeOf((*MockPayOSService)(nil).GetPayment), arg0, arg1)
+}
+
+// Refund mocks base method.
+func (m *MockPayOSService) Refund(arg0 context.Context, arg1 *model.ProviderRefundRequest) (*model.ProviderRefund, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Refund", arg0, arg1)
+	ret0, _ := ret[0].(*model.ProviderRefund)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Refund indicates an expected call of Refund.
+func (mr *MockPayOSServiceMockRecorder) Refund(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Refund", reflect.TypeOf((*MockPayOSService)(nil).Refund), arg0, arg1)
 }
 
 // ToTransactionStatus mocks base method.
-func (m *MockPayOSService) ToTransactionStatus(payOSStatus payos.PaymentLinkStatus) model.TransactionStatus {
+func (m *MockPayOSService) ToTransactionStatus(arg0 payos.PaymentLinkStatus) model.TransactionStatus {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ToTransactionStatus", payOSStatus)
+	ret := m.ctrl.Call(m, "ToTransactionStatus", arg0)
 	ret0, _ := ret[0].(model.TransactionStatus)
 	return ret0
 }
 
 // ToTransactionStatus indicates an expected call of ToTransactionStatus.
-func (mr *MockPayOSServiceMockRecorder) ToTransactionStatus(payOSStatus interface{}) *gomock.Call {
+func (mr *MockPayOSServiceMockRecorder) ToTransactionStatus(arg0 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ToTransactionStatus", reflect.TypeOf((*MockPayOSService)(nil).ToTransactionStatus), payOSStatus)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ToTransactionStatus", reflect.TypeOf((*MockPayOSService)(nil).ToTransactionStatus), arg0)
 }
 
 // VerifyWebhook mocks base method.
-func (m *MockPayOSService) VerifyWebhook(ctx context.Context, webhookData map[string]interface{}) error {
+func (m *MockPayOSService) VerifyWebhook(arg0 context.Context, arg1 map[string]interface{}) (*model.ProviderWebhook, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "VerifyWebhook", ctx, webhookData)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "VerifyWebhook", arg0, arg1)
+	ret0, _ := ret[0].(*model.ProviderWebhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // VerifyWebhook indicates an expected call of VerifyWebhook.
-func (mr *MockPayOSServiceMockRecorder) VerifyWebhook(ctx, webhookData interface{}) *gomock.Call {
+func (mr *MockPayOSServiceMockRecorder) VerifyWebhook(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyWebhook", reflect.TypeOf((*MockPayOSService)(nil).VerifyWebhook), ctx, webhookData)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyWebhook", reflect.TypeOf((*MockPayOSService)(nil).VerifyWebhook), arg0, arg1)
 }
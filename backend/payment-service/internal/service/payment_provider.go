@@ -0,0 +1,56 @@
+package service
+
+import (
+	"bus-booking/payment-service/internal/model"
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+)
+
+var (
+	// ErrRefundNotSupported means the provider cannot pay a refund out; it is transferred manually instead
+	ErrRefundNotSupported = errors.New("payment provider does not pay out refunds")
+	// ErrWebhookNotSupported means the provider never notifies us, payments are confirmed another way
+	ErrWebhookNotSupported = errors.New("payment provider does not send webhooks")
+	// ErrPaymentNotTracked means the provider keeps no state we could poll
+	ErrPaymentNotTracked = errors.New("payment provider does not track payments")
+)
+
+// PaymentProvider collects booking payments for one payment method
+type PaymentProvider interface {
+	CreatePayment(ctx context.Context, req *model.CreatePaymentLinkRequest) (*model.ProviderPayment, error)
+	GetPayment(ctx context.Context, paymentLinkID string) (*model.ProviderPayment, error)
+	CancelPayment(ctx context.Context, paymentLinkID string, reason string) (*model.ProviderPayment, error)
+	// VerifyWebhook checks a provider notification and tells which payment it is about
+	VerifyWebhook(ctx context.Context, payload map[string]interface{}) (*model.ProviderWebhook, error)
+	Refund(ctx context.Context, req *model.ProviderRefundRequest) (*model.ProviderRefund, error)
+}
+
+// PaymentProviders selects the provider of a transaction by its payment method
+type PaymentProviders map[model.PaymentMethod]PaymentProvider
+
+func (p PaymentProviders) Get(method model.PaymentMethod) (PaymentProvider, error) {
+	provider, ok := p[method]
+	if !ok {
+		return nil, fmt.Errorf("unsupported payment method %q", method)
+	}
+	return provider, nil
+}
+
+var lastOrderCode atomic.Int64
+
+// nextOrderCode hands out order codes from the current millisecond, never repeating one within the process
+func nextOrderCode() int64 {
+	for {
+		last := lastOrderCode.Load()
+		next := time.Now().UnixMilli()
+		if next <= last {
+			next = last + 1
+		}
+		if lastOrderCode.CompareAndSwap(last, next) {
+			return next
+		}
+	}
+}
@@ -0,0 +1,163 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"bus-booking/payment-service/internal/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPaymentProviders_Get(t *testing.T) {
+	cash := NewCashProvider()
+	providers := PaymentProviders{model.PaymentMethodCash: cash}
+
+	provider, err := providers.Get(model.PaymentMethodCash)
+	assert.NoError(t, err)
+	assert.Equal(t, cash, provider)
+
+	provider, err = providers.Get(model.PaymentMethodSandbox)
+	assert.Error(t, err)
+	assert.Nil(t, provider)
+}
+
+func TestNextOrderCode_Increasing(t *testing.T) {
+	previous := nextOrderCode()
+	for i := 0; i < 1000; i++ {
+		next := nextOrderCode()
+		assert.Greater(t, next, previous)
+		previous = next
+	}
+}
+
+func TestCashProvider(t *testing.T) {
+	provider := NewCashProvider()
+	ctx := context.Background()
+
+	payment, err := provider.CreatePayment(ctx, &model.CreatePaymentLinkRequest{Amount: 150000})
+	assert.NoError(t, err)
+	assert.Equal(t, model.TransactionStatusPending, payment.Status)
+	assert.NotZero(t, payment.OrderCode)
+	// Nothing to poll, the reconciliation worker skips it
+	assert.Empty(t, payment.PaymentLinkID)
+	assert.Empty(t, payment.CheckoutURL)
+
+	_, err = provider.GetPayment(ctx, "")
+	assert.ErrorIs(t, err, ErrPaymentNotTracked)
+
+	_, err = provider.VerifyWebhook(ctx, map[string]interface{}{})
+	assert.ErrorIs(t, err, ErrWebhookNotSupported)
+
+	_, err = provider.Refund(ctx, &model.ProviderRefundRequest{Amount: 150000})
+	assert.ErrorIs(t, err, ErrRefundNotSupported)
+}
+
+func TestSandboxProvider_Pay(t *testing.T) {
+	provider := NewSandboxProvider("http://localhost:3000/payment/sandbox")
+	ctx := context.Background()
+
+	payment, err := provider.CreatePayment(ctx, &model.CreatePaymentLinkRequest{
+		Amount:    150000,
+		ExpiresAt: time.Now().Add(15 * time.Minute),
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, model.TransactionStatusPending, payment.Status)
+	assert.Contains(t, payment.CheckoutURL, payment.PaymentLinkID)
+
+	webhook, err := provider.VerifyWebhook(ctx, map[string]interface{}{
+		"payment_link_id": payment.PaymentLinkID,
+		"status":          "PAID",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, payment.OrderCode, webhook.OrderCode)
+	assert.Equal(t, payment.PaymentLinkID, webhook.PaymentLinkID)
+	assert.NotEmpty(t, webhook.Reference)
+	assert.NotNil(t, webhook.PaidAt)
+
+	paid, err := provider.GetPayment(ctx, payment.PaymentLinkID)
+	assert.NoError(t, err)
+	assert.Equal(t, model.TransactionStatusPaid, paid.Status)
+	assert.Equal(t, 150000, paid.AmountPaid)
+
+	// A settled payment cannot be paid or cancelled again
+	_, err = provider.VerifyWebhook(ctx, map[string]interface{}{
+		"payment_link_id": payment.PaymentLinkID,
+		"status":          "PAID",
+	})
+	assert.Error(t, err)
+	_, err = provider.CancelPayment(ctx, payment.PaymentLinkID, "test")
+	assert.Error(t, err)
+
+	_, err = provider.Refund(ctx, &model.ProviderRefundRequest{PaymentLinkID: payment.PaymentLinkID, Amount: 100000})
+	assert.NoError(t, err)
+	_, err = provider.Refund(ctx, &model.ProviderRefundRequest{PaymentLinkID: payment.PaymentLinkID, Amount: 100000})
+	assert.Error(t, err)
+}
+
+func TestSandboxProvider_ShortTransfersAddUp(t *testing.T) {
+	provider := NewSandboxProvider("http://localhost:3000/payment/sandbox")
+	ctx := context.Background()
+
+	payment, err := provider.CreatePayment(ctx, &model.CreatePaymentLinkRequest{
+		Amount:    150000,
+		ExpiresAt: time.Now().Add(15 * time.Minute),
+	})
+	assert.NoError(t, err)
+
+	first, err := provider.VerifyWebhook(ctx, map[string]interface{}{
+		"payment_link_id": payment.PaymentLinkID,
+		"status":          "PAID",
+		"amount":          float64(100000),
+	})
+	assert.NoError(t, err)
+
+	underpaid, err := provider.GetPayment(ctx, payment.PaymentLinkID)
+	assert.NoError(t, err)
+	assert.Equal(t, model.TransactionStatusUnderpaid, underpaid.Status)
+	assert.Equal(t, 100000, underpaid.AmountPaid)
+
+	// The top-up pays the rest and more; each transfer is its own notification
+	second, err := provider.VerifyWebhook(ctx, map[string]interface{}{
+		"payment_link_id": payment.PaymentLinkID,
+		"status":          "PAID",
+		"amount":          float64(70000),
+	})
+	assert.NoError(t, err)
+	assert.NotEqual(t, first.EventID, second.EventID)
+
+	paid, err := provider.GetPayment(ctx, payment.PaymentLinkID)
+	assert.NoError(t, err)
+	assert.Equal(t, model.TransactionStatusPaid, paid.Status)
+	assert.Equal(t, 170000, paid.AmountPaid)
+}
+
+func TestSandboxProvider_Expires(t *testing.T) {
+	provider := NewSandboxProvider("http://localhost:3000/payment/sandbox")
+	ctx := context.Background()
+
+	payment, err := provider.CreatePayment(ctx, &model.CreatePaymentLinkRequest{
+		Amount:    150000,
+		ExpiresAt: time.Now().Add(-time.Second),
+	})
+	assert.NoError(t, err)
+
+	expired, err := provider.GetPayment(ctx, payment.PaymentLinkID)
+	assert.NoError(t, err)
+	assert.Equal(t, model.TransactionStatusExpired, expired.Status)
+
+	_, err = provider.VerifyWebhook(ctx, map[string]interface{}{
+		"payment_link_id": payment.PaymentLinkID,
+		"status":          "PAID",
+	})
+	assert.Error(t, err)
+}
+
+func TestSandboxProvider_UnknownPayment(t *testing.T) {
+	provider := NewSandboxProvider("http://localhost:3000/payment/sandbox")
+
+	_, err := provider.GetPayment(context.Background(), "sandbox-1")
+
+	assert.Error(t, err)
+}
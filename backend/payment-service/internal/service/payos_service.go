@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"time"
 
@@ -11,11 +12,12 @@ import (
 	"github.com/payOSHQ/payos-lib-golang/v2"
 )
 
+// payOSDateTimeLayout is the layout of PayOS transaction timestamps
+const payOSDateTimeLayout = "2006-01-02 15:04:05"
+
+// PayOSService is the PayOS payment provider
 type PayOSService interface {
-	CreatePaymentLink(ctx context.Context, req *model.CreatePaymentLinkRequest) (*payos.CreatePaymentLinkResponse, error)
-	GetPaymentLink(ctx context.Context, paymentLinkID string) (*payos.PaymentLink, error)
-	VerifyWebhook(ctx context.Context, webhookData map[string]interface{}) error
-	CancelPaymentLink(ctx context.Context, paymentLinkID string, cancellationReason *string) (*payos.PaymentLink, error)
+	PaymentProvider
 	ToTransactionStatus(payOSStatus payos.PaymentLinkStatus) model.TransactionStatus
 }
 
@@ -43,7 +45,7 @@ func NewPayOSService(cfg config.PayOSConfig) PayOSService {
 	}
 }
 
-func (c *PayOSServiceImpl) CreatePaymentLink(ctx context.Context, req *model.CreatePaymentLinkRequest) (*payos.CreatePaymentLinkResponse, error) {
+func (c *PayOSServiceImpl) CreatePayment(ctx context.Context, req *model.CreatePaymentLinkRequest) (*model.ProviderPayment, error) {
 	// Convert to UTC to ensure consistent timezone handling
 	expiredAtUTC := req.ExpiresAt.UTC()
 	nowUTC := time.Now().UTC()
@@ -76,27 +78,90 @@ func (c *PayOSServiceImpl) CreatePaymentLink(ctx context.Context, req *model.Cre
 		return nil, fmt.Errorf("failed to create payment link: %w", err)
 	}
 
-	return paymentLinkResponse, nil
+	return &model.ProviderPayment{
+		OrderCode:     paymentLinkResponse.OrderCode,
+		PaymentLinkID: paymentLinkResponse.PaymentLinkId,
+		Status:        c.ToTransactionStatus(paymentLinkResponse.Status),
+		Amount:        paymentLinkResponse.Amount,
+		CheckoutURL:   paymentLinkResponse.CheckoutUrl,
+		QRCode:        paymentLinkResponse.QrCode,
+	}, nil
 }
 
-func (c *PayOSServiceImpl) GetPaymentLink(ctx context.Context, paymentLinkID string) (*payos.PaymentLink, error) {
-	return c.payOSClient.PaymentRequests.Get(ctx, paymentLinkID)
+func (c *PayOSServiceImpl) GetPayment(ctx context.Context, paymentLinkID string) (*model.ProviderPayment, error) {
+	paymentLink, err := c.payOSClient.PaymentRequests.Get(ctx, paymentLinkID)
+	if err != nil {
+		return nil, err
+	}
+	return c.toProviderPayment(paymentLink), nil
 }
 
 func (c *PayOSServiceImpl) generateOrderCode() int64 {
-	return time.Now().UnixNano() / int64(time.Millisecond)
+	return nextOrderCode()
 }
 
-func (c *PayOSServiceImpl) VerifyWebhook(ctx context.Context, webhookData map[string]interface{}) error {
-	_, err := c.payOSClient.Webhooks.VerifyData(ctx, webhookData)
+func (c *PayOSServiceImpl) VerifyWebhook(ctx context.Context, webhookData map[string]interface{}) (*model.ProviderWebhook, error) {
+	data, err := c.payOSClient.Webhooks.VerifyData(ctx, webhookData)
+	if err != nil {
+		return nil, err
+	}
+
+	// The verified data is the webhook "data" object
+	raw, err := json.Marshal(data)
 	if err != nil {
-		return err
+		return nil, fmt.Errorf("invalid webhook data: %w", err)
 	}
-	return nil
+	var details model.PaymentWebhookDetails
+	if err := json.Unmarshal(raw, &details); err != nil {
+		return nil, fmt.Errorf("invalid webhook data: %w", err)
+	}
+
+	return &model.ProviderWebhook{
+		// PayOS redelivers a notification until it is acknowledged, the bank reference tells the payments apart
+		EventID:       fmt.Sprintf("%d:%s", details.OrderCode, details.Reference),
+		OrderCode:     int64(details.OrderCode),
+		PaymentLinkID: details.PaymentLinkID,
+		Reference:     details.Reference,
+		PaidAt:        parsePayOSDateTime(details.TransactionDateTime),
+	}, nil
 }
 
-func (c *PayOSServiceImpl) CancelPaymentLink(ctx context.Context, paymentLinkID string, cancellationReason *string) (*payos.PaymentLink, error) {
-	return c.payOSClient.PaymentRequests.Cancel(ctx, paymentLinkID, cancellationReason)
+func (c *PayOSServiceImpl) CancelPayment(ctx context.Context, paymentLinkID string, reason string) (*model.ProviderPayment, error) {
+	paymentLink, err := c.payOSClient.PaymentRequests.Cancel(ctx, paymentLinkID, &reason)
+	if err != nil {
+		return nil, err
+	}
+	return c.toProviderPayment(paymentLink), nil
+}
+
+// Refund is not offered by PayOS payment links; refunds are transferred to the customer's bank account
+func (c *PayOSServiceImpl) Refund(ctx context.Context, req *model.ProviderRefundRequest) (*model.ProviderRefund, error) {
+	return nil, ErrRefundNotSupported
+}
+
+func (c *PayOSServiceImpl) toProviderPayment(paymentLink *payos.PaymentLink) *model.ProviderPayment {
+	payment := &model.ProviderPayment{
+		OrderCode:     paymentLink.OrderCode,
+		PaymentLinkID: paymentLink.Id,
+		Status:        c.ToTransactionStatus(paymentLink.Status),
+		Amount:        paymentLink.Amount,
+		AmountPaid:    paymentLink.AmountPaid,
+	}
+
+	// The latest bank transaction carries the reference the webhook would have sent
+	if n := len(paymentLink.Transactions); n > 0 {
+		payment.Reference = paymentLink.Transactions[n-1].Reference
+		payment.PaidAt = parsePayOSDateTime(paymentLink.Transactions[n-1].TransactionDateTime)
+	}
+	return payment
+}
+
+func parsePayOSDateTime(value string) *time.Time {
+	t, err := time.Parse(payOSDateTimeLayout, value)
+	if err != nil {
+		return nil
+	}
+	return &t
 }
 
 func (c *PayOSServiceImpl) ToTransactionStatus(payOSStatus payos.PaymentLinkStatus) model.TransactionStatus {
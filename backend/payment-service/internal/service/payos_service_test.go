@@ -97,7 +97,7 @@ func TestToTransactionStatus(t *testing.T) {
 	}
 }
 
-func TestCreatePaymentLink_ExpirationValidation(t *testing.T) {
+func TestCreatePayment_ExpirationValidation(t *testing.T) {
 	cfg := config.PayOSConfig{
 		ClientID:    "test-client-id",
 		APIKey:      "test-api-key",
@@ -116,7 +116,7 @@ func TestCreatePaymentLink_ExpirationValidation(t *testing.T) {
 			ExpiresAt:   time.Now().Add(-1 * time.Hour), // Past time
 		}
 
-		result, err := service.CreatePaymentLink(ctx, req)
+		result, err := service.CreatePayment(ctx, req)
 
 		assert.Error(t, err)
 		assert.Nil(t, result)
@@ -130,7 +130,7 @@ func TestCreatePaymentLink_ExpirationValidation(t *testing.T) {
 			ExpiresAt:   time.Date(2039, 1, 1, 0, 0, 0, 0, time.UTC),
 		}
 
-		result, err := service.CreatePaymentLink(ctx, req)
+		result, err := service.CreatePayment(ctx, req)
 
 		assert.Error(t, err)
 		assert.Nil(t, result)
@@ -140,7 +140,7 @@ func TestCreatePaymentLink_ExpirationValidation(t *testing.T) {
 
 // Note: Integration tests with actual PayOS API are skipped in unit tests
 // These would require valid credentials and should be tested separately
-func TestCreatePaymentLink_Integration_Skipped(t *testing.T) {
+func TestCreatePayment_Integration_Skipped(t *testing.T) {
 	t.Skip("Skipping integration test - requires actual PayOS credentials")
 
 	// This is an example of how to structure an integration test
@@ -161,18 +161,18 @@ func TestCreatePaymentLink_Integration_Skipped(t *testing.T) {
 	// 	ExpiresAt:   time.Now().Add(15 * time.Minute),
 	// }
 	//
-	// result, err := service.CreatePaymentLink(ctx, req)
+	// result, err := service.CreatePayment(ctx, req)
 	//
 	// assert.NoError(t, err)
 	// assert.NotNil(t, result)
-	// assert.NotEmpty(t, result.CheckoutUrl)
+	// assert.NotEmpty(t, result.CheckoutURL)
 }
 
-func TestGetPaymentLink_Integration_Skipped(t *testing.T) {
+func TestGetPayment_Integration_Skipped(t *testing.T) {
 	t.Skip("Skipping integration test - requires actual PayOS credentials")
 }
 
-func TestCancelPaymentLink_Integration_Skipped(t *testing.T) {
+func TestCancelPayment_Integration_Skipped(t *testing.T) {
 	t.Skip("Skipping integration test - requires actual PayOS credentials")
 }
 
@@ -0,0 +1,118 @@
+package service
+
+import (
+	"bus-booking/payment-service/internal/model"
+	"bus-booking/payment-service/internal/repository"
+	"bus-booking/shared/ginext"
+	"bus-booking/shared/outbox"
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+type ReconciliationService interface {
+	ListIssues(ctx context.Context, query *model.ReconciliationIssueListQuery) ([]*model.ReconciliationIssue, int64, error)
+	ResolveIssue(ctx context.Context, id uuid.UUID, req *model.ResolveReconciliationIssueRequest, adminID uuid.UUID) (*model.ReconciliationIssue, error)
+}
+
+type ReconciliationServiceImpl struct {
+	reconciliationRepo repository.ReconciliationRepository
+	transactionRepo    repository.TransactionRepository
+}
+
+func NewReconciliationService(
+	reconciliationRepo repository.ReconciliationRepository,
+	transactionRepo repository.TransactionRepository,
+) ReconciliationService {
+	return &ReconciliationServiceImpl{
+		reconciliationRepo: reconciliationRepo,
+		transactionRepo:    transactionRepo,
+	}
+}
+
+func (s *ReconciliationServiceImpl) ListIssues(ctx context.Context, query *model.ReconciliationIssueListQuery) ([]*model.ReconciliationIssue, int64, error) {
+	issues, total, err := s.reconciliationRepo.List(ctx, query)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to list reconciliation issues")
+		return nil, 0, ginext.NewInternalServerError("failed to list reconciliation issues")
+	}
+	return issues, total, nil
+}
+
+// ResolveIssue records the provider status on the transaction; applying it also notifies booking service
+func (s *ReconciliationServiceImpl) ResolveIssue(ctx context.Context, id uuid.UUID, req *model.ResolveReconciliationIssueRequest, adminID uuid.UUID) (*model.ReconciliationIssue, error) {
+	issue, err := s.reconciliationRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, ginext.NewNotFoundError("reconciliation issue not found")
+	}
+	if issue.Status != model.ReconciliationIssueStatusOpen {
+		return nil, ginext.NewConflictError("reconciliation issue already resolved")
+	}
+
+	transaction, err := s.transactionRepo.GetByID(ctx, issue.TransactionID)
+	if err != nil {
+		return nil, ginext.NewNotFoundError("transaction not found")
+	}
+
+	transaction.Status = issue.ProviderStatus
+	if issue.ProviderReference != "" {
+		transaction.Reference = issue.ProviderReference
+	}
+	if issue.ProviderPaidAt != nil {
+		paidAtUnix := issue.ProviderPaidAt.Unix()
+		transaction.TransactionTime = &paidAtUnix
+	}
+
+	var events []*outbox.Event
+	if req.Resolution == model.ReconciliationResolutionApply {
+		event, err := outbox.NewEvent(model.AggregateTypeTransaction, transaction.ID, model.EventTypePaymentStatusChanged, &model.PaymentStatusChangedEvent{
+			BookingID:         transaction.BookingID,
+			TransactionID:     transaction.ID,
+			TransactionStatus: transaction.Status,
+		})
+		if err != nil {
+			return nil, ginext.NewInternalServerError(err.Error())
+		}
+		events = append(events, event)
+
+		if transaction.Status == model.TransactionStatusPaid && transaction.InvoiceBuyer != nil {
+			invoice, err := newInvoiceDueEvent(transaction)
+			if err != nil {
+				return nil, ginext.NewInternalServerError(err.Error())
+			}
+			events = append(events, invoice)
+		}
+	}
+	// The provider holds the money whether or not the booking takes it
+	if transaction.Status == model.TransactionStatusPaid {
+		event, err := newPaymentCapturedEvent(transaction)
+		if err != nil {
+			return nil, ginext.NewInternalServerError(err.Error())
+		}
+		events = append(events, event)
+	}
+
+	now := time.Now()
+	resolution := req.Resolution
+	issue.Status = model.ReconciliationIssueStatusResolved
+	issue.Resolution = &resolution
+	issue.ResolvedBy = &adminID
+	issue.ResolvedAt = &now
+	if req.Note != "" {
+		note := req.Note
+		issue.ResolutionNote = &note
+	}
+
+	resolved, err := s.reconciliationRepo.Resolve(ctx, issue, transaction, events...)
+	if err != nil {
+		log.Error().Err(err).Str("issue_id", id.String()).Msg("Failed to resolve reconciliation issue")
+		return nil, ginext.NewInternalServerError("failed to resolve reconciliation issue")
+	}
+	if !resolved {
+		return nil, ginext.NewConflictError("reconciliation issue already resolved")
+	}
+
+	return issue, nil
+}
@@ -0,0 +1,141 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"bus-booking/payment-service/internal/model"
+	repo_mocks "bus-booking/payment-service/internal/repository/mocks"
+	"bus-booking/shared/ginext"
+	"bus-booking/shared/outbox"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func newOpenIssue(transaction *model.Transaction) *model.ReconciliationIssue {
+	paidAt := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+	return &model.ReconciliationIssue{
+		ID:                uuid.New(),
+		TransactionID:     transaction.ID,
+		BookingID:         transaction.BookingID,
+		IssueType:         model.ReconciliationIssuePaidAfterExpired,
+		LocalStatus:       transaction.Status,
+		ProviderStatus:    model.TransactionStatusPaid,
+		Amount:            transaction.Amount,
+		ProviderAmount:    transaction.Amount,
+		ProviderReference: "FT001",
+		ProviderPaidAt:    &paidAt,
+		Status:            model.ReconciliationIssueStatusOpen,
+	}
+}
+
+func TestResolveIssue_Apply_NotifiesBooking(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockReconciliationRepo := repo_mocks.NewMockReconciliationRepository(ctrl)
+	mockTransactionRepo := repo_mocks.NewMockTransactionRepository(ctrl)
+	service := NewReconciliationService(mockReconciliationRepo, mockTransactionRepo)
+
+	ctx := context.Background()
+	adminID := uuid.New()
+	transaction := newPendingTransaction(time.Now().Add(-time.Hour))
+	issue := newOpenIssue(transaction)
+
+	mockReconciliationRepo.EXPECT().GetByID(ctx, issue.ID).Return(issue, nil)
+	mockTransactionRepo.EXPECT().GetByID(ctx, transaction.ID).Return(transaction, nil)
+	mockReconciliationRepo.EXPECT().
+		Resolve(ctx, issue, transaction, gomock.Any()).
+		DoAndReturn(func(ctx context.Context, issue *model.ReconciliationIssue, tx *model.Transaction, events ...*outbox.Event) (bool, error) {
+			assert.Equal(t, model.ReconciliationIssueStatusResolved, issue.Status)
+			assert.Equal(t, model.ReconciliationResolutionApply, *issue.Resolution)
+			assert.Equal(t, adminID, *issue.ResolvedBy)
+			assert.Equal(t, "Customer travelled", *issue.ResolutionNote)
+
+			assert.Equal(t, model.TransactionStatusPaid, tx.Status)
+			assert.Equal(t, "FT001", tx.Reference)
+			assert.NotNil(t, tx.TransactionTime)
+
+			assert.Len(t, events, 2)
+			assert.Equal(t, model.EventTypePaymentCaptured, events[1].EventType)
+			var payload model.PaymentStatusChangedEvent
+			assert.NoError(t, events[0].Decode(&payload))
+			assert.Equal(t, transaction.BookingID, payload.BookingID)
+			assert.Equal(t, model.TransactionStatusPaid, payload.TransactionStatus)
+			return true, nil
+		})
+
+	resolved, err := service.ResolveIssue(ctx, issue.ID, &model.ResolveReconciliationIssueRequest{
+		Resolution: model.ReconciliationResolutionApply,
+		Note:       "Customer travelled",
+	}, adminID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, model.ReconciliationIssueStatusResolved, resolved.Status)
+}
+
+func TestResolveIssue_Ignore_LeavesBooking(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockReconciliationRepo := repo_mocks.NewMockReconciliationRepository(ctrl)
+	mockTransactionRepo := repo_mocks.NewMockTransactionRepository(ctrl)
+	service := NewReconciliationService(mockReconciliationRepo, mockTransactionRepo)
+
+	ctx := context.Background()
+	transaction := newPendingTransaction(time.Now().Add(-time.Hour))
+	issue := newOpenIssue(transaction)
+
+	mockReconciliationRepo.EXPECT().GetByID(ctx, issue.ID).Return(issue, nil)
+	mockTransactionRepo.EXPECT().GetByID(ctx, transaction.ID).Return(transaction, nil)
+	mockReconciliationRepo.EXPECT().
+		Resolve(ctx, issue, transaction, gomock.Any()).
+		DoAndReturn(func(ctx context.Context, issue *model.ReconciliationIssue, tx *model.Transaction, events ...*outbox.Event) (bool, error) {
+			assert.Equal(t, model.ReconciliationResolutionIgnore, *issue.Resolution)
+			assert.Nil(t, issue.ResolutionNote)
+			assert.Equal(t, model.TransactionStatusPaid, tx.Status)
+			// Only the ledger hears of the payment
+			assert.Len(t, events, 1)
+			assert.Equal(t, model.EventTypePaymentCaptured, events[0].EventType)
+			return true, nil
+		})
+
+	_, err := service.ResolveIssue(ctx, issue.ID, &model.ResolveReconciliationIssueRequest{
+		Resolution: model.ReconciliationResolutionIgnore,
+	}, uuid.New())
+
+	assert.NoError(t, err)
+}
+
+func TestResolveIssue_AlreadyResolved(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockReconciliationRepo := repo_mocks.NewMockReconciliationRepository(ctrl)
+	mockTransactionRepo := repo_mocks.NewMockTransactionRepository(ctrl)
+	service := NewReconciliationService(mockReconciliationRepo, mockTransactionRepo)
+
+	ctx := context.Background()
+	transaction := newPendingTransaction(time.Now().Add(-time.Hour))
+	issue := newOpenIssue(transaction)
+
+	// Another admin resolved it between the read and the update
+	mockReconciliationRepo.EXPECT().GetByID(ctx, issue.ID).Return(issue, nil)
+	mockTransactionRepo.EXPECT().GetByID(ctx, transaction.ID).Return(transaction, nil)
+	mockReconciliationRepo.EXPECT().
+		Resolve(ctx, issue, transaction, gomock.Any()).
+		Return(false, nil)
+
+	_, err := service.ResolveIssue(ctx, issue.ID, &model.ResolveReconciliationIssueRequest{
+		Resolution: model.ReconciliationResolutionApply,
+	}, uuid.New())
+
+	assert.Error(t, err)
+	var apiErr *ginext.Error
+	assert.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusConflict, apiErr.Code)
+}
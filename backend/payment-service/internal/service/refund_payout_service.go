@@ -0,0 +1,367 @@
+package service
+
+import (
+	"bus-booking/payment-service/internal/client"
+	"bus-booking/payment-service/internal/model"
+	"bus-booking/payment-service/internal/model/notification"
+	"bus-booking/payment-service/internal/repository"
+	"bus-booking/shared/ginext"
+	"bus-booking/shared/outbox"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+type RefundPayoutService interface {
+	CreateBatch(ctx context.Context, req *model.CreateRefundPayoutBatchRequest, adminID uuid.UUID) (*model.RefundPayoutBatchResponse, error)
+	GetBatch(ctx context.Context, id uuid.UUID) (*model.RefundPayoutBatchResponse, error)
+	ListBatches(ctx context.Context, query *model.RefundPayoutBatchListQuery) ([]*model.RefundPayoutBatchResponse, int64, error)
+	SendBatch(ctx context.Context, id uuid.UUID) (*model.RefundPayoutBatchResponse, error)
+	ConfirmPayout(ctx context.Context, id uuid.UUID, req *model.ConfirmRefundPayoutRequest, adminID uuid.UUID) (*model.RefundPayout, error)
+	FailPayout(ctx context.Context, id uuid.UUID, req *model.FailRefundPayoutRequest) (*model.RefundPayout, error)
+
+	DeliverRefundPayoutSent(ctx context.Context, event *outbox.Event) error
+}
+
+type RefundPayoutServiceImpl struct {
+	payoutRepo         repository.RefundPayoutRepository
+	refundRepo         repository.RefundRepository
+	bankAccountRepo    repository.BankAccountRepository
+	constantsService   ConstantsService
+	userClient         client.UserClient
+	notificationClient client.NotificationClient
+	maxBatchSize       int
+}
+
+func NewRefundPayoutService(
+	payoutRepo repository.RefundPayoutRepository,
+	refundRepo repository.RefundRepository,
+	bankAccountRepo repository.BankAccountRepository,
+	constantsService ConstantsService,
+	userClient client.UserClient,
+	notificationClient client.NotificationClient,
+	maxBatchSize int,
+) RefundPayoutService {
+	return &RefundPayoutServiceImpl{
+		payoutRepo:         payoutRepo,
+		refundRepo:         refundRepo,
+		bankAccountRepo:    bankAccountRepo,
+		constantsService:   constantsService,
+		userClient:         userClient,
+		notificationClient: notificationClient,
+		maxBatchSize:       maxBatchSize,
+	}
+}
+
+// CreateBatch queues a transfer to the primary bank account of each approved refund.
+// Refunds that cannot be paid out are reported back and stay approved.
+func (s *RefundPayoutServiceImpl) CreateBatch(ctx context.Context, req *model.CreateRefundPayoutBatchRequest, adminID uuid.UUID) (*model.RefundPayoutBatchResponse, error) {
+	refunds, err := s.payoutRepo.ListPayableRefunds(ctx, req.RefundIDs, s.maxBatchSize)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to list payable refunds")
+		return nil, ginext.NewInternalServerError("failed to list approved refunds")
+	}
+
+	var skipped []model.SkippedRefund
+	if len(req.RefundIDs) > 0 {
+		payable := make(map[uuid.UUID]bool, len(refunds))
+		for _, refund := range refunds {
+			payable[refund.ID] = true
+		}
+		for _, id := range req.RefundIDs {
+			if !payable[id] {
+				skipped = append(skipped, model.SkippedRefund{RefundID: id, Reason: "refund is not approved or is already being paid out"})
+			}
+		}
+	}
+
+	banks, err := s.constantsService.GetBanks(ctx)
+	if err != nil {
+		return nil, ginext.NewInternalServerError("failed to get banks")
+	}
+
+	batch := &model.RefundPayoutBatch{
+		BaseModel: model.BaseModel{ID: uuid.New()},
+		CreatedBy: adminID,
+	}
+	for _, refund := range refunds {
+		payout, reason := s.newPayout(ctx, batch.ID, refund, banks)
+		if payout == nil {
+			skipped = append(skipped, model.SkippedRefund{RefundID: refund.ID, Reason: reason})
+			continue
+		}
+		batch.Payouts = append(batch.Payouts, payout)
+		batch.TotalAmount += payout.Amount
+	}
+	batch.PayoutCount = len(batch.Payouts)
+
+	if batch.PayoutCount == 0 {
+		return nil, ginext.NewBadRequestError(fmt.Sprintf("no approved refunds can be paid out (%d skipped)", len(skipped)))
+	}
+
+	if err := s.payoutRepo.CreateBatch(ctx, batch); err != nil {
+		log.Error().Err(err).Msg("Failed to create refund payout batch")
+		return nil, ginext.NewInternalServerError("failed to create refund payout batch")
+	}
+
+	log.Info().
+		Str("batch_id", batch.ID.String()).
+		Int("payout_count", batch.PayoutCount).
+		Int("total_amount", batch.TotalAmount).
+		Int("skipped", len(skipped)).
+		Msg("Refund payout batch created")
+
+	resp := s.toBatchResponse(batch)
+	resp.Skipped = skipped
+	return resp, nil
+}
+
+// newPayout builds the transfer of a refund, or tells why it cannot be made
+func (s *RefundPayoutServiceImpl) newPayout(ctx context.Context, batchID uuid.UUID, refund *model.Refund, banks []model.BankConstant) (*model.RefundPayout, string) {
+	account, err := s.bankAccountRepo.GetPrimaryBankAccount(ctx, refund.UserID)
+	if err != nil {
+		return nil, "passenger has no primary bank account"
+	}
+
+	var bank *model.BankConstant
+	for i := range banks {
+		if banks[i].Code == account.BankCode {
+			bank = &banks[i]
+			break
+		}
+	}
+	if bank == nil || bank.BIN == "" {
+		return nil, fmt.Sprintf("bank %s does not support VietQR transfers", account.BankCode)
+	}
+
+	content := refundTransferContent(refund)
+	payload, err := buildVietQRPayload(bank.BIN, account.AccountNumber, refund.RefundAmount, content)
+	if err != nil {
+		return nil, err.Error()
+	}
+
+	return &model.RefundPayout{
+		BatchID:         batchID,
+		RefundID:        refund.ID,
+		BookingID:       refund.BookingID,
+		UserID:          refund.UserID,
+		Amount:          refund.RefundAmount,
+		BankCode:        account.BankCode,
+		BankBIN:         bank.BIN,
+		AccountNumber:   account.AccountNumber,
+		AccountHolder:   account.AccountHolder,
+		TransferContent: content,
+		QRPayload:       payload,
+		Status:          model.RefundPayoutStatusQueued,
+	}, ""
+}
+
+func (s *RefundPayoutServiceImpl) GetBatch(ctx context.Context, id uuid.UUID) (*model.RefundPayoutBatchResponse, error) {
+	batch, err := s.payoutRepo.GetBatch(ctx, id)
+	if err != nil {
+		return nil, ginext.NewNotFoundError("refund payout batch not found")
+	}
+	return s.toBatchResponse(batch), nil
+}
+
+func (s *RefundPayoutServiceImpl) ListBatches(ctx context.Context, query *model.RefundPayoutBatchListQuery) ([]*model.RefundPayoutBatchResponse, int64, error) {
+	batches, total, err := s.payoutRepo.ListBatches(ctx, query)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to list refund payout batches")
+		return nil, 0, ginext.NewInternalServerError("failed to list refund payout batches")
+	}
+
+	responses := make([]*model.RefundPayoutBatchResponse, len(batches))
+	for i, batch := range batches {
+		responses[i] = s.toBatchResponse(batch)
+	}
+	return responses, total, nil
+}
+
+// SendBatch records that the queued transfers of a batch were submitted to the bank and notifies the passengers
+func (s *RefundPayoutServiceImpl) SendBatch(ctx context.Context, id uuid.UUID) (*model.RefundPayoutBatchResponse, error) {
+	batch, err := s.payoutRepo.GetBatch(ctx, id)
+	if err != nil {
+		return nil, ginext.NewNotFoundError("refund payout batch not found")
+	}
+
+	now := time.Now()
+	var (
+		queued []*model.RefundPayout
+		events []*outbox.Event
+	)
+	for _, payout := range batch.Payouts {
+		if payout.Status != model.RefundPayoutStatusQueued {
+			continue
+		}
+		payout.Status = model.RefundPayoutStatusSent
+		payout.SentAt = &now
+		queued = append(queued, payout)
+
+		event, err := outbox.NewEvent(model.AggregateTypeRefundPayout, payout.ID, model.EventTypeRefundPayoutSent, &model.RefundPayoutSentEvent{
+			PayoutID:        payout.ID,
+			RefundID:        payout.RefundID,
+			BookingID:       payout.BookingID,
+			UserID:          payout.UserID,
+			Amount:          payout.Amount,
+			BankCode:        payout.BankCode,
+			AccountNumber:   payout.AccountNumber,
+			TransferContent: payout.TransferContent,
+		})
+		if err != nil {
+			return nil, ginext.NewInternalServerError(err.Error())
+		}
+		events = append(events, event)
+	}
+	if len(queued) == 0 {
+		return nil, ginext.NewBadRequestError("refund payout batch has no queued payouts")
+	}
+
+	sent, err := s.payoutRepo.Transition(ctx, queued, model.RefundPayoutStatusQueued, nil, events...)
+	if err != nil {
+		log.Error().Err(err).Str("batch_id", id.String()).Msg("Failed to send refund payout batch")
+		return nil, ginext.NewInternalServerError("failed to send refund payout batch")
+	}
+	if !sent {
+		return nil, ginext.NewConflictError("refund payout batch was changed by someone else, reload it and try again")
+	}
+
+	return s.toBatchResponse(batch), nil
+}
+
+// ConfirmPayout matches a sent transfer against the bank statement and completes its refund
+func (s *RefundPayoutServiceImpl) ConfirmPayout(ctx context.Context, id uuid.UUID, req *model.ConfirmRefundPayoutRequest, adminID uuid.UUID) (*model.RefundPayout, error) {
+	payout, err := s.payoutRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, ginext.NewNotFoundError("refund payout not found")
+	}
+	if payout.Status != model.RefundPayoutStatusSent {
+		return nil, ginext.NewBadRequestError(fmt.Sprintf("refund payout is %s, only sent payouts can be confirmed", payout.Status))
+	}
+
+	refund, err := s.refundRepo.GetByID(ctx, payout.RefundID)
+	if err != nil {
+		return nil, ginext.NewNotFoundError("refund not found")
+	}
+
+	now := time.Now()
+	payout.Status = model.RefundPayoutStatusConfirmed
+	payout.BankReference = &req.BankReference
+	payout.ConfirmedAt = &now
+
+	refund.RefundStatus = model.RefundStatusCompleted
+	refund.ProcessedBy = &adminID
+	refund.ProcessedAt = &now
+
+	events, err := newRefundPaidBackEvents(refund)
+	if err != nil {
+		return nil, ginext.NewInternalServerError(err.Error())
+	}
+
+	confirmed, err := s.payoutRepo.Transition(ctx, []*model.RefundPayout{payout}, model.RefundPayoutStatusSent, []*model.Refund{refund}, events...)
+	if err != nil {
+		log.Error().Err(err).Str("payout_id", id.String()).Msg("Failed to confirm refund payout")
+		return nil, ginext.NewInternalServerError("failed to confirm refund payout")
+	}
+	if !confirmed {
+		return nil, ginext.NewConflictError("refund payout was changed by someone else")
+	}
+
+	return payout, nil
+}
+
+// FailPayout records a rejected transfer; the refund stays approved and goes into the next batch
+func (s *RefundPayoutServiceImpl) FailPayout(ctx context.Context, id uuid.UUID, req *model.FailRefundPayoutRequest) (*model.RefundPayout, error) {
+	payout, err := s.payoutRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, ginext.NewNotFoundError("refund payout not found")
+	}
+
+	from := payout.Status
+	if from != model.RefundPayoutStatusQueued && from != model.RefundPayoutStatusSent {
+		return nil, ginext.NewBadRequestError(fmt.Sprintf("refund payout is already %s", from))
+	}
+
+	now := time.Now()
+	payout.Status = model.RefundPayoutStatusFailed
+	payout.FailureReason = &req.Reason
+	payout.FailedAt = &now
+
+	failed, err := s.payoutRepo.Transition(ctx, []*model.RefundPayout{payout}, from, nil)
+	if err != nil {
+		log.Error().Err(err).Str("payout_id", id.String()).Msg("Failed to fail refund payout")
+		return nil, ginext.NewInternalServerError("failed to update refund payout")
+	}
+	if !failed {
+		return nil, ginext.NewConflictError("refund payout was changed by someone else")
+	}
+
+	return payout, nil
+}
+
+// DeliverRefundPayoutSent is the outbox handler emailing the passenger that their refund is on its way
+func (s *RefundPayoutServiceImpl) DeliverRefundPayoutSent(ctx context.Context, event *outbox.Event) error {
+	var payload model.RefundPayoutSentEvent
+	if err := event.Decode(&payload); err != nil {
+		return outbox.Permanent(err)
+	}
+
+	passenger, err := s.userClient.GetUserByID(ctx, payload.UserID)
+	if err != nil {
+		return err
+	}
+	if passenger.Email == "" {
+		log.Info().Str("payout_id", payload.PayoutID.String()).Msg("Passenger has no email, refund sent notification skipped")
+		return nil
+	}
+
+	return s.notificationClient.SendRefundSent(ctx, &notification.RefundSentRequest{
+		Email:           passenger.Email,
+		Name:            passenger.FullName,
+		RefundAmount:    payload.Amount,
+		BankName:        s.getBankName(ctx, payload.BankCode),
+		AccountNumber:   maskAccountNumber(payload.AccountNumber),
+		TransferContent: payload.TransferContent,
+	})
+}
+
+func (s *RefundPayoutServiceImpl) toBatchResponse(batch *model.RefundPayoutBatch) *model.RefundPayoutBatchResponse {
+	counts := make(map[model.RefundPayoutStatus]int)
+	for _, payout := range batch.Payouts {
+		counts[payout.Status]++
+	}
+	return &model.RefundPayoutBatchResponse{
+		RefundPayoutBatch: batch,
+		StatusCounts:      counts,
+	}
+}
+
+func (s *RefundPayoutServiceImpl) getBankName(ctx context.Context, bankCode string) string {
+	banks, err := s.constantsService.GetBanks(ctx)
+	if err != nil {
+		return bankCode
+	}
+	for _, bank := range banks {
+		if bank.Code == bankCode {
+			return bank.ShortName
+		}
+	}
+	return bankCode
+}
+
+// refundTransferContent is the transfer description the passenger sees on their statement
+func refundTransferContent(refund *model.Refund) string {
+	return "HOAN TIEN " + strings.ToUpper(refund.BookingID.String()[:8])
+}
+
+// maskAccountNumber keeps the last four digits, enough for the passenger to recognise the account
+func maskAccountNumber(accountNumber string) string {
+	if len(accountNumber) <= 4 {
+		return accountNumber
+	}
+	return strings.Repeat("*", len(accountNumber)-4) + accountNumber[len(accountNumber)-4:]
+}
@@ -0,0 +1,305 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	client_mocks "bus-booking/payment-service/internal/client/mocks"
+	"bus-booking/payment-service/internal/model"
+	"bus-booking/payment-service/internal/model/notification"
+	"bus-booking/payment-service/internal/model/user"
+	repo_mocks "bus-booking/payment-service/internal/repository/mocks"
+	service_mocks "bus-booking/payment-service/internal/service/mocks"
+	"bus-booking/shared/ginext"
+	"bus-booking/shared/outbox"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+type refundPayoutMocks struct {
+	payoutRepo         *repo_mocks.MockRefundPayoutRepository
+	refundRepo         *repo_mocks.MockRefundRepository
+	bankAccountRepo    *repo_mocks.MockBankAccountRepository
+	constantsService   *service_mocks.MockConstantsService
+	userClient         *client_mocks.MockUserClient
+	notificationClient *client_mocks.MockNotificationClient
+}
+
+func newRefundPayoutService(ctrl *gomock.Controller) (RefundPayoutService, *refundPayoutMocks) {
+	m := &refundPayoutMocks{
+		payoutRepo:         repo_mocks.NewMockRefundPayoutRepository(ctrl),
+		refundRepo:         repo_mocks.NewMockRefundRepository(ctrl),
+		bankAccountRepo:    repo_mocks.NewMockBankAccountRepository(ctrl),
+		constantsService:   service_mocks.NewMockConstantsService(ctrl),
+		userClient:         client_mocks.NewMockUserClient(ctrl),
+		notificationClient: client_mocks.NewMockNotificationClient(ctrl),
+	}
+	service := NewRefundPayoutService(m.payoutRepo, m.refundRepo, m.bankAccountRepo, m.constantsService, m.userClient, m.notificationClient, 200)
+	return service, m
+}
+
+func newApprovedRefund(amount int) *model.Refund {
+	return &model.Refund{
+		BaseModel:     model.BaseModel{ID: uuid.New()},
+		BookingID:     uuid.New(),
+		TransactionID: uuid.New(),
+		UserID:        uuid.New(),
+		RefundAmount:  amount,
+		RefundStatus:  model.RefundStatusProcessing,
+		RefundReason:  "Trip cancelled",
+	}
+}
+
+func newPayout(status model.RefundPayoutStatus) *model.RefundPayout {
+	return &model.RefundPayout{
+		BaseModel:       model.BaseModel{ID: uuid.New()},
+		BatchID:         uuid.New(),
+		RefundID:        uuid.New(),
+		BookingID:       uuid.New(),
+		UserID:          uuid.New(),
+		Amount:          150000,
+		BankCode:        "VCB",
+		BankBIN:         "970436",
+		AccountNumber:   "0123456789",
+		AccountHolder:   "NGUYEN VAN A",
+		TransferContent: "HOAN TIEN ABCD1234",
+		Status:          status,
+	}
+}
+
+func TestCreateRefundPayoutBatch_SkipsUnpayableRefunds(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, m := newRefundPayoutService(ctrl)
+	ctx := context.Background()
+	adminID := uuid.New()
+
+	payable := newApprovedRefund(150000)
+	noAccount := newApprovedRefund(80000)
+	alreadyQueued := uuid.New()
+	req := &model.CreateRefundPayoutBatchRequest{RefundIDs: []uuid.UUID{payable.ID, noAccount.ID, alreadyQueued}}
+
+	m.payoutRepo.EXPECT().ListPayableRefunds(ctx, req.RefundIDs, 200).Return([]*model.Refund{payable, noAccount}, nil)
+	m.constantsService.EXPECT().GetBanks(ctx).Return(vietnameseBanks, nil)
+	m.bankAccountRepo.EXPECT().GetPrimaryBankAccount(ctx, payable.UserID).Return(&model.BankAccount{
+		UserID:        payable.UserID,
+		BankCode:      "VCB",
+		AccountNumber: "0123456789",
+		AccountHolder: "NGUYEN VAN A",
+		IsPrimary:     true,
+	}, nil)
+	m.bankAccountRepo.EXPECT().GetPrimaryBankAccount(ctx, noAccount.UserID).Return(nil, errors.New("record not found"))
+	m.payoutRepo.EXPECT().CreateBatch(ctx, gomock.Any()).Return(nil)
+
+	resp, err := service.CreateBatch(ctx, req, adminID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, adminID, resp.CreatedBy)
+	assert.Equal(t, 1, resp.PayoutCount)
+	assert.Equal(t, 150000, resp.TotalAmount)
+	assert.Equal(t, 1, resp.StatusCounts[model.RefundPayoutStatusQueued])
+
+	payout := resp.Payouts[0]
+	assert.Equal(t, resp.ID, payout.BatchID)
+	assert.Equal(t, payable.ID, payout.RefundID)
+	assert.Equal(t, "970436", payout.BankBIN)
+	assert.Equal(t, "HOAN TIEN "+strings.ToUpper(payable.BookingID.String()[:8]), payout.TransferContent)
+	assert.Contains(t, payout.QRPayload, "0010A000000727")
+
+	assert.Len(t, resp.Skipped, 2)
+	assert.Equal(t, alreadyQueued, resp.Skipped[0].RefundID)
+	assert.Equal(t, noAccount.ID, resp.Skipped[1].RefundID)
+}
+
+func TestCreateRefundPayoutBatch_NothingPayable(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, m := newRefundPayoutService(ctrl)
+	ctx := context.Background()
+
+	m.payoutRepo.EXPECT().ListPayableRefunds(ctx, gomock.Nil(), 200).Return(nil, nil)
+	m.constantsService.EXPECT().GetBanks(ctx).Return(vietnameseBanks, nil)
+
+	_, err := service.CreateBatch(ctx, &model.CreateRefundPayoutBatchRequest{}, uuid.New())
+
+	var apiErr *ginext.Error
+	assert.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusBadRequest, apiErr.Code)
+}
+
+func TestSendRefundPayoutBatch_EmitsSentEvents(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, m := newRefundPayoutService(ctrl)
+	ctx := context.Background()
+
+	queued := newPayout(model.RefundPayoutStatusQueued)
+	failed := newPayout(model.RefundPayoutStatusFailed)
+	batch := &model.RefundPayoutBatch{
+		BaseModel:   model.BaseModel{ID: queued.BatchID},
+		PayoutCount: 2,
+		Payouts:     []*model.RefundPayout{queued, failed},
+	}
+
+	m.payoutRepo.EXPECT().GetBatch(ctx, batch.ID).Return(batch, nil)
+	m.payoutRepo.EXPECT().
+		Transition(ctx, []*model.RefundPayout{queued}, model.RefundPayoutStatusQueued, gomock.Nil(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, payouts []*model.RefundPayout, from model.RefundPayoutStatus, refunds []*model.Refund, events ...*outbox.Event) (bool, error) {
+			assert.Equal(t, model.RefundPayoutStatusSent, payouts[0].Status)
+			assert.NotNil(t, payouts[0].SentAt)
+
+			assert.Len(t, events, 1)
+			assert.Equal(t, model.EventTypeRefundPayoutSent, events[0].EventType)
+			var payload model.RefundPayoutSentEvent
+			assert.NoError(t, events[0].Decode(&payload))
+			assert.Equal(t, queued.ID, payload.PayoutID)
+			assert.Equal(t, queued.UserID, payload.UserID)
+			assert.Equal(t, queued.Amount, payload.Amount)
+			return true, nil
+		})
+
+	resp, err := service.SendBatch(ctx, batch.ID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, resp.StatusCounts[model.RefundPayoutStatusSent])
+	assert.Equal(t, 1, resp.StatusCounts[model.RefundPayoutStatusFailed])
+}
+
+func TestSendRefundPayoutBatch_ConcurrentChange(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, m := newRefundPayoutService(ctrl)
+	ctx := context.Background()
+
+	queued := newPayout(model.RefundPayoutStatusQueued)
+	batch := &model.RefundPayoutBatch{
+		BaseModel: model.BaseModel{ID: queued.BatchID},
+		Payouts:   []*model.RefundPayout{queued},
+	}
+
+	m.payoutRepo.EXPECT().GetBatch(ctx, batch.ID).Return(batch, nil)
+	m.payoutRepo.EXPECT().Transition(ctx, gomock.Any(), model.RefundPayoutStatusQueued, gomock.Nil(), gomock.Any()).Return(false, nil)
+
+	_, err := service.SendBatch(ctx, batch.ID)
+
+	var apiErr *ginext.Error
+	assert.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusConflict, apiErr.Code)
+}
+
+func TestConfirmRefundPayout_CompletesRefund(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, m := newRefundPayoutService(ctrl)
+	ctx := context.Background()
+	adminID := uuid.New()
+
+	payout := newPayout(model.RefundPayoutStatusSent)
+	refund := newApprovedRefund(payout.Amount)
+	refund.ID = payout.RefundID
+
+	m.payoutRepo.EXPECT().GetByID(ctx, payout.ID).Return(payout, nil)
+	m.refundRepo.EXPECT().GetByID(ctx, refund.ID).Return(refund, nil)
+	m.payoutRepo.EXPECT().
+		Transition(ctx, []*model.RefundPayout{payout}, model.RefundPayoutStatusSent, []*model.Refund{refund}, gomock.Any()).
+		DoAndReturn(func(ctx context.Context, payouts []*model.RefundPayout, from model.RefundPayoutStatus, refunds []*model.Refund, events ...*outbox.Event) (bool, error) {
+			assert.Len(t, events, 2)
+			assert.Equal(t, model.EventTypeRefundCompleted, events[0].EventType)
+			assert.Equal(t, refund.ID, events[0].AggregateID)
+			assert.Equal(t, model.EventTypeInvoiceAdjustmentDue, events[1].EventType)
+			return true, nil
+		})
+
+	confirmed, err := service.ConfirmPayout(ctx, payout.ID, &model.ConfirmRefundPayoutRequest{BankReference: "FT24015123"}, adminID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, model.RefundPayoutStatusConfirmed, confirmed.Status)
+	assert.Equal(t, "FT24015123", *confirmed.BankReference)
+	assert.NotNil(t, confirmed.ConfirmedAt)
+	assert.Equal(t, model.RefundStatusCompleted, refund.RefundStatus)
+	assert.Equal(t, adminID, *refund.ProcessedBy)
+}
+
+func TestConfirmRefundPayout_NotSent(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, m := newRefundPayoutService(ctrl)
+	ctx := context.Background()
+
+	payout := newPayout(model.RefundPayoutStatusQueued)
+	m.payoutRepo.EXPECT().GetByID(ctx, payout.ID).Return(payout, nil)
+
+	_, err := service.ConfirmPayout(ctx, payout.ID, &model.ConfirmRefundPayoutRequest{BankReference: "FT24015123"}, uuid.New())
+
+	var apiErr *ginext.Error
+	assert.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusBadRequest, apiErr.Code)
+}
+
+func TestFailRefundPayout_KeepsRefundApproved(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, m := newRefundPayoutService(ctrl)
+	ctx := context.Background()
+
+	payout := newPayout(model.RefundPayoutStatusSent)
+	m.payoutRepo.EXPECT().GetByID(ctx, payout.ID).Return(payout, nil)
+	m.payoutRepo.EXPECT().Transition(ctx, []*model.RefundPayout{payout}, model.RefundPayoutStatusSent, gomock.Nil()).Return(true, nil)
+
+	failed, err := service.FailPayout(ctx, payout.ID, &model.FailRefundPayoutRequest{Reason: "Account closed"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, model.RefundPayoutStatusFailed, failed.Status)
+	assert.Equal(t, "Account closed", *failed.FailureReason)
+	assert.NotNil(t, failed.FailedAt)
+}
+
+func TestDeliverRefundPayoutSent_EmailsPassenger(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, m := newRefundPayoutService(ctrl)
+	ctx := context.Background()
+
+	payout := newPayout(model.RefundPayoutStatusSent)
+	event, err := outbox.NewEvent(model.AggregateTypeRefundPayout, payout.ID, model.EventTypeRefundPayoutSent, &model.RefundPayoutSentEvent{
+		PayoutID:        payout.ID,
+		RefundID:        payout.RefundID,
+		BookingID:       payout.BookingID,
+		UserID:          payout.UserID,
+		Amount:          payout.Amount,
+		BankCode:        payout.BankCode,
+		AccountNumber:   payout.AccountNumber,
+		TransferContent: payout.TransferContent,
+	})
+	assert.NoError(t, err)
+
+	m.userClient.EXPECT().GetUserByID(ctx, payout.UserID).Return(&user.User{
+		ID:       payout.UserID,
+		Email:    "passenger@example.com",
+		FullName: "Nguyen Van A",
+	}, nil)
+	m.constantsService.EXPECT().GetBanks(ctx).Return(vietnameseBanks, nil)
+	m.notificationClient.EXPECT().SendRefundSent(ctx, &notification.RefundSentRequest{
+		Email:           "passenger@example.com",
+		Name:            "Nguyen Van A",
+		RefundAmount:    payout.Amount,
+		BankName:        "Vietcombank",
+		AccountNumber:   "******6789",
+		TransferContent: payout.TransferContent,
+	}).Return(nil)
+
+	assert.NoError(t, service.DeliverRefundPayoutSent(ctx, event))
+}
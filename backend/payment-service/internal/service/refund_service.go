@@ -4,7 +4,10 @@ import (
 	"bus-booking/payment-service/internal/model"
 	"bus-booking/payment-service/internal/repository"
 	"bus-booking/shared/ginext"
+	"bus-booking/shared/outbox"
 	"context"
+	"errors"
+	"fmt"
 	"time"
 
 	"github.com/google/uuid"
@@ -13,10 +16,14 @@ import (
 
 type RefundService interface {
 	CreateRefund(ctx context.Context, req *model.RefundRequest, userID uuid.UUID) (*model.RefundResponse, error)
-	GetRefundByBookingID(ctx context.Context, bookingID uuid.UUID, userID uuid.UUID) (*model.RefundResponse, error)
+	ListRefundsByBookingID(ctx context.Context, bookingID uuid.UUID, userID uuid.UUID) ([]*model.RefundResponse, error)
+	CreateOperatorRefund(ctx context.Context, req *model.RefundRequest) (*model.RefundResponse, error)
+	GetBookingRefund(ctx context.Context, bookingID uuid.UUID) (*model.RefundResponse, error)
+	GetRefundStatus(ctx context.Context, refundID uuid.UUID) (*model.RefundResponse, error)
 	ListRefunds(ctx context.Context, query *model.RefundListQuery) ([]*model.RefundResponse, int64, error)
 	UpdateRefundStatus(ctx context.Context, transactionID uuid.UUID, status model.RefundStatus, adminID uuid.UUID) error
 	ExportRefundsToExcel(ctx context.Context, refundIDs []uuid.UUID) ([]byte, error)
+	RefundSurplus(ctx context.Context, event *outbox.Event) error
 }
 
 type RefundServiceImpl struct {
@@ -43,82 +50,359 @@ func NewRefundService(
 	}
 }
 
+// CreateRefund requests a refund of the passenger's own payment. A booking can be refunded several
+// times, seat by seat, as long as the refunds add up to no more than was paid. Refunds to the wallet
+// are credited right away, refunds to a bank account wait for an admin to pay them out.
 func (s *RefundServiceImpl) CreateRefund(ctx context.Context, req *model.RefundRequest, userID uuid.UUID) (*model.RefundResponse, error) {
-	// Get original payment transaction
-	originalTx, err := s.transactionRepo.GetByBookingID(ctx, req.BookingID)
+	payments, err := s.bookingPayments(ctx, req.BookingID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Verify user owns this transaction
+	if payments[0].UserID != userID {
+		return nil, ginext.NewForbiddenError("you don't own this transaction")
+	}
+
+	// Validate refund amount against what is left after earlier refunds
+	if refundable := payments.RefundableAmount(); req.RefundAmount > refundable {
+		return nil, ginext.NewBadRequestError(fmt.Sprintf("refund amount cannot exceed the refundable amount of %d", refundable))
+	}
+
+	destination, err := s.refundDestination(ctx, payments, req)
+	if err != nil {
+		return nil, err
+	}
+
+	cash, wallet := req.RefundAmount, 0
+	if destination == model.RefundDestinationWallet {
+		cash, wallet = 0, req.RefundAmount
+	}
+
+	refund, err := s.createRefunds(ctx, req, nil, payments.SplitRefund(cash, wallet))
+	if err != nil {
+		return nil, err
+	}
+
+	return s.toRefundResponse(refund), nil
+}
+
+// bookingPayments loads the paid payment of a booking with the fare differences paid on top of it
+func (s *RefundServiceImpl) bookingPayments(ctx context.Context, bookingID uuid.UUID) (model.BookingPayments, error) {
+	originalTx, err := s.transactionRepo.GetByBookingID(ctx, bookingID)
 	if err != nil {
 		log.Error().Err(err).Msg("Failed to get original transaction")
 		return nil, ginext.NewNotFoundError("original transaction not found")
 	}
 
-	// Verify it's a paid transaction
 	if originalTx.Status != model.TransactionStatusPaid {
 		return nil, ginext.NewBadRequestError("cannot refund unpaid transaction")
 	}
 
-	// Verify user owns this transaction
-	if originalTx.UserID != userID {
-		return nil, ginext.NewForbiddenError("you don't own this transaction")
+	differences, err := s.transactionRepo.ListExchangeDifferences(ctx, originalTx.ID)
+	if err != nil {
+		log.Error().Err(err).Str("transaction_id", originalTx.ID.String()).Msg("Failed to get exchange differences")
+		return nil, ginext.NewInternalServerError("failed to get payments of the booking")
+	}
+
+	return append(model.BookingPayments{originalTx}, differences...), nil
+}
+
+// refundDestination checks where the refund asked for can go. Without a choice it goes to the primary
+// bank account when the passenger has one and it can be paid out there, to the wallet otherwise.
+func (s *RefundServiceImpl) refundDestination(ctx context.Context, payments model.BookingPayments, req *model.RefundRequest) (model.RefundDestination, error) {
+	if req.Destination == model.RefundDestinationWallet {
+		return model.RefundDestinationWallet, nil
+	}
+
+	_, err := s.bankAccountRepo.GetPrimaryBankAccount(ctx, payments[0].UserID)
+	hasBankAccount := err == nil
+	cashRefundable := payments.CashRefundableAmount()
+
+	if req.Destination == "" {
+		if hasBankAccount && req.RefundAmount <= cashRefundable {
+			return model.RefundDestinationBank, nil
+		}
+		return model.RefundDestinationWallet, nil
+	}
+
+	if !hasBankAccount {
+		return "", ginext.NewBadRequestError("you must add a bank account before requesting refund")
+	}
+	if req.RefundAmount > cashRefundable {
+		return "", ginext.NewBadRequestError(fmt.Sprintf("only %d can be refunded to a bank account, what was paid from the wallet goes back to the wallet", cashRefundable))
+	}
+	return model.RefundDestinationBank, nil
+}
+
+// ListRefundsByBookingID returns every refund of the passenger's booking, newest first, each with the
+// parts split off it folded in
+func (s *RefundServiceImpl) ListRefundsByBookingID(ctx context.Context, bookingID uuid.UUID, userID uuid.UUID) ([]*model.RefundResponse, error) {
+	refunds, err := s.refundRepo.ListByBookingID(ctx, bookingID)
+	if err != nil {
+		log.Error().Err(err).Str("booking_id", bookingID.String()).Msg("Failed to list refunds of booking")
+		return nil, ginext.NewInternalServerError("failed to get refunds")
+	}
+
+	responses := make([]*model.RefundResponse, len(refunds))
+	for i, refund := range refunds {
+		// Verify user owns this refund
+		if refund.UserID != userID {
+			return nil, ginext.NewForbiddenError("you don't own this refund")
+		}
+
+		responses[i], err = s.summarizeRefund(ctx, refund)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return responses, nil
+}
+
+// CreateOperatorRefund refunds a booking the operator cancelled, on behalf of the passenger who paid.
+// Unlike CreateRefund it does not require a bank account up front: the refund stays pending until
+// the passenger adds one and an admin pays it out. What the payment drew from the wallet is credited
+// back to the wallet right away. It pays back at most what is left after earlier refunds, and conflicts
+// once nothing is left. A request retried with the same ID returns the refund it created, adding only
+// the parts a crash left out, so retries do not refund twice.
+func (s *RefundServiceImpl) CreateOperatorRefund(ctx context.Context, req *model.RefundRequest) (*model.RefundResponse, error) {
+	existing, requested, err := s.requestedRefund(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	payments, err := s.bookingPayments(ctx, req.BookingID)
+	if err != nil {
+		return nil, err
+	}
+
+	amount := min(req.RefundAmount-requested, payments.RefundableAmount())
+	if amount <= 0 {
+		if existing != nil {
+			return s.toRefundResponse(existing), nil
+		}
+		return nil, ginext.NewConflictError("refund already exists for the full amount of this booking")
+	}
+
+	cash := min(amount, payments.CashRefundableAmount())
+	refund, err := s.createRefunds(ctx, req, existing, payments.SplitRefund(cash, amount-cash))
+	if err != nil {
+		return nil, err
+	}
+
+	return s.toRefundResponse(refund), nil
+}
+
+// requestedRefund looks up the refund an earlier delivery of the request created, with the amount it
+// and its parts already pay back
+func (s *RefundServiceImpl) requestedRefund(ctx context.Context, req *model.RefundRequest) (*model.Refund, int, error) {
+	if req.ID == uuid.Nil {
+		return nil, 0, nil
+	}
+
+	refund, err := s.refundRepo.GetByID(ctx, req.ID)
+	if err != nil {
+		return nil, 0, nil
+	}
+	if refund.BookingID != req.BookingID {
+		return nil, 0, ginext.NewConflictError("refund ID already used for another booking")
 	}
 
-	// Check if refund already exists for this booking
-	existingRefund, err := s.refundRepo.GetByBookingID(ctx, req.BookingID)
-	if err == nil && existingRefund != nil {
-		return nil, ginext.NewConflictError("refund already exists for this booking")
+	parts, err := s.refundRepo.ListByParentID(ctx, refund.ID)
+	if err != nil {
+		log.Error().Err(err).Str("refund_id", refund.ID.String()).Msg("Failed to list refund parts")
+		return nil, 0, ginext.NewInternalServerError("failed to create refund")
+	}
+
+	requested := refund.RefundAmount
+	for _, part := range parts {
+		requested += part.RefundAmount
+	}
+	return refund, requested, nil
+}
+
+// createRefunds records a refund split over the payments of a booking, one refund per part. The first
+// part, a bank refund whenever there is one, pays back the seats and is returned; the other parts come
+// on top of it and point at it, so GetRefundStatus follows the refund as a whole. When first is given
+// every part comes on top of it.
+func (s *RefundServiceImpl) createRefunds(ctx context.Context, req *model.RefundRequest, first *model.Refund, parts []model.RefundPart) (*model.Refund, error) {
+	for _, part := range parts {
+		partReq := req
+		if first != nil {
+			partReq = &model.RefundRequest{BookingID: req.BookingID, Reason: req.Reason, ParentRefundID: &first.ID}
+		}
+
+		refund, err := s.createRefund(ctx, part.Payment, partReq, part.Amount, part.Destination)
+		if err != nil {
+			return nil, err
+		}
+		if first == nil {
+			first = refund
+		}
+	}
+
+	if first == nil {
+		return nil, ginext.NewConflictError("nothing left to refund for this booking")
+	}
+	return first, nil
+}
+
+// RefundSurplus is the outbox handler refunding money received that no booking keeps, the excess of an
+// overpaid transfer or a partial payment whose booking expired. Like operator refunds it waits for the
+// passenger's bank account instead of requiring one.
+func (s *RefundServiceImpl) RefundSurplus(ctx context.Context, event *outbox.Event) error {
+	var payload model.SurplusRefundDueEvent
+	if err := event.Decode(&payload); err != nil {
+		return outbox.Permanent(err)
+	}
+
+	// A redelivered event finds the refund it created
+	if _, err := s.refundRepo.GetByID(ctx, payload.RefundID); err == nil {
+		return nil
 	}
 
-	// Check if user has a primary bank account
-	_, err = s.bankAccountRepo.GetPrimaryBankAccount(ctx, userID)
+	transaction, err := s.transactionRepo.GetByID(ctx, payload.TransactionID)
 	if err != nil {
-		return nil, ginext.NewBadRequestError("you must add a bank account before requesting refund")
+		return err
 	}
 
-	// Validate refund amount
-	if req.RefundAmount > originalTx.Amount {
-		return nil, ginext.NewBadRequestError("refund amount cannot exceed original amount")
+	// Money received from the provider goes back to a bank account
+	amount := min(payload.Amount, transaction.CashRefundableAmount())
+	if amount <= 0 {
+		log.Warn().
+			Str("transaction_id", transaction.ID.String()).
+			Int("surplus", payload.Amount).
+			Msg("Surplus already refunded, nothing left to refund")
+		return nil
 	}
 
-	// Create refund entity
+	_, err = s.createRefund(ctx, transaction, &model.RefundRequest{
+		ID:        payload.RefundID,
+		BookingID: transaction.BookingID,
+		Reason:    payload.Reason,
+	}, amount, model.RefundDestinationBank)
+	return err
+}
+
+// createRefund records a refund of the payment with its OUT ledger entry. A bank refund is pending until
+// it is paid out, a wallet refund is credited and completed at once.
+func (s *RefundServiceImpl) createRefund(ctx context.Context, originalTx *model.Transaction, req *model.RefundRequest, amount int, destination model.RefundDestination) (*model.Refund, error) {
 	refund := &model.Refund{
-		BookingID:     req.BookingID,
-		TransactionID: originalTx.ID,
-		UserID:        userID,
-		RefundAmount:  req.RefundAmount,
-		RefundStatus:  model.RefundStatusPending,
-		RefundReason:  req.Reason,
+		BaseModel:      model.BaseModel{ID: req.ID},
+		BookingID:      req.BookingID,
+		TransactionID:  originalTx.ID,
+		UserID:         originalTx.UserID,
+		RefundAmount:   amount,
+		RefundStatus:   model.RefundStatusPending,
+		RefundReason:   req.Reason,
+		Destination:    destination,
+		ParentRefundID: req.ParentRefundID,
+	}
+	for _, seatID := range req.BookingSeatIDs {
+		refund.Seats = append(refund.Seats, model.RefundSeat{BookingSeatID: seatID})
+	}
+
+	// The wallet refund needs its ID for the events queued with it
+	if refund.ID == uuid.Nil {
+		refund.ID = uuid.New()
+	}
+
+	entryStatus := model.TransactionStatusPending
+	paymentMethod := originalTx.PaymentMethod
+	var events []*outbox.Event
+	if destination == model.RefundDestinationWallet {
+		now := time.Now()
+		refund.RefundStatus = model.RefundStatusCompleted
+		refund.ProcessedAt = &now
+		entryStatus = model.TransactionStatusPaid
+		paymentMethod = model.PaymentMethodWallet
+
+		var err error
+		events, err = newRefundPaidBackEvents(refund)
+		if err != nil {
+			return nil, ginext.NewInternalServerError(err.Error())
+		}
 	}
 
-	if err := s.refundRepo.Create(ctx, refund); err != nil {
-		log.Error().Err(err).Msg("Failed to create refund")
+	refundAmount := amount
+	refundStatus := refund.RefundStatus
+	entry := &model.Transaction{
+		BookingID:       originalTx.BookingID,
+		UserID:          originalTx.UserID,
+		Amount:          amount,
+		Currency:        originalTx.Currency,
+		PaymentMethod:   paymentMethod,
+		OperatorID:      originalTx.OperatorID,
+		Status:          entryStatus,
+		TransactionType: model.TransactionTypeOut,
+		RefundStatus:    &refundStatus,
+		RefundAmount:    &refundAmount,
+	}
+
+	err := s.refundRepo.Create(ctx, refund, entry, events...)
+	switch {
+	case errors.Is(err, repository.ErrSeatAlreadyRefunded):
+		return nil, ginext.NewConflictError("refund already exists for one of these seats")
+	case errors.Is(err, repository.ErrRefundExceedsPayment):
+		// another refund of the booking was created in the meantime
+		return nil, ginext.NewConflictError("refund amount exceeds the refundable amount, another refund was just created")
+	case err != nil:
+		log.Error().Err(err).Str("booking_id", req.BookingID.String()).Msg("Failed to create refund")
 		return nil, ginext.NewInternalServerError("failed to create refund")
 	}
 
-	return &model.RefundResponse{
-		ID:                    refund.ID,
-		CreatedAt:             refund.CreatedAt,
-		UpdatedAt:             refund.UpdatedAt,
-		BookingID:             refund.BookingID,
-		UserID:                refund.UserID,
-		RefundAmount:          refund.RefundAmount,
-		RefundStatus:          refund.RefundStatus,
-		RefundReason:          refund.RefundReason,
-		OriginalTransactionID: refund.TransactionID, // For compatibility
-	}, nil
+	log.Info().
+		Str("booking_id", refund.BookingID.String()).
+		Str("refund_id", refund.ID.String()).
+		Int("refund_amount", refund.RefundAmount).
+		Str("destination", string(refund.Destination)).
+		Int("seats", len(refund.Seats)).
+		Msg("Refund created")
+
+	return refund, nil
 }
 
-func (s *RefundServiceImpl) GetRefundByBookingID(ctx context.Context, bookingID uuid.UUID, userID uuid.UUID) (*model.RefundResponse, error) {
+// GetBookingRefund returns the latest refund of a booking without an ownership check, for other services.
+// Like GetRefundStatus it reports the refund together with the parts split off it.
+func (s *RefundServiceImpl) GetBookingRefund(ctx context.Context, bookingID uuid.UUID) (*model.RefundResponse, error) {
 	refund, err := s.refundRepo.GetByBookingID(ctx, bookingID)
 	if err != nil {
 		log.Error().Err(err).Msg("Refund not found")
 		return nil, ginext.NewNotFoundError("refund not found for this booking")
 	}
 
-	// Verify user owns this refund
-	if refund.UserID != userID {
-		return nil, ginext.NewForbiddenError("you don't own this refund")
+	return s.summarizeRefund(ctx, refund)
+}
+
+// GetRefundStatus returns a refund together with the parts split off it, for other services waiting
+// on the refund they requested: the amount is what the parts pay back and the status is COMPLETED only
+// once every part was paid back
+func (s *RefundServiceImpl) GetRefundStatus(ctx context.Context, refundID uuid.UUID) (*model.RefundResponse, error) {
+	refund, err := s.refundRepo.GetByID(ctx, refundID)
+	if err != nil {
+		log.Error().Err(err).Str("refund_id", refundID.String()).Msg("Refund not found")
+		return nil, ginext.NewNotFoundError("refund not found")
 	}
 
-	return s.toRefundResponse(refund), nil
+	return s.summarizeRefund(ctx, refund)
+}
+
+// summarizeRefund folds the parts split off a refund into its response: the amount they pay back
+// together and their combined status
+func (s *RefundServiceImpl) summarizeRefund(ctx context.Context, refund *model.Refund) (*model.RefundResponse, error) {
+	parts, err := s.refundRepo.ListByParentID(ctx, refund.ID)
+	if err != nil {
+		log.Error().Err(err).Str("refund_id", refund.ID.String()).Msg("Failed to list refund parts")
+		return nil, ginext.NewInternalServerError("failed to get refund status")
+	}
+
+	response := s.toRefundResponse(refund)
+	refunds := append([]*model.Refund{refund}, parts...)
+	response.RefundStatus = model.SummarizeRefunds(refunds)
+	for _, part := range parts {
+		response.RefundAmount += part.RefundAmount
+	}
+	return response, nil
 }
 
 func (s *RefundServiceImpl) ListRefunds(ctx context.Context, query *model.RefundListQuery) ([]*model.RefundResponse, int64, error) {
@@ -132,6 +416,10 @@ func (s *RefundServiceImpl) ListRefunds(ctx context.Context, query *model.Refund
 		response := s.toRefundResponse(refund)
 
 		// Populate bank account info for admin view
+		if refund.Destination == model.RefundDestinationWallet {
+			responses[i] = response
+			continue
+		}
 		bankAccount, err := s.bankAccountRepo.GetPrimaryBankAccount(ctx, refund.UserID)
 		if err == nil && bankAccount != nil {
 			response.BankCode = bankAccount.BankCode
@@ -161,20 +449,52 @@ func (s *RefundServiceImpl) UpdateRefundStatus(ctx context.Context, refundID uui
 		return ginext.NewNotFoundError("refund not found")
 	}
 
+	// Completed refunds were paid out and rejected ones released their seats and amount
+	if refund.IsFinal() {
+		return ginext.NewBadRequestError(fmt.Sprintf("refund is already %s", refund.RefundStatus))
+	}
+
 	// Update fields
 	refund.RefundStatus = status
 	refund.ProcessedBy = &adminID
 	now := time.Now()
 	refund.ProcessedAt = &now
 
-	if err := s.refundRepo.Update(ctx, refund); err != nil {
+	// A completed refund left the provider balance, it is posted to the ledger and adjusts the invoice
+	var events []*outbox.Event
+	if status == model.RefundStatusCompleted {
+		events, err = newRefundPaidBackEvents(refund)
+		if err != nil {
+			return ginext.NewInternalServerError(err.Error())
+		}
+	}
+
+	updated, err := s.refundRepo.UpdateStatus(ctx, refund, events...)
+	if err != nil {
 		log.Error().Err(err).Msg("Failed to update refund status")
 		return ginext.NewInternalServerError("failed to update refund status")
 	}
+	if !updated {
+		return ginext.NewConflictError("refund was changed by someone else")
+	}
 
 	return nil
 }
 
+// newRefundPaidBackEvents queues what follows a completed refund: its ledger posting and the
+// adjustment of the invoice of its payment
+func newRefundPaidBackEvents(refund *model.Refund) ([]*outbox.Event, error) {
+	completed, err := newRefundCompletedEvent(refund)
+	if err != nil {
+		return nil, err
+	}
+	invoice, err := newInvoiceAdjustmentEvent(refund)
+	if err != nil {
+		return nil, err
+	}
+	return []*outbox.Event{completed, invoice}, nil
+}
+
 func (s *RefundServiceImpl) ExportRefundsToExcel(ctx context.Context, refundIDs []uuid.UUID) ([]byte, error) {
 	// Get refunds by IDs
 	refunds, err := s.refundRepo.ListByIDs(ctx, refundIDs)
@@ -190,6 +510,11 @@ func (s *RefundServiceImpl) ExportRefundsToExcel(ctx context.Context, refundIDs
 	// Get user bank accounts for each refund
 	exportItems := make([]*model.RefundExportItem, 0, len(refunds))
 	for _, refund := range refunds {
+		// Wallet refunds were credited, there is nothing to pay out
+		if refund.Destination == model.RefundDestinationWallet {
+			continue
+		}
+
 		// Get user's primary bank account
 		bankAccount, err := s.bankAccountRepo.GetPrimaryBankAccount(ctx, refund.UserID)
 		if err != nil {
@@ -242,7 +567,9 @@ func (s *RefundServiceImpl) toRefundResponse(refund *model.Refund) *model.Refund
 		RefundAmount:          refund.RefundAmount,
 		RefundStatus:          refund.RefundStatus,
 		RefundReason:          refund.RefundReason,
+		Destination:           refund.Destination,
 		OriginalTransactionID: refund.TransactionID,
+		BookingSeatIDs:        refund.BookingSeatIDs(),
 		ProcessedBy:           refund.ProcessedBy,
 		ProcessedAt:           refund.ProcessedAt,
 	}
@@ -2,11 +2,15 @@ package service
 
 import (
 	"context"
+	"net/http"
 	"testing"
 
 	"bus-booking/payment-service/internal/model"
+	"bus-booking/payment-service/internal/repository"
 	repo_mocks "bus-booking/payment-service/internal/repository/mocks"
 	service_mocks "bus-booking/payment-service/internal/service/mocks"
+	"bus-booking/shared/ginext"
+	"bus-booking/shared/outbox"
 
 	"github.com/golang/mock/gomock"
 	"github.com/google/uuid"
@@ -87,10 +91,9 @@ func TestCreateRefund_Success(t *testing.T) {
 		Return(transaction, nil).
 		Times(1)
 
-	// Mock refund existence check
-	mockRefundRepo.EXPECT().
-		GetByBookingID(ctx, bookingID).
-		Return(nil, assert.AnError). // No existing refund
+	mockTransactionRepo.EXPECT().
+		ListExchangeDifferences(ctx, transaction.ID).
+		Return(nil, nil).
 		Times(1)
 
 	// Mock bank account check
@@ -101,13 +104,18 @@ func TestCreateRefund_Success(t *testing.T) {
 
 	// Mock refund creation
 	mockRefundRepo.EXPECT().
-		Create(ctx, gomock.Any()).
-		DoAndReturn(func(ctx context.Context, refund *model.Refund) error {
+		Create(ctx, gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, refund *model.Refund, entry *model.Transaction, events ...*outbox.Event) error {
 			assert.Equal(t, bookingID, refund.BookingID)
 			assert.Equal(t, userID, refund.UserID)
 			assert.Equal(t, req.RefundAmount, refund.RefundAmount)
 			assert.Equal(t, req.Reason, refund.RefundReason)
 			assert.Equal(t, model.RefundStatusPending, refund.RefundStatus)
+
+			// The refund is recorded as money going out
+			assert.Equal(t, model.TransactionTypeOut, entry.TransactionType)
+			assert.Equal(t, req.RefundAmount, entry.Amount)
+			assert.Equal(t, model.RefundStatusPending, *entry.RefundStatus)
 			return nil
 		}).
 		Times(1)
@@ -210,6 +218,11 @@ func TestCreateRefund_NotOwner(t *testing.T) {
 		Return(transaction, nil).
 		Times(1)
 
+	mockTransactionRepo.EXPECT().
+		ListExchangeDifferences(ctx, transaction.ID).
+		Return(nil, nil).
+		Times(1)
+
 	result, err := service.CreateRefund(ctx, req, userID)
 
 	assert.Error(t, err)
@@ -217,7 +230,7 @@ func TestCreateRefund_NotOwner(t *testing.T) {
 	assert.Contains(t, err.Error(), "own")
 }
 
-func TestCreateRefund_AlreadyExists(t *testing.T) {
+func TestCreateRefund_SeatAlreadyRefunded(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
@@ -238,25 +251,22 @@ func TestCreateRefund_AlreadyExists(t *testing.T) {
 	ctx := context.Background()
 	userID := uuid.New()
 	bookingID := uuid.New()
+	seatID := uuid.New()
 
 	req := &model.RefundRequest{
-		BookingID:    bookingID,
-		Reason:       "Test",
-		RefundAmount: 100000,
+		BookingID:      bookingID,
+		Reason:         "Test",
+		RefundAmount:   50000,
+		BookingSeatIDs: []uuid.UUID{seatID},
 	}
 
 	transaction := &model.Transaction{
-		BaseModel: model.BaseModel{ID: uuid.New()},
-		BookingID: bookingID,
-		UserID:    userID,
-		Amount:    100000,
-		Status:    model.TransactionStatusPaid,
-	}
-
-	existingRefund := &model.Refund{
-		BaseModel:    model.BaseModel{ID: uuid.New()},
-		BookingID:    bookingID,
-		RefundStatus: model.RefundStatusPending,
+		BaseModel:      model.BaseModel{ID: uuid.New()},
+		BookingID:      bookingID,
+		UserID:         userID,
+		Amount:         150000,
+		RefundedAmount: 50000,
+		Status:         model.TransactionStatusPaid,
 	}
 
 	mockTransactionRepo.EXPECT().
@@ -264,9 +274,22 @@ func TestCreateRefund_AlreadyExists(t *testing.T) {
 		Return(transaction, nil).
 		Times(1)
 
+	mockTransactionRepo.EXPECT().
+		ListExchangeDifferences(ctx, transaction.ID).
+		Return(nil, nil).
+		Times(1)
+
+	mockBankAccountRepo.EXPECT().
+		GetPrimaryBankAccount(ctx, userID).
+		Return(&model.BankAccount{UserID: userID, IsPrimary: true}, nil).
+		Times(1)
+
 	mockRefundRepo.EXPECT().
-		GetByBookingID(ctx, bookingID).
-		Return(existingRefund, nil). // Refund exists
+		Create(ctx, gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, refund *model.Refund, entry *model.Transaction, events ...*outbox.Event) error {
+			assert.Equal(t, []uuid.UUID{seatID}, refund.BookingSeatIDs())
+			return repository.ErrSeatAlreadyRefunded
+		}).
 		Times(1)
 
 	result, err := service.CreateRefund(ctx, req, userID)
@@ -276,7 +299,60 @@ func TestCreateRefund_AlreadyExists(t *testing.T) {
 	assert.Contains(t, err.Error(), "already exists")
 }
 
-func TestCreateRefund_NoBankAccount(t *testing.T) {
+func TestCreateRefund_ExceedsRefundableAmount(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRefundRepo := repo_mocks.NewMockRefundRepository(ctrl)
+	mockTransactionRepo := repo_mocks.NewMockTransactionRepository(ctrl)
+	mockBankAccountRepo := repo_mocks.NewMockBankAccountRepository(ctrl)
+	mockConstantsService := service_mocks.NewMockConstantsService(ctrl)
+	mockExcelService := service_mocks.NewMockExcelService(ctrl)
+
+	service := NewRefundService(
+		mockRefundRepo,
+		mockTransactionRepo,
+		mockBankAccountRepo,
+		mockConstantsService,
+		mockExcelService,
+	)
+
+	ctx := context.Background()
+	userID := uuid.New()
+	bookingID := uuid.New()
+
+	// Two of three seats were already refunded
+	transaction := &model.Transaction{
+		BaseModel:      model.BaseModel{ID: uuid.New()},
+		BookingID:      bookingID,
+		UserID:         userID,
+		Amount:         150000,
+		RefundedAmount: 100000,
+		Status:         model.TransactionStatusPaid,
+	}
+
+	mockTransactionRepo.EXPECT().
+		GetByBookingID(ctx, bookingID).
+		Return(transaction, nil).
+		Times(1)
+
+	mockTransactionRepo.EXPECT().
+		ListExchangeDifferences(ctx, transaction.ID).
+		Return(nil, nil).
+		Times(1)
+
+	result, err := service.CreateRefund(ctx, &model.RefundRequest{
+		BookingID:    bookingID,
+		Reason:       "Test",
+		RefundAmount: 100000,
+	}, userID)
+
+	assert.Error(t, err)
+	assert.Nil(t, result)
+	assert.Contains(t, err.Error(), "refundable amount of 50000")
+}
+
+func TestCreateRefund_BankDestinationWithoutBankAccount(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
@@ -302,6 +378,7 @@ func TestCreateRefund_NoBankAccount(t *testing.T) {
 		BookingID:    bookingID,
 		Reason:       "Test",
 		RefundAmount: 100000,
+		Destination:  model.RefundDestinationBank,
 	}
 
 	transaction := &model.Transaction{
@@ -317,9 +394,9 @@ func TestCreateRefund_NoBankAccount(t *testing.T) {
 		Return(transaction, nil).
 		Times(1)
 
-	mockRefundRepo.EXPECT().
-		GetByBookingID(ctx, bookingID).
-		Return(nil, assert.AnError).
+	mockTransactionRepo.EXPECT().
+		ListExchangeDifferences(ctx, transaction.ID).
+		Return(nil, nil).
 		Times(1)
 
 	// No bank account
@@ -335,7 +412,263 @@ func TestCreateRefund_NoBankAccount(t *testing.T) {
 	assert.Contains(t, err.Error(), "bank account")
 }
 
-func TestGetRefundByBookingID_Success(t *testing.T) {
+func TestCreateRefund_NoBankAccountCreditsWallet(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRefundRepo := repo_mocks.NewMockRefundRepository(ctrl)
+	mockTransactionRepo := repo_mocks.NewMockTransactionRepository(ctrl)
+	mockBankAccountRepo := repo_mocks.NewMockBankAccountRepository(ctrl)
+
+	service := NewRefundService(
+		mockRefundRepo,
+		mockTransactionRepo,
+		mockBankAccountRepo,
+		service_mocks.NewMockConstantsService(ctrl),
+		service_mocks.NewMockExcelService(ctrl),
+	)
+
+	ctx := context.Background()
+	userID := uuid.New()
+	bookingID := uuid.New()
+
+	transaction := &model.Transaction{
+		BaseModel:     model.BaseModel{ID: uuid.New()},
+		BookingID:     bookingID,
+		UserID:        userID,
+		Amount:        100000,
+		PaymentMethod: model.PaymentMethodPayOS,
+		Status:        model.TransactionStatusPaid,
+	}
+
+	mockTransactionRepo.EXPECT().
+		GetByBookingID(ctx, bookingID).
+		Return(transaction, nil).
+		Times(1)
+
+	mockTransactionRepo.EXPECT().
+		ListExchangeDifferences(ctx, transaction.ID).
+		Return(nil, nil).
+		Times(1)
+
+	mockBankAccountRepo.EXPECT().
+		GetPrimaryBankAccount(ctx, userID).
+		Return(nil, assert.AnError).
+		Times(1)
+
+	// The wallet is credited at once, the ledger and the invoice follow through the outbox
+	mockRefundRepo.EXPECT().
+		Create(ctx, gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, refund *model.Refund, entry *model.Transaction, events ...*outbox.Event) error {
+			assert.Equal(t, model.RefundDestinationWallet, refund.Destination)
+			assert.Equal(t, model.RefundStatusCompleted, refund.RefundStatus)
+			assert.NotNil(t, refund.ProcessedAt)
+			assert.NotEqual(t, uuid.Nil, refund.ID)
+
+			assert.Equal(t, model.TransactionStatusPaid, entry.Status)
+			assert.Equal(t, model.PaymentMethodWallet, entry.PaymentMethod)
+			assert.Equal(t, model.RefundStatusCompleted, *entry.RefundStatus)
+
+			assert.Equal(t, model.EventTypeRefundCompleted, events[0].EventType)
+			assert.Equal(t, model.EventTypeInvoiceAdjustmentDue, events[1].EventType)
+			return nil
+		}).
+		Times(1)
+
+	result, err := service.CreateRefund(ctx, &model.RefundRequest{
+		BookingID:    bookingID,
+		Reason:       "Không muốn đi nữa",
+		RefundAmount: 100000,
+	}, userID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, model.RefundDestinationWallet, result.Destination)
+	assert.Equal(t, model.RefundStatusCompleted, result.RefundStatus)
+}
+
+func TestCreateRefund_WalletPartCannotGoToBank(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTransactionRepo := repo_mocks.NewMockTransactionRepository(ctrl)
+	mockBankAccountRepo := repo_mocks.NewMockBankAccountRepository(ctrl)
+
+	service := NewRefundService(
+		repo_mocks.NewMockRefundRepository(ctrl),
+		mockTransactionRepo,
+		mockBankAccountRepo,
+		service_mocks.NewMockConstantsService(ctrl),
+		service_mocks.NewMockExcelService(ctrl),
+	)
+
+	ctx := context.Background()
+	userID := uuid.New()
+	bookingID := uuid.New()
+
+	// 60000 of the price was paid from the wallet
+	transaction := &model.Transaction{
+		BaseModel:    model.BaseModel{ID: uuid.New()},
+		BookingID:    bookingID,
+		UserID:       userID,
+		Amount:       100000,
+		AmountPaid:   100000,
+		WalletAmount: 60000,
+		Status:       model.TransactionStatusPaid,
+	}
+
+	mockTransactionRepo.EXPECT().
+		GetByBookingID(ctx, bookingID).
+		Return(transaction, nil).
+		Times(1)
+
+	mockTransactionRepo.EXPECT().
+		ListExchangeDifferences(ctx, transaction.ID).
+		Return(nil, nil).
+		Times(1)
+
+	mockBankAccountRepo.EXPECT().
+		GetPrimaryBankAccount(ctx, userID).
+		Return(&model.BankAccount{UserID: userID, IsPrimary: true}, nil).
+		Times(1)
+
+	result, err := service.CreateRefund(ctx, &model.RefundRequest{
+		BookingID:    bookingID,
+		Reason:       "Không muốn đi nữa",
+		RefundAmount: 100000,
+		Destination:  model.RefundDestinationBank,
+	}, userID)
+
+	assert.Error(t, err)
+	assert.Nil(t, result)
+	assert.Contains(t, err.Error(), "only 40000 can be refunded to a bank account")
+}
+
+func TestCreateRefund_CancelAfterExchangeDrawsOnFareDifference(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRefundRepo := repo_mocks.NewMockRefundRepository(ctrl)
+	mockTransactionRepo := repo_mocks.NewMockTransactionRepository(ctrl)
+	mockBankAccountRepo := repo_mocks.NewMockBankAccountRepository(ctrl)
+
+	service := NewRefundService(
+		mockRefundRepo,
+		mockTransactionRepo,
+		mockBankAccountRepo,
+		service_mocks.NewMockConstantsService(ctrl),
+		service_mocks.NewMockExcelService(ctrl),
+	)
+
+	ctx := context.Background()
+	userID := uuid.New()
+	bookingID := uuid.New()
+	seatID := uuid.New()
+
+	// The booking was exchanged for a dearer trip: 300000 paid first, 100000 on the exchange
+	original := &model.Transaction{
+		BaseModel: model.BaseModel{ID: uuid.New()},
+		BookingID: bookingID,
+		UserID:    userID,
+		Amount:    300000,
+		Status:    model.TransactionStatusPaid,
+	}
+	difference := &model.Transaction{
+		BaseModel:             model.BaseModel{ID: uuid.New()},
+		BookingID:             bookingID,
+		UserID:                userID,
+		Amount:                100000,
+		Status:                model.TransactionStatusPaid,
+		OriginalTransactionID: &original.ID,
+	}
+
+	mockTransactionRepo.EXPECT().GetByBookingID(ctx, bookingID).Return(original, nil)
+	mockTransactionRepo.EXPECT().ListExchangeDifferences(ctx, original.ID).Return([]*model.Transaction{difference}, nil)
+	mockBankAccountRepo.EXPECT().GetPrimaryBankAccount(ctx, userID).Return(&model.BankAccount{UserID: userID}, nil)
+
+	gomock.InOrder(
+		// The original payment is refunded first, with the seats
+		mockRefundRepo.EXPECT().
+			Create(ctx, gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, refund *model.Refund, entry *model.Transaction, events ...*outbox.Event) error {
+				assert.Equal(t, original.ID, refund.TransactionID)
+				assert.Equal(t, 300000, refund.RefundAmount)
+				assert.Equal(t, []uuid.UUID{seatID}, refund.BookingSeatIDs())
+				return nil
+			}),
+		mockRefundRepo.EXPECT().
+			Create(ctx, gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, refund *model.Refund, entry *model.Transaction, events ...*outbox.Event) error {
+				assert.Equal(t, difference.ID, refund.TransactionID)
+				assert.Equal(t, 60000, refund.RefundAmount)
+				assert.Equal(t, model.RefundDestinationBank, refund.Destination)
+				assert.Empty(t, refund.Seats)
+				return nil
+			}),
+	)
+
+	// 90% of the 400000 the exchanged booking cost
+	result, err := service.CreateRefund(ctx, &model.RefundRequest{
+		BookingID:      bookingID,
+		Reason:         "Hủy vé sau khi đổi chuyến",
+		RefundAmount:   360000,
+		BookingSeatIDs: []uuid.UUID{seatID},
+	}, userID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 300000, result.RefundAmount)
+}
+
+func TestCreateRefund_CancelAfterAbandonedExchange(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRefundRepo := repo_mocks.NewMockRefundRepository(ctrl)
+	mockTransactionRepo := repo_mocks.NewMockTransactionRepository(ctrl)
+	mockBankAccountRepo := repo_mocks.NewMockBankAccountRepository(ctrl)
+
+	service := NewRefundService(
+		mockRefundRepo,
+		mockTransactionRepo,
+		mockBankAccountRepo,
+		service_mocks.NewMockConstantsService(ctrl),
+		service_mocks.NewMockExcelService(ctrl),
+	)
+
+	ctx := context.Background()
+	userID := uuid.New()
+	bookingID := uuid.New()
+
+	// The fare difference of an exchange that expired unpaid is neither the payment nor drawn on
+	original := &model.Transaction{
+		BaseModel: model.BaseModel{ID: uuid.New()},
+		BookingID: bookingID,
+		UserID:    userID,
+		Amount:    300000,
+		Status:    model.TransactionStatusPaid,
+	}
+
+	mockTransactionRepo.EXPECT().GetByBookingID(ctx, bookingID).Return(original, nil)
+	mockTransactionRepo.EXPECT().ListExchangeDifferences(ctx, original.ID).Return(nil, nil)
+	mockBankAccountRepo.EXPECT().GetPrimaryBankAccount(ctx, userID).Return(&model.BankAccount{UserID: userID}, nil)
+	mockRefundRepo.EXPECT().
+		Create(ctx, gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, refund *model.Refund, entry *model.Transaction, events ...*outbox.Event) error {
+			assert.Equal(t, original.ID, refund.TransactionID)
+			assert.Equal(t, 270000, refund.RefundAmount)
+			return nil
+		})
+
+	result, err := service.CreateRefund(ctx, &model.RefundRequest{
+		BookingID:    bookingID,
+		Reason:       "Hủy vé sau khi đổi chuyến",
+		RefundAmount: 270000,
+	}, userID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 270000, result.RefundAmount)
+}
+
+func TestListRefundsByBookingID_Success(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
@@ -357,7 +690,8 @@ func TestGetRefundByBookingID_Success(t *testing.T) {
 	userID := uuid.New()
 	bookingID := uuid.New()
 
-	refund := &model.Refund{
+	// One seat was refunded first, then the operator compensated a delay
+	compensation := &model.Refund{
 		BaseModel:    model.BaseModel{ID: uuid.New()},
 		BookingID:    bookingID,
 		UserID:       userID,
@@ -365,21 +699,44 @@ func TestGetRefundByBookingID_Success(t *testing.T) {
 		RefundStatus: model.RefundStatusPending,
 		RefundReason: "Test",
 	}
+	seatRefund := &model.Refund{
+		BaseModel:    model.BaseModel{ID: uuid.New()},
+		BookingID:    bookingID,
+		UserID:       userID,
+		RefundAmount: 20000,
+		RefundStatus: model.RefundStatusCompleted,
+		Destination:  model.RefundDestinationWallet,
+	}
+	bankPart := &model.Refund{
+		BaseModel:      model.BaseModel{ID: uuid.New()},
+		BookingID:      bookingID,
+		UserID:         userID,
+		RefundAmount:   80000,
+		RefundStatus:   model.RefundStatusCompleted,
+		Destination:    model.RefundDestinationBank,
+		ParentRefundID: &seatRefund.ID,
+	}
 
 	mockRefundRepo.EXPECT().
-		GetByBookingID(ctx, bookingID).
-		Return(refund, nil).
+		ListByBookingID(ctx, bookingID).
+		Return([]*model.Refund{compensation, seatRefund}, nil).
 		Times(1)
+	mockRefundRepo.EXPECT().ListByParentID(ctx, compensation.ID).Return(nil, nil)
+	mockRefundRepo.EXPECT().ListByParentID(ctx, seatRefund.ID).Return([]*model.Refund{bankPart}, nil)
 
-	result, err := service.GetRefundByBookingID(ctx, bookingID, userID)
+	result, err := service.ListRefundsByBookingID(ctx, bookingID, userID)
 
 	assert.NoError(t, err)
-	assert.NotNil(t, result)
-	assert.Equal(t, bookingID, result.BookingID)
-	assert.Equal(t, model.RefundStatusPending, result.RefundStatus)
+	if assert.Len(t, result, 2) {
+		assert.Equal(t, compensation.ID, result[0].ID)
+		assert.Equal(t, model.RefundStatusPending, result[0].RefundStatus)
+		assert.Equal(t, seatRefund.ID, result[1].ID)
+		assert.Equal(t, 100000, result[1].RefundAmount)
+		assert.Equal(t, model.RefundStatusCompleted, result[1].RefundStatus)
+	}
 }
 
-func TestGetRefundByBookingID_NotOwner(t *testing.T) {
+func TestListRefundsByBookingID_NotOwner(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
@@ -411,11 +768,11 @@ func TestGetRefundByBookingID_NotOwner(t *testing.T) {
 	}
 
 	mockRefundRepo.EXPECT().
-		GetByBookingID(ctx, bookingID).
-		Return(refund, nil).
+		ListByBookingID(ctx, bookingID).
+		Return([]*model.Refund{refund}, nil).
 		Times(1)
 
-	result, err := service.GetRefundByBookingID(ctx, bookingID, userID)
+	result, err := service.ListRefundsByBookingID(ctx, bookingID, userID)
 
 	assert.Error(t, err)
 	assert.Nil(t, result)
@@ -455,13 +812,18 @@ func TestUpdateRefundStatus_Success(t *testing.T) {
 		Times(1)
 
 	mockRefundRepo.EXPECT().
-		Update(ctx, gomock.Any()).
-		DoAndReturn(func(ctx context.Context, r *model.Refund) error {
+		UpdateStatus(ctx, gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, r *model.Refund, events ...*outbox.Event) (bool, error) {
 			assert.Equal(t, model.RefundStatusCompleted, r.RefundStatus)
 			assert.NotNil(t, r.ProcessedBy)
 			assert.Equal(t, adminID, *r.ProcessedBy)
 			assert.NotNil(t, r.ProcessedAt)
-			return nil
+
+			// The completed refund is posted to the ledger and adjusts the invoice of its payment
+			assert.Len(t, events, 2)
+			assert.Equal(t, model.EventTypeRefundCompleted, events[0].EventType)
+			assert.Equal(t, model.EventTypeInvoiceAdjustmentDue, events[1].EventType)
+			return true, nil
 		}).
 		Times(1)
 
@@ -470,7 +832,7 @@ func TestUpdateRefundStatus_Success(t *testing.T) {
 	assert.NoError(t, err)
 }
 
-func TestUpdateRefundStatus_InvalidStatus(t *testing.T) {
+func TestUpdateRefundStatus_AlreadyRejected(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
@@ -490,15 +852,21 @@ func TestUpdateRefundStatus_InvalidStatus(t *testing.T) {
 
 	ctx := context.Background()
 	refundID := uuid.New()
-	adminID := uuid.New()
 
-	err := service.UpdateRefundStatus(ctx, refundID, model.RefundStatusPending, adminID)
+	// Reopening would refund seats and money that may have been refunded again since
+	mockRefundRepo.EXPECT().
+		GetByID(ctx, refundID).
+		Return(&model.Refund{BaseModel: model.BaseModel{ID: refundID}, RefundStatus: model.RefundStatusRejected}, nil).
+		Times(1)
 
-	assert.Error(t, err)
-	assert.Contains(t, err.Error(), "invalid")
+	err := service.UpdateRefundStatus(ctx, refundID, model.RefundStatusProcessing, uuid.New())
+
+	var apiErr *ginext.Error
+	assert.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusBadRequest, apiErr.Code)
 }
 
-func TestListRefunds_Success(t *testing.T) {
+func TestUpdateRefundStatus_InvalidStatus(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
@@ -517,14 +885,42 @@ func TestListRefunds_Success(t *testing.T) {
 	)
 
 	ctx := context.Background()
-	userID := uuid.New()
+	refundID := uuid.New()
+	adminID := uuid.New()
 
-	query := &model.RefundListQuery{
-		PaginationRequest: model.PaginationRequest{
-			Page:     1,
-			PageSize: 10,
-		},
-	}
+	err := service.UpdateRefundStatus(ctx, refundID, model.RefundStatusPending, adminID)
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid")
+}
+
+func TestListRefunds_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRefundRepo := repo_mocks.NewMockRefundRepository(ctrl)
+	mockTransactionRepo := repo_mocks.NewMockTransactionRepository(ctrl)
+	mockBankAccountRepo := repo_mocks.NewMockBankAccountRepository(ctrl)
+	mockConstantsService := service_mocks.NewMockConstantsService(ctrl)
+	mockExcelService := service_mocks.NewMockExcelService(ctrl)
+
+	service := NewRefundService(
+		mockRefundRepo,
+		mockTransactionRepo,
+		mockBankAccountRepo,
+		mockConstantsService,
+		mockExcelService,
+	)
+
+	ctx := context.Background()
+	userID := uuid.New()
+
+	query := &model.RefundListQuery{
+		PaginationRequest: model.PaginationRequest{
+			Page:     1,
+			PageSize: 10,
+		},
+	}
 
 	refunds := []*model.Refund{
 		{
@@ -766,3 +1162,749 @@ func TestExportRefundsToExcel_NoBankAccount(t *testing.T) {
 	assert.Nil(t, result)
 	assert.Contains(t, err.Error(), "no refunds with valid bank accounts")
 }
+
+func TestCreateOperatorRefund_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRefundRepo := repo_mocks.NewMockRefundRepository(ctrl)
+	mockTransactionRepo := repo_mocks.NewMockTransactionRepository(ctrl)
+	mockBankAccountRepo := repo_mocks.NewMockBankAccountRepository(ctrl)
+	mockConstantsService := service_mocks.NewMockConstantsService(ctrl)
+	mockExcelService := service_mocks.NewMockExcelService(ctrl)
+
+	service := NewRefundService(
+		mockRefundRepo,
+		mockTransactionRepo,
+		mockBankAccountRepo,
+		mockConstantsService,
+		mockExcelService,
+	)
+
+	ctx := context.Background()
+	ownerID := uuid.New()
+	bookingID := uuid.New()
+	transactionID := uuid.New()
+
+	req := &model.RefundRequest{
+		BookingID:    bookingID,
+		Reason:       "Trip Cancelled by Operator",
+		RefundAmount: 100000,
+	}
+
+	transaction := &model.Transaction{
+		BaseModel: model.BaseModel{ID: transactionID},
+		BookingID: bookingID,
+		UserID:    ownerID,
+		Amount:    100000,
+		Status:    model.TransactionStatusPaid,
+	}
+
+	mockTransactionRepo.EXPECT().
+		GetByBookingID(ctx, bookingID).
+		Return(transaction, nil).
+		Times(1)
+
+	mockTransactionRepo.EXPECT().
+		ListExchangeDifferences(ctx, transaction.ID).
+		Return(nil, nil).
+		Times(1)
+
+	// No bank account check: the passenger may add one after the trip is cancelled
+	mockRefundRepo.EXPECT().
+		Create(ctx, gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, refund *model.Refund, entry *model.Transaction, events ...*outbox.Event) error {
+			assert.Equal(t, ownerID, refund.UserID)
+			assert.Equal(t, transactionID, refund.TransactionID)
+			assert.Equal(t, req.RefundAmount, refund.RefundAmount)
+			assert.Equal(t, model.RefundStatusPending, refund.RefundStatus)
+			return nil
+		}).
+		Times(1)
+
+	result, err := service.CreateOperatorRefund(ctx, req)
+
+	assert.NoError(t, err)
+	assert.NotNil(t, result)
+	assert.Equal(t, ownerID, result.UserID)
+	assert.Equal(t, model.RefundStatusPending, result.RefundStatus)
+}
+
+func TestCreateOperatorRefund_AlreadyExists(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRefundRepo := repo_mocks.NewMockRefundRepository(ctrl)
+	mockTransactionRepo := repo_mocks.NewMockTransactionRepository(ctrl)
+	mockBankAccountRepo := repo_mocks.NewMockBankAccountRepository(ctrl)
+	mockConstantsService := service_mocks.NewMockConstantsService(ctrl)
+	mockExcelService := service_mocks.NewMockExcelService(ctrl)
+
+	service := NewRefundService(
+		mockRefundRepo,
+		mockTransactionRepo,
+		mockBankAccountRepo,
+		mockConstantsService,
+		mockExcelService,
+	)
+
+	ctx := context.Background()
+	bookingID := uuid.New()
+
+	// A retried operator refund finds the booking fully refunded
+	transaction := &model.Transaction{
+		BaseModel:      model.BaseModel{ID: uuid.New()},
+		BookingID:      bookingID,
+		UserID:         uuid.New(),
+		Amount:         100000,
+		RefundedAmount: 100000,
+		Status:         model.TransactionStatusPaid,
+	}
+
+	mockTransactionRepo.EXPECT().
+		GetByBookingID(ctx, bookingID).
+		Return(transaction, nil).
+		Times(1)
+
+	mockTransactionRepo.EXPECT().
+		ListExchangeDifferences(ctx, transaction.ID).
+		Return(nil, nil).
+		Times(1)
+
+	result, err := service.CreateOperatorRefund(ctx, &model.RefundRequest{
+		BookingID:    bookingID,
+		Reason:       "Trip Cancelled by Operator",
+		RefundAmount: 100000,
+	})
+
+	assert.Error(t, err)
+	assert.Nil(t, result)
+	assert.Contains(t, err.Error(), "already exists")
+}
+
+func TestCreateOperatorRefund_RefundsWhatIsLeft(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRefundRepo := repo_mocks.NewMockRefundRepository(ctrl)
+	mockTransactionRepo := repo_mocks.NewMockTransactionRepository(ctrl)
+	mockBankAccountRepo := repo_mocks.NewMockBankAccountRepository(ctrl)
+	mockConstantsService := service_mocks.NewMockConstantsService(ctrl)
+	mockExcelService := service_mocks.NewMockExcelService(ctrl)
+
+	service := NewRefundService(
+		mockRefundRepo,
+		mockTransactionRepo,
+		mockBankAccountRepo,
+		mockConstantsService,
+		mockExcelService,
+	)
+
+	ctx := context.Background()
+	bookingID := uuid.New()
+
+	// The passenger already cancelled one of three seats before the operator cancelled the trip
+	transaction := &model.Transaction{
+		BaseModel:      model.BaseModel{ID: uuid.New()},
+		BookingID:      bookingID,
+		UserID:         uuid.New(),
+		Amount:         150000,
+		RefundedAmount: 50000,
+		Status:         model.TransactionStatusPaid,
+	}
+
+	mockTransactionRepo.EXPECT().
+		GetByBookingID(ctx, bookingID).
+		Return(transaction, nil).
+		Times(1)
+
+	mockTransactionRepo.EXPECT().
+		ListExchangeDifferences(ctx, transaction.ID).
+		Return(nil, nil).
+		Times(1)
+
+	mockRefundRepo.EXPECT().
+		Create(ctx, gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, refund *model.Refund, entry *model.Transaction, events ...*outbox.Event) error {
+			assert.Equal(t, 100000, refund.RefundAmount)
+			assert.Equal(t, 100000, entry.Amount)
+			return nil
+		}).
+		Times(1)
+
+	result, err := service.CreateOperatorRefund(ctx, &model.RefundRequest{
+		BookingID:    bookingID,
+		Reason:       "Trip Cancelled by Operator",
+		RefundAmount: 150000,
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 100000, result.RefundAmount)
+}
+
+func TestCreateOperatorRefund_WalletPartBackToWallet(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRefundRepo := repo_mocks.NewMockRefundRepository(ctrl)
+	mockTransactionRepo := repo_mocks.NewMockTransactionRepository(ctrl)
+
+	service := NewRefundService(
+		mockRefundRepo,
+		mockTransactionRepo,
+		repo_mocks.NewMockBankAccountRepository(ctrl),
+		service_mocks.NewMockConstantsService(ctrl),
+		service_mocks.NewMockExcelService(ctrl),
+	)
+
+	ctx := context.Background()
+	bookingID := uuid.New()
+	seatID := uuid.New()
+
+	// 50000 of the price was paid from the wallet
+	transaction := &model.Transaction{
+		BaseModel:    model.BaseModel{ID: uuid.New()},
+		BookingID:    bookingID,
+		UserID:       uuid.New(),
+		Amount:       150000,
+		AmountPaid:   150000,
+		WalletAmount: 50000,
+		Status:       model.TransactionStatusPaid,
+	}
+
+	mockTransactionRepo.EXPECT().
+		GetByBookingID(ctx, bookingID).
+		Return(transaction, nil).
+		Times(1)
+
+	mockTransactionRepo.EXPECT().
+		ListExchangeDifferences(ctx, transaction.ID).
+		Return(nil, nil).
+		Times(1)
+
+	var bankRefundID uuid.UUID
+	gomock.InOrder(
+		// The transferred part waits for the bank account, with the seats
+		mockRefundRepo.EXPECT().
+			Create(ctx, gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, refund *model.Refund, entry *model.Transaction, events ...*outbox.Event) error {
+				assert.Equal(t, model.RefundDestinationBank, refund.Destination)
+				assert.Equal(t, model.RefundStatusPending, refund.RefundStatus)
+				assert.Equal(t, 100000, refund.RefundAmount)
+				assert.Equal(t, []uuid.UUID{seatID}, refund.BookingSeatIDs())
+				assert.Nil(t, refund.ParentRefundID)
+				bankRefundID = refund.ID
+				return nil
+			}),
+		// The wallet part is credited back at once, split off the bank refund
+		mockRefundRepo.EXPECT().
+			Create(ctx, gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, refund *model.Refund, entry *model.Transaction, events ...*outbox.Event) error {
+				assert.Equal(t, model.RefundDestinationWallet, refund.Destination)
+				assert.Equal(t, model.RefundStatusCompleted, refund.RefundStatus)
+				assert.Equal(t, 50000, refund.RefundAmount)
+				assert.Empty(t, refund.Seats)
+				if assert.NotNil(t, refund.ParentRefundID) {
+					assert.Equal(t, bankRefundID, *refund.ParentRefundID)
+				}
+				return nil
+			}),
+	)
+
+	result, err := service.CreateOperatorRefund(ctx, &model.RefundRequest{
+		BookingID:      bookingID,
+		Reason:         "Trip Cancelled by Operator",
+		RefundAmount:   150000,
+		BookingSeatIDs: []uuid.UUID{seatID},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 100000, result.RefundAmount)
+	assert.Equal(t, model.RefundDestinationBank, result.Destination)
+}
+
+func TestCreateOperatorRefund_RetryAfterCrashAddsMissingPart(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRefundRepo := repo_mocks.NewMockRefundRepository(ctrl)
+	mockTransactionRepo := repo_mocks.NewMockTransactionRepository(ctrl)
+
+	service := NewRefundService(
+		mockRefundRepo,
+		mockTransactionRepo,
+		repo_mocks.NewMockBankAccountRepository(ctrl),
+		service_mocks.NewMockConstantsService(ctrl),
+		service_mocks.NewMockExcelService(ctrl),
+	)
+
+	ctx := context.Background()
+	bookingID := uuid.New()
+	requestID := uuid.New()
+
+	// The bank part was created before a crash, the wallet part was not
+	transaction := &model.Transaction{
+		BaseModel:      model.BaseModel{ID: uuid.New()},
+		BookingID:      bookingID,
+		UserID:         uuid.New(),
+		Amount:         150000,
+		AmountPaid:     150000,
+		WalletAmount:   50000,
+		RefundedAmount: 100000,
+		Status:         model.TransactionStatusPaid,
+	}
+	bankRefund := &model.Refund{
+		BaseModel:     model.BaseModel{ID: requestID},
+		BookingID:     bookingID,
+		TransactionID: transaction.ID,
+		RefundAmount:  100000,
+		RefundStatus:  model.RefundStatusPending,
+		Destination:   model.RefundDestinationBank,
+	}
+
+	mockRefundRepo.EXPECT().GetByID(ctx, requestID).Return(bankRefund, nil)
+	mockRefundRepo.EXPECT().ListByParentID(ctx, requestID).Return(nil, nil)
+	mockTransactionRepo.EXPECT().GetByBookingID(ctx, bookingID).Return(transaction, nil)
+	mockTransactionRepo.EXPECT().ListExchangeDifferences(ctx, transaction.ID).Return(nil, nil)
+	mockRefundRepo.EXPECT().
+		Create(ctx, gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, refund *model.Refund, entry *model.Transaction, events ...*outbox.Event) error {
+			assert.Equal(t, model.RefundDestinationWallet, refund.Destination)
+			assert.Equal(t, 50000, refund.RefundAmount)
+			assert.NotEqual(t, requestID, refund.ID)
+			if assert.NotNil(t, refund.ParentRefundID) {
+				assert.Equal(t, requestID, *refund.ParentRefundID)
+			}
+			return nil
+		}).
+		Times(1)
+
+	result, err := service.CreateOperatorRefund(ctx, &model.RefundRequest{
+		ID:           requestID,
+		BookingID:    bookingID,
+		Reason:       "Trip Cancelled by Operator",
+		RefundAmount: 150000,
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, requestID, result.ID)
+	assert.Equal(t, 100000, result.RefundAmount)
+}
+
+func TestCreateOperatorRefund_RetryReturnsRefundCreated(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRefundRepo := repo_mocks.NewMockRefundRepository(ctrl)
+	mockTransactionRepo := repo_mocks.NewMockTransactionRepository(ctrl)
+
+	service := NewRefundService(
+		mockRefundRepo,
+		mockTransactionRepo,
+		repo_mocks.NewMockBankAccountRepository(ctrl),
+		service_mocks.NewMockConstantsService(ctrl),
+		service_mocks.NewMockExcelService(ctrl),
+	)
+
+	ctx := context.Background()
+	bookingID := uuid.New()
+	requestID := uuid.New()
+
+	// The first leg of an itinerary was refunded, the response was lost before it was recorded.
+	// What is left of the payment belongs to the other legs.
+	transaction := &model.Transaction{
+		BaseModel:      model.BaseModel{ID: uuid.New()},
+		BookingID:      bookingID,
+		UserID:         uuid.New(),
+		Amount:         300000,
+		AmountPaid:     300000,
+		RefundedAmount: 100000,
+		Status:         model.TransactionStatusPaid,
+	}
+	refund := &model.Refund{
+		BaseModel:     model.BaseModel{ID: requestID},
+		BookingID:     bookingID,
+		TransactionID: transaction.ID,
+		RefundAmount:  100000,
+		RefundStatus:  model.RefundStatusPending,
+		Destination:   model.RefundDestinationBank,
+	}
+
+	mockRefundRepo.EXPECT().GetByID(ctx, requestID).Return(refund, nil)
+	mockRefundRepo.EXPECT().ListByParentID(ctx, requestID).Return(nil, nil)
+	mockTransactionRepo.EXPECT().GetByBookingID(ctx, bookingID).Return(transaction, nil)
+	mockTransactionRepo.EXPECT().ListExchangeDifferences(ctx, transaction.ID).Return(nil, nil)
+	mockRefundRepo.EXPECT().Create(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+	result, err := service.CreateOperatorRefund(ctx, &model.RefundRequest{
+		ID:           requestID,
+		BookingID:    bookingID,
+		Reason:       "Trip Cancelled by Operator",
+		RefundAmount: 100000,
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, requestID, result.ID)
+	assert.Equal(t, 100000, result.RefundAmount)
+}
+
+func TestCreateOperatorRefund_IDOfAnotherBooking(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRefundRepo := repo_mocks.NewMockRefundRepository(ctrl)
+	mockTransactionRepo := repo_mocks.NewMockTransactionRepository(ctrl)
+
+	service := NewRefundService(
+		mockRefundRepo,
+		mockTransactionRepo,
+		repo_mocks.NewMockBankAccountRepository(ctrl),
+		service_mocks.NewMockConstantsService(ctrl),
+		service_mocks.NewMockExcelService(ctrl),
+	)
+
+	ctx := context.Background()
+	requestID := uuid.New()
+
+	mockRefundRepo.EXPECT().GetByID(ctx, requestID).Return(&model.Refund{
+		BaseModel: model.BaseModel{ID: requestID},
+		BookingID: uuid.New(),
+	}, nil)
+	mockTransactionRepo.EXPECT().GetByBookingID(gomock.Any(), gomock.Any()).Times(0)
+
+	result, err := service.CreateOperatorRefund(ctx, &model.RefundRequest{
+		ID:           requestID,
+		BookingID:    uuid.New(),
+		Reason:       "Trip Cancelled by Operator",
+		RefundAmount: 100000,
+	})
+
+	assert.Nil(t, result)
+	var apiErr *ginext.Error
+	assert.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusConflict, apiErr.Code)
+}
+
+func TestCreateOperatorRefund_AfterExchangeRefundsFareDifference(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRefundRepo := repo_mocks.NewMockRefundRepository(ctrl)
+	mockTransactionRepo := repo_mocks.NewMockTransactionRepository(ctrl)
+
+	service := NewRefundService(
+		mockRefundRepo,
+		mockTransactionRepo,
+		repo_mocks.NewMockBankAccountRepository(ctrl),
+		service_mocks.NewMockConstantsService(ctrl),
+		service_mocks.NewMockExcelService(ctrl),
+	)
+
+	ctx := context.Background()
+	bookingID := uuid.New()
+	original := &model.Transaction{
+		BaseModel: model.BaseModel{ID: uuid.New()},
+		BookingID: bookingID,
+		UserID:    uuid.New(),
+		Amount:    300000,
+		Status:    model.TransactionStatusPaid,
+	}
+	difference := &model.Transaction{
+		BaseModel:             model.BaseModel{ID: uuid.New()},
+		BookingID:             bookingID,
+		UserID:                original.UserID,
+		Amount:                100000,
+		Status:                model.TransactionStatusPaid,
+		OriginalTransactionID: &original.ID,
+	}
+
+	mockTransactionRepo.EXPECT().GetByBookingID(ctx, bookingID).Return(original, nil)
+	mockTransactionRepo.EXPECT().ListExchangeDifferences(ctx, original.ID).Return([]*model.Transaction{difference}, nil)
+
+	var refunded []uuid.UUID
+	mockRefundRepo.EXPECT().
+		Create(ctx, gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, refund *model.Refund, entry *model.Transaction, events ...*outbox.Event) error {
+			refunded = append(refunded, refund.TransactionID)
+			return nil
+		}).
+		Times(2)
+
+	result, err := service.CreateOperatorRefund(ctx, &model.RefundRequest{
+		BookingID:    bookingID,
+		Reason:       "Trip Cancelled by Operator",
+		RefundAmount: 400000,
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 300000, result.RefundAmount)
+	assert.Equal(t, []uuid.UUID{original.ID, difference.ID}, refunded)
+}
+
+func TestGetBookingRefund_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRefundRepo := repo_mocks.NewMockRefundRepository(ctrl)
+	mockTransactionRepo := repo_mocks.NewMockTransactionRepository(ctrl)
+	mockBankAccountRepo := repo_mocks.NewMockBankAccountRepository(ctrl)
+	mockConstantsService := service_mocks.NewMockConstantsService(ctrl)
+	mockExcelService := service_mocks.NewMockExcelService(ctrl)
+
+	service := NewRefundService(
+		mockRefundRepo,
+		mockTransactionRepo,
+		mockBankAccountRepo,
+		mockConstantsService,
+		mockExcelService,
+	)
+
+	ctx := context.Background()
+	bookingID := uuid.New()
+	refund := &model.Refund{
+		BaseModel:    model.BaseModel{ID: uuid.New()},
+		BookingID:    bookingID,
+		UserID:       uuid.New(),
+		RefundAmount: 30000,
+		RefundStatus: model.RefundStatusCompleted,
+		Destination:  model.RefundDestinationWallet,
+	}
+	bankPart := &model.Refund{
+		BaseModel:      model.BaseModel{ID: uuid.New()},
+		BookingID:      bookingID,
+		RefundAmount:   70000,
+		RefundStatus:   model.RefundStatusPending,
+		Destination:    model.RefundDestinationBank,
+		ParentRefundID: &refund.ID,
+	}
+
+	mockRefundRepo.EXPECT().
+		GetByBookingID(ctx, bookingID).
+		Return(refund, nil).
+		Times(1)
+	mockRefundRepo.EXPECT().ListByParentID(ctx, refund.ID).Return([]*model.Refund{bankPart}, nil)
+
+	result, err := service.GetBookingRefund(ctx, bookingID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, refund.ID, result.ID)
+	// The wallet part was credited at once, the bank part still waits for its payout
+	assert.Equal(t, 100000, result.RefundAmount)
+	assert.Equal(t, model.RefundStatusPending, result.RefundStatus)
+}
+
+func TestGetRefundStatus_PendingUntilEveryPartIsPaidBack(t *testing.T) {
+	tests := []struct {
+		name       string
+		bankStatus model.RefundStatus
+		want       model.RefundStatus
+	}{
+		{name: "bank part waiting for payout", bankStatus: model.RefundStatusPending, want: model.RefundStatusPending},
+		{name: "bank part being paid out", bankStatus: model.RefundStatusProcessing, want: model.RefundStatusProcessing},
+		{name: "bank part paid out", bankStatus: model.RefundStatusCompleted, want: model.RefundStatusCompleted},
+		{name: "bank part rejected", bankStatus: model.RefundStatusRejected, want: model.RefundStatusRejected},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRefundRepo := repo_mocks.NewMockRefundRepository(ctrl)
+
+			service := NewRefundService(
+				mockRefundRepo,
+				repo_mocks.NewMockTransactionRepository(ctrl),
+				repo_mocks.NewMockBankAccountRepository(ctrl),
+				service_mocks.NewMockConstantsService(ctrl),
+				service_mocks.NewMockExcelService(ctrl),
+			)
+
+			ctx := context.Background()
+			bankRefund := &model.Refund{
+				BaseModel:    model.BaseModel{ID: uuid.New()},
+				BookingID:    uuid.New(),
+				RefundAmount: 100000,
+				RefundStatus: tt.bankStatus,
+				Destination:  model.RefundDestinationBank,
+			}
+			// The wallet part completes as it is created, and is the latest refund of the booking
+			walletRefund := &model.Refund{
+				BaseModel:      model.BaseModel{ID: uuid.New()},
+				BookingID:      bankRefund.BookingID,
+				RefundAmount:   50000,
+				RefundStatus:   model.RefundStatusCompleted,
+				Destination:    model.RefundDestinationWallet,
+				ParentRefundID: &bankRefund.ID,
+			}
+
+			mockRefundRepo.EXPECT().GetByID(ctx, bankRefund.ID).Return(bankRefund, nil)
+			mockRefundRepo.EXPECT().ListByParentID(ctx, bankRefund.ID).Return([]*model.Refund{walletRefund}, nil)
+
+			result, err := service.GetRefundStatus(ctx, bankRefund.ID)
+
+			assert.NoError(t, err)
+			assert.Equal(t, bankRefund.ID, result.ID)
+			assert.Equal(t, tt.want, result.RefundStatus)
+			assert.Equal(t, 150000, result.RefundAmount)
+		})
+	}
+}
+
+func TestGetRefundStatus_NotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRefundRepo := repo_mocks.NewMockRefundRepository(ctrl)
+
+	service := NewRefundService(
+		mockRefundRepo,
+		repo_mocks.NewMockTransactionRepository(ctrl),
+		repo_mocks.NewMockBankAccountRepository(ctrl),
+		service_mocks.NewMockConstantsService(ctrl),
+		service_mocks.NewMockExcelService(ctrl),
+	)
+
+	ctx := context.Background()
+	refundID := uuid.New()
+
+	mockRefundRepo.EXPECT().GetByID(ctx, refundID).Return(nil, assert.AnError)
+	mockRefundRepo.EXPECT().ListByParentID(gomock.Any(), gomock.Any()).Times(0)
+
+	result, err := service.GetRefundStatus(ctx, refundID)
+
+	assert.Nil(t, result)
+	var apiErr *ginext.Error
+	assert.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusNotFound, apiErr.Code)
+}
+
+func TestRefundSurplus_RefundsExcessWithoutBankAccount(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRefundRepo := repo_mocks.NewMockRefundRepository(ctrl)
+	mockTransactionRepo := repo_mocks.NewMockTransactionRepository(ctrl)
+	mockBankAccountRepo := repo_mocks.NewMockBankAccountRepository(ctrl)
+
+	service := NewRefundService(
+		mockRefundRepo,
+		mockTransactionRepo,
+		mockBankAccountRepo,
+		service_mocks.NewMockConstantsService(ctrl),
+		service_mocks.NewMockExcelService(ctrl),
+	)
+
+	ctx := context.Background()
+	refundID := uuid.New()
+
+	// 30.000 VND transferred on top of the price
+	transaction := &model.Transaction{
+		BaseModel:  model.BaseModel{ID: uuid.New()},
+		BookingID:  uuid.New(),
+		UserID:     uuid.New(),
+		Amount:     100000,
+		AmountPaid: 130000,
+		Status:     model.TransactionStatusPaid,
+	}
+
+	event, err := outbox.NewEvent(model.AggregateTypeTransaction, transaction.ID, model.EventTypeSurplusRefundDue, &model.SurplusRefundDueEvent{
+		RefundID:      refundID,
+		TransactionID: transaction.ID,
+		Amount:        30000,
+		Reason:        "Hoàn tiền chuyển thừa",
+	})
+	assert.NoError(t, err)
+
+	mockRefundRepo.EXPECT().GetByID(ctx, refundID).Return(nil, assert.AnError)
+	mockTransactionRepo.EXPECT().GetByID(ctx, transaction.ID).Return(transaction, nil)
+	mockBankAccountRepo.EXPECT().GetPrimaryBankAccount(gomock.Any(), gomock.Any()).Times(0)
+	mockRefundRepo.EXPECT().
+		Create(ctx, gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, refund *model.Refund, entry *model.Transaction, events ...*outbox.Event) error {
+			assert.Equal(t, refundID, refund.ID)
+			assert.Equal(t, transaction.ID, refund.TransactionID)
+			assert.Equal(t, 30000, refund.RefundAmount)
+			assert.Equal(t, model.TransactionTypeOut, entry.TransactionType)
+			return nil
+		})
+
+	assert.NoError(t, service.RefundSurplus(ctx, event))
+}
+
+func TestRefundSurplus_RedeliveredEventSkipped(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRefundRepo := repo_mocks.NewMockRefundRepository(ctrl)
+	mockTransactionRepo := repo_mocks.NewMockTransactionRepository(ctrl)
+
+	service := NewRefundService(
+		mockRefundRepo,
+		mockTransactionRepo,
+		repo_mocks.NewMockBankAccountRepository(ctrl),
+		service_mocks.NewMockConstantsService(ctrl),
+		service_mocks.NewMockExcelService(ctrl),
+	)
+
+	ctx := context.Background()
+	refundID := uuid.New()
+
+	event, err := outbox.NewEvent(model.AggregateTypeTransaction, uuid.New(), model.EventTypeSurplusRefundDue, &model.SurplusRefundDueEvent{
+		RefundID:      refundID,
+		TransactionID: uuid.New(),
+		Amount:        50000,
+	})
+	assert.NoError(t, err)
+
+	mockRefundRepo.EXPECT().GetByID(ctx, refundID).Return(&model.Refund{BaseModel: model.BaseModel{ID: refundID}}, nil)
+	mockRefundRepo.EXPECT().Create(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+	assert.NoError(t, service.RefundSurplus(ctx, event))
+}
+
+func TestRefundSurplus_PartialPaymentOfCancelledTransaction(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRefundRepo := repo_mocks.NewMockRefundRepository(ctrl)
+	mockTransactionRepo := repo_mocks.NewMockTransactionRepository(ctrl)
+
+	service := NewRefundService(
+		mockRefundRepo,
+		mockTransactionRepo,
+		repo_mocks.NewMockBankAccountRepository(ctrl),
+		service_mocks.NewMockConstantsService(ctrl),
+		service_mocks.NewMockExcelService(ctrl),
+	)
+
+	ctx := context.Background()
+
+	// The hold expired before the rest of the payment arrived
+	transaction := &model.Transaction{
+		BaseModel:  model.BaseModel{ID: uuid.New()},
+		BookingID:  uuid.New(),
+		UserID:     uuid.New(),
+		Amount:     200000,
+		AmountPaid: 80000,
+		Status:     model.TransactionStatusCancelled,
+	}
+
+	event, err := outbox.NewEvent(model.AggregateTypeTransaction, transaction.ID, model.EventTypeSurplusRefundDue, &model.SurplusRefundDueEvent{
+		RefundID:      uuid.New(),
+		TransactionID: transaction.ID,
+		Amount:        80000,
+	})
+	assert.NoError(t, err)
+
+	mockRefundRepo.EXPECT().GetByID(ctx, gomock.Any()).Return(nil, assert.AnError)
+	mockTransactionRepo.EXPECT().GetByID(ctx, transaction.ID).Return(transaction, nil)
+	mockRefundRepo.EXPECT().
+		Create(ctx, gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, refund *model.Refund, entry *model.Transaction, events ...*outbox.Event) error {
+			assert.Equal(t, 80000, refund.RefundAmount)
+			return nil
+		})
+
+	assert.NoError(t, service.RefundSurplus(ctx, event))
+}
@@ -0,0 +1,167 @@
+package service
+
+import (
+	"bus-booking/payment-service/internal/model"
+	"context"
+	"fmt"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// SandboxProvider is a local stand-in for a payment provider, so the booking to payment flow
+// runs offline. Payments stay pending until the customer action is simulated through its webhook.
+// State lives in memory and is lost on restart.
+type SandboxProvider struct {
+	checkoutURL string
+
+	mu       sync.Mutex
+	payments map[string]*sandboxPayment
+}
+
+type sandboxPayment struct {
+	payment   model.ProviderPayment
+	expiresAt time.Time
+	refunded  int
+}
+
+func NewSandboxProvider(checkoutURL string) PaymentProvider {
+	return &SandboxProvider{
+		checkoutURL: checkoutURL,
+		payments:    make(map[string]*sandboxPayment),
+	}
+}
+
+func (p *SandboxProvider) CreatePayment(ctx context.Context, req *model.CreatePaymentLinkRequest) (*model.ProviderPayment, error) {
+	orderCode := nextOrderCode()
+	paymentLinkID := fmt.Sprintf("sandbox-%d", orderCode)
+
+	entry := &sandboxPayment{
+		payment: model.ProviderPayment{
+			OrderCode:     orderCode,
+			PaymentLinkID: paymentLinkID,
+			Status:        model.TransactionStatusPending,
+			Amount:        req.Amount,
+			CheckoutURL:   p.checkoutURL + "?payment_link_id=" + url.QueryEscape(paymentLinkID),
+		},
+		expiresAt: req.ExpiresAt,
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.payments[paymentLinkID] = entry
+
+	payment := entry.payment
+	return &payment, nil
+}
+
+func (p *SandboxProvider) GetPayment(ctx context.Context, paymentLinkID string) (*model.ProviderPayment, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	entry, err := p.get(paymentLinkID)
+	if err != nil {
+		return nil, err
+	}
+
+	payment := entry.payment
+	return &payment, nil
+}
+
+func (p *SandboxProvider) CancelPayment(ctx context.Context, paymentLinkID string, reason string) (*model.ProviderPayment, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	entry, err := p.get(paymentLinkID)
+	if err != nil {
+		return nil, err
+	}
+	if entry.payment.Status != model.TransactionStatusPending && entry.payment.Status != model.TransactionStatusUnderpaid {
+		return nil, fmt.Errorf("sandbox payment %s is %s", paymentLinkID, entry.payment.Status)
+	}
+	entry.payment.Status = model.TransactionStatusCancelled
+
+	payment := entry.payment
+	return &payment, nil
+}
+
+// VerifyWebhook applies a simulated customer action: {"payment_link_id": "...", "status": "PAID"}.
+// Paying takes an optional "amount" transferred; transfers add up until the payment is paid in full.
+func (p *SandboxProvider) VerifyWebhook(ctx context.Context, payload map[string]interface{}) (*model.ProviderWebhook, error) {
+	paymentLinkID, _ := payload["payment_link_id"].(string)
+	status, _ := payload["status"].(string)
+	amount, _ := payload["amount"].(float64)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	entry, err := p.get(paymentLinkID)
+	if err != nil {
+		return nil, err
+	}
+	if entry.payment.Status != model.TransactionStatusPending && entry.payment.Status != model.TransactionStatusUnderpaid {
+		return nil, fmt.Errorf("sandbox payment %s is %s", paymentLinkID, entry.payment.Status)
+	}
+
+	switch model.TransactionStatus(status) {
+	case model.TransactionStatusPaid:
+		transferred := int(amount)
+		if transferred <= 0 {
+			transferred = entry.payment.Amount - entry.payment.AmountPaid
+		}
+		now := time.Now()
+		entry.payment.AmountPaid += transferred
+		entry.payment.Status = model.TransactionStatusPaid
+		if entry.payment.AmountPaid < entry.payment.Amount {
+			entry.payment.Status = model.TransactionStatusUnderpaid
+		}
+		entry.payment.Reference = fmt.Sprintf("SANDBOX%d-%d", entry.payment.OrderCode, entry.payment.AmountPaid)
+		entry.payment.PaidAt = &now
+	case model.TransactionStatusCancelled, model.TransactionStatusFailed, model.TransactionStatusExpired:
+		entry.payment.Status = model.TransactionStatus(status)
+	default:
+		return nil, fmt.Errorf("unsupported sandbox payment status %q", status)
+	}
+
+	return &model.ProviderWebhook{
+		EventID:       fmt.Sprintf("%s:%s:%d", paymentLinkID, status, entry.payment.AmountPaid),
+		OrderCode:     entry.payment.OrderCode,
+		PaymentLinkID: entry.payment.PaymentLinkID,
+		Reference:     entry.payment.Reference,
+		PaidAt:        entry.payment.PaidAt,
+	}, nil
+}
+
+func (p *SandboxProvider) Refund(ctx context.Context, req *model.ProviderRefundRequest) (*model.ProviderRefund, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	entry, err := p.get(req.PaymentLinkID)
+	if err != nil {
+		return nil, err
+	}
+	if entry.payment.Status != model.TransactionStatusPaid {
+		return nil, fmt.Errorf("sandbox payment %s is %s", req.PaymentLinkID, entry.payment.Status)
+	}
+	if entry.refunded+req.Amount > entry.payment.AmountPaid {
+		return nil, fmt.Errorf("sandbox refund of %d exceeds the refundable %d", req.Amount, entry.payment.AmountPaid-entry.refunded)
+	}
+	entry.refunded += req.Amount
+
+	return &model.ProviderRefund{
+		Reference:  fmt.Sprintf("SANDBOXRF%d", entry.payment.OrderCode),
+		RefundedAt: time.Now(),
+	}, nil
+}
+
+// get returns the payment, expiring it like a real provider once its deadline passes; callers hold mu
+func (p *SandboxProvider) get(paymentLinkID string) (*sandboxPayment, error) {
+	entry, ok := p.payments[paymentLinkID]
+	if !ok {
+		return nil, fmt.Errorf("sandbox payment %s not found", paymentLinkID)
+	}
+	if entry.payment.Status == model.TransactionStatusPending && !entry.expiresAt.IsZero() && time.Now().After(entry.expiresAt) {
+		entry.payment.Status = model.TransactionStatusExpired
+	}
+	return entry, nil
+}
@@ -0,0 +1,223 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"bus-booking/payment-service/internal/model"
+	"bus-booking/payment-service/internal/repository"
+	"bus-booking/shared/outbox"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func underpaidPayment(amount, amountPaid int) *model.ProviderPayment {
+	payment := paidPayment(amount)
+	payment.Status = model.TransactionStatusUnderpaid
+	payment.AmountPaid = amountPaid
+	return payment
+}
+
+func TestApplyWebhook_UnderpaidKeepsWaitingForRemainder(t *testing.T) {
+	service, mockTransactionRepo, mockPayOSService := newReconciliationTestService(t)
+	ctx := context.Background()
+
+	transaction := newPendingTransaction(time.Now().Add(10 * time.Minute))
+	transaction.OrderCode = 111111
+	webhook := &model.ProviderWebhook{OrderCode: transaction.OrderCode, PaymentLinkID: transaction.PaymentLinkID}
+
+	mockPayOSService.EXPECT().
+		GetPayment(gomock.Any(), transaction.PaymentLinkID).
+		Return(underpaidPayment(transaction.Amount, 100000), nil)
+	mockTransactionRepo.EXPECT().
+		GetByWebhookData(gomock.Any(), int(transaction.OrderCode), transaction.PaymentLinkID).
+		Return(transaction, nil)
+	mockTransactionRepo.EXPECT().
+		UpdateTransactionWithEvents(ctx, gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, tx *model.Transaction, _ model.TransactionStatus, events ...*outbox.Event) error {
+			assert.Equal(t, model.TransactionStatusUnderpaid, tx.Status)
+			assert.Equal(t, 100000, tx.AmountPaid)
+
+			// Nothing is captured or refunded while the passenger can still pay the rest
+			assert.Len(t, events, 1)
+			var payload model.PaymentStatusChangedEvent
+			assert.NoError(t, events[0].Decode(&payload))
+			assert.Equal(t, model.TransactionStatusUnderpaid, payload.TransactionStatus)
+			assert.Equal(t, 100000, payload.AmountPaid)
+			return nil
+		})
+
+	_, err := service.ApplyWebhook(ctx, model.PaymentMethodPayOS, webhook)
+
+	assert.NoError(t, err)
+}
+
+func TestApplyWebhook_FurtherShortTransferApplied(t *testing.T) {
+	service, mockTransactionRepo, mockPayOSService := newReconciliationTestService(t)
+	ctx := context.Background()
+
+	transaction := newPendingTransaction(time.Now().Add(10 * time.Minute))
+	transaction.OrderCode = 222222
+	transaction.Status = model.TransactionStatusUnderpaid
+	transaction.AmountPaid = 100000
+	webhook := &model.ProviderWebhook{OrderCode: transaction.OrderCode, PaymentLinkID: transaction.PaymentLinkID}
+
+	mockPayOSService.EXPECT().
+		GetPayment(gomock.Any(), transaction.PaymentLinkID).
+		Return(underpaidPayment(transaction.Amount, 200000), nil)
+	mockTransactionRepo.EXPECT().
+		GetByWebhookData(gomock.Any(), int(transaction.OrderCode), transaction.PaymentLinkID).
+		Return(transaction, nil)
+	mockTransactionRepo.EXPECT().
+		UpdateTransactionWithEvents(ctx, gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, tx *model.Transaction, _ model.TransactionStatus, events ...*outbox.Event) error {
+			assert.Equal(t, 200000, tx.AmountPaid)
+			assert.Len(t, events, 1)
+			return nil
+		})
+
+	_, err := service.ApplyWebhook(ctx, model.PaymentMethodPayOS, webhook)
+
+	assert.NoError(t, err)
+}
+
+func TestApplyWebhook_OverpaidRefundsExcess(t *testing.T) {
+	service, mockTransactionRepo, mockPayOSService := newReconciliationTestService(t)
+	ctx := context.Background()
+
+	transaction := newPendingTransaction(time.Now().Add(10 * time.Minute))
+	transaction.OrderCode = 333333
+	webhook := &model.ProviderWebhook{OrderCode: transaction.OrderCode, PaymentLinkID: transaction.PaymentLinkID}
+
+	payment := paidPayment(transaction.Amount)
+	payment.AmountPaid = transaction.Amount + 50000
+
+	mockPayOSService.EXPECT().
+		GetPayment(gomock.Any(), transaction.PaymentLinkID).
+		Return(payment, nil)
+	mockTransactionRepo.EXPECT().
+		GetByWebhookData(gomock.Any(), int(transaction.OrderCode), transaction.PaymentLinkID).
+		Return(transaction, nil)
+	mockTransactionRepo.EXPECT().
+		UpdateTransactionWithEvents(ctx, gomock.Any(), model.TransactionStatusPending, gomock.Any()).
+		DoAndReturn(func(ctx context.Context, tx *model.Transaction, _ model.TransactionStatus, events ...*outbox.Event) error {
+			assert.Equal(t, model.TransactionStatusPaid, tx.Status)
+			assert.Equal(t, 50000, tx.ExcessAmount())
+
+			assert.Len(t, events, 3)
+			assert.Equal(t, model.EventTypePaymentStatusChanged, events[0].EventType)
+			assert.Equal(t, model.EventTypePaymentCaptured, events[1].EventType)
+			assert.Equal(t, model.EventTypeSurplusRefundDue, events[2].EventType)
+
+			var payload model.SurplusRefundDueEvent
+			assert.NoError(t, events[2].Decode(&payload))
+			assert.Equal(t, tx.ID, payload.TransactionID)
+			assert.Equal(t, 50000, payload.Amount)
+			// Every delivery of the webhook queues the refund under the same ID
+			assert.Equal(t, uuid.NewSHA1(tx.ID, []byte("surplus")), payload.RefundID)
+			return nil
+		})
+
+	_, err := service.ApplyWebhook(ctx, model.PaymentMethodPayOS, webhook)
+
+	assert.NoError(t, err)
+}
+
+func TestApplyWebhook_ConcurrentDeliveryAppliedFirst(t *testing.T) {
+	service, mockTransactionRepo, mockPayOSService := newReconciliationTestService(t)
+	ctx := context.Background()
+
+	transaction := newPendingTransaction(time.Now().Add(10 * time.Minute))
+	transaction.OrderCode = 333334
+	webhook := &model.ProviderWebhook{OrderCode: transaction.OrderCode, PaymentLinkID: transaction.PaymentLinkID}
+
+	payment := paidPayment(transaction.Amount)
+	payment.AmountPaid = transaction.Amount + 50000
+
+	mockPayOSService.EXPECT().
+		GetPayment(gomock.Any(), transaction.PaymentLinkID).
+		Return(payment, nil)
+	mockTransactionRepo.EXPECT().
+		GetByWebhookData(gomock.Any(), int(transaction.OrderCode), transaction.PaymentLinkID).
+		Return(transaction, nil)
+	// Both deliveries read the transaction as pending, the other one saved its update first
+	mockTransactionRepo.EXPECT().
+		UpdateTransactionWithEvents(ctx, gomock.Any(), model.TransactionStatusPending, gomock.Any()).
+		Return(repository.ErrTransactionStatusChanged)
+
+	_, err := service.ApplyWebhook(ctx, model.PaymentMethodPayOS, webhook)
+
+	assert.NoError(t, err)
+}
+
+func TestApplyWebhook_UnderpaidExpiredRefundsPartialPayment(t *testing.T) {
+	service, mockTransactionRepo, mockPayOSService := newReconciliationTestService(t)
+	ctx := context.Background()
+
+	transaction := newPendingTransaction(time.Now().Add(-10 * time.Minute))
+	transaction.OrderCode = 444444
+	transaction.Status = model.TransactionStatusUnderpaid
+	transaction.AmountPaid = 100000
+	webhook := &model.ProviderWebhook{OrderCode: transaction.OrderCode, PaymentLinkID: transaction.PaymentLinkID}
+
+	expired := underpaidPayment(transaction.Amount, 100000)
+	expired.Status = model.TransactionStatusExpired
+
+	mockPayOSService.EXPECT().
+		GetPayment(gomock.Any(), transaction.PaymentLinkID).
+		Return(expired, nil)
+	mockTransactionRepo.EXPECT().
+		GetByWebhookData(gomock.Any(), int(transaction.OrderCode), transaction.PaymentLinkID).
+		Return(transaction, nil)
+	mockTransactionRepo.EXPECT().
+		UpdateTransactionWithEvents(ctx, gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, tx *model.Transaction, _ model.TransactionStatus, events ...*outbox.Event) error {
+			assert.Equal(t, model.TransactionStatusExpired, tx.Status)
+
+			assert.Len(t, events, 3)
+			assert.Equal(t, model.EventTypePaymentCaptured, events[1].EventType)
+			var payload model.SurplusRefundDueEvent
+			assert.NoError(t, events[2].Decode(&payload))
+			assert.Equal(t, 100000, payload.Amount)
+			return nil
+		})
+
+	_, err := service.ApplyWebhook(ctx, model.PaymentMethodPayOS, webhook)
+
+	assert.NoError(t, err)
+}
+
+func TestCancel_UnderpaidRefundsPartialPayment(t *testing.T) {
+	service, mockTransactionRepo, mockPayOSService := newReconciliationTestService(t)
+	ctx := context.Background()
+
+	transaction := newPendingTransaction(time.Now())
+	transaction.Status = model.TransactionStatusUnderpaid
+	transaction.AmountPaid = 120000
+
+	mockTransactionRepo.EXPECT().GetByID(ctx, transaction.ID).Return(transaction, nil)
+	mockPayOSService.EXPECT().
+		CancelPayment(ctx, transaction.PaymentLinkID, gomock.Any()).
+		Return(&model.ProviderPayment{Status: model.TransactionStatusCancelled}, nil)
+	mockTransactionRepo.EXPECT().
+		UpdateTransactionWithEvents(ctx, gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, tx *model.Transaction, _ model.TransactionStatus, events ...*outbox.Event) error {
+			assert.Equal(t, model.TransactionStatusCancelled, tx.Status)
+
+			assert.Len(t, events, 2)
+			assert.Equal(t, model.EventTypePaymentCaptured, events[0].EventType)
+			assert.Equal(t, model.EventTypeSurplusRefundDue, events[1].EventType)
+			var payload model.SurplusRefundDueEvent
+			assert.NoError(t, events[1].Decode(&payload))
+			assert.Equal(t, 120000, payload.Amount)
+			return nil
+		})
+
+	result, err := service.Cancel(ctx, transaction.ID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 120000, result.AmountPaid)
+}
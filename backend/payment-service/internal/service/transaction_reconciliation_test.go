@@ -0,0 +1,237 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	client_mocks "bus-booking/payment-service/internal/client/mocks"
+	"bus-booking/payment-service/internal/model"
+	repo_mocks "bus-booking/payment-service/internal/repository/mocks"
+	service_mocks "bus-booking/payment-service/internal/service/mocks"
+	"bus-booking/shared/outbox"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+// newReconciliationTestService wires a transaction service to a mocked PayOS provider
+func newReconciliationTestService(t *testing.T) (TransactionService, *repo_mocks.MockTransactionRepository, *service_mocks.MockPayOSService) {
+	ctrl := gomock.NewController(t)
+
+	mockTransactionRepo := repo_mocks.NewMockTransactionRepository(ctrl)
+	mockBookingClient := client_mocks.NewMockBookingClient(ctrl)
+	mockPayOSService := service_mocks.NewMockPayOSService(ctrl)
+
+	// Booking service is only called by the outbox relay
+	mockBookingClient.EXPECT().UpdateBookingStatus(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+	return NewTransactionService(mockTransactionRepo, repo_mocks.NewMockWalletRepository(ctrl), mockBookingClient, PaymentProviders{model.PaymentMethodPayOS: mockPayOSService}), mockTransactionRepo, mockPayOSService
+}
+
+func newPendingTransaction(expiresAt time.Time) *model.Transaction {
+	return &model.Transaction{
+		BaseModel:       model.BaseModel{ID: uuid.New()},
+		BookingID:       uuid.New(),
+		UserID:          uuid.New(),
+		Amount:          250000,
+		Status:          model.TransactionStatusPending,
+		PaymentMethod:   model.PaymentMethodPayOS,
+		PaymentLinkID:   "link-" + uuid.NewString(),
+		TransactionType: model.TransactionTypeIn,
+		ExpiresAt:       &expiresAt,
+	}
+}
+
+func paidPayment(amount int) *model.ProviderPayment {
+	paidAt := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+	return &model.ProviderPayment{
+		Status:     model.TransactionStatusPaid,
+		Amount:     amount,
+		AmountPaid: amount,
+		Reference:  "FT001",
+		PaidAt:     &paidAt,
+	}
+}
+
+func TestReconcile_AppliesMissedPayment(t *testing.T) {
+	service, mockTransactionRepo, mockPayOSService := newReconciliationTestService(t)
+	ctx := context.Background()
+	createdBefore := time.Now().Add(-2 * time.Minute)
+
+	transaction := newPendingTransaction(time.Now().Add(10 * time.Minute))
+
+	mockTransactionRepo.EXPECT().
+		ListUnsettled(ctx, createdBefore, 100).
+		Return([]*model.Transaction{transaction}, nil)
+	mockPayOSService.EXPECT().
+		GetPayment(ctx, transaction.PaymentLinkID).
+		Return(paidPayment(transaction.Amount), nil)
+
+	// The missed webhook is applied the same way: status, reference and the booking notification together
+	mockTransactionRepo.EXPECT().
+		UpdateTransactionWithEvents(ctx, transaction, gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, tx *model.Transaction, _ model.TransactionStatus, events ...*outbox.Event) error {
+			assert.Equal(t, model.TransactionStatusPaid, tx.Status)
+			assert.Equal(t, "FT001", tx.Reference)
+			assert.NotNil(t, tx.TransactionTime)
+
+			assert.Len(t, events, 2)
+			assert.Equal(t, model.EventTypePaymentCaptured, events[1].EventType)
+			var payload model.PaymentStatusChangedEvent
+			assert.NoError(t, events[0].Decode(&payload))
+			assert.Equal(t, transaction.BookingID, payload.BookingID)
+			assert.Equal(t, model.TransactionStatusPaid, payload.TransactionStatus)
+			return nil
+		})
+
+	summary, err := service.Reconcile(ctx, createdBefore, 100)
+
+	assert.NoError(t, err)
+	assert.Equal(t, &model.ReconciliationSummary{Checked: 1, Updated: 1}, summary)
+}
+
+func TestReconcile_FlagsPaidAfterExpiry(t *testing.T) {
+	service, mockTransactionRepo, mockPayOSService := newReconciliationTestService(t)
+	ctx := context.Background()
+	createdBefore := time.Now().Add(-2 * time.Minute)
+
+	// The booking deadline and its grace period have passed, booking service may have expired it
+	transaction := newPendingTransaction(time.Now().Add(-10 * time.Minute))
+
+	mockTransactionRepo.EXPECT().
+		ListUnsettled(ctx, createdBefore, 100).
+		Return([]*model.Transaction{transaction}, nil)
+	mockPayOSService.EXPECT().
+		GetPayment(ctx, transaction.PaymentLinkID).
+		Return(paidPayment(transaction.Amount), nil)
+
+	mockTransactionRepo.EXPECT().
+		FlagTransaction(ctx, gomock.Any()).
+		DoAndReturn(func(ctx context.Context, issue *model.ReconciliationIssue) error {
+			assert.Equal(t, transaction.ID, issue.TransactionID)
+			assert.Equal(t, transaction.BookingID, issue.BookingID)
+			assert.Equal(t, model.ReconciliationIssuePaidAfterExpired, issue.IssueType)
+			assert.Equal(t, model.TransactionStatusPending, issue.LocalStatus)
+			assert.Equal(t, model.TransactionStatusPaid, issue.ProviderStatus)
+			assert.Equal(t, "FT001", issue.ProviderReference)
+			assert.Equal(t, model.ReconciliationIssueStatusOpen, issue.Status)
+			return nil
+		})
+
+	// Neither the transaction nor the booking changes until an admin decides
+	mockTransactionRepo.EXPECT().UpdateTransactionWithEvents(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+	summary, err := service.Reconcile(ctx, createdBefore, 100)
+
+	assert.NoError(t, err)
+	assert.Equal(t, &model.ReconciliationSummary{Checked: 1, Flagged: 1}, summary)
+	assert.Equal(t, model.TransactionStatusPending, transaction.Status)
+}
+
+func TestReconcile_ProviderErrorDoesNotStopRun(t *testing.T) {
+	service, mockTransactionRepo, mockPayOSService := newReconciliationTestService(t)
+	ctx := context.Background()
+	createdBefore := time.Now().Add(-2 * time.Minute)
+
+	unreachable := newPendingTransaction(time.Now().Add(10 * time.Minute))
+	stillPending := newPendingTransaction(time.Now().Add(10 * time.Minute))
+
+	mockTransactionRepo.EXPECT().
+		ListUnsettled(ctx, createdBefore, 100).
+		Return([]*model.Transaction{unreachable, stillPending}, nil)
+	mockPayOSService.EXPECT().
+		GetPayment(ctx, unreachable.PaymentLinkID).
+		Return(nil, assert.AnError)
+	mockPayOSService.EXPECT().
+		GetPayment(ctx, stillPending.PaymentLinkID).
+		Return(&model.ProviderPayment{Amount: stillPending.Amount, Status: model.TransactionStatusPending}, nil)
+
+	// An unchanged status is left alone
+	mockTransactionRepo.EXPECT().UpdateTransactionWithEvents(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+	mockTransactionRepo.EXPECT().FlagTransaction(gomock.Any(), gomock.Any()).Times(0)
+
+	summary, err := service.Reconcile(ctx, createdBefore, 100)
+
+	assert.NoError(t, err)
+	assert.Equal(t, &model.ReconciliationSummary{Checked: 2, Failed: 1}, summary)
+}
+
+func TestReconcile_ListError(t *testing.T) {
+	service, mockTransactionRepo, _ := newReconciliationTestService(t)
+	ctx := context.Background()
+
+	mockTransactionRepo.EXPECT().
+		ListUnsettled(ctx, gomock.Any(), 100).
+		Return(nil, assert.AnError)
+
+	summary, err := service.Reconcile(ctx, time.Now(), 100)
+
+	assert.Error(t, err)
+	assert.Nil(t, summary)
+}
+
+func TestApplyWebhook_PaidAfterCancelled_Flagged(t *testing.T) {
+	service, mockTransactionRepo, mockPayOSService := newReconciliationTestService(t)
+	ctx := context.Background()
+
+	transaction := newPendingTransaction(time.Now().Add(10 * time.Minute))
+	transaction.Status = model.TransactionStatusCancelled
+	transaction.OrderCode = 123456
+
+	webhook := &model.ProviderWebhook{
+		OrderCode:     transaction.OrderCode,
+		PaymentLinkID: transaction.PaymentLinkID,
+		Reference:     "FT002",
+	}
+
+	mockPayOSService.EXPECT().
+		GetPayment(gomock.Any(), transaction.PaymentLinkID).
+		Return(paidPayment(transaction.Amount), nil)
+	mockTransactionRepo.EXPECT().
+		GetByWebhookData(gomock.Any(), int(transaction.OrderCode), transaction.PaymentLinkID).
+		Return(transaction, nil)
+
+	mockTransactionRepo.EXPECT().
+		FlagTransaction(ctx, gomock.Any()).
+		DoAndReturn(func(ctx context.Context, issue *model.ReconciliationIssue) error {
+			assert.Equal(t, model.ReconciliationIssuePaidAfterCancelled, issue.IssueType)
+			assert.Equal(t, model.TransactionStatusCancelled, issue.LocalStatus)
+			assert.Equal(t, "FT002", issue.ProviderReference)
+			return nil
+		})
+	mockTransactionRepo.EXPECT().UpdateTransactionWithEvents(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+	_, err := service.ApplyWebhook(ctx, model.PaymentMethodPayOS, webhook)
+
+	assert.NoError(t, err)
+}
+
+func TestApplyWebhook_RedeliveredStatusIgnored(t *testing.T) {
+	service, mockTransactionRepo, mockPayOSService := newReconciliationTestService(t)
+	ctx := context.Background()
+
+	transaction := newPendingTransaction(time.Now().Add(10 * time.Minute))
+	transaction.Status = model.TransactionStatusPaid
+	transaction.OrderCode = 654321
+
+	webhook := &model.ProviderWebhook{
+		OrderCode:     transaction.OrderCode,
+		PaymentLinkID: transaction.PaymentLinkID,
+	}
+
+	mockPayOSService.EXPECT().
+		GetPayment(gomock.Any(), transaction.PaymentLinkID).
+		Return(paidPayment(transaction.Amount), nil)
+	mockTransactionRepo.EXPECT().
+		GetByWebhookData(gomock.Any(), int(transaction.OrderCode), transaction.PaymentLinkID).
+		Return(transaction, nil)
+
+	mockTransactionRepo.EXPECT().UpdateTransactionWithEvents(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+	mockTransactionRepo.EXPECT().FlagTransaction(gomock.Any(), gomock.Any()).Times(0)
+
+	_, err := service.ApplyWebhook(ctx, model.PaymentMethodPayOS, webhook)
+
+	assert.NoError(t, err)
+}
@@ -6,12 +6,14 @@ import (
 	"bus-booking/payment-service/internal/model/booking"
 	"bus-booking/payment-service/internal/repository"
 	"bus-booking/shared/ginext"
+	"bus-booking/shared/outbox"
 	"context"
+	"errors"
 	"fmt"
+	"slices"
 	"time"
 
 	"github.com/google/uuid"
-	"github.com/payOSHQ/payos-lib-golang/v2"
 	"github.com/rs/zerolog/log"
 	"golang.org/x/sync/errgroup"
 )
@@ -23,24 +25,35 @@ type TransactionService interface {
 	GetByBookingID(ctx context.Context, bookingID uuid.UUID) (*model.TransactionResponse, error)
 	Create(ctx context.Context, req *model.CreateTransactionRequest, userID uuid.UUID) (*model.TransactionResponse, error)
 	Cancel(ctx context.Context, transactionID uuid.UUID) (*model.TransactionResponse, error)
-	HandleWebhook(ctx context.Context, webhookMap map[string]interface{}, webhookData model.PaymentWebhookData) error
+	// ApplyWebhook applies the provider status of the payment a verified notification is about.
+	// The transaction is returned whenever it was found, also when applying failed.
+	ApplyWebhook(ctx context.Context, method model.PaymentMethod, webhook *model.ProviderWebhook) (*model.Transaction, error)
+	ConfirmCashPayment(ctx context.Context, transactionID uuid.UUID, req *model.ConfirmCashPaymentRequest) (*model.TransactionResponse, error)
+	Reconcile(ctx context.Context, createdBefore time.Time, limit int) (*model.ReconciliationSummary, error)
+	DeliverPaymentStatusChanged(ctx context.Context, event *outbox.Event) error
 }
 
+// bookingExpirationGracePeriod mirrors the grace booking service gives a payment after the booking deadline
+const bookingExpirationGracePeriod = 1 * time.Minute
+
 type TransactionServiceImpl struct {
 	transactionRepo repository.TransactionRepository
+	walletRepo      repository.WalletRepository
 	bookingClient   client.BookingClient
-	payOSService    PayOSService
+	providers       PaymentProviders
 }
 
 func NewTransactionService(
 	transactionRepo repository.TransactionRepository,
+	walletRepo repository.WalletRepository,
 	bookingClient client.BookingClient,
-	payOSService PayOSService,
+	providers PaymentProviders,
 ) TransactionService {
 	return &TransactionServiceImpl{
 		transactionRepo: transactionRepo,
+		walletRepo:      walletRepo,
 		bookingClient:   bookingClient,
-		payOSService:    payOSService,
+		providers:       providers,
 	}
 }
 
@@ -67,14 +80,22 @@ func (s *TransactionServiceImpl) GetStats(ctx context.Context) (*model.Transacti
 	return stats, nil
 }
 
+// Create starts the payment of a booking. With UseWallet the wallet pays as much of it as it can and
+// the payment provider the rest; a booking the wallet pays in full is paid right away.
 func (s *TransactionServiceImpl) Create(ctx context.Context, req *model.CreateTransactionRequest, userID uuid.UUID) (*model.TransactionResponse, error) {
-	payosResp, err := s.payOSService.CreatePaymentLink(ctx, &model.CreatePaymentLinkRequest{
-		Amount:      req.Amount,
-		Description: req.Description,
-		ExpiresAt:   req.ExpiresAt,
-	})
-	if err != nil {
-		return nil, ginext.NewInternalServerError(fmt.Sprintf("failed to create payment link: %v", err))
+	walletAmount := 0
+	if req.UseWallet {
+		balance, err := s.spendableBalance(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		walletAmount = min(balance, req.Amount)
+	}
+
+	if req.OriginalTransactionID != nil {
+		if err := s.validateOriginalTransaction(ctx, req); err != nil {
+			return nil, err
+		}
 	}
 
 	id := req.ID
@@ -87,37 +108,152 @@ func (s *TransactionServiceImpl) Create(ctx context.Context, req *model.CreateTr
 			ID: id,
 		},
 		BookingID:     req.BookingID,
+		OperatorID:    req.OperatorID,
 		UserID:        userID,
 		Amount:        req.Amount,
+		AmountPaid:    walletAmount,
+		WalletAmount:  walletAmount,
 		Currency:      req.Currency,
 		PaymentMethod: req.PaymentMethod,
-		OrderCode:     payosResp.OrderCode,
-		PaymentLinkID: payosResp.PaymentLinkId,
-		Status:        s.payOSService.ToTransactionStatus(payosResp.Status),
-		CheckoutURL:   payosResp.CheckoutUrl,
-		QRCode:        payosResp.QrCode,
+		InvoiceBuyer:  req.InvoiceBuyer,
+		LegBookingIDs: req.LegBookingIDs,
+
+		OriginalTransactionID: req.OriginalTransactionID,
+	}
+	if !req.ExpiresAt.IsZero() {
+		expiresAt := req.ExpiresAt
+		transaction.ExpiresAt = &expiresAt
 	}
 
-	if err = s.transactionRepo.CreateTransaction(ctx, transaction); err != nil {
-		log.Error().Err(err).Msg("Failed to save transaction")
-		return nil, fmt.Errorf("failed to save transaction: %w", err)
+	if walletAmount == req.Amount {
+		return s.payFromWallet(ctx, transaction, req.Description)
 	}
 
+	provider, err := s.providers.Get(req.PaymentMethod)
+	if err != nil {
+		return nil, ginext.NewBadRequestError(err.Error())
+	}
+
+	payment, err := provider.CreatePayment(ctx, &model.CreatePaymentLinkRequest{
+		Amount:      transaction.ProviderAmount(),
+		Description: req.Description,
+		ExpiresAt:   req.ExpiresAt,
+	})
+	if err != nil {
+		return nil, ginext.NewInternalServerError(fmt.Sprintf("failed to create payment link: %v", err))
+	}
+
+	transaction.OrderCode = payment.OrderCode
+	transaction.PaymentLinkID = payment.PaymentLinkID
+	transaction.Status = payment.Status
+	transaction.CheckoutURL = payment.CheckoutURL
+	transaction.QRCode = payment.QRCode
+
+	if walletAmount == 0 {
+		if err = s.transactionRepo.CreateTransaction(ctx, transaction); err != nil {
+			log.Error().Err(err).Msg("Failed to save transaction")
+			return nil, fmt.Errorf("failed to save transaction: %w", err)
+		}
+		return s.toTransactionResponse(transaction), nil
+	}
+
+	if err := s.walletRepo.Pay(ctx, transaction, req.Description); err != nil {
+		// The payment link must not be paid for a transaction that was never saved
+		if _, cancelErr := provider.CancelPayment(ctx, payment.PaymentLinkID, "Wallet payment failed"); cancelErr != nil {
+			log.Error().Err(cancelErr).
+				Str("payment_link_id", payment.PaymentLinkID).
+				Msg("Failed to cancel provider payment")
+		}
+		return nil, s.walletPaymentError(err, transaction)
+	}
+
+	log.Info().
+		Str("transaction_id", transaction.ID.String()).
+		Int("wallet_amount", walletAmount).
+		Int("provider_amount", transaction.ProviderAmount()).
+		Msg("Payment partly drawn from the wallet")
+
 	return s.toTransactionResponse(transaction), nil
 }
 
-func (s *TransactionServiceImpl) HandleWebhook(ctx context.Context, webhookMap map[string]interface{}, webhookData model.PaymentWebhookData) error {
-	log.Info().Msg("Starting webhook verification")
+// validateOriginalTransaction checks a fare difference is paid on top of a paid payment of the same booking
+func (s *TransactionServiceImpl) validateOriginalTransaction(ctx context.Context, req *model.CreateTransactionRequest) error {
+	original, err := s.transactionRepo.GetByID(ctx, *req.OriginalTransactionID)
+	if err != nil {
+		return ginext.NewBadRequestError("original transaction not found")
+	}
+	if original.TransactionType != model.TransactionTypeIn || original.OriginalTransactionID != nil ||
+		(original.BookingID != req.BookingID && !slices.Contains(original.LegBookingIDs, req.BookingID)) {
+		return ginext.NewBadRequestError("original transaction is not a payment of this booking")
+	}
+	if original.Status != model.TransactionStatusPaid {
+		return ginext.NewBadRequestError("original transaction is not paid")
+	}
+	return nil
+}
 
-	// Verify webhook signature with original map data
-	if err := s.payOSService.VerifyWebhook(ctx, webhookMap); err != nil {
-		log.Error().Err(err).Msg("Webhook signature verification failed")
-		return ginext.NewUnauthorizedError("invalid webhook signature")
+// spendableBalance sums the credit the user can spend now. Expired credit still in the balance is left out.
+func (s *TransactionServiceImpl) spendableBalance(ctx context.Context, userID uuid.UUID) (int, error) {
+	credits, err := s.walletRepo.ListAvailableCredits(ctx, userID, time.Now())
+	if err != nil {
+		log.Error().Err(err).Str("user_id", userID.String()).Msg("Failed to get wallet credits")
+		return 0, ginext.NewInternalServerError("failed to get wallet balance")
+	}
+
+	balance := 0
+	for _, credit := range credits {
+		balance += credit.Remaining
+	}
+	return balance, nil
+}
+
+// payFromWallet pays the whole booking from the wallet, the payment is final as it is created
+func (s *TransactionServiceImpl) payFromWallet(ctx context.Context, transaction *model.Transaction, description string) (*model.TransactionResponse, error) {
+	now := time.Now()
+	paidAt := now.Unix()
+	transaction.PaymentMethod = model.PaymentMethodWallet
+	transaction.OrderCode = nextOrderCode()
+	transaction.Status = model.TransactionStatusPaid
+	transaction.TransactionTime = &paidAt
+
+	event, err := newPaymentStatusChangedEvent(transaction)
+	if err != nil {
+		return nil, ginext.NewInternalServerError(err.Error())
+	}
+	settlement, err := newSettlementEvents(transaction, model.TransactionStatusPending)
+	if err != nil {
+		return nil, ginext.NewInternalServerError(err.Error())
+	}
+
+	if err := s.walletRepo.Pay(ctx, transaction, description, append([]*outbox.Event{event}, settlement...)...); err != nil {
+		return nil, s.walletPaymentError(err, transaction)
+	}
+
+	log.Info().
+		Str("transaction_id", transaction.ID.String()).
+		Str("booking_id", transaction.BookingID.String()).
+		Int("amount", transaction.Amount).
+		Msg("Booking paid from the wallet")
+
+	return s.toTransactionResponse(transaction), nil
+}
+
+func (s *TransactionServiceImpl) walletPaymentError(err error, transaction *model.Transaction) error {
+	if errors.Is(err, repository.ErrInsufficientWalletBalance) {
+		return ginext.NewConflictError("wallet balance changed, please try again")
+	}
+	log.Error().Err(err).Str("booking_id", transaction.BookingID.String()).Msg("Failed to pay from the wallet")
+	return ginext.NewInternalServerError("failed to pay from the wallet")
+}
+
+func (s *TransactionServiceImpl) ApplyWebhook(ctx context.Context, method model.PaymentMethod, webhook *model.ProviderWebhook) (*model.Transaction, error) {
+	provider, err := s.providers.Get(method)
+	if err != nil {
+		return nil, ginext.NewBadRequestError(err.Error())
 	}
-	log.Info().Msg("Webhook signature verified successfully")
 
 	var (
-		paymentLink *payos.PaymentLink
+		payment     *model.ProviderPayment
 		transaction *model.Transaction
 	)
 
@@ -125,51 +261,308 @@ func (s *TransactionServiceImpl) HandleWebhook(ctx context.Context, webhookMap m
 
 	g.Go(func() error {
 		var err error
-		paymentLink, err = s.payOSService.GetPaymentLink(gCtx, webhookData.Data.PaymentLinkID)
+		payment, err = provider.GetPayment(gCtx, webhook.PaymentLinkID)
 		return err
 	})
 
 	g.Go(func() error {
 		var err error
-		orderCode := webhookData.Data.OrderCode
-		paymentLinkID := webhookData.Data.PaymentLinkID
-		transaction, err = s.transactionRepo.GetByWebhookData(ctx, orderCode, paymentLinkID)
+		transaction, err = s.transactionRepo.GetByWebhookData(ctx, int(webhook.OrderCode), webhook.PaymentLinkID)
 		return err
 	})
 
 	if err := g.Wait(); err != nil {
-		return err
+		return transaction, err
 	}
 
-	// Update transaction status
-	transaction.Status = s.payOSService.ToTransactionStatus(paymentLink.Status)
-	transaction.Reference = webhookData.Data.Reference
+	// The notification describes the payment that triggered it
+	if webhook.Reference != "" {
+		payment.Reference = webhook.Reference
+	}
+	if webhook.PaidAt != nil {
+		payment.PaidAt = webhook.PaidAt
+	}
+
+	_, err = s.applyPayment(ctx, transaction, payment)
+	return transaction, err
+}
+
+// ConfirmCashPayment records money an admin received at the counter
+func (s *TransactionServiceImpl) ConfirmCashPayment(ctx context.Context, transactionID uuid.UUID, req *model.ConfirmCashPaymentRequest) (*model.TransactionResponse, error) {
+	transaction, err := s.transactionRepo.GetByID(ctx, transactionID)
+	if err != nil {
+		return nil, ginext.NewNotFoundError("transaction not found")
+	}
+
+	if transaction.PaymentMethod != model.PaymentMethodCash {
+		return nil, ginext.NewBadRequestError("transaction is not paid at the counter")
+	}
+	if transaction.Status != model.TransactionStatusPending {
+		return nil, ginext.NewBadRequestError(fmt.Sprintf("transaction is %s, not awaiting payment", transaction.Status))
+	}
 
-	// Parse transaction datetime
-	if transTime, err := time.Parse("2006-01-02 15:04:05", webhookData.Data.TransactionDateTime); err == nil {
-		transTimeUnix := transTime.Unix()
+	now := time.Now()
+	issue, err := s.applyPayment(ctx, transaction, &model.ProviderPayment{
+		OrderCode:  transaction.OrderCode,
+		Status:     model.TransactionStatusPaid,
+		Amount:     transaction.ProviderAmount(),
+		AmountPaid: transaction.ProviderAmount(),
+		Reference:  req.ReceiptNumber,
+		PaidAt:     &now,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if issue != nil {
+		return nil, ginext.NewConflictError("payment flagged for reconciliation review: the booking may already have expired")
+	}
+
+	return s.toTransactionResponse(transaction), nil
+}
+
+// Reconcile polls the providers for payments whose webhook has not arrived and applies their status
+func (s *TransactionServiceImpl) Reconcile(ctx context.Context, createdBefore time.Time, limit int) (*model.ReconciliationSummary, error) {
+	transactions, err := s.transactionRepo.ListUnsettled(ctx, createdBefore, limit)
+	if err != nil {
+		return nil, ginext.NewInternalServerError("failed to list unsettled transactions")
+	}
+
+	summary := &model.ReconciliationSummary{}
+	for _, transaction := range transactions {
+		if ctx.Err() != nil {
+			break
+		}
+		summary.Checked++
+
+		payment, err := s.getProviderPayment(ctx, transaction)
+		if err != nil {
+			log.Warn().Err(err).
+				Str("transaction_id", transaction.ID.String()).
+				Str("payment_link_id", transaction.PaymentLinkID).
+				Msg("Failed to get payment for reconciliation")
+			summary.Failed++
+			continue
+		}
+
+		previousStatus := transaction.Status
+		issue, err := s.applyPayment(ctx, transaction, payment)
+		switch {
+		case err != nil:
+			log.Error().Err(err).Str("transaction_id", transaction.ID.String()).Msg("Failed to reconcile transaction")
+			summary.Failed++
+		case issue != nil:
+			summary.Flagged++
+		case transaction.Status != previousStatus:
+			summary.Updated++
+		}
+	}
+
+	return summary, nil
+}
+
+func (s *TransactionServiceImpl) getProviderPayment(ctx context.Context, transaction *model.Transaction) (*model.ProviderPayment, error) {
+	provider, err := s.providers.Get(transaction.PaymentMethod)
+	if err != nil {
+		return nil, err
+	}
+	return provider.GetPayment(ctx, transaction.PaymentLinkID)
+}
+
+// applyPayment moves the transaction to the provider status and notifies booking service.
+// Payments the booking may no longer be able to accept are flagged for an admin and returned instead.
+func (s *TransactionServiceImpl) applyPayment(ctx context.Context, transaction *model.Transaction, payment *model.ProviderPayment) (*model.ReconciliationIssue, error) {
+	// Redelivered webhooks must not notify booking service again, it may have moved on since.
+	// Another transfer to an underpaid payment keeps its status but raises the amount paid.
+	// The provider only knows the part of the price the wallet did not pay.
+	if payment.Status == transaction.Status && transaction.WalletAmount+payment.AmountPaid <= transaction.ReceivedAmount() {
+		return nil, nil
+	}
+
+	if issue := s.detectMismatch(transaction, payment); issue != nil {
+		log.Warn().
+			Str("transaction_id", transaction.ID.String()).
+			Str("booking_id", transaction.BookingID.String()).
+			Str("issue_type", string(issue.IssueType)).
+			Msg("Payment does not match its transaction, flagged for admin review")
+
+		if err := s.transactionRepo.FlagTransaction(ctx, issue); err != nil {
+			log.Error().Err(err).Msg("Failed to flag transaction")
+			return nil, ginext.NewInternalServerError("failed to flag transaction")
+		}
+		return issue, nil
+	}
+
+	// Update transaction status
+	previousStatus := transaction.Status
+	transaction.Status = payment.Status
+	transaction.Reference = payment.Reference
+	if payment.PaidAt != nil {
+		transTimeUnix := payment.PaidAt.Unix()
 		transaction.TransactionTime = &transTimeUnix
 	}
+	switch {
+	case payment.AmountPaid > 0:
+		transaction.AmountPaid = transaction.WalletAmount + payment.AmountPaid
+	case payment.Status == model.TransactionStatusPaid:
+		transaction.AmountPaid = transaction.Amount
+	}
 
-	// Update in database
-	if err := s.transactionRepo.UpdateTransaction(ctx, transaction); err != nil {
+	// Notify booking service through the outbox, so the update survives a booking service outage
+	event, err := newPaymentStatusChangedEvent(transaction)
+	if err != nil {
+		return nil, ginext.NewInternalServerError(err.Error())
+	}
+
+	settlement, err := newSettlementEvents(transaction, previousStatus)
+	if err != nil {
+		return nil, ginext.NewInternalServerError(err.Error())
+	}
+	events := append([]*outbox.Event{event}, settlement...)
+
+	err = s.transactionRepo.UpdateTransactionWithEvents(ctx, transaction, previousStatus, events...)
+	if errors.Is(err, repository.ErrTransactionStatusChanged) {
+		// A concurrent delivery of the same payment applied it first
+		log.Info().
+			Str("transaction_id", transaction.ID.String()).
+			Str("status", string(payment.Status)).
+			Msg("Payment already applied by another delivery")
+		return nil, nil
+	}
+	if err != nil {
 		log.Error().Err(err).Msg("Failed to update transaction")
-		return ginext.NewInternalServerError("failed to update transaction")
+		return nil, ginext.NewInternalServerError("failed to update transaction")
 	}
 
-	// Notify booking service about payment success
-	if err := s.bookingClient.UpdateBookingStatus(ctx, &booking.UpdateBookingStatusRequest{
+	if transaction.Status == model.TransactionStatusUnderpaid {
+		log.Info().
+			Str("transaction_id", transaction.ID.String()).
+			Int("amount", transaction.Amount).
+			Int("amount_paid", transaction.AmountPaid).
+			Msg("Payment underpaid, waiting for the remaining amount")
+	}
+
+	return nil, nil
+}
+
+func newPaymentStatusChangedEvent(transaction *model.Transaction) (*outbox.Event, error) {
+	return outbox.NewEvent(model.AggregateTypeTransaction, transaction.ID, model.EventTypePaymentStatusChanged, &model.PaymentStatusChangedEvent{
+		BookingID:         transaction.BookingID,
+		TransactionID:     transaction.ID,
 		TransactionStatus: transaction.Status,
-	}, transaction.BookingID); err != nil {
-		log.Error().Err(err).
-			Str("booking_id", transaction.BookingID.String()).
-			Msg("Failed to update booking payment status")
-		// Don't fail the webhook - payment is already recorded
-		// This can be retried via a background job
+		AmountPaid:        transaction.AmountPaid,
+	})
+}
+
+// newSettlementEvents queues the ledger posting of money received once a payment is final, and the
+// refund of what no booking keeps: the excess of an overpaid payment, or all of an underpaid payment
+// whose booking will not be paid in full. A paid booking also gets the VAT invoice asked for with it.
+// What a payment that is not completed drew from the wallet goes back to it.
+func newSettlementEvents(transaction *model.Transaction, previousStatus model.TransactionStatus) ([]*outbox.Event, error) {
+	var (
+		events  []*outbox.Event
+		surplus int
+		reason  string
+	)
+	switch transaction.Status {
+	case model.TransactionStatusPaid:
+		surplus = transaction.ExcessAmount()
+		reason = fmt.Sprintf("Hoàn tiền chuyển thừa của đơn hàng %d", transaction.OrderCode)
+	case model.TransactionStatusCancelled, model.TransactionStatusExpired, model.TransactionStatusFailed:
+		if transaction.WalletAmount > 0 {
+			release, err := outbox.NewEvent(model.AggregateTypeTransaction, transaction.ID, model.EventTypeWalletReleaseDue, &model.WalletReleaseDueEvent{
+				TransactionID: transaction.ID,
+			})
+			if err != nil {
+				return nil, err
+			}
+			events = append(events, release)
+		}
+		transferred := transaction.AmountPaid - transaction.WalletAmount
+		if previousStatus != model.TransactionStatusUnderpaid || transferred <= 0 {
+			return events, nil
+		}
+		surplus = transferred
+		reason = fmt.Sprintf("Hoàn tiền thanh toán thiếu của đơn hàng %d", transaction.OrderCode)
+	default:
+		return nil, nil
 	}
 
-	return nil
+	captured, err := newPaymentCapturedEvent(transaction)
+	if err != nil {
+		return nil, err
+	}
+	events = append(events, captured)
+
+	if surplus > 0 {
+		refund, err := outbox.NewEvent(model.AggregateTypeTransaction, transaction.ID, model.EventTypeSurplusRefundDue, &model.SurplusRefundDueEvent{
+			// Derived from the transaction, so the surplus is refunded once however often it is queued
+			RefundID:      uuid.NewSHA1(transaction.ID, []byte("surplus")),
+			TransactionID: transaction.ID,
+			Amount:        surplus,
+			Reason:        reason,
+		})
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, refund)
+	}
+	if transaction.Status == model.TransactionStatusPaid && transaction.InvoiceBuyer != nil {
+		invoice, err := newInvoiceDueEvent(transaction)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, invoice)
+	}
+	return events, nil
+}
+
+// detectMismatch returns the issue preventing a provider payment from confirming its booking, if any
+func (s *TransactionServiceImpl) detectMismatch(transaction *model.Transaction, payment *model.ProviderPayment) *model.ReconciliationIssue {
+	if payment.Status != model.TransactionStatusPaid {
+		return nil
+	}
+
+	var issueType model.ReconciliationIssueType
+	switch {
+	case transaction.Status == model.TransactionStatusExpired:
+		issueType = model.ReconciliationIssuePaidAfterExpired
+	case transaction.Status == model.TransactionStatusCancelled || transaction.Status == model.TransactionStatusFailed:
+		issueType = model.ReconciliationIssuePaidAfterCancelled
+	case transaction.ExpiresAt != nil && time.Now().After(transaction.ExpiresAt.Add(bookingExpirationGracePeriod)):
+		// Booking service may already have expired the booking and released its seats
+		issueType = model.ReconciliationIssuePaidAfterExpired
+	default:
+		return nil
+	}
+
+	return &model.ReconciliationIssue{
+		TransactionID:     transaction.ID,
+		BookingID:         transaction.BookingID,
+		IssueType:         issueType,
+		LocalStatus:       transaction.Status,
+		ProviderStatus:    payment.Status,
+		Amount:            transaction.Amount,
+		ProviderAmount:    payment.Amount,
+		ProviderReference: payment.Reference,
+		ProviderPaidAt:    payment.PaidAt,
+		Status:            model.ReconciliationIssueStatusOpen,
+	}
+}
+
+// DeliverPaymentStatusChanged is the outbox handler pushing a payment status change to booking service
+func (s *TransactionServiceImpl) DeliverPaymentStatusChanged(ctx context.Context, event *outbox.Event) error {
+	var payload model.PaymentStatusChangedEvent
+	if err := event.Decode(&payload); err != nil {
+		return outbox.Permanent(err)
+	}
+
+	return s.bookingClient.UpdateBookingStatus(ctx, &booking.UpdateBookingStatusRequest{
+		TransactionID:     payload.TransactionID,
+		TransactionStatus: payload.TransactionStatus,
+		AmountPaid:        payload.AmountPaid,
+	}, payload.BookingID)
 }
+
 func (s *TransactionServiceImpl) GetByID(ctx context.Context, id uuid.UUID) (*model.TransactionResponse, error) {
 	transaction, err := s.transactionRepo.GetByID(ctx, id)
 	if err != nil {
@@ -203,26 +596,40 @@ func (s *TransactionServiceImpl) Cancel(ctx context.Context, transactionID uuid.
 		return nil, ginext.NewBadRequestError("cannot cancel a paid transaction")
 	}
 
-	// Cancel PayOS payment link
-	reason := "Booking cancelled by user"
-	paymentLink, err := s.payOSService.CancelPaymentLink(ctx, transaction.PaymentLinkID, &reason)
+	// Cancel the payment at its provider
+	var payment *model.ProviderPayment
+	provider, err := s.providers.Get(transaction.PaymentMethod)
+	if err == nil {
+		payment, err = provider.CancelPayment(ctx, transaction.PaymentLinkID, "Booking cancelled by user")
+	}
 	if err != nil {
 		log.Error().Err(err).
 			Str("transaction_id", transactionID.String()).
 			Str("payment_link_id", transaction.PaymentLinkID).
-			Msg("Failed to cancel PayOS payment link")
-		// Continue to update local status even if PayOS call fails
+			Msg("Failed to cancel provider payment")
+		// Continue to update local status even if the provider call fails
 	}
 
 	// Update transaction status
-	if paymentLink != nil {
-		transaction.Status = s.payOSService.ToTransactionStatus(paymentLink.Status)
+	previousStatus := transaction.Status
+	if payment != nil {
+		transaction.Status = payment.Status
 	} else {
-		// If PayOS call failed, mark as cancelled locally
+		// If the provider call failed, mark as cancelled locally
 		transaction.Status = model.TransactionStatusCancelled
 	}
 
-	if err := s.transactionRepo.UpdateTransaction(ctx, transaction); err != nil {
+	// Money already received for an underpaid booking is refunded
+	events, err := newSettlementEvents(transaction, previousStatus)
+	if err != nil {
+		return nil, ginext.NewInternalServerError(err.Error())
+	}
+
+	err = s.transactionRepo.UpdateTransactionWithEvents(ctx, transaction, previousStatus, events...)
+	if errors.Is(err, repository.ErrTransactionStatusChanged) {
+		return nil, ginext.NewConflictError("transaction status changed, please try again")
+	}
+	if err != nil {
 		log.Error().Err(err).Msg("Failed to update transaction status")
 		return nil, ginext.NewInternalServerError("failed to update transaction")
 	}
@@ -243,6 +650,8 @@ func (s *TransactionServiceImpl) toTransactionResponse(t *model.Transaction) *mo
 		BookingID:       t.BookingID,
 		UserID:          t.UserID,
 		Amount:          t.Amount,
+		AmountPaid:      t.AmountPaid,
+		WalletAmount:    t.WalletAmount,
 		Currency:        t.Currency,
 		PaymentMethod:   t.PaymentMethod,
 		OrderCode:       t.OrderCode,
@@ -252,5 +661,12 @@ func (s *TransactionServiceImpl) toTransactionResponse(t *model.Transaction) *mo
 		TransactionType: t.TransactionType,
 		RefundStatus:    t.RefundStatus,
 		RefundAmount:    t.RefundAmount,
+		RefundedAmount:  t.RefundedAmount,
+		RefundID:        t.RefundID,
+		OperatorID:      t.OperatorID,
+		InvoiceBuyer:    t.InvoiceBuyer,
+		LegBookingIDs:   t.LegBookingIDs,
+
+		OriginalTransactionID: t.OriginalTransactionID,
 	}
 }
@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"net/http"
 	"testing"
 	"time"
 
@@ -9,10 +10,11 @@ import (
 	"bus-booking/payment-service/internal/model"
 	repo_mocks "bus-booking/payment-service/internal/repository/mocks"
 	service_mocks "bus-booking/payment-service/internal/service/mocks"
+	"bus-booking/shared/ginext"
+	"bus-booking/shared/outbox"
 
 	"github.com/golang/mock/gomock"
 	"github.com/google/uuid"
-	"github.com/payOSHQ/payos-lib-golang/v2"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -24,7 +26,7 @@ func TestNewTransactionService(t *testing.T) {
 	mockBookingClient := client_mocks.NewMockBookingClient(ctrl)
 	mockPayOSService := service_mocks.NewMockPayOSService(ctrl)
 
-	service := NewTransactionService(mockTransactionRepo, mockBookingClient, mockPayOSService)
+	service := NewTransactionService(mockTransactionRepo, repo_mocks.NewMockWalletRepository(ctrl), mockBookingClient, PaymentProviders{model.PaymentMethodPayOS: mockPayOSService})
 
 	assert.NotNil(t, service)
 	assert.IsType(t, &TransactionServiceImpl{}, service)
@@ -38,7 +40,7 @@ func TestGetByID_Success(t *testing.T) {
 	mockBookingClient := client_mocks.NewMockBookingClient(ctrl)
 	mockPayOSService := service_mocks.NewMockPayOSService(ctrl)
 
-	service := NewTransactionService(mockTransactionRepo, mockBookingClient, mockPayOSService)
+	service := NewTransactionService(mockTransactionRepo, repo_mocks.NewMockWalletRepository(ctrl), mockBookingClient, PaymentProviders{model.PaymentMethodPayOS: mockPayOSService})
 
 	ctx := context.Background()
 	transactionID := uuid.New()
@@ -74,7 +76,7 @@ func TestGetByID_NotFound(t *testing.T) {
 	mockBookingClient := client_mocks.NewMockBookingClient(ctrl)
 	mockPayOSService := service_mocks.NewMockPayOSService(ctrl)
 
-	service := NewTransactionService(mockTransactionRepo, mockBookingClient, mockPayOSService)
+	service := NewTransactionService(mockTransactionRepo, repo_mocks.NewMockWalletRepository(ctrl), mockBookingClient, PaymentProviders{model.PaymentMethodPayOS: mockPayOSService})
 
 	ctx := context.Background()
 	transactionID := uuid.New()
@@ -98,7 +100,7 @@ func TestGetByBookingID_Success(t *testing.T) {
 	mockBookingClient := client_mocks.NewMockBookingClient(ctrl)
 	mockPayOSService := service_mocks.NewMockPayOSService(ctrl)
 
-	service := NewTransactionService(mockTransactionRepo, mockBookingClient, mockPayOSService)
+	service := NewTransactionService(mockTransactionRepo, repo_mocks.NewMockWalletRepository(ctrl), mockBookingClient, PaymentProviders{model.PaymentMethodPayOS: mockPayOSService})
 
 	ctx := context.Background()
 	bookingID := uuid.New()
@@ -133,7 +135,7 @@ func TestGetByBookingID_NotFound(t *testing.T) {
 	mockBookingClient := client_mocks.NewMockBookingClient(ctrl)
 	mockPayOSService := service_mocks.NewMockPayOSService(ctrl)
 
-	service := NewTransactionService(mockTransactionRepo, mockBookingClient, mockPayOSService)
+	service := NewTransactionService(mockTransactionRepo, repo_mocks.NewMockWalletRepository(ctrl), mockBookingClient, PaymentProviders{model.PaymentMethodPayOS: mockPayOSService})
 
 	ctx := context.Background()
 	bookingID := uuid.New()
@@ -157,7 +159,7 @@ func TestGetList_Success(t *testing.T) {
 	mockBookingClient := client_mocks.NewMockBookingClient(ctrl)
 	mockPayOSService := service_mocks.NewMockPayOSService(ctrl)
 
-	service := NewTransactionService(mockTransactionRepo, mockBookingClient, mockPayOSService)
+	service := NewTransactionService(mockTransactionRepo, repo_mocks.NewMockWalletRepository(ctrl), mockBookingClient, PaymentProviders{model.PaymentMethodPayOS: mockPayOSService})
 
 	ctx := context.Background()
 
@@ -211,7 +213,7 @@ func TestGetList_Empty(t *testing.T) {
 	mockBookingClient := client_mocks.NewMockBookingClient(ctrl)
 	mockPayOSService := service_mocks.NewMockPayOSService(ctrl)
 
-	service := NewTransactionService(mockTransactionRepo, mockBookingClient, mockPayOSService)
+	service := NewTransactionService(mockTransactionRepo, repo_mocks.NewMockWalletRepository(ctrl), mockBookingClient, PaymentProviders{model.PaymentMethodPayOS: mockPayOSService})
 
 	ctx := context.Background()
 
@@ -242,7 +244,7 @@ func TestGetStats_Success(t *testing.T) {
 	mockBookingClient := client_mocks.NewMockBookingClient(ctrl)
 	mockPayOSService := service_mocks.NewMockPayOSService(ctrl)
 
-	service := NewTransactionService(mockTransactionRepo, mockBookingClient, mockPayOSService)
+	service := NewTransactionService(mockTransactionRepo, repo_mocks.NewMockWalletRepository(ctrl), mockBookingClient, PaymentProviders{model.PaymentMethodPayOS: mockPayOSService})
 
 	ctx := context.Background()
 
@@ -278,7 +280,7 @@ func TestGetStats_Error(t *testing.T) {
 	mockBookingClient := client_mocks.NewMockBookingClient(ctrl)
 	mockPayOSService := service_mocks.NewMockPayOSService(ctrl)
 
-	service := NewTransactionService(mockTransactionRepo, mockBookingClient, mockPayOSService)
+	service := NewTransactionService(mockTransactionRepo, repo_mocks.NewMockWalletRepository(ctrl), mockBookingClient, PaymentProviders{model.PaymentMethodPayOS: mockPayOSService})
 
 	ctx := context.Background()
 
@@ -301,7 +303,7 @@ func TestCancel_Success(t *testing.T) {
 	mockBookingClient := client_mocks.NewMockBookingClient(ctrl)
 	mockPayOSService := service_mocks.NewMockPayOSService(ctrl)
 
-	service := NewTransactionService(mockTransactionRepo, mockBookingClient, mockPayOSService)
+	service := NewTransactionService(mockTransactionRepo, repo_mocks.NewMockWalletRepository(ctrl), mockBookingClient, PaymentProviders{model.PaymentMethodPayOS: mockPayOSService})
 
 	ctx := context.Background()
 	transactionID := uuid.New()
@@ -317,30 +319,21 @@ func TestCancel_Success(t *testing.T) {
 		PaymentLinkID: paymentLinkID,
 	}
 
-	cancellationReason := "Booking cancelled by user"
-	paymentLink := &payos.PaymentLink{
-		Status: payos.PaymentLinkStatusCancelled,
-	}
-
 	mockTransactionRepo.EXPECT().
 		GetByID(ctx, transactionID).
 		Return(transaction, nil).
 		Times(1)
 
 	mockPayOSService.EXPECT().
-		CancelPaymentLink(ctx, paymentLinkID, &cancellationReason).
-		Return(paymentLink, nil).
-		Times(1)
-
-	mockPayOSService.EXPECT().
-		ToTransactionStatus(payos.PaymentLinkStatusCancelled).
-		Return(model.TransactionStatusCancelled).
+		CancelPayment(ctx, paymentLinkID, "Booking cancelled by user").
+		Return(&model.ProviderPayment{Status: model.TransactionStatusCancelled}, nil).
 		Times(1)
 
 	mockTransactionRepo.EXPECT().
-		UpdateTransaction(ctx, gomock.Any()).
-		DoAndReturn(func(ctx context.Context, tx *model.Transaction) error {
+		UpdateTransactionWithEvents(ctx, gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, tx *model.Transaction, _ model.TransactionStatus, events ...*outbox.Event) error {
 			assert.Equal(t, model.TransactionStatusCancelled, tx.Status)
+			assert.Empty(t, events)
 			return nil
 		}).
 		Times(1)
@@ -360,7 +353,7 @@ func TestCancel_AlreadyPaid(t *testing.T) {
 	mockBookingClient := client_mocks.NewMockBookingClient(ctrl)
 	mockPayOSService := service_mocks.NewMockPayOSService(ctrl)
 
-	service := NewTransactionService(mockTransactionRepo, mockBookingClient, mockPayOSService)
+	service := NewTransactionService(mockTransactionRepo, repo_mocks.NewMockWalletRepository(ctrl), mockBookingClient, PaymentProviders{model.PaymentMethodPayOS: mockPayOSService})
 
 	ctx := context.Background()
 	transactionID := uuid.New()
@@ -394,7 +387,7 @@ func TestCancel_TransactionNotFound(t *testing.T) {
 	mockBookingClient := client_mocks.NewMockBookingClient(ctrl)
 	mockPayOSService := service_mocks.NewMockPayOSService(ctrl)
 
-	service := NewTransactionService(mockTransactionRepo, mockBookingClient, mockPayOSService)
+	service := NewTransactionService(mockTransactionRepo, repo_mocks.NewMockWalletRepository(ctrl), mockBookingClient, PaymentProviders{model.PaymentMethodPayOS: mockPayOSService})
 
 	ctx := context.Background()
 	transactionID := uuid.New()
@@ -418,7 +411,7 @@ func TestCreate_Success(t *testing.T) {
 	mockBookingClient := client_mocks.NewMockBookingClient(ctrl)
 	mockPayOSService := service_mocks.NewMockPayOSService(ctrl)
 
-	service := NewTransactionService(mockTransactionRepo, mockBookingClient, mockPayOSService)
+	service := NewTransactionService(mockTransactionRepo, repo_mocks.NewMockWalletRepository(ctrl), mockBookingClient, PaymentProviders{model.PaymentMethodPayOS: mockPayOSService})
 
 	ctx := context.Background()
 	userID := uuid.New()
@@ -435,22 +428,17 @@ func TestCreate_Success(t *testing.T) {
 		ExpiresAt:     time.Now().Add(15 * time.Minute),
 	}
 
-	payosResponse := &payos.CreatePaymentLinkResponse{
+	payment := &model.ProviderPayment{
 		OrderCode:     123456,
-		PaymentLinkId: "payos-payment-link-123",
-		CheckoutUrl:   "https://checkout.url",
-		QrCode:        "qr-code-data",
-		Status:        payos.PaymentLinkStatusPending,
+		PaymentLinkID: "payos-payment-link-123",
+		CheckoutURL:   "https://checkout.url",
+		QRCode:        "qr-code-data",
+		Status:        model.TransactionStatusPending,
 	}
 
 	mockPayOSService.EXPECT().
-		CreatePaymentLink(ctx, gomock.Any()).
-		Return(payosResponse, nil).
-		Times(1)
-
-	mockPayOSService.EXPECT().
-		ToTransactionStatus(payos.PaymentLinkStatusPending).
-		Return(model.TransactionStatusPending).
+		CreatePayment(ctx, gomock.Any()).
+		Return(payment, nil).
 		Times(1)
 
 	mockTransactionRepo.EXPECT().
@@ -475,6 +463,88 @@ func TestCreate_Success(t *testing.T) {
 	assert.Equal(t, "https://checkout.url", result.CheckoutURL)
 }
 
+func TestCreate_ExchangeDifferenceLinkedToOriginalPayment(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTransactionRepo := repo_mocks.NewMockTransactionRepository(ctrl)
+	mockPayOSService := service_mocks.NewMockPayOSService(ctrl)
+
+	service := NewTransactionService(mockTransactionRepo, repo_mocks.NewMockWalletRepository(ctrl), client_mocks.NewMockBookingClient(ctrl), PaymentProviders{model.PaymentMethodPayOS: mockPayOSService})
+
+	ctx := context.Background()
+	userID := uuid.New()
+	bookingID := uuid.New()
+	original := &model.Transaction{
+		BaseModel:       model.BaseModel{ID: uuid.New()},
+		BookingID:       bookingID,
+		UserID:          userID,
+		Amount:          300000,
+		Status:          model.TransactionStatusPaid,
+		TransactionType: model.TransactionTypeIn,
+	}
+
+	req := &model.CreateTransactionRequest{
+		ID:                    uuid.New(),
+		BookingID:             bookingID,
+		Amount:                100000,
+		Currency:              model.CurrencyVND,
+		PaymentMethod:         model.PaymentMethodPayOS,
+		Description:           "Doi ve BK123",
+		ExpiresAt:             time.Now().Add(15 * time.Minute),
+		OriginalTransactionID: &original.ID,
+	}
+
+	mockTransactionRepo.EXPECT().GetByID(ctx, original.ID).Return(original, nil)
+	mockPayOSService.EXPECT().
+		CreatePayment(ctx, gomock.Any()).
+		Return(&model.ProviderPayment{OrderCode: 123457, PaymentLinkID: "payos-payment-link-124", Status: model.TransactionStatusPending}, nil)
+	mockTransactionRepo.EXPECT().
+		CreateTransaction(ctx, gomock.Any()).
+		DoAndReturn(func(ctx context.Context, tx *model.Transaction) error {
+			assert.Equal(t, &original.ID, tx.OriginalTransactionID)
+			assert.Equal(t, 100000, tx.Amount)
+			return nil
+		})
+
+	result, err := service.Create(ctx, req, userID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, &original.ID, result.OriginalTransactionID)
+}
+
+func TestCreate_ExchangeDifferenceOfAnotherBooking(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTransactionRepo := repo_mocks.NewMockTransactionRepository(ctrl)
+	service := NewTransactionService(mockTransactionRepo, repo_mocks.NewMockWalletRepository(ctrl), client_mocks.NewMockBookingClient(ctrl), PaymentProviders{})
+
+	ctx := context.Background()
+	original := &model.Transaction{
+		BaseModel:       model.BaseModel{ID: uuid.New()},
+		BookingID:       uuid.New(),
+		Status:          model.TransactionStatusPaid,
+		TransactionType: model.TransactionTypeIn,
+	}
+
+	mockTransactionRepo.EXPECT().GetByID(ctx, original.ID).Return(original, nil)
+	mockTransactionRepo.EXPECT().CreateTransaction(gomock.Any(), gomock.Any()).Times(0)
+
+	result, err := service.Create(ctx, &model.CreateTransactionRequest{
+		BookingID:             uuid.New(),
+		Amount:                100000,
+		Currency:              model.CurrencyVND,
+		PaymentMethod:         model.PaymentMethodPayOS,
+		OriginalTransactionID: &original.ID,
+	}, uuid.New())
+
+	assert.Nil(t, result)
+	var apiErr *ginext.Error
+	assert.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusBadRequest, apiErr.Code)
+}
+
 func TestCreate_PayOSError(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -483,21 +553,22 @@ func TestCreate_PayOSError(t *testing.T) {
 	mockBookingClient := client_mocks.NewMockBookingClient(ctrl)
 	mockPayOSService := service_mocks.NewMockPayOSService(ctrl)
 
-	service := NewTransactionService(mockTransactionRepo, mockBookingClient, mockPayOSService)
+	service := NewTransactionService(mockTransactionRepo, repo_mocks.NewMockWalletRepository(ctrl), mockBookingClient, PaymentProviders{model.PaymentMethodPayOS: mockPayOSService})
 
 	ctx := context.Background()
 	userID := uuid.New()
 
 	req := &model.CreateTransactionRequest{
-		ID:          uuid.New(),
-		BookingID:   uuid.New(),
-		Amount:      100000,
-		Description: "Test payment",
-		ExpiresAt:   time.Now().Add(15 * time.Minute),
+		ID:            uuid.New(),
+		BookingID:     uuid.New(),
+		Amount:        100000,
+		PaymentMethod: model.PaymentMethodPayOS,
+		Description:   "Test payment",
+		ExpiresAt:     time.Now().Add(15 * time.Minute),
 	}
 
 	mockPayOSService.EXPECT().
-		CreatePaymentLink(ctx, gomock.Any()).
+		CreatePayment(ctx, gomock.Any()).
 		Return(nil, assert.AnError).
 		Times(1)
 
@@ -507,3 +578,121 @@ func TestCreate_PayOSError(t *testing.T) {
 	assert.Nil(t, result)
 	assert.Contains(t, err.Error(), "failed to create payment link")
 }
+
+func newCashTransaction() *model.Transaction {
+	return &model.Transaction{
+		BaseModel:     model.BaseModel{ID: uuid.New()},
+		BookingID:     uuid.New(),
+		UserID:        uuid.New(),
+		Amount:        150000,
+		Status:        model.TransactionStatusPending,
+		PaymentMethod: model.PaymentMethodCash,
+		OrderCode:     123456,
+	}
+}
+
+func TestConfirmCashPayment_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTransactionRepo := repo_mocks.NewMockTransactionRepository(ctrl)
+	mockBookingClient := client_mocks.NewMockBookingClient(ctrl)
+
+	service := NewTransactionService(mockTransactionRepo, repo_mocks.NewMockWalletRepository(ctrl), mockBookingClient, PaymentProviders{model.PaymentMethodCash: NewCashProvider()})
+
+	ctx := context.Background()
+	transaction := newCashTransaction()
+
+	mockTransactionRepo.EXPECT().
+		GetByID(ctx, transaction.ID).
+		Return(transaction, nil).
+		Times(1)
+
+	mockTransactionRepo.EXPECT().
+		UpdateTransactionWithEvents(ctx, transaction, gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, tx *model.Transaction, _ model.TransactionStatus, events ...*outbox.Event) error {
+			assert.Equal(t, model.TransactionStatusPaid, tx.Status)
+			assert.Equal(t, "RC-0001", tx.Reference)
+			assert.NotNil(t, tx.TransactionTime)
+
+			assert.Len(t, events, 2)
+			assert.Equal(t, model.EventTypePaymentCaptured, events[1].EventType)
+			var payload model.PaymentStatusChangedEvent
+			assert.NoError(t, events[0].Decode(&payload))
+			assert.Equal(t, transaction.BookingID, payload.BookingID)
+			assert.Equal(t, model.TransactionStatusPaid, payload.TransactionStatus)
+			return nil
+		}).
+		Times(1)
+
+	result, err := service.ConfirmCashPayment(ctx, transaction.ID, &model.ConfirmCashPaymentRequest{ReceiptNumber: "RC-0001"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, model.TransactionStatusPaid, result.Status)
+}
+
+func TestConfirmCashPayment_NotCash(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTransactionRepo := repo_mocks.NewMockTransactionRepository(ctrl)
+	mockBookingClient := client_mocks.NewMockBookingClient(ctrl)
+
+	service := NewTransactionService(mockTransactionRepo, repo_mocks.NewMockWalletRepository(ctrl), mockBookingClient, PaymentProviders{model.PaymentMethodCash: NewCashProvider()})
+
+	ctx := context.Background()
+	transaction := newCashTransaction()
+	transaction.PaymentMethod = model.PaymentMethodPayOS
+
+	mockTransactionRepo.EXPECT().
+		GetByID(ctx, transaction.ID).
+		Return(transaction, nil).
+		Times(1)
+
+	result, err := service.ConfirmCashPayment(ctx, transaction.ID, &model.ConfirmCashPaymentRequest{ReceiptNumber: "RC-0001"})
+
+	assert.Error(t, err)
+	assert.Nil(t, result)
+	var apiErr *ginext.Error
+	assert.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusBadRequest, apiErr.Code)
+}
+
+func TestConfirmCashPayment_AfterBookingExpired(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTransactionRepo := repo_mocks.NewMockTransactionRepository(ctrl)
+	mockBookingClient := client_mocks.NewMockBookingClient(ctrl)
+
+	service := NewTransactionService(mockTransactionRepo, repo_mocks.NewMockWalletRepository(ctrl), mockBookingClient, PaymentProviders{model.PaymentMethodCash: NewCashProvider()})
+
+	ctx := context.Background()
+	transaction := newCashTransaction()
+	expiresAt := time.Now().Add(-time.Hour)
+	transaction.ExpiresAt = &expiresAt
+
+	mockTransactionRepo.EXPECT().
+		GetByID(ctx, transaction.ID).
+		Return(transaction, nil).
+		Times(1)
+
+	// The seats may have been released, an admin decides through the reconciliation issue
+	mockTransactionRepo.EXPECT().
+		FlagTransaction(ctx, gomock.Any()).
+		DoAndReturn(func(ctx context.Context, issue *model.ReconciliationIssue) error {
+			assert.Equal(t, model.ReconciliationIssuePaidAfterExpired, issue.IssueType)
+			assert.Equal(t, "RC-0001", issue.ProviderReference)
+			return nil
+		}).
+		Times(1)
+	mockTransactionRepo.EXPECT().UpdateTransactionWithEvents(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+	result, err := service.ConfirmCashPayment(ctx, transaction.ID, &model.ConfirmCashPaymentRequest{ReceiptNumber: "RC-0001"})
+
+	assert.Error(t, err)
+	assert.Nil(t, result)
+	var apiErr *ginext.Error
+	assert.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusConflict, apiErr.Code)
+}
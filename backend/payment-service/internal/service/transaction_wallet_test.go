@@ -0,0 +1,230 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	client_mocks "bus-booking/payment-service/internal/client/mocks"
+	"bus-booking/payment-service/internal/model"
+	"bus-booking/payment-service/internal/repository"
+	repo_mocks "bus-booking/payment-service/internal/repository/mocks"
+	service_mocks "bus-booking/payment-service/internal/service/mocks"
+	"bus-booking/shared/ginext"
+	"bus-booking/shared/outbox"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func newWalletTestService(t *testing.T) (TransactionService, *repo_mocks.MockTransactionRepository, *repo_mocks.MockWalletRepository, *service_mocks.MockPayOSService) {
+	ctrl := gomock.NewController(t)
+
+	mockTransactionRepo := repo_mocks.NewMockTransactionRepository(ctrl)
+	mockWalletRepo := repo_mocks.NewMockWalletRepository(ctrl)
+	mockPayOSService := service_mocks.NewMockPayOSService(ctrl)
+
+	service := NewTransactionService(mockTransactionRepo, mockWalletRepo, client_mocks.NewMockBookingClient(ctrl), PaymentProviders{model.PaymentMethodPayOS: mockPayOSService})
+	return service, mockTransactionRepo, mockWalletRepo, mockPayOSService
+}
+
+func newWalletTransactionRequest(amount int) *model.CreateTransactionRequest {
+	return &model.CreateTransactionRequest{
+		ID:            uuid.New(),
+		BookingID:     uuid.New(),
+		Amount:        amount,
+		Currency:      model.CurrencyVND,
+		PaymentMethod: model.PaymentMethodPayOS,
+		Description:   "Test payment",
+		ExpiresAt:     time.Now().Add(15 * time.Minute),
+		UseWallet:     true,
+	}
+}
+
+func TestCreate_WalletPaysInFull(t *testing.T) {
+	service, _, mockWalletRepo, _ := newWalletTestService(t)
+	ctx := context.Background()
+	userID := uuid.New()
+	req := newWalletTransactionRequest(100000)
+
+	mockWalletRepo.EXPECT().
+		ListAvailableCredits(ctx, userID, gomock.Any()).
+		Return([]*model.WalletEntry{{Remaining: 80000}, {Remaining: 50000}}, nil)
+	mockWalletRepo.EXPECT().
+		Pay(ctx, gomock.Any(), req.Description, gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, tx *model.Transaction, description string, events ...*outbox.Event) error {
+			assert.Equal(t, model.PaymentMethodWallet, tx.PaymentMethod)
+			assert.Equal(t, model.TransactionStatusPaid, tx.Status)
+			assert.Equal(t, 100000, tx.WalletAmount)
+			assert.Equal(t, 100000, tx.AmountPaid)
+			assert.NotZero(t, tx.OrderCode)
+
+			// Booking service hears of the payment and the ledger posts it, like any paid booking
+			assert.Equal(t, model.EventTypePaymentStatusChanged, events[0].EventType)
+			assert.Equal(t, model.EventTypePaymentCaptured, events[1].EventType)
+			return nil
+		})
+
+	result, err := service.Create(ctx, req, userID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, model.TransactionStatusPaid, result.Status)
+	assert.Equal(t, 100000, result.WalletAmount)
+	assert.Empty(t, result.CheckoutURL)
+}
+
+func TestCreate_WalletPaysPart(t *testing.T) {
+	service, _, mockWalletRepo, mockPayOSService := newWalletTestService(t)
+	ctx := context.Background()
+	userID := uuid.New()
+	req := newWalletTransactionRequest(100000)
+
+	mockWalletRepo.EXPECT().
+		ListAvailableCredits(ctx, userID, gomock.Any()).
+		Return([]*model.WalletEntry{{Remaining: 30000}}, nil)
+	mockPayOSService.EXPECT().
+		CreatePayment(ctx, gomock.Any()).
+		DoAndReturn(func(ctx context.Context, link *model.CreatePaymentLinkRequest) (*model.ProviderPayment, error) {
+			assert.Equal(t, 70000, link.Amount)
+			return &model.ProviderPayment{
+				OrderCode:     123456,
+				PaymentLinkID: "payos-payment-link-123",
+				CheckoutURL:   "https://checkout.url",
+				Status:        model.TransactionStatusPending,
+			}, nil
+		})
+	mockWalletRepo.EXPECT().
+		Pay(ctx, gomock.Any(), req.Description).
+		DoAndReturn(func(ctx context.Context, tx *model.Transaction, description string, events ...*outbox.Event) error {
+			assert.Equal(t, model.PaymentMethodPayOS, tx.PaymentMethod)
+			assert.Equal(t, model.TransactionStatusPending, tx.Status)
+			assert.Equal(t, 30000, tx.WalletAmount)
+			assert.Equal(t, 30000, tx.AmountPaid)
+			assert.Equal(t, "payos-payment-link-123", tx.PaymentLinkID)
+			return nil
+		})
+
+	result, err := service.Create(ctx, req, userID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 30000, result.WalletAmount)
+	assert.Equal(t, "https://checkout.url", result.CheckoutURL)
+}
+
+func TestCreate_WalletBalanceChangedCancelsPaymentLink(t *testing.T) {
+	service, _, mockWalletRepo, mockPayOSService := newWalletTestService(t)
+	ctx := context.Background()
+	userID := uuid.New()
+	req := newWalletTransactionRequest(100000)
+
+	mockWalletRepo.EXPECT().
+		ListAvailableCredits(ctx, userID, gomock.Any()).
+		Return([]*model.WalletEntry{{Remaining: 30000}}, nil)
+	mockPayOSService.EXPECT().
+		CreatePayment(ctx, gomock.Any()).
+		Return(&model.ProviderPayment{PaymentLinkID: "payos-payment-link-123", Status: model.TransactionStatusPending}, nil)
+	// Another payment spent the credit in the meantime
+	mockWalletRepo.EXPECT().
+		Pay(ctx, gomock.Any(), req.Description).
+		Return(repository.ErrInsufficientWalletBalance)
+	mockPayOSService.EXPECT().
+		CancelPayment(ctx, "payos-payment-link-123", gomock.Any()).
+		Return(&model.ProviderPayment{Status: model.TransactionStatusCancelled}, nil)
+
+	result, err := service.Create(ctx, req, userID)
+
+	assert.Nil(t, result)
+	var apiErr *ginext.Error
+	assert.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusConflict, apiErr.Code)
+}
+
+func TestCreate_EmptyWalletPaysWithProvider(t *testing.T) {
+	service, mockTransactionRepo, mockWalletRepo, mockPayOSService := newWalletTestService(t)
+	ctx := context.Background()
+	userID := uuid.New()
+	req := newWalletTransactionRequest(100000)
+
+	mockWalletRepo.EXPECT().
+		ListAvailableCredits(ctx, userID, gomock.Any()).
+		Return(nil, nil)
+	mockPayOSService.EXPECT().
+		CreatePayment(ctx, gomock.Any()).
+		Return(&model.ProviderPayment{PaymentLinkID: "payos-payment-link-123", Status: model.TransactionStatusPending}, nil)
+	mockTransactionRepo.EXPECT().
+		CreateTransaction(ctx, gomock.Any()).
+		Return(nil)
+
+	result, err := service.Create(ctx, req, userID)
+
+	assert.NoError(t, err)
+	assert.Zero(t, result.WalletAmount)
+}
+
+func TestApplyWebhook_ProviderPartCompletesWalletPayment(t *testing.T) {
+	service, mockTransactionRepo, mockPayOSService := newReconciliationTestService(t)
+	ctx := context.Background()
+
+	transaction := newPendingTransaction(time.Now().Add(10 * time.Minute))
+	transaction.WalletAmount = 50000
+	transaction.AmountPaid = 50000
+	webhook := &model.ProviderWebhook{OrderCode: transaction.OrderCode, PaymentLinkID: transaction.PaymentLinkID}
+
+	// The provider only knows the part the wallet did not pay
+	paid := paidPayment(transaction.ProviderAmount())
+
+	mockPayOSService.EXPECT().
+		GetPayment(gomock.Any(), transaction.PaymentLinkID).
+		Return(paid, nil)
+	mockTransactionRepo.EXPECT().
+		GetByWebhookData(gomock.Any(), int(transaction.OrderCode), transaction.PaymentLinkID).
+		Return(transaction, nil)
+	mockTransactionRepo.EXPECT().
+		UpdateTransactionWithEvents(ctx, gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, tx *model.Transaction, _ model.TransactionStatus, events ...*outbox.Event) error {
+			assert.Equal(t, model.TransactionStatusPaid, tx.Status)
+			assert.Equal(t, tx.Amount, tx.AmountPaid)
+
+			// Nothing was paid in excess
+			for _, event := range events {
+				assert.NotEqual(t, model.EventTypeSurplusRefundDue, event.EventType)
+			}
+			return nil
+		})
+
+	_, err := service.ApplyWebhook(ctx, model.PaymentMethodPayOS, webhook)
+
+	assert.NoError(t, err)
+}
+
+func TestCancel_ReleasesWalletPart(t *testing.T) {
+	service, mockTransactionRepo, mockPayOSService := newReconciliationTestService(t)
+	ctx := context.Background()
+
+	transaction := newPendingTransaction(time.Now())
+	transaction.WalletAmount = 50000
+	transaction.AmountPaid = 50000
+
+	mockTransactionRepo.EXPECT().GetByID(ctx, transaction.ID).Return(transaction, nil)
+	mockPayOSService.EXPECT().
+		CancelPayment(ctx, transaction.PaymentLinkID, gomock.Any()).
+		Return(&model.ProviderPayment{Status: model.TransactionStatusCancelled}, nil)
+	mockTransactionRepo.EXPECT().
+		UpdateTransactionWithEvents(ctx, gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, tx *model.Transaction, _ model.TransactionStatus, events ...*outbox.Event) error {
+			assert.Equal(t, model.TransactionStatusCancelled, tx.Status)
+
+			// Nothing was transferred, only the wallet part goes back
+			assert.Len(t, events, 1)
+			var payload model.WalletReleaseDueEvent
+			assert.NoError(t, events[0].Decode(&payload))
+			assert.Equal(t, transaction.ID, payload.TransactionID)
+			return nil
+		})
+
+	_, err := service.Cancel(ctx, transaction.ID)
+
+	assert.NoError(t, err)
+}
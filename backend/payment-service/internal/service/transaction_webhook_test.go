@@ -2,20 +2,24 @@ package service
 
 import (
 	"context"
+	"net/http"
 	"testing"
+	"time"
 
 	client_mocks "bus-booking/payment-service/internal/client/mocks"
 	"bus-booking/payment-service/internal/model"
+	"bus-booking/payment-service/internal/model/booking"
 	repo_mocks "bus-booking/payment-service/internal/repository/mocks"
 	service_mocks "bus-booking/payment-service/internal/service/mocks"
+	"bus-booking/shared/ginext"
+	"bus-booking/shared/outbox"
 
 	"github.com/golang/mock/gomock"
 	"github.com/google/uuid"
-	"github.com/payOSHQ/payos-lib-golang/v2"
 	"github.com/stretchr/testify/assert"
 )
 
-func TestHandleWebhook_Success(t *testing.T) {
+func TestApplyWebhook_Success(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
@@ -23,33 +27,13 @@ func TestHandleWebhook_Success(t *testing.T) {
 	mockBookingClient := client_mocks.NewMockBookingClient(ctrl)
 	mockPayOSService := service_mocks.NewMockPayOSService(ctrl)
 
-	service := NewTransactionService(mockTransactionRepo, mockBookingClient, mockPayOSService)
+	service := NewTransactionService(mockTransactionRepo, repo_mocks.NewMockWalletRepository(ctrl), mockBookingClient, PaymentProviders{model.PaymentMethodPayOS: mockPayOSService})
 
 	ctx := context.Background()
 	bookingID := uuid.New()
 	paymentLinkID := "payos-payment-link-123"
 	orderCode := 123456
-
-	webhookMap := map[string]interface{}{
-		"code": "00",
-		"desc": "success",
-		"data": map[string]interface{}{
-			"orderCode":     orderCode,
-			"paymentLinkId": paymentLinkID,
-		},
-	}
-
-	webhookData := model.PaymentWebhookData{
-		Code:    "00",
-		Desc:    "success",
-		Success: true,
-		Data: model.PaymentWebhookDetails{
-			OrderCode:           orderCode,
-			PaymentLinkID:       paymentLinkID,
-			Reference:           "REF123",
-			TransactionDateTime: "2024-01-15 10:30:00",
-		},
-	}
+	paidAt := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
 
 	transaction := &model.Transaction{
 		BaseModel:     model.BaseModel{ID: uuid.New()},
@@ -62,20 +46,18 @@ func TestHandleWebhook_Success(t *testing.T) {
 		OrderCode:     int64(orderCode),
 	}
 
-	paymentLink := &payos.PaymentLink{
-		Status: payos.PaymentLinkStatusPaid,
+	webhook := &model.ProviderWebhook{
+		EventID:       "123456:REF123",
+		OrderCode:     int64(orderCode),
+		PaymentLinkID: paymentLinkID,
+		Reference:     "REF123",
+		PaidAt:        &paidAt,
 	}
 
-	// Mock webhook verification
-	mockPayOSService.EXPECT().
-		VerifyWebhook(ctx, webhookMap).
-		Return(nil).
-		Times(1)
-
-	// Mock payment link retrieval
+	// Mock payment retrieval
 	mockPayOSService.EXPECT().
-		GetPaymentLink(gomock.Any(), paymentLinkID).
-		Return(paymentLink, nil).
+		GetPayment(gomock.Any(), paymentLinkID).
+		Return(&model.ProviderPayment{Status: model.TransactionStatusPaid}, nil).
 		Times(1)
 
 	// Mock transaction retrieval by webhook data
@@ -84,35 +66,57 @@ func TestHandleWebhook_Success(t *testing.T) {
 		Return(transaction, nil).
 		Times(1)
 
-	// Mock status conversion
-	mockPayOSService.EXPECT().
-		ToTransactionStatus(payos.PaymentLinkStatusPaid).
-		Return(model.TransactionStatusPaid).
-		Times(1)
-
-	// Mock transaction update
+	// Mock transaction update, queueing the booking notification in the same transaction
 	mockTransactionRepo.EXPECT().
-		UpdateTransaction(ctx, gomock.Any()).
-		DoAndReturn(func(ctx context.Context, tx *model.Transaction) error {
+		UpdateTransactionWithEvents(ctx, gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, tx *model.Transaction, _ model.TransactionStatus, events ...*outbox.Event) error {
 			assert.Equal(t, model.TransactionStatusPaid, tx.Status)
 			assert.Equal(t, "REF123", tx.Reference)
-			assert.NotNil(t, tx.TransactionTime)
+			assert.Equal(t, paidAt.Unix(), *tx.TransactionTime)
+
+			assert.Len(t, events, 2)
+			assert.Equal(t, model.EventTypePaymentStatusChanged, events[0].EventType)
+			assert.Equal(t, model.EventTypePaymentCaptured, events[1].EventType)
+			assert.Equal(t, tx.ID, events[0].AggregateID)
+
+			var payload model.PaymentStatusChangedEvent
+			assert.NoError(t, events[0].Decode(&payload))
+			assert.Equal(t, bookingID, payload.BookingID)
+			assert.Equal(t, model.TransactionStatusPaid, payload.TransactionStatus)
 			return nil
 		}).
 		Times(1)
 
-	// Mock booking status update
-	mockBookingClient.EXPECT().
-		UpdateBookingStatus(ctx, gomock.Any(), bookingID).
-		Return(nil).
-		Times(1)
+	// Booking service is only called by the outbox relay
+	mockBookingClient.EXPECT().UpdateBookingStatus(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
 
-	err := service.HandleWebhook(ctx, webhookMap, webhookData)
+	applied, err := service.ApplyWebhook(ctx, model.PaymentMethodPayOS, webhook)
 
 	assert.NoError(t, err)
+	assert.Equal(t, transaction, applied)
+}
+
+func TestApplyWebhook_UnsupportedMethod(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service := NewTransactionService(
+		repo_mocks.NewMockTransactionRepository(ctrl),
+		repo_mocks.NewMockWalletRepository(ctrl),
+		client_mocks.NewMockBookingClient(ctrl),
+		PaymentProviders{model.PaymentMethodPayOS: service_mocks.NewMockPayOSService(ctrl)},
+	)
+
+	// Sandbox payments are rejected when the sandbox provider is disabled
+	_, err := service.ApplyWebhook(context.Background(), model.PaymentMethodSandbox, &model.ProviderWebhook{})
+
+	assert.Error(t, err)
+	var apiErr *ginext.Error
+	assert.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusBadRequest, apiErr.Code)
 }
 
-func TestHandleWebhook_VerificationFailed(t *testing.T) {
+func TestApplyWebhook_TransactionNotFound(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
@@ -120,30 +124,29 @@ func TestHandleWebhook_VerificationFailed(t *testing.T) {
 	mockBookingClient := client_mocks.NewMockBookingClient(ctrl)
 	mockPayOSService := service_mocks.NewMockPayOSService(ctrl)
 
-	service := NewTransactionService(mockTransactionRepo, mockBookingClient, mockPayOSService)
+	service := NewTransactionService(mockTransactionRepo, repo_mocks.NewMockWalletRepository(ctrl), mockBookingClient, PaymentProviders{model.PaymentMethodPayOS: mockPayOSService})
 
 	ctx := context.Background()
-
-	webhookMap := map[string]interface{}{
-		"code": "00",
-	}
-
-	webhookData := model.PaymentWebhookData{
-		Code: "00",
-	}
+	paymentLinkID := "payos-payment-link-123"
+	orderCode := 123456
 
 	mockPayOSService.EXPECT().
-		VerifyWebhook(ctx, webhookMap).
-		Return(assert.AnError).
+		GetPayment(gomock.Any(), paymentLinkID).
+		Return(&model.ProviderPayment{Status: model.TransactionStatusPaid}, nil).
+		Times(1)
+
+	mockTransactionRepo.EXPECT().
+		GetByWebhookData(gomock.Any(), orderCode, paymentLinkID).
+		Return(nil, assert.AnError).
 		Times(1)
 
-	err := service.HandleWebhook(ctx, webhookMap, webhookData)
+	transaction, err := service.ApplyWebhook(ctx, model.PaymentMethodPayOS, &model.ProviderWebhook{OrderCode: int64(orderCode), PaymentLinkID: paymentLinkID})
 
 	assert.Error(t, err)
-	assert.Contains(t, err.Error(), "invalid webhook signature")
+	assert.Nil(t, transaction)
 }
 
-func TestHandleWebhook_TransactionNotFound(t *testing.T) {
+func TestDeliverPaymentStatusChanged_Success(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
@@ -151,44 +154,69 @@ func TestHandleWebhook_TransactionNotFound(t *testing.T) {
 	mockBookingClient := client_mocks.NewMockBookingClient(ctrl)
 	mockPayOSService := service_mocks.NewMockPayOSService(ctrl)
 
-	service := NewTransactionService(mockTransactionRepo, mockBookingClient, mockPayOSService)
+	service := NewTransactionService(mockTransactionRepo, repo_mocks.NewMockWalletRepository(ctrl), mockBookingClient, PaymentProviders{model.PaymentMethodPayOS: mockPayOSService})
 
 	ctx := context.Background()
-	paymentLinkID := "payos-payment-link-123"
-	orderCode := 123456
-
-	webhookMap := map[string]interface{}{
-		"code": "00",
-	}
-
-	webhookData := model.PaymentWebhookData{
-		Code: "00",
-		Data: model.PaymentWebhookDetails{
-			OrderCode:     orderCode,
-			PaymentLinkID: paymentLinkID,
-		},
-	}
+	bookingID := uuid.New()
+	transactionID := uuid.New()
 
-	paymentLink := &payos.PaymentLink{
-		Status: payos.PaymentLinkStatusPaid,
-	}
+	event, err := outbox.NewEvent(model.AggregateTypeTransaction, transactionID, model.EventTypePaymentStatusChanged, &model.PaymentStatusChangedEvent{
+		BookingID:         bookingID,
+		TransactionID:     transactionID,
+		TransactionStatus: model.TransactionStatusPaid,
+	})
+	assert.NoError(t, err)
 
-	mockPayOSService.EXPECT().
-		VerifyWebhook(ctx, webhookMap).
+	mockBookingClient.EXPECT().
+		UpdateBookingStatus(ctx, &booking.UpdateBookingStatusRequest{
+			TransactionID:     transactionID,
+			TransactionStatus: model.TransactionStatusPaid,
+		}, bookingID).
 		Return(nil).
 		Times(1)
 
-	mockPayOSService.EXPECT().
-		GetPaymentLink(gomock.Any(), paymentLinkID).
-		Return(paymentLink, nil).
-		Times(1)
+	assert.NoError(t, service.DeliverPaymentStatusChanged(ctx, event))
+}
 
-	mockTransactionRepo.EXPECT().
-		GetByWebhookData(gomock.Any(), orderCode, paymentLinkID).
-		Return(nil, assert.AnError).
+func TestDeliverPaymentStatusChanged_BookingServiceDown(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTransactionRepo := repo_mocks.NewMockTransactionRepository(ctrl)
+	mockBookingClient := client_mocks.NewMockBookingClient(ctrl)
+	mockPayOSService := service_mocks.NewMockPayOSService(ctrl)
+
+	service := NewTransactionService(mockTransactionRepo, repo_mocks.NewMockWalletRepository(ctrl), mockBookingClient, PaymentProviders{model.PaymentMethodPayOS: mockPayOSService})
+
+	ctx := context.Background()
+	event, err := outbox.NewEvent(model.AggregateTypeTransaction, uuid.New(), model.EventTypePaymentStatusChanged, &model.PaymentStatusChangedEvent{
+		BookingID:         uuid.New(),
+		TransactionID:     uuid.New(),
+		TransactionStatus: model.TransactionStatusPaid,
+	})
+	assert.NoError(t, err)
+
+	mockBookingClient.EXPECT().
+		UpdateBookingStatus(ctx, gomock.Any(), gomock.Any()).
+		Return(assert.AnError).
 		Times(1)
 
-	err := service.HandleWebhook(ctx, webhookMap, webhookData)
+	// The error is returned so the relay retries the event
+	assert.ErrorIs(t, service.DeliverPaymentStatusChanged(ctx, event), assert.AnError)
+}
 
-	assert.Error(t, err)
+func TestDeliverPaymentStatusChanged_InvalidPayload(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service := NewTransactionService(
+		repo_mocks.NewMockTransactionRepository(ctrl),
+		repo_mocks.NewMockWalletRepository(ctrl),
+		client_mocks.NewMockBookingClient(ctrl),
+		PaymentProviders{model.PaymentMethodPayOS: service_mocks.NewMockPayOSService(ctrl)},
+	)
+
+	event := &outbox.Event{ID: uuid.New(), EventType: model.EventTypePaymentStatusChanged, Payload: "not json"}
+
+	assert.Error(t, service.DeliverPaymentStatusChanged(context.Background(), event))
 }
@@ -0,0 +1,38 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCRC16CCITT(t *testing.T) {
+	assert.Equal(t, uint16(0x29B1), crc16CCITT("123456789"))
+}
+
+func TestBuildVietQRPayload(t *testing.T) {
+	payload, err := buildVietQRPayload("970436", "0123456789", 150000, "HOAN TIEN ABCD1234")
+
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(payload, "000201010212"))
+	assert.Contains(t, payload, "38540010A00000072701240006970436011001234567890208QRIBFTTA")
+	assert.Contains(t, payload, "5303704")
+	assert.Contains(t, payload, "5406150000")
+	assert.Contains(t, payload, "5802VN")
+	assert.Contains(t, payload, "62220818HOAN TIEN ABCD1234")
+
+	// The last four characters are the checksum of everything before them
+	body, crc := payload[:len(payload)-4], payload[len(payload)-4:]
+	assert.True(t, strings.HasSuffix(body, "6304"))
+	assert.Equal(t, fmt.Sprintf("%04X", crc16CCITT(body)), crc)
+}
+
+func TestBuildVietQRPayload_InvalidInput(t *testing.T) {
+	_, err := buildVietQRPayload("", "0123456789", 150000, "")
+	assert.Error(t, err)
+
+	_, err = buildVietQRPayload("970436", "0123456789", 0, "")
+	assert.Error(t, err)
+}
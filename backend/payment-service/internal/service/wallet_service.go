@@ -0,0 +1,183 @@
+package service
+
+import (
+	"bus-booking/payment-service/config"
+	"bus-booking/payment-service/internal/model"
+	"bus-booking/payment-service/internal/repository"
+	"bus-booking/shared/ginext"
+	"bus-booking/shared/outbox"
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+type WalletService interface {
+	GetWallet(ctx context.Context, userID uuid.UUID) (*model.WalletResponse, error)
+	ListEntries(ctx context.Context, userID uuid.UUID, query *model.WalletEntryListQuery) ([]*model.WalletEntry, int64, error)
+	GrantPromotionalCredit(ctx context.Context, userID uuid.UUID, req *model.GrantPromotionalCreditRequest, adminID uuid.UUID) (*model.WalletEntry, error)
+	ExpireCredits(ctx context.Context, limit int) (*model.WalletExpirySummary, error)
+	ReleasePayment(ctx context.Context, event *outbox.Event) error
+}
+
+type WalletServiceImpl struct {
+	walletRepo repository.WalletRepository
+	cfg        config.WalletConfig
+}
+
+func NewWalletService(walletRepo repository.WalletRepository, cfg config.WalletConfig) WalletService {
+	return &WalletServiceImpl{
+		walletRepo: walletRepo,
+		cfg:        cfg,
+	}
+}
+
+// GetWallet returns what the user can spend now, with the promotional credit that will expire
+func (s *WalletServiceImpl) GetWallet(ctx context.Context, userID uuid.UUID) (*model.WalletResponse, error) {
+	credits, err := s.walletRepo.ListAvailableCredits(ctx, userID, time.Now())
+	if err != nil {
+		log.Error().Err(err).Str("user_id", userID.String()).Msg("Failed to get wallet credits")
+		return nil, ginext.NewInternalServerError("failed to get wallet")
+	}
+
+	wallet := &model.WalletResponse{
+		UserID:          userID,
+		ExpiringCredits: []*model.WalletCredit{},
+	}
+	for _, credit := range credits {
+		wallet.Balance += credit.Remaining
+		if credit.Type == model.WalletEntryTypePromotion {
+			wallet.PromotionalBalance += credit.Remaining
+		}
+		if credit.ExpiresAt != nil {
+			wallet.ExpiringCredits = append(wallet.ExpiringCredits, &model.WalletCredit{
+				Type:      credit.Type,
+				Remaining: credit.Remaining,
+				ExpiresAt: credit.ExpiresAt,
+			})
+		}
+	}
+
+	return wallet, nil
+}
+
+func (s *WalletServiceImpl) ListEntries(ctx context.Context, userID uuid.UUID, query *model.WalletEntryListQuery) ([]*model.WalletEntry, int64, error) {
+	entries, total, err := s.walletRepo.ListEntries(ctx, userID, query)
+	if err != nil {
+		log.Error().Err(err).Str("user_id", userID.String()).Msg("Failed to list wallet entries")
+		return nil, 0, ginext.NewInternalServerError("failed to list wallet entries")
+	}
+	return entries, total, nil
+}
+
+// GrantPromotionalCredit hands out promotional credit, valid for the configured period unless the admin
+// sets when it expires
+func (s *WalletServiceImpl) GrantPromotionalCredit(ctx context.Context, userID uuid.UUID, req *model.GrantPromotionalCreditRequest, adminID uuid.UUID) (*model.WalletEntry, error) {
+	now := time.Now()
+	expiresAt := now.Add(s.cfg.PromotionalCreditValidity)
+	if req.ExpiresAt != nil {
+		if !req.ExpiresAt.After(now) {
+			return nil, ginext.NewBadRequestError("promotional credit must expire in the future")
+		}
+		expiresAt = *req.ExpiresAt
+	}
+
+	entry := &model.WalletEntry{
+		ID:          uuid.New(),
+		UserID:      userID,
+		Type:        model.WalletEntryTypePromotion,
+		Amount:      req.Amount,
+		ExpiresAt:   &expiresAt,
+		Description: req.Description,
+		CreatedBy:   &adminID,
+	}
+
+	event, err := newPromotionalCreditChangedEvent(entry, now)
+	if err != nil {
+		return nil, ginext.NewInternalServerError(err.Error())
+	}
+
+	if err := s.walletRepo.Credit(ctx, entry, event); err != nil {
+		log.Error().Err(err).Str("user_id", userID.String()).Msg("Failed to grant promotional credit")
+		return nil, ginext.NewInternalServerError("failed to grant promotional credit")
+	}
+
+	log.Info().
+		Str("user_id", userID.String()).
+		Str("admin_id", adminID.String()).
+		Int("amount", entry.Amount).
+		Time("expires_at", expiresAt).
+		Msg("Promotional credit granted")
+
+	return entry, nil
+}
+
+// ExpireCredits takes what is left of expired promotional credit off the wallets, one credit at a time
+func (s *WalletServiceImpl) ExpireCredits(ctx context.Context, limit int) (*model.WalletExpirySummary, error) {
+	now := time.Now()
+	credits, err := s.walletRepo.ListExpired(ctx, now, limit)
+	if err != nil {
+		return nil, ginext.NewInternalServerError("failed to list expired wallet credits")
+	}
+
+	summary := &model.WalletExpirySummary{}
+	for _, credit := range credits {
+		if ctx.Err() != nil {
+			break
+		}
+
+		expiry := &model.WalletEntry{
+			ID:          uuid.New(),
+			UserID:      credit.UserID,
+			Type:        model.WalletEntryTypeExpired,
+			Amount:      -credit.Remaining,
+			Description: "Khuyến mãi hết hạn: " + credit.Description,
+		}
+		event, err := newPromotionalCreditChangedEvent(expiry, now)
+		if err != nil {
+			return nil, ginext.NewInternalServerError(err.Error())
+		}
+
+		expired, err := s.walletRepo.Expire(ctx, credit, expiry, event)
+		switch {
+		case err != nil:
+			log.Error().Err(err).Str("entry_id", credit.ID.String()).Msg("Failed to expire wallet credit")
+			summary.Failed++
+		case expired:
+			summary.Expired++
+			summary.Amount += credit.Remaining
+		}
+	}
+
+	return summary, nil
+}
+
+// ReleasePayment is the outbox handler giving back to the wallet what a payment that was not
+// completed drew from it
+func (s *WalletServiceImpl) ReleasePayment(ctx context.Context, event *outbox.Event) error {
+	var payload model.WalletReleaseDueEvent
+	if err := event.Decode(&payload); err != nil {
+		return outbox.Permanent(err)
+	}
+
+	released, err := s.walletRepo.ReleasePayment(ctx, payload.TransactionID, "Hoàn lại số dư ví của thanh toán không hoàn tất")
+	if err != nil {
+		return err
+	}
+	if released {
+		log.Info().Str("transaction_id", payload.TransactionID.String()).Msg("Wallet payment released")
+	}
+	return nil
+}
+
+// newPromotionalCreditChangedEvent queues the ledger posting of promotional credit handed out or expired
+func newPromotionalCreditChangedEvent(entry *model.WalletEntry, occurredAt time.Time) (*outbox.Event, error) {
+	return outbox.NewEvent(model.AggregateTypeWallet, entry.UserID, model.EventTypePromotionalCreditChanged, &model.PromotionalCreditChangedEvent{
+		EntryID:    entry.ID,
+		UserID:     entry.UserID,
+		Type:       entry.Type,
+		Amount:     entry.Amount,
+		OccurredAt: occurredAt,
+	})
+}
@@ -0,0 +1,159 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"bus-booking/payment-service/config"
+	"bus-booking/payment-service/internal/model"
+	repo_mocks "bus-booking/payment-service/internal/repository/mocks"
+	"bus-booking/shared/outbox"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func newWalletService(ctrl *gomock.Controller) (WalletService, *repo_mocks.MockWalletRepository) {
+	mockWalletRepo := repo_mocks.NewMockWalletRepository(ctrl)
+	service := NewWalletService(mockWalletRepo, config.WalletConfig{
+		PromotionalCreditValidity: 90 * 24 * time.Hour,
+	})
+	return service, mockWalletRepo
+}
+
+func TestGetWallet_SumsSpendableCredits(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, mockWalletRepo := newWalletService(ctrl)
+	ctx := context.Background()
+	userID := uuid.New()
+	expiresAt := time.Now().Add(48 * time.Hour)
+
+	mockWalletRepo.EXPECT().
+		ListAvailableCredits(ctx, userID, gomock.Any()).
+		Return([]*model.WalletEntry{
+			{Type: model.WalletEntryTypePromotion, Remaining: 20000, ExpiresAt: &expiresAt},
+			{Type: model.WalletEntryTypeRefund, Remaining: 150000},
+		}, nil)
+
+	wallet, err := service.GetWallet(ctx, userID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 170000, wallet.Balance)
+	assert.Equal(t, 20000, wallet.PromotionalBalance)
+	assert.Len(t, wallet.ExpiringCredits, 1)
+	assert.Equal(t, 20000, wallet.ExpiringCredits[0].Remaining)
+}
+
+func TestGrantPromotionalCredit_DefaultValidity(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, mockWalletRepo := newWalletService(ctrl)
+	ctx := context.Background()
+	userID := uuid.New()
+	adminID := uuid.New()
+
+	mockWalletRepo.EXPECT().
+		Credit(ctx, gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, entry *model.WalletEntry, events ...*outbox.Event) error {
+			assert.Equal(t, model.WalletEntryTypePromotion, entry.Type)
+			assert.Equal(t, 50000, entry.Amount)
+			assert.Equal(t, adminID, *entry.CreatedBy)
+			assert.WithinDuration(t, time.Now().Add(90*24*time.Hour), *entry.ExpiresAt, time.Minute)
+
+			var payload model.PromotionalCreditChangedEvent
+			assert.NoError(t, events[0].Decode(&payload))
+			assert.Equal(t, entry.ID, payload.EntryID)
+			assert.Equal(t, 50000, payload.Amount)
+			return nil
+		})
+
+	entry, err := service.GrantPromotionalCredit(ctx, userID, &model.GrantPromotionalCreditRequest{
+		Amount:      50000,
+		Description: "Quà tặng khách hàng thân thiết",
+	}, adminID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, userID, entry.UserID)
+}
+
+func TestGrantPromotionalCredit_ExpiryInThePast(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, _ := newWalletService(ctrl)
+	expiresAt := time.Now().Add(-time.Hour)
+
+	entry, err := service.GrantPromotionalCredit(context.Background(), uuid.New(), &model.GrantPromotionalCreditRequest{
+		Amount:      50000,
+		Description: "Quà tặng khách hàng thân thiết",
+		ExpiresAt:   &expiresAt,
+	}, uuid.New())
+
+	assert.Error(t, err)
+	assert.Nil(t, entry)
+}
+
+func TestExpireCredits_TakesRemainingOff(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, mockWalletRepo := newWalletService(ctrl)
+	ctx := context.Background()
+
+	expired := &model.WalletEntry{ID: uuid.New(), UserID: uuid.New(), Type: model.WalletEntryTypePromotion, Remaining: 20000}
+	changed := &model.WalletEntry{ID: uuid.New(), UserID: uuid.New(), Type: model.WalletEntryTypePromotion, Remaining: 10000}
+	failed := &model.WalletEntry{ID: uuid.New(), UserID: uuid.New(), Type: model.WalletEntryTypePromotion, Remaining: 5000}
+
+	mockWalletRepo.EXPECT().
+		ListExpired(ctx, gomock.Any(), 100).
+		Return([]*model.WalletEntry{expired, changed, failed}, nil)
+	mockWalletRepo.EXPECT().
+		Expire(ctx, expired, gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, credit *model.WalletEntry, expiry *model.WalletEntry, events ...*outbox.Event) (bool, error) {
+			assert.Equal(t, model.WalletEntryTypeExpired, expiry.Type)
+			assert.Equal(t, -20000, expiry.Amount)
+			assert.Equal(t, credit.UserID, expiry.UserID)
+			assert.Equal(t, model.EventTypePromotionalCreditChanged, events[0].EventType)
+			return true, nil
+		})
+	// A released payment gave some credit back since it was listed
+	mockWalletRepo.EXPECT().
+		Expire(ctx, changed, gomock.Any(), gomock.Any()).
+		Return(false, nil)
+	mockWalletRepo.EXPECT().
+		Expire(ctx, failed, gomock.Any(), gomock.Any()).
+		Return(false, assert.AnError)
+
+	summary, err := service.ExpireCredits(ctx, 100)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, summary.Expired)
+	assert.Equal(t, 20000, summary.Amount)
+	assert.Equal(t, 1, summary.Failed)
+}
+
+func TestReleasePayment_GivesCreditBack(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, mockWalletRepo := newWalletService(ctrl)
+	ctx := context.Background()
+	transactionID := uuid.New()
+
+	event, err := outbox.NewEvent(model.AggregateTypeTransaction, transactionID, model.EventTypeWalletReleaseDue, &model.WalletReleaseDueEvent{
+		TransactionID: transactionID,
+	})
+	assert.NoError(t, err)
+
+	// A redelivered event finds the payment released and does nothing
+	mockWalletRepo.EXPECT().
+		ReleasePayment(ctx, transactionID, gomock.Any()).
+		Return(false, nil)
+
+	assert.NoError(t, service.ReleasePayment(ctx, event))
+}
@@ -0,0 +1,194 @@
+package service
+
+import (
+	"bus-booking/payment-service/internal/model"
+	"bus-booking/payment-service/internal/repository"
+	"bus-booking/shared/ginext"
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+type WebhookService interface {
+	// Receive records an inbound provider notification and applies it once per provider event.
+	// Redeliveries of an applied event are recorded as duplicates and acknowledged without processing.
+	Receive(ctx context.Context, method model.PaymentMethod, body []byte) error
+	ListForTransaction(ctx context.Context, transactionID uuid.UUID) ([]*model.WebhookEvent, error)
+	// Replay processes a verified event again; the provider status is fetched anew, so an applied payment is not repeated
+	Replay(ctx context.Context, id uuid.UUID) (*model.WebhookEvent, error)
+}
+
+type WebhookServiceImpl struct {
+	webhookEventRepo   repository.WebhookEventRepository
+	transactionRepo    repository.TransactionRepository
+	transactionService TransactionService
+	providers          PaymentProviders
+}
+
+func NewWebhookService(
+	webhookEventRepo repository.WebhookEventRepository,
+	transactionRepo repository.TransactionRepository,
+	transactionService TransactionService,
+	providers PaymentProviders,
+) WebhookService {
+	return &WebhookServiceImpl{
+		webhookEventRepo:   webhookEventRepo,
+		transactionRepo:    transactionRepo,
+		transactionService: transactionService,
+		providers:          providers,
+	}
+}
+
+func (s *WebhookServiceImpl) Receive(ctx context.Context, method model.PaymentMethod, body []byte) error {
+	event := &model.WebhookEvent{
+		PaymentMethod: method,
+		RawBody:       string(body),
+		Status:        model.WebhookEventStatusReceived,
+	}
+	if err := s.webhookEventRepo.Create(ctx, event); err != nil {
+		log.Error().Err(err).Str("payment_method", string(method)).Msg("Failed to record webhook event")
+		return ginext.NewInternalServerError("failed to record webhook")
+	}
+
+	provider, err := s.providers.Get(method)
+	if err != nil {
+		s.reject(ctx, event, err.Error())
+		return ginext.NewBadRequestError(err.Error())
+	}
+
+	var payload map[string]interface{}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		log.Error().Err(err).Msg("JSON parsing failed - invalid JSON format")
+		s.reject(ctx, event, "invalid JSON: "+err.Error())
+		return ginext.NewBadRequestError("Invalid webhook data")
+	}
+
+	log.Info().Str("payment_method", string(method)).Msg("Starting webhook verification")
+
+	// Verify webhook signature with original map data
+	webhook, err := provider.VerifyWebhook(ctx, payload)
+	if err != nil {
+		log.Error().Err(err).Str("webhook_event_id", event.ID.String()).Msg("Webhook signature verification failed")
+		s.reject(ctx, event, err.Error())
+		return ginext.NewUnauthorizedError("invalid webhook signature")
+	}
+	log.Info().Msg("Webhook signature verified successfully")
+
+	event.SignatureValid = true
+	event.EventKey = &webhook.EventID
+	event.OrderCode = &webhook.OrderCode
+	event.PaymentLinkID = webhook.PaymentLinkID
+	event.Reference = webhook.Reference
+	event.PaidAt = webhook.PaidAt
+	if err := s.webhookEventRepo.SaveResult(ctx, event); err != nil {
+		log.Error().Err(err).Str("webhook_event_id", event.ID.String()).Msg("Failed to save verified webhook event")
+		return ginext.NewInternalServerError("failed to record webhook")
+	}
+
+	claimed, err := s.webhookEventRepo.Claim(ctx, event)
+	if err != nil {
+		log.Error().Err(err).Str("webhook_event_id", event.ID.String()).Msg("Failed to claim webhook event")
+		return ginext.NewInternalServerError("failed to record webhook")
+	}
+	if !claimed {
+		// Acknowledge the redelivery so the provider stops sending it
+		log.Info().
+			Str("webhook_event_id", event.ID.String()).
+			Str("event_key", webhook.EventID).
+			Msg("Webhook event already processed, skipping duplicate")
+		now := time.Now().UTC()
+		event.Status = model.WebhookEventStatusDuplicate
+		event.ProcessedAt = &now
+		if err := s.webhookEventRepo.SaveResult(ctx, event); err != nil {
+			log.Error().Err(err).Str("webhook_event_id", event.ID.String()).Msg("Failed to save duplicate webhook event")
+		}
+		return nil
+	}
+
+	return s.process(ctx, event, webhook)
+}
+
+func (s *WebhookServiceImpl) ListForTransaction(ctx context.Context, transactionID uuid.UUID) ([]*model.WebhookEvent, error) {
+	transaction, err := s.transactionRepo.GetByID(ctx, transactionID)
+	if err != nil {
+		return nil, ginext.NewNotFoundError("transaction not found")
+	}
+
+	events, err := s.webhookEventRepo.ListForTransaction(ctx, transaction.ID, transaction.OrderCode)
+	if err != nil {
+		log.Error().Err(err).Str("transaction_id", transactionID.String()).Msg("Failed to list webhook events")
+		return nil, ginext.NewInternalServerError("failed to list webhook events")
+	}
+	return events, nil
+}
+
+func (s *WebhookServiceImpl) Replay(ctx context.Context, id uuid.UUID) (*model.WebhookEvent, error) {
+	event, err := s.webhookEventRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, ginext.NewNotFoundError("webhook event not found")
+	}
+	// Only what a valid signature vouched for is applied, the raw body is not trusted again
+	if !event.SignatureValid {
+		return nil, ginext.NewBadRequestError("rejected webhook events cannot be replayed")
+	}
+
+	claimed, err := s.webhookEventRepo.Claim(ctx, event)
+	if err != nil {
+		log.Error().Err(err).Str("webhook_event_id", id.String()).Msg("Failed to claim webhook event")
+		return nil, ginext.NewInternalServerError("failed to replay webhook event")
+	}
+	if !claimed {
+		return nil, ginext.NewConflictError("webhook event is being processed or another delivery of it was already processed")
+	}
+
+	log.Info().Str("webhook_event_id", id.String()).Int("attempts", event.Attempts).Msg("Replaying webhook event")
+	if err := s.process(ctx, event, event.ProviderWebhook()); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+// process applies a claimed event and saves the result on it
+func (s *WebhookServiceImpl) process(ctx context.Context, event *model.WebhookEvent, webhook *model.ProviderWebhook) error {
+	transaction, applyErr := s.transactionService.ApplyWebhook(ctx, event.PaymentMethod, webhook)
+	if transaction != nil {
+		event.TransactionID = &transaction.ID
+	}
+
+	now := time.Now().UTC()
+	event.ProcessedAt = &now
+	if applyErr != nil {
+		message := applyErr.Error()
+		event.Status = model.WebhookEventStatusFailed
+		event.Error = &message
+	} else {
+		event.Status = model.WebhookEventStatusProcessed
+		event.Error = nil
+	}
+
+	if err := s.webhookEventRepo.SaveResult(ctx, event); err != nil {
+		log.Error().Err(err).Str("webhook_event_id", event.ID.String()).Msg("Failed to save webhook event result")
+		if applyErr == nil {
+			return ginext.NewInternalServerError("failed to record webhook result")
+		}
+	}
+
+	if applyErr != nil {
+		log.Error().Err(applyErr).Str("webhook_event_id", event.ID.String()).Msg("Failed to process webhook event")
+	}
+	return applyErr
+}
+
+// reject records an event that could not be parsed or verified
+func (s *WebhookServiceImpl) reject(ctx context.Context, event *model.WebhookEvent, reason string) {
+	now := time.Now().UTC()
+	event.Status = model.WebhookEventStatusRejected
+	event.Error = &reason
+	event.ProcessedAt = &now
+	if err := s.webhookEventRepo.SaveResult(ctx, event); err != nil {
+		log.Error().Err(err).Str("webhook_event_id", event.ID.String()).Msg("Failed to save rejected webhook event")
+	}
+}
@@ -0,0 +1,297 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"bus-booking/payment-service/internal/model"
+	repo_mocks "bus-booking/payment-service/internal/repository/mocks"
+	service_mocks "bus-booking/payment-service/internal/service/mocks"
+	"bus-booking/shared/ginext"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+type webhookTestMocks struct {
+	webhookEventRepo   *repo_mocks.MockWebhookEventRepository
+	transactionRepo    *repo_mocks.MockTransactionRepository
+	transactionService *service_mocks.MockTransactionService
+	payOS              *service_mocks.MockPayOSService
+}
+
+func newWebhookTestService(t *testing.T) (WebhookService, *webhookTestMocks) {
+	ctrl := gomock.NewController(t)
+
+	mocks := &webhookTestMocks{
+		webhookEventRepo:   repo_mocks.NewMockWebhookEventRepository(ctrl),
+		transactionRepo:    repo_mocks.NewMockTransactionRepository(ctrl),
+		transactionService: service_mocks.NewMockTransactionService(ctrl),
+		payOS:              service_mocks.NewMockPayOSService(ctrl),
+	}
+	service := NewWebhookService(
+		mocks.webhookEventRepo,
+		mocks.transactionRepo,
+		mocks.transactionService,
+		PaymentProviders{model.PaymentMethodPayOS: mocks.payOS},
+	)
+	return service, mocks
+}
+
+const testWebhookBody = `{"code":"00","desc":"success","data":{"orderCode":123456,"reference":"FT001"},"signature":"abc"}`
+
+func verifiedTestWebhook() *model.ProviderWebhook {
+	paidAt := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+	return &model.ProviderWebhook{
+		EventID:       "123456:FT001",
+		OrderCode:     123456,
+		PaymentLinkID: "payos-link-1",
+		Reference:     "FT001",
+		PaidAt:        &paidAt,
+	}
+}
+
+func TestReceiveWebhook_Success(t *testing.T) {
+	service, mocks := newWebhookTestService(t)
+	ctx := context.Background()
+	webhook := verifiedTestWebhook()
+	transaction := &model.Transaction{BaseModel: model.BaseModel{ID: uuid.New()}, OrderCode: webhook.OrderCode}
+
+	mocks.webhookEventRepo.EXPECT().
+		Create(ctx, gomock.Any()).
+		DoAndReturn(func(ctx context.Context, event *model.WebhookEvent) error {
+			assert.Equal(t, model.PaymentMethodPayOS, event.PaymentMethod)
+			assert.Equal(t, testWebhookBody, event.RawBody)
+			assert.Equal(t, model.WebhookEventStatusReceived, event.Status)
+			event.ID = uuid.New()
+			return nil
+		})
+	mocks.payOS.EXPECT().
+		VerifyWebhook(ctx, gomock.Any()).
+		DoAndReturn(func(ctx context.Context, payload map[string]interface{}) (*model.ProviderWebhook, error) {
+			assert.Equal(t, "abc", payload["signature"])
+			return webhook, nil
+		})
+
+	gomock.InOrder(
+		mocks.webhookEventRepo.EXPECT().
+			SaveResult(ctx, gomock.Any()).
+			DoAndReturn(func(ctx context.Context, event *model.WebhookEvent) error {
+				assert.True(t, event.SignatureValid)
+				assert.Equal(t, "123456:FT001", *event.EventKey)
+				assert.Equal(t, int64(123456), *event.OrderCode)
+				assert.Equal(t, "FT001", event.Reference)
+				return nil
+			}),
+		mocks.webhookEventRepo.EXPECT().Claim(ctx, gomock.Any()).Return(true, nil),
+		mocks.transactionService.EXPECT().
+			ApplyWebhook(ctx, model.PaymentMethodPayOS, webhook).
+			Return(transaction, nil),
+		mocks.webhookEventRepo.EXPECT().
+			SaveResult(ctx, gomock.Any()).
+			DoAndReturn(func(ctx context.Context, event *model.WebhookEvent) error {
+				assert.Equal(t, model.WebhookEventStatusProcessed, event.Status)
+				assert.Equal(t, transaction.ID, *event.TransactionID)
+				assert.NotNil(t, event.ProcessedAt)
+				assert.Nil(t, event.Error)
+				return nil
+			}),
+	)
+
+	assert.NoError(t, service.Receive(ctx, model.PaymentMethodPayOS, []byte(testWebhookBody)))
+}
+
+func TestReceiveWebhook_DuplicateSkipped(t *testing.T) {
+	service, mocks := newWebhookTestService(t)
+	ctx := context.Background()
+
+	mocks.webhookEventRepo.EXPECT().Create(ctx, gomock.Any()).Return(nil)
+	mocks.payOS.EXPECT().VerifyWebhook(ctx, gomock.Any()).Return(verifiedTestWebhook(), nil)
+	gomock.InOrder(
+		mocks.webhookEventRepo.EXPECT().SaveResult(ctx, gomock.Any()).Return(nil),
+		mocks.webhookEventRepo.EXPECT().Claim(ctx, gomock.Any()).Return(false, nil),
+		mocks.webhookEventRepo.EXPECT().
+			SaveResult(ctx, gomock.Any()).
+			DoAndReturn(func(ctx context.Context, event *model.WebhookEvent) error {
+				assert.Equal(t, model.WebhookEventStatusDuplicate, event.Status)
+				return nil
+			}),
+	)
+	// Booking update and emails must not run again
+	mocks.transactionService.EXPECT().ApplyWebhook(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+	// Acknowledged so the provider stops redelivering
+	assert.NoError(t, service.Receive(ctx, model.PaymentMethodPayOS, []byte(testWebhookBody)))
+}
+
+func TestReceiveWebhook_InvalidSignature_Rejected(t *testing.T) {
+	service, mocks := newWebhookTestService(t)
+	ctx := context.Background()
+
+	mocks.webhookEventRepo.EXPECT().Create(ctx, gomock.Any()).Return(nil)
+	mocks.payOS.EXPECT().VerifyWebhook(ctx, gomock.Any()).Return(nil, assert.AnError)
+	mocks.webhookEventRepo.EXPECT().
+		SaveResult(ctx, gomock.Any()).
+		DoAndReturn(func(ctx context.Context, event *model.WebhookEvent) error {
+			assert.Equal(t, model.WebhookEventStatusRejected, event.Status)
+			assert.False(t, event.SignatureValid)
+			assert.Nil(t, event.EventKey)
+			assert.NotNil(t, event.Error)
+			return nil
+		})
+	mocks.webhookEventRepo.EXPECT().Claim(gomock.Any(), gomock.Any()).Times(0)
+
+	err := service.Receive(ctx, model.PaymentMethodPayOS, []byte(testWebhookBody))
+
+	var apiErr *ginext.Error
+	assert.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusUnauthorized, apiErr.Code)
+	assert.Contains(t, err.Error(), "invalid webhook signature")
+}
+
+func TestReceiveWebhook_InvalidJSON_Rejected(t *testing.T) {
+	service, mocks := newWebhookTestService(t)
+	ctx := context.Background()
+
+	mocks.webhookEventRepo.EXPECT().Create(ctx, gomock.Any()).Return(nil)
+	mocks.webhookEventRepo.EXPECT().
+		SaveResult(ctx, gomock.Any()).
+		DoAndReturn(func(ctx context.Context, event *model.WebhookEvent) error {
+			assert.Equal(t, model.WebhookEventStatusRejected, event.Status)
+			assert.Equal(t, "not json", event.RawBody)
+			return nil
+		})
+
+	err := service.Receive(ctx, model.PaymentMethodPayOS, []byte("not json"))
+
+	var apiErr *ginext.Error
+	assert.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusBadRequest, apiErr.Code)
+}
+
+func TestReceiveWebhook_ProcessingFailed_Recorded(t *testing.T) {
+	service, mocks := newWebhookTestService(t)
+	ctx := context.Background()
+
+	mocks.webhookEventRepo.EXPECT().Create(ctx, gomock.Any()).Return(nil)
+	mocks.payOS.EXPECT().VerifyWebhook(ctx, gomock.Any()).Return(verifiedTestWebhook(), nil)
+	gomock.InOrder(
+		mocks.webhookEventRepo.EXPECT().SaveResult(ctx, gomock.Any()).Return(nil),
+		mocks.webhookEventRepo.EXPECT().Claim(ctx, gomock.Any()).Return(true, nil),
+		mocks.transactionService.EXPECT().
+			ApplyWebhook(ctx, model.PaymentMethodPayOS, gomock.Any()).
+			Return(nil, assert.AnError),
+		mocks.webhookEventRepo.EXPECT().
+			SaveResult(ctx, gomock.Any()).
+			DoAndReturn(func(ctx context.Context, event *model.WebhookEvent) error {
+				assert.Equal(t, model.WebhookEventStatusFailed, event.Status)
+				assert.Equal(t, assert.AnError.Error(), *event.Error)
+				assert.Nil(t, event.TransactionID)
+				return nil
+			}),
+	)
+
+	// The error is returned so the provider delivers the notification again
+	assert.ErrorIs(t, service.Receive(ctx, model.PaymentMethodPayOS, []byte(testWebhookBody)), assert.AnError)
+}
+
+func TestReplayWebhookEvent_Success(t *testing.T) {
+	service, mocks := newWebhookTestService(t)
+	ctx := context.Background()
+
+	webhook := verifiedTestWebhook()
+	failure := "booking service unavailable"
+	event := &model.WebhookEvent{
+		ID:             uuid.New(),
+		PaymentMethod:  model.PaymentMethodPayOS,
+		EventKey:       &webhook.EventID,
+		SignatureValid: true,
+		OrderCode:      &webhook.OrderCode,
+		PaymentLinkID:  webhook.PaymentLinkID,
+		Reference:      webhook.Reference,
+		PaidAt:         webhook.PaidAt,
+		Status:         model.WebhookEventStatusFailed,
+		Error:          &failure,
+		Attempts:       1,
+	}
+	transaction := &model.Transaction{BaseModel: model.BaseModel{ID: uuid.New()}}
+
+	mocks.webhookEventRepo.EXPECT().GetByID(ctx, event.ID).Return(event, nil)
+	mocks.webhookEventRepo.EXPECT().Claim(ctx, event).Return(true, nil)
+	// The raw body is not verified again, the saved verified fields are applied
+	mocks.payOS.EXPECT().VerifyWebhook(gomock.Any(), gomock.Any()).Times(0)
+	mocks.transactionService.EXPECT().
+		ApplyWebhook(ctx, model.PaymentMethodPayOS, webhook).
+		Return(transaction, nil)
+	mocks.webhookEventRepo.EXPECT().SaveResult(ctx, event).Return(nil)
+
+	replayed, err := service.Replay(ctx, event.ID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, model.WebhookEventStatusProcessed, replayed.Status)
+	assert.Nil(t, replayed.Error)
+	assert.Equal(t, transaction.ID, *replayed.TransactionID)
+}
+
+func TestReplayWebhookEvent_RejectedEvent(t *testing.T) {
+	service, mocks := newWebhookTestService(t)
+	ctx := context.Background()
+
+	event := &model.WebhookEvent{ID: uuid.New(), Status: model.WebhookEventStatusRejected}
+	mocks.webhookEventRepo.EXPECT().GetByID(ctx, event.ID).Return(event, nil)
+	mocks.webhookEventRepo.EXPECT().Claim(gomock.Any(), gomock.Any()).Times(0)
+
+	_, err := service.Replay(ctx, event.ID)
+
+	var apiErr *ginext.Error
+	assert.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusBadRequest, apiErr.Code)
+}
+
+func TestReplayWebhookEvent_AlreadyApplied(t *testing.T) {
+	service, mocks := newWebhookTestService(t)
+	ctx := context.Background()
+
+	event := &model.WebhookEvent{ID: uuid.New(), SignatureValid: true, Status: model.WebhookEventStatusDuplicate}
+	mocks.webhookEventRepo.EXPECT().GetByID(ctx, event.ID).Return(event, nil)
+	mocks.webhookEventRepo.EXPECT().Claim(ctx, event).Return(false, nil)
+	mocks.transactionService.EXPECT().ApplyWebhook(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+	_, err := service.Replay(ctx, event.ID)
+
+	var apiErr *ginext.Error
+	assert.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusConflict, apiErr.Code)
+}
+
+func TestListWebhookEventsForTransaction(t *testing.T) {
+	service, mocks := newWebhookTestService(t)
+	ctx := context.Background()
+
+	transaction := &model.Transaction{BaseModel: model.BaseModel{ID: uuid.New()}, OrderCode: 123456}
+	events := []*model.WebhookEvent{{ID: uuid.New()}}
+
+	mocks.transactionRepo.EXPECT().GetByID(ctx, transaction.ID).Return(transaction, nil)
+	mocks.webhookEventRepo.EXPECT().ListForTransaction(ctx, transaction.ID, int64(123456)).Return(events, nil)
+
+	result, err := service.ListForTransaction(ctx, transaction.ID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, events, result)
+}
+
+func TestListWebhookEventsForTransaction_NotFound(t *testing.T) {
+	service, mocks := newWebhookTestService(t)
+	ctx := context.Background()
+
+	mocks.transactionRepo.EXPECT().GetByID(ctx, gomock.Any()).Return(nil, assert.AnError)
+
+	_, err := service.ListForTransaction(ctx, uuid.New())
+
+	var apiErr *ginext.Error
+	assert.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusNotFound, apiErr.Code)
+}
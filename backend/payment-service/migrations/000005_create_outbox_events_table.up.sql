@@ -0,0 +1,26 @@
+-- Events to other services, written in the same transaction as the state change
+CREATE TABLE IF NOT EXISTS outbox_events (
+    id UUID PRIMARY KEY,
+    aggregate_type VARCHAR(50) NOT NULL,
+    aggregate_id UUID NOT NULL,
+    event_type VARCHAR(100) NOT NULL,
+    payload JSONB NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'PENDING' CHECK (status IN ('PENDING', 'DELIVERED', 'FAILED')),
+    attempts INT NOT NULL DEFAULT 0,
+    last_error TEXT,
+    next_attempt_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    delivered_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Relay polling: due pending events, oldest first
+CREATE INDEX idx_outbox_events_pending ON outbox_events(next_attempt_at, created_at)
+    WHERE status = 'PENDING';
+
+-- Per-aggregate ordering check
+CREATE INDEX idx_outbox_events_aggregate ON outbox_events(aggregate_id, created_at)
+    WHERE status = 'PENDING';
+
+COMMENT ON TABLE outbox_events IS 'Transactional outbox delivered to other services by the relay worker';
+COMMENT ON COLUMN outbox_events.status IS 'PENDING | DELIVERED | FAILED (retries exhausted)';
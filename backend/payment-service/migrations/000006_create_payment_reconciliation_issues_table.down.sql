@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS payment_reconciliation_issues;
+
+DROP INDEX IF EXISTS idx_transactions_unsettled;
+
+ALTER TABLE transactions
+DROP COLUMN IF EXISTS expires_at;
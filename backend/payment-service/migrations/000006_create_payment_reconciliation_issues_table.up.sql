@@ -0,0 +1,42 @@
+-- Payment deadline of the booking, used to tell payments that arrived after the booking expired
+ALTER TABLE transactions
+ADD COLUMN IF NOT EXISTS expires_at TIMESTAMP;
+
+-- Reconciliation polling: unsettled payments, oldest first
+CREATE INDEX IF NOT EXISTS idx_transactions_unsettled ON transactions(created_at)
+    WHERE status IN ('PENDING', 'PROCESSING') AND deleted_at IS NULL;
+
+-- Differences between our transactions and the payment provider that need an admin decision
+CREATE TABLE IF NOT EXISTS payment_reconciliation_issues (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    transaction_id UUID NOT NULL,
+    booking_id UUID NOT NULL,
+    issue_type VARCHAR(50) NOT NULL CHECK (issue_type IN ('PAID_AFTER_EXPIRED', 'PAID_AFTER_CANCELLED')),
+    local_status VARCHAR(50) NOT NULL,
+    provider_status VARCHAR(50) NOT NULL,
+    amount INT NOT NULL,
+    provider_amount INT NOT NULL,
+    provider_reference VARCHAR(255),
+    provider_paid_at TIMESTAMP,
+    status VARCHAR(20) NOT NULL DEFAULT 'OPEN' CHECK (status IN ('OPEN', 'RESOLVED')),
+    resolution VARCHAR(20) CHECK (resolution IS NULL OR resolution IN ('APPLY', 'IGNORE')),
+    resolution_note TEXT,
+    resolved_by UUID,
+    resolved_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT fk_reconciliation_issues_transaction FOREIGN KEY (transaction_id)
+        REFERENCES transactions(id) ON DELETE CASCADE
+);
+
+-- A transaction is flagged once per issue type until an admin resolves it
+CREATE UNIQUE INDEX idx_reconciliation_issues_open ON payment_reconciliation_issues(transaction_id, issue_type)
+    WHERE status = 'OPEN';
+
+-- Admin review queue
+CREATE INDEX idx_reconciliation_issues_status ON payment_reconciliation_issues(status, created_at DESC);
+
+COMMENT ON TABLE payment_reconciliation_issues IS 'Provider payments that could not be applied to bookings automatically';
+COMMENT ON COLUMN payment_reconciliation_issues.status IS 'OPEN | RESOLVED';
+COMMENT ON COLUMN payment_reconciliation_issues.resolution IS 'APPLY (booking service is notified) | IGNORE (transaction updated only)';
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_refunds_processing;
+DROP TABLE IF EXISTS refund_payouts;
+DROP TABLE IF EXISTS refund_payout_batches;
@@ -0,0 +1,56 @@
+-- Approved refunds transferred to passengers together
+CREATE TABLE IF NOT EXISTS refund_payout_batches (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    created_by UUID NOT NULL,
+    total_amount INT NOT NULL CHECK (total_amount > 0),
+    payout_count INT NOT NULL CHECK (payout_count > 0),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP
+);
+
+CREATE INDEX idx_refund_payout_batches_created_at ON refund_payout_batches(created_at DESC) WHERE deleted_at IS NULL;
+
+-- One bank transfer per refund, with the VietQR payload the admin pays it with
+CREATE TABLE IF NOT EXISTS refund_payouts (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    batch_id UUID NOT NULL,
+    refund_id UUID NOT NULL,
+    booking_id UUID NOT NULL,
+    user_id UUID NOT NULL,
+    amount INT NOT NULL CHECK (amount > 0),
+    bank_code VARCHAR(20) NOT NULL,
+    bank_bin VARCHAR(10) NOT NULL,
+    account_number VARCHAR(50) NOT NULL,
+    account_holder VARCHAR(100) NOT NULL,
+    transfer_content VARCHAR(50) NOT NULL,
+    qr_payload TEXT NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'QUEUED' CHECK (status IN ('QUEUED', 'SENT', 'CONFIRMED', 'FAILED')),
+    bank_reference VARCHAR(100),
+    failure_reason TEXT,
+    sent_at TIMESTAMP,
+    confirmed_at TIMESTAMP,
+    failed_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP,
+
+    CONSTRAINT fk_refund_payouts_batch FOREIGN KEY (batch_id)
+        REFERENCES refund_payout_batches(id) ON DELETE CASCADE,
+    CONSTRAINT fk_refund_payouts_refund FOREIGN KEY (refund_id)
+        REFERENCES refunds(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_refund_payouts_batch_id ON refund_payouts(batch_id) WHERE deleted_at IS NULL;
+
+-- A refund is paid out by at most one transfer at a time; failed transfers can be retried in a new batch
+CREATE UNIQUE INDEX idx_refund_payouts_active_refund ON refund_payouts(refund_id)
+    WHERE status <> 'FAILED' AND deleted_at IS NULL;
+
+-- Approved refunds waiting for a batch
+CREATE INDEX IF NOT EXISTS idx_refunds_processing ON refunds(created_at)
+    WHERE refund_status = 'PROCESSING' AND deleted_at IS NULL;
+
+COMMENT ON TABLE refund_payouts IS 'Bank transfers paying out approved refunds';
+COMMENT ON COLUMN refund_payouts.status IS 'QUEUED | SENT | CONFIRMED | FAILED';
+COMMENT ON COLUMN refund_payouts.qr_payload IS 'VietQR (NAPAS 247) payload of the transfer';
@@ -0,0 +1,21 @@
+DROP TABLE IF EXISTS refund_seats;
+
+DELETE FROM transactions WHERE transaction_type = 'OUT' AND refund_id IS NOT NULL;
+
+ALTER TABLE transactions DROP CONSTRAINT IF EXISTS chk_refund_status;
+ALTER TABLE transactions
+ADD CONSTRAINT chk_refund_status CHECK (
+    refund_status IS NULL OR
+    refund_status IN ('PENDING', 'PROCESSING', 'COMPLETED', 'FAILED', 'CANCELLED')
+);
+
+DROP INDEX IF EXISTS idx_transactions_refund_id;
+
+ALTER TABLE transactions
+DROP CONSTRAINT IF EXISTS chk_refunded_amount,
+DROP COLUMN IF EXISTS refund_id,
+DROP COLUMN IF EXISTS refunded_amount;
+
+-- Only succeeds while every booking has at most one refund
+CREATE UNIQUE INDEX IF NOT EXISTS idx_refunds_unique_booking ON refunds(booking_id)
+    WHERE deleted_at IS NULL;
@@ -0,0 +1,57 @@
+-- A booking can be refunded several times, e.g. seat by seat or as a later compensation
+DROP INDEX IF EXISTS idx_refunds_unique_booking;
+
+-- Running refunded total of a payment, and the refund an OUT ledger entry pays
+ALTER TABLE transactions
+ADD COLUMN IF NOT EXISTS refunded_amount INTEGER NOT NULL DEFAULT 0,
+ADD COLUMN IF NOT EXISTS refund_id UUID;
+
+ALTER TABLE transactions
+ADD CONSTRAINT chk_refunded_amount CHECK (refunded_amount >= 0 AND refunded_amount <= amount);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_transactions_refund_id ON transactions(refund_id) WHERE refund_id IS NOT NULL;
+
+-- OUT ledger entries mirror the status of their refund
+ALTER TABLE transactions DROP CONSTRAINT IF EXISTS chk_refund_status;
+ALTER TABLE transactions
+ADD CONSTRAINT chk_refund_status CHECK (
+    refund_status IS NULL OR
+    refund_status IN ('PENDING', 'PROCESSING', 'COMPLETED', 'REJECTED', 'FAILED', 'CANCELLED')
+);
+
+-- Seats paid back by a refund
+CREATE TABLE IF NOT EXISTS refund_seats (
+    refund_id UUID NOT NULL,
+    booking_seat_id UUID NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    PRIMARY KEY (refund_id, booking_seat_id),
+    CONSTRAINT fk_refund_seats_refund FOREIGN KEY (refund_id)
+        REFERENCES refunds(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_refund_seats_booking_seat_id ON refund_seats(booking_seat_id);
+
+-- Backfill the refunded totals and ledger entries of existing refunds
+UPDATE transactions t
+SET refunded_amount = r.total
+FROM (
+    SELECT transaction_id, SUM(refund_amount) AS total
+    FROM refunds
+    WHERE refund_status <> 'REJECTED' AND deleted_at IS NULL
+    GROUP BY transaction_id
+) r
+WHERE t.id = r.transaction_id;
+
+INSERT INTO transactions (booking_id, user_id, amount, currency, payment_method, status, transaction_type, refund_status, refund_amount, refund_id, created_at, updated_at)
+SELECT r.booking_id, r.user_id, r.refund_amount, t.currency, t.payment_method,
+       CASE r.refund_status WHEN 'COMPLETED' THEN 'PAID' WHEN 'REJECTED' THEN 'CANCELLED' ELSE 'PENDING' END,
+       'OUT', r.refund_status, r.refund_amount, r.id, r.created_at, r.updated_at
+FROM refunds r
+JOIN transactions t ON t.id = r.transaction_id
+WHERE r.deleted_at IS NULL
+ON CONFLICT DO NOTHING;
+
+COMMENT ON TABLE refund_seats IS 'Booked seats paid back by a refund';
+COMMENT ON COLUMN transactions.refunded_amount IS 'IN payments: total of the refunds not rejected, never above amount';
+COMMENT ON COLUMN transactions.refund_id IS 'OUT ledger entries: the refund paid';
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS ledger_entries;
+DROP TABLE IF EXISTS ledger_journals;
+ALTER TABLE transactions DROP COLUMN IF EXISTS operator_id;
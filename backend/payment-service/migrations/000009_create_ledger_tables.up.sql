@@ -0,0 +1,70 @@
+-- Operator whose trip a payment is for, settlement reports are split by it
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS operator_id UUID;
+
+-- One balanced posting per charge, provider fee, completed refund or manual adjustment
+CREATE TABLE IF NOT EXISTS ledger_journals (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    kind VARCHAR(20) NOT NULL CHECK (kind IN ('CHARGE', 'FEE', 'REFUND', 'ADJUSTMENT')),
+    source_id UUID NOT NULL,
+    transaction_id UUID,
+    operator_id UUID,
+    payment_method VARCHAR(50) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    created_by UUID,
+    occurred_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- A charge, fee or refund is posted once however often its event is delivered
+CREATE UNIQUE INDEX idx_ledger_journals_source ON ledger_journals(kind, source_id);
+CREATE INDEX idx_ledger_journals_occurred_at ON ledger_journals(occurred_at DESC);
+
+CREATE TABLE IF NOT EXISTS ledger_entries (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    journal_id UUID NOT NULL,
+    account VARCHAR(30) NOT NULL CHECK (account IN ('PROVIDER_CLEARING', 'OPERATOR_PAYABLE', 'PROVIDER_FEES', 'ADJUSTMENTS')),
+    direction VARCHAR(10) NOT NULL CHECK (direction IN ('DEBIT', 'CREDIT')),
+    amount INT NOT NULL CHECK (amount > 0),
+    operator_id UUID,
+    payment_method VARCHAR(50) NOT NULL,
+    occurred_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT fk_ledger_entries_journal FOREIGN KEY (journal_id)
+        REFERENCES ledger_journals(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_ledger_entries_journal_id ON ledger_entries(journal_id);
+CREATE INDEX idx_ledger_entries_account_occurred_at ON ledger_entries(account, occurred_at);
+
+-- Post the charges and completed refunds recorded before the ledger existed; their fees are unknown
+WITH journals AS (
+    INSERT INTO ledger_journals (kind, source_id, transaction_id, payment_method, description, occurred_at)
+    SELECT 'CHARGE', t.id, t.id, t.payment_method, 'Charge recorded before the ledger', t.updated_at
+    FROM transactions t
+    WHERE t.transaction_type = 'IN' AND t.status = 'PAID' AND t.deleted_at IS NULL
+    RETURNING id, transaction_id, payment_method, occurred_at
+)
+INSERT INTO ledger_entries (journal_id, account, direction, amount, payment_method, occurred_at)
+SELECT j.id, leg.account, leg.direction, t.amount, j.payment_method, j.occurred_at
+FROM journals j
+JOIN transactions t ON t.id = j.transaction_id
+CROSS JOIN (VALUES ('PROVIDER_CLEARING', 'DEBIT'), ('OPERATOR_PAYABLE', 'CREDIT')) AS leg(account, direction);
+
+WITH journals AS (
+    INSERT INTO ledger_journals (kind, source_id, transaction_id, payment_method, description, occurred_at)
+    SELECT 'REFUND', r.id, r.transaction_id, t.payment_method, 'Refund recorded before the ledger', COALESCE(r.processed_at, r.updated_at)
+    FROM refunds r
+    JOIN transactions t ON t.id = r.transaction_id
+    WHERE r.refund_status = 'COMPLETED' AND r.deleted_at IS NULL
+    RETURNING id, source_id, payment_method, occurred_at
+)
+INSERT INTO ledger_entries (journal_id, account, direction, amount, payment_method, occurred_at)
+SELECT j.id, leg.account, leg.direction, r.refund_amount, j.payment_method, j.occurred_at
+FROM journals j
+JOIN refunds r ON r.id = j.source_id
+CROSS JOIN (VALUES ('OPERATOR_PAYABLE', 'DEBIT'), ('PROVIDER_CLEARING', 'CREDIT')) AS leg(account, direction);
+
+COMMENT ON TABLE ledger_journals IS 'Double-entry postings, the debits and credits of a journal always balance';
+COMMENT ON COLUMN ledger_journals.source_id IS 'Transaction of a CHARGE or FEE, refund of a REFUND, the journal itself for an ADJUSTMENT';
+COMMENT ON COLUMN ledger_entries.account IS 'PROVIDER_CLEARING | OPERATOR_PAYABLE | PROVIDER_FEES | ADJUSTMENTS';
@@ -0,0 +1,31 @@
+-- Every provider notification received, kept for auditing and manual replay
+CREATE TABLE IF NOT EXISTS webhook_events (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    payment_method VARCHAR(50) NOT NULL,
+    event_key VARCHAR(255),
+    raw_body TEXT NOT NULL,
+    signature_valid BOOLEAN NOT NULL DEFAULT FALSE,
+    order_code BIGINT,
+    payment_link_id VARCHAR(255),
+    reference VARCHAR(255),
+    paid_at TIMESTAMP,
+    transaction_id UUID,
+    status VARCHAR(20) NOT NULL DEFAULT 'RECEIVED'
+        CHECK (status IN ('RECEIVED', 'PROCESSING', 'PROCESSED', 'DUPLICATE', 'FAILED', 'REJECTED')),
+    error TEXT,
+    attempts INT NOT NULL DEFAULT 0,
+    processed_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- A provider event is applied by one delivery only, redeliveries are recorded as duplicates
+CREATE UNIQUE INDEX idx_webhook_events_applied ON webhook_events(payment_method, event_key)
+    WHERE status IN ('PROCESSING', 'PROCESSED');
+
+CREATE INDEX idx_webhook_events_transaction_id ON webhook_events(transaction_id, created_at DESC);
+CREATE INDEX idx_webhook_events_order_code ON webhook_events(order_code, created_at DESC);
+
+COMMENT ON TABLE webhook_events IS 'Inbound payment provider webhooks with their processing result';
+COMMENT ON COLUMN webhook_events.event_key IS 'Provider event identity, the same for every delivery of one notification';
+COMMENT ON COLUMN webhook_events.status IS 'RECEIVED | PROCESSING | PROCESSED | DUPLICATE | FAILED | REJECTED';
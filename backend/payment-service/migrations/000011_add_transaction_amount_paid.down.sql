@@ -0,0 +1,6 @@
+-- Only succeeds while no overpayment was refunded beyond the price
+ALTER TABLE transactions DROP CONSTRAINT IF EXISTS chk_refunded_amount;
+ALTER TABLE transactions
+ADD CONSTRAINT chk_refunded_amount CHECK (refunded_amount >= 0 AND refunded_amount <= amount);
+
+ALTER TABLE transactions DROP COLUMN IF EXISTS amount_paid;
@@ -0,0 +1,14 @@
+-- Money actually transferred for a payment, which differs from amount for short and excess transfers
+ALTER TABLE transactions
+ADD COLUMN IF NOT EXISTS amount_paid INTEGER NOT NULL DEFAULT 0;
+
+UPDATE transactions
+SET amount_paid = amount
+WHERE transaction_type = 'IN' AND status = 'PAID';
+
+-- The excess of an overpaid transfer is refunded on top of the price
+ALTER TABLE transactions DROP CONSTRAINT IF EXISTS chk_refunded_amount;
+ALTER TABLE transactions
+ADD CONSTRAINT chk_refunded_amount CHECK (refunded_amount >= 0 AND refunded_amount <= GREATEST(amount, amount_paid));
+
+COMMENT ON COLUMN transactions.amount_paid IS 'IN payments: amount received, below amount while UNDERPAID and above it when overpaid';
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS invoices;
+DROP TABLE IF EXISTS invoice_sequences;
+
+ALTER TABLE transactions DROP COLUMN IF EXISTS invoice_buyer;
@@ -0,0 +1,59 @@
+-- Buyer details of a VAT invoice asked for at booking time, issued once the payment is captured
+ALTER TABLE transactions
+ADD COLUMN IF NOT EXISTS invoice_buyer JSONB;
+
+-- Last invoice number used per operator and invoice series; rows are locked while an invoice is numbered
+CREATE TABLE IF NOT EXISTS invoice_sequences (
+    operator_id UUID NOT NULL,
+    series VARCHAR(10) NOT NULL,
+    last_number INTEGER NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (operator_id, series)
+);
+
+CREATE TABLE IF NOT EXISTS invoices (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    transaction_id UUID NOT NULL REFERENCES transactions(id),
+    booking_id UUID NOT NULL,
+    user_id UUID NOT NULL,
+    operator_id UUID,
+    series VARCHAR(10) NOT NULL,
+    number INTEGER NOT NULL CHECK (number > 0),
+    status VARCHAR(20) NOT NULL DEFAULT 'ISSUED'
+        CHECK (status IN ('ISSUED', 'CANCELLED', 'REPLACED')),
+    seller_name VARCHAR(255) NOT NULL,
+    seller_tax_code VARCHAR(14) NOT NULL,
+    seller_address TEXT NOT NULL,
+    buyer_name VARCHAR(255) NOT NULL DEFAULT '',
+    buyer_company_name VARCHAR(255) NOT NULL,
+    buyer_tax_code VARCHAR(14) NOT NULL,
+    buyer_address TEXT NOT NULL,
+    buyer_email VARCHAR(255) NOT NULL DEFAULT '',
+    description TEXT NOT NULL,
+    amount_before_tax INTEGER NOT NULL CHECK (amount_before_tax >= 0),
+    vat_rate INTEGER NOT NULL CHECK (vat_rate >= 0),
+    vat_amount INTEGER NOT NULL CHECK (vat_amount >= 0),
+    total_amount INTEGER NOT NULL CHECK (total_amount > 0),
+    issued_at TIMESTAMP NOT NULL,
+    cancelled_at TIMESTAMP,
+    cancel_reason TEXT,
+    replaces_id UUID REFERENCES invoices(id),
+    replaced_by_id UUID REFERENCES invoices(id),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP
+);
+
+-- The operator column is NULL for payments settled to the platform, numbered under the nil UUID
+CREATE UNIQUE INDEX idx_invoices_number ON invoices(COALESCE(operator_id, '00000000-0000-0000-0000-000000000000'), series, number);
+
+-- A payment has one valid invoice at a time, the others were cancelled or replaced
+CREATE UNIQUE INDEX idx_invoices_transaction_issued ON invoices(transaction_id) WHERE status = 'ISSUED';
+
+CREATE INDEX idx_invoices_booking_id ON invoices(booking_id, issued_at DESC);
+
+COMMENT ON TABLE invoices IS 'VAT invoices issued for paid bookings';
+COMMENT ON COLUMN invoices.series IS 'Invoice symbol, numbering restarts with each series';
+COMMENT ON COLUMN invoices.status IS 'ISSUED | CANCELLED | REPLACED';
+COMMENT ON COLUMN invoices.seller_name IS 'Seller details as printed when the invoice was issued';
+COMMENT ON COLUMN invoices.total_amount IS 'Amount paid including VAT';
@@ -0,0 +1,17 @@
+-- Only succeeds while nothing was posted to the wallet accounts
+ALTER TABLE ledger_entries DROP CONSTRAINT IF EXISTS ledger_entries_account_check;
+ALTER TABLE ledger_entries
+ADD CONSTRAINT ledger_entries_account_check CHECK (account IN ('PROVIDER_CLEARING', 'OPERATOR_PAYABLE', 'PROVIDER_FEES', 'ADJUSTMENTS'));
+
+ALTER TABLE ledger_journals DROP CONSTRAINT IF EXISTS ledger_journals_kind_check;
+ALTER TABLE ledger_journals
+ADD CONSTRAINT ledger_journals_kind_check CHECK (kind IN ('CHARGE', 'FEE', 'REFUND', 'ADJUSTMENT'));
+
+DROP TABLE IF EXISTS wallet_entries;
+DROP TABLE IF EXISTS wallets;
+
+ALTER TABLE refunds DROP CONSTRAINT IF EXISTS chk_refund_destination;
+ALTER TABLE refunds DROP COLUMN IF EXISTS destination;
+
+ALTER TABLE transactions DROP CONSTRAINT IF EXISTS chk_wallet_amount;
+ALTER TABLE transactions DROP COLUMN IF EXISTS wallet_amount;
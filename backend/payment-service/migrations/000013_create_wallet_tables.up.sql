@@ -0,0 +1,70 @@
+-- Part of a payment drawn from the wallet of its user, the rest goes through the payment provider
+ALTER TABLE transactions
+ADD COLUMN IF NOT EXISTS wallet_amount INTEGER NOT NULL DEFAULT 0;
+
+ALTER TABLE transactions DROP CONSTRAINT IF EXISTS chk_wallet_amount;
+ALTER TABLE transactions
+ADD CONSTRAINT chk_wallet_amount CHECK (wallet_amount >= 0 AND wallet_amount <= amount);
+
+-- Refunds are paid out to a bank account by an admin or credited to the wallet at once
+ALTER TABLE refunds
+ADD COLUMN IF NOT EXISTS destination VARCHAR(10) NOT NULL DEFAULT 'BANK';
+
+ALTER TABLE refunds DROP CONSTRAINT IF EXISTS chk_refund_destination;
+ALTER TABLE refunds
+ADD CONSTRAINT chk_refund_destination CHECK (destination IN ('BANK', 'WALLET'));
+
+-- One row per user, locked while the wallet changes so balances after each entry add up
+CREATE TABLE IF NOT EXISTS wallets (
+    user_id UUID PRIMARY KEY,
+    balance INTEGER NOT NULL DEFAULT 0 CHECK (balance >= 0),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS wallet_entries (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES wallets(user_id),
+    type VARCHAR(20) NOT NULL
+        CHECK (type IN ('REFUND', 'PROMOTION', 'PAYMENT', 'PAYMENT_RELEASED', 'EXPIRED')),
+    amount INTEGER NOT NULL CHECK (amount <> 0),
+    balance_after INTEGER NOT NULL CHECK (balance_after >= 0),
+    remaining INTEGER NOT NULL DEFAULT 0 CHECK (remaining >= 0),
+    expires_at TIMESTAMP,
+    transaction_id UUID REFERENCES transactions(id),
+    booking_id UUID,
+    refund_id UUID REFERENCES refunds(id),
+    allocations JSONB,
+    description TEXT NOT NULL DEFAULT '',
+    created_by UUID,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_wallet_entries_user_id ON wallet_entries(user_id, created_at DESC);
+
+-- Credits still to spend, drawn soonest expiring first
+CREATE INDEX idx_wallet_entries_available ON wallet_entries(user_id, expires_at) WHERE remaining > 0;
+
+-- Promotional credit waiting for the expiry job
+CREATE INDEX idx_wallet_entries_expiring ON wallet_entries(expires_at) WHERE remaining > 0 AND expires_at IS NOT NULL;
+
+-- A refund is credited once, and a payment draws from the wallet and is released at most once
+CREATE UNIQUE INDEX idx_wallet_entries_refund_id ON wallet_entries(refund_id) WHERE refund_id IS NOT NULL;
+CREATE UNIQUE INDEX idx_wallet_entries_payment ON wallet_entries(transaction_id, type) WHERE type IN ('PAYMENT', 'PAYMENT_RELEASED');
+
+-- Money owed to wallets and the promotional credit handed out are posted to the ledger
+ALTER TABLE ledger_journals DROP CONSTRAINT IF EXISTS ledger_journals_kind_check;
+ALTER TABLE ledger_journals
+ADD CONSTRAINT ledger_journals_kind_check CHECK (kind IN ('CHARGE', 'FEE', 'REFUND', 'ADJUSTMENT', 'WALLET_CHARGE', 'PROMOTION', 'CREDIT_EXPIRY'));
+
+ALTER TABLE ledger_entries DROP CONSTRAINT IF EXISTS ledger_entries_account_check;
+ALTER TABLE ledger_entries
+ADD CONSTRAINT ledger_entries_account_check CHECK (account IN ('PROVIDER_CLEARING', 'OPERATOR_PAYABLE', 'PROVIDER_FEES', 'ADJUSTMENTS', 'CUSTOMER_WALLETS', 'PROMOTIONS'));
+
+COMMENT ON COLUMN transactions.wallet_amount IS 'IN payments: part drawn from the wallet, included in amount_paid';
+COMMENT ON COLUMN refunds.destination IS 'BANK | WALLET';
+COMMENT ON TABLE wallet_entries IS 'Wallet history; credits keep what is left of them to spend in remaining';
+COMMENT ON COLUMN wallet_entries.amount IS 'Positive for credits, negative for payments and expiries';
+COMMENT ON COLUMN wallet_entries.expires_at IS 'End of validity of promotional credit, NULL for credit that does not expire';
+COMMENT ON COLUMN wallet_entries.allocations IS 'Credits a payment was drawn from, restored when the payment is released';
+COMMENT ON COLUMN ledger_entries.account IS 'PROVIDER_CLEARING | OPERATOR_PAYABLE | PROVIDER_FEES | ADJUSTMENTS | CUSTOMER_WALLETS | PROMOTIONS';
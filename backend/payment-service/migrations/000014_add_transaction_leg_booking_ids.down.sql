@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_transactions_leg_booking_ids;
+
+ALTER TABLE transactions DROP COLUMN IF EXISTS leg_booking_ids;
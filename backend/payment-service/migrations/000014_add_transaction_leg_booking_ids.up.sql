@@ -0,0 +1,7 @@
+-- Bookings paid for together with booking_id, e.g. the legs of a round trip paid at once
+ALTER TABLE transactions
+ADD COLUMN IF NOT EXISTS leg_booking_ids JSONB;
+
+CREATE INDEX IF NOT EXISTS idx_transactions_leg_booking_ids ON transactions USING GIN (leg_booking_ids);
+
+COMMENT ON COLUMN transactions.leg_booking_ids IS 'IN payments: JSON array of the other bookings a combined payment pays for';
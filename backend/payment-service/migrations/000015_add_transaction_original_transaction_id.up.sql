@@ -0,0 +1,7 @@
+-- Fare differences paid when a booking is exchanged point at the payment of the booking
+ALTER TABLE transactions
+ADD COLUMN IF NOT EXISTS original_transaction_id UUID REFERENCES transactions(id);
+
+CREATE INDEX IF NOT EXISTS idx_transactions_original_transaction_id ON transactions(original_transaction_id);
+
+COMMENT ON COLUMN transactions.original_transaction_id IS 'IN payments: the booking payment an exchange fare difference is paid on top of';
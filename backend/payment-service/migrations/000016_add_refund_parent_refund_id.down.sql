@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_refunds_parent_refund_id;
+
+ALTER TABLE refunds DROP COLUMN IF EXISTS parent_refund_id;
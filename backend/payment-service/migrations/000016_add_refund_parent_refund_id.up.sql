@@ -0,0 +1,7 @@
+-- Refunds split over several payments of a booking, or partly paid back to the wallet, point at the first part
+ALTER TABLE refunds
+ADD COLUMN IF NOT EXISTS parent_refund_id UUID REFERENCES refunds(id);
+
+CREATE INDEX IF NOT EXISTS idx_refunds_parent_refund_id ON refunds(parent_refund_id);
+
+COMMENT ON COLUMN refunds.parent_refund_id IS 'The refund this one was split off, NULL for a refund requested on its own';
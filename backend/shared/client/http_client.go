@@ -298,6 +298,11 @@ func (c *Client) setMicroserviceHeaders(req *http.Request, ctx context.Context)
 		req.Header.Set(constants.XUserEmail, reqCtx.UserEmail)
 	}
 
+	// Set event ID when delivering an outbox event
+	if eventID := sharedcontext.GetEventID(ctx); eventID != "" {
+		req.Header.Set(constants.XEventID, eventID)
+	}
+
 	// Set service name
 	req.Header.Set(constants.XServiceName, c.config.ServiceName)
 }
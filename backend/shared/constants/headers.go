@@ -9,4 +9,5 @@ const (
 	XUserName    = "X-User-Name"
 	XServiceName = "X-Service-Name"
 	XAccessToken = "X-Access-Token"
+	XEventID     = "X-Event-ID"
 )
@@ -145,6 +145,20 @@ func FromRequestContext(ctx context.Context) *RequestContext {
 	return reqCtx
 }
 
+// WithEventID marks outgoing requests made with ctx as the delivery of an outbox event,
+// so consumers can deduplicate redeliveries
+func WithEventID(ctx context.Context, eventID uuid.UUID) context.Context {
+	return context.WithValue(ctx, constants.XEventID, eventID.String())
+}
+
+// GetEventID gets the outbox event ID being delivered from standard context
+func GetEventID(ctx context.Context) string {
+	if eventID, ok := ctx.Value(constants.XEventID).(string); ok {
+		return eventID
+	}
+	return ""
+}
+
 // GetAccessToken gets access token from context
 func GetAccessToken(c *gin.Context) string {
 	if accessToken, exists := c.Get("access_token"); exists {
@@ -46,6 +46,10 @@ require (
 
 	// Database
 	gorm.io/gorm v1.25.12
+
+	// Testing
+	github.com/alicebob/miniredis/v2 v2.37.0
+	github.com/stretchr/testify v1.11.1
 )
 
 require (
@@ -82,6 +86,7 @@ require (
 	github.com/quic-go/quic-go v0.54.0 // indirect
 	github.com/twitchyliquid64/golang-asm v0.15.1 // indirect
 	github.com/ugorji/go/codec v1.3.0 // indirect
+	github.com/yuin/gopher-lua v1.1.1 // indirect
 	go.uber.org/mock v0.5.0 // indirect
 	golang.org/x/arch v0.20.0 // indirect
 	golang.org/x/mod v0.28.0 // indirect
@@ -0,0 +1,46 @@
+package outbox
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ProcessedEvent records an event a consumer has already applied, so redeliveries
+// of the same event ID are ignored
+type ProcessedEvent struct {
+	EventID     uuid.UUID `gorm:"type:uuid;primaryKey" json:"event_id"`
+	Consumer    string    `gorm:"type:varchar(100);not null" json:"consumer"`
+	ProcessedAt time.Time `gorm:"not null" json:"processed_at"`
+}
+
+func (ProcessedEvent) TableName() string {
+	return "processed_events"
+}
+
+// IsProcessed reports whether eventID has already been applied
+func IsProcessed(ctx context.Context, db *gorm.DB, eventID uuid.UUID) (bool, error) {
+	var count int64
+	if err := db.WithContext(ctx).Model(&ProcessedEvent{}).Where("event_id = ?", eventID).Count(&count).Error; err != nil {
+		return false, fmt.Errorf("failed to check processed event: %w", err)
+	}
+	return count > 0, nil
+}
+
+// MarkProcessed records eventID using tx, which must be the transaction applying the event.
+// It returns false when the event was already recorded by a concurrent delivery.
+func MarkProcessed(tx *gorm.DB, eventID uuid.UUID, consumer string) (bool, error) {
+	result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&ProcessedEvent{
+		EventID:     eventID,
+		Consumer:    consumer,
+		ProcessedAt: time.Now(),
+	})
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to record processed event: %w", result.Error)
+	}
+	return result.RowsAffected == 1, nil
+}
@@ -0,0 +1,97 @@
+package outbox
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type Status string
+
+const (
+	StatusPending   Status = "PENDING"
+	StatusDelivered Status = "DELIVERED"
+	StatusFailed    Status = "FAILED"
+)
+
+// Event is a message that must reach another service. It is written in the same
+// database transaction as the state change it announces and delivered by a Relay.
+type Event struct {
+	ID            uuid.UUID  `gorm:"type:uuid;primaryKey" json:"id"`
+	AggregateType string     `gorm:"type:varchar(50);not null" json:"aggregate_type"`
+	AggregateID   uuid.UUID  `gorm:"type:uuid;not null;index" json:"aggregate_id"`
+	EventType     string     `gorm:"type:varchar(100);not null" json:"event_type"`
+	Payload       string     `gorm:"type:jsonb;not null" json:"payload"`
+	Status        Status     `gorm:"type:varchar(20);not null;default:'PENDING'" json:"status"`
+	Attempts      int        `gorm:"not null;default:0" json:"attempts"`
+	LastError     string     `gorm:"type:text" json:"last_error,omitempty"`
+	NextAttemptAt time.Time  `gorm:"not null" json:"next_attempt_at"`
+	DeliveredAt   *time.Time `json:"delivered_at,omitempty"`
+	CreatedAt     time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt     time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+func (Event) TableName() string {
+	return "outbox_events"
+}
+
+// NewEvent builds a pending event; the payload is stored as JSON
+func NewEvent(aggregateType string, aggregateID uuid.UUID, eventType string, payload interface{}) (*Event, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal %s event payload: %w", eventType, err)
+	}
+
+	return &Event{
+		ID:            uuid.New(),
+		AggregateType: aggregateType,
+		AggregateID:   aggregateID,
+		EventType:     eventType,
+		Payload:       string(data),
+		Status:        StatusPending,
+		NextAttemptAt: time.Now(),
+	}, nil
+}
+
+// Decode unmarshals the event payload into target
+func (e *Event) Decode(target interface{}) error {
+	if err := json.Unmarshal([]byte(e.Payload), target); err != nil {
+		return fmt.Errorf("failed to decode %s event %s: %w", e.EventType, e.ID, err)
+	}
+	return nil
+}
+
+// Add writes events using tx, which must be the transaction of the state change
+func Add(tx *gorm.DB, events ...*Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+	if err := tx.Create(events).Error; err != nil {
+		return fmt.Errorf("failed to write outbox events: %w", err)
+	}
+	return nil
+}
+
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// Permanent marks a delivery error that retrying cannot fix; the event is failed immediately
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err: err}
+}
+
+func isPermanent(err error) bool {
+	var permanent *permanentError
+	return errors.As(err, &permanent)
+}
@@ -0,0 +1,192 @@
+package outbox
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	sharedcontext "bus-booking/shared/context"
+
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	DefaultBatchSize    = 50
+	DefaultPollInterval = 5 * time.Second
+	DefaultMaxAttempts  = 10
+	DefaultBaseBackoff  = 10 * time.Second
+	DefaultMaxBackoff   = 30 * time.Minute
+)
+
+// Handler delivers one event to its consumer. Returning an error schedules a retry,
+// unless it is wrapped with Permanent.
+type Handler func(ctx context.Context, event *Event) error
+
+type RelayConfig struct {
+	BatchSize    int
+	PollInterval time.Duration
+	MaxAttempts  int
+	BaseBackoff  time.Duration
+	MaxBackoff   time.Duration
+}
+
+func DefaultRelayConfig() RelayConfig {
+	return RelayConfig{
+		BatchSize:    DefaultBatchSize,
+		PollInterval: DefaultPollInterval,
+		MaxAttempts:  DefaultMaxAttempts,
+		BaseBackoff:  DefaultBaseBackoff,
+		MaxBackoff:   DefaultMaxBackoff,
+	}
+}
+
+// Relay polls the outbox table and delivers pending events with retries.
+// Several relays may run against the same table: each event is claimed with
+// FOR UPDATE SKIP LOCKED, and events of one aggregate are delivered in order.
+type Relay struct {
+	db       *gorm.DB
+	cfg      RelayConfig
+	handlers map[string]Handler
+}
+
+func NewRelay(db *gorm.DB, cfg RelayConfig) *Relay {
+	return &Relay{
+		db:       db,
+		cfg:      cfg,
+		handlers: make(map[string]Handler),
+	}
+}
+
+// Register sets the handler delivering events of eventType
+func (r *Relay) Register(eventType string, handler Handler) {
+	r.handlers[eventType] = handler
+}
+
+// Start delivers pending events every poll interval until ctx is cancelled
+func (r *Relay) Start(ctx context.Context) {
+	log.Info().Dur("interval", r.cfg.PollInterval).Msg("Starting outbox relay")
+
+	ticker := time.NewTicker(r.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := r.ProcessPending(ctx); err != nil && ctx.Err() == nil {
+			log.Error().Err(err).Msg("Failed to process outbox events")
+		}
+
+		select {
+		case <-ctx.Done():
+			log.Info().Msg("Outbox relay stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// ProcessPending delivers up to one batch of due events and returns how many were attempted
+func (r *Relay) ProcessPending(ctx context.Context) (int, error) {
+	processed := 0
+	for processed < r.cfg.BatchSize {
+		found, err := r.deliverNext(ctx)
+		if err != nil {
+			return processed, err
+		}
+		if !found {
+			break
+		}
+		processed++
+	}
+	return processed, nil
+}
+
+// deliverNext claims the oldest due event and delivers it inside the claiming transaction,
+// so a crash before the status update leaves the event pending for redelivery
+func (r *Relay) deliverNext(ctx context.Context) (bool, error) {
+	found := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var event Event
+		result := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND next_attempt_at <= ?", StatusPending, time.Now()).
+			Where(`NOT EXISTS (
+				SELECT 1 FROM outbox_events earlier
+				WHERE earlier.aggregate_id = outbox_events.aggregate_id
+					AND earlier.status = ?
+					AND earlier.created_at < outbox_events.created_at
+			)`, StatusPending).
+			Order("created_at").
+			Limit(1).
+			Find(&event)
+		if result.Error != nil {
+			return fmt.Errorf("failed to claim outbox event: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+		found = true
+
+		r.deliver(ctx, &event)
+
+		if err := tx.Save(&event).Error; err != nil {
+			return fmt.Errorf("failed to update outbox event %s: %w", event.ID, err)
+		}
+		return nil
+	})
+	return found, err
+}
+
+func (r *Relay) deliver(ctx context.Context, event *Event) {
+	event.Attempts++
+
+	handler, ok := r.handlers[event.EventType]
+	if !ok {
+		r.fail(event, Permanent(fmt.Errorf("no handler registered for event type %s", event.EventType)))
+		return
+	}
+
+	if err := handler(sharedcontext.WithEventID(ctx, event.ID), event); err != nil {
+		r.fail(event, err)
+		return
+	}
+
+	now := time.Now()
+	event.Status = StatusDelivered
+	event.DeliveredAt = &now
+	event.LastError = ""
+	log.Debug().Str("event_id", event.ID.String()).Str("event_type", event.EventType).Msg("Outbox event delivered")
+}
+
+func (r *Relay) fail(event *Event, err error) {
+	event.LastError = err.Error()
+
+	if isPermanent(err) || event.Attempts >= r.cfg.MaxAttempts {
+		event.Status = StatusFailed
+		log.Error().Err(err).
+			Str("event_id", event.ID.String()).
+			Str("event_type", event.EventType).
+			Int("attempts", event.Attempts).
+			Msg("Outbox event failed permanently")
+		return
+	}
+
+	event.NextAttemptAt = time.Now().Add(r.backoff(event.Attempts))
+	log.Warn().Err(err).
+		Str("event_id", event.ID.String()).
+		Str("event_type", event.EventType).
+		Int("attempts", event.Attempts).
+		Time("next_attempt_at", event.NextAttemptAt).
+		Msg("Outbox event delivery failed, will retry")
+}
+
+// backoff doubles the base delay for every failed attempt, up to the max
+func (r *Relay) backoff(attempts int) time.Duration {
+	delay := r.cfg.BaseBackoff
+	for i := 1; i < attempts && delay < r.cfg.MaxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > r.cfg.MaxBackoff {
+		delay = r.cfg.MaxBackoff
+	}
+	return delay
+}
@@ -3,16 +3,51 @@ package queue
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/redis/go-redis/v9"
 	"github.com/rs/zerolog/log"
 )
 
+const (
+	// DefaultVisibilityTimeout là thời gian một item được "thuê" (lease) bởi worker trước khi bị giao lại
+	DefaultVisibilityTimeout = 2 * time.Minute
+
+	// DefaultMaxAttempts là số lần xử lý tối đa trước khi item bị chuyển vào dead-letter queue
+	DefaultMaxAttempts = 5
+
+	// DefaultRetryBackoff là thời gian chờ trước lần thử lại đầu tiên, tăng gấp đôi sau mỗi lần thất bại
+	DefaultRetryBackoff = 30 * time.Second
+
+	// DefaultMaxRetryBackoff giới hạn thời gian chờ giữa hai lần thử lại
+	DefaultMaxRetryBackoff = 30 * time.Minute
+)
+
+// ErrDeadLetterNotFound được trả về khi replay một dead letter không tồn tại
+var ErrDeadLetterNotFound = errors.New("dead letter not found")
+
 // DelayedItem đại diện cho một item trong hàng đợi trễ
 type DelayedItem struct {
+	// ID là idempotency key của item trong hàng đợi: lên lịch lại cùng ID sẽ thay thế item cũ
+	// thay vì tạo bản sao. Để trống sẽ được sinh ngẫu nhiên.
+	ID      string      `json:"id,omitempty"`
 	Type    string      `json:"type"`    // Loại job (e.g., "booking_expiry")
 	Payload interface{} `json:"payload"` // Dữ liệu của job
+
+	// Được điền khi Poll / ListDeadLetters, không lưu cùng item
+	Attempts  int    `json:"attempts,omitempty"`
+	LastError string `json:"last_error,omitempty"`
+}
+
+// DeadLetter là item đã thất bại quá số lần cho phép
+type DeadLetter struct {
+	Queue  string       `json:"queue"`
+	Item   *DelayedItem `json:"item"`
+	DeadAt time.Time    `json:"dead_at"`
 }
 
 // DelayedQueueManager định nghĩa interface cho hàng đợi trễ
@@ -20,25 +55,196 @@ type DelayedQueueManager interface {
 	// Schedule lên lịch một công việc sẽ được thực thi tại thời điểm `executeAt`
 	Schedule(ctx context.Context, queueName string, item *DelayedItem, executeAt time.Time) error
 
-	// Poll tìm và lấy các item đã đến hạn xử lý.
-	// Sử dụng cơ chế nguyên tử (atomic) để đảm bảo một item chỉ được xử lý bởi một worker.
+	// ScheduleEarliest giống Schedule nhưng chỉ dời lịch sớm hơn: nếu item cùng ID đang chờ
+	// chạy trước `executeAt` thì lịch đó được giữ nguyên
+	ScheduleEarliest(ctx context.Context, queueName string, item *DelayedItem, executeAt time.Time) error
+
+	// Poll tìm và "thuê" các item đã đến hạn xử lý.
+	// Item được chuyển sang tập in-flight cho đến khi worker gọi Ack/Nack; nếu worker
+	// không phản hồi trong visibility timeout, item sẽ được giao lại.
 	Poll(ctx context.Context, queueName string, limit int) ([]*DelayedItem, error)
+
+	// Ack xác nhận item đã xử lý xong và xoá khỏi hàng đợi
+	Ack(ctx context.Context, queueName string, item *DelayedItem) error
+
+	// Nack báo xử lý thất bại: item được thử lại với exponential backoff, hoặc chuyển vào
+	// dead-letter queue khi hết số lần thử hoặc lỗi là Permanent
+	Nack(ctx context.Context, queueName string, item *DelayedItem, cause error) error
+
+	// ListDeadLetters trả về các dead letter của hàng đợi, cũ nhất trước
+	ListDeadLetters(ctx context.Context, queueName string, limit int) ([]*DeadLetter, error)
+
+	// ReplayDeadLetter đưa một dead letter trở lại hàng đợi để xử lý ngay
+	ReplayDeadLetter(ctx context.Context, queueName string, id string) error
+}
+
+// permanentError đánh dấu lỗi không thể khắc phục bằng cách thử lại
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// Permanent bọc lỗi để Nack chuyển item thẳng vào dead-letter queue mà không thử lại
+func Permanent(err error) error {
+	return &permanentError{err: err}
 }
 
-// RedisDelayedQueueManager implementation sử dụng Redis Sorted Sets (ZSET)
+// RedisDelayedQueueManager implementation sử dụng Redis Sorted Sets (ZSET).
+//
+// Mỗi hàng đợi dùng các key:
+//   - <queue>           ZSET id -> thời điểm thực thi (unix giây)
+//   - <queue>:inflight  ZSET id -> hạn lease (unix giây)
+//   - <queue>:dead      ZSET id -> thời điểm chuyển vào dead-letter
+//   - <queue>:items     HASH id -> JSON của item
+//   - <queue>:attempts  HASH id -> số lần đã giao cho worker
+//   - <queue>:errors    HASH id -> lỗi gần nhất
 type RedisDelayedQueueManager struct {
-	client *redis.Client
+	client            *redis.Client
+	visibilityTimeout time.Duration
+	maxAttempts       int
+	retryBackoff      time.Duration
+	maxRetryBackoff   time.Duration
 }
 
 // NewRedisDelayedQueueManager tạo mới một RedisDelayedQueueManager
 func NewRedisDelayedQueueManager(client *redis.Client) DelayedQueueManager {
 	return &RedisDelayedQueueManager{
-		client: client,
+		client:            client,
+		visibilityTimeout: DefaultVisibilityTimeout,
+		maxAttempts:       DefaultMaxAttempts,
+		retryBackoff:      DefaultRetryBackoff,
+		maxRetryBackoff:   DefaultMaxRetryBackoff,
+	}
+}
+
+func queueKeys(queueName string) []string {
+	return []string{
+		queueName,
+		queueName + ":inflight",
+		queueName + ":dead",
+		queueName + ":items",
+		queueName + ":attempts",
+		queueName + ":errors",
 	}
 }
 
+// scheduleScript lưu item và (lên lịch lại) nó; một lease đang giữ item sẽ bị huỷ
+// để Ack của worker cũ không xoá mất lịch mới
+var scheduleScript = redis.NewScript(`
+	redis.call('HSET', KEYS[4], ARGV[1], ARGV[2])
+	redis.call('ZADD', KEYS[1], ARGV[3], ARGV[1])
+	redis.call('ZREM', KEYS[2], ARGV[1])
+	redis.call('ZREM', KEYS[3], ARGV[1])
+	redis.call('HDEL', KEYS[5], ARGV[1])
+	redis.call('HDEL', KEYS[6], ARGV[1])
+	return 1
+`)
+
+// scheduleEarliestScript như scheduleScript, nhưng bỏ qua khi item đã chờ chạy sớm hơn.
+// Item đang được xử lý (in-flight) không tính là đang chờ, nên vẫn được lên lịch lại.
+var scheduleEarliestScript = redis.NewScript(`
+	local current = redis.call('ZSCORE', KEYS[1], ARGV[1])
+	if current and tonumber(current) <= tonumber(ARGV[3]) then
+		return 0
+	end
+	redis.call('HSET', KEYS[4], ARGV[1], ARGV[2])
+	redis.call('ZADD', KEYS[1], ARGV[3], ARGV[1])
+	redis.call('ZREM', KEYS[2], ARGV[1])
+	redis.call('ZREM', KEYS[3], ARGV[1])
+	redis.call('HDEL', KEYS[5], ARGV[1])
+	redis.call('HDEL', KEYS[6], ARGV[1])
+	return 1
+`)
+
+// pollScript:
+// 1. Giao lại các lease đã hết hạn (worker chết), hoặc chuyển vào dead-letter nếu đã hết lượt thử
+// 2. ZRANGEBYSCORE các item đến hạn, chuyển sang in-flight với hạn lease mới
+// 3. Trả về bộ ba (id, item JSON, số lần giao)
+var pollScript = redis.NewScript(`
+	local now = tonumber(ARGV[1])
+	local maxAttempts = tonumber(ARGV[4])
+
+	local expired = redis.call('ZRANGEBYSCORE', KEYS[2], '-inf', now)
+	for _, id in ipairs(expired) do
+		redis.call('ZREM', KEYS[2], id)
+		local attempts = tonumber(redis.call('HGET', KEYS[5], id) or '0')
+		if attempts >= maxAttempts then
+			redis.call('ZADD', KEYS[3], now, id)
+			redis.call('HSET', KEYS[6], id, 'lease expired without ack')
+		else
+			redis.call('ZADD', KEYS[1], now, id)
+		end
+	end
+
+	local ids = redis.call('ZRANGEBYSCORE', KEYS[1], '-inf', now, 'LIMIT', 0, ARGV[2])
+	local result = {}
+	for _, id in ipairs(ids) do
+		redis.call('ZREM', KEYS[1], id)
+		redis.call('ZADD', KEYS[2], ARGV[3], id)
+		local attempts = redis.call('HINCRBY', KEYS[5], id, 1)
+		local data = redis.call('HGET', KEYS[4], id) or ''
+		table.insert(result, id)
+		table.insert(result, data)
+		table.insert(result, tostring(attempts))
+	end
+	return result
+`)
+
+// ackScript xoá item nếu lease vẫn thuộc về worker và item chưa được lên lịch lại
+var ackScript = redis.NewScript(`
+	if redis.call('ZREM', KEYS[2], ARGV[1]) == 0 then
+		return 0
+	end
+	if not redis.call('ZSCORE', KEYS[1], ARGV[1]) then
+		redis.call('HDEL', KEYS[4], ARGV[1])
+		redis.call('HDEL', KEYS[5], ARGV[1])
+		redis.call('HDEL', KEYS[6], ARGV[1])
+	end
+	return 1
+`)
+
+// nackScript trả lease và chuyển item về hàng đợi (ARGV[3] = 'retry') hoặc dead-letter
+var nackScript = redis.NewScript(`
+	if redis.call('ZREM', KEYS[2], ARGV[1]) == 0 then
+		return 0
+	end
+	redis.call('HSET', KEYS[6], ARGV[1], ARGV[2])
+	if ARGV[3] == 'retry' then
+		redis.call('ZADD', KEYS[1], ARGV[4], ARGV[1])
+	else
+		redis.call('ZADD', KEYS[3], ARGV[4], ARGV[1])
+	end
+	return 1
+`)
+
+// replayScript đưa dead letter về hàng đợi với số lần thử được đặt lại
+var replayScript = redis.NewScript(`
+	if redis.call('ZREM', KEYS[3], ARGV[1]) == 0 then
+		return 0
+	end
+	redis.call('HDEL', KEYS[5], ARGV[1])
+	redis.call('HDEL', KEYS[6], ARGV[1])
+	redis.call('ZADD', KEYS[1], ARGV[2], ARGV[1])
+	return 1
+`)
+
 // Schedule thêm item vào ZSET với score là timestamp
 func (m *RedisDelayedQueueManager) Schedule(ctx context.Context, queueName string, item *DelayedItem, executeAt time.Time) error {
+	return m.schedule(ctx, scheduleScript, queueName, item, executeAt)
+}
+
+// ScheduleEarliest lên lịch item, trừ khi nó đã chờ chạy sớm hơn `executeAt`
+func (m *RedisDelayedQueueManager) ScheduleEarliest(ctx context.Context, queueName string, item *DelayedItem, executeAt time.Time) error {
+	return m.schedule(ctx, scheduleEarliestScript, queueName, item, executeAt)
+}
+
+func (m *RedisDelayedQueueManager) schedule(ctx context.Context, script *redis.Script, queueName string, item *DelayedItem, executeAt time.Time) error {
+	if item.ID == "" {
+		item.ID = uuid.NewString()
+	}
+
 	data, err := json.Marshal(item)
 	if err != nil {
 		log.Error().Err(err).Msg("Failed to marshal delayed item")
@@ -46,14 +252,9 @@ func (m *RedisDelayedQueueManager) Schedule(ctx context.Context, queueName strin
 	}
 
 	// Score là Unix timestamp
-	score := float64(executeAt.Unix())
-
-	err = m.client.ZAdd(ctx, queueName, redis.Z{
-		Score:  score,
-		Member: data,
-	}).Err()
+	score := executeAt.Unix()
 
-	if err != nil {
+	if err := script.Run(ctx, m.client, queueKeys(queueName), item.ID, data, score).Err(); err != nil {
 		log.Error().Err(err).Str("queue", queueName).Msg("Failed to schedule delayed item")
 		return err
 	}
@@ -61,6 +262,7 @@ func (m *RedisDelayedQueueManager) Schedule(ctx context.Context, queueName strin
 	// Logging an toàn (không log toàn bộ data nếu nhạy cảm, ở đây log type cho debug)
 	log.Debug().
 		Str("queue", queueName).
+		Str("id", item.ID).
 		Str("type", item.Type).
 		Time("execute_at", executeAt).
 		Msg("Scheduled delayed job")
@@ -68,24 +270,13 @@ func (m *RedisDelayedQueueManager) Schedule(ctx context.Context, queueName strin
 	return nil
 }
 
-// Poll sử dụng Lua script để atomic fetch-and-delete các item đã đến hạn
+// Poll sử dụng Lua script để atomic lease các item đã đến hạn
 func (m *RedisDelayedQueueManager) Poll(ctx context.Context, queueName string, limit int) ([]*DelayedItem, error) {
-	now := float64(time.Now().Unix())
-
-	// Lua script:
-	// 1. ZRANGEBYSCORE key -inf now LIMIT 0 limit
-	// 2. Nếu có item, ZREM key item...
-	// 3. Return items
-	script := redis.NewScript(`
-		local items = redis.call('ZRANGEBYSCORE', KEYS[1], '-inf', ARGV[1], 'LIMIT', 0, ARGV[2])
-		if #items > 0 then
-			redis.call('ZREM', KEYS[1], unpack(items))
-		end
-		return items
-	`)
+	now := time.Now()
+	leaseUntil := now.Add(m.visibilityTimeout)
 
-	// Run script
-	result, err := script.Run(ctx, m.client, []string{queueName}, now, limit).Result()
+	result, err := pollScript.Run(ctx, m.client, queueKeys(queueName),
+		now.Unix(), limit, leaseUntil.Unix(), m.maxAttempts).StringSlice()
 	if err != nil {
 		if err == redis.Nil {
 			return []*DelayedItem{}, nil
@@ -94,27 +285,157 @@ func (m *RedisDelayedQueueManager) Poll(ctx context.Context, queueName string, l
 		return nil, err
 	}
 
-	// Parse result
-	paramsInterface, ok := result.([]interface{})
-	if !ok {
-		// Trường hợp trả về rỗng hoặc format lạ
-		return []*DelayedItem{}, nil
+	items := make([]*DelayedItem, 0, len(result)/3)
+	for i := 0; i+2 < len(result); i += 3 {
+		id, data := result[i], result[i+1]
+		item, err := decodeItem(id, data)
+		if err != nil {
+			log.Error().Err(err).Str("queue", queueName).Str("id", id).Msg("Failed to unmarshal delayed item")
+			// Item hỏng không bao giờ xử lý được, chuyển thẳng vào dead-letter
+			if nackErr := m.Nack(ctx, queueName, &DelayedItem{ID: id}, Permanent(err)); nackErr != nil {
+				log.Error().Err(nackErr).Str("queue", queueName).Str("id", id).Msg("Failed to dead-letter malformed item")
+			}
+			continue
+		}
+		fmt.Sscan(result[i+2], &item.Attempts)
+		items = append(items, item)
 	}
 
-	var items []*DelayedItem
-	for _, p := range paramsInterface {
-		str, ok := p.(string)
-		if !ok {
-			continue
+	return items, nil
+}
+
+// decodeItem đọc item theo ID. Các item được lên lịch trước khi có idempotency key
+// lưu thẳng JSON làm member của ZSET, khi đó chính member là ID.
+func decodeItem(id, data string) (*DelayedItem, error) {
+	if data == "" && strings.HasPrefix(id, "{") {
+		data = id
+	}
+	if data == "" {
+		return nil, fmt.Errorf("item %s has no data", id)
+	}
+
+	var item DelayedItem
+	if err := json.Unmarshal([]byte(data), &item); err != nil {
+		return nil, err
+	}
+	item.ID = id
+	return &item, nil
+}
+
+// Ack xác nhận item đã xử lý xong
+func (m *RedisDelayedQueueManager) Ack(ctx context.Context, queueName string, item *DelayedItem) error {
+	acked, err := ackScript.Run(ctx, m.client, queueKeys(queueName), item.ID).Int()
+	if err != nil {
+		log.Error().Err(err).Str("queue", queueName).Str("id", item.ID).Msg("Failed to ack delayed item")
+		return err
+	}
+	if acked == 0 {
+		log.Debug().Str("queue", queueName).Str("id", item.ID).Msg("Acked delayed item that is no longer leased (rescheduled or lease expired)")
+	}
+	return nil
+}
+
+// Nack lên lịch thử lại item sau backoff, hoặc chuyển vào dead-letter queue
+func (m *RedisDelayedQueueManager) Nack(ctx context.Context, queueName string, item *DelayedItem, cause error) error {
+	reason := "unknown error"
+	if cause != nil {
+		reason = cause.Error()
+	}
+
+	var permanent *permanentError
+	mode, at := "retry", time.Now().Add(m.backoff(item.Attempts))
+	if errors.As(cause, &permanent) || item.Attempts >= m.maxAttempts {
+		mode, at = "dead", time.Now()
+	}
+
+	nacked, err := nackScript.Run(ctx, m.client, queueKeys(queueName), item.ID, reason, mode, at.Unix()).Int()
+	if err != nil {
+		log.Error().Err(err).Str("queue", queueName).Str("id", item.ID).Msg("Failed to nack delayed item")
+		return err
+	}
+	if nacked == 0 {
+		log.Warn().Str("queue", queueName).Str("id", item.ID).Msg("Nacked delayed item that is no longer leased (rescheduled or lease expired)")
+		return nil
+	}
+
+	event := log.Warn()
+	if mode == "dead" {
+		event = log.Error()
+	}
+	event.Str("queue", queueName).
+		Str("id", item.ID).
+		Int("attempts", item.Attempts).
+		Str("outcome", mode).
+		Time("next_at", at).
+		Str("error", reason).
+		Msg("Delayed item failed")
+
+	return nil
+}
+
+// backoff trả về thời gian chờ trước lần thử tiếp theo: retryBackoff * 2^(attempts-1)
+func (m *RedisDelayedQueueManager) backoff(attempts int) time.Duration {
+	delay := m.retryBackoff
+	for i := 1; i < attempts && delay < m.maxRetryBackoff; i++ {
+		delay *= 2
+	}
+	if delay > m.maxRetryBackoff {
+		delay = m.maxRetryBackoff
+	}
+	return delay
+}
+
+// ListDeadLetters trả về tối đa `limit` dead letter, cũ nhất trước
+func (m *RedisDelayedQueueManager) ListDeadLetters(ctx context.Context, queueName string, limit int) ([]*DeadLetter, error) {
+	keys := queueKeys(queueName)
+
+	entries, err := m.client.ZRangeWithScores(ctx, keys[2], 0, int64(limit)-1).Result()
+	if err != nil {
+		log.Error().Err(err).Str("queue", queueName).Msg("Failed to list dead letters")
+		return nil, err
+	}
+
+	letters := make([]*DeadLetter, 0, len(entries))
+	for _, entry := range entries {
+		id, _ := entry.Member.(string)
+
+		pipe := m.client.Pipeline()
+		dataCmd := pipe.HGet(ctx, keys[3], id)
+		attemptsCmd := pipe.HGet(ctx, keys[4], id)
+		errorCmd := pipe.HGet(ctx, keys[5], id)
+		if _, err := pipe.Exec(ctx); err != nil && err != redis.Nil {
+			return nil, err
 		}
 
-		var item DelayedItem
-		if err := json.Unmarshal([]byte(str), &item); err != nil {
-			log.Error().Err(err).Str("raw", str).Msg("Failed to unmarshal delayed item")
-			continue // Skip bad items
+		item, err := decodeItem(id, dataCmd.Val())
+		if err != nil {
+			// Giữ lại dead letter hỏng để admin vẫn thấy được
+			item = &DelayedItem{ID: id}
 		}
-		items = append(items, &item)
+		item.Attempts, _ = attemptsCmd.Int()
+		item.LastError = errorCmd.Val()
+
+		letters = append(letters, &DeadLetter{
+			Queue:  queueName,
+			Item:   item,
+			DeadAt: time.Unix(int64(entry.Score), 0).UTC(),
+		})
 	}
 
-	return items, nil
+	return letters, nil
+}
+
+// ReplayDeadLetter đưa dead letter trở lại hàng đợi để được poll ở lần tiếp theo
+func (m *RedisDelayedQueueManager) ReplayDeadLetter(ctx context.Context, queueName string, id string) error {
+	replayed, err := replayScript.Run(ctx, m.client, queueKeys(queueName), id, time.Now().Unix()).Int()
+	if err != nil {
+		log.Error().Err(err).Str("queue", queueName).Str("id", id).Msg("Failed to replay dead letter")
+		return err
+	}
+	if replayed == 0 {
+		return ErrDeadLetterNotFound
+	}
+
+	log.Info().Str("queue", queueName).Str("id", id).Msg("Replayed dead letter")
+	return nil
 }
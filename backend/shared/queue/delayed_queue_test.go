@@ -0,0 +1,240 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testQueue = "test:delayed"
+
+type delayedQueueTestFixture struct {
+	redis   *miniredis.Miniredis
+	manager *RedisDelayedQueueManager
+}
+
+// newDelayedQueueTestFixture runs the queue against an in-memory Redis. Failed items are retried
+// without backoff so the tests can poll them again at once.
+func newDelayedQueueTestFixture(t *testing.T, maxAttempts int) *delayedQueueTestFixture {
+	t.Helper()
+
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+
+	return &delayedQueueTestFixture{
+		redis: mr,
+		manager: &RedisDelayedQueueManager{
+			client:            client,
+			visibilityTimeout: DefaultVisibilityTimeout,
+			maxAttempts:       maxAttempts,
+			retryBackoff:      0,
+			maxRetryBackoff:   0,
+		},
+	}
+}
+
+// scheduleDue schedules an item that is due now
+func (f *delayedQueueTestFixture) scheduleDue(t *testing.T, id string) {
+	t.Helper()
+
+	item := &DelayedItem{ID: id, Type: "booking_expiry", Payload: map[string]interface{}{"booking_id": id}}
+	require.NoError(t, f.manager.Schedule(context.Background(), testQueue, item, time.Now().Add(-time.Second)))
+}
+
+// poll leases the due items, expecting at most one
+func (f *delayedQueueTestFixture) poll(t *testing.T) *DelayedItem {
+	t.Helper()
+
+	items, err := f.manager.Poll(context.Background(), testQueue, 10)
+	require.NoError(t, err)
+	require.LessOrEqual(t, len(items), 1)
+	if len(items) == 0 {
+		return nil
+	}
+	return items[0]
+}
+
+// expireLease makes the lease on the item run out, as when its worker dies without acking
+func (f *delayedQueueTestFixture) expireLease(t *testing.T, id string) {
+	t.Helper()
+
+	_, err := f.redis.ZAdd(testQueue+":inflight", float64(time.Now().Add(-time.Second).Unix()), id)
+	require.NoError(t, err)
+}
+
+func TestPoll_RedeliversItemAfterLeaseExpires(t *testing.T) {
+	f := newDelayedQueueTestFixture(t, DefaultMaxAttempts)
+	f.scheduleDue(t, "booking-1")
+
+	item := f.poll(t)
+	require.NotNil(t, item)
+	assert.Equal(t, "booking-1", item.ID)
+	assert.Equal(t, "booking_expiry", item.Type)
+	assert.Equal(t, map[string]interface{}{"booking_id": "booking-1"}, item.Payload)
+	assert.Equal(t, 1, item.Attempts)
+
+	// Leased to a worker, so not delivered again while the lease runs
+	assert.Nil(t, f.poll(t))
+
+	f.expireLease(t, "booking-1")
+
+	item = f.poll(t)
+	require.NotNil(t, item)
+	assert.Equal(t, "booking-1", item.ID)
+	assert.Equal(t, 2, item.Attempts)
+
+	require.NoError(t, f.manager.Ack(context.Background(), testQueue, item))
+	assert.Nil(t, f.poll(t))
+	for _, key := range queueKeys(testQueue) {
+		assert.False(t, f.redis.Exists(key), key)
+	}
+}
+
+func TestPoll_DeadLettersExpiredLeaseAfterMaxAttempts(t *testing.T) {
+	f := newDelayedQueueTestFixture(t, 2)
+	f.scheduleDue(t, "booking-1")
+
+	require.NotNil(t, f.poll(t))
+	f.expireLease(t, "booking-1")
+	require.NotNil(t, f.poll(t))
+	f.expireLease(t, "booking-1")
+
+	assert.Nil(t, f.poll(t))
+
+	letters, err := f.manager.ListDeadLetters(context.Background(), testQueue, 10)
+	require.NoError(t, err)
+	require.Len(t, letters, 1)
+	assert.Equal(t, testQueue, letters[0].Queue)
+	assert.Equal(t, "booking-1", letters[0].Item.ID)
+	assert.Equal(t, 2, letters[0].Item.Attempts)
+	assert.Equal(t, "lease expired without ack", letters[0].Item.LastError)
+}
+
+func TestNack_RetriesUntilMaxAttemptsThenDeadLetters(t *testing.T) {
+	f := newDelayedQueueTestFixture(t, 2)
+	ctx := context.Background()
+	f.scheduleDue(t, "booking-1")
+
+	item := f.poll(t)
+	require.NotNil(t, item)
+	require.NoError(t, f.manager.Nack(ctx, testQueue, item, errors.New("payment service unavailable")))
+
+	item = f.poll(t)
+	require.NotNil(t, item)
+	assert.Equal(t, 2, item.Attempts)
+	require.NoError(t, f.manager.Nack(ctx, testQueue, item, errors.New("payment service still unavailable")))
+
+	assert.Nil(t, f.poll(t))
+
+	letters, err := f.manager.ListDeadLetters(ctx, testQueue, 10)
+	require.NoError(t, err)
+	require.Len(t, letters, 1)
+	assert.Equal(t, 2, letters[0].Item.Attempts)
+	assert.Equal(t, "payment service still unavailable", letters[0].Item.LastError)
+}
+
+func TestNack_PermanentErrorDeadLettersAtOnce(t *testing.T) {
+	f := newDelayedQueueTestFixture(t, DefaultMaxAttempts)
+	ctx := context.Background()
+	f.scheduleDue(t, "booking-1")
+
+	item := f.poll(t)
+	require.NotNil(t, item)
+	require.NoError(t, f.manager.Nack(ctx, testQueue, item, Permanent(errors.New("booking not found"))))
+
+	assert.Nil(t, f.poll(t))
+
+	letters, err := f.manager.ListDeadLetters(ctx, testQueue, 10)
+	require.NoError(t, err)
+	require.Len(t, letters, 1)
+	assert.Equal(t, 1, letters[0].Item.Attempts)
+	assert.Equal(t, "booking not found", letters[0].Item.LastError)
+}
+
+func TestReplayDeadLetter_RequeuesWithAttemptsReset(t *testing.T) {
+	f := newDelayedQueueTestFixture(t, DefaultMaxAttempts)
+	ctx := context.Background()
+	f.scheduleDue(t, "booking-1")
+
+	item := f.poll(t)
+	require.NotNil(t, item)
+	require.NoError(t, f.manager.Nack(ctx, testQueue, item, Permanent(errors.New("booking not found"))))
+
+	require.NoError(t, f.manager.ReplayDeadLetter(ctx, testQueue, "booking-1"))
+
+	letters, err := f.manager.ListDeadLetters(ctx, testQueue, 10)
+	require.NoError(t, err)
+	assert.Empty(t, letters)
+
+	item = f.poll(t)
+	require.NotNil(t, item)
+	assert.Equal(t, "booking-1", item.ID)
+	assert.Equal(t, map[string]interface{}{"booking_id": "booking-1"}, item.Payload)
+	assert.Equal(t, 1, item.Attempts)
+	assert.Empty(t, item.LastError)
+
+	// Replaying again finds nothing: the item is back in the queue, not dead
+	assert.ErrorIs(t, f.manager.ReplayDeadLetter(ctx, testQueue, "booking-1"), ErrDeadLetterNotFound)
+}
+
+func TestSchedule_ReschedulingLeasedItemSurvivesAckOfOldLease(t *testing.T) {
+	f := newDelayedQueueTestFixture(t, DefaultMaxAttempts)
+	ctx := context.Background()
+	f.scheduleDue(t, "booking-1")
+
+	leased := f.poll(t)
+	require.NotNil(t, leased)
+
+	// The same ID replaces the item instead of adding a second one
+	f.scheduleDue(t, "booking-1")
+	require.NoError(t, f.manager.Ack(ctx, testQueue, leased))
+
+	item := f.poll(t)
+	require.NotNil(t, item)
+	assert.Equal(t, "booking-1", item.ID)
+	assert.Equal(t, 1, item.Attempts)
+}
+
+func TestScheduleEarliest_OnlyMovesPendingItemEarlier(t *testing.T) {
+	f := newDelayedQueueTestFixture(t, DefaultMaxAttempts)
+	ctx := context.Background()
+	now := time.Now()
+	item := &DelayedItem{ID: "trip-1", Type: "waitlist_offer"}
+
+	require.NoError(t, f.manager.ScheduleEarliest(ctx, testQueue, item, now.Add(time.Hour)))
+	require.NoError(t, f.manager.ScheduleEarliest(ctx, testQueue, item, now.Add(2*time.Hour)))
+
+	score, err := f.redis.ZScore(testQueue, "trip-1")
+	require.NoError(t, err)
+	assert.Equal(t, float64(now.Add(time.Hour).Unix()), score)
+
+	require.NoError(t, f.manager.ScheduleEarliest(ctx, testQueue, item, now.Add(-time.Second)))
+	item = f.poll(t)
+	require.NotNil(t, item)
+	assert.Equal(t, "trip-1", item.ID)
+}
+
+func TestScheduleEarliest_ReschedulesLeasedItem(t *testing.T) {
+	f := newDelayedQueueTestFixture(t, DefaultMaxAttempts)
+	ctx := context.Background()
+	f.scheduleDue(t, "trip-1")
+
+	leased := f.poll(t)
+	require.NotNil(t, leased)
+
+	// The run in progress may have missed what the new schedule is for, so it runs again
+	later := time.Now().Add(time.Hour)
+	require.NoError(t, f.manager.ScheduleEarliest(ctx, testQueue, &DelayedItem{ID: "trip-1"}, later))
+	require.NoError(t, f.manager.Ack(ctx, testQueue, leased))
+
+	score, err := f.redis.ZScore(testQueue, "trip-1")
+	require.NoError(t, err)
+	assert.Equal(t, float64(later.Unix()), score)
+}
@@ -36,6 +36,49 @@ func (m *MockDelayedQueueManager) EXPECT() *MockDelayedQueueManagerMockRecorder
 	return m.recorder
 }
 
+// Ack mocks base method.
+func (m *MockDelayedQueueManager) Ack(ctx context.Context, queueName string, item *queue.DelayedItem) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Ack", ctx, queueName, item)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Ack indicates an expected call of Ack.
+func (mr *MockDelayedQueueManagerMockRecorder) Ack(ctx, queueName, item interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Ack", reflect.TypeOf((*MockDelayedQueueManager)(nil).Ack), ctx, queueName, item)
+}
+
+// ListDeadLetters mocks base method.
+func (m *MockDelayedQueueManager) ListDeadLetters(ctx context.Context, queueName string, limit int) ([]*queue.DeadLetter, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDeadLetters", ctx, queueName, limit)
+	ret0, _ := ret[0].([]*queue.DeadLetter)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDeadLetters indicates an expected call of ListDeadLetters.
+func (mr *MockDelayedQueueManagerMockRecorder) ListDeadLetters(ctx, queueName, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDeadLetters", reflect.TypeOf((*MockDelayedQueueManager)(nil).ListDeadLetters), ctx, queueName, limit)
+}
+
+// Nack mocks base method.
+func (m *MockDelayedQueueManager) Nack(ctx context.Context, queueName string, item *queue.DelayedItem, cause error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Nack", ctx, queueName, item, cause)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Nack indicates an expected call of Nack.
+func (mr *MockDelayedQueueManagerMockRecorder) Nack(ctx, queueName, item, cause interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Nack", reflect.TypeOf((*MockDelayedQueueManager)(nil).Nack), ctx, queueName, item, cause)
+}
+
 // Poll mocks base method.
 func (m *MockDelayedQueueManager) Poll(ctx context.Context, queueName string, limit int) ([]*queue.DelayedItem, error) {
 	m.ctrl.T.Helper()
@@ -51,6 +94,20 @@ func (mr *MockDelayedQueueManagerMockRecorder) Poll(ctx, queueName, limit interf
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Poll", reflect.TypeOf((*MockDelayedQueueManager)(nil).Poll), ctx, queueName, limit)
 }
 
+// ReplayDeadLetter mocks base method.
+func (m *MockDelayedQueueManager) ReplayDeadLetter(ctx context.Context, queueName, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReplayDeadLetter", ctx, queueName, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReplayDeadLetter indicates an expected call of ReplayDeadLetter.
+func (mr *MockDelayedQueueManagerMockRecorder) ReplayDeadLetter(ctx, queueName, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplayDeadLetter", reflect.TypeOf((*MockDelayedQueueManager)(nil).ReplayDeadLetter), ctx, queueName, id)
+}
+
 // Schedule mocks base method.
 func (m *MockDelayedQueueManager) Schedule(ctx context.Context, queueName string, item *queue.DelayedItem, executeAt time.Time) error {
 	m.ctrl.T.Helper()
@@ -64,3 +121,17 @@ func (mr *MockDelayedQueueManagerMockRecorder) Schedule(ctx, queueName, item, ex
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Schedule", reflect.TypeOf((*MockDelayedQueueManager)(nil).Schedule), ctx, queueName, item, executeAt)
 }
+
+// ScheduleEarliest mocks base method.
+func (m *MockDelayedQueueManager) ScheduleEarliest(ctx context.Context, queueName string, item *queue.DelayedItem, executeAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ScheduleEarliest", ctx, queueName, item, executeAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ScheduleEarliest indicates an expected call of ScheduleEarliest.
+func (mr *MockDelayedQueueManagerMockRecorder) ScheduleEarliest(ctx, queueName, item, executeAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ScheduleEarliest", reflect.TypeOf((*MockDelayedQueueManager)(nil).ScheduleEarliest), ctx, queueName, item, executeAt)
+}
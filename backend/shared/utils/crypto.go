@@ -1,7 +1,9 @@
 package utils
 
 import (
+	"crypto/hmac"
 	"crypto/rand"
+	"crypto/sha256"
 	"crypto/subtle"
 	"encoding/base64"
 	"fmt"
@@ -132,3 +134,22 @@ func GenerateRandomBytes(length int) ([]byte, error) {
 	_, err := rand.Read(bytes)
 	return bytes, err
 }
+
+// SignHMAC returns the unpadded base64url HMAC-SHA256 of message keyed with secret
+func SignHMAC(secret, message string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(message))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// VerifyHMAC checks a SignHMAC signature using constant time comparison
+func VerifyHMAC(secret, message, signature string) bool {
+	expected, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(message))
+	return hmac.Equal(expected, mac.Sum(nil))
+}
@@ -8,6 +8,7 @@ type Config struct {
 	*sharedConfig.BaseConfig
 	External ExternalConfig `envPrefix:"EXTERNAL_"`
 	Storage  sharedConfig.StorageConfig
+	Schedule ScheduleConfig `envPrefix:"SCHEDULE_"`
 }
 
 type ExternalConfig struct {
@@ -16,6 +17,10 @@ type ExternalConfig struct {
 	PaymentServiceURL string `env:"PAYMENT_SERVICE_URL" envDefault:"http://localhost:8085"`
 }
 
+type ScheduleConfig struct {
+	GenerateDaysAhead int `env:"GENERATE_DAYS_AHEAD" envDefault:"14"`
+}
+
 func LoadConfig(envFilePath ...string) (*Config, error) {
 	return sharedConfig.LoadConfig[Config](envFilePath...)
 }
@@ -3,6 +3,7 @@ package client
 import (
 	"context"
 	"fmt"
+	"strconv"
 
 	"bus-booking/shared/client"
 	"bus-booking/trip-service/internal/model/booking"
@@ -12,9 +13,10 @@ import (
 )
 
 type BookingClient interface {
-	GetSeatStatus(ctx context.Context, tripID uuid.UUID, seatIDs []uuid.UUID) ([]booking.SeatStatus, error)
-	GetTripBookings(ctx context.Context, tripID uuid.UUID) ([]*booking.Booking, error)
-	CancelBooking(ctx context.Context, bookingID uuid.UUID, reason string) error
+	GetSeatStatus(ctx context.Context, tripID uuid.UUID, seatIDs []uuid.UUID, segment *booking.TripSegment) ([]booking.SeatStatus, error)
+	GetActiveTripBookings(ctx context.Context, tripID uuid.UUID) ([]*booking.Booking, error)
+	CancelForTrip(ctx context.Context, bookingID uuid.UUID, reason string) error
+	NotifyTripCancelled(ctx context.Context, bookingID uuid.UUID, req *booking.NotifyTripCancelledRequest) error
 }
 
 type bookingClientImpl struct {
@@ -32,7 +34,7 @@ func NewBookingClient(serviceName, baseURL string) BookingClient {
 	}
 }
 
-func (c *bookingClientImpl) GetSeatStatus(ctx context.Context, tripID uuid.UUID, seatIDs []uuid.UUID) ([]booking.SeatStatus, error) {
+func (c *bookingClientImpl) GetSeatStatus(ctx context.Context, tripID uuid.UUID, seatIDs []uuid.UUID, segment *booking.TripSegment) ([]booking.SeatStatus, error) {
 	if len(seatIDs) == 0 {
 		return []booking.SeatStatus{}, nil
 	}
@@ -44,6 +46,10 @@ func (c *bookingClientImpl) GetSeatStatus(ctx context.Context, tripID uuid.UUID,
 		seatIDStrings[i] = id.String()
 	}
 	params["seat_ids"] = seatIDStrings
+	if segment != nil {
+		params["from_stop_order"] = []string{strconv.Itoa(segment.FromStopOrder)}
+		params["to_stop_order"] = []string{strconv.Itoa(segment.ToStopOrder)}
+	}
 
 	url := fmt.Sprintf("/api/v1/bookings/trips/%s/seats/status", tripID)
 
@@ -60,38 +66,47 @@ func (c *bookingClientImpl) GetSeatStatus(ctx context.Context, tripID uuid.UUID,
 	return seatStatuses, nil
 }
 
-func (c *bookingClientImpl) GetTripBookings(ctx context.Context, tripID uuid.UUID) ([]*booking.Booking, error) {
-	// Fetch all bookings (using larger limit)
-	params := map[string][]string{
-		"page":  {"1"},
-		"limit": {"1000"},
-	}
-
-	url := fmt.Sprintf("/api/v1/bookings/trip/%s", tripID)
-	resp, err := c.httpClient.Get(ctx, url, params, nil)
+func (c *bookingClientImpl) GetActiveTripBookings(ctx context.Context, tripID uuid.UUID) ([]*booking.Booking, error) {
+	url := fmt.Sprintf("/api/v1/bookings/trips/%s/active", tripID)
+	resp, err := c.httpClient.Get(ctx, url, nil, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get trip bookings: %w", err)
 	}
 
-	// Parse paginated response
-	paginatedResp, err := client.ParseData[booking.BookingListResponse](resp)
+	bookings, err := client.ParseListData[*booking.Booking](resp)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse booking list: %w", err)
+		return nil, fmt.Errorf("failed to parse trip bookings: %w", err)
 	}
 
-	return paginatedResp.Data, nil
+	return bookings, nil
 }
 
-func (c *bookingClientImpl) CancelBooking(ctx context.Context, bookingID uuid.UUID, reason string) error {
+func (c *bookingClientImpl) CancelForTrip(ctx context.Context, bookingID uuid.UUID, reason string) error {
 	req := booking.CancelBookingRequest{
 		Reason: reason,
 	}
 
-	url := fmt.Sprintf("/api/v1/bookings/%s/cancel", bookingID)
-	_, err := c.httpClient.Post(ctx, url, req, nil)
+	url := fmt.Sprintf("/api/v1/bookings/%s/trip-cancelled", bookingID)
+	resp, err := c.httpClient.Post(ctx, url, req, nil)
 	if err != nil {
 		return fmt.Errorf("failed to cancel booking: %w", err)
 	}
+	if !resp.IsSuccess() {
+		return fmt.Errorf("failed to cancel booking: booking service responded %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+func (c *bookingClientImpl) NotifyTripCancelled(ctx context.Context, bookingID uuid.UUID, req *booking.NotifyTripCancelledRequest) error {
+	url := fmt.Sprintf("/api/v1/bookings/%s/notify-trip-cancelled", bookingID)
+	resp, err := c.httpClient.Post(ctx, url, req, nil)
+	if err != nil {
+		return fmt.Errorf("failed to notify passenger: %w", err)
+	}
+	if !resp.IsSuccess() {
+		return fmt.Errorf("failed to notify passenger: booking service responded %d", resp.StatusCode)
+	}
 
 	return nil
 }
@@ -36,46 +36,60 @@ func (m *MockBookingClient) EXPECT() *MockBookingClientMockRecorder {
 	return m.recorder
 }
 
-// GetSeatStatus mocks base method.
-func (m *MockBookingClient) GetSeatStatus(ctx context.Context, tripID uuid.UUID, seatIDs []uuid.UUID) ([]booking.SeatStatus, error) {
+// CancelForTrip mocks base method.
+func (m *MockBookingClient) CancelForTrip(ctx context.Context, bookingID uuid.UUID, reason string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetSeatStatus", ctx, tripID, seatIDs)
-	ret0, _ := ret[0].([]booking.SeatStatus)
+	ret := m.ctrl.Call(m, "CancelForTrip", ctx, bookingID, reason)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CancelForTrip indicates an expected call of CancelForTrip.
+func (mr *MockBookingClientMockRecorder) CancelForTrip(ctx, bookingID, reason interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelForTrip", reflect.TypeOf((*MockBookingClient)(nil).CancelForTrip), ctx, bookingID, reason)
+}
+
+// GetActiveTripBookings mocks base method.
+func (m *MockBookingClient) GetActiveTripBookings(ctx context.Context, tripID uuid.UUID) ([]*booking.Booking, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetActiveTripBookings", ctx, tripID)
+	ret0, _ := ret[0].([]*booking.Booking)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetSeatStatus indicates an expected call of GetSeatStatus.
-func (mr *MockBookingClientMockRecorder) GetSeatStatus(ctx, tripID, seatIDs interface{}) *gomock.Call {
+// GetActiveTripBookings indicates an expected call of GetActiveTripBookings.
+func (mr *MockBookingClientMockRecorder) GetActiveTripBookings(ctx, tripID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSeatStatus", reflect.TypeOf((*MockBookingClient)(nil).GetSeatStatus), ctx, tripID, seatIDs)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActiveTripBookings", reflect.TypeOf((*MockBookingClient)(nil).GetActiveTripBookings), ctx, tripID)
 }
 
-// GetTripBookings mocks base method.
-func (m *MockBookingClient) GetTripBookings(ctx context.Context, tripID uuid.UUID) ([]*booking.Booking, error) {
+// GetSeatStatus mocks base method.
+func (m *MockBookingClient) GetSeatStatus(ctx context.Context, tripID uuid.UUID, seatIDs []uuid.UUID, segment *booking.TripSegment) ([]booking.SeatStatus, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetTripBookings", ctx, tripID)
-	ret0, _ := ret[0].([]*booking.Booking)
+	ret := m.ctrl.Call(m, "GetSeatStatus", ctx, tripID, seatIDs, segment)
+	ret0, _ := ret[0].([]booking.SeatStatus)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetTripBookings indicates an expected call of GetTripBookings.
-func (mr *MockBookingClientMockRecorder) GetTripBookings(ctx, tripID interface{}) *gomock.Call {
+// GetSeatStatus indicates an expected call of GetSeatStatus.
+func (mr *MockBookingClientMockRecorder) GetSeatStatus(ctx, tripID, seatIDs, segment interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTripBookings", reflect.TypeOf((*MockBookingClient)(nil).GetTripBookings), ctx, tripID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSeatStatus", reflect.TypeOf((*MockBookingClient)(nil).GetSeatStatus), ctx, tripID, seatIDs, segment)
 }
 
-// CancelBooking mocks base method.
-func (m *MockBookingClient) CancelBooking(ctx context.Context, bookingID uuid.UUID, reason string) error {
+// NotifyTripCancelled mocks base method.
+func (m *MockBookingClient) NotifyTripCancelled(ctx context.Context, bookingID uuid.UUID, req *booking.NotifyTripCancelledRequest) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CancelBooking", ctx, bookingID, reason)
+	ret := m.ctrl.Call(m, "NotifyTripCancelled", ctx, bookingID, req)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// CancelBooking indicates an expected call of CancelBooking.
-func (mr *MockBookingClientMockRecorder) CancelBooking(ctx, bookingID, reason interface{}) *gomock.Call {
+// NotifyTripCancelled indicates an expected call of NotifyTripCancelled.
+func (mr *MockBookingClientMockRecorder) NotifyTripCancelled(ctx, bookingID, req interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelBooking", reflect.TypeOf((*MockBookingClient)(nil).CancelBooking), ctx, bookingID, reason)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NotifyTripCancelled", reflect.TypeOf((*MockBookingClient)(nil).NotifyTripCancelled), ctx, bookingID, req)
 }
@@ -10,6 +10,7 @@ import (
 	reflect "reflect"
 
 	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
 )
 
 // MockPaymentClient is a mock of PaymentClient interface.
@@ -35,17 +36,47 @@ func (m *MockPaymentClient) EXPECT() *MockPaymentClientMockRecorder {
 	return m.recorder
 }
 
-// CreateRefund mocks base method.
-func (m *MockPaymentClient) CreateRefund(ctx context.Context, req *payment.RefundRequest) (*payment.RefundResponse, error) {
+// CreateOperatorRefund mocks base method.
+func (m *MockPaymentClient) CreateOperatorRefund(ctx context.Context, req *payment.RefundRequest) (*payment.RefundResponse, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateRefund", ctx, req)
+	ret := m.ctrl.Call(m, "CreateOperatorRefund", ctx, req)
 	ret0, _ := ret[0].(*payment.RefundResponse)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// CreateRefund indicates an expected call of CreateRefund.
-func (mr *MockPaymentClientMockRecorder) CreateRefund(ctx, req interface{}) *gomock.Call {
+// CreateOperatorRefund indicates an expected call of CreateOperatorRefund.
+func (mr *MockPaymentClientMockRecorder) CreateOperatorRefund(ctx, req interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRefund", reflect.TypeOf((*MockPaymentClient)(nil).CreateRefund), ctx, req)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOperatorRefund", reflect.TypeOf((*MockPaymentClient)(nil).CreateOperatorRefund), ctx, req)
+}
+
+// GetBookingRefund mocks base method.
+func (m *MockPaymentClient) GetBookingRefund(ctx context.Context, bookingID uuid.UUID) (*payment.RefundResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBookingRefund", ctx, bookingID)
+	ret0, _ := ret[0].(*payment.RefundResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBookingRefund indicates an expected call of GetBookingRefund.
+func (mr *MockPaymentClientMockRecorder) GetBookingRefund(ctx, bookingID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBookingRefund", reflect.TypeOf((*MockPaymentClient)(nil).GetBookingRefund), ctx, bookingID)
+}
+
+// GetRefundStatus mocks base method.
+func (m *MockPaymentClient) GetRefundStatus(ctx context.Context, refundID uuid.UUID) (*payment.RefundResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRefundStatus", ctx, refundID)
+	ret0, _ := ret[0].(*payment.RefundResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRefundStatus indicates an expected call of GetRefundStatus.
+func (mr *MockPaymentClientMockRecorder) GetRefundStatus(ctx, refundID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRefundStatus", reflect.TypeOf((*MockPaymentClient)(nil).GetRefundStatus), ctx, refundID)
 }
@@ -2,14 +2,24 @@ package client
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net/http"
 
 	"bus-booking/shared/client"
 	"bus-booking/trip-service/internal/model/payment"
+
+	"github.com/google/uuid"
 )
 
+// ErrRefundAlreadyExists is returned when payment service already holds a refund for the booking
+var ErrRefundAlreadyExists = errors.New("refund already exists for this booking")
+
 type PaymentClient interface {
-	CreateRefund(ctx context.Context, req *payment.RefundRequest) (*payment.RefundResponse, error)
+	CreateOperatorRefund(ctx context.Context, req *payment.RefundRequest) (*payment.RefundResponse, error)
+	GetBookingRefund(ctx context.Context, bookingID uuid.UUID) (*payment.RefundResponse, error)
+	// GetRefundStatus returns a refund summed with the parts split off it, completed once all are paid back
+	GetRefundStatus(ctx context.Context, refundID uuid.UUID) (*payment.RefundResponse, error)
 }
 
 type paymentClientImpl struct {
@@ -27,12 +37,45 @@ func NewPaymentClient(serviceName, baseURL string) PaymentClient {
 	}
 }
 
-func (c *paymentClientImpl) CreateRefund(ctx context.Context, req *payment.RefundRequest) (*payment.RefundResponse, error) {
-	url := "/api/v1/refunds"
+func (c *paymentClientImpl) CreateOperatorRefund(ctx context.Context, req *payment.RefundRequest) (*payment.RefundResponse, error) {
+	url := "/api/v1/refunds/operator"
 	resp, err := c.httpClient.Post(ctx, url, req, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create refund: %w", err)
 	}
+	if resp.StatusCode == http.StatusConflict {
+		return nil, ErrRefundAlreadyExists
+	}
+
+	refund, err := client.ParseData[payment.RefundResponse](resp)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse refund response: %w", err)
+	}
+
+	return refund, nil
+}
+
+func (c *paymentClientImpl) GetBookingRefund(ctx context.Context, bookingID uuid.UUID) (*payment.RefundResponse, error) {
+	url := fmt.Sprintf("/api/v1/refunds/booking/%s/status", bookingID)
+	resp, err := c.httpClient.Get(ctx, url, nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get refund: %w", err)
+	}
+
+	refund, err := client.ParseData[payment.RefundResponse](resp)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse refund response: %w", err)
+	}
+
+	return refund, nil
+}
+
+func (c *paymentClientImpl) GetRefundStatus(ctx context.Context, refundID uuid.UUID) (*payment.RefundResponse, error) {
+	url := fmt.Sprintf("/api/v1/refunds/%s/status", refundID)
+	resp, err := c.httpClient.Get(ctx, url, nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get refund status: %w", err)
+	}
 
 	refund, err := client.ParseData[payment.RefundResponse](resp)
 	if err != nil {
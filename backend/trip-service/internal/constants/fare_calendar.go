@@ -0,0 +1,21 @@
+package constants
+
+import "time"
+
+const (
+	// FareCalendarMonthFormat is the layout of a fare calendar month
+	FareCalendarMonthFormat = "2006-01"
+	// FareCalendarMonthsAhead is how many months after the current one can be browsed
+	FareCalendarMonthsAhead = 12
+	// FareCalendarPrecomputeMonths is how many months, the current one included, are
+	// precomputed for every active route
+	FareCalendarPrecomputeMonths = 3
+	// FareCalendarRefreshInterval is how often the precompute cronjob runs. Bookings change
+	// seat availability and load-based fares without invalidating calendars, so this also
+	// bounds how stale a cached calendar gets.
+	FareCalendarRefreshInterval = 15 * time.Minute
+	// FareCalendarMaxTrips caps the departures read for one calendar month
+	FareCalendarMaxTrips = 2000
+	// FareCalendarRoutePageSize is how many routes the precompute cronjob loads at a time
+	FareCalendarRoutePageSize = 100
+)
@@ -0,0 +1,24 @@
+package constants
+
+import "time"
+
+const (
+	// JourneyMaxTransfers caps a connecting journey at three trips
+	JourneyMaxTransfers = 2
+	// JourneyDefaultMinTransferMinutes is the shortest change between trips when not requested
+	JourneyDefaultMinTransferMinutes = 30
+	// JourneyDefaultMaxTransferMinutes is the longest wait at a transfer point when not requested
+	JourneyDefaultMaxTransferMinutes = 6 * 60
+	// JourneyMaxTransferMinutes caps a requested transfer window
+	JourneyMaxTransferMinutes = 24 * 60
+	// JourneySearchHorizon covers connecting trips departing after the requested day ends
+	JourneySearchHorizon = 36 * time.Hour
+	// JourneyDefaultLimit is how many journeys are returned when not requested
+	JourneyDefaultLimit = 20
+)
+
+const (
+	JourneySortByDuration  = "duration"
+	JourneySortByPrice     = "price"
+	JourneySortByTransfers = "transfers"
+)
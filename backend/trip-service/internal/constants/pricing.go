@@ -0,0 +1,8 @@
+package constants
+
+const (
+	// FareRoundingUnit is what dynamic fares are rounded to, in VND
+	FareRoundingUnit = 1000
+	// PricingMaxMultiplier caps the multiplier of a single pricing rule
+	PricingMaxMultiplier = 5.0
+)
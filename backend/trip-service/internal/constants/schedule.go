@@ -0,0 +1,22 @@
+package constants
+
+import "time"
+
+const (
+	// ScheduleTimeZone is the zone schedule departure times are expressed in
+	ScheduleTimeZone = "Asia/Ho_Chi_Minh"
+	// ScheduleTimeFormat is the layout of a schedule departure time of day
+	ScheduleTimeFormat = "15:04"
+	// ScheduleDateFormat is the layout of schedule validity dates
+	ScheduleDateFormat = "2006-01-02"
+
+	// ScheduleConflictLookback covers trips that departed before the generation window
+	// but may still be on the road when a generated trip departs
+	ScheduleConflictLookback = 48 * time.Hour
+	// ScheduleGenerationInterval is how often the generator cronjob runs
+	ScheduleGenerationInterval = 1 * time.Hour
+	// ScheduleDefaultDaysAhead is how far ahead trips are generated when not configured
+	ScheduleDefaultDaysAhead = 14
+	// ScheduleMaxDaysAhead caps a single generator run
+	ScheduleMaxDaysAhead = 90
+)
@@ -0,0 +1,59 @@
+package cronjob
+
+import (
+	"context"
+	"time"
+
+	"bus-booking/trip-service/internal/constants"
+	"bus-booking/trip-service/internal/service"
+
+	"github.com/rs/zerolog/log"
+)
+
+// FareCalendarCronJob keeps the fare calendars of every active route precomputed
+type FareCalendarCronJob struct {
+	fareCalendarSvc service.FareCalendarService
+	stopChan        chan struct{}
+}
+
+func NewFareCalendarCronJob(fareCalendarSvc service.FareCalendarService) *FareCalendarCronJob {
+	return &FareCalendarCronJob{
+		fareCalendarSvc: fareCalendarSvc,
+		stopChan:        make(chan struct{}),
+	}
+}
+
+// Start begins the cronjob worker - runs every constants.FareCalendarRefreshInterval
+func (c *FareCalendarCronJob) Start(ctx context.Context) {
+	log.Info().Msg("Starting fare calendar cronjob worker")
+
+	// Run immediately on start
+	c.precompute(ctx)
+
+	ticker := time.NewTicker(constants.FareCalendarRefreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Info().Msg("Fare calendar cronjob context cancelled, stopping...")
+			return
+		case <-c.stopChan:
+			log.Info().Msg("Fare calendar cronjob stopped")
+			return
+		case <-ticker.C:
+			c.precompute(ctx)
+		}
+	}
+}
+
+// Stop stops the cronjob worker
+func (c *FareCalendarCronJob) Stop() {
+	close(c.stopChan)
+}
+
+func (c *FareCalendarCronJob) precompute(ctx context.Context) {
+	if err := c.fareCalendarSvc.PrecomputeCalendars(ctx); err != nil {
+		log.Error().Err(err).Msg("Failed to precompute fare calendars")
+	}
+}
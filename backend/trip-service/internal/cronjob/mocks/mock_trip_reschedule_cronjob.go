@@ -1,5 +0,0 @@
-// Code generated by MockGen. DO NOT EDIT.
-// Source: internal/cronjob/trip_reschedule_cronjob.go
-
-// Package mocks is a generated GoMock package.
-package mocks
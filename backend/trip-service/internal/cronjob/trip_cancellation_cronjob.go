@@ -0,0 +1,58 @@
+package cronjob
+
+import (
+	"context"
+	"time"
+
+	"bus-booking/trip-service/internal/service"
+
+	"github.com/rs/zerolog/log"
+)
+
+// TripCancellationCronJob resumes trip cancellation sagas left running by a crash or waiting for refunds
+type TripCancellationCronJob struct {
+	cancellationSvc service.TripCancellationService
+	stopChan        chan struct{}
+}
+
+func NewTripCancellationCronJob(cancellationSvc service.TripCancellationService) *TripCancellationCronJob {
+	return &TripCancellationCronJob{
+		cancellationSvc: cancellationSvc,
+		stopChan:        make(chan struct{}),
+	}
+}
+
+// Start begins the cronjob worker - runs every 1 minute
+func (c *TripCancellationCronJob) Start(ctx context.Context) {
+	log.Info().Msg("Starting trip cancellation cronjob worker")
+
+	// Run immediately on start to resume sagas interrupted by a restart
+	c.resume(ctx)
+
+	ticker := time.NewTicker(1 * time.Minute)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Info().Msg("Trip cancellation cronjob context cancelled, stopping...")
+			return
+		case <-c.stopChan:
+			log.Info().Msg("Trip cancellation cronjob stopped")
+			return
+		case <-ticker.C:
+			c.resume(ctx)
+		}
+	}
+}
+
+// Stop stops the cronjob worker
+func (c *TripCancellationCronJob) Stop() {
+	close(c.stopChan)
+}
+
+func (c *TripCancellationCronJob) resume(ctx context.Context) {
+	if err := c.cancellationSvc.ResumeCancellations(ctx); err != nil {
+		log.Error().Err(err).Msg("Failed to resume trip cancellations")
+	}
+}
@@ -0,0 +1,65 @@
+package cronjob
+
+import (
+	"context"
+	"time"
+
+	"bus-booking/trip-service/internal/constants"
+	"bus-booking/trip-service/internal/service"
+
+	"github.com/rs/zerolog/log"
+)
+
+// TripScheduleCronJob materializes upcoming trips from recurring schedules
+type TripScheduleCronJob struct {
+	scheduleSvc service.TripScheduleService
+	daysAhead   int
+	stopChan    chan struct{}
+}
+
+func NewTripScheduleCronJob(scheduleSvc service.TripScheduleService, daysAhead int) *TripScheduleCronJob {
+	if daysAhead < 1 {
+		daysAhead = constants.ScheduleDefaultDaysAhead
+	}
+
+	return &TripScheduleCronJob{
+		scheduleSvc: scheduleSvc,
+		daysAhead:   daysAhead,
+		stopChan:    make(chan struct{}),
+	}
+}
+
+// Start begins the cronjob worker - runs every constants.ScheduleGenerationInterval
+func (c *TripScheduleCronJob) Start(ctx context.Context) {
+	log.Info().Int("days_ahead", c.daysAhead).Msg("Starting trip schedule cronjob worker")
+
+	// Run immediately on start
+	c.generateTrips(ctx)
+
+	ticker := time.NewTicker(constants.ScheduleGenerationInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Info().Msg("Trip schedule cronjob context cancelled, stopping...")
+			return
+		case <-c.stopChan:
+			log.Info().Msg("Trip schedule cronjob stopped")
+			return
+		case <-ticker.C:
+			c.generateTrips(ctx)
+		}
+	}
+}
+
+// Stop stops the cronjob worker
+func (c *TripScheduleCronJob) Stop() {
+	close(c.stopChan)
+}
+
+func (c *TripScheduleCronJob) generateTrips(ctx context.Context) {
+	if _, err := c.scheduleSvc.GenerateTrips(ctx, c.daysAhead); err != nil {
+		log.Error().Err(err).Msg("Failed to generate trips from schedules")
+	}
+}
@@ -0,0 +1,51 @@
+package handler
+
+import (
+	"bus-booking/shared/ginext"
+	"bus-booking/trip-service/internal/model"
+	"bus-booking/trip-service/internal/service"
+
+	"github.com/rs/zerolog/log"
+)
+
+type FareCalendarHandler interface {
+	GetFareCalendar(r *ginext.Request) (*ginext.Response, error)
+}
+
+type FareCalendarHandlerImpl struct {
+	fareCalendarService service.FareCalendarService
+}
+
+func NewFareCalendarHandler(fareCalendarService service.FareCalendarService) FareCalendarHandler {
+	return &FareCalendarHandlerImpl{
+		fareCalendarService: fareCalendarService,
+	}
+}
+
+// GetFareCalendar godoc
+// @Summary Get fare calendar
+// @Description Get the cheapest fare still for sale and the number of departures on each day of a month between two places, whole trips and segments between intermediate stops alike. Days without seats left have no fare.
+// @Tags trips
+// @Produce json
+// @Param origin query string true "Origin city or stop (partial match)"
+// @Param destination query string true "Destination city or stop (partial match)"
+// @Param month query string true "Month (YYYY-MM), from the current month up to 12 months ahead" example(2025-12)
+// @Success 200 {object} ginext.Response{data=model.FareCalendar} "Fare calendar"
+// @Failure 400 {object} ginext.Response "Invalid request"
+// @Failure 500 {object} ginext.Response "Internal server error"
+// @Router /api/v1/trips/fare-calendar [get]
+func (h *FareCalendarHandlerImpl) GetFareCalendar(r *ginext.Request) (*ginext.Response, error) {
+	var req model.FareCalendarRequest
+	if err := r.GinCtx.ShouldBindQuery(&req); err != nil {
+		log.Error().Err(err).Msg("Query binding failed")
+		return nil, ginext.NewBadRequestError(err.Error())
+	}
+
+	calendar, err := h.fareCalendarService.GetFareCalendar(r.Context(), &req)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get fare calendar")
+		return nil, err
+	}
+
+	return ginext.NewSuccessResponse(calendar), nil
+}
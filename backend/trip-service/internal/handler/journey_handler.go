@@ -0,0 +1,57 @@
+package handler
+
+import (
+	"bus-booking/shared/ginext"
+	"bus-booking/trip-service/internal/model"
+	"bus-booking/trip-service/internal/service"
+
+	"github.com/rs/zerolog/log"
+)
+
+type JourneyHandler interface {
+	SearchJourneys(r *ginext.Request) (*ginext.Response, error)
+}
+
+type JourneyHandlerImpl struct {
+	journeyService service.JourneyService
+}
+
+func NewJourneyHandler(journeyService service.JourneyService) JourneyHandler {
+	return &JourneyHandlerImpl{
+		journeyService: journeyService,
+	}
+}
+
+// SearchJourneys godoc
+// @Summary Search connecting journeys
+// @Description Find ways to travel between two places on a day, on a direct trip or by changing buses at up to two transfer cities. Each leg can be booked as a segment of its trip.
+// @Tags trips
+// @Produce json
+// @Param origin query string true "Origin city or stop (partial match)"
+// @Param destination query string true "Destination city or stop (partial match)"
+// @Param departure_date query string true "Departure date of the first leg (YYYY-MM-DD)" example(2025-12-01)
+// @Param max_transfers query int false "Maximum number of transfers" minimum(0) maximum(2) default(2)
+// @Param min_transfer_minutes query int false "Shortest wait between legs in minutes" default(30)
+// @Param max_transfer_minutes query int false "Longest wait between legs in minutes" default(360)
+// @Param sort_by query string false "Sort by" Enums(duration, price, transfers) default(duration)
+// @Param sort_order query string false "Sort order" Enums(asc, desc)
+// @Param limit query int false "Maximum number of journeys" default(20)
+// @Success 200 {object} ginext.Response{data=[]model.Journey} "Matching journeys"
+// @Failure 400 {object} ginext.Response "Invalid request"
+// @Failure 500 {object} ginext.Response "Internal server error"
+// @Router /api/v1/trips/journeys [get]
+func (h *JourneyHandlerImpl) SearchJourneys(r *ginext.Request) (*ginext.Response, error) {
+	var req model.JourneySearchRequest
+	if err := r.GinCtx.ShouldBindQuery(&req); err != nil {
+		log.Error().Err(err).Msg("Query binding failed")
+		return nil, ginext.NewBadRequestError(err.Error())
+	}
+
+	journeys, err := h.journeyService.SearchJourneys(r.Context(), &req)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to search journeys")
+		return nil, err
+	}
+
+	return ginext.NewSuccessResponse(journeys), nil
+}
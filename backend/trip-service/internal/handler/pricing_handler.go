@@ -0,0 +1,280 @@
+package handler
+
+import (
+	"bus-booking/shared/ginext"
+	"bus-booking/trip-service/internal/model"
+	"bus-booking/trip-service/internal/service"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+type PricingHandler interface {
+	GetTripFare(r *ginext.Request) (*ginext.Response, error)
+
+	GetRule(r *ginext.Request) (*ginext.Response, error)
+	ListRules(r *ginext.Request) (*ginext.Response, error)
+	CreateRule(r *ginext.Request) (*ginext.Response, error)
+	UpdateRule(r *ginext.Request) (*ginext.Response, error)
+	DeleteRule(r *ginext.Request) (*ginext.Response, error)
+
+	ListHolidays(r *ginext.Request) (*ginext.Response, error)
+	CreateHoliday(r *ginext.Request) (*ginext.Response, error)
+	DeleteHoliday(r *ginext.Request) (*ginext.Response, error)
+}
+
+type PricingHandlerImpl struct {
+	pricingService service.PricingService
+}
+
+func NewPricingHandler(pricingService service.PricingService) PricingHandler {
+	return &PricingHandlerImpl{
+		pricingService: pricingService,
+	}
+}
+
+// GetTripFare godoc
+// @Summary Get current trip fare
+// @Description Quote the current fare of a trip from its base price and the pricing rules, with the price of each seat tier
+// @Tags trips
+// @Produce json
+// @Param id path string true "Trip ID" format(uuid)
+// @Success 200 {object} ginext.Response{data=model.TripFare} "Current fare"
+// @Failure 400 {object} ginext.Response "Invalid trip ID"
+// @Failure 404 {object} ginext.Response "Trip not found"
+// @Failure 500 {object} ginext.Response "Internal server error"
+// @Router /api/v1/trips/{id}/fare [get]
+func (h *PricingHandlerImpl) GetTripFare(r *ginext.Request) (*ginext.Response, error) {
+	idStr := r.GinCtx.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		return nil, ginext.NewBadRequestError("invalid trip ID")
+	}
+
+	fare, err := h.pricingService.QuoteTrip(r.Context(), id)
+	if err != nil {
+		log.Error().Err(err).Str("trip_id", idStr).Msg("Failed to quote trip fare")
+		return nil, err
+	}
+
+	return ginext.NewSuccessResponse(fare), nil
+}
+
+// GetRule godoc
+// @Summary Get pricing rule by ID
+// @Description Get a dynamic pricing rule
+// @Tags pricing
+// @Produce json
+// @Param id path string true "Rule ID" format(uuid)
+// @Success 200 {object} ginext.Response{data=model.PricingRule} "Pricing rule"
+// @Failure 400 {object} ginext.Response "Invalid rule ID"
+// @Failure 404 {object} ginext.Response "Rule not found"
+// @Router /api/v1/pricing-rules/{id} [get]
+func (h *PricingHandlerImpl) GetRule(r *ginext.Request) (*ginext.Response, error) {
+	idStr := r.GinCtx.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		return nil, ginext.NewBadRequestError("invalid rule ID")
+	}
+
+	rule, err := h.pricingService.GetRuleByID(r.Context(), id)
+	if err != nil {
+		log.Error().Err(err).Str("rule_id", idStr).Msg("Failed to get pricing rule")
+		return nil, err
+	}
+
+	return ginext.NewSuccessResponse(rule), nil
+}
+
+// ListRules godoc
+// @Summary List pricing rules
+// @Description Get a paginated list of pricing rules in the order they apply, optionally filtered by route
+// @Tags pricing
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Items per page" default(20)
+// @Param route_id query string false "Filter by route ID" format(uuid)
+// @Success 200 {object} ginext.Response{data=[]model.PricingRule} "Paginated pricing rule list"
+// @Failure 400 {object} ginext.Response "Invalid request"
+// @Failure 500 {object} ginext.Response "Internal server error"
+// @Router /api/v1/pricing-rules [get]
+func (h *PricingHandlerImpl) ListRules(r *ginext.Request) (*ginext.Response, error) {
+	var req model.ListPricingRulesRequest
+	if err := r.GinCtx.ShouldBindQuery(&req); err != nil {
+		return nil, ginext.NewBadRequestError(err.Error())
+	}
+
+	rules, total, err := h.pricingService.ListRules(r.Context(), &req)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to list pricing rules")
+		return nil, err
+	}
+
+	return ginext.NewPaginatedResponse(rules, req.Page, req.PageSize, total), nil
+}
+
+// CreateRule godoc
+// @Summary Create pricing rule
+// @Description Create a rule adjusting fares by load factor, days before departure, weekday or holidays. Rules apply from the highest priority down; price ratios clamp the fare relative to the trip base price
+// @Tags pricing
+// @Accept json
+// @Produce json
+// @Param request body model.CreatePricingRuleRequest true "Pricing rule data"
+// @Success 201 {object} ginext.Response{data=model.PricingRule} "Created pricing rule"
+// @Failure 400 {object} ginext.Response "Invalid request"
+// @Failure 500 {object} ginext.Response "Internal server error"
+// @Router /api/v1/pricing-rules [post]
+func (h *PricingHandlerImpl) CreateRule(r *ginext.Request) (*ginext.Response, error) {
+	var req model.CreatePricingRuleRequest
+	if err := r.GinCtx.ShouldBindJSON(&req); err != nil {
+		log.Debug().Err(err).Msg("JSON binding failed")
+		return nil, ginext.NewBadRequestError(err.Error())
+	}
+
+	rule, err := h.pricingService.CreateRule(r.Context(), &req)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to create pricing rule")
+		return nil, err
+	}
+
+	return ginext.NewCreatedResponse(rule), nil
+}
+
+// UpdateRule godoc
+// @Summary Update pricing rule
+// @Description Replace a pricing rule. Fares already held or paid for are not affected
+// @Tags pricing
+// @Accept json
+// @Produce json
+// @Param id path string true "Rule ID" format(uuid)
+// @Param request body model.UpdatePricingRuleRequest true "Pricing rule data"
+// @Success 200 {object} ginext.Response{data=model.PricingRule} "Updated pricing rule"
+// @Failure 400 {object} ginext.Response "Invalid request"
+// @Failure 404 {object} ginext.Response "Rule not found"
+// @Failure 500 {object} ginext.Response "Internal server error"
+// @Router /api/v1/pricing-rules/{id} [put]
+func (h *PricingHandlerImpl) UpdateRule(r *ginext.Request) (*ginext.Response, error) {
+	idStr := r.GinCtx.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		return nil, ginext.NewBadRequestError("invalid rule ID")
+	}
+
+	var req model.UpdatePricingRuleRequest
+	if err := r.GinCtx.ShouldBindJSON(&req); err != nil {
+		log.Debug().Err(err).Msg("JSON binding failed")
+		return nil, ginext.NewBadRequestError(err.Error())
+	}
+
+	rule, err := h.pricingService.UpdateRule(r.Context(), id, &req)
+	if err != nil {
+		log.Error().Err(err).Str("rule_id", idStr).Msg("Failed to update pricing rule")
+		return nil, err
+	}
+
+	return ginext.NewSuccessResponse(rule), nil
+}
+
+// DeleteRule godoc
+// @Summary Delete pricing rule
+// @Description Delete a pricing rule
+// @Tags pricing
+// @Produce json
+// @Param id path string true "Rule ID" format(uuid)
+// @Success 200 {object} ginext.Response "Success message"
+// @Failure 400 {object} ginext.Response "Invalid rule ID"
+// @Failure 404 {object} ginext.Response "Rule not found"
+// @Failure 500 {object} ginext.Response "Internal server error"
+// @Router /api/v1/pricing-rules/{id} [delete]
+func (h *PricingHandlerImpl) DeleteRule(r *ginext.Request) (*ginext.Response, error) {
+	idStr := r.GinCtx.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		return nil, ginext.NewBadRequestError("invalid rule ID")
+	}
+
+	if err := h.pricingService.DeleteRule(r.Context(), id); err != nil {
+		log.Error().Err(err).Str("rule_id", idStr).Msg("Failed to delete pricing rule")
+		return nil, err
+	}
+
+	return ginext.NewSuccessResponse("Pricing rule deleted successfully"), nil
+}
+
+// ListHolidays godoc
+// @Summary List holidays
+// @Description Get the holiday calendar used by pricing rules
+// @Tags pricing
+// @Produce json
+// @Param year query int false "Only holidays falling in this year, recurring ones included"
+// @Success 200 {object} ginext.Response{data=[]model.HolidayResponse} "Holidays"
+// @Failure 400 {object} ginext.Response "Invalid request"
+// @Failure 500 {object} ginext.Response "Internal server error"
+// @Router /api/v1/holidays [get]
+func (h *PricingHandlerImpl) ListHolidays(r *ginext.Request) (*ginext.Response, error) {
+	var req model.ListHolidaysRequest
+	if err := r.GinCtx.ShouldBindQuery(&req); err != nil {
+		return nil, ginext.NewBadRequestError(err.Error())
+	}
+
+	holidays, err := h.pricingService.ListHolidays(r.Context(), &req)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to list holidays")
+		return nil, err
+	}
+
+	return ginext.NewSuccessResponse(model.ToHolidayResponseList(holidays)), nil
+}
+
+// CreateHoliday godoc
+// @Summary Create holiday
+// @Description Add days to the holiday calendar, e.g. Tết or 30/4. Recurring holidays repeat every year on the same dates
+// @Tags pricing
+// @Accept json
+// @Produce json
+// @Param request body model.CreateHolidayRequest true "Holiday data"
+// @Success 201 {object} ginext.Response{data=model.HolidayResponse} "Created holiday"
+// @Failure 400 {object} ginext.Response "Invalid request"
+// @Failure 500 {object} ginext.Response "Internal server error"
+// @Router /api/v1/holidays [post]
+func (h *PricingHandlerImpl) CreateHoliday(r *ginext.Request) (*ginext.Response, error) {
+	var req model.CreateHolidayRequest
+	if err := r.GinCtx.ShouldBindJSON(&req); err != nil {
+		log.Debug().Err(err).Msg("JSON binding failed")
+		return nil, ginext.NewBadRequestError(err.Error())
+	}
+
+	holiday, err := h.pricingService.CreateHoliday(r.Context(), &req)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to create holiday")
+		return nil, err
+	}
+
+	return ginext.NewCreatedResponse(model.ToHolidayResponse(holiday)), nil
+}
+
+// DeleteHoliday godoc
+// @Summary Delete holiday
+// @Description Remove days from the holiday calendar
+// @Tags pricing
+// @Produce json
+// @Param id path string true "Holiday ID" format(uuid)
+// @Success 200 {object} ginext.Response "Success message"
+// @Failure 400 {object} ginext.Response "Invalid holiday ID"
+// @Failure 404 {object} ginext.Response "Holiday not found"
+// @Failure 500 {object} ginext.Response "Internal server error"
+// @Router /api/v1/holidays/{id} [delete]
+func (h *PricingHandlerImpl) DeleteHoliday(r *ginext.Request) (*ginext.Response, error) {
+	idStr := r.GinCtx.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		return nil, ginext.NewBadRequestError("invalid holiday ID")
+	}
+
+	if err := h.pricingService.DeleteHoliday(r.Context(), id); err != nil {
+		log.Error().Err(err).Str("holiday_id", idStr).Msg("Failed to delete holiday")
+		return nil, err
+	}
+
+	return ginext.NewSuccessResponse("Holiday deleted successfully"), nil
+}
@@ -0,0 +1,137 @@
+package handler
+
+import (
+	"bus-booking/shared/ginext"
+	"bus-booking/trip-service/internal/service"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+type TripCancellationHandler interface {
+	CancelTrip(r *ginext.Request) (*ginext.Response, error)
+	GetCancellation(r *ginext.Request) (*ginext.Response, error)
+	RetryCancellation(r *ginext.Request) (*ginext.Response, error)
+	AbortCancellation(r *ginext.Request) (*ginext.Response, error)
+}
+
+type TripCancellationHandlerImpl struct {
+	cancellationService service.TripCancellationService
+}
+
+func NewTripCancellationHandler(cancellationService service.TripCancellationService) TripCancellationHandler {
+	return &TripCancellationHandlerImpl{
+		cancellationService: cancellationService,
+	}
+}
+
+// CancelTrip godoc
+// @Summary Cancel trip
+// @Description Cancel a trip and start refunding and cancelling its bookings (Admin only)
+// @Tags trips
+// @Accept json
+// @Produce json
+// @Param id path string true "Trip ID" format(uuid)
+// @Success 200 {object} ginext.Response "Success message"
+// @Failure 400 {object} ginext.Response "Invalid trip ID or status"
+// @Failure 500 {object} ginext.Response "Internal server error"
+// @Router /api/v1/trips/{id}/cancel [put]
+func (h *TripCancellationHandlerImpl) CancelTrip(r *ginext.Request) (*ginext.Response, error) {
+	id, err := parseTripID(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = h.cancellationService.CancelTrip(r.Context(), id); err != nil {
+		log.Error().Err(err).Str("trip_id", id.String()).Msg("Failed to cancel trip")
+		return nil, err
+	}
+
+	return ginext.NewSuccessResponse("Trip cancelled and refunds processing initiated"), nil
+}
+
+// GetCancellation godoc
+// @Summary Get trip cancellation progress
+// @Description Get the refund, cancellation and notification progress of every booking of a cancelled trip (Admin only)
+// @Tags trips
+// @Produce json
+// @Param id path string true "Trip ID" format(uuid)
+// @Success 200 {object} ginext.Response{data=model.TripCancellationProgress}
+// @Failure 400 {object} ginext.Response
+// @Failure 404 {object} ginext.Response
+// @Router /api/v1/trips/{id}/cancellation [get]
+func (h *TripCancellationHandlerImpl) GetCancellation(r *ginext.Request) (*ginext.Response, error) {
+	id, err := parseTripID(r)
+	if err != nil {
+		return nil, err
+	}
+
+	progress, err := h.cancellationService.GetCancellation(r.Context(), id)
+	if err != nil {
+		log.Error().Err(err).Str("trip_id", id.String()).Msg("Failed to get trip cancellation")
+		return nil, err
+	}
+
+	return ginext.NewSuccessResponse(progress), nil
+}
+
+// RetryCancellation godoc
+// @Summary Retry failed trip cancellation steps
+// @Description Run the failed steps of a trip cancellation again (Admin only)
+// @Tags trips
+// @Produce json
+// @Param id path string true "Trip ID" format(uuid)
+// @Success 200 {object} ginext.Response{data=model.TripCancellationProgress}
+// @Failure 400 {object} ginext.Response
+// @Failure 404 {object} ginext.Response
+// @Router /api/v1/trips/{id}/cancellation/retry [post]
+func (h *TripCancellationHandlerImpl) RetryCancellation(r *ginext.Request) (*ginext.Response, error) {
+	id, err := parseTripID(r)
+	if err != nil {
+		return nil, err
+	}
+
+	progress, err := h.cancellationService.RetryCancellation(r.Context(), id)
+	if err != nil {
+		log.Error().Err(err).Str("trip_id", id.String()).Msg("Failed to retry trip cancellation")
+		return nil, err
+	}
+
+	return ginext.NewSuccessResponse(progress), nil
+}
+
+// AbortCancellation godoc
+// @Summary Abort trip cancellation
+// @Description Restore a cancelled trip before any of its bookings was cancelled (Admin only)
+// @Tags trips
+// @Produce json
+// @Param id path string true "Trip ID" format(uuid)
+// @Success 200 {object} ginext.Response{data=model.TripCancellationProgress}
+// @Failure 400 {object} ginext.Response
+// @Failure 404 {object} ginext.Response
+// @Failure 409 {object} ginext.Response "Bookings already cancelled"
+// @Router /api/v1/trips/{id}/cancellation/abort [post]
+func (h *TripCancellationHandlerImpl) AbortCancellation(r *ginext.Request) (*ginext.Response, error) {
+	id, err := parseTripID(r)
+	if err != nil {
+		return nil, err
+	}
+
+	progress, err := h.cancellationService.AbortCancellation(r.Context(), id)
+	if err != nil {
+		log.Error().Err(err).Str("trip_id", id.String()).Msg("Failed to abort trip cancellation")
+		return nil, err
+	}
+
+	return ginext.NewSuccessResponse(progress), nil
+}
+
+func parseTripID(r *ginext.Request) (uuid.UUID, error) {
+	idStr := r.GinCtx.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		log.Error().Err(err).Str("trip_id", idStr).Msg("Invalid trip ID")
+		return uuid.Nil, ginext.NewBadRequestError("invalid trip ID")
+	}
+	return id, nil
+}
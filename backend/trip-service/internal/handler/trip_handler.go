@@ -22,7 +22,6 @@ type TripHandler interface {
 	CreateTrip(r *ginext.Request) (*ginext.Response, error)
 	UpdateTrip(r *ginext.Request) (*ginext.Response, error)
 	DeleteTrip(r *ginext.Request) (*ginext.Response, error)
-	CancelTrip(r *ginext.Request) (*ginext.Response, error)
 }
 
 type TripHandlerImpl struct {
@@ -41,8 +40,8 @@ func NewTripHandler(tripService service.TripService) TripHandler {
 // @Tags trips
 // @Accept json
 // @Produce json
-// @Param origin query string false "Origin city (partial match)"
-// @Param destination query string false "Destination city (partial match)"
+// @Param origin query string false "Origin city or stop (partial match); matching intermediate stops returns a segment"
+// @Param destination query string false "Destination city or stop (partial match)"
 // @Param departure_time_start query string false "Departure time start (ISO8601 or HH:MM)" example(2025-12-01T06:00:00Z)
 // @Param departure_time_end query string false "Departure time end (ISO8601 or HH:MM)" example(2025-12-01T22:00:00Z)
 // @Param arrival_time_start query string false "Arrival time start (ISO8601 or HH:MM)"
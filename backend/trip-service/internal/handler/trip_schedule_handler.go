@@ -0,0 +1,209 @@
+package handler
+
+import (
+	"bus-booking/shared/ginext"
+	"bus-booking/trip-service/internal/constants"
+	"bus-booking/trip-service/internal/model"
+	"bus-booking/trip-service/internal/service"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+type TripScheduleHandler interface {
+	GetByID(r *ginext.Request) (*ginext.Response, error)
+	GetList(r *ginext.Request) (*ginext.Response, error)
+
+	Create(r *ginext.Request) (*ginext.Response, error)
+	Update(r *ginext.Request) (*ginext.Response, error)
+	Delete(r *ginext.Request) (*ginext.Response, error)
+
+	GenerateTrips(r *ginext.Request) (*ginext.Response, error)
+}
+
+type TripScheduleHandlerImpl struct {
+	service service.TripScheduleService
+}
+
+func NewTripScheduleHandler(service service.TripScheduleService) TripScheduleHandler {
+	return &TripScheduleHandlerImpl{
+		service: service,
+	}
+}
+
+// GetByID godoc
+// @Summary Get trip schedule by ID
+// @Description Get a recurring trip schedule with its route and bus
+// @Tags trip-schedules
+// @Accept json
+// @Produce json
+// @Param id path string true "Schedule ID" format(uuid)
+// @Success 200 {object} ginext.Response{data=model.TripScheduleResponse} "Trip schedule"
+// @Failure 400 {object} ginext.Response "Invalid schedule ID"
+// @Failure 404 {object} ginext.Response "Schedule not found"
+// @Router /api/v1/trip-schedules/{id} [get]
+func (h *TripScheduleHandlerImpl) GetByID(r *ginext.Request) (*ginext.Response, error) {
+	idStr := r.GinCtx.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		return nil, ginext.NewBadRequestError("invalid schedule ID")
+	}
+
+	schedule, err := h.service.GetScheduleByID(r.Context(), id)
+	if err != nil {
+		log.Error().Err(err).Str("schedule_id", idStr).Msg("Failed to get trip schedule")
+		return nil, err
+	}
+
+	return ginext.NewSuccessResponse(model.ToTripScheduleResponse(schedule)), nil
+}
+
+// GetList godoc
+// @Summary List trip schedules
+// @Description Get a paginated list of recurring trip schedules, optionally filtered by route
+// @Tags trip-schedules
+// @Accept json
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Items per page" default(20)
+// @Param route_id query string false "Filter by route ID" format(uuid)
+// @Success 200 {object} ginext.Response{data=[]model.TripScheduleResponse} "Paginated trip schedule list"
+// @Failure 400 {object} ginext.Response "Invalid request"
+// @Failure 500 {object} ginext.Response "Internal server error"
+// @Router /api/v1/trip-schedules [get]
+func (h *TripScheduleHandlerImpl) GetList(r *ginext.Request) (*ginext.Response, error) {
+	var req model.ListTripSchedulesRequest
+	if err := r.GinCtx.ShouldBindQuery(&req); err != nil {
+		return nil, ginext.NewBadRequestError(err.Error())
+	}
+
+	schedules, total, err := h.service.ListSchedules(r.Context(), &req)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to list trip schedules")
+		return nil, err
+	}
+
+	return ginext.NewPaginatedResponse(model.ToTripScheduleResponseList(schedules), req.Page, req.PageSize, total), nil
+}
+
+// Create godoc
+// @Summary Create trip schedule
+// @Description Create a recurring schedule (route, bus, time of day, weekdays, validity window, base price) that generates future trips
+// @Tags trip-schedules
+// @Accept json
+// @Produce json
+// @Param request body model.CreateTripScheduleRequest true "Trip schedule data"
+// @Success 201 {object} ginext.Response{data=model.TripScheduleResponse} "Created trip schedule"
+// @Failure 400 {object} ginext.Response "Invalid request"
+// @Failure 500 {object} ginext.Response "Internal server error"
+// @Router /api/v1/trip-schedules [post]
+func (h *TripScheduleHandlerImpl) Create(r *ginext.Request) (*ginext.Response, error) {
+	var req model.CreateTripScheduleRequest
+	if err := r.GinCtx.ShouldBindJSON(&req); err != nil {
+		log.Debug().Err(err).Msg("JSON binding failed")
+		return nil, ginext.NewBadRequestError(err.Error())
+	}
+
+	schedule, err := h.service.CreateSchedule(r.Context(), &req)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to create trip schedule")
+		return nil, err
+	}
+
+	return ginext.NewCreatedResponse(model.ToTripScheduleResponse(schedule)), nil
+}
+
+// Update godoc
+// @Summary Update trip schedule
+// @Description Update a recurring schedule. Only trips generated afterwards are affected; existing trips are left unchanged
+// @Tags trip-schedules
+// @Accept json
+// @Produce json
+// @Param id path string true "Schedule ID" format(uuid)
+// @Param request body model.UpdateTripScheduleRequest true "Trip schedule update data"
+// @Success 200 {object} ginext.Response{data=model.TripScheduleResponse} "Updated trip schedule"
+// @Failure 400 {object} ginext.Response "Invalid request"
+// @Failure 404 {object} ginext.Response "Schedule not found"
+// @Failure 500 {object} ginext.Response "Internal server error"
+// @Router /api/v1/trip-schedules/{id} [put]
+func (h *TripScheduleHandlerImpl) Update(r *ginext.Request) (*ginext.Response, error) {
+	idStr := r.GinCtx.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		return nil, ginext.NewBadRequestError("invalid schedule ID")
+	}
+
+	var req model.UpdateTripScheduleRequest
+	if err := r.GinCtx.ShouldBindJSON(&req); err != nil {
+		log.Debug().Err(err).Msg("JSON binding failed")
+		return nil, ginext.NewBadRequestError(err.Error())
+	}
+
+	schedule, err := h.service.UpdateSchedule(r.Context(), id, &req)
+	if err != nil {
+		log.Error().Err(err).Str("schedule_id", idStr).Msg("Failed to update trip schedule")
+		return nil, err
+	}
+
+	return ginext.NewSuccessResponse(model.ToTripScheduleResponse(schedule)), nil
+}
+
+// Delete godoc
+// @Summary Delete trip schedule
+// @Description Delete a recurring schedule. Trips already generated from it are kept
+// @Tags trip-schedules
+// @Accept json
+// @Produce json
+// @Param id path string true "Schedule ID" format(uuid)
+// @Success 200 {object} ginext.Response "Success message"
+// @Failure 400 {object} ginext.Response "Invalid schedule ID"
+// @Failure 404 {object} ginext.Response "Schedule not found"
+// @Failure 500 {object} ginext.Response "Internal server error"
+// @Router /api/v1/trip-schedules/{id} [delete]
+func (h *TripScheduleHandlerImpl) Delete(r *ginext.Request) (*ginext.Response, error) {
+	idStr := r.GinCtx.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		return nil, ginext.NewBadRequestError("invalid schedule ID")
+	}
+
+	if err = h.service.DeleteSchedule(r.Context(), id); err != nil {
+		log.Error().Err(err).Str("schedule_id", idStr).Msg("Failed to delete trip schedule")
+		return nil, err
+	}
+
+	return ginext.NewSuccessResponse("Trip schedule deleted successfully"), nil
+}
+
+// GenerateTrips godoc
+// @Summary Generate trips from schedules
+// @Description Materialize missing trips of all active schedules for the coming days. Existing trips and bus conflicts are skipped
+// @Tags trip-schedules
+// @Accept json
+// @Produce json
+// @Param days_ahead query int false "Number of days to generate ahead" default(14)
+// @Success 200 {object} ginext.Response{data=model.GenerateTripsResult} "Generation summary"
+// @Failure 400 {object} ginext.Response "Invalid request"
+// @Failure 500 {object} ginext.Response "Internal server error"
+// @Router /api/v1/trip-schedules/generate [post]
+func (h *TripScheduleHandlerImpl) GenerateTrips(r *ginext.Request) (*ginext.Response, error) {
+	var req model.GenerateTripsRequest
+	if err := r.GinCtx.ShouldBindQuery(&req); err != nil {
+		return nil, ginext.NewBadRequestError(err.Error())
+	}
+
+	if req.DaysAhead == 0 {
+		req.DaysAhead = constants.ScheduleDefaultDaysAhead
+	}
+	if req.DaysAhead > constants.ScheduleMaxDaysAhead {
+		return nil, ginext.NewBadRequestError("days_ahead is too large")
+	}
+
+	result, err := h.service.GenerateTrips(r.Context(), req.DaysAhead)
+	if err != nil {
+		log.Error().Err(err).Int("days_ahead", req.DaysAhead).Msg("Failed to generate trips from schedules")
+		return nil, err
+	}
+
+	return ginext.NewSuccessResponse(result), nil
+}
@@ -13,9 +13,7 @@ type CancelBookingRequest struct {
 	Reason string `json:"reason"`
 }
 
-// BookingListResponse represents the response when getting bookings for a trip
-type BookingListResponse struct {
-	Data  []*Booking `json:"data"`
-	Page  int        `json:"page"`
-	Total int64      `json:"total"`
+type NotifyTripCancelledRequest struct {
+	Reason       string `json:"reason"`
+	RefundAmount int    `json:"refund_amount"`
 }
@@ -12,3 +12,10 @@ type SeatStatusResponse struct {
 	IsBooked bool `json:"is_booked"`
 	IsLocked bool `json:"is_locked"`
 }
+
+// TripSegment is the range of stop orders a seat is held for; seats are free
+// for segments that do not overlap it
+type TripSegment struct {
+	FromStopOrder int `json:"from_stop_order"`
+	ToStopOrder   int `json:"to_stop_order"`
+}
@@ -0,0 +1,76 @@
+package model
+
+import (
+	"time"
+
+	"bus-booking/trip-service/internal/constants"
+)
+
+// FareCalendarRequest asks for the cheapest fare of each day of a month between two places
+type FareCalendarRequest struct {
+	Origin      string `form:"origin" json:"origin" binding:"required"`
+	Destination string `form:"destination" json:"destination" binding:"required"`
+	Month       string `form:"month" json:"month" binding:"required"` // YYYY-MM
+}
+
+// FareCalendar lists every day of a month with its cheapest seat still for sale and its number
+// of departures, whole trips and segments between intermediate stops alike
+type FareCalendar struct {
+	Origin      string            `json:"origin"`
+	Destination string            `json:"destination"`
+	Month       string            `json:"month"`
+	LowestFare  *float64          `json:"lowest_fare"` // cheapest day of the month, null when nothing is for sale
+	Days        []FareCalendarDay `json:"days"`
+	GeneratedAt time.Time         `json:"generated_at"`
+}
+
+type FareCalendarDay struct {
+	Date       string   `json:"date"`        // YYYY-MM-DD
+	LowestFare *float64 `json:"lowest_fare"` // null when no departure has seats left
+	Departures int      `json:"departures"`  // upcoming departures, sold out ones included
+}
+
+// NewFareCalendar returns a calendar with every day of the month starting at monthStart
+// and no departures yet
+func NewFareCalendar(origin, destination string, monthStart time.Time) *FareCalendar {
+	calendar := &FareCalendar{
+		Origin:      origin,
+		Destination: destination,
+		Month:       monthStart.Format(constants.FareCalendarMonthFormat),
+	}
+	for day := monthStart; day.Month() == monthStart.Month(); day = day.AddDate(0, 0, 1) {
+		calendar.Days = append(calendar.Days, FareCalendarDay{Date: day.Format(constants.ScheduleDateFormat)})
+	}
+	return calendar
+}
+
+// AddDeparture counts a departure on the day of departsAt, whose cheapest available seat
+// costs fare; fare is nil when the departure is sold out
+func (c *FareCalendar) AddDeparture(departsAt time.Time, fare *float64) {
+	day := &c.Days[departsAt.Day()-1]
+	day.Departures++
+	if fare == nil {
+		return
+	}
+	if day.LowestFare == nil || *fare < *day.LowestFare {
+		day.LowestFare = fare
+	}
+	if c.LowestFare == nil || *fare < *c.LowestFare {
+		c.LowestFare = fare
+	}
+}
+
+// LowestAvailablePrice returns the cheapest tier with seats left, nil when every tier is sold out
+func LowestAvailablePrice(tiers []PriceTier) *float64 {
+	var lowest *float64
+	for i := range tiers {
+		if tiers[i].AvailableCount == 0 {
+			continue
+		}
+		if lowest == nil || tiers[i].FinalPrice < *lowest {
+			price := tiers[i].FinalPrice
+			lowest = &price
+		}
+	}
+	return lowest
+}
@@ -0,0 +1,81 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// JourneySearchRequest looks for ways to travel between two places on a day, either on one trip
+// or by changing buses at transfer points along the way
+type JourneySearchRequest struct {
+	Origin        string `form:"origin" json:"origin" binding:"required"`
+	Destination   string `form:"destination" json:"destination" binding:"required"`
+	DepartureDate string `form:"departure_date" json:"departure_date" binding:"required"` // YYYY-MM-DD, first leg departs on this day
+
+	// Transfer constraints; defaults apply when not given
+	MaxTransfers       *int `form:"max_transfers" json:"max_transfers,omitempty" binding:"omitempty,min=0,max=2"`
+	MinTransferMinutes *int `form:"min_transfer_minutes" json:"min_transfer_minutes,omitempty" binding:"omitempty,min=0"`
+	MaxTransferMinutes *int `form:"max_transfer_minutes" json:"max_transfer_minutes,omitempty" binding:"omitempty,min=0"`
+
+	// Sorting
+	SortBy    string `form:"sort_by" json:"sort_by" binding:"omitempty,oneof=duration price transfers"`
+	SortOrder string `form:"sort_order" json:"sort_order" binding:"omitempty,oneof=asc desc"`
+
+	Limit int `form:"limit" json:"limit" binding:"omitempty,min=1,max=100"`
+}
+
+// Journey is a way to get from origin to destination on one or more trips in travel order
+type Journey struct {
+	Legs                 []JourneyLeg `json:"legs"`
+	Transfers            int          `json:"transfers"`
+	DepartureTime        time.Time    `json:"departure_time"`
+	ArrivalTime          time.Time    `json:"arrival_time"`
+	TotalDurationMinutes int          `json:"total_duration_minutes"` // from first departure to last arrival, waits included
+	TotalPrice           float64      `json:"total_price"`            // sum of the leg fares before seat multipliers
+	TotalDistanceKm      float64      `json:"total_distance_km"`
+}
+
+// JourneyLeg is the part of one trip travelled in a journey. Its stops are what
+// booking service needs to book the leg as a segment.
+type JourneyLeg struct {
+	TripID  uuid.UUID         `json:"trip_id"`
+	RouteID uuid.UUID         `json:"route_id"`
+	BusID   uuid.UUID         `json:"bus_id"`
+	Route   *RouteDetail      `json:"route,omitempty"`
+	Segment TripSegmentDetail `json:"segment"`
+
+	// Seat prices of the leg at the current fare; unset when the trip could not be priced
+	PriceTiers []PriceTier `json:"price_tiers,omitempty"`
+
+	// Wait before this leg departs, and whether passengers move to another station; unset for the first leg
+	TransferMinutes int  `json:"transfer_minutes,omitempty"`
+	ChangesStation  bool `json:"changes_station,omitempty"`
+}
+
+// ApplyFare prices the leg at the quoted fare of its trip, prorated by distance for a segment
+// like its base price
+func (l *JourneyLeg) ApplyFare(fare *TripFare) {
+	price := fare.Fare
+	if l.Route != nil && l.Route.DistanceKm > 0 {
+		price = SegmentBasePrice(fare.Fare, l.Segment.DistanceKm/l.Route.DistanceKm)
+	}
+	l.Segment.BasePrice = price
+	l.PriceTiers = fare.TiersAt(price)
+}
+
+// NewJourney totals the legs of a journey
+func NewJourney(legs []JourneyLeg) Journey {
+	journey := Journey{
+		Legs:          legs,
+		Transfers:     len(legs) - 1,
+		DepartureTime: legs[0].Segment.DepartureTime,
+		ArrivalTime:   legs[len(legs)-1].Segment.ArrivalTime,
+	}
+	for _, leg := range legs {
+		journey.TotalPrice += leg.Segment.BasePrice
+		journey.TotalDistanceKm += leg.Segment.DistanceKm
+	}
+	journey.TotalDurationMinutes = int(journey.ArrivalTime.Sub(journey.DepartureTime).Minutes())
+	return journey
+}
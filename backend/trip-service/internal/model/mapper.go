@@ -1,6 +1,9 @@
 package model
 
-import "bus-booking/trip-service/internal/model/booking"
+import (
+	"bus-booking/trip-service/internal/constants"
+	"bus-booking/trip-service/internal/model/booking"
+)
 
 // ToBusResponse converts Bus entity to BusResponse with raw string values
 func ToBusResponse(bus *Bus) *BusResponse {
@@ -82,6 +85,7 @@ func ToRouteStopResponse(stop *RouteStop) *RouteStopResponse {
 		Latitude:      stop.Latitude,
 		Longitude:     stop.Longitude,
 		OffsetMinutes: stop.OffsetMinutes,
+		DistanceKm:    stop.DistanceKm,
 		IsActive:      stop.IsActive,
 	}
 }
@@ -114,6 +118,21 @@ func ToRouteResponse(route *Route) *RouteResponse {
 	}
 }
 
+// ToRouteDetail converts Route entity to the RouteDetail summary used in search results
+func ToRouteDetail(route *Route) *RouteDetail {
+	if route == nil {
+		return nil
+	}
+
+	return &RouteDetail{
+		ID:              route.ID,
+		Origin:          route.Origin,
+		Destination:     route.Destination,
+		DistanceKm:      route.DistanceKm,
+		DurationMinutes: route.EstimatedMinutes,
+	}
+}
+
 // ToTripResponse converts Trip entity to TripResponse with raw string values
 func ToTripResponse(trip *Trip) *TripResponse {
 	if trip == nil {
@@ -129,6 +148,7 @@ func ToTripResponse(trip *Trip) *TripResponse {
 		BasePrice:     trip.BasePrice,
 		Status:        string(trip.Status), // Raw string value
 		IsActive:      trip.IsActive,
+		ScheduleID:    trip.ScheduleID,
 		Route:         ToRouteResponse(trip.Route),
 		Bus:           ToBusResponse(trip.Bus),
 		CreatedAt:     trip.CreatedAt,
@@ -171,3 +191,71 @@ func ToTripResponseList(trips []Trip) []TripResponse {
 	}
 	return responses
 }
+
+// ToTripScheduleResponse converts TripSchedule entity to TripScheduleResponse
+func ToTripScheduleResponse(schedule *TripSchedule) *TripScheduleResponse {
+	if schedule == nil {
+		return nil
+	}
+
+	weekdays := make([]int, len(schedule.Weekdays))
+	for i, d := range schedule.Weekdays {
+		weekdays[i] = int(d)
+	}
+
+	var validUntil *string
+	if schedule.ValidUntil != nil {
+		until := schedule.ValidUntil.Format(constants.ScheduleDateFormat)
+		validUntil = &until
+	}
+
+	return &TripScheduleResponse{
+		ID:            schedule.ID,
+		RouteID:       schedule.RouteID,
+		BusID:         schedule.BusID,
+		DepartureTime: schedule.DepartureTime,
+		Weekdays:      weekdays,
+		ValidFrom:     schedule.ValidFrom.Format(constants.ScheduleDateFormat),
+		ValidUntil:    validUntil,
+		BasePrice:     schedule.BasePrice,
+		IsActive:      schedule.IsActive,
+		Route:         ToRouteResponse(schedule.Route),
+		Bus:           ToBusResponse(schedule.Bus),
+		CreatedAt:     schedule.CreatedAt,
+		UpdatedAt:     schedule.UpdatedAt,
+	}
+}
+
+// ToTripScheduleResponseList converts list of TripSchedule entities to TripScheduleResponse list
+func ToTripScheduleResponseList(schedules []TripSchedule) []TripScheduleResponse {
+	responses := make([]TripScheduleResponse, len(schedules))
+	for i, schedule := range schedules {
+		responses[i] = *ToTripScheduleResponse(&schedule)
+	}
+	return responses
+}
+
+// ToHolidayResponse converts Holiday entity to HolidayResponse
+func ToHolidayResponse(holiday *Holiday) *HolidayResponse {
+	if holiday == nil {
+		return nil
+	}
+
+	return &HolidayResponse{
+		ID:              holiday.ID,
+		Name:            holiday.Name,
+		StartDate:       holiday.StartDate.Format(constants.ScheduleDateFormat),
+		EndDate:         holiday.EndDate.Format(constants.ScheduleDateFormat),
+		RecurringYearly: holiday.RecurringYearly,
+		CreatedAt:       holiday.CreatedAt,
+	}
+}
+
+// ToHolidayResponseList converts list of Holiday entities to HolidayResponse list
+func ToHolidayResponseList(holidays []Holiday) []HolidayResponse {
+	responses := make([]HolidayResponse, len(holidays))
+	for i, holiday := range holidays {
+		responses[i] = *ToHolidayResponse(&holiday)
+	}
+	return responses
+}
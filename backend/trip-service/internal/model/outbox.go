@@ -0,0 +1,17 @@
+package model
+
+import "github.com/google/uuid"
+
+const (
+	AggregateTypeTrip = "trip"
+
+	// EventTypeTripCancelled starts the saga refunding and cancelling every booking of a trip cancelled by the operator
+	EventTypeTripCancelled = "trip.cancelled"
+)
+
+// TripCancelledEvent is the payload of EventTypeTripCancelled
+type TripCancelledEvent struct {
+	SagaID uuid.UUID `json:"saga_id"`
+	TripID uuid.UUID `json:"trip_id"`
+	Reason string    `json:"reason"`
+}
@@ -3,6 +3,8 @@ package payment
 import "github.com/google/uuid"
 
 type RefundRequest struct {
+	// ID is the idempotency key of the refund, a retried request returns the refund it created
+	ID           uuid.UUID `json:"id"`
 	BookingID    uuid.UUID `json:"booking_id"`
 	Reason       string    `json:"reason"`
 	RefundAmount int       `json:"refund_amount"`
@@ -12,3 +14,8 @@ type RefundResponse struct {
 	ID           uuid.UUID `json:"id"`
 	RefundStatus string    `json:"refund_status"`
 }
+
+const (
+	RefundStatusCompleted = "COMPLETED"
+	RefundStatusRejected  = "REJECTED"
+)
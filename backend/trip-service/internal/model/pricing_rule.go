@@ -0,0 +1,277 @@
+package model
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"bus-booking/trip-service/internal/constants"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"gorm.io/gorm"
+)
+
+// PricingRule adjusts the fare of trips matching all of its conditions. Unset conditions match
+// every trip, and a rule without RouteID applies to all routes. Rules apply from the highest
+// priority down, each one on the fare left by the previous rules.
+type PricingRule struct {
+	BaseModel
+	Name     string     `gorm:"type:varchar(255);not null" json:"name"`
+	Priority int        `gorm:"type:integer;not null;default:0" json:"priority"`
+	RouteID  *uuid.UUID `gorm:"type:uuid;index" json:"route_id,omitempty"`
+
+	// Conditions
+	MinLoadFactor *float64      `gorm:"type:decimal(4,3)" json:"min_load_factor,omitempty"` // share of seats booked, 0..1
+	MaxLoadFactor *float64      `gorm:"type:decimal(4,3)" json:"max_load_factor,omitempty"`
+	MinDaysBefore *int          `gorm:"type:integer" json:"min_days_before,omitempty"` // calendar days from today to the departure date
+	MaxDaysBefore *int          `gorm:"type:integer" json:"max_days_before,omitempty"`
+	Weekdays      pq.Int64Array `gorm:"type:integer[];not null;default:'{}'" json:"weekdays"` // 0 = Sunday ... 6 = Saturday, empty for every day
+	OnHoliday     *bool         `gorm:"type:boolean" json:"on_holiday,omitempty"`             // departure on (true) or outside (false) a holiday
+
+	// Effect: fare * Multiplier + Adjustment, then clamped between the ratios of the trip base price
+	Multiplier     float64  `gorm:"type:decimal(5,3);not null;default:1" json:"multiplier"`
+	Adjustment     float64  `gorm:"type:decimal(10,2);not null;default:0" json:"adjustment"`
+	MinPriceRatio  *float64 `gorm:"type:decimal(5,3)" json:"min_price_ratio,omitempty"`
+	MaxPriceRatio  *float64 `gorm:"type:decimal(5,3)" json:"max_price_ratio,omitempty"`
+	StopProcessing bool     `gorm:"type:boolean;not null;default:false" json:"stop_processing"` // skip lower priority rules once applied
+	IsActive       bool     `gorm:"type:boolean;not null;default:true" json:"is_active"`
+}
+
+func (PricingRule) TableName() string {
+	return "pricing_rules"
+}
+
+func (r *PricingRule) BeforeCreate(tx *gorm.DB) error {
+	if r.ID == uuid.Nil {
+		r.ID = uuid.New()
+	}
+	return nil
+}
+
+// Matches reports whether the rule applies to a trip in the given situation
+func (r *PricingRule) Matches(input *PricingInput) bool {
+	if r.RouteID != nil && *r.RouteID != input.RouteID {
+		return false
+	}
+	if r.MinLoadFactor != nil && input.LoadFactor < *r.MinLoadFactor {
+		return false
+	}
+	if r.MaxLoadFactor != nil && input.LoadFactor > *r.MaxLoadFactor {
+		return false
+	}
+	if r.MinDaysBefore != nil && input.DaysBefore < *r.MinDaysBefore {
+		return false
+	}
+	if r.MaxDaysBefore != nil && input.DaysBefore > *r.MaxDaysBefore {
+		return false
+	}
+	if r.OnHoliday != nil && *r.OnHoliday != (input.Holiday != "") {
+		return false
+	}
+	if len(r.Weekdays) == 0 {
+		return true
+	}
+	for _, d := range r.Weekdays {
+		if time.Weekday(d) == input.Weekday {
+			return true
+		}
+	}
+	return false
+}
+
+// Apply adjusts the fare and keeps it within the rule's bounds relative to the base price
+func (r *PricingRule) Apply(fare, basePrice float64) float64 {
+	fare = fare*r.Multiplier + r.Adjustment
+	if r.MinPriceRatio != nil {
+		fare = math.Max(fare, basePrice**r.MinPriceRatio)
+	}
+	if r.MaxPriceRatio != nil {
+		fare = math.Min(fare, basePrice**r.MaxPriceRatio)
+	}
+	return math.Max(fare, 0)
+}
+
+// PricingInput is what pricing rules look at when quoting a trip
+type PricingInput struct {
+	RouteID    uuid.UUID
+	LoadFactor float64
+	DaysBefore int
+	Weekday    time.Weekday
+	Holiday    string // name of the holiday the departure falls on, empty otherwise
+}
+
+// PriceFare applies the matching rules to the base price from the highest priority down, rules
+// of equal priority in the given order, and rounds the result to constants.FareRoundingUnit.
+// It returns the fare and the rules that were applied.
+func PriceFare(basePrice float64, rules []PricingRule, input *PricingInput) (float64, []PricingRule) {
+	ordered := make([]PricingRule, len(rules))
+	copy(ordered, rules)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return ordered[i].Priority > ordered[j].Priority
+	})
+
+	fare := basePrice
+	var applied []PricingRule
+	for i := range ordered {
+		rule := &ordered[i]
+		if !rule.IsActive || !rule.Matches(input) {
+			continue
+		}
+		fare = rule.Apply(fare, basePrice)
+		applied = append(applied, *rule)
+		if rule.StopProcessing {
+			break
+		}
+	}
+
+	return math.Round(fare/constants.FareRoundingUnit) * constants.FareRoundingUnit, applied
+}
+
+// Holiday is a range of days fares may be priced differently on, e.g. Tết or 30/4.
+// Recurring holidays repeat on the same month and day every year; holidays following
+// the lunar calendar are entered for each year instead.
+type Holiday struct {
+	BaseModel
+	Name            string    `gorm:"type:varchar(255);not null" json:"name"`
+	StartDate       time.Time `gorm:"type:date;not null" json:"start_date"`
+	EndDate         time.Time `gorm:"type:date;not null" json:"end_date"`
+	RecurringYearly bool      `gorm:"type:boolean;not null;default:false" json:"recurring_yearly"`
+}
+
+func (Holiday) TableName() string {
+	return "holidays"
+}
+
+func (h *Holiday) BeforeCreate(tx *gorm.DB) error {
+	if h.ID == uuid.Nil {
+		h.ID = uuid.New()
+	}
+	return nil
+}
+
+// Covers reports whether the calendar date of day falls within the holiday
+func (h *Holiday) Covers(day time.Time) bool {
+	if !h.RecurringYearly {
+		date := day.Format(constants.ScheduleDateFormat)
+		return date >= h.StartDate.Format(constants.ScheduleDateFormat) && date <= h.EndDate.Format(constants.ScheduleDateFormat)
+	}
+
+	// Compare month and day only; a range such as 30/12 - 2/1 wraps around the new year
+	const monthDay = "01-02"
+	date := day.Format(monthDay)
+	start, end := h.StartDate.Format(monthDay), h.EndDate.Format(monthDay)
+	if start <= end {
+		return date >= start && date <= end
+	}
+	return date >= start || date <= end
+}
+
+// TripFare is the current price of a trip, quoted from its base price and the pricing rules
+type TripFare struct {
+	TripID       uuid.UUID   `json:"trip_id"`
+	BasePrice    float64     `json:"base_price"`
+	Fare         float64     `json:"fare"` // replaces the base price; seat multipliers apply on top
+	LoadFactor   float64     `json:"load_factor"`
+	DaysBefore   int         `json:"days_before"`
+	Holiday      string      `json:"holiday,omitempty"`
+	AppliedRules []string    `json:"applied_rules"`
+	PriceTiers   []PriceTier `json:"price_tiers"`
+	QuotedAt     time.Time   `json:"quoted_at"`
+}
+
+// TiersAt reprices the tiers from another base fare, such as the prorated fare of a segment
+func (f *TripFare) TiersAt(fare float64) []PriceTier {
+	tiers := make([]PriceTier, len(f.PriceTiers))
+	for i, tier := range f.PriceTiers {
+		tier.BasePrice = fare
+		tier.FinalPrice = fare * tier.PriceMultiplier
+		tiers[i] = tier
+	}
+	return tiers
+}
+
+// BuildPriceTiers groups seats by type and multiplier and prices each group from the fare.
+// Seats booked or held for the trip are not counted as available.
+func BuildPriceTiers(fare float64, seats []Seat, taken map[uuid.UUID]bool) []PriceTier {
+	type tierKey struct {
+		seatType   constants.SeatType
+		multiplier float64
+	}
+
+	index := make(map[tierKey]int)
+	tiers := []PriceTier{}
+	for _, seat := range seats {
+		key := tierKey{seatType: seat.SeatType, multiplier: seat.PriceMultiplier}
+		i, ok := index[key]
+		if !ok {
+			i = len(tiers)
+			index[key] = i
+			tiers = append(tiers, PriceTier{
+				SeatType:        string(seat.SeatType),
+				BasePrice:       fare,
+				PriceMultiplier: seat.PriceMultiplier,
+				FinalPrice:      fare * seat.PriceMultiplier,
+			})
+		}
+		if seat.IsAvailable && !taken[seat.ID] {
+			tiers[i].AvailableCount++
+		}
+	}
+
+	sort.SliceStable(tiers, func(i, j int) bool {
+		return tiers[i].FinalPrice < tiers[j].FinalPrice
+	})
+	return tiers
+}
+
+// Request models
+type CreatePricingRuleRequest struct {
+	Name     string     `json:"name" binding:"required,max=255"`
+	Priority int        `json:"priority"`
+	RouteID  *uuid.UUID `json:"route_id,omitempty"`
+
+	MinLoadFactor *float64 `json:"min_load_factor,omitempty" binding:"omitempty,min=0,max=1"`
+	MaxLoadFactor *float64 `json:"max_load_factor,omitempty" binding:"omitempty,min=0,max=1"`
+	MinDaysBefore *int     `json:"min_days_before,omitempty" binding:"omitempty,min=0"`
+	MaxDaysBefore *int     `json:"max_days_before,omitempty" binding:"omitempty,min=0"`
+	Weekdays      []int    `json:"weekdays,omitempty" binding:"omitempty,max=7,dive,min=0,max=6"`
+	OnHoliday     *bool    `json:"on_holiday,omitempty"`
+
+	Multiplier     *float64 `json:"multiplier,omitempty" binding:"omitempty,gt=0"` // defaults to 1
+	Adjustment     float64  `json:"adjustment"`
+	MinPriceRatio  *float64 `json:"min_price_ratio,omitempty" binding:"omitempty,gt=0"`
+	MaxPriceRatio  *float64 `json:"max_price_ratio,omitempty" binding:"omitempty,gt=0"`
+	StopProcessing bool     `json:"stop_processing"`
+}
+
+// UpdatePricingRuleRequest replaces every field of the rule
+type UpdatePricingRuleRequest struct {
+	CreatePricingRuleRequest
+	IsActive *bool `json:"is_active,omitempty"`
+}
+
+type ListPricingRulesRequest struct {
+	PaginationRequest
+	RouteID *string `form:"route_id" json:"route_id,omitempty"`
+}
+
+type CreateHolidayRequest struct {
+	Name            string `json:"name" binding:"required,max=255"`
+	StartDate       string `json:"start_date" binding:"required"` // YYYY-MM-DD
+	EndDate         string `json:"end_date" binding:"required"`   // YYYY-MM-DD
+	RecurringYearly bool   `json:"recurring_yearly"`
+}
+
+type ListHolidaysRequest struct {
+	Year int `form:"year" json:"year,omitempty" binding:"omitempty,min=2000,max=2100"` // holidays falling in this year, all when empty
+}
+
+type HolidayResponse struct {
+	ID              uuid.UUID `json:"id"`
+	Name            string    `json:"name"`
+	StartDate       string    `json:"start_date"`
+	EndDate         string    `json:"end_date"`
+	RecurringYearly bool      `json:"recurring_yearly"`
+	CreatedAt       time.Time `json:"created_at"`
+}
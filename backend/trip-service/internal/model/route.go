@@ -1,6 +1,7 @@
 package model
 
 import (
+	"math"
 	"time"
 
 	"github.com/google/uuid"
@@ -76,3 +77,52 @@ type UpdateRouteRequest struct {
 	EstimatedMinutes *int     `json:"estimated_minutes,omitempty" validate:"omitempty,min=1"`
 	IsActive         *bool    `json:"is_active,omitempty"`
 }
+
+// FindSegment returns the first pickup stop matching origin and the first later
+// dropoff stop matching destination. Empty places match the route's first pickup
+// and last dropoff stops. Stops must be ordered by stop_order.
+func (r *Route) FindSegment(origin, destination string) (*RouteStop, *RouteStop) {
+	var pickup *RouteStop
+	pickupIdx := -1
+	for i := range r.RouteStops {
+		stop := &r.RouteStops[i]
+		if stop.CanPickup() && (origin == "" || stop.MatchesPlace(origin)) {
+			pickup, pickupIdx = stop, i
+			break
+		}
+	}
+	if pickup == nil {
+		return nil, nil
+	}
+
+	var dropoff *RouteStop
+	for i := pickupIdx + 1; i < len(r.RouteStops); i++ {
+		stop := &r.RouteStops[i]
+		if !stop.CanDropoff() {
+			continue
+		}
+		if destination == "" {
+			dropoff = stop
+			continue
+		}
+		if stop.MatchesPlace(destination) {
+			dropoff = stop
+			break
+		}
+	}
+	if dropoff == nil {
+		return nil, nil
+	}
+
+	return pickup, dropoff
+}
+
+// DistanceFraction returns the share of the route distance travelled between two stops.
+// Falls back to the full route when stop distances are not configured.
+func (r *Route) DistanceFraction(pickup, dropoff *RouteStop) float64 {
+	distance := dropoff.DistanceKm - pickup.DistanceKm
+	if r.DistanceKm <= 0 || distance <= 0 {
+		return 1
+	}
+	return math.Min(distance/r.DistanceKm, 1)
+}
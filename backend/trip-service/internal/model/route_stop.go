@@ -1,6 +1,8 @@
 package model
 
 import (
+	"strings"
+
 	"bus-booking/trip-service/internal/constants"
 
 	"github.com/google/uuid"
@@ -17,6 +19,7 @@ type RouteStop struct {
 	Latitude      *float64           `gorm:"type:decimal(10,8)" json:"latitude,omitempty"`
 	Longitude     *float64           `gorm:"type:decimal(11,8)" json:"longitude,omitempty"`
 	OffsetMinutes int                `gorm:"type:integer;not null" json:"offset_minutes"`
+	DistanceKm    float64            `gorm:"type:decimal(10,2);not null;default:0" json:"distance_km"` // from route origin
 	IsActive      bool               `gorm:"type:boolean;not null;default:true" json:"is_active"`
 	Route         Route              `gorm:"foreignKey:RouteID" json:"route,omitempty"`
 }
@@ -42,6 +45,7 @@ type CreateRouteStopRequest struct {
 	Latitude      *float64           `json:"latitude,omitempty" validate:"omitempty,min=-90,max=90"`
 	Longitude     *float64           `json:"longitude,omitempty" validate:"omitempty,min=-180,max=180"`
 	OffsetMinutes int                `json:"offset_minutes" validate:"min=0"`
+	DistanceKm    float64            `json:"distance_km" validate:"min=0"`
 }
 
 type UpdateRouteStopRequest struct {
@@ -52,6 +56,7 @@ type UpdateRouteStopRequest struct {
 	Latitude      *float64            `json:"latitude,omitempty" validate:"omitempty,min=-90,max=90"`
 	Longitude     *float64            `json:"longitude,omitempty" validate:"omitempty,min=-180,max=180"`
 	OffsetMinutes *int                `json:"offset_minutes,omitempty" validate:"omitempty,min=0"`
+	DistanceKm    *float64            `json:"distance_km,omitempty" validate:"omitempty,min=0"`
 	IsActive      *bool               `json:"is_active,omitempty"`
 }
 
@@ -71,5 +76,42 @@ type RouteStopResponse struct {
 	Latitude      *float64           `json:"latitude,omitempty"`
 	Longitude     *float64           `json:"longitude,omitempty"`
 	OffsetMinutes int                `json:"offset_minutes"`
+	DistanceKm    float64            `json:"distance_km"`
 	IsActive      bool               `json:"is_active"`
 }
+
+// CanPickup reports whether passengers may board at this stop
+func (rs *RouteStop) CanPickup() bool {
+	return rs.IsActive && (rs.StopType == constants.StopTypePickup || rs.StopType == constants.StopTypeBoth)
+}
+
+// CanDropoff reports whether passengers may alight at this stop
+func (rs *RouteStop) CanDropoff() bool {
+	return rs.IsActive && (rs.StopType == constants.StopTypeDropoff || rs.StopType == constants.StopTypeBoth)
+}
+
+// MatchesPlace reports whether the stop location or address contains the given place (case-insensitive)
+func (rs *RouteStop) MatchesPlace(place string) bool {
+	place = strings.ToLower(strings.TrimSpace(place))
+	return strings.Contains(strings.ToLower(rs.Location), place) ||
+		strings.Contains(strings.ToLower(rs.Address), place)
+}
+
+// City returns the city part of the stop address, i.e. its last comma-separated part
+// without a "TP." prefix. Falls back to the location when no address is set.
+func (rs *RouteStop) City() string {
+	parts := strings.Split(rs.Address, ",")
+	city := strings.TrimSpace(parts[len(parts)-1])
+	for _, prefix := range []string{"TP.", "Thành phố"} {
+		city = strings.TrimSpace(strings.TrimPrefix(city, prefix))
+	}
+	if city == "" {
+		return strings.TrimSpace(rs.Location)
+	}
+	return city
+}
+
+// SameCity reports whether both stops are in the same city, so passengers can change buses between them
+func (rs *RouteStop) SameCity(other *RouteStop) bool {
+	return strings.EqualFold(rs.City(), other.City())
+}
@@ -2,6 +2,7 @@ package model
 
 import (
 	"bus-booking/trip-service/internal/constants"
+	"math"
 	"time"
 
 	"github.com/google/uuid"
@@ -17,6 +18,7 @@ type Trip struct {
 	BasePrice     float64              `gorm:"type:decimal(10,2);not null" json:"base_price" validate:"required,min=0"`
 	Status        constants.TripStatus `gorm:"type:varchar(50);not null;default:'scheduled'" json:"status" validate:"required"`
 	IsActive      bool                 `gorm:"type:boolean;not null;default:true" json:"is_active"`
+	ScheduleID    *uuid.UUID           `gorm:"type:uuid;index" json:"schedule_id,omitempty"`
 
 	Route *Route `gorm:"constraint:OnUpdate:CASCADE" json:"route,omitempty"`
 	Bus   *Bus   `gorm:"constraint:OnUpdate:CASCADE" json:"bus,omitempty"`
@@ -68,6 +70,10 @@ type GetTripByIDRequest struct {
 	PreLoadRouteStop  bool `form:"preload_route_stop" json:"preload_route_stop"`
 	PreloadBus        bool `form:"preload_bus" json:"preload_bus"`
 	PreloadSeat       bool `form:"preload_seat" json:"preload_seat"`
+
+	// Optional segment for seat booking status; both stops must be given together
+	PickupStopID  string `form:"pickup_stop_id" json:"pickup_stop_id,omitempty"`
+	DropoffStopID string `form:"dropoff_stop_id" json:"dropoff_stop_id,omitempty"`
 }
 
 type TripDetail struct {
@@ -81,9 +87,46 @@ type TripDetail struct {
 	AvailableSeats int       `json:"available_seats"`
 	TotalSeats     int       `json:"total_seats"`
 
-	Route      *RouteDetail `json:"route,omitempty"`
-	Bus        *BusDetail   `json:"bus,omitempty"`
-	PriceTiers []PriceTier  `json:"price_tiers,omitempty"`
+	Route      *RouteDetail       `json:"route,omitempty"`
+	Bus        *BusDetail         `json:"bus,omitempty"`
+	PriceTiers []PriceTier        `json:"price_tiers,omitempty"`
+	Segment    *TripSegmentDetail `json:"segment,omitempty"` // set when searching between intermediate stops
+}
+
+// TripSegmentDetail describes the part of a trip between a pickup and a later dropoff stop
+type TripSegmentDetail struct {
+	PickupStop    RouteStopResponse `json:"pickup_stop"`
+	DropoffStop   RouteStopResponse `json:"dropoff_stop"`
+	DepartureTime time.Time         `json:"departure_time"`
+	ArrivalTime   time.Time         `json:"arrival_time"`
+	DistanceKm    float64           `json:"distance_km"`
+	BasePrice     float64           `json:"base_price"`
+}
+
+// NewTripSegmentDetail builds segment times and fare from the trip schedule and stop offsets
+func NewTripSegmentDetail(trip *Trip, pickup, dropoff *RouteStop) *TripSegmentDetail {
+	segment := &TripSegmentDetail{
+		PickupStop:    *ToRouteStopResponse(pickup),
+		DropoffStop:   *ToRouteStopResponse(dropoff),
+		DepartureTime: trip.DepartureTime.Add(time.Duration(pickup.OffsetMinutes) * time.Minute),
+		ArrivalTime:   trip.DepartureTime.Add(time.Duration(dropoff.OffsetMinutes) * time.Minute),
+		BasePrice:     trip.BasePrice,
+	}
+	if trip.Route != nil {
+		fraction := trip.Route.DistanceFraction(pickup, dropoff)
+		segment.DistanceKm = trip.Route.DistanceKm * fraction
+		segment.BasePrice = SegmentBasePrice(trip.BasePrice, fraction)
+	}
+	return segment
+}
+
+// SegmentBasePrice prorates the trip base price by distance, rounded to the nearest 1,000 VND.
+// Booking service applies the same rule when charging segment bookings.
+func SegmentBasePrice(basePrice, fraction float64) float64 {
+	if fraction >= 1 {
+		return basePrice
+	}
+	return math.Round(basePrice*fraction/1000) * 1000
 }
 
 type RouteDetail struct {
@@ -125,6 +168,7 @@ type TripResponse struct {
 	BasePrice     float64        `json:"base_price"`
 	Status        string         `json:"status"` // Raw string value
 	IsActive      bool           `json:"is_active"`
+	ScheduleID    *uuid.UUID     `json:"schedule_id,omitempty"`
 	Route         *RouteResponse `json:"route,omitempty"`
 	Bus           *BusResponse   `json:"bus,omitempty"`
 	CreatedAt     time.Time      `json:"created_at"`
@@ -0,0 +1,130 @@
+package model
+
+import (
+	"time"
+
+	"bus-booking/trip-service/internal/constants"
+
+	"github.com/google/uuid"
+)
+
+type TripCancellationStatus string
+
+const (
+	TripCancellationStatusRunning   TripCancellationStatus = "RUNNING"
+	TripCancellationStatusCompleted TripCancellationStatus = "COMPLETED"
+	TripCancellationStatusFailed    TripCancellationStatus = "FAILED"
+	TripCancellationStatusAborted   TripCancellationStatus = "ABORTED"
+)
+
+// TripCancellationStep is a step the saga runs for every booking, in this order
+type TripCancellationStep string
+
+const (
+	TripCancellationStepCancelBooking   TripCancellationStep = "cancel_booking"
+	TripCancellationStepRequestRefund   TripCancellationStep = "request_refund"
+	TripCancellationStepNotifyPassenger TripCancellationStep = "notify_passenger"
+	TripCancellationStepCompleteRefund  TripCancellationStep = "complete_refund"
+)
+
+// TripCancellationSaga refunds and cancels the bookings of a trip cancelled by the operator.
+// Its state is persisted so a restarted service resumes where it stopped.
+type TripCancellationSaga struct {
+	ID             uuid.UUID              `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	TripID         uuid.UUID              `gorm:"type:uuid;not null" json:"trip_id"`
+	PreviousStatus constants.TripStatus   `gorm:"type:varchar(50);not null" json:"previous_status"`
+	Reason         string                 `gorm:"type:text;not null" json:"reason"`
+	Status         TripCancellationStatus `gorm:"type:varchar(20);not null;default:'RUNNING'" json:"status"`
+	BookingsLoaded bool                   `gorm:"not null;default:false" json:"bookings_loaded"`
+	LockedUntil    *time.Time             `json:"-"`
+	CompletedAt    *time.Time             `json:"completed_at,omitempty"`
+	CreatedAt      time.Time              `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt      time.Time              `gorm:"autoUpdateTime" json:"updated_at"`
+
+	Bookings []TripCancellationBooking `gorm:"foreignKey:SagaID" json:"bookings,omitempty"`
+}
+
+func (TripCancellationSaga) TableName() string {
+	return "trip_cancellation_sagas"
+}
+
+// TripCancellationBooking is the step state of one booking within a trip cancellation
+type TripCancellationBooking struct {
+	ID                  uuid.UUID              `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	SagaID              uuid.UUID              `gorm:"type:uuid;not null" json:"saga_id"`
+	BookingID           uuid.UUID              `gorm:"type:uuid;not null" json:"booking_id"`
+	TransactionStatus   string                 `gorm:"type:varchar(20);not null" json:"transaction_status"`
+	RefundRequired      bool                   `gorm:"not null;default:false" json:"refund_required"`
+	RefundAmount        int                    `gorm:"not null;default:0" json:"refund_amount"`
+	RefundID            *uuid.UUID             `gorm:"type:uuid" json:"refund_id,omitempty"`
+	RefundStatus        string                 `gorm:"type:varchar(20)" json:"refund_status,omitempty"`
+	BookingCancelledAt  *time.Time             `json:"booking_cancelled_at,omitempty"`
+	RefundRequestedAt   *time.Time             `json:"refund_requested_at,omitempty"`
+	PassengerNotifiedAt *time.Time             `json:"passenger_notified_at,omitempty"`
+	RefundCompletedAt   *time.Time             `json:"refund_completed_at,omitempty"`
+	Status              TripCancellationStatus `gorm:"type:varchar(20);not null;default:'RUNNING'" json:"status"`
+	Attempts            int                    `gorm:"not null;default:0" json:"attempts"`
+	FailedStep          TripCancellationStep   `gorm:"type:varchar(50)" json:"failed_step,omitempty"`
+	LastError           string                 `gorm:"type:text" json:"last_error,omitempty"`
+	CreatedAt           time.Time              `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt           time.Time              `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+func (TripCancellationBooking) TableName() string {
+	return "trip_cancellation_bookings"
+}
+
+// TripCancellationProgress summarises a trip cancellation for operators
+type TripCancellationProgress struct {
+	SagaID             uuid.UUID                 `json:"saga_id"`
+	TripID             uuid.UUID                 `json:"trip_id"`
+	Status             TripCancellationStatus    `json:"status"`
+	Reason             string                    `json:"reason"`
+	BookingsLoaded     bool                      `json:"bookings_loaded"`
+	TotalBookings      int                       `json:"total_bookings"`
+	BookingsCancelled  int                       `json:"bookings_cancelled"`
+	RefundsRequested   int                       `json:"refunds_requested"`
+	RefundsCompleted   int                       `json:"refunds_completed"`
+	PassengersNotified int                       `json:"passengers_notified"`
+	FailedBookings     int                       `json:"failed_bookings"`
+	CreatedAt          time.Time                 `json:"created_at"`
+	CompletedAt        *time.Time                `json:"completed_at,omitempty"`
+	Bookings           []TripCancellationBooking `json:"bookings"`
+}
+
+// NewTripCancellationProgress counts the steps every booking of the saga has completed
+func NewTripCancellationProgress(saga *TripCancellationSaga) *TripCancellationProgress {
+	progress := &TripCancellationProgress{
+		SagaID:         saga.ID,
+		TripID:         saga.TripID,
+		Status:         saga.Status,
+		Reason:         saga.Reason,
+		BookingsLoaded: saga.BookingsLoaded,
+		TotalBookings:  len(saga.Bookings),
+		CreatedAt:      saga.CreatedAt,
+		CompletedAt:    saga.CompletedAt,
+		Bookings:       saga.Bookings,
+	}
+	if progress.Bookings == nil {
+		progress.Bookings = []TripCancellationBooking{}
+	}
+
+	for _, b := range saga.Bookings {
+		if b.BookingCancelledAt != nil {
+			progress.BookingsCancelled++
+		}
+		if b.RefundRequestedAt != nil {
+			progress.RefundsRequested++
+		}
+		if b.RefundCompletedAt != nil {
+			progress.RefundsCompleted++
+		}
+		if b.PassengerNotifiedAt != nil {
+			progress.PassengersNotified++
+		}
+		if b.Status == TripCancellationStatusFailed {
+			progress.FailedBookings++
+		}
+	}
+	return progress
+}
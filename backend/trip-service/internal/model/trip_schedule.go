@@ -0,0 +1,143 @@
+package model
+
+import (
+	"time"
+
+	"bus-booking/trip-service/internal/constants"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"gorm.io/gorm"
+)
+
+// TripSchedule is a recurring template that the generator materializes into Trip rows
+type TripSchedule struct {
+	BaseModel
+	RouteID       uuid.UUID     `gorm:"type:uuid;not null;index" json:"route_id"`
+	BusID         uuid.UUID     `gorm:"type:uuid;not null;index" json:"bus_id"`
+	DepartureTime string        `gorm:"type:varchar(5);not null" json:"departure_time"` // HH:MM in constants.ScheduleTimeZone
+	Weekdays      pq.Int64Array `gorm:"type:integer[];not null" json:"weekdays"`        // 0 = Sunday ... 6 = Saturday
+	ValidFrom     time.Time     `gorm:"type:date;not null" json:"valid_from"`
+	ValidUntil    *time.Time    `gorm:"type:date" json:"valid_until,omitempty"`
+	BasePrice     float64       `gorm:"type:decimal(10,2);not null" json:"base_price"`
+	IsActive      bool          `gorm:"type:boolean;not null;default:true" json:"is_active"`
+
+	Route *Route `gorm:"foreignKey:RouteID" json:"route,omitempty"`
+	Bus   *Bus   `gorm:"foreignKey:BusID" json:"bus,omitempty"`
+}
+
+func (TripSchedule) TableName() string {
+	return "trip_schedules"
+}
+
+func (ts *TripSchedule) BeforeCreate(tx *gorm.DB) error {
+	if ts.ID == uuid.Nil {
+		ts.ID = uuid.New()
+	}
+	return nil
+}
+
+// RunsOn reports whether the schedule operates on the given day of week
+func (ts *TripSchedule) RunsOn(weekday time.Weekday) bool {
+	for _, d := range ts.Weekdays {
+		if time.Weekday(d) == weekday {
+			return true
+		}
+	}
+	return false
+}
+
+// DeparturesBetween lists departures within [from, to] that fall on a scheduled
+// weekday inside the validity window. Dates are evaluated in loc.
+func (ts *TripSchedule) DeparturesBetween(from, to time.Time, loc *time.Location) ([]time.Time, error) {
+	clock, err := time.Parse(constants.ScheduleTimeFormat, ts.DepartureTime)
+	if err != nil {
+		return nil, err
+	}
+
+	validFrom := dateIn(ts.ValidFrom, loc)
+	var validUntil *time.Time
+	if ts.ValidUntil != nil {
+		until := dateIn(*ts.ValidUntil, loc)
+		validUntil = &until
+	}
+
+	var departures []time.Time
+	start := dateIn(from.In(loc), loc)
+	for day := start; !day.After(to); day = day.AddDate(0, 0, 1) {
+		if day.Before(validFrom) || (validUntil != nil && day.After(*validUntil)) {
+			continue
+		}
+		if !ts.RunsOn(day.Weekday()) {
+			continue
+		}
+
+		departure := time.Date(day.Year(), day.Month(), day.Day(), clock.Hour(), clock.Minute(), 0, 0, loc)
+		if departure.Before(from) || departure.After(to) {
+			continue
+		}
+		departures = append(departures, departure)
+	}
+
+	return departures, nil
+}
+
+// dateIn truncates t to midnight of its calendar date, interpreting the date in loc
+func dateIn(t time.Time, loc *time.Location) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc)
+}
+
+// Request models
+type CreateTripScheduleRequest struct {
+	RouteID       uuid.UUID `json:"route_id" validate:"required"`
+	BusID         uuid.UUID `json:"bus_id" validate:"required"`
+	DepartureTime string    `json:"departure_time" validate:"required,datetime=15:04"`
+	Weekdays      []int     `json:"weekdays" validate:"required,min=1,max=7,dive,min=0,max=6"`
+	ValidFrom     string    `json:"valid_from" validate:"required,datetime=2006-01-02"`
+	ValidUntil    *string   `json:"valid_until,omitempty" validate:"omitempty,datetime=2006-01-02"`
+	BasePrice     float64   `json:"base_price" validate:"required,min=0"`
+}
+
+type UpdateTripScheduleRequest struct {
+	BusID         *uuid.UUID `json:"bus_id,omitempty"`
+	DepartureTime *string    `json:"departure_time,omitempty" validate:"omitempty,datetime=15:04"`
+	Weekdays      []int      `json:"weekdays,omitempty" validate:"omitempty,min=1,max=7,dive,min=0,max=6"`
+	ValidFrom     *string    `json:"valid_from,omitempty" validate:"omitempty,datetime=2006-01-02"`
+	ValidUntil    *string    `json:"valid_until,omitempty" validate:"omitempty,datetime=2006-01-02"`
+	BasePrice     *float64   `json:"base_price,omitempty" validate:"omitempty,min=0"`
+	IsActive      *bool      `json:"is_active,omitempty"`
+}
+
+type ListTripSchedulesRequest struct {
+	PaginationRequest
+	RouteID *string `form:"route_id" json:"route_id,omitempty"`
+}
+
+type GenerateTripsRequest struct {
+	DaysAhead int `form:"days_ahead" json:"days_ahead" validate:"omitempty,min=1,max=90"` // defaults to constants.ScheduleDefaultDaysAhead
+}
+
+// GenerateTripsResult summarizes a generator run
+type GenerateTripsResult struct {
+	Schedules int `json:"schedules"`
+	Created   int `json:"created"`
+	Existing  int `json:"existing"`
+	Conflicts int `json:"conflicts"`
+	Failed    int `json:"failed"`
+}
+
+type TripScheduleResponse struct {
+	ID            uuid.UUID      `json:"id"`
+	RouteID       uuid.UUID      `json:"route_id"`
+	BusID         uuid.UUID      `json:"bus_id"`
+	DepartureTime string         `json:"departure_time"`
+	Weekdays      []int          `json:"weekdays"`
+	ValidFrom     string         `json:"valid_from"`
+	ValidUntil    *string        `json:"valid_until,omitempty"`
+	BasePrice     float64        `json:"base_price"`
+	IsActive      bool           `json:"is_active"`
+	Route         *RouteResponse `json:"route,omitempty"`
+	Bus           *BusResponse   `json:"bus,omitempty"`
+	CreatedAt     time.Time      `json:"created_at"`
+	UpdatedAt     time.Time      `json:"updated_at"`
+}
@@ -65,21 +65,6 @@ func (mr *MockTripRepositoryMockRecorder) DeleteTrip(ctx, id interface{}) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTrip", reflect.TypeOf((*MockTripRepository)(nil).DeleteTrip), ctx, id)
 }
 
-// GetCompletedTripsForReschedule mocks base method.
-func (m *MockTripRepository) GetCompletedTripsForReschedule(ctx context.Context) ([]model.Trip, error) {
-	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetCompletedTripsForReschedule", ctx)
-	ret0, _ := ret[0].([]model.Trip)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
-}
-
-// GetCompletedTripsForReschedule indicates an expected call of GetCompletedTripsForReschedule.
-func (mr *MockTripRepositoryMockRecorder) GetCompletedTripsForReschedule(ctx interface{}) *gomock.Call {
-	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCompletedTripsForReschedule", reflect.TypeOf((*MockTripRepository)(nil).GetCompletedTripsForReschedule), ctx)
-}
-
 // GetTripByID mocks base method.
 func (m *MockTripRepository) GetTripByID(ctx context.Context, req *model.GetTripByIDRequest, id uuid.UUID) (*model.Trip, error) {
 	m.ctrl.T.Helper()
@@ -140,6 +125,21 @@ func (mr *MockTripRepositoryMockRecorder) GetTripsByRouteAndDate(ctx, routeID, d
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTripsByRouteAndDate", reflect.TypeOf((*MockTripRepository)(nil).GetTripsByRouteAndDate), ctx, routeID, date)
 }
 
+// ListDepartingTrips mocks base method.
+func (m *MockTripRepository) ListDepartingTrips(ctx context.Context, from, to time.Time) ([]model.Trip, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDepartingTrips", ctx, from, to)
+	ret0, _ := ret[0].([]model.Trip)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDepartingTrips indicates an expected call of ListDepartingTrips.
+func (mr *MockTripRepositoryMockRecorder) ListDepartingTrips(ctx, from, to interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDepartingTrips", reflect.TypeOf((*MockTripRepository)(nil).ListDepartingTrips), ctx, from, to)
+}
+
 // ListTrips mocks base method.
 func (m *MockTripRepository) ListTrips(ctx context.Context, page, pageSize int) ([]model.Trip, int64, error) {
 	m.ctrl.T.Helper()
@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"context"
+
+	"bus-booking/trip-service/internal/model"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type PricingRuleRepository interface {
+	Create(ctx context.Context, rule *model.PricingRule) error
+	Update(ctx context.Context, rule *model.PricingRule) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	GetByID(ctx context.Context, id uuid.UUID) (*model.PricingRule, error)
+	List(ctx context.Context, routeID *uuid.UUID, page, pageSize int) ([]model.PricingRule, int64, error)
+	ListActive(ctx context.Context) ([]model.PricingRule, error)
+
+	CreateHoliday(ctx context.Context, holiday *model.Holiday) error
+	DeleteHoliday(ctx context.Context, id uuid.UUID) error
+	ListHolidays(ctx context.Context) ([]model.Holiday, error)
+}
+
+type PricingRuleRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewPricingRuleRepository(db *gorm.DB) PricingRuleRepository {
+	return &PricingRuleRepositoryImpl{db: db}
+}
+
+func (r *PricingRuleRepositoryImpl) Create(ctx context.Context, rule *model.PricingRule) error {
+	return r.db.WithContext(ctx).Create(rule).Error
+}
+
+func (r *PricingRuleRepositoryImpl) Update(ctx context.Context, rule *model.PricingRule) error {
+	return r.db.WithContext(ctx).Save(rule).Error
+}
+
+func (r *PricingRuleRepositoryImpl) Delete(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Delete(&model.PricingRule{}, "id = ?", id).Error
+}
+
+func (r *PricingRuleRepositoryImpl) GetByID(ctx context.Context, id uuid.UUID) (*model.PricingRule, error) {
+	var rule model.PricingRule
+	if err := r.db.WithContext(ctx).First(&rule, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
+
+func (r *PricingRuleRepositoryImpl) List(ctx context.Context, routeID *uuid.UUID, page, pageSize int) ([]model.PricingRule, int64, error) {
+	var rules []model.PricingRule
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&model.PricingRule{})
+	if routeID != nil {
+		query = query.Where("route_id = ?", *routeID)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * pageSize
+	err := query.
+		Offset(offset).
+		Limit(pageSize).
+		Order("priority DESC, created_at ASC").
+		Find(&rules).Error
+
+	return rules, total, err
+}
+
+// ListActive returns the rules in effect, in the order they apply
+func (r *PricingRuleRepositoryImpl) ListActive(ctx context.Context) ([]model.PricingRule, error) {
+	var rules []model.PricingRule
+	err := r.db.WithContext(ctx).
+		Where("is_active = ?", true).
+		Order("priority DESC, created_at ASC").
+		Find(&rules).Error
+	return rules, err
+}
+
+func (r *PricingRuleRepositoryImpl) CreateHoliday(ctx context.Context, holiday *model.Holiday) error {
+	return r.db.WithContext(ctx).Create(holiday).Error
+}
+
+func (r *PricingRuleRepositoryImpl) DeleteHoliday(ctx context.Context, id uuid.UUID) error {
+	result := r.db.WithContext(ctx).Delete(&model.Holiday{}, "id = ?", id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *PricingRuleRepositoryImpl) ListHolidays(ctx context.Context) ([]model.Holiday, error) {
+	var holidays []model.Holiday
+	err := r.db.WithContext(ctx).
+		Order("start_date ASC").
+		Find(&holidays).Error
+	return holidays, err
+}
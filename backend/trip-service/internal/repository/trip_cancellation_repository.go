@@ -0,0 +1,168 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"bus-booking/shared/outbox"
+	"bus-booking/trip-service/internal/model"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type TripCancellationRepository interface {
+	// Start marks the trip cancelled and creates its saga and outbox events in one transaction
+	Start(ctx context.Context, trip *model.Trip, saga *model.TripCancellationSaga, events ...*outbox.Event) error
+	GetByID(ctx context.Context, id uuid.UUID) (*model.TripCancellationSaga, error)
+	GetLatestByTripID(ctx context.Context, tripID uuid.UUID) (*model.TripCancellationSaga, error)
+	ListRunningIDs(ctx context.Context) ([]uuid.UUID, error)
+
+	AcquireLease(ctx context.Context, id uuid.UUID, until time.Time) (bool, error)
+	ReleaseLease(ctx context.Context, id uuid.UUID) error
+
+	AddBookings(ctx context.Context, saga *model.TripCancellationSaga, bookings []model.TripCancellationBooking) error
+	UpdateBooking(ctx context.Context, booking *model.TripCancellationBooking) error
+	UpdateStatus(ctx context.Context, saga *model.TripCancellationSaga) error
+	RetryFailed(ctx context.Context, id uuid.UUID) (int64, error)
+	// Abort restores the trip status the saga replaced and marks the saga aborted
+	Abort(ctx context.Context, saga *model.TripCancellationSaga) error
+}
+
+type TripCancellationRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewTripCancellationRepository(db *gorm.DB) TripCancellationRepository {
+	return &TripCancellationRepositoryImpl{db: db}
+}
+
+func (r *TripCancellationRepositoryImpl) Start(ctx context.Context, trip *model.Trip, saga *model.TripCancellationSaga, events ...*outbox.Event) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(trip).Updates(trip).Error; err != nil {
+			return err
+		}
+		if err := tx.Create(saga).Error; err != nil {
+			return err
+		}
+		return outbox.Add(tx, events...)
+	})
+}
+
+func (r *TripCancellationRepositoryImpl) GetByID(ctx context.Context, id uuid.UUID) (*model.TripCancellationSaga, error) {
+	var saga model.TripCancellationSaga
+	if err := r.withBookings(r.db.WithContext(ctx)).First(&saga, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &saga, nil
+}
+
+func (r *TripCancellationRepositoryImpl) GetLatestByTripID(ctx context.Context, tripID uuid.UUID) (*model.TripCancellationSaga, error) {
+	var saga model.TripCancellationSaga
+	if err := r.withBookings(r.db.WithContext(ctx)).
+		Where("trip_id = ?", tripID).
+		Order("created_at DESC").
+		First(&saga).Error; err != nil {
+		return nil, err
+	}
+	return &saga, nil
+}
+
+func (r *TripCancellationRepositoryImpl) withBookings(db *gorm.DB) *gorm.DB {
+	return db.Preload("Bookings", func(db *gorm.DB) *gorm.DB {
+		return db.Order("created_at ASC, booking_id ASC")
+	})
+}
+
+func (r *TripCancellationRepositoryImpl) ListRunningIDs(ctx context.Context) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	err := r.db.WithContext(ctx).Model(&model.TripCancellationSaga{}).
+		Where("status = ?", model.TripCancellationStatusRunning).
+		Order("created_at ASC").
+		Pluck("id", &ids).Error
+	return ids, err
+}
+
+// AcquireLease claims the saga until the given time unless another worker holds an unexpired lease
+func (r *TripCancellationRepositoryImpl) AcquireLease(ctx context.Context, id uuid.UUID, until time.Time) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&model.TripCancellationSaga{}).
+		Where("id = ? AND (locked_until IS NULL OR locked_until < ?)", id, time.Now()).
+		Update("locked_until", until)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+func (r *TripCancellationRepositoryImpl) ReleaseLease(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Model(&model.TripCancellationSaga{}).
+		Where("id = ?", id).
+		Update("locked_until", nil).Error
+}
+
+// AddBookings records the bookings the saga has to handle; loading them twice keeps the first copy
+func (r *TripCancellationRepositoryImpl) AddBookings(ctx context.Context, saga *model.TripCancellationSaga, bookings []model.TripCancellationBooking) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if len(bookings) > 0 {
+			if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&bookings).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Model(saga).Update("bookings_loaded", true).Error
+	})
+}
+
+func (r *TripCancellationRepositoryImpl) UpdateBooking(ctx context.Context, booking *model.TripCancellationBooking) error {
+	return r.db.WithContext(ctx).Save(booking).Error
+}
+
+func (r *TripCancellationRepositoryImpl) UpdateStatus(ctx context.Context, saga *model.TripCancellationSaga) error {
+	return r.db.WithContext(ctx).Model(saga).
+		Select("status", "completed_at").
+		Updates(saga).Error
+}
+
+// RetryFailed puts the failed bookings of the saga back in line and reopens the saga
+func (r *TripCancellationRepositoryImpl) RetryFailed(ctx context.Context, id uuid.UUID) (int64, error) {
+	var retried int64
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&model.TripCancellationBooking{}).
+			Where("saga_id = ? AND status = ?", id, model.TripCancellationStatusFailed).
+			Updates(map[string]interface{}{
+				"status":      model.TripCancellationStatusRunning,
+				"attempts":    0,
+				"failed_step": nil,
+				"last_error":  nil,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		retried = result.RowsAffected
+
+		return tx.Model(&model.TripCancellationSaga{}).
+			Where("id = ?", id).
+			Updates(map[string]interface{}{
+				"status":       model.TripCancellationStatusRunning,
+				"completed_at": nil,
+			}).Error
+	})
+	return retried, err
+}
+
+func (r *TripCancellationRepositoryImpl) Abort(ctx context.Context, saga *model.TripCancellationSaga) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&model.Trip{}).
+			Where("id = ?", saga.TripID).
+			Update("status", saga.PreviousStatus).Error; err != nil {
+			return err
+		}
+
+		now := time.Now()
+		saga.Status = model.TripCancellationStatusAborted
+		saga.CompletedAt = &now
+		return tx.Model(saga).
+			Select("status", "completed_at").
+			Updates(saga).Error
+	})
+}
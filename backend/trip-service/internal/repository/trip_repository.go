@@ -2,8 +2,10 @@ package repository
 
 import (
 	"context"
+	"strings"
 	"time"
 
+	"bus-booking/trip-service/internal/constants"
 	"bus-booking/trip-service/internal/model"
 
 	"github.com/google/uuid"
@@ -17,12 +19,14 @@ type TripRepository interface {
 	GetTripsByIDs(ctx context.Context, ids []uuid.UUID) ([]model.Trip, error)
 	GetTripsByRouteAndDate(ctx context.Context, routeID uuid.UUID, date time.Time) ([]model.Trip, error)
 	GetTripsByBusAndDateRange(ctx context.Context, busID uuid.UUID, startDate, endDate time.Time) ([]model.Trip, error)
+	// ListDepartingTrips returns scheduled trips departing in [from, to] on active routes,
+	// with their active stops in order, earliest departure first
+	ListDepartingTrips(ctx context.Context, from, to time.Time) ([]model.Trip, error)
 
 	CreateTrip(ctx context.Context, trip *model.Trip) error
 	UpdateTrip(ctx context.Context, trip *model.Trip) error
 	DeleteTrip(ctx context.Context, id uuid.UUID) error
 
-	GetCompletedTripsForReschedule(ctx context.Context) ([]model.Trip, error)
 	UpdateTripStatuses(ctx context.Context) error
 }
 
@@ -41,6 +45,9 @@ func (r *TripRepositoryImpl) SearchTrips(ctx context.Context, req *model.TripSea
 	// Build base query with Preload
 	query := r.db.WithContext(ctx).Model(&model.Trip{}).
 		Preload("Route", "is_active = ?", true).
+		Preload("Route.RouteStops", func(db *gorm.DB) *gorm.DB {
+			return db.Where("is_active = ?", true).Order("stop_order ASC")
+		}).
 		Preload("Bus", "is_active = ?", true).
 		Joins("JOIN routes ON routes.id = trips.route_id").
 		Joins("JOIN buses ON buses.id = trips.bus_id")
@@ -48,12 +55,33 @@ func (r *TripRepositoryImpl) SearchTrips(ctx context.Context, req *model.TripSea
 	// Base filter - only active trips by default
 	query = query.Where("trips.is_active = ?", true)
 
-	// Optional filters
-	if req.Origin != nil && *req.Origin != "" {
-		query = query.Where("routes.origin ILIKE ?", "%"+*req.Origin+"%")
-	}
-	if req.Destination != nil && *req.Destination != "" {
-		query = query.Where("routes.destination ILIKE ?", "%"+*req.Destination+"%")
+	// Optional filters - match the whole route or any pickup/dropoff stop pair along it
+	origin, destination := searchPlace(req.Origin), searchPlace(req.Destination)
+	switch {
+	case origin != "" && destination != "":
+		query = query.Where(`((routes.origin ILIKE ? AND routes.destination ILIKE ?) OR EXISTS (
+			SELECT 1 FROM route_stops p
+			JOIN route_stops d ON d.route_id = p.route_id AND d.stop_order > p.stop_order
+			WHERE p.route_id = trips.route_id
+			AND p.is_active = true AND p.deleted_at IS NULL AND p.stop_type IN ('pickup', 'both')
+			AND d.is_active = true AND d.deleted_at IS NULL AND d.stop_type IN ('dropoff', 'both')
+			AND (p.location ILIKE ? OR p.address ILIKE ?)
+			AND (d.location ILIKE ? OR d.address ILIKE ?)
+		))`, like(origin), like(destination), like(origin), like(origin), like(destination), like(destination))
+	case origin != "":
+		query = query.Where(`(routes.origin ILIKE ? OR EXISTS (
+			SELECT 1 FROM route_stops p
+			WHERE p.route_id = trips.route_id
+			AND p.is_active = true AND p.deleted_at IS NULL AND p.stop_type IN ('pickup', 'both')
+			AND (p.location ILIKE ? OR p.address ILIKE ?)
+		))`, like(origin), like(origin), like(origin))
+	case destination != "":
+		query = query.Where(`(routes.destination ILIKE ? OR EXISTS (
+			SELECT 1 FROM route_stops d
+			WHERE d.route_id = trips.route_id
+			AND d.is_active = true AND d.deleted_at IS NULL AND d.stop_type IN ('dropoff', 'both')
+			AND (d.location ILIKE ? OR d.address ILIKE ?)
+		))`, like(destination), like(destination), like(destination))
 	}
 
 	// Status filter (for admin, default to scheduled for public)
@@ -159,13 +187,8 @@ func (r *TripRepositoryImpl) SearchTrips(ctx context.Context, req *model.TripSea
 
 		// Map Route details
 		if trip.Route != nil {
-			detail.Route = &model.RouteDetail{
-				ID:              trip.Route.ID,
-				Origin:          trip.Route.Origin,
-				Destination:     trip.Route.Destination,
-				DistanceKm:      trip.Route.DistanceKm,
-				DurationMinutes: trip.Route.EstimatedMinutes,
-			}
+			detail.Route = model.ToRouteDetail(trip.Route)
+			detail.Segment = searchSegment(&trip, origin, destination)
 		}
 
 		// Map Bus details
@@ -197,6 +220,37 @@ func (r *TripRepositoryImpl) SearchTrips(ctx context.Context, req *model.TripSea
 	return results, total, nil
 }
 
+func searchPlace(place *string) string {
+	if place == nil {
+		return ""
+	}
+	return strings.TrimSpace(*place)
+}
+
+func like(place string) string {
+	return "%" + place + "%"
+}
+
+// searchSegment returns the matched stop segment, or nil when the search covers the whole route
+func searchSegment(trip *model.Trip, origin, destination string) *model.TripSegmentDetail {
+	if origin == "" && destination == "" {
+		return nil
+	}
+
+	route := trip.Route
+	originMatches := origin == "" || strings.Contains(strings.ToLower(route.Origin), strings.ToLower(origin))
+	destinationMatches := destination == "" || strings.Contains(strings.ToLower(route.Destination), strings.ToLower(destination))
+	if originMatches && destinationMatches {
+		return nil
+	}
+
+	pickup, dropoff := route.FindSegment(origin, destination)
+	if pickup == nil || dropoff == nil {
+		return nil
+	}
+	return model.NewTripSegmentDetail(trip, pickup, dropoff)
+}
+
 func (r *TripRepositoryImpl) GetTripByID(ctx context.Context, req *model.GetTripByIDRequest, id uuid.UUID) (*model.Trip, error) {
 	var trip model.Trip
 	query := r.db.WithContext(ctx)
@@ -285,6 +339,21 @@ func (r *TripRepositoryImpl) GetTripsByBusAndDateRange(ctx context.Context, busI
 	return trips, err
 }
 
+func (r *TripRepositoryImpl) ListDepartingTrips(ctx context.Context, from, to time.Time) ([]model.Trip, error) {
+	var trips []model.Trip
+	err := r.db.WithContext(ctx).
+		Preload("Route").
+		Preload("Route.RouteStops", func(db *gorm.DB) *gorm.DB {
+			return db.Where("is_active = ?", true).Order("stop_order ASC")
+		}).
+		Joins("JOIN routes ON routes.id = trips.route_id AND routes.is_active = ? AND routes.deleted_at IS NULL", true).
+		Where("trips.departure_time >= ? AND trips.departure_time <= ?", from, to).
+		Where("trips.status = ? AND trips.is_active = ?", constants.TripStatusScheduled, true).
+		Order("trips.departure_time ASC").
+		Find(&trips).Error
+	return trips, err
+}
+
 func (r *TripRepositoryImpl) CreateTrip(ctx context.Context, trip *model.Trip) error {
 	return r.db.WithContext(ctx).Create(trip).Error
 }
@@ -297,20 +366,6 @@ func (r *TripRepositoryImpl) DeleteTrip(ctx context.Context, id uuid.UUID) error
 	return r.db.WithContext(ctx).Delete(&model.Trip{}, "id = ?", id).Error
 }
 
-func (r *TripRepositoryImpl) GetCompletedTripsForReschedule(ctx context.Context) ([]model.Trip, error) {
-	var trips []model.Trip
-	yesterday := time.Now().Add(-24 * time.Hour)
-
-	err := r.db.WithContext(ctx).
-		Where("status = ?", "completed").
-		Where("arrival_time >= ? AND arrival_time <= ?", yesterday, time.Now()).
-		Where("is_active = ?", true).
-		Preload("Route").
-		Find(&trips).Error
-
-	return trips, err
-}
-
 // UpdateTripStatuses updates trip statuses based on current time
 func (r *TripRepositoryImpl) UpdateTripStatuses(ctx context.Context) error {
 	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"bus-booking/trip-service/internal/model"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type TripScheduleRepository interface {
+	Create(ctx context.Context, schedule *model.TripSchedule) error
+	Update(ctx context.Context, schedule *model.TripSchedule) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	GetByID(ctx context.Context, id uuid.UUID) (*model.TripSchedule, error)
+	List(ctx context.Context, routeID *uuid.UUID, page, pageSize int) ([]model.TripSchedule, int64, error)
+	ListActive(ctx context.Context, from, to time.Time) ([]model.TripSchedule, error)
+}
+
+type TripScheduleRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewTripScheduleRepository(db *gorm.DB) TripScheduleRepository {
+	return &TripScheduleRepositoryImpl{db: db}
+}
+
+func (r *TripScheduleRepositoryImpl) Create(ctx context.Context, schedule *model.TripSchedule) error {
+	return r.db.WithContext(ctx).Create(schedule).Error
+}
+
+func (r *TripScheduleRepositoryImpl) Update(ctx context.Context, schedule *model.TripSchedule) error {
+	return r.db.WithContext(ctx).Omit("Route", "Bus").Save(schedule).Error
+}
+
+func (r *TripScheduleRepositoryImpl) Delete(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Delete(&model.TripSchedule{}, "id = ?", id).Error
+}
+
+func (r *TripScheduleRepositoryImpl) GetByID(ctx context.Context, id uuid.UUID) (*model.TripSchedule, error) {
+	var schedule model.TripSchedule
+	if err := r.db.WithContext(ctx).
+		Preload("Route").
+		Preload("Bus").
+		First(&schedule, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &schedule, nil
+}
+
+func (r *TripScheduleRepositoryImpl) List(ctx context.Context, routeID *uuid.UUID, page, pageSize int) ([]model.TripSchedule, int64, error) {
+	var schedules []model.TripSchedule
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&model.TripSchedule{})
+	if routeID != nil {
+		query = query.Where("route_id = ?", *routeID)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * pageSize
+	err := query.
+		Preload("Route").
+		Preload("Bus").
+		Offset(offset).
+		Limit(pageSize).
+		Order("departure_time ASC, created_at DESC").
+		Find(&schedules).Error
+
+	return schedules, total, err
+}
+
+// ListActive returns active schedules whose validity window overlaps [from, to]
+func (r *TripScheduleRepositoryImpl) ListActive(ctx context.Context, from, to time.Time) ([]model.TripSchedule, error) {
+	var schedules []model.TripSchedule
+	err := r.db.WithContext(ctx).
+		Where("is_active = ?", true).
+		Where("valid_from <= ?", to).
+		Where("valid_until IS NULL OR valid_until >= ?", from).
+		Preload("Route").
+		Preload("Bus").
+		Order("departure_time ASC").
+		Find(&schedules).Error
+	return schedules, err
+}
@@ -15,12 +15,17 @@ import (
 )
 
 type Handlers struct {
-	TripHandler      handler.TripHandler
-	RouteHandler     handler.RouteHandler
-	RouteStopHandler handler.RouteStopHandler
-	BusHandler       handler.BusHandler
-	SeatHandler      handler.SeatHandler
-	ConstantsHandler handler.ConstantsHandler
+	TripHandler         handler.TripHandler
+	CancellationHandler handler.TripCancellationHandler
+	RouteHandler        handler.RouteHandler
+	RouteStopHandler    handler.RouteStopHandler
+	BusHandler          handler.BusHandler
+	SeatHandler         handler.SeatHandler
+	ConstantsHandler    handler.ConstantsHandler
+	TripScheduleHandler handler.TripScheduleHandler
+	JourneyHandler      handler.JourneyHandler
+	PricingHandler      handler.PricingHandler
+	FareCalendarHandler handler.FareCalendarHandler
 }
 
 func SetupRoutes(router *gin.Engine, cfg *config.Config, h *Handlers) {
@@ -37,7 +42,10 @@ func SetupRoutes(router *gin.Engine, cfg *config.Config, h *Handlers) {
 		trips := v1.Group("/trips")
 		{
 			trips.GET("/search", ginext.WrapHandler(h.TripHandler.SearchTrips))
+			trips.GET("/journeys", ginext.WrapHandler(h.JourneyHandler.SearchJourneys))
+			trips.GET("/fare-calendar", ginext.WrapHandler(h.FareCalendarHandler.GetFareCalendar))
 			trips.GET("/:id", ginext.WrapHandler(h.TripHandler.GetByID))
+			trips.GET("/:id/fare", ginext.WrapHandler(h.PricingHandler.GetTripFare))
 		}
 
 		buses := v1.Group("/buses")
@@ -55,7 +63,10 @@ func SetupRoutes(router *gin.Engine, cfg *config.Config, h *Handlers) {
 			trips.GET("", ginext.WrapHandler(h.TripHandler.ListTrips))
 			trips.POST("", ginext.WrapHandler(h.TripHandler.CreateTrip))
 			trips.PUT("/:id", ginext.WrapHandler(h.TripHandler.UpdateTrip))
-			trips.PUT("/:id/cancel", ginext.WrapHandler(h.TripHandler.CancelTrip))
+			trips.PUT("/:id/cancel", ginext.WrapHandler(h.CancellationHandler.CancelTrip))
+			trips.GET("/:id/cancellation", ginext.WrapHandler(h.CancellationHandler.GetCancellation))
+			trips.POST("/:id/cancellation/retry", ginext.WrapHandler(h.CancellationHandler.RetryCancellation))
+			trips.POST("/:id/cancellation/abort", ginext.WrapHandler(h.CancellationHandler.AbortCancellation))
 			trips.DELETE("/:id", ginext.WrapHandler(h.TripHandler.DeleteTrip))
 		}
 
@@ -91,6 +102,32 @@ func SetupRoutes(router *gin.Engine, cfg *config.Config, h *Handlers) {
 			routeStops.DELETE("/:id", ginext.WrapHandler(h.RouteStopHandler.DeleteRouteStop))
 		}
 
+		schedules := adminV1.Group("/trip-schedules")
+		{
+			schedules.GET("", ginext.WrapHandler(h.TripScheduleHandler.GetList))
+			schedules.GET("/:id", ginext.WrapHandler(h.TripScheduleHandler.GetByID))
+			schedules.POST("", ginext.WrapHandler(h.TripScheduleHandler.Create))
+			schedules.POST("/generate", ginext.WrapHandler(h.TripScheduleHandler.GenerateTrips))
+			schedules.PUT("/:id", ginext.WrapHandler(h.TripScheduleHandler.Update))
+			schedules.DELETE("/:id", ginext.WrapHandler(h.TripScheduleHandler.Delete))
+		}
+
+		pricingRules := adminV1.Group("/pricing-rules")
+		{
+			pricingRules.GET("", ginext.WrapHandler(h.PricingHandler.ListRules))
+			pricingRules.GET("/:id", ginext.WrapHandler(h.PricingHandler.GetRule))
+			pricingRules.POST("", ginext.WrapHandler(h.PricingHandler.CreateRule))
+			pricingRules.PUT("/:id", ginext.WrapHandler(h.PricingHandler.UpdateRule))
+			pricingRules.DELETE("/:id", ginext.WrapHandler(h.PricingHandler.DeleteRule))
+		}
+
+		holidays := adminV1.Group("/holidays")
+		{
+			holidays.GET("", ginext.WrapHandler(h.PricingHandler.ListHolidays))
+			holidays.POST("", ginext.WrapHandler(h.PricingHandler.CreateHoliday))
+			holidays.DELETE("/:id", ginext.WrapHandler(h.PricingHandler.DeleteHoliday))
+		}
+
 	}
 
 	internalV1 := router.Group("/api/v1")
@@ -1,10 +1,12 @@
 package server
 
 import (
+	"bus-booking/shared/outbox"
 	"bus-booking/shared/storage"
 	"bus-booking/trip-service/internal/client"
 	"bus-booking/trip-service/internal/cronjob"
 	"bus-booking/trip-service/internal/handler"
+	"bus-booking/trip-service/internal/model"
 	"bus-booking/trip-service/internal/repository"
 	"bus-booking/trip-service/internal/router"
 	"bus-booking/trip-service/internal/service"
@@ -14,7 +16,7 @@ import (
 	"github.com/rs/zerolog/log"
 )
 
-func (s *Server) buildHandler() (http.Handler, *cronjob.TripRescheduleCronJob, *cronjob.TripStatusCronJob) {
+func (s *Server) buildHandler() (http.Handler, *cronjob.TripScheduleCronJob, *cronjob.TripStatusCronJob, *cronjob.TripCancellationCronJob, *cronjob.FareCalendarCronJob, *outbox.Relay) {
 	bookingClient := client.NewBookingClient(s.cfg.ServiceName, s.cfg.External.BookingServiceURL)
 	paymentClient := client.NewPaymentClient(s.cfg.ServiceName, s.cfg.External.PaymentServiceURL)
 
@@ -24,6 +26,9 @@ func (s *Server) buildHandler() (http.Handler, *cronjob.TripRescheduleCronJob, *
 	routeStopRepo := repository.NewRouteStopRepository(s.db.DB)
 	busRepo := repository.NewBusRepository(s.db.DB)
 	seatRepo := repository.NewSeatRepository(s.db.DB)
+	scheduleRepo := repository.NewTripScheduleRepository(s.db.DB)
+	cancellationRepo := repository.NewTripCancellationRepository(s.db.DB)
+	pricingRepo := repository.NewPricingRuleRepository(s.db.DB)
 
 	// Initialize storage service
 	storageService, err := storage.NewS3StorageService(storage.S3Config{
@@ -40,24 +45,41 @@ func (s *Server) buildHandler() (http.Handler, *cronjob.TripRescheduleCronJob, *
 	}
 
 	// Initialize services
-	tripService := service.NewTripService(tripRepo, routeRepo, routeStopRepo, busRepo, seatRepo, bookingClient, paymentClient)
+	cacheService := service.NewCacheService(s.redis)
+	pricingService := service.NewPricingService(pricingRepo, tripRepo, routeRepo, seatRepo, bookingClient, cacheService)
+	tripService := service.NewTripService(tripRepo, routeRepo, routeStopRepo, busRepo, seatRepo, bookingClient, pricingService, cacheService)
+	cancellationService := service.NewTripCancellationService(tripRepo, cancellationRepo, bookingClient, paymentClient, cacheService)
 	routeService := service.NewRouteService(routeRepo)
 	busService := service.NewBusService(busRepo, seatRepo, storageService)
 	routeStopService := service.NewRouteStopService(routeStopRepo, routeRepo)
 	seatService := service.NewSeatService(seatRepo)
 	constantsService := service.NewConstantsService()
+	scheduleService := service.NewTripScheduleService(scheduleRepo, tripRepo, routeRepo, busRepo, cacheService)
+	journeyService := service.NewJourneyService(tripRepo, pricingService)
+	fareCalendarService := service.NewFareCalendarService(tripRepo, routeRepo, pricingService, cacheService)
 
-	// Initialize trip reschedule cronjob
-	cronJob := cronjob.NewTripRescheduleCronJob(tripService)
+	// Initialize cronjobs
+	cronJob := cronjob.NewTripScheduleCronJob(scheduleService, s.cfg.Schedule.GenerateDaysAhead)
 	statusCron := cronjob.NewTripStatusCronJob(tripService)
+	cancellationCron := cronjob.NewTripCancellationCronJob(cancellationService)
+	fareCalendarCron := cronjob.NewFareCalendarCronJob(fareCalendarService)
+
+	// Initialize outbox relay
+	relay := outbox.NewRelay(s.db.DB, outbox.DefaultRelayConfig())
+	relay.Register(model.EventTypeTripCancelled, cancellationService.HandleTripCancelled)
 
 	// Initialize handlers
 	tripHandler := handler.NewTripHandler(tripService)
+	cancellationHandler := handler.NewTripCancellationHandler(cancellationService)
 	routeHandler := handler.NewRouteHandler(routeService)
 	busHandler := handler.NewBusHandler(busService)
 	routeStopHandler := handler.NewRouteStopHandler(routeStopService)
 	seatHandler := handler.NewSeatHandler(seatService)
 	constantsHandler := handler.NewConstantsHandler(constantsService)
+	scheduleHandler := handler.NewTripScheduleHandler(scheduleService)
+	journeyHandler := handler.NewJourneyHandler(journeyService)
+	pricingHandler := handler.NewPricingHandler(pricingService)
+	fareCalendarHandler := handler.NewFareCalendarHandler(fareCalendarService)
 
 	if s.cfg.Server.IsProduction {
 		gin.SetMode(gin.ReleaseMode)
@@ -67,12 +89,17 @@ func (s *Server) buildHandler() (http.Handler, *cronjob.TripRescheduleCronJob, *
 
 	engine := gin.New()
 	router.SetupRoutes(engine, s.cfg, &router.Handlers{
-		TripHandler:      tripHandler,
-		RouteHandler:     routeHandler,
-		BusHandler:       busHandler,
-		RouteStopHandler: routeStopHandler,
-		SeatHandler:      seatHandler,
-		ConstantsHandler: constantsHandler,
+		TripHandler:         tripHandler,
+		CancellationHandler: cancellationHandler,
+		RouteHandler:        routeHandler,
+		BusHandler:          busHandler,
+		RouteStopHandler:    routeStopHandler,
+		SeatHandler:         seatHandler,
+		ConstantsHandler:    constantsHandler,
+		TripScheduleHandler: scheduleHandler,
+		JourneyHandler:      journeyHandler,
+		PricingHandler:      pricingHandler,
+		FareCalendarHandler: fareCalendarHandler,
 	})
-	return engine, cronJob, statusCron
+	return engine, cronJob, statusCron, cancellationCron, fareCalendarCron, relay
 }
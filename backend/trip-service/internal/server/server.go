@@ -19,8 +19,11 @@ type Server struct {
 	cfg        *config.Config
 	db         *db.DatabaseManager
 	redis      db.RedisManager
-	cronjob    *cronjob.TripRescheduleCronJob
+	cronjob    *cronjob.TripScheduleCronJob
 	statusCron *cronjob.TripStatusCronJob
+
+	cancellationCron *cronjob.TripCancellationCronJob
+	fareCalendarCron *cronjob.FareCalendarCronJob
 }
 
 func NewServer(
@@ -32,9 +35,11 @@ func NewServer(
 }
 
 func (s *Server) Run() {
-	handler, cronJob, statusCron := s.buildHandler()
+	handler, cronJob, statusCron, cancellationCron, fareCalendarCron, relay := s.buildHandler()
 	s.cronjob = cronJob
 	s.statusCron = statusCron
+	s.cancellationCron = cancellationCron
+	s.fareCalendarCron = fareCalendarCron
 
 	server := &http.Server{
 		Addr:           s.cfg.GetServerAddr(),
@@ -59,6 +64,18 @@ func (s *Server) Run() {
 		s.statusCron.Start(ctx)
 	}()
 
+	go func() {
+		log.Info().Msg("Starting trip cancellation cronjob")
+		s.cancellationCron.Start(ctx)
+	}()
+
+	go func() {
+		log.Info().Msg("Starting fare calendar cronjob")
+		s.fareCalendarCron.Start(ctx)
+	}()
+
+	go relay.Start(ctx)
+
 	// Start HTTP server
 	go func() {
 		log.Info().
@@ -82,6 +99,8 @@ func (s *Server) Run() {
 	log.Info().Msg("Stopping cronjob...")
 	s.cronjob.Stop()
 	s.statusCron.Stop()
+	s.cancellationCron.Stop()
+	s.fareCalendarCron.Stop()
 	cancel()
 
 	// Then stop HTTP server
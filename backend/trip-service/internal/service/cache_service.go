@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"bus-booking/shared/db"
+	"bus-booking/trip-service/internal/constants"
 	"bus-booking/trip-service/internal/model"
 
 	"github.com/rs/zerolog/log"
@@ -17,6 +18,13 @@ type CacheService interface {
 	InvalidateTripCache(ctx context.Context, tripID string) error
 	InvalidateRouteCache(ctx context.Context, routeID string) error
 	InvalidateSearchCache(ctx context.Context, pattern string) error
+
+	GetFareCalendar(ctx context.Context, key string) (*model.FareCalendar, error)
+	SetFareCalendar(ctx context.Context, key string, calendar *model.FareCalendar, ttl time.Duration) error
+	// InvalidateFareCalendars drops the cached calendars of the given months (YYYY-MM)
+	InvalidateFareCalendars(ctx context.Context, months []string) error
+	// InvalidateAllFareCalendars drops every cached calendar, e.g. when pricing rules change
+	InvalidateAllFareCalendars(ctx context.Context) error
 }
 
 type CacheServiceImpl struct {
@@ -33,6 +41,12 @@ const (
 	routeCachePrefix  = "route:detail:"
 	searchCacheTTL    = 5 * time.Minute
 	detailCacheTTL    = 1 * time.Hour
+
+	// Calendars are indexed by month so trip changes only drop the months they touch
+	fareCalendarCachePrefix = "trip:fare-calendar:"
+	fareCalendarIndexPrefix = "trip:fare-calendar:index:"
+	fareCalendarMonthsKey   = "trip:fare-calendar:months"
+	fareCalendarCacheTTL    = 2 * constants.FareCalendarRefreshInterval
 )
 
 type cachedSearchResult struct {
@@ -106,3 +120,87 @@ func (s *CacheServiceImpl) InvalidateSearchCache(ctx context.Context, pattern st
 	log.Warn().Str("pattern", pattern).Msg("Search cache invalidation by pattern not fully implemented")
 	return nil
 }
+
+func (s *CacheServiceImpl) GetFareCalendar(ctx context.Context, key string) (*model.FareCalendar, error) {
+	data, err := s.redis.Get(ctx, fareCalendarCachePrefix+key)
+	if err != nil {
+		return nil, err
+	}
+
+	var calendar model.FareCalendar
+	if err := json.Unmarshal([]byte(data), &calendar); err != nil {
+		log.Error().Err(err).Msg("Failed to unmarshal cached fare calendar")
+		return nil, err
+	}
+
+	log.Debug().Str("key", key).Msg("Cache hit for fare calendar")
+	return &calendar, nil
+}
+
+func (s *CacheServiceImpl) SetFareCalendar(ctx context.Context, key string, calendar *model.FareCalendar, ttl time.Duration) error {
+	data, err := json.Marshal(calendar)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to marshal fare calendar")
+		return err
+	}
+
+	if err := s.redis.Set(ctx, fareCalendarCachePrefix+key, string(data), ttl); err != nil {
+		log.Error().Err(err).Msg("Failed to cache fare calendar")
+		return err
+	}
+
+	// Calendars share one TTL, so the index expires with the last one cached
+	indexKey := fareCalendarIndexPrefix + calendar.Month
+	if err := s.redis.SAdd(ctx, indexKey, key); err != nil {
+		log.Error().Err(err).Str("month", calendar.Month).Msg("Failed to index fare calendar")
+		return err
+	}
+	if err := s.redis.Expire(ctx, indexKey, ttl); err != nil {
+		log.Error().Err(err).Str("month", calendar.Month).Msg("Failed to set fare calendar index expiry")
+		return err
+	}
+	if err := s.redis.SAdd(ctx, fareCalendarMonthsKey, calendar.Month); err != nil {
+		log.Error().Err(err).Str("month", calendar.Month).Msg("Failed to index fare calendar month")
+		return err
+	}
+
+	log.Debug().Str("key", key).Dur("ttl", ttl).Msg("Cached fare calendar")
+	return nil
+}
+
+func (s *CacheServiceImpl) InvalidateFareCalendars(ctx context.Context, months []string) error {
+	for _, month := range months {
+		indexKey := fareCalendarIndexPrefix + month
+		keys, err := s.redis.SMembers(ctx, indexKey)
+		if err != nil {
+			log.Error().Err(err).Str("month", month).Msg("Failed to list cached fare calendars")
+			return err
+		}
+
+		cacheKeys := make([]string, 0, len(keys)+1)
+		for _, key := range keys {
+			cacheKeys = append(cacheKeys, fareCalendarCachePrefix+key)
+		}
+		cacheKeys = append(cacheKeys, indexKey)
+		if err := s.redis.Del(ctx, cacheKeys...); err != nil {
+			log.Error().Err(err).Str("month", month).Msg("Failed to invalidate fare calendars")
+			return err
+		}
+		if err := s.redis.SRem(ctx, fareCalendarMonthsKey, month); err != nil {
+			log.Error().Err(err).Str("month", month).Msg("Failed to unindex fare calendar month")
+			return err
+		}
+
+		log.Debug().Str("month", month).Int("calendars", len(keys)).Msg("Invalidated fare calendars")
+	}
+	return nil
+}
+
+func (s *CacheServiceImpl) InvalidateAllFareCalendars(ctx context.Context) error {
+	months, err := s.redis.SMembers(ctx, fareCalendarMonthsKey)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to list cached fare calendar months")
+		return err
+	}
+	return s.InvalidateFareCalendars(ctx, months)
+}
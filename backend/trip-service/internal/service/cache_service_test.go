@@ -159,3 +159,73 @@ func TestInvalidateSearchCache_Success(t *testing.T) {
 
 	assert.NoError(t, err)
 }
+
+func TestSetFareCalendar_IndexesByMonth(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRedis := mocks.NewMockRedisManager(ctrl)
+	service := NewCacheService(mockRedis)
+
+	ctx := context.Background()
+	key := "2026-04:hà nội:đà nẵng"
+	calendar := &model.FareCalendar{Origin: "Hà Nội", Destination: "Đà Nẵng", Month: "2026-04"}
+	ttl := 30 * time.Minute
+
+	gomock.InOrder(
+		mockRedis.EXPECT().Set(ctx, "trip:fare-calendar:"+key, gomock.Any(), ttl).Return(nil),
+		mockRedis.EXPECT().SAdd(ctx, "trip:fare-calendar:index:2026-04", key).Return(nil),
+		mockRedis.EXPECT().Expire(ctx, "trip:fare-calendar:index:2026-04", ttl).Return(nil),
+		mockRedis.EXPECT().SAdd(ctx, "trip:fare-calendar:months", "2026-04").Return(nil),
+	)
+
+	err := service.SetFareCalendar(ctx, key, calendar, ttl)
+
+	assert.NoError(t, err)
+}
+
+func TestInvalidateFareCalendars_DeletesIndexedCalendars(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRedis := mocks.NewMockRedisManager(ctrl)
+	service := NewCacheService(mockRedis)
+
+	ctx := context.Background()
+
+	mockRedis.EXPECT().
+		SMembers(ctx, "trip:fare-calendar:index:2026-04").
+		Return([]string{"2026-04:hà nội:đà nẵng", "2026-04:huế:đà lạt"}, nil).
+		Times(1)
+	mockRedis.EXPECT().
+		Del(ctx, "trip:fare-calendar:2026-04:hà nội:đà nẵng", "trip:fare-calendar:2026-04:huế:đà lạt", "trip:fare-calendar:index:2026-04").
+		Return(nil).
+		Times(1)
+	mockRedis.EXPECT().
+		SRem(ctx, "trip:fare-calendar:months", "2026-04").
+		Return(nil).
+		Times(1)
+
+	err := service.InvalidateFareCalendars(ctx, []string{"2026-04"})
+
+	assert.NoError(t, err)
+}
+
+func TestInvalidateAllFareCalendars_ListFails(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRedis := mocks.NewMockRedisManager(ctrl)
+	service := NewCacheService(mockRedis)
+
+	ctx := context.Background()
+
+	mockRedis.EXPECT().
+		SMembers(ctx, "trip:fare-calendar:months").
+		Return(nil, assert.AnError).
+		Times(1)
+
+	err := service.InvalidateAllFareCalendars(ctx)
+
+	assert.Error(t, err)
+}
@@ -0,0 +1,233 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"bus-booking/shared/ginext"
+	"bus-booking/trip-service/internal/constants"
+	"bus-booking/trip-service/internal/model"
+	"bus-booking/trip-service/internal/repository"
+
+	"github.com/rs/zerolog/log"
+)
+
+// FareCalendarService shows travellers with flexible dates the cheapest fare of each day of a
+// month. Calendars are precomputed for every active route and cached until trips or prices change.
+type FareCalendarService interface {
+	GetFareCalendar(ctx context.Context, req *model.FareCalendarRequest) (*model.FareCalendar, error)
+
+	// PrecomputeCalendars caches the calendars between the ends of every active route for the
+	// next constants.FareCalendarPrecomputeMonths months
+	PrecomputeCalendars(ctx context.Context) error
+}
+
+type FareCalendarServiceImpl struct {
+	tripRepo  repository.TripRepository
+	routeRepo repository.RouteRepository
+	pricing   PricingService
+	cache     CacheService
+	location  *time.Location
+	now       func() time.Time
+}
+
+func NewFareCalendarService(
+	tripRepo repository.TripRepository,
+	routeRepo repository.RouteRepository,
+	pricing PricingService,
+	cache CacheService,
+) FareCalendarService {
+	return &FareCalendarServiceImpl{
+		tripRepo:  tripRepo,
+		routeRepo: routeRepo,
+		pricing:   pricing,
+		cache:     cache,
+		location:  scheduleLocation(),
+		now:       time.Now,
+	}
+}
+
+func (s *FareCalendarServiceImpl) GetFareCalendar(ctx context.Context, req *model.FareCalendarRequest) (*model.FareCalendar, error) {
+	origin, destination := strings.TrimSpace(req.Origin), strings.TrimSpace(req.Destination)
+	if origin == "" || destination == "" {
+		return nil, ginext.NewBadRequestError("origin and destination are required")
+	}
+
+	monthStart, err := time.ParseInLocation(constants.FareCalendarMonthFormat, req.Month, s.location)
+	if err != nil {
+		return nil, ginext.NewBadRequestError("month must be in YYYY-MM format")
+	}
+	currentMonth := s.currentMonth()
+	if monthStart.Before(currentMonth) || monthStart.After(currentMonth.AddDate(0, constants.FareCalendarMonthsAhead, 0)) {
+		return nil, ginext.NewBadRequestError(fmt.Sprintf("month must be between the current month and %d months ahead", constants.FareCalendarMonthsAhead))
+	}
+
+	key := fareCalendarKey(origin, destination, monthStart)
+	if calendar, err := s.cache.GetFareCalendar(ctx, key); err == nil {
+		return calendar, nil
+	}
+
+	calendar, err := s.buildCalendar(ctx, origin, destination, monthStart)
+	if err != nil {
+		return nil, err
+	}
+
+	// The calendar is still served when it cannot be cached
+	_ = s.cache.SetFareCalendar(ctx, key, calendar, fareCalendarCacheTTL)
+	return calendar, nil
+}
+
+func (s *FareCalendarServiceImpl) PrecomputeCalendars(ctx context.Context) error {
+	isActive := true
+	req := &model.ListRoutesRequest{
+		PaginationRequest: model.PaginationRequest{Page: 1, PageSize: constants.FareCalendarRoutePageSize},
+		IsActive:          &isActive,
+	}
+
+	// Routes in both directions usually share their ends, so pairs are only computed once
+	seen := make(map[string]bool)
+	currentMonth := s.currentMonth()
+	computed, failed := 0, 0
+	for {
+		routes, _, err := s.routeRepo.ListRoutes(ctx, req)
+		if err != nil {
+			log.Error().Err(err).Msg("Failed to list routes for fare calendars")
+			return ginext.NewInternalServerError("failed to list routes")
+		}
+
+		for _, route := range routes {
+			pair := strings.ToLower(route.Origin) + "|" + strings.ToLower(route.Destination)
+			if seen[pair] {
+				continue
+			}
+			seen[pair] = true
+
+			for i := 0; i < constants.FareCalendarPrecomputeMonths; i++ {
+				monthStart := currentMonth.AddDate(0, i, 0)
+				calendar, err := s.buildCalendar(ctx, route.Origin, route.Destination, monthStart)
+				if err != nil {
+					log.Warn().Err(err).
+						Str("origin", route.Origin).
+						Str("destination", route.Destination).
+						Str("month", monthStart.Format(constants.FareCalendarMonthFormat)).
+						Msg("Failed to precompute fare calendar")
+					failed++
+					continue
+				}
+				if err := s.cache.SetFareCalendar(ctx, fareCalendarKey(route.Origin, route.Destination, monthStart), calendar, fareCalendarCacheTTL); err != nil {
+					failed++
+					continue
+				}
+				computed++
+			}
+		}
+
+		if len(routes) < req.PageSize {
+			break
+		}
+		req.Page++
+	}
+
+	log.Info().Int("computed", computed).Int("failed", failed).Msg("Precomputed fare calendars")
+	return nil
+}
+
+// buildCalendar prices the upcoming departures of the month, whole trips and segments alike,
+// and keeps the cheapest seat still for sale on each day
+func (s *FareCalendarServiceImpl) buildCalendar(ctx context.Context, origin, destination string, monthStart time.Time) (*model.FareCalendar, error) {
+	now := s.now()
+	monthEnd := monthStart.AddDate(0, 1, 0)
+	from := monthStart
+	if now.After(from) {
+		from = now
+	}
+
+	calendar := model.NewFareCalendar(origin, destination, monthStart)
+	calendar.GeneratedAt = now.UTC()
+	if !from.Before(monthEnd) {
+		return calendar, nil
+	}
+
+	departureStart, departureEnd := from.Format(time.RFC3339), monthEnd.Format(time.RFC3339)
+	details, _, err := s.tripRepo.SearchTrips(ctx, &model.TripSearchRequest{
+		Origin:             &origin,
+		Destination:        &destination,
+		DepartureTimeStart: &departureStart,
+		DepartureTimeEnd:   &departureEnd,
+		Page:               1,
+		PageSize:           constants.FareCalendarMaxTrips,
+	})
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to search trips for fare calendar")
+		return nil, ginext.NewInternalServerError("failed to search trips")
+	}
+	if len(details) == 0 {
+		return calendar, nil
+	}
+
+	fares, err := s.pricing.QuoteTrips(ctx, detailTrips(details))
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to quote fares for fare calendar")
+		return nil, ginext.NewInternalServerError("failed to quote fares")
+	}
+	applyPriceTiers(details, fares)
+
+	for _, detail := range details {
+		// A segment departs when the bus reaches the pickup stop
+		departsAt := detail.DepartureTime
+		if detail.Segment != nil {
+			departsAt = detail.Segment.DepartureTime
+		}
+		departsAt = departsAt.In(s.location)
+		if departsAt.Before(now) || !departsAt.Before(monthEnd) || departsAt.Before(monthStart) {
+			continue
+		}
+
+		// Trips that could not be priced count as departures without a fare
+		calendar.AddDeparture(departsAt, model.LowestAvailablePrice(detail.PriceTiers))
+	}
+
+	return calendar, nil
+}
+
+func (s *FareCalendarServiceImpl) currentMonth() time.Time {
+	now := s.now().In(s.location)
+	return time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, s.location)
+}
+
+// fareCalendarKey identifies a cached calendar; places match case-insensitively like trip search
+func fareCalendarKey(origin, destination string, monthStart time.Time) string {
+	return monthStart.Format(constants.FareCalendarMonthFormat) + ":" +
+		strings.ToLower(strings.TrimSpace(origin)) + ":" + strings.ToLower(strings.TrimSpace(destination))
+}
+
+// invalidateFareCalendars drops the cached calendars of the months the departures fall in.
+// Failures are only logged: calendars expire on their own within fareCalendarCacheTTL.
+func invalidateFareCalendars(ctx context.Context, cache CacheService, departures ...time.Time) {
+	location := scheduleLocation()
+	seen := make(map[string]bool, len(departures))
+	var months []string
+	for _, departure := range departures {
+		month := departure.In(location).Format(constants.FareCalendarMonthFormat)
+		if !seen[month] {
+			seen[month] = true
+			months = append(months, month)
+		}
+	}
+	if len(months) == 0 {
+		return
+	}
+
+	if err := cache.InvalidateFareCalendars(ctx, months); err != nil {
+		log.Warn().Err(err).Strs("months", months).Msg("Failed to invalidate fare calendars")
+	}
+}
+
+// invalidateAllFareCalendars drops every cached calendar after a change affecting any trip's fare
+func invalidateAllFareCalendars(ctx context.Context, cache CacheService) {
+	if err := cache.InvalidateAllFareCalendars(ctx); err != nil {
+		log.Warn().Err(err).Msg("Failed to invalidate fare calendars")
+	}
+}
@@ -0,0 +1,225 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"bus-booking/shared/ginext"
+	"bus-booking/trip-service/internal/model"
+	repo_mocks "bus-booking/trip-service/internal/repository/mocks"
+	service_mocks "bus-booking/trip-service/internal/service/mocks"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+type fareCalendarTestFixture struct {
+	service   *FareCalendarServiceImpl
+	tripRepo  *repo_mocks.MockTripRepository
+	routeRepo *repo_mocks.MockRouteRepository
+	pricing   *service_mocks.MockPricingService
+	cache     *service_mocks.MockCacheService
+	location  *time.Location
+}
+
+func newFareCalendarTestFixture(ctrl *gomock.Controller) *fareCalendarTestFixture {
+	f := &fareCalendarTestFixture{
+		tripRepo:  repo_mocks.NewMockTripRepository(ctrl),
+		routeRepo: repo_mocks.NewMockRouteRepository(ctrl),
+		pricing:   service_mocks.NewMockPricingService(ctrl),
+		cache:     service_mocks.NewMockCacheService(ctrl),
+	}
+
+	f.service = NewFareCalendarService(f.tripRepo, f.routeRepo, f.pricing, f.cache).(*FareCalendarServiceImpl)
+	f.location = f.service.location
+
+	// Tuesday 10 March 2026, 09:00
+	f.service.now = func() time.Time {
+		return time.Date(2026, 3, 10, 9, 0, 0, 0, f.location)
+	}
+	return f
+}
+
+// departure is an upcoming trip whose tiers are priced at the given fares, sold out when
+// available is zero
+func (f *fareCalendarTestFixture) departure(departsAt time.Time, available int, fares ...float64) (model.TripDetail, *model.TripFare) {
+	detail := model.TripDetail{ID: uuid.New(), RouteID: uuid.New(), DepartureTime: departsAt}
+	fare := &model.TripFare{TripID: detail.ID}
+	for _, price := range fares {
+		fare.PriceTiers = append(fare.PriceTiers, model.PriceTier{FinalPrice: price, PriceMultiplier: 1, AvailableCount: available})
+	}
+	return detail, fare
+}
+
+func TestGetFareCalendar_ServesCachedCalendar(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	f := newFareCalendarTestFixture(ctrl)
+	ctx := context.Background()
+	cached := &model.FareCalendar{Origin: "Hà Nội", Destination: "Đà Nẵng", Month: "2026-04"}
+
+	f.cache.EXPECT().GetFareCalendar(ctx, "2026-04:hà nội:đà nẵng").Return(cached, nil)
+	f.tripRepo.EXPECT().SearchTrips(gomock.Any(), gomock.Any()).Times(0)
+
+	calendar, err := f.service.GetFareCalendar(ctx, &model.FareCalendarRequest{Origin: " Hà Nội", Destination: "Đà Nẵng ", Month: "2026-04"})
+
+	assert.NoError(t, err)
+	assert.Same(t, cached, calendar)
+}
+
+func TestGetFareCalendar_BuildsCheapestFarePerDay(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	f := newFareCalendarTestFixture(ctrl)
+	ctx := context.Background()
+
+	morning, morningFare := f.departure(time.Date(2026, 3, 12, 7, 0, 0, 0, f.location), 5, 450000, 300000)
+	evening, eveningFare := f.departure(time.Date(2026, 3, 12, 21, 0, 0, 0, f.location), 2, 280000)
+	soldOut, soldOutFare := f.departure(time.Date(2026, 3, 15, 8, 0, 0, 0, f.location), 0, 200000)
+	unpriced, _ := f.departure(time.Date(2026, 3, 20, 8, 0, 0, 0, f.location), 0)
+	// Left the station earlier today
+	departed, departedFare := f.departure(time.Date(2026, 3, 10, 6, 0, 0, 0, f.location), 10, 100000)
+
+	f.cache.EXPECT().GetFareCalendar(ctx, "2026-03:hà nội:đà nẵng").Return(nil, assert.AnError)
+	f.tripRepo.EXPECT().SearchTrips(ctx, gomock.Any()).DoAndReturn(
+		func(_ context.Context, req *model.TripSearchRequest) ([]model.TripDetail, int64, error) {
+			// The search starts now rather than at the start of the month
+			assert.Equal(t, time.Date(2026, 3, 10, 9, 0, 0, 0, f.location).Format(time.RFC3339), *req.DepartureTimeStart)
+			assert.Equal(t, time.Date(2026, 4, 1, 0, 0, 0, 0, f.location).Format(time.RFC3339), *req.DepartureTimeEnd)
+			return []model.TripDetail{morning, evening, soldOut, unpriced, departed}, 5, nil
+		})
+	f.pricing.EXPECT().QuoteTrips(ctx, gomock.Len(5)).Return(map[uuid.UUID]*model.TripFare{
+		morning.ID:  morningFare,
+		evening.ID:  eveningFare,
+		soldOut.ID:  soldOutFare,
+		departed.ID: departedFare,
+	}, nil)
+	f.cache.EXPECT().SetFareCalendar(ctx, "2026-03:hà nội:đà nẵng", gomock.Any(), fareCalendarCacheTTL).Return(nil)
+
+	calendar, err := f.service.GetFareCalendar(ctx, &model.FareCalendarRequest{Origin: "Hà Nội", Destination: "Đà Nẵng", Month: "2026-03"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "2026-03", calendar.Month)
+	assert.Len(t, calendar.Days, 31)
+	if assert.NotNil(t, calendar.LowestFare) {
+		assert.Equal(t, 280000.0, *calendar.LowestFare)
+	}
+
+	assert.Equal(t, 0, calendar.Days[9].Departures)
+	assert.Nil(t, calendar.Days[9].LowestFare)
+
+	assert.Equal(t, "2026-03-12", calendar.Days[11].Date)
+	assert.Equal(t, 2, calendar.Days[11].Departures)
+	if assert.NotNil(t, calendar.Days[11].LowestFare) {
+		assert.Equal(t, 280000.0, *calendar.Days[11].LowestFare)
+	}
+
+	// Sold out and unpriced departures still count
+	assert.Equal(t, 1, calendar.Days[14].Departures)
+	assert.Nil(t, calendar.Days[14].LowestFare)
+	assert.Equal(t, 1, calendar.Days[19].Departures)
+	assert.Nil(t, calendar.Days[19].LowestFare)
+}
+
+func TestGetFareCalendar_InvalidRequest(t *testing.T) {
+	tests := []struct {
+		name string
+		req  model.FareCalendarRequest
+	}{
+		{name: "missing destination", req: model.FareCalendarRequest{Origin: "Hà Nội", Destination: "  ", Month: "2026-04"}},
+		{name: "malformed month", req: model.FareCalendarRequest{Origin: "Hà Nội", Destination: "Đà Nẵng", Month: "04/2026"}},
+		{name: "past month", req: model.FareCalendarRequest{Origin: "Hà Nội", Destination: "Đà Nẵng", Month: "2026-02"}},
+		{name: "too far ahead", req: model.FareCalendarRequest{Origin: "Hà Nội", Destination: "Đà Nẵng", Month: "2027-04"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			f := newFareCalendarTestFixture(ctrl)
+
+			calendar, err := f.service.GetFareCalendar(context.Background(), &tt.req)
+
+			assert.Nil(t, calendar)
+			var apiErr *ginext.Error
+			assert.ErrorAs(t, err, &apiErr)
+			assert.Equal(t, http.StatusBadRequest, apiErr.Code)
+		})
+	}
+}
+
+func TestGetFareCalendar_SearchFails(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	f := newFareCalendarTestFixture(ctrl)
+	ctx := context.Background()
+
+	f.cache.EXPECT().GetFareCalendar(ctx, gomock.Any()).Return(nil, assert.AnError)
+	f.tripRepo.EXPECT().SearchTrips(ctx, gomock.Any()).Return(nil, int64(0), assert.AnError)
+	f.cache.EXPECT().SetFareCalendar(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+	calendar, err := f.service.GetFareCalendar(ctx, &model.FareCalendarRequest{Origin: "Hà Nội", Destination: "Đà Nẵng", Month: "2026-05"})
+
+	assert.Nil(t, calendar)
+	var apiErr *ginext.Error
+	assert.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusInternalServerError, apiErr.Code)
+}
+
+func TestPrecomputeCalendars_CachesEachRoutePairOnce(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	f := newFareCalendarTestFixture(ctrl)
+	ctx := context.Background()
+
+	routes := []model.Route{
+		{BaseModel: model.BaseModel{ID: uuid.New()}, Origin: "Hà Nội", Destination: "Đà Nẵng", IsActive: true},
+		// Same ends, e.g. an express service on another road
+		{BaseModel: model.BaseModel{ID: uuid.New()}, Origin: "hà nội", Destination: "đà nẵng", IsActive: true},
+	}
+
+	f.routeRepo.EXPECT().ListRoutes(ctx, gomock.Any()).DoAndReturn(
+		func(_ context.Context, req *model.ListRoutesRequest) ([]model.Route, int64, error) {
+			assert.True(t, *req.IsActive)
+			return routes, int64(len(routes)), nil
+		})
+	f.tripRepo.EXPECT().SearchTrips(ctx, gomock.Any()).Return(nil, int64(0), nil).Times(3)
+	f.pricing.EXPECT().QuoteTrips(gomock.Any(), gomock.Any()).Times(0)
+
+	var keys []string
+	f.cache.EXPECT().SetFareCalendar(ctx, gomock.Any(), gomock.Any(), fareCalendarCacheTTL).DoAndReturn(
+		func(_ context.Context, key string, calendar *model.FareCalendar, _ time.Duration) error {
+			keys = append(keys, key)
+			assert.Nil(t, calendar.LowestFare)
+			return nil
+		}).Times(3)
+
+	err := f.service.PrecomputeCalendars(ctx)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"2026-03:hà nội:đà nẵng", "2026-04:hà nội:đà nẵng", "2026-05:hà nội:đà nẵng"}, keys)
+}
+
+func TestPrecomputeCalendars_ListRoutesFails(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	f := newFareCalendarTestFixture(ctrl)
+	ctx := context.Background()
+
+	f.routeRepo.EXPECT().ListRoutes(ctx, gomock.Any()).Return(nil, int64(0), assert.AnError)
+
+	err := f.service.PrecomputeCalendars(ctx)
+
+	var apiErr *ginext.Error
+	assert.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusInternalServerError, apiErr.Code)
+}
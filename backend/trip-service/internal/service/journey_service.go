@@ -0,0 +1,266 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"bus-booking/shared/ginext"
+	"bus-booking/trip-service/internal/constants"
+	"bus-booking/trip-service/internal/model"
+	"bus-booking/trip-service/internal/repository"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+// JourneyService plans journeys that combine up to three trips, changing buses where one
+// trip drops off in the city the next one picks up from
+type JourneyService interface {
+	SearchJourneys(ctx context.Context, req *model.JourneySearchRequest) ([]model.Journey, error)
+}
+
+type JourneyServiceImpl struct {
+	tripRepo repository.TripRepository
+	pricing  PricingService
+	location *time.Location
+}
+
+func NewJourneyService(tripRepo repository.TripRepository, pricing PricingService) JourneyService {
+	return &JourneyServiceImpl{
+		tripRepo: tripRepo,
+		pricing:  pricing,
+		location: scheduleLocation(),
+	}
+}
+
+// journeyPlanner walks the trips of a search window from the origin, one leg at a time
+type journeyPlanner struct {
+	trips       []model.Trip
+	origin      string
+	destination string
+	dayStart    time.Time
+	dayEnd      time.Time
+	maxLegs     int
+	minTransfer time.Duration
+	maxTransfer time.Duration
+
+	journeys []model.Journey
+}
+
+func (s *JourneyServiceImpl) SearchJourneys(ctx context.Context, req *model.JourneySearchRequest) ([]model.Journey, error) {
+	dayStart, err := time.ParseInLocation(constants.ScheduleDateFormat, req.DepartureDate, s.location)
+	if err != nil {
+		return nil, ginext.NewBadRequestError("departure_date must be in YYYY-MM-DD format")
+	}
+
+	planner := &journeyPlanner{
+		origin:      req.Origin,
+		destination: req.Destination,
+		dayStart:    dayStart,
+		dayEnd:      dayStart.AddDate(0, 0, 1),
+		maxLegs:     constants.JourneyMaxTransfers + 1,
+		minTransfer: constants.JourneyDefaultMinTransferMinutes * time.Minute,
+		maxTransfer: constants.JourneyDefaultMaxTransferMinutes * time.Minute,
+	}
+	if req.MaxTransfers != nil {
+		planner.maxLegs = *req.MaxTransfers + 1
+	}
+	if req.MinTransferMinutes != nil {
+		planner.minTransfer = time.Duration(*req.MinTransferMinutes) * time.Minute
+	}
+	if req.MaxTransferMinutes != nil {
+		planner.maxTransfer = time.Duration(*req.MaxTransferMinutes) * time.Minute
+	}
+	if planner.maxTransfer > constants.JourneyMaxTransferMinutes*time.Minute {
+		return nil, ginext.NewBadRequestError("max_transfer_minutes must not exceed one day")
+	}
+	if planner.minTransfer > planner.maxTransfer {
+		return nil, ginext.NewBadRequestError("min_transfer_minutes must not exceed max_transfer_minutes")
+	}
+
+	planner.trips, err = s.tripRepo.ListDepartingTrips(ctx, planner.dayStart, planner.dayEnd.Add(constants.JourneySearchHorizon))
+	if err != nil {
+		log.Error().Err(err).Str("date", req.DepartureDate).Msg("Failed to list departing trips")
+		return nil, ginext.NewInternalServerError("failed to search journeys")
+	}
+
+	planner.extend(nil, nil, nil)
+	journeys := s.quoteJourneys(ctx, planner.trips, planner.journeys)
+
+	sortJourneys(journeys, req.SortBy, req.SortOrder == "desc")
+
+	limit := req.Limit
+	if limit <= 0 {
+		limit = constants.JourneyDefaultLimit
+	}
+	if len(journeys) > limit {
+		journeys = journeys[:limit]
+	}
+	return journeys, nil
+}
+
+// quoteJourneys prices the legs of the journeys at the current fares of their trips, as trip
+// search does, and leaves out journeys with a sold-out leg. Legs keep their base price when
+// pricing fails, and are then offered without knowing whether seats are left.
+func (s *JourneyServiceImpl) quoteJourneys(ctx context.Context, trips []model.Trip, journeys []model.Journey) []model.Journey {
+	if len(journeys) == 0 {
+		return journeys
+	}
+
+	used := make(map[uuid.UUID]bool)
+	for _, journey := range journeys {
+		for _, leg := range journey.Legs {
+			used[leg.TripID] = true
+		}
+	}
+	quoted := make([]model.Trip, 0, len(used))
+	for _, trip := range trips {
+		if used[trip.ID] {
+			quoted = append(quoted, trip)
+		}
+	}
+
+	fares, err := s.pricing.QuoteTrips(ctx, quoted)
+	if err != nil {
+		log.Warn().Err(err).Msg("Failed to quote fares of journeys")
+		return journeys
+	}
+
+	bookable := journeys[:0]
+	for _, journey := range journeys {
+		soldOut := false
+		for j := range journey.Legs {
+			leg := &journey.Legs[j]
+			fare, ok := fares[leg.TripID]
+			if !ok {
+				continue
+			}
+			leg.ApplyFare(fare)
+			if model.LowestAvailablePrice(leg.PriceTiers) == nil {
+				soldOut = true
+				break
+			}
+		}
+		if !soldOut {
+			bookable = append(bookable, model.NewJourney(journey.Legs))
+		}
+	}
+	return bookable
+}
+
+// extend tries every trip that can follow the legs so far, which ended at the from stop.
+// Trips that reach the destination complete a journey; others drop off at a transfer point
+// for the next leg. The cities passed through are not visited again, and no trip is boarded twice.
+func (p *journeyPlanner) extend(legs []model.JourneyLeg, from *model.RouteStop, cities []string) {
+	for i := range p.trips {
+		trip := &p.trips[i]
+		if trip.Route == nil || usesTrip(legs, trip.ID) {
+			continue
+		}
+
+		pickup := p.boardingStop(trip, from)
+		if pickup == nil {
+			continue
+		}
+
+		leg := model.JourneyLeg{
+			TripID:  trip.ID,
+			RouteID: trip.RouteID,
+			BusID:   trip.BusID,
+			Route:   model.ToRouteDetail(trip.Route),
+		}
+		departure := trip.DepartureTime.Add(time.Duration(pickup.OffsetMinutes) * time.Minute)
+		if from == nil {
+			if departure.Before(p.dayStart) || !departure.Before(p.dayEnd) {
+				continue
+			}
+		} else {
+			wait := departure.Sub(legs[len(legs)-1].Segment.ArrivalTime)
+			if wait < p.minTransfer || wait > p.maxTransfer {
+				continue
+			}
+			leg.TransferMinutes = int(wait.Minutes())
+			leg.ChangesStation = !strings.EqualFold(strings.TrimSpace(from.Location), strings.TrimSpace(pickup.Location))
+		}
+
+		visited := append(cities[:len(cities):len(cities)], pickup.City())
+		for j := range trip.Route.RouteStops {
+			dropoff := &trip.Route.RouteStops[j]
+			if dropoff.StopOrder <= pickup.StopOrder || !dropoff.CanDropoff() {
+				continue
+			}
+
+			leg.Segment = *model.NewTripSegmentDetail(trip, pickup, dropoff)
+			next := append(legs[:len(legs):len(legs)], leg)
+
+			if dropoff.MatchesPlace(p.destination) {
+				p.journeys = append(p.journeys, model.NewJourney(next))
+				break
+			}
+			if len(next) < p.maxLegs && !containsCity(visited, dropoff.City()) {
+				p.extend(next, dropoff, visited)
+			}
+		}
+	}
+}
+
+// boardingStop returns where the next leg boards the trip: the first pickup stop matching the
+// origin for the first leg, or the first pickup stop in the city of the from stop
+func (p *journeyPlanner) boardingStop(trip *model.Trip, from *model.RouteStop) *model.RouteStop {
+	for i := range trip.Route.RouteStops {
+		stop := &trip.Route.RouteStops[i]
+		if !stop.CanPickup() {
+			continue
+		}
+		if from == nil && stop.MatchesPlace(p.origin) || from != nil && stop.SameCity(from) {
+			return stop
+		}
+	}
+	return nil
+}
+
+func usesTrip(legs []model.JourneyLeg, tripID uuid.UUID) bool {
+	for _, leg := range legs {
+		if leg.TripID == tripID {
+			return true
+		}
+	}
+	return false
+}
+
+func containsCity(cities []string, city string) bool {
+	for _, c := range cities {
+		if strings.EqualFold(c, city) {
+			return true
+		}
+	}
+	return false
+}
+
+// sortJourneys orders journeys by the requested criterion, then by departure time and duration.
+// Duration is the default criterion.
+func sortJourneys(journeys []model.Journey, sortBy string, desc bool) {
+	compare := func(a, b *model.Journey) float64 {
+		switch sortBy {
+		case constants.JourneySortByPrice:
+			return a.TotalPrice - b.TotalPrice
+		case constants.JourneySortByTransfers:
+			return float64(a.Transfers - b.Transfers)
+		default:
+			return float64(a.TotalDurationMinutes - b.TotalDurationMinutes)
+		}
+	}
+
+	sort.SliceStable(journeys, func(i, j int) bool {
+		a, b := &journeys[i], &journeys[j]
+		if diff := compare(a, b); diff != 0 {
+			return (diff < 0) != desc
+		}
+		if !a.DepartureTime.Equal(b.DepartureTime) {
+			return a.DepartureTime.Before(b.DepartureTime)
+		}
+		return a.TotalDurationMinutes < b.TotalDurationMinutes
+	})
+}
@@ -0,0 +1,456 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"bus-booking/shared/ginext"
+	"bus-booking/trip-service/internal/constants"
+	"bus-booking/trip-service/internal/model"
+	repo_mocks "bus-booking/trip-service/internal/repository/mocks"
+	service_mocks "bus-booking/trip-service/internal/service/mocks"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+type journeyTestFixture struct {
+	service  JourneyService
+	tripRepo *repo_mocks.MockTripRepository
+	pricing  *service_mocks.MockPricingService
+	location *time.Location
+}
+
+func newJourneyTestFixture(ctrl *gomock.Controller) *journeyTestFixture {
+	f := &journeyTestFixture{
+		tripRepo: repo_mocks.NewMockTripRepository(ctrl),
+		pricing:  service_mocks.NewMockPricingService(ctrl),
+	}
+	f.service = NewJourneyService(f.tripRepo, f.pricing)
+	f.location = f.service.(*JourneyServiceImpl).location
+	return f
+}
+
+// fare quotes a trip at the given fare with seats left
+func (f *journeyTestFixture) fare(trip model.Trip, fare float64) *model.TripFare {
+	return &model.TripFare{
+		TripID:     trip.ID,
+		BasePrice:  trip.BasePrice,
+		Fare:       fare,
+		PriceTiers: []model.PriceTier{{SeatType: "standard", BasePrice: fare, PriceMultiplier: 1, FinalPrice: fare, AvailableCount: 10}},
+	}
+}
+
+// quoteAtBasePrice prices every trip at its base price
+func (f *journeyTestFixture) quoteAtBasePrice() {
+	f.pricing.EXPECT().QuoteTrips(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, trips []model.Trip) (map[uuid.UUID]*model.TripFare, error) {
+			fares := make(map[uuid.UUID]*model.TripFare, len(trips))
+			for _, trip := range trips {
+				fares[trip.ID] = f.fare(trip, trip.BasePrice)
+			}
+			return fares, nil
+		}).AnyTimes()
+}
+
+// journeyStop is a stop of a test route, offsetMinutes after departure
+type journeyStop struct {
+	location      string
+	address       string
+	offsetMinutes int
+	distanceKm    float64
+}
+
+var (
+	stopHaNoi     = journeyStop{location: "Bến xe Giáp Bát", address: "Giải Phóng, Hoàng Mai, Hà Nội"}
+	stopHue       = journeyStop{location: "Bến xe phía Nam Huế", address: "An Dương Vương, TP. Huế"}
+	stopDaNang    = journeyStop{location: "Bến xe Đà Nẵng", address: "Điện Biên Phủ, Thanh Khê, Đà Nẵng"}
+	stopDaNangSon = journeyStop{location: "Bến xe Sơn Trà", address: "Ngô Quyền, Sơn Trà, Đà Nẵng"}
+	stopQuyNhon   = journeyStop{location: "Bến xe Quy Nhơn", address: "Tây Sơn, TP. Quy Nhơn"}
+)
+
+func (s journeyStop) at(offsetMinutes int, distanceKm float64) journeyStop {
+	s.offsetMinutes, s.distanceKm = offsetMinutes, distanceKm
+	return s
+}
+
+// trip builds a scheduled trip on a route through the stops: boarding only at the first,
+// alighting only at the last and both at the ones in between
+func (f *journeyTestFixture) trip(departure time.Time, basePrice float64, stops ...journeyStop) model.Trip {
+	last := stops[len(stops)-1]
+	route := &model.Route{
+		BaseModel:        model.BaseModel{ID: uuid.New()},
+		Origin:           stops[0].location,
+		Destination:      last.location,
+		DistanceKm:       last.distanceKm,
+		EstimatedMinutes: last.offsetMinutes,
+		IsActive:         true,
+	}
+	for i, stop := range stops {
+		stopType := constants.StopTypeBoth
+		switch i {
+		case 0:
+			stopType = constants.StopTypePickup
+		case len(stops) - 1:
+			stopType = constants.StopTypeDropoff
+		}
+		route.RouteStops = append(route.RouteStops, model.RouteStop{
+			BaseModel:     model.BaseModel{ID: uuid.New()},
+			RouteID:       route.ID,
+			StopOrder:     i + 1,
+			StopType:      stopType,
+			Location:      stop.location,
+			Address:       stop.address,
+			OffsetMinutes: stop.offsetMinutes,
+			DistanceKm:    stop.distanceKm,
+			IsActive:      true,
+		})
+	}
+
+	return model.Trip{
+		BaseModel:     model.BaseModel{ID: uuid.New()},
+		RouteID:       route.ID,
+		BusID:         uuid.New(),
+		DepartureTime: departure,
+		ArrivalTime:   departure.Add(time.Duration(last.offsetMinutes) * time.Minute),
+		BasePrice:     basePrice,
+		Status:        constants.TripStatusScheduled,
+		IsActive:      true,
+		Route:         route,
+	}
+}
+
+func (f *journeyTestFixture) at(day, hour, minute int) time.Time {
+	return time.Date(2025, 6, day, hour, minute, 0, 0, f.location)
+}
+
+func (f *journeyTestFixture) request(sortBy string) *model.JourneySearchRequest {
+	return &model.JourneySearchRequest{
+		Origin:        "Hà Nội",
+		Destination:   "Quy Nhơn",
+		DepartureDate: "2025-06-10",
+		SortBy:        sortBy,
+	}
+}
+
+// hubTrips returns a Hà Nội - Đà Nẵng trip arriving at 20:00 with onward Đà Nẵng - Quy Nhơn
+// trips that leave too soon, in time and too late, plus a slower direct Hà Nội - Quy Nhơn trip via Huế
+func (f *journeyTestFixture) hubTrips() (toHub, onward, direct model.Trip, trips []model.Trip) {
+	toHub = f.trip(f.at(10, 8, 0), 400000, stopHaNoi, stopDaNang.at(720, 760))
+	tooSoon := f.trip(f.at(10, 20, 10), 200000, stopDaNang, stopQuyNhon.at(360, 300))
+	onward = f.trip(f.at(10, 21, 0), 200000, stopDaNangSon, stopQuyNhon.at(360, 300))
+	tooLate := f.trip(f.at(11, 4, 0), 200000, stopDaNang, stopQuyNhon.at(360, 300))
+	direct = f.trip(f.at(10, 10, 0), 700000, stopHaNoi, stopHue.at(600, 660), stopQuyNhon.at(1200, 1060))
+	return toHub, onward, direct, []model.Trip{toHub, direct, tooSoon, onward, tooLate}
+}
+
+func TestSearchJourneys_CombinesTripsAtHub(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	f := newJourneyTestFixture(ctrl)
+	toHub, onward, direct, trips := f.hubTrips()
+
+	f.tripRepo.EXPECT().ListDepartingTrips(gomock.Any(), f.at(10, 0, 0), f.at(11, 0, 0).Add(constants.JourneySearchHorizon)).
+		Return(trips, nil).Times(1)
+	f.quoteAtBasePrice()
+
+	journeys, err := f.service.SearchJourneys(context.Background(), f.request(""))
+
+	assert.NoError(t, err)
+	assert.Len(t, journeys, 2)
+
+	// Changing at Đà Nẵng arrives at 03:00, an hour before the direct trip
+	connection := journeys[0]
+	assert.Len(t, connection.Legs, 2)
+	assert.Equal(t, 1, connection.Transfers)
+	assert.Equal(t, toHub.ID, connection.Legs[0].TripID)
+	assert.Equal(t, onward.ID, connection.Legs[1].TripID)
+	assert.Equal(t, 60, connection.Legs[1].TransferMinutes)
+	assert.True(t, connection.Legs[1].ChangesStation)
+	assert.Equal(t, onward.Route.RouteStops[0].ID, connection.Legs[1].Segment.PickupStop.ID)
+	assert.Equal(t, f.at(10, 8, 0), connection.DepartureTime)
+	assert.Equal(t, f.at(11, 3, 0), connection.ArrivalTime)
+	assert.Equal(t, 19*60, connection.TotalDurationMinutes)
+	assert.Equal(t, float64(600000), connection.TotalPrice)
+	assert.Equal(t, float64(1060), connection.TotalDistanceKm)
+
+	assert.Equal(t, direct.ID, journeys[1].Legs[0].TripID)
+	assert.Equal(t, 0, journeys[1].Transfers)
+	assert.Equal(t, 20*60, journeys[1].TotalDurationMinutes)
+	assert.Equal(t, float64(700000), journeys[1].TotalPrice)
+}
+
+func TestSearchJourneys_SortByTransfersAndPrice(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	f := newJourneyTestFixture(ctrl)
+	_, _, direct, trips := f.hubTrips()
+
+	f.tripRepo.EXPECT().ListDepartingTrips(gomock.Any(), gomock.Any(), gomock.Any()).Return(trips, nil).Times(3)
+	f.quoteAtBasePrice()
+
+	journeys, err := f.service.SearchJourneys(context.Background(), f.request(constants.JourneySortByTransfers))
+	assert.NoError(t, err)
+	assert.Len(t, journeys, 2)
+	assert.Equal(t, direct.ID, journeys[0].Legs[0].TripID)
+
+	journeys, err = f.service.SearchJourneys(context.Background(), f.request(constants.JourneySortByPrice))
+	assert.NoError(t, err)
+	assert.Len(t, journeys, 2)
+	assert.Equal(t, float64(600000), journeys[0].TotalPrice)
+
+	req := f.request(constants.JourneySortByPrice)
+	req.SortOrder = "desc"
+	journeys, err = f.service.SearchJourneys(context.Background(), req)
+	assert.NoError(t, err)
+	assert.Len(t, journeys, 2)
+	assert.Equal(t, float64(700000), journeys[0].TotalPrice)
+}
+
+func TestSearchJourneys_RespectsTransferWindow(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	f := newJourneyTestFixture(ctrl)
+	_, _, direct, trips := f.hubTrips()
+
+	f.tripRepo.EXPECT().ListDepartingTrips(gomock.Any(), gomock.Any(), gomock.Any()).Return(trips, nil).Times(1)
+	f.quoteAtBasePrice()
+
+	// The onward trip leaves an hour after arrival, short of the required 90 minutes,
+	// while the next morning one is now within the window
+	req := f.request("")
+	minTransfer, maxTransfer := 90, 600
+	req.MinTransferMinutes, req.MaxTransferMinutes = &minTransfer, &maxTransfer
+
+	journeys, err := f.service.SearchJourneys(context.Background(), req)
+
+	assert.NoError(t, err)
+	if assert.Len(t, journeys, 2) {
+		assert.Equal(t, direct.ID, journeys[0].Legs[0].TripID)
+		assert.Equal(t, 480, journeys[1].Legs[1].TransferMinutes)
+	}
+}
+
+func TestSearchJourneys_ThreeLegs(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	f := newJourneyTestFixture(ctrl)
+	toHue := f.trip(f.at(10, 6, 0), 300000, stopHaNoi, stopHue.at(600, 660))
+	toDaNang := f.trip(f.at(10, 17, 0), 100000, stopHue, stopDaNang.at(180, 100))
+	toQuyNhon := f.trip(f.at(10, 21, 0), 200000, stopDaNang, stopQuyNhon.at(360, 300))
+	trips := []model.Trip{toHue, toDaNang, toQuyNhon}
+
+	f.tripRepo.EXPECT().ListDepartingTrips(gomock.Any(), gomock.Any(), gomock.Any()).Return(trips, nil).Times(2)
+	f.quoteAtBasePrice()
+
+	journeys, err := f.service.SearchJourneys(context.Background(), f.request(""))
+
+	assert.NoError(t, err)
+	if assert.Len(t, journeys, 1) {
+		assert.Equal(t, 2, journeys[0].Transfers)
+		assert.Equal(t, toHue.ID, journeys[0].Legs[0].TripID)
+		assert.Equal(t, toDaNang.ID, journeys[0].Legs[1].TripID)
+		assert.Equal(t, toQuyNhon.ID, journeys[0].Legs[2].TripID)
+		assert.False(t, journeys[0].Legs[2].ChangesStation)
+		assert.Equal(t, float64(600000), journeys[0].TotalPrice)
+	}
+
+	req := f.request("")
+	maxTransfers := 1
+	req.MaxTransfers = &maxTransfers
+
+	journeys, err = f.service.SearchJourneys(context.Background(), req)
+
+	assert.NoError(t, err)
+	assert.Empty(t, journeys)
+}
+
+func TestSearchJourneys_SegmentOfLongerTrip(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	f := newJourneyTestFixture(ctrl)
+	// Board the through trip at its Đà Nẵng stop and leave it at Quy Nhơn
+	toHub := f.trip(f.at(10, 8, 0), 400000, stopHaNoi, stopDaNang.at(720, 760))
+	through := f.trip(f.at(10, 14, 0), 1000000, stopHaNoi, stopDaNang.at(420, 760), stopQuyNhon.at(780, 1060))
+	trips := []model.Trip{toHub, through}
+
+	f.tripRepo.EXPECT().ListDepartingTrips(gomock.Any(), gomock.Any(), gomock.Any()).Return(trips, nil).Times(1)
+	f.quoteAtBasePrice()
+
+	journeys, err := f.service.SearchJourneys(context.Background(), f.request(constants.JourneySortByTransfers))
+
+	assert.NoError(t, err)
+	if assert.Len(t, journeys, 2) {
+		assert.Equal(t, through.ID, journeys[0].Legs[0].TripID)
+
+		leg := journeys[1].Legs[1]
+		assert.Equal(t, through.ID, leg.TripID)
+		assert.Equal(t, through.Route.RouteStops[1].ID, leg.Segment.PickupStop.ID)
+		assert.Equal(t, f.at(10, 21, 0), leg.Segment.DepartureTime)
+		assert.Equal(t, float64(283000), leg.Segment.BasePrice)
+	}
+}
+
+func TestSearchJourneys_PricesLegsAtCurrentFares(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	f := newJourneyTestFixture(ctrl)
+	toHub, onward, direct, trips := f.hubTrips()
+
+	f.tripRepo.EXPECT().ListDepartingTrips(gomock.Any(), gomock.Any(), gomock.Any()).Return(trips, nil).Times(1)
+	// Only the trips of the journeys found are quoted; the direct trip fails to price
+	f.pricing.EXPECT().QuoteTrips(gomock.Any(), gomock.Len(3)).Return(map[uuid.UUID]*model.TripFare{
+		toHub.ID:  f.fare(toHub, 560000),
+		onward.ID: f.fare(onward, 200000),
+	}, nil).Times(1)
+
+	journeys, err := f.service.SearchJourneys(context.Background(), f.request(constants.JourneySortByPrice))
+
+	assert.NoError(t, err)
+	if assert.Len(t, journeys, 2) {
+		// The surcharge on the first leg makes the connection dearer than the direct trip
+		assert.Equal(t, direct.ID, journeys[0].Legs[0].TripID)
+		assert.Equal(t, float64(700000), journeys[0].TotalPrice)
+		assert.Empty(t, journeys[0].Legs[0].PriceTiers)
+
+		connection := journeys[1]
+		assert.Equal(t, float64(760000), connection.TotalPrice)
+		assert.Equal(t, float64(560000), connection.Legs[0].Segment.BasePrice)
+		if assert.Len(t, connection.Legs[0].PriceTiers, 1) {
+			assert.Equal(t, float64(560000), connection.Legs[0].PriceTiers[0].FinalPrice)
+		}
+	}
+}
+
+func TestSearchJourneys_SegmentLegProratesQuotedFare(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	f := newJourneyTestFixture(ctrl)
+	toHub := f.trip(f.at(10, 8, 0), 400000, stopHaNoi, stopDaNang.at(720, 760))
+	through := f.trip(f.at(10, 14, 0), 1000000, stopHaNoi, stopDaNang.at(420, 760), stopQuyNhon.at(780, 1060))
+
+	f.tripRepo.EXPECT().ListDepartingTrips(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return([]model.Trip{toHub, through}, nil).Times(1)
+	f.pricing.EXPECT().QuoteTrips(gomock.Any(), gomock.Any()).Return(map[uuid.UUID]*model.TripFare{
+		toHub.ID:   f.fare(toHub, 400000),
+		through.ID: f.fare(through, 1200000),
+	}, nil).Times(1)
+
+	journeys, err := f.service.SearchJourneys(context.Background(), f.request(constants.JourneySortByTransfers))
+
+	assert.NoError(t, err)
+	if assert.Len(t, journeys, 2) {
+		assert.Equal(t, float64(1200000), journeys[0].TotalPrice)
+
+		// 300 of the 1060 km at the quoted fare, rounded to 1,000 VND
+		leg := journeys[1].Legs[1]
+		assert.Equal(t, float64(340000), leg.Segment.BasePrice)
+		assert.Equal(t, float64(340000), leg.PriceTiers[0].FinalPrice)
+		assert.Equal(t, float64(740000), journeys[1].TotalPrice)
+	}
+}
+
+func TestSearchJourneys_LeavesOutSoldOutLegs(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	f := newJourneyTestFixture(ctrl)
+	toHub, onward, direct, trips := f.hubTrips()
+
+	soldOut := f.fare(onward, 200000)
+	soldOut.PriceTiers[0].AvailableCount = 0
+
+	f.tripRepo.EXPECT().ListDepartingTrips(gomock.Any(), gomock.Any(), gomock.Any()).Return(trips, nil).Times(1)
+	f.pricing.EXPECT().QuoteTrips(gomock.Any(), gomock.Any()).Return(map[uuid.UUID]*model.TripFare{
+		toHub.ID:  f.fare(toHub, 400000),
+		onward.ID: soldOut,
+		direct.ID: f.fare(direct, 700000),
+	}, nil).Times(1)
+
+	journeys, err := f.service.SearchJourneys(context.Background(), f.request(""))
+
+	assert.NoError(t, err)
+	if assert.Len(t, journeys, 1) {
+		assert.Equal(t, direct.ID, journeys[0].Legs[0].TripID)
+	}
+}
+
+func TestSearchJourneys_PricingFailureKeepsBasePrices(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	f := newJourneyTestFixture(ctrl)
+	_, _, _, trips := f.hubTrips()
+
+	f.tripRepo.EXPECT().ListDepartingTrips(gomock.Any(), gomock.Any(), gomock.Any()).Return(trips, nil).Times(1)
+	f.pricing.EXPECT().QuoteTrips(gomock.Any(), gomock.Any()).Return(nil, errors.New("pricing down")).Times(1)
+
+	journeys, err := f.service.SearchJourneys(context.Background(), f.request(constants.JourneySortByPrice))
+
+	assert.NoError(t, err)
+	if assert.Len(t, journeys, 2) {
+		assert.Equal(t, float64(600000), journeys[0].TotalPrice)
+		assert.Equal(t, float64(700000), journeys[1].TotalPrice)
+	}
+}
+
+func TestSearchJourneys_InvalidRequest(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	f := newJourneyTestFixture(ctrl)
+	f.tripRepo.EXPECT().ListDepartingTrips(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+	minTransfer, maxTransfer, tooLong := 120, 60, 25*60
+	tests := []struct {
+		name   string
+		modify func(req *model.JourneySearchRequest)
+	}{
+		{"invalid date", func(req *model.JourneySearchRequest) { req.DepartureDate = "10/06/2025" }},
+		{"inverted window", func(req *model.JourneySearchRequest) {
+			req.MinTransferMinutes, req.MaxTransferMinutes = &minTransfer, &maxTransfer
+		}},
+		{"window over a day", func(req *model.JourneySearchRequest) { req.MaxTransferMinutes = &tooLong }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := f.request("")
+			tt.modify(req)
+
+			journeys, err := f.service.SearchJourneys(context.Background(), req)
+
+			assert.Nil(t, journeys)
+			var apiErr *ginext.Error
+			assert.ErrorAs(t, err, &apiErr)
+			assert.Equal(t, http.StatusBadRequest, apiErr.Code)
+		})
+	}
+}
+
+func TestSearchJourneys_RepositoryError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	f := newJourneyTestFixture(ctrl)
+	f.tripRepo.EXPECT().ListDepartingTrips(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(nil, errors.New("db down")).Times(1)
+
+	journeys, err := f.service.SearchJourneys(context.Background(), f.request(""))
+
+	assert.Nil(t, journeys)
+	var apiErr *ginext.Error
+	assert.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusInternalServerError, apiErr.Code)
+}
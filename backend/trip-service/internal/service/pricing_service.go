@@ -0,0 +1,415 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"math"
+	"time"
+
+	"bus-booking/shared/ginext"
+	"bus-booking/trip-service/internal/client"
+	"bus-booking/trip-service/internal/constants"
+	"bus-booking/trip-service/internal/model"
+	"bus-booking/trip-service/internal/repository"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+// PricingService manages the rules that make fares follow demand and quotes trips with them.
+// A quote looks at how full the trip is, how many days remain before departure, the departure
+// weekday and whether it falls on a holiday.
+type PricingService interface {
+	CreateRule(ctx context.Context, req *model.CreatePricingRuleRequest) (*model.PricingRule, error)
+	GetRuleByID(ctx context.Context, id uuid.UUID) (*model.PricingRule, error)
+	ListRules(ctx context.Context, req *model.ListPricingRulesRequest) ([]model.PricingRule, int64, error)
+	UpdateRule(ctx context.Context, id uuid.UUID, req *model.UpdatePricingRuleRequest) (*model.PricingRule, error)
+	DeleteRule(ctx context.Context, id uuid.UUID) error
+
+	CreateHoliday(ctx context.Context, req *model.CreateHolidayRequest) (*model.Holiday, error)
+	ListHolidays(ctx context.Context, req *model.ListHolidaysRequest) ([]model.Holiday, error)
+	DeleteHoliday(ctx context.Context, id uuid.UUID) error
+
+	// QuoteTrip prices a trip as of now
+	QuoteTrip(ctx context.Context, tripID uuid.UUID) (*model.TripFare, error)
+	// QuoteTrips prices several trips, keyed by trip ID; trips that fail to price are left out
+	QuoteTrips(ctx context.Context, trips []model.Trip) (map[uuid.UUID]*model.TripFare, error)
+}
+
+type PricingServiceImpl struct {
+	pricingRepo   repository.PricingRuleRepository
+	tripRepo      repository.TripRepository
+	routeRepo     repository.RouteRepository
+	seatRepo      repository.SeatRepository
+	bookingClient client.BookingClient
+	cache         CacheService
+	location      *time.Location
+	now           func() time.Time
+}
+
+func NewPricingService(
+	pricingRepo repository.PricingRuleRepository,
+	tripRepo repository.TripRepository,
+	routeRepo repository.RouteRepository,
+	seatRepo repository.SeatRepository,
+	bookingClient client.BookingClient,
+	cache CacheService,
+) PricingService {
+	return &PricingServiceImpl{
+		pricingRepo:   pricingRepo,
+		tripRepo:      tripRepo,
+		routeRepo:     routeRepo,
+		seatRepo:      seatRepo,
+		bookingClient: bookingClient,
+		cache:         cache,
+		location:      scheduleLocation(),
+		now:           time.Now,
+	}
+}
+
+func (s *PricingServiceImpl) CreateRule(ctx context.Context, req *model.CreatePricingRuleRequest) (*model.PricingRule, error) {
+	rule := &model.PricingRule{IsActive: true}
+	if err := s.applyRuleRequest(ctx, rule, req); err != nil {
+		return nil, err
+	}
+
+	if err := s.pricingRepo.Create(ctx, rule); err != nil {
+		log.Error().Err(err).Msg("Failed to create pricing rule")
+		return nil, ginext.NewInternalServerError("failed to create pricing rule")
+	}
+	invalidateAllFareCalendars(ctx, s.cache)
+	return rule, nil
+}
+
+func (s *PricingServiceImpl) GetRuleByID(ctx context.Context, id uuid.UUID) (*model.PricingRule, error) {
+	rule, err := s.pricingRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ginext.NewNotFoundError("pricing rule not found")
+		}
+		return nil, ginext.NewInternalServerError("failed to get pricing rule")
+	}
+	return rule, nil
+}
+
+func (s *PricingServiceImpl) ListRules(ctx context.Context, req *model.ListPricingRulesRequest) ([]model.PricingRule, int64, error) {
+	req.Normalize()
+
+	var routeID *uuid.UUID
+	if req.RouteID != nil && *req.RouteID != "" {
+		id, err := uuid.Parse(*req.RouteID)
+		if err != nil {
+			return nil, 0, ginext.NewBadRequestError("invalid route_id")
+		}
+		routeID = &id
+	}
+
+	rules, total, err := s.pricingRepo.List(ctx, routeID, req.Page, req.PageSize)
+	if err != nil {
+		return nil, 0, ginext.NewInternalServerError("failed to list pricing rules")
+	}
+	return rules, total, nil
+}
+
+// UpdateRule replaces the rule; fares already held or paid for are not affected
+func (s *PricingServiceImpl) UpdateRule(ctx context.Context, id uuid.UUID, req *model.UpdatePricingRuleRequest) (*model.PricingRule, error) {
+	rule, err := s.GetRuleByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.applyRuleRequest(ctx, rule, &req.CreatePricingRuleRequest); err != nil {
+		return nil, err
+	}
+	if req.IsActive != nil {
+		rule.IsActive = *req.IsActive
+	}
+
+	if err := s.pricingRepo.Update(ctx, rule); err != nil {
+		log.Error().Err(err).Str("rule_id", id.String()).Msg("Failed to update pricing rule")
+		return nil, ginext.NewInternalServerError("failed to update pricing rule")
+	}
+	invalidateAllFareCalendars(ctx, s.cache)
+	return rule, nil
+}
+
+func (s *PricingServiceImpl) DeleteRule(ctx context.Context, id uuid.UUID) error {
+	if _, err := s.GetRuleByID(ctx, id); err != nil {
+		return err
+	}
+
+	if err := s.pricingRepo.Delete(ctx, id); err != nil {
+		return ginext.NewInternalServerError("failed to delete pricing rule")
+	}
+	invalidateAllFareCalendars(ctx, s.cache)
+	return nil
+}
+
+func (s *PricingServiceImpl) applyRuleRequest(ctx context.Context, rule *model.PricingRule, req *model.CreatePricingRuleRequest) error {
+	if req.RouteID != nil {
+		if _, err := s.routeRepo.GetRouteByID(ctx, *req.RouteID); err != nil {
+			return ginext.NewBadRequestError("invalid route")
+		}
+	}
+
+	weekdays := pq.Int64Array{}
+	if len(req.Weekdays) > 0 {
+		var err error
+		if weekdays, err = toWeekdays(req.Weekdays); err != nil {
+			return err
+		}
+	}
+
+	rule.Name = req.Name
+	rule.Priority = req.Priority
+	rule.RouteID = req.RouteID
+	rule.MinLoadFactor = req.MinLoadFactor
+	rule.MaxLoadFactor = req.MaxLoadFactor
+	rule.MinDaysBefore = req.MinDaysBefore
+	rule.MaxDaysBefore = req.MaxDaysBefore
+	rule.Weekdays = weekdays
+	rule.OnHoliday = req.OnHoliday
+	rule.Multiplier = 1
+	if req.Multiplier != nil {
+		rule.Multiplier = *req.Multiplier
+	}
+	rule.Adjustment = req.Adjustment
+	rule.MinPriceRatio = req.MinPriceRatio
+	rule.MaxPriceRatio = req.MaxPriceRatio
+	rule.StopProcessing = req.StopProcessing
+
+	return validatePricingRule(rule)
+}
+
+func validatePricingRule(rule *model.PricingRule) error {
+	if rule.Name == "" {
+		return ginext.NewBadRequestError("name is required")
+	}
+	if outOfRange(rule.MinLoadFactor, 0, 1) || outOfRange(rule.MaxLoadFactor, 0, 1) {
+		return ginext.NewBadRequestError("load factors must be between 0 and 1")
+	}
+	if rule.MinLoadFactor != nil && rule.MaxLoadFactor != nil && *rule.MinLoadFactor > *rule.MaxLoadFactor {
+		return ginext.NewBadRequestError("min_load_factor must not exceed max_load_factor")
+	}
+	if (rule.MinDaysBefore != nil && *rule.MinDaysBefore < 0) || (rule.MaxDaysBefore != nil && *rule.MaxDaysBefore < 0) {
+		return ginext.NewBadRequestError("days before departure must be non-negative")
+	}
+	if rule.MinDaysBefore != nil && rule.MaxDaysBefore != nil && *rule.MinDaysBefore > *rule.MaxDaysBefore {
+		return ginext.NewBadRequestError("min_days_before must not exceed max_days_before")
+	}
+	if rule.Multiplier <= 0 || rule.Multiplier > constants.PricingMaxMultiplier {
+		return ginext.NewBadRequestError("multiplier must be greater than 0 and at most 5")
+	}
+	if (rule.MinPriceRatio != nil && *rule.MinPriceRatio <= 0) || (rule.MaxPriceRatio != nil && *rule.MaxPriceRatio <= 0) {
+		return ginext.NewBadRequestError("price ratios must be positive")
+	}
+	if rule.MinPriceRatio != nil && rule.MaxPriceRatio != nil && *rule.MinPriceRatio > *rule.MaxPriceRatio {
+		return ginext.NewBadRequestError("min_price_ratio must not exceed max_price_ratio")
+	}
+	return nil
+}
+
+func outOfRange(value *float64, min, max float64) bool {
+	return value != nil && (*value < min || *value > max)
+}
+
+func (s *PricingServiceImpl) CreateHoliday(ctx context.Context, req *model.CreateHolidayRequest) (*model.Holiday, error) {
+	startDate, err := time.ParseInLocation(constants.ScheduleDateFormat, req.StartDate, s.location)
+	if err != nil {
+		return nil, ginext.NewBadRequestError("start_date must be in YYYY-MM-DD format")
+	}
+	endDate, err := time.ParseInLocation(constants.ScheduleDateFormat, req.EndDate, s.location)
+	if err != nil {
+		return nil, ginext.NewBadRequestError("end_date must be in YYYY-MM-DD format")
+	}
+	// Recurring holidays may wrap around the new year, e.g. 30/12 - 2/1
+	if !req.RecurringYearly && endDate.Before(startDate) {
+		return nil, ginext.NewBadRequestError("end_date must not be before start_date")
+	}
+
+	holiday := &model.Holiday{
+		Name:            req.Name,
+		StartDate:       startDate,
+		EndDate:         endDate,
+		RecurringYearly: req.RecurringYearly,
+	}
+	if err := s.pricingRepo.CreateHoliday(ctx, holiday); err != nil {
+		log.Error().Err(err).Msg("Failed to create holiday")
+		return nil, ginext.NewInternalServerError("failed to create holiday")
+	}
+	invalidateAllFareCalendars(ctx, s.cache)
+	return holiday, nil
+}
+
+// ListHolidays returns the holiday calendar; with a year, only recurring holidays and those falling in it
+func (s *PricingServiceImpl) ListHolidays(ctx context.Context, req *model.ListHolidaysRequest) ([]model.Holiday, error) {
+	holidays, err := s.pricingRepo.ListHolidays(ctx)
+	if err != nil {
+		return nil, ginext.NewInternalServerError("failed to list holidays")
+	}
+	if req.Year == 0 {
+		return holidays, nil
+	}
+
+	filtered := make([]model.Holiday, 0, len(holidays))
+	for _, holiday := range holidays {
+		if holiday.RecurringYearly || (holiday.StartDate.Year() <= req.Year && holiday.EndDate.Year() >= req.Year) {
+			filtered = append(filtered, holiday)
+		}
+	}
+	return filtered, nil
+}
+
+func (s *PricingServiceImpl) DeleteHoliday(ctx context.Context, id uuid.UUID) error {
+	if err := s.pricingRepo.DeleteHoliday(ctx, id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ginext.NewNotFoundError("holiday not found")
+		}
+		return ginext.NewInternalServerError("failed to delete holiday")
+	}
+	invalidateAllFareCalendars(ctx, s.cache)
+	return nil
+}
+
+// pricingCalendar is what every quote of a request shares
+type pricingCalendar struct {
+	rules    []model.PricingRule
+	holidays []model.Holiday
+	now      time.Time
+}
+
+func (s *PricingServiceImpl) loadCalendar(ctx context.Context) (*pricingCalendar, error) {
+	rules, err := s.pricingRepo.ListActive(ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to list active pricing rules")
+		return nil, ginext.NewInternalServerError("failed to load pricing rules")
+	}
+
+	holidays, err := s.pricingRepo.ListHolidays(ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to list holidays")
+		return nil, ginext.NewInternalServerError("failed to load holidays")
+	}
+
+	return &pricingCalendar{
+		rules:    rules,
+		holidays: holidays,
+		now:      s.now(),
+	}, nil
+}
+
+func (s *PricingServiceImpl) QuoteTrip(ctx context.Context, tripID uuid.UUID) (*model.TripFare, error) {
+	trip, err := s.tripRepo.GetTripByID(ctx, &model.GetTripByIDRequest{PreloadBus: true, PreloadSeat: true}, tripID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ginext.NewNotFoundError("trip not found")
+		}
+		return nil, ginext.NewInternalServerError("failed to get trip")
+	}
+
+	calendar, err := s.loadCalendar(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var seats []model.Seat
+	if trip.Bus != nil {
+		seats = trip.Bus.Seats
+	}
+	return s.quote(ctx, trip, seats, calendar)
+}
+
+func (s *PricingServiceImpl) QuoteTrips(ctx context.Context, trips []model.Trip) (map[uuid.UUID]*model.TripFare, error) {
+	fares := make(map[uuid.UUID]*model.TripFare, len(trips))
+	if len(trips) == 0 {
+		return fares, nil
+	}
+
+	calendar, err := s.loadCalendar(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	seatsByBus := make(map[uuid.UUID][]model.Seat)
+	for i := range trips {
+		trip := &trips[i]
+		logger := log.With().Str("trip_id", trip.ID.String()).Logger()
+
+		seats, ok := seatsByBus[trip.BusID]
+		if !ok {
+			seats, err = s.seatRepo.GetListByBusID(ctx, trip.BusID)
+			if err != nil {
+				logger.Warn().Err(err).Msg("Failed to list seats for pricing")
+				continue
+			}
+			seatsByBus[trip.BusID] = seats
+		}
+
+		fare, err := s.quote(ctx, trip, seats, calendar)
+		if err != nil {
+			logger.Warn().Err(err).Msg("Failed to quote trip fare")
+			continue
+		}
+		fares[trip.ID] = fare
+	}
+	return fares, nil
+}
+
+func (s *PricingServiceImpl) quote(ctx context.Context, trip *model.Trip, seats []model.Seat, calendar *pricingCalendar) (*model.TripFare, error) {
+	seatIDs := make([]uuid.UUID, len(seats))
+	for i, seat := range seats {
+		seatIDs[i] = seat.ID
+	}
+
+	statuses, err := s.bookingClient.GetSeatStatus(ctx, trip.ID, seatIDs, nil)
+	if err != nil {
+		log.Error().Err(err).Str("trip_id", trip.ID.String()).Msg("Failed to check seat status from booking service")
+		return nil, ginext.NewInternalServerError("failed to get seat status")
+	}
+
+	taken := make(map[uuid.UUID]bool, len(statuses))
+	booked := 0
+	for _, status := range statuses {
+		if status.IsBooked {
+			booked++
+		}
+		taken[status.SeatID] = status.IsBooked || status.IsLocked
+	}
+
+	departure := trip.DepartureTime.In(s.location)
+	input := &model.PricingInput{
+		RouteID:    trip.RouteID,
+		DaysBefore: int(math.Round(dateOf(departure).Sub(dateOf(calendar.now.In(s.location))).Hours() / 24)),
+		Weekday:    departure.Weekday(),
+	}
+	if len(seats) > 0 {
+		input.LoadFactor = float64(booked) / float64(len(seats))
+	}
+	for i := range calendar.holidays {
+		if calendar.holidays[i].Covers(departure) {
+			input.Holiday = calendar.holidays[i].Name
+			break
+		}
+	}
+
+	fare, applied := model.PriceFare(trip.BasePrice, calendar.rules, input)
+
+	appliedRules := make([]string, len(applied))
+	for i, rule := range applied {
+		appliedRules[i] = rule.Name
+	}
+
+	return &model.TripFare{
+		TripID:       trip.ID,
+		BasePrice:    trip.BasePrice,
+		Fare:         fare,
+		LoadFactor:   input.LoadFactor,
+		DaysBefore:   input.DaysBefore,
+		Holiday:      input.Holiday,
+		AppliedRules: appliedRules,
+		PriceTiers:   model.BuildPriceTiers(fare, seats, taken),
+		QuotedAt:     calendar.now.UTC(),
+	}, nil
+}
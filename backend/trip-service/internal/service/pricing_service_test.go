@@ -0,0 +1,312 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"bus-booking/shared/ginext"
+	"bus-booking/trip-service/internal/client/mocks"
+	"bus-booking/trip-service/internal/constants"
+	"bus-booking/trip-service/internal/model"
+	"bus-booking/trip-service/internal/model/booking"
+	repo_mocks "bus-booking/trip-service/internal/repository/mocks"
+	service_mocks "bus-booking/trip-service/internal/service/mocks"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+type pricingTestFixture struct {
+	service       PricingService
+	pricingRepo   *repo_mocks.MockPricingRuleRepository
+	tripRepo      *repo_mocks.MockTripRepository
+	routeRepo     *repo_mocks.MockRouteRepository
+	seatRepo      *repo_mocks.MockSeatRepository
+	bookingClient *mocks.MockBookingClient
+	cache         *service_mocks.MockCacheService
+	location      *time.Location
+	now           time.Time
+}
+
+func newPricingTestFixture(ctrl *gomock.Controller) *pricingTestFixture {
+	f := &pricingTestFixture{
+		pricingRepo:   repo_mocks.NewMockPricingRuleRepository(ctrl),
+		tripRepo:      repo_mocks.NewMockTripRepository(ctrl),
+		routeRepo:     repo_mocks.NewMockRouteRepository(ctrl),
+		seatRepo:      repo_mocks.NewMockSeatRepository(ctrl),
+		bookingClient: mocks.NewMockBookingClient(ctrl),
+		cache:         service_mocks.NewMockCacheService(ctrl),
+	}
+	f.service = NewPricingService(f.pricingRepo, f.tripRepo, f.routeRepo, f.seatRepo, f.bookingClient, f.cache)
+
+	impl := f.service.(*PricingServiceImpl)
+	f.location = impl.location
+	// Friday 2025-04-25, 09:00
+	f.now = time.Date(2025, 4, 25, 9, 0, 0, 0, f.location)
+	impl.now = func() time.Time { return f.now }
+	return f
+}
+
+// trip builds a trip on a bus with the given number of standard seats and two VIP seats
+func (f *pricingTestFixture) trip(departure time.Time, basePrice float64, standardSeats int) (*model.Trip, []model.Seat) {
+	trip := &model.Trip{
+		BaseModel:     model.BaseModel{ID: uuid.New()},
+		RouteID:       uuid.New(),
+		BusID:         uuid.New(),
+		DepartureTime: departure,
+		BasePrice:     basePrice,
+	}
+
+	var seats []model.Seat
+	for i := 0; i < standardSeats; i++ {
+		seats = append(seats, model.Seat{BaseModel: model.BaseModel{ID: uuid.New()}, BusID: trip.BusID, SeatType: constants.SeatTypeStandard, PriceMultiplier: 1, IsAvailable: true})
+	}
+	for i := 0; i < 2; i++ {
+		seats = append(seats, model.Seat{BaseModel: model.BaseModel{ID: uuid.New()}, BusID: trip.BusID, SeatType: constants.SeatTypeVIP, PriceMultiplier: 1.5, IsAvailable: true})
+	}
+	return trip, seats
+}
+
+func (f *pricingTestFixture) expectCalendar(rules []model.PricingRule, holidays []model.Holiday) {
+	f.pricingRepo.EXPECT().ListActive(gomock.Any()).Return(rules, nil)
+	f.pricingRepo.EXPECT().ListHolidays(gomock.Any()).Return(holidays, nil)
+}
+
+// expectSeatStatus reports the first booked seats as booked and the next locked ones as held
+func (f *pricingTestFixture) expectSeatStatus(trip *model.Trip, seats []model.Seat, booked, locked int) {
+	var statuses []booking.SeatStatus
+	for i, seat := range seats {
+		statuses = append(statuses, booking.SeatStatus{
+			SeatID:   seat.ID,
+			IsBooked: i < booked,
+			IsLocked: i >= booked && i < booked+locked,
+		})
+	}
+	f.bookingClient.EXPECT().
+		GetSeatStatus(gomock.Any(), trip.ID, gomock.Len(len(seats)), nil).
+		Return(statuses, nil)
+}
+
+func floatPtr(v float64) *float64 { return &v }
+func intPtr(v int) *int           { return &v }
+func boolPtr(v bool) *bool        { return &v }
+
+func TestQuoteTrip_AppliesRulesByPriority(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	f := newPricingTestFixture(ctrl)
+
+	// Departs Sunday 2025-04-27, two days ahead, with 6 of 8 seats booked
+	trip, seats := f.trip(time.Date(2025, 4, 27, 8, 0, 0, 0, f.location), 200000, 6)
+	rules := []model.PricingRule{
+		{Name: "Weekend", Priority: 10, Weekdays: pq.Int64Array{0, 6}, Multiplier: 1.1, IsActive: true},
+		{Name: "Nearly full", Priority: 20, MinLoadFactor: floatPtr(0.7), Multiplier: 1.2, IsActive: true},
+		{Name: "Early bird", Priority: 30, MinDaysBefore: intPtr(14), Multiplier: 0.8, IsActive: true},
+		{Name: "Inactive", Priority: 40, Multiplier: 3, IsActive: false},
+	}
+
+	f.tripRepo.EXPECT().GetTripByID(gomock.Any(), gomock.Any(), trip.ID).Return(&model.Trip{
+		BaseModel:     trip.BaseModel,
+		RouteID:       trip.RouteID,
+		BusID:         trip.BusID,
+		DepartureTime: trip.DepartureTime,
+		BasePrice:     trip.BasePrice,
+		Bus:           &model.Bus{Seats: seats},
+	}, nil)
+	f.expectCalendar(rules, nil)
+	f.expectSeatStatus(trip, seats, 6, 1)
+
+	fare, err := f.service.QuoteTrip(context.Background(), trip.ID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 200000.0, fare.BasePrice)
+	assert.Equal(t, 264000.0, fare.Fare) // 200000 * 1.2 * 1.1
+	assert.Equal(t, []string{"Nearly full", "Weekend"}, fare.AppliedRules)
+	assert.Equal(t, 0.75, fare.LoadFactor)
+	assert.Equal(t, 2, fare.DaysBefore)
+	assert.Equal(t, f.now.UTC(), fare.QuotedAt)
+
+	assert.Equal(t, []model.PriceTier{
+		{SeatType: "standard", BasePrice: 264000, PriceMultiplier: 1, FinalPrice: 264000, AvailableCount: 0},
+		{SeatType: "vip", BasePrice: 264000, PriceMultiplier: 1.5, FinalPrice: 396000, AvailableCount: 1},
+	}, fare.PriceTiers)
+}
+
+func TestQuoteTrip_ClampsAndStopsProcessing(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	f := newPricingTestFixture(ctrl)
+
+	// Departs on 30/4, a recurring holiday
+	trip, seats := f.trip(time.Date(2025, 4, 30, 22, 0, 0, 0, f.location), 300000, 2)
+	rules := []model.PricingRule{
+		{Name: "Holiday surge", Priority: 50, OnHoliday: boolPtr(true), Multiplier: 2, MaxPriceRatio: floatPtr(1.5), StopProcessing: true, IsActive: true},
+		{Name: "Low season", Priority: 10, Multiplier: 0.5, IsActive: true},
+	}
+	holidays := []model.Holiday{
+		{Name: "Tết Dương lịch", StartDate: time.Date(2024, 1, 1, 0, 0, 0, 0, f.location), EndDate: time.Date(2024, 1, 1, 0, 0, 0, 0, f.location), RecurringYearly: true},
+		{Name: "30/4 - 1/5", StartDate: time.Date(2024, 4, 30, 0, 0, 0, 0, f.location), EndDate: time.Date(2024, 5, 1, 0, 0, 0, 0, f.location), RecurringYearly: true},
+	}
+
+	f.tripRepo.EXPECT().GetTripByID(gomock.Any(), gomock.Any(), trip.ID).Return(&model.Trip{
+		BaseModel:     trip.BaseModel,
+		DepartureTime: trip.DepartureTime,
+		BasePrice:     trip.BasePrice,
+		Bus:           &model.Bus{Seats: seats},
+	}, nil)
+	f.expectCalendar(rules, holidays)
+	f.expectSeatStatus(trip, seats, 0, 0)
+
+	fare, err := f.service.QuoteTrip(context.Background(), trip.ID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 450000.0, fare.Fare)
+	assert.Equal(t, "30/4 - 1/5", fare.Holiday)
+	assert.Equal(t, []string{"Holiday surge"}, fare.AppliedRules)
+}
+
+func TestQuoteTrip_NotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	f := newPricingTestFixture(ctrl)
+
+	tripID := uuid.New()
+	f.tripRepo.EXPECT().GetTripByID(gomock.Any(), gomock.Any(), tripID).Return(nil, gorm.ErrRecordNotFound)
+
+	fare, err := f.service.QuoteTrip(context.Background(), tripID)
+
+	assert.Nil(t, fare)
+	var apiErr *ginext.Error
+	assert.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusNotFound, apiErr.Code)
+}
+
+func TestQuoteTrips_SharesSeatsPerBusAndSkipsFailures(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	f := newPricingTestFixture(ctrl)
+
+	first, seats := f.trip(time.Date(2025, 5, 10, 8, 0, 0, 0, f.location), 200000, 2)
+	second := *first
+	second.ID = uuid.New()
+	second.DepartureTime = first.DepartureTime.AddDate(0, 0, 1)
+	rules := []model.PricingRule{
+		{Name: "Weekend", Weekdays: pq.Int64Array{6}, Multiplier: 1, Adjustment: 50000, IsActive: true},
+	}
+
+	f.expectCalendar(rules, nil)
+	f.seatRepo.EXPECT().GetListByBusID(gomock.Any(), first.BusID).Return(seats, nil).Times(1)
+	f.expectSeatStatus(first, seats, 0, 0)
+	f.bookingClient.EXPECT().GetSeatStatus(gomock.Any(), second.ID, gomock.Any(), nil).Return(nil, assert.AnError)
+
+	fares, err := f.service.QuoteTrips(context.Background(), []model.Trip{*first, second})
+
+	assert.NoError(t, err)
+	assert.Len(t, fares, 1)
+	assert.Equal(t, 250000.0, fares[first.ID].Fare)
+	assert.Equal(t, 15, fares[first.ID].DaysBefore)
+}
+
+func TestPriceFare_MinimumRatioAndRounding(t *testing.T) {
+	rules := []model.PricingRule{
+		{Name: "Last minute", MaxDaysBefore: intPtr(1), Multiplier: 0.33, MinPriceRatio: floatPtr(0.75), IsActive: true},
+	}
+
+	fare, applied := model.PriceFare(185000, rules, &model.PricingInput{DaysBefore: 0})
+	assert.Equal(t, 139000.0, fare) // clamped to 138750, rounded to 1000 VND
+	assert.Len(t, applied, 1)
+
+	fare, applied = model.PriceFare(185000, rules, &model.PricingInput{DaysBefore: 3})
+	assert.Equal(t, 185000.0, fare)
+	assert.Empty(t, applied)
+}
+
+func TestHolidayCovers(t *testing.T) {
+	loc := time.UTC
+	tet := model.Holiday{Name: "Tết", StartDate: time.Date(2026, 2, 14, 0, 0, 0, 0, loc), EndDate: time.Date(2026, 2, 22, 0, 0, 0, 0, loc)}
+	newYear := model.Holiday{Name: "Year end", StartDate: time.Date(2025, 12, 30, 0, 0, 0, 0, loc), EndDate: time.Date(2025, 1, 2, 0, 0, 0, 0, loc), RecurringYearly: true}
+
+	assert.True(t, tet.Covers(time.Date(2026, 2, 17, 23, 0, 0, 0, loc)))
+	assert.False(t, tet.Covers(time.Date(2027, 2, 17, 8, 0, 0, 0, loc)))
+	assert.True(t, newYear.Covers(time.Date(2030, 12, 31, 8, 0, 0, 0, loc)))
+	assert.True(t, newYear.Covers(time.Date(2031, 1, 2, 8, 0, 0, 0, loc)))
+	assert.False(t, newYear.Covers(time.Date(2031, 1, 3, 8, 0, 0, 0, loc)))
+}
+
+func TestCreateRule_Validation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	f := newPricingTestFixture(ctrl)
+
+	tests := []struct {
+		name string
+		req  model.CreatePricingRuleRequest
+	}{
+		{"load factor range inverted", model.CreatePricingRuleRequest{Name: "x", MinLoadFactor: floatPtr(0.8), MaxLoadFactor: floatPtr(0.5)}},
+		{"days before range inverted", model.CreatePricingRuleRequest{Name: "x", MinDaysBefore: intPtr(10), MaxDaysBefore: intPtr(2)}},
+		{"price ratios inverted", model.CreatePricingRuleRequest{Name: "x", MinPriceRatio: floatPtr(1.5), MaxPriceRatio: floatPtr(1.2)}},
+		{"multiplier too large", model.CreatePricingRuleRequest{Name: "x", Multiplier: floatPtr(8)}},
+		{"invalid weekday", model.CreatePricingRuleRequest{Name: "x", Weekdays: []int{7}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := f.service.CreateRule(context.Background(), &tt.req)
+
+			assert.Nil(t, rule)
+			var apiErr *ginext.Error
+			assert.ErrorAs(t, err, &apiErr)
+			assert.Equal(t, http.StatusBadRequest, apiErr.Code)
+		})
+	}
+}
+
+func TestCreateRule_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	f := newPricingTestFixture(ctrl)
+
+	routeID := uuid.New()
+	f.routeRepo.EXPECT().GetRouteByID(gomock.Any(), routeID).Return(&model.Route{BaseModel: model.BaseModel{ID: routeID}}, nil)
+	f.pricingRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+	f.cache.EXPECT().InvalidateAllFareCalendars(gomock.Any()).Return(nil)
+
+	rule, err := f.service.CreateRule(context.Background(), &model.CreatePricingRuleRequest{
+		Name:          "Tết surge",
+		Priority:      100,
+		RouteID:       &routeID,
+		OnHoliday:     boolPtr(true),
+		Weekdays:      []int{5, 6, 5},
+		MaxPriceRatio: floatPtr(2),
+		Adjustment:    100000,
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1.0, rule.Multiplier)
+	assert.Equal(t, pq.Int64Array{5, 6}, rule.Weekdays)
+	assert.True(t, rule.IsActive)
+}
+
+func TestCreateHoliday(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	f := newPricingTestFixture(ctrl)
+
+	_, err := f.service.CreateHoliday(context.Background(), &model.CreateHolidayRequest{Name: "Tết", StartDate: "2026-02-22", EndDate: "2026-02-14"})
+	var apiErr *ginext.Error
+	assert.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusBadRequest, apiErr.Code)
+
+	f.pricingRepo.EXPECT().CreateHoliday(gomock.Any(), gomock.Any()).Return(nil)
+	f.cache.EXPECT().InvalidateAllFareCalendars(gomock.Any()).Return(nil)
+	holiday, err := f.service.CreateHoliday(context.Background(), &model.CreateHolidayRequest{Name: "Tết Bính Ngọ", StartDate: "2026-02-14", EndDate: "2026-02-22"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "2026-02-14", holiday.StartDate.Format(constants.ScheduleDateFormat))
+	assert.Equal(t, "2026-02-22", holiday.EndDate.Format(constants.ScheduleDateFormat))
+}
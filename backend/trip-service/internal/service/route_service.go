@@ -85,6 +85,7 @@ func (s *RouteServiceImpl) Create(ctx context.Context, req *model.CreateRouteReq
 			Latitude:      stopReq.Latitude,
 			Longitude:     stopReq.Longitude,
 			OffsetMinutes: stopReq.OffsetMinutes,
+			DistanceKm:    stopReq.DistanceKm,
 			IsActive:      true,
 		}
 	}
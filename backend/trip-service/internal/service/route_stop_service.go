@@ -93,6 +93,7 @@ func (s *RouteStopServiceImpl) CreateRouteStop(ctx context.Context, req *model.C
 		Latitude:      req.Latitude,
 		Longitude:     req.Longitude,
 		OffsetMinutes: req.OffsetMinutes,
+		DistanceKm:    req.DistanceKm,
 		IsActive:      true,
 	}
 
@@ -136,6 +137,9 @@ func (s *RouteStopServiceImpl) UpdateRouteStop(ctx context.Context, id uuid.UUID
 	if req.OffsetMinutes != nil {
 		stop.OffsetMinutes = *req.OffsetMinutes
 	}
+	if req.DistanceKm != nil {
+		stop.DistanceKm = *req.DistanceKm
+	}
 	if req.IsActive != nil {
 		stop.IsActive = *req.IsActive
 	}
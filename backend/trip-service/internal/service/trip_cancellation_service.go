@@ -0,0 +1,418 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"bus-booking/shared/ginext"
+	"bus-booking/shared/outbox"
+	"bus-booking/trip-service/internal/client"
+	"bus-booking/trip-service/internal/constants"
+	"bus-booking/trip-service/internal/model"
+	"bus-booking/trip-service/internal/model/booking"
+	"bus-booking/trip-service/internal/model/payment"
+	"bus-booking/trip-service/internal/repository"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+const (
+	tripCancelledReason = "Trip Cancelled by Operator"
+
+	// tripCancellationLease bounds how long a crashed worker keeps other workers off a saga
+	tripCancellationLease = 5 * time.Minute
+
+	// maxTripCancellationAttempts is how often a failing step is tried before it waits for an admin retry
+	maxTripCancellationAttempts = 5
+)
+
+var errRefundRejected = errors.New("refund was rejected")
+
+// TripCancellationService runs the saga that cancels, refunds and notifies every booking
+// of a trip cancelled by the operator
+type TripCancellationService interface {
+	CancelTrip(ctx context.Context, tripID uuid.UUID) error
+	HandleTripCancelled(ctx context.Context, event *outbox.Event) error
+	ResumeCancellations(ctx context.Context) error
+
+	GetCancellation(ctx context.Context, tripID uuid.UUID) (*model.TripCancellationProgress, error)
+	RetryCancellation(ctx context.Context, tripID uuid.UUID) (*model.TripCancellationProgress, error)
+	AbortCancellation(ctx context.Context, tripID uuid.UUID) (*model.TripCancellationProgress, error)
+}
+
+type TripCancellationServiceImpl struct {
+	tripRepo         repository.TripRepository
+	cancellationRepo repository.TripCancellationRepository
+	bookingClient    client.BookingClient
+	paymentClient    client.PaymentClient
+	cache            CacheService
+}
+
+func NewTripCancellationService(
+	tripRepo repository.TripRepository,
+	cancellationRepo repository.TripCancellationRepository,
+	bookingClient client.BookingClient,
+	paymentClient client.PaymentClient,
+	cache CacheService,
+) TripCancellationService {
+	return &TripCancellationServiceImpl{
+		tripRepo:         tripRepo,
+		cancellationRepo: cancellationRepo,
+		bookingClient:    bookingClient,
+		paymentClient:    paymentClient,
+		cache:            cache,
+	}
+}
+
+// CancelTrip marks the trip cancelled and starts its cancellation saga in the same transaction
+func (s *TripCancellationServiceImpl) CancelTrip(ctx context.Context, tripID uuid.UUID) error {
+	trip, err := s.tripRepo.GetTripByID(ctx, &model.GetTripByIDRequest{}, tripID)
+	if err != nil {
+		return ginext.NewInternalServerError("failed to get trip")
+	}
+
+	// Only Scheduled or Delayed trips can be cancelled
+	if trip.Status != constants.TripStatusScheduled && trip.Status != constants.TripStatusDelayed {
+		return ginext.NewBadRequestError(fmt.Sprintf("Cannot cancel trip with status: %s. Only scheduled or delayed trips can be cancelled.", trip.Status))
+	}
+
+	saga := &model.TripCancellationSaga{
+		ID:             uuid.New(),
+		TripID:         trip.ID,
+		PreviousStatus: trip.Status,
+		Reason:         tripCancelledReason,
+		Status:         model.TripCancellationStatusRunning,
+	}
+	trip.Status = constants.TripStatusCancelled
+
+	event, err := outbox.NewEvent(model.AggregateTypeTrip, trip.ID, model.EventTypeTripCancelled, &model.TripCancelledEvent{
+		SagaID: saga.ID,
+		TripID: trip.ID,
+		Reason: saga.Reason,
+	})
+	if err != nil {
+		return ginext.NewInternalServerError(err.Error())
+	}
+
+	if err := s.cancellationRepo.Start(ctx, trip, saga, event); err != nil {
+		return ginext.NewInternalServerError("failed to update trip status")
+	}
+	invalidateFareCalendars(ctx, s.cache, trip.DepartureTime)
+
+	return nil
+}
+
+// HandleTripCancelled is the outbox handler starting the saga right after the trip is cancelled.
+// Failed booking steps are recorded on the saga rather than returned, so the event is only
+// redelivered when the saga itself could not run.
+func (s *TripCancellationServiceImpl) HandleTripCancelled(ctx context.Context, event *outbox.Event) error {
+	var payload model.TripCancelledEvent
+	if err := event.Decode(&payload); err != nil {
+		return outbox.Permanent(err)
+	}
+	if payload.SagaID == uuid.Nil {
+		return outbox.Permanent(fmt.Errorf("trip cancelled event %s has no saga", event.ID))
+	}
+
+	return s.run(ctx, payload.SagaID)
+}
+
+// ResumeCancellations advances every running saga, picking up sagas a crashed worker left behind
+// and bookings still waiting for their refund to be paid out
+func (s *TripCancellationServiceImpl) ResumeCancellations(ctx context.Context) error {
+	ids, err := s.cancellationRepo.ListRunningIDs(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list running trip cancellations: %w", err)
+	}
+
+	var errs []error
+	for _, id := range ids {
+		if err := s.run(ctx, id); err != nil {
+			errs = append(errs, fmt.Errorf("trip cancellation %s: %w", id, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (s *TripCancellationServiceImpl) GetCancellation(ctx context.Context, tripID uuid.UUID) (*model.TripCancellationProgress, error) {
+	saga, err := s.getLatest(ctx, tripID)
+	if err != nil {
+		return nil, err
+	}
+	return model.NewTripCancellationProgress(saga), nil
+}
+
+// RetryCancellation resets the failed steps of a trip cancellation and runs them again
+func (s *TripCancellationServiceImpl) RetryCancellation(ctx context.Context, tripID uuid.UUID) (*model.TripCancellationProgress, error) {
+	saga, err := s.getLatest(ctx, tripID)
+	if err != nil {
+		return nil, err
+	}
+	if saga.Status == model.TripCancellationStatusAborted {
+		return nil, ginext.NewBadRequestError("trip cancellation was aborted")
+	}
+
+	retried, err := s.cancellationRepo.RetryFailed(ctx, saga.ID)
+	if err != nil {
+		return nil, ginext.NewInternalServerError("failed to reset failed steps")
+	}
+	log.Info().Str("trip_id", tripID.String()).Int64("bookings", retried).Msg("Retrying failed trip cancellation steps")
+
+	if err := s.run(ctx, saga.ID); err != nil {
+		return nil, ginext.NewInternalServerError(err.Error())
+	}
+
+	return s.GetCancellation(ctx, tripID)
+}
+
+// AbortCancellation is the compensation of CancelTrip: it restores the trip status, which is only
+// possible while no passenger has been affected yet
+func (s *TripCancellationServiceImpl) AbortCancellation(ctx context.Context, tripID uuid.UUID) (*model.TripCancellationProgress, error) {
+	saga, err := s.getLatest(ctx, tripID)
+	if err != nil {
+		return nil, err
+	}
+	if saga.Status != model.TripCancellationStatusRunning && saga.Status != model.TripCancellationStatusFailed {
+		return nil, ginext.NewBadRequestError(fmt.Sprintf("trip cancellation is already %s", saga.Status))
+	}
+
+	acquired, err := s.cancellationRepo.AcquireLease(ctx, saga.ID, time.Now().Add(tripCancellationLease))
+	if err != nil {
+		return nil, ginext.NewInternalServerError("failed to lock trip cancellation")
+	}
+	if !acquired {
+		return nil, ginext.NewConflictError("trip cancellation is being processed, try again shortly")
+	}
+	defer s.releaseLease(ctx, saga.ID)
+
+	// Reload under the lease, the saga may have moved on since it was read
+	saga, err = s.cancellationRepo.GetByID(ctx, saga.ID)
+	if err != nil {
+		return nil, ginext.NewInternalServerError("failed to get trip cancellation")
+	}
+	for _, b := range saga.Bookings {
+		if b.BookingCancelledAt != nil {
+			return nil, ginext.NewConflictError("bookings of this trip have already been cancelled, the trip cannot be restored")
+		}
+	}
+
+	if err := s.cancellationRepo.Abort(ctx, saga); err != nil {
+		return nil, ginext.NewInternalServerError("failed to abort trip cancellation")
+	}
+	// A rare admin action, not worth loading the trip for its departure month
+	invalidateAllFareCalendars(ctx, s.cache)
+	log.Info().Str("trip_id", tripID.String()).Str("status", string(saga.PreviousStatus)).Msg("Trip cancellation aborted, trip restored")
+
+	return model.NewTripCancellationProgress(saga), nil
+}
+
+func (s *TripCancellationServiceImpl) getLatest(ctx context.Context, tripID uuid.UUID) (*model.TripCancellationSaga, error) {
+	saga, err := s.cancellationRepo.GetLatestByTripID(ctx, tripID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ginext.NewNotFoundError("trip has not been cancelled")
+	}
+	if err != nil {
+		return nil, ginext.NewInternalServerError("failed to get trip cancellation")
+	}
+	return saga, nil
+}
+
+// run advances one saga as far as it can go. A saga held by another worker is skipped.
+func (s *TripCancellationServiceImpl) run(ctx context.Context, sagaID uuid.UUID) error {
+	acquired, err := s.cancellationRepo.AcquireLease(ctx, sagaID, time.Now().Add(tripCancellationLease))
+	if err != nil {
+		return fmt.Errorf("failed to lock trip cancellation: %w", err)
+	}
+	if !acquired {
+		return nil
+	}
+	defer s.releaseLease(ctx, sagaID)
+
+	saga, err := s.cancellationRepo.GetByID(ctx, sagaID)
+	if err != nil {
+		return fmt.Errorf("failed to get trip cancellation: %w", err)
+	}
+	if saga.Status != model.TripCancellationStatusRunning {
+		return nil
+	}
+
+	if !saga.BookingsLoaded {
+		if err := s.loadBookings(ctx, saga); err != nil {
+			return err
+		}
+	}
+
+	for i := range saga.Bookings {
+		b := &saga.Bookings[i]
+		if b.Status != model.TripCancellationStatusRunning {
+			continue
+		}
+
+		s.advanceBooking(ctx, saga, b)
+		if err := s.cancellationRepo.UpdateBooking(ctx, b); err != nil {
+			return fmt.Errorf("failed to save booking %s progress: %w", b.BookingID, err)
+		}
+	}
+
+	return s.updateStatus(ctx, saga)
+}
+
+func (s *TripCancellationServiceImpl) loadBookings(ctx context.Context, saga *model.TripCancellationSaga) error {
+	bookings, err := s.bookingClient.GetActiveTripBookings(ctx, saga.TripID)
+	if err != nil {
+		return fmt.Errorf("failed to get bookings of cancelled trip: %w", err)
+	}
+
+	steps := make([]model.TripCancellationBooking, 0, len(bookings))
+	for _, b := range bookings {
+		step := model.TripCancellationBooking{
+			ID:                uuid.New(),
+			SagaID:            saga.ID,
+			BookingID:         b.ID,
+			TransactionStatus: b.TransactionStatus,
+			Status:            model.TripCancellationStatusRunning,
+		}
+		// The operator cancelled, so paid bookings are refunded in full
+		if b.TransactionStatus == "PAID" {
+			step.RefundRequired = true
+			step.RefundAmount = b.TotalAmount
+		}
+		steps = append(steps, step)
+	}
+
+	if err := s.cancellationRepo.AddBookings(ctx, saga, steps); err != nil {
+		return fmt.Errorf("failed to save bookings of cancelled trip: %w", err)
+	}
+
+	saga.BookingsLoaded = true
+	saga.Bookings = steps
+	return nil
+}
+
+// advanceBooking runs the pending steps of one booking and records a failure on the booking
+func (s *TripCancellationServiceImpl) advanceBooking(ctx context.Context, saga *model.TripCancellationSaga, b *model.TripCancellationBooking) {
+	step, err := s.runSteps(ctx, saga, b)
+	if err == nil {
+		return
+	}
+
+	b.Attempts++
+	b.FailedStep = step
+	b.LastError = err.Error()
+	if errors.Is(err, errRefundRejected) || b.Attempts >= maxTripCancellationAttempts {
+		b.Status = model.TripCancellationStatusFailed
+	}
+
+	log.Error().Err(err).
+		Str("trip_id", saga.TripID.String()).
+		Str("booking_id", b.BookingID.String()).
+		Str("step", string(step)).
+		Int("attempts", b.Attempts).
+		Msg("Trip cancellation step failed")
+}
+
+// runSteps resumes after the last completed step. Every call it makes is safe to repeat, so a
+// step that succeeded remotely but was not recorded before a crash is simply done again.
+func (s *TripCancellationServiceImpl) runSteps(ctx context.Context, saga *model.TripCancellationSaga, b *model.TripCancellationBooking) (model.TripCancellationStep, error) {
+	if b.BookingCancelledAt == nil {
+		if err := s.bookingClient.CancelForTrip(ctx, b.BookingID, saga.Reason); err != nil {
+			return model.TripCancellationStepCancelBooking, err
+		}
+		now := time.Now()
+		b.BookingCancelledAt = &now
+	}
+
+	if b.RefundRequired && b.RefundRequestedAt == nil {
+		// Keyed by the step, so a refund created before a crash is not created again. Legs of an
+		// itinerary share one payment and would otherwise each be refunded twice.
+		refund, err := s.paymentClient.CreateOperatorRefund(ctx, &payment.RefundRequest{
+			ID:           b.ID,
+			BookingID:    b.BookingID,
+			Reason:       saga.Reason,
+			RefundAmount: b.RefundAmount,
+		})
+		if errors.Is(err, client.ErrRefundAlreadyExists) {
+			refund, err = s.paymentClient.GetBookingRefund(ctx, b.BookingID)
+		}
+		if err != nil {
+			return model.TripCancellationStepRequestRefund, err
+		}
+		now := time.Now()
+		b.RefundID = &refund.ID
+		b.RefundStatus = refund.RefundStatus
+		b.RefundRequestedAt = &now
+	}
+
+	if b.PassengerNotifiedAt == nil {
+		if err := s.bookingClient.NotifyTripCancelled(ctx, b.BookingID, &booking.NotifyTripCancelledRequest{
+			Reason:       saga.Reason,
+			RefundAmount: b.RefundAmount,
+		}); err != nil {
+			return model.TripCancellationStepNotifyPassenger, err
+		}
+		now := time.Now()
+		b.PassengerNotifiedAt = &now
+	}
+
+	// Followed by the ID recorded: the booking may hold other refunds, and a refund partly paid back
+	// to the wallet has a completed part long before its bank part is paid out
+	if b.RefundRequired && b.RefundCompletedAt == nil {
+		refund, err := s.paymentClient.GetRefundStatus(ctx, *b.RefundID)
+		if err != nil {
+			return model.TripCancellationStepCompleteRefund, err
+		}
+		b.RefundStatus = refund.RefundStatus
+
+		switch refund.RefundStatus {
+		case payment.RefundStatusCompleted:
+			now := time.Now()
+			b.RefundCompletedAt = &now
+		case payment.RefundStatusRejected:
+			return model.TripCancellationStepCompleteRefund, fmt.Errorf("%w: refund %s", errRefundRejected, refund.ID)
+		default:
+			// Still waiting for an admin to pay it out, checked again on the next run
+			return "", nil
+		}
+	}
+
+	b.Status = model.TripCancellationStatusCompleted
+	b.FailedStep = ""
+	b.LastError = ""
+	return "", nil
+}
+
+// updateStatus completes the saga once no booking is running any more
+func (s *TripCancellationServiceImpl) updateStatus(ctx context.Context, saga *model.TripCancellationSaga) error {
+	status := model.TripCancellationStatusCompleted
+	for _, b := range saga.Bookings {
+		if b.Status == model.TripCancellationStatusRunning {
+			return nil
+		}
+		if b.Status == model.TripCancellationStatusFailed {
+			status = model.TripCancellationStatusFailed
+		}
+	}
+
+	saga.Status = status
+	if status == model.TripCancellationStatusCompleted {
+		now := time.Now()
+		saga.CompletedAt = &now
+	}
+
+	if err := s.cancellationRepo.UpdateStatus(ctx, saga); err != nil {
+		return fmt.Errorf("failed to update trip cancellation status: %w", err)
+	}
+
+	log.Info().Str("trip_id", saga.TripID.String()).Str("status", string(status)).Msg("Trip cancellation finished")
+	return nil
+}
+
+func (s *TripCancellationServiceImpl) releaseLease(ctx context.Context, sagaID uuid.UUID) {
+	if err := s.cancellationRepo.ReleaseLease(context.WithoutCancel(ctx), sagaID); err != nil {
+		log.Warn().Err(err).Str("saga_id", sagaID.String()).Msg("Failed to release trip cancellation lease")
+	}
+}
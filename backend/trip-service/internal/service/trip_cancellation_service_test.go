@@ -0,0 +1,523 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"bus-booking/shared/outbox"
+	"bus-booking/trip-service/internal/client"
+	"bus-booking/trip-service/internal/client/mocks"
+	"bus-booking/trip-service/internal/constants"
+	"bus-booking/trip-service/internal/model"
+	"bus-booking/trip-service/internal/model/booking"
+	"bus-booking/trip-service/internal/model/payment"
+	repo_mocks "bus-booking/trip-service/internal/repository/mocks"
+	service_mocks "bus-booking/trip-service/internal/service/mocks"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+type tripCancellationMocks struct {
+	tripRepo         *repo_mocks.MockTripRepository
+	cancellationRepo *repo_mocks.MockTripCancellationRepository
+	bookingClient    *mocks.MockBookingClient
+	paymentClient    *mocks.MockPaymentClient
+	cache            *service_mocks.MockCacheService
+}
+
+func newTestTripCancellationService(ctrl *gomock.Controller) (TripCancellationService, *tripCancellationMocks) {
+	m := &tripCancellationMocks{
+		tripRepo:         repo_mocks.NewMockTripRepository(ctrl),
+		cancellationRepo: repo_mocks.NewMockTripCancellationRepository(ctrl),
+		bookingClient:    mocks.NewMockBookingClient(ctrl),
+		paymentClient:    mocks.NewMockPaymentClient(ctrl),
+		cache:            service_mocks.NewMockCacheService(ctrl),
+	}
+	return NewTripCancellationService(m.tripRepo, m.cancellationRepo, m.bookingClient, m.paymentClient, m.cache), m
+}
+
+// expectLease expects the saga to be claimed and released once
+func (m *tripCancellationMocks) expectLease(sagaID uuid.UUID) {
+	m.cancellationRepo.EXPECT().AcquireLease(gomock.Any(), sagaID, gomock.Any()).Return(true, nil).Times(1)
+	m.cancellationRepo.EXPECT().ReleaseLease(gomock.Any(), sagaID).Return(nil).Times(1)
+}
+
+func newRunningSaga(tripID uuid.UUID, bookings ...model.TripCancellationBooking) *model.TripCancellationSaga {
+	sagaID := uuid.New()
+	for i := range bookings {
+		bookings[i].SagaID = sagaID
+	}
+	return &model.TripCancellationSaga{
+		ID:             sagaID,
+		TripID:         tripID,
+		PreviousStatus: constants.TripStatusScheduled,
+		Reason:         tripCancelledReason,
+		Status:         model.TripCancellationStatusRunning,
+		BookingsLoaded: true,
+		Bookings:       bookings,
+	}
+}
+
+func TestCancelTrip_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, m := newTestTripCancellationService(ctrl)
+
+	ctx := context.Background()
+	tripID := uuid.New()
+
+	trip := &model.Trip{
+		BaseModel: model.BaseModel{ID: tripID},
+		Status:    constants.TripStatusDelayed,
+	}
+
+	m.tripRepo.EXPECT().GetTripByID(ctx, gomock.Any(), tripID).Return(trip, nil).Times(1)
+	m.cancellationRepo.EXPECT().Start(ctx, gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, tr *model.Trip, saga *model.TripCancellationSaga, events ...*outbox.Event) error {
+			assert.Equal(t, constants.TripStatusCancelled, tr.Status)
+			assert.Equal(t, constants.TripStatusDelayed, saga.PreviousStatus)
+			assert.Equal(t, model.TripCancellationStatusRunning, saga.Status)
+			assert.Len(t, events, 1)
+			assert.Equal(t, model.EventTypeTripCancelled, events[0].EventType)
+
+			var payload model.TripCancelledEvent
+			assert.NoError(t, events[0].Decode(&payload))
+			assert.Equal(t, saga.ID, payload.SagaID)
+			assert.Equal(t, tripID, payload.TripID)
+			return nil
+		}).Times(1)
+
+	m.cache.EXPECT().InvalidateFareCalendars(ctx, gomock.Any()).Return(nil).Times(1)
+
+	// Refunds and cancellations are left to the saga
+	m.bookingClient.EXPECT().GetActiveTripBookings(gomock.Any(), gomock.Any()).Times(0)
+
+	err := service.CancelTrip(ctx, tripID)
+	assert.NoError(t, err)
+}
+
+func TestCancelTrip_InvalidStatus(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, m := newTestTripCancellationService(ctrl)
+
+	ctx := context.Background()
+	tripID := uuid.New()
+
+	trip := &model.Trip{
+		BaseModel: model.BaseModel{ID: tripID},
+		Status:    constants.TripStatusCompleted, // Cannot cancel completed
+	}
+
+	m.tripRepo.EXPECT().GetTripByID(ctx, gomock.Any(), tripID).Return(trip, nil).Times(1)
+
+	err := service.CancelTrip(ctx, tripID)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "Cannot cancel trip")
+}
+
+func TestHandleTripCancelled_LoadsAndAdvancesBookings(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, m := newTestTripCancellationService(ctrl)
+
+	ctx := context.Background()
+	tripID := uuid.New()
+	paidID := uuid.New()
+	pendingID := uuid.New()
+	refundID := uuid.New()
+
+	saga := newRunningSaga(tripID)
+	saga.BookingsLoaded = false
+
+	event, err := outbox.NewEvent(model.AggregateTypeTrip, tripID, model.EventTypeTripCancelled, &model.TripCancelledEvent{
+		SagaID: saga.ID,
+		TripID: tripID,
+		Reason: tripCancelledReason,
+	})
+	assert.NoError(t, err)
+
+	var paidStepID uuid.UUID
+	m.expectLease(saga.ID)
+	m.cancellationRepo.EXPECT().GetByID(ctx, saga.ID).Return(saga, nil).Times(1)
+	m.bookingClient.EXPECT().GetActiveTripBookings(ctx, tripID).Return([]*booking.Booking{
+		{ID: paidID, TransactionStatus: "PAID", TotalAmount: 250000, Status: "CONFIRMED"},
+		{ID: pendingID, TransactionStatus: "PENDING", TotalAmount: 250000, Status: "PENDING"},
+	}, nil).Times(1)
+	m.cancellationRepo.EXPECT().AddBookings(ctx, saga, gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ *model.TripCancellationSaga, bookings []model.TripCancellationBooking) error {
+			assert.Len(t, bookings, 2)
+			assert.True(t, bookings[0].RefundRequired)
+			assert.Equal(t, 250000, bookings[0].RefundAmount)
+			assert.False(t, bookings[1].RefundRequired)
+			paidStepID = bookings[0].ID
+			return nil
+		}).Times(1)
+
+	m.bookingClient.EXPECT().CancelForTrip(ctx, paidID, tripCancelledReason).Return(nil).Times(1)
+	m.bookingClient.EXPECT().CancelForTrip(ctx, pendingID, tripCancelledReason).Return(nil).Times(1)
+	m.paymentClient.EXPECT().CreateOperatorRefund(ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, req *payment.RefundRequest) (*payment.RefundResponse, error) {
+			assert.Equal(t, paidStepID, req.ID)
+			assert.Equal(t, paidID, req.BookingID)
+			assert.Equal(t, 250000, req.RefundAmount)
+			return &payment.RefundResponse{ID: refundID, RefundStatus: "PENDING"}, nil
+		}).Times(1)
+	m.bookingClient.EXPECT().NotifyTripCancelled(ctx, paidID, &booking.NotifyTripCancelledRequest{
+		Reason:       tripCancelledReason,
+		RefundAmount: 250000,
+	}).Return(nil).Times(1)
+	m.bookingClient.EXPECT().NotifyTripCancelled(ctx, pendingID, gomock.Any()).Return(nil).Times(1)
+
+	// The refund is not paid out yet
+	m.paymentClient.EXPECT().GetRefundStatus(ctx, refundID).
+		Return(&payment.RefundResponse{ID: refundID, RefundStatus: "PENDING"}, nil).Times(1)
+
+	var saved []model.TripCancellationBooking
+	m.cancellationRepo.EXPECT().UpdateBooking(ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, b *model.TripCancellationBooking) error {
+			saved = append(saved, *b)
+			return nil
+		}).Times(2)
+
+	// The paid booking is still waiting, so the saga keeps running
+	m.cancellationRepo.EXPECT().UpdateStatus(gomock.Any(), gomock.Any()).Times(0)
+
+	err = service.HandleTripCancelled(ctx, event)
+	assert.NoError(t, err)
+
+	assert.Len(t, saved, 2)
+	assert.Equal(t, model.TripCancellationStatusRunning, saved[0].Status)
+	assert.NotNil(t, saved[0].BookingCancelledAt)
+	assert.NotNil(t, saved[0].RefundRequestedAt)
+	assert.NotNil(t, saved[0].PassengerNotifiedAt)
+	assert.Nil(t, saved[0].RefundCompletedAt)
+	assert.Equal(t, refundID, *saved[0].RefundID)
+	assert.Equal(t, model.TripCancellationStatusCompleted, saved[1].Status)
+}
+
+func TestResumeCancellations_CompletesSagaWhenRefundPaidOut(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, m := newTestTripCancellationService(ctrl)
+
+	ctx := context.Background()
+	bookingID := uuid.New()
+	refundID := uuid.New()
+	now := time.Now()
+
+	// Crashed after notifying the passenger, only the refund payout is left
+	saga := newRunningSaga(uuid.New(), model.TripCancellationBooking{
+		ID:                  uuid.New(),
+		BookingID:           bookingID,
+		RefundRequired:      true,
+		RefundAmount:        100000,
+		RefundID:            &refundID,
+		BookingCancelledAt:  &now,
+		RefundRequestedAt:   &now,
+		PassengerNotifiedAt: &now,
+		Status:              model.TripCancellationStatusRunning,
+	})
+
+	m.cancellationRepo.EXPECT().ListRunningIDs(ctx).Return([]uuid.UUID{saga.ID}, nil).Times(1)
+	m.expectLease(saga.ID)
+	m.cancellationRepo.EXPECT().GetByID(ctx, saga.ID).Return(saga, nil).Times(1)
+
+	// Completed steps are not repeated
+	m.bookingClient.EXPECT().CancelForTrip(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+	m.paymentClient.EXPECT().CreateOperatorRefund(gomock.Any(), gomock.Any()).Times(0)
+	m.bookingClient.EXPECT().NotifyTripCancelled(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+	m.paymentClient.EXPECT().GetRefundStatus(ctx, refundID).
+		Return(&payment.RefundResponse{ID: refundID, RefundStatus: payment.RefundStatusCompleted}, nil).Times(1)
+	m.cancellationRepo.EXPECT().UpdateBooking(ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, b *model.TripCancellationBooking) error {
+			assert.Equal(t, model.TripCancellationStatusCompleted, b.Status)
+			assert.NotNil(t, b.RefundCompletedAt)
+			return nil
+		}).Times(1)
+	m.cancellationRepo.EXPECT().UpdateStatus(ctx, saga).
+		DoAndReturn(func(_ context.Context, s *model.TripCancellationSaga) error {
+			assert.Equal(t, model.TripCancellationStatusCompleted, s.Status)
+			assert.NotNil(t, s.CompletedAt)
+			return nil
+		}).Times(1)
+
+	err := service.ResumeCancellations(ctx)
+	assert.NoError(t, err)
+}
+
+func TestResumeCancellations_SkipsSagaLeasedByAnotherWorker(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, m := newTestTripCancellationService(ctrl)
+
+	ctx := context.Background()
+	sagaID := uuid.New()
+
+	m.cancellationRepo.EXPECT().ListRunningIDs(ctx).Return([]uuid.UUID{sagaID}, nil).Times(1)
+	m.cancellationRepo.EXPECT().AcquireLease(ctx, sagaID, gomock.Any()).Return(false, nil).Times(1)
+	m.cancellationRepo.EXPECT().GetByID(gomock.Any(), gomock.Any()).Times(0)
+
+	err := service.ResumeCancellations(ctx)
+	assert.NoError(t, err)
+}
+
+func TestResumeCancellations_RefundAlreadyExists(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, m := newTestTripCancellationService(ctrl)
+
+	ctx := context.Background()
+	bookingID := uuid.New()
+	refundID := uuid.New()
+	now := time.Now()
+
+	// The refund went through before a crash but was never recorded
+	saga := newRunningSaga(uuid.New(), model.TripCancellationBooking{
+		ID:                 uuid.New(),
+		BookingID:          bookingID,
+		RefundRequired:     true,
+		RefundAmount:       100000,
+		BookingCancelledAt: &now,
+		Status:             model.TripCancellationStatusRunning,
+	})
+
+	m.cancellationRepo.EXPECT().ListRunningIDs(ctx).Return([]uuid.UUID{saga.ID}, nil).Times(1)
+	m.expectLease(saga.ID)
+	m.cancellationRepo.EXPECT().GetByID(ctx, saga.ID).Return(saga, nil).Times(1)
+	m.paymentClient.EXPECT().CreateOperatorRefund(ctx, gomock.Any()).Return(nil, client.ErrRefundAlreadyExists).Times(1)
+	m.paymentClient.EXPECT().GetBookingRefund(ctx, bookingID).
+		Return(&payment.RefundResponse{ID: refundID, RefundStatus: "PROCESSING"}, nil).Times(1)
+	m.paymentClient.EXPECT().GetRefundStatus(ctx, refundID).
+		Return(&payment.RefundResponse{ID: refundID, RefundStatus: "PROCESSING"}, nil).Times(1)
+	m.bookingClient.EXPECT().NotifyTripCancelled(ctx, bookingID, gomock.Any()).Return(nil).Times(1)
+	m.cancellationRepo.EXPECT().UpdateBooking(ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, b *model.TripCancellationBooking) error {
+			assert.Equal(t, refundID, *b.RefundID)
+			assert.Equal(t, 0, b.Attempts)
+			return nil
+		}).Times(1)
+
+	err := service.ResumeCancellations(ctx)
+	assert.NoError(t, err)
+}
+
+func TestResumeCancellations_RetriedRefundKeyedByStep(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, m := newTestTripCancellationService(ctrl)
+
+	ctx := context.Background()
+	stepID := uuid.New()
+	bookingID := uuid.New()
+	now := time.Now()
+
+	// The refund was created before a crash but never recorded, payment service returns it again
+	saga := newRunningSaga(uuid.New(), model.TripCancellationBooking{
+		ID:                 stepID,
+		BookingID:          bookingID,
+		RefundRequired:     true,
+		RefundAmount:       100000,
+		BookingCancelledAt: &now,
+		Status:             model.TripCancellationStatusRunning,
+	})
+
+	m.cancellationRepo.EXPECT().ListRunningIDs(ctx).Return([]uuid.UUID{saga.ID}, nil).Times(1)
+	m.expectLease(saga.ID)
+	m.cancellationRepo.EXPECT().GetByID(ctx, saga.ID).Return(saga, nil).Times(1)
+	m.paymentClient.EXPECT().CreateOperatorRefund(ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, req *payment.RefundRequest) (*payment.RefundResponse, error) {
+			assert.Equal(t, stepID, req.ID)
+			return &payment.RefundResponse{ID: stepID, RefundStatus: "PENDING"}, nil
+		}).Times(1)
+	m.paymentClient.EXPECT().GetBookingRefund(gomock.Any(), gomock.Any()).Times(0)
+	m.bookingClient.EXPECT().NotifyTripCancelled(ctx, bookingID, gomock.Any()).Return(nil).Times(1)
+	m.paymentClient.EXPECT().GetRefundStatus(ctx, stepID).
+		Return(&payment.RefundResponse{ID: stepID, RefundStatus: "PENDING"}, nil).Times(1)
+	m.cancellationRepo.EXPECT().UpdateBooking(ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, b *model.TripCancellationBooking) error {
+			assert.Equal(t, stepID, *b.RefundID)
+			assert.NotNil(t, b.RefundRequestedAt)
+			return nil
+		}).Times(1)
+
+	err := service.ResumeCancellations(ctx)
+	assert.NoError(t, err)
+}
+
+func TestResumeCancellations_StepFailureIsRecorded(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, m := newTestTripCancellationService(ctrl)
+
+	ctx := context.Background()
+	bookingID := uuid.New()
+
+	saga := newRunningSaga(uuid.New(), model.TripCancellationBooking{
+		ID:        uuid.New(),
+		BookingID: bookingID,
+		Status:    model.TripCancellationStatusRunning,
+		Attempts:  maxTripCancellationAttempts - 1,
+	})
+
+	m.cancellationRepo.EXPECT().ListRunningIDs(ctx).Return([]uuid.UUID{saga.ID}, nil).Times(1)
+	m.expectLease(saga.ID)
+	m.cancellationRepo.EXPECT().GetByID(ctx, saga.ID).Return(saga, nil).Times(1)
+	m.bookingClient.EXPECT().CancelForTrip(ctx, bookingID, tripCancelledReason).Return(assert.AnError).Times(1)
+
+	// Later steps wait for the booking to be cancelled
+	m.bookingClient.EXPECT().NotifyTripCancelled(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+	m.cancellationRepo.EXPECT().UpdateBooking(ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, b *model.TripCancellationBooking) error {
+			assert.Equal(t, model.TripCancellationStatusFailed, b.Status)
+			assert.Equal(t, model.TripCancellationStepCancelBooking, b.FailedStep)
+			assert.Equal(t, maxTripCancellationAttempts, b.Attempts)
+			assert.Equal(t, assert.AnError.Error(), b.LastError)
+			return nil
+		}).Times(1)
+	m.cancellationRepo.EXPECT().UpdateStatus(ctx, saga).
+		DoAndReturn(func(_ context.Context, s *model.TripCancellationSaga) error {
+			assert.Equal(t, model.TripCancellationStatusFailed, s.Status)
+			return nil
+		}).Times(1)
+
+	err := service.ResumeCancellations(ctx)
+	assert.NoError(t, err)
+}
+
+func TestRetryCancellation_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, m := newTestTripCancellationService(ctrl)
+
+	ctx := context.Background()
+	tripID := uuid.New()
+	bookingID := uuid.New()
+
+	failed := newRunningSaga(tripID, model.TripCancellationBooking{
+		ID:         uuid.New(),
+		BookingID:  bookingID,
+		Status:     model.TripCancellationStatusFailed,
+		Attempts:   maxTripCancellationAttempts,
+		FailedStep: model.TripCancellationStepNotifyPassenger,
+	})
+	failed.Status = model.TripCancellationStatusFailed
+
+	now := time.Now()
+	reset := newRunningSaga(tripID, model.TripCancellationBooking{
+		ID:                 uuid.New(),
+		BookingID:          bookingID,
+		BookingCancelledAt: &now,
+		Status:             model.TripCancellationStatusRunning,
+	})
+	reset.ID = failed.ID
+
+	done := *reset
+	done.Status = model.TripCancellationStatusCompleted
+
+	m.cancellationRepo.EXPECT().GetLatestByTripID(ctx, tripID).Return(failed, nil).Times(1)
+	m.cancellationRepo.EXPECT().RetryFailed(ctx, failed.ID).Return(int64(1), nil).Times(1)
+	m.expectLease(failed.ID)
+	m.cancellationRepo.EXPECT().GetByID(ctx, failed.ID).Return(reset, nil).Times(1)
+	m.bookingClient.EXPECT().NotifyTripCancelled(ctx, bookingID, gomock.Any()).Return(nil).Times(1)
+	m.cancellationRepo.EXPECT().UpdateBooking(ctx, gomock.Any()).Return(nil).Times(1)
+	m.cancellationRepo.EXPECT().UpdateStatus(ctx, reset).Return(nil).Times(1)
+	m.cancellationRepo.EXPECT().GetLatestByTripID(ctx, tripID).Return(&done, nil).Times(1)
+
+	progress, err := service.RetryCancellation(ctx, tripID)
+	assert.NoError(t, err)
+	assert.Equal(t, model.TripCancellationStatusCompleted, progress.Status)
+	assert.Equal(t, 1, progress.PassengersNotified)
+	assert.Equal(t, 0, progress.FailedBookings)
+}
+
+func TestGetCancellation_NotCancelled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, m := newTestTripCancellationService(ctrl)
+
+	ctx := context.Background()
+	tripID := uuid.New()
+
+	m.cancellationRepo.EXPECT().GetLatestByTripID(ctx, tripID).Return(nil, gorm.ErrRecordNotFound).Times(1)
+
+	progress, err := service.GetCancellation(ctx, tripID)
+	assert.Error(t, err)
+	assert.Nil(t, progress)
+	assert.Contains(t, err.Error(), "not been cancelled")
+}
+
+func TestAbortCancellation_RestoresTrip(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, m := newTestTripCancellationService(ctrl)
+
+	ctx := context.Background()
+	tripID := uuid.New()
+
+	// Bookings are loaded but none of them has been touched yet
+	saga := newRunningSaga(tripID, model.TripCancellationBooking{
+		ID:        uuid.New(),
+		BookingID: uuid.New(),
+		Status:    model.TripCancellationStatusRunning,
+	})
+
+	m.cancellationRepo.EXPECT().GetLatestByTripID(ctx, tripID).Return(saga, nil).Times(1)
+	m.expectLease(saga.ID)
+	m.cancellationRepo.EXPECT().GetByID(ctx, saga.ID).Return(saga, nil).Times(1)
+	m.cancellationRepo.EXPECT().Abort(ctx, saga).
+		DoAndReturn(func(_ context.Context, s *model.TripCancellationSaga) error {
+			s.Status = model.TripCancellationStatusAborted
+			return nil
+		}).Times(1)
+	m.cache.EXPECT().InvalidateAllFareCalendars(ctx).Return(nil).Times(1)
+
+	progress, err := service.AbortCancellation(ctx, tripID)
+	assert.NoError(t, err)
+	assert.Equal(t, model.TripCancellationStatusAborted, progress.Status)
+}
+
+func TestAbortCancellation_BookingsAlreadyCancelled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service, m := newTestTripCancellationService(ctrl)
+
+	ctx := context.Background()
+	tripID := uuid.New()
+	now := time.Now()
+
+	saga := newRunningSaga(tripID, model.TripCancellationBooking{
+		ID:                 uuid.New(),
+		BookingID:          uuid.New(),
+		BookingCancelledAt: &now,
+		Status:             model.TripCancellationStatusRunning,
+	})
+
+	m.cancellationRepo.EXPECT().GetLatestByTripID(ctx, tripID).Return(saga, nil).Times(1)
+	m.expectLease(saga.ID)
+	m.cancellationRepo.EXPECT().GetByID(ctx, saga.ID).Return(saga, nil).Times(1)
+	m.cancellationRepo.EXPECT().Abort(gomock.Any(), gomock.Any()).Times(0)
+
+	progress, err := service.AbortCancellation(ctx, tripID)
+	assert.Error(t, err)
+	assert.Nil(t, progress)
+	assert.Contains(t, err.Error(), "cannot be restored")
+}
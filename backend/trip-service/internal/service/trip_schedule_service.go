@@ -0,0 +1,409 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"bus-booking/shared/ginext"
+	"bus-booking/trip-service/internal/constants"
+	"bus-booking/trip-service/internal/model"
+	"bus-booking/trip-service/internal/repository"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+type TripScheduleService interface {
+	CreateSchedule(ctx context.Context, req *model.CreateTripScheduleRequest) (*model.TripSchedule, error)
+	GetScheduleByID(ctx context.Context, id uuid.UUID) (*model.TripSchedule, error)
+	ListSchedules(ctx context.Context, req *model.ListTripSchedulesRequest) ([]model.TripSchedule, int64, error)
+	UpdateSchedule(ctx context.Context, id uuid.UUID, req *model.UpdateTripScheduleRequest) (*model.TripSchedule, error)
+	DeleteSchedule(ctx context.Context, id uuid.UUID) error
+
+	// GenerateTrips materializes trips for every active schedule over the next daysAhead days
+	GenerateTrips(ctx context.Context, daysAhead int) (*model.GenerateTripsResult, error)
+}
+
+type TripScheduleServiceImpl struct {
+	scheduleRepo repository.TripScheduleRepository
+	tripRepo     repository.TripRepository
+	routeRepo    repository.RouteRepository
+	busRepo      repository.BusRepository
+	cache        CacheService
+	location     *time.Location
+	now          func() time.Time
+}
+
+func NewTripScheduleService(
+	scheduleRepo repository.TripScheduleRepository,
+	tripRepo repository.TripRepository,
+	routeRepo repository.RouteRepository,
+	busRepo repository.BusRepository,
+	cache CacheService,
+) TripScheduleService {
+	return &TripScheduleServiceImpl{
+		scheduleRepo: scheduleRepo,
+		tripRepo:     tripRepo,
+		routeRepo:    routeRepo,
+		busRepo:      busRepo,
+		cache:        cache,
+		location:     scheduleLocation(),
+		now:          time.Now,
+	}
+}
+
+// scheduleLocation returns the zone departure dates and times are expressed in
+func scheduleLocation() *time.Location {
+	location, err := time.LoadLocation(constants.ScheduleTimeZone)
+	if err != nil {
+		log.Warn().Err(err).Str("zone", constants.ScheduleTimeZone).Msg("Failed to load schedule time zone, using UTC+7")
+		location = time.FixedZone(constants.ScheduleTimeZone, 7*60*60)
+	}
+	return location
+}
+
+func (s *TripScheduleServiceImpl) CreateSchedule(ctx context.Context, req *model.CreateTripScheduleRequest) (*model.TripSchedule, error) {
+	if _, err := s.routeRepo.GetRouteByID(ctx, req.RouteID); err != nil {
+		return nil, ginext.NewBadRequestError("invalid route")
+	}
+
+	if err := s.validateBus(ctx, req.BusID); err != nil {
+		return nil, err
+	}
+
+	validFrom, err := s.parseDate(req.ValidFrom, "valid_from")
+	if err != nil {
+		return nil, err
+	}
+
+	schedule := &model.TripSchedule{
+		RouteID:       req.RouteID,
+		BusID:         req.BusID,
+		DepartureTime: req.DepartureTime,
+		ValidFrom:     validFrom,
+		BasePrice:     req.BasePrice,
+		IsActive:      true,
+	}
+
+	if req.ValidUntil != nil {
+		validUntil, err := s.parseDate(*req.ValidUntil, "valid_until")
+		if err != nil {
+			return nil, err
+		}
+		schedule.ValidUntil = &validUntil
+	}
+
+	weekdays, err := toWeekdays(req.Weekdays)
+	if err != nil {
+		return nil, err
+	}
+	schedule.Weekdays = weekdays
+
+	if err := validateSchedule(schedule); err != nil {
+		return nil, err
+	}
+
+	if err := s.scheduleRepo.Create(ctx, schedule); err != nil {
+		log.Error().Err(err).Msg("Failed to create trip schedule")
+		return nil, ginext.NewInternalServerError("failed to create trip schedule")
+	}
+
+	return s.GetScheduleByID(ctx, schedule.ID)
+}
+
+func (s *TripScheduleServiceImpl) GetScheduleByID(ctx context.Context, id uuid.UUID) (*model.TripSchedule, error) {
+	schedule, err := s.scheduleRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ginext.NewNotFoundError("trip schedule not found")
+		}
+		return nil, ginext.NewInternalServerError("failed to get trip schedule")
+	}
+	return schedule, nil
+}
+
+func (s *TripScheduleServiceImpl) ListSchedules(ctx context.Context, req *model.ListTripSchedulesRequest) ([]model.TripSchedule, int64, error) {
+	req.Normalize()
+
+	var routeID *uuid.UUID
+	if req.RouteID != nil && *req.RouteID != "" {
+		id, err := uuid.Parse(*req.RouteID)
+		if err != nil {
+			return nil, 0, ginext.NewBadRequestError("invalid route_id")
+		}
+		routeID = &id
+	}
+
+	schedules, total, err := s.scheduleRepo.List(ctx, routeID, req.Page, req.PageSize)
+	if err != nil {
+		return nil, 0, ginext.NewInternalServerError("failed to list trip schedules")
+	}
+	return schedules, total, nil
+}
+
+// UpdateSchedule only affects trips generated after the change; trips that
+// already exist keep their original time, bus and price.
+func (s *TripScheduleServiceImpl) UpdateSchedule(ctx context.Context, id uuid.UUID, req *model.UpdateTripScheduleRequest) (*model.TripSchedule, error) {
+	schedule, err := s.GetScheduleByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.BusID != nil {
+		if err := s.validateBus(ctx, *req.BusID); err != nil {
+			return nil, err
+		}
+		schedule.BusID = *req.BusID
+		schedule.Bus = nil
+	}
+
+	if req.DepartureTime != nil {
+		schedule.DepartureTime = *req.DepartureTime
+	}
+
+	if req.Weekdays != nil {
+		weekdays, err := toWeekdays(req.Weekdays)
+		if err != nil {
+			return nil, err
+		}
+		schedule.Weekdays = weekdays
+	}
+
+	if req.ValidFrom != nil {
+		validFrom, err := s.parseDate(*req.ValidFrom, "valid_from")
+		if err != nil {
+			return nil, err
+		}
+		schedule.ValidFrom = validFrom
+	}
+
+	if req.ValidUntil != nil {
+		if *req.ValidUntil == "" {
+			schedule.ValidUntil = nil
+		} else {
+			validUntil, err := s.parseDate(*req.ValidUntil, "valid_until")
+			if err != nil {
+				return nil, err
+			}
+			schedule.ValidUntil = &validUntil
+		}
+	}
+
+	if req.BasePrice != nil {
+		schedule.BasePrice = *req.BasePrice
+	}
+
+	if req.IsActive != nil {
+		schedule.IsActive = *req.IsActive
+	}
+
+	if err := validateSchedule(schedule); err != nil {
+		return nil, err
+	}
+
+	if err := s.scheduleRepo.Update(ctx, schedule); err != nil {
+		log.Error().Err(err).Str("schedule_id", id.String()).Msg("Failed to update trip schedule")
+		return nil, ginext.NewInternalServerError("failed to update trip schedule")
+	}
+
+	return s.GetScheduleByID(ctx, id)
+}
+
+// DeleteSchedule stops future generation; trips already generated are kept
+func (s *TripScheduleServiceImpl) DeleteSchedule(ctx context.Context, id uuid.UUID) error {
+	if _, err := s.GetScheduleByID(ctx, id); err != nil {
+		return err
+	}
+
+	if err := s.scheduleRepo.Delete(ctx, id); err != nil {
+		return ginext.NewInternalServerError("failed to delete trip schedule")
+	}
+	return nil
+}
+
+// GenerateTrips creates the missing trips of every active schedule between now and
+// daysAhead days from now. Trips are never modified: departures that already exist
+// are skipped, and departures whose bus is busy with another trip are reported as
+// conflicts and left for an operator to resolve.
+func (s *TripScheduleServiceImpl) GenerateTrips(ctx context.Context, daysAhead int) (*model.GenerateTripsResult, error) {
+	if daysAhead < 1 {
+		return nil, ginext.NewBadRequestError("days_ahead must be at least 1")
+	}
+
+	from := s.now().In(s.location)
+	to := from.AddDate(0, 0, daysAhead)
+
+	schedules, err := s.scheduleRepo.ListActive(ctx, dateOf(from), to)
+	if err != nil {
+		return nil, ginext.NewInternalServerError("failed to list active trip schedules")
+	}
+
+	result := &model.GenerateTripsResult{Schedules: len(schedules)}
+	var created []time.Time
+	for i := range schedules {
+		created = append(created, s.generateScheduleTrips(ctx, &schedules[i], from, to, result)...)
+	}
+	invalidateFareCalendars(ctx, s.cache, created...)
+
+	log.Info().
+		Int("schedules", result.Schedules).
+		Int("created", result.Created).
+		Int("existing", result.Existing).
+		Int("conflicts", result.Conflicts).
+		Int("failed", result.Failed).
+		Msg("Generated trips from schedules")
+
+	return result, nil
+}
+
+// generateScheduleTrips creates the missing trips of the schedule and returns their departures
+func (s *TripScheduleServiceImpl) generateScheduleTrips(ctx context.Context, schedule *model.TripSchedule, from, to time.Time, result *model.GenerateTripsResult) []time.Time {
+	logger := log.With().Str("schedule_id", schedule.ID.String()).Logger()
+
+	if schedule.Route == nil || !schedule.Route.IsActive {
+		logger.Debug().Msg("Skipping schedule with inactive route")
+		return nil
+	}
+	if schedule.Bus == nil || !schedule.Bus.IsActive {
+		logger.Debug().Msg("Skipping schedule with inactive bus")
+		return nil
+	}
+
+	departures, err := schedule.DeparturesBetween(from, to, s.location)
+	if err != nil {
+		logger.Error().Err(err).Str("departure_time", schedule.DepartureTime).Msg("Invalid schedule departure time")
+		result.Failed++
+		return nil
+	}
+	if len(departures) == 0 {
+		return nil
+	}
+
+	duration := time.Duration(schedule.Route.EstimatedMinutes) * time.Minute
+	lastArrival := departures[len(departures)-1].Add(duration)
+
+	busTrips, err := s.tripRepo.GetTripsByBusAndDateRange(ctx, schedule.BusID,
+		departures[0].Add(-constants.ScheduleConflictLookback), lastArrival)
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to load bus trips")
+		result.Failed += len(departures)
+		return nil
+	}
+
+	var created []time.Time
+	for _, departure := range departures {
+		arrival := departure.Add(duration)
+
+		existing, conflict := findBusTrip(busTrips, schedule.RouteID, departure, arrival)
+		if existing != nil {
+			result.Existing++
+			continue
+		}
+		if conflict != nil {
+			logger.Warn().
+				Str("bus_id", schedule.BusID.String()).
+				Str("conflicting_trip_id", conflict.ID.String()).
+				Time("departure_time", departure).
+				Msg("Bus is busy, skipping scheduled departure")
+			result.Conflicts++
+			continue
+		}
+
+		scheduleID := schedule.ID
+		trip := model.Trip{
+			RouteID:       schedule.RouteID,
+			BusID:         schedule.BusID,
+			DepartureTime: departure,
+			ArrivalTime:   arrival,
+			BasePrice:     schedule.BasePrice,
+			Status:        constants.TripStatusScheduled,
+			IsActive:      true,
+			ScheduleID:    &scheduleID,
+		}
+
+		if err := s.tripRepo.CreateTrip(ctx, &trip); err != nil {
+			logger.Error().Err(err).Time("departure_time", departure).Msg("Failed to create scheduled trip")
+			result.Failed++
+			continue
+		}
+
+		busTrips = append(busTrips, trip)
+		created = append(created, departure)
+		result.Created++
+	}
+	return created
+}
+
+// findBusTrip returns the bus trip that already serves this departure, or else
+// the first bus trip overlapping [departure, arrival]
+func findBusTrip(trips []model.Trip, routeID uuid.UUID, departure, arrival time.Time) (existing, conflict *model.Trip) {
+	for i := range trips {
+		trip := &trips[i]
+		if trip.RouteID == routeID && trip.DepartureTime.Equal(departure) {
+			return trip, nil
+		}
+		if conflict == nil && arrival.After(trip.DepartureTime) && departure.Before(trip.ArrivalTime) {
+			conflict = trip
+		}
+	}
+	return nil, conflict
+}
+
+func (s *TripScheduleServiceImpl) validateBus(ctx context.Context, busID uuid.UUID) error {
+	bus, err := s.busRepo.GetBusByID(ctx, busID)
+	if err != nil {
+		return ginext.NewBadRequestError("invalid bus")
+	}
+	if !bus.IsActive {
+		return ginext.NewBadRequestError("bus is not active")
+	}
+	return nil
+}
+
+func (s *TripScheduleServiceImpl) parseDate(value, field string) (time.Time, error) {
+	date, err := time.ParseInLocation(constants.ScheduleDateFormat, value, s.location)
+	if err != nil {
+		return time.Time{}, ginext.NewBadRequestError(field + " must be in YYYY-MM-DD format")
+	}
+	return date, nil
+}
+
+func validateSchedule(schedule *model.TripSchedule) error {
+	if _, err := time.Parse(constants.ScheduleTimeFormat, schedule.DepartureTime); err != nil || len(schedule.DepartureTime) != len(constants.ScheduleTimeFormat) {
+		return ginext.NewBadRequestError("departure_time must be in HH:MM format")
+	}
+	if schedule.ValidUntil != nil && schedule.ValidUntil.Before(schedule.ValidFrom) {
+		return ginext.NewBadRequestError("valid_until must not be before valid_from")
+	}
+	if schedule.BasePrice < 0 {
+		return ginext.NewBadRequestError("base price must be non-negative")
+	}
+	return nil
+}
+
+func toWeekdays(days []int) (pq.Int64Array, error) {
+	if len(days) == 0 {
+		return nil, ginext.NewBadRequestError("weekdays must not be empty")
+	}
+
+	seen := make(map[int]bool, len(days))
+	weekdays := make(pq.Int64Array, 0, len(days))
+	for _, d := range days {
+		if d < int(time.Sunday) || d > int(time.Saturday) {
+			return nil, ginext.NewBadRequestError("weekdays must be between 0 (Sunday) and 6 (Saturday)")
+		}
+		if seen[d] {
+			continue
+		}
+		seen[d] = true
+		weekdays = append(weekdays, int64(d))
+	}
+	return weekdays, nil
+}
+
+func dateOf(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
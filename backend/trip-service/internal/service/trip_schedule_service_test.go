@@ -0,0 +1,371 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"bus-booking/trip-service/internal/constants"
+	"bus-booking/trip-service/internal/model"
+	repo_mocks "bus-booking/trip-service/internal/repository/mocks"
+	service_mocks "bus-booking/trip-service/internal/service/mocks"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+type scheduleTestFixture struct {
+	service      *TripScheduleServiceImpl
+	scheduleRepo *repo_mocks.MockTripScheduleRepository
+	tripRepo     *repo_mocks.MockTripRepository
+	routeRepo    *repo_mocks.MockRouteRepository
+	busRepo      *repo_mocks.MockBusRepository
+	cache        *service_mocks.MockCacheService
+	location     *time.Location
+}
+
+// newScheduleTestFixture pins the clock to Monday 2025-06-02 06:00 in the schedule zone
+func newScheduleTestFixture(ctrl *gomock.Controller) *scheduleTestFixture {
+	f := &scheduleTestFixture{
+		scheduleRepo: repo_mocks.NewMockTripScheduleRepository(ctrl),
+		tripRepo:     repo_mocks.NewMockTripRepository(ctrl),
+		routeRepo:    repo_mocks.NewMockRouteRepository(ctrl),
+		busRepo:      repo_mocks.NewMockBusRepository(ctrl),
+		cache:        service_mocks.NewMockCacheService(ctrl),
+	}
+
+	f.service = NewTripScheduleService(f.scheduleRepo, f.tripRepo, f.routeRepo, f.busRepo, f.cache).(*TripScheduleServiceImpl)
+	f.location = f.service.location
+	f.service.now = func() time.Time {
+		return time.Date(2025, 6, 2, 6, 0, 0, 0, f.location)
+	}
+	return f
+}
+
+func (f *scheduleTestFixture) schedule(weekdays ...int64) model.TripSchedule {
+	return model.TripSchedule{
+		BaseModel:     model.BaseModel{ID: uuid.New()},
+		RouteID:       uuid.New(),
+		BusID:         uuid.New(),
+		DepartureTime: "08:30",
+		Weekdays:      pq.Int64Array(weekdays),
+		ValidFrom:     time.Date(2025, 1, 1, 0, 0, 0, 0, f.location),
+		BasePrice:     250000,
+		IsActive:      true,
+		Route:         &model.Route{IsActive: true, EstimatedMinutes: 300},
+		Bus:           &model.Bus{IsActive: true},
+	}
+}
+
+func TestNewTripScheduleService(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	f := newScheduleTestFixture(ctrl)
+
+	assert.NotNil(t, f.service)
+	assert.NotNil(t, f.service.location)
+}
+
+func TestTripSchedule_DeparturesBetween(t *testing.T) {
+	loc := time.FixedZone(constants.ScheduleTimeZone, 7*60*60)
+	until := time.Date(2025, 6, 6, 0, 0, 0, 0, loc)
+	schedule := model.TripSchedule{
+		DepartureTime: "22:15",
+		Weekdays:      pq.Int64Array{int64(time.Monday), int64(time.Wednesday), int64(time.Friday), int64(time.Saturday)},
+		ValidFrom:     time.Date(2025, 6, 2, 0, 0, 0, 0, loc),
+		ValidUntil:    &until,
+	}
+
+	// Window starts after Monday's departure, so Monday is excluded; Saturday is past valid_until
+	from := time.Date(2025, 6, 2, 23, 0, 0, 0, loc)
+	to := from.AddDate(0, 0, 14)
+
+	departures, err := schedule.DeparturesBetween(from, to, loc)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []time.Time{
+		time.Date(2025, 6, 4, 22, 15, 0, 0, loc),
+		time.Date(2025, 6, 6, 22, 15, 0, 0, loc),
+	}, departures)
+}
+
+func TestCreateSchedule_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	f := newScheduleTestFixture(ctrl)
+	ctx := context.Background()
+	routeID := uuid.New()
+	busID := uuid.New()
+	validUntil := "2025-12-31"
+
+	req := &model.CreateTripScheduleRequest{
+		RouteID:       routeID,
+		BusID:         busID,
+		DepartureTime: "07:00",
+		Weekdays:      []int{1, 3, 5, 3},
+		ValidFrom:     "2025-06-01",
+		ValidUntil:    &validUntil,
+		BasePrice:     300000,
+	}
+
+	f.routeRepo.EXPECT().GetRouteByID(ctx, routeID).Return(&model.Route{}, nil)
+	f.busRepo.EXPECT().GetBusByID(ctx, busID).Return(&model.Bus{IsActive: true}, nil)
+	f.scheduleRepo.EXPECT().Create(ctx, gomock.Any()).DoAndReturn(
+		func(_ context.Context, schedule *model.TripSchedule) error {
+			assert.Equal(t, pq.Int64Array{1, 3, 5}, schedule.Weekdays)
+			assert.Equal(t, "2025-06-01", schedule.ValidFrom.Format(constants.ScheduleDateFormat))
+			assert.Equal(t, validUntil, schedule.ValidUntil.Format(constants.ScheduleDateFormat))
+			assert.True(t, schedule.IsActive)
+			schedule.ID = uuid.New()
+			return nil
+		})
+	f.scheduleRepo.EXPECT().GetByID(ctx, gomock.Any()).Return(&model.TripSchedule{RouteID: routeID}, nil)
+
+	schedule, err := f.service.CreateSchedule(ctx, req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, routeID, schedule.RouteID)
+}
+
+func TestCreateSchedule_InvalidInput(t *testing.T) {
+	validUntil := "2025-05-01"
+
+	tests := []struct {
+		name    string
+		mutate  func(req *model.CreateTripScheduleRequest)
+		wantErr string
+	}{
+		{"bad departure time", func(req *model.CreateTripScheduleRequest) { req.DepartureTime = "7:00" }, "HH:MM"},
+		{"empty weekdays", func(req *model.CreateTripScheduleRequest) { req.Weekdays = nil }, "weekdays must not be empty"},
+		{"weekday out of range", func(req *model.CreateTripScheduleRequest) { req.Weekdays = []int{7} }, "between 0 (Sunday) and 6 (Saturday)"},
+		{"bad valid_from", func(req *model.CreateTripScheduleRequest) { req.ValidFrom = "01/06/2025" }, "valid_from must be in YYYY-MM-DD"},
+		{"valid_until before valid_from", func(req *model.CreateTripScheduleRequest) { req.ValidUntil = &validUntil }, "valid_until must not be before valid_from"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			f := newScheduleTestFixture(ctrl)
+			ctx := context.Background()
+
+			req := &model.CreateTripScheduleRequest{
+				RouteID:       uuid.New(),
+				BusID:         uuid.New(),
+				DepartureTime: "07:00",
+				Weekdays:      []int{1},
+				ValidFrom:     "2025-06-01",
+				BasePrice:     300000,
+			}
+			tt.mutate(req)
+
+			f.routeRepo.EXPECT().GetRouteByID(ctx, req.RouteID).Return(&model.Route{}, nil)
+			f.busRepo.EXPECT().GetBusByID(ctx, req.BusID).Return(&model.Bus{IsActive: true}, nil)
+
+			schedule, err := f.service.CreateSchedule(ctx, req)
+
+			assert.Nil(t, schedule)
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestCreateSchedule_InactiveBus(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	f := newScheduleTestFixture(ctrl)
+	ctx := context.Background()
+
+	req := &model.CreateTripScheduleRequest{
+		RouteID:       uuid.New(),
+		BusID:         uuid.New(),
+		DepartureTime: "07:00",
+		Weekdays:      []int{1},
+		ValidFrom:     "2025-06-01",
+	}
+
+	f.routeRepo.EXPECT().GetRouteByID(ctx, req.RouteID).Return(&model.Route{}, nil)
+	f.busRepo.EXPECT().GetBusByID(ctx, req.BusID).Return(&model.Bus{IsActive: false}, nil)
+
+	_, err := f.service.CreateSchedule(ctx, req)
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "bus is not active")
+}
+
+func TestGetScheduleByID_NotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	f := newScheduleTestFixture(ctrl)
+	ctx := context.Background()
+	id := uuid.New()
+
+	f.scheduleRepo.EXPECT().GetByID(ctx, id).Return(nil, gorm.ErrRecordNotFound)
+
+	_, err := f.service.GetScheduleByID(ctx, id)
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "trip schedule not found")
+}
+
+func TestUpdateSchedule_ClearsValidUntil(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	f := newScheduleTestFixture(ctrl)
+	ctx := context.Background()
+	existing := f.schedule(1)
+	until := time.Date(2025, 7, 1, 0, 0, 0, 0, f.location)
+	existing.ValidUntil = &until
+
+	empty := ""
+	departure := "09:45"
+	req := &model.UpdateTripScheduleRequest{ValidUntil: &empty, DepartureTime: &departure}
+
+	f.scheduleRepo.EXPECT().GetByID(ctx, existing.ID).Return(&existing, nil).Times(2)
+	f.scheduleRepo.EXPECT().Update(ctx, gomock.Any()).DoAndReturn(
+		func(_ context.Context, schedule *model.TripSchedule) error {
+			assert.Nil(t, schedule.ValidUntil)
+			assert.Equal(t, "09:45", schedule.DepartureTime)
+			return nil
+		})
+
+	_, err := f.service.UpdateSchedule(ctx, existing.ID, req)
+
+	assert.NoError(t, err)
+}
+
+func TestGenerateTrips_CreatesUpcomingTrips(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	f := newScheduleTestFixture(ctrl)
+	ctx := context.Background()
+	schedule := f.schedule(int64(time.Monday), int64(time.Thursday))
+
+	f.scheduleRepo.EXPECT().ListActive(ctx, gomock.Any(), gomock.Any()).Return([]model.TripSchedule{schedule}, nil)
+	f.tripRepo.EXPECT().GetTripsByBusAndDateRange(ctx, schedule.BusID, gomock.Any(), gomock.Any()).Return(nil, nil)
+
+	var created []model.Trip
+	f.tripRepo.EXPECT().CreateTrip(ctx, gomock.Any()).DoAndReturn(
+		func(_ context.Context, trip *model.Trip) error {
+			created = append(created, *trip)
+			return nil
+		}).Times(2)
+	f.cache.EXPECT().InvalidateFareCalendars(ctx, []string{"2025-06"}).Return(nil).Times(1)
+
+	result, err := f.service.GenerateTrips(ctx, 7)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, result.Created)
+	assert.Equal(t, 1, result.Schedules)
+
+	// Monday 2 June 08:30 and Thursday 5 June 08:30; next Monday 9 June 08:30 is past the window
+	if assert.Len(t, created, 2) {
+		assert.Equal(t, time.Date(2025, 6, 2, 8, 30, 0, 0, f.location), created[0].DepartureTime)
+		assert.Equal(t, time.Date(2025, 6, 5, 8, 30, 0, 0, f.location), created[1].DepartureTime)
+		assert.Equal(t, created[1].DepartureTime.Add(300*time.Minute), created[1].ArrivalTime)
+		assert.Equal(t, &schedule.ID, created[0].ScheduleID)
+		assert.Equal(t, schedule.BasePrice, created[0].BasePrice)
+		assert.Equal(t, constants.TripStatusScheduled, created[0].Status)
+	}
+}
+
+func TestGenerateTrips_SkipsExistingAndConflictingDepartures(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	f := newScheduleTestFixture(ctrl)
+	ctx := context.Background()
+	schedule := f.schedule(int64(time.Monday), int64(time.Tuesday), int64(time.Wednesday))
+
+	busTrips := []model.Trip{
+		// Monday's departure was generated by an earlier run
+		{
+			BaseModel:     model.BaseModel{ID: uuid.New()},
+			RouteID:       schedule.RouteID,
+			DepartureTime: time.Date(2025, 6, 2, 8, 30, 0, 0, f.location),
+			ArrivalTime:   time.Date(2025, 6, 2, 13, 30, 0, 0, f.location),
+		},
+		// The bus is still on another route on Tuesday morning
+		{
+			BaseModel:     model.BaseModel{ID: uuid.New()},
+			RouteID:       uuid.New(),
+			DepartureTime: time.Date(2025, 6, 3, 5, 0, 0, 0, f.location),
+			ArrivalTime:   time.Date(2025, 6, 3, 10, 0, 0, 0, f.location),
+		},
+	}
+
+	f.scheduleRepo.EXPECT().ListActive(ctx, gomock.Any(), gomock.Any()).Return([]model.TripSchedule{schedule}, nil)
+	f.tripRepo.EXPECT().GetTripsByBusAndDateRange(ctx, schedule.BusID, gomock.Any(), gomock.Any()).Return(busTrips, nil)
+	f.tripRepo.EXPECT().CreateTrip(ctx, gomock.Any()).DoAndReturn(
+		func(_ context.Context, trip *model.Trip) error {
+			assert.Equal(t, time.Date(2025, 6, 4, 8, 30, 0, 0, f.location), trip.DepartureTime)
+			return nil
+		}).Times(1)
+	f.cache.EXPECT().InvalidateFareCalendars(ctx, []string{"2025-06"}).Return(nil).Times(1)
+
+	result, err := f.service.GenerateTrips(ctx, 3)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, result.Created)
+	assert.Equal(t, 1, result.Existing)
+	assert.Equal(t, 1, result.Conflicts)
+}
+
+func TestGenerateTrips_SkipsInactiveBus(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	f := newScheduleTestFixture(ctrl)
+	ctx := context.Background()
+	schedule := f.schedule(int64(time.Monday))
+	schedule.Bus.IsActive = false
+
+	f.scheduleRepo.EXPECT().ListActive(ctx, gomock.Any(), gomock.Any()).Return([]model.TripSchedule{schedule}, nil)
+
+	result, err := f.service.GenerateTrips(ctx, 7)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 0, result.Created)
+}
+
+func TestGenerateTrips_CountsFailures(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	f := newScheduleTestFixture(ctrl)
+	ctx := context.Background()
+	schedule := f.schedule(int64(time.Monday))
+
+	f.scheduleRepo.EXPECT().ListActive(ctx, gomock.Any(), gomock.Any()).Return([]model.TripSchedule{schedule}, nil)
+	f.tripRepo.EXPECT().GetTripsByBusAndDateRange(ctx, schedule.BusID, gomock.Any(), gomock.Any()).Return(nil, nil)
+	f.tripRepo.EXPECT().CreateTrip(ctx, gomock.Any()).Return(errors.New("duplicate key"))
+
+	result, err := f.service.GenerateTrips(ctx, 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 0, result.Created)
+	assert.Equal(t, 1, result.Failed)
+}
+
+func TestGenerateTrips_InvalidDaysAhead(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	f := newScheduleTestFixture(ctrl)
+
+	_, err := f.service.GenerateTrips(context.Background(), 0)
+
+	assert.Error(t, err)
+}
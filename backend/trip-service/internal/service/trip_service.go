@@ -7,10 +7,8 @@ import (
 
 	"bus-booking/shared/ginext"
 	"bus-booking/trip-service/internal/client"
-	"bus-booking/trip-service/internal/constants"
 	"bus-booking/trip-service/internal/model"
 	"bus-booking/trip-service/internal/model/booking"
-	"bus-booking/trip-service/internal/model/payment"
 	"bus-booking/trip-service/internal/repository"
 
 	"github.com/google/uuid"
@@ -23,13 +21,10 @@ type TripService interface {
 	ListTrips(ctx context.Context, req *model.ListTripsRequest) ([]model.Trip, int64, error)
 	GetSeatAvailability(ctx context.Context, tripID uuid.UUID) (*model.SeatAvailabilityResponse, error)
 	GetTripsByRouteAndDate(ctx context.Context, routeID uuid.UUID, departureDate time.Time) ([]model.Trip, error)
-	GetCompletedTripsForReschedule(ctx context.Context) ([]model.Trip, error)
 
 	CreateTrip(ctx context.Context, req *model.CreateTripRequest) (*model.Trip, error)
 	UpdateTrip(ctx context.Context, id uuid.UUID, req *model.UpdateTripRequest) (*model.Trip, error)
 	DeleteTrip(ctx context.Context, id uuid.UUID) error
-	RescheduleTrip(ctx context.Context, id uuid.UUID, newDeparture, newArrival time.Time) error
-	CancelTrip(ctx context.Context, id uuid.UUID) error
 	ProcessTripStatusUpdates(ctx context.Context) error
 }
 
@@ -40,7 +35,8 @@ type TripServiceImpl struct {
 	busRepo       repository.BusRepository
 	seatRepo      repository.SeatRepository
 	bookingClient client.BookingClient
-	paymentClient client.PaymentClient
+	pricing       PricingService
+	cache         CacheService
 }
 
 func NewTripService(
@@ -50,7 +46,8 @@ func NewTripService(
 	busRepo repository.BusRepository,
 	seatRepo repository.SeatRepository,
 	bookingClient client.BookingClient,
-	paymentClient client.PaymentClient,
+	pricing PricingService,
+	cache CacheService,
 ) TripService {
 	return &TripServiceImpl{
 		tripRepo:      tripRepo,
@@ -59,7 +56,8 @@ func NewTripService(
 		busRepo:       busRepo,
 		seatRepo:      seatRepo,
 		bookingClient: bookingClient,
-		paymentClient: paymentClient,
+		pricing:       pricing,
+		cache:         cache,
 	}
 }
 
@@ -70,9 +68,56 @@ func (s *TripServiceImpl) SearchTrips(ctx context.Context, req *model.TripSearch
 		return nil, 0, ginext.NewInternalServerError("failed to search trips")
 	}
 
+	s.quotePriceTiers(ctx, trips)
 	return trips, total, nil
 }
 
+// quotePriceTiers prices the seat tiers of search results at the current fares. Results are
+// still returned without tiers when pricing fails.
+func (s *TripServiceImpl) quotePriceTiers(ctx context.Context, details []model.TripDetail) {
+	if len(details) == 0 {
+		return
+	}
+
+	fares, err := s.pricing.QuoteTrips(ctx, detailTrips(details))
+	if err != nil {
+		log.Warn().Err(err).Msg("Failed to quote fares of search results")
+		return
+	}
+	applyPriceTiers(details, fares)
+}
+
+// detailTrips returns the trip fields pricing needs from search results
+func detailTrips(details []model.TripDetail) []model.Trip {
+	trips := make([]model.Trip, len(details))
+	for i, detail := range details {
+		trips[i] = model.Trip{
+			BaseModel:     model.BaseModel{ID: detail.ID},
+			RouteID:       detail.RouteID,
+			BusID:         detail.BusID,
+			DepartureTime: detail.DepartureTime,
+			BasePrice:     detail.BasePrice,
+		}
+	}
+	return trips
+}
+
+// applyPriceTiers sets the price tiers of the search results that were quoted
+func applyPriceTiers(details []model.TripDetail, fares map[uuid.UUID]*model.TripFare) {
+	for i := range details {
+		fare, ok := fares[details[i].ID]
+		if !ok {
+			continue
+		}
+
+		// Segments are charged the fare prorated by distance, like their base price
+		details[i].PriceTiers = fare.PriceTiers
+		if segment, route := details[i].Segment, details[i].Route; segment != nil && route != nil && route.DistanceKm > 0 {
+			details[i].PriceTiers = fare.TiersAt(model.SegmentBasePrice(fare.Fare, segment.DistanceKm/route.DistanceKm))
+		}
+	}
+}
+
 func (s *TripServiceImpl) GetTripByID(ctx context.Context, req *model.GetTripByIDRequest, id uuid.UUID) (*model.Trip, error) {
 	trip, err := s.tripRepo.GetTripByID(ctx, req, id)
 	if err != nil {
@@ -85,7 +130,12 @@ func (s *TripServiceImpl) GetTripByID(ctx context.Context, req *model.GetTripByI
 			seatIDs[i] = seat.ID
 		}
 
-		seatStatuses, err := s.bookingClient.GetSeatStatus(ctx, trip.ID, seatIDs)
+		segment, err := s.resolveSegment(ctx, trip, req.PickupStopID, req.DropoffStopID)
+		if err != nil {
+			return nil, err
+		}
+
+		seatStatuses, err := s.bookingClient.GetSeatStatus(ctx, trip.ID, seatIDs, segment)
 		if err != nil {
 			log.Error().Err(err).Msg("Failed to check seat status from booking service")
 			return nil, ginext.NewInternalServerError("failed to get seat status")
@@ -112,6 +162,57 @@ func (s *TripServiceImpl) GetTripByID(ctx context.Context, req *model.GetTripByI
 	return trip, nil
 }
 
+// resolveSegment converts pickup/dropoff stop IDs into the stop order range used by booking service.
+// It returns nil when no segment is requested, meaning the whole trip.
+func (s *TripServiceImpl) resolveSegment(ctx context.Context, trip *model.Trip, pickupStopID, dropoffStopID string) (*booking.TripSegment, error) {
+	if pickupStopID == "" && dropoffStopID == "" {
+		return nil, nil
+	}
+	if pickupStopID == "" || dropoffStopID == "" {
+		return nil, ginext.NewBadRequestError("pickup_stop_id and dropoff_stop_id must be provided together")
+	}
+
+	pickupID, err := uuid.Parse(pickupStopID)
+	if err != nil {
+		return nil, ginext.NewBadRequestError("invalid pickup stop ID")
+	}
+	dropoffID, err := uuid.Parse(dropoffStopID)
+	if err != nil {
+		return nil, ginext.NewBadRequestError("invalid dropoff stop ID")
+	}
+
+	stops, err := s.routeStopRepo.ListByRouteID(ctx, trip.RouteID)
+	if err != nil {
+		log.Error().Err(err).Str("route_id", trip.RouteID.String()).Msg("Failed to list route stops")
+		return nil, ginext.NewInternalServerError("failed to get route stops")
+	}
+
+	var pickup, dropoff *model.RouteStop
+	for i := range stops {
+		switch stops[i].ID {
+		case pickupID:
+			pickup = &stops[i]
+		case dropoffID:
+			dropoff = &stops[i]
+		}
+	}
+
+	if pickup == nil || !pickup.CanPickup() {
+		return nil, ginext.NewBadRequestError("pickup stop is not a boarding point of this trip")
+	}
+	if dropoff == nil || !dropoff.CanDropoff() {
+		return nil, ginext.NewBadRequestError("dropoff stop is not an alighting point of this trip")
+	}
+	if pickup.StopOrder >= dropoff.StopOrder {
+		return nil, ginext.NewBadRequestError("dropoff stop must come after pickup stop")
+	}
+
+	return &booking.TripSegment{
+		FromStopOrder: pickup.StopOrder,
+		ToStopOrder:   dropoff.StopOrder,
+	}, nil
+}
+
 func (s *TripServiceImpl) ListTrips(ctx context.Context, req *model.ListTripsRequest) ([]model.Trip, int64, error) {
 	// If IDs provided, fetch specific trips (batch mode)
 	if len(req.IDs) > 0 {
@@ -145,6 +246,11 @@ func (s *TripServiceImpl) GetSeatAvailability(ctx context.Context, tripID uuid.U
 		return nil, ginext.NewInternalServerError("failed to get seats")
 	}
 
+	fare, err := s.pricing.QuoteTrip(ctx, tripID)
+	if err != nil {
+		return nil, err
+	}
+
 	// TODO: Check seat status from booking service
 	var seatAvailabilities []model.SeatAvailability
 	availableCount := 0
@@ -154,7 +260,7 @@ func (s *TripServiceImpl) GetSeatAvailability(ctx context.Context, tripID uuid.U
 			SeatID:      seat.ID,
 			SeatNumber:  seat.SeatNumber,
 			SeatType:    seat.SeatType,
-			Price:       trip.BasePrice * seat.PriceMultiplier,
+			Price:       fare.Fare * seat.PriceMultiplier,
 			IsAvailable: seat.IsAvailable, // TODO: Check from booking service
 			Row:         seat.Row,
 			Column:      seat.Column,
@@ -173,6 +279,7 @@ func (s *TripServiceImpl) GetSeatAvailability(ctx context.Context, tripID uuid.U
 		AvailableSeats: availableCount,
 		TotalSeats:     len(seats),
 		SeatMap:        seatAvailabilities,
+		PriceTiers:     fare.PriceTiers,
 	}, nil
 }
 
@@ -250,6 +357,7 @@ func (s *TripServiceImpl) CreateTrip(ctx context.Context, req *model.CreateTripR
 		log.Error().Err(err).Msg("Failed to create trip")
 		return nil, ginext.NewInternalServerError("failed to create trip")
 	}
+	invalidateFareCalendars(ctx, s.cache, trip.DepartureTime)
 
 	// Load relationships
 	return s.GetTripByID(ctx, &model.GetTripByIDRequest{}, trip.ID)
@@ -260,6 +368,7 @@ func (s *TripServiceImpl) UpdateTrip(ctx context.Context, id uuid.UUID, req *mod
 	if err != nil {
 		return nil, ginext.NewInternalServerError("failed to get trip")
 	}
+	previousDeparture := trip.DepartureTime
 
 	// Update fields if provided
 	if req.DepartureTime != nil {
@@ -294,6 +403,7 @@ func (s *TripServiceImpl) UpdateTrip(ctx context.Context, id uuid.UUID, req *mod
 	if err := s.tripRepo.UpdateTrip(ctx, trip); err != nil {
 		return nil, ginext.NewInternalServerError("failed to update trip")
 	}
+	invalidateFareCalendars(ctx, s.cache, previousDeparture, trip.DepartureTime)
 
 	return s.GetTripByID(ctx, &model.GetTripByIDRequest{}, id)
 }
@@ -315,34 +425,7 @@ func (s *TripServiceImpl) DeleteTrip(ctx context.Context, id uuid.UUID) error {
 	if err := s.tripRepo.DeleteTrip(ctx, id); err != nil {
 		return ginext.NewInternalServerError("failed to delete trip")
 	}
-
-	return nil
-}
-
-// GetCompletedTripsForReschedule gets completed trips from last 24h that need rescheduling
-func (s *TripServiceImpl) GetCompletedTripsForReschedule(ctx context.Context) ([]model.Trip, error) {
-	return s.tripRepo.GetCompletedTripsForReschedule(ctx)
-}
-
-// RescheduleTrip updates trip with new departure/arrival times (maintains same bus, route, price)
-func (s *TripServiceImpl) RescheduleTrip(ctx context.Context, id uuid.UUID, newDeparture, newArrival time.Time) error {
-	trip, err := s.tripRepo.GetTripByID(ctx, &model.GetTripByIDRequest{}, id)
-	if err != nil {
-		return fmt.Errorf("trip not found: %w", err)
-	}
-
-	if newArrival.Before(newDeparture) {
-		return ginext.NewBadRequestError("arrival time must be after departure time")
-	}
-
-	// Update times and reset status to scheduled
-	trip.DepartureTime = newDeparture
-	trip.ArrivalTime = newArrival
-	trip.Status = "scheduled"
-
-	if err := s.tripRepo.UpdateTrip(ctx, trip); err != nil {
-		return fmt.Errorf("failed to reschedule trip: %w", err)
-	}
+	invalidateFareCalendars(ctx, s.cache, trip.DepartureTime)
 
 	return nil
 }
@@ -351,56 +434,3 @@ func (s *TripServiceImpl) RescheduleTrip(ctx context.Context, id uuid.UUID, newD
 func (s *TripServiceImpl) ProcessTripStatusUpdates(ctx context.Context) error {
 	return s.tripRepo.UpdateTripStatuses(ctx)
 }
-
-func (s *TripServiceImpl) CancelTrip(ctx context.Context, id uuid.UUID) error {
-	// 1. Get Trip
-	trip, err := s.tripRepo.GetTripByID(ctx, &model.GetTripByIDRequest{}, id)
-	if err != nil {
-		return ginext.NewInternalServerError("failed to get trip")
-	}
-
-	// 2. Validate Status Check
-	// Only Scheduled or Delayed trips can be cancelled
-	if trip.Status != constants.TripStatusScheduled && trip.Status != constants.TripStatusDelayed {
-		return ginext.NewBadRequestError(fmt.Sprintf("Cannot cancel trip with status: %s. Only scheduled or delayed trips can be cancelled.", trip.Status))
-	}
-
-	// 3. Update Status to Cancelled
-	trip.Status = constants.TripStatusCancelled
-
-	if err := s.tripRepo.UpdateTrip(ctx, trip); err != nil {
-		return ginext.NewInternalServerError("failed to update trip status")
-	}
-
-	// 4. Get Bookings
-	bookings, err := s.bookingClient.GetTripBookings(ctx, id)
-	if err != nil {
-		// Log error but we already cancelled the trip in DB.
-		log.Error().Err(err).Str("trip_id", id.String()).Msg("Failed to get bookings for cancelled trip for processing refunds")
-		return nil
-	}
-
-	// 5. Refund/Cancel Bookings
-	for _, b := range bookings {
-		// Refund if PAID
-		if b.TransactionStatus == "PAID" {
-			req := &payment.RefundRequest{
-				BookingID:    b.ID,
-				Reason:       "Trip Cancelled by Operator",
-				RefundAmount: b.TotalAmount,
-			}
-			_, err := s.paymentClient.CreateRefund(ctx, req)
-			if err != nil {
-				log.Error().Err(err).Str("booking_id", b.ID.String()).Msg("Failed to process refund for booking")
-			}
-		}
-
-		// Cancel Booking
-		err := s.bookingClient.CancelBooking(ctx, b.ID, "Trip Cancelled by Operator")
-		if err != nil {
-			log.Error().Err(err).Str("booking_id", b.ID.String()).Msg("Failed to cancel booking")
-		}
-	}
-
-	return nil
-}
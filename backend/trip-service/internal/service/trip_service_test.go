@@ -10,6 +10,7 @@ import (
 	"bus-booking/trip-service/internal/model"
 	"bus-booking/trip-service/internal/model/booking"
 	repo_mocks "bus-booking/trip-service/internal/repository/mocks"
+	service_mocks "bus-booking/trip-service/internal/service/mocks"
 
 	"github.com/golang/mock/gomock"
 	"github.com/google/uuid"
@@ -26,6 +27,8 @@ func TestNewTripService(t *testing.T) {
 	mockBusRepo := repo_mocks.NewMockBusRepository(ctrl)
 	mockSeatRepo := repo_mocks.NewMockSeatRepository(ctrl)
 	mockBookingClient := mocks.NewMockBookingClient(ctrl)
+	mockPricingService := service_mocks.NewMockPricingService(ctrl)
+	mockCacheService := service_mocks.NewMockCacheService(ctrl)
 
 	service := NewTripService(
 		mockTripRepo,
@@ -34,7 +37,8 @@ func TestNewTripService(t *testing.T) {
 		mockBusRepo,
 		mockSeatRepo,
 		mockBookingClient,
-		nil,
+		mockPricingService,
+		mockCacheService,
 	)
 
 	assert.NotNil(t, service)
@@ -51,8 +55,10 @@ func TestSearchTrips_Success(t *testing.T) {
 	mockBusRepo := repo_mocks.NewMockBusRepository(ctrl)
 	mockSeatRepo := repo_mocks.NewMockSeatRepository(ctrl)
 	mockBookingClient := mocks.NewMockBookingClient(ctrl)
+	mockPricingService := service_mocks.NewMockPricingService(ctrl)
+	mockCacheService := service_mocks.NewMockCacheService(ctrl)
 
-	service := NewTripService(mockTripRepo, mockRouteRepo, mockRouteStopRepo, mockBusRepo, mockSeatRepo, mockBookingClient, nil)
+	service := NewTripService(mockTripRepo, mockRouteRepo, mockRouteStopRepo, mockBusRepo, mockSeatRepo, mockBookingClient, mockPricingService, mockCacheService)
 
 	ctx := context.Background()
 	origin := "Ha Noi"
@@ -60,7 +66,10 @@ func TestSearchTrips_Success(t *testing.T) {
 	req := &model.TripSearchRequest{Origin: &origin, Destination: &destination}
 
 	expectedTrips := []model.TripDetail{
-		{ID: uuid.New()},
+		{ID: uuid.New(), BasePrice: 200000},
+	}
+	tiers := []model.PriceTier{
+		{SeatType: string(constants.SeatTypeStandard), BasePrice: 240000, PriceMultiplier: 1, FinalPrice: 240000, AvailableCount: 30},
 	}
 
 	mockTripRepo.EXPECT().
@@ -68,11 +77,91 @@ func TestSearchTrips_Success(t *testing.T) {
 		Return(expectedTrips, int64(1), nil).
 		Times(1)
 
+	mockPricingService.EXPECT().
+		QuoteTrips(ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, trips []model.Trip) (map[uuid.UUID]*model.TripFare, error) {
+			assert.Len(t, trips, 1)
+			assert.Equal(t, 200000.0, trips[0].BasePrice)
+			return map[uuid.UUID]*model.TripFare{
+				trips[0].ID: {TripID: trips[0].ID, BasePrice: 200000, Fare: 240000, PriceTiers: tiers},
+			}, nil
+		}).
+		Times(1)
+
+	trips, total, err := service.SearchTrips(ctx, req)
+
+	assert.NoError(t, err)
+	assert.Len(t, trips, 1)
+	assert.Equal(t, int64(1), total)
+	assert.Equal(t, tiers, trips[0].PriceTiers)
+}
+
+func TestSearchTrips_SegmentTiersProrated(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTripRepo := repo_mocks.NewMockTripRepository(ctrl)
+	mockPricingService := service_mocks.NewMockPricingService(ctrl)
+	mockCacheService := service_mocks.NewMockCacheService(ctrl)
+
+	service := NewTripService(mockTripRepo, nil, nil, nil, nil, nil, mockPricingService, mockCacheService)
+
+	ctx := context.Background()
+	req := &model.TripSearchRequest{}
+	tripID := uuid.New()
+
+	mockTripRepo.EXPECT().
+		SearchTrips(ctx, req).
+		Return([]model.TripDetail{{
+			ID:        tripID,
+			BasePrice: 200000,
+			Route:     &model.RouteDetail{DistanceKm: 800},
+			Segment:   &model.TripSegmentDetail{DistanceKm: 400, BasePrice: 100000},
+		}}, int64(1), nil)
+
+	mockPricingService.EXPECT().
+		QuoteTrips(ctx, gomock.Any()).
+		Return(map[uuid.UUID]*model.TripFare{
+			tripID: {TripID: tripID, BasePrice: 200000, Fare: 300000, PriceTiers: []model.PriceTier{
+				{SeatType: string(constants.SeatTypeVIP), BasePrice: 300000, PriceMultiplier: 1.5, FinalPrice: 450000, AvailableCount: 4},
+			}},
+		}, nil)
+
+	trips, _, err := service.SearchTrips(ctx, req)
+
+	assert.NoError(t, err)
+	assert.Len(t, trips[0].PriceTiers, 1)
+	assert.Equal(t, 150000.0, trips[0].PriceTiers[0].BasePrice)
+	assert.Equal(t, 225000.0, trips[0].PriceTiers[0].FinalPrice)
+	assert.Equal(t, 4, trips[0].PriceTiers[0].AvailableCount)
+}
+
+func TestSearchTrips_PricingFailureKeepsResults(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTripRepo := repo_mocks.NewMockTripRepository(ctrl)
+	mockPricingService := service_mocks.NewMockPricingService(ctrl)
+	mockCacheService := service_mocks.NewMockCacheService(ctrl)
+
+	service := NewTripService(mockTripRepo, nil, nil, nil, nil, nil, mockPricingService, mockCacheService)
+
+	ctx := context.Background()
+	req := &model.TripSearchRequest{}
+
+	mockTripRepo.EXPECT().
+		SearchTrips(ctx, req).
+		Return([]model.TripDetail{{ID: uuid.New(), BasePrice: 200000}}, int64(1), nil)
+	mockPricingService.EXPECT().
+		QuoteTrips(ctx, gomock.Any()).
+		Return(nil, assert.AnError)
+
 	trips, total, err := service.SearchTrips(ctx, req)
 
 	assert.NoError(t, err)
 	assert.Len(t, trips, 1)
 	assert.Equal(t, int64(1), total)
+	assert.Empty(t, trips[0].PriceTiers)
 }
 
 func TestSearchTrips_Error(t *testing.T) {
@@ -85,8 +174,10 @@ func TestSearchTrips_Error(t *testing.T) {
 	mockBusRepo := repo_mocks.NewMockBusRepository(ctrl)
 	mockSeatRepo := repo_mocks.NewMockSeatRepository(ctrl)
 	mockBookingClient := mocks.NewMockBookingClient(ctrl)
+	mockPricingService := service_mocks.NewMockPricingService(ctrl)
+	mockCacheService := service_mocks.NewMockCacheService(ctrl)
 
-	service := NewTripService(mockTripRepo, mockRouteRepo, mockRouteStopRepo, mockBusRepo, mockSeatRepo, mockBookingClient, nil)
+	service := NewTripService(mockTripRepo, mockRouteRepo, mockRouteStopRepo, mockBusRepo, mockSeatRepo, mockBookingClient, mockPricingService, mockCacheService)
 
 	ctx := context.Background()
 	req := &model.TripSearchRequest{}
@@ -113,8 +204,10 @@ func TestGetTripByID_Simple(t *testing.T) {
 	mockBusRepo := repo_mocks.NewMockBusRepository(ctrl)
 	mockSeatRepo := repo_mocks.NewMockSeatRepository(ctrl)
 	mockBookingClient := mocks.NewMockBookingClient(ctrl)
+	mockPricingService := service_mocks.NewMockPricingService(ctrl)
+	mockCacheService := service_mocks.NewMockCacheService(ctrl)
 
-	service := NewTripService(mockTripRepo, mockRouteRepo, mockRouteStopRepo, mockBusRepo, mockSeatRepo, mockBookingClient, nil)
+	service := NewTripService(mockTripRepo, mockRouteRepo, mockRouteStopRepo, mockBusRepo, mockSeatRepo, mockBookingClient, mockPricingService, mockCacheService)
 
 	ctx := context.Background()
 	tripID := uuid.New()
@@ -147,8 +240,10 @@ func TestListTrips_ByIDs(t *testing.T) {
 	mockBusRepo := repo_mocks.NewMockBusRepository(ctrl)
 	mockSeatRepo := repo_mocks.NewMockSeatRepository(ctrl)
 	mockBookingClient := mocks.NewMockBookingClient(ctrl)
+	mockPricingService := service_mocks.NewMockPricingService(ctrl)
+	mockCacheService := service_mocks.NewMockCacheService(ctrl)
 
-	service := NewTripService(mockTripRepo, mockRouteRepo, mockRouteStopRepo, mockBusRepo, mockSeatRepo, mockBookingClient, nil)
+	service := NewTripService(mockTripRepo, mockRouteRepo, mockRouteStopRepo, mockBusRepo, mockSeatRepo, mockBookingClient, mockPricingService, mockCacheService)
 
 	ctx := context.Background()
 	tripIDs := []uuid.UUID{uuid.New(), uuid.New()}
@@ -181,8 +276,10 @@ func TestListTrips_Pagination(t *testing.T) {
 	mockBusRepo := repo_mocks.NewMockBusRepository(ctrl)
 	mockSeatRepo := repo_mocks.NewMockSeatRepository(ctrl)
 	mockBookingClient := mocks.NewMockBookingClient(ctrl)
+	mockPricingService := service_mocks.NewMockPricingService(ctrl)
+	mockCacheService := service_mocks.NewMockCacheService(ctrl)
 
-	service := NewTripService(mockTripRepo, mockRouteRepo, mockRouteStopRepo, mockBusRepo, mockSeatRepo, mockBookingClient, nil)
+	service := NewTripService(mockTripRepo, mockRouteRepo, mockRouteStopRepo, mockBusRepo, mockSeatRepo, mockBookingClient, mockPricingService, mockCacheService)
 
 	ctx := context.Background()
 	req := &model.ListTripsRequest{
@@ -213,8 +310,10 @@ func TestGetSeatAvailability_Success(t *testing.T) {
 	mockBusRepo := repo_mocks.NewMockBusRepository(ctrl)
 	mockSeatRepo := repo_mocks.NewMockSeatRepository(ctrl)
 	mockBookingClient := mocks.NewMockBookingClient(ctrl)
+	mockPricingService := service_mocks.NewMockPricingService(ctrl)
+	mockCacheService := service_mocks.NewMockCacheService(ctrl)
 
-	service := NewTripService(mockTripRepo, mockRouteRepo, mockRouteStopRepo, mockBusRepo, mockSeatRepo, mockBookingClient, nil)
+	service := NewTripService(mockTripRepo, mockRouteRepo, mockRouteStopRepo, mockBusRepo, mockSeatRepo, mockBookingClient, mockPricingService, mockCacheService)
 
 	ctx := context.Background()
 	tripID := uuid.New()
@@ -251,6 +350,20 @@ func TestGetSeatAvailability_Success(t *testing.T) {
 		Return(seats, nil).
 		Times(1)
 
+	fare := &model.TripFare{
+		TripID:    tripID,
+		BasePrice: 100000,
+		Fare:      120000,
+		PriceTiers: []model.PriceTier{
+			{BasePrice: 120000, PriceMultiplier: 1.0, FinalPrice: 120000, AvailableCount: 1},
+			{BasePrice: 120000, PriceMultiplier: 1.5, FinalPrice: 180000},
+		},
+	}
+	mockPricingService.EXPECT().
+		QuoteTrip(ctx, tripID).
+		Return(fare, nil).
+		Times(1)
+
 	result, err := service.GetSeatAvailability(ctx, tripID)
 
 	assert.NoError(t, err)
@@ -259,6 +372,9 @@ func TestGetSeatAvailability_Success(t *testing.T) {
 	assert.Equal(t, 1, result.AvailableSeats)
 	assert.Equal(t, 2, result.TotalSeats)
 	assert.Len(t, result.SeatMap, 2)
+	assert.Equal(t, 120000.0, result.SeatMap[0].Price)
+	assert.Equal(t, 180000.0, result.SeatMap[1].Price)
+	assert.Equal(t, fare.PriceTiers, result.PriceTiers)
 }
 
 func TestGetTripsByRouteAndDate_Success(t *testing.T) {
@@ -271,8 +387,10 @@ func TestGetTripsByRouteAndDate_Success(t *testing.T) {
 	mockBusRepo := repo_mocks.NewMockBusRepository(ctrl)
 	mockSeatRepo := repo_mocks.NewMockSeatRepository(ctrl)
 	mockBookingClient := mocks.NewMockBookingClient(ctrl)
+	mockPricingService := service_mocks.NewMockPricingService(ctrl)
+	mockCacheService := service_mocks.NewMockCacheService(ctrl)
 
-	service := NewTripService(mockTripRepo, mockRouteRepo, mockRouteStopRepo, mockBusRepo, mockSeatRepo, mockBookingClient, nil)
+	service := NewTripService(mockTripRepo, mockRouteRepo, mockRouteStopRepo, mockBusRepo, mockSeatRepo, mockBookingClient, mockPricingService, mockCacheService)
 
 	ctx := context.Background()
 	routeID := uuid.New()
@@ -307,8 +425,10 @@ func TestGetTripsByRouteAndDate_InvalidRoute(t *testing.T) {
 	mockBusRepo := repo_mocks.NewMockBusRepository(ctrl)
 	mockSeatRepo := repo_mocks.NewMockSeatRepository(ctrl)
 	mockBookingClient := mocks.NewMockBookingClient(ctrl)
+	mockPricingService := service_mocks.NewMockPricingService(ctrl)
+	mockCacheService := service_mocks.NewMockCacheService(ctrl)
 
-	service := NewTripService(mockTripRepo, mockRouteRepo, mockRouteStopRepo, mockBusRepo, mockSeatRepo, mockBookingClient, nil)
+	service := NewTripService(mockTripRepo, mockRouteRepo, mockRouteStopRepo, mockBusRepo, mockSeatRepo, mockBookingClient, mockPricingService, mockCacheService)
 
 	ctx := context.Background()
 	date := time.Now()
@@ -330,8 +450,10 @@ func TestCreateTrip_Success(t *testing.T) {
 	mockBusRepo := repo_mocks.NewMockBusRepository(ctrl)
 	mockSeatRepo := repo_mocks.NewMockSeatRepository(ctrl)
 	mockBookingClient := mocks.NewMockBookingClient(ctrl)
+	mockPricingService := service_mocks.NewMockPricingService(ctrl)
+	mockCacheService := service_mocks.NewMockCacheService(ctrl)
 
-	service := NewTripService(mockTripRepo, mockRouteRepo, mockRouteStopRepo, mockBusRepo, mockSeatRepo, mockBookingClient, nil)
+	service := NewTripService(mockTripRepo, mockRouteRepo, mockRouteStopRepo, mockBusRepo, mockSeatRepo, mockBookingClient, mockPricingService, mockCacheService)
 
 	ctx := context.Background()
 	now := time.Now()
@@ -352,6 +474,7 @@ func TestCreateTrip_Success(t *testing.T) {
 	mockTripRepo.EXPECT().GetTripsByBusAndDateRange(ctx, req.BusID, gomock.Any(), gomock.Any()).Return([]model.Trip{}, nil).Times(1)
 	mockTripRepo.EXPECT().CreateTrip(ctx, gomock.Any()).Return(nil).Times(1)
 	mockTripRepo.EXPECT().GetTripByID(ctx, gomock.Any(), gomock.Any()).Return(createdTrip, nil).Times(1)
+	mockCacheService.EXPECT().InvalidateFareCalendars(ctx, []string{req.DepartureTime.In(scheduleLocation()).Format(constants.FareCalendarMonthFormat)}).Return(nil).Times(1)
 
 	result, err := service.CreateTrip(ctx, req)
 
@@ -369,8 +492,10 @@ func TestCreateTrip_ArrivalBeforeDeparture(t *testing.T) {
 	mockBusRepo := repo_mocks.NewMockBusRepository(ctrl)
 	mockSeatRepo := repo_mocks.NewMockSeatRepository(ctrl)
 	mockBookingClient := mocks.NewMockBookingClient(ctrl)
+	mockPricingService := service_mocks.NewMockPricingService(ctrl)
+	mockCacheService := service_mocks.NewMockCacheService(ctrl)
 
-	service := NewTripService(mockTripRepo, mockRouteRepo, mockRouteStopRepo, mockBusRepo, mockSeatRepo, mockBookingClient, nil)
+	service := NewTripService(mockTripRepo, mockRouteRepo, mockRouteStopRepo, mockBusRepo, mockSeatRepo, mockBookingClient, mockPricingService, mockCacheService)
 
 	ctx := context.Background()
 	now := time.Now()
@@ -396,8 +521,10 @@ func TestCreateTrip_PastDeparture(t *testing.T) {
 	mockBusRepo := repo_mocks.NewMockBusRepository(ctrl)
 	mockSeatRepo := repo_mocks.NewMockSeatRepository(ctrl)
 	mockBookingClient := mocks.NewMockBookingClient(ctrl)
+	mockPricingService := service_mocks.NewMockPricingService(ctrl)
+	mockCacheService := service_mocks.NewMockCacheService(ctrl)
 
-	service := NewTripService(mockTripRepo, mockRouteRepo, mockRouteStopRepo, mockBusRepo, mockSeatRepo, mockBookingClient, nil)
+	service := NewTripService(mockTripRepo, mockRouteRepo, mockRouteStopRepo, mockBusRepo, mockSeatRepo, mockBookingClient, mockPricingService, mockCacheService)
 
 	ctx := context.Background()
 	past := time.Now().Add(-1 * time.Hour)
@@ -423,8 +550,10 @@ func TestUpdateTrip_Success(t *testing.T) {
 	mockBusRepo := repo_mocks.NewMockBusRepository(ctrl)
 	mockSeatRepo := repo_mocks.NewMockSeatRepository(ctrl)
 	mockBookingClient := mocks.NewMockBookingClient(ctrl)
+	mockPricingService := service_mocks.NewMockPricingService(ctrl)
+	mockCacheService := service_mocks.NewMockCacheService(ctrl)
 
-	service := NewTripService(mockTripRepo, mockRouteRepo, mockRouteStopRepo, mockBusRepo, mockSeatRepo, mockBookingClient, nil)
+	service := NewTripService(mockTripRepo, mockRouteRepo, mockRouteStopRepo, mockBusRepo, mockSeatRepo, mockBookingClient, mockPricingService, mockCacheService)
 
 	ctx := context.Background()
 	tripID := uuid.New()
@@ -442,6 +571,7 @@ func TestUpdateTrip_Success(t *testing.T) {
 
 	mockTripRepo.EXPECT().GetTripByID(ctx, gomock.Any(), tripID).Return(existingTrip, nil).Times(2)
 	mockTripRepo.EXPECT().UpdateTrip(ctx, gomock.Any()).Return(nil).Times(1)
+	mockCacheService.EXPECT().InvalidateFareCalendars(ctx, []string{existingTrip.DepartureTime.In(scheduleLocation()).Format(constants.FareCalendarMonthFormat)}).Return(nil).Times(1)
 
 	result, err := service.UpdateTrip(ctx, tripID, req)
 
@@ -459,8 +589,10 @@ func TestUpdateTrip_NegativePrice(t *testing.T) {
 	mockBusRepo := repo_mocks.NewMockBusRepository(ctrl)
 	mockSeatRepo := repo_mocks.NewMockSeatRepository(ctrl)
 	mockBookingClient := mocks.NewMockBookingClient(ctrl)
+	mockPricingService := service_mocks.NewMockPricingService(ctrl)
+	mockCacheService := service_mocks.NewMockCacheService(ctrl)
 
-	service := NewTripService(mockTripRepo, mockRouteRepo, mockRouteStopRepo, mockBusRepo, mockSeatRepo, mockBookingClient, nil)
+	service := NewTripService(mockTripRepo, mockRouteRepo, mockRouteStopRepo, mockBusRepo, mockSeatRepo, mockBookingClient, mockPricingService, mockCacheService)
 
 	ctx := context.Background()
 	tripID := uuid.New()
@@ -488,8 +620,10 @@ func TestDeleteTrip_Success(t *testing.T) {
 	mockBusRepo := repo_mocks.NewMockBusRepository(ctrl)
 	mockSeatRepo := repo_mocks.NewMockSeatRepository(ctrl)
 	mockBookingClient := mocks.NewMockBookingClient(ctrl)
+	mockPricingService := service_mocks.NewMockPricingService(ctrl)
+	mockCacheService := service_mocks.NewMockCacheService(ctrl)
 
-	service := NewTripService(mockTripRepo, mockRouteRepo, mockRouteStopRepo, mockBusRepo, mockSeatRepo, mockBookingClient, nil)
+	service := NewTripService(mockTripRepo, mockRouteRepo, mockRouteStopRepo, mockBusRepo, mockSeatRepo, mockBookingClient, mockPricingService, mockCacheService)
 
 	ctx := context.Background()
 	tripID := uuid.New()
@@ -502,6 +636,7 @@ func TestDeleteTrip_Success(t *testing.T) {
 
 	mockTripRepo.EXPECT().GetTripByID(ctx, gomock.Any(), tripID).Return(trip, nil).Times(1)
 	mockTripRepo.EXPECT().DeleteTrip(ctx, tripID).Return(nil).Times(1)
+	mockCacheService.EXPECT().InvalidateFareCalendars(ctx, gomock.Any()).Return(nil).Times(1)
 
 	err := service.DeleteTrip(ctx, tripID)
 
@@ -518,8 +653,10 @@ func TestDeleteTrip_NotScheduled(t *testing.T) {
 	mockBusRepo := repo_mocks.NewMockBusRepository(ctrl)
 	mockSeatRepo := repo_mocks.NewMockSeatRepository(ctrl)
 	mockBookingClient := mocks.NewMockBookingClient(ctrl)
+	mockPricingService := service_mocks.NewMockPricingService(ctrl)
+	mockCacheService := service_mocks.NewMockCacheService(ctrl)
 
-	service := NewTripService(mockTripRepo, mockRouteRepo, mockRouteStopRepo, mockBusRepo, mockSeatRepo, mockBookingClient, nil)
+	service := NewTripService(mockTripRepo, mockRouteRepo, mockRouteStopRepo, mockBusRepo, mockSeatRepo, mockBookingClient, mockPricingService, mockCacheService)
 
 	ctx := context.Background()
 	tripID := uuid.New()
@@ -537,7 +674,7 @@ func TestDeleteTrip_NotScheduled(t *testing.T) {
 	assert.Contains(t, err.Error(), "only scheduled trips")
 }
 
-func TestRescheduleTrip_Success(t *testing.T) {
+func TestGetTripByID_WithSeatStatus(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
@@ -547,53 +684,60 @@ func TestRescheduleTrip_Success(t *testing.T) {
 	mockBusRepo := repo_mocks.NewMockBusRepository(ctrl)
 	mockSeatRepo := repo_mocks.NewMockSeatRepository(ctrl)
 	mockBookingClient := mocks.NewMockBookingClient(ctrl)
+	mockPricingService := service_mocks.NewMockPricingService(ctrl)
+	mockCacheService := service_mocks.NewMockCacheService(ctrl)
 
-	service := NewTripService(mockTripRepo, mockRouteRepo, mockRouteStopRepo, mockBusRepo, mockSeatRepo, mockBookingClient, nil)
+	service := NewTripService(mockTripRepo, mockRouteRepo, mockRouteStopRepo, mockBusRepo, mockSeatRepo, mockBookingClient, mockPricingService, mockCacheService)
 
 	ctx := context.Background()
 	tripID := uuid.New()
-	newDeparture := time.Now().Add(72 * time.Hour)
-	newArrival := newDeparture.Add(12 * time.Hour)
-
-	trip := &model.Trip{BaseModel: model.BaseModel{ID: tripID}}
-
-	mockTripRepo.EXPECT().GetTripByID(ctx, gomock.Any(), tripID).Return(trip, nil).Times(1)
-	mockTripRepo.EXPECT().UpdateTrip(ctx, gomock.Any()).Return(nil).Times(1)
-
-	err := service.RescheduleTrip(ctx, tripID, newDeparture, newArrival)
+	seatID1 := uuid.New()
+	seatID2 := uuid.New()
 
-	assert.NoError(t, err)
-}
+	req := &model.GetTripByIDRequest{
+		SeatBookingStatus: true,
+		PreloadBus:        true,
+		PreloadSeat:       true,
+	}
 
-func TestRescheduleTrip_InvalidTimes(t *testing.T) {
-	ctrl := gomock.NewController(t)
-	defer ctrl.Finish()
+	seats := []model.Seat{
+		{BaseModel: model.BaseModel{ID: seatID1}, SeatNumber: "A1"},
+		{BaseModel: model.BaseModel{ID: seatID2}, SeatNumber: "A2"},
+	}
 
-	mockTripRepo := repo_mocks.NewMockTripRepository(ctrl)
-	mockRouteRepo := repo_mocks.NewMockRouteRepository(ctrl)
-	mockRouteStopRepo := repo_mocks.NewMockRouteStopRepository(ctrl)
-	mockBusRepo := repo_mocks.NewMockBusRepository(ctrl)
-	mockSeatRepo := repo_mocks.NewMockSeatRepository(ctrl)
-	mockBookingClient := mocks.NewMockBookingClient(ctrl)
+	trip := &model.Trip{
+		BaseModel: model.BaseModel{ID: tripID},
+		Bus: &model.Bus{
+			BaseModel: model.BaseModel{ID: uuid.New()},
+			Seats:     seats,
+		},
+	}
 
-	service := NewTripService(mockTripRepo, mockRouteRepo, mockRouteStopRepo, mockBusRepo, mockSeatRepo, mockBookingClient, nil)
+	seatStatuses := []booking.SeatStatus{
+		{SeatID: seatID1, IsBooked: true, IsLocked: false},
+		// seatID2 is missing (should default to available)
+	}
 
-	ctx := context.Background()
-	tripID := uuid.New()
-	newDeparture := time.Now().Add(72 * time.Hour)
-	newArrival := newDeparture.Add(-1 * time.Hour) // Before departure!
+	mockTripRepo.EXPECT().GetTripByID(ctx, req, tripID).Return(trip, nil).Times(1)
+	mockBookingClient.EXPECT().GetSeatStatus(ctx, tripID, gomock.Any(), nil).Return(seatStatuses, nil).Times(1)
 
-	trip := &model.Trip{BaseModel: model.BaseModel{ID: tripID}}
+	result, err := service.GetTripByID(ctx, req, tripID)
 
-	mockTripRepo.EXPECT().GetTripByID(ctx, gomock.Any(), tripID).Return(trip, nil).Times(1)
+	assert.NoError(t, err)
+	assert.NotNil(t, result)
+	assert.NotNil(t, result.Bus)
+	assert.Len(t, result.Bus.Seats, 2)
 
-	err := service.RescheduleTrip(ctx, tripID, newDeparture, newArrival)
+	// Check mapped status
+	assert.NotNil(t, result.Bus.Seats[0].Status)
+	assert.True(t, result.Bus.Seats[0].Status.IsBooked)
 
-	assert.Error(t, err)
-	assert.Contains(t, err.Error(), "arrival time must be after departure time")
+	// Check default status
+	assert.NotNil(t, result.Bus.Seats[1].Status)
+	assert.False(t, result.Bus.Seats[1].Status.IsBooked)
 }
 
-func TestGetCompletedTripsForReschedule(t *testing.T) {
+func TestGetTripByID_WithSeatStatusForSegment(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
@@ -603,24 +747,53 @@ func TestGetCompletedTripsForReschedule(t *testing.T) {
 	mockBusRepo := repo_mocks.NewMockBusRepository(ctrl)
 	mockSeatRepo := repo_mocks.NewMockSeatRepository(ctrl)
 	mockBookingClient := mocks.NewMockBookingClient(ctrl)
+	mockPricingService := service_mocks.NewMockPricingService(ctrl)
+	mockCacheService := service_mocks.NewMockCacheService(ctrl)
 
-	service := NewTripService(mockTripRepo, mockRouteRepo, mockRouteStopRepo, mockBusRepo, mockSeatRepo, mockBookingClient, nil)
+	service := NewTripService(mockTripRepo, mockRouteRepo, mockRouteStopRepo, mockBusRepo, mockSeatRepo, mockBookingClient, mockPricingService, mockCacheService)
 
 	ctx := context.Background()
-	expectedTrips := []model.Trip{{BaseModel: model.BaseModel{ID: uuid.New()}}}
+	tripID := uuid.New()
+	routeID := uuid.New()
+	pickupID := uuid.New()
+	dropoffID := uuid.New()
 
-	mockTripRepo.EXPECT().
-		GetCompletedTripsForReschedule(ctx).
-		Return(expectedTrips, nil).
-		Times(1)
+	req := &model.GetTripByIDRequest{
+		SeatBookingStatus: true,
+		PreloadBus:        true,
+		PreloadSeat:       true,
+		PickupStopID:      pickupID.String(),
+		DropoffStopID:     dropoffID.String(),
+	}
 
-	result, err := service.GetCompletedTripsForReschedule(ctx)
+	trip := &model.Trip{
+		BaseModel: model.BaseModel{ID: tripID},
+		RouteID:   routeID,
+		Bus: &model.Bus{
+			BaseModel: model.BaseModel{ID: uuid.New()},
+			Seats:     []model.Seat{{BaseModel: model.BaseModel{ID: uuid.New()}, SeatNumber: "A1"}},
+		},
+	}
+
+	stops := []model.RouteStop{
+		{BaseModel: model.BaseModel{ID: uuid.New()}, StopOrder: 100, StopType: constants.StopTypePickup, IsActive: true},
+		{BaseModel: model.BaseModel{ID: pickupID}, StopOrder: 200, StopType: constants.StopTypeBoth, IsActive: true},
+		{BaseModel: model.BaseModel{ID: dropoffID}, StopOrder: 300, StopType: constants.StopTypeDropoff, IsActive: true},
+	}
+
+	mockTripRepo.EXPECT().GetTripByID(ctx, req, tripID).Return(trip, nil)
+	mockRouteStopRepo.EXPECT().ListByRouteID(ctx, routeID).Return(stops, nil)
+	mockBookingClient.EXPECT().GetSeatStatus(ctx, tripID, gomock.Any(), &booking.TripSegment{FromStopOrder: 200, ToStopOrder: 300}).
+		Return([]booking.SeatStatus{}, nil)
+
+	result, err := service.GetTripByID(ctx, req, tripID)
 
 	assert.NoError(t, err)
-	assert.Len(t, result, 1)
+	assert.NotNil(t, result.Bus.Seats[0].Status)
+	assert.False(t, result.Bus.Seats[0].Status.IsBooked)
 }
 
-func TestGetTripByID_WithSeatStatus(t *testing.T) {
+func TestGetTripByID_SegmentDropoffBeforePickup(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
@@ -630,55 +803,47 @@ func TestGetTripByID_WithSeatStatus(t *testing.T) {
 	mockBusRepo := repo_mocks.NewMockBusRepository(ctrl)
 	mockSeatRepo := repo_mocks.NewMockSeatRepository(ctrl)
 	mockBookingClient := mocks.NewMockBookingClient(ctrl)
+	mockPricingService := service_mocks.NewMockPricingService(ctrl)
+	mockCacheService := service_mocks.NewMockCacheService(ctrl)
 
-	service := NewTripService(mockTripRepo, mockRouteRepo, mockRouteStopRepo, mockBusRepo, mockSeatRepo, mockBookingClient, nil)
+	service := NewTripService(mockTripRepo, mockRouteRepo, mockRouteStopRepo, mockBusRepo, mockSeatRepo, mockBookingClient, mockPricingService, mockCacheService)
 
 	ctx := context.Background()
 	tripID := uuid.New()
-	seatID1 := uuid.New()
-	seatID2 := uuid.New()
+	routeID := uuid.New()
+	pickupID := uuid.New()
+	dropoffID := uuid.New()
 
 	req := &model.GetTripByIDRequest{
 		SeatBookingStatus: true,
 		PreloadBus:        true,
 		PreloadSeat:       true,
-	}
-
-	seats := []model.Seat{
-		{BaseModel: model.BaseModel{ID: seatID1}, SeatNumber: "A1"},
-		{BaseModel: model.BaseModel{ID: seatID2}, SeatNumber: "A2"},
+		PickupStopID:      pickupID.String(),
+		DropoffStopID:     dropoffID.String(),
 	}
 
 	trip := &model.Trip{
 		BaseModel: model.BaseModel{ID: tripID},
+		RouteID:   routeID,
 		Bus: &model.Bus{
 			BaseModel: model.BaseModel{ID: uuid.New()},
-			Seats:     seats,
+			Seats:     []model.Seat{{BaseModel: model.BaseModel{ID: uuid.New()}, SeatNumber: "A1"}},
 		},
 	}
 
-	seatStatuses := []booking.SeatStatus{
-		{SeatID: seatID1, IsBooked: true, IsLocked: false},
-		// seatID2 is missing (should default to available)
+	stops := []model.RouteStop{
+		{BaseModel: model.BaseModel{ID: dropoffID}, StopOrder: 100, StopType: constants.StopTypeBoth, IsActive: true},
+		{BaseModel: model.BaseModel{ID: pickupID}, StopOrder: 200, StopType: constants.StopTypeBoth, IsActive: true},
 	}
 
-	mockTripRepo.EXPECT().GetTripByID(ctx, req, tripID).Return(trip, nil).Times(1)
-	mockBookingClient.EXPECT().GetSeatStatus(ctx, tripID, gomock.Any()).Return(seatStatuses, nil).Times(1)
+	mockTripRepo.EXPECT().GetTripByID(ctx, req, tripID).Return(trip, nil)
+	mockRouteStopRepo.EXPECT().ListByRouteID(ctx, routeID).Return(stops, nil)
 
 	result, err := service.GetTripByID(ctx, req, tripID)
 
-	assert.NoError(t, err)
-	assert.NotNil(t, result)
-	assert.NotNil(t, result.Bus)
-	assert.Len(t, result.Bus.Seats, 2)
-
-	// Check mapped status
-	assert.NotNil(t, result.Bus.Seats[0].Status)
-	assert.True(t, result.Bus.Seats[0].Status.IsBooked)
-
-	// Check default status
-	assert.NotNil(t, result.Bus.Seats[1].Status)
-	assert.False(t, result.Bus.Seats[1].Status.IsBooked)
+	assert.Error(t, err)
+	assert.Nil(t, result)
+	assert.Contains(t, err.Error(), "after pickup")
 }
 
 func TestUpdateTrip_Validations(t *testing.T) {
@@ -691,8 +856,10 @@ func TestUpdateTrip_Validations(t *testing.T) {
 	mockBusRepo := repo_mocks.NewMockBusRepository(ctrl)
 	mockSeatRepo := repo_mocks.NewMockSeatRepository(ctrl)
 	mockBookingClient := mocks.NewMockBookingClient(ctrl)
+	mockPricingService := service_mocks.NewMockPricingService(ctrl)
+	mockCacheService := service_mocks.NewMockCacheService(ctrl)
 
-	service := NewTripService(mockTripRepo, mockRouteRepo, mockRouteStopRepo, mockBusRepo, mockSeatRepo, mockBookingClient, nil)
+	service := NewTripService(mockTripRepo, mockRouteRepo, mockRouteStopRepo, mockBusRepo, mockSeatRepo, mockBookingClient, mockPricingService, mockCacheService)
 
 	ctx := context.Background()
 	tripID := uuid.New()
@@ -758,8 +925,10 @@ func TestUpdateTrip_FullUpdate(t *testing.T) {
 	mockBusRepo := repo_mocks.NewMockBusRepository(ctrl)
 	mockSeatRepo := repo_mocks.NewMockSeatRepository(ctrl)
 	mockBookingClient := mocks.NewMockBookingClient(ctrl)
+	mockPricingService := service_mocks.NewMockPricingService(ctrl)
+	mockCacheService := service_mocks.NewMockCacheService(ctrl)
 
-	service := NewTripService(mockTripRepo, mockRouteRepo, mockRouteStopRepo, mockBusRepo, mockSeatRepo, mockBookingClient, nil)
+	service := NewTripService(mockTripRepo, mockRouteRepo, mockRouteStopRepo, mockBusRepo, mockSeatRepo, mockBookingClient, mockPricingService, mockCacheService)
 
 	ctx := context.Background()
 	tripID := uuid.New()
@@ -778,6 +947,7 @@ func TestUpdateTrip_FullUpdate(t *testing.T) {
 		assert.Equal(t, constants.TripStatusCancelled, tr.Status)
 		assert.False(t, tr.IsActive)
 	}).Return(nil).Times(1)
+	mockCacheService.EXPECT().InvalidateFareCalendars(ctx, gomock.Any()).Return(nil).Times(1)
 
 	result, err := service.UpdateTrip(ctx, tripID, req)
 	assert.NoError(t, err)
@@ -798,76 +968,3 @@ func TestProcessTripStatusUpdates(t *testing.T) {
 	err := service.ProcessTripStatusUpdates(ctx)
 	assert.NoError(t, err)
 }
-
-func TestCancelTrip_Success(t *testing.T) {
-	ctrl := gomock.NewController(t)
-	defer ctrl.Finish()
-
-	mockTripRepo := repo_mocks.NewMockTripRepository(ctrl)
-	mockBookingClient := mocks.NewMockBookingClient(ctrl)
-	mockPaymentClient := mocks.NewMockPaymentClient(ctrl)
-
-	service := &TripServiceImpl{
-		tripRepo:      mockTripRepo,
-		bookingClient: mockBookingClient,
-		paymentClient: mockPaymentClient,
-	}
-
-	ctx := context.Background()
-	tripID := uuid.New()
-
-	trip := &model.Trip{
-		BaseModel: model.BaseModel{ID: tripID},
-		Status:    constants.TripStatusScheduled,
-	}
-
-	bookings := []*booking.Booking{
-		{
-			ID:                uuid.New(),
-			TransactionStatus: "PAID",
-			TotalAmount:       100000,
-		},
-		{
-			ID:                uuid.New(),
-			TransactionStatus: "PENDING",
-		},
-	}
-
-	mockTripRepo.EXPECT().GetTripByID(ctx, gomock.Any(), tripID).Return(trip, nil).Times(1)
-	mockTripRepo.EXPECT().UpdateTrip(ctx, gomock.Any()).Do(func(_ context.Context, tr *model.Trip) {
-		assert.Equal(t, constants.TripStatusCancelled, tr.Status)
-	}).Return(nil).Times(1)
-
-	mockBookingClient.EXPECT().GetTripBookings(ctx, tripID).Return(bookings, nil).Times(1)
-
-	// Expect refund for PAID booking
-	mockPaymentClient.EXPECT().CreateRefund(ctx, gomock.Any()).Return(nil, nil).Times(1)
-
-	// Expect cancel for both bookings
-	mockBookingClient.EXPECT().CancelBooking(ctx, gomock.Any(), gomock.Any()).Return(nil).Times(2)
-
-	err := service.CancelTrip(ctx, tripID)
-	assert.NoError(t, err)
-}
-
-func TestCancelTrip_InvalidStatus(t *testing.T) {
-	ctrl := gomock.NewController(t)
-	defer ctrl.Finish()
-
-	mockTripRepo := repo_mocks.NewMockTripRepository(ctrl)
-	service := &TripServiceImpl{tripRepo: mockTripRepo}
-
-	ctx := context.Background()
-	tripID := uuid.New()
-
-	trip := &model.Trip{
-		BaseModel: model.BaseModel{ID: tripID},
-		Status:    constants.TripStatusCompleted, // Cannot cancel completed
-	}
-
-	mockTripRepo.EXPECT().GetTripByID(ctx, gomock.Any(), tripID).Return(trip, nil).Times(1)
-
-	err := service.CancelTrip(ctx, tripID)
-	assert.Error(t, err)
-	assert.Contains(t, err.Error(), "Cannot cancel trip")
-}
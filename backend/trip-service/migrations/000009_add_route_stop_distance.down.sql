@@ -0,0 +1,2 @@
+ALTER TABLE route_stops DROP CONSTRAINT IF EXISTS route_stops_distance_check;
+ALTER TABLE route_stops DROP COLUMN IF EXISTS distance_km;
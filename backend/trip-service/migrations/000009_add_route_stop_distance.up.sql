@@ -0,0 +1,12 @@
+-- Distance of each stop from the route origin, used to price segment bookings
+ALTER TABLE route_stops ADD COLUMN IF NOT EXISTS distance_km DECIMAL(10,2) NOT NULL DEFAULT 0;
+
+-- Backfill existing stops proportionally to their travel time offset
+UPDATE route_stops
+SET distance_km = ROUND(routes.distance_km * LEAST(route_stops.offset_minutes, routes.estimated_minutes) / routes.estimated_minutes, 2)
+FROM routes
+WHERE routes.id = route_stops.route_id;
+
+ALTER TABLE route_stops ADD CONSTRAINT route_stops_distance_check CHECK (distance_km >= 0);
+
+COMMENT ON COLUMN route_stops.distance_km IS 'Kilometers from route origin to reach this stop';
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS uq_trips_schedule_departure;
+DROP INDEX IF EXISTS idx_trips_schedule_id;
+ALTER TABLE trips DROP COLUMN IF EXISTS schedule_id;
+DROP TABLE IF EXISTS trip_schedules;
@@ -0,0 +1,48 @@
+-- Recurring schedules that generate future trip instances
+CREATE TABLE IF NOT EXISTS trip_schedules (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMPTZ,
+
+    route_id UUID NOT NULL REFERENCES routes(id) ON DELETE CASCADE,
+    bus_id UUID NOT NULL REFERENCES buses(id) ON DELETE RESTRICT,
+    departure_time VARCHAR(5) NOT NULL,
+    weekdays INTEGER[] NOT NULL,
+    valid_from DATE NOT NULL,
+    valid_until DATE,
+    base_price DECIMAL(10,2) NOT NULL CHECK (base_price >= 0),
+    is_active BOOLEAN NOT NULL DEFAULT true,
+
+    CONSTRAINT trip_schedules_departure_time_check CHECK (departure_time ~ '^([01][0-9]|2[0-3]):[0-5][0-9]$'),
+    CONSTRAINT trip_schedules_weekdays_check CHECK (cardinality(weekdays) > 0 AND weekdays <@ ARRAY[0,1,2,3,4,5,6]),
+    CONSTRAINT trip_schedules_validity_check CHECK (valid_until IS NULL OR valid_until >= valid_from)
+);
+
+CREATE INDEX idx_trip_schedules_route_id ON trip_schedules(route_id);
+CREATE INDEX idx_trip_schedules_bus_id ON trip_schedules(bus_id);
+CREATE INDEX idx_trip_schedules_active ON trip_schedules(is_active, valid_from, valid_until) WHERE deleted_at IS NULL;
+CREATE INDEX idx_trip_schedules_deleted_at ON trip_schedules(deleted_at);
+
+COMMENT ON TABLE trip_schedules IS 'Recurring trip templates materialized into trips ahead of time';
+COMMENT ON COLUMN trip_schedules.departure_time IS 'Local departure time of day (HH:MM, Asia/Ho_Chi_Minh)';
+COMMENT ON COLUMN trip_schedules.weekdays IS 'Days of week the trip runs (0 = Sunday ... 6 = Saturday)';
+
+-- Trips generated from a schedule keep a reference to it; historical trips are never modified
+ALTER TABLE trips ADD COLUMN IF NOT EXISTS schedule_id UUID REFERENCES trip_schedules(id) ON DELETE SET NULL;
+CREATE INDEX idx_trips_schedule_id ON trips(schedule_id) WHERE deleted_at IS NULL;
+CREATE UNIQUE INDEX uq_trips_schedule_departure ON trips(schedule_id, departure_time)
+    WHERE schedule_id IS NOT NULL AND deleted_at IS NULL;
+
+-- Existing trips used to be rescheduled weekly in place; keep them running as schedules
+INSERT INTO trip_schedules (route_id, bus_id, departure_time, weekdays, valid_from, base_price)
+SELECT
+    route_id,
+    bus_id,
+    to_char(departure_time AT TIME ZONE 'Asia/Ho_Chi_Minh', 'HH24:MI'),
+    array_agg(DISTINCT EXTRACT(DOW FROM departure_time AT TIME ZONE 'Asia/Ho_Chi_Minh')::int),
+    CURRENT_DATE,
+    MAX(base_price)
+FROM trips
+WHERE deleted_at IS NULL AND is_active = true
+GROUP BY route_id, bus_id, to_char(departure_time AT TIME ZONE 'Asia/Ho_Chi_Minh', 'HH24:MI');
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS trip_cancellation_bookings;
+DROP TABLE IF EXISTS trip_cancellation_sagas;
@@ -0,0 +1,42 @@
+-- Progress of refunding and cancelling the bookings of a trip cancelled by the operator
+CREATE TABLE IF NOT EXISTS trip_cancellation_sagas (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    trip_id UUID NOT NULL REFERENCES trips(id) ON DELETE CASCADE,
+    previous_status VARCHAR(50) NOT NULL,
+    reason TEXT NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'RUNNING' CHECK (status IN ('RUNNING', 'COMPLETED', 'FAILED', 'ABORTED')),
+    bookings_loaded BOOLEAN NOT NULL DEFAULT FALSE,
+    locked_until TIMESTAMPTZ,
+    completed_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- A trip has at most one live cancellation; aborted ones are kept for history
+CREATE UNIQUE INDEX idx_trip_cancellation_sagas_trip ON trip_cancellation_sagas(trip_id)
+    WHERE status <> 'ABORTED';
+
+CREATE INDEX idx_trip_cancellation_sagas_running ON trip_cancellation_sagas(created_at)
+    WHERE status = 'RUNNING';
+
+CREATE TABLE IF NOT EXISTS trip_cancellation_bookings (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    saga_id UUID NOT NULL REFERENCES trip_cancellation_sagas(id) ON DELETE CASCADE,
+    booking_id UUID NOT NULL,
+    transaction_status VARCHAR(20) NOT NULL,
+    refund_required BOOLEAN NOT NULL DEFAULT FALSE,
+    refund_amount INT NOT NULL DEFAULT 0,
+    refund_id UUID,
+    refund_status VARCHAR(20),
+    booking_cancelled_at TIMESTAMPTZ,
+    refund_requested_at TIMESTAMPTZ,
+    passenger_notified_at TIMESTAMPTZ,
+    refund_completed_at TIMESTAMPTZ,
+    status VARCHAR(20) NOT NULL DEFAULT 'RUNNING' CHECK (status IN ('RUNNING', 'COMPLETED', 'FAILED')),
+    attempts INT NOT NULL DEFAULT 0,
+    failed_step VARCHAR(50),
+    last_error TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (saga_id, booking_id)
+);
@@ -0,0 +1,65 @@
+-- Rules adjusting trip fares by occupancy, lead time and calendar
+CREATE TABLE IF NOT EXISTS pricing_rules (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMPTZ,
+
+    name VARCHAR(255) NOT NULL,
+    priority INTEGER NOT NULL DEFAULT 0,
+    route_id UUID REFERENCES routes(id) ON DELETE CASCADE,
+
+    min_load_factor DECIMAL(4,3) CHECK (min_load_factor BETWEEN 0 AND 1),
+    max_load_factor DECIMAL(4,3) CHECK (max_load_factor BETWEEN 0 AND 1),
+    min_days_before INTEGER CHECK (min_days_before >= 0),
+    max_days_before INTEGER CHECK (max_days_before >= 0),
+    weekdays INTEGER[] NOT NULL DEFAULT '{}',
+    on_holiday BOOLEAN,
+
+    multiplier DECIMAL(5,3) NOT NULL DEFAULT 1 CHECK (multiplier > 0),
+    adjustment DECIMAL(10,2) NOT NULL DEFAULT 0,
+    min_price_ratio DECIMAL(5,3) CHECK (min_price_ratio > 0),
+    max_price_ratio DECIMAL(5,3) CHECK (max_price_ratio > 0),
+    stop_processing BOOLEAN NOT NULL DEFAULT false,
+    is_active BOOLEAN NOT NULL DEFAULT true,
+
+    CONSTRAINT pricing_rules_weekdays_check CHECK (weekdays <@ ARRAY[0,1,2,3,4,5,6]),
+    CONSTRAINT pricing_rules_load_factor_check CHECK (min_load_factor IS NULL OR max_load_factor IS NULL OR min_load_factor <= max_load_factor),
+    CONSTRAINT pricing_rules_days_before_check CHECK (min_days_before IS NULL OR max_days_before IS NULL OR min_days_before <= max_days_before),
+    CONSTRAINT pricing_rules_price_ratio_check CHECK (min_price_ratio IS NULL OR max_price_ratio IS NULL OR min_price_ratio <= max_price_ratio)
+);
+
+CREATE INDEX idx_pricing_rules_active ON pricing_rules(priority DESC, created_at) WHERE is_active = true AND deleted_at IS NULL;
+CREATE INDEX idx_pricing_rules_route_id ON pricing_rules(route_id);
+CREATE INDEX idx_pricing_rules_deleted_at ON pricing_rules(deleted_at);
+
+COMMENT ON TABLE pricing_rules IS 'Fare adjustments applied from the highest priority down; unset conditions match any departure';
+COMMENT ON COLUMN pricing_rules.weekdays IS 'Departure days of week the rule applies on (0 = Sunday ... 6 = Saturday), empty for every day';
+COMMENT ON COLUMN pricing_rules.min_price_ratio IS 'Lowest fare after this rule, as a multiple of the trip base price';
+COMMENT ON COLUMN pricing_rules.max_price_ratio IS 'Highest fare after this rule, as a multiple of the trip base price';
+
+-- Holiday calendar used by pricing rules
+CREATE TABLE IF NOT EXISTS holidays (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMPTZ,
+
+    name VARCHAR(255) NOT NULL,
+    start_date DATE NOT NULL,
+    end_date DATE NOT NULL,
+    recurring_yearly BOOLEAN NOT NULL DEFAULT false,
+
+    CONSTRAINT holidays_dates_check CHECK (recurring_yearly OR end_date >= start_date)
+);
+
+CREATE INDEX idx_holidays_start_date ON holidays(start_date) WHERE deleted_at IS NULL;
+CREATE INDEX idx_holidays_deleted_at ON holidays(deleted_at);
+
+COMMENT ON COLUMN holidays.recurring_yearly IS 'Repeats on the same dates every year; lunar holidays such as Tết are entered per year';
+
+-- Fixed-date public holidays
+INSERT INTO holidays (name, start_date, end_date, recurring_yearly) VALUES
+    ('Tết Dương lịch', '2025-01-01', '2025-01-01', true),
+    ('Giải phóng miền Nam và Quốc tế Lao động (30/4 - 1/5)', '2025-04-30', '2025-05-01', true),
+    ('Quốc khánh (2/9)', '2025-09-01', '2025-09-02', true);
@@ -21,6 +21,7 @@ function CheckoutGuestContent() {
   const router = useRouter();
   const searchParams = useSearchParams();
   const tripId = searchParams.get("tripId");
+  const sessionId = searchParams.get("sessionId");
   const seatIds = searchParams.get("seats")?.split(",") || [];
 
   const [fullName, setFullName] = useState("");
@@ -100,6 +101,7 @@ function CheckoutGuestContent() {
       email: email || undefined,
       phone: phone || undefined,
       notes,
+      session_id: sessionId || undefined,
     });
   };
 
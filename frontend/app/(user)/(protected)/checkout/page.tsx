@@ -120,6 +120,7 @@ function CheckoutContent() {
       trip_id: tripId,
       seat_ids: seatIds,
       notes,
+      session_id: sessionId || undefined,
     });
   };
 
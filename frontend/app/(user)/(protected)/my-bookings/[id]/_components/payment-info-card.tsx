@@ -18,7 +18,7 @@ import { retryPayment, cancelBooking } from "@/lib/api/booking/booking-service";
 import { getBankAccounts } from "@/lib/api/payment/bank-service";
 import {
   createRefund,
-  getRefundByBookingId,
+  getRefundsByBookingId,
 } from "@/lib/api/payment/refund-service";
 import { toast } from "sonner";
 import type { Transaction } from "@/lib/types/booking";
@@ -88,7 +88,7 @@ export function PaymentInfoCard({
   const [selectedBankAccount, setSelectedBankAccount] = useState<string>("");
   const [refundReason, setRefundReason] = useState<string>("");
   const [loadingBankAccounts, setLoadingBankAccounts] = useState(false);
-  const [refundInfo, setRefundInfo] = useState<RefundResponse | null>(null);
+  const [refunds, setRefunds] = useState<RefundResponse[]>([]);
   const [loadingRefund, setLoadingRefund] = useState(false);
 
   const formatCurrency = (amount: number) => {
@@ -175,7 +175,7 @@ export function PaymentInfoCard({
   const canRequestRefund =
     bookingStatus === "CONFIRMED" &&
     transactionStatus === "PAID" &&
-    !refundInfo; // Only allow if no refund exists
+    refunds.length === 0; // Only allow if no refund exists
 
   // Fetch bank accounts when refund dialog opens
   useEffect(() => {
@@ -192,8 +192,7 @@ export function PaymentInfoCard({
   const fetchRefundInfo = async () => {
     try {
       setLoadingRefund(true);
-      const refund = await getRefundByBookingId(bookingId);
-      setRefundInfo(refund);
+      setRefunds(await getRefundsByBookingId(bookingId));
     } catch (error) {
       console.error("Failed to fetch refund info:", error);
       // Don't show error toast, it's okay if there's no refund
@@ -381,8 +380,11 @@ export function PaymentInfoCard({
         )}
 
         {/* Refund Status Display */}
-        {refundInfo && (
-          <div className="space-y-3 rounded-lg border border-orange-200 bg-orange-50 p-4">
+        {refunds.map((refundInfo) => (
+          <div
+            key={refundInfo.id}
+            className="space-y-3 rounded-lg border border-orange-200 bg-orange-50 p-4"
+          >
             <div className="flex items-center justify-between">
               <span className="text-sm font-medium text-orange-900">
                 Thông tin hoàn tiền
@@ -446,7 +448,7 @@ export function PaymentInfoCard({
               </div>
             )}
           </div>
-        )}
+        ))}
 
         {/* Cancel Booking Button */}
         {canCancelBooking && (
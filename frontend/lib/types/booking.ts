@@ -190,6 +190,7 @@ export interface CreateBookingRequest {
   trip_id: string;
   seat_ids: string[];
   notes?: string;
+  session_id?: string; // seat-lock session holding the seats
 }
 
 /**
@@ -200,6 +201,7 @@ export interface CreateGuestBookingRequest {
   trip_id: string;
   seat_ids: string[];
   notes?: string;
+  session_id?: string; // seat-lock session holding the seats
   full_name: string;
   email?: string;
   phone?: string;